@@ -105,7 +105,8 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		return true
 	}
 
-	// Permission is checked by github token
+	// Permission is checked by github token, or by the ID of a resumable upload
+	// session that was created with one
 	if strings.HasPrefix(req.URL.Path, "/.api/lsif/upload") {
 		return true
 	}
@@ -120,6 +121,10 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		return true
 	}
 
+	if strings.HasPrefix(req.URL.Path, "/.api/gitlab-webhooks") {
+		return true
+	}
+
 	if strings.HasPrefix(req.URL.Path, "/.api/bitbucket-server-webhooks") {
 		return true
 	}
@@ -4,10 +4,17 @@ const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	// Restricted access token scopes. A token that has one or more of these
+	// scopes but not ScopeUserAll may only be used for the listed actions.
+	ScopeCampaignsWrite = "campaigns:write" // Ability to view, create and manage campaigns.
+	ScopeCodeIntelRead  = "codeintel:read"  // Ability to query code intelligence data.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeCampaignsWrite,
+	ScopeCodeIntelRead,
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
@@ -19,6 +20,9 @@ func CheckOrgAccess(ctx context.Context, orgID int32) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	if err := CheckActorHasScope(ctx, authz.ScopeUserAll); err != nil {
+		return err
+	}
 	currentUser, err := CurrentUser(ctx)
 	if err != nil {
 		return err
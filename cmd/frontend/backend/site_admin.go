@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -15,9 +16,21 @@ var ErrMustBeSiteAdmin = errors.New("must be site admin")
 
 // CheckCurrentUserIsSiteAdmin returns an error if the current user is NOT a site admin.
 func CheckCurrentUserIsSiteAdmin(ctx context.Context) error {
+	return CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeUserAll)
+}
+
+// CheckCurrentUserIsSiteAdminWithScope returns an error if the current user is NOT a site admin or
+// if the actor is restricted to access token scopes that don't include the given scope.
+//
+// It is used for site admin actions that may also be performed with a restricted access token,
+// such as managing campaigns with a "campaigns:write" token.
+func CheckCurrentUserIsSiteAdminWithScope(ctx context.Context, scope string) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	if err := CheckActorHasScope(ctx, scope); err != nil {
+		return err
+	}
 	user, err := CurrentUser(ctx)
 	if err != nil {
 		return err
@@ -58,6 +71,28 @@ type InsufficientAuthorizationError struct {
 
 func (e *InsufficientAuthorizationError) Error() string { return e.Message }
 
+// WithPermissionDeniedCode annotates err with the GraphQL error code errcode.CodePermissionDenied
+// if it was returned by one of the access checks in this package because the current actor lacks
+// permissions. Other errors, such as failures to look up the current user, are returned unchanged.
+func WithPermissionDeniedCode(err error) error {
+	type causer interface {
+		Cause() error
+	}
+
+	for e := err; e != nil; {
+		if _, ok := e.(*InsufficientAuthorizationError); ok || e == ErrMustBeSiteAdmin || e == ErrNotAuthenticated {
+			return errcode.WithGraphQLCode(err, errcode.CodePermissionDenied)
+		}
+
+		cause, ok := e.(causer)
+		if !ok {
+			break
+		}
+		e = cause.Cause()
+	}
+	return err
+}
+
 // CheckSiteAdminOrSameUser returns an error if the user is NEITHER (1) a
 // site admin NOR (2) the user specified by subjectUserID.
 //
@@ -69,6 +104,11 @@ func CheckSiteAdminOrSameUser(ctx context.Context, subjectUserID int32) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	// 🚨 SECURITY: Restricted access tokens must not be usable to manage the user account (e.g. to
+	// create an unrestricted access token).
+	if err := CheckActorHasScope(ctx, authz.ScopeUserAll); err != nil {
+		return err
+	}
 	actor := actor.FromContext(ctx)
 	if actor.IsAuthenticated() && actor.UID == subjectUserID {
 		return nil
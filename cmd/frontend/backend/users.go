@@ -47,3 +47,16 @@ func CheckActorHasTag(ctx context.Context, tag string) error {
 	}
 	return fmt.Errorf("actor lacks required tag %q", tag)
 }
+
+// CheckActorHasScope returns an error if the context actor is restricted to access token scopes
+// that don't include the given scope. Actors that are not restricted (e.g. those authenticated with
+// a session cookie or a "user:all" access token) have all scopes.
+func CheckActorHasScope(ctx context.Context, scope string) error {
+	if hasAuthzBypass(ctx) {
+		return nil
+	}
+	if !actor.FromContext(ctx).HasScope(scope) {
+		return &InsufficientAuthorizationError{fmt.Sprintf("access token lacks required scope %q", scope)}
+	}
+	return nil
+}
@@ -131,6 +131,44 @@ RETURNING t.subject_user_id
 	return subjectUserID, nil
 }
 
+// LookupScopes looks up the access token. If it's valid, it returns the subject's user ID and the
+// scopes of the token. Otherwise ErrAccessTokenNotFound is returned.
+//
+// Calling LookupScopes also updates the access token's last-used-at date.
+//
+// 🚨 SECURITY: The caller must ensure that the returned scopes are enforced for the actor that is
+// authenticated with this token.
+func (s *accessTokens) LookupScopes(ctx context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
+	if Mocks.AccessTokens.LookupScopes != nil {
+		return Mocks.AccessTokens.LookupScopes(tokenHexEncoded)
+	}
+
+	token, err := hex.DecodeString(tokenHexEncoded)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "AccessTokens.LookupScopes")
+	}
+
+	if err := dbconn.Global.QueryRowContext(ctx,
+		// Ensure that subject and creator users still exist.
+		`
+UPDATE access_tokens t SET last_used_at=now()
+FROM access_tokens t2
+JOIN users subject_user ON t2.subject_user_id=subject_user.id
+JOIN users creator_user ON t2.creator_user_id=creator_user.id
+WHERE t.id=t2.id AND t.value_sha256=$1 AND t.deleted_at IS NULL AND
+  subject_user.deleted_at IS NULL AND creator_user.deleted_at IS NULL
+RETURNING t.subject_user_id, t.scopes
+`,
+		toSHA256Bytes(token),
+	).Scan(&subjectUserID, pq.Array(&scopes)); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil, ErrAccessTokenNotFound
+		}
+		return 0, nil, err
+	}
+	return subjectUserID, scopes, nil
+}
+
 // GetByID retrieves the access token (if any) given its ID.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to view this access token.
@@ -264,8 +302,9 @@ func toSHA256Bytes(input []byte) []byte {
 }
 
 type MockAccessTokens struct {
-	Create     func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
-	DeleteByID func(id int64, subjectUserID int32) error
-	Lookup     func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
-	GetByID    func(id int64) (*AccessToken, error)
+	Create       func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
+	DeleteByID   func(id int64, subjectUserID int32) error
+	Lookup       func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
+	LookupScopes func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error)
+	GetByID      func(id int64) (*AccessToken, error)
 }
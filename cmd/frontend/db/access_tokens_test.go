@@ -226,6 +226,55 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	}
 }
 
+// 🚨 SECURITY: This tests the routine that looks up the scopes of access tokens, which restricted
+// tokens depend on.
+func TestAccessTokens_LookupScopes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	subject, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", subject.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotSubjectUserID, gotScopes, err := AccessTokens.LookupScopes(ctx, tv0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := subject.ID; gotSubjectUserID != want {
+		t.Errorf("got %v, want %v", gotSubjectUserID, want)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(gotScopes, want) {
+		t.Errorf("got %v, want %v", gotScopes, want)
+	}
+
+	// Delete a token and ensure LookupScopes fails on it.
+	if err := AccessTokens.DeleteByID(ctx, tid0, subject.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.LookupScopes(ctx, tv0); err != ErrAccessTokenNotFound {
+		t.Fatalf("got err %v, want %v", err, ErrAccessTokenNotFound)
+	}
+
+	// Try to LookupScopes a token that was never created.
+	if _, _, err := AccessTokens.LookupScopes(ctx, "abcdef" /* this token value was never created */); err != ErrAccessTokenNotFound {
+		t.Fatalf("got err %v, want %v", err, ErrAccessTokenNotFound)
+	}
+}
+
 // 🚨 SECURITY: This tests that deleting the subject or creator user of an access token invalidates
 // the token, and that no new access tokens may be created for deleted users.
 func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+type aggregatedSearchLatencies struct{}
+
+// AggregatedSearchLatency contains the latency percentiles of the searches matching a
+// filter during a day, as computed by EventLogs.PercentilesPerPeriod.
+type AggregatedSearchLatency struct {
+	Day    time.Time
+	Filter string
+	P50    float64
+	P90    float64
+	P99    float64
+}
+
+// Upsert inserts the given aggregated latencies, replacing the existing ones for the same day
+// and filter.
+func (*aggregatedSearchLatencies) Upsert(ctx context.Context, ls []*AggregatedSearchLatency) error {
+	if len(ls) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(ls))
+	for _, l := range ls {
+		values = append(values, sqlf.Sprintf("(%s::date, %s, %s, %s, %s)", l.Day, l.Filter, l.P50, l.P90, l.P99))
+	}
+
+	q := sqlf.Sprintf(`
+		INSERT INTO aggregated_search_latencies (day, filter, p50, p90, p99)
+		VALUES %s
+		ON CONFLICT (day, filter) DO UPDATE SET
+			p50 = excluded.p50,
+			p90 = excluded.p90,
+			p99 = excluded.p99,
+			created_at = now()`, sqlf.Join(values, ",\n"))
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// List returns the aggregated latencies of the days in the range [startDay, endDay].
+func (*aggregatedSearchLatencies) List(ctx context.Context, startDay, endDay time.Time) ([]*AggregatedSearchLatency, error) {
+	q := sqlf.Sprintf(`
+		SELECT day, filter, p50, p90, p99
+		FROM aggregated_search_latencies
+		WHERE day BETWEEN %s::date AND %s::date
+		ORDER BY day DESC, filter`, startDay, endDay)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ls := []*AggregatedSearchLatency{}
+	for rows.Next() {
+		var l AggregatedSearchLatency
+		if err := rows.Scan(&l.Day, &l.Filter, &l.P50, &l.P90, &l.P99); err != nil {
+			return nil, err
+		}
+		l.Day = l.Day.UTC()
+		ls = append(ls, &l)
+	}
+	return ls, rows.Err()
+}
+
+// LatestDay returns the latest day for which latencies were aggregated, or the zero time if
+// none were.
+func (*aggregatedSearchLatencies) LatestDay(ctx context.Context) (time.Time, error) {
+	var day *time.Time
+	q := sqlf.Sprintf(`SELECT MAX(day) FROM aggregated_search_latencies`)
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&day); err != nil {
+		return time.Time{}, err
+	}
+	if day == nil {
+		return time.Time{}, nil
+	}
+	return day.UTC(), nil
+}
+
+// DeleteBefore deletes the aggregated latencies of the days before the given one.
+func (*aggregatedSearchLatencies) DeleteBefore(ctx context.Context, day time.Time) error {
+	q := sqlf.Sprintf(`DELETE FROM aggregated_search_latencies WHERE day < %s::date`, day)
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
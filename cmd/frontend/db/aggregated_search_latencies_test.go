@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestAggregatedSearchLatencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	latest, err := AggregatedSearchLatencies.LatestDay(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !latest.IsZero() {
+		t.Fatalf("have latest day %v, want zero time", latest)
+	}
+
+	ls := []*AggregatedSearchLatency{
+		{Day: day(1), Filter: "type:literal", P50: 1, P90: 2, P99: 3},
+		{Day: day(2), Filter: "type:literal", P50: 4, P90: 5, P99: 6},
+		{Day: day(2), Filter: "scope:global", P50: 7, P90: 8, P99: 9},
+	}
+	if err := AggregatedSearchLatencies.Upsert(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+
+	// Aggregating a day again replaces its latencies.
+	ls[1] = &AggregatedSearchLatency{Day: day(2), Filter: "type:literal", P50: 10, P90: 11, P99: 12}
+	if err := AggregatedSearchLatencies.Upsert(ctx, ls[1:2]); err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := AggregatedSearchLatencies.List(ctx, day(2), day(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*AggregatedSearchLatency{ls[2], ls[1]}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have %+v, want %+v", have, want)
+	}
+
+	latest, err = AggregatedSearchLatencies.LatestDay(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !latest.Equal(day(2)) {
+		t.Fatalf("have latest day %v, want %v", latest, day(2))
+	}
+
+	if err := AggregatedSearchLatencies.DeleteBefore(ctx, day(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	have, err = AggregatedSearchLatencies.List(ctx, day(1), day(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != 2 {
+		t.Fatalf("have %d aggregated latencies, want 2", len(have))
+	}
+}
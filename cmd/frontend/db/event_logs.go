@@ -32,7 +32,33 @@ type Event struct {
 	Timestamp       time.Time
 }
 
+// ScrubbedUserIDPrefix prefixes the anonymous user IDs that replace the IDs of registered users
+// in scrubbed events, so that they are still counted as registered users.
+const ScrubbedUserIDPrefix = "user:"
+
+// An EventScrubber removes personal data from an event before it is inserted into the event
+// logs. Scrubbers that remove the ID of a registered user should set UserID to 0 and
+// AnonymousUserID to a value with the ScrubbedUserIDPrefix that identifies the user.
+type EventScrubber func(e *Event)
+
+var eventScrubbers []EventScrubber
+
+// RegisterEventScrubber registers a scrubber that is applied to all events inserted into the
+// event logs, in the order of registration. It must be called on startup, e.g. in an init
+// function.
+func RegisterEventScrubber(s EventScrubber) {
+	eventScrubbers = append(eventScrubbers, s)
+}
+
 func (*eventLogs) Insert(ctx context.Context, e *Event) error {
+	if len(eventScrubbers) > 0 {
+		scrubbed := *e
+		for _, scrub := range eventScrubbers {
+			scrub(&scrubbed)
+		}
+		e = &scrubbed
+	}
+
 	argument := e.Argument
 	if argument == nil {
 		argument = json.RawMessage([]byte(`{}`))
@@ -107,6 +133,16 @@ func (l *eventLogs) CountByUserIDAndEventNames(ctx context.Context, userID int32
 	return l.countBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d AND name IN (%s)", userID, sqlf.Join(items, ",")))
 }
 
+// CountByUserIDAndEventNamesInRange gets a count of events logged by a given user that match a list of given event
+// names in the time span [startDate, endDate).
+func (l *eventLogs) CountByUserIDAndEventNamesInRange(ctx context.Context, userID int32, names []string, startDate, endDate time.Time) (int, error) {
+	items := []*sqlf.Query{}
+	for _, v := range names {
+		items = append(items, sqlf.Sprintf("%s", v))
+	}
+	return l.countBySQL(ctx, sqlf.Sprintf("WHERE user_id = %d AND name IN (%s) AND timestamp >= %s AND timestamp < %s", userID, sqlf.Join(items, ","), startDate, endDate))
+}
+
 // countBySQL gets a count of event logs.
 func (*eventLogs) countBySQL(ctx context.Context, querySuffix *sqlf.Query) (int, error) {
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM event_logs %s", querySuffix)
@@ -212,6 +248,8 @@ type CountUniqueUsersOptions struct {
 	RegisteredOnly bool
 	// If true, only include code host integration users. Otherwise, include all users.
 	IntegrationOnly bool
+	// If not empty, only include events from one of the given sources (e.g. "WEB").
+	Sources []string
 	// If set, adds additional restrictions on the event types.
 	EventFilters *EventFilterOptions
 }
@@ -224,6 +262,51 @@ type EventFilterOptions struct {
 	ByEventName string
 	// If not empty, only include events that matche a list of given event names
 	ByEventNames []string
+	// If set, only include events with an integer argument field within a given range.
+	ByArgumentRange *ArgumentRange
+}
+
+// conds returns the conditions on event_logs rows of the options. It returns no
+// conditions for nil options.
+func (opt *EventFilterOptions) conds() []*sqlf.Query {
+	if opt == nil {
+		return nil
+	}
+
+	var conds []*sqlf.Query
+	if opt.ByEventNamePrefix != "" {
+		conds = append(conds, sqlf.Sprintf("name LIKE %s", opt.ByEventNamePrefix+"%"))
+	}
+	if opt.ByEventName != "" {
+		conds = append(conds, sqlf.Sprintf("name = %s", opt.ByEventName))
+	}
+	if len(opt.ByEventNames) > 0 {
+		items := []*sqlf.Query{}
+		for _, v := range opt.ByEventNames {
+			items = append(items, sqlf.Sprintf("%s", v))
+		}
+		conds = append(conds, sqlf.Sprintf("name IN (%s)", sqlf.Join(items, ",")))
+	}
+	if opt.ByArgumentRange != nil {
+		conds = append(conds, opt.ByArgumentRange.cond())
+	}
+	return conds
+}
+
+// ArgumentRange restricts the value of an integer field of the event's arguments to the
+// range [Min, Max]. A Max of zero means that there is no upper bound.
+type ArgumentRange struct {
+	Field string
+	Min   int
+	Max   int
+}
+
+func (r *ArgumentRange) cond() *sqlf.Query {
+	// See calculatePercentilesPerPeriodBySQL for why the field is cast to text first.
+	if r.Max == 0 {
+		return sqlf.Sprintf("(argument->%s)::text::integer >= %s", r.Field, r.Min)
+	}
+	return sqlf.Sprintf("(argument->%s)::text::integer BETWEEN %s AND %s", r.Field, r.Min, r.Max)
 }
 
 // CountUniqueUsersPerPeriod provides a count of unique active users in a given time span, broken up into periods of
@@ -243,26 +326,19 @@ func (l *eventLogs) CountUniqueUsersPerPeriod(ctx context.Context, periodType Pe
 	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
 	if opt != nil {
 		if opt.RegisteredOnly {
-			conds = append(conds, sqlf.Sprintf("user_id > 0"))
+			conds = append(conds, sqlf.Sprintf("(user_id > 0 OR anonymous_user_id LIKE %s)", ScrubbedUserIDPrefix+"%"))
 		}
 		if opt.IntegrationOnly {
 			conds = append(conds, sqlf.Sprintf("source = %s", integrationSource))
 		}
-		if opt.EventFilters != nil {
-			if opt.EventFilters.ByEventNamePrefix != "" {
-				conds = append(conds, sqlf.Sprintf("name LIKE %s", opt.EventFilters.ByEventNamePrefix+"%"))
-			}
-			if opt.EventFilters.ByEventName != "" {
-				conds = append(conds, sqlf.Sprintf("name = %s", opt.EventFilters.ByEventName))
-			}
-			if len(opt.EventFilters.ByEventNames) > 0 {
-				items := []*sqlf.Query{}
-				for _, v := range opt.EventFilters.ByEventNames {
-					items = append(items, sqlf.Sprintf("%s", v))
-				}
-				conds = append(conds, sqlf.Sprintf("name IN (%s)", sqlf.Join(items, ",")))
+		if len(opt.Sources) > 0 {
+			items := make([]*sqlf.Query, 0, len(opt.Sources))
+			for _, v := range opt.Sources {
+				items = append(items, sqlf.Sprintf("%s", v))
 			}
+			conds = append(conds, sqlf.Sprintf("source IN (%s)", sqlf.Join(items, ",")))
 		}
+		conds = append(conds, opt.EventFilters.conds()...)
 	}
 
 	return l.countUniqueUsersPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, conds)
@@ -281,22 +357,7 @@ func (l *eventLogs) CountEventsPerPeriod(ctx context.Context, periodType PeriodT
 		return nil, fmt.Errorf("periodType must be \"daily\", \"weekly\", or \"monthly\". Got %s", periodType)
 	}
 
-	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
-	if opt != nil {
-		if opt.ByEventNamePrefix != "" {
-			conds = append(conds, sqlf.Sprintf("name LIKE %s", opt.ByEventNamePrefix+"%"))
-		}
-		if opt.ByEventName != "" {
-			conds = append(conds, sqlf.Sprintf("name = %s", opt.ByEventName))
-		}
-		if len(opt.ByEventNames) > 0 {
-			items := []*sqlf.Query{}
-			for _, v := range opt.ByEventNames {
-				items = append(items, sqlf.Sprintf("%s", v))
-			}
-			conds = append(conds, sqlf.Sprintf("name IN (%s)", sqlf.Join(items, ",")))
-		}
-	}
+	conds := append([]*sqlf.Query{sqlf.Sprintf("TRUE")}, opt.conds()...)
 
 	return l.countEventsPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, conds)
 }
@@ -336,24 +397,133 @@ func (l *eventLogs) PercentilesPerPeriod(
 		return nil, fmt.Errorf("periodType must be \"daily\", \"weekly\", or \"monthly\". Got %s", periodType)
 	}
 
-	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
-	if opt != nil {
-		if opt.ByEventNamePrefix != "" {
-			conds = append(conds, sqlf.Sprintf("name LIKE %s", opt.ByEventNamePrefix+"%"))
-		}
-		if opt.ByEventName != "" {
-			conds = append(conds, sqlf.Sprintf("name = %s", opt.ByEventName))
+	conds := append([]*sqlf.Query{sqlf.Sprintf("TRUE")}, opt.conds()...)
+
+	return l.calculatePercentilesPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, field, percentiles, conds)
+}
+
+// ArgumentGroupValue is the number of events and of unique users of a time span that
+// have the same values for some fields of their arguments.
+type ArgumentGroupValue struct {
+	// Values are the values of the fields, in the order in which they were requested.
+	// Missing fields have the empty string as value.
+	Values      []string
+	EventsCount int
+	UsersCount  int
+}
+
+// CountByArgumentFields counts the events and the unique users in the time span from
+// startDate (inclusive) to endDate (exclusive), grouped by the values of the given fields
+// of the event's arguments. Groups are ordered by decreasing number of events.
+func (l *eventLogs) CountByArgumentFields(ctx context.Context, startDate, endDate time.Time, fields []string, opt *EventFilterOptions) ([]ArgumentGroupValue, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("expected at least one argument field in query")
+	}
+
+	groupExprs := make([]*sqlf.Query, 0, len(fields))
+	groupRefs := make([]*sqlf.Query, 0, len(fields))
+	for i, field := range fields {
+		groupExprs = append(groupExprs, sqlf.Sprintf("COALESCE(argument->>%s, '')", field))
+		groupRefs = append(groupRefs, sqlf.Sprintf(fmt.Sprint(i+1)))
+	}
+
+	conds := append([]*sqlf.Query{sqlf.Sprintf("timestamp >= %s AND timestamp < %s", startDate, endDate)}, opt.conds()...)
+	q := sqlf.Sprintf(`SELECT %s, COUNT(*) AS events_count, COUNT(DISTINCT CASE WHEN user_id = 0 THEN anonymous_user_id ELSE CAST(user_id AS TEXT) END)
+		FROM event_logs
+		WHERE (%s)
+		GROUP BY %s
+		ORDER BY events_count DESC, %s`, sqlf.Join(groupExprs, ", "), sqlf.Join(conds, ") AND ("), sqlf.Join(groupRefs, ", "), sqlf.Join(groupRefs, ", "))
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []ArgumentGroupValue{}
+	for rows.Next() {
+		v := ArgumentGroupValue{Values: make([]string, len(fields))}
+		dest := make([]interface{}, 0, len(fields)+2)
+		for i := range v.Values {
+			dest = append(dest, &v.Values[i])
 		}
-		if len(opt.ByEventNames) > 0 {
-			items := []*sqlf.Query{}
-			for _, v := range opt.ByEventNames {
-				items = append(items, sqlf.Sprintf("%s", v))
-			}
-			conds = append(conds, sqlf.Sprintf("name IN (%s)", sqlf.Join(items, ",")))
+		dest = append(dest, &v.EventsCount, &v.UsersCount)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
 		}
+		groups = append(groups, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// RetentionValue is the number of users of a weekly cohort that were active during a
+// week a given number of weeks after the cohort's first week.
+type RetentionValue struct {
+	CohortStart time.Time
+	WeeksAfter  int
+	Count       int
+}
+
+// CountRetainedUsersPerWeeklyCohort groups users into cohorts by the week of their first
+// logged event and counts, for each cohort, the number of users active in each following
+// week. Only cohorts starting within the last `weeks` weeks (relative to `now`, which should
+// be the current time in UTC) are returned. Values are ordered by cohort (newest first) and
+// then by week (oldest first).
+//
+// Only the events of the last `weeks` weeks are scanned. Whether a user was active before
+// is looked up by user (see the event_logs_user_id and event_logs_anonymous_user_id
+// indexes), so that the users of older cohorts are not counted as new users.
+func (l *eventLogs) CountRetainedUsersPerWeeklyCohort(ctx context.Context, now time.Time, weeks int) ([]RetentionValue, error) {
+	startDate, ok := calcStartDate(now, Weekly, weeks)
+	if !ok {
+		return nil, fmt.Errorf("periodType must be \"daily\", \"weekly\", or \"monthly\". Got %s", Weekly)
 	}
 
-	return l.calculatePercentilesPerPeriodBySQL(ctx, intervalByPeriodType[periodType], periodByPeriodType[periodType], startDate, endDate, field, percentiles, conds)
+	q := sqlf.Sprintf(`WITH user_periods AS (
+			SELECT DISTINCT user_id, CASE WHEN user_id = 0 THEN anonymous_user_id ELSE '' END AS anonymous_user_id, (%s)::timestamp AS period
+			FROM event_logs
+			WHERE timestamp >= (%s)::timestamp
+		), cohorts AS (
+			SELECT user_id, anonymous_user_id, MIN(period) AS cohort
+			FROM user_periods
+			GROUP BY user_id, anonymous_user_id
+		)
+		SELECT cohorts.cohort, user_periods.period, COUNT(*)
+		FROM cohorts
+		JOIN user_periods ON user_periods.user_id = cohorts.user_id AND user_periods.anonymous_user_id = cohorts.anonymous_user_id
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM event_logs earlier
+			WHERE earlier.timestamp < (%s)::timestamp AND earlier.user_id = cohorts.user_id AND
+				(cohorts.user_id <> 0 OR earlier.anonymous_user_id = cohorts.anonymous_user_id)
+		)
+		GROUP BY cohorts.cohort, user_periods.period
+		ORDER BY cohorts.cohort DESC, user_periods.period ASC`, periodByPeriodType[Weekly], startDate, startDate)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []RetentionValue{}
+	for rows.Next() {
+		var (
+			v      RetentionValue
+			period time.Time
+		)
+		if err := rows.Scan(&v.CohortStart, &period, &v.Count); err != nil {
+			return nil, err
+		}
+		v.CohortStart = v.CohortStart.UTC()
+		v.WeeksAfter = int(period.UTC().Sub(v.CohortStart).Hours() / (24 * 7))
+		values = append(values, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
 
 func (l *eventLogs) countUniqueUsersPerPeriodBySQL(ctx context.Context, interval, period *sqlf.Query, startDate, endDate time.Time, conds []*sqlf.Query) ([]UsageValue, error) {
@@ -368,8 +538,8 @@ func (l *eventLogs) countPerPeriodBySQL(ctx context.Context, countExpr, interval
 	allPeriods := sqlf.Sprintf("SELECT generate_series((%s)::timestamp, (%s)::timestamp, (%s)::interval) AS period", startDate, endDate, interval)
 	countByPeriod := sqlf.Sprintf(`SELECT (%s) AS period, COUNT(%s) AS count
 		FROM event_logs
-		WHERE (%s)
-		GROUP BY period`, period, countExpr, sqlf.Join(conds, ") AND ("))
+		WHERE (%s) AND (%s)
+		GROUP BY period`, period, countExpr, periodsTimestampCond(interval, startDate, endDate), sqlf.Join(conds, ") AND ("))
 	q := sqlf.Sprintf(`WITH all_periods AS (%s), count_by_period AS (%s)
 		SELECT all_periods.period, COALESCE(count, 0)
 		FROM all_periods
@@ -397,6 +567,101 @@ func (l *eventLogs) countPerPeriodBySQL(ctx context.Context, countExpr, interval
 	return counts, nil
 }
 
+// ArgumentGroupPercentiles is the number of events of a time span that have the same values
+// for some fields of their arguments, and the percentiles of another field of these events.
+type ArgumentGroupPercentiles struct {
+	// Values are the values of the grouped fields, in the order in which they were
+	// requested. Missing fields have the empty string as value.
+	Values      []string
+	EventsCount int
+	Percentiles []float64
+}
+
+// PercentilesByArgumentFields calculates the given percentiles over a field of the event's
+// arguments in the time span from startDate (inclusive) to endDate (exclusive), grouped by
+// the values of the given fields of the event's arguments. Groups are ordered by decreasing
+// number of events.
+func (l *eventLogs) PercentilesByArgumentFields(
+	ctx context.Context,
+	startDate, endDate time.Time,
+	groupFields []string,
+	field string,
+	percentiles []float64,
+	opt *EventFilterOptions,
+) ([]ArgumentGroupPercentiles, error) {
+	if len(groupFields) == 0 {
+		return nil, fmt.Errorf("expected at least one argument field in query")
+	}
+	if len(percentiles) == 0 {
+		return nil, fmt.Errorf("expected at least one percentile value in query")
+	}
+
+	groupExprs := make([]*sqlf.Query, 0, len(groupFields))
+	groupRefs := make([]*sqlf.Query, 0, len(groupFields))
+	for i, f := range groupFields {
+		groupExprs = append(groupExprs, sqlf.Sprintf("COALESCE(argument->>%s, '')", f))
+		groupRefs = append(groupRefs, sqlf.Sprintf(fmt.Sprint(i+1)))
+	}
+
+	percentileExprs := make([]*sqlf.Query, 0, len(percentiles))
+	for _, p := range percentiles {
+		percentileExprs = append(percentileExprs, sqlf.Sprintf("percentile_cont(%s) WITHIN GROUP (ORDER BY (argument->%s)::text::integer)", p, field))
+	}
+
+	conds := append([]*sqlf.Query{sqlf.Sprintf("timestamp >= %s AND timestamp < %s", startDate, endDate)}, opt.conds()...)
+	q := sqlf.Sprintf(`SELECT %s, COUNT(*) AS events_count, %s
+		FROM event_logs
+		WHERE (%s)
+		GROUP BY %s
+		ORDER BY events_count DESC, %s`, sqlf.Join(groupExprs, ", "), sqlf.Join(percentileExprs, ", "), sqlf.Join(conds, ") AND ("), sqlf.Join(groupRefs, ", "), sqlf.Join(groupRefs, ", "))
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []ArgumentGroupPercentiles{}
+	for rows.Next() {
+		v := ArgumentGroupPercentiles{Values: make([]string, len(groupFields)), Percentiles: make([]float64, len(percentiles))}
+		dest := make([]interface{}, 0, len(groupFields)+1+len(percentiles))
+		for i := range v.Values {
+			dest = append(dest, &v.Values[i])
+		}
+		dest = append(dest, &v.EventsCount)
+		for i := range v.Percentiles {
+			dest = append(dest, &v.Percentiles[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		groups = append(groups, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ListTopByArgumentField lists the given number of events in the time span from startDate
+// (inclusive) to endDate (exclusive) with the greatest values of an integer field of their
+// arguments, in descending order of the field. Events without the field are ignored.
+func (l *eventLogs) ListTopByArgumentField(ctx context.Context, startDate, endDate time.Time, field string, limit int, opt *EventFilterOptions) ([]*types.Event, error) {
+	conds := append([]*sqlf.Query{
+		sqlf.Sprintf("timestamp >= %s AND timestamp < %s", startDate, endDate),
+		sqlf.Sprintf("argument ? %s", field),
+	}, opt.conds()...)
+	// See calculatePercentilesPerPeriodBySQL for why the field is cast to text first.
+	return l.getBySQL(ctx, sqlf.Sprintf("WHERE (%s) ORDER BY (argument->%s)::text::integer DESC, id LIMIT %s", sqlf.Join(conds, ") AND ("), field, limit))
+}
+
+// periodsTimestampCond restricts events to the periods from startDate up to and including
+// the period starting at endDate. Events outside of these periods are not part of the result
+// anyway, but without this condition they would be aggregated, and Postgres could neither use
+// the timestamp index nor skip the partitions of event_logs outside of the time span.
+func periodsTimestampCond(interval *sqlf.Query, startDate, endDate time.Time) *sqlf.Query {
+	return sqlf.Sprintf(`timestamp >= (%s)::timestamp AND timestamp < (%s)::timestamp + (%s)::interval`, startDate, endDate, interval)
+}
+
 func (l *eventLogs) calculatePercentilesPerPeriodBySQL(
 	ctx context.Context,
 	interval *sqlf.Query,
@@ -426,8 +691,8 @@ func (l *eventLogs) calculatePercentilesPerPeriodBySQL(
 	allPeriods := sqlf.Sprintf("SELECT generate_series((%s)::timestamp, (%s)::timestamp, (%s)::interval) AS period", startDate, endDate, interval)
 	countByPeriod := sqlf.Sprintf(`SELECT (%s) AS period, %s
 		FROM event_logs
-		WHERE (%s)
-		GROUP BY period`, period, sqlf.Join(countByPeriodExprs, ", "), sqlf.Join(conds, ") AND ("))
+		WHERE (%s) AND (%s)
+		GROUP BY period`, period, sqlf.Join(countByPeriodExprs, ", "), periodsTimestampCond(interval, startDate, endDate), sqlf.Join(conds, ") AND ("))
 	q := sqlf.Sprintf(`WITH all_periods AS (%s), values_by_period AS (%s)
 		SELECT all_periods.period, %s
 		FROM all_periods
@@ -0,0 +1,175 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// eventLogsPartitionsAhead is the number of months after the current one for which
+// MaintainPartitions creates partitions, so that events are not inserted into the default
+// partition if it is not run for a while.
+const eventLogsPartitionsAhead = 2
+
+// eventLogsLegacyPartition is the partition of event_logs with the events that were logged
+// before the migration that partitioned event_logs.
+const eventLogsLegacyPartition = "event_logs_legacy"
+
+// eventLogsPartitionName returns the name of the partition of event_logs for the month
+// starting at the given time. It must match the names created by the migration that
+// partitioned event_logs.
+func eventLogsPartitionName(month time.Time) string {
+	return fmt.Sprintf("event_logs_y%04dm%02d", month.Year(), month.Month())
+}
+
+// parseEventLogsPartitionName returns the start of the month of the event_logs partition
+// with the given name. It returns false if the name is not one of a monthly partition.
+func parseEventLogsPartitionName(name string) (time.Time, bool) {
+	var year, month int
+	if n, err := fmt.Sscanf(name, "event_logs_y%04dm%02d", &year, &month); err != nil || n != 2 {
+		return time.Time{}, false
+	}
+	if month < 1 || month > 12 || eventLogsPartitionName(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)) != name {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), true
+}
+
+// MaintainPartitions creates the monthly partitions of event_logs for the month of now and
+// the upcoming months, and drops the partitions whose events are all older than
+// retainSince, including the legacy partition of the events logged before event_logs was
+// partitioned. Events older than retainSince in partitions that are kept have to be deleted
+// by the caller.
+//
+// It does nothing if event_logs is not partitioned, which is the case on Postgres versions
+// older than 11.
+func (*eventLogs) MaintainPartitions(ctx context.Context, now, retainSince time.Time) error {
+	var partitioned bool
+	q := sqlf.Sprintf(`SELECT relkind = 'p' FROM pg_class WHERE oid = 'event_logs'::regclass`)
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&partitioned); err != nil {
+		return errors.Wrap(err, "checking whether event_logs is partitioned")
+	}
+	if !partitioned {
+		return nil
+	}
+
+	now = now.UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= eventLogsPartitionsAhead; i++ {
+		month := thisMonth.AddDate(0, i, 0)
+		if err := createEventLogsPartition(ctx, month); err != nil {
+			return errors.Wrapf(err, "creating partition %s", eventLogsPartitionName(month))
+		}
+	}
+
+	q = sqlf.Sprintf(`
+		SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE pg_inherits.inhparent = 'event_logs'::regclass`)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return errors.Wrap(err, "listing partitions of event_logs")
+	}
+	defer rows.Close()
+
+	var (
+		expired []string
+		legacy  bool
+	)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if month, ok := parseEventLogsPartitionName(name); ok && !month.AddDate(0, 1, 0).After(retainSince) {
+			expired = append(expired, name)
+		}
+		if name == eventLogsLegacyPartition {
+			legacy = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if legacy {
+		// The bounds of the legacy partition are those of its events, so it's dropped
+		// once none of them are retained.
+		var retained bool
+		q := sqlf.Sprintf(`SELECT EXISTS (SELECT 1 FROM `+eventLogsLegacyPartition+` WHERE "timestamp" >= %s)`, retainSince)
+		if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&retained); err != nil {
+			return errors.Wrap(err, "checking for retained events in the legacy partition")
+		}
+		if !retained {
+			expired = append(expired, eventLogsLegacyPartition)
+		}
+	}
+
+	for _, name := range expired {
+		if _, err := dbconn.Global.ExecContext(ctx, "DROP TABLE IF EXISTS "+name); err != nil {
+			return errors.Wrapf(err, "dropping partition %s", name)
+		}
+	}
+	return nil
+}
+
+// createEventLogsPartition creates the partition of event_logs for the month starting at the
+// given time, unless it exists. Events of the month that were inserted into the default
+// partition, because the partition didn't exist yet, are moved to the new partition, since
+// Postgres can't add a partition whose rows are in the default partition.
+func createEventLogsPartition(ctx context.Context, month time.Time) error {
+	name := eventLogsPartitionName(month)
+
+	// The partition name and bounds can't be bind variables in DDL statements, but the
+	// name only consists of characters that don't need to be quoted, and the bounds are
+	// formatted dates in UTC.
+	from := month.Format("2006-01-02") + " 00:00:00+00"
+	to := month.AddDate(0, 1, 0).Format("2006-01-02") + " 00:00:00+00"
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		// Events can't be inserted into the default partition while they're moved.
+		if _, err := tx.ExecContext(ctx, "LOCK TABLE event_logs IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return err
+		}
+
+		var exists, hasDefault bool
+		q := sqlf.Sprintf(`SELECT to_regclass(%s) IS NOT NULL, to_regclass('event_logs_default') IS NOT NULL`, name)
+		if err := tx.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&exists, &hasDefault); err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		// The indexes of event_logs are created on the partition when it's attached.
+		if _, err := tx.ExecContext(ctx, "CREATE TABLE "+name+" (LIKE event_logs INCLUDING DEFAULTS INCLUDING CONSTRAINTS)"); err != nil {
+			return err
+		}
+
+		if hasDefault {
+			q := sqlf.Sprintf(`
+				WITH moved AS (
+					DELETE FROM event_logs_default
+					WHERE "timestamp" >= %s AND "timestamp" < %s
+					RETURNING *
+				)
+				INSERT INTO `+name+` SELECT * FROM moved`,
+				month,
+				month.AddDate(0, 1, 0),
+			)
+			if _, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+				return errors.Wrap(err, "moving events from the default partition")
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE event_logs ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')", name, from, to))
+		return err
+	})
+}
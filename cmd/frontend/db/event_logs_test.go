@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
 )
 
@@ -21,41 +24,45 @@ func TestEventLogs_ValidInfo(t *testing.T) {
 	var testCases = []struct {
 		name  string
 		event *Event
-		err   string // Stringified error
+		err   string // Part of the stringified error, which names the partition of the row if event_logs is partitioned
 	}{
 		{
 			name:  "EmptyName",
 			event: &Event{UserID: 1, URL: "http://sourcegraph.com", Source: "WEB"},
-			err:   `INSERT: pq: new row for relation "event_logs" violates check constraint "event_logs_check_name_not_empty"`,
+			err:   `violates check constraint "event_logs_check_name_not_empty"`,
 		},
 		{
 			name:  "EmptyURL",
 			event: &Event{Name: "test_event", UserID: 1, Source: "WEB"},
-			err:   `INSERT: pq: new row for relation "event_logs" violates check constraint "event_logs_check_url_not_empty"`,
+			err:   `violates check constraint "event_logs_check_url_not_empty"`,
 		},
 		{
 			name:  "InvalidUser",
 			event: &Event{Name: "test_event", URL: "http://sourcegraph.com", Source: "WEB"},
-			err:   `INSERT: pq: new row for relation "event_logs" violates check constraint "event_logs_check_has_user"`,
+			err:   `violates check constraint "event_logs_check_has_user"`,
 		},
 		{
 			name:  "EmptySource",
 			event: &Event{Name: "test_event", URL: "http://sourcegraph.com", UserID: 1},
-			err:   `INSERT: pq: new row for relation "event_logs" violates check constraint "event_logs_check_source_not_empty"`,
+			err:   `violates check constraint "event_logs_check_source_not_empty"`,
 		},
 
 		{
 			name:  "ValidInsert",
 			event: &Event{Name: "test_event", UserID: 1, URL: "http://sourcegraph.com", Source: "WEB"},
-			err:   "<nil>",
+			err:   "",
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			err := EventLogs.Insert(ctx, tc.event)
 
-			if have, want := fmt.Sprint(err), tc.err; have != want {
-				t.Errorf("have %+v, want %+v", have, want)
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("have %+v, want <nil>", err)
+				}
+			} else if have, want := fmt.Sprint(err), tc.err; !strings.Contains(have, want) {
+				t.Errorf("have %+v, want error containing %+v", have, want)
 			}
 		})
 	}
@@ -200,6 +207,234 @@ func TestEventLogs_PercentilesPerPeriod(t *testing.T) {
 	assertPercentileValue(t, values[2], startDate, []float64{30, 42})
 }
 
+func TestEventLogs_PercentilesPerPeriod_ByArgumentRange(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	startDate, _ := calcStartDate(now, Daily, 1)
+
+	events := []*Event{
+		makeTestEvent(&Event{Argument: json.RawMessage(`{"durationMs": 10, "reposCount": 1}`), Timestamp: startDate}),
+		makeTestEvent(&Event{Argument: json.RawMessage(`{"durationMs": 20, "reposCount": 5}`), Timestamp: startDate}),
+		makeTestEvent(&Event{Argument: json.RawMessage(`{"durationMs": 40, "reposCount": 9}`), Timestamp: startDate}),
+		makeTestEvent(&Event{Argument: json.RawMessage(`{"durationMs": 80, "reposCount": 10}`), Timestamp: startDate}),
+		makeTestEvent(&Event{Argument: json.RawMessage(`{"durationMs": 100, "reposCount": 500}`), Timestamp: startDate}),
+	}
+
+	for _, e := range events {
+		if err := EventLogs.Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		argRange ArgumentRange
+		want     float64
+	}{
+		{ArgumentRange{Field: "reposCount", Min: 1, Max: 1}, 10},
+		{ArgumentRange{Field: "reposCount", Min: 2, Max: 9}, 30},
+		{ArgumentRange{Field: "reposCount", Min: 10}, 90},
+	} {
+		argRange := tc.argRange
+		values, err := EventLogs.PercentilesPerPeriod(ctx, Daily, now, 1, "durationMs", []float64{0.5}, &EventFilterOptions{
+			ByArgumentRange: &argRange,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertPercentileValue(t, values[0], startDate, []float64{tc.want})
+	}
+}
+
+func TestEventLogs_CountByArgumentFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	startDate, _ := calcStartDate(now, Daily, 1)
+
+	events := []*Event{
+		makeTestEvent(&Event{UserID: 1, Argument: json.RawMessage(`{"indexer": "lsif-go", "language": "Go"}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 1, Argument: json.RawMessage(`{"indexer": "lsif-go", "language": "Go"}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"indexer": "lsif-go", "language": "Go"}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"indexer": "lsif-tsc", "language": "TypeScript"}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 3, Argument: json.RawMessage(`{"language": "Python"}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 3, Argument: json.RawMessage(`{"language": "Python"}`), Timestamp: startDate}),
+		// Outside of the time span
+		makeTestEvent(&Event{UserID: 4, Argument: json.RawMessage(`{"indexer": "lsif-go", "language": "Go"}`), Timestamp: startDate.AddDate(0, 0, -2)}),
+	}
+
+	for _, e := range events {
+		if err := EventLogs.Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	values, err := EventLogs.CountByArgumentFields(ctx, startDate, startDate.AddDate(0, 0, 1), []string{"indexer", "language"}, &EventFilterOptions{
+		ByEventName: "foo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ArgumentGroupValue{
+		{Values: []string{"lsif-go", "Go"}, EventsCount: 3, UsersCount: 2},
+		{Values: []string{"", "Python"}, EventsCount: 2, UsersCount: 1},
+		{Values: []string{"lsif-tsc", "TypeScript"}, EventsCount: 1, UsersCount: 1},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %+v, want %+v", values, want)
+	}
+}
+
+func TestEventLogs_PercentilesByArgumentFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	startDate, _ := calcStartDate(now, Daily, 1)
+
+	events := []*Event{
+		makeTestEvent(&Event{UserID: 1, Argument: json.RawMessage(`{"filters": "file,repo", "durationMs": 100}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 1, Argument: json.RawMessage(`{"filters": "file,repo", "durationMs": 200}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"filters": "file,repo", "durationMs": 300}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"durationMs": 50}`), Timestamp: startDate}),
+		// Outside of the time span
+		makeTestEvent(&Event{UserID: 3, Argument: json.RawMessage(`{"filters": "file,repo", "durationMs": 1000}`), Timestamp: startDate.AddDate(0, 0, -2)}),
+	}
+
+	for _, e := range events {
+		if err := EventLogs.Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	values, err := EventLogs.PercentilesByArgumentFields(ctx, startDate, startDate.AddDate(0, 0, 1), []string{"filters"}, "durationMs", []float64{0.5}, &EventFilterOptions{
+		ByEventName: "foo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ArgumentGroupPercentiles{
+		{Values: []string{"file,repo"}, EventsCount: 3, Percentiles: []float64{200}},
+		{Values: []string{""}, EventsCount: 1, Percentiles: []float64{50}},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %+v, want %+v", values, want)
+	}
+}
+
+func TestEventLogs_ListTopByArgumentField(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	startDate, _ := calcStartDate(now, Daily, 1)
+
+	events := []*Event{
+		makeTestEvent(&Event{UserID: 1, Argument: json.RawMessage(`{"durationMs": 100}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 1, Argument: json.RawMessage(`{"durationMs": 3000}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"durationMs": 200}`), Timestamp: startDate}),
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"durationMs": 50}`), Timestamp: startDate}),
+		// Without the field
+		makeTestEvent(&Event{UserID: 2, Argument: json.RawMessage(`{"reposCount": 5000}`), Timestamp: startDate}),
+		// Outside of the time span
+		makeTestEvent(&Event{UserID: 3, Argument: json.RawMessage(`{"durationMs": 5000}`), Timestamp: startDate.AddDate(0, 0, -2)}),
+	}
+
+	for _, e := range events {
+		if err := EventLogs.Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	have, err := EventLogs.ListTopByArgumentField(ctx, startDate, startDate.AddDate(0, 0, 1), "durationMs", 3, &EventFilterOptions{
+		ByEventName: "foo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var arguments []string
+	for _, e := range have {
+		arguments = append(arguments, e.Argument)
+	}
+	want := []string{`{"durationMs": 3000}`, `{"durationMs": 200}`, `{"durationMs": 100}`}
+	if !reflect.DeepEqual(arguments, want) {
+		t.Errorf("got %+v, want %+v", arguments, want)
+	}
+}
+
+func TestEventLogs_CountRetainedUsersPerWeeklyCohort(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	firstWeek, _ := calcStartDate(now, Weekly, 3)
+	secondWeek := firstWeek.AddDate(0, 0, 7)
+	thirdWeek := firstWeek.AddDate(0, 0, 14)
+
+	events := []*Event{
+		// User 5 was active before the first week, so it belongs to an
+		// older cohort and is not counted.
+		makeTestEvent(&Event{UserID: 5, Timestamp: firstWeek.AddDate(0, 0, -7)}),
+		makeTestEvent(&Event{UserID: 5, Timestamp: secondWeek}),
+
+		makeTestEvent(&Event{UserID: 1, Timestamp: firstWeek}),
+		makeTestEvent(&Event{UserID: 2, Timestamp: firstWeek}),
+		makeTestEvent(&Event{UserID: 2, Timestamp: firstWeek}),
+
+		makeTestEvent(&Event{UserID: 1, Timestamp: secondWeek}),
+		makeTestEvent(&Event{UserID: 3, Timestamp: secondWeek}),
+
+		makeTestEvent(&Event{UserID: 1, Timestamp: thirdWeek}),
+		makeTestEvent(&Event{UserID: 2, Timestamp: thirdWeek}),
+		makeTestEvent(&Event{UserID: 3, Timestamp: thirdWeek}),
+		makeTestEvent(&Event{UserID: 4, Timestamp: thirdWeek}),
+	}
+
+	for _, e := range events {
+		if err := EventLogs.Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	values, err := EventLogs.CountRetainedUsersPerWeeklyCohort(ctx, now, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RetentionValue{
+		{CohortStart: thirdWeek, WeeksAfter: 0, Count: 1},
+		{CohortStart: secondWeek, WeeksAfter: 0, Count: 1},
+		{CohortStart: secondWeek, WeeksAfter: 1, Count: 1},
+		{CohortStart: firstWeek, WeeksAfter: 0, Count: 2},
+		{CohortStart: firstWeek, WeeksAfter: 1, Count: 1},
+		{CohortStart: firstWeek, WeeksAfter: 2, Count: 2},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %+v, want %+v", values, want)
+	}
+}
+
 // makeTestEvent sets the required (uninteresting) fields that are required on insertion
 // due to db constraints. This method will also add some sub-day jitter to the timestamp.
 func makeTestEvent(e *Event) *Event {
@@ -233,3 +468,190 @@ func assertPercentileValue(t *testing.T, v PercentileValue, start time.Time, val
 		}
 	}
 }
+
+func TestParseEventLogsPartitionName(t *testing.T) {
+	for name, want := range map[string]time.Time{
+		"event_logs_y2020m01": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		"event_logs_y2019m12": time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC),
+		"event_logs_y2020m13": {},
+		"event_logs_y2020m1":  {},
+		"event_logs_default":  {},
+	} {
+		have, ok := parseEventLogsPartitionName(name)
+		if ok != !want.IsZero() || !have.Equal(want) {
+			t.Errorf("%s: have %v (%v), want %v", name, have, ok, want)
+		}
+		if ok && eventLogsPartitionName(have) != name {
+			t.Errorf("%s: round trip yields %s", name, eventLogsPartitionName(have))
+		}
+	}
+}
+
+func TestEventLogs_MaintainPartitions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	var partitioned bool
+	if err := dbconn.Global.QueryRow(`SELECT relkind = 'p' FROM pg_class WHERE oid = 'event_logs'::regclass`).Scan(&partitioned); err != nil {
+		t.Fatal(err)
+	}
+	if !partitioned {
+		t.Skip("event_logs is not partitioned on Postgres versions older than 11")
+	}
+
+	// The months of 1990 have no partitions, so their events are inserted into the
+	// default partition.
+	insert := func(ts time.Time) {
+		t.Helper()
+		e := &Event{Name: "test_event", URL: "http://sourcegraph.com", UserID: 1, Source: "WEB", Timestamp: ts}
+		if err := EventLogs.Insert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert(time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC))
+	// The last hour of January in UTC, but February in UTC+1.
+	insert(time.Date(1990, 2, 1, 0, 30, 0, 0, time.FixedZone("UTC+1", 60*60)))
+	insert(time.Date(1990, 2, 10, 0, 0, 0, 0, time.UTC))
+	insert(time.Date(1990, 3, 20, 0, 0, 0, 0, time.UTC))
+
+	partitionsOfEvents := func() []string {
+		t.Helper()
+		rows, err := dbconn.Global.Query(`SELECT tableoid::regclass::text FROM event_logs WHERE "timestamp" < '1991-01-01' ORDER BY "timestamp"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var names []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, name)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return names
+	}
+
+	partitions := func() []string {
+		t.Helper()
+		rows, err := dbconn.Global.Query(`
+			SELECT child.relname
+			FROM pg_inherits
+			JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+			WHERE pg_inherits.inhparent = 'event_logs'::regclass
+			AND child.relname LIKE 'event_logs_y1990%'
+			ORDER BY child.relname`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var names []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, name)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return names
+	}
+
+	want := []string{"event_logs_default", "event_logs_default", "event_logs_default", "event_logs_default"}
+	if have := partitionsOfEvents(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("have events in partitions %v, want %v", have, want)
+	}
+
+	retainAll := time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The events of the new partitions are moved out of the default partition.
+	if err := EventLogs.MaintainPartitions(ctx, time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC), retainAll); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := partitions(), []string{"event_logs_y1990m01", "event_logs_y1990m02", "event_logs_y1990m03"}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have partitions %v, want %v", have, want)
+	}
+	want = []string{"event_logs_default", "event_logs_y1990m01", "event_logs_y1990m02", "event_logs_y1990m03"}
+	if have := partitionsOfEvents(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("have events in partitions %v, want %v", have, want)
+	}
+
+	// Existing partitions are kept.
+	if err := EventLogs.MaintainPartitions(ctx, time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC), retainAll); err != nil {
+		t.Fatal(err)
+	}
+	if have := partitionsOfEvents(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("have events in partitions %v, want %v", have, want)
+	}
+
+	// The new partitions have the indexes and constraints of event_logs.
+	insert(time.Date(1990, 2, 15, 0, 0, 0, 0, time.UTC))
+	err := EventLogs.Insert(ctx, &Event{Name: "test_event", URL: "http://sourcegraph.com", Source: "WEB", Timestamp: time.Date(1990, 2, 15, 0, 0, 0, 0, time.UTC)})
+	if have, want := fmt.Sprint(err), `violates check constraint "event_logs_check_has_user"`; !strings.Contains(have, want) {
+		t.Fatalf("have %+v, want error containing %+v", have, want)
+	}
+	var indexes int
+	if err := dbconn.Global.QueryRow(`SELECT COUNT(*) FROM pg_indexes WHERE tablename = 'event_logs_y1990m02'`).Scan(&indexes); err != nil {
+		t.Fatal(err)
+	}
+	if indexes == 0 {
+		t.Fatal("new partition has no indexes")
+	}
+
+	// The partitions whose events are all expired are dropped.
+	if err := EventLogs.MaintainPartitions(ctx, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(1990, 2, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := partitions(), []string{"event_logs_y1990m02", "event_logs_y1990m03", "event_logs_y1990m04", "event_logs_y1990m05"}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have partitions %v, want %v", have, want)
+	}
+	want = []string{"event_logs_default", "event_logs_y1990m02", "event_logs_y1990m02", "event_logs_y1990m03"}
+	if have := partitionsOfEvents(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("have events in partitions %v, want %v", have, want)
+	}
+
+	// The legacy partition with the events logged before event_logs was partitioned is
+	// dropped once all of its events are expired.
+	for _, q := range []string{
+		`CREATE TABLE event_logs_legacy (LIKE event_logs INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`,
+		`ALTER TABLE event_logs ATTACH PARTITION event_logs_legacy FOR VALUES FROM ('1985-01-01 00:00:00+00') TO ('1986-01-01 00:00:00+00')`,
+	} {
+		if _, err := dbconn.Global.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert(time.Date(1985, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	legacyExists := func() bool {
+		t.Helper()
+		var exists bool
+		if err := dbconn.Global.QueryRow(`SELECT to_regclass('event_logs_legacy') IS NOT NULL`).Scan(&exists); err != nil {
+			t.Fatal(err)
+		}
+		return exists
+	}
+
+	if err := EventLogs.MaintainPartitions(ctx, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(1985, 5, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if !legacyExists() {
+		t.Fatal("legacy partition with retained events was dropped")
+	}
+
+	if err := EventLogs.MaintainPartitions(ctx, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(1985, 7, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if legacyExists() {
+		t.Fatal("legacy partition with expired events was not dropped")
+	}
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// featureFlags provides access to the values of feature flags stored in the
+// feature_flags table, and to the overrides of feature flags for users and
+// orgs stored in the feature_flag_overrides table.
+//
+// Feature flags are declared in code by the featureflag package. A flag
+// without a row in feature_flags has the default value of its declaration.
+type featureFlags struct{}
+
+type featureFlagNotFoundErr struct {
+	name string
+}
+
+func (err featureFlagNotFoundErr) Error() string {
+	return fmt.Sprintf("feature flag not found: %q", err.name)
+}
+
+func (featureFlagNotFoundErr) NotFound() bool { return true }
+
+var errFeatureFlagOverrideNamespace = errors.New("feature flag override must be for exactly one of a user or an org")
+
+const featureFlagColumns = `name, enabled, rollout_percentage, created_at, updated_at`
+
+// List returns the stored values of all feature flags, ordered by name.
+func (*featureFlags) List(ctx context.Context) ([]*types.FeatureFlag, error) {
+	q := sqlf.Sprintf(`SELECT ` + featureFlagColumns + ` FROM feature_flags ORDER BY name`)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []*types.FeatureFlag
+	for rows.Next() {
+		f, err := scanFeatureFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, f)
+	}
+	return flags, rows.Err()
+}
+
+// GetByName returns the stored value of the feature flag with the given name.
+// An error satisfying errcode.IsNotFound is returned if the flag has none.
+func (*featureFlags) GetByName(ctx context.Context, name string) (*types.FeatureFlag, error) {
+	q := sqlf.Sprintf(`SELECT `+featureFlagColumns+` FROM feature_flags WHERE name = %s`, name)
+	f, err := scanFeatureFlag(dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
+	if err == sql.ErrNoRows {
+		return nil, featureFlagNotFoundErr{name: name}
+	}
+	return f, err
+}
+
+// Upsert stores the value of the given feature flag, replacing its previous
+// value, and updates the timestamps of f.
+func (*featureFlags) Upsert(ctx context.Context, f *types.FeatureFlag) error {
+	var rollout interface{}
+	if f.RolloutPercentage != nil {
+		rollout = *f.RolloutPercentage
+	}
+
+	q := sqlf.Sprintf(`
+		INSERT INTO feature_flags (name, enabled, rollout_percentage)
+		VALUES (%s, %s, %s)
+		ON CONFLICT (name) DO UPDATE SET
+			enabled = excluded.enabled,
+			rollout_percentage = excluded.rollout_percentage,
+			updated_at = now()
+		RETURNING created_at, updated_at`,
+		f.Name, f.Enabled, rollout,
+	)
+	return dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&f.CreatedAt, &f.UpdatedAt)
+}
+
+// Delete deletes the stored value of the feature flag with the given name, so
+// that it has its default value again. The overrides of the flag are kept.
+func (*featureFlags) Delete(ctx context.Context, name string) error {
+	q := sqlf.Sprintf(`DELETE FROM feature_flags WHERE name = %s`, name)
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return featureFlagNotFoundErr{name: name}
+	}
+	return nil
+}
+
+const featureFlagOverrideColumns = `flag_name, namespace_user_id, namespace_org_id, enabled, created_at, updated_at`
+
+// ListOverrides returns the overrides of the feature flag with the given name,
+// ordered by creation.
+func (*featureFlags) ListOverrides(ctx context.Context, flagName string) ([]*types.FeatureFlagOverride, error) {
+	return listFeatureFlagOverrides(ctx, sqlf.Sprintf(`
+		SELECT `+featureFlagOverrideColumns+`
+		FROM feature_flag_overrides
+		WHERE flag_name = %s
+		ORDER BY id`,
+		flagName,
+	))
+}
+
+// ListOverridesForUser returns the overrides of all feature flags that apply
+// to the user with the given ID: those for the user and those for the orgs the
+// user is a member of.
+func (*featureFlags) ListOverridesForUser(ctx context.Context, userID int32) ([]*types.FeatureFlagOverride, error) {
+	return listFeatureFlagOverrides(ctx, sqlf.Sprintf(`
+		SELECT `+featureFlagOverrideColumns+`
+		FROM feature_flag_overrides
+		WHERE namespace_user_id = %s
+		OR namespace_org_id IN (SELECT org_id FROM org_members WHERE user_id = %s)
+		ORDER BY id`,
+		userID, userID,
+	))
+}
+
+// UpsertOverride stores the given override of a feature flag, replacing a
+// previous override of the flag for the same user or org.
+func (*featureFlags) UpsertOverride(ctx context.Context, o *types.FeatureFlagOverride) error {
+	var q *sqlf.Query
+	switch {
+	case o.NamespaceUserID != 0 && o.NamespaceOrgID == 0:
+		q = sqlf.Sprintf(`
+			INSERT INTO feature_flag_overrides (flag_name, namespace_user_id, enabled)
+			VALUES (%s, %s, %s)
+			ON CONFLICT (flag_name, namespace_user_id) WHERE namespace_user_id IS NOT NULL DO UPDATE SET
+				enabled = excluded.enabled,
+				updated_at = now()
+			RETURNING created_at, updated_at`,
+			o.FlagName, o.NamespaceUserID, o.Enabled,
+		)
+	case o.NamespaceOrgID != 0 && o.NamespaceUserID == 0:
+		q = sqlf.Sprintf(`
+			INSERT INTO feature_flag_overrides (flag_name, namespace_org_id, enabled)
+			VALUES (%s, %s, %s)
+			ON CONFLICT (flag_name, namespace_org_id) WHERE namespace_org_id IS NOT NULL DO UPDATE SET
+				enabled = excluded.enabled,
+				updated_at = now()
+			RETURNING created_at, updated_at`,
+			o.FlagName, o.NamespaceOrgID, o.Enabled,
+		)
+	default:
+		return errFeatureFlagOverrideNamespace
+	}
+	return dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&o.CreatedAt, &o.UpdatedAt)
+}
+
+// DeleteOverride deletes the override of the feature flag with the given name
+// for the user or org with the given ID. Exactly one of userID and orgID must
+// be nonzero. Deleting a nonexistent override is not an error.
+func (*featureFlags) DeleteOverride(ctx context.Context, flagName string, userID, orgID int32) error {
+	var q *sqlf.Query
+	switch {
+	case userID != 0 && orgID == 0:
+		q = sqlf.Sprintf(`DELETE FROM feature_flag_overrides WHERE flag_name = %s AND namespace_user_id = %s`, flagName, userID)
+	case orgID != 0 && userID == 0:
+		q = sqlf.Sprintf(`DELETE FROM feature_flag_overrides WHERE flag_name = %s AND namespace_org_id = %s`, flagName, orgID)
+	default:
+		return errFeatureFlagOverrideNamespace
+	}
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+func listFeatureFlagOverrides(ctx context.Context, q *sqlf.Query) ([]*types.FeatureFlagOverride, error) {
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overrides []*types.FeatureFlagOverride
+	for rows.Next() {
+		var o types.FeatureFlagOverride
+		if err := rows.Scan(
+			&o.FlagName,
+			&dbutil.NullInt32{N: &o.NamespaceUserID},
+			&dbutil.NullInt32{N: &o.NamespaceOrgID},
+			&o.Enabled,
+			&o.CreatedAt,
+			&o.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, &o)
+	}
+	return overrides, rows.Err()
+}
+
+func scanFeatureFlag(s interface{ Scan(...interface{}) error }) (*types.FeatureFlag, error) {
+	var (
+		f       types.FeatureFlag
+		rollout sql.NullInt64
+	)
+	if err := s.Scan(&f.Name, &f.Enabled, &rollout, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if rollout.Valid {
+		p := int32(rollout.Int64)
+		f.RolloutPercentage = &p
+	}
+	return &f, nil
+}
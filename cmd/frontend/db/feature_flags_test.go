@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	if _, err := FeatureFlags.GetByName(ctx, "a"); !errcode.IsNotFound(err) {
+		t.Fatalf("have err %v, want not found", err)
+	}
+
+	rollout := int32(25)
+	a := &types.FeatureFlag{Name: "a", Enabled: true}
+	b := &types.FeatureFlag{Name: "b", RolloutPercentage: &rollout}
+	for _, f := range []*types.FeatureFlag{b, a} {
+		if err := FeatureFlags.Upsert(ctx, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Upserting a flag again replaces its value.
+	a.Enabled = false
+	if err := FeatureFlags.Upsert(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := FeatureFlags.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*types.FeatureFlag{a, b}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have %+v, want %+v", have, want)
+	}
+
+	if err := FeatureFlags.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := FeatureFlags.Delete(ctx, "a"); !errcode.IsNotFound(err) {
+		t.Fatalf("have err %v, want not found", err)
+	}
+	if got, err := FeatureFlags.GetByName(ctx, "b"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, b) {
+		t.Fatalf("have %+v, want %+v", got, b)
+	}
+}
+
+func TestFeatureFlagOverrides(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2"} {
+		u, err := Users.Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, u.ID)
+	}
+	org, err := Orgs.Create(ctx, "o", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OrgMembers.Create(ctx, org.ID, userIDs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := []*types.FeatureFlagOverride{
+		{FlagName: "a", NamespaceUserID: userIDs[0], Enabled: true},
+		{FlagName: "a", NamespaceUserID: userIDs[1], Enabled: true},
+		{FlagName: "b", NamespaceOrgID: org.ID, Enabled: false},
+	}
+	for _, o := range overrides {
+		if err := FeatureFlags.UpsertOverride(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Overriding a flag again for the same user replaces the override.
+	overrides[0].Enabled = false
+	if err := FeatureFlags.UpsertOverride(ctx, overrides[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := FeatureFlags.UpsertOverride(ctx, &types.FeatureFlagOverride{FlagName: "a"}); err == nil {
+		t.Fatal("have no error for override without user or org")
+	}
+
+	have, err := FeatureFlags.ListOverrides(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := overrides[:2]; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have %+v, want %+v", have, want)
+	}
+
+	// The overrides of a user include those of their orgs.
+	have, err = FeatureFlags.ListOverridesForUser(ctx, userIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*types.FeatureFlagOverride{overrides[0], overrides[2]}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("have %+v, want %+v", have, want)
+	}
+
+	if err := FeatureFlags.DeleteOverride(ctx, "a", userIDs[0], 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := FeatureFlags.DeleteOverride(ctx, "b", 0, org.ID); err != nil {
+		t.Fatal(err)
+	}
+	have, err = FeatureFlags.ListOverridesForUser(ctx, userIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != 0 {
+		t.Fatalf("have overrides %+v, want none", have)
+	}
+}
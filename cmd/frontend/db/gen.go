@@ -1,4 +1,4 @@
 package db
 
 // $PGHOST, $PGUSER, $PGPORT etc. must be set to run this generate script.
-//go:generate env GO111MODULE=on go run schemadoc/main.go schema.md
+//go:generate env GO111MODULE=on go run schemadoc/main.go schema.md schema_doc.go
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// LSIFUploadToken describes an LSIF upload token. Unlike an access token, it
+// doesn't act on behalf of a user: it only authorizes LSIF uploads to a
+// single repository, e.g. from CI jobs. The actual token is not stored and is
+// not present in this struct.
+type LSIFUploadToken struct {
+	ID            int64
+	RepoID        api.RepoID
+	Note          string
+	CreatorUserID int32
+	CreatedAt     time.Time
+	LastUsedAt    *time.Time
+}
+
+// ErrLSIFUploadTokenNotFound occurs when a database operation expects a
+// specific LSIF upload token to exist but it does not exist.
+var ErrLSIFUploadTokenNotFound = errors.New("lsif upload token not found")
+
+type lsifUploadTokens struct{}
+
+// Create creates an LSIF upload token for the specified repository. The
+// secret token value itself is returned and, like for access tokens (see
+// (*accessTokens).Create), only its SHA-256 hash is stored.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to create
+// upload tokens for the repository.
+func (s *lsifUploadTokens) Create(ctx context.Context, repoID api.RepoID, note string, creatorUserID int32) (id int64, token string, err error) {
+	var b [20]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, "", err
+	}
+	token = hex.EncodeToString(b[:])
+
+	if err := dbconn.Global.QueryRowContext(ctx,
+		// Ensure that the repository and the creator have not been deleted. If
+		// they were, no row is inserted.
+		`
+INSERT INTO lsif_upload_tokens(repo_id, value_sha256, note, creator_user_id)
+SELECT repo.id, $2::bytea, $3::text, creator_user.id
+FROM repo, users creator_user
+WHERE repo.id=$1 AND repo.deleted_at IS NULL AND
+  creator_user.id=$4 AND creator_user.deleted_at IS NULL
+RETURNING lsif_upload_tokens.id
+`,
+		repoID, toSHA256Bytes(b[:]), note, creatorUserID,
+	).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, "", errors.New("repository or creator of lsif upload token not found")
+		}
+		return 0, "", err
+	}
+	return id, token, nil
+}
+
+// Lookup looks up the LSIF upload token. If it's valid for the specified
+// repository, it returns nil. Otherwise ErrLSIFUploadTokenNotFound is
+// returned.
+//
+// Calling Lookup also updates the token's last-used-at date.
+//
+// 🚨 SECURITY: This returns nil if and only if the tokenHexEncoded corresponds
+// to a non-deleted upload token of the repository whose creator has not been
+// deleted.
+func (s *lsifUploadTokens) Lookup(ctx context.Context, tokenHexEncoded string, repoID api.RepoID) error {
+	token, err := hex.DecodeString(tokenHexEncoded)
+	if err != nil {
+		return errors.Wrap(err, "LSIFUploadTokens.Lookup")
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		// Ensure that the creator still exists.
+		`
+UPDATE lsif_upload_tokens t SET last_used_at=now()
+FROM users creator_user
+WHERE t.value_sha256=$1 AND t.repo_id=$2 AND t.deleted_at IS NULL AND
+  t.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
+`,
+		toSHA256Bytes(token), repoID,
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrLSIFUploadTokenNotFound
+	}
+	return nil
+}
+
+// GetByID retrieves the LSIF upload token (if any) given its ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view
+// this token.
+func (s *lsifUploadTokens) GetByID(ctx context.Context, id int64) (*LSIFUploadToken, error) {
+	results, err := s.list(ctx, sqlf.Sprintf("id=%d", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrLSIFUploadTokenNotFound
+	}
+	return results[0], nil
+}
+
+// List lists the LSIF upload tokens of the specified repository, most
+// recently created first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list
+// the tokens of the repository.
+func (s *lsifUploadTokens) List(ctx context.Context, repoID api.RepoID) ([]*LSIFUploadToken, error) {
+	return s.list(ctx, sqlf.Sprintf("repo_id=%d", repoID))
+}
+
+func (s *lsifUploadTokens) list(ctx context.Context, cond *sqlf.Query) ([]*LSIFUploadToken, error) {
+	q := sqlf.Sprintf(`
+SELECT id, repo_id, note, creator_user_id, created_at, last_used_at FROM lsif_upload_tokens
+WHERE (%s) AND deleted_at IS NULL
+ORDER BY created_at DESC, id DESC`,
+		cond,
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*LSIFUploadToken
+	for rows.Next() {
+		var t LSIFUploadToken
+		if err := rows.Scan(&t.ID, &t.RepoID, &t.Note, &t.CreatorUserID, &t.CreatedAt, &t.LastUsedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &t)
+	}
+	return results, rows.Err()
+}
+
+// DeleteByID deletes and immediately revokes an LSIF upload token given its
+// ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to delete
+// the token.
+func (s *lsifUploadTokens) DeleteByID(ctx context.Context, id int64) error {
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE lsif_upload_tokens SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrLSIFUploadTokenNotFound
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+// 🚨 SECURITY: This tests that LSIF upload tokens only authorize uploads to
+// their repository until they are revoked.
+func TestLSIFUploadTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	creator, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []api.RepoName{"repo1", "repo2"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo1, err := Repos.GetByName(ctx, "repo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo2, err := Repos.GetByName(ctx, "repo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, token, err := LSIFUploadTokens.Create(ctx, repo1.ID, "ci", creator.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LSIFUploadTokens.GetByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RepoID != repo1.ID || got.Note != "ci" || got.CreatorUserID != creator.ID || got.LastUsedAt != nil {
+		t.Errorf("unexpected token %+v", got)
+	}
+
+	if err := LSIFUploadTokens.Lookup(ctx, token, repo1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := LSIFUploadTokens.Lookup(ctx, token, repo2.ID); err != ErrLSIFUploadTokenNotFound {
+		t.Errorf("got err %v, want %v for token of other repository", err, ErrLSIFUploadTokenNotFound)
+	}
+	if err := LSIFUploadTokens.Lookup(ctx, "abcd", repo1.ID); err != ErrLSIFUploadTokenNotFound {
+		t.Errorf("got err %v, want %v for unknown token", err, ErrLSIFUploadTokenNotFound)
+	}
+
+	tokens, err := LSIFUploadTokens.List(ctx, repo1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 || tokens[0].ID != id || tokens[0].LastUsedAt == nil {
+		t.Errorf("unexpected tokens %+v", tokens)
+	}
+	if tokens, err := LSIFUploadTokens.List(ctx, repo2.ID); err != nil || len(tokens) != 0 {
+		t.Errorf("got tokens %+v, err %v for other repository", tokens, err)
+	}
+
+	if err := LSIFUploadTokens.DeleteByID(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := LSIFUploadTokens.Lookup(ctx, token, repo1.ID); err != ErrLSIFUploadTokenNotFound {
+		t.Errorf("got err %v, want %v for revoked token", err, ErrLSIFUploadTokenNotFound)
+	}
+	if err := LSIFUploadTokens.DeleteByID(ctx, id); err != ErrLSIFUploadTokenNotFound {
+		t.Errorf("got err %v, want %v for deleting revoked token", err, ErrLSIFUploadTokenNotFound)
+	}
+}
+
+func TestLSIFUploadTokens_Lookup_deletedCreator(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	creator, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "repo", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, token, err := LSIFUploadTokens.Create(ctx, repo.ID, "ci", creator.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LSIFUploadTokens.Lookup(ctx, token, repo.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Users.Delete(ctx, creator.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := LSIFUploadTokens.Lookup(ctx, token, repo.ID); err != ErrLSIFUploadTokenNotFound {
+		t.Errorf("got err %v, want %v for token of deleted creator", err, ErrLSIFUploadTokenNotFound)
+	}
+}
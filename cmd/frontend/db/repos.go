@@ -119,6 +119,19 @@ func (s *repos) GetByIDs(ctx context.Context, ids ...api.RepoID) ([]*types.Repo,
 	return s.getReposBySQL(ctx, false, true, q)
 }
 
+// GetByIDsWithPerms returns the repositories with the given IDs on which the current user has the
+// given permissions. Like GetByIDs, the number of results could be less than the number of IDs.
+//
+// 🚨 SECURITY: This enforces repository permissions for p, so that callers can check permissions
+// other than authz.Read.
+func (s *repos) GetByIDsWithPerms(ctx context.Context, p authz.Perms, ids ...api.RepoID) ([]*types.Repo, error) {
+	repos, err := s.GetByIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	return authzFilter(ctx, repos, p)
+}
+
 func (s *repos) Count(ctx context.Context, opt ReposListOptions) (int, error) {
 	if Mocks.Repos.Count != nil {
 		return Mocks.Repos.Count(ctx, opt)
@@ -233,6 +246,9 @@ type ReposListOptions struct {
 	// Direction options are ignored
 	Query string
 
+	// IDs, if non-empty, restricts the list to the repositories with these IDs.
+	IDs []api.RepoID
+
 	// IncludePatterns is a list of regular expressions, all of which must match all
 	// repositories returned in the list.
 	IncludePatterns []string
@@ -401,6 +417,13 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	if opt.Query != "" {
 		conds = append(conds, sqlf.Sprintf("lower(name) LIKE %s", "%"+strings.ToLower(opt.Query)+"%"))
 	}
+	if len(opt.IDs) > 0 {
+		ids := make([]*sqlf.Query, 0, len(opt.IDs))
+		for _, id := range opt.IDs {
+			ids = append(ids, sqlf.Sprintf("%d", id))
+		}
+		conds = append(conds, sqlf.Sprintf("id IN (%s)", sqlf.Join(ids, ",")))
+	}
 	for _, includePattern := range opt.IncludePatterns {
 		extraConds, err := parsePattern(includePattern)
 		if err != nil {
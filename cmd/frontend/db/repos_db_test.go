@@ -235,6 +235,48 @@ func TestRepos_GetByIDs(t *testing.T) {
 	}
 }
 
+func TestRepos_GetByIDsWithPerms(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	// Only the first repository is writable.
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		if p == authz.Read {
+			return repos, nil
+		}
+		var filtered []*types.Repo
+		for _, r := range repos {
+			if r.Name == "r1" {
+				filtered = append(filtered, r)
+			}
+		}
+		return filtered, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	created := mustCreate(ctx, t, &types.Repo{Name: "r1"}, &types.Repo{Name: "r2"})
+
+	for _, tc := range []struct {
+		perms authz.Perms
+		want  []api.RepoName
+	}{
+		{perms: authz.Read, want: []api.RepoName{"r1", "r2"}},
+		{perms: authz.Write, want: []api.RepoName{"r1"}},
+	} {
+		repos, err := Repos.GetByIDsWithPerms(ctx, tc.perms, created[0].ID, created[1].ID, 404)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := sortedRepoNames(repos); !reflect.DeepEqual(have, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.perms, have, tc.want)
+		}
+	}
+}
+
 func TestRepos_List(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -306,6 +348,30 @@ func TestRepos_List_fork(t *testing.T) {
 	}
 }
 
+func TestRepos_List_ids(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	a := mustCreate(ctx, t, &types.Repo{Name: "a/r"})
+	mustCreate(ctx, t, &types.Repo{Name: "b/r"})
+	c := mustCreate(ctx, t, &types.Repo{Name: "c/r"})
+
+	repos, err := Repos.List(ctx, ReposListOptions{IDs: []api.RepoID{a[0].ID, c[0].ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, append(append([]*types.Repo(nil), a...), c...), repos)
+}
+
 func TestRepos_List_pagination(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -0,0 +1,440 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/migrations"
+)
+
+// SchemaDrift is the result of comparing the schema of the database with the one that the
+// migrations create.
+type SchemaDrift struct {
+	// Version is the version of the last migration applied to the database, or 0 if none was.
+	Version int64
+	// Dirty is whether the last migration failed before it completed.
+	Dirty bool
+	// PendingMigrations are the names of the up migrations that weren't applied yet, in the
+	// order in which they would be.
+	PendingMigrations []string
+	// Differences are the tables, columns, indexes and constraints that differ from
+	// schema.md, ordered by table.
+	Differences []*SchemaDifference
+}
+
+// Drifted returns whether the database isn't in the state that the migrations leave it in.
+func (d *SchemaDrift) Drifted() bool {
+	return d.Dirty || len(d.PendingMigrations) > 0 || len(d.Differences) > 0
+}
+
+// SchemaObjectKind is the kind of a database object compared by CheckSchemaDrift.
+type SchemaObjectKind string
+
+const (
+	SchemaObjectTable      SchemaObjectKind = "table"
+	SchemaObjectColumn     SchemaObjectKind = "column"
+	SchemaObjectIndex      SchemaObjectKind = "index"
+	SchemaObjectConstraint SchemaObjectKind = "constraint"
+)
+
+// schemaObjectKinds are the kinds of database objects in the order in which differences are
+// reported within a table.
+var schemaObjectKinds = []SchemaObjectKind{
+	SchemaObjectTable,
+	SchemaObjectColumn,
+	SchemaObjectIndex,
+	SchemaObjectConstraint,
+}
+
+// SchemaDifference is a database object that is missing, unexpected or defined differently
+// than in schema.md. Definitions are formatted like the output of psql's \d command.
+type SchemaDifference struct {
+	Kind SchemaObjectKind
+	// Table is the name of the table, or of the table the column, index or constraint
+	// belongs to.
+	Table string
+	// Name is the name of the column, index or constraint, or empty for tables.
+	Name string
+	// Expected is the definition in schema.md, or empty if the object is unexpected.
+	Expected string
+	// Actual is the definition in the database, or empty if the object is missing.
+	Actual string
+}
+
+func (d *SchemaDifference) String() string {
+	object := fmt.Sprintf("table %q", d.Table)
+	if d.Kind != SchemaObjectTable {
+		object = fmt.Sprintf("%s %q of %s", d.Kind, d.Name, object)
+	}
+
+	switch {
+	case d.Actual == "":
+		return "missing " + object
+	case d.Expected == "":
+		return "unexpected " + object
+	default:
+		return fmt.Sprintf("%s is %q instead of %q", object, d.Actual, d.Expected)
+	}
+}
+
+// CheckSchemaDrift compares the schema of the database with the one that the migrations
+// create, as documented in schema.md, without changing the database. It reports schema
+// changes that were made by hand or by a migration that was edited after it ran, e.g. a
+// table that wasn't renamed.
+//
+// schema.md is generated with PostgreSQL 9.6. Differences that are due to a newer version
+// of PostgreSQL, e.g. the partitions of event_logs, aren't reported.
+func CheckSchemaDrift(ctx context.Context) (*SchemaDrift, error) {
+	d := &SchemaDrift{}
+
+	q := sqlf.Sprintf(`SELECT version, dirty FROM schema_migrations`)
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&d.Version, &d.Dirty)
+	if err != nil && err != sql.ErrNoRows && !dbutil.IsPostgresError(err, "undefined_table") {
+		return nil, errors.Wrap(err, "getting migration version")
+	}
+
+	if d.PendingMigrations, err = pendingMigrations(d.Version); err != nil {
+		return nil, err
+	}
+
+	expected, err := parseSchemaDoc(schemaDoc)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing schema.md")
+	}
+	actual, err := describeSchema(ctx, dbconn.Global)
+	if err != nil {
+		return nil, errors.Wrap(err, "describing schema")
+	}
+	d.Differences = diffSchemas(expected, actual)
+
+	return d, nil
+}
+
+// pendingMigrations returns the names of the up migrations with a version greater than the
+// given one, ordered by version.
+func pendingMigrations(version int64) ([]string, error) {
+	var pending []string
+	for _, name := range migrations.AssetNames() {
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		v, err := strconv.ParseInt(strings.SplitN(name, "_", 2)[0], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid migration name %q", name)
+		}
+		if v > version {
+			pending = append(pending, name)
+		}
+	}
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// schemaObject identifies a table, or a column, index or constraint of a table.
+type schemaObject struct {
+	kind  SchemaObjectKind
+	table string
+	name  string
+}
+
+// schemaDescription maps the objects of a schema to their definitions.
+type schemaDescription map[schemaObject]string
+
+// alternativeDefinitions are the definitions that differ from schema.md on newer versions of
+// PostgreSQL.
+var alternativeDefinitions = map[schemaObject]string{
+	// The partition key of event_logs is part of its primary key if it is partitioned,
+	// which requires PostgreSQL 11.
+	{kind: SchemaObjectIndex, table: "event_logs", name: "event_logs_pkey"}: `PRIMARY KEY, btree (id, "timestamp")`,
+}
+
+// diffSchemas returns the differences between the expected and actual schemas, ordered by
+// table, kind and name.
+func diffSchemas(expected, actual schemaDescription) []*SchemaDifference {
+	var diffs []*SchemaDifference
+	add := func(o schemaObject) {
+		if expected[o] == actual[o] || (actual[o] != "" && actual[o] == alternativeDefinitions[o]) {
+			return
+		}
+		diffs = append(diffs, &SchemaDifference{
+			Kind:     o.kind,
+			Table:    o.table,
+			Name:     o.name,
+			Expected: expected[o],
+			Actual:   actual[o],
+		})
+	}
+	for o := range expected {
+		add(o)
+	}
+	for o := range actual {
+		if _, ok := expected[o]; !ok {
+			add(o)
+		}
+	}
+
+	kindRank := make(map[SchemaObjectKind]int, len(schemaObjectKinds))
+	for i, k := range schemaObjectKinds {
+		kindRank[k] = i
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		a, b := diffs[i], diffs[j]
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		if a.Kind != b.Kind {
+			return kindRank[a.Kind] < kindRank[b.Kind]
+		}
+		return a.Name < b.Name
+	})
+	return diffs
+}
+
+// parseSchemaDoc parses the output of schemadoc/main.go. The rows listing the tables that
+// reference a table and its triggers are ignored.
+func parseSchemaDoc(doc string) (schemaDescription, error) {
+	desc := schemaDescription{}
+
+	var (
+		table   string
+		section string
+	)
+	for _, line := range strings.Split(doc, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "):
+			name, err := strconv.Unquote(strings.TrimPrefix(line, "# Table "))
+			if err != nil || !strings.HasPrefix(name, "public.") {
+				return nil, errors.Errorf("invalid table header %q", line)
+			}
+			table = strings.TrimPrefix(name, "public.")
+			section = ""
+			desc[schemaObject{kind: SchemaObjectTable, table: table}] = tableDefinition(table)
+
+		case line == "" || line == "```" || strings.HasPrefix(line, "---"):
+			// Skip blank lines, code fences and the line below the column headers.
+
+		case !strings.HasPrefix(line, " "):
+			if !strings.HasSuffix(line, ":") {
+				return nil, errors.Errorf("invalid line %q in table %q", line, table)
+			}
+			section = strings.TrimSuffix(line, ":")
+
+		case section == "":
+			parts := strings.SplitN(line, "|", 3)
+			if len(parts) != 3 {
+				return nil, errors.Errorf("invalid column %q in table %q", line, table)
+			}
+			name := strings.TrimSpace(parts[0])
+			if name == "Column" {
+				continue
+			}
+			def := columnDefinition(strings.TrimSpace(parts[1]), strings.TrimSpace(parts[2]))
+			desc[schemaObject{kind: SchemaObjectColumn, table: table, name: name}] = def
+
+		case section == "Indexes" || section == "Check constraints" || section == "Foreign-key constraints":
+			kind := SchemaObjectConstraint
+			if section == "Indexes" {
+				kind = SchemaObjectIndex
+			}
+			entry := strings.TrimSpace(line)
+			i := strings.Index(entry, `" `)
+			if !strings.HasPrefix(entry, `"`) || i < 0 {
+				return nil, errors.Errorf("invalid entry %q in table %q", line, table)
+			}
+			desc[schemaObject{kind: kind, table: table, name: entry[1:i]}] = entry[i+2:]
+		}
+	}
+
+	return desc, nil
+}
+
+func tableDefinition(table string) string {
+	return fmt.Sprintf("Table %q", "public."+table)
+}
+
+func columnDefinition(typ, modifiers string) string {
+	return strings.TrimSpace(typ + " " + modifiers)
+}
+
+// schemaTables selects the OIDs and names of the tables in the public schema. Partitions are
+// left out since they are created and dropped at runtime.
+const schemaTables = `
+SELECT c.oid, c.relname
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = 'public'
+AND c.relkind IN ('r', 'p')
+AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_inherits i WHERE i.inhrelid = c.oid)
+`
+
+// describeSchema describes the tables in the public schema of db, with the definitions of
+// their columns, indexes and constraints formatted like in the output of psql 9.6's \d
+// command.
+func describeSchema(ctx context.Context, db dbutil.DB) (schemaDescription, error) {
+	desc := schemaDescription{}
+
+	q := sqlf.Sprintf(`SELECT relname FROM (` + schemaTables + `) t`)
+	err := querySchema(ctx, db, q, func(rows *sql.Rows) error {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		desc[schemaObject{kind: SchemaObjectTable, table: table}] = tableDefinition(table)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing tables")
+	}
+
+	// Like psql, only show the collation of a column if it isn't the default of its type,
+	// and truncate defaults to 128 characters.
+	q = sqlf.Sprintf(`
+		SELECT
+			t.relname,
+			a.attname,
+			pg_catalog.format_type(a.atttypid, a.atttypmod),
+			(SELECT co.collname FROM pg_catalog.pg_collation co, pg_catalog.pg_type ty
+				WHERE co.oid = a.attcollation AND ty.oid = a.atttypid AND a.attcollation <> ty.typcollation),
+			a.attnotnull,
+			(SELECT substring(pg_catalog.pg_get_expr(d.adbin, d.adrelid) for 128) FROM pg_catalog.pg_attrdef d
+				WHERE d.adrelid = a.attrelid AND d.adnum = a.attnum AND a.atthasdef)
+		FROM (` + schemaTables + `) t
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid
+		WHERE a.attnum > 0 AND NOT a.attisdropped`)
+	err = querySchema(ctx, db, q, func(rows *sql.Rows) error {
+		var (
+			table, name, typ   string
+			collation, defExpr sql.NullString
+			notNull            bool
+		)
+		if err := rows.Scan(&table, &name, &typ, &collation, &notNull, &defExpr); err != nil {
+			return err
+		}
+
+		var modifiers []string
+		if collation.Valid {
+			modifiers = append(modifiers, "collate "+collation.String)
+		}
+		if notNull {
+			modifiers = append(modifiers, "not null")
+		}
+		if defExpr.Valid {
+			modifiers = append(modifiers, "default "+defExpr.String)
+		}
+		desc[schemaObject{kind: SchemaObjectColumn, table: table, name: name}] = columnDefinition(typ, strings.Join(modifiers, " "))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing columns")
+	}
+
+	q = sqlf.Sprintf(`
+		SELECT
+			t.relname,
+			c.relname,
+			i.indisprimary,
+			i.indisunique,
+			i.indisclustered,
+			i.indisvalid,
+			i.indisreplident,
+			pg_catalog.pg_get_indexdef(i.indexrelid, 0, true),
+			COALESCE(con.contype::text, ''),
+			COALESCE(con.condeferrable, false),
+			COALESCE(con.condeferred, false),
+			COALESCE(pg_catalog.pg_get_constraintdef(con.oid, true), '')
+		FROM (` + schemaTables + `) t
+		JOIN pg_catalog.pg_index i ON i.indrelid = t.oid
+		JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+		LEFT JOIN pg_catalog.pg_constraint con
+			ON con.conrelid = i.indrelid AND con.conindid = i.indexrelid AND con.contype IN ('p', 'u', 'x')`)
+	err = querySchema(ctx, db, q, func(rows *sql.Rows) error {
+		var (
+			table, name, indexDef, contype, constraintDef                      string
+			primary, unique, clustered, valid, replIdent, deferrable, deferred bool
+		)
+		if err := rows.Scan(&table, &name, &primary, &unique, &clustered, &valid, &replIdent, &indexDef, &contype, &deferrable, &deferred, &constraintDef); err != nil {
+			return err
+		}
+
+		var def strings.Builder
+		if contype == "x" {
+			def.WriteString(constraintDef)
+		} else {
+			switch {
+			case primary:
+				def.WriteString("PRIMARY KEY, ")
+			case unique && contype == "u":
+				def.WriteString("UNIQUE CONSTRAINT, ")
+			case unique:
+				def.WriteString("UNIQUE, ")
+			}
+			// Leave out "CREATE INDEX name ON table USING".
+			if i := strings.Index(indexDef, " USING "); i >= 0 {
+				indexDef = indexDef[i+len(" USING "):]
+			}
+			def.WriteString(indexDef)
+			if deferrable {
+				def.WriteString(" DEFERRABLE")
+			}
+			if deferred {
+				def.WriteString(" INITIALLY DEFERRED")
+			}
+		}
+		if clustered {
+			def.WriteString(" CLUSTER")
+		}
+		if !valid {
+			def.WriteString(" INVALID")
+		}
+		if replIdent {
+			def.WriteString(" REPLICA IDENTITY")
+		}
+		desc[schemaObject{kind: SchemaObjectIndex, table: table, name: name}] = def.String()
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing indexes")
+	}
+
+	q = sqlf.Sprintf(`
+		SELECT t.relname, con.conname, pg_catalog.pg_get_constraintdef(con.oid, true)
+		FROM (` + schemaTables + `) t
+		JOIN pg_catalog.pg_constraint con ON con.conrelid = t.oid
+		WHERE con.contype IN ('c', 'f')`)
+	err = querySchema(ctx, db, q, func(rows *sql.Rows) error {
+		var table, name, def string
+		if err := rows.Scan(&table, &name, &def); err != nil {
+			return err
+		}
+		desc[schemaObject{kind: SchemaObjectConstraint, table: table, name: name}] = def
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing constraints")
+	}
+
+	return desc, nil
+}
+
+func querySchema(ctx context.Context, db dbutil.DB, q *sqlf.Query, scan func(*sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+const testSchemaDoc = "# Table \"public.campaigns\"\n" +
+	"```\n" +
+	"  Column  |  Type   |                       Modifiers                        \n" +
+	"----------+---------+--------------------------------------------------------\n" +
+	" id       | bigint  | not null default nextval('campaigns_id_seq'::regclass)\n" +
+	" name     | text    | not null\n" +
+	" owner_id | integer | \n" +
+	"Indexes:\n" +
+	"    \"campaigns_pkey\" PRIMARY KEY, btree (id)\n" +
+	"    \"campaigns_name\" btree (name)\n" +
+	"Check constraints:\n" +
+	"    \"campaigns_name_not_blank\" CHECK (name <> ''::text)\n" +
+	"Foreign-key constraints:\n" +
+	"    \"campaigns_owner_id_fkey\" FOREIGN KEY (owner_id) REFERENCES users(id) DEFERRABLE\n" +
+	"Referenced by:\n" +
+	"    TABLE \"changesets\" CONSTRAINT \"changesets_campaign_id_fkey\" FOREIGN KEY (campaign_id) REFERENCES campaigns(id)\n" +
+	"\n" +
+	"```\n" +
+	"\n" +
+	"# Table \"public.event_logs\"\n" +
+	"```\n" +
+	" Column |  Type  | Modifiers \n" +
+	"--------+--------+-----------\n" +
+	" id     | bigint | not null\n" +
+	"Indexes:\n" +
+	"    \"event_logs_pkey\" PRIMARY KEY, btree (id)\n" +
+	"\n" +
+	"```\n"
+
+func TestParseSchemaDoc(t *testing.T) {
+	have, err := parseSchemaDoc(testSchemaDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := schemaDescription{
+		{kind: SchemaObjectTable, table: "campaigns"}:                                        `Table "public.campaigns"`,
+		{kind: SchemaObjectColumn, table: "campaigns", name: "id"}:                           "bigint not null default nextval('campaigns_id_seq'::regclass)",
+		{kind: SchemaObjectColumn, table: "campaigns", name: "name"}:                         "text not null",
+		{kind: SchemaObjectColumn, table: "campaigns", name: "owner_id"}:                     "integer",
+		{kind: SchemaObjectIndex, table: "campaigns", name: "campaigns_pkey"}:                "PRIMARY KEY, btree (id)",
+		{kind: SchemaObjectIndex, table: "campaigns", name: "campaigns_name"}:                "btree (name)",
+		{kind: SchemaObjectConstraint, table: "campaigns", name: "campaigns_name_not_blank"}: "CHECK (name <> ''::text)",
+		{kind: SchemaObjectConstraint, table: "campaigns", name: "campaigns_owner_id_fkey"}:  "FOREIGN KEY (owner_id) REFERENCES users(id) DEFERRABLE",
+		{kind: SchemaObjectTable, table: "event_logs"}:                                       `Table "public.event_logs"`,
+		{kind: SchemaObjectColumn, table: "event_logs", name: "id"}:                          "bigint not null",
+		{kind: SchemaObjectIndex, table: "event_logs", name: "event_logs_pkey"}:              "PRIMARY KEY, btree (id)",
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+
+	if _, err := parseSchemaDoc("# Table \"public.campaigns\"\n```\nIndexes:\n    campaigns_pkey\n```\n"); err == nil {
+		t.Error("want error for invalid index entry")
+	}
+
+	if _, err := parseSchemaDoc(schemaDoc); err != nil {
+		t.Errorf("parsing schema.md: %s", err)
+	}
+}
+
+func TestDiffSchemas(t *testing.T) {
+	expected, err := parseSchemaDoc(testSchemaDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The campaigns table was renamed, but not its index and constraints.
+	actual := schemaDescription{
+		{kind: SchemaObjectTable, table: "changeset_campaigns"}:                         `Table "public.changeset_campaigns"`,
+		{kind: SchemaObjectColumn, table: "changeset_campaigns", name: "id"}:            "bigint not null default nextval('campaigns_id_seq'::regclass)",
+		{kind: SchemaObjectIndex, table: "changeset_campaigns", name: "campaigns_pkey"}: "PRIMARY KEY, btree (id)",
+		{kind: SchemaObjectTable, table: "event_logs"}:                                  `Table "public.event_logs"`,
+		{kind: SchemaObjectColumn, table: "event_logs", name: "id"}:                     "bigint",
+		{kind: SchemaObjectIndex, table: "event_logs", name: "event_logs_pkey"}:         `PRIMARY KEY, btree (id, "timestamp")`,
+	}
+
+	have := diffSchemas(expected, actual)
+	want := []*SchemaDifference{
+		{Kind: SchemaObjectTable, Table: "campaigns", Expected: `Table "public.campaigns"`},
+		{Kind: SchemaObjectColumn, Table: "campaigns", Name: "id", Expected: "bigint not null default nextval('campaigns_id_seq'::regclass)"},
+		{Kind: SchemaObjectColumn, Table: "campaigns", Name: "name", Expected: "text not null"},
+		{Kind: SchemaObjectColumn, Table: "campaigns", Name: "owner_id", Expected: "integer"},
+		{Kind: SchemaObjectIndex, Table: "campaigns", Name: "campaigns_name", Expected: "btree (name)"},
+		{Kind: SchemaObjectIndex, Table: "campaigns", Name: "campaigns_pkey", Expected: "PRIMARY KEY, btree (id)"},
+		{Kind: SchemaObjectConstraint, Table: "campaigns", Name: "campaigns_name_not_blank", Expected: "CHECK (name <> ''::text)"},
+		{Kind: SchemaObjectConstraint, Table: "campaigns", Name: "campaigns_owner_id_fkey", Expected: "FOREIGN KEY (owner_id) REFERENCES users(id) DEFERRABLE"},
+		{Kind: SchemaObjectTable, Table: "changeset_campaigns", Actual: `Table "public.changeset_campaigns"`},
+		{Kind: SchemaObjectColumn, Table: "changeset_campaigns", Name: "id", Actual: "bigint not null default nextval('campaigns_id_seq'::regclass)"},
+		{Kind: SchemaObjectIndex, Table: "changeset_campaigns", Name: "campaigns_pkey", Actual: "PRIMARY KEY, btree (id)"},
+		{Kind: SchemaObjectColumn, Table: "event_logs", Name: "id", Expected: "bigint not null", Actual: "bigint"},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+
+	for d, s := range map[*SchemaDifference]string{
+		want[0]:  `missing table "campaigns"`,
+		want[10]: `unexpected index "campaigns_pkey" of table "changeset_campaigns"`,
+		want[11]: `column "id" of table "event_logs" is "bigint" instead of "bigint not null"`,
+	} {
+		if d.String() != s {
+			t.Errorf("have %q, want %q", d.String(), s)
+		}
+	}
+}
+
+func TestCheckSchemaDrift(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	drift, err := CheckSchemaDrift(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift.Dirty || len(drift.PendingMigrations) > 0 {
+		t.Fatalf("have dirty %v and pending migrations %v, want a migrated database", drift.Dirty, drift.PendingMigrations)
+	}
+	// The schema of the migrated test database is the one in schema.md, unless schema.md is
+	// out of date, in which case it has to be generated again with go generate.
+	for _, d := range drift.Differences {
+		t.Error(d)
+	}
+
+	if _, err := dbconn.Global.ExecContext(ctx, `ALTER TABLE versions ADD COLUMN note text`); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if _, err := dbconn.Global.ExecContext(ctx, `ALTER TABLE versions DROP COLUMN note`); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	drift, err = CheckSchemaDrift(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*SchemaDifference{{Kind: SchemaObjectColumn, Table: "versions", Name: "note", Actual: "text"}}
+	if !reflect.DeepEqual(drift.Differences, want) {
+		t.Errorf("have differences %v, want %v", drift.Differences, want)
+	}
+}
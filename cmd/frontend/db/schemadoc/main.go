@@ -1,13 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
 	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,7 +28,9 @@ func runIgnoreError(cmd string, args ...string) {
 // the current dabase schema, obtained from postgres. The correct PGHOST,
 // PGPORT, PGUSER etc. env variables must be set to run this script.
 //
-// First CLI argument is an optional filename to write the output to.
+// First CLI argument is an optional filename to write the output to. The
+// second is an optional filename to write a Go source file to, which declares
+// the output as the expected schema of db.CheckSchemaDrift.
 func generate(log *log.Logger) (string, error) {
 	const dbname = "schemadoc-gen-temp"
 
@@ -149,6 +154,25 @@ WHERE table_schema='public' AND table_type='BASE TABLE';
 	return strings.Join(docs, "\n"), nil
 }
 
+// goSource returns the source of a Go file in package db which declares the
+// given output of generate as the constant schemaDoc.
+func goSource(doc string) ([]byte, error) {
+	var lines []string
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		if line != "" {
+			lines = append(lines, strconv.Quote(line))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by schemadoc/main.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package db\n\n")
+	buf.WriteString("// schemaDoc is the description of the schema created by the migrations, as\n")
+	buf.WriteString("// documented in schema.md.\n")
+	buf.WriteString("const schemaDoc = " + strings.Join(lines, " +\n\t") + "\n")
+	return format.Source(buf.Bytes())
+}
+
 func main() {
 	out, err := generate(log.New(os.Stderr, "", log.LstdFlags))
 	if err != nil {
@@ -161,4 +185,13 @@ func main() {
 	} else {
 		fmt.Print(out)
 	}
+	if len(os.Args) > 2 {
+		src, err := goSource(out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(os.Args[2], src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
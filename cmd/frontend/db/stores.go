@@ -17,6 +17,10 @@ var (
 	Users                     = &users{}
 	UserEmails                = &userEmails{}
 	EventLogs                 = &eventLogs{}
+	AggregatedSearchLatencies = &aggregatedSearchLatencies{}
+	FeatureFlags              = &featureFlags{}
+	LSIFUploadTokens          = &lsifUploadTokens{}
+	UsageStatisticsJobs       = &usageStatisticsJobs{}
 
 	SurveyResponses = &surveyResponses{}
 
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+type usageStatisticsJobs struct{}
+
+// UsageStatisticsJob is the last run of a background job that computes or maintains usage
+// statistics, e.g. the aggregation of search latencies.
+type UsageStatisticsJob struct {
+	Name          string
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	RowsProcessed int64
+	// Error is the error of the last run, or empty if it succeeded.
+	Error string
+	// LastSucceededAt is when the last successful run finished, or nil if none did.
+	LastSucceededAt *time.Time
+}
+
+// Record records the given run of a job, replacing its previous run. LastSucceededAt is
+// ignored: it is set to FinishedAt if the run succeeded and kept otherwise.
+func (*usageStatisticsJobs) Record(ctx context.Context, j *UsageStatisticsJob) error {
+	q := sqlf.Sprintf(`
+		INSERT INTO usage_statistics_jobs (name, started_at, finished_at, rows_processed, error, last_succeeded_at)
+		VALUES (%s, %s, %s, %s, NULLIF(%s, ''), CASE WHEN %s = '' THEN %s::timestamptz END)
+		ON CONFLICT (name) DO UPDATE SET
+			started_at = excluded.started_at,
+			finished_at = excluded.finished_at,
+			rows_processed = excluded.rows_processed,
+			error = excluded.error,
+			last_succeeded_at = COALESCE(excluded.last_succeeded_at, usage_statistics_jobs.last_succeeded_at)`,
+		j.Name, j.StartedAt, j.FinishedAt, j.RowsProcessed, j.Error, j.Error, j.FinishedAt)
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// List returns the last runs of the jobs that ran at least once, ordered by name.
+func (*usageStatisticsJobs) List(ctx context.Context) ([]*UsageStatisticsJob, error) {
+	q := sqlf.Sprintf(`
+		SELECT name, started_at, finished_at, rows_processed, COALESCE(error, ''), last_succeeded_at
+		FROM usage_statistics_jobs
+		ORDER BY name`)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	js := []*UsageStatisticsJob{}
+	for rows.Next() {
+		var j UsageStatisticsJob
+		if err := rows.Scan(&j.Name, &j.StartedAt, &j.FinishedAt, &j.RowsProcessed, &j.Error, &j.LastSucceededAt); err != nil {
+			return nil, err
+		}
+		j.StartedAt = j.StartedAt.UTC()
+		j.FinishedAt = j.FinishedAt.UTC()
+		if j.LastSucceededAt != nil {
+			t := j.LastSucceededAt.UTC()
+			j.LastSucceededAt = &t
+		}
+		js = append(js, &j)
+	}
+	return js, rows.Err()
+}
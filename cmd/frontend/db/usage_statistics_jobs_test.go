@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestUsageStatisticsJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	at := func(h int) time.Time { return time.Date(2020, 1, 1, h, 0, 0, 0, time.UTC) }
+
+	runs := []*UsageStatisticsJob{
+		{Name: "b", StartedAt: at(1), FinishedAt: at(2), RowsProcessed: 10},
+		{Name: "a", StartedAt: at(1), FinishedAt: at(3), RowsProcessed: 20},
+		// A failed run keeps the time of the last successful one.
+		{Name: "b", StartedAt: at(4), FinishedAt: at(5), Error: "boom"},
+	}
+	for _, j := range runs {
+		if err := UsageStatisticsJobs.Record(ctx, j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	have, err := UsageStatisticsJobs.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := at(3), at(2)
+	want := []*UsageStatisticsJob{
+		{Name: "a", StartedAt: at(1), FinishedAt: at(3), RowsProcessed: 20, LastSucceededAt: &a},
+		{Name: "b", StartedAt: at(4), FinishedAt: at(5), Error: "boom", LastSucceededAt: &b},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+}
@@ -0,0 +1,151 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"net/http"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// values are the stored values of all feature flags and the overrides that
+// apply to a user, keyed by flag name.
+type values struct {
+	flags     map[string]*types.FeatureFlag
+	overrides map[string][]*types.FeatureFlagOverride
+}
+
+// requestValues caches the values loaded for the actors of a request, so that
+// they are loaded at most once per request and flags are evaluated
+// consistently within it.
+type requestValues struct {
+	mu     sync.Mutex
+	byUser map[int32]*values
+}
+
+type requestValuesKey struct{}
+
+// Middleware caches the values of feature flags for the duration of each
+// request. Without it, flags are evaluated against the database every time.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), requestValuesKey{}, &requestValues{byUser: map[int32]*values{}})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func enabled(ctx context.Context, f *Flag) bool {
+	userID := actor.FromContext(ctx).UID
+
+	v, err := valuesFor(ctx, userID)
+	if err != nil {
+		log15.Warn("Loading feature flags, using default value", "flag", f.Name, "err", err)
+		return f.Default
+	}
+	return evaluate(f, userID, v.flags[f.Name], v.overrides[f.Name])
+}
+
+// valuesFor returns the values of feature flags for the user with the given ID
+// (or 0 for anonymous users), from the cache of the request if there is one.
+func valuesFor(ctx context.Context, userID int32) (*values, error) {
+	rv, ok := ctx.Value(requestValuesKey{}).(*requestValues)
+	if !ok {
+		return loadValues(ctx, userID)
+	}
+
+	// Holding the lock while loading ensures that concurrent resolvers of the
+	// same request don't load the values more than once.
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	if v, ok := rv.byUser[userID]; ok {
+		return v, nil
+	}
+	v, err := loadValues(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rv.byUser[userID] = v
+	return v, nil
+}
+
+func loadValues(ctx context.Context, userID int32) (*values, error) {
+	flags, err := db.FeatureFlags.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &values{
+		flags:     make(map[string]*types.FeatureFlag, len(flags)),
+		overrides: map[string][]*types.FeatureFlagOverride{},
+	}
+	for _, f := range flags {
+		v.flags[f.Name] = f
+	}
+
+	if userID != 0 {
+		overrides, err := db.FeatureFlags.ListOverridesForUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range overrides {
+			v.overrides[o.FlagName] = append(v.overrides[o.FlagName], o)
+		}
+	}
+
+	return v, nil
+}
+
+// evaluate returns whether the flag is enabled for the user with the given ID
+// (or 0 for anonymous users), given its stored value and the overrides that
+// apply to the user. In order of precedence:
+//
+// - an override for the user
+// - overrides for the orgs of the user, where enabling the flag wins over disabling it
+// - the rollout percentage, which only anonymous users are excluded from unless it is 100
+// - the stored value
+// - the default value
+func evaluate(f *Flag, userID int32, stored *types.FeatureFlag, overrides []*types.FeatureFlagOverride) bool {
+	var orgEnabled, orgDisabled bool
+	for _, o := range overrides {
+		switch {
+		case o.NamespaceUserID != 0 && o.NamespaceUserID == userID:
+			return o.Enabled
+		case o.NamespaceOrgID != 0 && o.Enabled:
+			orgEnabled = true
+		case o.NamespaceOrgID != 0:
+			orgDisabled = true
+		}
+	}
+	if orgEnabled || orgDisabled {
+		return orgEnabled
+	}
+
+	if stored == nil {
+		return f.Default
+	}
+	if stored.RolloutPercentage != nil {
+		if userID == 0 {
+			return *stored.RolloutPercentage >= 100
+		}
+		return rolloutBucket(f.Name, userID) < *stored.RolloutPercentage
+	}
+	return stored.Enabled
+}
+
+// rolloutBucket returns the bucket in [0, 100) of the user with the given ID
+// for the flag with the given name. A user stays in a rollout while its
+// percentage grows, and the users in the rollouts of different flags differ.
+func rolloutBucket(flagName string, userID int32) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flagName))
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(userID))
+	_, _ = h.Write(b[:])
+	return int32(h.Sum32() % 100)
+}
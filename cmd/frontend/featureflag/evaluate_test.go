@@ -0,0 +1,116 @@
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestEvaluate(t *testing.T) {
+	f := &Flag{Name: "f", Default: true}
+	percent := func(p int32) *int32 { return &p }
+
+	tests := []struct {
+		name      string
+		userID    int32
+		stored    *types.FeatureFlag
+		overrides []*types.FeatureFlagOverride
+		want      bool
+	}{
+		{
+			name:   "default",
+			userID: 1,
+			want:   true,
+		},
+		{
+			name:   "stored",
+			userID: 1,
+			stored: &types.FeatureFlag{Name: "f", Enabled: false},
+			want:   false,
+		},
+		{
+			name:   "rollout of 0 percent",
+			userID: 1,
+			stored: &types.FeatureFlag{Name: "f", Enabled: true, RolloutPercentage: percent(0)},
+			want:   false,
+		},
+		{
+			name:   "rollout of 100 percent",
+			userID: 1,
+			stored: &types.FeatureFlag{Name: "f", RolloutPercentage: percent(100)},
+			want:   true,
+		},
+		{
+			name:   "anonymous user in partial rollout",
+			stored: &types.FeatureFlag{Name: "f", Enabled: true, RolloutPercentage: percent(99)},
+			want:   false,
+		},
+		{
+			name:   "anonymous user in full rollout",
+			stored: &types.FeatureFlag{Name: "f", RolloutPercentage: percent(100)},
+			want:   true,
+		},
+		{
+			name:   "user override",
+			userID: 1,
+			stored: &types.FeatureFlag{Name: "f", Enabled: true},
+			overrides: []*types.FeatureFlagOverride{
+				{FlagName: "f", NamespaceOrgID: 2, Enabled: true},
+				{FlagName: "f", NamespaceUserID: 1, Enabled: false},
+			},
+			want: false,
+		},
+		{
+			name:   "org override",
+			userID: 1,
+			stored: &types.FeatureFlag{Name: "f", RolloutPercentage: percent(0)},
+			overrides: []*types.FeatureFlagOverride{
+				{FlagName: "f", NamespaceOrgID: 2, Enabled: true},
+			},
+			want: true,
+		},
+		{
+			name:   "conflicting org overrides",
+			userID: 1,
+			overrides: []*types.FeatureFlagOverride{
+				{FlagName: "f", NamespaceOrgID: 2, Enabled: false},
+				{FlagName: "f", NamespaceOrgID: 3, Enabled: true},
+			},
+			want: true,
+		},
+		{
+			name:   "org override disabling",
+			userID: 1,
+			overrides: []*types.FeatureFlagOverride{
+				{FlagName: "f", NamespaceOrgID: 2, Enabled: false},
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := evaluate(f, tc.userID, tc.stored, tc.overrides); have != tc.want {
+				t.Fatalf("have %t, want %t", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestRolloutBucket(t *testing.T) {
+	// A rollout to about half of the users enables the flag for about half of
+	// them, and users stay in the rollout as it grows.
+	var in50 int
+	for userID := int32(1); userID <= 1000; userID++ {
+		b := rolloutBucket("f", userID)
+		if b < 0 || b >= 100 {
+			t.Fatalf("have bucket %d, want in [0, 100)", b)
+		}
+		if b < 50 {
+			in50++
+		}
+	}
+	if in50 < 400 || in50 > 600 {
+		t.Fatalf("have %d of 1000 users in rollout of 50 percent", in50)
+	}
+}
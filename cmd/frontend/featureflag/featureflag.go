@@ -0,0 +1,91 @@
+// Package featureflag declares and evaluates feature flags, which turn
+// behaviors of Sourcegraph on or off at runtime.
+//
+// A flag is declared by the code it gates, with the value it has by default.
+// Site admins change the value of a flag, roll it out to a percentage of
+// users, or override it for individual users and orgs, without redeploying
+// Sourcegraph. Values and overrides are stored in the database (see
+// db.FeatureFlags).
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Flag is a declared feature flag.
+type Flag struct {
+	// Name is the unique name of the flag, e.g. "codeintel-badges".
+	Name string
+	// Description describes the behavior that the flag turns on or off.
+	Description string
+	// Default is whether the flag is enabled when no value is stored.
+	Default bool
+}
+
+var (
+	mu    sync.RWMutex
+	flags = map[string]*Flag{}
+)
+
+// Register declares the feature flag with the given name. It is intended to be
+// called when initializing packages, and panics if a flag with the same name
+// is already declared.
+func Register(name, description string, defaultEnabled bool) *Flag {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := flags[name]; ok {
+		panic(fmt.Sprintf("feature flag %q is already registered", name))
+	}
+	f := &Flag{Name: name, Description: description, Default: defaultEnabled}
+	flags[name] = f
+	return f
+}
+
+// Lookup returns the declared feature flag with the given name.
+func Lookup(name string) (*Flag, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	f, ok := flags[name]
+	return f, ok
+}
+
+// All returns all declared feature flags, ordered by name.
+func All() []*Flag {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]*Flag, 0, len(flags))
+	for _, f := range flags {
+		all = append(all, f)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Enabled reports whether the flag is enabled for the actor of the given
+// context. If the values of feature flags can't be loaded, the flag has its
+// default value.
+func (f *Flag) Enabled(ctx context.Context) bool {
+	return enabled(ctx, f)
+}
+
+// Variant returns the names of the given flags that are enabled for the actor
+// of the given context, ordered by name and joined by commas, or the empty
+// string if none is. It identifies the variant of an experiment that a user
+// takes part in, so that measurements can be compared between variants.
+func Variant(ctx context.Context, flags ...*Flag) string {
+	var names []string
+	for _, f := range flags {
+		if f.Enabled(ctx) {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
@@ -15,18 +15,34 @@ import (
 var NewA8NResolver func(*sql.DB) A8NResolver
 
 type AddChangesetsToCampaignArgs struct {
+	Campaign       graphql.ID
+	Changesets     []graphql.ID
+	OverrideQuotas bool
+}
+
+type ImportChangesetsArgs struct {
 	Campaign   graphql.ID
-	Changesets []graphql.ID
+	Changesets []struct {
+		ExternalURL *string
+		Repository  *graphql.ID
+		ExternalID  *string
+	}
+	OverrideQuotas bool
 }
 
 type CreateCampaignArgs struct {
 	Input struct {
-		Namespace   graphql.ID
-		Name        string
-		Description string
-		Branch      *string
-		Plan        *graphql.ID
-		Draft       *bool
+		Namespace      graphql.ID
+		Name           string
+		Description    string
+		Branch         *string
+		Plan           *graphql.ID
+		Draft          *bool
+		OverrideQuotas *bool
+		IdempotencyKey *string
+		Spec           *string
+		Parent         *graphql.ID
+		AutoRebase     *bool
 	}
 }
 
@@ -37,6 +53,8 @@ type UpdateCampaignArgs struct {
 		Description *string
 		Branch      *string
 		Plan        *graphql.ID
+		Spec        *string
+		AutoRebase  *bool
 	}
 }
 
@@ -44,15 +62,54 @@ type CreateCampaignPlanFromPatchesArgs struct {
 	Patches []CampaignPlanPatch
 }
 
+type CreateCampaignPlanFromSpecArgs struct {
+	Spec string
+}
+
+type PreviewCampaignArgs struct {
+	Patches []CampaignPlanPatch
+	First   *int32
+}
+
 type CampaignPlanPatch struct {
 	Repository   graphql.ID
 	BaseRevision string
 	Patch        string
 }
 
+type CampaignByNameArgs struct {
+	Namespace graphql.ID
+	Name      string
+}
+
 type ListCampaignArgs struct {
-	First *int32
-	State *string
+	First             *int32
+	After             *string
+	Query             *string
+	State             *string
+	Namespaces        *[]graphql.ID
+	HasOpenChangesets *bool
+	ChangesetState    *string
+	OrderBy           string
+}
+
+type UserCampaignsArgs struct {
+	First             *int32
+	After             *string
+	Query             *string
+	State             *string
+	HasOpenChangesets *bool
+	ChangesetState    *string
+	InvolvedAs        *string
+}
+
+type CampaignFacetsArgs struct {
+	Namespaces *[]graphql.ID
+}
+
+type CampaignRepositoryActivityArgs struct {
+	Namespaces   *[]graphql.ID
+	Repositories *[]graphql.ID
 }
 
 type DeleteCampaignArgs struct {
@@ -60,6 +117,24 @@ type DeleteCampaignArgs struct {
 	CloseChangesets bool
 }
 
+type RestoreCampaignArgs struct {
+	Campaign graphql.ID
+}
+
+type RollbackCampaignArgs struct {
+	Campaign graphql.ID
+}
+
+type SetCampaignParentArgs struct {
+	Campaign graphql.ID
+	Parent   *graphql.ID
+}
+
+type MoveCampaignArgs struct {
+	Campaign graphql.ID
+	Before   *graphql.ID
+}
+
 type RetryCampaignArgs struct {
 	Campaign graphql.ID
 }
@@ -69,39 +144,146 @@ type CloseCampaignArgs struct {
 	CloseChangesets bool
 }
 
+type UpdateCampaignsArgs struct {
+	IDs             []graphql.ID
+	State           string
+	CloseChangesets bool
+	OverrideQuotas  bool
+}
+
 type CreateChangesetsArgs struct {
 	Input []struct {
 		Repository graphql.ID
 		ExternalID string
 	}
+	IdempotencyKey *string
 }
 
 type PublishCampaignArgs struct {
-	Campaign graphql.ID
+	Campaign       graphql.ID
+	OverrideQuotas bool
 }
 
 type PublishChangesetArgs struct {
-	ChangesetPlan graphql.ID
+	ChangesetPlan  graphql.ID
+	OverrideQuotas bool
+}
+
+type SyncChangesetArgs struct {
+	Changeset graphql.ID
+}
+
+type SaveCampaignSavedFilterArgs struct {
+	Input struct {
+		Name              string
+		Query             *string
+		State             *string
+		Namespaces        *[]graphql.ID
+		HasOpenChangesets *bool
+		ChangesetState    *string
+		IsDefault         bool
+	}
+}
+
+type RenderChangesetTemplateArgs struct {
+	Title         string
+	Body          string
+	Campaign      *graphql.ID
+	ChangesetPlan *graphql.ID
+}
+
+type DeleteCampaignSavedFilterArgs struct {
+	SavedFilter graphql.ID
+}
+
+type SetDefaultCampaignSavedFilterArgs struct {
+	SavedFilter *graphql.ID
+}
+
+type CampaignNamespaceSettingsArgs struct {
+	Namespace graphql.ID
+}
+
+type UpdateCampaignNamespaceSettingsArgs struct {
+	Input struct {
+		Namespace              graphql.ID
+		BranchPrefix           *string
+		DefaultLabels          *[]string
+		RequireApproval        *bool
+		NotificationChannel    *string
+		NotificationWebhookURL *string
+	}
+}
+
+type UpdateCampaignNotificationSettingsArgs struct {
+	Input struct {
+		NotifyMerged          *bool
+		NotifyClosed          *bool
+		NotifyChecksFailed    *bool
+		DigestIntervalMinutes *int32
+	}
+}
+
+type SetCampaignSubscriptionArgs struct {
+	Campaign   graphql.ID
+	Subscribed bool
+}
+
+type MarkCampaignViewedArgs struct {
+	Campaign graphql.ID
+}
+
+type RedeliverWebhookArgs struct {
+	Delivery graphql.ID
 }
 
 type A8NResolver interface {
 	CreateCampaign(ctx context.Context, args *CreateCampaignArgs) (CampaignResolver, error)
 	UpdateCampaign(ctx context.Context, args *UpdateCampaignArgs) (CampaignResolver, error)
 	CampaignByID(ctx context.Context, id graphql.ID) (CampaignResolver, error)
+	CampaignByName(ctx context.Context, args *CampaignByNameArgs) (CampaignByNameResultResolver, error)
 	Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error)
+	UserCampaigns(ctx context.Context, user *UserResolver, args *UserCampaignsArgs) (CampaignsConnectionResolver, error)
+	CampaignFacets(ctx context.Context, args *CampaignFacetsArgs) (CampaignFacetsResolver, error)
+	CampaignRepositoryActivity(ctx context.Context, args *CampaignRepositoryActivityArgs) ([]CampaignRepositoryActivityResolver, error)
 	DeleteCampaign(ctx context.Context, args *DeleteCampaignArgs) (*EmptyResponse, error)
+	RestoreCampaign(ctx context.Context, args *RestoreCampaignArgs) (CampaignResolver, error)
+	RollbackCampaign(ctx context.Context, args *RollbackCampaignArgs) (CampaignResolver, error)
+	SetCampaignParent(ctx context.Context, args *SetCampaignParentArgs) (CampaignResolver, error)
+	MoveCampaign(ctx context.Context, args *MoveCampaignArgs) (CampaignResolver, error)
 	RetryCampaign(ctx context.Context, args *RetryCampaignArgs) (CampaignResolver, error)
 	CloseCampaign(ctx context.Context, args *CloseCampaignArgs) (CampaignResolver, error)
+	UpdateCampaigns(ctx context.Context, args *UpdateCampaignsArgs) ([]UpdateCampaignsResultResolver, error)
 	PublishCampaign(ctx context.Context, args *PublishCampaignArgs) (CampaignResolver, error)
 	PublishChangeset(ctx context.Context, args *PublishChangesetArgs) (*EmptyResponse, error)
+	SyncChangeset(ctx context.Context, args *SyncChangesetArgs) (*EmptyResponse, error)
+
+	RenderChangesetTemplate(ctx context.Context, args *RenderChangesetTemplateArgs) (RenderedChangesetTemplateResolver, error)
+
+	CampaignSavedFilters(ctx context.Context) ([]CampaignSavedFilterResolver, error)
+	SaveCampaignSavedFilter(ctx context.Context, args *SaveCampaignSavedFilterArgs) (CampaignSavedFilterResolver, error)
+	DeleteCampaignSavedFilter(ctx context.Context, args *DeleteCampaignSavedFilterArgs) (*EmptyResponse, error)
+	SetDefaultCampaignSavedFilter(ctx context.Context, args *SetDefaultCampaignSavedFilterArgs) (*EmptyResponse, error)
+
+	CampaignNamespaceSettings(ctx context.Context, args *CampaignNamespaceSettingsArgs) (CampaignNamespaceSettingsResolver, error)
+	UpdateCampaignNamespaceSettings(ctx context.Context, args *UpdateCampaignNamespaceSettingsArgs) (CampaignNamespaceSettingsResolver, error)
+
+	CampaignNotificationSettings(ctx context.Context) (CampaignNotificationSettingsResolver, error)
+	UpdateCampaignNotificationSettings(ctx context.Context, args *UpdateCampaignNotificationSettingsArgs) (CampaignNotificationSettingsResolver, error)
+	SetCampaignSubscription(ctx context.Context, args *SetCampaignSubscriptionArgs) (CampaignResolver, error)
+	MarkCampaignViewed(ctx context.Context, args *MarkCampaignViewedArgs) (CampaignResolver, error)
+	RedeliverWebhook(ctx context.Context, args *RedeliverWebhookArgs) (CampaignWebhookDeliveryResolver, error)
 
 	CreateChangesets(ctx context.Context, args *CreateChangesetsArgs) ([]ExternalChangesetResolver, error)
 	ChangesetByID(ctx context.Context, id graphql.ID) (ExternalChangesetResolver, error)
 	Changesets(ctx context.Context, args *graphqlutil.ConnectionArgs) (ExternalChangesetsConnectionResolver, error)
 
 	AddChangesetsToCampaign(ctx context.Context, args *AddChangesetsToCampaignArgs) (CampaignResolver, error)
+	ImportChangesets(ctx context.Context, args *ImportChangesetsArgs) (CampaignResolver, error)
 
 	CreateCampaignPlanFromPatches(ctx context.Context, args CreateCampaignPlanFromPatchesArgs) (CampaignPlanResolver, error)
+	CreateCampaignPlanFromSpec(ctx context.Context, args CreateCampaignPlanFromSpecArgs) (CampaignPlanResolver, error)
+	PreviewCampaign(ctx context.Context, args PreviewCampaignArgs) (ChangesetPlansConnectionResolver, error)
 	CampaignPlanByID(ctx context.Context, id graphql.ID) (CampaignPlanResolver, error)
 
 	ChangesetPlanByID(ctx context.Context, id graphql.ID) (ChangesetPlanResolver, error)
@@ -123,14 +305,46 @@ func (defaultA8NResolver) CampaignByID(ctx context.Context, id graphql.ID) (Camp
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) CampaignByName(ctx context.Context, args *CampaignByNameArgs) (CampaignByNameResultResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error) {
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) UserCampaigns(ctx context.Context, user *UserResolver, args *UserCampaignsArgs) (CampaignsConnectionResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) CampaignFacets(ctx context.Context, args *CampaignFacetsArgs) (CampaignFacetsResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) CampaignRepositoryActivity(ctx context.Context, args *CampaignRepositoryActivityArgs) ([]CampaignRepositoryActivityResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) DeleteCampaign(ctx context.Context, args *DeleteCampaignArgs) (*EmptyResponse, error) {
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) RestoreCampaign(ctx context.Context, args *RestoreCampaignArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) RollbackCampaign(ctx context.Context, args *RollbackCampaignArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) SetCampaignParent(ctx context.Context, args *SetCampaignParentArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) MoveCampaign(ctx context.Context, args *MoveCampaignArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) RetryCampaign(ctx context.Context, args *RetryCampaignArgs) (CampaignResolver, error) {
 	return nil, a8nOnlyInEnterprise
 }
@@ -139,6 +353,10 @@ func (defaultA8NResolver) CloseCampaign(ctx context.Context, args *CloseCampaign
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) UpdateCampaigns(ctx context.Context, args *UpdateCampaignsArgs) ([]UpdateCampaignsResultResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) PublishCampaign(ctx context.Context, args *PublishCampaignArgs) (CampaignResolver, error) {
 	return nil, a8nOnlyInEnterprise
 }
@@ -147,6 +365,58 @@ func (defaultA8NResolver) PublishChangeset(ctx context.Context, args *PublishCha
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) SyncChangeset(ctx context.Context, args *SyncChangesetArgs) (*EmptyResponse, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) RenderChangesetTemplate(ctx context.Context, args *RenderChangesetTemplateArgs) (RenderedChangesetTemplateResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) CampaignSavedFilters(ctx context.Context) ([]CampaignSavedFilterResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) SaveCampaignSavedFilter(ctx context.Context, args *SaveCampaignSavedFilterArgs) (CampaignSavedFilterResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) DeleteCampaignSavedFilter(ctx context.Context, args *DeleteCampaignSavedFilterArgs) (*EmptyResponse, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) SetDefaultCampaignSavedFilter(ctx context.Context, args *SetDefaultCampaignSavedFilterArgs) (*EmptyResponse, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) CampaignNamespaceSettings(ctx context.Context, args *CampaignNamespaceSettingsArgs) (CampaignNamespaceSettingsResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) UpdateCampaignNamespaceSettings(ctx context.Context, args *UpdateCampaignNamespaceSettingsArgs) (CampaignNamespaceSettingsResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) CampaignNotificationSettings(ctx context.Context) (CampaignNotificationSettingsResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) UpdateCampaignNotificationSettings(ctx context.Context, args *UpdateCampaignNotificationSettingsArgs) (CampaignNotificationSettingsResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) SetCampaignSubscription(ctx context.Context, args *SetCampaignSubscriptionArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) MarkCampaignViewed(ctx context.Context, args *MarkCampaignViewedArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) RedeliverWebhook(ctx context.Context, args *RedeliverWebhookArgs) (CampaignWebhookDeliveryResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) CreateChangesets(ctx context.Context, args *CreateChangesetsArgs) ([]ExternalChangesetResolver, error) {
 	return nil, a8nOnlyInEnterprise
 }
@@ -163,10 +433,22 @@ func (defaultA8NResolver) AddChangesetsToCampaign(ctx context.Context, args *Add
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) ImportChangesets(ctx context.Context, args *ImportChangesetsArgs) (CampaignResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) CreateCampaignPlanFromPatches(ctx context.Context, args CreateCampaignPlanFromPatchesArgs) (CampaignPlanResolver, error) {
 	return nil, a8nOnlyInEnterprise
 }
 
+func (defaultA8NResolver) CreateCampaignPlanFromSpec(ctx context.Context, args CreateCampaignPlanFromSpecArgs) (CampaignPlanResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
+func (defaultA8NResolver) PreviewCampaign(ctx context.Context, args PreviewCampaignArgs) (ChangesetPlansConnectionResolver, error) {
+	return nil, a8nOnlyInEnterprise
+}
+
 func (defaultA8NResolver) CampaignPlanByID(ctx context.Context, id graphql.ID) (CampaignPlanResolver, error) {
 	return nil, a8nOnlyInEnterprise
 }
@@ -175,18 +457,144 @@ func (defaultA8NResolver) ChangesetPlanByID(ctx context.Context, id graphql.ID)
 	return nil, a8nOnlyInEnterprise
 }
 
+// CampaignMetrics is called to resolve Site.campaignMetrics.
+//
+// This is contributed by enterprise.
+var CampaignMetrics func(context.Context, *CampaignMetricsArgs) (CampaignMetricsResolver, error)
+
+type CampaignMetricsArgs struct {
+	Days   *int32
+	Weeks  *int32
+	Months *int32
+}
+
+func (r *siteResolver) CampaignMetrics(ctx context.Context, args *CampaignMetricsArgs) (CampaignMetricsResolver, error) {
+	if CampaignMetrics == nil {
+		return nil, a8nOnlyInEnterprise
+	}
+	return CampaignMetrics(ctx, args)
+}
+
+// CampaignWorkerQueues is called to resolve Site.campaignWorkerQueues.
+//
+// This is contributed by enterprise.
+var CampaignWorkerQueues func(context.Context) ([]CampaignWorkerQueueResolver, error)
+
+func (r *siteResolver) CampaignWorkerQueues(ctx context.Context) ([]CampaignWorkerQueueResolver, error) {
+	if CampaignWorkerQueues == nil {
+		return nil, a8nOnlyInEnterprise
+	}
+	return CampaignWorkerQueues(ctx)
+}
+
+type CampaignWorkerQueueFailuresArgs struct {
+	First *int32
+	State *string
+}
+
+type CampaignWorkerQueueResolver interface {
+	Name() string
+	Queued() int32
+	Processing() int32
+	Completed() int32
+	Retrying() int32
+	Dead() int32
+	OldestQueuedAt() *DateTime
+	Failures(ctx context.Context, args *CampaignWorkerQueueFailuresArgs) ([]CampaignWorkerJobResolver, error)
+}
+
+// CampaignWebhookDeliveries is called to resolve Site.campaignWebhookDeliveries.
+//
+// This is contributed by enterprise.
+var CampaignWebhookDeliveries func(context.Context, *CampaignWebhookDeliveriesArgs) (CampaignWebhookDeliveryConnectionResolver, error)
+
+type CampaignWebhookDeliveriesArgs struct {
+	First  *int32
+	After  *string
+	Failed *bool
+}
+
+func (r *siteResolver) CampaignWebhookDeliveries(ctx context.Context, args *CampaignWebhookDeliveriesArgs) (CampaignWebhookDeliveryConnectionResolver, error) {
+	if CampaignWebhookDeliveries == nil {
+		return nil, a8nOnlyInEnterprise
+	}
+	return CampaignWebhookDeliveries(ctx, args)
+}
+
+type CampaignWebhookDeliveryConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CampaignWebhookDeliveryResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type CampaignWebhookDeliveryResolver interface {
+	ID() graphql.ID
+	Channel() string
+	URL() string
+	Recipient(ctx context.Context) (*UserResolver, error)
+	Payload() JSONValue
+	Succeeded() bool
+	SucceededAt() *DateTime
+	LastAttemptedAt() *DateTime
+	CreatedAt() DateTime
+	Attempts(ctx context.Context) ([]CampaignWebhookDeliveryAttemptResolver, error)
+}
+
+type CampaignWebhookDeliveryAttemptResolver interface {
+	Succeeded() bool
+	StatusCode() *int32
+	ResponseBody() *string
+	Error() *string
+	DurationMilliseconds() int32
+	AttemptedAt() DateTime
+}
+
+type CampaignWorkerJobResolver interface {
+	State() string
+	Payload() JSONValue
+	Attempts() int32
+	MaxAttempts() int32
+	Error() *string
+	RunAfter() DateTime
+	CreatedAt() DateTime
+	UpdatedAt() DateTime
+	FinishedAt() *DateTime
+}
+
+type CampaignMetricsResolver interface {
+	Daily(ctx context.Context) ([]CampaignMetricsPeriodResolver, error)
+	Weekly(ctx context.Context) ([]CampaignMetricsPeriodResolver, error)
+	Monthly(ctx context.Context) ([]CampaignMetricsPeriodResolver, error)
+}
+
+type CampaignMetricsPeriodResolver interface {
+	StartTime() DateTime
+	CampaignsCreated() int32
+	ChangesetsMerged() int32
+	MedianTimeToMerge() *float64
+}
+
 type ChangesetCountsArgs struct {
 	From *DateTime
 	To   *DateTime
 }
 
+type CampaignByNameResultResolver interface {
+	Campaign() CampaignResolver
+	Renamed() bool
+}
+
 type CampaignResolver interface {
 	ID() graphql.ID
 	Name() string
 	Description() string
 	Branch() *string
+	Spec() (*JSONValue, error)
 	Author(ctx context.Context) (*UserResolver, error)
 	ViewerCanAdminister(ctx context.Context) (bool, error)
+	ViewerIsSubscribed(ctx context.Context) (bool, error)
+	HasUnreadActivity(ctx context.Context) (bool, error)
+	ViewerLastViewedAt(ctx context.Context) (*DateTime, error)
 	URL(ctx context.Context) (string, error)
 	Namespace(ctx context.Context) (n NamespaceResolver, err error)
 	CreatedAt() DateTime
@@ -194,19 +602,149 @@ type CampaignResolver interface {
 	Changesets(ctx context.Context, args struct{ graphqlutil.ConnectionArgs }) ExternalChangesetsConnectionResolver
 	ChangesetCountsOverTime(ctx context.Context, args *ChangesetCountsArgs) ([]ChangesetCountsResolver, error)
 	RepositoryDiffs(ctx context.Context, args *graphqlutil.ConnectionArgs) (RepositoryComparisonConnectionResolver, error)
+	DiffStat(ctx context.Context) (*DiffStat, error)
+	ChangesetsWithConflictsCount(ctx context.Context) (int32, error)
+	AutoRebase() bool
+	Position() string
 	Plan(ctx context.Context) (CampaignPlanResolver, error)
+	RollbackOf(ctx context.Context) (CampaignResolver, error)
+	Rollback(ctx context.Context) (CampaignResolver, error)
+	Parent(ctx context.Context) (CampaignResolver, error)
+	Children(ctx context.Context, args *graphqlutil.ConnectionArgs) CampaignsConnectionResolver
+	Progress(ctx context.Context) (CampaignProgressResolver, error)
+	ChangesetCheckStates(ctx context.Context) (ChangesetCheckStateCountsResolver, error)
 	Status(context.Context) (BackgroundProcessStatus, error)
+	CloseStatus(context.Context) (BackgroundProcessStatus, error)
 	ClosedAt() *DateTime
 	PublishedAt(ctx context.Context) (*DateTime, error)
 	ChangesetPlans(ctx context.Context, args *graphqlutil.ConnectionArgs) ChangesetPlansConnectionResolver
+	Activity(ctx context.Context, args *CampaignActivityArgs) (CampaignActivityConnectionResolver, error)
+}
+
+type CampaignActivityArgs struct {
+	graphqlutil.ConnectionArgs
+	After *string
+}
+
+type CampaignActivityConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CampaignActivityEventResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+// CampaignActivityEventResolver resolves the CampaignActivityEvent union.
+type CampaignActivityEventResolver interface {
+	ToCampaignCreatedEvent() (CampaignCreatedEventResolver, bool)
+	ToChangesetAddedEvent() (ChangesetAddedEventResolver, bool)
+	ToChangesetMergedEvent() (ChangesetMergedEventResolver, bool)
+	ToChangesetCommentedEvent() (ChangesetCommentedEventResolver, bool)
+}
+
+type CampaignCreatedEventResolver interface {
+	CreatedAt() DateTime
+	Author(ctx context.Context) (*UserResolver, error)
+}
+
+type ChangesetAddedEventResolver interface {
+	CreatedAt() DateTime
+	Changeset() ExternalChangesetResolver
+}
+
+type ChangesetMergedEventResolver interface {
+	CreatedAt() DateTime
+	Changeset() ExternalChangesetResolver
+}
+
+type ChangesetCommentedEventResolver interface {
+	CreatedAt() DateTime
+	Changeset() ExternalChangesetResolver
+	Author() (string, error)
+	Body() (string, error)
+}
+
+type UpdateCampaignsResultResolver interface {
+	ID() graphql.ID
+	Campaign() CampaignResolver
+	Error() *string
 }
 
 type CampaignsConnectionResolver interface {
 	Nodes(ctx context.Context) ([]CampaignResolver, error)
 	TotalCount(ctx context.Context) (int32, error)
+	ApproximateCount(ctx context.Context) (int32, error)
+	UnreadCount(ctx context.Context) (int32, error)
 	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
 }
 
+type ChangesetCheckStateCountsResolver interface {
+	Total() int32
+	Pending() int32
+	Passed() int32
+	Failed() int32
+	Unknown() int32
+}
+
+type CampaignProgressResolver interface {
+	Total() int32
+	Open() int32
+	Merged() int32
+	Closed() int32
+	Deleted() int32
+}
+
+type CampaignSavedFilterResolver interface {
+	ID() graphql.ID
+	Name() string
+	Query() *string
+	State() *string
+	Namespaces() *[]graphql.ID
+	HasOpenChangesets() bool
+	ChangesetState() *string
+	IsDefault() bool
+	CreatedAt() DateTime
+	UpdatedAt() DateTime
+}
+
+type CampaignNamespaceSettingsResolver interface {
+	Namespace(ctx context.Context) (NamespaceResolver, error)
+	BranchPrefix() string
+	DefaultLabels() []string
+	RequireApproval() bool
+	NotificationChannel() string
+	NotificationWebhookURL(ctx context.Context) (*string, error)
+	UpdatedAt() *DateTime
+}
+
+type CampaignNotificationSettingsResolver interface {
+	NotifyMerged() bool
+	NotifyClosed() bool
+	NotifyChecksFailed() bool
+	DigestIntervalMinutes() int32
+	UpdatedAt() *DateTime
+}
+
+type CampaignFacetsResolver interface {
+	States() []CampaignStateFacetResolver
+	Authors() []CampaignAuthorFacetResolver
+}
+
+type CampaignStateFacetResolver interface {
+	State() a8n.CampaignState
+	Count() int32
+}
+
+type CampaignAuthorFacetResolver interface {
+	Author(ctx context.Context) (*UserResolver, error)
+	Count() int32
+}
+
+type CampaignRepositoryActivityResolver interface {
+	Repository() *RepositoryResolver
+	OpenCampaigns() int32
+	OpenChangesets() int32
+	ChangesetsMergedThisWeek() int32
+}
+
 type ExternalChangesetsConnectionResolver interface {
 	Nodes(ctx context.Context) ([]ExternalChangesetResolver, error)
 	TotalCount(ctx context.Context) (int32, error)
@@ -234,9 +772,32 @@ type ExternalChangesetResolver interface {
 	Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error)
 	Events(ctx context.Context, args *struct{ graphqlutil.ConnectionArgs }) (ChangesetEventsConnectionResolver, error)
 	Diff(ctx context.Context) (*RepositoryComparisonResolver, error)
+	DiffStat() (*DiffStat, error)
+	HasConflicts() (*bool, error)
+	Rebases(ctx context.Context) ([]ChangesetRebaseResolver, error)
 	Head(ctx context.Context) (*GitRefResolver, error)
 	Base(ctx context.Context) (*GitRefResolver, error)
 	Labels(ctx context.Context) ([]ChangesetLabelResolver, error)
+	ReviewerRequest(ctx context.Context) (ChangesetReviewerRequestResolver, error)
+}
+
+type ChangesetRebaseResolver interface {
+	BaseOid() GitObjectID
+	Reexecuted() bool
+	Error() *string
+	CreatedAt() DateTime
+}
+
+type RenderedChangesetTemplateResolver interface {
+	Title() *string
+	Body() *string
+	Errors() []string
+}
+
+type ChangesetReviewerRequestResolver interface {
+	Reviewers() []string
+	RequestedAt() *DateTime
+	Error() *string
 }
 
 type ChangesetPlansConnectionResolver interface {
@@ -252,6 +813,48 @@ type ChangesetPlanResolver interface {
 	Diff() ChangesetPlanResolver
 	FileDiffs(ctx context.Context, args *graphqlutil.ConnectionArgs) (PreviewFileDiffConnection, error)
 	PublicationEnqueued(ctx context.Context) (bool, error)
+	ExecutionLog(ctx context.Context, args *ExecutionLogArgs) (ExecutionLogResolver, error)
+	ExecutionError() *string
+	HighlightedDiff(ctx context.Context, args *HighlightedDiffArgs) (HighlightedDiffHunkConnectionResolver, error)
+}
+
+type ExecutionLogArgs struct {
+	After *string
+}
+
+type ExecutionLogResolver interface {
+	Output() string
+	EndCursor() *string
+	HasNextPage() bool
+	Completed() bool
+}
+
+type HighlightedDiffArgs struct {
+	graphqlutil.ConnectionArgs
+	After              *string
+	IsLightTheme       bool
+	HighlightLongLines bool
+}
+
+type HighlightedDiffHunkConnectionResolver interface {
+	Nodes(ctx context.Context) ([]HighlightedDiffHunkResolver, error)
+	TotalCount() int32
+	PageInfo() *graphqlutil.PageInfo
+}
+
+type HighlightedDiffHunkResolver interface {
+	OldPath() *string
+	NewPath() *string
+	OldRange() *DiffHunkRange
+	NewRange() *DiffHunkRange
+	Section() *string
+	Lines() []HighlightedDiffHunkLineResolver
+	Aborted() bool
+}
+
+type HighlightedDiffHunkLineResolver interface {
+	Kind() string
+	HTML() string
 }
 
 type ChangesetEventsConnectionResolver interface {
@@ -301,6 +904,8 @@ type CampaignPlanResolver interface {
 
 	ChangesetPlans(ctx context.Context, args *graphqlutil.ConnectionArgs) ChangesetPlansConnectionResolver
 
+	AllChangesetPlans(ctx context.Context, args *graphqlutil.ConnectionArgs) ChangesetPlansConnectionResolver
+
 	PreviewURL() string
 }
 
@@ -0,0 +1,125 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+// scopedFields are the GraphQL fields that actors restricted to access token scopes (see
+// actor.Actor.Scopes) may resolve, by scope. Fields are keyed as "Type.field", and a type name
+// alone allows all fields of the type. Actors restricted to scopes can't resolve any other field.
+var scopedFields = map[string]map[string]bool{}
+
+// RegisterScopedFields allows actors restricted to the given access token scope to resolve the
+// given GraphQL fields, which are either "Type.field" or a type name to allow all fields of the
+// type. Only types whose fields are all allowed can be looked up with the node field. It must be
+// called before the schema is used, e.g. by the packages that implement the resolvers.
+//
+// 🚨 SECURITY: The resolvers of the root fields must check the scope themselves, e.g. with
+// backend.CheckActorHasScope, since they are also reachable with the other scopes.
+func RegisterScopedFields(scope string, fields ...string) {
+	if scopedFields[scope] == nil {
+		scopedFields[scope] = map[string]bool{}
+	}
+	for _, f := range fields {
+		scopedFields[scope][f] = true
+	}
+}
+
+// checkScopedFields returns an error if a registered scoped field or type doesn't exist in the
+// schema, so that a misspelled field doesn't silently deny a scope what it should allow.
+func checkScopedFields(schema *graphql.Schema) error {
+	known := map[string]bool{}
+	for _, typ := range schema.Inspect().Types() {
+		name := *typ.Name()
+		known[name] = true
+		if fields := typ.Fields(&struct{ IncludeDeprecated bool }{true}); fields != nil {
+			for _, f := range *fields {
+				known[name+"."+f.Name()] = true
+			}
+		}
+	}
+
+	for scope, fields := range scopedFields {
+		for f := range fields {
+			if !known[f] {
+				return fmt.Errorf("field %q of access token scope %q is not in the GraphQL schema", f, scope)
+			}
+		}
+	}
+	return nil
+}
+
+// actorMayResolveField reports whether the actor may resolve the given field of the given type.
+// Actors that are not restricted to access token scopes may resolve all fields.
+func actorMayResolveField(a *actor.Actor, typeName, fieldName string) bool {
+	if len(a.Scopes) == 0 {
+		return true
+	}
+	if strings.HasPrefix(typeName, "__") || strings.HasPrefix(fieldName, "__") {
+		// Introspection doesn't reveal any data.
+		return true
+	}
+	for _, scope := range a.Scopes {
+		if fields := scopedFields[scope]; fields[typeName] || fields[typeName+"."+fieldName] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkActorMayResolveNode returns an error if the actor of ctx is restricted to access token
+// scopes and none of them allows all fields of nodes of the given kind.
+func checkActorMayResolveNode(ctx context.Context, kind string) error {
+	a := actor.FromContext(ctx)
+	if len(a.Scopes) == 0 {
+		return nil
+	}
+	for _, scope := range a.Scopes {
+		if scopedFields[scope][kind] {
+			return nil
+		}
+	}
+	return &backend.InsufficientAuthorizationError{Message: fmt.Sprintf("access token restricted to scopes %q may not look up nodes of type %q", a.Scopes, kind)}
+}
+
+// scopeTracer rejects the fields that the actor of the request may not resolve (see
+// actorMayResolveField) before their resolvers are called. It is a tracer because the tracer is
+// called for every field that is resolved, including the fields of nested and fragment
+// selections.
+type scopeTracer struct {
+	trace.Tracer
+}
+
+func (t scopeTracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, args map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
+	traceCtx, finish := t.Tracer.TraceField(ctx, label, typeName, fieldName, trivial, args)
+
+	// 🚨 SECURITY: The executor doesn't call the resolver of a field if the context returned
+	// by the tracer is done, and reports the error of the context instead.
+	if a := actor.FromContext(ctx); !actorMayResolveField(a, typeName, fieldName) {
+		err := &backend.InsufficientAuthorizationError{Message: fmt.Sprintf("access token restricted to scopes %q may not request %s.%s", a.Scopes, typeName, fieldName)}
+		return deniedContext{Context: traceCtx, err: err}, finish
+	}
+	return traceCtx, finish
+}
+
+// deniedContext is a context that is done with the given error.
+type deniedContext struct {
+	context.Context
+	err error
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+func (c deniedContext) Done() <-chan struct{} { return closedChan }
+func (c deniedContext) Err() error            { return c.err }
@@ -0,0 +1,235 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+const scopeTestSchema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	campaign: Campaign
+	repository: Repository
+}
+
+type Mutation {
+	deleteCampaign: Boolean
+	deleteRepository: Boolean
+}
+
+type Campaign {
+	name: String!
+	repository: Repository!
+}
+
+type Repository {
+	name: String!
+	secret: String!
+}
+`
+
+type scopeTestResolver struct{ called []string }
+
+func (r *scopeTestResolver) Campaign() *scopeTestCampaign {
+	r.called = append(r.called, "Query.campaign")
+	return &scopeTestCampaign{r}
+}
+
+func (r *scopeTestResolver) Repository() *scopeTestRepository {
+	r.called = append(r.called, "Query.repository")
+	return &scopeTestRepository{r}
+}
+
+func (r *scopeTestResolver) DeleteCampaign() *bool {
+	r.called = append(r.called, "Mutation.deleteCampaign")
+	return nil
+}
+
+func (r *scopeTestResolver) DeleteRepository() *bool {
+	r.called = append(r.called, "Mutation.deleteRepository")
+	return nil
+}
+
+type scopeTestCampaign struct{ r *scopeTestResolver }
+
+func (c *scopeTestCampaign) Name() string { return "c" }
+
+func (c *scopeTestCampaign) Repository() *scopeTestRepository {
+	c.r.called = append(c.r.called, "Campaign.repository")
+	return &scopeTestRepository{c.r}
+}
+
+type scopeTestRepository struct{ r *scopeTestResolver }
+
+func (r *scopeTestRepository) Name() string { return "r" }
+
+func (r *scopeTestRepository) Secret() string {
+	r.r.called = append(r.r.called, "Repository.secret")
+	return "s"
+}
+
+func TestScopeTracer(t *testing.T) {
+	defer func(orig map[string]map[string]bool) { scopedFields = orig }(scopedFields)
+	scopedFields = map[string]map[string]bool{}
+	RegisterScopedFields("campaigns", "Query.campaign", "Mutation.deleteCampaign", "Campaign", "Repository.name")
+	RegisterScopedFields("repos", "Query.repository", "Repository")
+
+	for _, tc := range []struct {
+		name       string
+		actor      *actor.Actor
+		query      string
+		wantData   string
+		wantErrs   []string
+		wantCalled []string
+	}{
+		{
+			name:       "unrestricted actor",
+			actor:      &actor.Actor{UID: 1},
+			query:      `{ campaign { repository { secret } } repository { name } }`,
+			wantData:   `{"campaign":{"repository":{"secret":"s"}},"repository":{"name":"r"}}`,
+			wantCalled: []string{"Campaign.repository", "Query.campaign", "Query.repository", "Repository.secret"},
+		},
+		{
+			name:       "allowed fields",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"campaigns"}},
+			query:      `query { campaign { name repository { name } } __typename }`,
+			wantData:   `{"campaign":{"name":"c","repository":{"name":"r"}},"__typename":"Query"}`,
+			wantCalled: []string{"Campaign.repository", "Query.campaign"},
+		},
+		{
+			name:       "root field of other scope",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"campaigns"}},
+			query:      `query { campaign { name } r: repository { name } }`,
+			wantData:   `{"campaign":{"name":"c"},"r":null}`,
+			wantErrs:   []string{"may not request Query.repository"},
+			wantCalled: []string{"Query.campaign"},
+		},
+		{
+			name:       "nested field",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"campaigns"}},
+			query:      `query { campaign { repository { secret } } }`,
+			wantData:   `{"campaign":null}`,
+			wantErrs:   []string{"may not request Repository.secret"},
+			wantCalled: []string{"Campaign.repository", "Query.campaign"},
+		},
+		{
+			name:       "fragments",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"campaigns"}},
+			query:      `query { ...Q } fragment Q on Query { ... on Query { repository { name } } }`,
+			wantData:   `{"repository":null}`,
+			wantErrs:   []string{"may not request Query.repository"},
+			wantCalled: nil,
+		},
+		{
+			name:       "mutations",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"campaigns"}},
+			query:      `mutation { deleteCampaign deleteRepository }`,
+			wantData:   `{"deleteCampaign":null,"deleteRepository":null}`,
+			wantErrs:   []string{"may not request Mutation.deleteRepository"},
+			wantCalled: []string{"Mutation.deleteCampaign"},
+		},
+		{
+			name:       "several scopes",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"campaigns", "repos"}},
+			query:      `query { campaign { name } repository { secret } }`,
+			wantData:   `{"campaign":{"name":"c"},"repository":{"secret":"s"}}`,
+			wantCalled: []string{"Query.campaign", "Query.repository", "Repository.secret"},
+		},
+		{
+			name:       "introspection",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{"repos"}},
+			query:      `query { __type(name: "Campaign") { name } }`,
+			wantData:   `{"__type":{"name":"Campaign"}}`,
+			wantCalled: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resolver := &scopeTestResolver{}
+			schema := graphql.MustParseSchema(scopeTestSchema, resolver, graphql.Tracer(scopeTracer{trace.NoopTracer{}}))
+
+			ctx := actor.WithActor(context.Background(), tc.actor)
+			res := schema.Exec(ctx, tc.query, "", nil)
+
+			if have := string(res.Data); have != tc.wantData {
+				t.Errorf("have data %s, want %s", have, tc.wantData)
+			}
+
+			if len(res.Errors) != len(tc.wantErrs) {
+				t.Fatalf("have errors %v, want %q", res.Errors, tc.wantErrs)
+			}
+			for i, err := range res.Errors {
+				if !strings.Contains(err.Message, tc.wantErrs[i]) {
+					t.Errorf("have error %q, want %q", err.Message, tc.wantErrs[i])
+				}
+			}
+
+			sort.Strings(resolver.called)
+			if !reflect.DeepEqual(resolver.called, tc.wantCalled) {
+				t.Errorf("have resolvers %q called, want %q", resolver.called, tc.wantCalled)
+			}
+		})
+	}
+}
+
+func TestCheckActorMayResolveNode(t *testing.T) {
+	defer func(orig map[string]map[string]bool) { scopedFields = orig }(scopedFields)
+	scopedFields = map[string]map[string]bool{}
+	RegisterScopedFields("campaigns", "Query.node", "Campaign", "Repository.name")
+
+	for _, tc := range []struct {
+		name    string
+		actor   *actor.Actor
+		kind    string
+		wantErr bool
+	}{
+		{name: "unrestricted actor", actor: &actor.Actor{UID: 1}, kind: "Repository"},
+		{name: "allowed type", actor: &actor.Actor{UID: 1, Scopes: []string{"campaigns"}}, kind: "Campaign"},
+		{name: "partially allowed type", actor: &actor.Actor{UID: 1, Scopes: []string{"campaigns"}}, kind: "Repository", wantErr: true},
+		{name: "other type", actor: &actor.Actor{UID: 1, Scopes: []string{"campaigns"}}, kind: "User", wantErr: true},
+		{name: "other scope", actor: &actor.Actor{UID: 1, Scopes: []string{"repos"}}, kind: "Campaign", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkActorMayResolveNode(actor.WithActor(context.Background(), tc.actor), tc.kind)
+			if tc.wantErr {
+				if _, ok := err.(*backend.InsufficientAuthorizationError); !ok {
+					t.Fatalf("have error %v, want InsufficientAuthorizationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCheckScopedFields(t *testing.T) {
+	defer func(orig map[string]map[string]bool) { scopedFields = orig }(scopedFields)
+	schema := graphql.MustParseSchema(scopeTestSchema, &scopeTestResolver{})
+
+	scopedFields = map[string]map[string]bool{}
+	RegisterScopedFields("campaigns", "Query.campaign", "Mutation.deleteCampaign", "Campaign", "Repository.name")
+	if err := checkScopedFields(schema); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"Query.campaigns", "Campaigns", "Repository.Name"} {
+		scopedFields = map[string]map[string]bool{}
+		RegisterScopedFields("campaigns", field)
+		if err := checkScopedFields(schema); err == nil {
+			t.Errorf("have no error for %q, want error", field)
+		}
+	}
+}
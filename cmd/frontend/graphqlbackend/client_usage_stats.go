@@ -0,0 +1,107 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+type clientUsageStatisticsResolver struct {
+	clientUsageStatistics *types.ClientUsageStatistics
+}
+
+func (r *siteResolver) ClientUsageStatistics(ctx context.Context, args *struct {
+	Days   *int32
+	Weeks  *int32
+	Months *int32
+	From   *DateTime
+	To     *DateTime
+}) (*clientUsageStatisticsResolver, error) {
+	// 🚨 SECURITY: Only site admins can see which clients the users of this site use.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := &usagestats.ClientUsageStatisticsOptions{}
+	if args.Days != nil {
+		d := int(*args.Days)
+		opt.DayPeriods = &d
+	}
+	opt.DayRange = usageStatisticsDayRange(args.From, args.To, opt.DayPeriods)
+	if args.Weeks != nil {
+		w := int(*args.Weeks)
+		opt.WeekPeriods = &w
+	}
+	if args.Months != nil {
+		m := int(*args.Months)
+		opt.MonthPeriods = &m
+	}
+	activity, err := usagestats.GetClientUsageStatistics(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &clientUsageStatisticsResolver{activity}, nil
+}
+
+func (s *clientUsageStatisticsResolver) DAUs() []*clientUsagePeriodResolver {
+	return s.activities(s.clientUsageStatistics.DAUs)
+}
+
+func (s *clientUsageStatisticsResolver) WAUs() []*clientUsagePeriodResolver {
+	return s.activities(s.clientUsageStatistics.WAUs)
+}
+
+func (s *clientUsageStatisticsResolver) MAUs() []*clientUsagePeriodResolver {
+	return s.activities(s.clientUsageStatistics.MAUs)
+}
+
+func (s *clientUsageStatisticsResolver) activities(periods []*types.ClientUsagePeriod) []*clientUsagePeriodResolver {
+	resolvers := make([]*clientUsagePeriodResolver, 0, len(periods))
+	for _, p := range periods {
+		resolvers = append(resolvers, &clientUsagePeriodResolver{clientUsagePeriod: p})
+	}
+	return resolvers
+}
+
+type clientUsagePeriodResolver struct {
+	clientUsagePeriod *types.ClientUsagePeriod
+}
+
+func (s *clientUsagePeriodResolver) StartTime() DateTime {
+	return DateTime{s.clientUsagePeriod.StartTime}
+}
+
+func (s *clientUsagePeriodResolver) WebUserCount() int32 {
+	return s.clientUsagePeriod.WebUserCount
+}
+
+func (s *clientUsagePeriodResolver) BrowserExtensionUserCount() int32 {
+	return s.clientUsagePeriod.BrowserExtensionUserCount
+}
+
+func (s *clientUsagePeriodResolver) EditorUserCount() int32 {
+	return s.clientUsagePeriod.EditorUserCount
+}
+
+func (s *clientUsagePeriodResolver) APIUserCount() int32 {
+	return s.clientUsagePeriod.APIUserCount
+}
+
+// usageStatisticsDayRange returns the range of daily periods selected by the from and to
+// arguments of usage statistics fields, or nil if neither is set.
+func usageStatisticsDayRange(from, to *DateTime, days *int) *usagestats.DayRange {
+	if from == nil && to == nil {
+		return nil
+	}
+
+	r := &usagestats.DayRange{First: days}
+	if from != nil {
+		r.From = &from.Time
+	}
+	if to != nil {
+		r.To = &to.Time
+	}
+	return r
+}
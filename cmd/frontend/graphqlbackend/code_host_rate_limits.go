@@ -0,0 +1,113 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+)
+
+func (r *siteResolver) CodeHostRateLimits(ctx context.Context) ([]*codeHostRateLimitResolver, error) {
+	// 🚨 SECURITY: Only site admins can see the rate limits of external services.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := repoupdater.DefaultClient.RateLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Campaigns also make API requests from the frontend, so its budgets can
+	// be more up to date than the ones of repo-updater.
+	byID := make(map[int64]protocol.RateLimit, len(result.RateLimits))
+	for _, l := range result.RateLimits {
+		byID[l.ExternalServiceID] = l
+	}
+	for _, b := range ratelimit.DefaultBudgets.All() {
+		l := protocol.RateLimit{
+			ExternalServiceID: b.ExternalServiceID,
+			Kind:              b.Kind,
+			Known:             b.Known,
+			Limit:             b.Limit,
+			Remaining:         b.Remaining,
+			ResetAt:           b.ResetAt,
+			RetryAt:           b.RetryAt,
+		}
+		if other, ok := byID[l.ExternalServiceID]; !ok || newerRateLimit(l, other) {
+			byID[l.ExternalServiceID] = l
+		}
+	}
+
+	resolvers := make([]*codeHostRateLimitResolver, 0, len(byID))
+	for _, l := range byID {
+		resolvers = append(resolvers, &codeHostRateLimitResolver{rateLimit: l})
+	}
+	sort.Slice(resolvers, func(i, j int) bool {
+		return resolvers[i].rateLimit.ExternalServiceID < resolvers[j].rateLimit.ExternalServiceID
+	})
+	return resolvers, nil
+}
+
+// newerRateLimit reports whether a is more recent than b, two observations of
+// the rate limit of the same token. The remaining budget only decreases until
+// the rate limit resets.
+func newerRateLimit(a, b protocol.RateLimit) bool {
+	switch {
+	case !a.Known || !b.Known:
+		return a.Known
+	case !a.ResetAt.Equal(b.ResetAt):
+		return a.ResetAt.After(b.ResetAt)
+	default:
+		return a.Remaining < b.Remaining
+	}
+}
+
+type codeHostRateLimitResolver struct {
+	rateLimit protocol.RateLimit
+}
+
+func (r *codeHostRateLimitResolver) ExternalService(ctx context.Context) (*externalServiceResolver, error) {
+	externalService, err := db.ExternalServices.GetByID(ctx, r.rateLimit.ExternalServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalServiceResolver{externalService: externalService}, nil
+}
+
+func (r *codeHostRateLimitResolver) Known() bool { return r.rateLimit.Known }
+
+func (r *codeHostRateLimitResolver) Limit() *int32 {
+	if !r.rateLimit.Known {
+		return nil
+	}
+	limit := int32(r.rateLimit.Limit)
+	return &limit
+}
+
+func (r *codeHostRateLimitResolver) Remaining() *int32 {
+	if !r.rateLimit.Known {
+		return nil
+	}
+	remaining := int32(r.rateLimit.Remaining)
+	return &remaining
+}
+
+func (r *codeHostRateLimitResolver) ResetAt() *DateTime {
+	if !r.rateLimit.Known {
+		return nil
+	}
+	return &DateTime{Time: r.rateLimit.ResetAt}
+}
+
+func (r *codeHostRateLimitResolver) RetryAt() *DateTime {
+	if r.rateLimit.RetryAt.IsZero() {
+		return nil
+	}
+	return &DateTime{Time: r.rateLimit.RetryAt}
+}
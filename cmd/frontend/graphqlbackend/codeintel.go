@@ -15,7 +15,10 @@ type CodeIntelResolver interface {
 	LSIFUploadByID(ctx context.Context, id graphql.ID) (LSIFUploadResolver, error)
 	LSIFUploads(ctx context.Context, args *LSIFRepositoryUploadsQueryArgs) (LSIFUploadConnectionResolver, error)
 	DeleteLSIFUpload(ctx context.Context, id graphql.ID) (*EmptyResponse, error)
+	RetryLSIFUpload(ctx context.Context, id graphql.ID) (LSIFUploadResolver, error)
 	LSIF(ctx context.Context, args *LSIFQueryArgs) (LSIFQueryResolver, error)
+	CodeIntelSupport(ctx context.Context, repo *RepositoryResolver) ([]CodeIntelLanguageSupportResolver, error)
+	LSIFCoverage(ctx context.Context, repo *RepositoryResolver, args *LSIFCoverageArgs) (LSIFCoverageResolver, error)
 }
 
 var codeIntelOnlyInEnterprise = errors.New("lsif uploads and queries are only available in enterprise")
@@ -34,15 +37,40 @@ func (defaultCodeIntelResolver) DeleteLSIFUpload(ctx context.Context, id graphql
 	return nil, codeIntelOnlyInEnterprise
 }
 
+func (defaultCodeIntelResolver) RetryLSIFUpload(ctx context.Context, id graphql.ID) (LSIFUploadResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
 func (defaultCodeIntelResolver) LSIF(ctx context.Context, args *LSIFQueryArgs) (LSIFQueryResolver, error) {
 	return nil, codeIntelOnlyInEnterprise
 }
 
+func (defaultCodeIntelResolver) CodeIntelSupport(ctx context.Context, repo *RepositoryResolver) ([]CodeIntelLanguageSupportResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
+func (defaultCodeIntelResolver) LSIFCoverage(ctx context.Context, repo *RepositoryResolver, args *LSIFCoverageArgs) (LSIFCoverageResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
 func (r *schemaResolver) DeleteLSIFUpload(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error) {
 	// We need to override the embedded method here as it takes slightly different arguments
 	return r.CodeIntelResolver.DeleteLSIFUpload(ctx, args.ID)
 }
 
+func (r *schemaResolver) RetryLSIFUpload(ctx context.Context, args *struct{ ID graphql.ID }) (LSIFUploadResolver, error) {
+	// We need to override the embedded method here as it takes slightly different arguments
+	return r.CodeIntelResolver.RetryLSIFUpload(ctx, args.ID)
+}
+
+func (r *schemaResolver) LSIFUploads(ctx context.Context, args *LSIFUploadsQueryArgs) (LSIFUploadConnectionResolver, error) {
+	// We need to override the embedded method here as it takes slightly different arguments.
+	// Without a repository ID, the uploads of all repositories are listed.
+	return r.CodeIntelResolver.LSIFUploads(ctx, &LSIFRepositoryUploadsQueryArgs{
+		LSIFUploadsQueryArgs: args,
+	})
+}
+
 type LSIFUploadsQueryArgs struct {
 	graphqlutil.ConnectionArgs
 	Query           *string
@@ -74,6 +102,39 @@ type LSIFUploadFailureReasonResolver interface {
 	Stacktrace() string
 }
 
+type CodeIntelLanguageSupportResolver interface {
+	Language() string
+	Indexer() *string
+	Upload() LSIFUploadResolver
+	CommitsBehind() *int32
+	SearchBased() bool
+}
+
+type LSIFCoverageArgs struct {
+	Rev *string
+}
+
+type LSIFCoverageResolver interface {
+	Commit() GitObjectID
+	Uploads() []LSIFUploadCoverageResolver
+	Entries(args *struct{ Path string }) []LSIFCoverageEntryResolver
+}
+
+type LSIFUploadCoverageResolver interface {
+	Upload() LSIFUploadResolver
+	Root() string
+	Indexer() *string
+	FileCount() int32
+}
+
+type LSIFCoverageEntryResolver interface {
+	Path() string
+	IsDirectory() bool
+	FileCount() int32
+	CoveredFileCount() int32
+	Uploads() []LSIFUploadResolver
+}
+
 type LSIFUploadConnectionResolver interface {
 	Nodes(ctx context.Context) ([]LSIFUploadResolver, error)
 	TotalCount(ctx context.Context) (*int32, error)
@@ -82,9 +143,13 @@ type LSIFUploadConnectionResolver interface {
 
 type LSIFQueryResolver interface {
 	Commit(ctx context.Context) (*GitCommitResolver, error)
+	IsExactCommit() bool
+	SkippedUploads() []LSIFUploadResolver
 	Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
-	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
+	ReferenceCount(ctx context.Context, args *LSIFQueryPositionArgs) (int32, error)
+	Hover(ctx context.Context, args *LSIFQueryHoverArgs) (HoverResolver, error)
+	Hovers(ctx context.Context, args *LSIFQueryHoversArgs) ([]HoverResolver, error)
 }
 
 type LSIFQueryArgs struct {
@@ -99,18 +164,55 @@ type LSIFQueryPositionArgs struct {
 	Character int32
 }
 
+type LSIFQueryHoverArgs struct {
+	LSIFQueryPositionArgs
+	Format string
+}
+
+type LSIFQueryHoversArgs struct {
+	Positions []LSIFQueryPositionArgs
+	Format    string
+}
+
 type LSIFPagedQueryPositionArgs struct {
 	LSIFQueryPositionArgs
 	graphqlutil.ConnectionArgs
 	After *string
 }
 
+type LocationConnectionNodesArgs struct {
+	ContextLines *int32
+}
+
 type LocationConnectionResolver interface {
-	Nodes(ctx context.Context) ([]LocationResolver, error)
+	Nodes(ctx context.Context, args *LocationConnectionNodesArgs) ([]LocationResolver, error)
+	Groups(ctx context.Context, args *LocationConnectionNodesArgs) ([]LocationRepositoryGroupResolver, error)
 	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+	Partial() bool
+	Truncated() bool
+	CountEstimate() int32
+}
+
+type LocationRepositoryGroupResolver interface {
+	Repository() *RepositoryResolver
+	Count() int32
+	Files() []LocationFileGroupResolver
+}
+
+type LocationFileGroupResolver interface {
+	File() *GitTreeEntryResolver
+	Count() int32
+	Locations() []LocationResolver
 }
 
 type HoverResolver interface {
 	Markdown() MarkdownResolver
+	Contents() []HoverContentResolver
 	Range() RangeResolver
 }
+
+type HoverContentResolver interface {
+	Format() string
+	Value() string
+	Language() *string
+}
@@ -0,0 +1,101 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// LogCodeIntelQuery records a code intel query of the current user, so that it is counted in
+// the site's code intel query statistics.
+func LogCodeIntelQuery(ctx context.Context, q *types.CodeIntelQuery) {
+	userID := actor.FromContext(ctx).UID
+	goroutine.Go(func() {
+		if err := usagestats.LogCodeIntelQuery(userID, q); err != nil {
+			log15.Warn("Could not log code intel query", "operation", q.Operation, "error", err)
+		}
+	})
+}
+
+func (r *siteResolver) CodeIntelQueryStatistics(ctx context.Context, args *struct {
+	Days *int32
+}) ([]*codeIntelQueryStatisticsResolver, error) {
+	// 🚨 SECURITY: Only site admins may view code intel query statistics.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := &usagestats.CodeIntelQueryStatisticsOptions{}
+	if args.Days != nil {
+		d := int(*args.Days)
+		opt.Days = &d
+	}
+	stats, err := usagestats.GetCodeIntelQueryStatistics(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*codeIntelQueryStatisticsResolver, 0, len(stats))
+	for _, s := range stats {
+		resolvers = append(resolvers, &codeIntelQueryStatisticsResolver{stats: s})
+	}
+	return resolvers, nil
+}
+
+type codeIntelQueryStatisticsResolver struct {
+	stats *types.CodeIntelQueryStatistics
+}
+
+func (r *codeIntelQueryStatisticsResolver) Indexer() string { return r.stats.Indexer }
+
+func (r *codeIntelQueryStatisticsResolver) Language() string { return r.stats.Language }
+
+func (r *codeIntelQueryStatisticsResolver) UsersCount() int32 { return r.stats.UsersCount }
+
+func (r *codeIntelQueryStatisticsResolver) QueriesCount() int32 { return r.stats.QueriesCount }
+
+func (r *siteResolver) CodeIntelQueryPathStatistics(ctx context.Context, args *struct {
+	Days *int32
+}) ([]*codeIntelQueryPathStatisticsResolver, error) {
+	// 🚨 SECURITY: Only site admins may view code intel query statistics.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := &usagestats.CodeIntelQueryStatisticsOptions{}
+	if args.Days != nil {
+		d := int(*args.Days)
+		opt.Days = &d
+	}
+	stats, err := usagestats.GetCodeIntelQueryPathStatistics(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*codeIntelQueryPathStatisticsResolver, 0, len(stats))
+	for _, s := range stats {
+		resolvers = append(resolvers, &codeIntelQueryPathStatisticsResolver{stats: s})
+	}
+	return resolvers, nil
+}
+
+type codeIntelQueryPathStatisticsResolver struct {
+	stats *types.CodeIntelQueryPathStatistics
+}
+
+func (r *codeIntelQueryPathStatisticsResolver) CodePath() string { return r.stats.CodePath }
+
+func (r *codeIntelQueryPathStatisticsResolver) Variant() []string { return r.stats.Variant }
+
+func (r *codeIntelQueryPathStatisticsResolver) QueriesCount() int32 { return r.stats.QueriesCount }
+
+func (r *codeIntelQueryPathStatisticsResolver) P50() float64 { return r.stats.Latencies.P50 }
+
+func (r *codeIntelQueryPathStatisticsResolver) P90() float64 { return r.stats.Latencies.P90 }
+
+func (r *codeIntelQueryPathStatisticsResolver) P99() float64 { return r.stats.Latencies.P99 }
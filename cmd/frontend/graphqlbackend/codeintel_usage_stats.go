@@ -15,6 +15,8 @@ func (r *siteResolver) CodeIntelUsageStatistics(ctx context.Context, args *struc
 	Days   *int32
 	Weeks  *int32
 	Months *int32
+	From   *DateTime
+	To     *DateTime
 }) (*codeIntelUsageStatisticsResolver, error) {
 	opt := &usagestats.CodeIntelUsageStatisticsOptions{
 		IncludeEventCounts:    true,
@@ -24,6 +26,7 @@ func (r *siteResolver) CodeIntelUsageStatistics(ctx context.Context, args *struc
 		d := int(*args.Days)
 		opt.DayPeriods = &d
 	}
+	opt.DayRange = usageStatisticsDayRange(args.From, args.To, opt.DayPeriods)
 	if args.Weeks != nil {
 		w := int(*args.Weeks)
 		opt.WeekPeriods = &w
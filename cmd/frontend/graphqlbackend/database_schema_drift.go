@@ -0,0 +1,73 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func (r *siteResolver) DatabaseSchemaDrift(ctx context.Context) (*databaseSchemaDriftResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the database schema.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	drift, err := db.CheckSchemaDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &databaseSchemaDriftResolver{drift: drift}, nil
+}
+
+type databaseSchemaDriftResolver struct {
+	drift *db.SchemaDrift
+}
+
+func (r *databaseSchemaDriftResolver) Version() string {
+	return strconv.FormatInt(r.drift.Version, 10)
+}
+
+func (r *databaseSchemaDriftResolver) Dirty() bool { return r.drift.Dirty }
+
+func (r *databaseSchemaDriftResolver) PendingMigrations() []string {
+	if r.drift.PendingMigrations == nil {
+		return []string{}
+	}
+	return r.drift.PendingMigrations
+}
+
+func (r *databaseSchemaDriftResolver) Differences() []*databaseSchemaDifferenceResolver {
+	resolvers := make([]*databaseSchemaDifferenceResolver, 0, len(r.drift.Differences))
+	for _, d := range r.drift.Differences {
+		resolvers = append(resolvers, &databaseSchemaDifferenceResolver{diff: d})
+	}
+	return resolvers
+}
+
+type databaseSchemaDifferenceResolver struct {
+	diff *db.SchemaDifference
+}
+
+func (r *databaseSchemaDifferenceResolver) Kind() string {
+	return strings.ToUpper(string(r.diff.Kind))
+}
+
+func (r *databaseSchemaDifferenceResolver) Table() string { return r.diff.Table }
+
+func (r *databaseSchemaDifferenceResolver) Name() *string { return nonEmptyString(r.diff.Name) }
+
+func (r *databaseSchemaDifferenceResolver) Expected() *string { return nonEmptyString(r.diff.Expected) }
+
+func (r *databaseSchemaDifferenceResolver) Actual() *string { return nonEmptyString(r.diff.Actual) }
+
+func (r *databaseSchemaDifferenceResolver) Description() string { return r.diff.String() }
+
+func nonEmptyString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
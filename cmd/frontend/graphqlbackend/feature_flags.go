@@ -0,0 +1,240 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/featureflag"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func (r *schemaResolver) FeatureFlagEnabled(ctx context.Context, args *struct{ Name string }) (bool, error) {
+	f, err := lookupFeatureFlag(args.Name)
+	if err != nil {
+		return false, err
+	}
+	return f.Enabled(ctx), nil
+}
+
+func (r *siteResolver) FeatureFlags(ctx context.Context) ([]*featureFlagResolver, error) {
+	// 🚨 SECURITY: Only site admins can see the values and overrides of feature flags.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	stored, err := db.FeatureFlags.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*types.FeatureFlag, len(stored))
+	for _, f := range stored {
+		byName[f.Name] = f
+	}
+
+	// Stored values of flags that this version doesn't declare are omitted.
+	flags := featureflag.All()
+	resolvers := make([]*featureFlagResolver, 0, len(flags))
+	for _, f := range flags {
+		resolvers = append(resolvers, &featureFlagResolver{flag: f, stored: byName[f.Name]})
+	}
+	return resolvers, nil
+}
+
+func (r *schemaResolver) UpdateFeatureFlag(ctx context.Context, args *struct {
+	Name              string
+	Enabled           bool
+	RolloutPercentage *int32
+}) (*featureFlagResolver, error) {
+	// 🚨 SECURITY: Only site admins can change feature flags.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	f, err := lookupFeatureFlag(args.Name)
+	if err != nil {
+		return nil, err
+	}
+	if p := args.RolloutPercentage; p != nil && (*p < 0 || *p > 100) {
+		return nil, errors.Errorf("rollout percentage %d is not between 0 and 100", *p)
+	}
+
+	stored := &types.FeatureFlag{
+		Name:              f.Name,
+		Enabled:           args.Enabled,
+		RolloutPercentage: args.RolloutPercentage,
+	}
+	if err := db.FeatureFlags.Upsert(ctx, stored); err != nil {
+		return nil, err
+	}
+	return &featureFlagResolver{flag: f, stored: stored}, nil
+}
+
+func (r *schemaResolver) ResetFeatureFlag(ctx context.Context, args *struct{ Name string }) (*featureFlagResolver, error) {
+	// 🚨 SECURITY: Only site admins can change feature flags.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	f, err := lookupFeatureFlag(args.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.FeatureFlags.Delete(ctx, f.Name); err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	return &featureFlagResolver{flag: f}, nil
+}
+
+type featureFlagOverrideArgs struct {
+	Name      string
+	Namespace graphql.ID
+}
+
+func (r *schemaResolver) SetFeatureFlagOverride(ctx context.Context, args *struct {
+	featureFlagOverrideArgs
+	Enabled bool
+}) (*featureFlagResolver, error) {
+	f, userID, orgID, err := featureFlagOverrideTarget(ctx, &args.featureFlagOverrideArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.FeatureFlags.UpsertOverride(ctx, &types.FeatureFlagOverride{
+		FlagName:        f.Name,
+		NamespaceUserID: userID,
+		NamespaceOrgID:  orgID,
+		Enabled:         args.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newFeatureFlagResolver(ctx, f)
+}
+
+func (r *schemaResolver) DeleteFeatureFlagOverride(ctx context.Context, args *featureFlagOverrideArgs) (*featureFlagResolver, error) {
+	f, userID, orgID, err := featureFlagOverrideTarget(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.FeatureFlags.DeleteOverride(ctx, f.Name, userID, orgID); err != nil {
+		return nil, err
+	}
+	return newFeatureFlagResolver(ctx, f)
+}
+
+// featureFlagOverrideTarget returns the feature flag and the user or org of an
+// override of the flag.
+func featureFlagOverrideTarget(ctx context.Context, args *featureFlagOverrideArgs) (f *featureflag.Flag, userID, orgID int32, err error) {
+	// 🚨 SECURITY: Only site admins can change feature flags.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, 0, 0, err
+	}
+
+	if f, err = lookupFeatureFlag(args.Name); err != nil {
+		return nil, 0, 0, err
+	}
+
+	// Looking up the namespace ensures that it exists.
+	if _, err := NamespaceByID(ctx, args.Namespace); err != nil {
+		return nil, 0, 0, err
+	}
+	switch relay.UnmarshalKind(args.Namespace) {
+	case "User":
+		userID, err = UnmarshalUserID(args.Namespace)
+	case "Org":
+		orgID, err = UnmarshalOrgID(args.Namespace)
+	}
+	return f, userID, orgID, err
+}
+
+func lookupFeatureFlag(name string) (*featureflag.Flag, error) {
+	f, ok := featureflag.Lookup(name)
+	if !ok {
+		return nil, errors.Errorf("unknown feature flag %q", name)
+	}
+	return f, nil
+}
+
+func newFeatureFlagResolver(ctx context.Context, f *featureflag.Flag) (*featureFlagResolver, error) {
+	stored, err := db.FeatureFlags.GetByName(ctx, f.Name)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	return &featureFlagResolver{flag: f, stored: stored}, nil
+}
+
+type featureFlagResolver struct {
+	flag   *featureflag.Flag
+	stored *types.FeatureFlag // nil if the flag has its default value
+}
+
+func (r *featureFlagResolver) Name() string        { return r.flag.Name }
+func (r *featureFlagResolver) Description() string { return r.flag.Description }
+func (r *featureFlagResolver) Default() bool       { return r.flag.Default }
+func (r *featureFlagResolver) Stored() bool        { return r.stored != nil }
+
+func (r *featureFlagResolver) Enabled() bool {
+	if r.stored == nil {
+		return r.flag.Default
+	}
+	return r.stored.Enabled
+}
+
+func (r *featureFlagResolver) RolloutPercentage() *int32 {
+	if r.stored == nil {
+		return nil
+	}
+	return r.stored.RolloutPercentage
+}
+
+func (r *featureFlagResolver) Overrides(ctx context.Context) ([]*featureFlagOverrideResolver, error) {
+	overrides, err := db.FeatureFlags.ListOverrides(ctx, r.flag.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*featureFlagOverrideResolver, 0, len(overrides))
+	for _, o := range overrides {
+		resolvers = append(resolvers, &featureFlagOverrideResolver{override: o})
+	}
+	return resolvers, nil
+}
+
+func (r *featureFlagResolver) UpdatedAt() *DateTime {
+	if r.stored == nil {
+		return nil
+	}
+	return &DateTime{Time: r.stored.UpdatedAt}
+}
+
+type featureFlagOverrideResolver struct {
+	override *types.FeatureFlagOverride
+}
+
+func (r *featureFlagOverrideResolver) Namespace(ctx context.Context) (*NamespaceResolver, error) {
+	var (
+		n   Namespace
+		err error
+	)
+	if r.override.NamespaceUserID != 0 {
+		n, err = UserByIDInt32(ctx, r.override.NamespaceUserID)
+	} else {
+		n, err = OrgByIDInt32(ctx, r.override.NamespaceOrgID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &NamespaceResolver{n}, nil
+}
+
+func (r *featureFlagOverrideResolver) Enabled() bool { return r.override.Enabled }
+
+func (r *featureFlagOverrideResolver) UpdatedAt() DateTime {
+	return DateTime{Time: r.override.UpdatedAt}
+}
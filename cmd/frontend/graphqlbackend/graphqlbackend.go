@@ -107,6 +107,7 @@ func NewSchema(a8n A8NResolver, codeIntel CodeIntelResolver, authz AuthzResolver
 		CodeIntelResolver: defaultCodeIntelResolver{},
 	}
 	if a8n != nil {
+		EnterpriseResolvers.a8nResolver = a8n
 		resolver.A8NResolver = a8n
 	}
 	if codeIntel != nil {
@@ -118,11 +119,18 @@ func NewSchema(a8n A8NResolver, codeIntel CodeIntelResolver, authz AuthzResolver
 		resolver.AuthzResolver = authz
 	}
 
-	return graphql.ParseSchema(
+	schema, err := graphql.ParseSchema(
 		Schema,
 		resolver,
-		graphql.Tracer(prometheusTracer{}),
+		graphql.Tracer(scopeTracer{prometheusTracer{}}),
 	)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScopedFields(schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
 }
 
 // EmptyResponse is a type that can be used in the return signature for graphql queries
@@ -250,6 +258,11 @@ func (r *NodeResolver) ToLSIFUpload() (LSIFUploadResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToLSIFUploadToken() (*lsifUploadTokenResolver, bool) {
+	n, ok := r.Node.(*lsifUploadTokenResolver)
+	return n, ok
+}
+
 // schemaResolver handles all GraphQL queries for Sourcegraph. To do this, it
 // uses subresolvers which are globals. Enterprise-only resolvers are assigned
 // to a field of EnterpriseResolvers.
@@ -262,9 +275,11 @@ type schemaResolver struct {
 // EnterpriseResolvers holds the instances of resolvers which are enabled only
 // in enterprise mode. These resolver instances are nil when running as OSS.
 var EnterpriseResolvers = struct {
+	a8nResolver       A8NResolver
 	codeIntelResolver CodeIntelResolver
 	authzResolver     AuthzResolver
 }{
+	a8nResolver:       defaultA8NResolver{},
 	codeIntelResolver: defaultCodeIntelResolver{},
 	authzResolver:     defaultAuthzResolver{},
 }
@@ -275,6 +290,12 @@ func (r *schemaResolver) Root() *schemaResolver {
 }
 
 func (r *schemaResolver) Node(ctx context.Context, args *struct{ ID graphql.ID }) (*NodeResolver, error) {
+	// 🚨 SECURITY: Actors restricted to access token scopes may only look up the nodes of the
+	// types that their scopes allow.
+	if err := checkActorMayResolveNode(ctx, relay.UnmarshalKind(args.ID)); err != nil {
+		return nil, err
+	}
+
 	n, err := r.nodeByID(ctx, args.ID)
 	if err != nil {
 		return nil, err
@@ -332,6 +353,8 @@ func (r *schemaResolver) nodeByID(ctx context.Context, id graphql.ID) (Node, err
 		return siteByGQLID(ctx, id)
 	case "LSIFUpload":
 		return r.LSIFUploadByID(ctx, id)
+	case "LSIFUploadToken":
+		return lsifUploadTokenByID(ctx, id)
 	default:
 		return nil, errors.New("invalid id")
 	}
@@ -13,11 +13,13 @@ type LocationResolver interface {
 	Range() *rangeResolver
 	URL(ctx context.Context) (string, error)
 	CanonicalURL() (string, error)
+	Content() LocationContentResolver
 }
 
 type locationResolver struct {
 	resource *GitTreeEntryResolver
 	lspRange *lsp.Range
+	content  LocationContentResolver
 }
 
 var _ LocationResolver = &locationResolver{}
@@ -29,8 +31,20 @@ func NewLocationResolver(resource *GitTreeEntryResolver, lspRange *lsp.Range) Lo
 	}
 }
 
+// NewLocationResolverWithContent returns a LocationResolver whose content field resolves
+// to the given content, which may be nil if it wasn't fetched.
+func NewLocationResolverWithContent(resource *GitTreeEntryResolver, lspRange *lsp.Range, content LocationContentResolver) LocationResolver {
+	return &locationResolver{
+		resource: resource,
+		lspRange: lspRange,
+		content:  content,
+	}
+}
+
 func (r *locationResolver) Resource() *GitTreeEntryResolver { return r.resource }
 
+func (r *locationResolver) Content() LocationContentResolver { return r.content }
+
 func (r *locationResolver) Range() *rangeResolver {
 	if r.lspRange == nil {
 		return nil
@@ -62,6 +76,29 @@ func (r *locationResolver) urlPath(prefix string) string {
 	return url
 }
 
+type LocationContentResolver interface {
+	Line() string
+	Before() []string
+	After() []string
+}
+
+type locationContentResolver struct {
+	line          string
+	before, after []string
+}
+
+var _ LocationContentResolver = &locationContentResolver{}
+
+// NewLocationContentResolver returns a LocationContentResolver for the given line of a
+// location and the lines before and after it.
+func NewLocationContentResolver(line string, before, after []string) LocationContentResolver {
+	return &locationContentResolver{line: line, before: before, after: after}
+}
+
+func (r *locationContentResolver) Line() string     { return r.line }
+func (r *locationContentResolver) Before() []string { return r.before }
+func (r *locationContentResolver) After() []string  { return r.after }
+
 type RangeResolver interface {
 	Start() PositionResolver
 	End() PositionResolver
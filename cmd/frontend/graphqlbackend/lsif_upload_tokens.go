@@ -0,0 +1,121 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func (r *schemaResolver) CreateLSIFUploadToken(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Note       string
+}) (*createLSIFUploadTokenResult, error) {
+	// 🚨 SECURITY: Only site admins can create upload tokens.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	id, token, err := db.LSIFUploadTokens.Create(ctx, repo.repo.ID, args.Note, actor.FromContext(ctx).UID)
+	if err != nil {
+		return nil, err
+	}
+	return &createLSIFUploadTokenResult{id: marshalLSIFUploadTokenID(id), token: token}, nil
+}
+
+type createLSIFUploadTokenResult struct {
+	id    graphql.ID
+	token string
+}
+
+func (r *createLSIFUploadTokenResult) ID() graphql.ID { return r.id }
+func (r *createLSIFUploadTokenResult) Token() string  { return r.token }
+
+func (r *schemaResolver) RevokeLSIFUploadToken(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can revoke upload tokens.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalLSIFUploadTokenID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.LSIFUploadTokens.DeleteByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *RepositoryResolver) LSIFUploadTokens(ctx context.Context) ([]*lsifUploadTokenResolver, error) {
+	// 🚨 SECURITY: Only site admins can list upload tokens.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	tokens, err := db.LSIFUploadTokens.List(ctx, r.repo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*lsifUploadTokenResolver, 0, len(tokens))
+	for _, t := range tokens {
+		resolvers = append(resolvers, &lsifUploadTokenResolver{token: *t})
+	}
+	return resolvers, nil
+}
+
+// lsifUploadTokenResolver resolves an LSIF upload token.
+type lsifUploadTokenResolver struct {
+	token db.LSIFUploadToken
+}
+
+func lsifUploadTokenByID(ctx context.Context, id graphql.ID) (*lsifUploadTokenResolver, error) {
+	// 🚨 SECURITY: Only site admins can view upload tokens.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	tokenID, err := unmarshalLSIFUploadTokenID(id)
+	if err != nil {
+		return nil, err
+	}
+	token, err := db.LSIFUploadTokens.GetByID(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return &lsifUploadTokenResolver{token: *token}, nil
+}
+
+func marshalLSIFUploadTokenID(id int64) graphql.ID { return relay.MarshalID("LSIFUploadToken", id) }
+
+func unmarshalLSIFUploadTokenID(id graphql.ID) (tokenID int64, err error) {
+	err = relay.UnmarshalSpec(id, &tokenID)
+	return
+}
+
+func (r *lsifUploadTokenResolver) ID() graphql.ID { return marshalLSIFUploadTokenID(r.token.ID) }
+
+func (r *lsifUploadTokenResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	return RepositoryByIDInt32(ctx, r.token.RepoID)
+}
+
+func (r *lsifUploadTokenResolver) Note() string { return r.token.Note }
+
+func (r *lsifUploadTokenResolver) Creator(ctx context.Context) (*UserResolver, error) {
+	return UserByIDInt32(ctx, r.token.CreatorUserID)
+}
+
+func (r *lsifUploadTokenResolver) CreatedAt() DateTime { return DateTime{Time: r.token.CreatedAt} }
+
+func (r *lsifUploadTokenResolver) LastUsedAt() *DateTime {
+	return DateTimeOrNil(r.token.LastUsedAt)
+}
@@ -272,6 +272,14 @@ func (r *RepositoryResolver) LSIFUploads(ctx context.Context, args *LSIFUploadsQ
 	})
 }
 
+func (r *RepositoryResolver) CodeIntelSupport(ctx context.Context) ([]CodeIntelLanguageSupportResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.CodeIntelSupport(ctx, r)
+}
+
+func (r *RepositoryResolver) LSIFCoverage(ctx context.Context, args *LSIFCoverageArgs) (LSIFCoverageResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.LSIFCoverage(ctx, r, args)
+}
+
 type AuthorizedUserArgs struct {
 	RepositoryID graphql.ID
 	Perm         string
@@ -0,0 +1,84 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// repositoryLoader caches the repositories looked up by LoadRepositories for
+// the duration of a GraphQL request, so that resolvers of the same request
+// that refer to the same repositories share their RepositoryResolvers.
+type repositoryLoader struct {
+	mu    sync.Mutex
+	repos map[api.RepoID]*RepositoryResolver
+}
+
+type repositoryLoaderKey struct{}
+
+// WithRepositoryLoader returns a context for a GraphQL request in which
+// LoadRepositories caches the repositories it looks up.
+func WithRepositoryLoader(ctx context.Context) context.Context {
+	return context.WithValue(ctx, repositoryLoaderKey{}, &repositoryLoader{
+		repos: map[api.RepoID]*RepositoryResolver{},
+	})
+}
+
+// LoadRepositories returns the resolvers of the repositories with the given
+// IDs, in the same order. The repositories that haven't been loaded yet during
+// the request are looked up in a single query. If a repository doesn't exist
+// or the current user can't access it, the error of looking it up by its ID is
+// returned.
+func LoadRepositories(ctx context.Context, ids ...api.RepoID) ([]*RepositoryResolver, error) {
+	l, _ := ctx.Value(repositoryLoaderKey{}).(*repositoryLoader)
+	if l == nil {
+		l = &repositoryLoader{repos: map[api.RepoID]*RepositoryResolver{}}
+	}
+
+	// The lock is held while querying, so that concurrent resolvers don't
+	// look up the same repositories again.
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var missing []api.RepoID
+	seen := map[api.RepoID]bool{}
+	for _, id := range ids {
+		if _, ok := l.repos[id]; !ok && !seen[id] {
+			seen[id] = true
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		// 🚨 SECURITY: Listing repositories only returns the ones the current
+		// user has access to.
+		repos, err := backend.Repos.List(ctx, db.ReposListOptions{IDs: missing})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			l.repos[repo.ID] = NewRepositoryResolver(repo)
+		}
+	}
+
+	resolvers := make([]*RepositoryResolver, 0, len(ids))
+	for _, id := range ids {
+		r, ok := l.repos[id]
+		if !ok {
+			// Returns the same error as other lookups of the repository,
+			// e.g. a not found error.
+			repo, err := backend.Repos.Get(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			r = NewRepositoryResolver(repo)
+			l.repos[id] = r
+		}
+		resolvers = append(resolvers, r)
+	}
+
+	return resolvers, nil
+}
@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestLoadRepositories(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var listed [][]api.RepoID
+	backend.Mocks.Repos.List = func(ctx context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		listed = append(listed, opt.IDs)
+
+		var repos []*types.Repo
+		for _, id := range opt.IDs {
+			// Repository 3 is not visible to the current user.
+			if id != 3 {
+				repos = append(repos, &types.Repo{ID: id})
+			}
+		}
+		return repos, nil
+	}
+	errNotFound := errors.New("repository not found")
+	backend.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return nil, errNotFound
+	}
+
+	ctx := WithRepositoryLoader(context.Background())
+
+	repos, err := LoadRepositories(ctx, 1, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 3 || repos[0].repo.ID != 1 || repos[1].repo.ID != 2 || repos[2] != repos[0] {
+		t.Errorf("have repositories %v, want repositories 1, 2 and 1", repos)
+	}
+
+	// Repositories loaded earlier in the request are not looked up again.
+	again, err := LoadRepositories(ctx, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 2 || again[0] != repos[1] || again[1].repo.ID != 4 {
+		t.Errorf("have repositories %v, want repositories 2 and 4", again)
+	}
+
+	if want := [][]api.RepoID{{1, 2}, {4}}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("have listed %v, want %v", listed, want)
+	}
+
+	// Repositories that can't be listed return the error of getting them.
+	if _, err := LoadRepositories(ctx, 1, 3); err != errNotFound {
+		t.Errorf("have error %v, want %v", err, errNotFound)
+	}
+}
+
+func TestLoadRepositoriesWithoutLoader(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var calls int
+	backend.Mocks.Repos.List = func(ctx context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		calls++
+		return []*types.Repo{{ID: 1}}, nil
+	}
+
+	// Outside of a GraphQL request, repositories are not cached.
+	for i := 0; i < 2; i++ {
+		repos, err := LoadRepositories(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repos) != 1 || repos[0].repo.ID != 1 {
+			t.Errorf("have repositories %v, want repository 1", repos)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("have %d lookups, want 2", calls)
+	}
+}
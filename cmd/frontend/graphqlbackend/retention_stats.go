@@ -0,0 +1,57 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func (r *siteResolver) RetentionStatistics(ctx context.Context, args *struct {
+	Weeks *int32
+}) (*retentionStatisticsResolver, error) {
+	// 🚨 SECURITY: Only site admins may view retention statistics.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := &usagestats.RetentionStatisticsOptions{}
+	if args.Weeks != nil {
+		w := int(*args.Weeks)
+		opt.WeekPeriods = &w
+	}
+	retention, err := usagestats.GetRetentionStatistics(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &retentionStatisticsResolver{retention}, nil
+}
+
+type retentionStatisticsResolver struct {
+	retentionStatistics *types.RetentionStatistics
+}
+
+func (s *retentionStatisticsResolver) Weekly() []*retentionCohortResolver {
+	resolvers := make([]*retentionCohortResolver, 0, len(s.retentionStatistics.Weekly))
+	for _, c := range s.retentionStatistics.Weekly {
+		resolvers = append(resolvers, &retentionCohortResolver{retentionCohort: c})
+	}
+	return resolvers
+}
+
+type retentionCohortResolver struct {
+	retentionCohort *types.RetentionCohort
+}
+
+func (s *retentionCohortResolver) StartTime() DateTime {
+	return DateTime{s.retentionCohort.StartTime}
+}
+
+func (s *retentionCohortResolver) UsersCount() int32 {
+	return s.retentionCohort.UsersCount
+}
+
+func (s *retentionCohortResolver) RetainedUsersCounts() []int32 {
+	return s.retentionCohort.RetainedUsersCounts
+}
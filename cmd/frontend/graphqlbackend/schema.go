@@ -33,11 +33,44 @@ type Mutation {
     # Creates a list of Changesets of a given repository in a code host (e.g.
     # pull request on GitHub). If a changeset with the given input already
     # exists, it's returned instead of a new entry being added to the database.
-    createChangesets(input: [CreateChangesetInput!]!): [ExternalChangeset!]!
+    createChangesets(
+        input: [CreateChangesetInput!]!
+        # An optional key chosen by the client that identifies this request. If
+        # a request with the same key was made by the same user in the last 24
+        # hours, its result is returned instead of the mutation being run again.
+        # While that request is still being processed, the error has the
+        # extension code IDEMPOTENCY_KEY_IN_USE.
+        idempotencyKey: String
+    ): [ExternalChangeset!]!
     # Adds a list of Changesets to a Campaign.
     # The campaign must not have a campaign plan.
-    addChangesetsToCampaign(campaign: ID!, changesets: [ID!]!): Campaign!
+    #
+    # If the campaign would exceed the automation.quotas site configuration, the
+    # error has the extension code QUOTA_EXCEEDED.
+    addChangesetsToCampaign(
+        campaign: ID!
+        changesets: [ID!]!
+        # Whether to ignore the automation.quotas site configuration.
+        overrideQuotas: Boolean = false
+    ): Campaign!
+    # Imports existing changesets on code hosts (e.g. pull requests created by hand) into a
+    # campaign and starts syncing them. Changesets that are already in the campaign are skipped.
+    # The campaign must not have a campaign plan.
+    #
+    # If the campaign would exceed the automation.quotas site configuration, the
+    # error has the extension code QUOTA_EXCEEDED.
+    importChangesets(
+        campaign: ID!
+        changesets: [ImportChangesetInput!]!
+        # Whether to ignore the automation.quotas site configuration.
+        overrideQuotas: Boolean = false
+    ): Campaign!
     # Create a campaign in a namespace. The newly created campaign is returned.
+    #
+    # If another campaign in the namespace already has the given name, the error
+    # has the extension code CAMPAIGN_NAME_CONFLICT. If the campaign would exceed
+    # the automation.quotas site configuration, the error has the extension code
+    # QUOTA_EXCEEDED.
     createCampaign(input: CreateCampaignInput!): Campaign!
     # Create a campaign plan from patches (in unified diff format) that are computed by the caller.
     #
@@ -48,12 +81,41 @@ type Mutation {
         # created from this campaign plan.
         patches: [CampaignPlanPatch!]!
     ): CampaignPlan!
+    # Create a campaign plan from a campaign spec whose steps are run on the server, so that no
+    # local src-cli or Docker installation is required. The steps are run in the repositories
+    # matched by the scope queries of the spec, on their default branches. Only docker steps can
+    # be run on the server, each in a sandboxed container without network access.
+    #
+    # The changeset plans are computed in the background, as reported by CampaignPlan.status, and
+    # the output of the steps is available in ChangesetPlan.executionLog while they run. Running
+    # campaign specs on the server requires the campaign-executor service.
+    createCampaignPlanFromSpec(
+        # The campaign spec, as JSONC.
+        spec: String!
+    ): CampaignPlan!
+    # Computes the changeset plans that createCampaignPlanFromPatches would create for the given
+    # patches, without persisting anything or contacting any code host. Use this to review which
+    # repositories a campaign would touch before creating it.
+    previewCampaign(
+        # A list of patches (diffs) to apply to repositories (in new branches).
+        patches: [CampaignPlanPatch!]!
+        # Returns the first n changeset plans from the list.
+        first: Int
+    ): ChangesetPlanConnection!
     # Updates a campaign.
+    #
+    # If the campaign doesn't exist, the error has the extension code
+    # CAMPAIGN_NOT_FOUND. If it is renamed to the name of another campaign in its
+    # namespace, the error has the extension code CAMPAIGN_NAME_CONFLICT.
     updateCampaign(input: UpdateCampaignInput!): Campaign!
     # Retries creating changesets of the campaign plan that could not be successfully created on the code host.
     # Retrying will clear the errors list of a campaign and change its state back to CREATING_CHANGESETS.
     retryCampaign(campaign: ID!): Campaign!
     # Deletes a campaign.
+    #
+    # The campaign can be restored with restoreCampaign during the retention
+    # period configured in the site configuration (7 days by default), after
+    # which it is purged.
     deleteCampaign(
         campaign: ID!
         # Whether to close the changesets associated with this campaign on their
@@ -61,10 +123,40 @@ type Mutation {
         # on the codehost (e.g. "declined" on Bitbucket Server).
         closeChangesets: Boolean = false
     ): EmptyResponse
+    # Restores a deleted campaign that hasn't been purged yet. Changesets that were
+    # closed when the campaign was deleted are not reopened.
+    #
+    # If there is no such campaign, the error has the extension code
+    # CAMPAIGN_NOT_FOUND. If another campaign in its namespace took its name in the
+    # meantime, the error has the extension code CAMPAIGN_NAME_CONFLICT.
+    restoreCampaign(campaign: ID!): Campaign!
+    # Rolls back a campaign by creating a rollback campaign in the same namespace with
+    # changesets that revert the campaign's merged changesets. The revert commits are
+    # created on top of the current head of the changesets' base branches. Only changesets
+    # created from the campaign plan of the campaign are reverted.
+    #
+    # If the campaign was already rolled back, the error has the extension code
+    # CAMPAIGN_ROLLED_BACK.
+    rollbackCampaign(campaign: ID!): Campaign!
+    # Groups a campaign under an umbrella campaign, its parent, or removes it from its
+    # current parent if parent is null.
+    #
+    # If the campaign is the parent itself or one of its ancestors, the error has the
+    # extension code CAMPAIGN_PARENT_CYCLE.
+    setCampaignParent(campaign: ID!, parent: ID): Campaign!
+    # Moves a campaign right before another campaign in the same state, or after all campaigns
+    # in its state if before is null, e.g. to persist the manual ordering of a column of a board.
+    # Only the position of the moved campaign changes (see Campaign.position).
+    #
+    # If the other campaign is in another state, the error has the extension code
+    # CAMPAIGN_STATE_CHANGE.
+    moveCampaign(campaign: ID!, before: ID): Campaign!
     # Closes a campaign.
     # Closing a campaign sets the Campaign's ClosedAt timestamp to the current
     # time and, if closeChangesets = true, closes associated changesets on the
-    # codehosts.
+    # codehosts in the background. Campaign.closeStatus reports the progress
+    # of closing the changesets. The campaigns grouped under the campaign (see
+    # Campaign.children) are closed too.
     closeCampaign(
         campaign: ID!
         # Whether to close the changesets associated with this campaign on their
@@ -72,18 +164,80 @@ type Mutation {
         # on the codehost (e.g. "declined" on Bitbucket Server).
         closeChangesets: Boolean = false
     ): Campaign!
+    # Closes or reopens many campaigns at once, in a single transaction.
+    # A campaign that cannot be transitioned (e.g. because its changesets are
+    # still being created) does not prevent the other campaigns from being
+    # updated. Instead, the result for that campaign contains the error.
+    updateCampaigns(
+        ids: [ID!]!
+        # The state to transition the campaigns to. Reopening a campaign does
+        # not reopen changesets that have been closed on the codehosts.
+        state: CampaignState!
+        # Whether to close the changesets associated with the campaigns on
+        # their respective codehosts. Only used when state is CLOSED.
+        closeChangesets: Boolean = false
+        # Whether to ignore the automation.quotas site configuration when
+        # reopening campaigns. Only used when state is OPEN.
+        overrideQuotas: Boolean = false
+    ): [UpdateCampaignsResult!]!
     # Publishes the Campaign by turning its changesetPlans into changesets on
     # the codehosts.
     # The Campaign.draft field will be set to false and Campaign.status will
     # update according to the progress of turning the changesetPlans into
     # changesets.
-    publishCampaign(campaign: ID!): Campaign!
+    #
+    # If the campaign would exceed the automation.quotas site configuration, the
+    # error has the extension code QUOTA_EXCEEDED.
+    publishCampaign(
+        campaign: ID!
+        # Whether to ignore the automation.quotas site configuration.
+        overrideQuotas: Boolean = false
+    ): Campaign!
     # Creates an ExternalChangeset on the codehost asynchronously.
     # The ChangesetPlan has to belong to a CampaignPlan that has been attached
     # to a Campaign. Otherwise an error is returned.
     # Since this is an asynchronous operation, the Campaign.status field can be
     # used to keep track of progress.
-    publishChangeset(changesetPlan: ID!): EmptyResponse!
+    #
+    # If the campaign would exceed the automation.quotas site configuration, the
+    # error has the extension code QUOTA_EXCEEDED.
+    publishChangeset(
+        changesetPlan: ID!
+        # Whether to ignore the automation.quotas site configuration.
+        overrideQuotas: Boolean = false
+    ): EmptyResponse!
+    # Refreshes the state of the given ExternalChangeset from its code host,
+    # instead of waiting for it to be synced in the background.
+    #
+    # Only site admins may perform this mutation.
+    syncChangeset(changeset: ID!): EmptyResponse!
+    # Saves a named combination of filters of the list of campaigns for the current user.
+    # If the user already has a saved filter with the same name, its filters are replaced.
+    saveCampaignSavedFilter(input: SaveCampaignSavedFilterInput!): CampaignSavedFilter!
+    # Deletes a saved filter of the list of campaigns of the current user.
+    deleteCampaignSavedFilter(savedFilter: ID!): EmptyResponse!
+    # Makes a saved filter the view shown by default when the current user opens the list of
+    # campaigns, or removes the default view if savedFilter is null.
+    setDefaultCampaignSavedFilter(savedFilter: ID): EmptyResponse!
+    # Updates the defaults of the campaigns created in a namespace. Fields of the input that are
+    # null are left unchanged.
+    #
+    # Only site admins may perform this mutation.
+    updateCampaignNamespaceSettings(input: UpdateCampaignNamespaceSettingsInput!): CampaignNamespaceSettings!
+    # Updates the campaign email notification preferences of the current user. Fields of the input
+    # that are null are left unchanged.
+    updateCampaignNotificationSettings(input: UpdateCampaignNotificationSettingsInput!): CampaignNotificationSettings!
+    # Subscribes the current user to the email notifications about the changesets of a campaign, or
+    # unsubscribes them. Authors of a campaign are subscribed to it unless they unsubscribe.
+    setCampaignSubscription(campaign: ID!, subscribed: Boolean!): Campaign!
+    # Records that the current user viewed a campaign, which marks its activity until now as read
+    # (see Campaign.hasUnreadActivity).
+    markCampaignViewed(campaign: ID!): Campaign!
+    # Posts the payload of a campaign webhook delivery to its webhook again, e.g. after the webhook
+    # was fixed, and records the attempt in the delivery (see Site.campaignWebhookDeliveries).
+    #
+    # Only site admins may perform this mutation.
+    redeliverWebhook(delivery: ID!): CampaignWebhookDelivery!
 
     # Updates the user profile information for the user with the given ID.
     #
@@ -197,6 +351,12 @@ type Mutation {
     # - "user:all": Full control of all resources accessible to the user account.
     # - "site-admin:sudo": Ability to perform any action as any other user. (Only site admins may create tokens
     #   with this scope.)
+    # - "campaigns:write": Ability to view, create and manage campaigns.
+    # - "codeintel:read": Ability to query code intelligence data.
+    #
+    # A token must have the "user:all" scope or at least one of the restricted scopes "campaigns:write" and
+    # "codeintel:read". A token without the "user:all" scope may only be used for the actions of its restricted
+    # scopes.
     #
     # Only the user or site admins may perform this mutation.
     createAccessToken(user: ID!, scopes: [String!]!, note: String!): CreateAccessTokenResult!
@@ -356,6 +516,33 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     reloadSite: EmptyResponse
+    # Stores the value of a feature flag, which then applies to all users without an override of
+    # the flag instead of its default value.
+    #
+    # Only site admins may perform this mutation.
+    updateFeatureFlag(
+        # The name of the feature flag.
+        name: String!
+        # Whether the feature flag is enabled.
+        enabled: Boolean!
+        # If set, the flag is enabled for this percentage (from 0 to 100) of users instead, and
+        # disabled for anonymous users unless the percentage is 100.
+        rolloutPercentage: Int
+    ): FeatureFlag!
+    # Deletes the stored value of a feature flag, so that it has its default value again. The
+    # overrides of the flag are kept.
+    #
+    # Only site admins may perform this mutation.
+    resetFeatureFlag(name: String!): FeatureFlag!
+    # Enables or disables a feature flag for a user or an organization (and thus its members),
+    # regardless of the value of the flag.
+    #
+    # Only site admins may perform this mutation.
+    setFeatureFlagOverride(name: String!, namespace: ID!, enabled: Boolean!): FeatureFlag!
+    # Deletes the override of a feature flag for a user or an organization.
+    #
+    # Only site admins may perform this mutation.
+    deleteFeatureFlagOverride(name: String!, namespace: ID!): FeatureFlag!
     # Submits a user satisfaction (NPS) survey.
     submitSurvey(input: SurveySubmissionInput!): EmptyResponse
     # Submits a request for a Sourcegraph Enterprise trial license.
@@ -391,8 +578,31 @@ type Mutation {
     # (experimental) The LSIF API may change substantially in the near future as we
     # continue to adjust it for our use cases. Changes will not be documented in the
     # CHANGELOG during this time.
-    # Deletes an LSIF upload.
+    # Deletes an LSIF upload. Cached data of the upload is evicted and the uploads visible
+    # from the tip of the default branch are recalculated, so that code intelligence queries
+    # stop using the deleted upload immediately.
+    #
+    # Only site admins and users with write permissions on the repository of the upload may
+    # perform this mutation.
     deleteLSIFUpload(id: ID!): EmptyResponse
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # Moves an errored LSIF upload back into the queue so that it is processed again.
+    #
+    # Only site admins and users with write permissions on the repository of the upload may
+    # perform this mutation.
+    retryLSIFUpload(id: ID!): LSIFUpload!
+    # Creates an LSIF upload token for the repository. An upload token authorizes LSIF uploads
+    # to the repository, e.g. from CI jobs, when it's given in the upload_token parameter of
+    # the upload endpoint. It doesn't grant any other access.
+    #
+    # Only site admins may perform this mutation.
+    createLSIFUploadToken(repository: ID!, note: String!): CreateLSIFUploadTokenResult!
+    # Deletes and immediately revokes an LSIF upload token.
+    #
+    # Only site admins may perform this mutation.
+    revokeLSIFUploadToken(id: ID!): EmptyResponse!
 
     # Set permissions of a repository with a full set of users by their usernames or emails.
     setRepositoryPermissionsForUsers(
@@ -450,6 +660,80 @@ input CreateCampaignInput {
     # When a Campaign is created in draft mode, its changesetPlans are not
     # created on the codehost, but only when publishing the Campaign.
     draft: Boolean
+
+    # Whether to ignore the automation.quotas site configuration when creating
+    # the campaign. Default is false.
+    overrideQuotas: Boolean
+
+    # An optional key chosen by the client that identifies this request, so
+    # that retrying it doesn't create a duplicate campaign. If a request with
+    # the same key was made by the same user in the last 24 hours, the
+    # campaign it created is returned instead of a new one being created.
+    # While that request is still being processed, the error has the extension
+    # code IDEMPOTENCY_KEY_IN_USE.
+    idempotencyKey: String
+
+    # An optional campaign spec describing how the changesets of the campaign are
+    # produced, so that the campaign can be re-executed. It must match the campaign
+    # spec JSON schema, after specs of older versions are migrated to the current
+    # version. Otherwise, the error has the extension code INVALID_CAMPAIGN_SPEC.
+    spec: JSONCString
+
+    # An optional umbrella campaign to group the campaign under (see
+    # Mutation.setCampaignParent).
+    parent: ID
+
+    # Whether to rebase the open changesets of the campaign automatically (see
+    # Campaign.autoRebase). Default is false.
+    autoRebase: Boolean
+}
+
+# Input arguments for saving a filter of the list of campaigns.
+input SaveCampaignSavedFilterInput {
+    # The name of the saved filter, unique among the saved filters of the user.
+    name: String!
+    # Only list campaigns whose name or description contains this query.
+    query: String
+    # Only list campaigns in this state.
+    state: CampaignState
+    # Only list campaigns belonging to one of these namespaces (users or organizations).
+    namespaces: [ID!]
+    # Only list campaigns with at least one open changeset.
+    hasOpenChangesets: Boolean
+    # Only list campaigns with at least one changeset in this state.
+    changesetState: ChangesetState
+    # Whether to show this view by default when the user opens the list of campaigns.
+    isDefault: Boolean = false
+}
+
+# Input arguments for updating the campaign defaults of a namespace.
+input UpdateCampaignNamespaceSettingsInput {
+    # The namespace (user or organization) whose settings are updated.
+    namespace: ID!
+    # The prefix prepended to the branches of new campaigns.
+    branchPrefix: String
+    # The labels added to the changesets created by new campaigns.
+    defaultLabels: [String!]
+    # Whether new campaigns are drafts that must be published to create their changesets.
+    requireApproval: Boolean
+    # The channel through which the notifications about the campaigns are sent.
+    notificationChannel: CampaignNotificationChannel
+    # The URL of the Slack incoming webhook or the generic webhook the notifications are posted
+    # to. Required unless the notifications are sent by email.
+    notificationWebhookURL: String
+}
+
+# Input arguments for updating the campaign email notification preferences of the current user.
+input UpdateCampaignNotificationSettingsInput {
+    # Whether to be notified when a changeset is merged.
+    notifyMerged: Boolean
+    # Whether to be notified when a changeset is closed without being merged.
+    notifyClosed: Boolean
+    # Whether to be notified when the checks of an open changeset fail.
+    notifyChecksFailed: Boolean
+    # The number of minutes notifications are collected before they're sent in a single email.
+    # 0 sends them with the next digest, which runs every minute.
+    digestIntervalMinutes: Int
 }
 
 # Input arguments for updating a campaign.
@@ -472,6 +756,14 @@ input UpdateCampaignInput {
     # The Campaign's status will be updated accordingly while possibly
     # new ExternalChangesets are created/updated/closed on the codehosts.
     plan: ID
+
+    # The updated campaign spec (if non-null), which is removed if empty. See
+    # CreateCampaignInput.spec.
+    spec: JSONCString
+
+    # Whether to rebase the open changesets of the campaign automatically (if non-null). See
+    # Campaign.autoRebase.
+    autoRebase: Boolean
 }
 
 # A preview of changes that will be applied by a campaign.
@@ -491,6 +783,11 @@ type CampaignPlan implements Node {
     # The proposed patches ("plans") for the changesets that will be created by the campaign.
     changesetPlans(first: Int): ChangesetPlanConnection!
 
+    # All changeset plans of the campaign plan, including the ones that are still being computed,
+    # that failed or that have an empty diff. Unlike changesetPlans, it's useful to follow the
+    # execution of a campaign plan created with createCampaignPlanFromSpec.
+    allChangesetPlans(first: Int): ChangesetPlanConnection!
+
     # The URL where the plan can be previewed and a campaign can be created from it.
     previewURL: String!
 }
@@ -534,6 +831,15 @@ type BackgroundProcessStatus {
     errors: [String!]!
 }
 
+# The result of looking up a campaign by name.
+type CampaignByNameResult {
+    # The campaign.
+    campaign: Campaign!
+    # Whether the campaign was found by a previous name. Clients that referenced the campaign by
+    # this name should update their references to the campaign's current name.
+    renamed: Boolean!
+}
+
 # A collection of changesets.
 type Campaign implements Node {
     # The unique ID for the campaign.
@@ -543,10 +849,38 @@ type Campaign implements Node {
     # If null, changesets are added to the campaign manually.
     plan: CampaignPlan
 
+    # The campaign whose merged changesets this rollback campaign reverts (see
+    # Mutation.rollbackCampaign). Null if this isn't a rollback campaign.
+    rollbackOf: Campaign
+
+    # The rollback campaign reverting the merged changesets of this campaign, if
+    # it was rolled back.
+    rollback: Campaign
+
+    # The umbrella campaign this campaign is grouped under, if any.
+    parent: Campaign
+
+    # The campaigns grouped under this campaign.
+    children(first: Int): CampaignConnection!
+
+    # The number of changesets in each state of this campaign and of all the campaigns
+    # below it in the hierarchy of campaigns, e.g. to track the progress of an umbrella
+    # campaign.
+    progress: CampaignProgress!
+
+    # The number of open changesets of this campaign in each check state, e.g. to see how
+    # many changesets are blocked on failing CI.
+    changesetCheckStates: ChangesetCheckStateCounts!
+
     # The current status of creating or updating the campaigns changesets on
     # the code host.
     status: BackgroundProcessStatus!
 
+    # The status of closing the campaign's open changesets on the code host,
+    # with one item per changeset. Null if closing the changesets was never
+    # requested (see Mutation.closeCampaign).
+    closeStatus: BackgroundProcessStatus
+
     # The namespace where this campaign is defined.
     namespace: Namespace!
 
@@ -559,12 +893,30 @@ type Campaign implements Node {
     # The branch of the changesets created by a campaign plan.
     branch: String
 
+    # The campaign spec describing how the changesets of the campaign are produced,
+    # migrated to the current version of the campaign spec format, or null if the
+    # campaign has none.
+    spec: JSONValue
+
     # The user who authored the campaign.
     author: User!
 
     # Whether the current user can edit or delete this campaign.
     viewerCanAdminister: Boolean!
 
+    # Whether the current user receives email notifications about the changesets of this
+    # campaign (see Mutation.setCampaignSubscription).
+    viewerIsSubscribed: Boolean!
+
+    # Whether the campaign had activity since the current user last viewed it (see
+    # Mutation.markCampaignViewed): it was created, a changeset was added to it or a changeset
+    # was merged or commented on. Campaigns the user never viewed are unread. Always false if
+    # the user isn't signed in.
+    hasUnreadActivity: Boolean!
+
+    # The date and time when the current user last viewed the campaign, or null if they never did.
+    viewerLastViewedAt: DateTime
+
     # The URL to this campaign.
     url: String!
 
@@ -577,6 +929,26 @@ type Campaign implements Node {
     # The combined diff of all changesets across all repositories, already created on the code host.
     repositoryDiffs(first: Int): RepositoryComparisonConnection!
 
+    # The total number of lines added, changed and deleted by the open
+    # changesets of this campaign whose diff stat has been computed.
+    diffStat: DiffStat!
+
+    # The number of open changesets of this campaign that have merge conflicts with their
+    # base branch and need to be rebased (see ExternalChangeset.hasConflicts).
+    changesetsWithConflictsCount: Int!
+
+    # Whether the open changesets of this campaign are rebased onto the head of their base
+    # branch when they have merge conflicts with it or fall behind it. If the campaign plan
+    # ran a campaign spec on the server, its steps are run again on the new base; otherwise
+    # the diff of the changeset is applied to it. The branch of the changeset is then
+    # force-pushed. See ExternalChangeset.rebases.
+    autoRebase: Boolean!
+
+    # The position of this campaign among the campaigns in the same state, set with
+    # Mutation.moveCampaign. Campaigns are ordered by comparing their positions bytewise.
+    # Initially, campaigns are ordered by creation.
+    position: String!
+
     # The changesets in this campaign, already created on the code host.
     changesets(first: Int): ExternalChangesetConnection!
 
@@ -608,6 +980,65 @@ type Campaign implements Node {
     # Campaign.status increments with every ChangesetPlan turned into an
     # ExternalChangeset.
     changesetPlans(first: Int): ChangesetPlanConnection!
+    # The activity of the campaign and its changesets, most recent first: the
+    # creation of the campaign, changesets being added to it, and changesets
+    # being merged or commented on.
+    activity(
+        # Returns the first n events of the activity.
+        first: Int
+        # Opaque pagination cursor.
+        after: String
+    ): CampaignActivityConnection!
+}
+
+# A list of campaign activity events.
+type CampaignActivityConnection {
+    # A list of campaign activity events.
+    nodes: [CampaignActivityEvent!]!
+    # The total number of events in the connection.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# An event in the activity of a campaign.
+union CampaignActivityEvent = CampaignCreatedEvent | ChangesetAddedEvent | ChangesetMergedEvent | ChangesetCommentedEvent
+
+# The creation of a campaign.
+type CampaignCreatedEvent {
+    # The date and time when the campaign was created.
+    createdAt: DateTime!
+    # The user who created the campaign, or null if the user has been deleted.
+    author: User
+}
+
+# A changeset being added to a campaign.
+type ChangesetAddedEvent {
+    # The date and time when the changeset was added to the campaign. This is
+    # approximated by the time the changeset was created on Sourcegraph.
+    createdAt: DateTime!
+    # The changeset that was added.
+    changeset: ExternalChangeset!
+}
+
+# A changeset of a campaign being merged on the codehost.
+type ChangesetMergedEvent {
+    # The date and time when the changeset was merged.
+    createdAt: DateTime!
+    # The changeset that was merged.
+    changeset: ExternalChangeset!
+}
+
+# A comment posted on a changeset of a campaign on the codehost.
+type ChangesetCommentedEvent {
+    # The date and time when the comment was posted.
+    createdAt: DateTime!
+    # The changeset that was commented on.
+    changeset: ExternalChangeset!
+    # The username of the author of the comment on the codehost.
+    author: String!
+    # The text of the comment.
+    body: String!
 }
 
 # The counts of changesets in certain states at a specific point in time.
@@ -630,6 +1061,16 @@ type ChangesetCounts {
     openPending: Int!
 }
 
+# The result of transitioning a single campaign in Mutation.updateCampaigns.
+type UpdateCampaignsResult {
+    # The ID of the campaign, as passed to updateCampaigns.
+    id: ID!
+    # The updated campaign, or null if it could not be updated.
+    campaign: Campaign
+    # The reason the campaign could not be updated, or null if it was updated.
+    error: String
+}
+
 # A list of campaigns.
 type CampaignConnection {
     # A list of campaigns.
@@ -638,10 +1079,119 @@ type CampaignConnection {
     # The total number of campaigns in the connection.
     totalCount: Int!
 
+    # The total number of campaigns in the connection, or an estimate of it if counting
+    # them exactly takes too long, e.g. when searching the campaigns of a large instance.
+    # Prefer it to totalCount when an approximate number suffices.
+    approximateCount: Int!
+
+    # The number of campaigns in the connection with activity that the current user hasn't seen
+    # (see Campaign.hasUnreadActivity), e.g. to show the campaigns needing their attention.
+    unreadCount: Int!
+
     # Pagination information.
     pageInfo: PageInfo!
 }
 
+# The number of changesets in each state of a campaign and of all the campaigns below it.
+# Changesets belonging to several of these campaigns are counted once.
+type CampaignProgress {
+    # The total number of changesets.
+    total: Int!
+    # The number of open changesets.
+    open: Int!
+    # The number of merged changesets.
+    merged: Int!
+    # The number of closed changesets.
+    closed: Int!
+    # The number of changesets deleted on the code host.
+    deleted: Int!
+}
+
+# The number of open changesets in each check state.
+type ChangesetCheckStateCounts {
+    # The total number of open changesets.
+    total: Int!
+    # The number of open changesets whose checks are pending.
+    pending: Int!
+    # The number of open changesets whose checks passed.
+    passed: Int!
+    # The number of open changesets with at least one failed check.
+    failed: Int!
+    # The number of open changesets without checks, or whose check state is unknown.
+    unknown: Int!
+}
+
+# The defaults of the campaigns created in a namespace (a user or an organization).
+type CampaignNamespaceSettings {
+    # The namespace the settings apply to.
+    namespace: Namespace!
+    # The prefix prepended to the branch of a new campaign, unless the branch already starts with it.
+    branchPrefix: String!
+    # The labels added to the changesets created by the campaigns, on code hosts that support labels.
+    defaultLabels: [String!]!
+    # Whether new campaigns are always created as drafts, so that their changesets are only created
+    # once a site admin publishes them.
+    requireApproval: Boolean!
+    # The channel through which the notifications about the campaigns are sent.
+    notificationChannel: CampaignNotificationChannel!
+    # The URL the notifications are posted to, or null if they're sent by email. Only visible to
+    # site admins, since webhook URLs grant access to post messages.
+    notificationWebhookURL: String
+    # The date and time when the settings were last updated, or null if they were never set.
+    updatedAt: DateTime
+}
+
+# A channel through which the notifications about changes of the changesets of campaigns are sent
+# (see CampaignNotificationSettings).
+enum CampaignNotificationChannel {
+    # A digest email to each notified user.
+    EMAIL
+    # A message per notified user, mentioning them, posted to a Slack incoming webhook.
+    SLACK
+    # A JSON digest per notified user, posted to a URL.
+    WEBHOOK
+}
+
+# The campaign email notification preferences of a user. Notifications are sent for the changesets of
+# the campaigns the user authored or subscribed to.
+type CampaignNotificationSettings {
+    # Whether the user is notified when a changeset is merged.
+    notifyMerged: Boolean!
+    # Whether the user is notified when a changeset is closed without being merged.
+    notifyClosed: Boolean!
+    # Whether the user is notified when the checks of an open changeset fail.
+    notifyChecksFailed: Boolean!
+    # The number of minutes notifications are collected before they're sent in a single email.
+    digestIntervalMinutes: Int!
+    # The date and time when the settings were last updated, or null if they were never set.
+    updatedAt: DateTime
+}
+
+# A named combination of filters of the list of campaigns, saved by a user. Its fields
+# match the arguments of Query.campaigns.
+type CampaignSavedFilter {
+    # The unique ID for the saved filter.
+    id: ID!
+    # The name of the saved filter.
+    name: String!
+    # Only list campaigns whose name or description contains this query.
+    query: String
+    # Only list campaigns in this state.
+    state: CampaignState
+    # Only list campaigns belonging to one of these namespaces (users or organizations).
+    namespaces: [ID!]
+    # Only list campaigns with at least one open changeset.
+    hasOpenChangesets: Boolean!
+    # Only list campaigns with at least one changeset in this state.
+    changesetState: ChangesetState
+    # Whether this view is shown by default when the user opens the list of campaigns.
+    isDefault: Boolean!
+    # The date and time when the saved filter was created.
+    createdAt: DateTime!
+    # The date and time when the saved filter was last updated.
+    updatedAt: DateTime!
+}
+
 # A Changeset's state
 enum ChangesetState {
     OPEN
@@ -673,6 +1223,19 @@ input CreateChangesetInput {
     externalID: String!
 }
 
+# A changeset to import with the importChangesets mutation. Either externalURL or both
+# repository and externalID must be given.
+input ImportChangesetInput {
+    # The URL of the changeset on its code host, e.g. https://github.com/owner/repo/pull/1.
+    # Only GitHub and Bitbucket Server pull requests are supported.
+    externalURL: String
+    # The repository the changeset belongs to.
+    repository: ID
+    # The external ID that uniquely identifies the changeset in the repository.
+    # GitHub: PR number
+    externalID: String
+}
+
 # Preview of a changeset planned to be created.
 type ChangesetPlan {
     # The id of the changeset plan.
@@ -693,6 +1256,96 @@ type ChangesetPlan {
     # - when the ChangesetPlan has been individually published through the
     # publishChangeset mutation
     publicationEnqueued: Boolean!
+
+    # The output of the steps of the campaign spec in the repository, if the campaign plan was
+    # created with createCampaignPlanFromSpec. It grows while the steps are running, so it can be
+    # tailed by passing the endCursor of the previous result as after.
+    executionLog(
+        # Returns the output written after this cursor.
+        after: String
+    ): ExecutionLog!
+
+    # The error of running the steps of the campaign spec in the repository, e.g. the exit status
+    # of a failed step.
+    executionError: String
+
+    # The syntax highlighted hunks of the diff of the changeset. The hunks of all files are
+    # paginated together in the order of the diff, so that large diffs can be previewed without
+    # fetching them entirely.
+    highlightedDiff(
+        # Returns the first n hunks from the list.
+        first: Int
+        # Opaque pagination cursor.
+        after: String
+        # Whether to highlight with the colors of the light theme.
+        isLightTheme: Boolean = false
+        # Whether to highlight lines longer than 2000 bytes, which may produce a lot of HTML.
+        highlightLongLines: Boolean = false
+    ): HighlightedDiffHunkConnection!
+}
+
+# The output of running the steps of a campaign spec in a repository.
+type ExecutionLog {
+    # The output written after the cursor given as the after argument, or from the start.
+    output: String!
+
+    # The cursor to pass as the after argument to get the output written since. It's null if no
+    # output was written yet.
+    endCursor: String
+
+    # Whether more output was already written than returned. It can be fetched right away with
+    # endCursor.
+    hasNextPage: Boolean!
+
+    # Whether the execution completed, so that no more output will be written.
+    completed: Boolean!
+}
+
+# A list of syntax highlighted diff hunks.
+type HighlightedDiffHunkConnection {
+    # A list of syntax highlighted diff hunks.
+    nodes: [HighlightedDiffHunk!]!
+    # The total count of hunks in the diff.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A syntax highlighted hunk of a file diff.
+type HighlightedDiffHunk {
+    # The old (original) path of the file, or null if the file was added.
+    oldPath: String
+    # The new path of the file, or null if the file was deleted.
+    newPath: String
+    # The range of the old file that the hunk applies to.
+    oldRange: FileDiffHunkRange!
+    # The range of the new file that the hunk applies to.
+    newRange: FileDiffHunkRange!
+    # The diff hunk section heading, if any.
+    section: String
+    # The lines of the hunk.
+    lines: [HighlightedDiffHunkLine!]!
+    # Whether highlighting was aborted because it took too long, in which case the lines are
+    # plain text.
+    aborted: Boolean!
+}
+
+# A line of a syntax highlighted diff hunk.
+type HighlightedDiffHunkLine {
+    # Whether the line was added, deleted or left unchanged.
+    kind: DiffHunkLineKind!
+    # The HTML of the line, without the '+', '-' or ' ' prefix of the diff.
+    html: String!
+}
+
+# The kind of a line of a diff hunk.
+enum DiffHunkLineKind {
+    # The line was added.
+    ADDED
+    # The line was deleted.
+    DELETED
+    # The line is unchanged context.
+    UNCHANGED
 }
 
 # A label attached to a changeset on a codehost, mirrored
@@ -718,7 +1371,21 @@ type ExternalChangeset implements Node {
     repository: Repository!
 
     # The campaigns that have this changeset in them.
-    campaigns(first: Int, state: CampaignState): CampaignConnection!
+    campaigns(
+        # Returns the first n campaigns from the list.
+        first: Int
+        # Opaque pagination cursor.
+        after: String
+        # Only return campaigns whose name or description contains this query.
+        query: String
+        state: CampaignState
+        # Only return campaigns belonging to one of these namespaces (users or organizations).
+        namespaces: [ID!]
+        # Only return campaigns with at least one open changeset, i.e. with unmerged work.
+        hasOpenChangesets: Boolean
+        # Only return campaigns with at least one changeset in this state.
+        changesetState: ChangesetState
+    ): CampaignConnection!
 
     # The events belonging to this changeset.
     events(first: Int): ChangesetEventConnection!
@@ -757,9 +1424,47 @@ type ExternalChangeset implements Node {
     # Only returned if the changeset has not been merged or closed.
     diff: RepositoryComparison
 
+    # The number of lines added, changed and deleted by this changeset.
+    # It is null if the diff stat hasn't been computed yet or if the
+    # changeset has been merged or closed.
+    diffStat: DiffStat
+
+    # Whether the head of the changeset has merge conflicts with its base branch. Changesets
+    # are checked periodically, so it is null if the changeset hasn't been checked yet or if
+    # it has been merged or closed.
+    hasConflicts: Boolean
+
+    # The rebases of the changeset onto the head of its base branch, from oldest to newest (see
+    # Campaign.autoRebase).
+    rebases: [ChangesetRebase!]!
+
     # The state of the continuous integration checks on this changeset.
     # It can be null if no checks have been configured.
     checkState: ChangesetCheckState
+
+    # The request of the reviewers listed in the spec of the campaign that created this changeset.
+    # It is null if the changeset wasn't created by a campaign or the campaign spec lists no reviewers.
+    reviewerRequest: ChangesetReviewerRequest
+}
+
+# The changeset title and body rendered from the templates of a campaign spec.
+type RenderedChangesetTemplate {
+    # The rendered title, or null if the templates are invalid.
+    title: String
+    # The rendered body, or null if the templates are invalid.
+    body: String
+    # The errors of parsing or executing the templates.
+    errors: [String!]!
+}
+
+# The request of the reviewers listed in a campaign spec on a changeset created by the campaign.
+type ChangesetReviewerRequest {
+    # The requested users and teams, as listed in the campaign spec.
+    reviewers: [String!]!
+    # The date and time when the reviewers were requested, or null if they haven't been requested yet.
+    requestedAt: DateTime
+    # The error of the last failed attempt to request the reviewers, which is retried.
+    error: String
 }
 
 # A list of changesets.
@@ -798,6 +1503,22 @@ type ChangesetEvent implements Node {
     createdAt: DateTime!
 }
 
+# An automatic rebase of a changeset onto the head of its base branch (see Campaign.autoRebase).
+type ChangesetRebase {
+    # The commit of the base branch that the changeset was rebased onto.
+    baseOid: GitObjectID!
+
+    # Whether the steps of the campaign spec were run again on the new base, rather than
+    # the diff of the changeset being applied to it.
+    reexecuted: Boolean!
+
+    # The error of the rebase, or null if it succeeded.
+    error: String
+
+    # The date and time when the rebase happened.
+    createdAt: DateTime!
+}
+
 # A list of changeset events.
 type ChangesetEventConnection {
     # A list of changeset events.
@@ -1000,6 +1721,8 @@ enum EventSource {
     WEB
     CODEHOSTINTEGRATION
     BACKEND
+    EDITOR
+    API
 }
 
 # Input for Mutation.settingsMutation, which contains fields that all settings (global, organization, and user
@@ -1135,6 +1858,63 @@ enum CampaignState {
     CLOSED
 }
 
+# The orders of a list of campaigns.
+enum CampaignOrder {
+    # By ascending ID, i.e. by creation.
+    ID
+    # By ascending position (see Campaign.position), e.g. for the columns of a board.
+    POSITION
+}
+
+# How a user is involved in a campaign.
+enum CampaignInvolvement {
+    # The user authored the campaign.
+    AUTHOR
+    # The user was requested to review, or reviewed, a changeset of the campaign on the code host.
+    # GitHub reviewers are matched by the login of the user's GitHub external account, and
+    # Bitbucket Server reviewers by username.
+    REVIEWER
+    # The user is subscribed to the campaign (see Mutation.setCampaignSubscription).
+    SUBSCRIBER
+}
+
+# The number of campaigns in each state and by each author.
+type CampaignFacets {
+    # The number of campaigns in each state.
+    states: [CampaignStateFacet!]!
+    # The number of campaigns by each author, ordered by descending count.
+    authors: [CampaignAuthorFacet!]!
+}
+
+# The number of campaigns in a state.
+type CampaignStateFacet {
+    # The state.
+    state: CampaignState!
+    # The number of campaigns in the state.
+    count: Int!
+}
+
+# The number of campaigns created by an author.
+type CampaignAuthorFacet {
+    # The author, or null if the user no longer exists.
+    author: User
+    # The number of campaigns created by the author.
+    count: Int!
+}
+
+# The campaign activity in a repository.
+type CampaignRepositoryActivity {
+    # The repository.
+    repository: Repository!
+    # The number of open campaigns with a changeset in the repository.
+    openCampaigns: Int!
+    # The number of open changesets in the repository.
+    openChangesets: Int!
+    # The number of changesets in the repository merged since the start of the current week
+    # (Monday).
+    changesetsMergedThisWeek: Int!
+}
+
 # A query.
 type Query {
     # The root of the query.
@@ -1146,8 +1926,62 @@ type Query {
     campaigns(
         # Returns the first n campaigns from the list.
         first: Int
+        # Opaque pagination cursor.
+        after: String
+        # Only return campaigns whose name or description contains this query.
+        query: String
         state: CampaignState
+        # Only return campaigns belonging to one of these namespaces (users or organizations).
+        namespaces: [ID!]
+        # Only return campaigns with at least one open changeset, i.e. with unmerged work.
+        hasOpenChangesets: Boolean
+        # Only return campaigns with at least one changeset in this state.
+        changesetState: ChangesetState
+        # The order of the campaigns.
+        orderBy: CampaignOrder = ID
     ): CampaignConnection!
+    # The number of campaigns in each state and by each author, counted in a single pass
+    # for rendering the filters of a list of campaigns.
+    campaignFacets(
+        # Only count campaigns belonging to one of these namespaces (users or organizations).
+        namespaces: [ID!]
+    ): CampaignFacets!
+    # The campaign activity in each repository with a changeset of a campaign, counted in a single
+    # query for dashboards. Repositories are ordered by descending number of open changesets.
+    campaignRepositoryActivity(
+        # Only count the changesets of campaigns belonging to one of these namespaces (users or
+        # organizations).
+        namespaces: [ID!]
+        # Only return the activity in these repositories.
+        repositories: [ID!]
+    ): [CampaignRepositoryActivity!]!
+    # Looks up a campaign in a namespace by its current name or, if no campaign currently has that name,
+    # by a name it previously had. Returns null if no campaign matches.
+    campaignByName(
+        # The namespace (user or organization) the campaign belongs to.
+        namespace: ID!
+        # The current or a previous name of the campaign.
+        name: String!
+    ): CampaignByNameResult
+    # The saved filters of the list of campaigns of the current user, ordered by name.
+    campaignSavedFilters: [CampaignSavedFilter!]!
+    # The defaults of the campaigns created in the given namespace (user or organization).
+    campaignNamespaceSettings(namespace: ID!): CampaignNamespaceSettings!
+    # The campaign email notification preferences of the current user.
+    campaignNotificationSettings: CampaignNotificationSettings!
+    # Renders the changeset title and body templates of a campaign spec, to preview them.
+    # The templates are rendered with the values of the given campaign and changeset plan,
+    # and with example values in place of missing ones.
+    renderChangesetTemplate(
+        # The Go template of the changeset title.
+        title: String!
+        # The Go template of the changeset body.
+        body: String!
+        # The campaign whose name, description, URL and branch are used.
+        campaign: ID
+        # The changeset plan whose repository, base ref, description and diff stat are used.
+        changesetPlan: ID
+    ): RenderedChangesetTemplate!
 
     # Looks up a repository by either name or cloneURL.
     repository(
@@ -1291,6 +2125,8 @@ type Query {
     viewerConfiguration: ConfigurationCascade! @deprecated(reason: "use viewerSettings instead")
     # The configuration for clients.
     clientConfiguration: ClientConfigurationDetails!
+    # Whether the feature flag with the given name is enabled for the current user.
+    featureFlagEnabled(name: String!): Boolean!
     # Fetch search filter suggestions for autocompletion.
     searchFilterSuggestions: SearchFilterSuggestions!
     # Runs a search.
@@ -1367,6 +2203,34 @@ type Query {
     # Returns a list of usernames or emails that have associated pending permissions.
     # The returned list can be used to query authorizedUserRepositories for pending permissions.
     usersWithPendingPermissions: [String!]!
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The LSIF uploads of all repositories, most recently uploaded first. This can be
+    # used to inspect the LSIF processing queue.
+    #
+    # For users who aren't site admins, only the uploads of repositories they can read are
+    # returned, pages may have fewer uploads than requested, and the total count is null.
+    lsifUploads(
+        # An (optional) search query that searches over the commit, root and failure properties.
+        query: String
+
+        # The state of returned uploads.
+        state: LSIFUploadState
+
+        # When specified, indicates that this request should be paginated and
+        # the first N results (relative to the cursor) should be returned. i.e.
+        # how many results to return per page. It must be in the range of 0-5000.
+        first: Int
+
+        # When specified, indicates that this request should be paginated and
+        # to fetch results starting at this cursor.
+        #
+        # A future request can be made for more results by passing in the
+        # 'LSIFUploadConnection.pageInfo.endCursor' that is returned.
+        after: String
+    ): LSIFUploadConnection!
 }
 
 # The version of the search syntax.
@@ -1912,6 +2776,26 @@ type Repository implements Node & GenericSearchResultInterface {
         after: String
     ): LSIFUploadConnection!
 
+    # The code intelligence support for each language of the repository at the tip of its default
+    # branch. Languages without a precise LSIF indexer or LSIF upload fall back to search-based code
+    # intelligence. Returns an empty list if the repository is empty.
+    codeIntelSupport: [CodeIntelLanguageSupport!]!
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The LSIF coverage of the repository at a commit: which of its files and directories the LSIF
+    # uploads closest to the commit contain data for.
+    lsifCoverage(
+        # The revision to report the coverage of. Defaults to the tip of the default branch.
+        rev: String
+    ): LSIFCoverage!
+
+    # The LSIF upload tokens of the repository, most recently created first.
+    #
+    # Only site admins may access this field.
+    lsifUploadTokens: [LSIFUploadToken!]!
+
     # A list of authorized users to access this repository with the given permission.
     # This API currently only returns permissions from the Sourcegraph provider, i.e.
     # "permissions.userMapping" in site configuration.
@@ -2262,6 +3146,20 @@ type Location {
     url: String!
     # The canonical URL to this location (using an immutable revision specifier).
     canonicalURL: String!
+    # The text of the first line of the range and of the lines around it, if requested
+    # with the contextLines argument of LocationConnection.nodes. Null if it wasn't
+    # requested or the file couldn't be read.
+    content: LocationContent
+}
+
+# The text of the first line of a location's range and of the lines around it.
+type LocationContent {
+    # The text of the first line of the range, without its line terminator.
+    line: String!
+    # The lines preceding the line, from top to bottom.
+    before: [String!]!
+    # The lines following the line, from top to bottom.
+    after: [String!]!
 }
 
 # A range inside a file. The start position is inclusive, and the end position is exclusive.
@@ -2799,6 +3697,23 @@ type LSIFQueryResolver {
     # LSIF data available for that commit.
     commit: GitCommit!
 
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # Whether all queried uploads are of the commit of the git blob from which this query
+    # resolver came. If false, some results come from an upload of a nearby commit (at most
+    # lsifMaxCommitDistance commits away, see site configuration) and are adjusted to the
+    # requested commit, so they may be stale or missing for code that changed since.
+    isExactCommit: Boolean!
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The uploads that also contain data for the path, but are not queried because at most
+    # lsifMaxUploadsPerQuery uploads (see site configuration) are queried. The closest uploads
+    # are queried first. This is intended to debug missing code intelligence results.
+    skippedUploads: [LSIFUpload!]!
+
     # (experimental) The LSIF API may change substantially in the near future as we
     # continue to adjust it for our use cases. Changes will not be documented in the
     # CHANGELOG during this time.
@@ -2835,6 +3750,20 @@ type LSIFQueryResolver {
         first: Int
     ): LocationConnection
 
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The approximate number of references of the symbol under the given document position.
+    # The count is computed without fetching the references, so it is cheap enough to be
+    # displayed for many symbols at once. It doesn't include references in other repositories.
+    referenceCount(
+        # The line on which the symbol occurs (zero-based, inclusive).
+        line: Int!
+
+        # The character (not byte) of the start line on which the symbol occurs (zero-based, inclusive).
+        character: Int!
+    ): Int!
+
     # (experimental) The LSIF API may change substantially in the near future as we
     # continue to adjust it for our use cases. Changes will not be documented in the
     # CHANGELOG during this time.
@@ -2845,7 +3774,33 @@ type LSIFQueryResolver {
 
         # The character (not byte) of the start line on which the symbol occurs (zero-based, inclusive).
         character: Int!
+
+        # The preferred format of the prose sections in Hover.contents.
+        format: HoverContentFormat = MARKDOWN
     ): Hover
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The hover results of the symbols under the given document positions, in the order of the
+    # positions. The hover of a position without a symbol is null. All positions are resolved in
+    # a single query, e.g. to prefetch the hovers of the symbols visible in a blob. At most 100
+    # positions may be given.
+    hovers(
+        # The positions of the symbols.
+        positions: [LSIFPosition!]!
+
+        # The preferred format of the prose sections in Hover.contents.
+        format: HoverContentFormat = MARKDOWN
+    ): [Hover]!
+}
+
+# A position in a document.
+input LSIFPosition {
+    # The line of the position (zero-based).
+    line: Int!
+
+    # The character (not byte) of the position on its line (zero-based).
+    character: Int!
 }
 
 # A highlighted file.
@@ -3021,6 +3976,26 @@ type User implements Node & SettingsSubject & Namespace {
     #
     # Only the user and site admins can access this field.
     surveyResponses: [SurveyResponse!]!
+    # The campaigns that the user is involved in: that they authored, that have a changeset they
+    # were requested to review or reviewed, or that they are subscribed to.
+    #
+    # Only the user and site admins can access this field.
+    campaigns(
+        # Returns the first n campaigns from the list.
+        first: Int
+        # Opaque pagination cursor.
+        after: String
+        # Only return campaigns whose name or description contains this query.
+        query: String
+        state: CampaignState
+        # Only return campaigns with at least one open changeset, i.e. with unmerged work.
+        hasOpenChangesets: Boolean
+        # Only return campaigns with at least one changeset in this state.
+        changesetState: ChangesetState
+        # Only return campaigns that the user is involved in this way. If null, the campaigns that
+        # the user is involved in in any way are returned.
+        involvedAs: CampaignInvolvement
+    ): CampaignConnection!
     # The URL to view this user's customer information (for Sourcegraph.com site admins).
     #
     # Only Sourcegraph.com site admins may query this field.
@@ -3640,7 +4615,160 @@ type Site implements SettingsSubject {
         weeks: Int
         # Months of history (based on current UTC time).
         months: Int
+        # The first day (UTC) of the daily periods. If from or to is set, the daily periods are
+        # those from "from" to "to", and days is the maximum number of them that is returned.
+        from: DateTime
+        # The last day (UTC) of the daily periods. Defaults to today. The periods are ordered
+        # newest first, so the next page of a range is requested with "to" set to the day before
+        # the oldest returned period.
+        to: DateTime
     ): CodeIntelUsageStatistics!
+    # The number of precise code intel queries and of users that made them, by the indexer of the
+    # queried upload and the language of the queried file, ordered by decreasing number of queries.
+    #
+    # Only site admins may access this field.
+    codeIntelQueryStatistics(
+        # Days of history (based on current UTC time).
+        days: Int
+    ): [CodeIntelQueryStatistics!]!
+    # The number and the latency percentiles of code intel queries by the code path that served
+    # them and the code intel feature flags enabled for their users, ordered by decreasing number
+    # of queries. It measures how changes of the code intel resolvers that are behind feature
+    # flags affect precision and latency.
+    #
+    # Only site admins may access this field.
+    codeIntelQueryPathStatistics(
+        # Days of history (based on current UTC time).
+        days: Int
+    ): [CodeIntelQueryPathStatistics!]!
+    # The number and the latency percentiles of searches by the combination of filters (repo:,
+    # file:, lang: and type:) their queries used, ordered by decreasing number of searches.
+    # Searches of several types are counted once for each type.
+    #
+    # Only site admins may access this field.
+    searchFilterLatencyStatistics(
+        # Days of history (based on current UTC time).
+        days: Int
+    ): [SearchFilterLatencyStatistics!]!
+    # The slowest searches of a type on a day, slowest first, as examples of the searches behind
+    # the daily latency percentiles of the type.
+    #
+    # Only site admins may access this field.
+    slowestSearches(
+        # The day (UTC) on which the searches ran.
+        day: DateTime!
+        # The type of the searches: literal, regexp, structural, file, repo, diff, commit or symbol.
+        type: String!
+        # Returns the first n searches. At most 100 searches are returned.
+        first: Int = 10
+    ): [SlowSearch!]!
+    # The last runs of the background jobs that compute or maintain usage statistics, e.g. the
+    # aggregation of search latencies, ordered by name. They tell whether the statistics are
+    # fresh. Jobs that haven't run yet are not returned.
+    #
+    # Only site admins may access this field.
+    usageStatisticsJobs: [UsageStatisticsJob!]!
+    # How the schema of the database drifted from the one that the migrations create, e.g. a
+    # missing index or an unexpected column. Checking doesn't change the database.
+    #
+    # Only site admins may access this field.
+    databaseSchemaDrift: DatabaseSchemaDrift!
+    # Unique users of each kind of Sourcegraph client (web app, browser extension, editor
+    # integrations, and the API), derived from the sources of logged events.
+    #
+    # Only site admins may access this field.
+    clientUsageStatistics(
+        # Days of history (based on current UTC time).
+        days: Int
+        # Weeks of history (based on current UTC time).
+        weeks: Int
+        # Months of history (based on current UTC time).
+        months: Int
+        # The first day (UTC) of the daily periods. If from or to is set, the daily periods are
+        # those from "from" to "to", and days is the maximum number of them that is returned.
+        from: DateTime
+        # The last day (UTC) of the daily periods. Defaults to today. The periods are ordered
+        # newest first, so the next page of a range is requested with "to" set to the day before
+        # the oldest returned period.
+        to: DateTime
+    ): ClientUsageStatistics!
+    # Time series of campaign metrics. The metrics are computed at most once per day.
+    #
+    # Only site admins may access this field.
+    campaignMetrics(
+        # Days of history (based on current UTC time).
+        days: Int
+        # Weeks of history (based on current UTC time).
+        weeks: Int
+        # Months of history (based on current UTC time).
+        months: Int
+    ): CampaignMetrics!
+    # The queues of background jobs of campaigns, e.g. creating changesets on their code hosts,
+    # ordered by name. Only queues that contain jobs are returned.
+    #
+    # Only site admins may access this field.
+    campaignWorkerQueues: [CampaignWorkerQueue!]!
+    # The payloads posted to the Slack and generic webhooks that campaign notifications are sent
+    # to, with every attempt to post them, newest first. Deliveries are kept for 30 days.
+    #
+    # Only site admins may access this field.
+    campaignWebhookDeliveries(
+        # Returns the first n deliveries from the list.
+        first: Int
+        # Opaque pagination cursor.
+        after: String
+        # Only return deliveries that no attempt succeeded for.
+        failed: Boolean
+    ): CampaignWebhookDeliveryConnection!
+    # The API rate limits of the tokens of the external services that repositories are synced from
+    # and that campaigns create changesets on, ordered by external service. Campaigns leave part of
+    # each rate limit to repository syncing.
+    #
+    # Only site admins may access this field.
+    codeHostRateLimits: [CodeHostRateLimit!]!
+    # The feature flags that turn behaviors of this site on or off, ordered by name.
+    #
+    # Only site admins may access this field.
+    featureFlags: [FeatureFlag!]!
+    # Weekly cohort retention of this site's users. Only site admins may access this field.
+    retentionStatistics(
+        # Weeks of history (based on current UTC time).
+        weeks: Int
+    ): RetentionStatistics!
+    # The exact JSON payload of aggregated usage statistics that the next telemetry export would send
+    # (see the "telemetry.export" site configuration). Only site admins may access this field.
+    telemetryExportPreview: String!
+}
+
+# A feature flag, which turns a behavior of the site on or off without a redeploy.
+type FeatureFlag {
+    # The unique name of the feature flag.
+    name: String!
+    # The behavior that the feature flag turns on or off.
+    description: String!
+    # Whether the feature flag is enabled when no value is stored.
+    default: Boolean!
+    # Whether a value of the feature flag is stored. Otherwise, the flag has its default value.
+    stored: Boolean!
+    # Whether the feature flag is enabled for users without an override, unless rolloutPercentage
+    # is set.
+    enabled: Boolean!
+    # The percentage of users that the feature flag is enabled for, if it is rolled out gradually.
+    rolloutPercentage: Int
+    # The overrides of the feature flag for users and organizations.
+    overrides: [FeatureFlagOverride!]!
+    # When the stored value of the feature flag was last updated.
+    updatedAt: DateTime
+}
+
+# An override of a feature flag for a user or an organization.
+type FeatureFlagOverride {
+    # The user or organization that the override applies to.
+    namespace: Namespace!
+    # Whether the feature flag is enabled for the namespace.
+    enabled: Boolean!
+    # When the override was last updated.
+    updatedAt: DateTime!
 }
 
 # The configuration for a site.
@@ -3889,6 +5017,148 @@ type CodeIntelUsageStatistics {
     monthly: [CodeIntelUsagePeriod!]!
 }
 
+# Usage of precise code intel queries for a language, with the uploads of an indexer.
+type CodeIntelQueryStatistics {
+    # The name of the indexer that produced the queried uploads, e.g. "lsif-go". It is empty for
+    # uploads that did not record their indexer.
+    indexer: String!
+    # The language of the queried files, e.g. "Go". It is empty if it could not be detected.
+    language: String!
+    # The number of unique users that made queries.
+    usersCount: Int!
+    # The total number of queries.
+    queriesCount: Int!
+}
+
+# The code intel queries served by a code path to the users of a variant of the code intel
+# feature flags.
+type CodeIntelQueryPathStatistics {
+    # The code path that served the queries: "precise" for queries answered from uploads,
+    # "precise-empty" for queries answered from uploads without a result, and "fallback" for
+    # queries of files without uploads. Clients fall back to search-based code intel for the
+    # latter two.
+    codePath: String!
+    # The code intel feature flags enabled for the users that made the queries. It is empty for
+    # users without any of the flags.
+    variant: [String!]!
+    # The number of queries.
+    queriesCount: Int!
+    # The 50th percentile of the latencies, in milliseconds.
+    p50: Float!
+    # The 90th percentile of the latencies, in milliseconds.
+    p90: Float!
+    # The 99th percentile of the latencies, in milliseconds.
+    p99: Float!
+}
+
+# The latencies of the searches whose queries used a combination of filters.
+type SearchFilterLatencyStatistics {
+    # The filters of the queries, e.g. ["lang", "repo"]. It is empty for queries without filters.
+    filters: [String!]!
+    # The number of searches.
+    searchesCount: Int!
+    # The 50th percentile of the latencies, in milliseconds.
+    p50: Float!
+    # The 90th percentile of the latencies, in milliseconds.
+    p90: Float!
+    # The 99th percentile of the latencies, in milliseconds.
+    p99: Float!
+}
+
+# A slow search. Only the shape of its query is known, since the query text isn't recorded.
+type SlowSearch {
+    # The date and time when the search ran.
+    timestamp: DateTime!
+    # The latency of the search, in milliseconds.
+    durationMs: Int!
+    # The number of repositories that were searched.
+    reposCount: Int!
+    # The filters of the query, e.g. ["lang", "repo"]. It is empty for queries without filters.
+    filters: [String!]!
+    # An anonymous identifier of the user that ran the search, e.g. "user 1". It is the same for
+    # the searches of the same user in the list, but isn't related to the user's ID.
+    user: String!
+}
+
+# The last run of a background job that computes or maintains usage statistics.
+type UsageStatisticsJob {
+    # The name of the job, e.g. "aggregate_search_latencies".
+    name: String!
+    # The date and time when the last run started.
+    lastRunAt: DateTime!
+    # The duration of the last run, in milliseconds.
+    durationMs: Int!
+    # The number of rows that the last run processed, e.g. the number of aggregated latencies
+    # that were stored or the number of expired event logs that were deleted.
+    rowsProcessed: Int!
+    # The error of the last run, or null if it succeeded.
+    error: String
+    # The date and time when the last successful run finished, or null if no run succeeded.
+    lastSucceededAt: DateTime
+}
+
+# The result of comparing the schema of the database with the one that the migrations create.
+type DatabaseSchemaDrift {
+    # The version of the last migration applied to the database, or "0" if none was.
+    version: String!
+    # Whether the last migration failed before it completed.
+    dirty: Boolean!
+    # The names of the migrations that weren't applied yet, in the order in which they would be.
+    pendingMigrations: [String!]!
+    # The tables, columns, indexes and constraints that differ from the expected schema, ordered
+    # by table.
+    differences: [DatabaseSchemaDifference!]!
+}
+
+# The kind of a database object.
+enum DatabaseSchemaObjectKind {
+    TABLE
+    COLUMN
+    INDEX
+    CONSTRAINT
+}
+
+# A database object that is missing, unexpected or defined differently than expected. Definitions
+# are formatted like the output of psql's \d command.
+type DatabaseSchemaDifference {
+    # The kind of the object.
+    kind: DatabaseSchemaObjectKind!
+    # The name of the table, or of the table that the column, index or constraint belongs to.
+    table: String!
+    # The name of the column, index or constraint, or null for tables.
+    name: String
+    # The expected definition, or null if the object is unexpected.
+    expected: String
+    # The definition in the database, or null if the object is missing.
+    actual: String
+    # A description of the difference, e.g. 'missing index "repo_name_idx" of table "repo"'.
+    description: String!
+}
+
+# A site's aggregate usage statistics by client.
+type ClientUsageStatistics {
+    # Recent daily active users.
+    daus: [ClientUsagePeriod!]!
+    # Recent weekly active users.
+    waus: [ClientUsagePeriod!]!
+    # Recent monthly active users.
+    maus: [ClientUsagePeriod!]!
+}
+
+# The number of unique users of each kind of client in a given timespan.
+type ClientUsagePeriod {
+    # The time when this started.
+    startTime: DateTime!
+    # The number of unique users of the web app.
+    webUserCount: Int!
+    # The number of unique users of the browser extension and other code host integrations.
+    browserExtensionUserCount: Int!
+    # The number of unique users of editor integrations.
+    editorUserCount: Int!
+    # The number of unique users of the API.
+    apiUserCount: Int!
+}
+
 # Usage statistics of code intel features in a given timespan.
 #
 # This information is visible to all viewers.
@@ -3933,6 +5203,174 @@ type CodeIntelEventLatencies {
     p99: Float!
 }
 
+# A site's campaign metrics over time.
+type CampaignMetrics {
+    # Recent daily campaign metrics, oldest first.
+    daily: [CampaignMetricsPeriod!]!
+    # Recent weekly campaign metrics, oldest first.
+    weekly: [CampaignMetricsPeriod!]!
+    # Recent monthly campaign metrics, oldest first.
+    monthly: [CampaignMetricsPeriod!]!
+}
+
+# Campaign metrics of a given timespan.
+type CampaignMetricsPeriod {
+    # The time when this started.
+    startTime: DateTime!
+    # The number of campaigns created in this timespan.
+    campaignsCreated: Int!
+    # The number of changesets merged in this timespan.
+    changesetsMerged: Int!
+    # The median time in seconds between the creation of a changeset on its codehost and
+    # its merge, across all changesets merged in this timespan. Null if no changeset was
+    # merged.
+    medianTimeToMerge: Float
+}
+
+# A queue of background jobs of campaigns.
+# The API rate limit of the token of an external service, as of the last API response received with it.
+type CodeHostRateLimit {
+    # The external service whose token the rate limit applies to.
+    externalService: ExternalService!
+    # Whether the code host reported its rate limit. If false, limit, remaining and resetAt are null.
+    known: Boolean!
+    # The number of API requests allowed per rate limit window.
+    limit: Int
+    # The number of API requests left in the current rate limit window.
+    remaining: Int
+    # When the current rate limit window ends.
+    resetAt: DateTime
+    # When the code host asked for requests to be retried, if it did.
+    retryAt: DateTime
+}
+
+type CampaignWorkerQueue {
+    # The name of the queue, e.g. "changeset_jobs".
+    name: String!
+    # The number of jobs waiting to be run, including failed jobs that will be retried.
+    queued: Int!
+    # The number of jobs being run.
+    processing: Int!
+    # The number of jobs that were completed successfully in the last 24 hours.
+    completed: Int!
+    # The number of queued jobs that already failed at least once.
+    retrying: Int!
+    # The number of jobs that failed on every attempt and won't be retried.
+    dead: Int!
+    # The time when the longest waiting queued job was enqueued. Null if no job is queued.
+    oldestQueuedAt: DateTime
+    # The jobs of the queue that failed at least once, oldest first.
+    failures(
+        # Returns the first n jobs from the list.
+        first: Int
+        # Only return jobs in this state.
+        state: CampaignWorkerJobState
+    ): [CampaignWorkerJob!]!
+}
+
+# The state of a background job of campaigns.
+enum CampaignWorkerJobState {
+    # The job is waiting to be run, possibly after failing before.
+    QUEUED
+    # The job is being run.
+    PROCESSING
+    # The job was completed successfully.
+    COMPLETED
+    # The job failed on every attempt and won't be retried.
+    DEAD
+}
+
+# A background job of campaigns.
+type CampaignWorkerJob {
+    # The state of the job.
+    state: CampaignWorkerJobState!
+    # The description of the job, whose format depends on the queue.
+    payload: JSONValue!
+    # The number of times the job was run.
+    attempts: Int!
+    # The number of times the job is run before it's considered dead.
+    maxAttempts: Int!
+    # The error of the last failed attempt.
+    error: String
+    # The time after which the job is run next, if it's queued.
+    runAfter: DateTime!
+    # The time when the job was enqueued.
+    createdAt: DateTime!
+    # The time when the job was last updated.
+    updatedAt: DateTime!
+    # The time when the job was completed or considered dead.
+    finishedAt: DateTime
+}
+
+# A list of campaign webhook deliveries.
+type CampaignWebhookDeliveryConnection {
+    # A list of campaign webhook deliveries.
+    nodes: [CampaignWebhookDelivery!]!
+    # The total number of campaign webhook deliveries in the connection.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A payload posted to the Slack or generic webhook of a namespace, e.g. a digest of campaign
+# notifications.
+type CampaignWebhookDelivery {
+    # The unique ID of the delivery.
+    id: ID!
+    # The notification channel of the webhook.
+    channel: CampaignNotificationChannel!
+    # The URL of the webhook.
+    url: String!
+    # The user whose notifications are delivered. Null if the user was deleted.
+    recipient: User
+    # The JSON payload posted to the webhook.
+    payload: JSONValue!
+    # Whether the webhook accepted the payload in any attempt.
+    succeeded: Boolean!
+    # The time of the first successful attempt.
+    succeededAt: DateTime
+    # The time of the last attempt.
+    lastAttemptedAt: DateTime
+    # The time when the delivery was created.
+    createdAt: DateTime!
+    # The attempts to post the payload, oldest first.
+    attempts: [CampaignWebhookDeliveryAttempt!]!
+}
+
+# An attempt to post the payload of a campaign webhook delivery to its webhook.
+type CampaignWebhookDeliveryAttempt {
+    # Whether the webhook responded with a 2xx status.
+    succeeded: Boolean!
+    # The HTTP status of the response. Null if the webhook didn't respond.
+    statusCode: Int
+    # The first kilobyte of the body of the response.
+    responseBody: String
+    # The reason why the attempt failed.
+    error: String
+    # The time the webhook took to respond, in milliseconds.
+    durationMilliseconds: Int!
+    # The time of the attempt.
+    attemptedAt: DateTime!
+}
+
+# A site's weekly cohort retention statistics.
+type RetentionStatistics {
+    # Recent weekly cohorts, newest first.
+    weekly: [RetentionCohort!]!
+}
+
+# The set of users who were first active in a given week, and how many of them remained active
+# in each of the following weeks.
+type RetentionCohort {
+    # The start of the week in which the users of this cohort were first active.
+    startTime: DateTime!
+    # The number of users in this cohort.
+    usersCount: Int!
+    # The number of users in this cohort that were active in each week since the cohort started.
+    # The element at index k is the number of users active k weeks after the cohort's first week.
+    retainedUsersCounts: [Int!]!
+}
+
 # A deployment configuration.
 type DeploymentConfiguration {
     # The email.
@@ -4218,10 +5656,55 @@ type RegistryExtensionConnection {
 # A list of locations within a file.
 type LocationConnection {
     # A list of locations within a file.
-    nodes: [Location!]!
+    nodes(
+        # If set, the content of each location is fetched with this many lines of context
+        # before and after its line (at most 10), so that clients don't need to fetch the
+        # file of each location to display it.
+        contextLines: Int
+    ): [Location!]!
+
+    # The locations of nodes grouped by repository, then by file, so that clients can render
+    # them in sections without regrouping them. Groups are ordered by their first location.
+    groups(
+        # If set, the content of each location is fetched with this many lines of context
+        # before and after its line (at most 10), as for nodes.
+        contextLines: Int
+    ): [LocationRepositoryGroup!]!
 
     # Pagination information.
     pageInfo: PageInfo!
+
+    # Whether the list of locations is incomplete because some LSIF uploads could not
+    # be queried within their share of the time budget of the request.
+    partial: Boolean!
+
+    # Whether locations were dropped from this page because it contained more locations than
+    # allowed by the lsifMaxReferences site configuration.
+    truncated: Boolean!
+
+    # The number of locations found for this page, including the locations dropped if the list
+    # is truncated. Further pages may contain more locations.
+    countEstimate: Int!
+}
+
+# The locations of a LocationConnection in a repository.
+type LocationRepositoryGroup {
+    # The repository of the locations.
+    repository: Repository!
+    # The number of locations in the repository.
+    count: Int!
+    # The locations in the repository, grouped by file.
+    files: [LocationFileGroup!]!
+}
+
+# The locations of a LocationConnection in a file.
+type LocationFileGroup {
+    # The file of the locations.
+    file: GitBlob!
+    # The number of locations in the file.
+    count: Int!
+    # The locations in the file, in the order of the connection.
+    locations: [Location!]!
 }
 
 # Hover range and markdown content.
@@ -4229,10 +5712,36 @@ type Hover {
     # A markdown string containing the contents of the hover.
     markdown: Markdown!
 
+    # The contents of the hover split into prose and code sections, in order. Prose sections
+    # are returned in the format requested by the hover query. Code sections are returned
+    # verbatim with their language so that clients can apply syntax highlighting.
+    contents: [HoverContent!]!
+
     # The range to highlight.
     range: Range!
 }
 
+# A section of the contents of a hover.
+type HoverContent {
+    # The format of the value.
+    format: HoverContentFormat!
+
+    # The content of this section.
+    value: String!
+
+    # The language of the code in this section, or null if this section is prose.
+    language: String
+}
+
+# The format of hover content.
+enum HoverContentFormat {
+    # The content is a markdown string.
+    MARKDOWN
+
+    # The content is plain text.
+    PLAINTEXT
+}
+
 # The state an LSIF upload can be in.
 enum LSIFUploadState {
     # The LSIF worker is processing this upload.
@@ -4284,6 +5793,28 @@ type LSIFUpload implements Node {
     isLatestForRepo: Boolean!
 }
 
+# The code intelligence support of a language in a repository.
+type CodeIntelLanguageSupport {
+    # The name of the language (e.g., "Go" or "TypeScript").
+    language: String!
+
+    # The name of the LSIF indexer that can produce precise code intelligence for the language, or null
+    # if there is no known indexer for it.
+    indexer: String
+
+    # The completed LSIF upload that provides precise code intelligence for the language at the tip of
+    # the default branch, or null if there is none.
+    upload: LSIFUpload
+
+    # The number of commits the upload's commit is behind the tip of the default branch, or null if there
+    # is no upload.
+    commitsBehind: Int
+
+    # Whether search-based code intelligence is used for the language because there is no LSIF upload
+    # for it.
+    searchBased: Boolean!
+}
+
 # Metadata about a LSIF upload failure.
 type LSIFUploadFailureReason {
     # A summary of the failure.
@@ -4305,6 +5836,81 @@ type LSIFUploadConnection {
     pageInfo: PageInfo!
 }
 
+# The LSIF coverage of a repository at a commit.
+type LSIFCoverage {
+    # The commit whose coverage is reported.
+    commit: GitObjectID!
+
+    # The upload closest to the commit of each root and indexer, ordered by their distance to the
+    # commit and then by decreasing root length.
+    uploads: [LSIFUploadCoverage!]!
+
+    # The coverage of the files and directories in the given directory of the repository at the
+    # commit, directories first and then ordered by path.
+    entries(
+        # The path of the directory. Defaults to the root directory of the repository.
+        path: String = ""
+    ): [LSIFCoverageEntry!]!
+}
+
+# An LSIF upload with the number of files it contains data for.
+type LSIFUploadCoverage {
+    # The upload.
+    upload: LSIFUpload!
+
+    # The root of the upload.
+    root: String!
+
+    # The name of the indexer that produced the upload, or null if it is unknown.
+    indexer: String
+
+    # The number of files of the repository at the commit that the upload contains data for.
+    fileCount: Int!
+}
+
+# The LSIF coverage of a file or directory.
+type LSIFCoverageEntry {
+    # The path of the file or directory.
+    path: String!
+
+    # Whether the entry is a directory.
+    isDirectory: Boolean!
+
+    # The number of files in the entry, which is 1 for a file.
+    fileCount: Int!
+
+    # The number of files in the entry that at least one upload contains data for.
+    coveredFileCount: Int!
+
+    # The uploads that contain data for files in the entry, in the order of LSIFCoverage.uploads.
+    uploads: [LSIFUpload!]!
+}
+
+# A token that authorizes LSIF uploads to a repository.
+type LSIFUploadToken implements Node {
+    # The unique ID for the upload token.
+    id: ID!
+    # The repository that the token authorizes uploads to.
+    repository: Repository!
+    # A user-supplied descriptive note for the upload token.
+    note: String!
+    # The user who created the upload token.
+    creator: User!
+    # The date when the upload token was created.
+    createdAt: DateTime!
+    # The date when the upload token was last used to authorize an upload.
+    lastUsedAt: DateTime
+}
+
+# The result for Mutation.createLSIFUploadToken.
+type CreateLSIFUploadTokenResult {
+    # The ID of the newly created upload token.
+    id: ID!
+    # The secret token value that is given to the upload endpoint. The caller is responsible for
+    # storing this value.
+    token: String!
+}
+
 # Mutations that are only used on Sourcegraph.com.
 #
 # FOR INTERNAL USE ONLY.
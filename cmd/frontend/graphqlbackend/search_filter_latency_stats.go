@@ -0,0 +1,89 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func (r *siteResolver) SearchFilterLatencyStatistics(ctx context.Context, args *struct {
+	Days *int32
+}) ([]*searchFilterLatencyStatisticsResolver, error) {
+	// 🚨 SECURITY: Only site admins may view search latency statistics.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := &usagestats.SearchFilterLatencyStatisticsOptions{}
+	if args.Days != nil {
+		d := int(*args.Days)
+		opt.Days = &d
+	}
+	stats, err := usagestats.GetSearchFilterLatencyStatistics(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*searchFilterLatencyStatisticsResolver, 0, len(stats))
+	for _, s := range stats {
+		resolvers = append(resolvers, &searchFilterLatencyStatisticsResolver{stats: s})
+	}
+	return resolvers, nil
+}
+
+type searchFilterLatencyStatisticsResolver struct {
+	stats *types.SearchFilterLatency
+}
+
+func (r *searchFilterLatencyStatisticsResolver) Filters() []string { return r.stats.Filters }
+
+func (r *searchFilterLatencyStatisticsResolver) SearchesCount() int32 { return r.stats.SearchesCount }
+
+func (r *searchFilterLatencyStatisticsResolver) P50() float64 { return r.stats.Latency.P50 }
+
+func (r *searchFilterLatencyStatisticsResolver) P90() float64 { return r.stats.Latency.P90 }
+
+func (r *searchFilterLatencyStatisticsResolver) P99() float64 { return r.stats.Latency.P99 }
+
+func (r *siteResolver) SlowestSearches(ctx context.Context, args *struct {
+	Day   DateTime
+	Type  string
+	First int32
+}) ([]*slowSearchResolver, error) {
+	// 🚨 SECURITY: Only site admins may view search latency statistics.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	first := int(args.First)
+	searches, err := usagestats.GetSlowestSearches(ctx, &usagestats.SlowestSearchesOptions{
+		Day:        args.Day.Time,
+		SearchType: args.Type,
+		First:      &first,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*slowSearchResolver, 0, len(searches))
+	for _, s := range searches {
+		resolvers = append(resolvers, &slowSearchResolver{search: s})
+	}
+	return resolvers, nil
+}
+
+type slowSearchResolver struct {
+	search *types.SlowSearch
+}
+
+func (r *slowSearchResolver) Timestamp() DateTime { return DateTime{Time: r.search.Timestamp} }
+
+func (r *slowSearchResolver) DurationMs() int32 { return r.search.DurationMs }
+
+func (r *slowSearchResolver) ReposCount() int32 { return r.search.ReposCount }
+
+func (r *slowSearchResolver) Filters() []string { return r.search.Filters }
+
+func (r *slowSearchResolver) User() string { return r.search.User }
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 
 	"github.com/hashicorp/go-multierror"
@@ -30,6 +31,7 @@ import (
 	"gopkg.in/inconshreveable/log15.v2"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -478,6 +480,7 @@ func (r *searchResolver) Results(ctx context.Context) (*SearchResultsResolver, e
 		return r.paginatedResults(ctx)
 	}
 
+	start := time.Now()
 	rr, err := r.resultsWithTimeoutSuggestion(ctx)
 
 	// Record what type of response we sent back via Prometheus.
@@ -499,9 +502,62 @@ func (r *searchResolver) Results(ctx context.Context) (*SearchResultsResolver, e
 	}
 	searchResponseCounter.WithLabelValues(status, alertType).Inc()
 
+	if status == "success" || status == "partial_timeout" {
+		r.logSearchLatency(ctx, time.Since(start), len(rr.searchResultsCommon.repos))
+	}
+
 	return rr, err
 }
 
+// logSearchLatency records the latency of a search by an authenticated user for the search
+// latency statistics, once for each type of search the query performed.
+func (r *searchResolver) logSearchLatency(ctx context.Context, duration time.Duration, reposCount int) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return
+	}
+
+	var searchTypes []string
+	if r.patternType == SearchTypeStructural {
+		searchTypes = []string{"structural"}
+	} else {
+		resultTypes, _ := r.query.StringValues(query.FieldType)
+		if len(resultTypes) == 0 {
+			resultTypes = []string{"file"}
+		}
+		for _, resultType := range resultTypes {
+			switch resultType {
+			case "file":
+				if r.patternType == SearchTypeLiteral {
+					searchTypes = append(searchTypes, "literal")
+				} else {
+					searchTypes = append(searchTypes, "regexp")
+				}
+			case "path":
+				searchTypes = append(searchTypes, "file")
+			case "repo", "diff", "commit", "symbol":
+				searchTypes = append(searchTypes, resultType)
+			}
+		}
+	}
+
+	var filters []string
+	for _, field := range usagestats.SearchLatencyFilters {
+		if len(r.query.Values(field)) > 0 {
+			filters = append(filters, field)
+		}
+	}
+
+	durationMs := duration.Nanoseconds() / int64(time.Millisecond)
+	goroutine.Go(func() {
+		for _, searchType := range searchTypes {
+			if err := usagestats.LogSearchLatency(a.UID, searchType, durationMs, reposCount, filters); err != nil {
+				log15.Warn("Could not log search latency", "type", searchType, "error", err)
+			}
+		}
+	})
+}
+
 // resultsWithTimeoutSuggestion calls doResults, and in case of deadline
 // exceeded returns a search alert with a did-you-mean link for the same
 // query with a longer timeout.
@@ -0,0 +1,21 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/telemetryexport"
+)
+
+func (r *siteResolver) TelemetryExportPreview(ctx context.Context) (string, error) {
+	// 🚨 SECURITY: Only site admins may view the telemetry export payload.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return "", err
+	}
+
+	payload, err := telemetryexport.Preview(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
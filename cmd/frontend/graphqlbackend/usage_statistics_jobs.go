@@ -0,0 +1,52 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func (r *siteResolver) UsageStatisticsJobs(ctx context.Context) ([]*usageStatisticsJobResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the usage statistics jobs.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	jobs, err := db.UsageStatisticsJobs.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*usageStatisticsJobResolver, 0, len(jobs))
+	for _, j := range jobs {
+		resolvers = append(resolvers, &usageStatisticsJobResolver{job: j})
+	}
+	return resolvers, nil
+}
+
+type usageStatisticsJobResolver struct {
+	job *db.UsageStatisticsJob
+}
+
+func (r *usageStatisticsJobResolver) Name() string { return r.job.Name }
+
+func (r *usageStatisticsJobResolver) LastRunAt() DateTime { return DateTime{Time: r.job.StartedAt} }
+
+func (r *usageStatisticsJobResolver) DurationMs() int32 {
+	return int32(r.job.FinishedAt.Sub(r.job.StartedAt).Nanoseconds() / int64(time.Millisecond))
+}
+
+func (r *usageStatisticsJobResolver) RowsProcessed() int32 { return int32(r.job.RowsProcessed) }
+
+func (r *usageStatisticsJobResolver) Error() *string {
+	if r.job.Error == "" {
+		return nil
+	}
+	return &r.job.Error
+}
+
+func (r *usageStatisticsJobResolver) LastSucceededAt() *DateTime {
+	return DateTimeOrNil(r.job.LastSucceededAt)
+}
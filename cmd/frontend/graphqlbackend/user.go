@@ -265,6 +265,10 @@ func (r *UserResolver) SurveyResponses(ctx context.Context) ([]*surveyResponseRe
 	return surveyResponseResolvers, nil
 }
 
+func (r *UserResolver) Campaigns(ctx context.Context, args *UserCampaignsArgs) (CampaignsConnectionResolver, error) {
+	return EnterpriseResolvers.a8nResolver.UserCampaigns(ctx, r, args)
+}
+
 func (r *UserResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err == backend.ErrNotAuthenticated || err == backend.ErrMustBeSiteAdmin {
 		return false, nil
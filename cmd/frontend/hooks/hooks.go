@@ -1,8 +1,16 @@
 // Package hooks allow hooking into the frontend.
 package hooks
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // PostAuthMiddleware is an HTTP handler middleware that, if set, runs just before auth-related
 // middleware. The client is authenticated when PostAuthMiddleware is called.
 var PostAuthMiddleware func(http.Handler) http.Handler
+
+// HealthChecks are checks of the services the frontend depends on, keyed by the name of the
+// service. Their results are shown by the /healthz endpoint of the frontend's debug server. A
+// check returns a JSON-serializable status of the service, and an error if it is unhealthy.
+var HealthChecks = map[string]func(ctx context.Context) (status interface{}, err error){}
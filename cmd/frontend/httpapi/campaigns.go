@@ -0,0 +1,19 @@
+package httpapi
+
+import "net/http"
+
+// CampaignsAPI serves the REST API for campaigns, which is an alternative to the
+// GraphQL API for clients such as CI tools.
+type CampaignsAPI struct {
+	// CampaignsHandler serves GET (list) and POST (create) requests to
+	// /.api/campaigns.
+	CampaignsHandler http.Handler
+	// CampaignHandler serves GET and PATCH requests to /.api/campaigns/{id}.
+	CampaignHandler http.Handler
+	// FeedHandler serves GET requests to /.api/campaigns/feed with an Atom
+	// feed of the activity of the campaigns in a namespace.
+	FeedHandler http.Handler
+}
+
+// Set by enterprise frontend
+var NewCampaignsAPI func() *CampaignsAPI
@@ -7,6 +7,23 @@ import (
 type LSIFServerProxy struct {
 	UploadHandler    http.Handler
 	AllRoutesHandler http.Handler
+
+	// UploadSessionsHandler serves POST requests to /.api/lsif/upload/sessions,
+	// which start a resumable upload of an LSIF dump in chunks.
+	UploadSessionsHandler http.Handler
+	// UploadSessionHandler serves HEAD, PATCH and DELETE requests to
+	// /.api/lsif/upload/sessions/{id}, which query the offset of, append a
+	// chunk to, and abort a resumable upload.
+	UploadSessionHandler http.Handler
+
+	// LSPHandler serves precise code intelligence over the Language Server
+	// Protocol on WebSocket connections.
+	LSPHandler http.Handler
+
+	// BadgeHandler serves GET requests to
+	// /.api/repos/{repo}/-/code-intel-badge.{svg,json} with a badge describing
+	// the precise code intelligence of a repository.
+	BadgeHandler http.Handler
 }
 
 // Set by enterprise frontend
@@ -0,0 +1,17 @@
+package httpapi
+
+import "net/http"
+
+// SiteExportAPI serves the endpoints that move campaigns, changesets and LSIF
+// upload metadata from one instance to another.
+type SiteExportAPI struct {
+	// ExportHandler serves GET requests to /.api/site/export with an archive
+	// of the data.
+	ExportHandler http.Handler
+	// ImportHandler serves POST requests to /.api/site/import, whose body is
+	// an archive served by ExportHandler.
+	ImportHandler http.Handler
+}
+
+// Set by enterprise frontend
+var NewSiteExportAPI func() *SiteExportAPI
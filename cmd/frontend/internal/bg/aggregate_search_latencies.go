@@ -0,0 +1,31 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/leader"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// AggregateSearchLatencies aggregates the search latencies of each day shortly after it
+// ended, so that the site admin search latency statistics are read from the aggregates.
+func AggregateSearchLatencies(ctx context.Context) {
+	lock := leader.New(dbconn.Global, "aggregate_search_latencies")
+	for {
+		if held, err := lock.Held(ctx); err != nil {
+			log15.Error("electing leader to aggregate search latencies", "error", err)
+		} else if held {
+			err := runUsageStatisticsJob(ctx, "aggregate_search_latencies", func(ctx context.Context) (int64, error) {
+				n, err := usagestats.AggregateSearchLatencies(ctx)
+				return int64(n), err
+			})
+			if err != nil {
+				log15.Error("aggregating search latencies", "error", err)
+			}
+		}
+		time.Sleep(time.Hour)
+	}
+}
@@ -4,19 +4,51 @@ import (
 	"context"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/leader"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
 func DeleteOldEventLogsInPostgres(ctx context.Context) {
+	// All frontend replicas run this, but only one of them needs to do the work.
+	lock := leader.New(dbconn.Global, "delete_old_event_logs")
 	for {
-		_, err := dbconn.Global.ExecContext(
-			ctx,
-			`DELETE FROM event_logs WHERE "timestamp" < now() - interval '93' day`,
-		)
-		if err != nil {
-			log15.Error("deleting expired rows from event_logs table", "error", err)
+		if held, err := lock.Held(ctx); err != nil || !held {
+			if err != nil {
+				log15.Error("electing leader to delete expired event logs", "error", err)
+			}
+			time.Sleep(time.Hour)
+			continue
 		}
+
+		_ = runUsageStatisticsJob(ctx, "delete_old_event_logs", deleteOldEventLogs)
 		time.Sleep(time.Hour)
 	}
 }
+
+// deleteOldEventLogs deletes the expired event logs and returns the number of rows that were
+// deleted, not counting those of dropped partitions.
+func deleteOldEventLogs(ctx context.Context) (int64, error) {
+	// Dropping the partitions of expired months is much cheaper than deleting their
+	// rows, which is only needed for the remainder.
+	now := time.Now()
+	partitionsErr := db.EventLogs.MaintainPartitions(ctx, now, now.AddDate(0, 0, -93))
+	if partitionsErr != nil {
+		log15.Error("maintaining partitions of event_logs table", "error", partitionsErr)
+	}
+
+	res, err := dbconn.Global.ExecContext(
+		ctx,
+		`DELETE FROM event_logs WHERE "timestamp" < now() - interval '93' day`,
+	)
+	if err != nil {
+		log15.Error("deleting expired rows from event_logs table", "error", err)
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return n, partitionsErr
+}
@@ -0,0 +1,28 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// runUsageStatisticsJob runs the job with the given name and records its run, so that site
+// admins can tell whether the usage statistics that depend on it are fresh. run returns the
+// number of rows that the job processed.
+func runUsageStatisticsJob(ctx context.Context, name string, run func(ctx context.Context) (int64, error)) error {
+	j := &db.UsageStatisticsJob{Name: name, StartedAt: time.Now().UTC()}
+
+	rows, err := run(ctx)
+	j.FinishedAt = time.Now().UTC()
+	j.RowsProcessed = rows
+	if err != nil {
+		j.Error = err.Error()
+	}
+
+	if err := db.UsageStatisticsJobs.Record(ctx, j); err != nil {
+		log15.Error("recording run of usage statistics job", "job", name, "error", err)
+	}
+	return err
+}
@@ -1,14 +1,19 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/NYTimes/gziphandler"
 	gcontext "github.com/gorilla/context"
 	"github.com/gorilla/mux"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/featureflag"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/hooks"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
@@ -28,14 +33,14 @@ import (
 
 // newExternalHTTPHandler creates and returns the HTTP handler that serves the app and API pages to
 // external clients.
-func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServerWebhook http.Handler, lsifServerProxy *httpapi.LSIFServerProxy) (http.Handler, error) {
+func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, gitlabWebhook, bitbucketServerWebhook http.Handler, lsifServerProxy *httpapi.LSIFServerProxy, campaignsAPI *httpapi.CampaignsAPI, siteExportAPI *httpapi.SiteExportAPI) (http.Handler, error) {
 	// Each auth middleware determines on a per-request basis whether it should be enabled (if not, it
 	// immediately delegates the request to the next middleware in the chain).
 	authMiddlewares := auth.AuthMiddleware()
 
 	// HTTP API handler.
 	r := router.New(mux.NewRouter().PathPrefix("/.api/").Subrouter())
-	apiHandler := internalhttpapi.NewHandler(r, schema, githubWebhook, bitbucketServerWebhook, lsifServerProxy)
+	apiHandler := internalhttpapi.NewHandler(r, schema, githubWebhook, gitlabWebhook, bitbucketServerWebhook, lsifServerProxy, campaignsAPI, siteExportAPI)
 	apiHandler = authMiddlewares.API(apiHandler) // 🚨 SECURITY: auth middleware
 	// 🚨 SECURITY: The HTTP API should not accept cookies as authentication (except those with the
 	// X-Requested-With header). Doing so would open it up to CSRF attacks.
@@ -52,9 +57,10 @@ func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServ
 	appHandler = handlerutil.CSRFMiddleware(appHandler, func() bool {
 		return globals.ExternalURL().Scheme == "https"
 	}) // after appAuthMiddleware because SAML IdP posts data to us w/o a CSRF token
-	appHandler = authMiddlewares.App(appHandler)                       // 🚨 SECURITY: auth middleware
-	appHandler = session.CookieMiddleware(appHandler)                  // app accepts cookies
-	appHandler = internalhttpapi.AccessTokenAuthMiddleware(appHandler) // app accepts access tokens
+	appHandler = authMiddlewares.App(appHandler)                              // 🚨 SECURITY: auth middleware
+	appHandler = session.CookieMiddleware(appHandler)                         // app accepts cookies
+	appHandler = internalhttpapi.ForbidRestrictedActorsMiddleware(appHandler) // 🚨 SECURITY: scoped access tokens are only accepted by the API
+	appHandler = internalhttpapi.AccessTokenAuthMiddleware(appHandler)        // app accepts access tokens
 	if hooks.PostAuthMiddleware != nil {
 		// 🚨 SECURITY: These all run after the auth handler so the client is authenticated.
 		appHandler = hooks.PostAuthMiddleware(appHandler)
@@ -73,6 +79,7 @@ func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServ
 	// 🚨 SECURITY: Auth middleware that must run before other auth middlewares.
 	h = internalauth.OverrideAuthMiddleware(h)
 	h = internalauth.ForbidAllRequestsMiddleware(h)
+	h = featureflag.Middleware(h)
 	h = tracepkg.Middleware(h)
 	h = middleware.SourcegraphComGoGetHandler(h)
 	h = middleware.BlackHole(h)
@@ -94,6 +101,61 @@ func healthCheckMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// healthzDebugHandler reports the health of the services the frontend depends on, as
+// registered in hooks.HealthChecks. Unlike /healthz of the frontend itself, which only reports
+// whether the frontend is up, it responds with 503 if any of the services is unhealthy.
+func healthzDebugHandler(w http.ResponseWriter, r *http.Request) {
+	type serviceHealth struct {
+		Healthy bool        `json:"healthy"`
+		Error   string      `json:"error,omitempty"`
+		Status  interface{} `json:"status,omitempty"`
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		services = make(map[string]serviceHealth, len(hooks.HealthChecks))
+		healthy  = true
+	)
+	for name, check := range hooks.HealthChecks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) (interface{}, error)) {
+			defer wg.Done()
+
+			status, err := check(ctx)
+			health := serviceHealth{Healthy: err == nil, Status: status}
+			if err != nil {
+				health.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			services[name] = health
+			healthy = healthy && err == nil
+		}(name, check)
+	}
+	wg.Wait()
+
+	b, err := json.MarshalIndent(map[string]interface{}{
+		"version":  version.Version(),
+		"healthy":  healthy,
+		"services": services,
+	}, "", "  ")
+	if err != nil {
+		http.Error(w, "failed to marshal health: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write(b)
+}
+
 // newInternalHTTPHandler creates and returns the HTTP handler for the internal API (accessible to
 // other internal services).
 func newInternalHTTPHandler(schema *graphql.Schema) http.Handler {
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/hooks"
+)
+
+func TestHealthzDebugHandler(t *testing.T) {
+	defer func(checks map[string]func(context.Context) (interface{}, error)) {
+		hooks.HealthChecks = checks
+	}(hooks.HealthChecks)
+
+	type serviceHealth struct {
+		Healthy bool
+		Error   string
+		Status  interface{}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		checks       map[string]func(context.Context) (interface{}, error)
+		wantStatus   int
+		wantHealthy  bool
+		wantServices map[string]serviceHealth
+	}{
+		{
+			name:         "no services",
+			checks:       map[string]func(context.Context) (interface{}, error){},
+			wantStatus:   http.StatusOK,
+			wantHealthy:  true,
+			wantServices: map[string]serviceHealth{},
+		},
+		{
+			name: "healthy services",
+			checks: map[string]func(context.Context) (interface{}, error){
+				"a": func(context.Context) (interface{}, error) { return "up", nil },
+				"b": func(context.Context) (interface{}, error) { return nil, nil },
+			},
+			wantStatus:  http.StatusOK,
+			wantHealthy: true,
+			wantServices: map[string]serviceHealth{
+				"a": {Healthy: true, Status: "up"},
+				"b": {Healthy: true},
+			},
+		},
+		{
+			name: "unhealthy service",
+			checks: map[string]func(context.Context) (interface{}, error){
+				"a": func(context.Context) (interface{}, error) { return "up", nil },
+				"b": func(context.Context) (interface{}, error) { return "down", errors.New("connection refused") },
+			},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantHealthy: false,
+			wantServices: map[string]serviceHealth{
+				"a": {Healthy: true, Status: "up"},
+				"b": {Healthy: false, Error: "connection refused", Status: "down"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hooks.HealthChecks = tc.checks
+
+			rec := httptest.NewRecorder()
+			healthzDebugHandler(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("have status %d, want %d", rec.Code, tc.wantStatus)
+			}
+
+			var have struct {
+				Healthy  bool
+				Services map[string]serviceHealth
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &have); err != nil {
+				t.Fatal(err)
+			}
+			if have.Healthy != tc.wantHealthy {
+				t.Errorf("have healthy %v, want %v", have.Healthy, tc.wantHealthy)
+			}
+			if !reflect.DeepEqual(have.Services, tc.wantServices) {
+				t.Errorf("have services %+v, want %+v", have.Services, tc.wantServices)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var checkDBSchema, _ = strconv.ParseBool(env.Get("SRC_CHECK_DB_SCHEMA", "false", "compare the database schema with the one the migrations create, print the differences and exit without migrating"))
+
+// printSchemaDrift writes a report of how the schema of the database drifted from the one
+// that the migrations create to w. It returns whether it drifted.
+func printSchemaDrift(ctx context.Context, w io.Writer) (bool, error) {
+	drift, err := db.CheckSchemaDrift(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(w, "Database schema version: %d\n", drift.Version)
+	if drift.Dirty {
+		fmt.Fprintln(w, "The last migration failed before it completed (dirty).")
+	}
+	if len(drift.PendingMigrations) > 0 {
+		fmt.Fprintln(w, "Pending migrations:")
+		for _, name := range drift.PendingMigrations {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	if len(drift.Differences) > 0 {
+		fmt.Fprintln(w, "Differences from the expected schema:")
+		for _, d := range drift.Differences {
+			fmt.Fprintf(w, "  %s\n", d)
+		}
+	}
+	if !drift.Drifted() {
+		fmt.Fprintln(w, "The database schema is up to date.")
+	}
+
+	return drift.Drifted(), nil
+}
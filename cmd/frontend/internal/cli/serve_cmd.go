@@ -26,6 +26,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/mailreply"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/telemetryexport"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagedigest"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/confdb"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
@@ -83,12 +85,28 @@ func defaultExternalURL(nginxAddr, httpAddr string) *url.URL {
 
 // InitDB initializes the global database connection and sets the
 // version of the frontend in our versions table.
+//
+// If SRC_CHECK_DB_SCHEMA is set, it instead reports whether the database
+// schema drifted from the one the migrations create and exits, with a
+// non-zero status if it did.
 func InitDB() error {
 	if err := dbconn.ConnectToDB(""); err != nil {
 		return err
 	}
 
 	ctx := context.Background()
+
+	if checkDBSchema {
+		drifted, err := printSchemaDrift(ctx, os.Stdout)
+		if err != nil {
+			return err
+		}
+		if drifted {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	migrate := true
 
 	for {
@@ -114,7 +132,7 @@ func InitDB() error {
 }
 
 // Main is the main entrypoint for the frontend server program.
-func Main(githubWebhook, bitbucketServerWebhook http.Handler) error {
+func Main(githubWebhook, gitlabWebhook, bitbucketServerWebhook http.Handler) error {
 	log.SetFlags(0)
 	log.SetPrefix("")
 
@@ -185,7 +203,11 @@ func Main(githubWebhook, bitbucketServerWebhook http.Handler) error {
 		return err
 	}
 
-	go debugserver.Start()
+	go debugserver.Start(debugserver.Endpoint{
+		Name:    "Health",
+		Path:    "/healthz",
+		Handler: http.HandlerFunc(healthzDebugHandler),
+	})
 
 	siteid.Init()
 
@@ -197,8 +219,11 @@ func Main(githubWebhook, bitbucketServerWebhook http.Handler) error {
 	goroutine.Go(func() { bg.CheckRedisCacheEvictionPolicy() })
 	goroutine.Go(func() { bg.DeleteOldCacheDataInRedis() })
 	goroutine.Go(func() { bg.DeleteOldEventLogsInPostgres(context.Background()) })
+	goroutine.Go(func() { bg.AggregateSearchLatencies(context.Background()) })
 	goroutine.Go(mailreply.StartWorker)
 	go updatecheck.Start()
+	go telemetryexport.Start()
+	go usagedigest.Start()
 
 	// Parse GraphQL schema and set up resolvers that depend on dbconn.Global
 	// being initialized
@@ -238,8 +263,20 @@ func Main(githubWebhook, bitbucketServerWebhook http.Handler) error {
 		}
 	}
 
+	// httpapi.NewCampaignsAPI is set by enterprise frontend
+	var campaignsAPI *httpapi.CampaignsAPI
+	if httpapi.NewCampaignsAPI != nil {
+		campaignsAPI = httpapi.NewCampaignsAPI()
+	}
+
+	// httpapi.NewSiteExportAPI is set by enterprise frontend
+	var siteExportAPI *httpapi.SiteExportAPI
+	if httpapi.NewSiteExportAPI != nil {
+		siteExportAPI = httpapi.NewSiteExportAPI()
+	}
+
 	// Create the external HTTP handler.
-	externalHandler, err := newExternalHTTPHandler(schema, githubWebhook, bitbucketServerWebhook, lsifServerProxy)
+	externalHandler, err := newExternalHTTPHandler(schema, githubWebhook, gitlabWebhook, bitbucketServerWebhook, lsifServerProxy, campaignsAPI, siteExportAPI)
 	if err != nil {
 		return err
 	}
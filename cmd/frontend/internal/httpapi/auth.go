@@ -1,11 +1,14 @@
 package httpapi
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/gorilla/mux"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -68,6 +71,12 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 				requiredScope = authz.ScopeSiteAdminSudo
 			}
 			subjectUserID, err := db.AccessTokens.Lookup(r.Context(), token, requiredScope)
+			var restrictedScopes []string
+			if err == db.ErrAccessTokenNotFound && sudoUser == "" {
+				// The token may still be valid if it was created with only restricted scopes (such
+				// as "campaigns:write"). In that case the actor is limited to those scopes.
+				subjectUserID, restrictedScopes, err = lookupRestrictedAccessToken(r.Context(), token)
+			}
 			if err != nil {
 				log15.Error("Invalid access token.", "token", token, "err", err)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
@@ -105,9 +114,95 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID, Scopes: restrictedScopes}))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// lookupRestrictedAccessToken looks up an access token that lacks the "user:all" scope. It returns
+// the subject's user ID and the restricted scopes of the token, which the actor must be limited
+// to.
+//
+// 🚨 SECURITY: ErrAccessTokenNotFound is returned if the token has no restricted scopes, so that a
+// token is never treated as unrestricted here.
+func lookupRestrictedAccessToken(ctx context.Context, token string) (subjectUserID int32, scopes []string, err error) {
+	subjectUserID, allScopes, err := db.AccessTokens.LookupScopes(ctx, token)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, scope := range allScopes {
+		switch scope {
+		case authz.ScopeCampaignsWrite, authz.ScopeCodeIntelRead:
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return 0, nil, db.ErrAccessTokenNotFound
+	}
+	return subjectUserID, scopes, nil
+}
+
+// restrictedRouteScopes are the access token scopes that allow actors restricted to them (see
+// actor.Actor.Scopes) to use the routes of the HTTP API, by route name. Requests of these actors
+// to any other route are rejected.
+//
+// 🚨 SECURITY: The handlers of these routes must check the scope themselves, since they are also
+// reachable with the other scopes.
+var restrictedRouteScopes = map[string][]string{
+	// The fields of the requests are checked when they are resolved, see
+	// graphqlbackend.RegisterScopedFields.
+	apirouter.GraphQL: {authz.ScopeCampaignsWrite, authz.ScopeCodeIntelRead},
+
+	apirouter.Campaigns:     {authz.ScopeCampaignsWrite},
+	apirouter.Campaign:      {authz.ScopeCampaignsWrite},
+	apirouter.CampaignsFeed: {authz.ScopeCampaignsWrite},
+
+	apirouter.LSIFLSP:            {authz.ScopeCodeIntelRead},
+	apirouter.RepoCodeIntelBadge: {authz.ScopeCodeIntelRead},
+}
+
+// restrictedActorRouteMiddleware rejects the requests of actors restricted to access token scopes
+// to routes of the HTTP API that none of their scopes allows. It must be used by the router, so
+// that the route of the request is known.
+func restrictedActorRouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := actor.FromContext(r.Context())
+		if len(a.Scopes) > 0 {
+			var name string
+			if route := mux.CurrentRoute(r); route != nil {
+				name = route.GetName()
+			}
+			if !actorHasAnyScope(a, restrictedRouteScopes[name]) {
+				http.Error(w, "The scopes of the access token don't allow this request.", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ForbidRestrictedActorsMiddleware rejects all requests of actors restricted to access token
+// scopes. It must be used by handlers that don't check the scopes of the actor, such as the app
+// handler, after AccessTokenAuthMiddleware.
+func ForbidRestrictedActorsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(actor.FromContext(r.Context()).Scopes) > 0 {
+			http.Error(w, "The scopes of the access token don't allow this request.", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func actorHasAnyScope(a *actor.Actor, scopes []string) bool {
+	for _, have := range a.Scopes {
+		for _, want := range scopes {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
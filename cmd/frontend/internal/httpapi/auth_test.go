@@ -7,10 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -21,6 +24,9 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		actor := actor.FromContext(r.Context())
 		if actor.IsAuthenticated() {
 			fmt.Fprintf(w, "user %v", actor.UID)
+			if len(actor.Scopes) > 0 {
+				fmt.Fprintf(w, " scopes %v", actor.Scopes)
+			}
 		} else {
 			fmt.Fprint(w, "no user")
 		}
@@ -103,6 +109,40 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		})
 	}
 
+	t.Run("valid restricted token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			return 0, db.ErrAccessTokenNotFound
+		}
+		var calledAccessTokensLookupScopes bool
+		db.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
+			calledAccessTokensLookupScopes = true
+			if want := "abcdef"; tokenHexEncoded != want {
+				t.Errorf("got %q, want %q", tokenHexEncoded, want)
+			}
+			return 123, []string{authz.ScopeCampaignsWrite, "x"}, nil
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusOK, "user 123 scopes [campaigns:write]")
+		if !calledAccessTokensLookupScopes {
+			t.Error("!calledAccessTokensLookupScopes")
+		}
+	})
+
+	t.Run("valid token without restricted scopes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			return 0, db.ErrAccessTokenNotFound
+		}
+		db.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
+			return 123, []string{authz.ScopeSiteAdminSudo}, nil
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
+	})
+
 	// Test that an access token overwrites the actor set by a prior auth middleware.
 	t.Run("actor present, valid non-sudo token", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
@@ -281,3 +321,134 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestRestrictedActorRouteMiddleware(t *testing.T) {
+	handler := NewHandler(router.New(mux.NewRouter()), nil, nil, nil, nil, nil, nil, nil)
+
+	campaignsWrite := &actor.Actor{UID: 1, Scopes: []string{authz.ScopeCampaignsWrite}}
+	codeIntelRead := &actor.Actor{UID: 1, Scopes: []string{authz.ScopeCodeIntelRead}}
+
+	for _, tc := range []struct {
+		name           string
+		actor          *actor.Actor
+		method, path   string
+		body           string
+		wantStatusCode int
+		wantBodyPart   string
+	}{
+		{
+			// The campaigns API isn't available in the OSS build, so the
+			// handler responds with 404.
+			name:           "unrestricted actor",
+			actor:          &actor.Actor{UID: 1},
+			method:         "GET",
+			path:           "/campaigns",
+			wantStatusCode: http.StatusNotFound,
+			wantBodyPart:   "only available in enterprise",
+		},
+		{
+			name:           "route allowing the scope",
+			actor:          campaignsWrite,
+			method:         "GET",
+			path:           "/campaigns",
+			wantStatusCode: http.StatusNotFound,
+			wantBodyPart:   "only available in enterprise",
+		},
+		{
+			name:           "route allowing the scope in repository",
+			actor:          codeIntelRead,
+			method:         "GET",
+			path:           "/repos/github.com/gorilla/mux/-/code-intel-badge.svg",
+			wantStatusCode: http.StatusNotFound,
+			wantBodyPart:   "only available in enterprise",
+		},
+		{
+			name:           "route allowing another scope",
+			actor:          codeIntelRead,
+			method:         "GET",
+			path:           "/campaigns",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "route allowing no scope",
+			actor:          campaignsWrite,
+			method:         "POST",
+			path:           "/repos/github.com/gorilla/mux/-/refresh",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "upload with read scope",
+			actor:          codeIntelRead,
+			method:         "POST",
+			path:           "/lsif/upload",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "GraphQL root field allowing another scope",
+			actor:          codeIntelRead,
+			method:         "POST",
+			path:           "/graphql",
+			body:           `{"query": "mutation { deleteCampaign(campaign: \"Q2FtcGFpZ246MQ==\") { alwaysNil } }"}`,
+			wantStatusCode: http.StatusForbidden,
+			wantBodyPart:   `may not request "deleteCampaign"`,
+		},
+		{
+			name:           "GraphQL root field allowing no scope",
+			actor:          campaignsWrite,
+			method:         "POST",
+			path:           "/graphql",
+			body:           `{"query": "query { currentUser { username } }"}`,
+			wantStatusCode: http.StatusForbidden,
+			wantBodyPart:   `may not request "currentUser"`,
+		},
+		{
+			name:           "invalid GraphQL request",
+			actor:          campaignsWrite,
+			method:         "POST",
+			path:           "/graphql",
+			body:           `{"query": 1}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req = req.WithContext(actor.WithActor(req.Context(), tc.actor))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tc.wantStatusCode {
+				t.Errorf("got response status %d, want %d: %s", rr.Code, tc.wantStatusCode, rr.Body)
+			}
+			if got := rr.Body.String(); !strings.Contains(got, tc.wantBodyPart) {
+				t.Errorf("got response body %q, want it to contain %q", got, tc.wantBodyPart)
+			}
+		})
+	}
+}
+
+func TestForbidRestrictedActorsMiddleware(t *testing.T) {
+	handler := ForbidRestrictedActorsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+
+	for _, tc := range []struct {
+		name           string
+		actor          *actor.Actor
+		wantStatusCode int
+	}{
+		{"anonymous", &actor.Actor{}, http.StatusOK},
+		{"unrestricted actor", &actor.Actor{UID: 1}, http.StatusOK},
+		{"restricted actor", &actor.Actor{UID: 1, Scopes: []string{authz.ScopeCodeIntelRead}}, http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			req = req.WithContext(actor.WithActor(req.Context(), tc.actor))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tc.wantStatusCode {
+				t.Errorf("got response status %d, want %d", rr.Code, tc.wantStatusCode)
+			}
+		})
+	}
+}
@@ -30,12 +30,16 @@ import (
 //
 // 🚨 SECURITY: The caller MUST wrap the returned handler in middleware that checks authentication
 // and sets the actor in the request context.
-func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketServerWebhook http.Handler, lsifServerProxy *httpapi.LSIFServerProxy) http.Handler {
+func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, gitlabWebhook, bitbucketServerWebhook http.Handler, lsifServerProxy *httpapi.LSIFServerProxy, campaignsAPI *httpapi.CampaignsAPI, siteExportAPI *httpapi.SiteExportAPI) http.Handler {
 	if m == nil {
 		m = apirouter.New(nil)
 	}
 	m.StrictSlash(true)
 
+	// 🚨 SECURITY: Actors restricted to access token scopes may only use the routes that allow
+	// one of their scopes.
+	m.Use(restrictedActorRouteMiddleware)
+
 	handler := jsonMiddleware(&errorHandler{
 		// Only display error message to admins when in debug mode, since it
 		// may contain sensitive info (like API keys in net/http error
@@ -52,6 +56,10 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 		m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(githubWebhook))
 	}
 
+	if gitlabWebhook != nil {
+		m.Get(apirouter.GitLabWebhooks).Handler(trace.TraceRoute(gitlabWebhook))
+	}
+
 	if bitbucketServerWebhook != nil {
 		m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.TraceRoute(bitbucketServerWebhook))
 	}
@@ -64,11 +72,52 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 
 	if lsifServerProxy != nil {
 		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(lsifServerProxy.UploadHandler))
+		m.Get(apirouter.LSIFUploadSessions).Handler(trace.TraceRoute(lsifServerProxy.UploadSessionsHandler))
+		m.Get(apirouter.LSIFUploadSession).Handler(trace.TraceRoute(lsifServerProxy.UploadSessionHandler))
+		m.Get(apirouter.LSIFLSP).Handler(trace.TraceRoute(lsifServerProxy.LSPHandler))
+		m.Get(apirouter.RepoCodeIntelBadge).Handler(trace.TraceRoute(lsifServerProxy.BadgeHandler))
 	} else {
-		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lsifUploadUnavailable := trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			_, _ = w.Write([]byte("lsif upload is only available in enterprise"))
+		}))
+		m.Get(apirouter.LSIFUpload).Handler(lsifUploadUnavailable)
+		m.Get(apirouter.LSIFUploadSessions).Handler(lsifUploadUnavailable)
+		m.Get(apirouter.LSIFUploadSession).Handler(lsifUploadUnavailable)
+		m.Get(apirouter.LSIFLSP).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("lsif lsp is only available in enterprise"))
 		})))
+		m.Get(apirouter.RepoCodeIntelBadge).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("code intel badges are only available in enterprise"))
+		})))
+	}
+
+	if campaignsAPI != nil {
+		m.Get(apirouter.Campaigns).Handler(trace.TraceRoute(campaignsAPI.CampaignsHandler))
+		m.Get(apirouter.Campaign).Handler(trace.TraceRoute(campaignsAPI.CampaignHandler))
+		m.Get(apirouter.CampaignsFeed).Handler(trace.TraceRoute(campaignsAPI.FeedHandler))
+	} else {
+		campaignsUnavailable := trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("campaigns are only available in enterprise"))
+		}))
+		m.Get(apirouter.Campaigns).Handler(campaignsUnavailable)
+		m.Get(apirouter.Campaign).Handler(campaignsUnavailable)
+		m.Get(apirouter.CampaignsFeed).Handler(campaignsUnavailable)
+	}
+
+	if siteExportAPI != nil {
+		m.Get(apirouter.SiteExport).Handler(trace.TraceRoute(siteExportAPI.ExportHandler))
+		m.Get(apirouter.SiteImport).Handler(trace.TraceRoute(siteExportAPI.ImportHandler))
+	} else {
+		siteExportUnavailable := trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("site export and import are only available in enterprise"))
+		}))
+		m.Get(apirouter.SiteExport).Handler(siteExportUnavailable)
+		m.Get(apirouter.SiteImport).Handler(siteExportUnavailable)
 	}
 
 	// Return the minimum src-cli version that's compatible with this instance
@@ -7,21 +7,33 @@ import (
 )
 
 const (
-	LSIFUpload = "lsif.upload"
-	GraphQL    = "graphql"
+	LSIFUpload         = "lsif.upload"
+	LSIFUploadSessions = "lsif.upload.sessions"
+	LSIFUploadSession  = "lsif.upload.session"
+	LSIFLSP            = "lsif.lsp"
+	GraphQL            = "graphql"
 
 	SrcCliVersion  = "src-cli.version"
 	SrcCliDownload = "src-cli.download"
 
 	Registry = "registry"
 
-	RepoShield  = "repo.shield"
-	RepoRefresh = "repo.refresh"
-	Telemetry   = "telemetry"
+	RepoShield         = "repo.shield"
+	RepoCodeIntelBadge = "repo.code-intel-badge"
+	RepoRefresh        = "repo.refresh"
+	Telemetry          = "telemetry"
 
 	GitHubWebhooks          = "github.webhooks"
+	GitLabWebhooks          = "gitlab.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
 
+	Campaigns     = "campaigns"
+	Campaign      = "campaign"
+	CampaignsFeed = "campaigns.feed"
+
+	SiteExport = "site.export"
+	SiteImport = "site.import"
+
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
 	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
@@ -63,8 +75,17 @@ func New(base *mux.Router) *mux.Router {
 	addRegistryRoute(base)
 	addGraphQLRoute(base)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
+	base.Path("/gitlab-webhooks").Methods("POST").Name(GitLabWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
+	base.Path("/lsif/upload/sessions").Methods("POST").Name(LSIFUploadSessions)
+	base.Path("/lsif/upload/sessions/{id}").Methods("HEAD", "PATCH", "DELETE").Name(LSIFUploadSession)
+	base.Path("/lsif/lsp").Methods("GET").Name(LSIFLSP)
+	base.Path("/campaigns").Methods("GET", "POST").Name(Campaigns)
+	base.Path("/campaigns/feed").Methods("GET").Name(CampaignsFeed)
+	base.Path("/campaigns/{id}").Methods("GET", "PATCH").Name(Campaign)
+	base.Path("/site/export").Methods("GET").Name(SiteExport)
+	base.Path("/site/import").Methods("POST").Name(SiteImport)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
 
@@ -75,6 +96,7 @@ func New(base *mux.Router) *mux.Router {
 	// add above repo paths.
 	repo := base.PathPrefix(repoPath + "/" + routevar.RepoPathDelim + "/").Subrouter()
 	repo.Path("/shield").Methods("GET").Name(RepoShield)
+	repo.Path("/code-intel-badge.{format:svg|json}").Methods("GET").Name(RepoCodeIntelBadge)
 	repo.Path("/refresh").Methods("POST").Name(RepoRefresh)
 
 	return base
@@ -0,0 +1,4 @@
+// Package telemetryexport periodically sends anonymized, aggregated usage statistics to a
+// site-admin-configured endpoint. It is disabled unless the "telemetry.export" site
+// configuration is set and enabled.
+package telemetryexport
@@ -0,0 +1,210 @@
+package telemetryexport
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context/ctxhttp"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/leader"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// SignatureHeader is the HTTP header containing the hex-encoded HMAC-SHA256 signature
+// of the request body, computed with the configured signing key.
+const SignatureHeader = "X-Sourcegraph-Signature"
+
+const defaultIntervalMinutes = 24 * 60
+
+// Payload is the document sent to the telemetry export endpoint. It must only ever contain
+// aggregated statistics: nothing in it may identify an individual user.
+type Payload struct {
+	SiteID          string                           `json:"siteID"`
+	Version         string                           `json:"version"`
+	DeployType      string                           `json:"deployType"`
+	Timestamp       time.Time                        `json:"timestamp"`
+	SiteUsage       *types.SiteUsageStatistics       `json:"siteUsage"`
+	CodeIntelUsage  *types.CodeIntelUsageStatistics  `json:"codeIntelUsage"`
+	AutomationUsage *types.AutomationUsageStatistics `json:"automationUsage"`
+	Retention       *types.RetentionStatistics       `json:"retention"`
+}
+
+var timeNow = time.Now
+
+// BuildPayload assembles the aggregated usage statistics that would be sent by the next export.
+func BuildPayload(ctx context.Context) (*Payload, error) {
+	days, weeks, months := 7, 4, 2
+	siteUsage, err := usagestats.GetSiteUsageStatistics(ctx, &usagestats.SiteUsageStatisticsOptions{
+		DayPeriods:   &days,
+		WeekPeriods:  &weeks,
+		MonthPeriods: &months,
+	})
+	if err != nil {
+		return nil, err
+	}
+	codeIntelUsage, err := usagestats.GetCodeIntelUsageStatistics(ctx, &usagestats.CodeIntelUsageStatisticsOptions{
+		DayPeriods:            &days,
+		WeekPeriods:           &weeks,
+		MonthPeriods:          &months,
+		IncludeEventCounts:    true,
+		IncludeEventLatencies: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	automationUsage, err := usagestats.GetAutomationUsageStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	retention, err := usagestats.GetRetentionStatistics(ctx, &usagestats.RetentionStatisticsOptions{
+		WeekPeriods: &weeks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Payload{
+		SiteID:          siteid.Get(),
+		Version:         version.Version(),
+		DeployType:      conf.DeployType(),
+		Timestamp:       timeNow().UTC(),
+		SiteUsage:       siteUsage,
+		CodeIntelUsage:  codeIntelUsage,
+		AutomationUsage: automationUsage,
+		Retention:       retention,
+	}, nil
+}
+
+// Preview returns the exact request body that the next export would send.
+func Preview(ctx context.Context) ([]byte, error) {
+	payload, err := BuildPayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(payload, "", "  ")
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 signature of body using key.
+func Sign(body []byte, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func export(ctx context.Context, cfg *schema.TelemetryExport) error {
+	body, err := Preview(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", cfg.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(body, cfg.SigningKey))
+
+	resp, err := ctxhttp.Do(ctx, nil, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var description string
+		if body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 30)); err != nil {
+			description = err.Error()
+		} else if len(body) == 0 {
+			description = "(no response body)"
+		} else {
+			description = strconv.Quote(string(bytes.TrimSpace(body)))
+		}
+		return fmt.Errorf("telemetry export endpoint returned HTTP error %d: %s", resp.StatusCode, description)
+	}
+	return nil
+}
+
+func interval(cfg *schema.TelemetryExport) time.Duration {
+	minutes := cfg.IntervalMinutes
+	if minutes <= 0 {
+		minutes = defaultIntervalMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+var (
+	mu          sync.Mutex
+	lastAttempt time.Time
+	lastExport  time.Time
+	started     bool
+)
+
+// LastExport returns the time of the last successful export, or the zero time if
+// no export has succeeded since the process started.
+func LastExport() time.Time {
+	mu.Lock()
+	defer mu.Unlock()
+	return lastExport
+}
+
+// Start periodically exports usage statistics while the telemetry export is enabled in
+// site configuration. Configuration changes take effect without a restart.
+func Start() {
+	mu.Lock()
+	if started {
+		mu.Unlock()
+		panic("already started")
+	}
+	started = true
+	mu.Unlock()
+
+	// Only one frontend replica exports, so that the statistics aren't sent once per replica.
+	lock := leader.New(dbconn.Global, "telemetry_export")
+
+	ctx := context.Background()
+	for {
+		// Failed exports are not retried before the next interval to avoid hammering an
+		// unavailable endpoint.
+		if cfg := conf.Get().TelemetryExport; cfg != nil && cfg.Enabled && time.Since(lastAttempt) >= interval(cfg) && isLeader(ctx, lock) {
+			lastAttempt = time.Now()
+
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			if err := export(ctx, cfg); err != nil {
+				log15.Error("telemetryexport: export failed", "url", cfg.Url, "error", err)
+			} else {
+				mu.Lock()
+				lastExport = time.Now()
+				mu.Unlock()
+			}
+			cancel()
+		}
+
+		time.Sleep(time.Minute)
+	}
+}
+
+func isLeader(ctx context.Context, lock *leader.Lock) bool {
+	held, err := lock.Held(ctx)
+	if err != nil {
+		log15.Error("telemetryexport: electing leader failed", "error", err)
+	}
+	return held
+}
@@ -0,0 +1,32 @@
+package telemetryexport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSign(t *testing.T) {
+	// Computed with: printf '{"a":1}' | openssl dgst -sha256 -hmac secret
+	want := "aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494"
+	if got := Sign([]byte(`{"a":1}`), "secret"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		cfg  *schema.TelemetryExport
+		want time.Duration
+	}{
+		{cfg: &schema.TelemetryExport{}, want: 24 * time.Hour},
+		{cfg: &schema.TelemetryExport{IntervalMinutes: -5}, want: 24 * time.Hour},
+		{cfg: &schema.TelemetryExport{IntervalMinutes: 90}, want: 90 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := interval(test.cfg); got != test.want {
+			t.Errorf("interval(%+v): got %s, want %s", test.cfg, got, test.want)
+		}
+	}
+}
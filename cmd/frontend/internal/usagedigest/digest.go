@@ -0,0 +1,204 @@
+package usagedigest
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/leader"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+var timeNow = time.Now
+
+// Start periodically sends the digests of the previous week to the users that haven't
+// received it yet. Only one frontend replica sends digests at a time.
+func Start() {
+	lock := leader.New(dbconn.Global, "usage_digest")
+
+	ctx := context.Background()
+	for {
+		if held, err := lock.Held(ctx); err != nil {
+			log15.Error("usagedigest: electing leader failed", "error", err)
+		} else if held && conf.Get().EmailSmtp != nil {
+			if err := sendDigests(ctx, timeutil.StartOfWeek(timeNow().UTC(), 1)); err != nil {
+				log15.Error("usagedigest: sending digests failed", "error", err)
+			}
+		}
+
+		time.Sleep(time.Hour)
+	}
+}
+
+// sendDigests sends the digests of the week starting at weekStart to all users that
+// haven't been processed for that week yet.
+func sendDigests(ctx context.Context, weekStart time.Time) error {
+	processed, err := processedUserIDs(ctx, weekStart)
+	if err != nil {
+		return err
+	}
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		users, err := db.Users.List(ctx, &db.UsersListOptions{LimitOffset: &db.LimitOffset{Limit: pageSize, Offset: offset}})
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			if processed[u.ID] {
+				continue
+			}
+			if err := processUser(ctx, u, weekStart); err != nil {
+				log15.Warn("usagedigest: sending digest failed", "user", u.ID, "error", err)
+			}
+		}
+
+		if len(users) < pageSize {
+			return nil
+		}
+	}
+}
+
+// processUser sends the digest of the week starting at weekStart to the given user, unless
+// they opted out, weren't active or the user was processed for that week already.
+func processUser(ctx context.Context, u *types.User, weekStart time.Time) error {
+	reserved, err := reserveDigest(ctx, u.ID, weekStart)
+	if err != nil || !reserved {
+		return err
+	}
+
+	sent, err := sendDigest(ctx, u, weekStart)
+	if err != nil {
+		// The digest is retried in the next run.
+		if err := deleteDigest(ctx, u.ID, weekStart); err != nil {
+			log15.Error("usagedigest: deleting failed digest", "user", u.ID, "error", err)
+		}
+		return err
+	}
+
+	if !sent {
+		return nil
+	}
+	return markDigestSent(ctx, u.ID, weekStart)
+}
+
+// sendDigest sends the digest of the week starting at weekStart to the given user. It
+// returns false if the digest was not sent because it isn't wanted.
+func sendDigest(ctx context.Context, u *types.User, weekStart time.Time) (sent bool, err error) {
+	settings, err := backend.Configuration.GetForSubject(ctx, api.SettingsSubject{User: &u.ID})
+	if err != nil {
+		return false, errors.Wrap(err, "getting user settings")
+	}
+	if settings.EmailUsageDigest != nil && !*settings.EmailUsageDigest {
+		return false, nil
+	}
+
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, u.ID)
+	if errcode.IsNotFound(err) || (err == nil && !verified) {
+		// Only verified emails receive digests.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "getting primary email")
+	}
+
+	activity, err := usagestats.GetUserActivity(ctx, u.ID, weekStart, weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return false, errors.Wrap(err, "getting user activity")
+	}
+	if activity.IsEmpty() {
+		return false, nil
+	}
+
+	err = txemail.Send(ctx, txemail.Message{
+		To:       []string{email},
+		Template: digestEmailTemplates,
+		Data:     newDigestData(u, weekStart, activity),
+	})
+	return err == nil, err
+}
+
+// digestData is the data of the digestEmailTemplates.
+type digestData struct {
+	Username                string
+	WeekStart               string
+	SearchQueries           int
+	CodeIntelligenceActions int
+	CampaignsUpdated        int
+	URL                     string
+	SettingsURL             string
+}
+
+func newDigestData(u *types.User, weekStart time.Time, activity *usagestats.UserActivity) *digestData {
+	return &digestData{
+		Username:                u.Username,
+		WeekStart:               weekStart.Format("January 2, 2006"),
+		SearchQueries:           activity.SearchQueries,
+		CodeIntelligenceActions: activity.CodeIntelligenceActions,
+		CampaignsUpdated:        activity.CampaignsUpdated,
+		URL:                     emailURL("/search"),
+		SettingsURL:             emailURL("/users/" + u.Username + "/settings"),
+	}
+}
+
+func emailURL(path string) string {
+	u := globals.ExternalURL().ResolveReference(&url.URL{Path: path})
+	q := u.Query()
+	q.Set("utm_source", "usage-digest-email")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// processedUserIDs returns the IDs of the users that were processed for the week starting
+// at weekStart.
+func processedUserIDs(ctx context.Context, weekStart time.Time) (map[int32]bool, error) {
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT user_id FROM user_usage_digests WHERE week_start = $1::date", weekStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[int32]bool{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// reserveDigest records that the given user is processed for the week starting at
+// weekStart. It returns false if the user was processed already, e.g. by another replica.
+func reserveDigest(ctx context.Context, userID int32, weekStart time.Time) (bool, error) {
+	res, err := dbconn.Global.ExecContext(ctx, "INSERT INTO user_usage_digests (user_id, week_start) VALUES ($1, $2::date) ON CONFLICT DO NOTHING", userID, weekStart)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func markDigestSent(ctx context.Context, userID int32, weekStart time.Time) error {
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE user_usage_digests SET sent_at = now() WHERE user_id = $1 AND week_start = $2::date", userID, weekStart)
+	return err
+}
+
+func deleteDigest(ctx context.Context, userID int32, weekStart time.Time) error {
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_usage_digests WHERE user_id = $1 AND week_start = $2::date", userID, weekStart)
+	return err
+}
@@ -0,0 +1,4 @@
+// Package usagedigest sends each user a weekly email that summarizes their activity on
+// Sourcegraph during the previous week. Users opt out with the "email.usageDigest" setting.
+// No digests are sent unless email is configured in site configuration.
+package usagedigest
@@ -0,0 +1,38 @@
+package usagedigest
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+var digestEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Your week on Sourcegraph`,
+	Text: `
+Hi {{.Username}}, here's what you did on Sourcegraph in the week of {{.WeekStart}}:
+{{if .SearchQueries}}
+  Searches run: {{.SearchQueries}}{{end}}{{if .CodeIntelligenceActions}}
+  Code intelligence actions: {{.CodeIntelligenceActions}}{{end}}{{if .CampaignsUpdated}}
+  Campaigns updated: {{.CampaignsUpdated}}{{end}}
+
+Continue on Sourcegraph:
+
+  {{.URL}}
+
+To stop receiving this email, set "email.usageDigest" to false in your user settings:
+
+  {{.SettingsURL}}
+`,
+	HTML: `
+<p>Hi {{.Username}}, here's what you did on Sourcegraph in the week of {{.WeekStart}}:</p>
+
+<table>
+{{if .SearchQueries}}<tr><td>Searches run</td><td><strong>{{.SearchQueries}}</strong></td></tr>{{end}}
+{{if .CodeIntelligenceActions}}<tr><td>Code intelligence actions</td><td><strong>{{.CodeIntelligenceActions}}</strong></td></tr>{{end}}
+{{if .CampaignsUpdated}}<tr><td>Campaigns updated</td><td><strong>{{.CampaignsUpdated}}</strong></td></tr>{{end}}
+</table>
+
+<p><strong><a href="{{.URL}}">Continue on Sourcegraph</a></strong></p>
+
+<p style="color:#777">To stop receiving this email, set <code>"email.usageDigest"</code> to <code>false</code> in your <a href="{{.SettingsURL}}">user settings</a>.</p>
+`,
+})
@@ -0,0 +1,38 @@
+package usagedigest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+)
+
+func TestDigestEmailTemplates(t *testing.T) {
+	m, err := txemail.Render(txemail.Message{
+		To:       []string{"alice@example.com"},
+		Template: digestEmailTemplates,
+		Data: &digestData{
+			Username:         "alice",
+			WeekStart:        "January 5, 2020",
+			SearchQueries:    12,
+			CampaignsUpdated: 1,
+			URL:              "https://sourcegraph.example.com/search",
+			SettingsURL:      "https://sourcegraph.example.com/users/alice/settings",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{m.Body, m.HTMLBody} {
+		for _, want := range []string{"alice", "January 5, 2020", "Searches run", "12", "Campaigns updated", "/users/alice/settings"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("body doesn't contain %q:\n%s", want, body)
+			}
+		}
+		// Activities without any actions are left out.
+		if strings.Contains(body, "Code intelligence actions") {
+			t.Errorf("body contains code intelligence actions:\n%s", body)
+		}
+	}
+}
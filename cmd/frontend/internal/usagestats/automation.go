@@ -9,7 +9,7 @@ import (
 
 // GetAutomationUsageStatistics returns the current site's automation usage.
 func GetAutomationUsageStatistics(ctx context.Context) (*types.AutomationUsageStatistics, error) {
-	const q = "SELECT COUNT(*) FROM campaigns;"
+	const q = "SELECT COUNT(*) FROM campaigns WHERE deleted_at IS NULL;"
 
 	var count int
 	if err := dbconn.Global.QueryRowContext(ctx, q).Scan(&count); err != nil {
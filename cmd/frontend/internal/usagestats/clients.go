@@ -0,0 +1,99 @@
+package usagestats
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// ClientUsageStatisticsOptions contains options for the number of daily, weekly, and monthly
+// periods in which to calculate the number of unique users of each client.
+type ClientUsageStatisticsOptions struct {
+	DayPeriods   *int
+	WeekPeriods  *int
+	MonthPeriods *int
+
+	// DayRange, if set, selects the daily periods instead of DayPeriods.
+	DayRange *DayRange
+}
+
+func (opt *ClientUsageStatisticsOptions) dayPeriods(now time.Time) (time.Time, int) {
+	return dayPeriods(now, opt.DayPeriods, opt.DayRange)
+}
+
+// clientSources maps the event log sources to the user count of the client that logs them.
+// Events with the BACKEND source are logged by the server on behalf of any client, so they
+// are not attributed to one.
+var clientSources = map[string]func(p *types.ClientUsagePeriod) *int32{
+	"WEB":                 func(p *types.ClientUsagePeriod) *int32 { return &p.WebUserCount },
+	"CODEHOSTINTEGRATION": func(p *types.ClientUsagePeriod) *int32 { return &p.BrowserExtensionUserCount },
+	"EDITOR":              func(p *types.ClientUsagePeriod) *int32 { return &p.EditorUserCount },
+	"API":                 func(p *types.ClientUsagePeriod) *int32 { return &p.APIUserCount },
+}
+
+// GetClientUsageStatistics returns the number of unique users of the web app, the browser
+// extension, editor integrations, and the API in recent days, weeks, and months.
+func GetClientUsageStatistics(ctx context.Context, opt *ClientUsageStatisticsOptions) (*types.ClientUsageStatistics, error) {
+	var (
+		now          = timeNow().UTC()
+		dayEnd       = now
+		dayPeriods   = defaultDays
+		weekPeriods  = defaultWeeks
+		monthPeriods = defaultMonths
+	)
+
+	if opt != nil {
+		dayEnd, dayPeriods = opt.dayPeriods(now)
+		if opt.WeekPeriods != nil {
+			weekPeriods = minIntOrZero(maxStorageDays/7, *opt.WeekPeriods)
+		}
+		if opt.MonthPeriods != nil {
+			monthPeriods = minIntOrZero(maxStorageDays/31, *opt.MonthPeriods)
+		}
+	}
+
+	daus, err := clientActivity(ctx, db.Daily, dayEnd, dayPeriods)
+	if err != nil {
+		return nil, err
+	}
+	waus, err := clientActivity(ctx, db.Weekly, now, weekPeriods)
+	if err != nil {
+		return nil, err
+	}
+	maus, err := clientActivity(ctx, db.Monthly, now, monthPeriods)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ClientUsageStatistics{
+		DAUs: daus,
+		WAUs: waus,
+		MAUs: maus,
+	}, nil
+}
+
+func clientActivity(ctx context.Context, periodType db.PeriodType, now time.Time, periods int) ([]*types.ClientUsagePeriod, error) {
+	activityPeriods := make([]*types.ClientUsagePeriod, 0, periods)
+	for i := 0; i < periods; i++ {
+		activityPeriods = append(activityPeriods, &types.ClientUsagePeriod{})
+	}
+	if periods == 0 {
+		return activityPeriods, nil
+	}
+
+	for source, getUserCount := range clientSources {
+		userCounts, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, now, periods, &db.CountUniqueUsersOptions{
+			Sources: []string{source},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i, uc := range userCounts {
+			activityPeriods[i].StartTime = uc.Start
+			*getUserCount(activityPeriods[i]) = int32(uc.Count)
+		}
+	}
+	return activityPeriods, nil
+}
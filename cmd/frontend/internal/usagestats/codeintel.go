@@ -2,7 +2,12 @@ package usagestats
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 )
@@ -15,6 +20,13 @@ type CodeIntelUsageStatisticsOptions struct {
 	MonthPeriods          *int
 	IncludeEventCounts    bool
 	IncludeEventLatencies bool
+
+	// DayRange, if set, selects the daily periods instead of DayPeriods.
+	DayRange *DayRange
+}
+
+func (opt *CodeIntelUsageStatisticsOptions) dayPeriods(now time.Time) (time.Time, int) {
+	return dayPeriods(now, opt.DayPeriods, opt.DayRange)
 }
 
 type (
@@ -29,18 +41,154 @@ var (
 	DurationPercentiles = []float64{0.5, 0.9, 0.99}
 )
 
+// CodeIntelQueryEventPrefix is the prefix of the names of the events that record precise code
+// intel queries. The name of the operation is appended to it, e.g. "definitions".
+const CodeIntelQueryEventPrefix = "codeintel.lsifQuery."
+
+// CodeIntelFallbackEventName is the name of the events that record lookups of the uploads of
+// a file that found none, after which clients fall back to search-based code intel.
+const CodeIntelFallbackEventName = "codeintel.lsifFallback"
+
+// IndexerField and LanguageField are the fields of the arguments of code intel query events
+// that contain the name of the indexer of the queried upload and the language of the queried
+// file. CodePathField and VariantField contain the code path that served the query and the
+// variant of the code intel feature flags of the user.
+const (
+	IndexerField  = "indexer"
+	LanguageField = "language"
+	CodePathField = "codePath"
+	VariantField  = "variant"
+)
+
+var codeIntelQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "src",
+	Subsystem: "codeintel",
+	Name:      "lsif_query_duration_seconds",
+	Help:      "Duration of code intel queries, by operation, code path, feature flag variant, indexer and language.",
+	Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+}, []string{"operation", "code_path", "variant", "indexer", "language"})
+
+func init() {
+	prometheus.MustRegister(codeIntelQueryDuration)
+}
+
+// LogCodeIntelQuery logs a code intel query by the given user. Queries that found no upload
+// are logged as CodeIntelFallbackEventName events, and the others as events named after their
+// operation.
+func LogCodeIntelQuery(userID int32, q *types.CodeIntelQuery) error {
+	durationMs := q.Duration.Nanoseconds() / int64(time.Millisecond)
+	codeIntelQueryDuration.WithLabelValues(q.Operation, q.CodePath, q.Variant, q.Indexer, q.Language).Observe(q.Duration.Seconds())
+
+	argument, err := json.Marshal(map[string]interface{}{
+		DurationField: durationMs,
+		IndexerField:  q.Indexer,
+		LanguageField: q.Language,
+		CodePathField: q.CodePath,
+		VariantField:  q.Variant,
+	})
+	if err != nil {
+		return err
+	}
+
+	name := CodeIntelQueryEventPrefix + q.Operation
+	if q.CodePath == types.CodeIntelPathFallback {
+		name = CodeIntelFallbackEventName
+	}
+	return LogBackendEvent(userID, name, argument)
+}
+
+// CodeIntelQueryStatisticsOptions contains options for the number of days over which precise
+// code intel queries are aggregated.
+type CodeIntelQueryStatisticsOptions struct {
+	Days *int
+}
+
+// GetCodeIntelQueryStatistics returns the number of precise code intel queries and of users that
+// made them, by indexer and language, ordered by decreasing number of queries.
+func GetCodeIntelQueryStatistics(ctx context.Context, opt *CodeIntelQueryStatisticsOptions) ([]*types.CodeIntelQueryStatistics, error) {
+	days := defaultDays
+	if opt != nil && opt.Days != nil {
+		days = minIntOrZero(maxStorageDays, *opt.Days)
+	}
+
+	now := timeNow().UTC()
+	groups, err := db.EventLogs.CountByArgumentFields(ctx, now.Add(-time.Duration(days)*24*time.Hour), now, []string{IndexerField, LanguageField}, &db.EventFilterOptions{
+		ByEventNamePrefix: CodeIntelQueryEventPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*types.CodeIntelQueryStatistics, 0, len(groups))
+	for _, g := range groups {
+		stats = append(stats, &types.CodeIntelQueryStatistics{
+			Indexer:      g.Values[0],
+			Language:     g.Values[1],
+			UsersCount:   int32(g.UsersCount),
+			QueriesCount: int32(g.EventsCount),
+		})
+	}
+	return stats, nil
+}
+
+// GetCodeIntelQueryPathStatistics returns the number and the latency percentiles of code intel
+// queries by the code path that served them and the variant of the code intel feature flags of
+// their users, ordered by decreasing number of queries. Queries logged before code paths were
+// recorded are omitted.
+func GetCodeIntelQueryPathStatistics(ctx context.Context, opt *CodeIntelQueryStatisticsOptions) ([]*types.CodeIntelQueryPathStatistics, error) {
+	days := defaultDays
+	if opt != nil && opt.Days != nil {
+		days = minIntOrZero(maxStorageDays, *opt.Days)
+	}
+
+	now := timeNow().UTC()
+	var stats []*types.CodeIntelQueryPathStatistics
+	for _, filter := range []*db.EventFilterOptions{
+		{ByEventNamePrefix: CodeIntelQueryEventPrefix},
+		{ByEventName: CodeIntelFallbackEventName},
+	} {
+		groups, err := db.EventLogs.PercentilesByArgumentFields(ctx, now.Add(-time.Duration(days)*24*time.Hour), now, []string{CodePathField, VariantField}, DurationField, DurationPercentiles, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range groups {
+			if g.Values[0] == "" {
+				continue
+			}
+			variant := []string{}
+			if g.Values[1] != "" {
+				variant = strings.Split(g.Values[1], ",")
+			}
+			stats = append(stats, &types.CodeIntelQueryPathStatistics{
+				CodePath:     g.Values[0],
+				Variant:      variant,
+				QueriesCount: int32(g.EventsCount),
+				Latencies: &types.CodeIntelEventLatencies{
+					P50: g.Percentiles[0],
+					P90: g.Percentiles[1],
+					P99: g.Percentiles[2],
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].QueriesCount > stats[j].QueriesCount })
+	return stats, nil
+}
+
 // GetCodeIntelUsageStatistics returns the current site's code intel activity.
 func GetCodeIntelUsageStatistics(ctx context.Context, opt *CodeIntelUsageStatisticsOptions) (*types.CodeIntelUsageStatistics, error) {
 	var (
+		now          = timeNow().UTC()
+		dayEnd       = now
 		dayPeriods   = defaultDays
 		weekPeriods  = defaultWeeks
 		monthPeriods = defaultMonths
 	)
 
 	if opt != nil {
-		if opt.DayPeriods != nil {
-			dayPeriods = minIntOrZero(maxStorageDays, *opt.DayPeriods)
-		}
+		dayEnd, dayPeriods = opt.dayPeriods(now)
 		if opt.WeekPeriods != nil {
 			weekPeriods = minIntOrZero(maxStorageDays/7, *opt.WeekPeriods)
 		}
@@ -49,15 +197,15 @@ func GetCodeIntelUsageStatistics(ctx context.Context, opt *CodeIntelUsageStatist
 		}
 	}
 
-	daily, err := codeIntelActivity(ctx, db.Daily, dayPeriods, opt.IncludeEventCounts, opt.IncludeEventLatencies)
+	daily, err := codeIntelActivity(ctx, db.Daily, dayEnd, dayPeriods, opt.IncludeEventCounts, opt.IncludeEventLatencies)
 	if err != nil {
 		return nil, err
 	}
-	weekly, err := codeIntelActivity(ctx, db.Weekly, weekPeriods, opt.IncludeEventCounts, opt.IncludeEventLatencies)
+	weekly, err := codeIntelActivity(ctx, db.Weekly, now, weekPeriods, opt.IncludeEventCounts, opt.IncludeEventLatencies)
 	if err != nil {
 		return nil, err
 	}
-	monthly, err := codeIntelActivity(ctx, db.Monthly, monthPeriods, opt.IncludeEventCounts, opt.IncludeEventLatencies)
+	monthly, err := codeIntelActivity(ctx, db.Monthly, now, monthPeriods, opt.IncludeEventCounts, opt.IncludeEventLatencies)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +216,7 @@ func GetCodeIntelUsageStatistics(ctx context.Context, opt *CodeIntelUsageStatist
 	}, nil
 }
 
-func codeIntelActivity(ctx context.Context, periodType db.PeriodType, periods int, includeEventCounts, includeEventLatencies bool) ([]*types.CodeIntelUsagePeriod, error) {
+func codeIntelActivity(ctx context.Context, periodType db.PeriodType, now time.Time, periods int, includeEventCounts, includeEventLatencies bool) ([]*types.CodeIntelUsagePeriod, error) {
 	if periods == 0 {
 		return []*types.CodeIntelUsagePeriod{}, nil
 	}
@@ -95,7 +243,7 @@ func codeIntelActivity(ctx context.Context, periodType db.PeriodType, periods in
 	}
 
 	for eventName, getEventStatistic := range eventStatisticByName {
-		userCounts, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, timeNow().UTC(), periods, &db.CountUniqueUsersOptions{
+		userCounts, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, now, periods, &db.CountUniqueUsersOptions{
 			EventFilters: &db.EventFilterOptions{
 				ByEventName: eventName,
 			},
@@ -110,7 +258,7 @@ func codeIntelActivity(ctx context.Context, periodType db.PeriodType, periods in
 		}
 
 		if includeEventCounts {
-			eventCounts, err := db.EventLogs.CountEventsPerPeriod(ctx, periodType, timeNow().UTC(), periods, &db.EventFilterOptions{
+			eventCounts, err := db.EventLogs.CountEventsPerPeriod(ctx, periodType, now, periods, &db.EventFilterOptions{
 				ByEventName: eventName,
 			})
 			if err != nil {
@@ -124,7 +272,7 @@ func codeIntelActivity(ctx context.Context, periodType db.PeriodType, periods in
 		}
 
 		if includeEventLatencies {
-			percentiles, err := db.EventLogs.PercentilesPerPeriod(ctx, periodType, timeNow().UTC(), periods, DurationField, DurationPercentiles, &db.EventFilterOptions{
+			percentiles, err := db.EventLogs.PercentilesPerPeriod(ctx, periodType, now, periods, DurationField, DurationPercentiles, &db.EventFilterOptions{
 				ByEventName: eventName,
 			})
 			if err != nil {
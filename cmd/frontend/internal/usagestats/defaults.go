@@ -1,5 +1,7 @@
 package usagestats
 
+import "time"
+
 const (
 	defaultDays   = 14
 	defaultWeeks  = 10
@@ -7,3 +9,53 @@ const (
 
 	maxStorageDays = 93
 )
+
+// DayRange selects the daily periods from the day of From to the day of To (in UTC), both
+// inclusive, instead of the most recent days. Periods are returned newest first, so the next
+// page of a range is requested by setting To to the day before the oldest returned period.
+type DayRange struct {
+	// From defaults to maxStorageDays before To, To defaults to now. Days before the event
+	// logs retention period have no data.
+	From, To *time.Time
+	// First is the maximum number of periods returned. It defaults to defaultDays.
+	First *int
+}
+
+// periods returns the last day and the number of the daily periods selected by r, given the
+// current time.
+func (r *DayRange) periods(now time.Time) (end time.Time, periods int) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	end = today
+	if r.To != nil {
+		to := r.To.UTC()
+		if to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC); to.Before(today) {
+			end = to
+		}
+	}
+
+	periods = maxStorageDays
+	if r.From != nil {
+		from := r.From.UTC()
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		periods = minIntOrZero(periods, int(end.Sub(from)/(24*time.Hour))+1)
+	}
+
+	first := defaultDays
+	if r.First != nil {
+		first = *r.First
+	}
+	return end, minIntOrZero(periods, first)
+}
+
+// dayPeriods returns the last day and the number of daily periods that are selected by the
+// given options, given the current time.
+func dayPeriods(now time.Time, days *int, r *DayRange) (end time.Time, periods int) {
+	if r != nil {
+		return r.periods(now)
+	}
+	if days != nil {
+		return now, minIntOrZero(maxStorageDays, *days)
+	}
+	return now, defaultDays
+}
@@ -0,0 +1,58 @@
+package usagestats
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+)
+
+// RetentionStatisticsOptions contains options for the number of weekly cohorts to return.
+type RetentionStatisticsOptions struct {
+	WeekPeriods *int
+}
+
+// GetRetentionStatistics returns the current site's weekly cohort retention. Cohorts are
+// ordered newest first, and each cohort has one retention count for every week that has
+// elapsed since (and including) the week it started.
+func GetRetentionStatistics(ctx context.Context, opt *RetentionStatisticsOptions) (*types.RetentionStatistics, error) {
+	weekPeriods := defaultWeeks
+	if opt != nil && opt.WeekPeriods != nil {
+		weekPeriods = minIntOrZero(maxStorageDays/7, *opt.WeekPeriods)
+	}
+
+	if weekPeriods == 0 {
+		return &types.RetentionStatistics{Weekly: []*types.RetentionCohort{}}, nil
+	}
+
+	now := timeNow().UTC()
+	values, err := db.EventLogs.CountRetainedUsersPerWeeklyCohort(ctx, now, weekPeriods)
+	if err != nil {
+		return nil, err
+	}
+
+	cohorts := make([]*types.RetentionCohort, 0, weekPeriods)
+	cohortsByStart := make(map[int64]*types.RetentionCohort, weekPeriods)
+	for i := 0; i < weekPeriods; i++ {
+		cohort := &types.RetentionCohort{
+			StartTime:           timeutil.StartOfWeek(now, i),
+			RetainedUsersCounts: make([]int32, i+1),
+		}
+		cohorts = append(cohorts, cohort)
+		cohortsByStart[cohort.StartTime.Unix()] = cohort
+	}
+
+	for _, v := range values {
+		cohort, ok := cohortsByStart[v.CohortStart.Unix()]
+		if !ok || v.WeeksAfter < 0 || v.WeeksAfter >= len(cohort.RetainedUsersCounts) {
+			continue
+		}
+		cohort.RetainedUsersCounts[v.WeeksAfter] = int32(v.Count)
+		if v.WeeksAfter == 0 {
+			cohort.UsersCount = int32(v.Count)
+		}
+	}
+
+	return &types.RetentionStatistics{Weekly: cohorts}, nil
+}
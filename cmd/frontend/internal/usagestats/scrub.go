@@ -0,0 +1,108 @@
+package usagestats
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func init() {
+	db.RegisterEventScrubber(scrubEvent)
+}
+
+// scrubbedArgumentFields are the fields of event arguments that contain query text.
+var scrubbedArgumentFields = []string{"query", "q"}
+
+// scrubEvent removes the personal data that the "eventLogging.scrubbing" site configuration
+// selects from an event.
+func scrubEvent(e *db.Event) {
+	c := conf.Get().EventLoggingScrubbing
+	if c == nil {
+		return
+	}
+
+	if c.HashUserIDs {
+		hashUserIDs(e, c)
+	}
+	if c.StripQueryText {
+		stripQueryText(e)
+	}
+}
+
+// hashUserIDs replaces the user ID and the anonymous user ID of an event with their hashes. The
+// hashes of registered users are prefixed with db.ScrubbedUserIDPrefix, so that they are still
+// counted as registered users.
+func hashUserIDs(e *db.Event, c *schema.EventLoggingScrubbing) {
+	hash := func(id string) string {
+		mac := hmac.New(sha256.New, []byte(c.HashKey))
+		_, _ = mac.Write([]byte(id))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	if e.UserID != 0 {
+		e.AnonymousUserID = db.ScrubbedUserIDPrefix + hash(strconv.FormatUint(uint64(e.UserID), 10))
+		e.UserID = 0
+	} else if e.AnonymousUserID != "" {
+		e.AnonymousUserID = hash(e.AnonymousUserID)
+	}
+}
+
+// stripQueryText removes the query string and fragment of the URL of an event and the
+// scrubbedArgumentFields of its argument, at any depth.
+func stripQueryText(e *db.Event) {
+	if e.URL != "" {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			e.URL = ""
+		} else {
+			u.RawQuery, u.Fragment = "", ""
+			e.URL = u.String()
+		}
+	}
+
+	if len(e.Argument) == 0 {
+		return
+	}
+
+	// Numbers are decoded as json.Number, so that they are encoded unchanged and the
+	// aggregations over numeric fields still work.
+	var argument interface{}
+	d := json.NewDecoder(bytes.NewReader(e.Argument))
+	d.UseNumber()
+	if err := d.Decode(&argument); err != nil {
+		e.Argument = nil
+		return
+	}
+
+	scrubbed, err := json.Marshal(removeFields(argument))
+	if err != nil {
+		e.Argument = nil
+		return
+	}
+	e.Argument = scrubbed
+}
+
+func removeFields(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, f := range scrubbedArgumentFields {
+			delete(v, f)
+		}
+		for k, w := range v {
+			v[k] = removeFields(w)
+		}
+	case []interface{}:
+		for i, w := range v {
+			v[i] = removeFields(w)
+		}
+	}
+	return v
+}
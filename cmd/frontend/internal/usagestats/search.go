@@ -0,0 +1,456 @@
+package usagestats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// SearchLatencyEventPrefix is the prefix of the names of the events that record the latency
+// of a search. The name of the type of the search is appended to it, e.g. "literal".
+const SearchLatencyEventPrefix = "search.latencies."
+
+// ReposCountField is the field of the arguments of search latency events that contains the
+// number of repositories that were searched.
+const ReposCountField = "reposCount"
+
+// FiltersField is the field of the arguments of search latency events that contains the
+// filters of the query, sorted and separated by commas, e.g. "file,repo".
+const FiltersField = "filters"
+
+// SearchLatencyFilters are the filters of queries that are recorded by LogSearchLatency.
+var SearchLatencyFilters = []string{"repo", "file", "lang", "type"}
+
+var searchLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "src",
+	Subsystem: "search",
+	Name:      "latency_seconds",
+	Help:      "Latency of the searches recorded in the event logs, by type of search.",
+	Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+}, []string{"type"})
+
+func init() {
+	prometheus.MustRegister(searchLatency)
+}
+
+// LogSearchLatency logs the latency of a search of the given type (which must be the lowercase
+// name of a field of types.SearchTypeLatency) by the given user, which searched reposCount
+// repositories with a query that used the given SearchLatencyFilters. The latency is also
+// observed by the src_search_latency_seconds histogram, so that regressions can be alerted on
+// before the daily percentiles are aggregated.
+func LogSearchLatency(userID int32, searchType string, durationMs int64, reposCount int, filters []string) error {
+	searchLatency.WithLabelValues(searchType).Observe(float64(durationMs) / 1000)
+
+	sorted := append([]string(nil), filters...)
+	sort.Strings(sorted)
+
+	argument, err := json.Marshal(map[string]interface{}{
+		DurationField:   durationMs,
+		ReposCountField: reposCount,
+		FiltersField:    strings.Join(sorted, ","),
+	})
+	if err != nil {
+		return err
+	}
+	return LogBackendEvent(userID, SearchLatencyEventPrefix+searchType, argument)
+}
+
+// SearchLatencyStatisticsOptions contains options for the number of daily, weekly, and monthly
+// periods in which to calculate search latency percentiles.
+type SearchLatencyStatisticsOptions struct {
+	DayPeriods   *int
+	WeekPeriods  *int
+	MonthPeriods *int
+
+	// DayRange, if set, selects the daily periods instead of DayPeriods.
+	DayRange *DayRange
+}
+
+func (opt *SearchLatencyStatisticsOptions) dayPeriods(now time.Time) (time.Time, int) {
+	return dayPeriods(now, opt.DayPeriods, opt.DayRange)
+}
+
+// GetSearchLatencyStatistics returns the latency percentiles of the current site's searches,
+// by type of search and by the number of repositories searched.
+func GetSearchLatencyStatistics(ctx context.Context, opt *SearchLatencyStatisticsOptions) (*types.SearchLatencyStatistics, error) {
+	var (
+		now          = timeNow().UTC()
+		dayEnd       = now
+		dayPeriods   = defaultDays
+		weekPeriods  = defaultWeeks
+		monthPeriods = defaultMonths
+	)
+
+	if opt != nil {
+		dayEnd, dayPeriods = opt.dayPeriods(now)
+		if opt.WeekPeriods != nil {
+			weekPeriods = minIntOrZero(maxStorageDays/7, *opt.WeekPeriods)
+		}
+		if opt.MonthPeriods != nil {
+			monthPeriods = minIntOrZero(maxStorageDays/31, *opt.MonthPeriods)
+		}
+	}
+
+	daily, err := searchLatencies(ctx, db.Daily, dayEnd, dayPeriods)
+	if err != nil {
+		return nil, err
+	}
+	weekly, err := searchLatencies(ctx, db.Weekly, now, weekPeriods)
+	if err != nil {
+		return nil, err
+	}
+	monthly, err := searchLatencies(ctx, db.Monthly, now, monthPeriods)
+	if err != nil {
+		return nil, err
+	}
+	return &types.SearchLatencyStatistics{
+		Daily:   daily,
+		Weekly:  weekly,
+		Monthly: monthly,
+	}, nil
+}
+
+// searchLatencies returns the latencies of the given number of periods, the last of which
+// contains now.
+func searchLatencies(ctx context.Context, periodType db.PeriodType, now time.Time, periods int) ([]*types.SearchLatencyPeriod, error) {
+	if periods == 0 {
+		return []*types.SearchLatencyPeriod{}, nil
+	}
+
+	latencyPeriods := []*types.SearchLatencyPeriod{}
+	for i := 0; i < periods; i++ {
+		latencyPeriods = append(latencyPeriods, &types.SearchLatencyPeriod{
+			Latencies: &types.SearchTypeLatency{
+				Literal:    &types.SearchLatency{},
+				Regexp:     &types.SearchLatency{},
+				Structural: &types.SearchLatency{},
+				File:       &types.SearchLatency{},
+				Repo:       &types.SearchLatency{},
+				Diff:       &types.SearchLatency{},
+				Commit:     &types.SearchLatency{},
+				Symbol:     &types.SearchLatency{},
+			},
+			LatenciesByScope: &types.SearchScopeLatency{
+				SingleRepo: &types.SearchLatency{},
+				FewRepos:   &types.SearchLatency{},
+				Global:     &types.SearchLatency{},
+			},
+		})
+	}
+
+	if periodType == db.Daily {
+		ok, err := aggregatedSearchLatencies(ctx, now, latencyPeriods)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return latencyPeriods, nil
+		}
+	}
+
+	for _, f := range searchLatencyFilters() {
+		percentiles, err := db.EventLogs.PercentilesPerPeriod(ctx, periodType, now, periods, DurationField, DurationPercentiles, f.opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, p := range percentiles {
+			latencyPeriods[i].StartTime = p.Start
+			f.get(latencyPeriods[i]).P50 = p.Values[0]
+			f.get(latencyPeriods[i]).P90 = p.Values[1]
+			f.get(latencyPeriods[i]).P99 = p.Values[2]
+		}
+	}
+
+	return latencyPeriods, nil
+}
+
+// aggregatedSearchLatencies fills the given daily periods, which end on the day of end, with
+// the latencies aggregated by AggregateSearchLatencies. Only the current day is computed from
+// the event logs. It returns false if the latencies of a past day weren't aggregated yet, in
+// which case all of them have to be computed from the event logs.
+func aggregatedSearchLatencies(ctx context.Context, end time.Time, latencyPeriods []*types.SearchLatencyPeriod) (bool, error) {
+	now := timeNow().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	filters := searchLatencyFilters()
+
+	// The current day isn't aggregated yet. It's the first period if the periods end today.
+	first := 0
+	if last.Equal(today) {
+		first = 1
+	}
+
+	if len(latencyPeriods) > first {
+		ls, err := db.AggregatedSearchLatencies.List(ctx, last.AddDate(0, 0, 1-len(latencyPeriods)), last.AddDate(0, 0, -first))
+		if err != nil {
+			return false, err
+		}
+
+		byDayAndFilter := make(map[string]*db.AggregatedSearchLatency, len(ls))
+		for _, l := range ls {
+			byDayAndFilter[l.Day.Format("2006-01-02")+" "+l.Filter] = l
+		}
+
+		for i := first; i < len(latencyPeriods); i++ {
+			day := last.AddDate(0, 0, -i)
+			for _, f := range filters {
+				l, ok := byDayAndFilter[day.Format("2006-01-02")+" "+f.name]
+				if !ok {
+					return false, nil
+				}
+				latencyPeriods[i].StartTime = day
+				f.get(latencyPeriods[i]).P50 = l.P50
+				f.get(latencyPeriods[i]).P90 = l.P90
+				f.get(latencyPeriods[i]).P99 = l.P99
+			}
+		}
+	}
+
+	if first == 0 {
+		return true, nil
+	}
+
+	for _, f := range filters {
+		percentiles, err := db.EventLogs.PercentilesPerPeriod(ctx, db.Daily, now, 1, DurationField, DurationPercentiles, f.opt)
+		if err != nil {
+			return false, err
+		}
+
+		for _, p := range percentiles {
+			latencyPeriods[0].StartTime = p.Start
+			f.get(latencyPeriods[0]).P50 = p.Values[0]
+			f.get(latencyPeriods[0]).P90 = p.Values[1]
+			f.get(latencyPeriods[0]).P99 = p.Values[2]
+		}
+	}
+
+	return true, nil
+}
+
+// AggregateSearchLatencies stores the daily latency percentiles of the days that ended since
+// it last ran, so that GetSearchLatencyStatistics doesn't have to compute them from the
+// event logs. On its first run, all the days for which events are stored are aggregated. It
+// returns the number of aggregated latencies that were stored.
+func AggregateSearchLatencies(ctx context.Context) (int, error) {
+	now := timeNow().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+
+	latest, err := db.AggregatedSearchLatencies.LatestDay(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	days := maxStorageDays
+	if !latest.IsZero() {
+		days = minIntOrZero(maxStorageDays, int(yesterday.Sub(latest)/(24*time.Hour)))
+	}
+	if days == 0 {
+		return 0, nil
+	}
+
+	var ls []*db.AggregatedSearchLatency
+	for _, f := range searchLatencyFilters() {
+		percentiles, err := db.EventLogs.PercentilesPerPeriod(ctx, db.Daily, yesterday, days, DurationField, DurationPercentiles, f.opt)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, p := range percentiles {
+			ls = append(ls, &db.AggregatedSearchLatency{
+				Day:    p.Start,
+				Filter: f.name,
+				P50:    p.Values[0],
+				P90:    p.Values[1],
+				P99:    p.Values[2],
+			})
+		}
+	}
+
+	if err := db.AggregatedSearchLatencies.Upsert(ctx, ls); err != nil {
+		return 0, err
+	}
+	return len(ls), db.AggregatedSearchLatencies.DeleteBefore(ctx, yesterday.AddDate(0, 0, -maxStorageDays))
+}
+
+// SearchFilterLatencyStatisticsOptions contains options for the number of days over which the
+// latencies of searches are aggregated by the filters of their queries.
+type SearchFilterLatencyStatisticsOptions struct {
+	Days *int
+}
+
+// GetSearchFilterLatencyStatistics returns the number and the latency percentiles of searches
+// by the combination of SearchLatencyFilters their queries used, ordered by decreasing number
+// of searches. Searches of several types are counted once for each type.
+func GetSearchFilterLatencyStatistics(ctx context.Context, opt *SearchFilterLatencyStatisticsOptions) ([]*types.SearchFilterLatency, error) {
+	days := defaultDays
+	if opt != nil && opt.Days != nil {
+		days = minIntOrZero(maxStorageDays, *opt.Days)
+	}
+
+	now := timeNow().UTC()
+	groups, err := db.EventLogs.PercentilesByArgumentFields(ctx, now.Add(-time.Duration(days)*24*time.Hour), now, []string{FiltersField}, DurationField, DurationPercentiles, &db.EventFilterOptions{
+		ByEventNamePrefix: SearchLatencyEventPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*types.SearchFilterLatency, 0, len(groups))
+	for _, g := range groups {
+		filters := []string{}
+		if g.Values[0] != "" {
+			filters = strings.Split(g.Values[0], ",")
+		}
+		stats = append(stats, &types.SearchFilterLatency{
+			Filters:       filters,
+			SearchesCount: int32(g.EventsCount),
+			Latency: &types.SearchLatency{
+				P50: g.Percentiles[0],
+				P90: g.Percentiles[1],
+				P99: g.Percentiles[2],
+			},
+		})
+	}
+	return stats, nil
+}
+
+// SlowestSearchesOptions selects the searches returned by GetSlowestSearches.
+type SlowestSearchesOptions struct {
+	// Day is the day (in UTC) on which the searches ran.
+	Day time.Time
+	// SearchType is the type of the searches, e.g. "literal" (see LogSearchLatency).
+	SearchType string
+	// First is the number of searches returned. It defaults to defaultSlowestSearches and is
+	// at most maxSlowestSearches.
+	First *int
+}
+
+const (
+	defaultSlowestSearches = 10
+	maxSlowestSearches     = 100
+)
+
+// GetSlowestSearches returns the slowest searches of a type on a day, slowest first, as
+// examples of the searches behind the daily latency percentiles of the type. The users that
+// ran them are only told apart from each other.
+func GetSlowestSearches(ctx context.Context, opt *SlowestSearchesOptions) ([]*types.SlowSearch, error) {
+	known := false
+	for _, f := range searchLatencyFilters() {
+		known = known || f.name == "type:"+opt.SearchType
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown search type %q", opt.SearchType)
+	}
+
+	first := defaultSlowestSearches
+	if opt.First != nil {
+		first = minIntOrZero(maxSlowestSearches, *opt.First)
+	}
+
+	day := opt.Day.UTC()
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	events, err := db.EventLogs.ListTopByArgumentField(ctx, day, day.AddDate(0, 0, 1), DurationField, first, &db.EventFilterOptions{
+		ByEventName: SearchLatencyEventPrefix + opt.SearchType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// users maps the IDs of the users (or the anonymous user IDs) to their pseudonyms.
+	users := map[string]string{}
+	searches := make([]*types.SlowSearch, 0, len(events))
+	for _, e := range events {
+		var argument struct {
+			DurationMs int32  `json:"durationMs"`
+			ReposCount int32  `json:"reposCount"`
+			Filters    string `json:"filters"`
+		}
+		if err := json.Unmarshal([]byte(e.Argument), &argument); err != nil {
+			return nil, err
+		}
+
+		id := e.AnonymousUserID
+		if e.UserID != nil && *e.UserID != 0 {
+			id = strconv.FormatInt(int64(*e.UserID), 10)
+		}
+		user, ok := users[id]
+		if !ok {
+			user = fmt.Sprintf("user %d", len(users)+1)
+			users[id] = user
+		}
+
+		filters := []string{}
+		if argument.Filters != "" {
+			filters = strings.Split(argument.Filters, ",")
+		}
+
+		searches = append(searches, &types.SlowSearch{
+			Timestamp:  e.Timestamp.UTC(),
+			DurationMs: argument.DurationMs,
+			ReposCount: argument.ReposCount,
+			Filters:    filters,
+			User:       user,
+		})
+	}
+	return searches, nil
+}
+
+// searchLatencyFilter selects the search latency events whose percentiles are stored in one
+// of the latencies of a types.SearchLatencyPeriod.
+type searchLatencyFilter struct {
+	// name identifies the filter in the aggregated_search_latencies table.
+	name string
+	opt  *db.EventFilterOptions
+	get  func(p *types.SearchLatencyPeriod) *types.SearchLatency
+}
+
+func searchLatencyFilters() []searchLatencyFilter {
+	filters := []searchLatencyFilter{}
+	for _, searchType := range []struct {
+		name string
+		get  func(p *types.SearchLatencyPeriod) *types.SearchLatency
+	}{
+		{"literal", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Literal }},
+		{"regexp", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Regexp }},
+		{"structural", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Structural }},
+		{"file", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.File }},
+		{"repo", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Repo }},
+		{"diff", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Diff }},
+		{"commit", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Commit }},
+		{"symbol", func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.Latencies.Symbol }},
+	} {
+		filters = append(filters, searchLatencyFilter{
+			name: "type:" + searchType.name,
+			opt:  &db.EventFilterOptions{ByEventName: SearchLatencyEventPrefix + searchType.name},
+			get:  searchType.get,
+		})
+	}
+	for _, scope := range []struct {
+		name     string
+		min, max int
+		get      func(p *types.SearchLatencyPeriod) *types.SearchLatency
+	}{
+		{"single_repo", 1, 1, func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.LatenciesByScope.SingleRepo }},
+		{"few_repos", 2, 9, func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.LatenciesByScope.FewRepos }},
+		{"global", 10, 0, func(p *types.SearchLatencyPeriod) *types.SearchLatency { return p.LatenciesByScope.Global }},
+	} {
+		filters = append(filters, searchLatencyFilter{
+			name: "scope:" + scope.name,
+			opt: &db.EventFilterOptions{
+				ByEventNamePrefix: SearchLatencyEventPrefix,
+				ByArgumentRange:   &db.ArgumentRange{Field: ReposCountField, Min: scope.min, Max: scope.max},
+			},
+			get: scope.get,
+		})
+	}
+	return filters
+}
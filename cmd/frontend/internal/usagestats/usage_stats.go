@@ -15,6 +15,9 @@ var (
 	timeNow = time.Now
 )
 
+// codeIntelligenceActionEvents are the names of the events logged for code intelligence actions.
+var codeIntelligenceActionEvents = []string{"hover", "findReferences", "goToDefinition.preloaded", "goToDefinition"}
+
 var MockGetByUserID func(userID int32) (*types.UserUsageStatistics, error)
 
 // GetByUserID returns a single user's UserUsageStatistics.
@@ -31,7 +34,7 @@ func GetByUserID(ctx context.Context, userID int32) (*types.UserUsageStatistics,
 	if err != nil {
 		return nil, err
 	}
-	codeIntelligenceActions, err := db.EventLogs.CountByUserIDAndEventNames(ctx, userID, []string{"hover", "findReferences", "goToDefinition.preloaded", "goToDefinition"})
+	codeIntelligenceActions, err := db.EventLogs.CountByUserIDAndEventNames(ctx, userID, codeIntelligenceActionEvents)
 	if err != nil {
 		return nil, err
 	}
@@ -92,20 +95,27 @@ type SiteUsageStatisticsOptions struct {
 	DayPeriods   *int
 	WeekPeriods  *int
 	MonthPeriods *int
+
+	// DayRange, if set, selects the daily periods instead of DayPeriods.
+	DayRange *DayRange
+}
+
+func (opt *SiteUsageStatisticsOptions) dayPeriods(now time.Time) (time.Time, int) {
+	return dayPeriods(now, opt.DayPeriods, opt.DayRange)
 }
 
 // GetSiteUsageStatistics returns the current site's SiteActivity.
 func GetSiteUsageStatistics(ctx context.Context, opt *SiteUsageStatisticsOptions) (*types.SiteUsageStatistics, error) {
 	var (
+		now          = timeNow().UTC()
+		dayEnd       = now
 		dayPeriods   = defaultDays
 		weekPeriods  = defaultWeeks
 		monthPeriods = defaultMonths
 	)
 
 	if opt != nil {
-		if opt.DayPeriods != nil {
-			dayPeriods = minIntOrZero(maxStorageDays, *opt.DayPeriods)
-		}
+		dayEnd, dayPeriods = opt.dayPeriods(now)
 		if opt.WeekPeriods != nil {
 			weekPeriods = minIntOrZero(maxStorageDays/7, *opt.WeekPeriods)
 		}
@@ -114,15 +124,15 @@ func GetSiteUsageStatistics(ctx context.Context, opt *SiteUsageStatisticsOptions
 		}
 	}
 
-	daus, err := activeUsers(ctx, db.Daily, dayPeriods)
+	daus, err := activeUsers(ctx, db.Daily, dayEnd, dayPeriods)
 	if err != nil {
 		return nil, err
 	}
-	waus, err := activeUsers(ctx, db.Weekly, weekPeriods)
+	waus, err := activeUsers(ctx, db.Weekly, now, weekPeriods)
 	if err != nil {
 		return nil, err
 	}
-	maus, err := activeUsers(ctx, db.Monthly, monthPeriods)
+	maus, err := activeUsers(ctx, db.Monthly, now, monthPeriods)
 	if err != nil {
 		return nil, err
 	}
@@ -133,23 +143,23 @@ func GetSiteUsageStatistics(ctx context.Context, opt *SiteUsageStatisticsOptions
 	}, nil
 }
 
-// activeUsers returns counts of active users in the given number of days, weeks, or months, as selected (including the current, partially completed period).
-func activeUsers(ctx context.Context, periodType db.PeriodType, periods int) ([]*types.SiteActivityPeriod, error) {
+// activeUsers returns counts of active users in the given number of days, weeks, or months, as selected (including the period that contains now).
+func activeUsers(ctx context.Context, periodType db.PeriodType, now time.Time, periods int) ([]*types.SiteActivityPeriod, error) {
 	if periods == 0 {
 		return []*types.SiteActivityPeriod{}, nil
 	}
 
-	uniqueUsers, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, timeNow().UTC(), periods, nil)
+	uniqueUsers, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, now, periods, nil)
 	if err != nil {
 		return nil, err
 	}
-	registeredUniqueUsers, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, timeNow().UTC(), periods, &db.CountUniqueUsersOptions{
+	registeredUniqueUsers, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, now, periods, &db.CountUniqueUsersOptions{
 		RegisteredOnly: true,
 	})
 	if err != nil {
 		return nil, err
 	}
-	integrationUniqueUsers, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, timeNow().UTC(), periods, &db.CountUniqueUsersOptions{
+	integrationUniqueUsers, err := db.EventLogs.CountUniqueUsersPerPeriod(ctx, periodType, now, periods, &db.CountUniqueUsersOptions{
 		IntegrationOnly: true,
 	})
 	if err != nil {
@@ -2,14 +2,18 @@ package usagestats
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestUserUsageStatistics_None(t *testing.T) {
@@ -373,6 +377,377 @@ func TestUserUsageStatistics_DAUs_WAUs_MAUs(t *testing.T) {
 	}
 }
 
+func TestClientUsageStatistics(t *testing.T) {
+	ctx := context.Background()
+
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	setupForTest(t)
+
+	// hardcode "now" as 2018/03/31
+	now := time.Date(2018, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	mockTimeNow(now.AddDate(0, 0, -3))
+	for _, e := range []struct {
+		userID   int32
+		cookieID string
+		source   string
+	}{
+		{1, "test-cookie-id-1", "WEB"},
+		{1, "test-cookie-id-1", "CODEHOSTINTEGRATION"},
+		{2, "test-cookie-id-2", "WEB"},
+		{2, "test-cookie-id-2", "EDITOR"},
+		{0, "068ccbfa-8529-4fa7-859e-2c3514af2434", "API"},
+		{3, "test-cookie-id-3", "BACKEND"},
+	} {
+		err := logLocalEvent(ctx, "ViewBlob", "https://sourcegraph.example.com/", e.userID, e.cookieID, e.source, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockTimeNow(now)
+	days, weeks, months := 1, 1, 2
+	have, err := GetClientUsageStatistics(ctx, &ClientUsageStatisticsOptions{
+		DayPeriods:   &days,
+		WeekPeriods:  &weeks,
+		MonthPeriods: &months,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active := func(start time.Time) *types.ClientUsagePeriod {
+		return &types.ClientUsagePeriod{
+			StartTime:                 start,
+			WebUserCount:              2,
+			BrowserExtensionUserCount: 1,
+			EditorUserCount:           1,
+			APIUserCount:              1,
+		}
+	}
+	want := &types.ClientUsageStatistics{
+		DAUs: []*types.ClientUsagePeriod{
+			{StartTime: time.Date(2018, 3, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		WAUs: []*types.ClientUsagePeriod{
+			active(time.Date(2018, 3, 25, 0, 0, 0, 0, time.UTC)),
+		},
+		MAUs: []*types.ClientUsagePeriod{
+			active(time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)),
+			{StartTime: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+}
+
+func TestClientUsageStatistics_DayRange(t *testing.T) {
+	ctx := context.Background()
+
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	setupForTest(t)
+
+	// hardcode "now" as 2018/03/31
+	now := time.Date(2018, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	mockTimeNow(now.AddDate(0, 0, -3))
+	if err := logLocalEvent(ctx, "ViewBlob", "https://sourcegraph.example.com/", 1, "test-cookie-id-1", "WEB", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mockTimeNow(now)
+	from, to := time.Date(2018, 3, 20, 0, 0, 0, 0, time.UTC), time.Date(2018, 3, 29, 18, 0, 0, 0, time.UTC)
+	first, zero := 2, 0
+	have, err := GetClientUsageStatistics(ctx, &ClientUsageStatisticsOptions{
+		DayRange:     &DayRange{From: &from, To: &to, First: &first},
+		WeekPeriods:  &zero,
+		MonthPeriods: &zero,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &types.ClientUsageStatistics{
+		DAUs: []*types.ClientUsagePeriod{
+			{StartTime: time.Date(2018, 3, 29, 0, 0, 0, 0, time.UTC)},
+			{StartTime: time.Date(2018, 3, 28, 0, 0, 0, 0, time.UTC), WebUserCount: 1},
+		},
+		WAUs: []*types.ClientUsagePeriod{},
+		MAUs: []*types.ClientUsagePeriod{},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+}
+
+func TestCodeIntelQueryStatistics(t *testing.T) {
+	ctx := context.Background()
+
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	setupForTest(t)
+
+	now := time.Date(2018, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	for _, e := range []struct {
+		daysAgo  int
+		userID   int32
+		name     string
+		indexer  string
+		language string
+	}{
+		{1, 1, "definitions", "lsif-go", "Go"},
+		{1, 1, "hover", "lsif-go", "Go"},
+		{2, 2, "references", "lsif-go", "Go"},
+		{2, 2, "hover", "lsif-tsc", "TypeScript"},
+		// Not a precise code intel query
+		{1, 3, "", "lsif-go", "Go"},
+		// Outside of the requested days
+		{10, 4, "hover", "lsif-tsc", "TypeScript"},
+	} {
+		argument, err := json.Marshal(map[string]interface{}{
+			DurationField: 10,
+			IndexerField:  e.indexer,
+			LanguageField: e.language,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := "codeintel.lsifHover"
+		if e.name != "" {
+			name = CodeIntelQueryEventPrefix + e.name
+		}
+
+		mockTimeNow(now.AddDate(0, 0, -e.daysAgo))
+		if err := logLocalEvent(ctx, name, "", e.userID, "", "BACKEND", argument); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockTimeNow(now)
+	days := 7
+	have, err := GetCodeIntelQueryStatistics(ctx, &CodeIntelQueryStatisticsOptions{Days: &days})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*types.CodeIntelQueryStatistics{
+		{Indexer: "lsif-go", Language: "Go", UsersCount: 2, QueriesCount: 3},
+		{Indexer: "lsif-tsc", Language: "TypeScript", UsersCount: 1, QueriesCount: 1},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+}
+
+func TestCodeIntelQueryPathStatistics(t *testing.T) {
+	ctx := context.Background()
+
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	setupForTest(t)
+
+	now := time.Date(2018, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	for _, e := range []struct {
+		name       string
+		codePath   string
+		variant    string
+		durationMs int
+	}{
+		{CodeIntelQueryEventPrefix + "hover", types.CodeIntelPathPrecise, "", 10},
+		{CodeIntelQueryEventPrefix + "definitions", types.CodeIntelPathPrecise, "", 10},
+		{CodeIntelQueryEventPrefix + "hover", types.CodeIntelPathPrecise, "codeintel-a,codeintel-b", 20},
+		{CodeIntelQueryEventPrefix + "hover", types.CodeIntelPathPreciseEmpty, "", 40},
+		{CodeIntelFallbackEventName, types.CodeIntelPathFallback, "", 5},
+		{CodeIntelFallbackEventName, types.CodeIntelPathFallback, "", 5},
+		{CodeIntelFallbackEventName, types.CodeIntelPathFallback, "", 5},
+		// Logged before code paths were recorded
+		{CodeIntelQueryEventPrefix + "hover", "", "", 10},
+	} {
+		argument, err := json.Marshal(map[string]interface{}{
+			DurationField: e.durationMs,
+			CodePathField: e.codePath,
+			VariantField:  e.variant,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mockTimeNow(now.AddDate(0, 0, -1))
+		if err := logLocalEvent(ctx, e.name, "", 1, "", "BACKEND", argument); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockTimeNow(now)
+	have, err := GetCodeIntelQueryPathStatistics(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*types.CodeIntelQueryPathStatistics{
+		{
+			CodePath:     types.CodeIntelPathFallback,
+			Variant:      []string{},
+			QueriesCount: 3,
+			Latencies:    &types.CodeIntelEventLatencies{P50: 5, P90: 5, P99: 5},
+		},
+		{
+			CodePath:     types.CodeIntelPathPrecise,
+			Variant:      []string{},
+			QueriesCount: 2,
+			Latencies:    &types.CodeIntelEventLatencies{P50: 10, P90: 10, P99: 10},
+		},
+		{
+			CodePath:     types.CodeIntelPathPrecise,
+			Variant:      []string{"codeintel-a", "codeintel-b"},
+			QueriesCount: 1,
+			Latencies:    &types.CodeIntelEventLatencies{P50: 20, P90: 20, P99: 20},
+		},
+		{
+			CodePath:     types.CodeIntelPathPreciseEmpty,
+			Variant:      []string{},
+			QueriesCount: 1,
+			Latencies:    &types.CodeIntelEventLatencies{P50: 40, P90: 40, P99: 40},
+		},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+}
+
+func TestSlowestSearches(t *testing.T) {
+	ctx := context.Background()
+
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	setupForTest(t)
+
+	day := time.Date(2018, 3, 30, 0, 0, 0, 0, time.UTC)
+
+	for _, e := range []struct {
+		searchType string
+		userID     int32
+		anonUserID string
+		durationMs int
+		reposCount int
+		filters    string
+		time       time.Time
+	}{
+		{"literal", 1, "", 100, 1, "repo", day.Add(time.Hour)},
+		{"literal", 2, "", 4000, 500, "", day.Add(2 * time.Hour)},
+		{"literal", 1, "", 3000, 20, "file,lang", day.Add(3 * time.Hour)},
+		{"literal", 0, "a", 2000, 10, "", day.Add(4 * time.Hour)},
+		// Another type
+		{"regexp", 1, "", 9000, 500, "", day.Add(time.Hour)},
+		// Another day
+		{"literal", 1, "", 9000, 500, "", day.Add(-time.Hour)},
+	} {
+		argument, err := json.Marshal(map[string]interface{}{
+			DurationField:   e.durationMs,
+			ReposCountField: e.reposCount,
+			FiltersField:    e.filters,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mockTimeNow(e.time)
+		if err := logLocalEvent(ctx, SearchLatencyEventPrefix+e.searchType, "", e.userID, e.anonUserID, "BACKEND", argument); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := 3
+	have, err := GetSlowestSearches(ctx, &SlowestSearchesOptions{
+		Day:        day.Add(12 * time.Hour),
+		SearchType: "literal",
+		First:      &first,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*types.SlowSearch{
+		{Timestamp: day.Add(2 * time.Hour), DurationMs: 4000, ReposCount: 500, Filters: []string{}, User: "user 1"},
+		{Timestamp: day.Add(3 * time.Hour), DurationMs: 3000, ReposCount: 20, Filters: []string{"file", "lang"}, User: "user 2"},
+		{Timestamp: day.Add(4 * time.Hour), DurationMs: 2000, ReposCount: 10, Filters: []string{}, User: "user 3"},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("got %+v, want %+v", have, want)
+	}
+
+	if _, err := GetSlowestSearches(ctx, &SlowestSearchesOptions{Day: day, SearchType: "foo"}); err == nil {
+		t.Error("got no error for unknown search type")
+	}
+}
+
+func TestScrubEvent(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		EventLoggingScrubbing: &schema.EventLoggingScrubbing{
+			HashUserIDs:    true,
+			HashKey:        "0123456789abcdef",
+			StripQueryText: true,
+		},
+	}})
+	defer conf.Mock(nil)
+
+	newEvent := func() *db.Event {
+		return &db.Event{
+			Name:            "SearchResultsQueried",
+			URL:             "https://sourcegraph.example.com/search?q=secret#L1",
+			UserID:          1,
+			AnonymousUserID: "test-cookie-id-1",
+			Argument:        json.RawMessage(`{"durationMs":1500000,"query":"secret","code_search":{"q":"secret","type":"literal"}}`),
+		}
+	}
+
+	e := newEvent()
+	scrubEvent(e)
+
+	if e.UserID != 0 {
+		t.Errorf("got user ID %d, want 0", e.UserID)
+	}
+	if len(e.AnonymousUserID) != len(db.ScrubbedUserIDPrefix)+64 || e.AnonymousUserID[:len(db.ScrubbedUserIDPrefix)] != db.ScrubbedUserIDPrefix {
+		t.Errorf("got anonymous user ID %q, want the prefixed hash of the user ID", e.AnonymousUserID)
+	}
+	if want := "https://sourcegraph.example.com/search"; e.URL != want {
+		t.Errorf("got URL %q, want %q", e.URL, want)
+	}
+	if want := `{"code_search":{"type":"literal"},"durationMs":1500000}`; string(e.Argument) != want {
+		t.Errorf("got argument %s, want %s", e.Argument, want)
+	}
+
+	// The hashes of a user are stable, so that unique users can be counted.
+	other := newEvent()
+	scrubEvent(other)
+	if other.AnonymousUserID != e.AnonymousUserID {
+		t.Errorf("got different hashes %q and %q of the same user", other.AnonymousUserID, e.AnonymousUserID)
+	}
+
+	anonymous := newEvent()
+	anonymous.UserID = 0
+	scrubEvent(anonymous)
+	if len(anonymous.AnonymousUserID) != 64 {
+		t.Errorf("got anonymous user ID %q, want its hash", anonymous.AnonymousUserID)
+	}
+}
+
 func setupForTest(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
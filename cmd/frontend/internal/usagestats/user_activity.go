@@ -0,0 +1,46 @@
+package usagestats
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// UserActivity summarizes the activity of a user during a time span.
+type UserActivity struct {
+	SearchQueries           int
+	CodeIntelligenceActions int
+	CampaignsUpdated        int
+}
+
+// IsEmpty reports whether the user wasn't active at all.
+func (a *UserActivity) IsEmpty() bool {
+	return a.SearchQueries == 0 && a.CodeIntelligenceActions == 0 && a.CampaignsUpdated == 0
+}
+
+// GetUserActivity returns the activity of the given user in the time span [startDate, endDate).
+func GetUserActivity(ctx context.Context, userID int32, startDate, endDate time.Time) (*UserActivity, error) {
+	searchQueries, err := db.EventLogs.CountByUserIDAndEventNamesInRange(ctx, userID, []string{"SearchResultsQueried"}, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	codeIntelligenceActions, err := db.EventLogs.CountByUserIDAndEventNamesInRange(ctx, userID, codeIntelligenceActionEvents, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Campaigns are only available in enterprise, but their table always exists.
+	const q = "SELECT COUNT(*) FROM campaigns WHERE author_id = $1 AND updated_at >= $2 AND updated_at < $3"
+	var campaignsUpdated int
+	if err := dbconn.Global.QueryRowContext(ctx, q, userID, startDate, endDate).Scan(&campaignsUpdated); err != nil {
+		return nil, err
+	}
+
+	return &UserActivity{
+		SearchQueries:           searchQueries,
+		CodeIntelligenceActions: codeIntelligenceActions,
+		CampaignsUpdated:        campaignsUpdated,
+	}, nil
+}
@@ -129,6 +129,22 @@ type SiteActivityPeriod struct {
 	Stages               *Stages
 }
 
+// ClientUsageStatistics contains the number of unique users of each kind of
+// Sourcegraph client in recent days, weeks and months.
+type ClientUsageStatistics struct {
+	DAUs []*ClientUsagePeriod
+	WAUs []*ClientUsagePeriod
+	MAUs []*ClientUsagePeriod
+}
+
+type ClientUsagePeriod struct {
+	StartTime                 time.Time
+	WebUserCount              int32
+	BrowserExtensionUserCount int32
+	EditorUserCount           int32
+	APIUserCount              int32
+}
+
 type Stages struct {
 	Manage    int32 `json:"mng"`
 	Plan      int32 `json:"plan"`
@@ -174,6 +190,66 @@ type CodeIntelEventLatencies struct {
 	P99 float64
 }
 
+// CodeIntelQueryStatistics describes how much precise code intel queries are used for a
+// language, with the uploads of an indexer.
+type CodeIntelQueryStatistics struct {
+	Indexer      string
+	Language     string
+	UsersCount   int32
+	QueriesCount int32
+}
+
+// The code paths that serve code intel queries.
+const (
+	// CodeIntelPathPrecise queries are answered from the uploads of the queried file.
+	CodeIntelPathPrecise = "precise"
+	// CodeIntelPathPreciseEmpty queries are answered from the uploads of the queried file
+	// without a result, so that clients fall back to search-based code intel.
+	CodeIntelPathPreciseEmpty = "precise-empty"
+	// CodeIntelPathFallback queries find no upload of the queried file, so that clients use
+	// search-based code intel.
+	CodeIntelPathFallback = "fallback"
+)
+
+// CodeIntelQuery is a code intel query and the code path that served it.
+type CodeIntelQuery struct {
+	// Operation is one of "hover", "hovers", "definitions" or "references", or "lsif" for
+	// the lookup of the uploads of the queried file.
+	Operation string
+	CodePath  string
+	// Indexer is the name of the indexer of the closest queried upload, and Language is the
+	// language of the queried file. Both are empty if unknown.
+	Indexer  string
+	Language string
+	// Variant is the set of code intel feature flags enabled for the user, see
+	// featureflag.Variant.
+	Variant  string
+	Duration time.Duration
+}
+
+// CodeIntelQueryPathStatistics describes the code intel queries served by a code path to the
+// users of a variant of the code intel feature flags.
+type CodeIntelQueryPathStatistics struct {
+	CodePath     string
+	Variant      []string
+	QueriesCount int32
+	Latencies    *CodeIntelEventLatencies
+}
+
+// RetentionStatistics describes how many users of weekly cohorts remain active in the
+// weeks following their first activity.
+type RetentionStatistics struct {
+	Weekly []*RetentionCohort
+}
+
+// RetentionCohort is the set of users whose first activity occurred in the week starting at
+// StartTime. RetainedUsersCounts[k] is the number of those users active k weeks later.
+type RetentionCohort struct {
+	StartTime           time.Time
+	UsersCount          int32
+	RetainedUsersCounts []int32
+}
+
 type SurveyResponse struct {
 	ID        int32
 	UserID    *int32
@@ -207,8 +283,9 @@ type SearchLatencyStatistics struct {
 }
 
 type SearchLatencyPeriod struct {
-	StartTime time.Time
-	Latencies *SearchTypeLatency
+	StartTime        time.Time
+	Latencies        *SearchTypeLatency
+	LatenciesByScope *SearchScopeLatency
 }
 
 type SearchTypeLatency struct {
@@ -222,8 +299,59 @@ type SearchTypeLatency struct {
 	Symbol     *SearchLatency
 }
 
+// SearchScopeLatency breaks down the latencies of searches of all types by the number of
+// repositories they searched, to tell global searches apart from searches in a few repositories.
+type SearchScopeLatency struct {
+	SingleRepo *SearchLatency // 1 repository
+	FewRepos   *SearchLatency // 2 to 9 repositories
+	Global     *SearchLatency // 10 or more repositories
+}
+
 type SearchLatency struct {
 	P50 float64
 	P90 float64
 	P99 float64
 }
+
+// SearchFilterLatency is the number and the latencies of the searches whose queries used a
+// combination of filters, e.g. repo: and lang:.
+type SearchFilterLatency struct {
+	Filters       []string
+	SearchesCount int32
+	Latency       *SearchLatency
+}
+
+// SlowSearch is a search latency event, described by the shape of its query but not its
+// text, which isn't recorded.
+type SlowSearch struct {
+	Timestamp  time.Time
+	DurationMs int32
+	ReposCount int32
+	Filters    []string
+	// User identifies the user that ran the search among the users of the other searches
+	// returned with it, without revealing who they are.
+	User string
+}
+
+// FeatureFlag is the value of a feature flag stored in the database, which takes precedence over
+// the default value of the flag in code.
+type FeatureFlag struct {
+	Name    string
+	Enabled bool
+	// RolloutPercentage, if set, is the percentage of users for which the flag is enabled instead
+	// of Enabled.
+	RolloutPercentage *int32
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// FeatureFlagOverride enables or disables a feature flag for a user or an org, regardless of the
+// value of the flag. Exactly one of NamespaceUserID and NamespaceOrgID is set.
+type FeatureFlagOverride struct {
+	FlagName        string
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+	Enabled         bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
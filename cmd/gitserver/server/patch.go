@@ -103,7 +103,7 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 		return out, err
 	}
 
-	if req.UniqueRef {
+	if req.UniqueRef || req.FailIfRefExists {
 		refs, err := repoRemoteRefs(ctx, remoteURL, ref)
 		if err != nil {
 			log15.Error("Failed to get remote refs", "ref", ref, "err", err)
@@ -111,6 +111,12 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 			return http.StatusInternalServerError, resp
 		}
 
+		if _, ok := refs[ref]; ok && !req.UniqueRef {
+			resp.SetError(repo, "", "", errors.Errorf("ref %q already exists", ref))
+			resp.Error.RefExists = true
+			return http.StatusConflict, resp
+		}
+
 		retry := 1
 		tmp := ref
 		for {
@@ -237,6 +237,24 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 	return nil
 }
 
+// RequestReviewers adds the users with the given names as reviewers of the
+// given *Changeset. Bitbucket Server has no teams that can review pull
+// requests.
+func (s BitbucketServerSource) RequestReviewers(ctx context.Context, c *Changeset, reviewers []string) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Server pull request")
+	}
+
+	for _, r := range reviewers {
+		if err := s.client.AddPullRequestReviewer(ctx, pr, r); err != nil {
+			return errors.Wrapf(err, "adding reviewer %q", r)
+		}
+	}
+
+	return nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s BitbucketServerSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 	"github.com/sourcegraph/sourcegraph/schema"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -100,6 +101,13 @@ func newGithubSource(svc *ExternalService, c *schema.GitHubConnection, cf *httpc
 		}
 	}
 
+	client := github.NewClient(apiURL, c.Token, cli)
+	if svc != nil && svc.ID != 0 {
+		// Share the rate limit of the token with everything else in this
+		// process that uses it, such as campaigns.
+		client.RateLimit = ratelimit.DefaultBudgets.Get(svc.ID, svc.Kind, "X-").Monitor
+	}
+
 	return &GithubSource{
 		svc:              svc,
 		config:           c,
@@ -107,7 +115,7 @@ func newGithubSource(svc *ExternalService, c *schema.GitHubConnection, cf *httpc
 		excludePatterns:  excludePatterns,
 		baseURL:          baseURL,
 		githubDotCom:     githubDotCom,
-		client:           github.NewClient(apiURL, c.Token, cli),
+		client:           client,
 		searchClient:     github.NewClient(apiURL, c.Token, cli),
 		originalHostname: originalHostname,
 	}, nil
@@ -175,6 +183,12 @@ func (s GithubSource) CreateChangeset(ctx context.Context, c *Changeset) (bool,
 		exists = true
 	}
 
+	if len(c.Labels) > 0 {
+		if err := s.client.AddLabels(ctx, repo.NameWithOwner, pr.Number, c.Labels); err != nil {
+			return exists, errors.Wrap(err, "adding labels")
+		}
+	}
+
 	c.Changeset.Metadata = pr
 	c.Changeset.ExternalID = strconv.FormatInt(pr.Number, 10)
 	c.Changeset.ExternalServiceType = github.ServiceType
@@ -229,6 +243,13 @@ func (s GithubSource) LoadChangesets(ctx context.Context, cs ...*Changeset) erro
 	return nil
 }
 
+// RecommendedWaitForBackgroundOp returns the recommended wait time before
+// performing a background operation with the given rate limit cost against
+// the code host, based on the rate limit reported by its last API response.
+func (s GithubSource) RecommendedWaitForBackgroundOp(cost int) time.Duration {
+	return s.client.RateLimit.RecommendedWaitForBackgroundOp(cost)
+}
+
 // UpdateChangeset updates the given *Changeset in the code host.
 func (s GithubSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
 	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
@@ -252,6 +273,37 @@ func (s GithubSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
 	return nil
 }
 
+// RequestReviewers requests reviews of the given *Changeset from the given
+// users and teams. Teams are given as "org/team-slug" and must belong to the
+// organization owning the repository.
+func (s GithubSource) RequestReviewers(ctx context.Context, c *Changeset, reviewers []string) error {
+	repo := c.Repo.Metadata.(*github.Repository)
+	owner, _, err := github.SplitRepositoryNameWithOwner(repo.NameWithOwner)
+	if err != nil {
+		return errors.Wrap(err, "getting repo owner and name")
+	}
+
+	number, err := strconv.ParseInt(c.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "parsing changeset external id")
+	}
+
+	var users, teams []string
+	for _, r := range reviewers {
+		i := strings.Index(r, "/")
+		if i < 0 {
+			users = append(users, r)
+			continue
+		}
+		if org := r[:i]; !strings.EqualFold(org, owner) {
+			return errors.Errorf("team %q does not belong to the owner %q of the repository", r, owner)
+		}
+		teams = append(teams, r[i+1:])
+	}
+
+	return s.client.RequestReviews(ctx, repo.NameWithOwner, number, users, teams)
+}
+
 // GetRepo returns the Github repository with the given name and owner
 // ("org/repo-name")
 func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo, error) {
@@ -104,6 +104,9 @@ type ChangesetSource interface {
 
 	// UpdateChangeset can update Changesets.
 	UpdateChangeset(context.Context, *Changeset) error
+	// RequestReviewers requests reviews of the Changeset from the given
+	// reviewers, in addition to the ones already requested.
+	RequestReviewers(context.Context, *Changeset, []string) error
 }
 
 // ChangesetsNotFoundError is returned by LoadChangesets if any of the passed
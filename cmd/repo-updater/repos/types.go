@@ -31,6 +31,10 @@ type Changeset struct {
 	HeadRef string
 	BaseRef string
 
+	// Labels are added to the Changeset when it's created, by the sources of
+	// code hosts that support labels.
+	Labels []string
+
 	*a8n.Changeset
 	*Repo
 }
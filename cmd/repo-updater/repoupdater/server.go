@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -59,6 +60,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
 	mux.HandleFunc("/status-messages", s.handleStatusMessages)
+	mux.HandleFunc("/rate-limits", s.handleRateLimits)
 	return mux
 }
 
@@ -514,6 +516,25 @@ func (s *Server) handleStatusMessages(w http.ResponseWriter, r *http.Request) {
 	respond(w, http.StatusOK, resp)
 }
 
+func (s *Server) handleRateLimits(w http.ResponseWriter, r *http.Request) {
+	budgets := ratelimit.DefaultBudgets.All()
+	resp := protocol.RateLimitsResponse{
+		RateLimits: make([]protocol.RateLimit, 0, len(budgets)),
+	}
+	for _, b := range budgets {
+		resp.RateLimits = append(resp.RateLimits, protocol.RateLimit{
+			ExternalServiceID: b.ExternalServiceID,
+			Kind:              b.Kind,
+			Known:             b.Known,
+			Limit:             b.Limit,
+			Remaining:         b.Remaining,
+			ResetAt:           b.ResetAt,
+			RetryAt:           b.RetryAt,
+		})
+	}
+	respond(w, http.StatusOK, resp)
+}
+
 func (s *Server) computeNotClonedCount(ctx context.Context) (uint64, error) {
 	// Coarse lock so we single flight the expensive computation.
 	s.notClonedCountMu.Lock()
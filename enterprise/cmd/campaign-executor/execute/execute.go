@@ -0,0 +1,407 @@
+// Package execute is a service exposing an API to run the steps of campaign
+// specs in repositories and compute the resulting diffs. It streams back the
+// output of the steps and the diff with JSON lines.
+//
+// The archive of the repository is fetched from gitserver and extracted into
+// a temporary Git working tree. Its Git directory is kept outside of the
+// working tree, so that steps can't change the configuration of the Git
+// commands run by the executor. Each step runs in a Docker container with the
+// working tree mounted as its working directory. Containers have no network
+// access, no capabilities and limited memory, CPU and processes. The diff is
+// computed with Git after the last step, without prefixes, so that it can be
+// applied like the patches uploaded by src-cli.
+//
+// There is currently no concept of authorization, so only the frontend should
+// be able to reach the service.
+package execute
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/campaign-executor/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Service runs the steps of campaign specs in repositories.
+type Service struct {
+	// FetchTar returns the tar archive of a repository at a commit.
+	FetchTar func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error)
+
+	// Dir is the directory in which the working trees are created. When the
+	// Docker daemon runs on another host, it must be shared with that host at
+	// the same path, since it's mounted into the containers.
+	Dir string
+
+	// Memory and CPUs are the limits of the container of each step, in the
+	// formats of the --memory and --cpus flags of docker run.
+	Memory string
+	CPUs   string
+
+	// StepTimeout is the maximum duration of a step.
+	StepTimeout time.Duration
+
+	Log log15.Logger
+}
+
+// ServeHTTP handles HTTP based execution requests.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	running.Inc()
+	defer running.Dec()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var p protocol.Request
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, "failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := validateParams(&p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	var mu sync.Mutex
+	emit := func(e protocol.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := json.NewEncoder(w).Encode(e); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	diff, err := s.execute(r.Context(), &p, emit)
+	if err != nil {
+		// The status was already sent, so the error is the last event.
+		_ = emit(protocol.Event{Error: err.Error()})
+		return
+	}
+	_ = emit(protocol.Event{Diff: &diff})
+}
+
+func validateParams(p *protocol.Request) error {
+	if p.Repo == "" {
+		return errors.New("Repo must be non-empty")
+	}
+	// Surprisingly this is the same sanity check used in the git source.
+	if len(p.Commit) != 40 {
+		return errors.Errorf("Commit must be resolved (Commit=%q)", p.Commit)
+	}
+	if len(p.Steps) == 0 {
+		return errors.New("Steps must be non-empty")
+	}
+	for i, step := range p.Steps {
+		if step.Image == "" {
+			return errors.Errorf("Image of step %d must be non-empty", i+1)
+		}
+		// 🚨 SECURITY: The image is passed to docker run after the flags that
+		// sandbox the container, so it must not be parsed as a flag itself.
+		if strings.HasPrefix(step.Image, "-") || !imageReferencePattern.MatchString(step.Image) {
+			return errors.Errorf("Image of step %d must be an image reference (Image=%q)", i+1, step.Image)
+		}
+	}
+	return nil
+}
+
+// imageReferencePattern matches Docker image references: an optional registry
+// host, a lowercase repository path, an optional tag and an optional digest.
+var imageReferencePattern = regexp.MustCompile(`^` +
+	`(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?` +
+	`(?:@sha256:[0-9a-f]{64})?$`)
+
+// maxLogSize is the maximum size of the output of the steps of an execution
+// that is emitted. The rest is discarded.
+const maxLogSize = 1 << 20
+
+func (s *Service) execute(ctx context.Context, p *protocol.Request, emit func(protocol.Event) error) (diff string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Execute")
+	ext.Component.Set(span, "service")
+	span.SetTag("repo", p.Repo)
+	span.SetTag("commit", p.Commit)
+	span.SetTag("steps", len(p.Steps))
+	defer func(start time.Time) {
+		code := "200"
+		if ctx.Err() == context.Canceled {
+			code = "canceled"
+		} else if err != nil {
+			code = "error"
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		requestTotal.WithLabelValues(code).Inc()
+		span.Finish()
+
+		if s.Log != nil {
+			s.Log.Debug("execute request", "repo", p.Repo, "commit", p.Commit, "steps", len(p.Steps), "code", code, "duration", time.Since(start), "err", err)
+		}
+	}(time.Now())
+
+	tmp, err := ioutil.TempDir(s.Dir, "campaign-executor-")
+	if err != nil {
+		return "", errors.Wrap(err, "creating working tree")
+	}
+	defer os.RemoveAll(tmp)
+
+	g := &gitRepo{dir: filepath.Join(tmp, "git"), workTree: filepath.Join(tmp, "work")}
+	if err := os.Mkdir(g.workTree, 0755); err != nil {
+		return "", errors.Wrap(err, "creating working tree")
+	}
+
+	if err := s.prepareWorkingTree(ctx, p, g); err != nil {
+		return "", err
+	}
+
+	logs := &logWriter{emit: emit, remaining: maxLogSize}
+	for i, step := range p.Steps {
+		if err := emit(protocol.Event{Log: fmt.Sprintf("Running step %d: %s %s\n", i+1, step.Image, strings.Join(step.Args, " "))}); err != nil {
+			return "", err
+		}
+		if err := s.runStep(ctx, g.workTree, step, logs); err != nil {
+			return "", errors.Wrapf(err, "step %d", i+1)
+		}
+	}
+
+	// Files that are ignored by Git, e.g. dependencies installed by a step,
+	// aren't part of the diff.
+	if _, err := g.run(ctx, "add", "--all"); err != nil {
+		return "", err
+	}
+	out, err := g.run(ctx, "diff", "--cached", "--no-prefix", "--binary")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// prepareWorkingTree extracts the archive of the repository into the working
+// tree of g and commits its contents, so that the changes of the steps can be
+// diffed.
+func (s *Service) prepareWorkingTree(ctx context.Context, p *protocol.Request, g *gitRepo) error {
+	rc, err := s.FetchTar(ctx, p.GitserverRepo(), p.Commit)
+	if err != nil {
+		return errors.Wrap(err, "fetching archive")
+	}
+	defer rc.Close()
+
+	if err := untar(rc, g.workTree); err != nil {
+		return errors.Wrap(err, "extracting archive")
+	}
+
+	if _, err := g.run(ctx, "init", "--quiet"); err != nil {
+		return err
+	}
+	// Tracked files that match .gitignore are in the archive too.
+	if _, err := g.run(ctx, "add", "--all", "--force"); err != nil {
+		return err
+	}
+	_, err = g.run(ctx, "-c", "user.name=Sourcegraph", "-c", "user.email=campaigns@sourcegraph.com",
+		"commit", "--quiet", "--no-verify", "--allow-empty", "--message", string(p.Commit))
+	return err
+}
+
+// runStep runs the step in a Docker container, with dir mounted as its
+// working directory, and writes its output to w.
+func (s *Service) runStep(ctx context.Context, dir string, step protocol.Step, w *logWriter) error {
+	ctx, cancel := context.WithTimeout(ctx, s.StepTimeout)
+	defer cancel()
+
+	name := "campaign-executor-" + filepath.Base(filepath.Dir(dir))
+	args := []string{
+		"run", "--rm", "--name", name,
+		"--network", "none",
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		"--pids-limit", "1024",
+		"--memory", s.Memory,
+		"--cpus", s.CPUs,
+		// The files written by the step must be owned by the executor,
+		// which removes the working tree afterwards.
+		"--user", strconv.Itoa(os.Getuid()) + ":" + strconv.Itoa(os.Getgid()),
+		"--mount", "type=bind,source=" + dir + ",target=/work",
+		"--workdir", "/work",
+		step.Image,
+	}
+	args = append(args, step.Args...)
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		// Killing the docker client doesn't stop the container.
+		if out, err := exec.Command("docker", "rm", "--force", name).CombinedOutput(); err != nil {
+			log15.Warn("campaign-executor: removing container", "name", name, "output", string(out), "err", err)
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Errorf("timed out after %s", s.StepTimeout)
+		}
+		return ctx.Err()
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if e, ok := err.(*exec.ExitError); ok {
+		return errors.Errorf("exited with status %d", e.ExitCode())
+	}
+	return err
+}
+
+// logWriter emits the output written to it as Log events, until remaining
+// bytes were emitted.
+type logWriter struct {
+	emit      func(protocol.Event) error
+	remaining int
+	err       error
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	if w.err == nil && w.remaining > 0 {
+		log := p
+		if len(log) > w.remaining {
+			log = log[:w.remaining]
+		}
+		w.remaining -= len(log)
+		w.err = w.emit(protocol.Event{Log: string(log)})
+		if w.err == nil && w.remaining == 0 {
+			w.err = w.emit(protocol.Event{Log: "\n(the rest of the output was discarded)\n"})
+		}
+	}
+	// The step keeps running when the client went away, until the request
+	// context is canceled.
+	return len(p), nil
+}
+
+// gitRepo is a Git repository whose Git directory is separate from its
+// working tree.
+type gitRepo struct {
+	dir, workTree string
+}
+
+// run runs a Git command in the repository and returns its output.
+func (g *gitRepo) run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.workTree
+	cmd.Env = append(os.Environ(), "GIT_DIR="+g.dir, "GIT_WORK_TREE="+g.workTree)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git %s: %s", args[0], stderr.String())
+	}
+	return out, nil
+}
+
+// untar extracts the directories, regular files and symlinks of the given
+// tar archive into dir.
+func untar(r io.Reader, dir string) error {
+	// Symlinks are created last, so that no other entry is extracted through
+	// them, possibly outside of dir.
+	var symlinks []*tar.Header
+	defer func() {
+		for _, h := range symlinks {
+			path := filepath.Join(dir, filepath.Clean(h.Name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				log15.Warn("campaign-executor: creating symlink", "name", h.Name, "err", err)
+				continue
+			}
+			if err := os.Symlink(h.Linkname, path); err != nil {
+				log15.Warn("campaign-executor: creating symlink", "name", h.Name, "err", err)
+			}
+		}
+	}()
+
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(h.Name)
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.Errorf("invalid path %q in archive", h.Name)
+		}
+		path := filepath.Join(dir, name)
+
+		switch h.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, os.FileMode(h.Mode)&0755|0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, h)
+		}
+	}
+}
+
+var (
+	running = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "campaign_executor_execute_running",
+		Help: "Number of executions currently running.",
+	})
+	requestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "campaign_executor_execute_request_total",
+		Help: "Number of returned execute requests.",
+	}, []string{"code"})
+)
+
+func init() {
+	prometheus.MustRegister(running)
+	prometheus.MustRegister(requestTotal)
+}
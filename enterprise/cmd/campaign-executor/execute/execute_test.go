@@ -0,0 +1,77 @@
+package execute
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/campaign-executor/protocol"
+)
+
+func TestValidateParams(t *testing.T) {
+	const commit = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+
+	for _, tc := range []struct {
+		image string
+		valid bool
+	}{
+		{image: "alpine", valid: true},
+		{image: "alpine:3.11", valid: true},
+		{image: "sourcegraph/comby:latest", valid: true},
+		{image: "registry.example.com:5000/team/my_image-2:v1.0", valid: true},
+		{image: "alpine@sha256:" + strings.Repeat("a", 64), valid: true},
+		{image: "localhost/tools/go.fmt", valid: true},
+
+		{image: ""},
+		{image: "--privileged"},
+		{image: "--network=host"},
+		{image: "-v=/:/host"},
+		{image: "-it"},
+		{image: "Alpine"},
+		{image: "alpine latest"},
+		{image: "alpine:"},
+		{image: "alpine:-tag"},
+		{image: "alpine@sha256:abc"},
+		{image: "/alpine"},
+		{image: "alpine/"},
+	} {
+		t.Run(tc.image, func(t *testing.T) {
+			err := validateParams(&protocol.Request{
+				Repo:   "github.com/foo/bar",
+				Commit: commit,
+				Steps:  []protocol.Step{{Image: "alpine"}, {Image: tc.image, Args: []string{"true"}}},
+			})
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("want an error")
+			}
+		})
+	}
+}
+
+func TestLogWriter(t *testing.T) {
+	var logs []string
+	w := &logWriter{
+		emit: func(e protocol.Event) error {
+			logs = append(logs, e.Log)
+			return nil
+		},
+		remaining: 10,
+	}
+
+	for _, p := range []string{"1234", "5678", "90abc", "def"} {
+		if n, err := w.Write([]byte(p)); n != len(p) || err != nil {
+			t.Fatalf("have %d, %v, want %d, nil", n, err, len(p))
+		}
+	}
+	if w.err != nil {
+		t.Fatal(w.err)
+	}
+
+	want := []string{"1234", "5678", "90", "\n(the rest of the output was discarded)\n"}
+	if !reflect.DeepEqual(logs, want) {
+		t.Fatalf("have logs %q, want %q", logs, want)
+	}
+}
@@ -0,0 +1,102 @@
+// Command campaign-executor runs the steps of campaign specs in repositories
+// on behalf of the frontend, so that campaigns can be run without src-cli. It
+// runs the steps in sandboxed Docker containers and streams back JSON lines
+// results.
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	opentracing "github.com/opentracing/opentracing-go"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/campaign-executor/execute"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/debugserver"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/tracer"
+)
+
+var (
+	workDir     = env.Get("WORK_DIR", "/tmp", "directory to create the working trees of repositories in. It must be shared with the Docker daemon at the same path.")
+	memory      = env.Get("CAMPAIGN_EXECUTOR_MEMORY", "2g", "memory limit of the container of each step")
+	cpus        = env.Get("CAMPAIGN_EXECUTOR_CPUS", "1", "CPU limit of the container of each step")
+	stepTimeout = env.Get("CAMPAIGN_EXECUTOR_STEP_TIMEOUT", "10m", "maximum duration of each step")
+)
+
+const port = "3189"
+
+func main() {
+	env.Lock()
+	env.HandleHelpFlag()
+	log.SetFlags(0)
+	tracer.Init()
+
+	go debugserver.Start()
+
+	timeout, err := time.ParseDuration(stepTimeout)
+	if err != nil {
+		log.Fatalf("invalid duration %q for CAMPAIGN_EXECUTOR_STEP_TIMEOUT: %s", stepTimeout, err)
+	}
+
+	service := &execute.Service{
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+			return gitserver.DefaultClient.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar"})
+		},
+		Dir:         workDir,
+		Memory:      memory,
+		CPUs:        cpus,
+		StepTimeout: timeout,
+		Log:         log15.Root(),
+	}
+	handler := nethttp.Middleware(opentracing.GlobalTracer(), service)
+
+	host := ""
+	if env.InsecureDev {
+		host = "127.0.0.1"
+	}
+	addr := net.JoinHostPort(host, port)
+	server := &http.Server{
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// For cluster liveness and readiness probes
+			if r.URL.Path == "/healthz" {
+				w.WriteHeader(200)
+				_, err := w.Write([]byte("ok"))
+				if err != nil {
+					log15.Info("Error checking /healthz: " + err.Error())
+				}
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		}),
+	}
+	go shutdownOnSIGINT(server)
+
+	log15.Info("campaign-executor: listening", "addr", server.Addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+func shutdownOnSIGINT(s *http.Server) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	<-c
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := s.Shutdown(ctx)
+	if err != nil {
+		log.Fatal("graceful server shutdown failed, will exit:", err)
+	}
+}
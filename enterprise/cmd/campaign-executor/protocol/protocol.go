@@ -0,0 +1,51 @@
+// Package protocol contains structures used by the campaign-executor API.
+package protocol
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// Request represents a request to run the steps of a campaign spec in a
+// repository. It's sent as the JSON body of a POST request.
+type Request struct {
+	// Repo is the name of the repository, e.g. "github.com/gorilla/mux".
+	Repo api.RepoName
+
+	// Commit is the commit that the steps are run on. It is required to be
+	// resolved, not a ref like HEAD or master.
+	Commit api.CommitID
+
+	// Steps are run in order in the same working tree.
+	Steps []Step
+}
+
+// A Step runs a Docker container with the working tree of the repository
+// mounted as its working directory.
+type Step struct {
+	// Image is the Docker image the step is run in.
+	Image string
+
+	// Args are passed to the entrypoint of the image.
+	Args []string
+}
+
+// GitserverRepo returns the repository information necessary to perform gitserver requests.
+func (r Request) GitserverRepo() gitserver.Repo { return gitserver.Repo{Name: r.Repo} }
+
+// Event is a line of the JSON lines response to a Request. Exactly one of its
+// fields is set. The last event of a successful execution has the Diff, that
+// of a failed one the Error.
+type Event struct {
+	// Log is output of a step, or a message about the execution, which ends
+	// with a newline.
+	Log string `json:",omitempty"`
+
+	// Diff is the unified diff of the changes made by the steps. It's empty if
+	// the steps made no changes.
+	Diff *string `json:",omitempty"`
+
+	// Error describes why the execution failed, e.g. a step exited with a
+	// non-zero status.
+	Error string `json:",omitempty"`
+}
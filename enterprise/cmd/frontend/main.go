@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/hooks"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/shared"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
@@ -26,8 +27,10 @@ import (
 	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/registry"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
 	a8nResolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n/resolvers"
+	lsifserverClient "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/proxy"
 	codeIntelResolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/siteexport"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -69,13 +72,36 @@ func main() {
 	repositories := repos.NewDBStore(dbconn.Global, sql.TxOptions{})
 
 	githubWebhook := a8n.NewGitHubWebhook(a8nStore, repositories, clock)
+	gitlabWebhook := a8n.NewGitLabWebhook(a8nStore, repositories, clock)
 	bitbucketServerWebhook := a8n.NewBitbucketServerWebhook(a8nStore, repositories, clock)
 
 	go bitbucketServerWebhook.Upsert(30 * time.Second)
 
+	httpapi.NewCampaignsAPI = func() *httpapi.CampaignsAPI {
+		return a8nResolvers.NewCampaignsAPI(a8nStore)
+	}
+
+	httpapi.NewSiteExportAPI = func() *httpapi.SiteExportAPI {
+		return siteexport.NewSiteExportAPI(a8nStore)
+	}
+
 	go a8n.RunChangesetJobs(ctx, a8nStore, clock, gitserver.DefaultClient, 5*time.Second)
+	go a8n.RunChangesetCloseJobs(ctx, a8nStore, clock, nil, 5*time.Second)
+	go a8n.RunChangesetReviewersJobs(ctx, a8nStore, clock, nil, 5*time.Second)
+	go a8n.RunChangesetDiffStatJobs(ctx, a8nStore, time.Minute)
+	go a8n.RunChangesetConflictChecks(ctx, a8nStore, 5*time.Minute)
+	go a8n.RunChangesetRebases(ctx, a8nStore, clock, gitserver.DefaultClient, 5*time.Second)
+	go a8n.RunCampaignNotificationDigests(ctx, a8nStore, time.Minute)
+	if a8n.ServerExecutionEnabled() {
+		go a8n.RunCampaignJobExecutions(ctx, a8nStore, clock, 5*time.Second)
+	}
 
-	shared.Main(githubWebhook, bitbucketServerWebhook)
+	// Webhooks enqueue the changesets whose state they change, so that they
+	// are synced right away.
+	syncer := &a8n.ChangesetSyncer{Store: a8nStore, ReposStore: repositories}
+	go a8n.RunChangesetSyncJobs(ctx, a8nStore, syncer, 5*time.Second)
+
+	shared.Main(githubWebhook, gitlabWebhook, bitbucketServerWebhook)
 }
 
 func initLicensing() {
@@ -115,6 +141,15 @@ func initLicensing() {
 
 func initResolvers() {
 	graphqlbackend.NewA8NResolver = a8nResolvers.NewResolver
+	graphqlbackend.CampaignMetrics = func(ctx context.Context, args *graphqlbackend.CampaignMetricsArgs) (graphqlbackend.CampaignMetricsResolver, error) {
+		return a8nResolvers.NewCampaignMetricsResolver(dbconn.Global)(ctx, args)
+	}
+	graphqlbackend.CampaignWorkerQueues = func(ctx context.Context) ([]graphqlbackend.CampaignWorkerQueueResolver, error) {
+		return a8nResolvers.NewCampaignWorkerQueuesResolver(dbconn.Global)(ctx)
+	}
+	graphqlbackend.CampaignWebhookDeliveries = func(ctx context.Context, args *graphqlbackend.CampaignWebhookDeliveriesArgs) (graphqlbackend.CampaignWebhookDeliveryConnectionResolver, error) {
+		return a8nResolvers.NewCampaignWebhookDeliveriesResolver(dbconn.Global)(ctx, args)
+	}
 	graphqlbackend.NewCodeIntelResolver = codeIntelResolvers.NewResolver
 	graphqlbackend.NewAuthzResolver = func() graphqlbackend.AuthzResolver {
 		return authzResolvers.NewResolver(dbconn.Global, func() time.Time {
@@ -125,6 +160,9 @@ func initResolvers() {
 
 func initLSIFEndpoints() {
 	httpapi.NewLSIFServerProxy = proxy.NewProxy
+	hooks.HealthChecks["lsifserver"] = func(ctx context.Context) (interface{}, error) {
+		return lsifserverClient.DefaultClient.Health(ctx)
+	}
 }
 
 type usersStore struct{}
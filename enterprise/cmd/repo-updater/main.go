@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/shared"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -43,17 +44,33 @@ func enterpriseInit(db *sql.DB, repoStore repos.Store, cf *httpcli.Factory) {
 				if err != nil {
 					log15.Error("Syncing Changesets", "err", err)
 				}
-				time.Sleep(2 * time.Minute)
+				// Each changeset is only synced when it's due, so we can
+				// check for due changesets more often than they're synced.
+				time.Sleep(30 * time.Second)
 			}
 		}()
 
-		// Set up expired campaign deletion
+		// Set up deletion of expired campaign plans, idempotency keys,
+		// completed worker jobs and campaigns deleted longer ago than the
+		// retention period
 		go func() {
 			for {
 				err := a8nStore.DeleteExpiredCampaignPlans(ctx)
 				if err != nil {
 					log15.Error("DeleteExpiredCampaignPlans", "error", err)
 				}
+				err = a8nStore.DeleteExpiredIdempotencyKeys(ctx)
+				if err != nil {
+					log15.Error("DeleteExpiredIdempotencyKeys", "error", err)
+				}
+				err = a8nStore.DeleteCompletedWorkerJobs(ctx, 24*time.Hour)
+				if err != nil {
+					log15.Error("DeleteCompletedWorkerJobs", "error", err)
+				}
+				err = a8nStore.PurgeDeletedCampaigns(ctx, conf.AutomationDeletedCampaignRetention())
+				if err != nil {
+					log15.Error("PurgeDeletedCampaigns", "error", err)
+				}
 				time.Sleep(2 * time.Minute)
 			}
 		}()
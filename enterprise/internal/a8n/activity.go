@@ -0,0 +1,104 @@
+package a8n
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// CampaignActivityKind is the kind of an entry in the activity feed of a
+// Campaign.
+type CampaignActivityKind string
+
+// The kinds of CampaignActivity.
+const (
+	CampaignActivityCreated            CampaignActivityKind = "CREATED"
+	CampaignActivityChangesetAdded     CampaignActivityKind = "CHANGESET_ADDED"
+	CampaignActivityChangesetMerged    CampaignActivityKind = "CHANGESET_MERGED"
+	CampaignActivityChangesetCommented CampaignActivityKind = "CHANGESET_COMMENTED"
+)
+
+// CampaignActivityEventKinds are the kinds of ChangesetEvents that are part of
+// the activity feed of a Campaign.
+var CampaignActivityEventKinds = []a8n.ChangesetEventKind{
+	a8n.ChangesetEventKindGitHubMerged,
+	a8n.ChangesetEventKindBitbucketServerMerged,
+	a8n.ChangesetEventKindGitHubCommented,
+	a8n.ChangesetEventKindBitbucketServerCommented,
+}
+
+// CampaignActivity is a single entry in the activity feed of a Campaign.
+type CampaignActivity struct {
+	Kind CampaignActivityKind
+	Time time.Time
+
+	// Changeset is set for all kinds but CampaignActivityCreated.
+	Changeset *a8n.Changeset
+	// Event is set for CampaignActivityChangesetMerged and
+	// CampaignActivityChangesetCommented.
+	Event *a8n.ChangesetEvent
+}
+
+// CampaignActivityFeed returns the activity feed of the Campaign c, given its
+// Changesets and their ChangesetEvents, ordered from newest to oldest.
+// ChangesetEvents of kinds other than CampaignActivityEventKinds are ignored.
+//
+// Since the time a Changeset was added to a Campaign is not recorded, it is
+// approximated by the time the Changeset was created, but not earlier than
+// the creation of the Campaign.
+func CampaignActivityFeed(c *a8n.Campaign, cs []*a8n.Changeset, es []*a8n.ChangesetEvent) []*CampaignActivity {
+	feed := make([]*CampaignActivity, 0, 1+len(cs)+len(es))
+	feed = append(feed, &CampaignActivity{Kind: CampaignActivityCreated, Time: c.CreatedAt})
+
+	byID := make(map[int64]*a8n.Changeset, len(cs))
+	for _, ch := range cs {
+		byID[ch.ID] = ch
+
+		added := ch.CreatedAt
+		if added.Before(c.CreatedAt) {
+			added = c.CreatedAt
+		}
+		feed = append(feed, &CampaignActivity{
+			Kind:      CampaignActivityChangesetAdded,
+			Time:      added,
+			Changeset: ch,
+		})
+	}
+
+	for _, e := range es {
+		ch, ok := byID[e.ChangesetID]
+		if !ok {
+			continue
+		}
+
+		var kind CampaignActivityKind
+		switch e.Kind {
+		case a8n.ChangesetEventKindGitHubMerged, a8n.ChangesetEventKindBitbucketServerMerged:
+			kind = CampaignActivityChangesetMerged
+		case a8n.ChangesetEventKindGitHubCommented, a8n.ChangesetEventKindBitbucketServerCommented:
+			kind = CampaignActivityChangesetCommented
+		default:
+			continue
+		}
+
+		feed = append(feed, &CampaignActivity{
+			Kind:      kind,
+			Time:      e.Timestamp(),
+			Changeset: ch,
+			Event:     e,
+		})
+	}
+
+	// The feed was built in causal order (the Campaign before its Changesets
+	// before their events), which a stable sort keeps for equal timestamps
+	// once reversed.
+	for i, j := 0, len(feed)-1; i < j; i, j = i+1, j-1 {
+		feed[i], feed[j] = feed[j], feed[i]
+	}
+	sort.SliceStable(feed, func(i, j int) bool {
+		return feed[i].Time.After(feed[j].Time)
+	})
+
+	return feed
+}
@@ -0,0 +1,66 @@
+package a8n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestCampaignActivityFeed(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	campaign := &a8n.Campaign{ID: 1, CreatedAt: daysAgo(5)}
+
+	// Tracked before the campaign was created and added to it later.
+	older := &a8n.Changeset{ID: 1, CreatedAt: daysAgo(10)}
+	newer := &a8n.Changeset{ID: 2, CreatedAt: daysAgo(4)}
+
+	merged := &a8n.ChangesetEvent{
+		ChangesetID: older.ID,
+		Kind:        a8n.ChangesetEventKindGitHubMerged,
+		Metadata:    &github.MergedEvent{CreatedAt: daysAgo(1)},
+	}
+	commented := &a8n.ChangesetEvent{
+		ChangesetID: newer.ID,
+		Kind:        a8n.ChangesetEventKindBitbucketServerCommented,
+		Metadata: &bitbucketserver.Activity{
+			CreatedDate: int(daysAgo(2).UnixNano() / int64(time.Millisecond)),
+			Action:      bitbucketserver.CommentedActivityAction,
+		},
+	}
+	ignored := []*a8n.ChangesetEvent{
+		{
+			ChangesetID: newer.ID,
+			Kind:        a8n.ChangesetEventKindGitHubReviewed,
+			Metadata:    &github.PullRequestReview{UpdatedAt: daysAgo(3)},
+		},
+		{
+			ChangesetID: 3, // Not part of the campaign
+			Kind:        a8n.ChangesetEventKindGitHubMerged,
+			Metadata:    &github.MergedEvent{CreatedAt: daysAgo(3)},
+		},
+	}
+
+	have := CampaignActivityFeed(
+		campaign,
+		[]*a8n.Changeset{older, newer},
+		append([]*a8n.ChangesetEvent{merged, commented}, ignored...),
+	)
+
+	want := []*CampaignActivity{
+		{Kind: CampaignActivityChangesetMerged, Time: daysAgo(1), Changeset: older, Event: merged},
+		{Kind: CampaignActivityChangesetCommented, Time: daysAgo(2), Changeset: newer, Event: commented},
+		{Kind: CampaignActivityChangesetAdded, Time: daysAgo(4), Changeset: newer},
+		{Kind: CampaignActivityChangesetAdded, Time: daysAgo(5), Changeset: older},
+		{Kind: CampaignActivityCreated, Time: daysAgo(5)},
+	}
+
+	if diff := cmp.Diff(have, want); diff != "" {
+		t.Fatal(diff)
+	}
+}
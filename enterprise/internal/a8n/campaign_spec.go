@@ -0,0 +1,188 @@
+package a8n
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// CampaignSpecVersion is the current version of the campaign spec format,
+// which is described by schema/campaign_spec.schema.json.
+const CampaignSpecVersion = 2
+
+// campaignSpecMigrations contains, at index i, the migration of campaign specs
+// of version i+1 to version i+2. Bumping CampaignSpecVersion requires
+// appending a migration.
+var campaignSpecMigrations = []func(spec map[string]interface{}) error{
+	migrateCampaignSpecV1,
+}
+
+// migrateCampaignSpecV1 migrates the unversioned action files of src-cli,
+// which have a single scope query, to version 2.
+func migrateCampaignSpecV1(spec map[string]interface{}) error {
+	if q, ok := spec["scopeQuery"]; ok {
+		spec["scopeQueries"] = []interface{}{q}
+		delete(spec, "scopeQuery")
+	}
+	return nil
+}
+
+// ParseCampaignSpec parses the given JSONC campaign spec, migrates it to the
+// current version and validates it against the campaign spec JSON schema.
+// It returns the migrated spec as JSON, or an *ErrInvalidCampaignSpec.
+func ParseCampaignSpec(spec string) (json.RawMessage, error) {
+	normalized, err := jsonc.Parse(spec)
+	if err != nil {
+		return nil, &ErrInvalidCampaignSpec{Errors: []string{err.Error()}}
+	}
+
+	migrated, err := MigrateCampaignSpec(normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := gojsonschema.NewSchemaLoader().Compile(gojsonschema.NewStringLoader(schema.CampaignSpecSchemaJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sc.Validate(gojsonschema.NewBytesLoader(migrated))
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.Valid() {
+		errs := make([]string, 0, len(res.Errors()))
+		for _, e := range res.Errors() {
+			errs = append(errs, strings.TrimPrefix(e.String(), "(root): "))
+		}
+		return nil, &ErrInvalidCampaignSpec{Errors: errs}
+	}
+
+	// The changeset template is executed with example data, so that
+	// references to unknown variables are reported too.
+	t, err := CampaignSpecChangesetTemplate(migrated)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		data, err := NewChangesetTemplateData(nil, "", nil, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := t.Render(data); err != nil {
+			if e, ok := err.(*ErrInvalidChangesetTemplate); ok {
+				return nil, &ErrInvalidCampaignSpec{Errors: e.Errors}
+			}
+			return nil, err
+		}
+	}
+
+	return migrated, nil
+}
+
+// MigrateCampaignSpec migrates the given campaign spec to the current version.
+// Specs without a version are of version 1. Specs are migrated when they are
+// saved, but the specs saved before CampaignSpecVersion was last bumped must be
+// migrated when they are read.
+func MigrateCampaignSpec(spec json.RawMessage) (json.RawMessage, error) {
+	var s map[string]interface{}
+	if err := json.Unmarshal(spec, &s); err != nil || s == nil {
+		return nil, &ErrInvalidCampaignSpec{Errors: []string{"campaign spec must be a JSON object"}}
+	}
+
+	version := 1
+	if v, ok := s["version"]; ok {
+		f, ok := v.(float64)
+		if !ok || f != float64(int(f)) || f < 1 {
+			return nil, &ErrInvalidCampaignSpec{Errors: []string{"version must be a positive integer"}}
+		}
+		version = int(f)
+	}
+
+	if version > CampaignSpecVersion {
+		return nil, &ErrInvalidCampaignSpec{Errors: []string{
+			fmt.Sprintf("unsupported version %d, the latest supported version is %d", version, CampaignSpecVersion),
+		}}
+	}
+
+	if version == CampaignSpecVersion {
+		return spec, nil
+	}
+
+	for ; version < CampaignSpecVersion; version++ {
+		if err := campaignSpecMigrations[version-1](s); err != nil {
+			return nil, err
+		}
+	}
+	s["version"] = CampaignSpecVersion
+
+	return json.Marshal(s)
+}
+
+// CampaignSpecReviewers returns the reviewers requested on the changesets of
+// a campaign with the given spec, which may be nil.
+func CampaignSpecReviewers(spec json.RawMessage) ([]string, error) {
+	if len(spec) == 0 {
+		return nil, nil
+	}
+
+	migrated, err := MigrateCampaignSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var s struct {
+		Reviewers []string `json:"reviewers"`
+	}
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return nil, err
+	}
+	return s.Reviewers, nil
+}
+
+// CampaignSpecStep is a step of a campaign spec, which is run in each
+// repository matched by the scope queries of the spec.
+type CampaignSpecStep struct {
+	// Type is "command" or "docker".
+	Type  string   `json:"type"`
+	Image string   `json:"image,omitempty"`
+	Args  []string `json:"args,omitempty"`
+}
+
+// CampaignSpecScopeQueries returns the scope queries of a campaign with the
+// given spec.
+func CampaignSpecScopeQueries(spec json.RawMessage) ([]string, error) {
+	migrated, err := MigrateCampaignSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var s struct {
+		ScopeQueries []string `json:"scopeQueries"`
+	}
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return nil, err
+	}
+	return s.ScopeQueries, nil
+}
+
+// CampaignSpecSteps returns the steps of a campaign with the given spec.
+func CampaignSpecSteps(spec json.RawMessage) ([]CampaignSpecStep, error) {
+	migrated, err := MigrateCampaignSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var s struct {
+		Steps []CampaignSpecStep `json:"steps"`
+	}
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return nil, err
+	}
+	return s.Steps, nil
+}
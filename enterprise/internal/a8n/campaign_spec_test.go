@@ -0,0 +1,141 @@
+package a8n
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCampaignSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "current version",
+			spec: `{
+				// Comments are allowed
+				"version": 2,
+				"scopeQueries": ["repo:foo"],
+				"steps": [{"type": "docker", "image": "alpine", "args": ["ls"]}],
+			}`,
+			want: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "docker", "image": "alpine", "args": ["ls"]}]}`,
+		},
+		{
+			name: "unversioned action file",
+			spec: `{"scopeQuery": "repo:foo", "steps": [{"type": "command", "args": ["ls"]}]}`,
+			want: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}]}`,
+		},
+		{
+			name: "reviewers",
+			spec: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "reviewers": ["alice", "org/team"]}`,
+			want: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "reviewers": ["alice", "org/team"]}`,
+		},
+		{
+			name:    "duplicate reviewers",
+			spec:    `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "reviewers": ["alice", "alice"]}`,
+			wantErr: true,
+		},
+		{
+			name: "changeset template",
+			spec: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "changesetTemplate": {"title": "{{.Campaign.Name}}"}}`,
+			want: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "changesetTemplate": {"title": "{{.Campaign.Name}}"}}`,
+		},
+		{
+			name:    "changeset template with unknown variable",
+			spec:    `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "changesetTemplate": {"title": "{{.Unknown}}"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "docker step without image",
+			spec:    `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "docker"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown property",
+			spec:    `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": []}], "foo": 1}`,
+			wantErr: true,
+		},
+		{
+			name:    "future version",
+			spec:    `{"version": 3, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": []}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "not an object",
+			spec:    `["repo:foo"]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := ParseCampaignSpec(tc.spec)
+			if tc.wantErr {
+				if _, ok := err.(*ErrInvalidCampaignSpec); !ok {
+					t.Fatalf("have err %v, want *ErrInvalidCampaignSpec", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var haveValue, wantValue interface{}
+			if err := json.Unmarshal(have, &haveValue); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &wantValue); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(wantValue, haveValue); diff != "" {
+				t.Fatalf("unexpected spec (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCampaignSpecReviewers(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want []string
+	}{
+		{
+			name: "no spec",
+		},
+		{
+			name: "no reviewers",
+			spec: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}]}`,
+		},
+		{
+			name: "reviewers",
+			spec: `{"version": 2, "scopeQueries": ["repo:foo"], "steps": [{"type": "command", "args": ["ls"]}], "reviewers": ["alice", "org/team"]}`,
+			want: []string{"alice", "org/team"},
+		},
+		{
+			name: "unversioned action file",
+			spec: `{"scopeQuery": "repo:foo", "steps": [{"type": "command", "args": ["ls"]}]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var spec json.RawMessage
+			if tc.spec != "" {
+				spec = json.RawMessage(tc.spec)
+			}
+
+			have, err := CampaignSpecReviewers(spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("unexpected reviewers (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
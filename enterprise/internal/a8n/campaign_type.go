@@ -2,4 +2,7 @@ package a8n
 
 const (
 	campaignTypePatch = "patch"
+	// campaignTypeSpec is the type of CampaignPlans whose CampaignJobs are
+	// executed on the server. Their arguments are the campaign spec.
+	campaignTypeSpec = "spec"
 )
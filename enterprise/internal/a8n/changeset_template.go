@@ -0,0 +1,170 @@
+package a8n
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// A ChangesetTemplate contains the Go templates of the title and body of the
+// changesets of a campaign, which are listed in its spec.
+type ChangesetTemplate struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// ChangesetTemplateData is the data ChangesetTemplates are executed with.
+type ChangesetTemplateData struct {
+	// Repository is the name of the repository of the changeset.
+	Repository string
+	// Branch is the branch of the changeset and BaseRef the ref it's merged
+	// into.
+	Branch  string
+	BaseRef string
+	// Description is the description of the patch of the changeset.
+	Description string
+	DiffStat    ChangesetTemplateDiffStat
+	Campaign    ChangesetTemplateCampaign
+}
+
+// ChangesetTemplateDiffStat is the number of lines added, changed and
+// deleted by the patch of a changeset.
+type ChangesetTemplateDiffStat struct {
+	Added, Changed, Deleted int32
+}
+
+// ChangesetTemplateCampaign describes the campaign of a changeset.
+type ChangesetTemplateCampaign struct {
+	Name        string
+	Description string
+	URL         string
+}
+
+// NewChangesetTemplateData returns the data the ChangesetTemplate of the
+// given campaign is executed with for the changeset of the given campaign job
+// in the given repository, whose head is the given branch. When the campaign
+// or the campaign job is nil, example values are used in their place, e.g.
+// to preview or validate templates.
+func NewChangesetTemplateData(c *a8n.Campaign, repo api.RepoName, job *a8n.CampaignJob, branch string) (*ChangesetTemplateData, error) {
+	data := &ChangesetTemplateData{
+		Repository:  "github.com/sourcegraph/example",
+		Branch:      "example-branch",
+		BaseRef:     "master",
+		Description: "Example patch description",
+		DiffStat:    ChangesetTemplateDiffStat{Added: 3, Changed: 2, Deleted: 1},
+		Campaign: ChangesetTemplateCampaign{
+			Name:        "Example campaign",
+			Description: "Example campaign description",
+			URL:         campaignURL(0),
+		},
+	}
+
+	if c != nil {
+		data.Branch = c.Branch
+		data.Campaign = ChangesetTemplateCampaign{
+			Name:        c.Name,
+			Description: c.Description,
+			URL:         campaignURL(c.ID),
+		}
+	}
+
+	if branch != "" {
+		data.Branch = git.AbbreviateRef(branch)
+	}
+
+	if job != nil {
+		fileDiffs, err := diff.ParseMultiFileDiff([]byte(job.Diff))
+		if err != nil {
+			return nil, err
+		}
+
+		var stat diff.Stat
+		for _, d := range fileDiffs {
+			s := d.Stat()
+			stat.Added += s.Added
+			stat.Changed += s.Changed
+			stat.Deleted += s.Deleted
+		}
+
+		data.Repository = string(repo)
+		data.BaseRef = git.AbbreviateRef(job.BaseRef)
+		data.Description = job.Description
+		data.DiffStat = ChangesetTemplateDiffStat{Added: stat.Added, Changed: stat.Changed, Deleted: stat.Deleted}
+	}
+
+	return data, nil
+}
+
+// campaignURL returns the absolute URL of the campaign with the given ID.
+func campaignURL(id int64) string {
+	u := &url.URL{Path: "/campaigns/" + string(relay.MarshalID("Campaign", id))}
+	return globals.ExternalURL().ResolveReference(u).String()
+}
+
+// CampaignSpecChangesetTemplate returns the ChangesetTemplate listed in the
+// given campaign spec, which may be nil, or nil if it lists none.
+func CampaignSpecChangesetTemplate(spec json.RawMessage) (*ChangesetTemplate, error) {
+	if len(spec) == 0 {
+		return nil, nil
+	}
+
+	migrated, err := MigrateCampaignSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var s struct {
+		ChangesetTemplate *ChangesetTemplate `json:"changesetTemplate"`
+	}
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return nil, err
+	}
+	return s.ChangesetTemplate, nil
+}
+
+// Render executes the title and body templates with the given data. Empty
+// templates render as empty strings. If a template can't be parsed or
+// executed, an *ErrInvalidChangesetTemplate is returned.
+func (t *ChangesetTemplate) Render(data *ChangesetTemplateData) (title, body string, err error) {
+	var errs []string
+
+	if title, err = renderTemplate("title", t.Title, data); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if body, err = renderTemplate("body", t.Body, data); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return "", "", &ErrInvalidChangesetTemplate{Errors: errs}
+	}
+
+	// Titles are a single line on all code hosts.
+	return strings.Join(strings.Fields(title), " "), body, nil
+}
+
+func renderTemplate(name, text string, data *ChangesetTemplateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,94 @@
+package a8n
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+func TestChangesetTemplateRender(t *testing.T) {
+	campaign := &a8n.Campaign{
+		ID:          1,
+		Name:        "Update dependencies",
+		Description: "Updates the dependencies.",
+		Branch:      "update-deps",
+	}
+
+	job := &a8n.CampaignJob{
+		BaseRef:     "refs/heads/master",
+		Description: "Bumps foo to 1.2.3",
+		Diff: `diff README.md README.md
+index 671e50a..851b23a 100644
+--- README.md
++++ README.md
+@@ -1,2 +1,3 @@
+ # README
+-foo
++bar
++baz
+`,
+	}
+
+	data, err := NewChangesetTemplateData(campaign, "github.com/sourcegraph/sourcegraph", job, "refs/heads/update-deps-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		tmpl      ChangesetTemplate
+		wantTitle string
+		wantBody  string
+		wantErrs  []string
+	}{
+		{
+			name: "variables",
+			tmpl: ChangesetTemplate{
+				Title: "{{.Campaign.Name}}\n in {{.Repository}}",
+				Body:  "{{.Description}} on {{.Branch}} into {{.BaseRef}} (+{{.DiffStat.Added}} ~{{.DiffStat.Changed}} -{{.DiffStat.Deleted}})\n\n{{.Campaign.URL}}",
+			},
+			wantTitle: "Update dependencies in github.com/sourcegraph/sourcegraph",
+			wantBody:  "Bumps foo to 1.2.3 on update-deps-1 into master (+1 ~1 -0)\n\nhttp://example.com/campaigns/Q2FtcGFpZ246MQ==",
+		},
+		{
+			name:      "empty body",
+			tmpl:      ChangesetTemplate{Title: "{{.Campaign.Name}}"},
+			wantTitle: "Update dependencies",
+		},
+		{
+			name: "invalid templates",
+			tmpl: ChangesetTemplate{Title: "{{.Campaign.Name", Body: "{{.Unknown}}"},
+			wantErrs: []string{
+				`template: title:1: unclosed action`,
+				`template: body:1:2: executing "body" at <.Unknown>: can't evaluate field Unknown in type *a8n.ChangesetTemplateData`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			title, body, err := tc.tmpl.Render(data)
+			if tc.wantErrs != nil {
+				e, ok := err.(*ErrInvalidChangesetTemplate)
+				if !ok {
+					t.Fatalf("have err %v, want *ErrInvalidChangesetTemplate", err)
+				}
+				if diff := cmp.Diff(tc.wantErrs, e.Errors); diff != "" {
+					t.Fatalf("unexpected errors (-want +have):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if title != tc.wantTitle {
+				t.Errorf("have title %q, want %q", title, tc.wantTitle)
+			}
+			if body != tc.wantBody {
+				t.Errorf("have body %q, want %q", body, tc.wantBody)
+			}
+		})
+	}
+}
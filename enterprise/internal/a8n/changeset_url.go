@@ -0,0 +1,44 @@
+package a8n
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+)
+
+var (
+	// gitHubPullRequestPath matches the path of a pull request on GitHub,
+	// e.g. /sourcegraph/sourcegraph/pull/1234/files.
+	gitHubPullRequestPath = regexp.MustCompile(`^/([^/]+)/([^/]+)/pull/(\d+)(?:/.*)?$`)
+	// bitbucketServerPullRequestPath matches the path of a pull request on
+	// Bitbucket Server, which may be served below a context path, e.g.
+	// /projects/SOUR/repos/vegeta/pull-requests/1/overview.
+	bitbucketServerPullRequestPath = regexp.MustCompile(`^(?:/.*)?/projects/([^/]+)/repos/([^/]+)/pull-requests/(\d+)(?:/.*)?$`)
+)
+
+// ParseChangesetURL returns the URI of the repository of the changeset with
+// the given URL on GitHub or Bitbucket Server, and the external ID of the
+// changeset in that repository.
+func ParseChangesetURL(rawURL string) (repoURI api.RepoName, externalID string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "parsing changeset URL %q", rawURL)
+	}
+
+	if u.Hostname() == "" {
+		return "", "", errors.Errorf("changeset URL %q has no host", rawURL)
+	}
+
+	if m := bitbucketServerPullRequestPath.FindStringSubmatch(u.Path); m != nil {
+		return reposource.BitbucketServerRepoName("", u.Hostname(), m[1], m[2]), m[3], nil
+	}
+
+	if m := gitHubPullRequestPath.FindStringSubmatch(u.Path); m != nil {
+		return reposource.GitHubRepoName("", u.Hostname(), m[1]+"/"+m[2]), m[3], nil
+	}
+
+	return "", "", errors.Errorf("%q is not the URL of a GitHub or Bitbucket Server pull request", rawURL)
+}
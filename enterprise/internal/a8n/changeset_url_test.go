@@ -0,0 +1,70 @@
+package a8n
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseChangesetURL(t *testing.T) {
+	tests := []struct {
+		url            string
+		wantRepoURI    api.RepoName
+		wantExternalID string
+		wantErr        bool
+	}{
+		{
+			url:            "https://github.com/sourcegraph/sourcegraph/pull/1234",
+			wantRepoURI:    "github.com/sourcegraph/sourcegraph",
+			wantExternalID: "1234",
+		},
+		{
+			url:            "https://github.example.com/org/repo/pull/5/files?diff=split",
+			wantRepoURI:    "github.example.com/org/repo",
+			wantExternalID: "5",
+		},
+		{
+			url:            "https://bitbucket.sgdev.org/projects/SOUR/repos/vegeta/pull-requests/1/overview",
+			wantRepoURI:    "bitbucket.sgdev.org/SOUR/vegeta",
+			wantExternalID: "1",
+		},
+		{
+			url:            "https://example.com/bitbucket/projects/SOUR/repos/vegeta/pull-requests/42",
+			wantRepoURI:    "example.com/SOUR/vegeta",
+			wantExternalID: "42",
+		},
+		{
+			url:     "https://github.com/sourcegraph/sourcegraph/issues/1234",
+			wantErr: true,
+		},
+		{
+			url:     "https://github.com/sourcegraph/sourcegraph/pull/abc",
+			wantErr: true,
+		},
+		{
+			url:     "/sourcegraph/sourcegraph/pull/1234",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			repoURI, externalID, err := ParseChangesetURL(tc.url)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repoURI != tc.wantRepoURI {
+				t.Errorf("have repo URI %q, want %q", repoURI, tc.wantRepoURI)
+			}
+			if externalID != tc.wantExternalID {
+				t.Errorf("have external ID %q, want %q", externalID, tc.wantExternalID)
+			}
+		})
+	}
+}
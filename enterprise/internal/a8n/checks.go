@@ -0,0 +1,51 @@
+package a8n
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// CheckStateCounts is the number of open changesets in each check state.
+type CheckStateCounts struct {
+	Total   int32
+	Pending int32
+	Passed  int32
+	Failed  int32
+	// Unknown is the number of open changesets whose code host reported no
+	// checks, or whose checks couldn't be synced.
+	Unknown int32
+}
+
+// CountCheckStates returns the number of changesets in each check state, as
+// computed from their synced metadata and the given events. Changesets that
+// are not open are skipped, since their checks can no longer block them.
+func CountCheckStates(cs []*a8n.Changeset, es []*a8n.ChangesetEvent) (*CheckStateCounts, error) {
+	eventsByChangeset := make(map[int64][]*a8n.ChangesetEvent, len(cs))
+	for _, e := range es {
+		eventsByChangeset[e.ChangesetID] = append(eventsByChangeset[e.ChangesetID], e)
+	}
+
+	counts := &CheckStateCounts{}
+	for _, c := range cs {
+		s, err := c.State()
+		if err != nil {
+			return nil, err
+		}
+		if s != a8n.ChangesetStateOpen {
+			continue
+		}
+
+		counts.Total++
+		switch a8n.ComputeCheckState(c, eventsByChangeset[c.ID]) {
+		case a8n.ChangesetCheckStatePending:
+			counts.Pending++
+		case a8n.ChangesetCheckStatePassed:
+			counts.Passed++
+		case a8n.ChangesetCheckStateFailed:
+			counts.Failed++
+		default:
+			counts.Unknown++
+		}
+	}
+
+	return counts, nil
+}
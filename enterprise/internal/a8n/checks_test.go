@@ -0,0 +1,72 @@
+package a8n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestCountCheckStates(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	ghPR := func(id int64, state string, contextStates ...string) *a8n.Changeset {
+		pr := &github.PullRequest{State: state}
+		if len(contextStates) > 0 {
+			var commit github.PullRequestCommit
+			for i, s := range contextStates {
+				commit.Commit.Status.Contexts = append(commit.Commit.Status.Contexts, github.Context{
+					Context: string(rune('a' + i)),
+					State:   s,
+				})
+			}
+			pr.Commits.Nodes = []github.PullRequestCommit{commit}
+		}
+		return &a8n.Changeset{ID: id, Metadata: pr, UpdatedAt: now}
+	}
+	bbsPR := func(id int64, state string, buildStates ...string) *a8n.Changeset {
+		pr := &bitbucketserver.PullRequest{State: state}
+		for _, s := range buildStates {
+			pr.BuildStatuses = append(pr.BuildStatuses, bitbucketserver.BuildStatus{State: s})
+		}
+		return &a8n.Changeset{ID: id, Metadata: pr, UpdatedAt: now}
+	}
+
+	cs := []*a8n.Changeset{
+		ghPR(1, "OPEN", "SUCCESS"),
+		ghPR(2, "OPEN", "SUCCESS", "FAILURE"),
+		ghPR(3, "OPEN", "PENDING"),
+		// Passed according to a status received after the last sync
+		ghPR(4, "OPEN"),
+		ghPR(5, "OPEN"),
+		// Not open
+		ghPR(6, "MERGED", "FAILURE"),
+		bbsPR(7, "OPEN", "SUCCESSFUL", "INPROGRESS"),
+		bbsPR(8, "OPEN", "FAILED"),
+		bbsPR(9, "DECLINED", "FAILED"),
+	}
+	es := []*a8n.ChangesetEvent{
+		{
+			ChangesetID: 4,
+			Kind:        a8n.ChangesetEventKindCommitStatus,
+			Metadata: &github.CommitStatus{
+				Context:    "ci",
+				State:      "SUCCESS",
+				ReceivedAt: now.Add(time.Minute),
+			},
+		},
+	}
+
+	have, err := CountCheckStates(cs, es)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &CheckStateCounts{Total: 7, Pending: 2, Passed: 2, Failed: 2, Unknown: 1}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+}
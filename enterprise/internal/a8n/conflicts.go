@@ -0,0 +1,165 @@
+package a8n
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// RunChangesetConflictChecks should run in a background goroutine and is
+// responsible for checking whether open changesets have merge conflicts with
+// their base branch. A changeset is checked again when its head or the head
+// of its base branch changes.
+func RunChangesetConflictChecks(ctx context.Context, s *Store, backoffDuration time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if err := checkChangesetConflicts(ctx, s); err != nil {
+				log15.Error("Checking changeset conflicts", "err", err)
+			}
+			time.Sleep(backoffDuration)
+		}
+	}
+}
+
+func checkChangesetConflicts(ctx context.Context, s *Store) error {
+	for cursor := int64(-1); cursor != 0; {
+		opts := ListChangesetsOpts{Cursor: cursor, Limit: 1000}
+		cs, next, err := s.ListChangesets(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		open := cs[:0]
+		for _, c := range cs {
+			if state, err := c.State(); err == nil && state == a8n.ChangesetStateOpen {
+				open = append(open, c)
+				continue
+			}
+
+			// Closed, merged and deleted changesets can't be rebased anymore,
+			// so they don't count as conflicting.
+			if c.HasConflicts != nil {
+				c.HasConflicts, c.ConflictsBaseOid, c.ConflictsHeadOid = nil, "", ""
+				if _, err := s.UpdateChangesetConflicts(ctx, c); err != nil {
+					return err
+				}
+			}
+		}
+
+		repoSet, err := changesetRepos(ctx, s, open)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range open {
+			repo := repoSet[c.RepoID]
+			if repo == nil {
+				continue
+			}
+
+			if err := checkChangesetConflict(ctx, s, repo, c); err != nil {
+				log15.Warn("Checking changeset conflicts", "changeset_id", c.ID, "err", err)
+			}
+		}
+
+		cursor = next
+	}
+
+	return nil
+}
+
+// checkChangesetConflict checks whether the given open Changeset has merge
+// conflicts with its base branch and saves the result, unless it was already
+// checked for the current commits of its base branch and head. If the
+// Changeset has conflicts or fell behind its base branch, it's enqueued to be
+// rebased for each of its Campaigns with AutoRebase.
+func checkChangesetConflict(ctx context.Context, s *Store, repo *repos.Repo, c *a8n.Changeset) error {
+	// The base is the current head of the base branch, rather than the base
+	// commit recorded by the code host, which doesn't move when the base
+	// branch does.
+	baseRef, err := c.BaseRef()
+	if err != nil {
+		return err
+	}
+	headRef, err := c.HeadRefOid()
+	if err != nil {
+		return err
+	}
+	if headRef == "" {
+		// Fallback to the ref if we can't get the OID
+		if headRef, err = c.HeadRef(); err != nil {
+			return err
+		}
+	}
+	if baseRef == "" || headRef == "" {
+		return errors.New("changeset base and head could not be determined")
+	}
+	for _, ref := range []string{baseRef, headRef} {
+		if strings.HasPrefix(ref, "-") {
+			// Don't let the changeset metadata add git command-line flags.
+			return errors.Errorf("invalid ref: %q", ref)
+		}
+	}
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, &types.Repo{
+		Name:         api.RepoName(repo.Name),
+		ExternalRepo: repo.ExternalRepo,
+	})
+	if err != nil {
+		return err
+	}
+
+	base, err := git.ResolveRevision(ctx, *cachedRepo, nil, baseRef, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return err
+	}
+	head, err := git.ResolveRevision(ctx, *cachedRepo, nil, headRef, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return err
+	}
+
+	if c.HasConflicts != nil && c.ConflictsBaseOid == string(base) && c.ConflictsHeadOid == string(head) {
+		return nil
+	}
+
+	hasConflicts, err := git.HasMergeConflicts(ctx, *cachedRepo, base, head)
+	if err != nil {
+		return err
+	}
+
+	c.HasConflicts = &hasConflicts
+	c.ConflictsBaseOid, c.ConflictsHeadOid = string(base), string(head)
+	if updated, err := s.UpdateChangesetConflicts(ctx, c); err != nil || !updated {
+		return err
+	}
+
+	jobs, err := autoRebaseChangesetJobs(ctx, s, c)
+	if err != nil || len(jobs) == 0 {
+		return err
+	}
+
+	if !hasConflicts {
+		mb, err := git.MergeBase(ctx, *cachedRepo, base, head)
+		if err != nil {
+			return err
+		}
+		if mb == base {
+			// The changeset is up to date with its base branch.
+			return nil
+		}
+	}
+
+	return enqueueChangesetRebases(ctx, s, jobs)
+}
@@ -0,0 +1,194 @@
+package a8n
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// RunChangesetDiffStatJobs should run in a background goroutine and is
+// responsible for computing the diff stats of open changesets whose diff stat
+// hasn't been computed yet or is outdated because their base or head
+// changed.
+func RunChangesetDiffStatJobs(ctx context.Context, s *Store, backoffDuration time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if err := computeChangesetDiffStats(ctx, s); err != nil {
+				log15.Error("Computing changeset diff stats", "err", err)
+			}
+			time.Sleep(backoffDuration)
+		}
+	}
+}
+
+func computeChangesetDiffStats(ctx context.Context, s *Store) error {
+	for cursor := int64(-1); cursor != 0; {
+		opts := ListChangesetsOpts{Cursor: cursor, Limit: 1000, WithoutDeleted: true, WithoutDiffStat: true}
+		cs, next, err := s.ListChangesets(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		open := cs[:0]
+		for _, c := range cs {
+			// Only open changesets have diffs, since we can't guarantee that
+			// gitserver has the refs of closed and merged ones.
+			if state, err := c.State(); err == nil && state == a8n.ChangesetStateOpen {
+				open = append(open, c)
+			}
+		}
+
+		repoSet, err := changesetRepos(ctx, s, open)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range open {
+			repo := repoSet[c.RepoID]
+			if repo == nil {
+				continue
+			}
+
+			stat, err := changesetDiffStat(ctx, repo, c)
+			if err != nil {
+				log15.Warn("Computing changeset diff stat", "changeset_id", c.ID, "err", err)
+				continue
+			}
+
+			c.SetDiffStat(stat)
+			if _, err := s.UpdateChangesetDiffStat(ctx, c); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+	}
+
+	return nil
+}
+
+// changesetRepos returns the repositories of the given Changesets, keyed by
+// their ID.
+func changesetRepos(ctx context.Context, s *Store, cs []*a8n.Changeset) (map[api.RepoID]*repos.Repo, error) {
+	repoIDs := make([]api.RepoID, 0, len(cs))
+	for _, c := range cs {
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+
+	var rs []*repos.Repo
+	if len(repoIDs) > 0 {
+		reposStore := repos.NewDBStore(s.DB(), sql.TxOptions{})
+		var err error
+		if rs, err = reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs}); err != nil {
+			return nil, err
+		}
+	}
+
+	repoSet := make(map[api.RepoID]*repos.Repo, len(rs))
+	for _, r := range rs {
+		repoSet[r.ID] = r
+	}
+	return repoSet, nil
+}
+
+// diffStatRevs returns the range of revisions of the given Changeset that its
+// diff stat is computed for.
+func diffStatRevs(c *a8n.Changeset) string {
+	base, err := c.BaseRefOid()
+	if err != nil {
+		return ""
+	}
+	if base == "" {
+		// Fallback to the ref if we can't get the OID
+		if base, err = c.BaseRef(); err != nil {
+			return ""
+		}
+	}
+
+	head, err := c.HeadRefOid()
+	if err != nil {
+		return ""
+	}
+	if head == "" {
+		// Fallback to the ref if we can't get the OID
+		if head, err = c.HeadRef(); err != nil {
+			return ""
+		}
+	}
+
+	if base == "" || head == "" {
+		return ""
+	}
+	return base + "..." + head
+}
+
+// changesetDiffStat computes the diff stat of the given Changeset with
+// gitserver.
+func changesetDiffStat(ctx context.Context, repo *repos.Repo, c *a8n.Changeset) (*diff.Stat, error) {
+	rangeSpec := diffStatRevs(c)
+	if rangeSpec == "" {
+		return nil, errors.New("changeset base and head could not be determined")
+	}
+	if strings.HasPrefix(rangeSpec, "-") || strings.HasPrefix(rangeSpec, ".") {
+		// Don't let the changeset metadata add git diff command-line flags
+		// or refer to a file.
+		return nil, errors.Errorf("invalid diff range argument: %q", rangeSpec)
+	}
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, &types.Repo{
+		Name:         api.RepoName(repo.Name),
+		ExternalRepo: repo.ExternalRepo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := git.ExecReader(ctx, *cachedRepo, []string{
+		"diff",
+		"--find-renames",
+		"--find-copies",
+		"--full-index",
+		"--inter-hunk-context=3",
+		"--no-prefix",
+		rangeSpec,
+		"--",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	stat := &diff.Stat{}
+	dr := diff.NewMultiFileDiffReader(rdr)
+	for {
+		fileDiff, err := dr.ReadFile()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s := fileDiff.Stat()
+		stat.Added += s.Added
+		stat.Changed += s.Changed
+		stat.Deleted += s.Deleted
+	}
+
+	return stat, nil
+}
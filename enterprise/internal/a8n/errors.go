@@ -0,0 +1,352 @@
+package a8n
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// The codes of the errors returned by the Service that are exposed to API
+// clients in the "code" field of the GraphQL error extensions.
+const (
+	ErrCodeCampaignNotFound     = "CAMPAIGN_NOT_FOUND"
+	ErrCodeCampaignPlanNotFound = "CAMPAIGN_PLAN_NOT_FOUND"
+	ErrCodeChangesetNotFound    = "CHANGESET_NOT_FOUND"
+	ErrCodeCampaignNameConflict = "CAMPAIGN_NAME_CONFLICT"
+	ErrCodeQuotaExceeded        = "QUOTA_EXCEEDED"
+	ErrCodeIdempotencyKeyInUse  = "IDEMPOTENCY_KEY_IN_USE"
+	ErrCodeInvalidCampaignSpec  = "INVALID_CAMPAIGN_SPEC"
+	ErrCodeCampaignRolledBack   = "CAMPAIGN_ROLLED_BACK"
+	ErrCodeCampaignParentCycle  = "CAMPAIGN_PARENT_CYCLE"
+	ErrCodeCampaignStateChange  = "CAMPAIGN_STATE_CHANGE"
+
+	ErrCodeCampaignSavedFilterNotFound     = "CAMPAIGN_SAVED_FILTER_NOT_FOUND"
+	ErrCodeCampaignWebhookDeliveryNotFound = "CAMPAIGN_WEBHOOK_DELIVERY_NOT_FOUND"
+)
+
+// ErrCampaignNotFound is returned by the Service if the Campaign with the
+// given ID doesn't exist.
+//
+// It implements the Extensions method that graphql-go uses to populate the
+// extensions of a GraphQL error, so that API clients can tell it apart from
+// other errors without matching on the message. For this to work, it must be
+// returned unwrapped by resolvers.
+type ErrCampaignNotFound struct {
+	ID int64
+}
+
+func (e *ErrCampaignNotFound) Error() string {
+	return fmt.Sprintf("campaign not found: %d", e.ID)
+}
+
+// NotFound implements the interface checked by errcode.IsNotFound.
+func (e *ErrCampaignNotFound) NotFound() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignNotFound}
+}
+
+// ErrCampaignPlanNotFound is returned if the CampaignPlan with the given ID
+// doesn't exist.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignPlanNotFound struct {
+	ID int64
+}
+
+func (e *ErrCampaignPlanNotFound) Error() string {
+	return fmt.Sprintf("campaign plan not found: %d", e.ID)
+}
+
+// NotFound implements the interface checked by errcode.IsNotFound.
+func (e *ErrCampaignPlanNotFound) NotFound() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignPlanNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignPlanNotFound}
+}
+
+// ErrChangesetNotFound is returned if the Changeset with the given ID doesn't
+// exist.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrChangesetNotFound struct {
+	ID int64
+}
+
+func (e *ErrChangesetNotFound) Error() string {
+	return fmt.Sprintf("changeset not found: %d", e.ID)
+}
+
+// NotFound implements the interface checked by errcode.IsNotFound.
+func (e *ErrChangesetNotFound) NotFound() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrChangesetNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeChangesetNotFound}
+}
+
+// ErrCampaignSavedFilterNotFound is returned if the current user has no
+// CampaignSavedFilter with the given ID.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignSavedFilterNotFound struct {
+	ID int64
+}
+
+func (e *ErrCampaignSavedFilterNotFound) Error() string {
+	return fmt.Sprintf("campaign saved filter not found: %d", e.ID)
+}
+
+// NotFound implements the interface checked by errcode.IsNotFound.
+func (e *ErrCampaignSavedFilterNotFound) NotFound() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignSavedFilterNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignSavedFilterNotFound}
+}
+
+// ErrCampaignWebhookDeliveryNotFound is returned if the
+// CampaignWebhookDelivery with the given ID doesn't exist.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignWebhookDeliveryNotFound struct {
+	ID int64
+}
+
+func (e *ErrCampaignWebhookDeliveryNotFound) Error() string {
+	return fmt.Sprintf("campaign webhook delivery not found: %d", e.ID)
+}
+
+// NotFound implements the interface checked by errcode.IsNotFound.
+func (e *ErrCampaignWebhookDeliveryNotFound) NotFound() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignWebhookDeliveryNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignWebhookDeliveryNotFound}
+}
+
+// ErrCampaignNameConflict is returned by CreateCampaign or UpdateCampaign if
+// another Campaign in the same namespace is already named Name.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignNameConflict struct {
+	Name            string
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+func (e *ErrCampaignNameConflict) Error() string {
+	return fmt.Sprintf("a campaign named %q already exists in this namespace", e.Name)
+}
+
+// BadRequest implements the interface checked by errcode.IsBadRequest.
+func (e *ErrCampaignNameConflict) BadRequest() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignNameConflict) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeCampaignNameConflict,
+		"name": e.Name,
+	}
+}
+
+// campaignNameConflict returns an *ErrCampaignNameConflict for c if err is a
+// violation of the unique indexes on the names of Campaigns within their
+// namespace, and err otherwise. The indexes reject the conflicts that
+// checkCampaignName misses because of concurrent requests.
+func campaignNameConflict(err error, c *a8n.Campaign) error {
+	pqErr, ok := errors.Cause(err).(*pq.Error)
+	if !ok {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "campaigns_namespace_user_id_name_unique", "campaigns_namespace_org_id_name_unique":
+		return &ErrCampaignNameConflict{
+			Name:            c.Name,
+			NamespaceUserID: c.NamespaceUserID,
+			NamespaceOrgID:  c.NamespaceOrgID,
+		}
+	}
+	return err
+}
+
+// The quotas that can be configured in the "automation.quotas" site
+// configuration.
+const (
+	QuotaOpenCampaignsPerNamespace = "maxOpenCampaignsPerNamespace"
+	QuotaChangesetsPerCampaign     = "maxChangesetsPerCampaign"
+)
+
+// ErrQuotaExceeded is returned by the Service if an operation would exceed
+// one of the configured Automation quotas.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrQuotaExceeded struct {
+	// Quota is the name of the exceeded quota, e.g.
+	// QuotaChangesetsPerCampaign.
+	Quota string
+	// Limit is the configured limit of the quota.
+	Limit int
+	// Requested is the number the operation would have resulted in.
+	Requested int
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf(
+		"quota %s exceeded: the operation would result in %d, but the limit is %d (a site admin can override the quota)",
+		e.Quota, e.Requested, e.Limit,
+	)
+}
+
+// BadRequest implements the interface checked by errcode.IsBadRequest.
+func (e *ErrQuotaExceeded) BadRequest() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrQuotaExceeded) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":      ErrCodeQuotaExceeded,
+		"quota":     e.Quota,
+		"limit":     e.Limit,
+		"requested": e.Requested,
+	}
+}
+
+// ErrIdempotencyKeyInUse is returned by Service.WithIdempotencyKey if a
+// mutation with the same idempotency key is still running. Clients should
+// retry the mutation later.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrIdempotencyKeyInUse struct {
+	Key string
+}
+
+func (e *ErrIdempotencyKeyInUse) Error() string {
+	return fmt.Sprintf("a request with the idempotency key %q is still being processed", e.Key)
+}
+
+// Temporary implements the interface checked by errcode.IsTemporary.
+func (e *ErrIdempotencyKeyInUse) Temporary() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrIdempotencyKeyInUse) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeIdempotencyKeyInUse,
+		"key":  e.Key,
+	}
+}
+
+// ErrInvalidCampaignSpec is returned by CreateCampaign or UpdateCampaign if
+// the given campaign spec can't be parsed, migrated to the current version or
+// doesn't match the campaign spec JSON schema.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrInvalidCampaignSpec struct {
+	Errors []string
+}
+
+func (e *ErrInvalidCampaignSpec) Error() string {
+	return fmt.Sprintf("invalid campaign spec: %s", strings.Join(e.Errors, "; "))
+}
+
+// BadRequest implements the interface checked by errcode.IsBadRequest.
+func (e *ErrInvalidCampaignSpec) BadRequest() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrInvalidCampaignSpec) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   ErrCodeInvalidCampaignSpec,
+		"errors": e.Errors,
+	}
+}
+
+// ErrInvalidChangesetTemplate is returned by ChangesetTemplate.Render if the
+// title or body template can't be parsed or executed.
+type ErrInvalidChangesetTemplate struct {
+	Errors []string
+}
+
+func (e *ErrInvalidChangesetTemplate) Error() string {
+	return fmt.Sprintf("invalid changeset template: %s", strings.Join(e.Errors, "; "))
+}
+
+// ErrCampaignRolledBack is returned by RollbackCampaign if the Campaign
+// already has a rollback campaign.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignRolledBack struct {
+	ID         int64
+	RollbackID int64
+}
+
+func (e *ErrCampaignRolledBack) Error() string {
+	return fmt.Sprintf("campaign %d was already rolled back by campaign %d", e.ID, e.RollbackID)
+}
+
+// BadRequest implements the interface checked by errcode.IsBadRequest.
+func (e *ErrCampaignRolledBack) BadRequest() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignRolledBack) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignRolledBack}
+}
+
+// ErrCampaignParentCycle is returned by the Service if making the Campaign
+// with ParentID the parent of the Campaign with ID would make the latter an
+// ancestor of itself.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignParentCycle struct {
+	ID       int64
+	ParentID int64
+}
+
+func (e *ErrCampaignParentCycle) Error() string {
+	return fmt.Sprintf("campaign %d cannot be the parent of campaign %d, which is one of its ancestors", e.ParentID, e.ID)
+}
+
+// BadRequest implements the interface checked by errcode.IsBadRequest.
+func (e *ErrCampaignParentCycle) BadRequest() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignParentCycle) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignParentCycle}
+}
+
+// ErrCampaignStateChange is returned by the Service if the Campaign with ID
+// would be moved before the Campaign with BeforeID, which is in another
+// state. Moving campaigns only reorders them within their state.
+//
+// Like ErrCampaignNotFound, it implements the Extensions method used by
+// graphql-go.
+type ErrCampaignStateChange struct {
+	ID       int64
+	BeforeID int64
+}
+
+func (e *ErrCampaignStateChange) Error() string {
+	return fmt.Sprintf("campaign %d cannot be moved before campaign %d, which is in another state", e.ID, e.BeforeID)
+}
+
+// BadRequest implements the interface checked by errcode.IsBadRequest.
+func (e *ErrCampaignStateChange) BadRequest() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrCampaignStateChange) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeCampaignStateChange}
+}
@@ -0,0 +1,356 @@
+package a8n
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/campaign-executor/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"golang.org/x/net/context/ctxhttp"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// campaignExecutorURL is the URL of the campaign-executor service, which runs
+// the steps of campaign specs on the server.
+var campaignExecutorURL = env.Get("CAMPAIGN_EXECUTOR_URL", "", "campaign-executor URL. Campaign specs can only be run on the server if it's set.")
+
+// ServerExecutionEnabled returns whether campaign specs can be run on the
+// server with CreateCampaignPlanFromSpec.
+func ServerExecutionEnabled() bool {
+	if MockServerExecutionEnabled != nil {
+		return MockServerExecutionEnabled()
+	}
+	return campaignExecutorURL != ""
+}
+
+// MockServerExecutionEnabled mocks ServerExecutionEnabled in tests.
+var MockServerExecutionEnabled func() bool
+
+// campaignJobLogFlushInterval is the interval at which the output of a
+// CampaignJob execution is appended to its log.
+const campaignJobLogFlushInterval = time.Second
+
+// campaignJobExecutionPayload is the payload of the WorkerJobs in
+// CampaignJobExecutionsQueue. It's built by CreateCampaignPlanFromSpec.
+type campaignJobExecutionPayload struct {
+	CampaignJobID int64 `json:"campaign_job_id"`
+}
+
+// CampaignSpecRepo is a repository matched by the scope queries of a campaign
+// spec, in which the steps of the spec are run on the given base revision.
+type CampaignSpecRepo struct {
+	Repo         api.RepoID
+	BaseRevision string
+}
+
+// CreateCampaignPlanFromSpec creates a CampaignPlan whose CampaignJobs run the
+// steps of the given JSONC campaign spec in the given repositories on the
+// server. The CampaignJobs are pending until the campaign-executor ran the
+// steps and computed their diffs. Repositories that are not supported by
+// Automation are skipped.
+//
+// Only docker steps can be run on the server. An *ErrInvalidCampaignSpec is
+// returned if the spec has other steps or is invalid.
+func (s *Service) CreateCampaignPlanFromSpec(ctx context.Context, spec string, specRepos []CampaignSpecRepo, userID int32) (plan *a8n.CampaignPlan, err error) {
+	defer observeOperation("Service.CreateCampaignPlanFromSpec", time.Now(), &err)
+
+	if userID == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	if !ServerExecutionEnabled() {
+		return nil, errors.New("running campaign specs on the server is not enabled, because CAMPAIGN_EXECUTOR_URL is not set")
+	}
+
+	migrated, err := ParseCampaignSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := CampaignSpecSteps(migrated)
+	if err != nil {
+		return nil, err
+	}
+	for i, step := range steps {
+		if step.Type != "docker" {
+			return nil, &ErrInvalidCampaignSpec{Errors: []string{
+				fmt.Sprintf("steps.%d: only docker steps can be run on the server", i),
+			}}
+		}
+	}
+
+	patches := make([]a8n.CampaignPlanPatch, len(specRepos))
+	for i, r := range specRepos {
+		patches[i] = a8n.CampaignPlanPatch{Repo: r.Repo, BaseRevision: r.BaseRevision}
+	}
+
+	jobs, err := s.campaignJobsFromPatches(ctx, patches)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	plan = &a8n.CampaignPlan{
+		CampaignType: campaignTypeSpec,
+		Arguments:    string(migrated),
+		UserID:       userID,
+	}
+
+	if err = tx.CreateCampaignPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		// The job is pending until it was executed.
+		job.CampaignPlanID = plan.ID
+		job.StartedAt = time.Time{}
+		job.FinishedAt = time.Time{}
+		if err = tx.CreateCampaignJob(ctx, job); err != nil {
+			return nil, err
+		}
+
+		var payload []byte
+		payload, err = json.Marshal(campaignJobExecutionPayload{CampaignJobID: job.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.EnqueueWorkerJob(ctx, &a8n.WorkerJob{
+			Queue:   CampaignJobExecutionsQueue,
+			Payload: payload,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// RunCampaignJobExecutions should run in a background goroutine and is
+// responsible for executing the CampaignJobs in CampaignJobExecutionsQueue
+// with the campaign-executor.
+// ctx should be canceled to terminate the function
+func RunCampaignJobExecutions(ctx context.Context, s *Store, clock func() time.Time, backoffDuration time.Duration) {
+	workerCount, err := strconv.Atoi(maxWorkers)
+	if err != nil {
+		log15.Error("Parsing max worker count failed. Falling back to default.", "default", defaultWorkerCount, "err", err)
+		workerCount = defaultWorkerCount
+	}
+	process := func(ctx context.Context, s *Store, job *a8n.WorkerJob) error {
+		var p campaignJobExecutionPayload
+		if err := json.Unmarshal(job.Payload, &p); err != nil {
+			return errors.Wrap(err, "parsing payload")
+		}
+
+		err := executeCampaignJob(ctx, s, clock, p.CampaignJobID, job.Attempts)
+		if err != nil && job.Attempts >= job.MaxAttempts {
+			// The CampaignJob would be pending forever otherwise.
+			return finishCampaignJobExecution(ctx, s, clock, p.CampaignJobID, clock(), "", err.Error())
+		}
+		return err
+	}
+	w := &Worker{
+		Store:       s,
+		Queue:       CampaignJobExecutionsQueue,
+		Handler:     process,
+		Concurrency: workerCount,
+		Backoff:     backoffDuration,
+	}
+	w.Start(ctx)
+}
+
+// executeCampaignJob runs the steps of the campaign spec of the CampaignJob
+// with the given ID in its repository with the campaign-executor, appends
+// their output to the log of the job and saves the resulting diff or the
+// error of the failed step. An error is returned if the job should be
+// retried, e.g. because the campaign-executor couldn't be reached.
+func executeCampaignJob(ctx context.Context, s *Store, clock func() time.Time, id int64, attempt int32) error {
+	job, err := s.GetCampaignJob(ctx, GetCampaignJobOpts{ID: id})
+	if err == ErrNoResults {
+		// The job was deleted with its campaign plan in the meantime.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting campaign job")
+	}
+	if !job.FinishedAt.IsZero() {
+		return nil
+	}
+
+	plan, err := s.GetCampaignPlan(ctx, GetCampaignPlanOpts{ID: job.CampaignPlanID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign plan")
+	}
+	if !plan.CanceledAt.IsZero() {
+		return nil
+	}
+
+	steps, err := CampaignSpecSteps(json.RawMessage(plan.Arguments))
+	if err != nil {
+		return errors.Wrap(err, "getting campaign spec steps")
+	}
+
+	reposStore := repos.NewDBStore(s.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{job.RepoID}})
+	if err != nil {
+		return errors.Wrap(err, "getting repository")
+	}
+	if len(rs) != 1 {
+		return finishCampaignJobExecution(ctx, s, clock, id, clock(), "", "repository not found")
+	}
+
+	req := protocol.Request{
+		Repo:   api.RepoName(rs[0].Name),
+		Commit: job.Rev,
+		Steps:  make([]protocol.Step, len(steps)),
+	}
+	for i, step := range steps {
+		req.Steps[i] = protocol.Step{Image: step.Image, Args: step.Args}
+	}
+
+	log := &campaignJobLog{ctx: ctx, store: s, id: id}
+	if attempt > 1 {
+		log.write(fmt.Sprintf("Retrying, attempt %d\n", attempt))
+	}
+
+	startedAt := clock()
+	ev, err := runCampaignExecutor(ctx, &req, log.write)
+	if flushErr := log.flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return err
+	}
+
+	var diff string
+	if ev.Diff != nil {
+		diff = *ev.Diff
+	}
+	return finishCampaignJobExecution(ctx, s, clock, id, startedAt, diff, ev.Error)
+}
+
+// finishCampaignJobExecution saves the result of the execution of the
+// CampaignJob with the given ID, which makes the job finished.
+func finishCampaignJobExecution(ctx context.Context, s *Store, clock func() time.Time, id int64, startedAt time.Time, diff, execErr string) error {
+	job, err := s.GetCampaignJob(ctx, GetCampaignJobOpts{ID: id})
+	if err == ErrNoResults {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting campaign job")
+	}
+
+	job.Diff = diff
+	job.Error = execErr
+	job.StartedAt = startedAt
+	job.FinishedAt = clock()
+
+	return errors.Wrap(s.UpdateCampaignJob(ctx, job), "updating campaign job")
+}
+
+// runCampaignExecutor sends the request to the campaign-executor, passes the
+// output of the steps to onLog and returns the last event, which has either
+// the diff or the error of the execution.
+func runCampaignExecutor(ctx context.Context, req *protocol.Request, onLog func(string)) (*protocol.Event, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest("POST", campaignExecutorURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq = httpReq.WithContext(ctx)
+
+	httpReq, ht := nethttp.TraceRequest(opentracing.GlobalTracer(), httpReq,
+		nethttp.OperationName("Campaign executor client"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	resp, err := ctxhttp.Do(ctx, http.DefaultClient, httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, errors.Wrap(err, "campaign executor request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf("campaign executor request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var ev protocol.Event
+		if err := dec.Decode(&ev); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, errors.Wrap(err, "reading campaign executor response")
+		}
+
+		if ev.Log != "" {
+			onLog(ev.Log)
+			continue
+		}
+		return &ev, nil
+	}
+}
+
+// campaignJobLog buffers the output of a CampaignJob execution and appends it
+// to the log of the job at most every campaignJobLogFlushInterval.
+type campaignJobLog struct {
+	ctx   context.Context
+	store *Store
+	id    int64
+
+	buf       strings.Builder
+	flushedAt time.Time
+	err       error
+}
+
+func (l *campaignJobLog) write(s string) {
+	l.buf.WriteString(s)
+	if time.Since(l.flushedAt) >= campaignJobLogFlushInterval {
+		if err := l.flush(); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+}
+
+func (l *campaignJobLog) flush() error {
+	if l.buf.Len() > 0 {
+		if err := l.store.AppendCampaignJobLog(l.ctx, l.id, l.buf.String()); err != nil {
+			return errors.Wrap(err, "appending to campaign job log")
+		}
+		l.buf.Reset()
+	}
+	l.flushedAt = time.Now()
+	return l.err
+}
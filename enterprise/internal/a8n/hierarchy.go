@@ -0,0 +1,89 @@
+package a8n
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// SetCampaignParent makes the Campaign with the given parentID the parent of
+// the Campaign with the given ID, grouping it under the parent. A parentID of
+// 0 removes the Campaign from its current parent.
+//
+// It returns an *ErrCampaignParentCycle if the Campaign is the parent itself
+// or one of its ancestors.
+func (s *Service) SetCampaignParent(ctx context.Context, id, parentID int64) (campaign *a8n.Campaign, err error) {
+	traceTitle := fmt.Sprintf("campaign: %d, parent: %d", id, parentID)
+	tr, ctx := trace.New(ctx, "service.SetCampaignParent", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	campaign, err = getCampaign(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.ParentCampaignID == parentID {
+		return campaign, nil
+	}
+
+	campaign.ParentCampaignID = parentID
+	if err = checkCampaignParent(ctx, tx, campaign); err != nil {
+		return nil, err
+	}
+
+	return campaign, tx.UpdateCampaign(ctx, campaign)
+}
+
+// checkCampaignParent returns an *ErrCampaignNotFound if the parent of c
+// doesn't exist, and an *ErrCampaignParentCycle if c is one of the ancestors
+// of its parent.
+func checkCampaignParent(ctx context.Context, store CampaignsStore, c *a8n.Campaign) error {
+	seen := map[int64]bool{}
+	for id := c.ParentCampaignID; id != 0 && !seen[id]; {
+		if id == c.ID {
+			return &ErrCampaignParentCycle{ID: c.ID, ParentID: c.ParentCampaignID}
+		}
+		seen[id] = true
+
+		ancestor, err := getCampaign(ctx, store, id)
+		if err != nil {
+			return err
+		}
+		id = ancestor.ParentCampaignID
+	}
+	return nil
+}
+
+// closeChildCampaigns closes the children of the Campaign with the given ID
+// and, through closeCampaign, all the Campaigns below them.
+func closeChildCampaigns(ctx context.Context, tx *Store, id int64, closeChangesets bool) error {
+	opts := ListCampaignsOpts{ParentCampaignID: id}
+	for {
+		children, next, err := tx.ListCampaigns(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, child := range children {
+			if _, err := closeCampaign(ctx, tx, child.ID, closeChangesets); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		opts.Cursor = next
+	}
+}
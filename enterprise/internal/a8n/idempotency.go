@@ -0,0 +1,74 @@
+package a8n
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// WithIdempotencyKey calls run, which runs the mutation with the given name
+// for the given user and returns the IDs of the entities it created, unless
+// the user already ran the mutation with the same idempotency key within
+// IdempotencyKeyTTL. In that case, run isn't called and the IDs returned by
+// the earlier run are returned instead.
+//
+// If key is nil or empty, run is always called.
+func (s *Service) WithIdempotencyKey(ctx context.Context, userID int32, mutation string, key *string, run func() ([]int64, error)) ([]int64, error) {
+	if key == nil || *key == "" {
+		return run()
+	}
+
+	opts := GetIdempotencyKeyOpts{UserID: userID, Mutation: mutation, Key: *key}
+	k := &a8n.IdempotencyKey{UserID: userID, Mutation: mutation, Key: *key}
+
+	created, err := s.store.CreateIdempotencyKey(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		existing, err := s.store.GetIdempotencyKey(ctx, opts)
+		if err == ErrNoResults {
+			// The existing key expired but hasn't been deleted yet.
+			if err = s.store.DeleteIdempotencyKey(ctx, opts); err != nil {
+				return nil, err
+			}
+			if created, err = s.store.CreateIdempotencyKey(ctx, k); err == nil && !created {
+				return nil, &ErrIdempotencyKeyInUse{Key: *key}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil {
+			if existing.ResultIDs == nil {
+				return nil, &ErrIdempotencyKeyInUse{Key: *key}
+			}
+			return existing.ResultIDs, nil
+		}
+	}
+
+	ids, err := run()
+	if err != nil {
+		// Failed mutations can be retried with the same key.
+		if err := s.store.DeleteIdempotencyKey(ctx, opts); err != nil {
+			log15.Error("Deleting idempotency key of failed mutation", "mutation", mutation, "err", err)
+		}
+		return nil, err
+	}
+
+	k.ResultIDs = ids
+	if k.ResultIDs == nil {
+		k.ResultIDs = []int64{}
+	}
+	if err := s.store.UpdateIdempotencyKeyResult(ctx, k); err != nil {
+		// The mutation succeeded, so we don't fail the request. Retries with
+		// the same key fail with ErrIdempotencyKeyInUse until the key
+		// expires.
+		log15.Error("Saving result of idempotency key", "mutation", mutation, "err", err)
+	}
+
+	return ids, nil
+}
@@ -21,8 +21,12 @@ func TestIntegration(t *testing.T) {
 
 	t.Run("Store", testStore(db))
 	t.Run("GitHubWebhook", testGitHubWebhook(db))
+	t.Run("GitLabWebhook", testGitLabWebhook(db))
+	t.Run("BitbucketServerWebhook", testBitbucketServerWebhook(db))
+	t.Run("StoreQueryPlans", testStoreQueryPlans(db))
 
 	// The following tests need to be separate because testStore above wraps everything in a global transaction
 	t.Run("StoreLocking", testStoreLocking(db))
+	t.Run("WorkerJobQueue", testWorkerJobQueue(db))
 	t.Run("ProcessCampaignJob", testProcessCampaignJob(db))
 }
@@ -0,0 +1,57 @@
+package a8n
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// CampaignMetricsCache caches the results of Store.GetCampaignMetrics for the
+// remainder of the day (in UTC) on which they were computed, since computing
+// them requires aggregating over all Campaigns and ChangesetEvents.
+//
+// The zero value is ready to use.
+type CampaignMetricsCache struct {
+	mu      sync.Mutex
+	day     time.Time
+	entries map[campaignMetricsKey][]*a8n.CampaignMetricsPeriod
+}
+
+type campaignMetricsKey struct {
+	period  a8n.CampaignMetricsPeriodType
+	periods int
+}
+
+// Get returns the last periods campaign metrics periods of the given type,
+// computing them with the given Store if they haven't been computed today.
+func (c *CampaignMetricsCache) Get(ctx context.Context, s *Store, period a8n.CampaignMetricsPeriodType, periods int) ([]*a8n.CampaignMetricsPeriod, error) {
+	now := s.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	key := campaignMetricsKey{period: period, periods: periods}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.day.Equal(day) {
+		c.day = day
+		c.entries = map[campaignMetricsKey][]*a8n.CampaignMetricsPeriod{}
+	}
+
+	if ps, ok := c.entries[key]; ok {
+		return ps, nil
+	}
+
+	ps, err := s.GetCampaignMetrics(ctx, GetCampaignMetricsOpts{
+		Period:  period,
+		Periods: periods,
+		Now:     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[key] = ps
+	return ps, nil
+}
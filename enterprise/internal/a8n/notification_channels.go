@@ -0,0 +1,185 @@
+package a8n
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// notificationWebhookTimeout is the time after which posting a digest to a
+// Slack or generic webhook is aborted.
+const notificationWebhookTimeout = 30 * time.Second
+
+// A NotificationChannel sends CampaignNotificationDigests to their recipients
+// through a transport, e.g. email.
+type NotificationChannel interface {
+	// Send sends the given digest of the CampaignNotifications of the given
+	// user.
+	Send(ctx context.Context, user *types.User, d *CampaignNotificationDigest) error
+}
+
+// NewNotificationChannel returns the NotificationChannel through which the
+// CampaignNotifications about the Campaigns of the namespace with the given
+// settings are sent. An error is returned if the settings have an invalid
+// channel or webhook URL. Deliveries to webhooks are recorded in the given
+// Store, unless it's nil.
+func NewNotificationChannel(s *Store, ns *a8n.CampaignNamespaceSettings) (NotificationChannel, error) {
+	switch ns.NotificationChannel {
+	case a8n.CampaignNotificationChannelEmail, "":
+		return emailNotificationChannel{}, nil
+	case a8n.CampaignNotificationChannelSlack:
+		if err := validateNotificationWebhookURL(ns.NotificationWebhookURL); err != nil {
+			return nil, err
+		}
+		return slackNotificationChannel{store: s, url: ns.NotificationWebhookURL}, nil
+	case a8n.CampaignNotificationChannelWebhook:
+		if err := validateNotificationWebhookURL(ns.NotificationWebhookURL); err != nil {
+			return nil, err
+		}
+		return webhookNotificationChannel{store: s, url: ns.NotificationWebhookURL}, nil
+	default:
+		return nil, errors.Errorf("invalid notification channel %q", ns.NotificationChannel)
+	}
+}
+
+func validateNotificationWebhookURL(rawurl string) error {
+	if rawurl == "" {
+		return errors.New("notification webhook URL must not be empty")
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return errors.Wrap(err, "invalid notification webhook URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("notification webhook URL %q must be an http or https URL", rawurl)
+	}
+	return nil
+}
+
+// emailNotificationChannel sends the digests to the verified primary email
+// address of their recipients. Digests of users without one, and all digests
+// while email isn't configured in site configuration, are dropped.
+type emailNotificationChannel struct{}
+
+func (emailNotificationChannel) Send(ctx context.Context, user *types.User, d *CampaignNotificationDigest) error {
+	if conf.Get().EmailSmtp == nil {
+		return nil
+	}
+
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, user.ID)
+	if errcode.IsNotFound(err) || (err == nil && !verified) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting primary email")
+	}
+
+	return txemail.Send(ctx, txemail.Message{
+		To:       []string{email},
+		Template: campaignNotificationDigestTemplates,
+		Data:     d,
+	})
+}
+
+// slackNotificationChannel posts the digests as messages mentioning their
+// recipients to a Slack incoming webhook.
+type slackNotificationChannel struct {
+	store *Store
+	url   string
+}
+
+func (c slackNotificationChannel) Send(ctx context.Context, user *types.User, d *CampaignNotificationDigest) error {
+	payload := map[string]string{"text": slackNotificationText(user, d)}
+	return deliverNotificationWebhook(ctx, c.store, a8n.CampaignNotificationChannelSlack, c.url, user, payload)
+}
+
+// slackEscaper escapes the characters that Slack interprets as control
+// sequences in message text.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackNotificationText(user *types.User, d *CampaignNotificationDigest) string {
+	var b strings.Builder
+
+	updates := "updates"
+	if d.Count == 1 {
+		updates = "update"
+	}
+	fmt.Fprintf(&b, "%d %s to campaigns of %s:\n", d.Count, updates, slackEscaper.Replace(user.Username))
+
+	for _, c := range d.Campaigns {
+		fmt.Fprintf(&b, "\n*<%s|%s>*\n", c.URL, slackEscaper.Replace(c.Name))
+		for _, cs := range c.Changesets {
+			title := slackEscaper.Replace(cs.Title)
+			if cs.URL != "" {
+				title = fmt.Sprintf("<%s|%s>", cs.URL, title)
+			}
+			fmt.Fprintf(&b, "• %s: %s %s\n", slackEscaper.Replace(cs.Repository), title, cs.Event)
+		}
+	}
+
+	return b.String()
+}
+
+// webhookNotificationChannel posts the digests as JSON to a URL.
+type webhookNotificationChannel struct {
+	store *Store
+	url   string
+}
+
+// webhookNotificationPayload is the JSON body posted by the
+// webhookNotificationChannel.
+type webhookNotificationPayload struct {
+	Recipient string `json:"recipient"`
+	*CampaignNotificationDigest
+}
+
+func (c webhookNotificationChannel) Send(ctx context.Context, user *types.User, d *CampaignNotificationDigest) error {
+	payload := webhookNotificationPayload{
+		Recipient:                  user.Username,
+		CampaignNotificationDigest: d,
+	}
+	return deliverNotificationWebhook(ctx, c.store, a8n.CampaignNotificationChannelWebhook, c.url, user, payload)
+}
+
+// postNotificationWebhook posts the given JSON body to the given URL and
+// returns the status and the beginning of the body of the response. An error
+// is returned unless the webhook responds with a 2xx status.
+func postNotificationWebhook(ctx context.Context, webhookURL string, body []byte) (status int, respBody string, err error) {
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(ctx, notificationWebhookTimeout)
+	defer cancel()
+
+	resp, err := ctxhttp.Do(ctx, http.DefaultClient, req)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "posting to notification webhook")
+	}
+	defer resp.Body.Close()
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	respBody = strings.TrimSpace(string(msg))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, respBody, errors.Errorf("notification webhook responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	return resp.StatusCode, respBody, nil
+}
@@ -0,0 +1,181 @@
+package a8n
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+func TestNewNotificationChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		ns      *a8n.CampaignNamespaceSettings
+		want    NotificationChannel
+		wantErr bool
+	}{
+		{
+			name: "default",
+			ns:   &a8n.CampaignNamespaceSettings{},
+			want: emailNotificationChannel{},
+		},
+		{
+			name: "email",
+			ns:   &a8n.CampaignNamespaceSettings{NotificationChannel: a8n.CampaignNotificationChannelEmail},
+			want: emailNotificationChannel{},
+		},
+		{
+			name: "slack",
+			ns: &a8n.CampaignNamespaceSettings{
+				NotificationChannel:    a8n.CampaignNotificationChannelSlack,
+				NotificationWebhookURL: "https://hooks.slack.com/services/T0/B0/X",
+			},
+			want: slackNotificationChannel{url: "https://hooks.slack.com/services/T0/B0/X"},
+		},
+		{
+			name: "webhook",
+			ns: &a8n.CampaignNamespaceSettings{
+				NotificationChannel:    a8n.CampaignNotificationChannelWebhook,
+				NotificationWebhookURL: "http://example.com/hook",
+			},
+			want: webhookNotificationChannel{url: "http://example.com/hook"},
+		},
+		{
+			name:    "webhook without URL",
+			ns:      &a8n.CampaignNamespaceSettings{NotificationChannel: a8n.CampaignNotificationChannelWebhook},
+			wantErr: true,
+		},
+		{
+			name: "webhook with non-HTTP URL",
+			ns: &a8n.CampaignNamespaceSettings{
+				NotificationChannel:    a8n.CampaignNotificationChannelSlack,
+				NotificationWebhookURL: "file:///etc/passwd",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid channel",
+			ns:      &a8n.CampaignNamespaceSettings{NotificationChannel: "CARRIER_PIGEON"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := NewNotificationChannel(nil, tc.ns)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("have err %v, want error: %t", err, tc.wantErr)
+			}
+			if have != tc.want {
+				t.Fatalf("have channel %#v, want %#v", have, tc.want)
+			}
+		})
+	}
+}
+
+func testCampaignNotificationDigest() *CampaignNotificationDigest {
+	return &CampaignNotificationDigest{
+		Count: 2,
+		Campaigns: []*CampaignNotificationDigestCampaign{{
+			Name: "Upgrade <ESLint>",
+			URL:  "https://sourcegraph.example.com/campaigns/Q2FtcGFpZ246MQ==",
+			Changesets: []*CampaignNotificationDigestChangeset{
+				{
+					Repository: "github.com/sourcegraph/sourcegraph",
+					Title:      "Upgrade to ESLint 6",
+					URL:        "https://github.com/sourcegraph/sourcegraph/pull/1",
+					Kind:       a8n.CampaignNotificationKindMerged,
+					Event:      campaignNotificationEvents[a8n.CampaignNotificationKindMerged],
+				},
+				{
+					Repository: "github.com/sourcegraph/about",
+					Title:      "Lint & fix",
+					Kind:       a8n.CampaignNotificationKindChecksFailed,
+					Event:      campaignNotificationEvents[a8n.CampaignNotificationKindChecksFailed],
+				},
+			},
+		}},
+	}
+}
+
+func TestSlackNotificationText(t *testing.T) {
+	have := slackNotificationText(&types.User{Username: "alice"}, testCampaignNotificationDigest())
+	want := `2 updates to campaigns of alice:
+
+*<https://sourcegraph.example.com/campaigns/Q2FtcGFpZ246MQ==|Upgrade &lt;ESLint&gt;>*
+• github.com/sourcegraph/sourcegraph: <https://github.com/sourcegraph/sourcegraph/pull/1|Upgrade to ESLint 6> was merged
+• github.com/sourcegraph/about: Lint &amp; fix has failing checks
+`
+	if diff := cmp.Diff(have, want); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestWebhookNotificationChannel(t *testing.T) {
+	var (
+		status   = http.StatusOK
+		received json.RawMessage
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("have content type %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	user := &types.User{Username: "alice"}
+	channel := webhookNotificationChannel{url: srv.URL}
+
+	if err := channel.Send(ctx, user, testCampaignNotificationDigest()); err != nil {
+		t.Fatal(err)
+	}
+
+	var have map[string]interface{}
+	if err := json.Unmarshal(received, &have); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"recipient": "alice",
+		"count":     float64(2),
+		"campaigns": []interface{}{map[string]interface{}{
+			"name": "Upgrade <ESLint>",
+			"url":  "https://sourcegraph.example.com/campaigns/Q2FtcGFpZ246MQ==",
+			"changesets": []interface{}{
+				map[string]interface{}{
+					"repository": "github.com/sourcegraph/sourcegraph",
+					"title":      "Upgrade to ESLint 6",
+					"url":        "https://github.com/sourcegraph/sourcegraph/pull/1",
+					"kind":       "MERGED",
+					"event":      "was merged",
+				},
+				map[string]interface{}{
+					"repository": "github.com/sourcegraph/about",
+					"title":      "Lint & fix",
+					"url":        "",
+					"kind":       "CHECKS_FAILED",
+					"event":      "has failing checks",
+				},
+			},
+		}},
+	}
+	if diff := cmp.Diff(have, want); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Failed deliveries are reported, so that the notifications are sent
+	// with the next digest.
+	status = http.StatusInternalServerError
+	if err := channel.Send(ctx, user, testCampaignNotificationDigest()); err == nil {
+		t.Fatal("have no error for failed delivery")
+	}
+}
@@ -0,0 +1,355 @@
+package a8n
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// changesetNotificationState is the part of the state of a Changeset whose
+// changes the authors and subscribers of its Campaigns are notified about.
+type changesetNotificationState struct {
+	state  a8n.ChangesetState
+	checks a8n.ChangesetCheckState
+}
+
+// newChangesetNotificationState returns the notification state of the given
+// Changeset, whose check state is computed with the given events. It returns
+// false if the Changeset has no valid state, e.g. because it was never synced.
+func newChangesetNotificationState(c *a8n.Changeset, events []*a8n.ChangesetEvent) (changesetNotificationState, bool) {
+	state, err := c.State()
+	if err != nil {
+		return changesetNotificationState{}, false
+	}
+	return changesetNotificationState{state: state, checks: a8n.ComputeCheckState(c, events)}, true
+}
+
+// campaignNotificationKinds returns the kinds of the CampaignNotifications
+// caused by a change of a Changeset from the state before to the one after.
+func campaignNotificationKinds(before, after changesetNotificationState) []a8n.CampaignNotificationKind {
+	var kinds []a8n.CampaignNotificationKind
+
+	if after.state != before.state {
+		switch after.state {
+		case a8n.ChangesetStateMerged:
+			kinds = append(kinds, a8n.CampaignNotificationKindMerged)
+		case a8n.ChangesetStateClosed:
+			kinds = append(kinds, a8n.CampaignNotificationKindClosed)
+		}
+	}
+
+	// Checks that fail after a changeset was merged or closed don't need the
+	// attention of the campaign's authors anymore.
+	if after.state == a8n.ChangesetStateOpen && after.checks == a8n.ChangesetCheckStateFailed && before.checks != a8n.ChangesetCheckStateFailed {
+		kinds = append(kinds, a8n.CampaignNotificationKindChecksFailed)
+	}
+
+	return kinds
+}
+
+// changesetNotification is a change of a Changeset for which
+// CampaignNotifications are enqueued.
+type changesetNotification struct {
+	changesetID int64
+	kind        a8n.CampaignNotificationKind
+}
+
+// RunCampaignNotificationDigests should run in a background goroutine and is
+// responsible for sending the due CampaignNotifications of each user in a
+// single digest per NotificationChannel, and for deleting expired ones.
+// ctx should be canceled to terminate the function
+func RunCampaignNotificationDigests(ctx context.Context, s *Store, backoffDuration time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if err := sendCampaignNotificationDigests(ctx, s); err != nil {
+				log15.Error("Sending campaign notification digests", "err", err)
+			}
+			if err := s.DeleteExpiredCampaignNotifications(ctx); err != nil {
+				log15.Error("Deleting expired campaign notifications", "err", err)
+			}
+			if err := s.DeleteExpiredCampaignWebhookDeliveries(ctx); err != nil {
+				log15.Error("Deleting expired campaign webhook deliveries", "err", err)
+			}
+			time.Sleep(backoffDuration)
+		}
+	}
+}
+
+func sendCampaignNotificationDigests(ctx context.Context, s *Store) error {
+	userIDs, err := s.ListDueCampaignNotificationUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range userIDs {
+		if err := sendCampaignNotificationDigest(ctx, s, id); err != nil {
+			log15.Warn("Sending campaign notification digest failed", "user", id, "err", err)
+		}
+	}
+	return nil
+}
+
+// sendCampaignNotificationDigest sends the unsent CampaignNotifications of
+// the given user through the NotificationChannels of the namespaces of their
+// Campaigns, with one digest per channel. The notifications of the digests
+// that couldn't be sent are sent with the next digest.
+func sendCampaignNotificationDigest(ctx context.Context, s *Store, userID int32) error {
+	ns, err := s.MarkCampaignNotificationsSent(ctx, userID)
+	if err != nil || len(ns) == 0 {
+		return err
+	}
+
+	unsent := make([]int64, len(ns))
+	for i, n := range ns {
+		unsent[i] = n.ID
+	}
+	defer func() {
+		if len(unsent) == 0 {
+			return
+		}
+		if err := s.ResetCampaignNotifications(ctx, unsent...); err != nil {
+			log15.Error("Resetting campaign notifications", "user", userID, "err", err)
+		}
+	}()
+
+	user, err := db.Users.GetByID(ctx, userID)
+	if errcode.IsNotFound(err) {
+		unsent = nil
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting user")
+	}
+
+	data, err := newCampaignNotificationDigestData(ctx, s, userID, ns)
+	if err != nil {
+		return err
+	}
+
+	digests, err := splitCampaignNotificationDigest(ctx, s, data)
+	if err != nil {
+		return err
+	}
+
+	unsent = nil
+	var errs *multierror.Error
+	for _, d := range digests {
+		if err := d.channel.Send(ctx, user, d.digest); err != nil {
+			errs = multierror.Append(errs, err)
+			for _, c := range d.digest.Campaigns {
+				unsent = append(unsent, c.notificationIDs...)
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// CampaignNotificationDigest is the digest of the CampaignNotifications of a
+// user that is sent through a NotificationChannel, grouped by Campaign.
+type CampaignNotificationDigest struct {
+	Count     int                                   `json:"count"`
+	Campaigns []*CampaignNotificationDigestCampaign `json:"campaigns"`
+}
+
+// CampaignNotificationDigestCampaign is a Campaign in a
+// CampaignNotificationDigest with its changed Changesets.
+type CampaignNotificationDigestCampaign struct {
+	Name       string                                 `json:"name"`
+	URL        string                                 `json:"url"`
+	Changesets []*CampaignNotificationDigestChangeset `json:"changesets"`
+
+	namespace       GetCampaignNamespaceSettingsOpts
+	notificationIDs []int64
+}
+
+// CampaignNotificationDigestChangeset is a change of a Changeset in a
+// CampaignNotificationDigest.
+type CampaignNotificationDigestChangeset struct {
+	Repository string                       `json:"repository"`
+	Title      string                       `json:"title"`
+	URL        string                       `json:"url"`
+	Kind       a8n.CampaignNotificationKind `json:"kind"`
+	Event      string                       `json:"event"`
+}
+
+// campaignNotificationEvents describe the kinds of CampaignNotifications in
+// the digests.
+var campaignNotificationEvents = map[a8n.CampaignNotificationKind]string{
+	a8n.CampaignNotificationKindMerged:       "was merged",
+	a8n.CampaignNotificationKindClosed:       "was closed",
+	a8n.CampaignNotificationKindChecksFailed: "has failing checks",
+}
+
+// newCampaignNotificationDigestData returns the digest of the given
+// CampaignNotifications of a user, grouped by Campaign. Notifications about
+// Campaigns that were deleted in the meantime and Changesets in repositories
+// that the user can't access are left out.
+func newCampaignNotificationDigestData(ctx context.Context, s *Store, userID int32, ns []*a8n.CampaignNotification) (*CampaignNotificationDigest, error) {
+	changesetIDs := make([]int64, 0, len(ns))
+	for _, n := range ns {
+		changesetIDs = append(changesetIDs, n.ChangesetID)
+	}
+
+	cs, _, err := s.ListChangesets(ctx, ListChangesetsOpts{IDs: changesetIDs, Limit: -1})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing changesets")
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(cs))
+	for _, c := range cs {
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+
+	// 🚨 SECURITY: The repositories are listed as the user that receives the
+	// digest, so that it only mentions the changesets they can access.
+	rs, err := backend.Repos.List(actor.WithActor(ctx, actor.FromUser(userID)), db.ReposListOptions{IDs: repoIDs})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing repositories")
+	}
+
+	repoNames := make(map[api.RepoID]string, len(rs))
+	for _, r := range rs {
+		repoNames[r.ID] = string(r.Name)
+	}
+
+	changesets := make(map[int64]*CampaignNotificationDigestChangeset, len(cs))
+	for _, c := range cs {
+		name, ok := repoNames[c.RepoID]
+		if !ok {
+			continue
+		}
+
+		title, err := c.Title()
+		if err != nil {
+			title = fmt.Sprintf("#%s", c.ExternalID)
+		}
+		url, _ := c.URL()
+
+		changesets[c.ID] = &CampaignNotificationDigestChangeset{Repository: name, Title: title, URL: url}
+	}
+
+	type seenKey struct {
+		campaignID, changesetID int64
+		kind                    a8n.CampaignNotificationKind
+	}
+
+	var (
+		data      CampaignNotificationDigest
+		campaigns = map[int64]*CampaignNotificationDigestCampaign{}
+		seen      = map[seenKey]bool{}
+	)
+	for _, n := range ns {
+		changeset, ok := changesets[n.ChangesetID]
+		if !ok {
+			continue
+		}
+
+		campaign, ok := campaigns[n.CampaignID]
+		if !ok {
+			c, err := s.GetCampaign(ctx, GetCampaignOpts{ID: n.CampaignID})
+			if err == ErrNoResults {
+				campaigns[n.CampaignID] = nil
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, "getting campaign")
+			}
+
+			campaign = &CampaignNotificationDigestCampaign{
+				Name: c.Name,
+				URL:  campaignURL(c.ID),
+				namespace: GetCampaignNamespaceSettingsOpts{
+					NamespaceUserID: c.NamespaceUserID,
+					NamespaceOrgID:  c.NamespaceOrgID,
+				},
+			}
+			campaigns[n.CampaignID] = campaign
+			data.Campaigns = append(data.Campaigns, campaign)
+		}
+		if campaign == nil {
+			continue
+		}
+
+		campaign.notificationIDs = append(campaign.notificationIDs, n.ID)
+
+		key := seenKey{n.CampaignID, n.ChangesetID, n.Kind}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		event := *changeset
+		event.Kind = n.Kind
+		event.Event = campaignNotificationEvents[n.Kind]
+		campaign.Changesets = append(campaign.Changesets, &event)
+		data.Count++
+	}
+
+	return &data, nil
+}
+
+// channelCampaignNotificationDigest is the part of a
+// CampaignNotificationDigest that is sent through a NotificationChannel.
+type channelCampaignNotificationDigest struct {
+	channel NotificationChannel
+	digest  *CampaignNotificationDigest
+}
+
+// splitCampaignNotificationDigest splits the given CampaignNotificationDigest
+// by the NotificationChannels of the namespaces of its Campaigns. Namespaces
+// without CampaignNamespaceSettings use email.
+func splitCampaignNotificationDigest(ctx context.Context, s *Store, d *CampaignNotificationDigest) ([]*channelCampaignNotificationDigest, error) {
+	type channelKey struct {
+		channel a8n.CampaignNotificationChannel
+		url     string
+	}
+
+	var (
+		digests  []*channelCampaignNotificationDigest
+		channels = map[channelKey]*channelCampaignNotificationDigest{}
+		settings = map[GetCampaignNamespaceSettingsOpts]*a8n.CampaignNamespaceSettings{}
+	)
+	for _, c := range d.Campaigns {
+		ns, ok := settings[c.namespace]
+		if !ok {
+			var err error
+			ns, err = s.GetCampaignNamespaceSettings(ctx, c.namespace)
+			if err == ErrNoResults {
+				ns = &a8n.CampaignNamespaceSettings{NotificationChannel: a8n.CampaignNotificationChannelEmail}
+			} else if err != nil {
+				return nil, errors.Wrap(err, "getting campaign namespace settings")
+			}
+			settings[c.namespace] = ns
+		}
+
+		key := channelKey{ns.NotificationChannel, ns.NotificationWebhookURL}
+		cd, ok := channels[key]
+		if !ok {
+			channel, err := NewNotificationChannel(s, ns)
+			if err != nil {
+				return nil, err
+			}
+			cd = &channelCampaignNotificationDigest{channel: channel, digest: &CampaignNotificationDigest{}}
+			channels[key] = cd
+			digests = append(digests, cd)
+		}
+
+		cd.digest.Campaigns = append(cd.digest.Campaigns, c)
+		cd.digest.Count += len(c.Changesets)
+	}
+
+	return digests, nil
+}
@@ -0,0 +1,31 @@
+package a8n
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+var campaignNotificationDigestTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Count}} {{if eq .Count 1}}update{{else}}updates{{end}} to your campaigns on Sourcegraph`,
+	Text: `
+Changesets of your campaigns changed:
+{{range .Campaigns}}
+{{.Name}}
+{{.URL}}
+{{range .Changesets}}
+  {{.Repository}}: {{.Title}} {{.Event}}{{if .URL}}
+  {{.URL}}{{end}}
+{{end}}{{end}}
+You're receiving this email because you created or subscribed to these campaigns. To stop receiving it, unsubscribe on the page of a campaign or change your campaign notification settings.
+`,
+	HTML: `
+<p>Changesets of your campaigns changed:</p>
+{{range .Campaigns}}
+<p><strong><a href="{{.URL}}">{{.Name}}</a></strong></p>
+<ul>
+{{range .Changesets}}<li>{{.Repository}}: {{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}} {{.Event}}</li>
+{{end}}</ul>
+{{end}}
+<p style="color:#777">You're receiving this email because you created or subscribed to these campaigns. To stop receiving it, unsubscribe on the page of a campaign or change your campaign notification settings.</p>
+`,
+})
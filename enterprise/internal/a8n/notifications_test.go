@@ -0,0 +1,126 @@
+package a8n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+)
+
+func TestCampaignNotificationKinds(t *testing.T) {
+	state := func(s a8n.ChangesetState, c a8n.ChangesetCheckState) changesetNotificationState {
+		return changesetNotificationState{state: s, checks: c}
+	}
+
+	tests := []struct {
+		name          string
+		before, after changesetNotificationState
+		want          []a8n.CampaignNotificationKind
+	}{
+		{
+			name:   "unchanged",
+			before: state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePassed),
+			after:  state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePassed),
+		},
+		{
+			name:   "merged",
+			before: state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePassed),
+			after:  state(a8n.ChangesetStateMerged, a8n.ChangesetCheckStatePassed),
+			want:   []a8n.CampaignNotificationKind{a8n.CampaignNotificationKindMerged},
+		},
+		{
+			name:   "closed",
+			before: state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePending),
+			after:  state(a8n.ChangesetStateClosed, a8n.ChangesetCheckStatePending),
+			want:   []a8n.CampaignNotificationKind{a8n.CampaignNotificationKindClosed},
+		},
+		{
+			name:   "reopened",
+			before: state(a8n.ChangesetStateClosed, a8n.ChangesetCheckStatePassed),
+			after:  state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePassed),
+		},
+		{
+			name:   "checks failed",
+			before: state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePending),
+			after:  state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStateFailed),
+			want:   []a8n.CampaignNotificationKind{a8n.CampaignNotificationKindChecksFailed},
+		},
+		{
+			name:   "checks still failing",
+			before: state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStateFailed),
+			after:  state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStateFailed),
+		},
+		{
+			name:   "checks failed after merge",
+			before: state(a8n.ChangesetStateMerged, a8n.ChangesetCheckStatePassed),
+			after:  state(a8n.ChangesetStateMerged, a8n.ChangesetCheckStateFailed),
+		},
+		{
+			name:   "closed with failing checks",
+			before: state(a8n.ChangesetStateOpen, a8n.ChangesetCheckStatePending),
+			after:  state(a8n.ChangesetStateClosed, a8n.ChangesetCheckStateFailed),
+			want:   []a8n.CampaignNotificationKind{a8n.CampaignNotificationKindClosed},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			have := campaignNotificationKinds(tc.before, tc.after)
+			if diff := cmp.Diff(have, tc.want); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestCampaignNotificationDigestTemplates(t *testing.T) {
+	m, err := txemail.Render(txemail.Message{
+		To:       []string{"alice@example.com"},
+		Template: campaignNotificationDigestTemplates,
+		Data: &CampaignNotificationDigest{
+			Count: 2,
+			Campaigns: []*CampaignNotificationDigestCampaign{{
+				Name: "Upgrade ESLint",
+				URL:  "https://sourcegraph.example.com/campaigns/Q2FtcGFpZ246MQ==",
+				Changesets: []*CampaignNotificationDigestChangeset{
+					{
+						Repository: "github.com/sourcegraph/sourcegraph",
+						Title:      "Upgrade to ESLint 6",
+						URL:        "https://github.com/sourcegraph/sourcegraph/pull/1",
+						Event:      campaignNotificationEvents[a8n.CampaignNotificationKindMerged],
+					},
+					{
+						Repository: "github.com/sourcegraph/about",
+						Title:      "Upgrade to ESLint 6",
+						Event:      campaignNotificationEvents[a8n.CampaignNotificationKindChecksFailed],
+					},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "2 updates to your campaigns on Sourcegraph"; m.Subject != want {
+		t.Errorf("have subject %q, want %q", m.Subject, want)
+	}
+
+	for _, body := range []string{m.Body, m.HTMLBody} {
+		for _, want := range []string{
+			"Upgrade ESLint",
+			"/campaigns/Q2FtcGFpZ246MQ==",
+			"github.com/sourcegraph/sourcegraph: ",
+			"/pull/1",
+			"was merged",
+			"github.com/sourcegraph/about: ",
+			"has failing checks",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("body doesn't contain %q:\n%s", want, body)
+			}
+		}
+	}
+}
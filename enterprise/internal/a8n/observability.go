@@ -0,0 +1,69 @@
+package a8n
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+var (
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "src",
+		Subsystem: "campaigns",
+		Name:      "operation_duration_seconds",
+		Help:      "Time spent on campaign operations of the Service and the Store.",
+	}, []string{"op", "success"})
+
+	workerJobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "src",
+		Subsystem: "campaigns",
+		Name:      "worker_job_duration_seconds",
+		Help:      "Time spent processing worker jobs, such as changeset jobs.",
+	}, []string{"queue", "success"})
+
+	workerJobsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "campaigns",
+		Name:      "worker_jobs_total",
+		Help:      "Total number of processed worker jobs, such as changeset jobs.",
+	}, []string{"queue", "success"})
+)
+
+func init() {
+	prometheus.MustRegister(operationDuration)
+	prometheus.MustRegister(workerJobDuration)
+	prometheus.MustRegister(workerJobsTotal)
+}
+
+// observeOperation records the duration of the operation op, which began at
+// the given time and failed if *err isn't nil. It's meant to be deferred.
+func observeOperation(op string, began time.Time, err *error) {
+	success := strconv.FormatBool(err == nil || *err == nil)
+	operationDuration.WithLabelValues(op, success).Observe(time.Since(began).Seconds())
+}
+
+// observeStore starts a trace span for the Store operation op and returns a
+// function that finishes it and records the duration of the operation, which
+// failed if *err isn't nil.
+func observeStore(ctx context.Context, op string) (context.Context, func(err *error)) {
+	op = "Store." + op
+	tr, ctx := trace.New(ctx, op, "")
+	began := time.Now()
+
+	return ctx, func(err *error) {
+		observeOperation(op, began, err)
+		tr.SetError(*err)
+		tr.Finish()
+	}
+}
+
+// observeWorkerJob records the processing of a WorkerJob of the given queue,
+// which began at the given time and failed if err isn't nil.
+func observeWorkerJob(queue string, began time.Time, err error) {
+	success := strconv.FormatBool(err == nil)
+	workerJobDuration.WithLabelValues(queue, success).Observe(time.Since(began).Seconds())
+	workerJobsTotal.WithLabelValues(queue, success).Inc()
+}
@@ -0,0 +1,156 @@
+package a8n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// MoveCampaign moves the Campaign with the given ID right before the Campaign
+// with the given beforeID in the ordering by position of the Campaigns in the
+// same state, e.g. a column of a board. A beforeID of 0 moves it after all of
+// them.
+//
+// Only the position of the moved Campaign changes. It returns an
+// *ErrCampaignStateChange if the Campaign with beforeID is in another state.
+func (s *Service) MoveCampaign(ctx context.Context, id, beforeID int64) (campaign *a8n.Campaign, err error) {
+	traceTitle := fmt.Sprintf("campaign: %d, before: %d", id, beforeID)
+	tr, ctx := trace.New(ctx, "service.MoveCampaign", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	campaign, err = getCampaign(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if beforeID == id {
+		return campaign, nil
+	}
+
+	opts := GetPreviousCampaignPositionOpts{State: campaignState(campaign), ExcludeID: id}
+
+	var next string
+	if beforeID != 0 {
+		before, err := getCampaign(ctx, tx, beforeID)
+		if err != nil {
+			return nil, err
+		}
+		if campaignState(before) != opts.State {
+			return nil, &ErrCampaignStateChange{ID: id, BeforeID: beforeID}
+		}
+		next = before.Position
+		opts.Position = before.Position
+	}
+
+	prev, err := tx.GetPreviousCampaignPosition(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting previous position")
+	}
+
+	campaign.Position, err = campaignPositionBetween(prev, next)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, tx.UpdateCampaignPosition(ctx, campaign)
+}
+
+func campaignState(c *a8n.Campaign) a8n.CampaignState {
+	if c.ClosedAt.IsZero() {
+		return a8n.CampaignStateOpen
+	}
+	return a8n.CampaignStateClosed
+}
+
+// campaignPositionDigits are the digits of campaign positions, in ascending
+// byte order, so that positions compare like the fractions they represent.
+// Positions are fractional indexes: the digits after the point of a number
+// between 0 and 1 in base 62. Since there is always another fraction between
+// two of them, a campaign can be moved by updating its own position only.
+const campaignPositionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// campaignPositionAt returns the initial position of a campaign created at
+// the given time, which orders campaigns by creation until they're moved.
+// The migration that added positions computes the same ones.
+func campaignPositionAt(t time.Time) string {
+	return fmt.Sprintf("%014x", t.UnixNano()/int64(time.Microsecond)) + "V"
+}
+
+// campaignPositionBetween returns a position between the positions a and b,
+// where an empty a is before all positions and an empty b after all of them.
+// The result is as short as possible and never ends with the digit 0, which
+// would leave no room before it.
+func campaignPositionBetween(a, b string) (string, error) {
+	for _, p := range []string{a, b} {
+		if strings.HasSuffix(p, "0") || strings.Trim(p, campaignPositionDigits) != "" {
+			return "", errors.Errorf("invalid campaign position %q", p)
+		}
+	}
+	if b != "" && a >= b {
+		return "", errors.Errorf("campaign position %q is not before %q", a, b)
+	}
+	return positionMidpoint(a, b), nil
+}
+
+// positionMidpoint returns a position between the valid positions a < b. See
+// campaignPositionBetween.
+func positionMidpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, treating the missing digits of a as zeros.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			var rest string
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + positionMidpoint(rest, b[n:])
+		}
+	}
+
+	da, db := 0, len(campaignPositionDigits)
+	if a != "" {
+		da = strings.IndexByte(campaignPositionDigits, a[0])
+	}
+	if b != "" {
+		db = strings.IndexByte(campaignPositionDigits, b[0])
+	}
+
+	if db-da > 1 {
+		return string(campaignPositionDigits[(da+db+1)/2])
+	}
+
+	// The first digits are consecutive, so either the first digit of b
+	// alone is between a and b, or the result starts with the one of a.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	var rest string
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(campaignPositionDigits[da]) + positionMidpoint(rest, "")
+}
+
+func digitAt(p string, i int) byte {
+	if i < len(p) {
+		return p[i]
+	}
+	return campaignPositionDigits[0]
+}
@@ -0,0 +1,84 @@
+package a8n
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCampaignPositionBetween(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    string
+		wantErr bool
+	}{
+		{a: "", b: "", want: "V"},
+		{a: "V", b: "", want: "l"},
+		{a: "", b: "V", want: "G"},
+		{a: "", b: "0V", want: "0G"},
+		{a: "1", b: "2V", want: "2"},
+		{a: "V", b: "W", want: "VV"},
+		{a: "z", b: "", want: "zV"},
+		{a: "0z", b: "1", want: "0zV"},
+		{a: "W", b: "V", wantErr: true},
+		{a: "V", b: "V", wantErr: true},
+		{a: "V0", b: "", wantErr: true},
+		{a: "", b: "V-", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		have, err := campaignPositionBetween(tc.a, tc.b)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("between %q and %q: want error, have %q", tc.a, tc.b, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("between %q and %q: unexpected error: %s", tc.a, tc.b, err)
+			continue
+		}
+		if have != tc.want {
+			t.Errorf("between %q and %q: have %q, want %q", tc.a, tc.b, have, tc.want)
+		}
+	}
+
+	t.Run("random moves", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		positions := []string{campaignPositionAt(time.Now())}
+		for i := 0; i < 1000; i++ {
+			sort.Strings(positions)
+
+			var a, b string
+			if k := r.Intn(len(positions) + 1); k > 0 {
+				a = positions[k-1]
+				if k < len(positions) {
+					b = positions[k]
+				}
+			} else {
+				b = positions[0]
+			}
+
+			p, err := campaignPositionBetween(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p <= a || (b != "" && p >= b) {
+				t.Fatalf("position %q is not between %q and %q", p, a, b)
+			}
+			positions = append(positions, p)
+		}
+	})
+}
+
+func TestCampaignPositionAt(t *testing.T) {
+	earlier := campaignPositionAt(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	later := campaignPositionAt(time.Date(2020, 1, 1, 0, 0, 0, 1000, time.UTC))
+	if earlier >= later {
+		t.Errorf("position %q of earlier campaign is not before %q", earlier, later)
+	}
+	if _, err := campaignPositionBetween(earlier, later); err != nil {
+		t.Errorf("invalid initial position: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package a8n
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// OverrideQuotas disables the enforcement of the "automation.quotas" site
+// configuration for all operations of the Service if override is true.
+//
+// 🚨 SECURITY: Callers must make sure that only site admins can override
+// quotas.
+func (s *Service) OverrideQuotas(override bool) {
+	s.overrideQuotas = override
+}
+
+// checkOpenCampaignsQuota returns an *ErrQuotaExceeded if opening c would
+// exceed the maximum number of open Campaigns in its namespace. The given
+// store is used to count the open Campaigns, so that the check can be made in
+// the transaction in which c is opened.
+func (s *Service) checkOpenCampaignsQuota(ctx context.Context, store CampaignsStore, c *a8n.Campaign) error {
+	limit := conf.AutomationQuotas().MaxOpenCampaignsPerNamespace
+	if s.overrideQuotas || limit <= 0 {
+		return nil
+	}
+
+	opts := CountCampaignsOpts{State: a8n.CampaignStateOpen}
+	if c.NamespaceUserID != 0 {
+		opts.NamespaceUserIDs = []int32{c.NamespaceUserID}
+	} else {
+		opts.NamespaceOrgIDs = []int32{c.NamespaceOrgID}
+	}
+
+	open, err := store.CountCampaigns(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "counting open campaigns")
+	}
+
+	if requested := int(open) + 1; requested > limit {
+		return &ErrQuotaExceeded{
+			Quota:     QuotaOpenCampaignsPerNamespace,
+			Limit:     limit,
+			Requested: requested,
+		}
+	}
+	return nil
+}
+
+// CheckChangesetsQuota returns an *ErrQuotaExceeded if adding n changesets to
+// c would exceed the maximum number of changesets per Campaign.
+//
+// Both the ChangesetJobs and the ChangesetIDs of c are taken into account, so
+// the check applies to Campaigns that create their own changesets as well as
+// to Campaigns that changesets are added to manually.
+func (s *Service) CheckChangesetsQuota(ctx context.Context, store *Store, c *a8n.Campaign, n int) error {
+	limit := conf.AutomationQuotas().MaxChangesetsPerCampaign
+	if s.overrideQuotas || limit <= 0 {
+		return nil
+	}
+
+	jobs, err := store.CountChangesetJobs(ctx, CountChangesetJobsOpts{CampaignID: c.ID})
+	if err != nil {
+		return errors.Wrap(err, "counting changeset jobs")
+	}
+
+	existing := int(jobs)
+	if len(c.ChangesetIDs) > existing {
+		existing = len(c.ChangesetIDs)
+	}
+
+	if requested := existing + n; requested > limit {
+		return &ErrQuotaExceeded{
+			Quota:     QuotaChangesetsPerCampaign,
+			Limit:     limit,
+			Requested: requested,
+		}
+	}
+	return nil
+}
+
+func isQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrQuotaExceeded)
+	return ok
+}
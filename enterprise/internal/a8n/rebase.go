@@ -0,0 +1,276 @@
+package a8n
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/campaign-executor/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	gitprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// autoRebaseChangesetJobs returns the ChangesetJobs that created the given
+// Changeset for its open Campaigns with AutoRebase.
+func autoRebaseChangesetJobs(ctx context.Context, s *Store, c *a8n.Changeset) ([]*a8n.ChangesetJob, error) {
+	var jobs []*a8n.ChangesetJob
+	for _, id := range c.CampaignIDs {
+		campaign, err := s.GetCampaign(ctx, GetCampaignOpts{ID: id})
+		if err == ErrNoResults {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "getting campaign")
+		}
+		if !campaign.AutoRebase || !campaign.ClosedAt.IsZero() {
+			continue
+		}
+
+		// Changesets that were added to the campaign manually have no
+		// ChangesetJob and aren't rebased, since their branch isn't ours.
+		job, err := s.GetChangesetJob(ctx, GetChangesetJobOpts{CampaignID: id, ChangesetID: c.ID})
+		if err == ErrNoResults {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "getting changeset job")
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// enqueueChangesetRebases enqueues the given ChangesetJobs in
+// ChangesetRebasesQueue.
+func enqueueChangesetRebases(ctx context.Context, s *Store, jobs []*a8n.ChangesetJob) error {
+	for _, job := range jobs {
+		payload, err := json.Marshal(changesetJobPayload{ChangesetJobID: job.ID})
+		if err != nil {
+			return err
+		}
+
+		err = s.EnqueueWorkerJob(ctx, &a8n.WorkerJob{
+			Queue:   ChangesetRebasesQueue,
+			Payload: payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunChangesetRebases should run in a background goroutine and is
+// responsible for rebasing the changesets of the ChangesetJobs in
+// ChangesetRebasesQueue onto the current head of their base branch.
+// ctx should be canceled to terminate the function
+func RunChangesetRebases(ctx context.Context, s *Store, clock func() time.Time, gitClient GitserverClient, backoffDuration time.Duration) {
+	process := func(ctx context.Context, s *Store, job *a8n.WorkerJob) error {
+		var p changesetJobPayload
+		if err := json.Unmarshal(job.Payload, &p); err != nil {
+			return errors.Wrap(err, "parsing payload")
+		}
+		return rebaseChangeset(ctx, s, clock, gitClient, p.ChangesetJobID)
+	}
+
+	w := &Worker{
+		Store:   s,
+		Queue:   ChangesetRebasesQueue,
+		Handler: process,
+		Backoff: backoffDuration,
+	}
+	w.Start(ctx)
+}
+
+// rebaseChangeset rebases the changeset created by the ChangesetJob with the
+// given ID onto the current head of its base branch and force-pushes its
+// branch. If the CampaignPlan of the job ran the steps of a campaign spec,
+// the steps are run again on the new base; otherwise the diff of the
+// CampaignJob is applied to it. The outcome is recorded as a ChangesetEvent
+// of the changeset, and a failed rebase is returned, so that the Worker
+// retries it.
+func rebaseChangeset(ctx context.Context, s *Store, clock func() time.Time, gitClient GitserverClient, id int64) error {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return errors.Wrap(err, "starting transaction")
+	}
+	// A failed rebase is recorded before it's returned, so we always commit.
+	defer tx.Done()
+
+	// The ChangesetJob could be run again at the same time, e.g. because its
+	// campaign was updated, and both push to the same branch.
+	locked, err := tx.TryAcquireAdvisoryLock(ctx, fmt.Sprintf("changeset_job:%d", id))
+	if err != nil {
+		return errors.Wrap(err, "acquiring lock")
+	}
+	if !locked {
+		return errors.Errorf("changeset job %d is already running", id)
+	}
+
+	job, err := tx.GetChangesetJob(ctx, GetChangesetJobOpts{ID: id})
+	if err == ErrNoResults {
+		// The job was deleted with its campaign in the meantime.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting changeset job")
+	}
+	if job.ChangesetID == 0 || job.Branch == "" {
+		return nil
+	}
+
+	c, err := tx.GetCampaign(ctx, GetCampaignOpts{ID: job.CampaignID})
+	if err == ErrNoResults {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting campaign")
+	}
+	if !c.AutoRebase || !c.ClosedAt.IsZero() {
+		return nil
+	}
+
+	changeset, err := tx.GetChangeset(ctx, GetChangesetOpts{ID: job.ChangesetID})
+	if err == ErrNoResults {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting changeset")
+	}
+	if state, err := changeset.State(); err != nil || state != a8n.ChangesetStateOpen {
+		return err
+	}
+
+	campaignJob, err := tx.GetCampaignJob(ctx, GetCampaignJobOpts{ID: job.CampaignJobID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign job")
+	}
+
+	plan, err := tx.GetCampaignPlan(ctx, GetCampaignPlanOpts{ID: campaignJob.CampaignPlanID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign plan")
+	}
+
+	reposStore := repos.NewDBStore(tx.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: []api.RepoID{changeset.RepoID}})
+	if err != nil {
+		return errors.Wrap(err, "getting repository")
+	}
+	if len(rs) != 1 {
+		return errors.Errorf("repo not found: %d", changeset.RepoID)
+	}
+	repo := rs[0]
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, &types.Repo{
+		Name:         api.RepoName(repo.Name),
+		ExternalRepo: repo.ExternalRepo,
+	})
+	if err != nil {
+		return err
+	}
+
+	baseRef, err := changeset.BaseRef()
+	if err != nil {
+		return err
+	}
+	base, err := git.ResolveRevision(ctx, *cachedRepo, nil, baseRef, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return errors.Wrap(err, "resolving base branch")
+	}
+
+	rebase := &a8n.ChangesetRebase{BaseOid: string(base), CreatedAt: clock()}
+
+	diff := campaignJob.Diff
+	if plan.CampaignType == campaignTypeSpec {
+		rebase.Reexecuted = true
+		diff, err = reexecuteCampaignJob(ctx, plan, api.RepoName(repo.Name), base)
+	}
+
+	if err == nil {
+		_, err = gitClient.CreateCommitFromPatch(ctx, gitprotocol.CreateCommitFromPatchRequest{
+			Repo:       api.RepoName(repo.Name),
+			BaseCommit: base,
+			// See RunChangesetJob for the trailing newline and the arguments
+			// of git apply.
+			Patch:     diff + "\n",
+			TargetRef: job.Branch,
+			CommitInfo: gitprotocol.PatchCommitInfo{
+				Message:     c.Name,
+				AuthorName:  "Sourcegraph Bot",
+				AuthorEmail: "automation@sourcegraph.com",
+				Date:        rebase.CreatedAt,
+			},
+			GitApplyArgs: []string{"-p0", "--unidiff-zero"},
+			Push:         true,
+		})
+		if diffErr, ok := err.(*gitprotocol.CreateCommitFromPatchError); ok {
+			err = errors.Errorf("creating commit from patch for repo %q: %v (command: %q)", diffErr.RepositoryName, diffErr.Err, diffErr.Command)
+		}
+	}
+
+	if err != nil {
+		rebase.Error = err.Error()
+	}
+
+	// Retries of the rebase onto the same base update the same event.
+	ev := &a8n.ChangesetEvent{
+		ChangesetID: changeset.ID,
+		Kind:        a8n.ChangesetEventKindSourcegraphRebased,
+		Key:         string(base),
+		Metadata:    rebase,
+	}
+	if e := tx.UpsertChangesetEvents(ctx, ev); e != nil {
+		return errors.Wrap(e, "recording rebase")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The head of the changeset changed, so we sync it right away.
+	return EnqueueChangesetSyncs(ctx, tx, changeset)
+}
+
+// reexecuteCampaignJob runs the steps of the campaign spec of the given
+// CampaignPlan in the given repository on the given commit with the
+// campaign-executor, and returns the resulting diff.
+func reexecuteCampaignJob(ctx context.Context, plan *a8n.CampaignPlan, repo api.RepoName, commit api.CommitID) (string, error) {
+	if !ServerExecutionEnabled() {
+		return "", errors.New("running campaign specs on the server is not enabled, because CAMPAIGN_EXECUTOR_URL is not set")
+	}
+
+	steps, err := CampaignSpecSteps(json.RawMessage(plan.Arguments))
+	if err != nil {
+		return "", errors.Wrap(err, "getting campaign spec steps")
+	}
+
+	req := protocol.Request{
+		Repo:   repo,
+		Commit: commit,
+		Steps:  make([]protocol.Step, len(steps)),
+	}
+	for i, step := range steps {
+		req.Steps[i] = protocol.Step{Image: step.Image, Args: step.Args}
+	}
+
+	// The output of the steps is only kept for the initial execution.
+	ev, err := runCampaignExecutor(ctx, &req, func(string) {})
+	if err != nil {
+		return "", err
+	}
+	if ev.Error != "" {
+		return "", errors.New(ev.Error)
+	}
+	if ev.Diff == nil || *ev.Diff == "" {
+		return "", errors.New("the campaign spec steps produced no changes on the new base")
+	}
+	return *ev.Diff, nil
+}
@@ -0,0 +1,213 @@
+package resolvers
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func (r *campaignResolver) Activity(
+	ctx context.Context,
+	args *graphqlbackend.CampaignActivityArgs,
+) (graphqlbackend.CampaignActivityConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access changesets.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	var offset int
+	if args.After != nil {
+		var err error
+		if offset, err = strconv.Atoi(*args.After); err != nil || offset < 0 {
+			return nil, errors.Errorf("invalid cursor %q", *args.After)
+		}
+	}
+
+	return &campaignActivityConnectionResolver{
+		store:    r.store,
+		campaign: r.Campaign,
+		first:    args.GetFirst(),
+		offset:   offset,
+	}, nil
+}
+
+type campaignActivityConnectionResolver struct {
+	store    *ee.Store
+	campaign *a8n.Campaign
+	first    int32
+	offset   int
+
+	// cache results because they are used by multiple fields
+	once sync.Once
+	feed []*ee.CampaignActivity
+	err  error
+}
+
+func (r *campaignActivityConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.CampaignActivityEventResolver, error) {
+	feed, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := r.page(feed)
+	resolvers := make([]graphqlbackend.CampaignActivityEventResolver, 0, len(page))
+	for _, a := range page {
+		resolvers = append(resolvers, &campaignActivityEventResolver{
+			store:    r.store,
+			campaign: r.campaign,
+			activity: a,
+		})
+	}
+	return resolvers, nil
+}
+
+func (r *campaignActivityConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	feed, err := r.compute(ctx)
+	return int32(len(feed)), err
+}
+
+func (r *campaignActivityConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	feed, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	end := r.offset + len(r.page(feed))
+	if end < len(feed) {
+		return graphqlutil.NextPageCursor(strconv.Itoa(end)), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+// page returns the part of the feed selected by the pagination arguments.
+func (r *campaignActivityConnectionResolver) page(feed []*ee.CampaignActivity) []*ee.CampaignActivity {
+	if r.offset >= len(feed) {
+		return nil
+	}
+	feed = feed[r.offset:]
+
+	if r.first > 0 && int(r.first) < len(feed) {
+		feed = feed[:r.first]
+	}
+	return feed
+}
+
+func (r *campaignActivityConnectionResolver) compute(ctx context.Context) ([]*ee.CampaignActivity, error) {
+	r.once.Do(func() {
+		cs, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{
+			CampaignID: r.campaign.ID,
+			Limit:      -1,
+		})
+		if err != nil {
+			r.err = err
+			return
+		}
+
+		var es []*a8n.ChangesetEvent
+		if len(cs) > 0 {
+			changesetIDs := make([]int64, len(cs))
+			for i, c := range cs {
+				changesetIDs[i] = c.ID
+			}
+
+			es, _, err = r.store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{
+				ChangesetIDs: changesetIDs,
+				Kinds:        ee.CampaignActivityEventKinds,
+				Limit:        -1,
+			})
+			if err != nil {
+				r.err = err
+				return
+			}
+		}
+
+		r.feed = ee.CampaignActivityFeed(r.campaign, cs, es)
+	})
+	return r.feed, r.err
+}
+
+type campaignActivityEventResolver struct {
+	store    *ee.Store
+	campaign *a8n.Campaign
+	activity *ee.CampaignActivity
+}
+
+func (r *campaignActivityEventResolver) ToCampaignCreatedEvent() (graphqlbackend.CampaignCreatedEventResolver, bool) {
+	if r.activity.Kind != ee.CampaignActivityCreated {
+		return nil, false
+	}
+	return &campaignCreatedEventResolver{r}, true
+}
+
+func (r *campaignActivityEventResolver) ToChangesetAddedEvent() (graphqlbackend.ChangesetAddedEventResolver, bool) {
+	return r, r.activity.Kind == ee.CampaignActivityChangesetAdded
+}
+
+func (r *campaignActivityEventResolver) ToChangesetMergedEvent() (graphqlbackend.ChangesetMergedEventResolver, bool) {
+	return r, r.activity.Kind == ee.CampaignActivityChangesetMerged
+}
+
+func (r *campaignActivityEventResolver) ToChangesetCommentedEvent() (graphqlbackend.ChangesetCommentedEventResolver, bool) {
+	if r.activity.Kind != ee.CampaignActivityChangesetCommented {
+		return nil, false
+	}
+	return &changesetCommentedEventResolver{r}, true
+}
+
+func (r *campaignActivityEventResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.activity.Time}
+}
+
+func (r *campaignActivityEventResolver) Changeset() graphqlbackend.ExternalChangesetResolver {
+	return &changesetResolver{store: r.store, Changeset: r.activity.Changeset}
+}
+
+type campaignCreatedEventResolver struct {
+	*campaignActivityEventResolver
+}
+
+func (r *campaignCreatedEventResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.campaign.AuthorID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+type changesetCommentedEventResolver struct {
+	*campaignActivityEventResolver
+}
+
+func (r *changesetCommentedEventResolver) Author() (string, error) {
+	switch m := r.activity.Event.Metadata.(type) {
+	case *github.IssueComment:
+		return m.Author.Login, nil
+	case *bitbucketserver.Activity:
+		return m.User.Name, nil
+	default:
+		return "", errors.Errorf("unexpected metadata of comment event: %T", m)
+	}
+}
+
+func (r *changesetCommentedEventResolver) Body() (string, error) {
+	switch m := r.activity.Event.Metadata.(type) {
+	case *github.IssueComment:
+		return m.Body, nil
+	case *bitbucketserver.Activity:
+		if m.Comment == nil {
+			return "", nil
+		}
+		return m.Comment.Text, nil
+	default:
+		return "", errors.Errorf("unexpected metadata of comment event: %T", m)
+	}
+}
@@ -0,0 +1,98 @@
+package resolvers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func (r *Resolver) CampaignFacets(ctx context.Context, args *graphqlbackend.CampaignFacetsArgs) (graphqlbackend.CampaignFacetsResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		opts ee.GetCampaignFacetsOpts
+		err  error
+	)
+	opts.NamespaceUserIDs, opts.NamespaceOrgIDs, err = parseCampaignNamespaces(args.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := r.store.GetCampaignFacets(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignFacetsResolver{facets: facets}, nil
+}
+
+type campaignFacetsResolver struct {
+	facets *a8n.CampaignFacets
+}
+
+var _ graphqlbackend.CampaignFacetsResolver = &campaignFacetsResolver{}
+
+func (r *campaignFacetsResolver) States() []graphqlbackend.CampaignStateFacetResolver {
+	resolvers := make([]graphqlbackend.CampaignStateFacetResolver, 0, len(r.facets.States))
+	for _, state := range []a8n.CampaignState{a8n.CampaignStateOpen, a8n.CampaignStateClosed} {
+		resolvers = append(resolvers, &campaignStateFacetResolver{
+			state: state,
+			count: r.facets.States[state],
+		})
+	}
+	return resolvers
+}
+
+func (r *campaignFacetsResolver) Authors() []graphqlbackend.CampaignAuthorFacetResolver {
+	resolvers := make([]*campaignAuthorFacetResolver, 0, len(r.facets.Authors))
+	for authorID, count := range r.facets.Authors {
+		resolvers = append(resolvers, &campaignAuthorFacetResolver{authorID: authorID, count: count})
+	}
+
+	sort.Slice(resolvers, func(i, j int) bool {
+		if resolvers[i].count != resolvers[j].count {
+			return resolvers[i].count > resolvers[j].count
+		}
+		return resolvers[i].authorID < resolvers[j].authorID
+	})
+
+	facets := make([]graphqlbackend.CampaignAuthorFacetResolver, 0, len(resolvers))
+	for _, r := range resolvers {
+		facets = append(facets, r)
+	}
+	return facets
+}
+
+type campaignStateFacetResolver struct {
+	state a8n.CampaignState
+	count int32
+}
+
+var _ graphqlbackend.CampaignStateFacetResolver = &campaignStateFacetResolver{}
+
+func (r *campaignStateFacetResolver) State() a8n.CampaignState { return r.state }
+func (r *campaignStateFacetResolver) Count() int32             { return r.count }
+
+type campaignAuthorFacetResolver struct {
+	authorID int32
+	count    int32
+}
+
+var _ graphqlbackend.CampaignAuthorFacetResolver = &campaignAuthorFacetResolver{}
+
+func (r *campaignAuthorFacetResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.authorID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *campaignAuthorFacetResolver) Count() int32 { return r.count }
@@ -0,0 +1,116 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+const (
+	defaultCampaignMetricsDays   = 14
+	defaultCampaignMetricsWeeks  = 10
+	defaultCampaignMetricsMonths = 3
+
+	maxCampaignMetricsPeriods = 366
+)
+
+// campaignMetricsCache is shared by all resolvers so that the metrics are
+// computed at most once per day.
+var campaignMetricsCache ee.CampaignMetricsCache
+
+// NewCampaignMetricsResolver returns the function resolving Site.campaignMetrics
+// whose store uses the given db.
+func NewCampaignMetricsResolver(db *sql.DB) func(context.Context, *graphqlbackend.CampaignMetricsArgs) (graphqlbackend.CampaignMetricsResolver, error) {
+	r := &Resolver{store: ee.NewStore(db)}
+	return r.CampaignMetrics
+}
+
+func (r *Resolver) CampaignMetrics(ctx context.Context, args *graphqlbackend.CampaignMetricsArgs) (graphqlbackend.CampaignMetricsResolver, error) {
+	// 🚨 SECURITY: Only site admins may view campaign metrics.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	return &campaignMetricsResolver{
+		store:  r.store,
+		days:   campaignMetricsPeriods(args.Days, defaultCampaignMetricsDays),
+		weeks:  campaignMetricsPeriods(args.Weeks, defaultCampaignMetricsWeeks),
+		months: campaignMetricsPeriods(args.Months, defaultCampaignMetricsMonths),
+	}, nil
+}
+
+func campaignMetricsPeriods(n *int32, def int) int {
+	switch {
+	case n == nil:
+		return def
+	case *n < 0:
+		return 0
+	case *n > maxCampaignMetricsPeriods:
+		return maxCampaignMetricsPeriods
+	default:
+		return int(*n)
+	}
+}
+
+type campaignMetricsResolver struct {
+	store *ee.Store
+
+	days, weeks, months int
+}
+
+var _ graphqlbackend.CampaignMetricsResolver = &campaignMetricsResolver{}
+
+func (r *campaignMetricsResolver) Daily(ctx context.Context) ([]graphqlbackend.CampaignMetricsPeriodResolver, error) {
+	return r.periods(ctx, a8n.CampaignMetricsPeriodDay, r.days)
+}
+
+func (r *campaignMetricsResolver) Weekly(ctx context.Context) ([]graphqlbackend.CampaignMetricsPeriodResolver, error) {
+	return r.periods(ctx, a8n.CampaignMetricsPeriodWeek, r.weeks)
+}
+
+func (r *campaignMetricsResolver) Monthly(ctx context.Context) ([]graphqlbackend.CampaignMetricsPeriodResolver, error) {
+	return r.periods(ctx, a8n.CampaignMetricsPeriodMonth, r.months)
+}
+
+func (r *campaignMetricsResolver) periods(ctx context.Context, period a8n.CampaignMetricsPeriodType, n int) ([]graphqlbackend.CampaignMetricsPeriodResolver, error) {
+	ps, err := campaignMetricsCache.Get(ctx, r.store, period, n)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignMetricsPeriodResolver, 0, len(ps))
+	for _, p := range ps {
+		resolvers = append(resolvers, &campaignMetricsPeriodResolver{period: p})
+	}
+	return resolvers, nil
+}
+
+type campaignMetricsPeriodResolver struct {
+	period *a8n.CampaignMetricsPeriod
+}
+
+var _ graphqlbackend.CampaignMetricsPeriodResolver = &campaignMetricsPeriodResolver{}
+
+func (r *campaignMetricsPeriodResolver) StartTime() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.period.StartTime}
+}
+
+func (r *campaignMetricsPeriodResolver) CampaignsCreated() int32 {
+	return r.period.CampaignsCreated
+}
+
+func (r *campaignMetricsPeriodResolver) ChangesetsMerged() int32 {
+	return r.period.ChangesetsMerged
+}
+
+func (r *campaignMetricsPeriodResolver) MedianTimeToMerge() *float64 {
+	if r.period.MedianTimeToMerge == nil {
+		return nil
+	}
+	seconds := r.period.MedianTimeToMerge.Seconds()
+	return &seconds
+}
@@ -0,0 +1,184 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// unmarshalNamespaceID returns the settings options of the user or org with
+// the given GraphQL ID.
+func unmarshalNamespaceID(id graphql.ID) (opts ee.GetCampaignNamespaceSettingsOpts, err error) {
+	switch relay.UnmarshalKind(id) {
+	case "User":
+		err = relay.UnmarshalSpec(id, &opts.NamespaceUserID)
+	case "Org":
+		err = relay.UnmarshalSpec(id, &opts.NamespaceOrgID)
+	default:
+		err = errors.Errorf("Invalid namespace %q", id)
+	}
+	return opts, err
+}
+
+// campaignNamespaceSettings returns the CampaignNamespaceSettings of the given
+// namespace, or the zero settings if it has none.
+func (r *Resolver) campaignNamespaceSettings(ctx context.Context, opts ee.GetCampaignNamespaceSettingsOpts) (*a8n.CampaignNamespaceSettings, error) {
+	ns, err := r.store.GetCampaignNamespaceSettings(ctx, opts)
+	if err == ee.ErrNoResults {
+		return &a8n.CampaignNamespaceSettings{
+			NamespaceUserID: opts.NamespaceUserID,
+			NamespaceOrgID:  opts.NamespaceOrgID,
+		}, nil
+	}
+	return ns, err
+}
+
+func (r *Resolver) CampaignNamespaceSettings(ctx context.Context, args *graphqlbackend.CampaignNamespaceSettingsArgs) (graphqlbackend.CampaignNamespaceSettingsResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaigns and their defaults.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	opts, err := unmarshalNamespaceID(args.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := r.campaignNamespaceSettings(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignNamespaceSettingsResolver{ns: ns}, nil
+}
+
+func (r *Resolver) UpdateCampaignNamespaceSettings(ctx context.Context, args *graphqlbackend.UpdateCampaignNamespaceSettingsArgs) (_ graphqlbackend.CampaignNamespaceSettingsResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.UpdateCampaignNamespaceSettings", string(args.Input.Namespace))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may change the defaults of campaigns for now.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	opts, err := unmarshalNamespaceID(args.Input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := r.campaignNamespaceSettings(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Input.BranchPrefix != nil {
+		ns.BranchPrefix = *args.Input.BranchPrefix
+	}
+
+	if args.Input.DefaultLabels != nil {
+		labels := make([]string, 0, len(*args.Input.DefaultLabels))
+		seen := make(map[string]bool, len(*args.Input.DefaultLabels))
+		for _, l := range *args.Input.DefaultLabels {
+			if strings.TrimSpace(l) == "" {
+				return nil, errors.New("default labels must not be empty")
+			}
+			if !seen[l] {
+				seen[l] = true
+				labels = append(labels, l)
+			}
+		}
+		ns.DefaultLabels = labels
+	}
+
+	if args.Input.RequireApproval != nil {
+		ns.RequireApproval = *args.Input.RequireApproval
+	}
+
+	if args.Input.NotificationChannel != nil {
+		ns.NotificationChannel = a8n.CampaignNotificationChannel(*args.Input.NotificationChannel)
+		if ns.NotificationChannel == a8n.CampaignNotificationChannelEmail {
+			ns.NotificationWebhookURL = ""
+		}
+	}
+
+	if args.Input.NotificationWebhookURL != nil {
+		ns.NotificationWebhookURL = *args.Input.NotificationWebhookURL
+	}
+
+	if _, err := ee.NewNotificationChannel(r.store, ns); err != nil {
+		return nil, err
+	}
+
+	if err = r.store.UpsertCampaignNamespaceSettings(ctx, ns); err != nil {
+		return nil, err
+	}
+
+	return &campaignNamespaceSettingsResolver{ns: ns}, nil
+}
+
+type campaignNamespaceSettingsResolver struct {
+	ns *a8n.CampaignNamespaceSettings
+}
+
+var _ graphqlbackend.CampaignNamespaceSettingsResolver = &campaignNamespaceSettingsResolver{}
+
+func (r *campaignNamespaceSettingsResolver) Namespace(ctx context.Context) (n graphqlbackend.NamespaceResolver, err error) {
+	if r.ns.NamespaceUserID != 0 {
+		n.Namespace, err = graphqlbackend.UserByIDInt32(ctx, r.ns.NamespaceUserID)
+	} else {
+		n.Namespace, err = graphqlbackend.OrgByIDInt32(ctx, r.ns.NamespaceOrgID)
+	}
+
+	return n, err
+}
+
+func (r *campaignNamespaceSettingsResolver) BranchPrefix() string  { return r.ns.BranchPrefix }
+func (r *campaignNamespaceSettingsResolver) RequireApproval() bool { return r.ns.RequireApproval }
+
+func (r *campaignNamespaceSettingsResolver) DefaultLabels() []string {
+	if r.ns.DefaultLabels == nil {
+		return []string{}
+	}
+	return r.ns.DefaultLabels
+}
+
+func (r *campaignNamespaceSettingsResolver) NotificationChannel() string {
+	if r.ns.NotificationChannel == "" {
+		return string(a8n.CampaignNotificationChannelEmail)
+	}
+	return string(r.ns.NotificationChannel)
+}
+
+func (r *campaignNamespaceSettingsResolver) NotificationWebhookURL(ctx context.Context) (*string, error) {
+	if r.ns.NotificationWebhookURL == "" {
+		return nil, nil
+	}
+
+	// 🚨 SECURITY: Webhook URLs are secrets that allow posting messages, so
+	// only site admins may see them.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAuthenticated {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &r.ns.NotificationWebhookURL, nil
+}
+
+func (r *campaignNamespaceSettingsResolver) UpdatedAt() *graphqlbackend.DateTime {
+	if r.ns.UpdatedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.ns.UpdatedAt}
+}
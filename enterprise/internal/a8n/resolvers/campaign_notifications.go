@@ -0,0 +1,157 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// campaignsViewerID returns the ID of the current user, whose own state of
+// campaigns is read or written, e.g. their notification settings or which
+// campaigns they viewed.
+func campaignsViewerID(ctx context.Context) (int32, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may
+	// access campaigns, and thus have a state of them.
+	if err := allowReadAccess(ctx); err != nil {
+		return 0, err
+	}
+
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return 0, backend.ErrNotAuthenticated
+	}
+	return a.UID, nil
+}
+
+// campaignNotificationSettings returns the CampaignNotificationSettings of
+// the given user, or the default settings if they have none.
+func (r *Resolver) campaignNotificationSettings(ctx context.Context, userID int32) (*a8n.CampaignNotificationSettings, error) {
+	ns, err := r.store.GetCampaignNotificationSettings(ctx, userID)
+	if err == ee.ErrNoResults {
+		return a8n.DefaultCampaignNotificationSettings(userID), nil
+	}
+	return ns, err
+}
+
+func (r *Resolver) CampaignNotificationSettings(ctx context.Context) (graphqlbackend.CampaignNotificationSettingsResolver, error) {
+	userID, err := campaignsViewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := r.campaignNotificationSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignNotificationSettingsResolver{ns: ns}, nil
+}
+
+func (r *Resolver) UpdateCampaignNotificationSettings(ctx context.Context, args *graphqlbackend.UpdateCampaignNotificationSettingsArgs) (_ graphqlbackend.CampaignNotificationSettingsResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.UpdateCampaignNotificationSettings", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	userID, err := campaignsViewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := r.campaignNotificationSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Input.NotifyMerged != nil {
+		ns.NotifyMerged = *args.Input.NotifyMerged
+	}
+	if args.Input.NotifyClosed != nil {
+		ns.NotifyClosed = *args.Input.NotifyClosed
+	}
+	if args.Input.NotifyChecksFailed != nil {
+		ns.NotifyChecksFailed = *args.Input.NotifyChecksFailed
+	}
+	if args.Input.DigestIntervalMinutes != nil {
+		if *args.Input.DigestIntervalMinutes < 0 {
+			return nil, errors.New("digest interval must not be negative")
+		}
+		ns.DigestInterval = time.Duration(*args.Input.DigestIntervalMinutes) * time.Minute
+	}
+
+	if err = r.store.UpsertCampaignNotificationSettings(ctx, ns); err != nil {
+		return nil, err
+	}
+
+	return &campaignNotificationSettingsResolver{ns: ns}, nil
+}
+
+func (r *Resolver) SetCampaignSubscription(ctx context.Context, args *graphqlbackend.SetCampaignSubscriptionArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SetCampaignSubscription", fmt.Sprintf("Campaign: %q, Subscribed: %t", args.Campaign, args.Subscribed))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	userID, err := campaignsViewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: campaignID}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.store.UpsertCampaignSubscription(ctx, &a8n.CampaignSubscription{
+		CampaignID: campaign.ID,
+		UserID:     userID,
+		Subscribed: args.Subscribed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+type campaignNotificationSettingsResolver struct {
+	ns *a8n.CampaignNotificationSettings
+}
+
+var _ graphqlbackend.CampaignNotificationSettingsResolver = &campaignNotificationSettingsResolver{}
+
+func (r *campaignNotificationSettingsResolver) NotifyMerged() bool { return r.ns.NotifyMerged }
+func (r *campaignNotificationSettingsResolver) NotifyClosed() bool { return r.ns.NotifyClosed }
+
+func (r *campaignNotificationSettingsResolver) NotifyChecksFailed() bool {
+	return r.ns.NotifyChecksFailed
+}
+
+func (r *campaignNotificationSettingsResolver) DigestIntervalMinutes() int32 {
+	return int32(r.ns.DigestInterval / time.Minute)
+}
+
+func (r *campaignNotificationSettingsResolver) UpdatedAt() *graphqlbackend.DateTime {
+	if r.ns.UpdatedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.ns.UpdatedAt}
+}
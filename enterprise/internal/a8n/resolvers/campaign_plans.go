@@ -7,10 +7,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -80,6 +83,19 @@ func (r *campaignPlanResolver) ChangesetPlans(
 	}
 }
 
+func (r *campaignPlanResolver) AllChangesetPlans(
+	ctx context.Context,
+	args *graphqlutil.ConnectionArgs,
+) graphqlbackend.ChangesetPlansConnectionResolver {
+	return &campaignJobsConnectionResolver{
+		store: r.store,
+		opts: ee.ListCampaignJobsOpts{
+			CampaignPlanID: r.campaignPlan.ID,
+			Limit:          int(args.GetFirst()),
+		},
+	}
+}
+
 func (r *campaignPlanResolver) PreviewURL() string {
 	u := globals.ExternalURL().ResolveReference(&url.URL{Path: "/campaigns/new"})
 	q := url.Values{}
@@ -192,6 +208,62 @@ func (r *campaignJobsConnectionResolver) PageInfo(ctx context.Context) (*graphql
 	return graphqlutil.HasNextPage(next != 0), nil
 }
 
+// previewChangesetPlansConnectionResolver resolves CampaignJobs that have
+// been computed for a preview but were never persisted.
+type previewChangesetPlansConnectionResolver struct {
+	store *ee.Store
+	jobs  []*a8n.CampaignJob
+	first *int32
+}
+
+func (r *previewChangesetPlansConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.ChangesetPlanResolver, error) {
+	jobs := r.jobs
+	if r.first != nil && len(jobs) > int(*r.first) {
+		jobs = jobs[:*r.first]
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetPlanResolver, 0, len(jobs))
+	for i, j := range jobs {
+		resolvers = append(resolvers, &previewChangesetPlanResolver{
+			campaignJobResolver: &campaignJobResolver{
+				store: r.store,
+				job:   j,
+				// The job was never persisted, so there can't be a
+				// ChangesetJob for it.
+				attemptedPreloadChangesetJob: true,
+			},
+			index: i,
+		})
+	}
+	return resolvers, nil
+}
+
+func (r *previewChangesetPlansConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	return int32(len(r.jobs)), nil
+}
+
+func (r *previewChangesetPlansConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	return graphqlutil.HasNextPage(r.first != nil && len(r.jobs) > int(*r.first)), nil
+}
+
+const previewChangesetPlanIDKind = "ChangesetPlanPreview"
+
+// previewChangesetPlanResolver is a ChangesetPlan that only exists in a
+// preview. Since it has no database ID, it is identified by its position in
+// the preview.
+type previewChangesetPlanResolver struct {
+	*campaignJobResolver
+	index int
+}
+
+func (r *previewChangesetPlanResolver) ID() graphql.ID {
+	return relay.MarshalID(previewChangesetPlanIDKind, r.index)
+}
+
+func (r *previewChangesetPlanResolver) Diff() graphqlbackend.ChangesetPlanResolver {
+	return r
+}
+
 type campaignJobResolver struct {
 	store *ee.Store
 
@@ -275,6 +347,71 @@ func (r *campaignJobResolver) PublicationEnqueued(ctx context.Context) (bool, er
 	return true, nil
 }
 
+// executionLogChunksPerPage is the maximum number of log chunks returned by
+// ChangesetPlan.executionLog.
+const executionLogChunksPerPage = 64
+
+func (r *campaignJobResolver) ExecutionLog(ctx context.Context, args *graphqlbackend.ExecutionLogArgs) (graphqlbackend.ExecutionLogResolver, error) {
+	var after int64
+	if args.After != nil {
+		var err error
+		if after, err = strconv.ParseInt(*args.After, 10, 64); err != nil {
+			return nil, errors.Wrap(err, "parsing cursor")
+		}
+	}
+
+	// The job was loaded before the chunks, and its last chunks are written
+	// before it's finished, so no chunks are missed when it's completed.
+	chunks, next, err := r.store.ListCampaignJobLogChunks(ctx, ee.ListCampaignJobLogChunksOpts{
+		CampaignJobID: r.job.ID,
+		Cursor:        after + 1,
+		Limit:         executionLogChunksPerPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &executionLogResolver{
+		hasNextPage: next != 0,
+		completed:   !r.job.FinishedAt.IsZero(),
+	}
+
+	var output strings.Builder
+	for _, c := range chunks {
+		output.WriteString(c.Output)
+		after = c.ID
+	}
+	res.output = output.String()
+
+	if after != 0 {
+		cursor := strconv.FormatInt(after, 10)
+		res.endCursor = &cursor
+	}
+
+	return res, nil
+}
+
+func (r *campaignJobResolver) ExecutionError() *string {
+	if r.job.Error == "" {
+		return nil
+	}
+	return &r.job.Error
+}
+
+type executionLogResolver struct {
+	output      string
+	endCursor   *string
+	hasNextPage bool
+	completed   bool
+}
+
+var _ graphqlbackend.ExecutionLogResolver = &executionLogResolver{}
+
+func (r *executionLogResolver) Output() string     { return r.output }
+func (r *executionLogResolver) EndCursor() *string { return r.endCursor }
+func (r *executionLogResolver) HasNextPage() bool  { return r.hasNextPage }
+func (r *executionLogResolver) Completed() bool    { return r.completed }
+
 type previewFileDiffConnectionResolver struct {
 	job    *a8n.CampaignJob
 	commit *graphqlbackend.GitCommitResolver
@@ -0,0 +1,85 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+func (r *Resolver) MarkCampaignViewed(ctx context.Context, args *graphqlbackend.MarkCampaignViewedArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.MarkCampaignViewed", fmt.Sprintf("Campaign: %q", args.Campaign))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	userID, err := campaignsViewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: campaignID}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.store.MarkCampaignViewed(ctx, campaign.ID, userID); err != nil {
+		return nil, err
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+// readState returns the CampaignReadState of the campaign for the current
+// user, or nil if they aren't signed in.
+func (r *campaignResolver) readState(ctx context.Context) (*a8n.CampaignReadState, error) {
+	r.readStateOnce.Do(func() {
+		a := actor.FromContext(ctx)
+		if !a.IsAuthenticated() {
+			return
+		}
+		r.readStateValue, r.readStateErr = r.store.GetCampaignReadState(ctx, r.Campaign.ID, a.UID)
+	})
+	return r.readStateValue, r.readStateErr
+}
+
+func (r *campaignResolver) HasUnreadActivity(ctx context.Context) (bool, error) {
+	rs, err := r.readState(ctx)
+	if err != nil || rs == nil {
+		return false, err
+	}
+	return rs.HasUnreadActivity(), nil
+}
+
+func (r *campaignResolver) ViewerLastViewedAt(ctx context.Context) (*graphqlbackend.DateTime, error) {
+	rs, err := r.readState(ctx)
+	if err != nil || rs == nil || rs.LastViewedAt.IsZero() {
+		return nil, err
+	}
+	return &graphqlbackend.DateTime{Time: rs.LastViewedAt}, nil
+}
+
+func (r *campaignsConnectionResolver) UnreadCount(ctx context.Context) (int32, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return 0, nil
+	}
+
+	opts := r.countOpts()
+	opts.UnreadByUserID = a.UID
+	count, err := r.store.CountCampaigns(ctx, opts)
+	return int32(count), err
+}
@@ -0,0 +1,106 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/featureflag"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// repositoryActivityFlag gates the activity summary, whose query aggregates
+// the changesets of all campaigns and can be turned off if it's too expensive.
+var repositoryActivityFlag = featureflag.Register("campaigns-repository-activity", "Serves the summary of the campaign activity in each repository.", true)
+
+func (r *Resolver) CampaignRepositoryActivity(ctx context.Context, args *graphqlbackend.CampaignRepositoryActivityArgs) ([]graphqlbackend.CampaignRepositoryActivityResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	if !repositoryActivityFlag.Enabled(ctx) {
+		return nil, errors.New("campaign repository activity is disabled")
+	}
+
+	var (
+		opts ee.ListCampaignRepositoryActivityOpts
+		err  error
+	)
+	opts.NamespaceUserIDs, opts.NamespaceOrgIDs, err = parseCampaignNamespaces(args.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Repositories != nil {
+		for _, id := range *args.Repositories {
+			repoID, err := graphqlbackend.UnmarshalRepositoryID(id)
+			if err != nil {
+				return nil, err
+			}
+			opts.RepoIDs = append(opts.RepoIDs, repoID)
+		}
+	}
+
+	activity, err := r.store.ListCampaignRepositoryActivity(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(activity) == 0 {
+		return []graphqlbackend.CampaignRepositoryActivityResolver{}, nil
+	}
+
+	repoIDs := make([]api.RepoID, len(activity))
+	for i, a := range activity {
+		repoIDs[i] = a.RepoID
+	}
+
+	reposStore := repos.NewDBStore(r.store.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	reposByID := make(map[api.RepoID]*repos.Repo, len(rs))
+	for _, repo := range rs {
+		reposByID[api.RepoID(repo.ID)] = repo
+	}
+
+	// The activity in repositories that were deleted since is skipped.
+	resolvers := make([]graphqlbackend.CampaignRepositoryActivityResolver, 0, len(activity))
+	for _, a := range activity {
+		repo, ok := reposByID[a.RepoID]
+		if !ok {
+			continue
+		}
+		resolvers = append(resolvers, &campaignRepositoryActivityResolver{activity: a, repo: repo})
+	}
+	return resolvers, nil
+}
+
+type campaignRepositoryActivityResolver struct {
+	activity *a8n.CampaignRepositoryActivity
+	repo     *repos.Repo
+}
+
+var _ graphqlbackend.CampaignRepositoryActivityResolver = &campaignRepositoryActivityResolver{}
+
+func (r *campaignRepositoryActivityResolver) Repository() *graphqlbackend.RepositoryResolver {
+	return newRepositoryResolver(r.repo)
+}
+
+func (r *campaignRepositoryActivityResolver) OpenCampaigns() int32 {
+	return r.activity.OpenCampaigns
+}
+
+func (r *campaignRepositoryActivityResolver) OpenChangesets() int32 {
+	return r.activity.OpenChangesets
+}
+
+func (r *campaignRepositoryActivityResolver) ChangesetsMergedThisWeek() int32 {
+	return r.activity.ChangesetsMergedThisWeek
+}
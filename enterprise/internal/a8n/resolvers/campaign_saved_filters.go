@@ -0,0 +1,236 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+const campaignSavedFilterIDKind = "CampaignSavedFilter"
+
+func marshalCampaignSavedFilterID(id int64) graphql.ID {
+	return relay.MarshalID(campaignSavedFilterIDKind, id)
+}
+
+func unmarshalCampaignSavedFilterID(id graphql.ID) (savedFilterID int64, err error) {
+	err = relay.UnmarshalSpec(id, &savedFilterID)
+	return
+}
+
+// campaignSavedFiltersUserID returns the ID of the current user, whose saved
+// filters of the list of campaigns are read or written.
+func campaignSavedFiltersUserID(ctx context.Context) (int32, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may
+	// list campaigns, and thus save filters of that list.
+	if err := allowReadAccess(ctx); err != nil {
+		return 0, err
+	}
+
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return 0, backend.ErrNotAuthenticated
+	}
+	return a.UID, nil
+}
+
+func (r *Resolver) CampaignSavedFilters(ctx context.Context) ([]graphqlbackend.CampaignSavedFilterResolver, error) {
+	userID, err := campaignSavedFiltersUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := r.store.ListCampaignSavedFilters(ctx, ee.ListCampaignSavedFiltersOpts{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignSavedFilterResolver, 0, len(fs))
+	for _, f := range fs {
+		resolvers = append(resolvers, &campaignSavedFilterResolver{f})
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) SaveCampaignSavedFilter(ctx context.Context, args *graphqlbackend.SaveCampaignSavedFilterArgs) (_ graphqlbackend.CampaignSavedFilterResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SaveCampaignSavedFilter", args.Input.Name)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	userID, err := campaignSavedFiltersUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(args.Input.Name) == "" {
+		return nil, errors.New("saved filter name must not be empty")
+	}
+
+	// The filters are validated like the arguments of the campaigns
+	// connections they are passed to.
+	opts, err := listCampaignsOpts(&graphqlbackend.ListCampaignArgs{
+		Query:             args.Input.Query,
+		State:             args.Input.State,
+		Namespaces:        args.Input.Namespaces,
+		HasOpenChangesets: args.Input.HasOpenChangesets,
+		ChangesetState:    args.Input.ChangesetState,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f := &a8n.CampaignSavedFilter{
+		UserID: userID,
+		Name:   args.Input.Name,
+		Filters: a8n.CampaignListFilters{
+			Query:             opts.Query,
+			NamespaceUserIDs:  opts.NamespaceUserIDs,
+			NamespaceOrgIDs:   opts.NamespaceOrgIDs,
+			HasOpenChangesets: opts.HasOpenChangesets,
+			ChangesetState:    opts.ChangesetState,
+		},
+		IsDefault: args.Input.IsDefault,
+	}
+	if opts.State != a8n.CampaignStateAny {
+		f.Filters.State = opts.State
+	}
+
+	if err = r.store.SaveCampaignSavedFilter(ctx, f); err != nil {
+		return nil, errors.Wrap(err, "saving campaign saved filter")
+	}
+
+	return &campaignSavedFilterResolver{f}, nil
+}
+
+func (r *Resolver) DeleteCampaignSavedFilter(ctx context.Context, args *graphqlbackend.DeleteCampaignSavedFilterArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.DeleteCampaignSavedFilter", fmt.Sprintf("SavedFilter: %q", args.SavedFilter))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	userID, err := campaignSavedFiltersUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalCampaignSavedFilterID(args.SavedFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users may only delete their own saved filters.
+	f, err := r.store.GetCampaignSavedFilter(ctx, id)
+	if err == ee.ErrNoResults || (err == nil && f.UserID != userID) {
+		return nil, &ee.ErrCampaignSavedFilterNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.store.DeleteCampaignSavedFilter(ctx, id); err != nil {
+		return nil, errors.Wrap(err, "deleting campaign saved filter")
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) SetDefaultCampaignSavedFilter(ctx context.Context, args *graphqlbackend.SetDefaultCampaignSavedFilterArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SetDefaultCampaignSavedFilter", fmt.Sprintf("SavedFilter: %v", args.SavedFilter))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	userID, err := campaignSavedFiltersUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	if args.SavedFilter != nil {
+		if id, err = unmarshalCampaignSavedFilterID(*args.SavedFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	// 🚨 SECURITY: The store only updates saved filters of the given user.
+	err = r.store.SetDefaultCampaignSavedFilter(ctx, userID, id)
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignSavedFilterNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "setting default campaign saved filter")
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+type campaignSavedFilterResolver struct {
+	*a8n.CampaignSavedFilter
+}
+
+var _ graphqlbackend.CampaignSavedFilterResolver = &campaignSavedFilterResolver{}
+
+func (r *campaignSavedFilterResolver) ID() graphql.ID {
+	return marshalCampaignSavedFilterID(r.CampaignSavedFilter.ID)
+}
+
+func (r *campaignSavedFilterResolver) Name() string { return r.CampaignSavedFilter.Name }
+
+func (r *campaignSavedFilterResolver) Query() *string {
+	return nullString(r.Filters.Query)
+}
+
+func (r *campaignSavedFilterResolver) State() *string {
+	return nullString(string(r.Filters.State))
+}
+
+func (r *campaignSavedFilterResolver) Namespaces() *[]graphql.ID {
+	if len(r.Filters.NamespaceUserIDs) == 0 && len(r.Filters.NamespaceOrgIDs) == 0 {
+		return nil
+	}
+
+	namespaces := make([]graphql.ID, 0, len(r.Filters.NamespaceUserIDs)+len(r.Filters.NamespaceOrgIDs))
+	for _, id := range r.Filters.NamespaceUserIDs {
+		namespaces = append(namespaces, relay.MarshalID("User", id))
+	}
+	for _, id := range r.Filters.NamespaceOrgIDs {
+		namespaces = append(namespaces, relay.MarshalID("Org", id))
+	}
+	return &namespaces
+}
+
+func (r *campaignSavedFilterResolver) HasOpenChangesets() bool { return r.Filters.HasOpenChangesets }
+
+func (r *campaignSavedFilterResolver) ChangesetState() *string {
+	return nullString(string(r.Filters.ChangesetState))
+}
+
+func (r *campaignSavedFilterResolver) IsDefault() bool { return r.CampaignSavedFilter.IsDefault }
+
+func (r *campaignSavedFilterResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.CampaignSavedFilter.CreatedAt}
+}
+
+func (r *campaignSavedFilterResolver) UpdatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.CampaignSavedFilter.UpdatedAt}
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
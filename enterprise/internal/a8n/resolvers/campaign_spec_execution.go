@@ -0,0 +1,129 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// scopeQueryResultCount is the number of results that scope queries without a
+// count: filter are searched for, so that all matching repositories are found.
+const scopeQueryResultCount = "count:10000"
+
+func (r *Resolver) CreateCampaignPlanFromSpec(ctx context.Context, args graphqlbackend.CreateCampaignPlanFromSpecArgs) (_ graphqlbackend.CampaignPlanResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CreateCampaignPlanFromSpec", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may create campaign plans for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	user, err := backend.CurrentUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+	if user == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	spec, err := ee.ParseCampaignSpec(args.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	queries, err := ee.CampaignSpecScopeQueries(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	specRepos, err := scopeQueryRepos(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	plan, err := svc.CreateCampaignPlanFromSpec(ctx, args.Spec, specRepos, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignPlanResolver{store: r.store, campaignPlan: plan}, nil
+}
+
+var mockScopeQueryRepos func(queries []string) ([]ee.CampaignSpecRepo, error)
+
+// scopeQueryRepos returns the repositories matched by the given scope queries
+// of a campaign spec, with their default branches as base revisions. Empty
+// repositories and the ones that are still being cloned are skipped.
+func scopeQueryRepos(ctx context.Context, queries []string) ([]ee.CampaignSpecRepo, error) {
+	if mockScopeQueryRepos != nil {
+		return mockScopeQueryRepos(queries)
+	}
+
+	var (
+		repos []*graphqlbackend.RepositoryResolver
+		seen  = map[api.RepoID]bool{}
+	)
+	add := func(repo *graphqlbackend.RepositoryResolver) {
+		if id := repo.Type().ID; !seen[id] {
+			seen[id] = true
+			repos = append(repos, repo)
+		}
+	}
+
+	for _, q := range queries {
+		if !strings.Contains(q, "count:") {
+			q += " " + scopeQueryResultCount
+		}
+
+		// 🚨 SECURITY: The search only returns the repositories the current
+		// user has access to.
+		search, err := graphqlbackend.NewSearchImplementer(&graphqlbackend.SearchArgs{Version: "V2", Query: q})
+		if err != nil {
+			return nil, err
+		}
+		results, err := search.Results(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scope query %q", q)
+		}
+		if alert := results.Alert(); alert != nil && len(results.Results()) == 0 {
+			return nil, errors.Errorf("scope query %q: %s", q, alert.Title())
+		}
+
+		for _, res := range results.Results() {
+			if repo, ok := res.ToRepository(); ok {
+				add(repo)
+			} else if fm, ok := res.ToFileMatch(); ok {
+				add(fm.Repository())
+			} else if c, ok := res.ToCommitSearchResult(); ok {
+				add(c.Commit().Repository())
+			}
+		}
+	}
+
+	specRepos := make([]ee.CampaignSpecRepo, 0, len(repos))
+	for _, repo := range repos {
+		ref, err := repo.DefaultBranch(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "default branch of repository %q", repo.Name())
+		}
+		if ref == nil {
+			continue
+		}
+		specRepos = append(specRepos, ee.CampaignSpecRepo{Repo: repo.Type().ID, BaseRevision: ref.Name()})
+	}
+
+	return specRepos, nil
+}
@@ -0,0 +1,326 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const testDockerSpec = `{
+  // Run on the server
+  "version": 2,
+  "scopeQueries": ["repo:sourcegraph"],
+  "steps": [{"type": "docker", "image": "alpine", "args": ["sh", "-c", "echo hello > README.md"]}],
+}`
+
+func TestCreateCampaignPlanFromSpecResolver(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := backend.WithAuthzBypass(context.Background())
+	dbtesting.SetupGlobalTestDB(t)
+	rcache.SetupForTest(t)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	clock := func() time.Time {
+		return now.UTC().Truncate(time.Microsecond)
+	}
+
+	// For testing purposes they all share the same rev, across repos
+	testingRev := api.CommitID("24f7ca7c1190835519e261d7eefa09df55ceea4f")
+
+	backend.Mocks.Repos.ResolveRev = func(_ context.Context, _ *types.Repo, _ string) (api.CommitID, error) {
+		return testingRev, nil
+	}
+	defer func() { backend.Mocks.Repos.ResolveRev = nil }()
+
+	backend.Mocks.Repos.GetCommit = func(_ context.Context, _ *types.Repo, _ api.CommitID) (*git.Commit, error) {
+		return &git.Commit{ID: testingRev}, nil
+	}
+	defer func() { backend.Mocks.Repos.GetCommit = nil }()
+
+	ee.MockServerExecutionEnabled = func() bool { return true }
+	defer func() { ee.MockServerExecutionEnabled = nil }()
+
+	reposStore := repos.NewDBStore(dbconn.Global, sql.TxOptions{})
+
+	var rs []*repos.Repo
+	for i := 0; i < 2; i++ {
+		repo := &repos.Repo{
+			Name: fmt.Sprintf("github.com/sourcegraph/sourcegraph-%d", i),
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          fmt.Sprintf("external-id-%d", i),
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+			Sources: map[string]*repos.SourceInfo{
+				"extsvc:github:4": {
+					ID:       "extsvc:github:4",
+					CloneURL: "https://secrettoken@github.com/sourcegraph/sourcegraph",
+				},
+			},
+		}
+		if err := reposStore.UpsertRepos(ctx, repo); err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, repo)
+	}
+
+	// The search of the scope queries is mocked, since it needs the
+	// searcher and gitserver.
+	var scopeQueries []string
+	mockScopeQueryRepos = func(queries []string) ([]ee.CampaignSpecRepo, error) {
+		scopeQueries = queries
+		specRepos := make([]ee.CampaignSpecRepo, 0, len(rs))
+		for _, repo := range rs {
+			specRepos = append(specRepos, ee.CampaignSpecRepo{Repo: repo.ID, BaseRevision: "refs/heads/master"})
+		}
+		return specRepos, nil
+	}
+	defer func() { mockScopeQueryRepos = nil }()
+
+	// The first user is a site admin.
+	admin := createTestUser(ctx, t)
+	adminCtx := actor.WithActor(ctx, actor.FromUser(admin.ID))
+
+	store := ee.NewStoreWithClock(dbconn.Global, clock)
+
+	sr := &Resolver{store: store}
+	s, err := graphqlbackend.NewSchema(sr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		user, err := db.Users.Create(ctx, db.NewUser{
+			Email:                "spec-user@sourcegraph.com",
+			Username:             "spec-user",
+			Password:             "spec-user",
+			EmailIsVerified:      true,
+			FailIfNotInitialUser: false,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The site admin check is bypassed in ctx.
+		userCtx := actor.WithActor(context.Background(), actor.FromUser(user.ID))
+
+		for _, tc := range []struct {
+			name    string
+			ctx     context.Context
+			spec    string
+			enabled bool
+			wantErr string
+		}{
+			{
+				name:    "not a site admin",
+				ctx:     userCtx,
+				spec:    testDockerSpec,
+				enabled: true,
+				wantErr: "must be site admin",
+			},
+			{
+				name:    "invalid spec",
+				ctx:     adminCtx,
+				spec:    `{"version": 2, "scopeQueries": []}`,
+				enabled: true,
+				wantErr: "invalid campaign spec",
+			},
+			{
+				name:    "command step",
+				ctx:     adminCtx,
+				spec:    `{"version": 2, "scopeQueries": ["repo:sourcegraph"], "steps": [{"type": "command", "args": ["ls"]}]}`,
+				enabled: true,
+				wantErr: "steps.0: only docker steps can be run on the server",
+			},
+			{
+				name:    "server execution not enabled",
+				ctx:     adminCtx,
+				spec:    testDockerSpec,
+				wantErr: "CAMPAIGN_EXECUTOR_URL is not set",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ee.MockServerExecutionEnabled = func() bool { return tc.enabled }
+				defer func() { ee.MockServerExecutionEnabled = func() bool { return true } }()
+
+				var response struct{}
+				errs := exec(tc.ctx, t, s, map[string]interface{}{"spec": tc.spec}, &response, `
+				mutation($spec: String!) {
+				  createCampaignPlanFromSpec(spec: $spec) { id }
+				}
+				`)
+				if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.wantErr) {
+					t.Fatalf("have errors %v, want one containing %q", errs, tc.wantErr)
+				}
+			})
+		}
+
+		plans, _, err := store.ListCampaignPlans(ctx, ee.ListCampaignPlansOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plans) != 0 {
+			t.Fatalf("have %d campaign plans after errors, want none", len(plans))
+		}
+	})
+
+	type SpecChangesetPlan struct {
+		Repository     struct{ Name string }
+		ExecutionLog   *string
+		ExecutionError *string
+	}
+
+	type SpecCampaignPlan struct {
+		ID                string
+		Status            Status
+		ChangesetPlans    struct{ TotalCount int }
+		AllChangesetPlans struct {
+			TotalCount int
+			Nodes      []SpecChangesetPlan
+		}
+	}
+
+	const campaignPlanFields = `
+	  id
+	  status {
+	    completedCount
+	    pendingCount
+	    state
+	    errors
+	  }
+	  changesetPlans(first: 10) { totalCount }
+	  allChangesetPlans(first: 10) {
+	    totalCount
+	    nodes {
+	      repository { name }
+	      executionLog
+	      executionError
+	    }
+	  }
+	`
+
+	var created struct{ CreateCampaignPlanFromSpec SpecCampaignPlan }
+	mustExec(adminCtx, t, s, map[string]interface{}{"spec": testDockerSpec}, &created, `
+	mutation($spec: String!) {
+	  createCampaignPlanFromSpec(spec: $spec) {`+campaignPlanFields+`}
+	}
+	`)
+
+	if diff := cmp.Diff(scopeQueries, []string{"repo:sourcegraph"}); diff != "" {
+		t.Fatalf("wrong scope queries. diff=%s", diff)
+	}
+
+	plan := created.CreateCampaignPlanFromSpec
+
+	// The changeset plans are pending until they were executed, so only
+	// allChangesetPlans returns them.
+	wantStatus := Status{
+		State:        "PROCESSING",
+		PendingCount: len(rs),
+		Errors:       []string{},
+	}
+	if diff := cmp.Diff(plan.Status, wantStatus); diff != "" {
+		t.Fatalf("wrong Status. diff=%s", diff)
+	}
+	if plan.ChangesetPlans.TotalCount != 0 {
+		t.Fatalf("have %d finished changeset plans, want none", plan.ChangesetPlans.TotalCount)
+	}
+	wantNodes := []SpecChangesetPlan{
+		{Repository: struct{ Name string }{rs[0].Name}},
+		{Repository: struct{ Name string }{rs[1].Name}},
+	}
+	if diff := cmp.Diff(plan.AllChangesetPlans.Nodes, wantNodes); diff != "" {
+		t.Fatalf("wrong changeset plans. diff=%s", diff)
+	}
+
+	planID, err := unmarshalCampaignPlanID(graphql.ID(plan.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, _, err := store.ListCampaignJobs(ctx, ee.ListCampaignJobsOpts{CampaignPlanID: planID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != len(rs) {
+		t.Fatalf("have %d campaign jobs, want %d", len(jobs), len(rs))
+	}
+
+	// Each changeset plan is executed by a worker.
+	workerJobs, _, err := store.ListWorkerJobs(ctx, ee.ListWorkerJobsOpts{Queue: ee.CampaignJobExecutionsQueue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workerJobs) != len(jobs) {
+		t.Fatalf("have %d worker jobs, want %d", len(workerJobs), len(jobs))
+	}
+
+	// The first changeset plan is running and has written some output, the
+	// second one failed.
+	if err := store.AppendCampaignJobLog(ctx, jobs[0].ID, "step 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AppendCampaignJobLog(ctx, jobs[0].ID, "step 2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs[1].StartedAt = now
+	jobs[1].FinishedAt = now
+	jobs[1].Error = "step 1 exited with status 1"
+	if err := store.UpdateCampaignJob(ctx, jobs[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	var queried struct{ Node SpecCampaignPlan }
+	mustExec(adminCtx, t, s, nil, &queried, fmt.Sprintf(`
+	query {
+	  node(id: %q) {
+	    ... on CampaignPlan {`+campaignPlanFields+`}
+	  }
+	}
+	`, plan.ID))
+
+	wantStatus = Status{
+		State:          "PROCESSING",
+		PendingCount:   1,
+		CompletedCount: 1,
+		Errors:         []string{jobs[1].Error},
+	}
+	if diff := cmp.Diff(queried.Node.Status, wantStatus); diff != "" {
+		t.Fatalf("wrong Status. diff=%s", diff)
+	}
+
+	// The failed changeset plan has no diff.
+	if queried.Node.ChangesetPlans.TotalCount != 0 {
+		t.Fatalf("have %d changeset plans with a diff, want none", queried.Node.ChangesetPlans.TotalCount)
+	}
+
+	log, execErr := "step 1\nstep 2\n", jobs[1].Error
+	wantNodes = []SpecChangesetPlan{
+		{Repository: struct{ Name string }{rs[0].Name}, ExecutionLog: &log},
+		{Repository: struct{ Name string }{rs[1].Name}, ExecutionError: &execErr},
+	}
+	if diff := cmp.Diff(queried.Node.AllChangesetPlans.Nodes, wantNodes); diff != "" {
+		t.Fatalf("wrong changeset plans. diff=%s", diff)
+	}
+}
@@ -0,0 +1,253 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+const campaignWebhookDeliveryIDKind = "CampaignWebhookDelivery"
+
+func marshalCampaignWebhookDeliveryID(id int64) graphql.ID {
+	return relay.MarshalID(campaignWebhookDeliveryIDKind, id)
+}
+
+func unmarshalCampaignWebhookDeliveryID(id graphql.ID) (deliveryID int64, err error) {
+	err = relay.UnmarshalSpec(id, &deliveryID)
+	return
+}
+
+// NewCampaignWebhookDeliveriesResolver returns the function resolving
+// Site.campaignWebhookDeliveries whose store uses the given db.
+func NewCampaignWebhookDeliveriesResolver(db *sql.DB) func(context.Context, *graphqlbackend.CampaignWebhookDeliveriesArgs) (graphqlbackend.CampaignWebhookDeliveryConnectionResolver, error) {
+	r := &Resolver{store: ee.NewStore(db)}
+	return r.CampaignWebhookDeliveries
+}
+
+func (r *Resolver) CampaignWebhookDeliveries(ctx context.Context, args *graphqlbackend.CampaignWebhookDeliveriesArgs) (graphqlbackend.CampaignWebhookDeliveryConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may view webhook deliveries, since their
+	// payloads describe the campaigns of any namespace and their URLs carry
+	// the secrets of the webhooks.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	var opts ee.ListCampaignWebhookDeliveriesOpts
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		cursor, err := strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		opts.Cursor = cursor
+	}
+	if args.Failed != nil {
+		opts.OnlyFailed = *args.Failed
+	}
+
+	return &campaignWebhookDeliveryConnectionResolver{store: r.store, opts: opts}, nil
+}
+
+func (r *Resolver) RedeliverWebhook(ctx context.Context, args *graphqlbackend.RedeliverWebhookArgs) (_ graphqlbackend.CampaignWebhookDeliveryResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.RedeliverWebhook", fmt.Sprintf("Delivery: %q", args.Delivery))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may redeliver webhooks.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	id, err := unmarshalCampaignWebhookDeliveryID(args.Delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := r.store.GetCampaignWebhookDelivery(ctx, id)
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignWebhookDeliveryNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = ee.RedeliverCampaignWebhook(ctx, r.store, d); err != nil {
+		return nil, err
+	}
+
+	// The delivery is reloaded to return its attempt count and success time
+	// as updated by the attempt.
+	if d, err = r.store.GetCampaignWebhookDelivery(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &campaignWebhookDeliveryResolver{store: r.store, delivery: d}, nil
+}
+
+type campaignWebhookDeliveryConnectionResolver struct {
+	store *ee.Store
+	opts  ee.ListCampaignWebhookDeliveriesOpts
+
+	// cache results because they are used by multiple fields
+	once       sync.Once
+	deliveries []*a8n.CampaignWebhookDelivery
+	next       int64
+	err        error
+}
+
+var _ graphqlbackend.CampaignWebhookDeliveryConnectionResolver = &campaignWebhookDeliveryConnectionResolver{}
+
+func (r *campaignWebhookDeliveryConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.CampaignWebhookDeliveryResolver, error) {
+	deliveries, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.CampaignWebhookDeliveryResolver, 0, len(deliveries))
+	for _, d := range deliveries {
+		resolvers = append(resolvers, &campaignWebhookDeliveryResolver{store: r.store, delivery: d})
+	}
+	return resolvers, nil
+}
+
+func (r *campaignWebhookDeliveryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := r.store.CountCampaignWebhookDeliveries(ctx, r.opts.OnlyFailed)
+	return int32(count), err
+}
+
+func (r *campaignWebhookDeliveryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if next == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(strconv.FormatInt(next, 10)), nil
+}
+
+func (r *campaignWebhookDeliveryConnectionResolver) compute(ctx context.Context) ([]*a8n.CampaignWebhookDelivery, int64, error) {
+	r.once.Do(func() {
+		r.deliveries, r.next, r.err = r.store.ListCampaignWebhookDeliveries(ctx, r.opts)
+	})
+	return r.deliveries, r.next, r.err
+}
+
+type campaignWebhookDeliveryResolver struct {
+	store    *ee.Store
+	delivery *a8n.CampaignWebhookDelivery
+}
+
+var _ graphqlbackend.CampaignWebhookDeliveryResolver = &campaignWebhookDeliveryResolver{}
+
+func (r *campaignWebhookDeliveryResolver) ID() graphql.ID {
+	return marshalCampaignWebhookDeliveryID(r.delivery.ID)
+}
+
+func (r *campaignWebhookDeliveryResolver) Channel() string { return string(r.delivery.Channel) }
+func (r *campaignWebhookDeliveryResolver) URL() string     { return r.delivery.URL }
+func (r *campaignWebhookDeliveryResolver) Succeeded() bool { return r.delivery.Succeeded() }
+
+func (r *campaignWebhookDeliveryResolver) Recipient(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	if r.delivery.RecipientUserID == 0 {
+		return nil, nil
+	}
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.delivery.RecipientUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *campaignWebhookDeliveryResolver) Payload() graphqlbackend.JSONValue {
+	var payload interface{}
+	if err := json.Unmarshal(r.delivery.Payload, &payload); err != nil {
+		return graphqlbackend.JSONValue{Value: string(r.delivery.Payload)}
+	}
+	return graphqlbackend.JSONValue{Value: payload}
+}
+
+func (r *campaignWebhookDeliveryResolver) SucceededAt() *graphqlbackend.DateTime {
+	if r.delivery.SucceededAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.delivery.SucceededAt}
+}
+
+func (r *campaignWebhookDeliveryResolver) LastAttemptedAt() *graphqlbackend.DateTime {
+	if r.delivery.LastAttemptedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.delivery.LastAttemptedAt}
+}
+
+func (r *campaignWebhookDeliveryResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.delivery.CreatedAt}
+}
+
+func (r *campaignWebhookDeliveryResolver) Attempts(ctx context.Context) ([]graphqlbackend.CampaignWebhookDeliveryAttemptResolver, error) {
+	attempts, err := r.store.ListCampaignWebhookDeliveryAttempts(ctx, r.delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignWebhookDeliveryAttemptResolver, 0, len(attempts))
+	for _, a := range attempts {
+		resolvers = append(resolvers, &campaignWebhookDeliveryAttemptResolver{attempt: a})
+	}
+	return resolvers, nil
+}
+
+type campaignWebhookDeliveryAttemptResolver struct {
+	attempt *a8n.CampaignWebhookDeliveryAttempt
+}
+
+var _ graphqlbackend.CampaignWebhookDeliveryAttemptResolver = &campaignWebhookDeliveryAttemptResolver{}
+
+func (r *campaignWebhookDeliveryAttemptResolver) Succeeded() bool { return r.attempt.Succeeded }
+
+func (r *campaignWebhookDeliveryAttemptResolver) StatusCode() *int32 {
+	if r.attempt.StatusCode == 0 {
+		return nil
+	}
+	return &r.attempt.StatusCode
+}
+
+func (r *campaignWebhookDeliveryAttemptResolver) ResponseBody() *string {
+	if r.attempt.ResponseBody == "" {
+		return nil
+	}
+	return &r.attempt.ResponseBody
+}
+
+func (r *campaignWebhookDeliveryAttemptResolver) Error() *string {
+	if r.attempt.Error == "" {
+		return nil
+	}
+	return &r.attempt.Error
+}
+
+func (r *campaignWebhookDeliveryAttemptResolver) DurationMilliseconds() int32 {
+	return int32(r.attempt.Duration / time.Millisecond)
+}
+
+func (r *campaignWebhookDeliveryAttemptResolver) AttemptedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.attempt.AttemptedAt}
+}
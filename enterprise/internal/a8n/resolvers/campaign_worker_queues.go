@@ -0,0 +1,128 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// NewCampaignWorkerQueuesResolver returns the function resolving
+// Site.campaignWorkerQueues whose store uses the given db.
+func NewCampaignWorkerQueuesResolver(db *sql.DB) func(context.Context) ([]graphqlbackend.CampaignWorkerQueueResolver, error) {
+	r := &Resolver{store: ee.NewStore(db)}
+	return r.CampaignWorkerQueues
+}
+
+func (r *Resolver) CampaignWorkerQueues(ctx context.Context) ([]graphqlbackend.CampaignWorkerQueueResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the worker queues, since the
+	// payloads and errors of jobs can reference any campaign.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	stats, err := r.store.GetWorkerQueueStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignWorkerQueueResolver, 0, len(stats))
+	for _, s := range stats {
+		resolvers = append(resolvers, &campaignWorkerQueueResolver{store: r.store, stats: s})
+	}
+	return resolvers, nil
+}
+
+type campaignWorkerQueueResolver struct {
+	store *ee.Store
+	stats *a8n.WorkerQueueStats
+}
+
+var _ graphqlbackend.CampaignWorkerQueueResolver = &campaignWorkerQueueResolver{}
+
+func (r *campaignWorkerQueueResolver) Name() string      { return r.stats.Queue }
+func (r *campaignWorkerQueueResolver) Queued() int32     { return r.stats.Queued }
+func (r *campaignWorkerQueueResolver) Processing() int32 { return r.stats.Processing }
+func (r *campaignWorkerQueueResolver) Completed() int32  { return r.stats.Completed }
+func (r *campaignWorkerQueueResolver) Retrying() int32   { return r.stats.Retrying }
+func (r *campaignWorkerQueueResolver) Dead() int32       { return r.stats.Dead }
+
+func (r *campaignWorkerQueueResolver) OldestQueuedAt() *graphqlbackend.DateTime {
+	if r.stats.OldestQueuedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.stats.OldestQueuedAt}
+}
+
+func (r *campaignWorkerQueueResolver) Failures(ctx context.Context, args *graphqlbackend.CampaignWorkerQueueFailuresArgs) ([]graphqlbackend.CampaignWorkerJobResolver, error) {
+	opts := ee.ListWorkerJobsOpts{Queue: r.stats.Queue, OnlyFailed: true}
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	}
+	if args.State != nil {
+		opts.State = a8n.WorkerJobState(*args.State)
+		if !opts.State.Valid() {
+			return nil, errors.Errorf("unknown state %q", *args.State)
+		}
+	}
+
+	jobs, _, err := r.store.ListWorkerJobs(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CampaignWorkerJobResolver, 0, len(jobs))
+	for _, j := range jobs {
+		resolvers = append(resolvers, &campaignWorkerJobResolver{job: j})
+	}
+	return resolvers, nil
+}
+
+type campaignWorkerJobResolver struct {
+	job *a8n.WorkerJob
+}
+
+var _ graphqlbackend.CampaignWorkerJobResolver = &campaignWorkerJobResolver{}
+
+func (r *campaignWorkerJobResolver) State() string      { return string(r.job.State) }
+func (r *campaignWorkerJobResolver) Attempts() int32    { return r.job.Attempts }
+func (r *campaignWorkerJobResolver) MaxAttempts() int32 { return r.job.MaxAttempts }
+
+func (r *campaignWorkerJobResolver) Payload() graphqlbackend.JSONValue {
+	var payload interface{}
+	if err := json.Unmarshal(r.job.Payload, &payload); err != nil {
+		return graphqlbackend.JSONValue{Value: string(r.job.Payload)}
+	}
+	return graphqlbackend.JSONValue{Value: payload}
+}
+
+func (r *campaignWorkerJobResolver) Error() *string {
+	if r.job.Error == "" {
+		return nil
+	}
+	return &r.job.Error
+}
+
+func (r *campaignWorkerJobResolver) RunAfter() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.job.RunAfter}
+}
+
+func (r *campaignWorkerJobResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.job.CreatedAt}
+}
+
+func (r *campaignWorkerJobResolver) UpdatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.job.UpdatedAt}
+}
+
+func (r *campaignWorkerJobResolver) FinishedAt() *graphqlbackend.DateTime {
+	if r.job.FinishedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.job.FinishedAt}
+}
@@ -2,7 +2,9 @@ package resolvers
 
 import (
 	"context"
+	"encoding/json"
 	"path"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 )
 
 var _ graphqlbackend.CampaignsConnectionResolver = &campaignsConnectionResolver{}
@@ -41,17 +44,51 @@ func (r *campaignsConnectionResolver) Nodes(ctx context.Context) ([]graphqlbacke
 }
 
 func (r *campaignsConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
-	opts := ee.CountCampaignsOpts{ChangesetID: r.opts.ChangesetID, State: r.opts.State}
-	count, err := r.store.CountCampaigns(ctx, opts)
+	count, err := r.store.CountCampaigns(ctx, r.countOpts())
 	return int32(count), err
 }
 
+// campaignsCountBudget is the time that ApproximateCount spends counting the
+// campaigns exactly before falling back to an estimate.
+const campaignsCountBudget = 2 * time.Second
+
+func (r *campaignsConnectionResolver) ApproximateCount(ctx context.Context) (int32, error) {
+	opts := r.countOpts()
+
+	countCtx, cancel := context.WithTimeout(ctx, campaignsCountBudget)
+	defer cancel()
+
+	count, err := r.store.CountCampaigns(countCtx, opts)
+	if err != nil && countCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		count, err = r.store.EstimateCampaignsCount(ctx, opts)
+	}
+	return int32(count), err
+}
+
+func (r *campaignsConnectionResolver) countOpts() ee.CountCampaignsOpts {
+	return ee.CountCampaignsOpts{
+		ChangesetID:       r.opts.ChangesetID,
+		State:             r.opts.State,
+		Query:             r.opts.Query,
+		NamespaceUserIDs:  r.opts.NamespaceUserIDs,
+		NamespaceOrgIDs:   r.opts.NamespaceOrgIDs,
+		HasOpenChangesets: r.opts.HasOpenChangesets,
+		ChangesetState:    r.opts.ChangesetState,
+		ParentCampaignID:  r.opts.ParentCampaignID,
+		InvolvedUserID:    r.opts.InvolvedUserID,
+		InvolvedAs:        r.opts.InvolvedAs,
+	}
+}
+
 func (r *campaignsConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
 	_, next, err := r.compute(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return graphqlutil.HasNextPage(next != 0), nil
+	if next == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(strconv.FormatInt(next, 10)), nil
 }
 
 func (r *campaignsConnectionResolver) compute(ctx context.Context) ([]*a8n.Campaign, int64, error) {
@@ -61,11 +98,31 @@ func (r *campaignsConnectionResolver) compute(ctx context.Context) ([]*a8n.Campa
 	return r.campaigns, r.next, r.err
 }
 
+var _ graphqlbackend.CampaignByNameResultResolver = &campaignByNameResultResolver{}
+
+type campaignByNameResultResolver struct {
+	campaign *campaignResolver
+	renamed  bool
+}
+
+func (r *campaignByNameResultResolver) Campaign() graphqlbackend.CampaignResolver {
+	return r.campaign
+}
+
+func (r *campaignByNameResultResolver) Renamed() bool {
+	return r.renamed
+}
+
 var _ graphqlbackend.CampaignResolver = &campaignResolver{}
 
 type campaignResolver struct {
 	store *ee.Store
 	*a8n.Campaign
+
+	// cache the read state because it's used by multiple fields
+	readStateOnce  sync.Once
+	readStateValue *a8n.CampaignReadState
+	readStateErr   error
 }
 
 const campaignIDKind = "Campaign"
@@ -98,6 +155,31 @@ func (r *campaignResolver) Branch() *string {
 	return &r.Campaign.Branch
 }
 
+func (r *campaignResolver) AutoRebase() bool {
+	return r.Campaign.AutoRebase
+}
+
+func (r *campaignResolver) Position() string {
+	return r.Campaign.Position
+}
+
+func (r *campaignResolver) Spec() (*graphqlbackend.JSONValue, error) {
+	if r.Campaign.Spec == nil {
+		return nil, nil
+	}
+
+	spec, err := ee.MigrateCampaignSpec(r.Campaign.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(spec, &v); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.JSONValue{Value: v}, nil
+}
+
 func (r *campaignResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
 	return graphqlbackend.UserByIDInt32(ctx, r.AuthorID)
 }
@@ -110,6 +192,26 @@ func (r *campaignResolver) ViewerCanAdminister(ctx context.Context) (bool, error
 	return currentUser.SiteAdmin, nil
 }
 
+func (r *campaignResolver) ViewerIsSubscribed(ctx context.Context) (bool, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return false, nil
+	}
+
+	sub, err := r.store.GetCampaignSubscription(ctx, ee.GetCampaignSubscriptionOpts{
+		CampaignID: r.Campaign.ID,
+		UserID:     a.UID,
+	})
+	if err == ee.ErrNoResults {
+		// Authors are subscribed to their campaigns unless they unsubscribed.
+		return r.Campaign.AuthorID == a.UID, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return sub.Subscribed, nil
+}
+
 func (r *campaignResolver) URL(ctx context.Context) (string, error) {
 	return path.Join("/campaigns", string(r.ID())), nil
 }
@@ -257,6 +359,128 @@ func (r *campaignResolver) Plan(ctx context.Context) (graphqlbackend.CampaignPla
 	return &campaignPlanResolver{store: r.store, campaignPlan: plan}, nil
 }
 
+func (r *campaignResolver) RollbackOf(ctx context.Context) (graphqlbackend.CampaignResolver, error) {
+	if r.Campaign.RollbackOfCampaignID == 0 {
+		return nil, nil
+	}
+
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: r.Campaign.RollbackOfCampaignID})
+	if err == ee.ErrNoResults {
+		// The rolled back campaign was deleted.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+func (r *campaignResolver) Rollback(ctx context.Context) (graphqlbackend.CampaignResolver, error) {
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{RollbackOfCampaignID: r.Campaign.ID})
+	if err == ee.ErrNoResults {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+func (r *campaignResolver) Parent(ctx context.Context) (graphqlbackend.CampaignResolver, error) {
+	if r.Campaign.ParentCampaignID == 0 {
+		return nil, nil
+	}
+
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: r.Campaign.ParentCampaignID})
+	if err == ee.ErrNoResults {
+		// The parent campaign was deleted.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+func (r *campaignResolver) Children(ctx context.Context, args *graphqlutil.ConnectionArgs) graphqlbackend.CampaignsConnectionResolver {
+	return &campaignsConnectionResolver{
+		store: r.store,
+		opts: ee.ListCampaignsOpts{
+			ParentCampaignID: r.Campaign.ID,
+			Limit:            int(args.GetFirst()),
+		},
+	}
+}
+
+func (r *campaignResolver) Progress(ctx context.Context) (graphqlbackend.CampaignProgressResolver, error) {
+	progress, err := r.store.GetCampaignProgress(ctx, r.Campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &campaignProgressResolver{progress}, nil
+}
+
+type campaignProgressResolver struct {
+	*a8n.CampaignProgress
+}
+
+var _ graphqlbackend.CampaignProgressResolver = &campaignProgressResolver{}
+
+func (r *campaignProgressResolver) Total() int32   { return r.CampaignProgress.Total }
+func (r *campaignProgressResolver) Open() int32    { return r.CampaignProgress.Open }
+func (r *campaignProgressResolver) Merged() int32  { return r.CampaignProgress.Merged }
+func (r *campaignProgressResolver) Closed() int32  { return r.CampaignProgress.Closed }
+func (r *campaignProgressResolver) Deleted() int32 { return r.CampaignProgress.Deleted }
+
+func (r *campaignResolver) ChangesetCheckStates(ctx context.Context) (graphqlbackend.ChangesetCheckStateCountsResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access changesets.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	cs, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{
+		CampaignID: r.Campaign.ID,
+		Limit:      -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changesetIDs := make([]int64, len(cs))
+	for i, c := range cs {
+		changesetIDs[i] = c.ID
+	}
+
+	es, _, err := r.store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{
+		ChangesetIDs: changesetIDs,
+		Limit:        -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := ee.CountCheckStates(cs, es)
+	if err != nil {
+		return nil, err
+	}
+	return &changesetCheckStateCountsResolver{counts}, nil
+}
+
+type changesetCheckStateCountsResolver struct {
+	*ee.CheckStateCounts
+}
+
+var _ graphqlbackend.ChangesetCheckStateCountsResolver = &changesetCheckStateCountsResolver{}
+
+func (r *changesetCheckStateCountsResolver) Total() int32   { return r.CheckStateCounts.Total }
+func (r *changesetCheckStateCountsResolver) Pending() int32 { return r.CheckStateCounts.Pending }
+func (r *changesetCheckStateCountsResolver) Passed() int32  { return r.CheckStateCounts.Passed }
+func (r *changesetCheckStateCountsResolver) Failed() int32  { return r.CheckStateCounts.Failed }
+func (r *changesetCheckStateCountsResolver) Unknown() int32 { return r.CheckStateCounts.Unknown }
+
 func (r *campaignResolver) RepositoryDiffs(
 	ctx context.Context,
 	args *graphqlutil.ConnectionArgs,
@@ -271,10 +495,50 @@ func (r *campaignResolver) RepositoryDiffs(
 	return &changesetDiffsConnectionResolver{changesetsConnection}, nil
 }
 
+func (r *campaignResolver) DiffStat(ctx context.Context) (*graphqlbackend.DiffStat, error) {
+	cs, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{
+		CampaignID: r.Campaign.ID,
+		Limit:      -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stat := &graphqlbackend.DiffStat{}
+	for _, c := range cs {
+		if s, err := c.State(); err != nil || s != a8n.ChangesetStateOpen {
+			continue
+		}
+		if s := c.DiffStat(); s != nil {
+			stat.AddStat(*s)
+		}
+	}
+	return stat, nil
+}
+
+func (r *campaignResolver) ChangesetsWithConflictsCount(ctx context.Context) (int32, error) {
+	count, err := r.store.CountChangesets(ctx, ee.CountChangesetsOpts{
+		CampaignID:   r.Campaign.ID,
+		HasConflicts: true,
+	})
+	return int32(count), err
+}
+
 func (r *campaignResolver) Status(ctx context.Context) (graphqlbackend.BackgroundProcessStatus, error) {
 	return r.store.GetCampaignStatus(ctx, r.Campaign.ID)
 }
 
+func (r *campaignResolver) CloseStatus(ctx context.Context) (graphqlbackend.BackgroundProcessStatus, error) {
+	status, err := r.store.GetCampaignCloseStatus(ctx, r.Campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Total == 0 {
+		return nil, nil
+	}
+	return status, nil
+}
+
 type changesetDiffsConnectionResolver struct {
 	*changesetsConnectionResolver
 }
@@ -310,3 +574,30 @@ func (r *emptyChangesetPlansConnectionsResolver) TotalCount(ctx context.Context)
 func (r *emptyChangesetPlansConnectionsResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
 	return graphqlutil.HasNextPage(false), nil
 }
+
+type updateCampaignsResultResolver struct {
+	id       graphql.ID
+	campaign *campaignResolver
+	err      error
+}
+
+var _ graphqlbackend.UpdateCampaignsResultResolver = &updateCampaignsResultResolver{}
+
+func (r *updateCampaignsResultResolver) ID() graphql.ID {
+	return r.id
+}
+
+func (r *updateCampaignsResultResolver) Campaign() graphqlbackend.CampaignResolver {
+	if r.campaign == nil {
+		return nil
+	}
+	return r.campaign
+}
+
+func (r *updateCampaignsResultResolver) Error() *string {
+	if r.err == nil {
+		return nil
+	}
+	msg := r.err.Error()
+	return &msg
+}
@@ -0,0 +1,89 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func (r *Resolver) RenderChangesetTemplate(ctx context.Context, args *graphqlbackend.RenderChangesetTemplateArgs) (graphqlbackend.RenderedChangesetTemplateResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaigns and campaign jobs.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	var campaign *a8n.Campaign
+	if args.Campaign != nil {
+		id, err := unmarshalCampaignID(*args.Campaign)
+		if err != nil {
+			return nil, err
+		}
+
+		campaign, err = r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: id})
+		if err == ee.ErrNoResults {
+			return nil, &ee.ErrCampaignNotFound{ID: id}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		job  *a8n.CampaignJob
+		repo api.RepoName
+	)
+	if args.ChangesetPlan != nil {
+		id, err := unmarshalCampaignJobID(*args.ChangesetPlan)
+		if err != nil {
+			return nil, err
+		}
+
+		job, err = r.store.GetCampaignJob(ctx, ee.GetCampaignJobOpts{ID: id})
+		if err != nil {
+			return nil, err
+		}
+
+		dbRepo, err := db.Repos.Get(ctx, job.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		repo = dbRepo.Name
+	}
+
+	data, err := ee.NewChangesetTemplateData(campaign, repo, job, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &ee.ChangesetTemplate{Title: args.Title, Body: args.Body}
+	title, body, err := tmpl.Render(data)
+	if e, ok := err.(*ee.ErrInvalidChangesetTemplate); ok {
+		return &renderedChangesetTemplateResolver{errors: e.Errors}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderedChangesetTemplateResolver{title: &title, body: &body}, nil
+}
+
+type renderedChangesetTemplateResolver struct {
+	title, body *string
+	errors      []string
+}
+
+var _ graphqlbackend.RenderedChangesetTemplateResolver = &renderedChangesetTemplateResolver{}
+
+func (r *renderedChangesetTemplateResolver) Title() *string { return r.title }
+func (r *renderedChangesetTemplateResolver) Body() *string  { return r.body }
+
+func (r *renderedChangesetTemplateResolver) Errors() []string {
+	if r.errors == nil {
+		return []string{}
+	}
+	return r.errors
+}
@@ -167,17 +167,11 @@ func (r *changesetResolver) Repository(ctx context.Context) (*graphqlbackend.Rep
 }
 
 func (r *changesetResolver) Campaigns(ctx context.Context, args *graphqlbackend.ListCampaignArgs) (graphqlbackend.CampaignsConnectionResolver, error) {
-	opts := ee.ListCampaignsOpts{
-		ChangesetID: r.Changeset.ID,
-	}
-	state, err := parseCampaignState(args.State)
+	opts, err := listCampaignsOpts(args)
 	if err != nil {
 		return nil, err
 	}
-	opts.State = state
-	if args.First != nil {
-		opts.Limit = int(*args.First)
-	}
+	opts.ChangesetID = r.Changeset.ID
 	return &campaignsConnectionResolver{
 		store: r.store,
 		opts:  opts,
@@ -244,6 +238,34 @@ func (r *changesetResolver) CheckState(ctx context.Context) (*a8n.ChangesetCheck
 	return &state, nil
 }
 
+func (r *changesetResolver) ReviewerRequest(ctx context.Context) (graphqlbackend.ChangesetReviewerRequestResolver, error) {
+	job, err := r.store.GetChangesetJob(ctx, ee.GetChangesetJobOpts{ChangesetID: r.Changeset.ID})
+	if err == ee.ErrNoResults {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: job.CampaignID})
+	if err == ee.ErrNoResults {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := ee.CampaignSpecReviewers(c.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviewers) == 0 {
+		return nil, nil
+	}
+
+	return &changesetReviewerRequestResolver{job: job, reviewers: reviewers}, nil
+}
+
 func (r *changesetResolver) Labels(ctx context.Context) ([]graphqlbackend.ChangesetLabelResolver, error) {
 	// Only GitHub supports labels on pull requests so don't make a DB call unless we need to
 	if _, ok := r.Changeset.Metadata.(*github.PullRequest); !ok {
@@ -281,6 +303,46 @@ func (r *changesetResolver) Events(ctx context.Context, args *struct {
 	}, nil
 }
 
+func (r *changesetResolver) Rebases(ctx context.Context) ([]graphqlbackend.ChangesetRebaseResolver, error) {
+	es, _, err := r.store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{
+		ChangesetIDs: []int64{r.Changeset.ID},
+		Kinds:        []a8n.ChangesetEventKind{a8n.ChangesetEventKindSourcegraphRebased},
+		Limit:        -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetRebaseResolver, 0, len(es))
+	for _, e := range es {
+		if rebase, ok := e.Metadata.(*a8n.ChangesetRebase); ok {
+			resolvers = append(resolvers, &changesetRebaseResolver{rebase})
+		}
+	}
+	return resolvers, nil
+}
+
+type changesetRebaseResolver struct {
+	rebase *a8n.ChangesetRebase
+}
+
+func (r *changesetRebaseResolver) BaseOid() graphqlbackend.GitObjectID {
+	return graphqlbackend.GitObjectID(r.rebase.BaseOid)
+}
+
+func (r *changesetRebaseResolver) Reexecuted() bool { return r.rebase.Reexecuted }
+
+func (r *changesetRebaseResolver) Error() *string {
+	if r.rebase.Error == "" {
+		return nil
+	}
+	return &r.rebase.Error
+}
+
+func (r *changesetRebaseResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.rebase.CreatedAt}
+}
+
 func (r *changesetResolver) Diff(ctx context.Context) (*graphqlbackend.RepositoryComparisonResolver, error) {
 	s, err := r.Changeset.State()
 	if err != nil {
@@ -328,6 +390,37 @@ func (r *changesetResolver) Diff(ctx context.Context) (*graphqlbackend.Repositor
 	})
 }
 
+func (r *changesetResolver) DiffStat() (*graphqlbackend.DiffStat, error) {
+	s, err := r.Changeset.State()
+	if err != nil {
+		return nil, err
+	}
+
+	// Diff stats are only computed for open changesets
+	if s != a8n.ChangesetStateOpen {
+		return nil, nil
+	}
+
+	stat := r.Changeset.DiffStat()
+	if stat == nil {
+		return nil, nil
+	}
+	return graphqlbackend.NewDiffStat(*stat), nil
+}
+
+func (r *changesetResolver) HasConflicts() (*bool, error) {
+	s, err := r.Changeset.State()
+	if err != nil {
+		return nil, err
+	}
+
+	// Conflicts are only checked for open changesets
+	if s != a8n.ChangesetStateOpen {
+		return nil, nil
+	}
+	return r.Changeset.HasConflicts, nil
+}
+
 func (r *changesetResolver) Head(ctx context.Context) (*graphqlbackend.GitRefResolver, error) {
 	name, err := r.Changeset.HeadRef()
 	if err != nil {
@@ -406,6 +499,27 @@ func newRepositoryResolver(r *repos.Repo) *graphqlbackend.RepositoryResolver {
 	})
 }
 
+type changesetReviewerRequestResolver struct {
+	job       *a8n.ChangesetJob
+	reviewers []string
+}
+
+func (r *changesetReviewerRequestResolver) Reviewers() []string { return r.reviewers }
+
+func (r *changesetReviewerRequestResolver) RequestedAt() *graphqlbackend.DateTime {
+	if r.job.ReviewersRequestedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.job.ReviewersRequestedAt}
+}
+
+func (r *changesetReviewerRequestResolver) Error() *string {
+	if r.job.ReviewersError == "" {
+		return nil
+	}
+	return &r.job.ReviewersError
+}
+
 type changesetLabelResolver struct {
 	label a8n.ChangesetLabel
 }
@@ -0,0 +1,233 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxFeedEntries is the number of most recent entries in a campaigns feed.
+const maxFeedEntries = 50
+
+// atomFeed is an Atom feed as specified by RFC 4287, limited to the elements
+// used by the campaigns feed.
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Link    atomLink     `xml:"link"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Link    atomLink   `xml:"link"`
+	Author  atomAuthor `xml:"author"`
+	Summary string     `xml:"summary,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// serveFeed serves an Atom feed of the activity of the campaigns in the
+// namespace given by the "namespace" query parameter: campaigns being created
+// and closed, and their changesets being merged. Feed readers can
+// authenticate with an access token in the "token" query parameter.
+func (api *campaignsAPI) serveFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := api.feed(r)
+	if err != nil {
+		// Errors are reported as JSON, since feed readers only show that
+		// the feed couldn't be fetched anyway.
+		writeRESTResponse(w, 0, nil, err)
+		return
+	}
+
+	b, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		log15.Error("campaigns feed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(b)
+}
+
+func (api *campaignsAPI) feed(r *http.Request) (*atomFeed, error) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	namespace := graphql.ID(r.URL.Query().Get("namespace"))
+	if namespace == "" {
+		return nil, badRESTRequest(errors.New("missing namespace"))
+	}
+	userIDs, orgIDs, err := parseCampaignNamespaces(&[]graphql.ID{namespace})
+	if err != nil {
+		return nil, badRESTRequest(err)
+	}
+
+	campaigns, _, err := api.store.ListCampaigns(ctx, ee.ListCampaignsOpts{
+		NamespaceUserIDs: userIDs,
+		NamespaceOrgIDs:  orgIDs,
+		Limit:            -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := api.feedActivities(ctx, campaigns)
+	if err != nil {
+		return nil, err
+	}
+
+	base := globals.ExternalURL()
+	feedURL := base.ResolveReference(&url.URL{Path: "/.api/campaigns/feed", RawQuery: url.Values{"namespace": {string(namespace)}}.Encode()})
+	feed := &atomFeed{
+		ID:      feedURL.String(),
+		Title:   "Campaigns",
+		Updated: formatAtomTime(time.Now()),
+		Link:    atomLink{Href: base.ResolveReference(&url.URL{Path: "/campaigns"}).String()},
+		Entries: make([]*atomEntry, 0, len(activities)),
+	}
+	if len(activities) > 0 {
+		feed.Updated = formatAtomTime(activities[0].Time)
+	}
+
+	for _, a := range activities {
+		feed.Entries = append(feed.Entries, newAtomEntry(base, a))
+	}
+	return feed, nil
+}
+
+// feedActivity is an entry of a campaigns feed. It's either a
+// CampaignActivity of one of the campaigns or the closing of a campaign, in
+// which case Activity is nil.
+type feedActivity struct {
+	Campaign *a8n.Campaign
+	Activity *ee.CampaignActivity
+	Time     time.Time
+}
+
+// feedActivities returns the maxFeedEntries most recent activities of the
+// given campaigns, ordered from newest to oldest.
+func (api *campaignsAPI) feedActivities(ctx context.Context, campaigns []*a8n.Campaign) ([]*feedActivity, error) {
+	var changesetIDs []int64
+	for _, c := range campaigns {
+		changesetIDs = append(changesetIDs, c.ChangesetIDs...)
+	}
+
+	byID := map[int64]*a8n.Changeset{}
+	var es []*a8n.ChangesetEvent
+	if len(changesetIDs) > 0 {
+		cs, _, err := api.store.ListChangesets(ctx, ee.ListChangesetsOpts{
+			IDs:   changesetIDs,
+			Limit: -1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			byID[c.ID] = c
+		}
+
+		es, _, err = api.store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{
+			ChangesetIDs: changesetIDs,
+			Kinds:        ee.CampaignActivityEventKinds,
+			Limit:        -1,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var activities []*feedActivity
+	for _, c := range campaigns {
+		cs := make([]*a8n.Changeset, 0, len(c.ChangesetIDs))
+		for _, id := range c.ChangesetIDs {
+			if ch, ok := byID[id]; ok {
+				cs = append(cs, ch)
+			}
+		}
+
+		for _, a := range ee.CampaignActivityFeed(c, cs, es) {
+			switch a.Kind {
+			case ee.CampaignActivityCreated, ee.CampaignActivityChangesetMerged:
+				activities = append(activities, &feedActivity{Campaign: c, Activity: a, Time: a.Time})
+			}
+		}
+
+		if !c.ClosedAt.IsZero() {
+			activities = append(activities, &feedActivity{Campaign: c, Time: c.ClosedAt})
+		}
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Time.After(activities[j].Time)
+	})
+	if len(activities) > maxFeedEntries {
+		activities = activities[:maxFeedEntries]
+	}
+	return activities, nil
+}
+
+func newAtomEntry(base *url.URL, a *feedActivity) *atomEntry {
+	campaignID := marshalCampaignID(a.Campaign.ID)
+	e := &atomEntry{
+		Updated: formatAtomTime(a.Time),
+		Link:    atomLink{Href: base.ResolveReference(&url.URL{Path: "/campaigns/" + string(campaignID)}).String()},
+		Author:  atomAuthor{Name: "Sourcegraph"},
+	}
+
+	var kind string
+	switch {
+	case a.Activity == nil:
+		kind = "closed"
+		e.Title = fmt.Sprintf("Campaign %q was closed", a.Campaign.Name)
+	case a.Activity.Kind == ee.CampaignActivityCreated:
+		kind = "created"
+		e.Title = fmt.Sprintf("Campaign %q was created", a.Campaign.Name)
+		e.Summary = a.Campaign.Description
+	default:
+		kind = fmt.Sprintf("merged:%d", a.Activity.Changeset.ID)
+		title, err := a.Activity.Changeset.Title()
+		if err != nil {
+			title = fmt.Sprintf("#%d", a.Activity.Changeset.ID)
+		}
+		e.Title = fmt.Sprintf("Changeset %q of campaign %q was merged", title, a.Campaign.Name)
+		if u, err := a.Activity.Changeset.URL(); err == nil {
+			e.Summary = u
+		}
+	}
+
+	// Entry IDs must be stable across fetches so that feed readers don't
+	// show the same entry twice.
+	e.ID = fmt.Sprintf("tag:%s,%s:campaigns/%s/%s", base.Host, a.Campaign.CreatedAt.UTC().Format("2006-01-02"), campaignID, kind)
+	return e
+}
+
+func formatAtomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
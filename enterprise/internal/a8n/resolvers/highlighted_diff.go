@@ -0,0 +1,273 @@
+package resolvers
+
+import (
+	"context"
+	"html/template"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
+)
+
+const (
+	// defaultHighlightedDiffHunks is the number of hunks returned by
+	// ChangesetPlan.highlightedDiff if first isn't given.
+	defaultHighlightedDiffHunks = 20
+	// maxHighlightedDiffHunks is the maximum number of hunks returned by
+	// ChangesetPlan.highlightedDiff, since each hunk is highlighted
+	// separately.
+	maxHighlightedDiffHunks = 100
+	// highlightedDiffConcurrency is the number of hunks highlighted
+	// concurrently.
+	highlightedDiffConcurrency = 8
+)
+
+// The kinds of the lines of a HighlightedDiffHunk.
+const (
+	diffHunkLineAdded     = "ADDED"
+	diffHunkLineDeleted   = "DELETED"
+	diffHunkLineUnchanged = "UNCHANGED"
+)
+
+func (r *campaignJobResolver) HighlightedDiff(ctx context.Context, args *graphqlbackend.HighlightedDiffArgs) (graphqlbackend.HighlightedDiffHunkConnectionResolver, error) {
+	var offset int
+	if args.After != nil {
+		var err error
+		if offset, err = strconv.Atoi(*args.After); err != nil || offset < 0 {
+			return nil, errors.Errorf("invalid cursor %q", *args.After)
+		}
+	}
+
+	limit := defaultHighlightedDiffHunks
+	if args.First != nil {
+		limit = int(*args.First)
+	}
+	if limit < 0 || limit > maxHighlightedDiffHunks {
+		return nil, errors.Errorf("first must be between 0 and %d", maxHighlightedDiffHunks)
+	}
+
+	fileDiffs, err := diff.ParseMultiFileDiff([]byte(r.job.Diff))
+	if err != nil {
+		return nil, err
+	}
+
+	var hunks []*highlightedDiffHunkResolver
+	for _, fileDiff := range fileDiffs {
+		for _, hunk := range fileDiff.Hunks {
+			hunks = append(hunks, &highlightedDiffHunkResolver{fileDiff: fileDiff, hunk: hunk})
+		}
+	}
+
+	res := &highlightedDiffHunkConnectionResolver{
+		totalCount: len(hunks),
+		params: highlight.Params{
+			IsLightTheme:       args.IsLightTheme,
+			HighlightLongLines: args.HighlightLongLines,
+			Metadata:           highlight.Metadata{Revision: string(r.job.Rev)},
+		},
+	}
+	if offset < len(hunks) {
+		hunks = hunks[offset:]
+		if len(hunks) > limit {
+			hunks = hunks[:limit]
+			res.endCursor = strconv.Itoa(offset + limit)
+		}
+		res.hunks = hunks
+	}
+
+	return res, nil
+}
+
+type highlightedDiffHunkConnectionResolver struct {
+	hunks      []*highlightedDiffHunkResolver
+	totalCount int
+	endCursor  string
+	params     highlight.Params
+}
+
+var _ graphqlbackend.HighlightedDiffHunkConnectionResolver = &highlightedDiffHunkConnectionResolver{}
+
+func (r *highlightedDiffHunkConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.HighlightedDiffHunkResolver, error) {
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, highlightedDiffConcurrency)
+		errs = make([]error, len(r.hunks))
+	)
+	for i, h := range r.hunks {
+		wg.Add(1)
+		go func(i int, h *highlightedDiffHunkResolver) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = h.highlight(ctx, r.params)
+		}(i, h)
+	}
+	wg.Wait()
+
+	nodes := make([]graphqlbackend.HighlightedDiffHunkResolver, len(r.hunks))
+	for i, h := range r.hunks {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		nodes[i] = h
+	}
+	return nodes, nil
+}
+
+func (r *highlightedDiffHunkConnectionResolver) TotalCount() int32 {
+	return int32(r.totalCount)
+}
+
+func (r *highlightedDiffHunkConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	if r.endCursor == "" {
+		return graphqlutil.HasNextPage(false)
+	}
+	return graphqlutil.NextPageCursor(r.endCursor)
+}
+
+type highlightedDiffHunkResolver struct {
+	fileDiff *diff.FileDiff
+	hunk     *diff.Hunk
+
+	lines   []graphqlbackend.HighlightedDiffHunkLineResolver
+	aborted bool
+}
+
+var _ graphqlbackend.HighlightedDiffHunkResolver = &highlightedDiffHunkResolver{}
+
+func (r *highlightedDiffHunkResolver) OldPath() *string { return diffPathOrNull(r.fileDiff.OrigName) }
+func (r *highlightedDiffHunkResolver) NewPath() *string { return diffPathOrNull(r.fileDiff.NewName) }
+
+func (r *highlightedDiffHunkResolver) OldRange() *graphqlbackend.DiffHunkRange {
+	return graphqlbackend.NewDiffHunkRange(r.hunk.OrigStartLine, r.hunk.OrigLines)
+}
+
+func (r *highlightedDiffHunkResolver) NewRange() *graphqlbackend.DiffHunkRange {
+	return graphqlbackend.NewDiffHunkRange(r.hunk.NewStartLine, r.hunk.NewLines)
+}
+
+func (r *highlightedDiffHunkResolver) Section() *string {
+	if r.hunk.Section == "" {
+		return nil
+	}
+	return &r.hunk.Section
+}
+
+func (r *highlightedDiffHunkResolver) Lines() []graphqlbackend.HighlightedDiffHunkLineResolver {
+	return r.lines
+}
+
+func (r *highlightedDiffHunkResolver) Aborted() bool { return r.aborted }
+
+// highlight highlights the old and the new side of the hunk separately, each
+// consisting of the context lines and the deleted or added lines, and then
+// interleaves their lines again in the order of the hunk body. The sides are
+// highlighted without the rest of the file, so constructs that start before
+// the hunk, e.g. multi-line comments, may be highlighted incorrectly.
+func (r *highlightedDiffHunkResolver) highlight(ctx context.Context, p highlight.Params) error {
+	var kinds, oldLines, newLines []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(r.hunk.Body), "\n"), "\n") {
+		if line == "" {
+			// Some tools strip the trailing space of empty context lines.
+			line = " "
+		}
+		switch line[0] {
+		case '-':
+			kinds = append(kinds, diffHunkLineDeleted)
+			oldLines = append(oldLines, line[1:])
+		case '+':
+			kinds = append(kinds, diffHunkLineAdded)
+			newLines = append(newLines, line[1:])
+		case ' ':
+			kinds = append(kinds, diffHunkLineUnchanged)
+			oldLines = append(oldLines, line[1:])
+			newLines = append(newLines, line[1:])
+		default:
+			// "\ No newline at end of file"
+		}
+	}
+
+	p.Filepath = r.fileDiff.NewName
+	if p.Filepath == "/dev/null" {
+		p.Filepath = r.fileDiff.OrigName
+	}
+
+	oldHTML, oldAborted, err := highlightLines(ctx, p, oldLines)
+	if err != nil {
+		return err
+	}
+	newHTML, newAborted, err := highlightLines(ctx, p, newLines)
+	if err != nil {
+		return err
+	}
+	r.aborted = oldAborted || newAborted
+
+	r.lines = make([]graphqlbackend.HighlightedDiffHunkLineResolver, len(kinds))
+	var o, n int
+	for i, kind := range kinds {
+		var h template.HTML
+		switch kind {
+		case diffHunkLineDeleted:
+			h = oldHTML[o]
+			o++
+		case diffHunkLineAdded:
+			h = newHTML[n]
+			n++
+		default:
+			h = newHTML[n]
+			o++
+			n++
+		}
+		r.lines[i] = &highlightedDiffHunkLineResolver{kind: kind, html: string(h)}
+	}
+
+	return nil
+}
+
+// highlightLines returns the highlighted HTML of each of the given lines of
+// a file. Binary content and lines the highlighter didn't return are
+// rendered as plain text.
+func highlightLines(ctx context.Context, p highlight.Params, lines []string) ([]template.HTML, bool, error) {
+	if len(lines) == 0 {
+		return nil, false, nil
+	}
+
+	// Code trims the trailing newline again, so that empty last lines are kept.
+	p.Content = []byte(strings.Join(lines, "\n") + "\n")
+	if highlight.IsBinary(p.Content) {
+		return plainLines(lines), false, nil
+	}
+
+	html, aborted, err := highlight.CodeAsLines(ctx, p)
+	if err != nil {
+		return nil, false, err
+	}
+	// The highlighter may return an additional empty line at the end.
+	if len(html) < len(lines) {
+		return plainLines(lines), aborted, nil
+	}
+	return html[:len(lines)], aborted, nil
+}
+
+func plainLines(lines []string) []template.HTML {
+	html := make([]template.HTML, len(lines))
+	for i, line := range lines {
+		html[i] = template.HTML("<span>" + template.HTMLEscapeString(line) + "</span>")
+	}
+	return html
+}
+
+type highlightedDiffHunkLineResolver struct {
+	kind string
+	html string
+}
+
+var _ graphqlbackend.HighlightedDiffHunkLineResolver = &highlightedDiffHunkLineResolver{}
+
+func (r *highlightedDiffHunkLineResolver) Kind() string { return r.kind }
+func (r *highlightedDiffHunkLineResolver) HTML() string { return r.html }
@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// previewCache caches the CampaignJobs computed for campaign previews, keyed
+// by the hash of the patches they were computed from. Resolving the base
+// revisions of a large campaign takes a while, and the preview page is
+// reloaded every time the user pages through the changesets.
+//
+// The TTL bounds how long a preview may show a base revision that moved in
+// the meantime.
+var previewCache = rcache.NewWithTTL("a8n_campaign_preview:v1", 10*60)
+
+// campaignSpecHash returns the key of the preview of the given patches in
+// previewCache. Any change to the patches changes the key.
+func campaignSpecHash(patches []a8n.CampaignPlanPatch) (string, error) {
+	b, err := json.Marshal(patches)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+func getCachedPreview(hash string) ([]*a8n.CampaignJob, bool) {
+	b, ok := previewCache.Get(hash)
+	if !ok {
+		return nil, false
+	}
+
+	var jobs []*a8n.CampaignJob
+	if err := json.Unmarshal(b, &jobs); err != nil {
+		log15.Warn("a8n: decoding cached campaign preview", "hash", hash, "error", err)
+		previewCache.Delete(hash)
+		return nil, false
+	}
+	return jobs, true
+}
+
+func setCachedPreview(hash string, jobs []*a8n.CampaignJob) {
+	b, err := json.Marshal(jobs)
+	if err != nil {
+		log15.Warn("a8n: encoding campaign preview", "hash", hash, "error", err)
+		return
+	}
+	previewCache.Set(hash, b)
+}
+
+// invalidateCachedPreview removes the preview of the given patches from
+// previewCache.
+func invalidateCachedPreview(patches []a8n.CampaignPlanPatch) {
+	hash, err := campaignSpecHash(patches)
+	if err != nil {
+		return
+	}
+	previewCache.Delete(hash)
+}
@@ -0,0 +1,108 @@
+package resolvers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+)
+
+func TestCampaignSpecHash(t *testing.T) {
+	patches := []a8n.CampaignPlanPatch{
+		{Repo: 1, BaseRevision: "refs/heads/master", Patch: "diff a"},
+		{Repo: 2, BaseRevision: "refs/heads/master", Patch: "diff b"},
+	}
+
+	hash := func(patches []a8n.CampaignPlanPatch) string {
+		h, err := campaignSpecHash(patches)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	want := hash(patches)
+	if have := hash(append([]a8n.CampaignPlanPatch{}, patches...)); have != want {
+		t.Errorf("same patches: have key %q, want %q", have, want)
+	}
+
+	for name, changed := range map[string][]a8n.CampaignPlanPatch{
+		"repo":          {{Repo: 3, BaseRevision: "refs/heads/master", Patch: "diff a"}, patches[1]},
+		"base revision": {{Repo: 1, BaseRevision: "refs/heads/dev", Patch: "diff a"}, patches[1]},
+		"patch":         {{Repo: 1, BaseRevision: "refs/heads/master", Patch: "diff c"}, patches[1]},
+		"order":         {patches[1], patches[0]},
+		"subset":        patches[:1],
+		"none":          nil,
+	} {
+		if have := hash(changed); have == want {
+			t.Errorf("changed %s: have the key of the original patches", name)
+		}
+	}
+}
+
+func TestPreviewCache(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	patches := []a8n.CampaignPlanPatch{
+		{Repo: 1, BaseRevision: "refs/heads/master", Patch: "diff a"},
+	}
+	hash, err := campaignSpecHash(patches)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := []*a8n.CampaignJob{{
+		RepoID:  1,
+		Rev:     "deadbeef",
+		BaseRef: "refs/heads/master",
+		Diff:    "diff a",
+	}}
+
+	if _, ok := getCachedPreview(hash); ok {
+		t.Fatal("empty cache: have preview")
+	}
+
+	setCachedPreview(hash, jobs)
+	have, ok := getCachedPreview(hash)
+	if !ok {
+		t.Fatal("have no preview after setting it")
+	}
+	if diff := cmp.Diff(have, jobs); diff != "" {
+		t.Fatal(diff)
+	}
+
+	t.Run("invalidation", func(t *testing.T) {
+		setCachedPreview(hash, jobs)
+		invalidateCachedPreview(patches)
+		if _, ok := getCachedPreview(hash); ok {
+			t.Fatal("have preview after invalidation")
+		}
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		previewCache.Set(hash, []byte("not json"))
+		if _, ok := getCachedPreview(hash); ok {
+			t.Fatal("have preview from invalid entry")
+		}
+		if _, ok := previewCache.Get(hash); ok {
+			t.Fatal("invalid entry wasn't deleted")
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		defer func(c *rcache.Cache) { previewCache = c }(previewCache)
+		previewCache = rcache.NewWithTTL("a8n_campaign_preview:test", 1)
+
+		setCachedPreview(hash, jobs)
+		if _, ok := getCachedPreview(hash); !ok {
+			t.Fatal("have no preview before the TTL")
+		}
+
+		time.Sleep(2 * time.Second)
+		if _, ok := getCachedPreview(hash); ok {
+			t.Fatal("have preview after the TTL")
+		}
+	})
+}
@@ -3,14 +3,17 @@ package resolvers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -18,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -38,17 +42,32 @@ func NewResolver(db *sql.DB) graphqlbackend.A8NResolver {
 }
 
 func allowReadAccess(ctx context.Context) error {
+	// Restricted access tokens need the "campaigns:write" scope to view campaigns.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return backend.WithPermissionDeniedCode(err)
+	}
+
 	if readAccess := conf.AutomationReadAccessEnabled(); readAccess {
 		return nil
 	}
 
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return err
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return backend.WithPermissionDeniedCode(err)
 	}
 
 	return nil
 }
 
+// wrapServiceError wraps err with the given message, unless err carries
+// GraphQL error extensions. graphql-go only picks up the extensions of the
+// error returned by a resolver, not those of its cause.
+func wrapServiceError(err error, message string) error {
+	if _, ok := err.(interface{ Extensions() map[string]interface{} }); ok {
+		return err
+	}
+	return errors.Wrap(err, message)
+}
+
 func (r *Resolver) ChangesetByID(ctx context.Context, id graphql.ID) (graphqlbackend.ExternalChangesetResolver, error) {
 	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access changesets.
 	if err := allowReadAccess(ctx); err != nil {
@@ -61,6 +80,9 @@ func (r *Resolver) ChangesetByID(ctx context.Context, id graphql.ID) (graphqlbac
 	}
 
 	changeset, err := r.store.GetChangeset(ctx, ee.GetChangesetOpts{ID: changesetID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrChangesetNotFound{ID: changesetID}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +102,9 @@ func (r *Resolver) CampaignByID(ctx context.Context, id graphql.ID) (graphqlback
 	}
 
 	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: campaignID}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +112,42 @@ func (r *Resolver) CampaignByID(ctx context.Context, id graphql.ID) (graphqlback
 	return &campaignResolver{store: r.store, Campaign: campaign}, nil
 }
 
+func (r *Resolver) CampaignByName(ctx context.Context, args *graphqlbackend.CampaignByNameArgs) (graphqlbackend.CampaignByNameResultResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	opts := ee.GetCampaignByNameOpts{Name: args.Name}
+
+	var err error
+	switch relay.UnmarshalKind(args.Namespace) {
+	case "User":
+		err = relay.UnmarshalSpec(args.Namespace, &opts.NamespaceUserID)
+	case "Org":
+		err = relay.UnmarshalSpec(args.Namespace, &opts.NamespaceOrgID)
+	default:
+		err = errors.Errorf("Invalid namespace %q", args.Namespace)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, renamed, err := r.store.GetCampaignByName(ctx, opts)
+	if err == ee.ErrNoResults {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignByNameResultResolver{
+		campaign: &campaignResolver{store: r.store, Campaign: campaign},
+		renamed:  renamed,
+	}, nil
+}
+
 func (r *Resolver) ChangesetPlanByID(ctx context.Context, id graphql.ID) (graphqlbackend.ChangesetPlanResolver, error) {
 	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign jobs.
 	if err := allowReadAccess(ctx); err != nil {
@@ -118,6 +179,9 @@ func (r *Resolver) CampaignPlanByID(ctx context.Context, id graphql.ID) (graphql
 	}
 
 	plan, err := r.store.GetCampaignPlan(ctx, ee.GetCampaignPlanOpts{ID: planID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignPlanNotFound{ID: planID}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +191,8 @@ func (r *Resolver) CampaignPlanByID(ctx context.Context, id graphql.ID) (graphql
 
 func (r *Resolver) AddChangesetsToCampaign(ctx context.Context, args *graphqlbackend.AddChangesetsToCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
 	// 🚨 SECURITY: Only site admins may modify changesets and campaigns for now.
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, err
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
 	}
 
 	campaignID, err := unmarshalCampaignID(args.Campaign)
@@ -158,6 +222,9 @@ func (r *Resolver) AddChangesetsToCampaign(ctx context.Context, args *graphqlbac
 	defer tx.Done(&err)
 
 	campaign, err := tx.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: campaignID}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +233,12 @@ func (r *Resolver) AddChangesetsToCampaign(ctx context.Context, args *graphqlbac
 		return nil, errors.New("Changesets can only be added to campaigns that don't create their own changesets")
 	}
 
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	svc.OverrideQuotas(args.OverrideQuotas)
+	if err = svc.CheckChangesetsQuota(ctx, tx, campaign, len(changesetIDs)); err != nil {
+		return nil, err
+	}
+
 	changesets, _, err := tx.ListChangesets(ctx, ee.ListChangesetsOpts{IDs: changesetIDs})
 	if err != nil {
 		return nil, err
@@ -192,6 +265,99 @@ func (r *Resolver) AddChangesetsToCampaign(ctx context.Context, args *graphqlbac
 	return &campaignResolver{store: r.store, Campaign: campaign}, nil
 }
 
+func (r *Resolver) ImportChangesets(ctx context.Context, args *graphqlbackend.ImportChangesetsArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ImportChangesets", fmt.Sprintf("Campaign: %q, Changesets: %d", args.Campaign, len(args.Changesets)))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may modify changesets and campaigns for now.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	// We check the campaign before creating the changesets, which would
+	// otherwise be synced without belonging to any campaign.
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: campaignID}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.CampaignPlanID != 0 {
+		return nil, errors.New("Changesets can only be imported into campaigns that don't create their own changesets")
+	}
+
+	var create graphqlbackend.CreateChangesetsArgs
+	for _, c := range args.Changesets {
+		var in struct {
+			Repository graphql.ID
+			ExternalID string
+		}
+
+		switch {
+		case c.ExternalURL != nil && c.Repository == nil && c.ExternalID == nil:
+			uri, externalID, err := ee.ParseChangesetURL(*c.ExternalURL)
+			if err != nil {
+				return nil, err
+			}
+
+			repo, err := db.Repos.GetByName(ctx, uri)
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting repository of changeset %q", *c.ExternalURL)
+			}
+
+			in.Repository = graphqlbackend.MarshalRepositoryID(repo.ID)
+			in.ExternalID = externalID
+
+		case c.ExternalURL == nil && c.Repository != nil && c.ExternalID != nil:
+			in.Repository = *c.Repository
+			in.ExternalID = *c.ExternalID
+
+		default:
+			return nil, errors.New("either externalURL or both repository and externalID must be given")
+		}
+
+		create.Input = append(create.Input, in)
+	}
+
+	cs, _, err := r.createChangesets(ctx, &create)
+	if err != nil {
+		return nil, err
+	}
+
+	inCampaign := make(map[int64]bool, len(campaign.ChangesetIDs))
+	for _, id := range campaign.ChangesetIDs {
+		inCampaign[id] = true
+	}
+
+	var changesetIDs []graphql.ID
+	for _, c := range cs {
+		if !inCampaign[c.ID] {
+			inCampaign[c.ID] = true
+			changesetIDs = append(changesetIDs, marshalChangesetID(c.ID))
+		}
+	}
+
+	if len(changesetIDs) == 0 {
+		return &campaignResolver{store: r.store, Campaign: campaign}, nil
+	}
+
+	return r.AddChangesetsToCampaign(ctx, &graphqlbackend.AddChangesetsToCampaignArgs{
+		Campaign:       args.Campaign,
+		Changesets:     changesetIDs,
+		OverrideQuotas: args.OverrideQuotas,
+	})
+}
+
 func (r *Resolver) CreateCampaign(ctx context.Context, args *graphqlbackend.CreateCampaignArgs) (graphqlbackend.CampaignResolver, error) {
 	var err error
 	tr, ctx := trace.New(ctx, "Resolver.CreateCampaign", args.Input.Name)
@@ -219,6 +385,14 @@ func (r *Resolver) CreateCampaign(ctx context.Context, args *graphqlbackend.Crea
 		campaign.Branch = *args.Input.Branch
 	}
 
+	if args.Input.Spec != nil {
+		campaign.Spec = json.RawMessage(*args.Input.Spec)
+	}
+
+	if args.Input.AutoRebase != nil {
+		campaign.AutoRebase = *args.Input.AutoRebase
+	}
+
 	if args.Input.Plan != nil {
 		planID, err := unmarshalCampaignPlanID(*args.Input.Plan)
 		if err != nil {
@@ -227,6 +401,14 @@ func (r *Resolver) CreateCampaign(ctx context.Context, args *graphqlbackend.Crea
 		campaign.CampaignPlanID = planID
 	}
 
+	if args.Input.Parent != nil {
+		parentID, err := unmarshalCampaignID(*args.Input.Parent)
+		if err != nil {
+			return nil, err
+		}
+		campaign.ParentCampaignID = parentID
+	}
+
 	var draft bool
 	if args.Input.Draft != nil {
 		draft = *args.Input.Draft
@@ -246,11 +428,36 @@ func (r *Resolver) CreateCampaign(ctx context.Context, args *graphqlbackend.Crea
 	}
 
 	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
-	err = svc.CreateCampaign(ctx, campaign, draft)
+	if args.Input.OverrideQuotas != nil {
+		svc.OverrideQuotas(*args.Input.OverrideQuotas)
+	}
+
+	ids, err := svc.WithIdempotencyKey(ctx, user.ID, "createCampaign", args.Input.IdempotencyKey, func() ([]int64, error) {
+		if err := svc.CreateCampaign(ctx, campaign, draft); err != nil {
+			return nil, err
+		}
+		return []int64{campaign.ID}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if len(ids) != 1 {
+		return nil, errors.Errorf("unexpected result of createCampaign: %v", ids)
+	}
+
+	if campaign.ID != ids[0] {
+		// The campaign was created by a previous request with the same
+		// idempotency key.
+		campaign, err = r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: ids[0]})
+		if err == ee.ErrNoResults {
+			return nil, &ee.ErrCampaignNotFound{ID: ids[0]}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &campaignResolver{store: r.store, Campaign: campaign}, nil
 }
 
@@ -262,8 +469,8 @@ func (r *Resolver) UpdateCampaign(ctx context.Context, args *graphqlbackend.Upda
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, err
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
 	}
 
 	campaignID, err := unmarshalCampaignID(args.Input.ID)
@@ -275,6 +482,8 @@ func (r *Resolver) UpdateCampaign(ctx context.Context, args *graphqlbackend.Upda
 	updateArgs.Name = args.Input.Name
 	updateArgs.Description = args.Input.Description
 	updateArgs.Branch = args.Input.Branch
+	updateArgs.Spec = args.Input.Spec
+	updateArgs.AutoRebase = args.Input.AutoRebase
 
 	if args.Input.Plan != nil {
 		campaignPlanID, err := unmarshalCampaignPlanID(*args.Input.Plan)
@@ -311,8 +520,8 @@ func (r *Resolver) DeleteCampaign(ctx context.Context, args *graphqlbackend.Dele
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, err
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
 	}
 
 	campaignID, err := unmarshalCampaignID(args.Campaign)
@@ -325,6 +534,124 @@ func (r *Resolver) DeleteCampaign(ctx context.Context, args *graphqlbackend.Dele
 	return &graphqlbackend.EmptyResponse{}, err
 }
 
+func (r *Resolver) RestoreCampaign(ctx context.Context, args *graphqlbackend.RestoreCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.RestoreCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may update campaigns for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	campaign, err := svc.RestoreCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, wrapServiceError(err, "restoring campaign")
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+func (r *Resolver) RollbackCampaign(ctx context.Context, args *graphqlbackend.RollbackCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.RollbackCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may create campaigns for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	rollback, err := svc.RollbackCampaign(ctx, campaignID, actor.FromContext(ctx).UID)
+	if err != nil {
+		return nil, wrapServiceError(err, "rolling back campaign")
+	}
+
+	return &campaignResolver{store: r.store, Campaign: rollback}, nil
+}
+
+func (r *Resolver) SetCampaignParent(ctx context.Context, args *graphqlbackend.SetCampaignParentArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SetCampaignParent", fmt.Sprintf("Campaign: %q", args.Campaign))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may update campaigns for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID int64
+	if args.Parent != nil {
+		if parentID, err = unmarshalCampaignID(*args.Parent); err != nil {
+			return nil, err
+		}
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	campaign, err := svc.SetCampaignParent(ctx, campaignID, parentID)
+	if err != nil {
+		return nil, wrapServiceError(err, "setting campaign parent")
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
+func (r *Resolver) MoveCampaign(ctx context.Context, args *graphqlbackend.MoveCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.MoveCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may update campaigns for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	var beforeID int64
+	if args.Before != nil {
+		if beforeID, err = unmarshalCampaignID(*args.Before); err != nil {
+			return nil, err
+		}
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	campaign, err := svc.MoveCampaign(ctx, campaignID, beforeID)
+	if err != nil {
+		return nil, wrapServiceError(err, "moving campaign")
+	}
+
+	return &campaignResolver{store: r.store, Campaign: campaign}, nil
+}
+
 func (r *Resolver) RetryCampaign(ctx context.Context, args *graphqlbackend.RetryCampaignArgs) (graphqlbackend.CampaignResolver, error) {
 	var err error
 	tr, ctx := trace.New(ctx, "Resolver.RetryCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
@@ -334,8 +661,8 @@ func (r *Resolver) RetryCampaign(ctx context.Context, args *graphqlbackend.Retry
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(errors.Wrap(err, "checking if user is admin"))
 	}
 
 	campaignID, err := unmarshalCampaignID(args.Campaign)
@@ -344,6 +671,9 @@ func (r *Resolver) RetryCampaign(ctx context.Context, args *graphqlbackend.Retry
 	}
 
 	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: campaignID}
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "getting campaign")
 	}
@@ -361,15 +691,46 @@ func (r *Resolver) Campaigns(ctx context.Context, args *graphqlbackend.ListCampa
 	if err := allowReadAccess(ctx); err != nil {
 		return nil, err
 	}
-	var opts ee.ListCampaignsOpts
-	state, err := parseCampaignState(args.State)
+	opts, err := listCampaignsOpts(args)
 	if err != nil {
 		return nil, err
 	}
-	opts.State = state
-	if args.First != nil {
-		opts.Limit = int(*args.First)
+	return &campaignsConnectionResolver{
+		store: r.store,
+		opts:  opts,
+	}, nil
+}
+
+func (r *Resolver) UserCampaigns(ctx context.Context, user *graphqlbackend.UserResolver, args *graphqlbackend.UserCampaignsArgs) (graphqlbackend.CampaignsConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins may see which campaigns the
+	// user reviews or is subscribed to.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, user.DatabaseID()); err != nil {
+		return nil, err
+	}
+
+	opts, err := listCampaignsOpts(&graphqlbackend.ListCampaignArgs{
+		First:             args.First,
+		After:             args.After,
+		Query:             args.Query,
+		State:             args.State,
+		HasOpenChangesets: args.HasOpenChangesets,
+		ChangesetState:    args.ChangesetState,
+	})
+	if err != nil {
+		return nil, err
+	}
+	opts.InvolvedUserID = user.DatabaseID()
+	if args.InvolvedAs != nil {
+		opts.InvolvedAs = a8n.CampaignInvolvement(*args.InvolvedAs)
+		if !opts.InvolvedAs.Valid() {
+			return nil, fmt.Errorf("unknown campaign involvement %q", *args.InvolvedAs)
+		}
 	}
+
 	return &campaignsConnectionResolver{
 		store: r.store,
 		opts:  opts,
@@ -378,10 +739,65 @@ func (r *Resolver) Campaigns(ctx context.Context, args *graphqlbackend.ListCampa
 
 func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.CreateChangesetsArgs) (_ []graphqlbackend.ExternalChangesetResolver, err error) {
 	// 🚨 SECURITY: Only site admins may create changesets for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	var (
+		cs      []*a8n.Changeset
+		repoSet map[api.RepoID]*repos.Repo
+	)
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	ids, err := svc.WithIdempotencyKey(ctx, actor.FromContext(ctx).UID, "createChangesets", args.IdempotencyKey, func() (_ []int64, err error) {
+		if cs, repoSet, err = r.createChangesets(ctx, args); err != nil {
+			return nil, err
+		}
+
+		ids := make([]int64, len(cs))
+		for i, c := range cs {
+			ids[i] = c.ID
+		}
+		return ids, nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	if cs == nil && len(ids) > 0 {
+		// The changesets were created by a previous request with the same
+		// idempotency key.
+		listed, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{IDs: ids, Limit: -1})
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[int64]*a8n.Changeset, len(listed))
+		for _, c := range listed {
+			byID[c.ID] = c
+		}
+		for _, id := range ids {
+			if c, ok := byID[id]; ok {
+				cs = append(cs, c)
+			}
+		}
+	}
+
+	csr := make([]graphqlbackend.ExternalChangesetResolver, len(cs))
+	for i := range cs {
+		csr[i] = &changesetResolver{
+			store:         r.store,
+			Changeset:     cs[i],
+			preloadedRepo: repoSet[cs[i].RepoID],
+		}
+	}
+
+	return csr, nil
+}
+
+// createChangesets creates the changesets in the given args, or gets them if
+// they already exist, and syncs them.
+func (r *Resolver) createChangesets(ctx context.Context, args *graphqlbackend.CreateChangesetsArgs) (_ []*a8n.Changeset, _ map[api.RepoID]*repos.Repo, err error) {
 	var repoIDs []api.RepoID
 	repoSet := map[api.RepoID]*repos.Repo{}
 	cs := make([]*a8n.Changeset, 0, len(args.Input))
@@ -389,7 +805,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 	for _, c := range args.Input {
 		repoID, err := graphqlbackend.UnmarshalRepositoryID(c.Repository)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if _, ok := repoSet[repoID]; !ok {
@@ -405,7 +821,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 
 	tx, err := r.store.Transact(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer tx.Done(&err)
@@ -414,7 +830,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 
 	rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, r := range rs {
@@ -424,7 +840,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 				r.ExternalRepo.ServiceType,
 				r.Name,
 			)
-			return nil, err
+			return nil, nil, err
 		}
 
 		repoSet[r.ID] = r
@@ -432,7 +848,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 
 	for id, r := range repoSet {
 		if r == nil {
-			return nil, errors.Errorf("repo %v not found", graphqlbackend.MarshalRepositoryID(api.RepoID(id)))
+			return nil, nil, errors.Errorf("repo %v not found", graphqlbackend.MarshalRepositoryID(api.RepoID(id)))
 		}
 	}
 
@@ -443,7 +859,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 	err = tx.CreateChangesets(ctx, cs...)
 	if err != nil {
 		if _, ok := err.(ee.AlreadyExistError); !ok {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -454,19 +870,10 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 		HTTPFactory: r.httpFactory,
 	}
 	if err = syncer.SyncChangesets(ctx, cs...); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	csr := make([]graphqlbackend.ExternalChangesetResolver, len(cs))
-	for i := range cs {
-		csr[i] = &changesetResolver{
-			store:         r.store,
-			Changeset:     cs[i],
-			preloadedRepo: repoSet[cs[i].RepoID],
-		}
-	}
-
-	return csr, nil
+	return cs, repoSet, nil
 }
 
 func (r *Resolver) Changesets(ctx context.Context, args *graphqlutil.ConnectionArgs) (graphqlbackend.ExternalChangesetsConnectionResolver, error) {
@@ -491,8 +898,8 @@ func (r *Resolver) CreateCampaignPlanFromPatches(ctx context.Context, args graph
 	}()
 
 	// 🚨 SECURITY: Only site admins may create campaign plans for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, err
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
 	}
 
 	user, err := backend.CurrentUser(ctx)
@@ -503,8 +910,70 @@ func (r *Resolver) CreateCampaignPlanFromPatches(ctx context.Context, args graph
 		return nil, backend.ErrNotAuthenticated
 	}
 
-	patches := make([]a8n.CampaignPlanPatch, len(args.Patches))
-	for i, patch := range args.Patches {
+	patches, err := unmarshalCampaignPlanPatches(args.Patches)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	plan, err := svc.CreateCampaignPlanFromPatches(ctx, patches, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The preview of the patches was superseded by the plan, whose base
+	// revisions were resolved again.
+	invalidateCachedPreview(patches)
+
+	return &campaignPlanResolver{store: r.store, campaignPlan: plan}, nil
+}
+
+func (r *Resolver) PreviewCampaign(ctx context.Context, args graphqlbackend.PreviewCampaignArgs) (_ graphqlbackend.ChangesetPlansConnectionResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.PreviewCampaign", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may preview campaigns for now, since
+	// previewing resolves the same data as creating a campaign plan.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	patches, err := unmarshalCampaignPlanPatches(args.Patches)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := campaignSpecHash(patches)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, ok := getCachedPreview(hash)
+	if !ok {
+		svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+		jobs, err = svc.PreviewCampaignPlanFromPatches(ctx, patches)
+		if err != nil {
+			return nil, err
+		}
+		setCachedPreview(hash, jobs)
+	}
+	tr.LazyPrintf("cached: %t", ok)
+
+	return &previewChangesetPlansConnectionResolver{
+		store: r.store,
+		jobs:  jobs,
+		first: args.First,
+	}, nil
+}
+
+// unmarshalCampaignPlanPatches converts the given GraphQL patches into
+// CampaignPlanPatches, ensuring that each patch is a valid unified diff.
+func unmarshalCampaignPlanPatches(args []graphqlbackend.CampaignPlanPatch) ([]a8n.CampaignPlanPatch, error) {
+	patches := make([]a8n.CampaignPlanPatch, len(args))
+	for i, patch := range args {
 		repo, err := graphqlbackend.UnmarshalRepositoryID(patch.Repository)
 		if err != nil {
 			return nil, err
@@ -528,14 +997,7 @@ func (r *Resolver) CreateCampaignPlanFromPatches(ctx context.Context, args graph
 			Patch:        patch.Patch,
 		}
 	}
-
-	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
-	plan, err := svc.CreateCampaignPlanFromPatches(ctx, patches, user.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	return &campaignPlanResolver{store: r.store, campaignPlan: plan}, nil
+	return patches, nil
 }
 
 func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.CloseCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
@@ -546,8 +1008,8 @@ func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.Close
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(errors.Wrap(err, "checking if user is admin"))
 	}
 
 	campaignID, err := unmarshalCampaignID(args.Campaign)
@@ -559,12 +1021,79 @@ func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.Close
 
 	campaign, err := svc.CloseCampaign(ctx, campaignID, args.CloseChangesets)
 	if err != nil {
-		return nil, errors.Wrap(err, "closing campaign")
+		return nil, wrapServiceError(err, "closing campaign")
 	}
 
 	return &campaignResolver{store: r.store, Campaign: campaign}, nil
 }
 
+func (r *Resolver) UpdateCampaigns(ctx context.Context, args *graphqlbackend.UpdateCampaignsArgs) (_ []graphqlbackend.UpdateCampaignsResultResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.UpdateCampaigns", fmt.Sprintf("Campaigns: %q, State: %q", args.IDs, args.State))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may update campaigns for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(errors.Wrap(err, "checking if user is admin"))
+	}
+
+	state, err := parseCampaignState(&args.State)
+	if err != nil {
+		return nil, err
+	}
+	if state == a8n.CampaignStateAny {
+		return nil, fmt.Errorf("invalid state %q", args.State)
+	}
+
+	results := make([]graphqlbackend.UpdateCampaignsResultResolver, len(args.IDs))
+	campaignIDs := make([]int64, len(args.IDs))
+	ids := make([]int64, 0, len(args.IDs))
+	seen := make(map[int64]bool, len(args.IDs))
+	for i, gqlID := range args.IDs {
+		id, err := unmarshalCampaignID(gqlID)
+		if err != nil {
+			results[i] = &updateCampaignsResultResolver{id: gqlID, err: errors.Wrap(err, "unmarshaling campaign id")}
+			continue
+		}
+
+		campaignIDs[i] = id
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	svc.OverrideQuotas(args.OverrideQuotas)
+	updates, err := svc.UpdateCampaignsState(ctx, ids, state == a8n.CampaignStateClosed, args.CloseChangesets)
+	if err != nil {
+		return nil, errors.Wrap(err, "updating campaigns")
+	}
+
+	byID := make(map[int64]*ee.CampaignStateUpdate, len(updates))
+	for _, u := range updates {
+		byID[u.ID] = u
+	}
+
+	for i, gqlID := range args.IDs {
+		if results[i] != nil {
+			continue
+		}
+
+		u := byID[campaignIDs[i]]
+
+		res := &updateCampaignsResultResolver{id: gqlID, err: u.Err}
+		if u.Campaign != nil {
+			res.campaign = &campaignResolver{store: r.store, Campaign: u.Campaign}
+		}
+		results[i] = res
+	}
+
+	return results, nil
+}
+
 func (r *Resolver) PublishCampaign(ctx context.Context, args *graphqlbackend.PublishCampaignArgs) (_ graphqlbackend.CampaignResolver, err error) {
 	tr, ctx := trace.New(ctx, "Resolver.PublishCampaign", fmt.Sprintf("Campaign: %q", args.Campaign))
 	defer func() {
@@ -573,8 +1102,8 @@ func (r *Resolver) PublishCampaign(ctx context.Context, args *graphqlbackend.Pub
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(errors.Wrap(err, "checking if user is admin"))
 	}
 
 	campaignID, err := unmarshalCampaignID(args.Campaign)
@@ -583,9 +1112,10 @@ func (r *Resolver) PublishCampaign(ctx context.Context, args *graphqlbackend.Pub
 	}
 
 	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	svc.OverrideQuotas(args.OverrideQuotas)
 	campaign, err := svc.PublishCampaign(ctx, campaignID)
 	if err != nil {
-		return nil, errors.Wrap(err, "publishing campaign")
+		return nil, wrapServiceError(err, "publishing campaign")
 	}
 
 	return &campaignResolver{store: r.store, Campaign: campaign}, nil
@@ -599,8 +1129,8 @@ func (r *Resolver) PublishChangeset(ctx context.Context, args *graphqlbackend.Pu
 	}()
 
 	// 🚨 SECURITY: Only site admins may update campaigns for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
-		return nil, errors.Wrap(err, "checking if user is admin")
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(errors.Wrap(err, "checking if user is admin"))
 	}
 
 	campaignJobID, err := unmarshalCampaignJobID(args.ChangesetPlan)
@@ -609,6 +1139,7 @@ func (r *Resolver) PublishChangeset(ctx context.Context, args *graphqlbackend.Pu
 	}
 
 	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	svc.OverrideQuotas(args.OverrideQuotas)
 	err = svc.CreateChangesetJobForCampaignJob(ctx, campaignJobID)
 	if err != nil {
 		return nil, err
@@ -617,6 +1148,107 @@ func (r *Resolver) PublishChangeset(ctx context.Context, args *graphqlbackend.Pu
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
+func (r *Resolver) SyncChangeset(ctx context.Context, args *graphqlbackend.SyncChangesetArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SyncChangeset", fmt.Sprintf("Changeset: %q", args.Changeset))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may sync changesets for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(errors.Wrap(err, "checking if user is admin"))
+	}
+
+	changesetID, err := unmarshalChangesetID(args.Changeset)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := ee.NewService(r.store, gitserver.DefaultClient, nil, r.httpFactory)
+	if err = svc.SyncChangeset(ctx, changesetID); err != nil {
+		return nil, err
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func parseCampaignNamespaces(namespaces *[]graphql.ID) (userIDs, orgIDs []int32, err error) {
+	if namespaces == nil {
+		return nil, nil, nil
+	}
+
+	for _, ns := range *namespaces {
+		var id int32
+		switch relay.UnmarshalKind(ns) {
+		case "User":
+			if err = relay.UnmarshalSpec(ns, &id); err != nil {
+				return nil, nil, err
+			}
+			userIDs = append(userIDs, id)
+		case "Org":
+			if err = relay.UnmarshalSpec(ns, &id); err != nil {
+				return nil, nil, err
+			}
+			orgIDs = append(orgIDs, id)
+		default:
+			return nil, nil, errors.Errorf("Invalid namespace %q", ns)
+		}
+	}
+
+	return userIDs, orgIDs, nil
+}
+
+// maxCampaignsPerPage is the maximum number of campaigns that one page of a
+// CampaignConnection or of the campaigns REST API lists.
+const maxCampaignsPerPage = 1000
+
+// listCampaignsOpts returns the options to list the campaigns of a
+// CampaignConnection with the given arguments.
+func listCampaignsOpts(args *graphqlbackend.ListCampaignArgs) (opts ee.ListCampaignsOpts, err error) {
+	if opts.State, err = parseCampaignState(args.State); err != nil {
+		return opts, err
+	}
+	opts.NamespaceUserIDs, opts.NamespaceOrgIDs, err = parseCampaignNamespaces(args.Namespaces)
+	if err != nil {
+		return opts, err
+	}
+	if args.Query != nil {
+		opts.Query = *args.Query
+	}
+	if args.HasOpenChangesets != nil {
+		opts.HasOpenChangesets = *args.HasOpenChangesets
+	}
+	if args.ChangesetState != nil {
+		opts.ChangesetState = a8n.ChangesetState(*args.ChangesetState)
+		if !opts.ChangesetState.Valid() {
+			return opts, fmt.Errorf("unknown changeset state %q", *args.ChangesetState)
+		}
+	}
+	switch args.OrderBy {
+	case "", "ID":
+	case "POSITION":
+		opts.OrderByPosition = true
+	default:
+		return opts, fmt.Errorf("unknown campaign order %q", args.OrderBy)
+	}
+	if args.First != nil {
+		if *args.First < 0 {
+			return opts, fmt.Errorf("invalid first %d", *args.First)
+		}
+		opts.Limit = int(*args.First)
+		if opts.Limit > maxCampaignsPerPage {
+			opts.Limit = maxCampaignsPerPage
+		}
+	}
+	if args.After != nil {
+		if opts.Cursor, err = strconv.ParseInt(*args.After, 10, 64); err != nil {
+			return opts, errors.Wrap(err, "parsing cursor")
+		}
+	}
+	return opts, nil
+}
+
 func parseCampaignState(s *string) (a8n.CampaignState, error) {
 	if s == nil {
 		return a8n.CampaignStateAny, nil
@@ -703,6 +703,33 @@ func TestCampaigns(t *testing.T) {
 	if haveCount != wantCount {
 		t.Errorf("wrong campaigns totalcount after delete. want=%d, have=%d", wantCount, haveCount)
 	}
+
+	var restored struct{ RestoreCampaign Campaign }
+	mustExec(ctx, t, s, deleteInput, &restored, `
+		mutation($id: ID!){
+			restoreCampaign(campaign: $id) { id }
+		}
+	`)
+
+	if have, want := restored.RestoreCampaign.ID, campaigns.Admin.ID; have != want {
+		t.Errorf("wrong restored campaign. want=%q, have=%q", want, have)
+	}
+
+	var campaignsAfterRestore struct {
+		Campaigns struct {
+			TotalCount int
+		}
+	}
+
+	mustExec(ctx, t, s, nil, &campaignsAfterRestore, `
+		query { campaigns { totalCount } }
+	`)
+
+	haveCount = campaignsAfterRestore.Campaigns.TotalCount
+	wantCount = listed.All.TotalCount
+	if haveCount != wantCount {
+		t.Errorf("wrong campaigns totalcount after restore. want=%d, have=%d", wantCount, haveCount)
+	}
 }
 
 func TestChangesetCountsOverTime(t *testing.T) {
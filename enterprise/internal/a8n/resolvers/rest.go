@@ -0,0 +1,551 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// NewCampaignsAPI returns the REST API for campaigns. It uses the same service
+// layer and performs the same access checks as the GraphQL resolvers, and
+// identifies campaigns, plans and namespaces by their GraphQL IDs, so that
+// clients can mix both APIs.
+func NewCampaignsAPI(store *ee.Store) *httpapi.CampaignsAPI {
+	api := &campaignsAPI{store: store}
+	return &httpapi.CampaignsAPI{
+		CampaignsHandler: http.HandlerFunc(api.serveCampaigns),
+		CampaignHandler:  http.HandlerFunc(api.serveCampaign),
+		FeedHandler:      http.HandlerFunc(api.serveFeed),
+	}
+}
+
+type campaignsAPI struct {
+	store *ee.Store
+}
+
+// restCampaign is the representation of a Campaign in the REST API.
+type restCampaign struct {
+	ID             graphql.ID      `json:"id"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	Branch         string          `json:"branch,omitempty"`
+	Namespace      graphql.ID      `json:"namespace"`
+	Author         graphql.ID      `json:"author"`
+	Plan           *graphql.ID     `json:"plan,omitempty"`
+	State          string          `json:"state"`
+	ChangesetCount int             `json:"changesetCount"`
+	URL            string          `json:"url"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	ClosedAt       *time.Time      `json:"closedAt,omitempty"`
+	Spec           json.RawMessage `json:"spec,omitempty"`
+
+	// Changesets is only set when listing campaigns with
+	// include=changesets.
+	Changesets []*restChangeset `json:"changesets,omitempty"`
+}
+
+// restChangeset is the representation of a Changeset of a campaign in the
+// REST API.
+type restChangeset struct {
+	ID          graphql.ID               `json:"id"`
+	ExternalID  string                   `json:"externalID"`
+	Repository  string                   `json:"repository"`
+	Title       string                   `json:"title"`
+	State       a8n.ChangesetState       `json:"state"`
+	ReviewState a8n.ChangesetReviewState `json:"reviewState"`
+	CheckState  *a8n.ChangesetCheckState `json:"checkState,omitempty"`
+	URL         string                   `json:"url"`
+	UpdatedAt   time.Time                `json:"updatedAt"`
+}
+
+func newRESTCampaign(c *a8n.Campaign) *restCampaign {
+	rc := &restCampaign{
+		ID:             marshalCampaignID(c.ID),
+		Name:           c.Name,
+		Description:    c.Description,
+		Branch:         c.Branch,
+		Author:         graphqlbackend.MarshalUserID(c.AuthorID),
+		State:          string(a8n.CampaignStateOpen),
+		ChangesetCount: len(c.ChangesetIDs),
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+	rc.URL = "/campaigns/" + string(rc.ID)
+
+	if c.Spec != nil {
+		if spec, err := ee.MigrateCampaignSpec(c.Spec); err == nil {
+			rc.Spec = spec
+		}
+	}
+
+	if c.NamespaceUserID != 0 {
+		rc.Namespace = graphqlbackend.MarshalUserID(c.NamespaceUserID)
+	} else {
+		rc.Namespace = relay.MarshalID("Org", c.NamespaceOrgID)
+	}
+	if c.CampaignPlanID != 0 {
+		plan := marshalCampaignPlanID(c.CampaignPlanID)
+		rc.Plan = &plan
+	}
+	if !c.ClosedAt.IsZero() {
+		closedAt := c.ClosedAt
+		rc.State = string(a8n.CampaignStateClosed)
+		rc.ClosedAt = &closedAt
+	}
+	return rc
+}
+
+func (api *campaignsAPI) serveCampaigns(w http.ResponseWriter, r *http.Request) {
+	var (
+		v   interface{}
+		err error
+	)
+	status := http.StatusOK
+	switch r.Method {
+	case "GET":
+		v, err = api.listCampaigns(r)
+	case "POST":
+		v, err = api.createCampaign(r)
+		status = http.StatusCreated
+	default:
+		err = &errcode.HTTPErr{Status: http.StatusMethodNotAllowed}
+	}
+	writeRESTResponse(w, status, v, err)
+}
+
+func (api *campaignsAPI) serveCampaign(w http.ResponseWriter, r *http.Request) {
+	id, err := unmarshalCampaignID(graphql.ID(mux.Vars(r)["id"]))
+	if err != nil || id == 0 {
+		writeRESTResponse(w, 0, nil, &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("invalid campaign ID")})
+		return
+	}
+
+	var v interface{}
+	switch r.Method {
+	case "GET":
+		v, err = api.getCampaign(r.Context(), id)
+	case "PATCH":
+		v, err = api.updateCampaign(r, id)
+	default:
+		err = &errcode.HTTPErr{Status: http.StatusMethodNotAllowed}
+	}
+	writeRESTResponse(w, http.StatusOK, v, err)
+}
+
+func (api *campaignsAPI) listCampaigns(r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	opts, withChangesets, err := parseRESTListCampaignsQuery(r.URL.Query())
+	if err != nil {
+		return nil, badRESTRequest(err)
+	}
+
+	campaigns, next, err := api.store.ListCampaigns(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Campaigns []*restCampaign `json:"campaigns"`
+		Next      string          `json:"next,omitempty"`
+	}{
+		Campaigns: make([]*restCampaign, 0, len(campaigns)),
+	}
+	for _, c := range campaigns {
+		resp.Campaigns = append(resp.Campaigns, newRESTCampaign(c))
+	}
+	if withChangesets {
+		if err := loadRESTChangesets(ctx, api.store, campaigns, resp.Campaigns); err != nil {
+			return nil, err
+		}
+	}
+	if next != 0 {
+		resp.Next = strconv.FormatInt(next, 10)
+	}
+	return resp, nil
+}
+
+// parseRESTListCampaignsQuery returns the options to list campaigns and
+// whether to include their changesets for the given query of a request to
+// list campaigns. Like in a CampaignConnection, at most maxCampaignsPerPage
+// campaigns are listed.
+func parseRESTListCampaignsQuery(query url.Values) (opts ee.ListCampaignsOpts, withChangesets bool, err error) {
+	opts.Limit = 50
+
+	var state *string
+	if s := query.Get("state"); s != "" {
+		state = &s
+	}
+	if opts.State, err = parseCampaignState(state); err != nil {
+		return opts, false, err
+	}
+
+	if ns, ok := query["namespace"]; ok {
+		namespaces := make([]graphql.ID, 0, len(ns))
+		for _, n := range ns {
+			namespaces = append(namespaces, graphql.ID(n))
+		}
+		if opts.NamespaceUserIDs, opts.NamespaceOrgIDs, err = parseCampaignNamespaces(&namespaces); err != nil {
+			return opts, false, err
+		}
+	}
+
+	if first := query.Get("first"); first != "" {
+		if opts.Limit, err = strconv.Atoi(first); err != nil || opts.Limit <= 0 {
+			return opts, false, errors.Errorf("invalid first %q", first)
+		}
+		if opts.Limit > maxCampaignsPerPage {
+			opts.Limit = maxCampaignsPerPage
+		}
+	}
+	if after := query.Get("after"); after != "" {
+		if opts.Cursor, err = strconv.ParseInt(after, 10, 64); err != nil {
+			return opts, false, errors.Errorf("invalid cursor %q", after)
+		}
+	}
+
+	switch include := query.Get("include"); include {
+	case "":
+	case "changesets":
+		withChangesets = true
+	default:
+		return opts, false, errors.Errorf("invalid include %q", include)
+	}
+
+	return opts, withChangesets, nil
+}
+
+// loadRESTChangesets sets the Changesets of the given restCampaigns, which
+// correspond to the given campaigns. The changesets, their events and their
+// repositories are each loaded with a single query for all campaigns, so that
+// clients like src can list campaigns with the states of their changesets in
+// one request.
+func loadRESTChangesets(ctx context.Context, s *ee.Store, campaigns []*a8n.Campaign, rcs []*restCampaign) error {
+	var ids []int64
+	for _, c := range campaigns {
+		ids = append(ids, c.ChangesetIDs...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cs, _, err := s.ListChangesets(ctx, ee.ListChangesetsOpts{IDs: ids, Limit: -1})
+	if err != nil {
+		return err
+	}
+
+	events, _, err := s.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{ChangesetIDs: ids, Limit: -1})
+	if err != nil {
+		return err
+	}
+	eventsByChangeset := make(map[int64]a8n.ChangesetEvents, len(cs))
+	for _, e := range events {
+		eventsByChangeset[e.ChangesetID] = append(eventsByChangeset[e.ChangesetID], e)
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(cs))
+	for _, c := range cs {
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+	// 🚨 SECURITY: db.Repos.List only returns the repositories the current
+	// user can access. The changesets in other repositories are left out.
+	rs, err := db.Repos.List(ctx, db.ReposListOptions{IDs: repoIDs})
+	if err != nil {
+		return err
+	}
+	repoNames := make(map[api.RepoID]api.RepoName, len(rs))
+	for _, r := range rs {
+		repoNames[r.ID] = r.Name
+	}
+
+	byID := make(map[int64]*restChangeset, len(cs))
+	for _, c := range cs {
+		repo, ok := repoNames[c.RepoID]
+		if !ok {
+			continue
+		}
+
+		rc, err := newRESTChangeset(c, repo, eventsByChangeset[c.ID])
+		if err != nil {
+			return errors.Wrapf(err, "changeset %d", c.ID)
+		}
+		byID[c.ID] = rc
+	}
+
+	for i, c := range campaigns {
+		rcs[i].Changesets = make([]*restChangeset, 0, len(c.ChangesetIDs))
+		for _, id := range c.ChangesetIDs {
+			if rc, ok := byID[id]; ok {
+				rcs[i].Changesets = append(rcs[i].Changesets, rc)
+			}
+		}
+	}
+	return nil
+}
+
+func newRESTChangeset(c *a8n.Changeset, repo api.RepoName, events a8n.ChangesetEvents) (*restChangeset, error) {
+	title, err := c.Title()
+	if err != nil {
+		return nil, err
+	}
+	state, err := c.State()
+	if err != nil {
+		return nil, err
+	}
+	url, err := c.URL()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(events)
+
+	rc := &restChangeset{
+		ID:         marshalChangesetID(c.ID),
+		ExternalID: c.ExternalID,
+		Repository: string(repo),
+		Title:      title,
+		State:      state,
+		URL:        url,
+		UpdatedAt:  c.UpdatedAt,
+	}
+
+	// GitHub review states are computed from the events, like in the
+	// GraphQL API.
+	if _, ok := c.Metadata.(*github.PullRequest); ok {
+		rc.ReviewState, err = events.ReviewState()
+	} else {
+		rc.ReviewState, err = c.ReviewState()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checkState := a8n.ComputeCheckState(c, events); checkState != a8n.ChangesetCheckStateUnknown {
+		rc.CheckState = &checkState
+	}
+
+	return rc, nil
+}
+
+func (api *campaignsAPI) getCampaign(ctx context.Context, id int64) (*restCampaign, error) {
+	// 🚨 SECURITY: Only site admins or users when read-access is enabled may access campaign.
+	if err := allowReadAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	campaign, err := api.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: id})
+	if err == ee.ErrNoResults {
+		return nil, &ee.ErrCampaignNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newRESTCampaign(campaign), nil
+}
+
+func (api *campaignsAPI) createCampaign(r *http.Request) (_ *restCampaign, err error) {
+	tr, ctx := trace.New(r.Context(), "campaignsAPI.createCampaign", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may create a campaign for now.
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+
+	var input struct {
+		Name           string           `json:"name"`
+		Description    string           `json:"description"`
+		Branch         string           `json:"branch"`
+		Namespace      graphql.ID       `json:"namespace"`
+		Plan           *graphql.ID      `json:"plan"`
+		Draft          bool             `json:"draft"`
+		OverrideQuotas bool             `json:"overrideQuotas"`
+		Spec           *json.RawMessage `json:"spec"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, badRESTRequest(errors.Wrap(err, "decoding campaign"))
+	}
+
+	campaign := &a8n.Campaign{
+		Name:        input.Name,
+		Description: input.Description,
+		Branch:      input.Branch,
+		AuthorID:    user.ID,
+	}
+	if input.Spec != nil {
+		campaign.Spec = *input.Spec
+	}
+
+	userIDs, orgIDs, err := parseCampaignNamespaces(&[]graphql.ID{input.Namespace})
+	if err != nil {
+		return nil, badRESTRequest(err)
+	}
+	if len(userIDs) > 0 {
+		campaign.NamespaceUserID = userIDs[0]
+	} else {
+		campaign.NamespaceOrgID = orgIDs[0]
+	}
+
+	if input.Plan != nil {
+		if campaign.CampaignPlanID, err = unmarshalCampaignPlanID(*input.Plan); err != nil {
+			return nil, badRESTRequest(err)
+		}
+	}
+
+	svc := ee.NewService(api.store, gitserver.DefaultClient, nil, nil)
+	svc.OverrideQuotas(input.OverrideQuotas)
+	if err := svc.CreateCampaign(ctx, campaign, input.Draft); err != nil {
+		return nil, err
+	}
+
+	return newRESTCampaign(campaign), nil
+}
+
+func (api *campaignsAPI) updateCampaign(r *http.Request, id int64) (_ *restCampaign, err error) {
+	tr, ctx := trace.New(r.Context(), "campaignsAPI.updateCampaign", fmt.Sprintf("Campaign: %d", id))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site admins may update campaigns for now
+	if err := backend.CheckCurrentUserIsSiteAdminWithScope(ctx, authz.ScopeCampaignsWrite); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	var input struct {
+		Name        *string          `json:"name"`
+		Description *string          `json:"description"`
+		Branch      *string          `json:"branch"`
+		Plan        *graphql.ID      `json:"plan"`
+		Spec        *json.RawMessage `json:"spec"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, badRESTRequest(errors.Wrap(err, "decoding campaign"))
+	}
+
+	updateArgs := ee.UpdateCampaignArgs{
+		Campaign:    id,
+		Name:        input.Name,
+		Description: input.Description,
+		Branch:      input.Branch,
+	}
+	if input.Spec != nil {
+		spec := string(*input.Spec)
+		updateArgs.Spec = &spec
+	}
+	if input.Plan != nil {
+		campaignPlanID, err := unmarshalCampaignPlanID(*input.Plan)
+		if err != nil {
+			return nil, badRESTRequest(err)
+		}
+		updateArgs.Plan = &campaignPlanID
+	}
+
+	svc := ee.NewService(api.store, gitserver.DefaultClient, nil, nil)
+	campaign, detachedChangesets, err := svc.UpdateCampaign(ctx, updateArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if detachedChangesets != nil {
+		go func() {
+			ctx := trace.ContextWithTrace(context.Background(), tr)
+			err := svc.CloseOpenChangesets(ctx, detachedChangesets)
+			if err != nil {
+				log15.Error("CloseOpenChangesets", "err", err)
+			}
+		}()
+	}
+
+	return newRESTCampaign(campaign), nil
+}
+
+func badRESTRequest(err error) error {
+	return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+}
+
+// writeRESTResponse writes v as JSON with the given status code or, if err is
+// non-nil, an error object with the status code that best describes err.
+// Messages of internal errors are not exposed.
+func writeRESTResponse(w http.ResponseWriter, status int, v interface{}, err error) {
+	if err != nil {
+		status = restErrorStatus(err)
+		message := http.StatusText(status)
+		if status < 500 {
+			if e, ok := err.(*errcode.HTTPErr); !ok {
+				message = err.Error()
+			} else if e.Err != nil {
+				message = e.Err.Error()
+			}
+		} else {
+			log15.Error("campaigns REST API", "error", err)
+		}
+		v = struct {
+			Error string `json:"error"`
+		}{Error: message}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func restErrorStatus(err error) int {
+	switch errors.Cause(err) {
+	case backend.ErrNotAuthenticated:
+		return http.StatusUnauthorized
+	case backend.ErrMustBeSiteAdmin:
+		return http.StatusForbidden
+	case ee.ErrCampaignNameBlank,
+		ee.ErrCampaignBranchBlank,
+		ee.ErrPublishedCampaignBranchChange,
+		ee.ErrUpdateProcessingCampaign:
+		return http.StatusBadRequest
+	}
+	if _, ok := errors.Cause(err).(*backend.InsufficientAuthorizationError); ok {
+		return http.StatusForbidden
+	}
+	return errcode.HTTP(err)
+}
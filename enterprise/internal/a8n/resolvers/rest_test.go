@@ -0,0 +1,115 @@
+package resolvers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func TestParseRESTListCampaignsQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query              string
+		wantLimit          int
+		wantCursor         int64
+		wantWithChangesets bool
+		wantErr            bool
+	}{
+		{query: "", wantLimit: 50},
+		{query: "first=10&after=20", wantLimit: 10, wantCursor: 20},
+		{query: "first=1000", wantLimit: maxCampaignsPerPage},
+		{query: "first=1000000", wantLimit: maxCampaignsPerPage},
+		{query: "include=changesets", wantLimit: 50, wantWithChangesets: true},
+		{query: "first=0", wantErr: true},
+		{query: "first=-1", wantErr: true},
+		{query: "first=ten", wantErr: true},
+		{query: "after=x", wantErr: true},
+		{query: "include=events", wantErr: true},
+		{query: "state=PENDING", wantErr: true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts, withChangesets, err := parseRESTListCampaignsQuery(values)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("have no error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if opts.Limit != tc.wantLimit {
+				t.Errorf("have limit %d, want %d", opts.Limit, tc.wantLimit)
+			}
+			if opts.Cursor != tc.wantCursor {
+				t.Errorf("have cursor %d, want %d", opts.Cursor, tc.wantCursor)
+			}
+			if withChangesets != tc.wantWithChangesets {
+				t.Errorf("have withChangesets %v, want %v", withChangesets, tc.wantWithChangesets)
+			}
+		})
+	}
+}
+
+func TestListCampaignsOptsLimit(t *testing.T) {
+	for _, tc := range []struct {
+		first     *int32
+		wantLimit int
+		wantErr   bool
+	}{
+		{first: nil, wantLimit: 0},
+		{first: int32Ptr(0), wantLimit: 0},
+		{first: int32Ptr(20), wantLimit: 20},
+		{first: int32Ptr(maxCampaignsPerPage + 1), wantLimit: maxCampaignsPerPage},
+		{first: int32Ptr(-1), wantErr: true},
+	} {
+		opts, err := listCampaignsOpts(&graphqlbackend.ListCampaignArgs{First: tc.first})
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("first %d: have no error, want error", *tc.first)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.Limit != tc.wantLimit {
+			t.Errorf("have limit %d, want %d", opts.Limit, tc.wantLimit)
+		}
+	}
+}
+
+func TestListCampaignsOptsOrderBy(t *testing.T) {
+	for _, tc := range []struct {
+		orderBy      string
+		wantPosition bool
+		wantErr      bool
+	}{
+		{orderBy: "", wantPosition: false},
+		{orderBy: "ID", wantPosition: false},
+		{orderBy: "POSITION", wantPosition: true},
+		{orderBy: "NAME", wantErr: true},
+	} {
+		opts, err := listCampaignsOpts(&graphqlbackend.ListCampaignArgs{OrderBy: tc.orderBy})
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("order %q: have no error, want error", tc.orderBy)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.OrderByPosition != tc.wantPosition {
+			t.Errorf("order %q: have ordering by position %v, want %v", tc.orderBy, opts.OrderByPosition, tc.wantPosition)
+		}
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
@@ -0,0 +1,141 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func init() {
+	// 🚨 SECURITY: Access tokens with the campaigns:write scope may only be used to manage
+	// campaigns. The resolvers of the root fields check the scope.
+	graphqlbackend.RegisterScopedFields(authz.ScopeCampaignsWrite, campaignsWriteFields...)
+}
+
+// campaignsWriteFields are the GraphQL fields that actors restricted to the campaigns:write
+// access token scope may resolve, see graphqlbackend.RegisterScopedFields.
+var campaignsWriteFields = []string{
+	// Queries
+	"Query.node",
+	"Query.campaigns",
+	"Query.campaignFacets",
+	"Query.campaignRepositoryActivity",
+	"Query.campaignByName",
+	"Query.campaignSavedFilters",
+	"Query.campaignNamespaceSettings",
+	"Query.campaignNotificationSettings",
+	"Query.renderChangesetTemplate",
+
+	// Mutations
+	"Mutation.createChangesets",
+	"Mutation.addChangesetsToCampaign",
+	"Mutation.importChangesets",
+	"Mutation.createCampaign",
+	"Mutation.createCampaignPlanFromPatches",
+	"Mutation.createCampaignPlanFromSpec",
+	"Mutation.previewCampaign",
+	"Mutation.updateCampaign",
+	"Mutation.retryCampaign",
+	"Mutation.deleteCampaign",
+	"Mutation.restoreCampaign",
+	"Mutation.rollbackCampaign",
+	"Mutation.setCampaignParent",
+	"Mutation.moveCampaign",
+	"Mutation.closeCampaign",
+	"Mutation.updateCampaigns",
+	"Mutation.publishCampaign",
+	"Mutation.publishChangeset",
+	"Mutation.syncChangeset",
+	"Mutation.saveCampaignSavedFilter",
+	"Mutation.deleteCampaignSavedFilter",
+	"Mutation.setDefaultCampaignSavedFilter",
+	"Mutation.updateCampaignNamespaceSettings",
+	"Mutation.updateCampaignNotificationSettings",
+	"Mutation.setCampaignSubscription",
+	"Mutation.markCampaignViewed",
+	"Mutation.redeliverWebhook",
+
+	// The types of campaigns, whose fields are all allowed. These are also the types of the
+	// nodes that may be looked up with Query.node.
+	"Campaign",
+	"CampaignPlan",
+	"ExternalChangeset",
+	"ChangesetPlan",
+	"ChangesetEvent",
+
+	"BackgroundProcessStatus",
+	"CampaignActivityConnection",
+	"CampaignAuthorFacet",
+	"CampaignByNameResult",
+	"CampaignConnection",
+	"CampaignCreatedEvent",
+	"CampaignFacets",
+	"CampaignNamespaceSettings",
+	"CampaignNotificationSettings",
+	"CampaignProgress",
+	"CampaignRepositoryActivity",
+	"CampaignSavedFilter",
+	"CampaignStateFacet",
+	"ChangesetAddedEvent",
+	"ChangesetCheckStateCounts",
+	"ChangesetCommentedEvent",
+	"ChangesetCounts",
+	"ChangesetEventConnection",
+	"ChangesetLabel",
+	"ChangesetMergedEvent",
+	"ChangesetPlanConnection",
+	"ChangesetRebase",
+	"ChangesetReviewerRequest",
+	"EmptyResponse",
+	"ExecutionLog",
+	"ExternalChangesetConnection",
+	"ExternalLink",
+	"HighlightedDiffHunk",
+	"HighlightedDiffHunkConnection",
+	"HighlightedDiffHunkLine",
+	"PageInfo",
+	"RenderedChangesetTemplate",
+	"UpdateCampaignsResult",
+
+	// The diffs of changesets, without the files, commits and other contents of the
+	// repositories.
+	"DiffStat",
+	"FileDiffConnection",
+	"FileDiffHunk",
+	"FileDiffHunkRange",
+	"PreviewFileDiffConnection",
+	"RepositoryComparisonConnection",
+	"FileDiff.oldPath",
+	"FileDiff.newPath",
+	"FileDiff.hunks",
+	"FileDiff.stat",
+	"FileDiff.internalID",
+	"PreviewFileDiff.oldPath",
+	"PreviewFileDiff.newPath",
+	"PreviewFileDiff.hunks",
+	"PreviewFileDiff.stat",
+	"PreviewFileDiff.internalID",
+	"PreviewRepositoryComparison.baseRepository",
+	"PreviewRepositoryComparison.fileDiffs",
+	"RepositoryComparison.baseRepository",
+	"RepositoryComparison.headRepository",
+	"RepositoryComparison.fileDiffs",
+
+	// The names of the repositories, branches and users that campaigns refer to.
+	"Node.id",
+	"Namespace",
+	"Repository.id",
+	"Repository.name",
+	"Repository.url",
+	"GitRef.name",
+	"GitRef.abbrevName",
+	"GitRef.displayName",
+	"User.id",
+	"User.username",
+	"User.displayName",
+	"User.url",
+	"User.avatarURL",
+	"Org.id",
+	"Org.name",
+	"Org.displayName",
+	"Org.url",
+}
@@ -0,0 +1,149 @@
+package a8n
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// enqueueChangesetReviewers enqueues the given ChangesetJob, whose changeset
+// has just been created, in ChangesetReviewersQueue if the spec of its
+// campaign lists reviewers that haven't been requested yet.
+func enqueueChangesetReviewers(ctx context.Context, s *Store, c *a8n.Campaign, job *a8n.ChangesetJob) error {
+	if !job.ReviewersRequestedAt.IsZero() {
+		return nil
+	}
+
+	reviewers, err := CampaignSpecReviewers(c.Spec)
+	if err != nil {
+		return errors.Wrap(err, "getting reviewers of campaign spec")
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(changesetJobPayload{ChangesetJobID: job.ID})
+	if err != nil {
+		return err
+	}
+
+	return s.EnqueueWorkerJob(ctx, &a8n.WorkerJob{
+		Queue:   ChangesetReviewersQueue,
+		Payload: payload,
+	})
+}
+
+// RunChangesetReviewersJobs should run in a background goroutine and is
+// responsible for requesting the reviewers listed in the campaign specs on
+// the changesets of the ChangesetJobs in ChangesetReviewersQueue.
+// ctx should be canceled to terminate the function
+func RunChangesetReviewersJobs(ctx context.Context, s *Store, clock func() time.Time, cf *httpcli.Factory, backoffDuration time.Duration) {
+	process := func(ctx context.Context, s *Store, job *a8n.WorkerJob) error {
+		var p changesetJobPayload
+		if err := json.Unmarshal(job.Payload, &p); err != nil {
+			return errors.Wrap(err, "parsing payload")
+		}
+		return requestChangesetReviewers(ctx, s, clock, cf, p.ChangesetJobID)
+	}
+
+	w := &Worker{
+		Store:   s,
+		Queue:   ChangesetReviewersQueue,
+		Handler: process,
+		Backoff: backoffDuration,
+	}
+	w.Start(ctx)
+}
+
+// requestChangesetReviewers requests the reviewers listed in the campaign
+// spec on the changeset created by the ChangesetJob with the given ID and
+// records the result in the ChangesetJob. A failed request is returned, so
+// that the Worker retries it.
+func requestChangesetReviewers(ctx context.Context, s *Store, clock func() time.Time, cf *httpcli.Factory, id int64) error {
+	job, err := s.GetChangesetJob(ctx, GetChangesetJobOpts{ID: id})
+	if err == ErrNoResults {
+		// The job was deleted with its campaign in the meantime.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting changeset job")
+	}
+
+	if !job.ReviewersRequestedAt.IsZero() || job.ChangesetID == 0 {
+		return nil
+	}
+
+	c, err := s.GetCampaign(ctx, GetCampaignOpts{ID: job.CampaignID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign")
+	}
+
+	reviewers, err := CampaignSpecReviewers(c.Spec)
+	if err != nil {
+		return errors.Wrap(err, "getting reviewers of campaign spec")
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	changeset, err := s.GetChangeset(ctx, GetChangesetOpts{ID: job.ChangesetID})
+	if err == ErrNoResults {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting changeset")
+	}
+
+	syncer := ChangesetSyncer{
+		Store:       s,
+		ReposStore:  repos.NewDBStore(s.DB(), sql.TxOptions{}),
+		HTTPFactory: cf,
+	}
+
+	bySource, err := syncer.GroupChangesetsBySource(ctx, changeset)
+	if err == nil {
+		err = requestReviewers(ctx, bySource, reviewers)
+	}
+
+	if err != nil {
+		job.ReviewersError = err.Error()
+	} else {
+		job.ReviewersError = ""
+		job.ReviewersRequestedAt = clock()
+	}
+
+	if e := s.UpdateChangesetJob(ctx, job); e != nil {
+		return errors.Wrap(e, "updating changeset job")
+	}
+
+	return err
+}
+
+// requestReviewers requests the given reviewers on all the changesets of the
+// given SourceChangesets.
+func requestReviewers(ctx context.Context, bySource []*SourceChangesets, reviewers []string) error {
+	var requested bool
+	for _, s := range bySource {
+		for _, c := range s.Changesets {
+			// Requesting a reviewer takes at most one API request.
+			if err := borrowRateLimit(ctx, s.ExternalServiceID, "changeset_reviewers", len(reviewers)); err != nil {
+				return err
+			}
+			if err := s.RequestReviewers(ctx, c, reviewers); err != nil {
+				return errors.Wrap(err, "requesting reviewers")
+			}
+			requested = true
+		}
+	}
+
+	if !requested {
+		return errors.New("no code host connection found for the changeset")
+	}
+	return nil
+}
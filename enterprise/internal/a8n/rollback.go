@@ -0,0 +1,386 @@
+package a8n
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// ErrNoMergedChangesets is returned by RollbackCampaign if none of the
+// changesets of the Campaign that were created from its CampaignPlan has been
+// merged.
+var ErrNoMergedChangesets = errors.New("cannot roll back a Campaign without merged changesets")
+
+// RollbackCampaign creates a rollback campaign that reverts the merged
+// changesets of the Campaign with the given ID. The rollback campaign belongs
+// to the same namespace and is published right away: for each merged
+// changeset, it creates a changeset with the reverse of the diff that the
+// changeset was created from, committed by gitserver on top of the current
+// head of the changeset's base branch.
+//
+// Changesets that weren't created from the CampaignPlan of the Campaign are
+// not reverted, since their diff is unknown.
+//
+// An *ErrCampaignNotFound is returned if the Campaign doesn't exist, an
+// *ErrCampaignRolledBack if it was rolled back already and an
+// *ErrCampaignNameConflict or *ErrQuotaExceeded if the rollback campaign
+// can't be created.
+func (s *Service) RollbackCampaign(ctx context.Context, id int64, authorID int32) (rollback *a8n.Campaign, err error) {
+	tr, ctx := trace.New(ctx, "service.RollbackCampaign", fmt.Sprintf("campaign: %d", id))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if authorID == 0 {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	campaign, err := getCampaign(ctx, s.store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.rollbackCampaignJobs(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrNoMergedChangesets
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	existing, err := tx.GetCampaign(ctx, GetCampaignOpts{RollbackOfCampaignID: id})
+	if err != nil && err != ErrNoResults {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, &ErrCampaignRolledBack{ID: id, RollbackID: existing.ID}
+	}
+
+	plan := &a8n.CampaignPlan{CampaignType: campaignTypePatch, UserID: authorID}
+	if err := tx.CreateCampaignPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		job.CampaignPlanID = plan.ID
+		if err := tx.CreateCampaignJob(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	rollback = &a8n.Campaign{
+		Name:                 "Rollback of " + campaign.Name,
+		Description:          fmt.Sprintf("Reverts the merged changesets of the campaign %q.", campaign.Name),
+		Branch:               campaign.Branch + "-rollback",
+		AuthorID:             authorID,
+		NamespaceUserID:      campaign.NamespaceUserID,
+		NamespaceOrgID:       campaign.NamespaceOrgID,
+		CampaignPlanID:       plan.ID,
+		RollbackOfCampaignID: campaign.ID,
+	}
+
+	if err := checkCampaignName(ctx, tx, rollback); err != nil {
+		return nil, err
+	}
+	if err := s.checkOpenCampaignsQuota(ctx, tx, rollback); err != nil {
+		return nil, err
+	}
+
+	rollback.CreatedAt = s.clock()
+	rollback.UpdatedAt = rollback.CreatedAt
+	if err := tx.CreateCampaign(ctx, rollback); err != nil {
+		return nil, err
+	}
+
+	return rollback, s.createChangesetJobsWithStore(ctx, tx, rollback)
+}
+
+// rollbackCampaignJobs returns unsaved CampaignJobs reverting the diffs of
+// the merged changesets of the given Campaign.
+func (s *Service) rollbackCampaignJobs(ctx context.Context, c *a8n.Campaign) ([]*a8n.CampaignJob, error) {
+	if c.CampaignPlanID == 0 {
+		return nil, nil
+	}
+
+	cs, _, err := s.store.ListChangesets(ctx, ListChangesetsOpts{CampaignID: c.ID, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[int64]bool, len(cs))
+	for _, ch := range cs {
+		if state, err := ch.State(); err == nil && state == a8n.ChangesetStateMerged {
+			merged[ch.ID] = true
+		}
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	changesetJobs, _, err := s.store.ListChangesetJobs(ctx, ListChangesetJobsOpts{CampaignID: c.ID, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	campaignJobs, _, err := s.store.ListCampaignJobs(ctx, ListCampaignJobsOpts{CampaignPlanID: c.CampaignPlanID, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+	campaignJobsByID := make(map[int64]*a8n.CampaignJob, len(campaignJobs))
+	for _, j := range campaignJobs {
+		campaignJobsByID[j.ID] = j
+	}
+
+	var toRevert []*a8n.CampaignJob
+	for _, j := range changesetJobs {
+		if campaignJob, ok := campaignJobsByID[j.CampaignJobID]; ok && merged[j.ChangesetID] {
+			toRevert = append(toRevert, campaignJob)
+		}
+	}
+	if len(toRevert) == 0 {
+		return nil, nil
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(toRevert))
+	for _, j := range toRevert {
+		repoIDs = append(repoIDs, j.RepoID)
+	}
+	reposStore := repos.NewDBStore(s.store.DB(), sql.TxOptions{})
+	rs, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+	reposByID := make(map[api.RepoID]*repos.Repo, len(rs))
+	for _, r := range rs {
+		reposByID[r.ID] = r
+	}
+
+	jobs := make([]*a8n.CampaignJob, 0, len(toRevert))
+	for _, j := range toRevert {
+		repo := reposByID[j.RepoID]
+		if repo == nil {
+			return nil, fmt.Errorf("repository ID %d not found", j.RepoID)
+		}
+
+		// The revert is applied on top of the current head of the base
+		// branch, which contains the merged diff.
+		commit, err := s.repoResolveRevision(ctx, repo, j.BaseRef)
+		if err != nil {
+			return nil, errors.Wrapf(err, "repository %q", repo.Name)
+		}
+
+		diff, err := reverseDiff(j.Diff)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reverting diff of repository %q", repo.Name)
+		}
+
+		jobs = append(jobs, &a8n.CampaignJob{
+			RepoID:      j.RepoID,
+			BaseRef:     j.BaseRef,
+			Rev:         commit,
+			Diff:        diff,
+			Description: j.Description,
+			StartedAt:   s.clock(),
+			FinishedAt:  s.clock(),
+		})
+	}
+
+	return jobs, nil
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// reverseDiff returns the unified diff that undoes the given one, so that
+// applying it after the given diff restores the original files. Diffs of
+// binary files and copies can't be reversed.
+func reverseDiff(d string) (string, error) {
+	var out strings.Builder
+	lines := strings.SplitAfter(d, "\n")
+
+	// origLeft and newLeft are the numbers of lines of the original and new
+	// file that are left in the current hunk.
+	var origLeft, newLeft int
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		text := strings.TrimSuffix(line, "\n")
+		nl := line[len(text):]
+
+		if origLeft > 0 || newLeft > 0 {
+			switch {
+			case text == "" || text[0] == ' ':
+				origLeft--
+				newLeft--
+				out.WriteString(line)
+			case text[0] == '-':
+				origLeft--
+				out.WriteString("+" + line[1:])
+			case text[0] == '+':
+				newLeft--
+				out.WriteString("-" + line[1:])
+			case text[0] == '\\':
+				out.WriteString(line)
+			default:
+				return "", errors.Errorf("unexpected line in hunk: %q", text)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "--- "):
+			if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+				return "", errors.Errorf("missing new file header after %q", text)
+			}
+			next := strings.TrimSuffix(lines[i+1], "\n")
+			out.WriteString("--- " + next[4:] + nl)
+			out.WriteString("+++ " + text[4:] + lines[i+1][len(next):])
+			i++
+
+		case strings.HasPrefix(text, "@@ "):
+			m := hunkHeader.FindStringSubmatch(text)
+			if m == nil {
+				return "", errors.Errorf("malformed hunk header %q", text)
+			}
+			origCount, newCount := hunkLength(m[2]), hunkLength(m[4])
+			out.WriteString(fmt.Sprintf("@@ -%s,%d +%s,%d @@%s%s", m[3], newCount, m[1], origCount, m[5], nl))
+			origLeft, newLeft = origCount, newCount
+
+		case strings.HasPrefix(text, "diff --git "):
+			header, n, err := reverseGitHeader(lines[i:])
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(header)
+			i += n - 1
+
+		case strings.HasPrefix(text, "Binary files ") || text == "GIT binary patch":
+			return "", errors.New("diffs of binary files can't be reversed")
+
+		default:
+			out.WriteString(line)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// hunkLength parses the line count of a hunk header, which defaults to 1.
+func hunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// reverseGitHeader reverses the extended header of a git diff, which starts
+// with the first of the given lines and ends before the file headers or the
+// first hunk. It returns the reversed header and the number of lines it
+// spans.
+func reverseGitHeader(lines []string) (string, int, error) {
+	n := 1
+	for n < len(lines) {
+		text := lines[n]
+		if strings.HasPrefix(text, "--- ") || strings.HasPrefix(text, "@@ ") || strings.HasPrefix(text, "diff --git ") {
+			break
+		}
+		n++
+	}
+
+	header := make([]string, n)
+	copy(header, lines[:n])
+
+	// Swapped pairs keep their position, but exchange their values.
+	swap := func(fromPrefix, toPrefix string) {
+		from, to := -1, -1
+		for i, l := range header {
+			switch {
+			case strings.HasPrefix(l, fromPrefix):
+				from = i
+			case strings.HasPrefix(l, toPrefix):
+				to = i
+			}
+		}
+		if from >= 0 && to >= 0 {
+			header[from], header[to] = fromPrefix+header[to][len(toPrefix):], toPrefix+header[from][len(fromPrefix):]
+		}
+	}
+
+	var renameFrom, renameTo string
+	for i, l := range header {
+		text := strings.TrimSuffix(l, "\n")
+		switch {
+		case strings.HasPrefix(text, "copy from ") || strings.HasPrefix(text, "copy to "):
+			return "", 0, errors.New("diffs of copied files can't be reversed")
+		case strings.HasPrefix(text, "Binary files ") || text == "GIT binary patch":
+			return "", 0, errors.New("diffs of binary files can't be reversed")
+		case strings.HasPrefix(text, "new file mode "):
+			header[i] = "deleted file mode " + l[len("new file mode "):]
+		case strings.HasPrefix(text, "deleted file mode "):
+			header[i] = "new file mode " + l[len("deleted file mode "):]
+		case strings.HasPrefix(text, "rename from "):
+			renameFrom = text[len("rename from "):]
+		case strings.HasPrefix(text, "rename to "):
+			renameTo = text[len("rename to "):]
+		case strings.HasPrefix(text, "index "):
+			fields := strings.SplitN(text[len("index "):], " ", 2)
+			if revs := strings.SplitN(fields[0], "..", 2); len(revs) == 2 {
+				fields[0] = revs[1] + ".." + revs[0]
+				header[i] = "index " + strings.Join(fields, " ") + l[len(text):]
+			}
+		}
+	}
+	swap("old mode ", "new mode ")
+	swap("rename from ", "rename to ")
+
+	if renameFrom != "" && renameTo != "" {
+		first := strings.TrimSuffix(header[0], "\n")
+		if h, ok := reverseGitRenameLine(first, renameFrom, renameTo); ok {
+			header[0] = h + header[0][len(first):]
+		}
+	}
+
+	return strings.Join(header, ""), n, nil
+}
+
+// reverseGitRenameLine swaps the paths in the "diff --git" line of a renamed
+// file, which may or may not have the a/ and b/ prefixes.
+func reverseGitRenameLine(line, from, to string) (string, bool) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if !strings.HasSuffix(rest, to) {
+		return "", false
+	}
+	rest = strings.TrimSuffix(rest, to)
+
+	var newPrefix string
+	switch {
+	case strings.HasSuffix(rest, " b/"):
+		newPrefix, rest = "b/", strings.TrimSuffix(rest, " b/")
+	case strings.HasSuffix(rest, " "):
+		rest = strings.TrimSuffix(rest, " ")
+	default:
+		return "", false
+	}
+
+	if !strings.HasSuffix(rest, from) {
+		return "", false
+	}
+	origPrefix := strings.TrimSuffix(rest, from)
+
+	return "diff --git " + origPrefix + to + " " + newPrefix + from, true
+}
@@ -0,0 +1,141 @@
+package a8n
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReverseDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "modified file",
+			diff: `diff --git README.md README.md
+index 671e50a..851b23a 100644
+--- README.md
++++ README.md
+@@ -1,3 +1,4 @@
+ # README
+-foo
++bar
++baz
+ --- a line that looks like a header
+`,
+			want: `diff --git README.md README.md
+index 851b23a..671e50a 100644
+--- README.md
++++ README.md
+@@ -1,4 +1,3 @@
+ # README
++foo
+-bar
+-baz
+ --- a line that looks like a header
+`,
+		},
+		{
+			name: "removed line that looks like a header",
+			diff: `--- file.txt
++++ file.txt
+@@ -1,2 +1 @@
+ a
+--- b
+`,
+			want: `--- file.txt
++++ file.txt
+@@ -1,1 +1,2 @@
+ a
++-- b
+`,
+		},
+		{
+			name: "new file without trailing newline",
+			diff: `diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..3b18e51
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+\ No newline at end of file
+`,
+			want: `diff --git a/new.txt b/new.txt
+deleted file mode 100644
+index 3b18e51..0000000
+--- b/new.txt
++++ /dev/null
+@@ -1,1 +0,0 @@
+-hello
+\ No newline at end of file
+`,
+		},
+		{
+			name: "renamed file",
+			diff: `diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+old mode 100644
+new mode 100755
+--- a/old.go
++++ b/new.go
+@@ -1 +1 @@
+-package old
++package new
+`,
+			want: `diff --git a/new.go b/old.go
+similarity index 90%
+rename from new.go
+rename to old.go
+old mode 100755
+new mode 100644
+--- b/new.go
++++ a/old.go
+@@ -1,1 +1,1 @@
++package old
+-package new
+`,
+		},
+		{
+			name: "binary file",
+			diff: `diff --git a/img.png b/img.png
+index 0000000..3b18e51
+Binary files a/img.png and b/img.png differ
+`,
+			wantErr: true,
+		},
+		{
+			name: "malformed hunk",
+			diff: `--- file.txt
++++ file.txt
+@@ -1,2 +1,2 @@
+ a
+*b
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := reverseDiff(tc.diff)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("unexpected diff (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ package a8n
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -17,7 +20,7 @@ var maxWorkers = env.Get("A8N_MAX_WORKERS", "8", "maximum number of repository j
 const defaultWorkerCount = 8
 
 // RunChangesetJobs should run in a background goroutine and is responsible
-// for finding pending jobs and running them.
+// for running the ChangesetJobs in ChangesetJobsQueue.
 // ctx should be canceled to terminate the function
 func RunChangesetJobs(ctx context.Context, s *Store, clock func() time.Time, gitClient GitserverClient, backoffDuration time.Duration) {
 	workerCount, err := strconv.Atoi(maxWorkers)
@@ -25,27 +28,105 @@ func RunChangesetJobs(ctx context.Context, s *Store, clock func() time.Time, git
 		log15.Error("Parsing max worker count failed. Falling back to default.", "default", defaultWorkerCount, "err", err)
 		workerCount = defaultWorkerCount
 	}
-	process := func(ctx context.Context, s *Store, job a8n.ChangesetJob) error {
-		c, err := s.GetCampaign(ctx, GetCampaignOpts{
-			ID: job.CampaignID,
-		})
-		if err != nil {
-			return errors.Wrap(err, "getting campaign")
+	process := func(ctx context.Context, s *Store, job *a8n.WorkerJob) error {
+		var p changesetJobPayload
+		if err := json.Unmarshal(job.Payload, &p); err != nil {
+			return errors.Wrap(err, "parsing payload")
 		}
-		_ = RunChangesetJob(ctx, clock, s, gitClient, nil, c, &job)
-		// We ignore the error here so that we don't roll back the transaction
-		// RunChangesetJob will save the error in the job row
+		return runQueuedChangesetJob(ctx, s, clock, gitClient, p.ChangesetJobID)
+	}
+	w := &Worker{
+		Store:       s,
+		Queue:       ChangesetJobsQueue,
+		Handler:     process,
+		Concurrency: workerCount,
+		Backoff:     backoffDuration,
+	}
+	w.Start(ctx)
+}
+
+// runQueuedChangesetJob runs the ChangesetJob with the given ID, unless its
+// campaign plan has been canceled or it has already been run successfully.
+func runQueuedChangesetJob(ctx context.Context, s *Store, clock func() time.Time, gitClient GitserverClient, id int64) error {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return errors.Wrap(err, "starting transaction")
+	}
+	// RunChangesetJob saves its error in the job row, so we always commit.
+	defer tx.Done()
+
+	// Resetting a ChangesetJob enqueues it again, even if it's still
+	// running, so we make sure that it's not run twice at the same time.
+	locked, err := tx.TryAcquireAdvisoryLock(ctx, fmt.Sprintf("changeset_job:%d", id))
+	if err != nil {
+		return errors.Wrap(err, "acquiring lock")
+	}
+	if !locked {
+		return errors.Errorf("changeset job %d is already running", id)
+	}
+
+	job, err := tx.GetChangesetJob(ctx, GetChangesetJobOpts{ID: id})
+	if err == ErrNoResults {
+		// The job was deleted with its campaign in the meantime.
 		return nil
 	}
+	if err != nil {
+		return errors.Wrap(err, "getting changeset job")
+	}
+
+	c, err := tx.GetCampaign(ctx, GetCampaignOpts{ID: job.CampaignID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign")
+	}
+
+	plan, err := tx.GetCampaignPlan(ctx, GetCampaignPlanOpts{ID: c.CampaignPlanID})
+	if err != nil {
+		return errors.Wrap(err, "getting campaign plan")
+	}
+	if !plan.CanceledAt.IsZero() {
+		return nil
+	}
+
+	if !job.SuccessfullyCompleted() {
+		// Clear the error of a previous attempt, so that the job is
+		// considered successful if this one succeeds.
+		job.Error = ""
+		job.FinishedAt = time.Time{}
+	}
+
+	return RunChangesetJob(ctx, clock, tx, gitClient, nil, c, job)
+}
+
+// RunChangesetCloseJobs should run in a background goroutine and is
+// responsible for finding pending changeset close jobs and closing the
+// changesets on their codehosts.
+// ctx should be canceled to terminate the function
+func RunChangesetCloseJobs(ctx context.Context, s *Store, clock func() time.Time, cf *httpcli.Factory, backoffDuration time.Duration) {
+	process := func(ctx context.Context, s *Store, job a8n.ChangesetCloseJob) error {
+		c, err := s.GetChangeset(ctx, GetChangesetOpts{ID: job.ChangesetID})
+		if err != nil {
+			return errors.Wrap(err, "getting changeset")
+		}
+
+		svc := NewServiceWithClock(s, nil, nil, cf, clock)
+		if err := svc.CloseOpenChangesets(ctx, []*a8n.Changeset{c}); err != nil {
+			// We save the error in the job row instead of returning it, so
+			// that we don't roll back the transaction.
+			job.Error = fmt.Sprintf("closing changeset %s on %s: %s", c.ExternalID, c.ExternalServiceType, err)
+		}
+
+		job.FinishedAt = clock()
+		return s.UpdateChangesetCloseJob(ctx, &job)
+	}
 	worker := func() {
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			default:
-				didRun, err := s.ProcessPendingChangesetJobs(context.Background(), process)
+				didRun, err := s.ProcessPendingChangesetCloseJob(context.Background(), process)
 				if err != nil {
-					log15.Error("Running changeset job", "err", err)
+					log15.Error("Running changeset close job", "err", err)
 				}
 				// Back off on error or when no jobs available
 				if err != nil || !didRun {
@@ -54,7 +135,5 @@ func RunChangesetJobs(ctx context.Context, s *Store, clock func() time.Time, git
 			}
 		}
 	}
-	for i := 0; i < workerCount; i++ {
-		go worker()
-	}
+	go worker()
 }
@@ -3,7 +3,9 @@ package a8n
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -14,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -54,6 +57,9 @@ type Service struct {
 	repoResolveRevision repoResolveRevision
 	cf                  *httpcli.Factory
 
+	// overrideQuotas is set through OverrideQuotas.
+	overrideQuotas bool
+
 	clock func() time.Time
 }
 
@@ -79,20 +85,11 @@ func (s *Service) CreateCampaignPlanFromPatches(ctx context.Context, patches []a
 	if userID == 0 {
 		return nil, backend.ErrNotAuthenticated
 	}
-	// Look up all repositories
-	reposStore := repos.NewDBStore(s.store.DB(), sql.TxOptions{})
-	repoIDs := make([]api.RepoID, len(patches))
-	for i, patch := range patches {
-		repoIDs[i] = api.RepoID(patch.Repo)
-	}
-	allRepos, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+
+	jobs, err := s.campaignJobsFromPatches(ctx, patches)
 	if err != nil {
 		return nil, err
 	}
-	reposByID := make(map[api.RepoID]*repos.Repo, len(patches))
-	for _, repo := range allRepos {
-		reposByID[repo.ID] = repo
-	}
 
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
@@ -111,6 +108,44 @@ func (s *Service) CreateCampaignPlanFromPatches(ctx context.Context, patches []a
 		return nil, err
 	}
 
+	for _, job := range jobs {
+		job.CampaignPlanID = plan.ID
+		if err := tx.CreateCampaignJob(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// PreviewCampaignPlanFromPatches returns the CampaignJobs that
+// CreateCampaignPlanFromPatches would create for the given patches, without
+// persisting anything. The returned CampaignJobs have no ID and don't belong
+// to a CampaignPlan.
+func (s *Service) PreviewCampaignPlanFromPatches(ctx context.Context, patches []a8n.CampaignPlanPatch) ([]*a8n.CampaignJob, error) {
+	return s.campaignJobsFromPatches(ctx, patches)
+}
+
+// campaignJobsFromPatches resolves the repositories and base revisions of
+// the given patches and returns unsaved CampaignJobs for them. Patches for
+// repositories that are not supported by Automation are skipped.
+func (s *Service) campaignJobsFromPatches(ctx context.Context, patches []a8n.CampaignPlanPatch) ([]*a8n.CampaignJob, error) {
+	// Look up all repositories
+	reposStore := repos.NewDBStore(s.store.DB(), sql.TxOptions{})
+	repoIDs := make([]api.RepoID, len(patches))
+	for i, patch := range patches {
+		repoIDs[i] = api.RepoID(patch.Repo)
+	}
+	allRepos, err := reposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+	reposByID := make(map[api.RepoID]*repos.Repo, len(patches))
+	for _, repo := range allRepos {
+		reposByID[repo.ID] = repo
+	}
+
+	jobs := make([]*a8n.CampaignJob, 0, len(patches))
 	for _, patch := range patches {
 		repo := reposByID[patch.Repo]
 		if repo == nil {
@@ -125,27 +160,30 @@ func (s *Service) CreateCampaignPlanFromPatches(ctx context.Context, patches []a
 			return nil, errors.Wrapf(err, "repository %q", repo.Name)
 		}
 
-		job := &a8n.CampaignJob{
-			CampaignPlanID: plan.ID,
-			RepoID:         patch.Repo,
-			BaseRef:        patch.BaseRevision,
-			Rev:            commit,
-			Diff:           patch.Patch,
-			StartedAt:      s.clock(),
-			FinishedAt:     s.clock(),
-		}
-		if err := tx.CreateCampaignJob(ctx, job); err != nil {
-			return nil, err
-		}
+		jobs = append(jobs, &a8n.CampaignJob{
+			RepoID:     patch.Repo,
+			BaseRef:    patch.BaseRevision,
+			Rev:        commit,
+			Diff:       patch.Patch,
+			StartedAt:  s.clock(),
+			FinishedAt: s.clock(),
+		})
 	}
 
-	return plan, nil
+	return jobs, nil
 }
 
 // CreateCampaign creates the Campaign. When a CampaignPlanID is set on the
 // Campaign and the Campaign is not created as a draft, it calls
 // CreateChangesetJobs inside the same transaction in which it creates the
 // Campaign.
+//
+// If set, c.Spec must be a JSONC campaign spec. It's migrated to the current
+// version and stored as JSON, or an *ErrInvalidCampaignSpec is returned if it's
+// invalid.
+//
+// An *ErrQuotaExceeded is returned if the Campaign would exceed the open
+// campaigns quota of its namespace or the changesets quota.
 func (s *Service) CreateCampaign(ctx context.Context, c *a8n.Campaign, draft bool) error {
 	var err error
 	tr, ctx := trace.New(ctx, "Service.CreateCampaign", fmt.Sprintf("Name: %q", c.Name))
@@ -153,17 +191,40 @@ func (s *Service) CreateCampaign(ctx context.Context, c *a8n.Campaign, draft boo
 		tr.SetError(err)
 		tr.Finish()
 	}()
+	defer observeOperation("Service.CreateCampaign", time.Now(), &err)
 
 	if c.Name == "" {
 		return ErrCampaignNameBlank
 	}
 
+	if c.Spec != nil {
+		if c.Spec, err = ParseCampaignSpec(string(c.Spec)); err != nil {
+			return err
+		}
+	}
+
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Done(&err)
 
+	if err = checkCampaignName(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err = checkCampaignParent(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err = s.checkOpenCampaignsQuota(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if draft, err = applyCampaignNamespaceSettings(ctx, tx, c, draft); err != nil {
+		return err
+	}
+
 	c.CreatedAt = s.clock()
 	c.UpdatedAt = c.CreatedAt
 
@@ -182,11 +243,82 @@ func (s *Service) CreateCampaign(ctx context.Context, c *a8n.Campaign, draft boo
 	return s.createChangesetJobsWithStore(ctx, tx, c)
 }
 
+// applyCampaignNamespaceSettings applies the CampaignNamespaceSettings of the
+// namespace of c, if it has any, to the new Campaign c. It returns whether c
+// is created as a draft, which is always the case if the namespace requires
+// campaigns to be approved.
+func applyCampaignNamespaceSettings(ctx context.Context, store *Store, c *a8n.Campaign, draft bool) (bool, error) {
+	ns, err := store.GetCampaignNamespaceSettings(ctx, GetCampaignNamespaceSettingsOpts{
+		NamespaceUserID: c.NamespaceUserID,
+		NamespaceOrgID:  c.NamespaceOrgID,
+	})
+	if err == ErrNoResults {
+		return draft, nil
+	}
+	if err != nil {
+		return draft, errors.Wrap(err, "getting campaign namespace settings")
+	}
+
+	if c.Branch != "" && !strings.HasPrefix(c.Branch, ns.BranchPrefix) {
+		c.Branch = ns.BranchPrefix + c.Branch
+	}
+
+	return draft || ns.RequireApproval, nil
+}
+
 // ErrNoCampaignJobs is returned by CreateCampaign or UpdateCampaign if a
 // CampaignPlanID was specified but the CampaignPlan does not have any
 // (finished) CampaignJobs.
 var ErrNoCampaignJobs = errors.New("cannot create or update a Campaign without any changesets")
 
+// getCampaign returns the Campaign with the given ID, or an
+// *ErrCampaignNotFound if it doesn't exist.
+func getCampaign(ctx context.Context, store CampaignsStore, id int64) (*a8n.Campaign, error) {
+	campaign, err := store.GetCampaign(ctx, GetCampaignOpts{ID: id})
+	if err == ErrNoResults {
+		return nil, &ErrCampaignNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting campaign")
+	}
+	return campaign, nil
+}
+
+func isCampaignNotFound(err error) bool {
+	_, ok := err.(*ErrCampaignNotFound)
+	return ok
+}
+
+// checkCampaignName returns an *ErrCampaignNameConflict if a Campaign other
+// than c is currently named c.Name within the namespace of c. Previous names
+// of Campaigns don't conflict.
+//
+// Concurrent requests can't see each other's Campaigns, so the names are
+// also unique in the database, see campaignNameConflict.
+func checkCampaignName(ctx context.Context, store CampaignsStore, c *a8n.Campaign) error {
+	other, renamed, err := store.GetCampaignByName(ctx, GetCampaignByNameOpts{
+		Name:            c.Name,
+		NamespaceUserID: c.NamespaceUserID,
+		NamespaceOrgID:  c.NamespaceOrgID,
+	})
+	if err == ErrNoResults {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting campaign by name")
+	}
+
+	if renamed || other.ID == c.ID {
+		return nil
+	}
+
+	return &ErrCampaignNameConflict{
+		Name:            c.Name,
+		NamespaceUserID: c.NamespaceUserID,
+		NamespaceOrgID:  c.NamespaceOrgID,
+	}
+}
+
 func (s *Service) createChangesetJobsWithStore(ctx context.Context, store *Store, c *a8n.Campaign) error {
 	if c.CampaignPlanID == 0 {
 		return errors.New("cannot create changesets for campaign with no campaign plan")
@@ -207,6 +339,10 @@ func (s *Service) createChangesetJobsWithStore(ctx context.Context, store *Store
 		return ErrNoCampaignJobs
 	}
 
+	if err := s.CheckChangesetsQuota(ctx, store, c, len(jobs)); err != nil {
+		return err
+	}
+
 	for _, job := range jobs {
 		changesetJob := &a8n.ChangesetJob{
 			CampaignID:    c.ID,
@@ -298,23 +434,27 @@ func RunChangesetJob(
 	// it stable across retries and only set it the first time.
 
 	branch := c.Branch
-	ensureUniqueRef := true
+	strategy := conf.AutomationBranchCollisionStrategy()
+	ensureUniqueRef := strategy == "suffix"
+	failIfRefExists := !ensureUniqueRef
 	if job.Branch != "" {
 		// If job.Branch is set that means this method has already been
 		// executed for the given job. In that case, we want to use job.Branch
 		// as the ref, since we created it, and not fallback to another ref.
 		branch = job.Branch
 		ensureUniqueRef = false
+		failIfRefExists = false
 	}
 
-	ref, err := gitClient.CreateCommitFromPatch(ctx, protocol.CreateCommitFromPatchRequest{
+	req := protocol.CreateCommitFromPatchRequest{
 		Repo:       api.RepoName(repo.Name),
 		BaseCommit: campaignJob.Rev,
 		// IMPORTANT: We add a trailing newline here, otherwise `git apply`
 		// will fail with "corrupt patch at line <N>" where N is the last line.
-		Patch:     campaignJob.Diff + "\n",
-		TargetRef: branch,
-		UniqueRef: ensureUniqueRef,
+		Patch:           campaignJob.Diff + "\n",
+		TargetRef:       branch,
+		UniqueRef:       ensureUniqueRef,
+		FailIfRefExists: failIfRefExists,
 		CommitInfo: protocol.PatchCommitInfo{
 			Message:     c.Name,
 			AuthorName:  "Sourcegraph Bot",
@@ -328,7 +468,22 @@ func RunChangesetJob(
 		// so we need to disable that check with `--unidiff-zero`.
 		GitApplyArgs: []string{"-p0", "--unidiff-zero"},
 		Push:         true,
-	})
+	}
+
+	ref, err := gitClient.CreateCommitFromPatch(ctx, req)
+	if e, ok := err.(*protocol.CreateCommitFromPatchError); ok && e.RefExists {
+		if strategy != "reuse" {
+			job.BranchCollision = a8n.BranchCollisionFailed
+			return errors.Errorf("branch %q already exists on the code host of repo %q", branch, repo.Name)
+		}
+
+		job.BranchCollision = a8n.BranchCollisionReused
+		req.FailIfRefExists = false
+		ref, err = gitClient.CreateCommitFromPatch(ctx, req)
+	}
+	if err == nil && ensureUniqueRef && ref != git.EnsureRefPrefix(branch) {
+		job.BranchCollision = a8n.BranchCollisionSuffixed
+	}
 	if job.Branch != "" && job.Branch != ref {
 		return fmt.Errorf("ref %q doesn't match ChangesetJob's branch %q", ref, job.Branch)
 	}
@@ -381,21 +536,61 @@ func RunChangesetJob(
 		return err
 	}
 
+	if err := borrowRateLimit(ctx, externalService.ID, "changeset_creation", changesetCreationCost); err != nil {
+		return err
+	}
+
 	baseRef := "refs/heads/master"
 	if campaignJob.BaseRef != "" {
 		baseRef = campaignJob.BaseRef
 	}
 
+	title := c.Name
 	body := c.Description
 	if campaignJob.Description != "" {
 		body += "\n\n---\n\n" + campaignJob.Description
 	}
 
+	tmpl, err := CampaignSpecChangesetTemplate(c.Spec)
+	if err != nil {
+		return errors.Wrap(err, "getting changeset template of campaign spec")
+	}
+	if tmpl != nil {
+		data, err := NewChangesetTemplateData(c, api.RepoName(repo.Name), campaignJob, ref)
+		if err != nil {
+			return errors.Wrap(err, "computing changeset template data")
+		}
+
+		renderedTitle, renderedBody, err := tmpl.Render(data)
+		if err != nil {
+			return err
+		}
+		if renderedTitle != "" {
+			title = renderedTitle
+		}
+		if tmpl.Body != "" {
+			body = renderedBody
+		}
+	}
+
+	var labels []string
+	ns, err := store.GetCampaignNamespaceSettings(ctx, GetCampaignNamespaceSettingsOpts{
+		NamespaceUserID: c.NamespaceUserID,
+		NamespaceOrgID:  c.NamespaceOrgID,
+	})
+	if err != nil && err != ErrNoResults {
+		return errors.Wrap(err, "getting campaign namespace settings")
+	}
+	if ns != nil {
+		labels = ns.DefaultLabels
+	}
+
 	cs := repos.Changeset{
-		Title:   c.Name,
+		Title:   title,
 		Body:    body,
 		BaseRef: baseRef,
 		HeadRef: git.EnsureRefPrefix(ref),
+		Labels:  labels,
 		Repo:    repo,
 		Changeset: &a8n.Changeset{
 			RepoID:      repo.ID,
@@ -456,6 +651,10 @@ func RunChangesetJob(
 	}
 
 	job.ChangesetID = clone.ID
+	if err = enqueueChangesetReviewers(ctx, store, c, job); err != nil {
+		return err
+	}
+
 	runFinalUpdate(ctx, store)
 	return
 }
@@ -473,6 +672,7 @@ func (s *Service) CloseCampaign(ctx context.Context, id int64, closeChangesets b
 		tr.SetError(err)
 		tr.Finish()
 	}()
+	defer observeOperation("Service.CloseCampaign", time.Now(), &err)
 
 	transaction := func() (err error) {
 		tx, err := s.store.Transact(ctx)
@@ -481,56 +681,159 @@ func (s *Service) CloseCampaign(ctx context.Context, id int64, closeChangesets b
 		}
 		defer tx.Done(&err)
 
-		processing, err := campaignIsProcessing(ctx, tx, id)
-		if err != nil {
-			return err
-		}
-		if processing {
-			err = ErrDeleteProcessingCampaign
-			return err
+		campaign, err = closeCampaign(ctx, tx, id, closeChangesets)
+		return err
+	}
+
+	err = transaction()
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// closeCampaign closes the Campaign with the given ID and all the Campaigns
+// below it in the hierarchy of campaigns using the given store, which is
+// expected to be in a transaction.
+func closeCampaign(ctx context.Context, tx *Store, id int64, closeChangesets bool) (*a8n.Campaign, error) {
+	processing, err := campaignIsProcessing(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if processing {
+		return nil, ErrDeleteProcessingCampaign
+	}
+
+	campaign, err := getCampaign(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if closeChangesets {
+		if err = enqueueChangesetCloseJobs(ctx, tx, campaign.ID); err != nil {
+			return nil, err
 		}
+	}
 
-		campaign, err = tx.GetCampaign(ctx, GetCampaignOpts{ID: id})
-		if err != nil {
-			return errors.Wrap(err, "getting campaign")
+	if err = closeChildCampaigns(ctx, tx, campaign.ID, closeChangesets); err != nil {
+		return nil, err
+	}
+
+	if !campaign.ClosedAt.IsZero() {
+		return campaign, nil
+	}
+
+	campaign.ClosedAt = time.Now().UTC()
+
+	return campaign, tx.UpdateCampaign(ctx, campaign)
+}
+
+// CampaignStateUpdate is the result of transitioning a single Campaign in
+// UpdateCampaignsState. Exactly one of Campaign and Err is set.
+type CampaignStateUpdate struct {
+	ID       int64
+	Campaign *a8n.Campaign
+	Err      error
+}
+
+// UpdateCampaignsState closes (if closed is true) or reopens the Campaigns
+// with the given IDs in a single transaction.
+//
+// A Campaign that cannot be transitioned, because it doesn't exist, because
+// it is still being processed or because reopening it would exceed the open
+// campaigns quota, doesn't abort the transaction. Instead its error is
+// reported in the returned CampaignStateUpdate. Any other error rolls back the
+// whole transaction.
+func (s *Service) UpdateCampaignsState(ctx context.Context, ids []int64, closed, closeChangesets bool) (updates []*CampaignStateUpdate, err error) {
+	traceTitle := fmt.Sprintf("campaigns: %v, closed: %t, closeChangesets: %t", ids, closed, closeChangesets)
+	tr, ctx := trace.New(ctx, "service.UpdateCampaignsState", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	defer observeOperation("Service.UpdateCampaignsState", time.Now(), &err)
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	updates = make([]*CampaignStateUpdate, 0, len(ids))
+	for _, id := range ids {
+		var campaign *a8n.Campaign
+		if closed {
+			campaign, err = closeCampaign(ctx, tx, id, closeChangesets)
+		} else {
+			campaign, err = s.reopenCampaign(ctx, tx, id)
 		}
 
-		if !campaign.ClosedAt.IsZero() {
-			return nil
+		switch cause := errors.Cause(err); {
+		case cause == nil:
+			updates = append(updates, &CampaignStateUpdate{ID: id, Campaign: campaign})
+		case cause == ErrDeleteProcessingCampaign, isCampaignNotFound(cause), isQuotaExceeded(cause):
+			updates = append(updates, &CampaignStateUpdate{ID: id, Err: err})
+			err = nil
+		default:
+			return nil, err
 		}
+	}
 
-		campaign.ClosedAt = time.Now().UTC()
+	return updates, nil
+}
 
-		return tx.UpdateCampaign(ctx, campaign)
+// reopenCampaign reopens the closed Campaign with the given ID using the
+// given store, which is expected to be in a transaction. Changesets that
+// have been closed on the codehosts are not reopened.
+func (s *Service) reopenCampaign(ctx context.Context, tx *Store, id int64) (*a8n.Campaign, error) {
+	campaign, err := getCampaign(ctx, tx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	err = transaction()
-	if err != nil {
+	if campaign.ClosedAt.IsZero() {
+		return campaign, nil
+	}
+
+	if err := s.checkOpenCampaignsQuota(ctx, tx, campaign); err != nil {
 		return nil, err
 	}
 
-	if closeChangesets {
-		go func() {
-			ctx := trace.ContextWithTrace(context.Background(), tr)
+	campaign.ClosedAt = time.Time{}
 
-			cs, _, err := s.store.ListChangesets(ctx, ListChangesetsOpts{
-				CampaignID: campaign.ID,
-				Limit:      -1,
-			})
-			if err != nil {
-				log15.Error("ListChangesets", "err", err)
-				return
-			}
+	return campaign, tx.UpdateCampaign(ctx, campaign)
+}
 
-			// Close only the changesets that are open
-			err = s.CloseOpenChangesets(ctx, cs)
-			if err != nil {
-				log15.Error("CloseCampaignChangesets", "err", err)
-			}
-		}()
+// enqueueChangesetCloseJobs creates a ChangesetCloseJob for every open
+// Changeset of the Campaign with the given ID, to be processed by
+// RunChangesetCloseJobs.
+func enqueueChangesetCloseJobs(ctx context.Context, store *Store, campaignID int64) error {
+	cs, _, err := store.ListChangesets(ctx, ListChangesetsOpts{
+		CampaignID: campaignID,
+		Limit:      -1,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
 	}
 
-	return campaign, nil
+	cs = selectChangesets(cs, func(c *a8n.Changeset) bool {
+		s, err := c.State()
+		if err != nil {
+			log15.Warn("could not determine changeset state", "err", err)
+			return false
+		}
+		return s == a8n.ChangesetStateOpen
+	})
+
+	for _, c := range cs {
+		job := &a8n.ChangesetCloseJob{CampaignID: campaignID, ChangesetID: c.ID}
+		if err := store.CreateChangesetCloseJob(ctx, job); err != nil {
+			return errors.Wrap(err, "creating changeset close job")
+		}
+	}
+
+	return nil
 }
 
 // PublishCampaign publishes the Campaign with the given ID
@@ -543,6 +846,7 @@ func (s *Service) PublishCampaign(ctx context.Context, id int64) (campaign *a8n.
 		tr.SetError(err)
 		tr.Finish()
 	}()
+	defer observeOperation("Service.PublishCampaign", time.Now(), &err)
 
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
@@ -550,9 +854,9 @@ func (s *Service) PublishCampaign(ctx context.Context, id int64) (campaign *a8n.
 	}
 	defer tx.Done(&err)
 
-	campaign, err = tx.GetCampaign(ctx, GetCampaignOpts{ID: id})
+	campaign, err = getCampaign(ctx, tx, id)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting campaign")
+		return nil, err
 	}
 	return campaign, s.createChangesetJobsWithStore(ctx, tx, campaign)
 }
@@ -565,6 +869,10 @@ var ErrDeleteProcessingCampaign = errors.New("cannot delete a Campaign while cha
 // DeleteCampaign deletes the Campaign with the given ID if it hasn't been
 // deleted yet. If closeChangesets is true, the changesets associated with the
 // Campaign will be closed on the codehosts.
+//
+// The Campaign can be restored with RestoreCampaign until it's purged after
+// the retention period configured in the site configuration. Its Changesets
+// keep referencing it until then.
 func (s *Service) DeleteCampaign(ctx context.Context, id int64, closeChangesets bool) (err error) {
 	traceTitle := fmt.Sprintf("campaign: %d, closeChangesets: %t", id, closeChangesets)
 	tr, ctx := trace.New(ctx, "service.DeleteCampaign", traceTitle)
@@ -572,6 +880,7 @@ func (s *Service) DeleteCampaign(ctx context.Context, id int64, closeChangesets
 		tr.SetError(err)
 		tr.Finish()
 	}()
+	defer observeOperation("Service.DeleteCampaign", time.Now(), &err)
 
 	transaction := func() (cs []*a8n.Changeset, err error) {
 		tx, err := s.store.Transact(ctx)
@@ -588,31 +897,19 @@ func (s *Service) DeleteCampaign(ctx context.Context, id int64, closeChangesets
 			return nil, ErrDeleteProcessingCampaign
 		}
 
-		// If we don't have to close the changesets, we can simply delete the
-		// Campaign and return. The triggers in the database will remove the
-		// campaign's ID from the changesets' CampaignIDs.
+		if err := tx.DeleteCampaign(ctx, id); err != nil {
+			return nil, err
+		}
+
 		if !closeChangesets {
-			return nil, tx.DeleteCampaign(ctx, id)
+			return nil, nil
 		}
 
-		// First load the Changesets with the given campaignID, before deleting
-		// the campaign would remove the association.
-		cs, _, err = s.store.ListChangesets(ctx, ListChangesetsOpts{
+		cs, _, err = tx.ListChangesets(ctx, ListChangesetsOpts{
 			CampaignID: id,
 			Limit:      -1,
 		})
-		if err != nil {
-			return nil, err
-		}
-
-		// Remove the association manually, since we'll update the Changesets in
-		// the database, after closing them and we can't update them with an
-		// invalid CampaignID.
-		for _, c := range cs {
-			c.RemoveCampaignID(id)
-		}
-
-		return cs, s.store.DeleteCampaign(ctx, id)
+		return cs, err
 	}
 
 	cs, err := transaction()
@@ -631,6 +928,51 @@ func (s *Service) DeleteCampaign(ctx context.Context, id int64, closeChangesets
 	return nil
 }
 
+// RestoreCampaign restores the deleted Campaign with the given ID. Changesets
+// that were closed on the codehosts when it was deleted are not reopened.
+//
+// An *ErrCampaignNotFound is returned if the Campaign wasn't deleted within
+// the retention period, an *ErrCampaignNameConflict if another Campaign in its
+// namespace took its name in the meantime and an *ErrQuotaExceeded if
+// restoring it would exceed the open campaigns quota.
+func (s *Service) RestoreCampaign(ctx context.Context, id int64) (campaign *a8n.Campaign, err error) {
+	tr, ctx := trace.New(ctx, "service.RestoreCampaign", fmt.Sprintf("campaign: %d", id))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	defer observeOperation("Service.RestoreCampaign", time.Now(), &err)
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	campaign, err = tx.GetCampaign(ctx, GetCampaignOpts{
+		ID:           id,
+		DeletedAfter: s.clock().Add(-conf.AutomationDeletedCampaignRetention()),
+	})
+	if err == ErrNoResults {
+		return nil, &ErrCampaignNotFound{ID: id}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting deleted campaign")
+	}
+
+	if err := checkCampaignName(ctx, tx, campaign); err != nil {
+		return nil, err
+	}
+
+	if campaign.ClosedAt.IsZero() {
+		if err := s.checkOpenCampaignsQuota(ctx, tx, campaign); err != nil {
+			return nil, err
+		}
+	}
+
+	return campaign, campaignNameConflict(tx.RestoreCampaign(ctx, id), campaign)
+}
+
 // CloseOpenChangesets closes the given Changesets on their respective codehosts and syncs them.
 func (s *Service) CloseOpenChangesets(ctx context.Context, cs []*a8n.Changeset) (err error) {
 	cs = selectChangesets(cs, func(c *a8n.Changeset) bool {
@@ -680,6 +1022,33 @@ func (s *Service) CloseOpenChangesets(ctx context.Context, cs []*a8n.Changeset)
 	return syncer.SyncChangesetsWithSources(ctx, bySource)
 }
 
+// SyncChangeset refreshes the metadata of the Changeset with the given ID
+// from its codehost, regardless of when it's next due to be synced by the
+// ChangesetSyncer.
+func (s *Service) SyncChangeset(ctx context.Context, id int64) (err error) {
+	traceTitle := fmt.Sprintf("changeset: %d", id)
+	tr, ctx := trace.New(ctx, "service.SyncChangeset", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	c, err := s.store.GetChangeset(ctx, GetChangesetOpts{ID: id})
+	if err == ErrNoResults {
+		return &ErrChangesetNotFound{ID: id}
+	}
+	if err != nil {
+		return err
+	}
+
+	syncer := ChangesetSyncer{
+		ReposStore:  repos.NewDBStore(s.store.DB(), sql.TxOptions{}),
+		Store:       s.store,
+		HTTPFactory: s.cf,
+	}
+	return syncer.SyncChangesets(ctx, c)
+}
+
 // CreateChangesetJobForCampaignJob creates a ChangesetJob for the
 // CampaignJob with the given ID. The CampaignJob has to belong to a
 // CampaignPlan that was attached to a Campaign.
@@ -718,6 +1087,11 @@ func (s *Service) CreateChangesetJobForCampaignJob(ctx context.Context, campaign
 		// Already exists
 		return nil
 	}
+
+	if err = s.CheckChangesetsQuota(ctx, tx, campaign, 1); err != nil {
+		return err
+	}
+
 	changesetJob := &a8n.ChangesetJob{
 		CampaignID:    campaign.ID,
 		CampaignJobID: job.ID,
@@ -740,6 +1114,11 @@ type UpdateCampaignArgs struct {
 	Description *string
 	Branch      *string
 	Plan        *int64
+	// Spec is the JSONC campaign spec replacing the current one, if non-nil.
+	// The spec is removed if it's empty.
+	Spec *string
+	// AutoRebase replaces the AutoRebase setting of the Campaign, if non-nil.
+	AutoRebase *bool
 }
 
 // ErrCampaignNameBlank is returned by CreateCampaign or UpdateCampaign if the
@@ -762,6 +1141,7 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 		tr.SetError(err)
 		tr.Finish()
 	}()
+	defer observeOperation("Service.UpdateCampaign", time.Now(), &err)
 
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
@@ -770,9 +1150,9 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 
 	defer tx.Done(&err)
 
-	campaign, err = tx.GetCampaign(ctx, GetCampaignOpts{ID: args.Campaign})
+	campaign, err = getCampaign(ctx, tx, args.Campaign)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "getting campaign")
+		return nil, nil, err
 	}
 
 	var updateAttributes, updatePlanID, updateBranch bool
@@ -783,6 +1163,10 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 		}
 
 		campaign.Name = *args.Name
+		if err = checkCampaignName(ctx, tx, campaign); err != nil {
+			return nil, nil, err
+		}
+
 		updateAttributes = true
 	}
 
@@ -806,7 +1190,31 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 		updateBranch = true
 	}
 
+	var updateSpec bool
+	if args.Spec != nil {
+		var spec json.RawMessage
+		if *args.Spec != "" {
+			if spec, err = ParseCampaignSpec(*args.Spec); err != nil {
+				return nil, nil, err
+			}
+		}
+		campaign.Spec = spec
+		updateSpec = true
+	}
+
+	var updateAutoRebase bool
+	if args.AutoRebase != nil && campaign.AutoRebase != *args.AutoRebase {
+		campaign.AutoRebase = *args.AutoRebase
+		updateAutoRebase = true
+	}
+
 	if !updateAttributes && !updatePlanID && !updateBranch {
+		if updateSpec || updateAutoRebase {
+			// The spec only records how the changesets were produced and
+			// AutoRebase only affects future rebases, so changing them
+			// doesn't affect the existing changesets.
+			return campaign, nil, tx.UpdateCampaign(ctx, campaign)
+		}
 		return campaign, nil, nil
 	}
 
@@ -866,6 +1274,11 @@ func (s *Service) UpdateCampaign(ctx context.Context, args UpdateCampaignArgs) (
 	// have already been published, we don't want to create new ChangesetJobs,
 	// since they would be processed and publish the other Changesets.
 	if !partiallyPublished {
+		err := s.CheckChangesetsQuota(ctx, tx, campaign, len(diff.Create)-len(diff.Delete))
+		if err != nil {
+			return nil, nil, err
+		}
+
 		for _, c := range diff.Create {
 			err := tx.CreateChangesetJob(ctx, c)
 			if err != nil {
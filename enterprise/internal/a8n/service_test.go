@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sort"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,11 +16,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func init() {
@@ -112,6 +115,60 @@ func TestService(t *testing.T) {
 		}
 	})
 
+	t.Run("PreviewCampaignPlanFromPatches", func(t *testing.T) {
+		const commit = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		repoResolveRevision := func(context.Context, *repos.Repo, string) (api.CommitID, error) {
+			return commit, nil
+		}
+
+		svc := NewServiceWithClock(store, nil, repoResolveRevision, nil, clock)
+
+		const patch = `diff f f
+--- f
++++ f
+@@ -1,1 +1,2 @@
++x
+ y
+`
+		patches := []a8n.CampaignPlanPatch{
+			{Repo: api.RepoID(rs[2].ID), BaseRevision: "b2", Patch: patch},
+			{Repo: api.RepoID(rs[3].ID), BaseRevision: "b3", Patch: patch},
+		}
+
+		plansBefore, err := store.CountCampaignPlans(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jobs, err := svc.PreviewCampaignPlanFromPatches(ctx, patches)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantJobs := make([]*a8n.CampaignJob, len(patches))
+		for i, patch := range patches {
+			wantJobs[i] = &a8n.CampaignJob{
+				RepoID:     patch.Repo,
+				BaseRef:    patch.BaseRevision,
+				Rev:        commit,
+				Diff:       patch.Patch,
+				StartedAt:  now,
+				FinishedAt: now,
+			}
+		}
+		if !cmp.Equal(jobs, wantJobs) {
+			t.Error("jobs != wantJobs", cmp.Diff(jobs, wantJobs))
+		}
+
+		plansAfter, err := store.CountCampaignPlans(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plansAfter != plansBefore {
+			t.Fatalf("previewing created campaign plans: have %d, want %d", plansAfter, plansBefore)
+		}
+	})
+
 	t.Run("CreateCampaign", func(t *testing.T) {
 		plan := &a8n.CampaignPlan{CampaignType: "test", Arguments: `{}`, UserID: user.ID}
 		err = store.CreateCampaignPlan(ctx, plan)
@@ -201,6 +258,62 @@ func TestService(t *testing.T) {
 		}
 	})
 
+	t.Run("CreateCampaignWithNamespaceSettings", func(t *testing.T) {
+		// The settings are saved for another user, so that they don't apply
+		// to the campaigns of the other tests.
+		other, err := db.Users.Create(ctx, db.NewUser{Username: "namespace-settings"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = store.UpsertCampaignNamespaceSettings(ctx, &a8n.CampaignNamespaceSettings{
+			NamespaceUserID: other.ID,
+			BranchPrefix:    "campaigns/",
+			RequireApproval: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan := &a8n.CampaignPlan{CampaignType: "test", Arguments: `{}`, UserID: user.ID}
+		if err = store.CreateCampaignPlan(ctx, plan); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, repo := range rs {
+			if err := store.CreateCampaignJob(ctx, testCampaignJob(plan.ID, repo.ID, now)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		campaign := testCampaign(other.ID, plan.ID)
+
+		svc := NewServiceWithClock(store, gitClient, nil, cf, clock)
+		if err = svc.CreateCampaign(ctx, campaign, false); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := store.GetCampaign(ctx, GetCampaignOpts{ID: campaign.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "campaigns/test-branch"; have.Branch != want {
+			t.Errorf("have branch %q, want %q", have.Branch, want)
+		}
+
+		// The namespace requires approval, so the campaign is a draft.
+		haveJobs, _, err := store.ListChangesetJobs(ctx, ListChangesetJobsOpts{
+			CampaignID: campaign.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(haveJobs) != 0 {
+			t.Errorf("wrong number of ChangesetJobs: %d. want=%d", len(haveJobs), 0)
+		}
+	})
+
 	t.Run("CreateChangesetJobForCampaignJob", func(t *testing.T) {
 		plan := &a8n.CampaignPlan{CampaignType: "test", Arguments: `{}`, UserID: user.ID}
 		err = store.CreateCampaignPlan(ctx, plan)
@@ -341,7 +454,7 @@ func TestService(t *testing.T) {
 					}
 				}
 
-				newName := "this is a new campaign name"
+				newName := fmt.Sprintf("this is a new campaign name %d", campaign.ID)
 				args := UpdateCampaignArgs{Campaign: campaign.ID, Name: &newName, Branch: tc.branch}
 
 				updatedCampaign, _, err := svc.UpdateCampaign(ctx, args)
@@ -363,6 +476,188 @@ func TestService(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("UpdateCampaignsState", func(t *testing.T) {
+		svc := NewServiceWithClock(store, gitClient, nil, cf, clock)
+
+		var ids []int64
+		for i := 0; i < 2; i++ {
+			plan := &a8n.CampaignPlan{CampaignType: "test", Arguments: `{}`, UserID: user.ID}
+			if err := store.CreateCampaignPlan(ctx, plan); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.CreateCampaignJob(ctx, testCampaignJob(plan.ID, rs[0].ID, now)); err != nil {
+				t.Fatal(err)
+			}
+
+			campaign := testCampaign(user.ID, plan.ID)
+			if err := svc.CreateCampaign(ctx, campaign, true); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, campaign.ID)
+		}
+
+		missingID := ids[len(ids)-1] + 1000
+		ids = append(ids, missingID)
+
+		for _, closed := range []bool{true, false} {
+			updates, err := svc.UpdateCampaignsState(ctx, ids, closed, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if have, want := len(updates), len(ids); have != want {
+				t.Fatalf("wrong number of updates. have=%d, want=%d", have, want)
+			}
+
+			for i, u := range updates {
+				if u.ID != ids[i] {
+					t.Errorf("wrong ID in update %d. have=%d, want=%d", i, u.ID, ids[i])
+				}
+
+				if u.ID == missingID {
+					want := &ErrCampaignNotFound{ID: missingID}
+					if have, ok := u.Err.(*ErrCampaignNotFound); !ok || *have != *want {
+						t.Errorf("wrong error for missing campaign. have=%v, want=%v", u.Err, want)
+					}
+					continue
+				}
+
+				if u.Err != nil {
+					t.Fatalf("unexpected error for campaign %d: %s", u.ID, u.Err)
+				}
+
+				have, err := store.GetCampaign(ctx, GetCampaignOpts{ID: u.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have.ClosedAt.IsZero() == closed {
+					t.Errorf("campaign %d has wrong ClosedAt %v. want closed=%t", u.ID, have.ClosedAt, closed)
+				}
+			}
+		}
+	})
+
+	t.Run("CampaignNameConflict", func(t *testing.T) {
+		svc := NewServiceWithClock(store, gitClient, nil, cf, clock)
+
+		campaign := testCampaign(user.ID, 0)
+		if err := svc.CreateCampaign(ctx, campaign, true); err != nil {
+			t.Fatal(err)
+		}
+
+		want := &ErrCampaignNameConflict{Name: campaign.Name, NamespaceUserID: user.ID}
+
+		duplicate := testCampaign(user.ID, 0)
+		duplicate.Name = campaign.Name
+		err := svc.CreateCampaign(ctx, duplicate, true)
+		if have, ok := err.(*ErrCampaignNameConflict); !ok || *have != *want {
+			t.Errorf("wrong error creating campaign with taken name. have=%v, want=%v", err, want)
+		}
+
+		other := testCampaign(user.ID, 0)
+		if err := svc.CreateCampaign(ctx, other, true); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = svc.UpdateCampaign(ctx, UpdateCampaignArgs{Campaign: other.ID, Name: &campaign.Name})
+		if have, ok := err.(*ErrCampaignNameConflict); !ok || *have != *want {
+			t.Errorf("wrong error renaming campaign to taken name. have=%v, want=%v", err, want)
+		}
+
+		// Renaming a campaign frees up its previous name.
+		oldName := campaign.Name
+		newName := oldName + " (renamed)"
+		if _, _, err := svc.UpdateCampaign(ctx, UpdateCampaignArgs{Campaign: campaign.ID, Name: &newName}); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := svc.UpdateCampaign(ctx, UpdateCampaignArgs{Campaign: other.ID, Name: &oldName}); err != nil {
+			t.Fatal(err)
+		}
+
+		missingID := other.ID + 1000
+		_, _, err = svc.UpdateCampaign(ctx, UpdateCampaignArgs{Campaign: missingID, Name: &newName})
+		if have, ok := err.(*ErrCampaignNotFound); !ok || have.ID != missingID {
+			t.Errorf("wrong error updating missing campaign. have=%v, want=%v", err, &ErrCampaignNotFound{ID: missingID})
+		}
+	})
+
+	t.Run("Quotas", func(t *testing.T) {
+		open, err := store.CountCampaigns(ctx, CountCampaignsOpts{
+			State:            a8n.CampaignStateOpen,
+			NamespaceUserIDs: []int32{user.ID},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Leave room for a single open campaign in the namespace, which must
+		// not have as many changesets as there are repos.
+		quotas := schema.AutomationQuotas{
+			MaxOpenCampaignsPerNamespace: int(open) + 1,
+			MaxChangesetsPerCampaign:     len(rs) - 1,
+		}
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{AutomationQuotas: &quotas}})
+		defer conf.Mock(nil)
+
+		plan := &a8n.CampaignPlan{CampaignType: "test", Arguments: `{}`, UserID: user.ID}
+		if err := store.CreateCampaignPlan(ctx, plan); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, repo := range rs {
+			if err := store.CreateCampaignJob(ctx, testCampaignJob(plan.ID, repo.ID, now)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		svc := NewServiceWithClock(store, gitClient, nil, cf, clock)
+
+		wantChangesets := &ErrQuotaExceeded{
+			Quota:     QuotaChangesetsPerCampaign,
+			Limit:     quotas.MaxChangesetsPerCampaign,
+			Requested: len(rs),
+		}
+
+		err = svc.CreateCampaign(ctx, testCampaign(user.ID, plan.ID), false)
+		if have, ok := err.(*ErrQuotaExceeded); !ok || *have != *wantChangesets {
+			t.Errorf("wrong error creating campaign. have=%v, want=%v", err, wantChangesets)
+		}
+
+		// The failed campaign was rolled back, so there's still room for a
+		// draft that doesn't create any changesets yet.
+		draft := testCampaign(user.ID, plan.ID)
+		if err := svc.CreateCampaign(ctx, draft, true); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = svc.PublishCampaign(ctx, draft.ID)
+		if have, ok := err.(*ErrQuotaExceeded); !ok || *have != *wantChangesets {
+			t.Errorf("wrong error publishing campaign. have=%v, want=%v", err, wantChangesets)
+		}
+
+		wantOpen := &ErrQuotaExceeded{
+			Quota:     QuotaOpenCampaignsPerNamespace,
+			Limit:     quotas.MaxOpenCampaignsPerNamespace,
+			Requested: quotas.MaxOpenCampaignsPerNamespace + 1,
+		}
+
+		err = svc.CreateCampaign(ctx, testCampaign(user.ID, 0), true)
+		if have, ok := err.(*ErrQuotaExceeded); !ok || *have != *wantOpen {
+			t.Errorf("wrong error creating campaign. have=%v, want=%v", err, wantOpen)
+		}
+
+		svc.OverrideQuotas(true)
+
+		if _, err := svc.PublishCampaign(ctx, draft.ID); err != nil {
+			t.Fatalf("publishing campaign with overridden quotas: %s", err)
+		}
+		if err := svc.CreateCampaign(ctx, testCampaign(user.ID, 0), true); err != nil {
+			t.Fatalf("creating campaign with overridden quotas: %s", err)
+		}
+	})
 }
 
 type repoNames []string
@@ -654,7 +949,7 @@ func TestService_UpdateCampaignWithNewCampaignPlanID(t *testing.T) {
 			// Update the Campaign
 			args := UpdateCampaignArgs{Campaign: campaign.ID}
 			if tt.updateName {
-				newName := "new campaign Name"
+				newName := fmt.Sprintf("new campaign Name %d", campaign.ID)
 				args.Name = &newName
 			}
 			if tt.updateDescription {
@@ -940,9 +1235,13 @@ func testCampaignJob(plan int64, repo api.RepoID, t time.Time) *a8n.CampaignJob
 	}
 }
 
+// testCampaignCount is used to give each Campaign created by testCampaign a
+// unique name, since names are unique within a namespace.
+var testCampaignCount int64
+
 func testCampaign(user int32, plan int64) *a8n.Campaign {
 	c := &a8n.Campaign{
-		Name:            "Testing Campaign",
+		Name:            fmt.Sprintf("Testing Campaign %d", atomic.AddInt64(&testCampaignCount, 1)),
 		Description:     "Testing Campaign",
 		AuthorID:        user,
 		NamespaceUserID: user,
@@ -973,3 +1272,94 @@ type dummyGitserverClient struct {
 func (d *dummyGitserverClient) CreateCommitFromPatch(ctx context.Context, req protocol.CreateCommitFromPatchRequest) (string, error) {
 	return d.response, d.responseErr
 }
+
+// fakeCampaignsStore is a CampaignsStore holding the given Campaigns. Only
+// the methods used by the tests are implemented.
+type fakeCampaignsStore struct {
+	CampaignsStore
+	campaigns []*a8n.Campaign
+}
+
+func (s *fakeCampaignsStore) GetCampaignByName(ctx context.Context, opts GetCampaignByNameOpts) (*a8n.Campaign, bool, error) {
+	for _, c := range s.campaigns {
+		if c.Name == opts.Name && c.NamespaceUserID == opts.NamespaceUserID && c.NamespaceOrgID == opts.NamespaceOrgID {
+			return c, false, nil
+		}
+	}
+	return nil, false, ErrNoResults
+}
+
+func (s *fakeCampaignsStore) GetCampaign(ctx context.Context, opts GetCampaignOpts) (*a8n.Campaign, error) {
+	for _, c := range s.campaigns {
+		if c.ID == opts.ID {
+			return c, nil
+		}
+	}
+	return nil, ErrNoResults
+}
+
+func TestCheckCampaignName(t *testing.T) {
+	store := &fakeCampaignsStore{campaigns: []*a8n.Campaign{
+		{ID: 1, Name: "taken", NamespaceUserID: 1},
+	}}
+
+	for _, tc := range []struct {
+		name     string
+		campaign *a8n.Campaign
+		conflict bool
+	}{
+		{name: "free name", campaign: &a8n.Campaign{ID: 2, Name: "free", NamespaceUserID: 1}},
+		{name: "same campaign", campaign: &a8n.Campaign{ID: 1, Name: "taken", NamespaceUserID: 1}},
+		{name: "other namespace", campaign: &a8n.Campaign{ID: 2, Name: "taken", NamespaceOrgID: 1}},
+		{name: "conflict", campaign: &a8n.Campaign{ID: 2, Name: "taken", NamespaceUserID: 1}, conflict: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkCampaignName(context.Background(), store, tc.campaign)
+			if _, ok := err.(*ErrCampaignNameConflict); ok != tc.conflict {
+				t.Fatalf("got error %v, want conflict: %t", err, tc.conflict)
+			}
+		})
+	}
+}
+
+func TestCheckCampaignParent(t *testing.T) {
+	// 1 <- 2 <- 3
+	store := &fakeCampaignsStore{campaigns: []*a8n.Campaign{
+		{ID: 1},
+		{ID: 2, ParentCampaignID: 1},
+		{ID: 3, ParentCampaignID: 2},
+	}}
+
+	for _, tc := range []struct {
+		name     string
+		campaign *a8n.Campaign
+		wantErr  error
+	}{
+		{name: "no parent", campaign: &a8n.Campaign{ID: 4}},
+		{name: "new campaign", campaign: &a8n.Campaign{ParentCampaignID: 3}},
+		{name: "leaf", campaign: &a8n.Campaign{ID: 4, ParentCampaignID: 3}},
+		{name: "move subtree", campaign: &a8n.Campaign{ID: 2}},
+		{
+			name:     "own parent",
+			campaign: &a8n.Campaign{ID: 1, ParentCampaignID: 1},
+			wantErr:  &ErrCampaignParentCycle{ID: 1, ParentID: 1},
+		},
+		{
+			name:     "descendant as parent",
+			campaign: &a8n.Campaign{ID: 1, ParentCampaignID: 3},
+			wantErr:  &ErrCampaignParentCycle{ID: 1, ParentID: 3},
+		},
+		{
+			name:     "missing parent",
+			campaign: &a8n.Campaign{ID: 4, ParentCampaignID: 5},
+			wantErr:  &ErrCampaignNotFound{ID: 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkCampaignParent(context.Background(), store, tc.campaign)
+			if diff := cmp.Diff(tc.wantErr, err); diff != "" {
+				t.Fatalf("unexpected error (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
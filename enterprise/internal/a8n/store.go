@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
@@ -39,6 +41,23 @@ func NewStoreWithClock(db dbutil.DB, clock func() time.Time) *Store {
 	return &Store{db: db, now: clock}
 }
 
+// CampaignsStore reads and writes Campaigns. It is implemented by Store, so
+// code that only deals with Campaigns can depend on it instead, and be tested
+// with a fake.
+type CampaignsStore interface {
+	CreateCampaign(ctx context.Context, c *a8n.Campaign) error
+	UpdateCampaign(ctx context.Context, c *a8n.Campaign) error
+	DeleteCampaign(ctx context.Context, id int64) error
+	RestoreCampaign(ctx context.Context, id int64) error
+	CountCampaigns(ctx context.Context, opts CountCampaignsOpts) (int64, error)
+	EstimateCampaignsCount(ctx context.Context, opts CountCampaignsOpts) (int64, error)
+	GetCampaign(ctx context.Context, opts GetCampaignOpts) (*a8n.Campaign, error)
+	GetCampaignByName(ctx context.Context, opts GetCampaignByNameOpts) (c *a8n.Campaign, renamed bool, err error)
+	ListCampaigns(ctx context.Context, opts ListCampaignsOpts) (cs []*a8n.Campaign, next int64, err error)
+}
+
+var _ CampaignsStore = &Store{}
+
 // Clock returns the clock used by the Store.
 func (s *Store) Clock() func() time.Time { return s.now }
 
@@ -63,59 +82,6 @@ func (s *Store) Transact(ctx context.Context) (*Store, error) {
 	return &Store{db: tx, now: s.now}, nil
 }
 
-// ProcessPendingChangesetJobs attempts to fetch one pending changeset job.
-// A pending job is one that has never been started and its plan is not cancelled.
-// If found, 'process' is called. We guarantee that if process is called it will have exclusive global access to
-// the job. All operations on the job should be done using the supplied store as they will run in a transaction.
-// Returning an error will roll back the transaction.
-// NOTE: It should not be called from within an existing transaction
-func (s *Store) ProcessPendingChangesetJobs(ctx context.Context, process func(ctx context.Context, s *Store, job a8n.ChangesetJob) error) (didRun bool, err error) {
-	tx, err := s.Transact(ctx)
-	if err != nil {
-		return false, errors.Wrap(err, "starting transaction")
-	}
-	defer tx.Done(&err)
-	q := sqlf.Sprintf(getPendingChangesetJobQuery)
-	var job a8n.ChangesetJob
-	_, count, err := tx.query(ctx, q, func(sc scanner) (last, count int64, err error) {
-		err = scanChangesetJob(&job, sc)
-		if err != nil {
-			return 0, 0, errors.Wrap(err, "scanning campaign job row")
-		}
-		return job.ID, 1, nil
-	})
-	if err != nil {
-		return false, errors.Wrap(err, "querying for pending campaign job")
-	}
-	if count == 0 {
-		return false, nil
-	}
-	err = process(ctx, tx, job)
-	return true, err
-}
-
-const getPendingChangesetJobQuery = `
-UPDATE changeset_jobs j SET started_at = now() WHERE id = (
-	SELECT j.id FROM changeset_jobs j
-	JOIN campaigns c ON c.id = j.campaign_id
-	JOIN campaign_plans p ON p.id = c.campaign_plan_id
-	WHERE j.started_at IS NULL
-	AND p.canceled_at IS NULL
-	ORDER BY j.id ASC
-	FOR UPDATE SKIP LOCKED LIMIT 1
-)
-RETURNING j.id,
-  j.campaign_id,
-  j.campaign_job_id,
-  j.changeset_id,
-  j.branch,
-  j.error,
-  j.started_at,
-  j.finished_at,
-  j.created_at,
-  j.updated_at
-`
-
 // ProcessPendingCampaignJob attempts to fetch one pending campaign job. If found, 'process'
 // is called. We guarantee that if process is called it will have exclusive global access to the job.
 // All operations on the job should be done using the supplied store as they will run in a transaction.
@@ -316,7 +282,10 @@ WITH batch AS (
       external_id           text,
       external_service_type text,
       external_branch       text,
-      external_deleted_at   timestamptz
+      external_deleted_at   timestamptz,
+      diff_stat_added       integer,
+      diff_stat_changed     integer,
+      diff_stat_deleted     integer
     )
   )
   WITH ORDINALITY
@@ -335,7 +304,10 @@ changed AS (
     external_id,
     external_service_type,
     external_branch,
-	external_deleted_at
+	external_deleted_at,
+    diff_stat_added,
+    diff_stat_changed,
+    diff_stat_deleted
   )
   SELECT
     repo_id,
@@ -346,7 +318,10 @@ changed AS (
     external_id,
     external_service_type,
     external_branch,
-	external_deleted_at
+	external_deleted_at,
+    diff_stat_added,
+    diff_stat_changed,
+    diff_stat_deleted
   FROM batch
   ON CONFLICT ON CONSTRAINT
     changesets_repo_external_id_unique
@@ -366,7 +341,13 @@ SELECT
   COALESCE(changed.external_id, existing.external_id) AS external_id,
   COALESCE(changed.external_service_type, existing.external_service_type) AS external_service_type,
   COALESCE(changed.external_branch, existing.external_branch) AS external_branch,
-  COALESCE(changed.external_deleted_at, existing.external_deleted_at) AS external_deleted_at
+  COALESCE(changed.external_deleted_at, existing.external_deleted_at) AS external_deleted_at,
+  COALESCE(changed.diff_stat_added, existing.diff_stat_added) AS diff_stat_added,
+  COALESCE(changed.diff_stat_changed, existing.diff_stat_changed) AS diff_stat_changed,
+  COALESCE(changed.diff_stat_deleted, existing.diff_stat_deleted) AS diff_stat_deleted,
+  COALESCE(changed.has_conflicts, existing.has_conflicts) AS has_conflicts,
+  COALESCE(changed.conflicts_base_oid, existing.conflicts_base_oid) AS conflicts_base_oid,
+  COALESCE(changed.conflicts_head_oid, existing.conflicts_head_oid) AS conflicts_head_oid
 FROM changed
 RIGHT JOIN batch ON batch.repo_id = changed.repo_id
 AND batch.external_id = changed.external_id
@@ -401,6 +382,9 @@ func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, erro
 		ExternalServiceType string          `json:"external_service_type"`
 		ExternalBranch      string          `json:"external_branch"`
 		ExternalDeletedAt   *time.Time      `json:"external_deleted_at"`
+		DiffStatAdded       *int32          `json:"diff_stat_added"`
+		DiffStatChanged     *int32          `json:"diff_stat_changed"`
+		DiffStatDeleted     *int32          `json:"diff_stat_deleted"`
 	}
 
 	records := make([]record, 0, len(cs))
@@ -427,6 +411,9 @@ func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, erro
 			ExternalServiceType: c.ExternalServiceType,
 			ExternalBranch:      c.ExternalBranch,
 			ExternalDeletedAt:   nullTimeColumn(c.ExternalDeletedAt),
+			DiffStatAdded:       c.DiffStatAdded,
+			DiffStatChanged:     c.DiffStatChanged,
+			DiffStatDeleted:     c.DiffStatDeleted,
 		})
 	}
 
@@ -442,6 +429,9 @@ func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, erro
 // counting changesets.
 type CountChangesetsOpts struct {
 	CampaignID int64
+	// HasConflicts, if true, only counts changesets that have merge conflicts
+	// with their base branch.
+	HasConflicts bool
 }
 
 // CountChangesets returns the number of changesets in the database.
@@ -466,6 +456,10 @@ func countChangesetsQuery(opts *CountChangesetsOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("campaign_ids ? %s", opts.CampaignID))
 	}
 
+	if opts.HasConflicts {
+		preds = append(preds, sqlf.Sprintf("has_conflicts"))
+	}
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -476,6 +470,7 @@ func countChangesetsQuery(opts *CountChangesetsOpts) *sqlf.Query {
 // GetChangesetOpts captures the query options needed for getting a Changeset
 type GetChangesetOpts struct {
 	ID                  int64
+	RepoID              api.RepoID
 	ExternalID          string
 	ExternalServiceType string
 }
@@ -514,7 +509,13 @@ SELECT
   external_id,
   external_service_type,
   external_branch,
-  external_deleted_at
+  external_deleted_at,
+  diff_stat_added,
+  diff_stat_changed,
+  diff_stat_deleted,
+  has_conflicts,
+  conflicts_base_oid,
+  conflicts_head_oid
 FROM changesets
 WHERE %s
 LIMIT 1
@@ -526,6 +527,10 @@ func getChangesetQuery(opts *GetChangesetOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("id = %s", opts.ID))
 	}
 
+	if opts.RepoID != 0 {
+		preds = append(preds, sqlf.Sprintf("repo_id = %s", opts.RepoID))
+	}
+
 	if opts.ExternalID != "" && opts.ExternalServiceType != "" {
 		preds = append(preds,
 			sqlf.Sprintf("external_id = %s", opts.ExternalID),
@@ -548,6 +553,9 @@ type ListChangesetsOpts struct {
 	CampaignID     int64
 	IDs            []int64
 	WithoutDeleted bool
+	// WithoutDiffStat, if true, only lists changesets whose diff stat
+	// hasn't been computed.
+	WithoutDiffStat bool
 }
 
 // ListChangesets lists Changesets with the given filters.
@@ -584,7 +592,13 @@ SELECT
   external_id,
   external_service_type,
   external_branch,
-  external_deleted_at
+  external_deleted_at,
+  diff_stat_added,
+  diff_stat_changed,
+  diff_stat_deleted,
+  has_conflicts,
+  conflicts_base_oid,
+  conflicts_head_oid
 FROM changesets
 WHERE %s
 ORDER BY id ASC
@@ -625,6 +639,10 @@ func listChangesetsQuery(opts *ListChangesetsOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("external_deleted_at IS NULL"))
 	}
 
+	if opts.WithoutDiffStat {
+		preds = append(preds, sqlf.Sprintf("diff_stat_added IS NULL"))
+	}
+
 	return sqlf.Sprintf(
 		listChangesetsQueryFmtstr+limitClause,
 		sqlf.Join(preds, "\n AND "),
@@ -659,7 +677,10 @@ changed AS (
     external_id           = batch.external_id,
     external_service_type = batch.external_service_type,
     external_branch       = batch.external_branch,
-	external_deleted_at   = batch.external_deleted_at
+	external_deleted_at   = batch.external_deleted_at,
+    diff_stat_added       = batch.diff_stat_added,
+    diff_stat_changed     = batch.diff_stat_changed,
+    diff_stat_deleted     = batch.diff_stat_deleted
   FROM batch
   WHERE changesets.id = batch.id
   RETURNING changesets.*
@@ -677,7 +698,13 @@ SELECT
   changed.external_id,
   changed.external_service_type,
   changed.external_branch,
-  changed.external_deleted_at
+  changed.external_deleted_at,
+  changed.diff_stat_added,
+  changed.diff_stat_changed,
+  changed.diff_stat_deleted,
+  changed.has_conflicts,
+  changed.conflicts_base_oid,
+  changed.conflicts_head_oid
 FROM changed
 LEFT JOIN batch ON batch.repo_id = changed.repo_id
 AND batch.external_id = changed.external_id
@@ -692,6 +719,75 @@ func (s *Store) updateChangesetsQuery(cs []*a8n.Changeset) (*sqlf.Query, error)
 	return batchChangesetsQuery(updateChangesetsQueryFmtstr, cs)
 }
 
+// UpdateChangesetDiffStat sets the diff stat columns of the given Changeset
+// to its DiffStat. Unlike UpdateChangesets, it doesn't touch the other
+// columns, so that it can't overwrite the results of a concurrent sync. If
+// the Changeset was updated since it was read, the diff stat may be
+// outdated and is not saved, in which case false is returned.
+func (s *Store) UpdateChangesetDiffStat(ctx context.Context, c *a8n.Changeset) (updated bool, err error) {
+	q := sqlf.Sprintf(
+		updateChangesetDiffStatQueryFmtstr,
+		c.DiffStatAdded,
+		c.DiffStatChanged,
+		c.DiffStatDeleted,
+		c.ID,
+		c.UpdatedAt,
+	)
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		updated = true
+		err = sc.Scan(&last)
+		return last, 1, err
+	})
+	return updated, err
+}
+
+var updateChangesetDiffStatQueryFmtstr = `
+-- source: internal/a8n/store.go:UpdateChangesetDiffStat
+UPDATE changesets
+SET
+  diff_stat_added   = %s,
+  diff_stat_changed = %s,
+  diff_stat_deleted = %s
+WHERE id = %s
+AND updated_at = %s
+RETURNING id
+`
+
+// UpdateChangesetConflicts sets the merge conflict columns of the given
+// Changeset. Like UpdateChangesetDiffStat, it doesn't touch the other columns
+// and returns false without saving anything if the Changeset was updated
+// since it was read.
+func (s *Store) UpdateChangesetConflicts(ctx context.Context, c *a8n.Changeset) (updated bool, err error) {
+	q := sqlf.Sprintf(
+		updateChangesetConflictsQueryFmtstr,
+		c.HasConflicts,
+		nullStringColumn(c.ConflictsBaseOid),
+		nullStringColumn(c.ConflictsHeadOid),
+		c.ID,
+		c.UpdatedAt,
+	)
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		updated = true
+		err = sc.Scan(&last)
+		return last, 1, err
+	})
+	return updated, err
+}
+
+var updateChangesetConflictsQueryFmtstr = `
+-- source: internal/a8n/store.go:UpdateChangesetConflicts
+UPDATE changesets
+SET
+  has_conflicts      = %s,
+  conflicts_base_oid = %s,
+  conflicts_head_oid = %s
+WHERE id = %s
+AND updated_at = %s
+RETURNING id
+`
+
 // GetChangesetEventOpts captures the query options needed for getting a ChangesetEvent
 type GetChangesetEventOpts struct {
 	ID          int64
@@ -759,6 +855,7 @@ func getChangesetEventQuery(opts *GetChangesetEventOpts) *sqlf.Query {
 // listing changeset events.
 type ListChangesetEventsOpts struct {
 	ChangesetIDs []int64
+	Kinds        []a8n.ChangesetEventKind
 	Cursor       int64
 	Limit        int
 }
@@ -826,6 +923,15 @@ func listChangesetEventsQuery(opts *ListChangesetEventsOpts) *sqlf.Query {
 			sqlf.Sprintf("changeset_id IN (%s)", sqlf.Join(ids, ",")))
 	}
 
+	if len(opts.Kinds) != 0 {
+		kinds := make([]*sqlf.Query, 0, len(opts.Kinds))
+		for _, k := range opts.Kinds {
+			kinds = append(kinds, sqlf.Sprintf("%s", k))
+		}
+		preds = append(preds,
+			sqlf.Sprintf("kind IN (%s)", sqlf.Join(kinds, ",")))
+	}
+
 	return sqlf.Sprintf(
 		listChangesetEventsQueryFmtstr+limitClause,
 		sqlf.Join(preds, "\n AND "),
@@ -998,17 +1104,22 @@ func batchChangesetEventsQuery(fmtstr string, es []*a8n.ChangesetEvent) (*sqlf.Q
 	return sqlf.Sprintf(fmtstr, string(batch)), nil
 }
 
-// CreateCampaign creates the given Campaign.
-func (s *Store) CreateCampaign(ctx context.Context, c *a8n.Campaign) error {
+// CreateCampaign creates the given Campaign. An *ErrCampaignNameConflict is
+// returned if another Campaign in its namespace has the same name.
+func (s *Store) CreateCampaign(ctx context.Context, c *a8n.Campaign) (err error) {
+	ctx, done := observeStore(ctx, "CreateCampaign")
+	defer done(&err)
+
 	q, err := s.createCampaignQuery(c)
 	if err != nil {
 		return err
 	}
 
-	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
 		err = scanCampaign(c, sc)
 		return c.ID, 1, err
 	})
+	return campaignNameConflict(err, c)
 }
 
 var createCampaignQueryFmtstr = `
@@ -1024,9 +1135,14 @@ INSERT INTO campaigns (
   updated_at,
   changeset_ids,
   campaign_plan_id,
-  closed_at
+  closed_at,
+  spec,
+  rollback_of_campaign_id,
+  parent_campaign_id,
+  auto_rebase,
+  position
 )
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
   id,
   name,
@@ -1039,7 +1155,12 @@ RETURNING
   updated_at,
   changeset_ids,
   campaign_plan_id,
-  closed_at
+  closed_at,
+  spec,
+  rollback_of_campaign_id,
+  parent_campaign_id,
+  auto_rebase,
+  position
 `
 
 func (s *Store) createCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
@@ -1056,6 +1177,10 @@ func (s *Store) createCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
 		c.UpdatedAt = c.CreatedAt
 	}
 
+	if c.Position == "" {
+		c.Position = campaignPositionAt(c.CreatedAt)
+	}
+
 	return sqlf.Sprintf(
 		createCampaignQueryFmtstr,
 		c.Name,
@@ -1069,6 +1194,11 @@ func (s *Store) createCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
 		changesetIDs,
 		nullInt64Column(c.CampaignPlanID),
 		nullTimeColumn(c.ClosedAt),
+		nullStringColumn(string(c.Spec)),
+		nullInt64Column(c.RollbackOfCampaignID),
+		nullInt64Column(c.ParentCampaignID),
+		c.AutoRebase,
+		c.Position,
 	), nil
 }
 
@@ -1100,21 +1230,38 @@ func nullStringColumn(s string) *string {
 	return &s
 }
 
-// UpdateCampaign updates the given Campaign.
-func (s *Store) UpdateCampaign(ctx context.Context, c *a8n.Campaign) error {
+// UpdateCampaign updates the given Campaign. An *ErrCampaignNameConflict is
+// returned if another Campaign in its namespace has the same name.
+func (s *Store) UpdateCampaign(ctx context.Context, c *a8n.Campaign) (err error) {
+	ctx, done := observeStore(ctx, "UpdateCampaign")
+	defer done(&err)
+
 	q, err := s.updateCampaignQuery(c)
 	if err != nil {
 		return err
 	}
 
-	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
 		err = scanCampaign(c, sc)
 		return c.ID, 1, err
 	})
+	return campaignNameConflict(err, c)
 }
 
 var updateCampaignQueryFmtstr = `
 -- source: internal/a8n/store.go:UpdateCampaign
+WITH renamed AS (
+  INSERT INTO campaign_name_history (
+    campaign_id,
+    name,
+    namespace_user_id,
+    namespace_org_id,
+    renamed_at
+  )
+  SELECT id, name, namespace_user_id, namespace_org_id, %s
+  FROM campaigns
+  WHERE id = %s AND name <> %s
+)
 UPDATE campaigns
 SET (
   name,
@@ -1126,8 +1273,11 @@ SET (
   updated_at,
   changeset_ids,
   campaign_plan_id,
-  closed_at
-) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+  closed_at,
+  spec,
+  parent_campaign_id,
+  auto_rebase
+) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   id,
@@ -1141,7 +1291,12 @@ RETURNING
   updated_at,
   changeset_ids,
   campaign_plan_id,
-  closed_at
+  closed_at,
+  spec,
+  rollback_of_campaign_id,
+  parent_campaign_id,
+  auto_rebase,
+  position
 `
 
 func (s *Store) updateCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
@@ -1154,6 +1309,9 @@ func (s *Store) updateCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
 
 	return sqlf.Sprintf(
 		updateCampaignQueryFmtstr,
+		c.UpdatedAt,
+		c.ID,
+		c.Name,
 		c.Name,
 		c.Description,
 		c.Branch,
@@ -1164,13 +1322,109 @@ func (s *Store) updateCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
 		changesetIDs,
 		nullInt64Column(c.CampaignPlanID),
 		nullTimeColumn(c.ClosedAt),
+		nullStringColumn(string(c.Spec)),
+		nullInt64Column(c.ParentCampaignID),
+		c.AutoRebase,
 		c.ID,
 	), nil
 }
 
-// DeleteCampaign deletes the Campaign with the given ID.
-func (s *Store) DeleteCampaign(ctx context.Context, id int64) error {
-	q := sqlf.Sprintf(deleteCampaignQueryFmtstr, id)
+// UpdateCampaignPosition sets the position of the given Campaign. Unlike
+// UpdateCampaign, it doesn't touch the other columns, so that reordering
+// campaigns on a board doesn't count as an update of them.
+func (s *Store) UpdateCampaignPosition(ctx context.Context, c *a8n.Campaign) (err error) {
+	ctx, done := observeStore(ctx, "UpdateCampaignPosition")
+	defer done(&err)
+
+	q := sqlf.Sprintf(updateCampaignPositionQueryFmtstr, c.Position, c.ID)
+
+	var found bool
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		found = true
+		err = sc.Scan(&last)
+		return last, 1, err
+	})
+	if err == nil && !found {
+		err = ErrNoResults
+	}
+	return err
+}
+
+var updateCampaignPositionQueryFmtstr = `
+-- source: internal/a8n/store.go:UpdateCampaignPosition
+UPDATE campaigns
+SET position = %s
+WHERE id = %s
+AND deleted_at IS NULL
+RETURNING id
+`
+
+// GetPreviousCampaignPositionOpts captures the query options needed for
+// getting the position preceding a given one.
+type GetPreviousCampaignPositionOpts struct {
+	State a8n.CampaignState
+	// If set, only positions lower than Position are considered.
+	Position string
+	// If set, the position of the campaign with this ID is ignored.
+	ExcludeID int64
+}
+
+// GetPreviousCampaignPosition returns the greatest position of the Campaigns
+// in the given state that is lower than opts.Position, or "" if there is
+// none.
+func (s *Store) GetPreviousCampaignPosition(ctx context.Context, opts GetPreviousCampaignPositionOpts) (position string, err error) {
+	ctx, done := observeStore(ctx, "GetPreviousCampaignPosition")
+	defer done(&err)
+
+	q := getPreviousCampaignPositionQuery(&opts)
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		return 0, 1, sc.Scan(&position)
+	})
+	return position, err
+}
+
+var getPreviousCampaignPositionQueryFmtstr = `
+-- source: internal/a8n/store.go:GetPreviousCampaignPosition
+SELECT position
+FROM campaigns
+WHERE %s
+ORDER BY position DESC
+LIMIT 1
+`
+
+func getPreviousCampaignPositionQuery(opts *GetPreviousCampaignPositionOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("deleted_at IS NULL"),
+	}
+
+	switch opts.State {
+	case a8n.CampaignStateOpen:
+		preds = append(preds, sqlf.Sprintf("closed_at IS NULL"))
+	case a8n.CampaignStateClosed:
+		preds = append(preds, sqlf.Sprintf("closed_at IS NOT NULL"))
+	}
+
+	if opts.Position != "" {
+		preds = append(preds, sqlf.Sprintf("position < %s", opts.Position))
+	}
+
+	if opts.ExcludeID != 0 {
+		preds = append(preds, sqlf.Sprintf("id <> %s", opts.ExcludeID))
+	}
+
+	return sqlf.Sprintf(getPreviousCampaignPositionQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
+// DeleteCampaign marks the Campaign with the given ID as deleted. Deleted
+// Campaigns are ignored by the other methods of the Store, unless stated
+// otherwise, until they are restored with RestoreCampaign or removed from the
+// database by PurgeDeletedCampaigns.
+func (s *Store) DeleteCampaign(ctx context.Context, id int64) (err error) {
+	ctx, done := observeStore(ctx, "DeleteCampaign")
+	defer done(&err)
+
+	q := sqlf.Sprintf(deleteCampaignQueryFmtstr, s.now(), id)
 
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
@@ -1181,7 +1435,41 @@ func (s *Store) DeleteCampaign(ctx context.Context, id int64) error {
 
 var deleteCampaignQueryFmtstr = `
 -- source: internal/a8n/store.go:DeleteCampaign
-DELETE FROM campaigns WHERE id = %s
+UPDATE campaigns SET deleted_at = %s WHERE id = %s AND deleted_at IS NULL
+`
+
+// RestoreCampaign restores the deleted Campaign with the given ID.
+func (s *Store) RestoreCampaign(ctx context.Context, id int64) error {
+	q := sqlf.Sprintf(restoreCampaignQueryFmtstr, id)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var restoreCampaignQueryFmtstr = `
+-- source: internal/a8n/store.go:RestoreCampaign
+UPDATE campaigns SET deleted_at = NULL WHERE id = %s
+`
+
+// PurgeDeletedCampaigns removes the Campaigns that were deleted more than
+// retention ago from the database. The triggers in the database remove their
+// IDs from the CampaignIDs of their Changesets.
+func (s *Store) PurgeDeletedCampaigns(ctx context.Context, retention time.Duration) error {
+	q := sqlf.Sprintf(purgeDeletedCampaignsQueryFmtstr, s.now().Add(-retention))
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var purgeDeletedCampaignsQueryFmtstr = `
+-- source: internal/a8n/store.go:PurgeDeletedCampaigns
+DELETE FROM campaigns WHERE deleted_at <= %s
 `
 
 // CountCampaignsOpts captures the query options needed for
@@ -1189,6 +1477,34 @@ DELETE FROM campaigns WHERE id = %s
 type CountCampaignsOpts struct {
 	ChangesetID int64
 	State       a8n.CampaignState
+
+	// If set, only campaigns whose name or description contains Query are
+	// counted.
+	Query string
+
+	// If either of these is set, only campaigns belonging to one of the
+	// given user or org namespaces are counted.
+	NamespaceUserIDs []int32
+	NamespaceOrgIDs  []int32
+
+	// If set, only campaigns with at least one open changeset are counted.
+	HasOpenChangesets bool
+	// If set, only campaigns with at least one changeset in this state are
+	// counted.
+	ChangesetState a8n.ChangesetState
+
+	// If set, only the direct children of the campaign with this ID are
+	// counted.
+	ParentCampaignID int64
+
+	// If set, only campaigns with activity that the user with this ID hasn't
+	// seen are counted.
+	UnreadByUserID int32
+
+	// If set, only campaigns that the user with this ID is involved in as
+	// InvolvedAs, or in any way if InvolvedAs is empty, are counted.
+	InvolvedUserID int32
+	InvolvedAs     a8n.CampaignInvolvement
 }
 
 // CountCampaigns returns the number of campaigns in the database.
@@ -1208,7 +1524,53 @@ WHERE %s
 `
 
 func countCampaignsQuery(opts *CountCampaignsOpts) *sqlf.Query {
-	var preds []*sqlf.Query
+	return sqlf.Sprintf(countCampaignsQueryFmtstr, sqlf.Join(countCampaignsPreds(opts), "\n AND "))
+}
+
+// EstimateCampaignsCount returns the number of campaigns that the query
+// planner of the database estimates to match the given options. Unlike
+// CountCampaigns, it doesn't scan the campaigns, so it's fast but can be off
+// by a large margin, for example when filtering by Query.
+func (s *Store) EstimateCampaignsCount(ctx context.Context, opts CountCampaignsOpts) (count int64, _ error) {
+	q := estimateCampaignsCountQuery(&opts)
+	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		var raw []byte
+		if err = sc.Scan(&raw); err != nil {
+			return 0, 0, err
+		}
+
+		var plans []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err = json.Unmarshal(raw, &plans); err != nil {
+			return 0, 0, errors.Wrap(err, "parsing query plan")
+		}
+		if len(plans) > 0 {
+			count = int64(plans[0].Plan.Rows)
+		}
+		return 0, count, nil
+	})
+}
+
+var estimateCampaignsCountQueryFmtstr = `
+-- source: internal/a8n/store.go:EstimateCampaignsCount
+EXPLAIN (FORMAT JSON)
+SELECT id
+FROM campaigns
+WHERE %s
+`
+
+func estimateCampaignsCountQuery(opts *CountCampaignsOpts) *sqlf.Query {
+	return sqlf.Sprintf(estimateCampaignsCountQueryFmtstr, sqlf.Join(countCampaignsPreds(opts), "\n AND "))
+}
+
+func countCampaignsPreds(opts *CountCampaignsOpts) []*sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("deleted_at IS NULL"),
+	}
+
 	if opts.ChangesetID != 0 {
 		preds = append(preds, sqlf.Sprintf("changeset_ids ? %s", opts.ChangesetID))
 	}
@@ -1220,17 +1582,159 @@ func countCampaignsQuery(opts *CountCampaignsOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("closed_at IS NOT NULL"))
 	}
 
-	if len(preds) == 0 {
-		preds = append(preds, sqlf.Sprintf("TRUE"))
+	if opts.Query != "" {
+		preds = append(preds, campaignQueryPred(opts.Query))
+	}
+
+	if len(opts.NamespaceUserIDs) > 0 || len(opts.NamespaceOrgIDs) > 0 {
+		preds = append(preds, campaignNamespacesPred(opts.NamespaceUserIDs, opts.NamespaceOrgIDs))
+	}
+
+	if opts.HasOpenChangesets {
+		preds = append(preds, campaignChangesetStatePred(a8n.ChangesetStateOpen))
+	}
+
+	if opts.ChangesetState != "" {
+		preds = append(preds, campaignChangesetStatePred(opts.ChangesetState))
+	}
+
+	if opts.ParentCampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("parent_campaign_id = %s", opts.ParentCampaignID))
+	}
+
+	if opts.UnreadByUserID != 0 {
+		preds = append(preds, campaignUnreadPred(opts.UnreadByUserID))
+	}
+
+	if opts.InvolvedUserID != 0 {
+		preds = append(preds, campaignInvolvementPred(opts.InvolvedUserID, opts.InvolvedAs))
+	}
+
+	return preds
+}
+
+// campaignQueryPred returns a predicate matching campaigns whose name or
+// description contains the given query.
+func campaignQueryPred(query string) *sqlf.Query {
+	query = "%" + query + "%"
+	return sqlf.Sprintf("(name ILIKE %s OR description ILIKE %s)", query, query)
+}
+
+// campaignNamespacesPred returns a predicate matching campaigns that belong
+// to any of the given user or org namespaces.
+func campaignNamespacesPred(userIDs, orgIDs []int32) *sqlf.Query {
+	var preds []*sqlf.Query
+	for _, ns := range []struct {
+		column string
+		ids    []int32
+	}{
+		{"namespace_user_id", userIDs},
+		{"namespace_org_id", orgIDs},
+	} {
+		if len(ns.ids) == 0 {
+			continue
+		}
+
+		ids := make([]*sqlf.Query, 0, len(ns.ids))
+		for _, id := range ns.ids {
+			ids = append(ids, sqlf.Sprintf("%d", id))
+		}
+		preds = append(preds, sqlf.Sprintf(ns.column+" IN (%s)", sqlf.Join(ids, ",")))
 	}
 
-	return sqlf.Sprintf(countCampaignsQueryFmtstr, sqlf.Join(preds, "\n AND "))
+	return sqlf.Sprintf("(%s)", sqlf.Join(preds, " OR "))
+}
+
+// campaignChangesetStatePred returns a predicate matching campaigns that have
+// at least one changeset in the given state.
+func campaignChangesetStatePred(state a8n.ChangesetState) *sqlf.Query {
+	return sqlf.Sprintf(campaignChangesetStatePredFmtstr, changesetStateExpr(), string(state))
+}
+
+var campaignChangesetStatePredFmtstr = `
+EXISTS (
+  SELECT 1
+  FROM changesets c
+  WHERE c.campaign_ids ? campaigns.id::text
+  AND %s = %s
+)
+`
+
+// changesetStateExpr returns an expression computing the state of the
+// changeset c from its metadata in the same way as Changeset.State.
+func changesetStateExpr() *sqlf.Query {
+	return sqlf.Sprintf(changesetStateExprFmtstr,
+		a8n.ChangesetStateDeleted,
+		github.ServiceType,
+		bitbucketserver.ServiceType, a8n.ChangesetStateClosed,
+	)
+}
+
+var changesetStateExprFmtstr = `
+CASE
+  WHEN c.external_deleted_at IS NOT NULL THEN %s
+  WHEN c.external_service_type = %s THEN c.metadata->>'State'
+  WHEN c.external_service_type = %s THEN
+    CASE c.metadata->>'state' WHEN 'DECLINED' THEN %s ELSE c.metadata->>'state' END
+END
+`
+
+// GetCampaignProgress returns the number of changesets in each state of the
+// Campaign with the given ID and of all the Campaigns below it in the
+// hierarchy of campaigns. Deleted Campaigns are skipped, along with the
+// Campaigns below them.
+func (s *Store) GetCampaignProgress(ctx context.Context, id int64) (*a8n.CampaignProgress, error) {
+	q := sqlf.Sprintf(getCampaignProgressQueryFmtstr,
+		id,
+		changesetStateExpr(),
+		a8n.ChangesetStateOpen,
+		a8n.ChangesetStateMerged,
+		a8n.ChangesetStateClosed,
+		a8n.ChangesetStateDeleted,
+	)
+
+	var p a8n.CampaignProgress
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 1, sc.Scan(&p.Total, &p.Open, &p.Merged, &p.Closed, &p.Deleted)
+	})
+	return &p, err
 }
 
+var getCampaignProgressQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignProgress
+WITH RECURSIVE tree AS (
+  SELECT id
+  FROM campaigns
+  WHERE id = %s AND deleted_at IS NULL
+  UNION
+  SELECT campaigns.id
+  FROM campaigns
+  JOIN tree ON campaigns.parent_campaign_id = tree.id
+  WHERE campaigns.deleted_at IS NULL
+),
+states AS (
+  SELECT %s AS state
+  FROM changesets c
+  WHERE EXISTS (SELECT 1 FROM tree WHERE c.campaign_ids ? tree.id::text)
+)
+SELECT
+  COUNT(*),
+  COUNT(*) FILTER (WHERE state = %s),
+  COUNT(*) FILTER (WHERE state = %s),
+  COUNT(*) FILTER (WHERE state = %s),
+  COUNT(*) FILTER (WHERE state = %s)
+FROM states
+`
+
 // GetCampaignOpts captures the query options needed for getting a Campaign
 type GetCampaignOpts struct {
-	ID             int64
-	CampaignPlanID int64
+	ID                   int64
+	CampaignPlanID       int64
+	RollbackOfCampaignID int64
+
+	// If set, only a Campaign that was deleted after DeletedAfter is
+	// returned, instead of only a Campaign that isn't deleted.
+	DeletedAfter time.Time
 }
 
 // GetCampaign gets a campaign matching the given options.
@@ -1266,7 +1770,12 @@ SELECT
   updated_at,
   changeset_ids,
   campaign_plan_id,
-  closed_at
+  closed_at,
+  spec,
+  rollback_of_campaign_id,
+  parent_campaign_id,
+  auto_rebase,
+  position
 FROM campaigns
 WHERE %s
 LIMIT 1
@@ -1282,46 +1791,51 @@ func getCampaignQuery(opts *GetCampaignOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("campaign_plan_id = %s", opts.CampaignPlanID))
 	}
 
-	if len(preds) == 0 {
-		preds = append(preds, sqlf.Sprintf("TRUE"))
+	if opts.RollbackOfCampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("rollback_of_campaign_id = %s", opts.RollbackOfCampaignID))
+	}
+
+	if opts.DeletedAfter.IsZero() {
+		preds = append(preds, sqlf.Sprintf("deleted_at IS NULL"))
+	} else {
+		preds = append(preds, sqlf.Sprintf("deleted_at > %s", opts.DeletedAfter))
 	}
 
 	return sqlf.Sprintf(getCampaignsQueryFmtstr, sqlf.Join(preds, "\n AND "))
 }
 
-// ListCampaignsOpts captures the query options needed for
-// listing campaigns.
-type ListCampaignsOpts struct {
-	ChangesetID int64
-	Cursor      int64
-	Limit       int
-	State       a8n.CampaignState
+// GetCampaignByNameOpts captures the query options needed for getting a
+// Campaign by its current or a previous name within a namespace.
+type GetCampaignByNameOpts struct {
+	Name            string
+	NamespaceUserID int32
+	NamespaceOrgID  int32
 }
 
-// ListCampaigns lists Campaigns with the given filters.
-func (s *Store) ListCampaigns(ctx context.Context, opts ListCampaignsOpts) (cs []*a8n.Campaign, next int64, err error) {
-	q := listCampaignsQuery(&opts)
+// GetCampaignByName gets the campaign in the given namespace that is
+// currently named opts.Name. If there is none, it falls back to the campaign
+// that was most recently renamed away from opts.Name, in which case renamed is
+// true.
+func (s *Store) GetCampaignByName(ctx context.Context, opts GetCampaignByNameOpts) (c *a8n.Campaign, renamed bool, err error) {
+	q := getCampaignByNameQuery(&opts)
 
-	cs = make([]*a8n.Campaign, 0, opts.Limit)
-	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
-		var c a8n.Campaign
-		if err = scanCampaign(&c, sc); err != nil {
-			return 0, 0, err
-		}
-		cs = append(cs, &c)
-		return c.ID, 1, err
+	var campaign a8n.Campaign
+	err = s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanCampaignByName(&campaign, &renamed, sc)
 	})
+	if err != nil {
+		return nil, false, err
+	}
 
-	if opts.Limit != 0 && len(cs) == opts.Limit {
-		next = cs[len(cs)-1].ID
-		cs = cs[:len(cs)-1]
+	if campaign.ID == 0 {
+		return nil, false, ErrNoResults
 	}
 
-	return cs, next, err
+	return &campaign, renamed, nil
 }
 
-var listCampaignsQueryFmtstr = `
--- source: internal/a8n/store.go:ListCampaigns
+var getCampaignByNameQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignByName
 SELECT
   id,
   name,
@@ -1334,10 +1848,171 @@ SELECT
   updated_at,
   changeset_ids,
   campaign_plan_id,
-  closed_at
+  closed_at,
+  spec,
+  rollback_of_campaign_id,
+  parent_campaign_id,
+  auto_rebase,
+  position,
+  renamed
+FROM (
+  SELECT
+    id,
+    name,
+    description,
+    branch,
+    author_id,
+    namespace_user_id,
+    namespace_org_id,
+    created_at,
+    updated_at,
+    changeset_ids,
+    campaign_plan_id,
+    closed_at,
+    spec,
+    rollback_of_campaign_id,
+    parent_campaign_id,
+    auto_rebase,
+    position,
+    FALSE AS renamed,
+    NULL::timestamptz AS renamed_at
+  FROM campaigns
+  WHERE deleted_at IS NULL AND %s
+  UNION ALL
+  SELECT
+    c.id,
+    c.name,
+    c.description,
+    c.branch,
+    c.author_id,
+    c.namespace_user_id,
+    c.namespace_org_id,
+    c.created_at,
+    c.updated_at,
+    c.changeset_ids,
+    c.campaign_plan_id,
+    c.closed_at,
+    c.spec,
+    c.rollback_of_campaign_id,
+    c.parent_campaign_id,
+    c.auto_rebase,
+    c.position,
+    TRUE AS renamed,
+    h.renamed_at
+  FROM campaign_name_history h
+  JOIN campaigns c ON c.id = h.campaign_id
+  WHERE c.deleted_at IS NULL AND %s
+) AS candidates
+ORDER BY renamed ASC, renamed_at DESC
+LIMIT 1
+`
+
+func getCampaignByNameQuery(opts *GetCampaignByNameOpts) *sqlf.Query {
+	return sqlf.Sprintf(
+		getCampaignByNameQueryFmtstr,
+		sqlf.Join(campaignNamePreds("", opts), "\n AND "),
+		sqlf.Join(campaignNamePreds("h.", opts), "\n AND "),
+	)
+}
+
+func campaignNamePreds(prefix string, opts *GetCampaignByNameOpts) []*sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf(prefix+"name = %s", opts.Name),
+	}
+
+	if opts.NamespaceUserID != 0 {
+		preds = append(preds, sqlf.Sprintf(prefix+"namespace_user_id = %s", opts.NamespaceUserID))
+	}
+
+	if opts.NamespaceOrgID != 0 {
+		preds = append(preds, sqlf.Sprintf(prefix+"namespace_org_id = %s", opts.NamespaceOrgID))
+	}
+
+	return preds
+}
+
+// ListCampaignsOpts captures the query options needed for
+// listing campaigns.
+type ListCampaignsOpts struct {
+	ChangesetID int64
+	Cursor      int64
+	Limit       int
+	State       a8n.CampaignState
+
+	// If set, only campaigns whose name or description contains Query are
+	// listed.
+	Query string
+
+	// If either of these is set, only campaigns belonging to one of the
+	// given user or org namespaces are listed.
+	NamespaceUserIDs []int32
+	NamespaceOrgIDs  []int32
+
+	// If set, only campaigns with at least one open changeset are listed.
+	HasOpenChangesets bool
+	// If set, only campaigns with at least one changeset in this state are
+	// listed.
+	ChangesetState a8n.ChangesetState
+
+	// If set, only the direct children of the campaign with this ID are
+	// listed.
+	ParentCampaignID int64
+
+	// If set, only campaigns that the user with this ID is involved in as
+	// InvolvedAs, or in any way if InvolvedAs is empty, are listed.
+	InvolvedUserID int32
+	InvolvedAs     a8n.CampaignInvolvement
+
+	// If set, campaigns are listed in the order of their positions (see
+	// MoveCampaign) instead of their IDs. The cursor is still an ID.
+	OrderByPosition bool
+}
+
+// ListCampaigns lists Campaigns with the given filters.
+func (s *Store) ListCampaigns(ctx context.Context, opts ListCampaignsOpts) (cs []*a8n.Campaign, next int64, err error) {
+	q := listCampaignsQuery(&opts)
+
+	cs = make([]*a8n.Campaign, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var c a8n.Campaign
+		if err = scanCampaign(&c, sc); err != nil {
+			return 0, 0, err
+		}
+		cs = append(cs, &c)
+		return c.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(cs) == opts.Limit {
+		next = cs[len(cs)-1].ID
+		cs = cs[:len(cs)-1]
+	}
+
+	return cs, next, err
+}
+
+var listCampaignsQueryFmtstr = `
+-- source: internal/a8n/store.go:ListCampaigns
+SELECT
+  id,
+  name,
+  description,
+  branch,
+  author_id,
+  namespace_user_id,
+  namespace_org_id,
+  created_at,
+  updated_at,
+  changeset_ids,
+  campaign_plan_id,
+  closed_at,
+  spec,
+  rollback_of_campaign_id,
+  parent_campaign_id,
+  auto_rebase,
+  position
 FROM campaigns
 WHERE %s
-ORDER BY id ASC
+ORDER BY %s
 LIMIT %s
 `
 
@@ -1348,7 +2023,17 @@ func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
 	opts.Limit++
 
 	preds := []*sqlf.Query{
-		sqlf.Sprintf("id >= %s", opts.Cursor),
+		sqlf.Sprintf("deleted_at IS NULL"),
+	}
+
+	orderBy := sqlf.Sprintf("id ASC")
+	if opts.OrderByPosition {
+		orderBy = sqlf.Sprintf("position ASC, id ASC")
+		if opts.Cursor != 0 {
+			preds = append(preds, sqlf.Sprintf("(position, id) >= (SELECT position, id FROM campaigns WHERE id = %s)", opts.Cursor))
+		}
+	} else {
+		preds = append(preds, sqlf.Sprintf("id >= %s", opts.Cursor))
 	}
 
 	if opts.ChangesetID != 0 {
@@ -1362,13 +2047,226 @@ func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("closed_at IS NOT NULL"))
 	}
 
+	if opts.Query != "" {
+		preds = append(preds, campaignQueryPred(opts.Query))
+	}
+
+	if len(opts.NamespaceUserIDs) > 0 || len(opts.NamespaceOrgIDs) > 0 {
+		preds = append(preds, campaignNamespacesPred(opts.NamespaceUserIDs, opts.NamespaceOrgIDs))
+	}
+
+	if opts.HasOpenChangesets {
+		preds = append(preds, campaignChangesetStatePred(a8n.ChangesetStateOpen))
+	}
+
+	if opts.ChangesetState != "" {
+		preds = append(preds, campaignChangesetStatePred(opts.ChangesetState))
+	}
+
+	if opts.ParentCampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("parent_campaign_id = %s", opts.ParentCampaignID))
+	}
+
+	if opts.InvolvedUserID != 0 {
+		preds = append(preds, campaignInvolvementPred(opts.InvolvedUserID, opts.InvolvedAs))
+	}
+
 	return sqlf.Sprintf(
 		listCampaignsQueryFmtstr,
 		sqlf.Join(preds, "\n AND "),
+		orderBy,
 		opts.Limit,
 	)
 }
 
+// GetCampaignFacetsOpts captures the query options needed for getting the
+// facets of Campaigns.
+type GetCampaignFacetsOpts struct {
+	// If either of these is set, only campaigns belonging to one of the
+	// given user or org namespaces are counted.
+	NamespaceUserIDs []int32
+	NamespaceOrgIDs  []int32
+}
+
+// GetCampaignFacets returns the number of Campaigns per state and per
+// author, computed in a single query.
+func (s *Store) GetCampaignFacets(ctx context.Context, opts GetCampaignFacetsOpts) (*a8n.CampaignFacets, error) {
+	q := getCampaignFacetsQuery(&opts)
+
+	facets := &a8n.CampaignFacets{
+		States: map[a8n.CampaignState]int32{
+			a8n.CampaignStateOpen:   0,
+			a8n.CampaignStateClosed: 0,
+		},
+		Authors: map[int32]int32{},
+	}
+
+	_, _, err := s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var (
+			byState  bool
+			open     sql.NullBool
+			authorID sql.NullInt64
+			n        int32
+		)
+		if err = sc.Scan(&byState, &open, &authorID, &n); err != nil {
+			return 0, 0, err
+		}
+
+		switch {
+		case byState && open.Bool:
+			facets.States[a8n.CampaignStateOpen] = n
+		case byState:
+			facets.States[a8n.CampaignStateClosed] = n
+		default:
+			facets.Authors[int32(authorID.Int64)] = n
+		}
+		return 0, 1, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+var getCampaignFacetsQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignFacets
+SELECT
+  GROUPING(closed_at IS NULL) = 0 AS by_state,
+  closed_at IS NULL AS open,
+  author_id,
+  COUNT(*)
+FROM campaigns
+WHERE %s
+GROUP BY GROUPING SETS ((closed_at IS NULL), (author_id))
+`
+
+func getCampaignFacetsQuery(opts *GetCampaignFacetsOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("deleted_at IS NULL"),
+	}
+
+	if len(opts.NamespaceUserIDs) > 0 || len(opts.NamespaceOrgIDs) > 0 {
+		preds = append(preds, campaignNamespacesPred(opts.NamespaceUserIDs, opts.NamespaceOrgIDs))
+	}
+
+	return sqlf.Sprintf(getCampaignFacetsQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
+// ListCampaignRepositoryActivityOpts captures the query options needed for
+// listing CampaignRepositoryActivity.
+type ListCampaignRepositoryActivityOpts struct {
+	// If either of these is set, only the Changesets of campaigns belonging
+	// to one of the given user or org namespaces are counted.
+	NamespaceUserIDs []int32
+	NamespaceOrgIDs  []int32
+
+	// If set, only the activity in these repositories is listed.
+	RepoIDs []api.RepoID
+}
+
+// ListCampaignRepositoryActivity returns the CampaignRepositoryActivity of
+// every repository with a Changeset of a Campaign matching the given
+// options, computed in a single query. Repositories are ordered by
+// descending number of open Changesets. The week starts on Monday.
+func (s *Store) ListCampaignRepositoryActivity(ctx context.Context, opts ListCampaignRepositoryActivityOpts) (as []*a8n.CampaignRepositoryActivity, err error) {
+	q := listCampaignRepositoryActivityQuery(&opts, s.now())
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var a a8n.CampaignRepositoryActivity
+		err = sc.Scan(
+			&a.RepoID,
+			&a.OpenCampaigns,
+			&a.OpenChangesets,
+			&a.ChangesetsMergedThisWeek,
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+		as = append(as, &a)
+		return int64(a.RepoID), 1, nil
+	})
+
+	return as, err
+}
+
+var listCampaignRepositoryActivityQueryFmtstr = `
+-- source: internal/a8n/store.go:ListCampaignRepositoryActivity
+WITH
+campaign_changesets AS (
+  SELECT
+    c.id AS changeset_id,
+    c.repo_id,
+    campaigns.id AS campaign_id,
+    campaigns.closed_at IS NULL AS campaign_open
+  FROM changesets c
+  JOIN campaigns ON c.campaign_ids ? campaigns.id::text
+  WHERE %s
+),
+open_campaigns AS (
+  SELECT repo_id, COUNT(DISTINCT campaign_id) AS count
+  FROM campaign_changesets
+  WHERE campaign_open
+  GROUP BY repo_id
+),
+changeset_states AS (
+  SELECT c.id, c.repo_id, %s AS state
+  FROM changesets c
+  WHERE c.id IN (SELECT changeset_id FROM campaign_changesets)
+),
+merged AS (
+  SELECT DISTINCT ce.changeset_id
+  FROM changeset_events ce
+  WHERE ce.changeset_id IN (SELECT changeset_id FROM campaign_changesets)
+  AND ce.kind IN (%s, %s)
+  AND CASE ce.kind
+    WHEN %s THEN (ce.metadata->>'CreatedAt')::timestamptz
+    WHEN %s THEN to_timestamp((ce.metadata->>'createdDate')::bigint / 1000.0)
+  END >= date_trunc('week', %s::timestamptz)
+)
+SELECT
+  s.repo_id,
+  COALESCE(MAX(oc.count), 0) AS open_campaigns,
+  COUNT(*) FILTER (WHERE s.state = %s) AS open_changesets,
+  COUNT(m.changeset_id) AS merged_this_week
+FROM changeset_states s
+LEFT JOIN open_campaigns oc ON oc.repo_id = s.repo_id
+LEFT JOIN merged m ON m.changeset_id = s.id
+GROUP BY s.repo_id
+ORDER BY open_changesets DESC, s.repo_id ASC
+`
+
+func listCampaignRepositoryActivityQuery(opts *ListCampaignRepositoryActivityOpts, now time.Time) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("campaigns.deleted_at IS NULL"),
+	}
+
+	if len(opts.NamespaceUserIDs) > 0 || len(opts.NamespaceOrgIDs) > 0 {
+		preds = append(preds, campaignNamespacesPred(opts.NamespaceUserIDs, opts.NamespaceOrgIDs))
+	}
+
+	if len(opts.RepoIDs) > 0 {
+		ids := make([]*sqlf.Query, 0, len(opts.RepoIDs))
+		for _, id := range opts.RepoIDs {
+			ids = append(ids, sqlf.Sprintf("%d", id))
+		}
+		preds = append(preds, sqlf.Sprintf("c.repo_id IN (%s)", sqlf.Join(ids, ",")))
+	}
+
+	githubMerged := string(a8n.ChangesetEventKindGitHubMerged)
+	bbsMerged := string(a8n.ChangesetEventKindBitbucketServerMerged)
+
+	return sqlf.Sprintf(
+		listCampaignRepositoryActivityQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+		changesetStateExpr(),
+		githubMerged, bbsMerged,
+		githubMerged, bbsMerged,
+		now,
+		a8n.ChangesetStateOpen,
+	)
+}
+
 // CreateCampaignPlan creates the given CampaignPlan.
 func (s *Store) CreateCampaignPlan(ctx context.Context, c *a8n.CampaignPlan) error {
 	q, err := s.createCampaignPlanQuery(c)
@@ -1674,6 +2572,126 @@ WHERE %s
 LIMIT 1
 `
 
+// GetCampaignMetricsOpts captures the query options needed for getting
+// campaign metrics.
+type GetCampaignMetricsOpts struct {
+	// Period is the length of each returned period.
+	Period a8n.CampaignMetricsPeriodType
+	// Periods is the number of periods to return, the last of which is the
+	// one containing Now.
+	Periods int
+	Now     time.Time
+}
+
+// GetCampaignMetrics returns the number of created Campaigns, the number of
+// merged Changesets and the median time to merge of those Changesets for
+// each of the last opts.Periods periods, oldest first. Periods without any
+// activity are included.
+func (s *Store) GetCampaignMetrics(ctx context.Context, opts GetCampaignMetricsOpts) (ps []*a8n.CampaignMetricsPeriod, err error) {
+	switch opts.Period {
+	case a8n.CampaignMetricsPeriodDay, a8n.CampaignMetricsPeriodWeek, a8n.CampaignMetricsPeriodMonth:
+	default:
+		return nil, fmt.Errorf("invalid campaign metrics period %q", opts.Period)
+	}
+
+	if opts.Periods <= 0 {
+		return []*a8n.CampaignMetricsPeriod{}, nil
+	}
+
+	q := getCampaignMetricsQuery(&opts)
+
+	ps = make([]*a8n.CampaignMetricsPeriod, 0, opts.Periods)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var (
+			p      a8n.CampaignMetricsPeriod
+			median sql.NullFloat64
+		)
+		if err = sc.Scan(&p.StartTime, &p.CampaignsCreated, &p.ChangesetsMerged, &median); err != nil {
+			return 0, 0, err
+		}
+		if median.Valid {
+			d := time.Duration(median.Float64 * float64(time.Second))
+			p.MedianTimeToMerge = &d
+		}
+		ps = append(ps, &p)
+		return 0, 1, nil
+	})
+
+	return ps, err
+}
+
+var getCampaignMetricsQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignMetrics
+WITH
+periods AS (
+  SELECT generate_series(
+    date_trunc(%s, %s::timestamptz) - %s::interval,
+    date_trunc(%s, %s::timestamptz),
+    %s::interval
+  ) AS start_time
+),
+created AS (
+  SELECT date_trunc(%s, created_at) AS start_time, COUNT(*) AS count
+  FROM campaigns
+  WHERE deleted_at IS NULL
+  GROUP BY 1
+),
+merges AS (
+  SELECT
+    date_trunc(%s, merged_at) AS start_time,
+    EXTRACT(EPOCH FROM merged_at - opened_at) AS seconds
+  FROM (
+    SELECT
+      CASE ce.kind
+        WHEN %s THEN (ce.metadata->>'CreatedAt')::timestamptz
+        WHEN %s THEN to_timestamp((ce.metadata->>'createdDate')::bigint / 1000.0)
+      END AS merged_at,
+      CASE c.external_service_type
+        WHEN %s THEN (c.metadata->>'CreatedAt')::timestamptz
+        WHEN %s THEN to_timestamp((c.metadata->>'createdDate')::bigint / 1000.0)
+      END AS opened_at
+    FROM changeset_events ce
+    JOIN changesets c ON c.id = ce.changeset_id
+    WHERE ce.kind IN (%s, %s)
+  ) m
+),
+merged AS (
+  SELECT
+    start_time,
+    COUNT(*) AS count,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds) AS median
+  FROM merges
+  GROUP BY 1
+)
+SELECT
+  periods.start_time,
+  COALESCE(created.count, 0),
+  COALESCE(merged.count, 0),
+  merged.median
+FROM periods
+LEFT JOIN created ON created.start_time = periods.start_time
+LEFT JOIN merged ON merged.start_time = periods.start_time
+ORDER BY periods.start_time ASC
+`
+
+func getCampaignMetricsQuery(opts *GetCampaignMetricsOpts) *sqlf.Query {
+	period := string(opts.Period)
+	githubMerged := string(a8n.ChangesetEventKindGitHubMerged)
+	bbsMerged := string(a8n.ChangesetEventKindBitbucketServerMerged)
+
+	return sqlf.Sprintf(
+		getCampaignMetricsQueryFmtstr,
+		period, opts.Now, fmt.Sprintf("%d %s", opts.Periods-1, period),
+		period, opts.Now,
+		"1 "+period,
+		period,
+		period,
+		githubMerged, bbsMerged,
+		github.ServiceType, bitbucketserver.ServiceType,
+		githubMerged, bbsMerged,
+	)
+}
+
 // ListCampaignPlansOpts captures the query options needed for
 // listing code mods.
 type ListCampaignPlansOpts struct {
@@ -1886,6 +2904,126 @@ var deleteCampaignJobQueryFmtstr = `
 DELETE FROM campaign_jobs WHERE id = %s
 `
 
+// maxCampaignJobLogChunkSize is the maximum size in bytes of the output of
+// a CampaignJobLogChunk. Larger outputs are split into multiple chunks.
+const maxCampaignJobLogChunkSize = 64 * 1024
+
+// AppendCampaignJobLog appends the given output to the log of the
+// CampaignJob with the given ID, which is written while the job is executed
+// on the server. The log is stored in CampaignJobLogChunks, so that it can be
+// read incrementally with ListCampaignJobLogChunks while it's written.
+func (s *Store) AppendCampaignJobLog(ctx context.Context, id int64, output string) error {
+	for _, chunk := range splitCampaignJobLog(output, maxCampaignJobLogChunkSize) {
+		q := sqlf.Sprintf(appendCampaignJobLogQueryFmtstr, chunk, s.now(), id)
+
+		_, count, err := s.query(ctx, q, func(sc scanner) (_, _ int64, err error) {
+			var id int64
+			err = sc.Scan(&id)
+			return id, 1, err
+		})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNoResults
+		}
+	}
+	return nil
+}
+
+var appendCampaignJobLogQueryFmtstr = `
+-- source: internal/a8n/store.go:AppendCampaignJobLog
+INSERT INTO campaign_job_log_chunks (campaign_job_id, output, created_at)
+SELECT id, %s, %s FROM campaign_jobs WHERE id = %s
+RETURNING id
+`
+
+// splitCampaignJobLog splits the given output into chunks of at most max
+// bytes, without splitting UTF-8 encoded runes.
+func splitCampaignJobLog(output string, max int) []string {
+	var chunks []string
+	for len(output) > max {
+		i := max
+		for i > 0 && !utf8.RuneStart(output[i]) {
+			i--
+		}
+		if i == 0 {
+			i = max
+		}
+		chunks = append(chunks, output[:i])
+		output = output[i:]
+	}
+	if output != "" {
+		chunks = append(chunks, output)
+	}
+	return chunks
+}
+
+// ListCampaignJobLogChunksOpts captures the query options needed for
+// listing the log chunks of a campaign job.
+type ListCampaignJobLogChunksOpts struct {
+	CampaignJobID int64
+	Cursor        int64
+	Limit         int
+}
+
+// ListCampaignJobLogChunks lists the CampaignJobLogChunks of a CampaignJob in
+// the order in which they were written.
+func (s *Store) ListCampaignJobLogChunks(ctx context.Context, opts ListCampaignJobLogChunksOpts) (cs []*a8n.CampaignJobLogChunk, next int64, err error) {
+	q := listCampaignJobLogChunksQuery(&opts)
+
+	cs = make([]*a8n.CampaignJobLogChunk, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var c a8n.CampaignJobLogChunk
+		if err = sc.Scan(&c.ID, &c.CampaignJobID, &c.Output, &c.CreatedAt); err != nil {
+			return 0, 0, err
+		}
+		cs = append(cs, &c)
+		return c.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(cs) == opts.Limit {
+		next = cs[len(cs)-1].ID
+		cs = cs[:len(cs)-1]
+	}
+
+	return cs, next, err
+}
+
+var listCampaignJobLogChunksQueryFmtstr = `
+-- source: internal/a8n/store.go:ListCampaignJobLogChunks
+SELECT
+  id,
+  campaign_job_id,
+  output,
+  created_at
+FROM campaign_job_log_chunks
+WHERE %s
+ORDER BY id ASC
+`
+
+func listCampaignJobLogChunksQuery(opts *ListCampaignJobLogChunksOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("campaign_job_id = %s", opts.CampaignJobID),
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	return sqlf.Sprintf(
+		listCampaignJobLogChunksQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
 // CountCampaignJobsOpts captures the query options needed for
 // counting campaign jobs
 type CountCampaignJobsOpts struct {
@@ -2108,7 +3246,8 @@ func onlyUnpublishedInCampaignQuery(campaignID int64) *sqlf.Query {
 	return sqlf.Sprintf(onlyUnpublishedInCampaignQueryFmtstr, campaignID)
 }
 
-// CreateChangesetJob creates the given ChangesetJob.
+// CreateChangesetJob creates the given ChangesetJob. Unless it has already
+// been started, a WorkerJob that runs it is enqueued in ChangesetJobsQueue.
 func (s *Store) CreateChangesetJob(ctx context.Context, c *a8n.ChangesetJob) error {
 	q, err := s.createChangesetJobQuery(c)
 	if err != nil {
@@ -2123,29 +3262,58 @@ func (s *Store) CreateChangesetJob(ctx context.Context, c *a8n.ChangesetJob) err
 
 var createChangesetJobQueryFmtstr = `
 -- source: internal/a8n/store.go:CreateChangesetJob
-INSERT INTO changeset_jobs (
-  campaign_id,
-  campaign_job_id,
-  changeset_id,
-  branch,
-  error,
-  started_at,
-  finished_at,
-  created_at,
-  updated_at
+WITH job AS (
+  INSERT INTO changeset_jobs (
+    campaign_id,
+    campaign_job_id,
+    changeset_id,
+    branch,
+    branch_collision,
+    error,
+    started_at,
+    finished_at,
+    reviewers_requested_at,
+    reviewers_error,
+    created_at,
+    updated_at
+  )
+  VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+  RETURNING
+    id,
+    campaign_id,
+    campaign_job_id,
+    changeset_id,
+    branch,
+    branch_collision,
+    error,
+    started_at,
+    finished_at,
+    reviewers_requested_at,
+    reviewers_error,
+    created_at,
+    updated_at
+),
+queued AS (
+  INSERT INTO campaign_worker_jobs (queue, payload, run_after, created_at, updated_at)
+  SELECT %s, jsonb_build_object('changeset_job_id', job.id), job.created_at, job.created_at, job.created_at
+  FROM job
+  WHERE job.started_at IS NULL
 )
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s)
-RETURNING
+SELECT
   id,
   campaign_id,
   campaign_job_id,
   changeset_id,
   branch,
+  branch_collision,
   error,
   started_at,
   finished_at,
+  reviewers_requested_at,
+  reviewers_error,
   created_at,
   updated_at
+FROM job
 `
 
 func (s *Store) createChangesetJobQuery(c *a8n.ChangesetJob) (*sqlf.Query, error) {
@@ -2163,11 +3331,15 @@ func (s *Store) createChangesetJobQuery(c *a8n.ChangesetJob) (*sqlf.Query, error
 		c.CampaignJobID,
 		nullInt64Column(c.ChangesetID),
 		c.Branch,
+		nullStringColumn(string(c.BranchCollision)),
 		nullStringColumn(c.Error),
 		nullTimeColumn(c.StartedAt),
 		nullTimeColumn(c.FinishedAt),
+		nullTimeColumn(c.ReviewersRequestedAt),
+		nullStringColumn(c.ReviewersError),
 		c.CreatedAt,
 		c.UpdatedAt,
+		ChangesetJobsQueue,
 	), nil
 }
 
@@ -2192,11 +3364,14 @@ SET (
   campaign_job_id,
   changeset_id,
   branch,
+  branch_collision,
   error,
   started_at,
   finished_at,
+  reviewers_requested_at,
+  reviewers_error,
   updated_at
-) = (%s, %s, %s, %s, %s, %s, %s, %s)
+) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   id,
@@ -2204,9 +3379,12 @@ RETURNING
   campaign_job_id,
   changeset_id,
   branch,
+  branch_collision,
   error,
   started_at,
   finished_at,
+  reviewers_requested_at,
+  reviewers_error,
   created_at,
   updated_at
 `
@@ -2220,9 +3398,12 @@ func (s *Store) updateChangesetJobQuery(c *a8n.ChangesetJob) (*sqlf.Query, error
 		c.CampaignJobID,
 		nullInt64Column(c.ChangesetID),
 		c.Branch,
+		nullStringColumn(string(c.BranchCollision)),
 		nullStringColumn(c.Error),
 		nullTimeColumn(c.StartedAt),
 		nullTimeColumn(c.FinishedAt),
+		nullTimeColumn(c.ReviewersRequestedAt),
+		nullStringColumn(c.ReviewersError),
 		c.UpdatedAt,
 		c.ID,
 	), nil
@@ -2342,9 +3523,12 @@ SELECT
   campaign_job_id,
   changeset_id,
   branch,
+  branch_collision,
   error,
   started_at,
   finished_at,
+  reviewers_requested_at,
+  reviewers_error,
   created_at,
   updated_at
 FROM changeset_jobs
@@ -2416,9 +3600,12 @@ SELECT
   changeset_jobs.campaign_job_id,
   changeset_jobs.changeset_id,
   changeset_jobs.branch,
+  changeset_jobs.branch_collision,
   changeset_jobs.error,
   changeset_jobs.started_at,
   changeset_jobs.finished_at,
+  changeset_jobs.reviewers_requested_at,
+  changeset_jobs.reviewers_error,
   changeset_jobs.created_at,
   changeset_jobs.updated_at
 FROM changeset_jobs
@@ -2463,9 +3650,9 @@ func listChangesetJobsQuery(opts *ListChangesetJobsOpts) *sqlf.Query {
 
 // ResetFailedChangesetJobs resets the Error, StartedAt and FinishedAt fields
 // of the ChangesetJobs belonging to the Campaign with the given ID that
-// resulted in an error.
+// resulted in an error, and enqueues them in ChangesetJobsQueue again.
 func (s *Store) ResetFailedChangesetJobs(ctx context.Context, campaignID int64) (err error) {
-	q := resetChangesetJobsQuery(campaignID, true)
+	q := s.resetChangesetJobsQuery(campaignID, true)
 
 	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
 		return 0, 1, nil
@@ -2473,16 +3660,17 @@ func (s *Store) ResetFailedChangesetJobs(ctx context.Context, campaignID int64)
 }
 
 // ResetChangesetJobs resets the Error, StartedAt and FinishedAt fields
-// of all ChangesetJobs belonging to the Campaign with the given ID.
+// of all ChangesetJobs belonging to the Campaign with the given ID, and
+// enqueues them in ChangesetJobsQueue again.
 func (s *Store) ResetChangesetJobs(ctx context.Context, campaignID int64) (err error) {
-	q := resetChangesetJobsQuery(campaignID, false)
+	q := s.resetChangesetJobsQuery(campaignID, false)
 
 	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
 		return 0, 1, nil
 	})
 }
 
-func resetChangesetJobsQuery(campaignID int64, onlyErrored bool) *sqlf.Query {
+func (s *Store) resetChangesetJobsQuery(campaignID int64, onlyErrored bool) *sqlf.Query {
 	preds := []*sqlf.Query{
 		sqlf.Sprintf("campaign_id = %s", campaignID),
 	}
@@ -2491,69 +3679,2196 @@ func resetChangesetJobsQuery(campaignID int64, onlyErrored bool) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("error != ''"))
 	}
 
+	now := s.now()
+
+	return sqlf.Sprintf(
+		resetChangesetJobsQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+		ChangesetJobsQueue,
+		now,
+		now,
+		now,
+	)
+}
+
+var resetChangesetJobsQueryFmtstr = `
+-- source: internal/a8n/store.go:resetChangesetJobsQuery
+WITH reset AS (
+  UPDATE changeset_jobs
+  SET
+    error = '',
+    started_at = NULL,
+    finished_at = NULL
+  WHERE %s
+  RETURNING id
+)
+INSERT INTO campaign_worker_jobs (queue, payload, run_after, created_at, updated_at)
+SELECT %s, jsonb_build_object('changeset_job_id', reset.id), %s, %s, %s
+FROM reset
+`
+
+// CreateChangesetCloseJob creates the given ChangesetCloseJob. If a
+// ChangesetCloseJob for the same Campaign and Changeset already exists, it is
+// reset so that it will be picked up again by RunChangesetCloseJobs.
+func (s *Store) CreateChangesetCloseJob(ctx context.Context, c *a8n.ChangesetCloseJob) error {
+	q := s.createChangesetCloseJobQuery(c)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetCloseJob(c, sc)
+		return c.ID, 1, err
+	})
+}
+
+var createChangesetCloseJobQueryFmtstr = `
+-- source: internal/a8n/store.go:CreateChangesetCloseJob
+INSERT INTO changeset_close_jobs (
+  campaign_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (campaign_id, changeset_id) DO UPDATE
+SET
+  error = excluded.error,
+  started_at = excluded.started_at,
+  finished_at = excluded.finished_at,
+  updated_at = excluded.updated_at
+RETURNING
+  id,
+  campaign_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+func (s *Store) createChangesetCloseJobQuery(c *a8n.ChangesetCloseJob) *sqlf.Query {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = s.now()
+	}
+
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = c.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createChangesetCloseJobQueryFmtstr,
+		c.CampaignID,
+		c.ChangesetID,
+		nullStringColumn(c.Error),
+		nullTimeColumn(c.StartedAt),
+		nullTimeColumn(c.FinishedAt),
+		c.CreatedAt,
+		c.UpdatedAt,
+	)
+}
+
+// UpdateChangesetCloseJob updates the given ChangesetCloseJob.
+func (s *Store) UpdateChangesetCloseJob(ctx context.Context, c *a8n.ChangesetCloseJob) error {
+	c.UpdatedAt = s.now()
+
+	q := sqlf.Sprintf(
+		updateChangesetCloseJobQueryFmtstr,
+		c.CampaignID,
+		c.ChangesetID,
+		nullStringColumn(c.Error),
+		nullTimeColumn(c.StartedAt),
+		nullTimeColumn(c.FinishedAt),
+		c.UpdatedAt,
+		c.ID,
+	)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetCloseJob(c, sc)
+		return c.ID, 1, err
+	})
+}
+
+var updateChangesetCloseJobQueryFmtstr = `
+-- source: internal/a8n/store.go:UpdateChangesetCloseJob
+UPDATE changeset_close_jobs
+SET (
+  campaign_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s)
+WHERE id = %s
+RETURNING
+  id,
+  campaign_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
+// ListChangesetCloseJobsOpts captures the query options needed for
+// listing changeset close jobs.
+type ListChangesetCloseJobsOpts struct {
+	CampaignID int64
+	Cursor     int64
+	Limit      int
+}
+
+// ListChangesetCloseJobs lists ChangesetCloseJobs with the given filters.
+func (s *Store) ListChangesetCloseJobs(ctx context.Context, opts ListChangesetCloseJobsOpts) (cs []*a8n.ChangesetCloseJob, next int64, err error) {
+	q := listChangesetCloseJobsQuery(&opts)
+
+	cs = make([]*a8n.ChangesetCloseJob, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var c a8n.ChangesetCloseJob
+		if err = scanChangesetCloseJob(&c, sc); err != nil {
+			return 0, 0, err
+		}
+		cs = append(cs, &c)
+		return c.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(cs) == opts.Limit {
+		next = cs[len(cs)-1].ID
+		cs = cs[:len(cs)-1]
+	}
+
+	return cs, next, err
+}
+
+var listChangesetCloseJobsQueryFmtstr = `
+-- source: internal/a8n/store.go:ListChangesetCloseJobs
+SELECT
+  id,
+  campaign_id,
+  changeset_id,
+  error,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+FROM changeset_close_jobs
+WHERE %s
+ORDER BY id ASC
+`
+
+func listChangesetCloseJobsQuery(opts *ListChangesetCloseJobsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.CampaignID != 0 {
+		preds = append(preds, sqlf.Sprintf("campaign_id = %s", opts.CampaignID))
+	}
+
+	return sqlf.Sprintf(
+		listChangesetCloseJobsQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// ProcessPendingChangesetCloseJob attempts to fetch one pending changeset
+// close job. If found, 'process' is called. We guarantee that if process is
+// called it will have exclusive global access to the job. All operations on
+// the job should be done using the supplied store as they will run in a
+// transaction. Returning an error will roll back the transaction.
+// NOTE: It should not be called from within an existing transaction
+func (s *Store) ProcessPendingChangesetCloseJob(ctx context.Context, process func(ctx context.Context, s *Store, job a8n.ChangesetCloseJob) error) (didRun bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "starting transaction")
+	}
+	defer tx.Done(&err)
+	q := sqlf.Sprintf(getPendingChangesetCloseJobQuery)
+	var job a8n.ChangesetCloseJob
+	_, count, err := tx.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanChangesetCloseJob(&job, sc)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "scanning changeset close job row")
+		}
+		return job.ID, 1, nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "querying for pending changeset close job")
+	}
+	if count == 0 {
+		return false, nil
+	}
+	err = process(ctx, tx, job)
+	return true, err
+}
+
+const getPendingChangesetCloseJobQuery = `
+UPDATE changeset_close_jobs j SET started_at = now() WHERE id = (
+	SELECT j.id FROM changeset_close_jobs j
+	WHERE j.started_at IS NULL
+	ORDER BY j.id ASC
+	FOR UPDATE SKIP LOCKED LIMIT 1
+)
+RETURNING j.id,
+  j.campaign_id,
+  j.changeset_id,
+  j.error,
+  j.started_at,
+  j.finished_at,
+  j.created_at,
+  j.updated_at
+`
+
+// GetCampaignCloseStatus gets the a8n.BackgroundProcessStatus of closing the
+// changesets of a Campaign on their codehosts.
+func (s *Store) GetCampaignCloseStatus(ctx context.Context, id int64) (*a8n.BackgroundProcessStatus, error) {
+	return s.queryBackgroundProcessStatus(ctx, sqlf.Sprintf(
+		getCampaignCloseStatusQueryFmtstr,
+		sqlf.Sprintf("campaign_id = %s", id),
+	))
+}
+
+var getCampaignCloseStatusQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignCloseStatus
+SELECT
+  -- canceled is here so that this can be used with scanBackgroundProcessStatus
+  false AS canceled,
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE finished_at IS NULL) AS pending,
+  COUNT(*) FILTER (WHERE finished_at IS NOT NULL) AS completed,
+  array_agg(error) FILTER (WHERE error != '') AS errors
+FROM changeset_close_jobs
+WHERE %s
+LIMIT 1
+`
+
+// GetGithubExternalIDForRefs allows us to find the external id for GitHub pull requests based on
+// a slice of head refs. We need this in order to match incoming status webhooks to pull requests as
+// the only information they provide is the remote branch
+func (s *Store) GetGithubExternalIDForRefs(ctx context.Context, refs []string) ([]string, error) {
+	queryFmtString := `
+SELECT external_id FROM changesets
+WHERE external_service_type = 'github'
+AND external_branch IN (%s)
+ORDER BY id ASC
+`
+	inClause := make([]*sqlf.Query, 0, len(refs))
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+		inClause = append(inClause, sqlf.Sprintf("%s", ref))
+	}
+	q := sqlf.Sprintf(queryFmtString, sqlf.Join(inClause, ","))
+	ids := make([]string, 0, len(refs))
+	_, _, err := s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var s string
+		err = sc.Scan(&s)
+		if err != nil {
+			return 0, 0, err
+		}
+		ids = append(ids, s)
+		return 0, 1, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *Store) exec(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
+	_, _, err := s.query(ctx, q, sc)
+	return err
+}
+
+func (s *Store) query(ctx context.Context, q *sqlf.Query, sc scanFunc) (last, count int64, err error) {
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return 0, 0, err
+	}
+	return scanAll(rows, sc)
+}
+
+// IdempotencyKeyTTL is the duration for which idempotency keys are stored,
+// and thus the duration in which retried mutations aren't run again.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// CreateIdempotencyKey creates the given IdempotencyKey. If it already
+// exists, it's not modified and false is returned.
+func (s *Store) CreateIdempotencyKey(ctx context.Context, k *a8n.IdempotencyKey) (created bool, err error) {
+	q, err := s.createIdempotencyKeyQuery(k)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		created = true
+		return 0, 1, sc.Scan(&k.CreatedAt)
+	})
+	return created, err
+}
+
+var createIdempotencyKeyQueryFmtstr = `
+-- source: internal/a8n/store.go:CreateIdempotencyKey
+INSERT INTO campaign_idempotency_keys (
+  user_id,
+  mutation,
+  key,
+  result_ids,
+  created_at
+)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT DO NOTHING
+RETURNING created_at
+`
+
+func (s *Store) createIdempotencyKeyQuery(k *a8n.IdempotencyKey) (*sqlf.Query, error) {
+	resultIDs, err := nullJSONArrayColumn(k.ResultIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = s.now()
+	}
+
+	return sqlf.Sprintf(
+		createIdempotencyKeyQueryFmtstr,
+		k.UserID,
+		k.Mutation,
+		k.Key,
+		resultIDs,
+		k.CreatedAt,
+	), nil
+}
+
+// GetIdempotencyKeyOpts captures the query options needed for getting an
+// IdempotencyKey.
+type GetIdempotencyKeyOpts struct {
+	UserID   int32
+	Mutation string
+	Key      string
+}
+
+// GetIdempotencyKey gets the IdempotencyKey matching the given options. It
+// returns ErrNoResults if it doesn't exist or is expired.
+func (s *Store) GetIdempotencyKey(ctx context.Context, opts GetIdempotencyKeyOpts) (*a8n.IdempotencyKey, error) {
+	q := sqlf.Sprintf(
+		getIdempotencyKeyQueryFmtstr,
+		opts.UserID,
+		opts.Mutation,
+		opts.Key,
+		s.now().Add(-IdempotencyKeyTTL),
+	)
+
+	var k *a8n.IdempotencyKey
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		k = &a8n.IdempotencyKey{}
+		return 0, 0, scanIdempotencyKey(k, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if k == nil {
+		return nil, ErrNoResults
+	}
+
+	return k, nil
+}
+
+var getIdempotencyKeyQueryFmtstr = `
+-- source: internal/a8n/store.go:GetIdempotencyKey
+SELECT
+  user_id,
+  mutation,
+  key,
+  result_ids,
+  created_at
+FROM campaign_idempotency_keys
+WHERE user_id = %s
+AND mutation = %s
+AND key = %s
+AND created_at > %s
+`
+
+// UpdateIdempotencyKeyResult sets the ResultIDs of the given IdempotencyKey.
+func (s *Store) UpdateIdempotencyKeyResult(ctx context.Context, k *a8n.IdempotencyKey) error {
+	resultIDs, err := nullJSONArrayColumn(k.ResultIDs)
+	if err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(
+		updateIdempotencyKeyResultQueryFmtstr,
+		resultIDs,
+		k.UserID,
+		k.Mutation,
+		k.Key,
+	)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var updateIdempotencyKeyResultQueryFmtstr = `
+-- source: internal/a8n/store.go:UpdateIdempotencyKeyResult
+UPDATE campaign_idempotency_keys
+SET result_ids = %s
+WHERE user_id = %s
+AND mutation = %s
+AND key = %s
+`
+
+// DeleteIdempotencyKey deletes the IdempotencyKey matching the given
+// options.
+func (s *Store) DeleteIdempotencyKey(ctx context.Context, opts GetIdempotencyKeyOpts) error {
+	q := sqlf.Sprintf(deleteIdempotencyKeyQueryFmtstr, opts.UserID, opts.Mutation, opts.Key)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteIdempotencyKeyQueryFmtstr = `
+-- source: internal/a8n/store.go:DeleteIdempotencyKey
+DELETE FROM campaign_idempotency_keys
+WHERE user_id = %s
+AND mutation = %s
+AND key = %s
+`
+
+// DeleteExpiredIdempotencyKeys deletes the IdempotencyKeys that were created
+// more than IdempotencyKeyTTL ago.
+func (s *Store) DeleteExpiredIdempotencyKeys(ctx context.Context) error {
+	q := sqlf.Sprintf(deleteExpiredIdempotencyKeysQueryFmtstr, s.now().Add(-IdempotencyKeyTTL))
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteExpiredIdempotencyKeysQueryFmtstr = `
+-- source: internal/a8n/store.go:DeleteExpiredIdempotencyKeys
+DELETE FROM campaign_idempotency_keys
+WHERE created_at <= %s
+`
+
+func scanIdempotencyKey(k *a8n.IdempotencyKey, s scanner) error {
+	var resultIDs []byte
+
+	err := s.Scan(
+		&k.UserID,
+		&k.Mutation,
+		&k.Key,
+		&resultIDs,
+		&k.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if resultIDs == nil {
+		return nil
+	}
+	return json.Unmarshal(resultIDs, &k.ResultIDs)
+}
+
+func nullJSONArrayColumn(ids []int64) (*string, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	bs, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	s := string(bs)
+	return &s, nil
+}
+
+// SaveCampaignSavedFilter creates the given CampaignSavedFilter or, if its
+// user already has one with the same name, overwrites the filters of that
+// one. If the CampaignSavedFilter is the default one, the previous default
+// of the user is unset.
+func (s *Store) SaveCampaignSavedFilter(ctx context.Context, f *a8n.CampaignSavedFilter) (err error) {
+	filters, err := json.Marshal(f.Filters)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Done(&err)
+
+	if f.IsDefault {
+		if err = tx.unsetDefaultCampaignSavedFilter(ctx, f.UserID, sqlf.Sprintf("name <> %s", f.Name)); err != nil {
+			return err
+		}
+	}
+
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = tx.now()
+	}
+	f.UpdatedAt = tx.now()
+
+	q := sqlf.Sprintf(
+		saveCampaignSavedFilterQueryFmtstr,
+		f.UserID,
+		f.Name,
+		filters,
+		f.IsDefault,
+		f.CreatedAt,
+		f.UpdatedAt,
+	)
+
+	return tx.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignSavedFilter(f, sc)
+		return f.ID, 1, err
+	})
+}
+
+var saveCampaignSavedFilterQueryFmtstr = `
+-- source: internal/a8n/store.go:SaveCampaignSavedFilter
+INSERT INTO campaign_saved_filters (
+  user_id,
+  name,
+  filters,
+  is_default,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s)
+ON CONFLICT ON CONSTRAINT campaign_saved_filters_user_id_name_unique DO UPDATE SET
+  filters = excluded.filters,
+  is_default = excluded.is_default,
+  updated_at = excluded.updated_at
+RETURNING
+  id,
+  user_id,
+  name,
+  filters,
+  is_default,
+  created_at,
+  updated_at
+`
+
+// SetDefaultCampaignSavedFilter makes the CampaignSavedFilter with the given
+// ID the default one of the given user. If id is 0, the user is left without
+// a default CampaignSavedFilter. It returns ErrNoResults if the user has no
+// CampaignSavedFilter with the given ID.
+func (s *Store) SetDefaultCampaignSavedFilter(ctx context.Context, userID int32, id int64) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Done(&err)
+
+	if err = tx.unsetDefaultCampaignSavedFilter(ctx, userID, sqlf.Sprintf("id <> %s", id)); err != nil {
+		return err
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	q := sqlf.Sprintf(setDefaultCampaignSavedFilterQueryFmtstr, id, userID)
+	_, count, err := tx.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = sc.Scan(&last)
+		return last, 1, err
+	})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrNoResults
+	}
+
+	return nil
+}
+
+var setDefaultCampaignSavedFilterQueryFmtstr = `
+-- source: internal/a8n/store.go:SetDefaultCampaignSavedFilter
+UPDATE campaign_saved_filters
+SET is_default = TRUE
+WHERE id = %s
+AND user_id = %s
+RETURNING id
+`
+
+// unsetDefaultCampaignSavedFilter unsets the default CampaignSavedFilter of
+// the given user, if it matches the given predicate. It runs before a new
+// default is set, since the unique index on the default filters of a user
+// is checked row by row.
+func (s *Store) unsetDefaultCampaignSavedFilter(ctx context.Context, userID int32, pred *sqlf.Query) error {
+	q := sqlf.Sprintf(unsetDefaultCampaignSavedFilterQueryFmtstr, userID, pred)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var unsetDefaultCampaignSavedFilterQueryFmtstr = `
+-- source: internal/a8n/store.go:unsetDefaultCampaignSavedFilter
+UPDATE campaign_saved_filters
+SET is_default = FALSE
+WHERE user_id = %s
+AND is_default
+AND %s
+`
+
+// DeleteCampaignSavedFilter deletes the CampaignSavedFilter with the given ID.
+func (s *Store) DeleteCampaignSavedFilter(ctx context.Context, id int64) error {
+	q := sqlf.Sprintf(deleteCampaignSavedFilterQueryFmtstr, id)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteCampaignSavedFilterQueryFmtstr = `
+-- source: internal/a8n/store.go:DeleteCampaignSavedFilter
+DELETE FROM campaign_saved_filters WHERE id = %s
+`
+
+// GetCampaignSavedFilter gets the CampaignSavedFilter with the given ID. It
+// returns ErrNoResults if it doesn't exist.
+func (s *Store) GetCampaignSavedFilter(ctx context.Context, id int64) (*a8n.CampaignSavedFilter, error) {
+	q := sqlf.Sprintf(getCampaignSavedFilterQueryFmtstr, id)
+
+	var f a8n.CampaignSavedFilter
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanCampaignSavedFilter(&f, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if f.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &f, nil
+}
+
+var getCampaignSavedFilterQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignSavedFilter
+SELECT
+  id,
+  user_id,
+  name,
+  filters,
+  is_default,
+  created_at,
+  updated_at
+FROM campaign_saved_filters
+WHERE id = %s
+LIMIT 1
+`
+
+// ListCampaignSavedFiltersOpts captures the query options needed for
+// listing CampaignSavedFilters.
+type ListCampaignSavedFiltersOpts struct {
+	UserID int32
+}
+
+// ListCampaignSavedFilters lists the CampaignSavedFilters of a user, ordered
+// by name. Users save few of them, so they're not paginated.
+func (s *Store) ListCampaignSavedFilters(ctx context.Context, opts ListCampaignSavedFiltersOpts) (fs []*a8n.CampaignSavedFilter, err error) {
+	q := sqlf.Sprintf(listCampaignSavedFiltersQueryFmtstr, opts.UserID)
+
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var f a8n.CampaignSavedFilter
+		if err = scanCampaignSavedFilter(&f, sc); err != nil {
+			return 0, 0, err
+		}
+		fs = append(fs, &f)
+		return f.ID, 1, nil
+	})
+
+	return fs, err
+}
+
+var listCampaignSavedFiltersQueryFmtstr = `
+-- source: internal/a8n/store.go:ListCampaignSavedFilters
+SELECT
+  id,
+  user_id,
+  name,
+  filters,
+  is_default,
+  created_at,
+  updated_at
+FROM campaign_saved_filters
+WHERE user_id = %s
+ORDER BY name ASC
+`
+
+func scanCampaignSavedFilter(f *a8n.CampaignSavedFilter, s scanner) error {
+	var filters []byte
+
+	err := s.Scan(
+		&f.ID,
+		&f.UserID,
+		&f.Name,
+		&filters,
+		&f.IsDefault,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	f.Filters = a8n.CampaignListFilters{}
+	return json.Unmarshal(filters, &f.Filters)
+}
+
+// UpsertCampaignNamespaceSettings creates the given CampaignNamespaceSettings
+// or, if its namespace already has CampaignNamespaceSettings, updates them.
+func (s *Store) UpsertCampaignNamespaceSettings(ctx context.Context, ns *a8n.CampaignNamespaceSettings) error {
+	labels := ns.DefaultLabels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	defaultLabels, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+
+	constraint := "campaign_namespace_settings_namespace_user_id_unique"
+	if ns.NamespaceOrgID != 0 {
+		constraint = "campaign_namespace_settings_namespace_org_id_unique"
+	}
+
+	channel := ns.NotificationChannel
+	if channel == "" {
+		channel = a8n.CampaignNotificationChannelEmail
+	}
+
+	if ns.CreatedAt.IsZero() {
+		ns.CreatedAt = s.now()
+	}
+	ns.UpdatedAt = s.now()
+
+	q := sqlf.Sprintf(
+		upsertCampaignNamespaceSettingsQueryFmtstr,
+		nullInt32Column(ns.NamespaceUserID),
+		nullInt32Column(ns.NamespaceOrgID),
+		ns.BranchPrefix,
+		defaultLabels,
+		ns.RequireApproval,
+		string(channel),
+		ns.NotificationWebhookURL,
+		ns.CreatedAt,
+		ns.UpdatedAt,
+		sqlf.Sprintf(constraint),
+	)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignNamespaceSettings(ns, sc)
+		return ns.ID, 1, err
+	})
+}
+
+var upsertCampaignNamespaceSettingsQueryFmtstr = `
+-- source: internal/a8n/store.go:UpsertCampaignNamespaceSettings
+INSERT INTO campaign_namespace_settings (
+  namespace_user_id,
+  namespace_org_id,
+  branch_prefix,
+  default_labels,
+  require_approval,
+  notification_channel,
+  notification_webhook_url,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT ON CONSTRAINT %s DO UPDATE SET
+  branch_prefix = excluded.branch_prefix,
+  default_labels = excluded.default_labels,
+  require_approval = excluded.require_approval,
+  notification_channel = excluded.notification_channel,
+  notification_webhook_url = excluded.notification_webhook_url,
+  updated_at = excluded.updated_at
+RETURNING
+  id,
+  namespace_user_id,
+  namespace_org_id,
+  branch_prefix,
+  default_labels,
+  require_approval,
+  notification_channel,
+  notification_webhook_url,
+  created_at,
+  updated_at
+`
+
+// GetCampaignNamespaceSettingsOpts captures the query options needed for
+// getting the CampaignNamespaceSettings of a namespace.
+type GetCampaignNamespaceSettingsOpts struct {
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+// GetCampaignNamespaceSettings gets the CampaignNamespaceSettings of the given
+// namespace. It returns ErrNoResults if the namespace has none.
+func (s *Store) GetCampaignNamespaceSettings(ctx context.Context, opts GetCampaignNamespaceSettingsOpts) (*a8n.CampaignNamespaceSettings, error) {
+	var pred *sqlf.Query
+	switch {
+	case opts.NamespaceUserID != 0:
+		pred = sqlf.Sprintf("namespace_user_id = %s", opts.NamespaceUserID)
+	case opts.NamespaceOrgID != 0:
+		pred = sqlf.Sprintf("namespace_org_id = %s", opts.NamespaceOrgID)
+	default:
+		return nil, errors.New("namespace user or org ID required")
+	}
+
+	q := sqlf.Sprintf(getCampaignNamespaceSettingsQueryFmtstr, pred)
+
+	var ns a8n.CampaignNamespaceSettings
+	err := s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 0, scanCampaignNamespaceSettings(&ns, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ns.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &ns, nil
+}
+
+var getCampaignNamespaceSettingsQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignNamespaceSettings
+SELECT
+  id,
+  namespace_user_id,
+  namespace_org_id,
+  branch_prefix,
+  default_labels,
+  require_approval,
+  notification_channel,
+  notification_webhook_url,
+  created_at,
+  updated_at
+FROM campaign_namespace_settings
+WHERE %s
+LIMIT 1
+`
+
+func scanCampaignNamespaceSettings(ns *a8n.CampaignNamespaceSettings, s scanner) error {
+	var defaultLabels []byte
+
+	err := s.Scan(
+		&ns.ID,
+		&dbutil.NullInt32{N: &ns.NamespaceUserID},
+		&dbutil.NullInt32{N: &ns.NamespaceOrgID},
+		&ns.BranchPrefix,
+		&defaultLabels,
+		&ns.RequireApproval,
+		&ns.NotificationChannel,
+		&ns.NotificationWebhookURL,
+		&ns.CreatedAt,
+		&ns.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	ns.DefaultLabels = nil
+	return json.Unmarshal(defaultLabels, &ns.DefaultLabels)
+}
+
+// GetCampaignNotificationSettings gets the CampaignNotificationSettings of the
+// user with the given ID. It returns ErrNoResults if the user has none.
+func (s *Store) GetCampaignNotificationSettings(ctx context.Context, userID int32) (*a8n.CampaignNotificationSettings, error) {
+	q := sqlf.Sprintf(getCampaignNotificationSettingsQueryFmtstr, userID)
+
+	var ns a8n.CampaignNotificationSettings
+	_, count, err := s.query(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 1, scanCampaignNotificationSettings(&ns, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &ns, nil
+}
+
+var getCampaignNotificationSettingsQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignNotificationSettings
+SELECT
+  user_id,
+  notify_merged,
+  notify_closed,
+  notify_checks_failed,
+  digest_interval_minutes,
+  created_at,
+  updated_at
+FROM campaign_notification_settings
+WHERE user_id = %s
+`
+
+// UpsertCampaignNotificationSettings creates the given
+// CampaignNotificationSettings or, if their user already has some, updates
+// them. The DigestInterval is stored with a precision of minutes.
+func (s *Store) UpsertCampaignNotificationSettings(ctx context.Context, ns *a8n.CampaignNotificationSettings) error {
+	if ns.CreatedAt.IsZero() {
+		ns.CreatedAt = s.now()
+	}
+	ns.UpdatedAt = s.now()
+
+	q := sqlf.Sprintf(
+		upsertCampaignNotificationSettingsQueryFmtstr,
+		ns.UserID,
+		ns.NotifyMerged,
+		ns.NotifyClosed,
+		ns.NotifyChecksFailed,
+		int64(ns.DigestInterval/time.Minute),
+		ns.CreatedAt,
+		ns.UpdatedAt,
+	)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignNotificationSettings(ns, sc)
+		return int64(ns.UserID), 1, err
+	})
+}
+
+var upsertCampaignNotificationSettingsQueryFmtstr = `
+-- source: internal/a8n/store.go:UpsertCampaignNotificationSettings
+INSERT INTO campaign_notification_settings (
+  user_id,
+  notify_merged,
+  notify_closed,
+  notify_checks_failed,
+  digest_interval_minutes,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (user_id) DO UPDATE SET
+  notify_merged = excluded.notify_merged,
+  notify_closed = excluded.notify_closed,
+  notify_checks_failed = excluded.notify_checks_failed,
+  digest_interval_minutes = excluded.digest_interval_minutes,
+  updated_at = excluded.updated_at
+RETURNING
+  user_id,
+  notify_merged,
+  notify_closed,
+  notify_checks_failed,
+  digest_interval_minutes,
+  created_at,
+  updated_at
+`
+
+func scanCampaignNotificationSettings(ns *a8n.CampaignNotificationSettings, s scanner) error {
+	var minutes int64
+
+	err := s.Scan(
+		&ns.UserID,
+		&ns.NotifyMerged,
+		&ns.NotifyClosed,
+		&ns.NotifyChecksFailed,
+		&minutes,
+		&ns.CreatedAt,
+		&ns.UpdatedAt,
+	)
+
+	ns.DigestInterval = time.Duration(minutes) * time.Minute
+	return err
+}
+
+// GetCampaignSubscriptionOpts captures the query options needed for getting
+// a CampaignSubscription.
+type GetCampaignSubscriptionOpts struct {
+	CampaignID int64
+	UserID     int32
+}
+
+// GetCampaignSubscription gets the CampaignSubscription of the given user to
+// the given Campaign. It returns ErrNoResults if the user never subscribed or
+// unsubscribed.
+func (s *Store) GetCampaignSubscription(ctx context.Context, opts GetCampaignSubscriptionOpts) (*a8n.CampaignSubscription, error) {
+	q := sqlf.Sprintf(getCampaignSubscriptionQueryFmtstr, opts.CampaignID, opts.UserID)
+
+	var cs a8n.CampaignSubscription
+	_, count, err := s.query(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 1, scanCampaignSubscription(&cs, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &cs, nil
+}
+
+var getCampaignSubscriptionQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignSubscription
+SELECT
+  campaign_id,
+  user_id,
+  subscribed,
+  created_at,
+  updated_at
+FROM campaign_subscriptions
+WHERE campaign_id = %s AND user_id = %s
+`
+
+// UpsertCampaignSubscription creates the given CampaignSubscription or, if
+// its user already subscribed to or unsubscribed from its Campaign, updates
+// it.
+func (s *Store) UpsertCampaignSubscription(ctx context.Context, cs *a8n.CampaignSubscription) error {
+	if cs.CreatedAt.IsZero() {
+		cs.CreatedAt = s.now()
+	}
+	cs.UpdatedAt = s.now()
+
+	q := sqlf.Sprintf(
+		upsertCampaignSubscriptionQueryFmtstr,
+		cs.CampaignID,
+		cs.UserID,
+		cs.Subscribed,
+		cs.CreatedAt,
+		cs.UpdatedAt,
+	)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignSubscription(cs, sc)
+		return cs.CampaignID, 1, err
+	})
+}
+
+var upsertCampaignSubscriptionQueryFmtstr = `
+-- source: internal/a8n/store.go:UpsertCampaignSubscription
+INSERT INTO campaign_subscriptions (
+  campaign_id,
+  user_id,
+  subscribed,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (campaign_id, user_id) DO UPDATE SET
+  subscribed = excluded.subscribed,
+  updated_at = excluded.updated_at
+RETURNING
+  campaign_id,
+  user_id,
+  subscribed,
+  created_at,
+  updated_at
+`
+
+func scanCampaignSubscription(cs *a8n.CampaignSubscription, s scanner) error {
+	return s.Scan(
+		&cs.CampaignID,
+		&cs.UserID,
+		&cs.Subscribed,
+		&cs.CreatedAt,
+		&cs.UpdatedAt,
+	)
+}
+
+// campaignNotificationSettingsColumns are the columns of
+// campaign_notification_settings in which users opt out of the notifications
+// of each kind.
+var campaignNotificationSettingsColumns = map[a8n.CampaignNotificationKind]string{
+	a8n.CampaignNotificationKindMerged:       "notify_merged",
+	a8n.CampaignNotificationKindClosed:       "notify_closed",
+	a8n.CampaignNotificationKindChecksFailed: "notify_checks_failed",
+}
+
+// EnqueueCampaignNotifications creates a CampaignNotification of the given
+// kind about the Changeset with the given ID for the authors and subscribers
+// of each of the open Campaigns that the Changeset belongs to. Users that
+// unsubscribed from a Campaign or opted out of notifications of the kind
+// aren't notified.
+func (s *Store) EnqueueCampaignNotifications(ctx context.Context, changesetID int64, kind a8n.CampaignNotificationKind) error {
+	column, ok := campaignNotificationSettingsColumns[kind]
+	if !ok {
+		return errors.Errorf("invalid campaign notification kind %q", kind)
+	}
+
+	q := sqlf.Sprintf(
+		enqueueCampaignNotificationsQueryFmtstr,
+		changesetID,
+		string(kind),
+		s.now(),
+		changesetID,
+		changesetID,
+		sqlf.Sprintf("settings."+column),
+	)
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var enqueueCampaignNotificationsQueryFmtstr = `
+-- source: internal/a8n/store.go:EnqueueCampaignNotifications
+INSERT INTO campaign_notifications (user_id, campaign_id, changeset_id, kind, created_at)
+SELECT recipients.user_id, recipients.campaign_id, %s, %s, %s
+FROM (
+  SELECT id AS campaign_id, author_id AS user_id
+  FROM campaigns
+  WHERE changeset_ids ? %s AND deleted_at IS NULL AND closed_at IS NULL
+  UNION
+  SELECT campaigns.id, campaign_subscriptions.user_id
+  FROM campaigns
+  JOIN campaign_subscriptions ON campaign_subscriptions.campaign_id = campaigns.id
+  WHERE changeset_ids ? %s AND deleted_at IS NULL AND closed_at IS NULL
+  AND campaign_subscriptions.subscribed
+) AS recipients
+LEFT JOIN campaign_subscriptions ON
+  campaign_subscriptions.campaign_id = recipients.campaign_id AND
+  campaign_subscriptions.user_id = recipients.user_id
+LEFT JOIN campaign_notification_settings AS settings ON settings.user_id = recipients.user_id
+WHERE COALESCE(campaign_subscriptions.subscribed, true)
+AND COALESCE(%s, true)
+`
+
+// ListDueCampaignNotificationUserIDs returns the IDs of the users whose
+// oldest unsent CampaignNotification was created at least their digest
+// interval ago.
+func (s *Store) ListDueCampaignNotificationUserIDs(ctx context.Context) (ids []int32, err error) {
+	defaultMinutes := int64(a8n.DefaultCampaignNotificationSettings(0).DigestInterval / time.Minute)
+	q := sqlf.Sprintf(listDueCampaignNotificationUserIDsQueryFmtstr, s.now(), defaultMinutes)
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var id int32
+		if err = sc.Scan(&id); err != nil {
+			return 0, 0, err
+		}
+		ids = append(ids, id)
+		return int64(id), 1, nil
+	})
+
+	return ids, err
+}
+
+var listDueCampaignNotificationUserIDsQueryFmtstr = `
+-- source: internal/a8n/store.go:ListDueCampaignNotificationUserIDs
+SELECT campaign_notifications.user_id
+FROM campaign_notifications
+LEFT JOIN campaign_notification_settings AS settings ON settings.user_id = campaign_notifications.user_id
+WHERE campaign_notifications.sent_at IS NULL
+GROUP BY campaign_notifications.user_id, settings.digest_interval_minutes
+HAVING MIN(campaign_notifications.created_at) <= %s::timestamptz - make_interval(mins => COALESCE(settings.digest_interval_minutes, %s))
+ORDER BY campaign_notifications.user_id ASC
+`
+
+// MarkCampaignNotificationsSent marks all unsent CampaignNotifications of the
+// given user as sent and returns them, ordered by ID. Concurrent calls for the
+// same user never return the same notifications, so that each is sent once.
+func (s *Store) MarkCampaignNotificationsSent(ctx context.Context, userID int32) (ns []*a8n.CampaignNotification, err error) {
+	q := sqlf.Sprintf(markCampaignNotificationsSentQueryFmtstr, s.now(), userID)
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var n a8n.CampaignNotification
+		if err = scanCampaignNotification(&n, sc); err != nil {
+			return 0, 0, err
+		}
+		ns = append(ns, &n)
+		return n.ID, 1, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ns, func(i, j int) bool { return ns[i].ID < ns[j].ID })
+	return ns, nil
+}
+
+var markCampaignNotificationsSentQueryFmtstr = `
+-- source: internal/a8n/store.go:MarkCampaignNotificationsSent
+UPDATE campaign_notifications
+SET sent_at = %s
+WHERE user_id = %s AND sent_at IS NULL
+RETURNING
+  id,
+  user_id,
+  campaign_id,
+  changeset_id,
+  kind,
+  created_at,
+  sent_at
+`
+
+// ResetCampaignNotifications marks the CampaignNotifications with the given
+// IDs as unsent again, e.g. because sending them failed.
+func (s *Store) ResetCampaignNotifications(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	qs := make([]*sqlf.Query, 0, len(ids))
+	for _, id := range ids {
+		qs = append(qs, sqlf.Sprintf("%d", id))
+	}
+
+	q := sqlf.Sprintf(resetCampaignNotificationsQueryFmtstr, sqlf.Join(qs, ","))
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var resetCampaignNotificationsQueryFmtstr = `
+-- source: internal/a8n/store.go:ResetCampaignNotifications
+UPDATE campaign_notifications
+SET sent_at = NULL
+WHERE id IN (%s)
+`
+
+// CampaignNotificationTTL is the duration for which CampaignNotifications are
+// stored. Notifications that couldn't be sent in that time, e.g. because email
+// isn't configured, are dropped.
+const CampaignNotificationTTL = 7 * 24 * time.Hour
+
+// DeleteExpiredCampaignNotifications deletes the CampaignNotifications that
+// were created more than CampaignNotificationTTL ago.
+func (s *Store) DeleteExpiredCampaignNotifications(ctx context.Context) error {
+	q := sqlf.Sprintf(deleteExpiredCampaignNotificationsQueryFmtstr, s.now().Add(-CampaignNotificationTTL))
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteExpiredCampaignNotificationsQueryFmtstr = `
+-- source: internal/a8n/store.go:DeleteExpiredCampaignNotifications
+DELETE FROM campaign_notifications
+WHERE created_at <= %s
+`
+
+func scanCampaignNotification(n *a8n.CampaignNotification, s scanner) error {
+	return s.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.CampaignID,
+		&n.ChangesetID,
+		&n.Kind,
+		&n.CreatedAt,
+		&dbutil.NullTime{Time: &n.SentAt},
+	)
+}
+
+// CampaignWebhookDeliveryTTL is the duration for which
+// CampaignWebhookDeliveries and their attempts are stored.
+const CampaignWebhookDeliveryTTL = 30 * 24 * time.Hour
+
+// CreateCampaignWebhookDelivery creates the given CampaignWebhookDelivery
+// without any attempts.
+func (s *Store) CreateCampaignWebhookDelivery(ctx context.Context, d *a8n.CampaignWebhookDelivery) error {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = s.now()
+	}
+
+	q := sqlf.Sprintf(
+		createCampaignWebhookDeliveryQueryFmtstr,
+		d.Channel,
+		d.URL,
+		nullInt32Column(d.RecipientUserID),
+		[]byte(d.Payload),
+		d.CreatedAt,
+	)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanCampaignWebhookDelivery(d, sc)
+		return d.ID, 1, err
+	})
+}
+
+var createCampaignWebhookDeliveryQueryFmtstr = `
+-- source: internal/a8n/store.go:CreateCampaignWebhookDelivery
+INSERT INTO campaign_webhook_deliveries (
+  channel,
+  url,
+  recipient_user_id,
+  payload,
+  created_at
+)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING
+` + campaignWebhookDeliveryColumns
+
+// CreateCampaignWebhookDeliveryAttempt records the given attempt of a
+// CampaignWebhookDelivery and updates the attempt count, the time of the
+// last attempt and, if it succeeded, the success time of the delivery.
+func (s *Store) CreateCampaignWebhookDeliveryAttempt(ctx context.Context, a *a8n.CampaignWebhookDeliveryAttempt) error {
+	if a.AttemptedAt.IsZero() {
+		a.AttemptedAt = s.now()
+	}
+
+	q := sqlf.Sprintf(
+		createCampaignWebhookDeliveryAttemptQueryFmtstr,
+		a.DeliveryID,
+		a.Succeeded,
+		nullInt32Column(a.StatusCode),
+		nullStringColumn(a.ResponseBody),
+		nullStringColumn(a.Error),
+		int64(a.Duration/time.Millisecond),
+		a.AttemptedAt,
+	)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = sc.Scan(&a.ID)
+		return a.ID, 1, err
+	})
+}
+
+var createCampaignWebhookDeliveryAttemptQueryFmtstr = `
+-- source: internal/a8n/store.go:CreateCampaignWebhookDeliveryAttempt
+WITH attempt AS (
+  INSERT INTO campaign_webhook_delivery_attempts (
+    delivery_id,
+    succeeded,
+    status_code,
+    response_body,
+    error,
+    duration_ms,
+    attempted_at
+  )
+  VALUES (%s, %s, %s, %s, %s, %s, %s)
+  RETURNING id, delivery_id, succeeded, attempted_at
+), delivery AS (
+  UPDATE campaign_webhook_deliveries d
+  SET
+    attempt_count = d.attempt_count + 1,
+    last_attempted_at = attempt.attempted_at,
+    succeeded_at = CASE
+      WHEN attempt.succeeded THEN COALESCE(d.succeeded_at, attempt.attempted_at)
+      ELSE d.succeeded_at
+    END
+  FROM attempt
+  WHERE d.id = attempt.delivery_id
+)
+SELECT id FROM attempt
+`
+
+// GetCampaignWebhookDelivery gets the CampaignWebhookDelivery with the given
+// ID. It returns ErrNoResults if it doesn't exist.
+func (s *Store) GetCampaignWebhookDelivery(ctx context.Context, id int64) (*a8n.CampaignWebhookDelivery, error) {
+	q := sqlf.Sprintf(getCampaignWebhookDeliveryQueryFmtstr, id)
+
+	var d a8n.CampaignWebhookDelivery
+	_, count, err := s.query(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		return 0, 1, scanCampaignWebhookDelivery(&d, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &d, nil
+}
+
+var getCampaignWebhookDeliveryQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignWebhookDelivery
+SELECT
+` + campaignWebhookDeliveryColumns + `
+FROM campaign_webhook_deliveries
+WHERE id = %s
+LIMIT 1
+`
+
+// ListCampaignWebhookDeliveriesOpts captures the query options needed for
+// listing CampaignWebhookDeliveries.
+type ListCampaignWebhookDeliveriesOpts struct {
+	Cursor int64
+	Limit  int
+
+	// OnlyFailed limits the results to deliveries without a successful
+	// attempt.
+	OnlyFailed bool
+}
+
+// ListCampaignWebhookDeliveries lists CampaignWebhookDeliveries with the
+// given filters, newest first.
+func (s *Store) ListCampaignWebhookDeliveries(ctx context.Context, opts ListCampaignWebhookDeliveriesOpts) (ds []*a8n.CampaignWebhookDelivery, next int64, err error) {
+	q := listCampaignWebhookDeliveriesQuery(&opts)
+
+	ds = make([]*a8n.CampaignWebhookDelivery, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var d a8n.CampaignWebhookDelivery
+		if err = scanCampaignWebhookDelivery(&d, sc); err != nil {
+			return 0, 0, err
+		}
+		ds = append(ds, &d)
+		return d.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(ds) == opts.Limit {
+		next = ds[len(ds)-1].ID
+		ds = ds[:len(ds)-1]
+	}
+
+	return ds, next, err
+}
+
+var listCampaignWebhookDeliveriesQueryFmtstr = `
+-- source: internal/a8n/store.go:ListCampaignWebhookDeliveries
+SELECT
+` + campaignWebhookDeliveryColumns + `
+FROM campaign_webhook_deliveries
+WHERE %s
+ORDER BY id DESC
+LIMIT %s
+`
+
+func listCampaignWebhookDeliveriesQuery(opts *ListCampaignWebhookDeliveriesOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit++
+
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+
+	// The cursor is the ID of the oldest delivery of the previous page, since
+	// deliveries are listed newest first.
+	if opts.Cursor != 0 {
+		preds = append(preds, sqlf.Sprintf("id <= %s", opts.Cursor))
+	}
+
+	if opts.OnlyFailed {
+		preds = append(preds, sqlf.Sprintf("succeeded_at IS NULL"))
+	}
+
+	return sqlf.Sprintf(
+		listCampaignWebhookDeliveriesQueryFmtstr,
+		sqlf.Join(preds, "\n AND "),
+		opts.Limit,
+	)
+}
+
+// CountCampaignWebhookDeliveries returns the number of
+// CampaignWebhookDeliveries, or of those without a successful attempt if
+// onlyFailed is set.
+func (s *Store) CountCampaignWebhookDeliveries(ctx context.Context, onlyFailed bool) (count int64, _ error) {
+	pred := sqlf.Sprintf("TRUE")
+	if onlyFailed {
+		pred = sqlf.Sprintf("succeeded_at IS NULL")
+	}
+
+	q := sqlf.Sprintf(countCampaignWebhookDeliveriesQueryFmtstr, pred)
+	return count, s.exec(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		err = sc.Scan(&count)
+		return 0, count, err
+	})
+}
+
+var countCampaignWebhookDeliveriesQueryFmtstr = `
+-- source: internal/a8n/store.go:CountCampaignWebhookDeliveries
+SELECT COUNT(id)
+FROM campaign_webhook_deliveries
+WHERE %s
+`
+
+// ListCampaignWebhookDeliveryAttempts lists the attempts of the
+// CampaignWebhookDelivery with the given ID, oldest first.
+func (s *Store) ListCampaignWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) (as []*a8n.CampaignWebhookDeliveryAttempt, err error) {
+	q := sqlf.Sprintf(listCampaignWebhookDeliveryAttemptsQueryFmtstr, deliveryID)
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var (
+			a          a8n.CampaignWebhookDeliveryAttempt
+			durationMS int64
+		)
+		err = sc.Scan(
+			&a.ID,
+			&a.DeliveryID,
+			&a.Succeeded,
+			&dbutil.NullInt32{N: &a.StatusCode},
+			&dbutil.NullString{S: &a.ResponseBody},
+			&dbutil.NullString{S: &a.Error},
+			&durationMS,
+			&a.AttemptedAt,
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+		a.Duration = time.Duration(durationMS) * time.Millisecond
+		as = append(as, &a)
+		return a.ID, 1, nil
+	})
+
+	return as, err
+}
+
+var listCampaignWebhookDeliveryAttemptsQueryFmtstr = `
+-- source: internal/a8n/store.go:ListCampaignWebhookDeliveryAttempts
+SELECT
+  id,
+  delivery_id,
+  succeeded,
+  status_code,
+  response_body,
+  error,
+  duration_ms,
+  attempted_at
+FROM campaign_webhook_delivery_attempts
+WHERE delivery_id = %s
+ORDER BY id ASC
+`
+
+// DeleteExpiredCampaignWebhookDeliveries deletes the
+// CampaignWebhookDeliveries that were created more than
+// CampaignWebhookDeliveryTTL ago, along with their attempts.
+func (s *Store) DeleteExpiredCampaignWebhookDeliveries(ctx context.Context) error {
+	q := sqlf.Sprintf(deleteExpiredCampaignWebhookDeliveriesQueryFmtstr, s.now().Add(-CampaignWebhookDeliveryTTL))
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var deleteExpiredCampaignWebhookDeliveriesQueryFmtstr = `
+-- source: internal/a8n/store.go:DeleteExpiredCampaignWebhookDeliveries
+DELETE FROM campaign_webhook_deliveries
+WHERE created_at <= %s
+`
+
+const campaignWebhookDeliveryColumns = `
+  id,
+  channel,
+  url,
+  recipient_user_id,
+  payload,
+  attempt_count,
+  last_attempted_at,
+  succeeded_at,
+  created_at
+`
+
+func scanCampaignWebhookDelivery(d *a8n.CampaignWebhookDelivery, s scanner) error {
+	var payload []byte
+
+	err := s.Scan(
+		&d.ID,
+		&d.Channel,
+		&d.URL,
+		&dbutil.NullInt32{N: &d.RecipientUserID},
+		&payload,
+		&d.AttemptCount,
+		&dbutil.NullTime{Time: &d.LastAttemptedAt},
+		&dbutil.NullTime{Time: &d.SucceededAt},
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.Payload = payload
+	return nil
+}
+
+// GetCampaignReadState returns when the given user last viewed the Campaign
+// with the given ID and when the latest activity in it happened. It returns
+// ErrNoResults if the Campaign doesn't exist.
+func (s *Store) GetCampaignReadState(ctx context.Context, campaignID int64, userID int32) (*a8n.CampaignReadState, error) {
+	q := sqlf.Sprintf(getCampaignReadStateQueryFmtstr, campaignLastActivityExpr(), userID, campaignID)
+
+	rs := a8n.CampaignReadState{UserID: userID}
+	_, count, err := s.query(ctx, q, func(sc scanner) (_, _ int64, err error) {
+		err = sc.Scan(
+			&rs.CampaignID,
+			&dbutil.NullTime{Time: &rs.LastViewedAt},
+			&rs.LastActivityAt,
+		)
+		return 0, 1, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &rs, nil
+}
+
+var getCampaignReadStateQueryFmtstr = `
+-- source: internal/a8n/store.go:GetCampaignReadState
+SELECT
+  campaigns.id,
+  campaign_views.viewed_at,
+  %s
+FROM campaigns
+LEFT JOIN campaign_views ON
+  campaign_views.campaign_id = campaigns.id AND
+  campaign_views.user_id = %s
+WHERE campaigns.id = %s
+`
+
+// MarkCampaignViewed records that the given user viewed the Campaign with the
+// given ID now, which marks its activity until now as read.
+func (s *Store) MarkCampaignViewed(ctx context.Context, campaignID int64, userID int32) error {
+	q := sqlf.Sprintf(markCampaignViewedQueryFmtstr, campaignID, userID, s.now())
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+var markCampaignViewedQueryFmtstr = `
+-- source: internal/a8n/store.go:MarkCampaignViewed
+INSERT INTO campaign_views (campaign_id, user_id, viewed_at)
+VALUES (%s, %s, %s)
+ON CONFLICT (campaign_id, user_id) DO UPDATE SET
+  viewed_at = GREATEST(campaign_views.viewed_at, excluded.viewed_at)
+`
+
+// campaignLastActivityExpr returns an expression computing the time of the
+// latest activity in the campaign in the current row: its creation, a
+// changeset being added to it or an event of one of its changesets of the
+// kinds shown in its activity feed being synced.
+func campaignLastActivityExpr() *sqlf.Query {
+	kinds := make([]*sqlf.Query, 0, len(CampaignActivityEventKinds))
+	for _, k := range CampaignActivityEventKinds {
+		kinds = append(kinds, sqlf.Sprintf("%s", string(k)))
+	}
+	return sqlf.Sprintf(campaignLastActivityExprFmtstr, sqlf.Join(kinds, ","))
+}
+
+var campaignLastActivityExprFmtstr = `
+GREATEST(
+  campaigns.created_at,
+  (
+    SELECT MAX(c.created_at)
+    FROM changesets c
+    WHERE c.campaign_ids ? campaigns.id::text
+  ),
+  (
+    SELECT MAX(e.created_at)
+    FROM changeset_events e
+    JOIN changesets c ON c.id = e.changeset_id
+    WHERE c.campaign_ids ? campaigns.id::text
+    AND e.kind IN (%s)
+  )
+)
+`
+
+// campaignUnreadPred returns a predicate matching campaigns with activity
+// that the given user hasn't seen, as in CampaignReadState.HasUnreadActivity.
+func campaignUnreadPred(userID int32) *sqlf.Query {
+	return sqlf.Sprintf(campaignUnreadPredFmtstr, userID, campaignLastActivityExpr())
+}
+
+var campaignUnreadPredFmtstr = `
+NOT EXISTS (
+  SELECT 1
+  FROM campaign_views v
+  WHERE v.campaign_id = campaigns.id
+  AND v.user_id = %s
+  AND v.viewed_at >= %s
+)
+`
+
+// campaignInvolvementPred returns a predicate matching campaigns that the
+// given user is involved in as the given CampaignInvolvement, or in any way if
+// it's empty.
+func campaignInvolvementPred(userID int32, as a8n.CampaignInvolvement) *sqlf.Query {
+	preds := map[a8n.CampaignInvolvement]*sqlf.Query{
+		a8n.CampaignInvolvementAuthor: sqlf.Sprintf("campaigns.author_id = %s", userID),
+		a8n.CampaignInvolvementReviewer: sqlf.Sprintf(campaignReviewerPredFmtstr,
+			github.ServiceType,
+			string(a8n.ChangesetEventKindGitHubReviewRequested), string(a8n.ChangesetEventKindGitHubReviewed),
+			userID,
+			bitbucketserver.ServiceType,
+			userID,
+		),
+		a8n.CampaignInvolvementSubscriber: sqlf.Sprintf(campaignSubscriberPredFmtstr, userID, userID),
+	}
+
+	if pred, ok := preds[as]; ok {
+		return pred
+	}
+	return sqlf.Sprintf("(%s)", sqlf.Join([]*sqlf.Query{
+		preds[a8n.CampaignInvolvementAuthor],
+		preds[a8n.CampaignInvolvementReviewer],
+		preds[a8n.CampaignInvolvementSubscriber],
+	}, " OR "))
+}
+
+// campaignReviewerPredFmtstr matches campaigns with a changeset that the user
+// was requested to review, or reviewed. GitHub reviewers are matched by the
+// login of the user's external account on the code host of the changeset's
+// repository. Bitbucket Server reviewers are matched by username, like the
+// Bitbucket Server authz provider matches users.
+var campaignReviewerPredFmtstr = `
+EXISTS (
+  SELECT 1
+  FROM changesets c
+  WHERE c.campaign_ids ? campaigns.id::text
+  AND (
+    (c.external_service_type = %s AND EXISTS (
+      SELECT 1
+      FROM changeset_events e
+      JOIN repo r ON r.id = c.repo_id
+      JOIN user_external_accounts a ON
+        a.service_type = c.external_service_type AND
+        a.service_id = r.external_service_id AND
+        a.deleted_at IS NULL
+      WHERE e.changeset_id = c.id
+      AND e.kind IN (%s, %s)
+      AND a.user_id = %s
+      AND COALESCE(e.metadata->'RequestedReviewer'->>'Login', e.metadata->'Author'->>'Login') = a.account_data->>'login'
+    ))
+    OR
+    (c.external_service_type = %s AND c.metadata->'reviewers' @> jsonb_build_array(
+      jsonb_build_object('user', jsonb_build_object('name', (
+        SELECT u.username::text FROM users u WHERE u.id = %s AND u.deleted_at IS NULL
+      )))
+    ))
+  )
+)
+`
+
+// campaignSubscriberPredFmtstr matches campaigns that the user subscribed
+// to, or authored without unsubscribing, as in EnqueueCampaignNotifications.
+var campaignSubscriberPredFmtstr = `
+COALESCE((
+  SELECT s.subscribed
+  FROM campaign_subscriptions s
+  WHERE s.campaign_id = campaigns.id AND s.user_id = %s
+), campaigns.author_id = %s)
+`
+
+// DefaultWorkerJobMaxAttempts is the number of times a WorkerJob is run
+// before it's moved to the dead-letter state, if it doesn't set MaxAttempts.
+const DefaultWorkerJobMaxAttempts = 5
+
+// EnqueueWorkerJob adds the given WorkerJob to its queue. If RunAfter is
+// zero, the job is due right away.
+func (s *Store) EnqueueWorkerJob(ctx context.Context, j *a8n.WorkerJob) error {
+	q := s.enqueueWorkerJobQuery(j)
+
+	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		err = scanWorkerJob(j, sc)
+		return j.ID, 1, err
+	})
+}
+
+var enqueueWorkerJobQueryFmtstr = `
+-- source: internal/a8n/store.go:EnqueueWorkerJob
+INSERT INTO campaign_worker_jobs (
+  queue,
+  payload,
+  state,
+  attempts,
+  max_attempts,
+  error,
+  run_after,
+  created_at,
+  updated_at
+)
+VALUES (%s, %s, %s, 0, %s, NULL, %s, %s, %s)
+RETURNING
+` + workerJobColumns
+
+func (s *Store) enqueueWorkerJobQuery(j *a8n.WorkerJob) *sqlf.Query {
+	if len(j.Payload) == 0 {
+		j.Payload = json.RawMessage("{}")
+	}
+
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = s.now()
+	}
+
+	if j.UpdatedAt.IsZero() {
+		j.UpdatedAt = j.CreatedAt
+	}
+
+	if j.RunAfter.IsZero() {
+		j.RunAfter = j.CreatedAt
+	}
+
+	if j.MaxAttempts == 0 {
+		j.MaxAttempts = DefaultWorkerJobMaxAttempts
+	}
+
 	return sqlf.Sprintf(
-		resetChangesetJobsQueryFmtstr,
-		sqlf.Join(preds, "\n AND "),
+		enqueueWorkerJobQueryFmtstr,
+		j.Queue,
+		j.Payload,
+		a8n.WorkerJobStateQueued,
+		j.MaxAttempts,
+		j.RunAfter,
+		j.CreatedAt,
+		j.UpdatedAt,
 	)
 }
 
-var resetChangesetJobsQueryFmtstr = `
--- source: internal/a8n/store.go:resetChangesetJobsQuery
-UPDATE changeset_jobs
+// DequeueWorkerJob takes the next due WorkerJob of the given queue and marks
+// it as processing, counting it as another attempt. Jobs that haven't had a
+// heartbeat for longer than stalledAfter are taken again, since the worker
+// that was processing them is presumed dead. Concurrent callers never get the
+// same job, since locked rows are skipped.
+//
+// It returns ErrNoResults if no job is due.
+func (s *Store) DequeueWorkerJob(ctx context.Context, queue string, stalledAfter time.Duration) (*a8n.WorkerJob, error) {
+	now := s.now()
+
+	q := sqlf.Sprintf(
+		dequeueWorkerJobQueryFmtstr,
+		a8n.WorkerJobStateProcessing,
+		now,
+		now,
+		now,
+		queue,
+		a8n.WorkerJobStateQueued,
+		now,
+		a8n.WorkerJobStateProcessing,
+		now.Add(-stalledAfter),
+	)
+
+	var j *a8n.WorkerJob
+	err := s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		j = &a8n.WorkerJob{}
+		err = scanWorkerJob(j, sc)
+		return j.ID, 1, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if j == nil {
+		return nil, ErrNoResults
+	}
+
+	return j, nil
+}
+
+var dequeueWorkerJobQueryFmtstr = `
+-- source: internal/a8n/store.go:DequeueWorkerJob
+UPDATE campaign_worker_jobs
 SET
-  error = '',
-  started_at = NULL,
-  finished_at = NULL
-WHERE %s
-`
+  state = %s,
+  attempts = attempts + 1,
+  heartbeat_at = %s,
+  started_at = %s,
+  updated_at = %s
+WHERE id = (
+  SELECT id FROM campaign_worker_jobs
+  WHERE queue = %s
+  AND (
+    (state = %s AND run_after <= %s)
+    OR (state = %s AND heartbeat_at < %s)
+  )
+  ORDER BY run_after ASC, id ASC
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING
+` + workerJobColumns
+
+// HeartbeatWorkerJob records that the given WorkerJob is still being
+// processed. It returns ErrNoResults if the attempt isn't current anymore,
+// because the job was taken again after it stalled.
+func (s *Store) HeartbeatWorkerJob(ctx context.Context, j *a8n.WorkerJob) error {
+	j.HeartbeatAt = s.now()
+	return s.updateWorkerJobAttempt(ctx, j)
+}
 
-// GetGithubExternalIDForRefs allows us to find the external id for GitHub pull requests based on
-// a slice of head refs. We need this in order to match incoming status webhooks to pull requests as
-// the only information they provide is the remote branch
-func (s *Store) GetGithubExternalIDForRefs(ctx context.Context, refs []string) ([]string, error) {
-	queryFmtString := `
-SELECT external_id FROM changesets
-WHERE external_service_type = 'github'
-AND external_branch IN (%s)
+// CompleteWorkerJob marks the given WorkerJob as completed. It returns
+// ErrNoResults if the attempt isn't current anymore.
+func (s *Store) CompleteWorkerJob(ctx context.Context, j *a8n.WorkerJob) error {
+	j.State = a8n.WorkerJobStateCompleted
+	j.Error = ""
+	j.FinishedAt = s.now()
+	return s.updateWorkerJobAttempt(ctx, j)
+}
+
+// FailWorkerJob records the given error of the current attempt of the given
+// WorkerJob. The job is retried after retryAfter, unless it has run out of
+// attempts, in which case it's moved to the dead-letter state. It returns
+// ErrNoResults if the attempt isn't current anymore.
+func (s *Store) FailWorkerJob(ctx context.Context, j *a8n.WorkerJob, jobErr error, retryAfter time.Duration) error {
+	now := s.now()
+
+	j.Error = jobErr.Error()
+	if j.Attempts >= j.MaxAttempts {
+		j.State = a8n.WorkerJobStateDead
+		j.FinishedAt = now
+	} else {
+		j.State = a8n.WorkerJobStateQueued
+		j.RunAfter = now.Add(retryAfter)
+	}
+
+	return s.updateWorkerJobAttempt(ctx, j)
+}
+
+func (s *Store) updateWorkerJobAttempt(ctx context.Context, j *a8n.WorkerJob) error {
+	j.UpdatedAt = s.now()
+
+	q := sqlf.Sprintf(
+		updateWorkerJobAttemptQueryFmtstr,
+		j.State,
+		nullStringColumn(j.Error),
+		j.RunAfter,
+		nullTimeColumn(j.HeartbeatAt),
+		nullTimeColumn(j.FinishedAt),
+		j.UpdatedAt,
+		j.ID,
+		a8n.WorkerJobStateProcessing,
+		j.Attempts,
+	)
+
+	var updated bool
+	err := s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		updated = true
+		err = scanWorkerJob(j, sc)
+		return j.ID, 1, err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return ErrNoResults
+	}
+
+	return nil
+}
+
+var updateWorkerJobAttemptQueryFmtstr = `
+-- source: internal/a8n/store.go:updateWorkerJobAttempt
+UPDATE campaign_worker_jobs
+SET (
+  state,
+  error,
+  run_after,
+  heartbeat_at,
+  finished_at,
+  updated_at
+) = (%s, %s, %s, %s, %s, %s)
+WHERE id = %s
+AND state = %s
+AND attempts = %s
+RETURNING
+` + workerJobColumns
+
+// ListWorkerJobsOpts captures the query options needed for listing
+// WorkerJobs.
+type ListWorkerJobsOpts struct {
+	Queue  string
+	State  a8n.WorkerJobState
+	Cursor int64
+	Limit  int
+
+	// OnlyFailed limits the results to jobs that failed at least once.
+	OnlyFailed bool
+}
+
+// ListWorkerJobs lists WorkerJobs with the given filters.
+func (s *Store) ListWorkerJobs(ctx context.Context, opts ListWorkerJobsOpts) (js []*a8n.WorkerJob, next int64, err error) {
+	q := listWorkerJobsQuery(&opts)
+
+	js = make([]*a8n.WorkerJob, 0, opts.Limit)
+	_, _, err = s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var j a8n.WorkerJob
+		if err = scanWorkerJob(&j, sc); err != nil {
+			return 0, 0, err
+		}
+		js = append(js, &j)
+		return j.ID, 1, err
+	})
+
+	if opts.Limit != 0 && len(js) == opts.Limit {
+		next = js[len(js)-1].ID
+		js = js[:len(js)-1]
+	}
+
+	return js, next, err
+}
+
+var listWorkerJobsQueryFmtstr = `
+-- source: internal/a8n/store.go:ListWorkerJobs
+SELECT
+` + workerJobColumns + `
+FROM campaign_worker_jobs
+WHERE %s
 ORDER BY id ASC
 `
-	inClause := make([]*sqlf.Query, 0, len(refs))
-	for _, ref := range refs {
-		if ref == "" {
-			continue
-		}
-		inClause = append(inClause, sqlf.Sprintf("%s", ref))
+
+func listWorkerJobsQuery(opts *ListWorkerJobsOpts) *sqlf.Query {
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
 	}
-	q := sqlf.Sprintf(queryFmtString, sqlf.Join(inClause, ","))
-	ids := make([]string, 0, len(refs))
-	_, _, err := s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
-		var s string
-		err = sc.Scan(&s)
+	opts.Limit++
+
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("id >= %s", opts.Cursor),
+	}
+
+	if opts.Queue != "" {
+		preds = append(preds, sqlf.Sprintf("queue = %s", opts.Queue))
+	}
+
+	if opts.State != "" {
+		preds = append(preds, sqlf.Sprintf("state = %s", opts.State))
+	}
+
+	if opts.OnlyFailed {
+		preds = append(preds, sqlf.Sprintf("error IS NOT NULL"))
+	}
+
+	return sqlf.Sprintf(
+		listWorkerJobsQueryFmtstr+limitClause,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// GetWorkerQueueStats returns the WorkerQueueStats of every queue that has
+// WorkerJobs, ordered by the queue name.
+func (s *Store) GetWorkerQueueStats(ctx context.Context) ([]*a8n.WorkerQueueStats, error) {
+	q := sqlf.Sprintf(
+		getWorkerQueueStatsQueryFmtstr,
+		a8n.WorkerJobStateQueued,
+		a8n.WorkerJobStateProcessing,
+		a8n.WorkerJobStateCompleted,
+		a8n.WorkerJobStateDead,
+		a8n.WorkerJobStateQueued,
+		a8n.WorkerJobStateQueued,
+	)
+
+	var stats []*a8n.WorkerQueueStats
+	err := s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var st a8n.WorkerQueueStats
+		err = sc.Scan(
+			&st.Queue,
+			&st.Queued,
+			&st.Processing,
+			&st.Completed,
+			&st.Dead,
+			&st.Retrying,
+			&dbutil.NullTime{Time: &st.OldestQueuedAt},
+		)
 		if err != nil {
 			return 0, 0, err
 		}
-		ids = append(ids, s)
+		stats = append(stats, &st)
 		return 0, 1, nil
 	})
-	if err != nil {
-		return nil, err
-	}
-	return ids, nil
-}
 
-func (s *Store) exec(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
-	_, _, err := s.query(ctx, q, sc)
-	return err
+	return stats, err
 }
 
-func (s *Store) query(ctx context.Context, q *sqlf.Query, sc scanFunc) (last, count int64, err error) {
+var getWorkerQueueStatsQueryFmtstr = `
+-- source: internal/a8n/store.go:GetWorkerQueueStats
+SELECT
+  queue,
+  COUNT(*) FILTER (WHERE state = %s) AS queued,
+  COUNT(*) FILTER (WHERE state = %s) AS processing,
+  COUNT(*) FILTER (WHERE state = %s) AS completed,
+  COUNT(*) FILTER (WHERE state = %s) AS dead,
+  COUNT(*) FILTER (WHERE state = %s AND error IS NOT NULL) AS retrying,
+  MIN(created_at) FILTER (WHERE state = %s) AS oldest_queued_at
+FROM campaign_worker_jobs
+GROUP BY queue
+ORDER BY queue ASC
+`
+
+// DeleteCompletedWorkerJobs deletes the WorkerJobs that were completed more
+// than the given duration ago. Dead jobs are kept, so that they can be
+// inspected.
+func (s *Store) DeleteCompletedWorkerJobs(ctx context.Context, olderThan time.Duration) error {
+	q := sqlf.Sprintf(
+		deleteCompletedWorkerJobsQueryFmtstr,
+		a8n.WorkerJobStateCompleted,
+		s.now().Add(-olderThan),
+	)
+
 	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
-		return 0, 0, err
+		return err
 	}
-	return scanAll(rows, sc)
+	return rows.Close()
 }
 
+var deleteCompletedWorkerJobsQueryFmtstr = `
+-- source: internal/a8n/store.go:DeleteCompletedWorkerJobs
+DELETE FROM campaign_worker_jobs
+WHERE state = %s
+AND finished_at <= %s
+`
+
+const workerJobColumns = `
+  id,
+  queue,
+  payload,
+  state,
+  attempts,
+  max_attempts,
+  error,
+  run_after,
+  heartbeat_at,
+  started_at,
+  finished_at,
+  created_at,
+  updated_at
+`
+
 // scanner captures the Scan method of sql.Rows and sql.Row
 type scanner interface {
 	Scan(dst ...interface{}) error
@@ -2598,6 +5913,12 @@ func scanChangeset(t *a8n.Changeset, s scanner) error {
 		&t.ExternalServiceType,
 		&t.ExternalBranch,
 		&dbutil.NullTime{Time: &t.ExternalDeletedAt},
+		&t.DiffStatAdded,
+		&t.DiffStatChanged,
+		&t.DiffStatDeleted,
+		&t.HasConflicts,
+		&dbutil.NullString{S: &t.ConflictsBaseOid},
+		&dbutil.NullString{S: &t.ConflictsHeadOid},
 	)
 	if err != nil {
 		return err
@@ -2648,7 +5969,33 @@ func scanChangesetEvent(e *a8n.ChangesetEvent, s scanner) error {
 }
 
 func scanCampaign(c *a8n.Campaign, s scanner) error {
-	return s.Scan(
+	var spec []byte
+	err := s.Scan(
+		&c.ID,
+		&c.Name,
+		&c.Description,
+		&dbutil.NullString{S: &c.Branch},
+		&c.AuthorID,
+		&dbutil.NullInt32{N: &c.NamespaceUserID},
+		&dbutil.NullInt32{N: &c.NamespaceOrgID},
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&dbutil.JSONInt64Set{Set: &c.ChangesetIDs},
+		&dbutil.NullInt64{N: &c.CampaignPlanID},
+		&dbutil.NullTime{Time: &c.ClosedAt},
+		&spec,
+		&dbutil.NullInt64{N: &c.RollbackOfCampaignID},
+		&dbutil.NullInt64{N: &c.ParentCampaignID},
+		&c.AutoRebase,
+		&c.Position,
+	)
+	c.Spec = spec
+	return err
+}
+
+func scanCampaignByName(c *a8n.Campaign, renamed *bool, s scanner) error {
+	var spec []byte
+	err := s.Scan(
 		&c.ID,
 		&c.Name,
 		&c.Description,
@@ -2661,7 +6008,15 @@ func scanCampaign(c *a8n.Campaign, s scanner) error {
 		&dbutil.JSONInt64Set{Set: &c.ChangesetIDs},
 		&dbutil.NullInt64{N: &c.CampaignPlanID},
 		&dbutil.NullTime{Time: &c.ClosedAt},
+		&spec,
+		&dbutil.NullInt64{N: &c.RollbackOfCampaignID},
+		&dbutil.NullInt64{N: &c.ParentCampaignID},
+		&c.AutoRebase,
+		&c.Position,
+		renamed,
 	)
+	c.Spec = spec
+	return err
 }
 
 func scanCampaignPlan(c *a8n.CampaignPlan, s scanner) error {
@@ -2700,6 +6055,22 @@ func scanChangesetJob(c *a8n.ChangesetJob, s scanner) error {
 		&c.CampaignJobID,
 		&dbutil.NullInt64{N: &c.ChangesetID},
 		&c.Branch,
+		&dbutil.NullString{S: (*string)(&c.BranchCollision)},
+		&dbutil.NullString{S: &c.Error},
+		&dbutil.NullTime{Time: &c.StartedAt},
+		&dbutil.NullTime{Time: &c.FinishedAt},
+		&dbutil.NullTime{Time: &c.ReviewersRequestedAt},
+		&dbutil.NullString{S: &c.ReviewersError},
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+}
+
+func scanChangesetCloseJob(c *a8n.ChangesetCloseJob, s scanner) error {
+	return s.Scan(
+		&c.ID,
+		&c.CampaignID,
+		&c.ChangesetID,
 		&dbutil.NullString{S: &c.Error},
 		&dbutil.NullTime{Time: &c.StartedAt},
 		&dbutil.NullTime{Time: &c.FinishedAt},
@@ -2708,6 +6079,32 @@ func scanChangesetJob(c *a8n.ChangesetJob, s scanner) error {
 	)
 }
 
+func scanWorkerJob(j *a8n.WorkerJob, s scanner) error {
+	var payload []byte
+
+	err := s.Scan(
+		&j.ID,
+		&j.Queue,
+		&payload,
+		&j.State,
+		&j.Attempts,
+		&j.MaxAttempts,
+		&dbutil.NullString{S: &j.Error},
+		&j.RunAfter,
+		&dbutil.NullTime{Time: &j.HeartbeatAt},
+		&dbutil.NullTime{Time: &j.StartedAt},
+		&dbutil.NullTime{Time: &j.FinishedAt},
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	j.Payload = payload
+	return nil
+}
+
 func scanBackgroundProcessStatus(b *a8n.BackgroundProcessStatus, s scanner) error {
 	return s.Scan(
 		&b.Canceled,
@@ -0,0 +1,113 @@
+package a8n
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtest"
+)
+
+// Ran in integration_test.go
+//
+// testStoreQueryPlans asserts that the queries generated by the Store for
+// commonly used filters are supported by an index, so that adding a filter
+// without a matching index doesn't go unnoticed until the tables are large.
+func testStoreQueryPlans(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			query  *sqlf.Query
+			tables []string
+		}{
+			{
+				name:   "ListCampaigns",
+				query:  listCampaignsQuery(&ListCampaignsOpts{}),
+				tables: []string{"campaigns"},
+			},
+			{
+				name:   "ListCampaigns by ChangesetID",
+				query:  listCampaignsQuery(&ListCampaignsOpts{ChangesetID: 1}),
+				tables: []string{"campaigns"},
+			},
+			{
+				name: "ListCampaigns by namespace",
+				query: listCampaignsQuery(&ListCampaignsOpts{
+					State:            a8n.CampaignStateOpen,
+					NamespaceUserIDs: []int32{1},
+					NamespaceOrgIDs:  []int32{2},
+				}),
+				tables: []string{"campaigns"},
+			},
+			{
+				name:   "CountCampaigns by ChangesetID",
+				query:  countCampaignsQuery(&CountCampaignsOpts{ChangesetID: 1}),
+				tables: []string{"campaigns"},
+			},
+			{
+				name:   "CountCampaigns by namespace",
+				query:  countCampaignsQuery(&CountCampaignsOpts{NamespaceUserIDs: []int32{1}}),
+				tables: []string{"campaigns"},
+			},
+			{
+				name:   "ListChangesets",
+				query:  listChangesetsQuery(&ListChangesetsOpts{}),
+				tables: []string{"changesets"},
+			},
+			{
+				name:   "ListChangesets by CampaignID",
+				query:  listChangesetsQuery(&ListChangesetsOpts{CampaignID: 1, WithoutDeleted: true}),
+				tables: []string{"changesets"},
+			},
+			{
+				name:   "ListChangesets by IDs",
+				query:  listChangesetsQuery(&ListChangesetsOpts{IDs: []int64{1, 2, 3}}),
+				tables: []string{"changesets"},
+			},
+			{
+				name:   "CountChangesets by CampaignID",
+				query:  countChangesetsQuery(&CountChangesetsOpts{CampaignID: 1}),
+				tables: []string{"changesets"},
+			},
+			{
+				name:   "ListChangesetEvents by ChangesetIDs",
+				query:  listChangesetEventsQuery(&ListChangesetEventsOpts{ChangesetIDs: []int64{1, 2}}),
+				tables: []string{"changeset_events"},
+			},
+			{
+				name:   "CountChangesetEvents by ChangesetID",
+				query:  countChangesetEventsQuery(&CountChangesetEventsOpts{ChangesetID: 1}),
+				tables: []string{"changeset_events"},
+			},
+			{
+				name:   "ListCampaignJobs by CampaignPlanID",
+				query:  listCampaignJobsQuery(&ListCampaignJobsOpts{CampaignPlanID: 1}),
+				tables: []string{"campaign_jobs"},
+			},
+			{
+				name:   "CountCampaignJobs by CampaignPlanID",
+				query:  countCampaignJobsQuery(&CountCampaignJobsOpts{CampaignPlanID: 1}),
+				tables: []string{"campaign_jobs"},
+			},
+			{
+				name:   "ListChangesetJobs by CampaignID",
+				query:  listChangesetJobsQuery(&ListChangesetJobsOpts{CampaignID: 1}),
+				tables: []string{"changeset_jobs"},
+			},
+			{
+				name:   "CountChangesetJobs by CampaignID",
+				query:  countChangesetJobsQuery(&CountChangesetJobsOpts{CampaignID: 1}),
+				tables: []string{"changeset_jobs"},
+			},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				tx, done := dbtest.NewTx(t, db)
+				defer done()
+
+				dbtest.AssertNoSeqScans(t, tx, tc.query, tc.tables...)
+			})
+		}
+	}
+}
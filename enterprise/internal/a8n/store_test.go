@@ -3,14 +3,18 @@ package a8n
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -57,6 +61,7 @@ func testStore(db *sql.DB) func(*testing.T) {
 						c.NamespaceOrgID = 23
 					} else {
 						c.NamespaceUserID = 42
+						c.AutoRebase = true
 					}
 
 					want := c.Clone()
@@ -74,6 +79,7 @@ func testStore(db *sql.DB) func(*testing.T) {
 					want.ID = have.ID
 					want.CreatedAt = now
 					want.UpdatedAt = now
+					want.Position = campaignPositionAt(now)
 
 					if diff := cmp.Diff(have, want); diff != "" {
 						t.Fatal(diff)
@@ -101,6 +107,12 @@ func testStore(db *sql.DB) func(*testing.T) {
 				if have, want := count, int64(1); have != want {
 					t.Fatalf("have count: %d, want: %d", have, want)
 				}
+
+				// The estimate depends on the statistics of the table, so
+				// only check that the query plan can be parsed.
+				if _, err = s.EstimateCampaignsCount(ctx, CountCampaignsOpts{Query: "campaign"}); err != nil {
+					t.Fatal(err)
+				}
 			})
 
 			t.Run("List", func(t *testing.T) {
@@ -206,6 +218,103 @@ func testStore(db *sql.DB) func(*testing.T) {
 						}
 					})
 				}
+
+				namespaceTests := []struct {
+					name    string
+					userIDs []int32
+					orgIDs  []int32
+					want    []*a8n.Campaign
+				}{
+					{
+						name:   "Org",
+						orgIDs: []int32{23},
+						want:   []*a8n.Campaign{campaigns[0], campaigns[2]},
+					},
+					{
+						name:    "User",
+						userIDs: []int32{42},
+						want:    campaigns[1:2],
+					},
+					{
+						name:    "UserOrOrg",
+						userIDs: []int32{42},
+						orgIDs:  []int32{23, 9999},
+						want:    campaigns,
+					},
+					{
+						name:   "NoMatch",
+						orgIDs: []int32{9999},
+						want:   []*a8n.Campaign{},
+					},
+				}
+
+				queryTests := []struct {
+					name  string
+					query string
+					want  []*a8n.Campaign
+				}{
+					{
+						name:  "Name",
+						query: "es-lint 1",
+						want:  campaigns[1:2],
+					},
+					{
+						name:  "Description",
+						query: "javascripts",
+						want:  campaigns,
+					},
+					{
+						name:  "NoMatch",
+						query: "python",
+						want:  []*a8n.Campaign{},
+					},
+				}
+
+				for _, tc := range queryTests {
+					t.Run("ListCampaigns Query "+tc.name, func(t *testing.T) {
+						have, _, err := s.ListCampaigns(ctx, ListCampaignsOpts{Query: tc.query})
+						if err != nil {
+							t.Fatal(err)
+						}
+						if diff := cmp.Diff(have, tc.want); diff != "" {
+							t.Fatal(diff)
+						}
+
+						count, err := s.CountCampaigns(ctx, CountCampaignsOpts{Query: tc.query})
+						if err != nil {
+							t.Fatal(err)
+						}
+						if have, want := count, int64(len(tc.want)); have != want {
+							t.Fatalf("have count: %d, want: %d", have, want)
+						}
+					})
+				}
+
+				for _, tc := range namespaceTests {
+					t.Run("ListCampaigns Namespaces "+tc.name, func(t *testing.T) {
+						have, _, err := s.ListCampaigns(ctx, ListCampaignsOpts{
+							NamespaceUserIDs: tc.userIDs,
+							NamespaceOrgIDs:  tc.orgIDs,
+						})
+						if err != nil {
+							t.Fatal(err)
+						}
+						if diff := cmp.Diff(have, tc.want); diff != "" {
+							t.Fatal(diff)
+						}
+
+						count, err := s.CountCampaigns(ctx, CountCampaignsOpts{
+							NamespaceUserIDs: tc.userIDs,
+							NamespaceOrgIDs:  tc.orgIDs,
+						})
+						if err != nil {
+							t.Fatal(err)
+						}
+						if have, want := count, int64(len(tc.want)); have != want {
+							t.Fatalf("have count: %d, want: %d", have, want)
+						}
+					})
+				}
 			})
 
 			t.Run("Update", func(t *testing.T) {
@@ -276,6 +385,76 @@ func testStore(db *sql.DB) func(*testing.T) {
 				}
 			})
 
+			t.Run("Position", func(t *testing.T) {
+				// Move the last closed campaign before the first one.
+				moved, first := campaigns[2], campaigns[1]
+
+				prev, err := s.GetPreviousCampaignPosition(ctx, GetPreviousCampaignPositionOpts{
+					State:     a8n.CampaignStateClosed,
+					Position:  first.Position,
+					ExcludeID: moved.ID,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if prev != "" {
+					t.Fatalf("have previous position %q, want none", prev)
+				}
+
+				last, err := s.GetPreviousCampaignPosition(ctx, GetPreviousCampaignPositionOpts{
+					State: a8n.CampaignStateOpen,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have, want := last, campaigns[0].Position; have != want {
+					t.Fatalf("have last position %q, want %q", have, want)
+				}
+
+				if moved.Position, err = campaignPositionBetween(prev, first.Position); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.UpdateCampaignPosition(ctx, moved); err != nil {
+					t.Fatal(err)
+				}
+
+				want := []*a8n.Campaign{moved, first}
+
+				have, _, err := s.ListCampaigns(ctx, ListCampaignsOpts{
+					State:           a8n.CampaignStateClosed,
+					OrderByPosition: true,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+
+				var cursor int64
+				for i := range want {
+					opts := ListCampaignsOpts{
+						State:           a8n.CampaignStateClosed,
+						OrderByPosition: true,
+						Cursor:          cursor,
+						Limit:           1,
+					}
+					have, next, err := s.ListCampaigns(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(have, want[i:i+1]); diff != "" {
+						t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					}
+					cursor = next
+				}
+
+				err = s.UpdateCampaignPosition(ctx, &a8n.Campaign{ID: 0xdeadbeef, Position: "V"})
+				if have, want := err, ErrNoResults; have != want {
+					t.Fatalf("have err %v, want %v", have, want)
+				}
+			})
+
 			t.Run("Get", func(t *testing.T) {
 				t.Run("ByID", func(t *testing.T) {
 					want := campaigns[0]
@@ -317,6 +496,57 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 			})
 
+			t.Run("GetByName", func(t *testing.T) {
+				t.Run("CurrentName", func(t *testing.T) {
+					want := campaigns[0]
+					opts := GetCampaignByNameOpts{Name: want.Name, NamespaceOrgID: want.NamespaceOrgID}
+
+					have, renamed, err := s.GetCampaignByName(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if renamed {
+						t.Fatal("campaign looked up by its current name should not be marked as renamed")
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("PreviousName", func(t *testing.T) {
+					want := campaigns[0]
+					// The Update test above renamed the campaign and moved it
+					// from org 23 to org 24.
+					opts := GetCampaignByNameOpts{Name: "Upgrade ES-Lint 0", NamespaceOrgID: 23}
+
+					have, renamed, err := s.GetCampaignByName(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if !renamed {
+						t.Fatal("campaign looked up by a previous name should be marked as renamed")
+					}
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+
+				t.Run("NoResults", func(t *testing.T) {
+					opts := GetCampaignByNameOpts{Name: "does not exist"}
+
+					_, _, have := s.GetCampaignByName(ctx, opts)
+					want := ErrNoResults
+
+					if have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
+				})
+			})
+
 			t.Run("Delete", func(t *testing.T) {
 				for i := range campaigns {
 					err := s.DeleteCampaign(ctx, campaigns[i].ID)
@@ -335,6 +565,50 @@ func testStore(db *sql.DB) func(*testing.T) {
 				}
 			})
 
+			t.Run("Restore", func(t *testing.T) {
+				opts := GetCampaignOpts{ID: campaigns[0].ID, DeletedAfter: clock().Add(-time.Hour)}
+				if _, err := s.GetCampaign(ctx, opts); err != nil {
+					t.Fatal(err)
+				}
+
+				err := s.RestoreCampaign(ctx, campaigns[0].ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				have, err := s.GetCampaign(ctx, GetCampaignOpts{ID: campaigns[0].ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have.ID != campaigns[0].ID {
+					t.Fatalf("have campaign %d, want %d", have.ID, campaigns[0].ID)
+				}
+
+				if err := s.DeleteCampaign(ctx, campaigns[0].ID); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			t.Run("PurgeDeleted", func(t *testing.T) {
+				opts := GetCampaignOpts{ID: campaigns[0].ID, DeletedAfter: clock().Add(-time.Hour)}
+
+				err := s.PurgeDeletedCampaigns(ctx, time.Hour)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := s.GetCampaign(ctx, opts); err != nil {
+					t.Fatalf("campaign within retention period was purged: %v", err)
+				}
+
+				err = s.PurgeDeletedCampaigns(ctx, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := s.GetCampaign(ctx, opts); err != ErrNoResults {
+					t.Fatalf("have err %v, want %v", err, ErrNoResults)
+				}
+			})
+
 		})
 
 		t.Run("Changesets", func(t *testing.T) {
@@ -743,6 +1017,97 @@ func testStore(db *sql.DB) func(*testing.T) {
 					t.Fatal(diff)
 				}
 			})
+
+			t.Run("UpdateDiffStat", func(t *testing.T) {
+				c := changesets[0].Clone()
+				c.SetDiffStat(&diff.Stat{Added: 1, Changed: 2, Deleted: 3})
+
+				updated, err := s.UpdateChangesetDiffStat(ctx, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !updated {
+					t.Fatal("diff stat not updated")
+				}
+
+				have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: c.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(have, c); diff != "" {
+					t.Fatal(diff)
+				}
+
+				// The diff stat of a changeset that was updated since it was
+				// read is not saved.
+				stale := c.Clone()
+				stale.UpdatedAt = stale.UpdatedAt.Add(-time.Second)
+				stale.SetDiffStat(nil)
+
+				updated, err = s.UpdateChangesetDiffStat(ctx, stale)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if updated {
+					t.Fatal("stale diff stat updated")
+				}
+
+				listed, _, err := s.ListChangesets(ctx, ListChangesetsOpts{WithoutDiffStat: true, Limit: -1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, l := range listed {
+					if l.ID == c.ID {
+						t.Fatalf("changeset with diff stat listed: %d", c.ID)
+					}
+				}
+			})
+
+			t.Run("UpdateConflicts", func(t *testing.T) {
+				c := changesets[1].Clone()
+				hasConflicts := true
+				c.HasConflicts = &hasConflicts
+				c.ConflictsBaseOid = "deadbeef"
+				c.ConflictsHeadOid = "cafebabe"
+
+				updated, err := s.UpdateChangesetConflicts(ctx, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !updated {
+					t.Fatal("conflicts not updated")
+				}
+
+				have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: c.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(have, c); diff != "" {
+					t.Fatal(diff)
+				}
+
+				count, err := s.CountChangesets(ctx, CountChangesetsOpts{HasConflicts: true})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have, want := count, int64(1); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
+
+				// The conflicts of a changeset that was updated since it was
+				// read are not saved.
+				stale := c.Clone()
+				stale.UpdatedAt = stale.UpdatedAt.Add(-time.Second)
+				stale.HasConflicts = nil
+
+				updated, err = s.UpdateChangesetConflicts(ctx, stale)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if updated {
+					t.Fatal("stale conflicts updated")
+				}
+			})
 		})
 
 		t.Run("ChangesetEvents", func(t *testing.T) {
@@ -963,6 +1328,36 @@ func testStore(db *sql.DB) func(*testing.T) {
 					}
 				})
 
+				t.Run("ByKinds", func(t *testing.T) {
+					for _, tc := range []struct {
+						kinds []a8n.ChangesetEventKind
+						want  []*a8n.ChangesetEvent
+					}{
+						{
+							kinds: []a8n.ChangesetEventKind{a8n.ChangesetEventKindGitHubCommented},
+							want:  events,
+						},
+						{
+							kinds: []a8n.ChangesetEventKind{
+								a8n.ChangesetEventKindGitHubMerged,
+								a8n.ChangesetEventKindBitbucketServerMerged,
+							},
+							want: []*a8n.ChangesetEvent{},
+						},
+					} {
+						opts := ListChangesetEventsOpts{Kinds: tc.kinds, Limit: -1}
+
+						have, _, err := s.ListChangesetEvents(ctx, opts)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						if diff := cmp.Diff(have, tc.want); diff != "" {
+							t.Fatalf("opts: %+v, diff: %s", opts, diff)
+						}
+					}
+				})
+
 				t.Run("EmptyResultListingAll", func(t *testing.T) {
 					opts := ListChangesetEventsOpts{ChangesetIDs: []int64{99999}, Limit: -1}
 
@@ -1574,6 +1969,69 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 			})
 
+			t.Run("LogChunks", func(t *testing.T) {
+				job := campaignJobs[0]
+				// The chunk boundaries of the large output fall inside runes.
+				large := "x" + strings.Repeat("ä", maxCampaignJobLogChunkSize)
+
+				for _, output := range []string{"first\n", "second\n", large} {
+					if err := s.AppendCampaignJobLog(ctx, job.ID, output); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				chunks, next, err := s.ListCampaignJobLogChunks(ctx, ListCampaignJobLogChunksOpts{
+					CampaignJobID: job.ID,
+					Limit:         -1,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if next != 0 {
+					t.Fatalf("have next %d, want 0", next)
+				}
+
+				if have, want := len(chunks), 5; have != want {
+					t.Fatalf("have %d chunks, want %d", have, want)
+				}
+
+				var log strings.Builder
+				for _, c := range chunks {
+					if c.CampaignJobID != job.ID {
+						t.Fatalf("chunk %d has campaign job ID %d, want %d", c.ID, c.CampaignJobID, job.ID)
+					}
+					if len(c.Output) > maxCampaignJobLogChunkSize || !utf8.ValidString(c.Output) {
+						t.Fatalf("chunk %d has invalid output of %d bytes", c.ID, len(c.Output))
+					}
+					log.WriteString(c.Output)
+				}
+				if have, want := log.String(), "first\nsecond\n"+large; have != want {
+					t.Fatalf("have log of %d bytes, want %d", len(have), len(want))
+				}
+
+				t.Run("WithCursor", func(t *testing.T) {
+					opts := ListCampaignJobLogChunksOpts{CampaignJobID: job.ID, Cursor: chunks[1].ID, Limit: 1}
+					have, next, err := s.ListCampaignJobLogChunks(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, chunks[1:2]); diff != "" {
+						t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					}
+					if want := chunks[2].ID; next != want {
+						t.Fatalf("opts: %+v: have next %d, want %d", opts, next, want)
+					}
+				})
+
+				t.Run("NoResults", func(t *testing.T) {
+					have := s.AppendCampaignJobLog(ctx, 0xdeadbeef, "output")
+					if want := ErrNoResults; have != want {
+						t.Fatalf("have err %v, want %v", have, want)
+					}
+				})
+			})
+
 			t.Run("Delete", func(t *testing.T) {
 				for i := range campaignJobs {
 					err := s.DeleteCampaignJob(ctx, campaignJobs[i].ID)
@@ -1882,6 +2340,9 @@ func testStore(db *sql.DB) func(*testing.T) {
 						StartedAt:     now,
 						FinishedAt:    now,
 					}
+					if i == 0 {
+						c.BranchCollision = a8n.BranchCollisionSuffixed
+					}
 
 					want := c.Clone()
 					have := c
@@ -2523,169 +2984,1634 @@ func testStore(db *sql.DB) func(*testing.T) {
 			})
 
 		})
-	}
-}
 
-func testProcessCampaignJob(db *sql.DB) func(*testing.T) {
-	return func(t *testing.T) {
-		now := time.Now().UTC().Truncate(time.Microsecond)
-		clock := func() time.Time { return now.UTC().Truncate(time.Microsecond) }
-		ctx := context.Background()
+		t.Run("ChangesetCloseJobs", func(t *testing.T) {
+			closeJobs := make([]*a8n.ChangesetCloseJob, 0, 3)
 
-		// Create a test repo
-		reposStore := repos.NewDBStore(db, sql.TxOptions{})
-		repo := &repos.Repo{
-			Name: fmt.Sprintf("github.com/sourcegraph/sourcegraph"),
-			ExternalRepo: api.ExternalRepoSpec{
-				ID:          "external-id",
-				ServiceType: "github",
-				ServiceID:   "https://github.com/",
-			},
-			Sources: map[string]*repos.SourceInfo{
-				"extsvc:github:4": {
-					ID:       "extsvc:github:4",
-					CloneURL: "https://secrettoken@github.com/sourcegraph/sourcegraph",
-				},
-			},
-		}
-		if err := reposStore.UpsertRepos(context.Background(), repo); err != nil {
-			t.Fatal(err)
-		}
+			t.Run("Create", func(t *testing.T) {
+				for i := 0; i < cap(closeJobs); i++ {
+					c := &a8n.ChangesetCloseJob{
+						CampaignID:  int64(i%2 + 1),
+						ChangesetID: int64(i + 1),
+					}
 
-		t.Run("GetPendingCampaignJobsWhenNoneAvailable", func(t *testing.T) {
-			tx, done := dbtest.NewTx(t, db)
-			defer done()
-			s := NewStoreWithClock(tx, clock)
+					want := c.Clone()
+					have := c
 
-			process := func(ctx context.Context, s *Store, job a8n.CampaignJob) error {
-				return errors.New("rollback")
+					err := s.CreateChangesetCloseJob(ctx, have)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have.ID == 0 {
+						t.Fatal("ID should not be zero")
+					}
+
+					want.ID = have.ID
+					want.CreatedAt = now
+					want.UpdatedAt = now
+
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatal(diff)
+					}
+
+					closeJobs = append(closeJobs, c)
+				}
+			})
+
+			t.Run("List", func(t *testing.T) {
+				opts := ListChangesetCloseJobsOpts{CampaignID: 1}
+
+				have, next, err := s.ListChangesetCloseJobs(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if next != 0 {
+					t.Fatalf("have next %d, want 0", next)
+				}
+
+				want := []*a8n.ChangesetCloseJob{closeJobs[0], closeJobs[2]}
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatalf("opts: %+v, diff: %s", opts, diff)
+				}
+			})
+
+			t.Run("Update", func(t *testing.T) {
+				for _, c := range closeJobs {
+					c.StartedAt = now.Add(-time.Minute)
+					c.FinishedAt = now
+					if c.ChangesetID == 3 {
+						c.Error = "closing failed"
+					}
+
+					want := c.Clone()
+					if err := s.UpdateChangesetCloseJob(ctx, c); err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(c, want); diff != "" {
+						t.Fatal(diff)
+					}
+				}
+			})
+
+			t.Run("GetCampaignCloseStatus", func(t *testing.T) {
+				have, err := s.GetCampaignCloseStatus(ctx, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := &a8n.BackgroundProcessStatus{
+					ProcessState:  a8n.BackgroundProcessStateErrored,
+					Total:         2,
+					Completed:     2,
+					Pending:       0,
+					ProcessErrors: []string{"closing failed"},
+				}
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+
+			t.Run("CreateResetsExisting", func(t *testing.T) {
+				c := &a8n.ChangesetCloseJob{
+					CampaignID:  closeJobs[2].CampaignID,
+					ChangesetID: closeJobs[2].ChangesetID,
+				}
+
+				if err := s.CreateChangesetCloseJob(ctx, c); err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := c.ID, closeJobs[2].ID; have != want {
+					t.Fatalf("have ID %d, want %d", have, want)
+				}
+
+				if c.Error != "" || !c.StartedAt.IsZero() || !c.FinishedAt.IsZero() {
+					t.Fatalf("job was not reset: %+v", c)
+				}
+
+				status, err := s.GetCampaignCloseStatus(ctx, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := status.ProcessState, a8n.BackgroundProcessStateProcessing; have != want {
+					t.Fatalf("have state %q, want %q", have, want)
+				}
+			})
+		})
+
+		t.Run("GetCampaignMetrics", func(t *testing.T) {
+			opts := GetCampaignMetricsOpts{
+				Period:  a8n.CampaignMetricsPeriodDay,
+				Periods: 3,
+				Now:     now,
 			}
-			ran, err := s.ProcessPendingCampaignJob(ctx, process)
+
+			before, err := s.GetCampaignMetrics(ctx, opts)
 			if err != nil {
 				t.Fatal(err)
 			}
-			if ran {
-				// We shouldn't have any pending jobs yet
-				t.Fatalf("process function should not have run")
-			}
-		})
 
-		t.Run("GetPendingCampaignJobsWhenAvailable", func(t *testing.T) {
-			tx, done := dbtest.NewTx(t, db)
-			defer done()
-			s := NewStoreWithClock(tx, clock)
+			if have, want := len(before), opts.Periods; have != want {
+				t.Fatalf("wrong number of periods. have=%d, want=%d", have, want)
+			}
 
-			process := func(ctx context.Context, s *Store, job a8n.CampaignJob) error {
-				return errors.New("rollback")
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			if have, want := before[len(before)-1].StartTime.UTC(), today; !have.Equal(want) {
+				t.Fatalf("wrong start time of last period. have=%s, want=%s", have, want)
 			}
-			plan := &a8n.CampaignPlan{
-				CampaignType: "test",
+
+			campaign := &a8n.Campaign{
+				Name:            "Campaign metrics",
+				AuthorID:        23,
+				NamespaceUserID: 23,
+				CampaignPlanID:  1234,
 			}
-			err := s.CreateCampaignPlan(context.Background(), plan)
-			if err != nil {
+			if err := s.CreateCampaign(ctx, campaign); err != nil {
 				t.Fatal(err)
 			}
-			job := &a8n.CampaignJob{
-				ID:             0,
-				CampaignPlanID: plan.ID,
-				RepoID:         repo.ID,
-				Rev:            "",
-				BaseRef:        "abc",
-				Diff:           "",
-				Description:    "",
-				Error:          "",
+
+			timeToMerge := 48 * time.Hour
+			changeset := &a8n.Changeset{
+				RepoID:              42,
+				CampaignIDs:         []int64{campaign.ID},
+				ExternalID:          "campaign-metrics",
+				ExternalServiceType: github.ServiceType,
+				Metadata:            &github.PullRequest{CreatedAt: now.Add(-timeToMerge)},
 			}
-			err = s.CreateCampaignJob(context.Background(), job)
-			if err != nil {
+			if err := s.CreateChangesets(ctx, changeset); err != nil {
 				t.Fatal(err)
 			}
-			ran, err := s.ProcessPendingCampaignJob(ctx, process)
-			if err != nil && err.Error() != "rollback" {
+
+			merged := &github.MergedEvent{CreatedAt: now}
+			err = s.UpsertChangesetEvents(ctx, &a8n.ChangesetEvent{
+				ChangesetID: changeset.ID,
+				Kind:        a8n.ChangesetEventKindGitHubMerged,
+				Key:         merged.Key(),
+				Metadata:    merged,
+			})
+			if err != nil {
 				t.Fatal(err)
 			}
-			if !ran {
-				// We shouldn't have any pending jobs yet
-				t.Fatalf("process function should have run")
+
+			after, err := s.GetCampaignMetrics(ctx, opts)
+			if err != nil {
+				t.Fatal(err)
 			}
-		})
 
-		t.Run("GetPendingCampaignJobsWhenAvailableLocking", func(t *testing.T) {
-			dbtesting.SetupGlobalTestDB(t)
-			user := createTestUser(ctx, t)
-			s := NewStoreWithClock(db, clock)
+			last, lastBefore := after[len(after)-1], before[len(before)-1]
+			if have, want := last.CampaignsCreated, lastBefore.CampaignsCreated+1; have != want {
+				t.Errorf("wrong number of created campaigns. have=%d, want=%d", have, want)
+			}
 
-			process := func(ctx context.Context, s *Store, job a8n.CampaignJob) error {
-				time.Sleep(100 * time.Millisecond)
-				return errors.New("rollback")
+			if have, want := last.ChangesetsMerged, lastBefore.ChangesetsMerged+1; have != want {
+				t.Errorf("wrong number of merged changesets. have=%d, want=%d", have, want)
 			}
-			plan := &a8n.CampaignPlan{
-				CampaignType: "test",
-				UserID:       user.ID,
+
+			if lastBefore.ChangesetsMerged == 0 {
+				if last.MedianTimeToMerge == nil || *last.MedianTimeToMerge != timeToMerge {
+					t.Errorf("wrong median time to merge. have=%v, want=%s", last.MedianTimeToMerge, timeToMerge)
+				}
 			}
-			err := s.CreateCampaignPlan(context.Background(), plan)
-			if err != nil {
-				t.Fatal(err)
+		})
+
+		t.Run("GetCampaignFacets", func(t *testing.T) {
+			const namespaceOrgID = 4711
+
+			for i, c := range []struct {
+				authorID int32
+				closed   bool
+			}{
+				{authorID: 1},
+				{authorID: 1, closed: true},
+				{authorID: 2},
+			} {
+				campaign := &a8n.Campaign{
+					Name:           fmt.Sprintf("Campaign facets %d", i),
+					AuthorID:       c.authorID,
+					NamespaceOrgID: namespaceOrgID,
+				}
+				if c.closed {
+					campaign.ClosedAt = now
+				}
+				if err := s.CreateCampaign(ctx, campaign); err != nil {
+					t.Fatal(err)
+				}
 			}
-			err = s.CreateCampaignJob(context.Background(), &a8n.CampaignJob{
-				ID:             0,
-				CampaignPlanID: plan.ID,
-				RepoID:         repo.ID,
-				Rev:            "",
-				BaseRef:        "abc",
-				Diff:           "",
-				Description:    "",
-				Error:          "",
+
+			have, err := s.GetCampaignFacets(ctx, GetCampaignFacetsOpts{
+				NamespaceOrgIDs: []int32{namespaceOrgID},
 			})
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			var runCount int64
-			errChan := make(chan error, 2)
-
-			for i := 0; i < 2; i++ {
-				go func() {
-					ran, err := s.ProcessPendingCampaignJob(ctx, process)
-					if ran {
-						atomic.AddInt64(&runCount, 1)
-					}
-					errChan <- err
-				}()
+			want := &a8n.CampaignFacets{
+				States: map[a8n.CampaignState]int32{
+					a8n.CampaignStateOpen:   2,
+					a8n.CampaignStateClosed: 1,
+				},
+				Authors: map[int32]int32{1: 2, 2: 1},
 			}
-			for i := 0; i < 2; i++ {
-				err := <-errChan
-				if err != nil && err.Error() != "rollback" {
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
+		t.Run("ListCampaignRepositoryActivity", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			const (
+				namespaceOrgID      = 4713
+				otherNamespaceOrgID = 4714
+				repo1               = api.RepoID(101)
+				repo2               = api.RepoID(102)
+			)
+
+			createCampaign := func(name string, orgID int32, closed bool) *a8n.Campaign {
+				c := &a8n.Campaign{Name: name, AuthorID: 1, NamespaceOrgID: orgID}
+				if closed {
+					c.ClosedAt = now
+				}
+				if err := s.CreateCampaign(ctx, c); err != nil {
 					t.Fatal(err)
 				}
+				return c
 			}
+			open := createCampaign("Repository activity open", namespaceOrgID, false)
+			closed := createCampaign("Repository activity closed", namespaceOrgID, true)
+			other := createCampaign("Repository activity other", otherNamespaceOrgID, false)
+
+			var changesets int
+			createChangeset := func(repoID api.RepoID, state string, mergedAt time.Time, campaigns ...*a8n.Campaign) {
+				changesets++
+				c := &a8n.Changeset{
+					RepoID:              repoID,
+					ExternalID:          fmt.Sprintf("repository-activity-%d", changesets),
+					ExternalServiceType: github.ServiceType,
+					Metadata:            &github.PullRequest{State: state},
+				}
+				for _, campaign := range campaigns {
+					c.CampaignIDs = append(c.CampaignIDs, campaign.ID)
+				}
+				if err := s.CreateChangesets(ctx, c); err != nil {
+					t.Fatal(err)
+				}
 
-			rc := atomic.LoadInt64(&runCount)
-			if rc != 1 {
-				t.Errorf("Want %d, got %d", 1, rc)
+				if mergedAt.IsZero() {
+					return
+				}
+				merged := &github.MergedEvent{CreatedAt: mergedAt}
+				err := s.UpsertChangesetEvents(ctx, &a8n.ChangesetEvent{
+					ChangesetID: c.ID,
+					Kind:        a8n.ChangesetEventKindGitHubMerged,
+					Key:         merged.Key(),
+					Metadata:    merged,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			createChangeset(repo1, "OPEN", time.Time{}, open, closed)
+			createChangeset(repo1, "MERGED", now, open)
+			createChangeset(repo1, "OPEN", time.Time{}, other)
+			createChangeset(repo2, "OPEN", time.Time{}, closed)
+			createChangeset(repo2, "OPEN", time.Time{}, open)
+			createChangeset(repo2, "MERGED", now.Add(-14*24*time.Hour), open)
+
+			for _, tc := range []struct {
+				name string
+				opts ListCampaignRepositoryActivityOpts
+				want []*a8n.CampaignRepositoryActivity
+			}{
+				{
+					name: "namespace",
+					opts: ListCampaignRepositoryActivityOpts{NamespaceOrgIDs: []int32{namespaceOrgID}},
+					want: []*a8n.CampaignRepositoryActivity{
+						{RepoID: repo2, OpenCampaigns: 1, OpenChangesets: 2},
+						{RepoID: repo1, OpenCampaigns: 1, OpenChangesets: 1, ChangesetsMergedThisWeek: 1},
+					},
+				},
+				{
+					name: "repository",
+					opts: ListCampaignRepositoryActivityOpts{RepoIDs: []api.RepoID{repo1}},
+					want: []*a8n.CampaignRepositoryActivity{
+						{RepoID: repo1, OpenCampaigns: 2, OpenChangesets: 2, ChangesetsMergedThisWeek: 1},
+					},
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					have, err := s.ListCampaignRepositoryActivity(ctx, tc.opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(have, tc.want); diff != "" {
+						t.Fatal(diff)
+					}
+				})
 			}
-		})
-	}
-}
-
-func testStoreLocking(db *sql.DB) func(*testing.T) {
-	return func(t *testing.T) {
-		now := time.Now().UTC().Truncate(time.Microsecond)
-		s := NewStoreWithClock(db, func() time.Time {
-			return now.UTC().Truncate(time.Microsecond)
 		})
 
-		testKey := "test-acquire"
-		s1, err := s.Transact(context.Background())
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer s1.Done(nil)
+		t.Run("ListCampaignsByChangesetState", func(t *testing.T) {
+			const namespaceUserID = 4712
 
-		s2, err := s.Transact(context.Background())
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer s2.Done(nil)
+			states := map[string]string{
+				"open":     "OPEN",
+				"merged":   "MERGED",
+				"declined": "DECLINED",
+			}
+			ids := make(map[string]int64, len(states))
+			for name, state := range states {
+				campaign := &a8n.Campaign{
+					Name:            "Changeset state " + name,
+					AuthorID:        23,
+					NamespaceUserID: namespaceUserID,
+				}
+				if err := s.CreateCampaign(ctx, campaign); err != nil {
+					t.Fatal(err)
+				}
+				ids[name] = campaign.ID
+
+				changeset := &a8n.Changeset{
+					RepoID:      42,
+					CampaignIDs: []int64{campaign.ID},
+					ExternalID:  "changeset-state-" + name,
+				}
+				if state == "DECLINED" {
+					changeset.ExternalServiceType = bitbucketserver.ServiceType
+					changeset.Metadata = &bitbucketserver.PullRequest{State: state}
+				} else {
+					changeset.ExternalServiceType = github.ServiceType
+					changeset.Metadata = &github.PullRequest{State: state}
+				}
+				if err := s.CreateChangesets(ctx, changeset); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, tc := range []struct {
+				name string
+				opts ListCampaignsOpts
+				want []int64
+			}{
+				{
+					name: "HasOpenChangesets",
+					opts: ListCampaignsOpts{HasOpenChangesets: true},
+					want: []int64{ids["open"]},
+				},
+				{
+					name: "Merged",
+					opts: ListCampaignsOpts{ChangesetState: a8n.ChangesetStateMerged},
+					want: []int64{ids["merged"]},
+				},
+				{
+					name: "Closed",
+					opts: ListCampaignsOpts{ChangesetState: a8n.ChangesetStateClosed},
+					want: []int64{ids["declined"]},
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.opts.NamespaceUserIDs = []int32{namespaceUserID}
+
+					cs, _, err := s.ListCampaigns(ctx, tc.opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					have := make([]int64, 0, len(cs))
+					for _, c := range cs {
+						have = append(have, c.ID)
+					}
+					if diff := cmp.Diff(have, tc.want); diff != "" {
+						t.Fatal(diff)
+					}
+
+					count, err := s.CountCampaigns(ctx, CountCampaignsOpts{
+						NamespaceUserIDs:  tc.opts.NamespaceUserIDs,
+						HasOpenChangesets: tc.opts.HasOpenChangesets,
+						ChangesetState:    tc.opts.ChangesetState,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if have, want := count, int64(len(tc.want)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
+					}
+				})
+			}
+		})
+
+		t.Run("CampaignHierarchy", func(t *testing.T) {
+			var parentID int64
+			ids := make([]int64, 0, 3)
+			for i := 0; i < 3; i++ {
+				campaign := &a8n.Campaign{
+					Name:             fmt.Sprintf("Campaign hierarchy %d", i),
+					AuthorID:         23,
+					NamespaceUserID:  23,
+					ParentCampaignID: parentID,
+				}
+				if err := s.CreateCampaign(ctx, campaign); err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, campaign.ID)
+				parentID = campaign.ID
+			}
+
+			// The open changeset belongs to both the root and its child, but
+			// is only counted once.
+			changesets := []*a8n.Changeset{
+				{
+					CampaignIDs:         []int64{ids[0], ids[1]},
+					ExternalServiceType: github.ServiceType,
+					Metadata:            &github.PullRequest{State: "OPEN"},
+				},
+				{
+					CampaignIDs:         []int64{ids[1]},
+					ExternalServiceType: github.ServiceType,
+					Metadata:            &github.PullRequest{State: "MERGED"},
+				},
+				{
+					CampaignIDs:         []int64{ids[2]},
+					ExternalServiceType: bitbucketserver.ServiceType,
+					Metadata:            &bitbucketserver.PullRequest{State: "DECLINED"},
+				},
+			}
+			for i, c := range changesets {
+				c.RepoID = 42
+				c.ExternalID = fmt.Sprintf("campaign-hierarchy-%d", i)
+			}
+			if err := s.CreateChangesets(ctx, changesets...); err != nil {
+				t.Fatal(err)
+			}
+
+			children, _, err := s.ListCampaigns(ctx, ListCampaignsOpts{ParentCampaignID: ids[0]})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(children) != 1 || children[0].ID != ids[1] || children[0].ParentCampaignID != ids[0] {
+				t.Fatalf("unexpected children: %+v", children)
+			}
+
+			for i, want := range []*a8n.CampaignProgress{
+				{Total: 3, Open: 1, Merged: 1, Closed: 1},
+				{Total: 3, Open: 1, Merged: 1, Closed: 1},
+				{Total: 1, Closed: 1},
+			} {
+				have, err := s.GetCampaignProgress(ctx, ids[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatalf("campaign %d: %s", i, diff)
+				}
+			}
+		})
+
+		t.Run("WorkerJobs", func(t *testing.T) {
+			const queue = "test"
+
+			queued := make([]*a8n.WorkerJob, 0, 2)
+			for i := 0; i < cap(queued); i++ {
+				j := &a8n.WorkerJob{
+					Queue:       queue,
+					Payload:     json.RawMessage(fmt.Sprintf(`{"n": %d}`, i)),
+					MaxAttempts: 2,
+				}
+				if err := s.EnqueueWorkerJob(ctx, j); err != nil {
+					t.Fatal(err)
+				}
+				if j.ID == 0 || j.State != a8n.WorkerJobStateQueued || !j.RunAfter.Equal(now) {
+					t.Fatalf("enqueued job has wrong fields: %+v", j)
+				}
+				queued = append(queued, j)
+			}
+
+			// Jobs that aren't due yet aren't dequeued.
+			later := &a8n.WorkerJob{Queue: queue, RunAfter: now.Add(time.Hour)}
+			if err := s.EnqueueWorkerJob(ctx, later); err != nil {
+				t.Fatal(err)
+			}
+
+			dequeue := func(t *testing.T) *a8n.WorkerJob {
+				t.Helper()
+				j, err := s.DequeueWorkerJob(ctx, queue, time.Minute)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return j
+			}
+
+			first := dequeue(t)
+			if first.ID != queued[0].ID || first.State != a8n.WorkerJobStateProcessing || first.Attempts != 1 {
+				t.Fatalf("dequeued wrong job: %+v", first)
+			}
+
+			second := dequeue(t)
+			if second.ID != queued[1].ID {
+				t.Fatalf("dequeued wrong job: %+v", second)
+			}
+
+			if _, err := s.DequeueWorkerJob(ctx, queue, time.Minute); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			if err := s.HeartbeatWorkerJob(ctx, first); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.CompleteWorkerJob(ctx, first); err != nil {
+				t.Fatal(err)
+			}
+			if first.State != a8n.WorkerJobStateCompleted || first.FinishedAt.IsZero() {
+				t.Fatalf("job not completed: %+v", first)
+			}
+
+			// The first failure is retried.
+			if err := s.FailWorkerJob(ctx, second, errors.New("boom"), 0); err != nil {
+				t.Fatal(err)
+			}
+			if second.State != a8n.WorkerJobStateQueued || second.Error != "boom" {
+				t.Fatalf("failed job not queued again: %+v", second)
+			}
+
+			retried := dequeue(t)
+			if retried.ID != second.ID || retried.Attempts != 2 {
+				t.Fatalf("dequeued wrong job: %+v", retried)
+			}
+
+			// An attempt that isn't current anymore can't update the job.
+			if err := s.CompleteWorkerJob(ctx, second); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			// The last failure moves the job to the dead-letter state.
+			if err := s.FailWorkerJob(ctx, retried, errors.New("boom again"), 0); err != nil {
+				t.Fatal(err)
+			}
+			if retried.State != a8n.WorkerJobStateDead || retried.FinishedAt.IsZero() {
+				t.Fatalf("failed job not dead: %+v", retried)
+			}
+
+			stats, err := s.GetWorkerQueueStats(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have *a8n.WorkerQueueStats
+			for _, st := range stats {
+				if st.Queue == queue {
+					have = st
+				}
+			}
+
+			want := &a8n.WorkerQueueStats{
+				Queue:          queue,
+				Queued:         1,
+				Completed:      1,
+				Dead:           1,
+				OldestQueuedAt: later.CreatedAt,
+			}
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+
+			failed, _, err := s.ListWorkerJobs(ctx, ListWorkerJobsOpts{Queue: queue, OnlyFailed: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(failed, []*a8n.WorkerJob{retried}); diff != "" {
+				t.Fatal(diff)
+			}
+
+			if err := s.DeleteCompletedWorkerJobs(ctx, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			completed, _, err := s.ListWorkerJobs(ctx, ListWorkerJobsOpts{Queue: queue, State: a8n.WorkerJobStateCompleted})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(completed) != 0 {
+				t.Fatalf("completed jobs not deleted: %+v", completed)
+			}
+		})
+
+		t.Run("IdempotencyKeys", func(t *testing.T) {
+			var userID int32
+			err := tx.QueryRow("INSERT INTO users (username) VALUES ('idempotency-key-user') RETURNING id").Scan(&userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := GetIdempotencyKeyOpts{UserID: userID, Mutation: "createCampaign", Key: "retry-1"}
+			k := &a8n.IdempotencyKey{UserID: opts.UserID, Mutation: opts.Mutation, Key: opts.Key}
+
+			created, err := s.CreateIdempotencyKey(ctx, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !created {
+				t.Fatal("idempotency key not created")
+			}
+
+			dup := *k
+			created, err = s.CreateIdempotencyKey(ctx, &dup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if created {
+				t.Fatal("existing idempotency key created again")
+			}
+
+			have, err := s.GetIdempotencyKey(ctx, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, k); diff != "" {
+				t.Fatal(diff)
+			}
+
+			k.ResultIDs = []int64{3, 1, 2}
+			if err = s.UpdateIdempotencyKeyResult(ctx, k); err != nil {
+				t.Fatal(err)
+			}
+
+			have, err = s.GetIdempotencyKey(ctx, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, k); diff != "" {
+				t.Fatal(diff)
+			}
+
+			expiredOpts := GetIdempotencyKeyOpts{UserID: userID, Mutation: "createCampaign", Key: "retry-2"}
+			expired := &a8n.IdempotencyKey{
+				UserID:    expiredOpts.UserID,
+				Mutation:  expiredOpts.Mutation,
+				Key:       expiredOpts.Key,
+				CreatedAt: now.Add(-IdempotencyKeyTTL),
+			}
+			if _, err = s.CreateIdempotencyKey(ctx, expired); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = s.GetIdempotencyKey(ctx, expiredOpts); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			if err = s.DeleteExpiredIdempotencyKeys(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			// The expired key is gone, so it can be created again.
+			created, err = s.CreateIdempotencyKey(ctx, expired)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !created {
+				t.Fatal("expired idempotency key not deleted")
+			}
+
+			if err = s.DeleteIdempotencyKey(ctx, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = s.GetIdempotencyKey(ctx, opts); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+		})
+
+		t.Run("CampaignSavedFilters", func(t *testing.T) {
+			var userID int32
+			err := tx.QueryRow("INSERT INTO users (username) VALUES ('saved-filters-user') RETURNING id").Scan(&userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			open := &a8n.CampaignSavedFilter{
+				UserID:    userID,
+				Name:      "Open with unmerged work",
+				Filters:   a8n.CampaignListFilters{State: a8n.CampaignStateOpen, HasOpenChangesets: true},
+				IsDefault: true,
+			}
+			mine := &a8n.CampaignSavedFilter{
+				UserID:  userID,
+				Name:    "Mine",
+				Filters: a8n.CampaignListFilters{Query: "eslint", NamespaceUserIDs: []int32{userID}},
+			}
+			for _, f := range []*a8n.CampaignSavedFilter{open, mine} {
+				if err := s.SaveCampaignSavedFilter(ctx, f); err != nil {
+					t.Fatal(err)
+				}
+				if f.ID == 0 {
+					t.Fatalf("filter %q has no ID", f.Name)
+				}
+			}
+
+			have, err := s.GetCampaignSavedFilter(ctx, mine.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, mine); diff != "" {
+				t.Fatal(diff)
+			}
+
+			// Saving a filter with an existing name overwrites it.
+			overwrite := mine.Clone()
+			overwrite.ID = 0
+			overwrite.Filters = a8n.CampaignListFilters{ChangesetState: a8n.ChangesetStateMerged}
+			overwrite.IsDefault = true
+			if err := s.SaveCampaignSavedFilter(ctx, overwrite); err != nil {
+				t.Fatal(err)
+			}
+			if overwrite.ID != mine.ID {
+				t.Fatalf("have ID %d, want %d", overwrite.ID, mine.ID)
+			}
+
+			isDefault := func(fs []*a8n.CampaignSavedFilter) map[string]bool {
+				m := make(map[string]bool, len(fs))
+				for _, f := range fs {
+					m[f.Name] = f.IsDefault
+				}
+				return m
+			}
+
+			listed, err := s.ListCampaignSavedFilters(ctx, ListCampaignSavedFiltersOpts{UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(listed) != 2 || listed[0].Name != mine.Name {
+				t.Fatalf("unexpected filters: %+v", listed)
+			}
+			if diff := cmp.Diff(listed[0].Filters, overwrite.Filters); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(isDefault(listed), map[string]bool{mine.Name: true, open.Name: false}); diff != "" {
+				t.Fatal(diff)
+			}
+
+			if err = s.SetDefaultCampaignSavedFilter(ctx, userID, open.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err = s.SetDefaultCampaignSavedFilter(ctx, userID+1, mine.ID); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			listed, err = s.ListCampaignSavedFilters(ctx, ListCampaignSavedFiltersOpts{UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(isDefault(listed), map[string]bool{mine.Name: false, open.Name: true}); diff != "" {
+				t.Fatal(diff)
+			}
+
+			if err = s.SetDefaultCampaignSavedFilter(ctx, userID, 0); err != nil {
+				t.Fatal(err)
+			}
+			if err = s.DeleteCampaignSavedFilter(ctx, mine.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			listed, err = s.ListCampaignSavedFilters(ctx, ListCampaignSavedFiltersOpts{UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(isDefault(listed), map[string]bool{open.Name: false}); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
+		t.Run("CampaignNamespaceSettings", func(t *testing.T) {
+			var userID, orgID int32
+			err := tx.QueryRow("INSERT INTO users (username) VALUES ('namespace-settings-user') RETURNING id").Scan(&userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tx.QueryRow("INSERT INTO orgs (name) VALUES ('namespace-settings-org') RETURNING id").Scan(&orgID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = s.GetCampaignNamespaceSettings(ctx, GetCampaignNamespaceSettingsOpts{NamespaceUserID: userID})
+			if err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			user := &a8n.CampaignNamespaceSettings{
+				NamespaceUserID: userID,
+				BranchPrefix:    "campaigns/",
+				DefaultLabels:   []string{"automation", "sourcegraph"},
+			}
+			org := &a8n.CampaignNamespaceSettings{
+				NamespaceOrgID:         orgID,
+				RequireApproval:        true,
+				NotificationChannel:    a8n.CampaignNotificationChannelSlack,
+				NotificationWebhookURL: "https://hooks.slack.com/services/T0/B0/X",
+			}
+			for _, ns := range []*a8n.CampaignNamespaceSettings{user, org} {
+				if err := s.UpsertCampaignNamespaceSettings(ctx, ns); err != nil {
+					t.Fatal(err)
+				}
+				if ns.ID == 0 {
+					t.Fatalf("settings %+v have no ID", ns)
+				}
+				if ns.NotificationChannel == "" {
+					t.Fatalf("settings %+v have no notification channel", ns)
+				}
+			}
+
+			have, err := s.GetCampaignNamespaceSettings(ctx, GetCampaignNamespaceSettingsOpts{NamespaceOrgID: orgID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, org); diff != "" {
+				t.Fatal(diff)
+			}
+
+			// Upserting the settings of a namespace that has some updates them.
+			update := user.Clone()
+			update.ID = 0
+			update.DefaultLabels = []string{"automation"}
+			update.RequireApproval = true
+			if err := s.UpsertCampaignNamespaceSettings(ctx, update); err != nil {
+				t.Fatal(err)
+			}
+			if update.ID != user.ID {
+				t.Fatalf("have ID %d, want %d", update.ID, user.ID)
+			}
+
+			have, err = s.GetCampaignNamespaceSettings(ctx, GetCampaignNamespaceSettingsOpts{NamespaceUserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, update); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func testProcessCampaignJob(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		clock := func() time.Time { return now.UTC().Truncate(time.Microsecond) }
+		ctx := context.Background()
+
+		// Create a test repo
+		reposStore := repos.NewDBStore(db, sql.TxOptions{})
+		repo := &repos.Repo{
+			Name: fmt.Sprintf("github.com/sourcegraph/sourcegraph"),
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "external-id",
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+			Sources: map[string]*repos.SourceInfo{
+				"extsvc:github:4": {
+					ID:       "extsvc:github:4",
+					CloneURL: "https://secrettoken@github.com/sourcegraph/sourcegraph",
+				},
+			},
+		}
+		if err := reposStore.UpsertRepos(context.Background(), repo); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("CampaignNotifications", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			const (
+				author       int32 = 1001
+				subscriber   int32 = 1002
+				unsubscribed int32 = 1003
+			)
+
+			changeset := &a8n.Changeset{
+				RepoID:              42,
+				Metadata:            &github.PullRequest{},
+				ExternalID:          "notifications",
+				ExternalServiceType: "github",
+			}
+			if err := s.CreateChangesets(ctx, changeset); err != nil {
+				t.Fatal(err)
+			}
+
+			var campaigns []*a8n.Campaign
+			for _, authorID := range []int32{author, unsubscribed} {
+				c := &a8n.Campaign{
+					Name:            fmt.Sprintf("Notify %d", authorID),
+					AuthorID:        authorID,
+					NamespaceUserID: authorID,
+					ChangesetIDs:    []int64{changeset.ID},
+				}
+				if err := s.CreateCampaign(ctx, c); err != nil {
+					t.Fatal(err)
+				}
+				campaigns = append(campaigns, c)
+			}
+
+			_, err := s.GetCampaignNotificationSettings(ctx, author)
+			if err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			settings := a8n.DefaultCampaignNotificationSettings(author)
+			settings.NotifyChecksFailed = false
+			settings.DigestInterval = 0
+			if err := s.UpsertCampaignNotificationSettings(ctx, settings); err != nil {
+				t.Fatal(err)
+			}
+
+			have, err := s.GetCampaignNotificationSettings(ctx, author)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(have, settings); diff != "" {
+				t.Fatal(diff)
+			}
+
+			_, err = s.GetCampaignSubscription(ctx, GetCampaignSubscriptionOpts{CampaignID: campaigns[0].ID, UserID: subscriber})
+			if err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			for _, sub := range []*a8n.CampaignSubscription{
+				{CampaignID: campaigns[0].ID, UserID: subscriber, Subscribed: true},
+				{CampaignID: campaigns[1].ID, UserID: unsubscribed, Subscribed: false},
+			} {
+				if err := s.UpsertCampaignSubscription(ctx, sub); err != nil {
+					t.Fatal(err)
+				}
+
+				have, err := s.GetCampaignSubscription(ctx, GetCampaignSubscriptionOpts{CampaignID: sub.CampaignID, UserID: sub.UserID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(have, sub); diff != "" {
+					t.Fatal(diff)
+				}
+			}
+
+			// The author opted out of failed checks and the author of the
+			// second campaign unsubscribed from it.
+			for _, kind := range []a8n.CampaignNotificationKind{
+				a8n.CampaignNotificationKindChecksFailed,
+				a8n.CampaignNotificationKindMerged,
+			} {
+				if err := s.EnqueueCampaignNotifications(ctx, changeset.ID, kind); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			due, err := s.ListDueCampaignNotificationUserIDs(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(due, []int32{author}); diff != "" {
+				t.Fatalf("due before default digest interval: %s", diff)
+			}
+
+			now = now.Add(a8n.DefaultCampaignNotificationSettings(0).DigestInterval)
+
+			due, err = s.ListDueCampaignNotificationUserIDs(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(due, []int32{author, subscriber}); diff != "" {
+				t.Fatalf("due after default digest interval: %s", diff)
+			}
+
+			sent, err := s.MarkCampaignNotificationsSent(ctx, subscriber)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var kinds []a8n.CampaignNotificationKind
+			ids := make([]int64, 0, len(sent))
+			for _, n := range sent {
+				if n.UserID != subscriber || n.CampaignID != campaigns[0].ID || n.ChangesetID != changeset.ID {
+					t.Fatalf("unexpected notification %+v", n)
+				}
+				if !n.SentAt.Equal(clock()) {
+					t.Fatalf("have sent at %s, want %s", n.SentAt, clock())
+				}
+				kinds = append(kinds, n.Kind)
+				ids = append(ids, n.ID)
+			}
+			want := []a8n.CampaignNotificationKind{
+				a8n.CampaignNotificationKindChecksFailed,
+				a8n.CampaignNotificationKindMerged,
+			}
+			if diff := cmp.Diff(kinds, want); diff != "" {
+				t.Fatal(diff)
+			}
+
+			sent, err = s.MarkCampaignNotificationsSent(ctx, subscriber)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sent) != 0 {
+				t.Fatalf("notifications sent twice: %+v", sent)
+			}
+
+			if err := s.ResetCampaignNotifications(ctx, ids...); err != nil {
+				t.Fatal(err)
+			}
+
+			sent, err = s.MarkCampaignNotificationsSent(ctx, subscriber)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sent) != len(ids) {
+				t.Fatalf("have %d notifications after reset, want %d", len(sent), len(ids))
+			}
+
+			now = now.Add(CampaignNotificationTTL)
+
+			if err := s.DeleteExpiredCampaignNotifications(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			due, err = s.ListDueCampaignNotificationUserIDs(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(due) != 0 {
+				t.Fatalf("have due users %v after expiry, want none", due)
+			}
+		})
+
+		t.Run("CampaignReadState", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			const userID int32 = 2001
+
+			campaign := &a8n.Campaign{
+				Name:            "Read state",
+				AuthorID:        userID,
+				NamespaceUserID: userID,
+			}
+			if err := s.CreateCampaign(ctx, campaign); err != nil {
+				t.Fatal(err)
+			}
+
+			assertReadState := func(t *testing.T, lastViewedAt, lastActivityAt time.Time, unread bool) {
+				t.Helper()
+
+				have, err := s.GetCampaignReadState(ctx, campaign.ID, userID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := &a8n.CampaignReadState{
+					CampaignID:     campaign.ID,
+					UserID:         userID,
+					LastViewedAt:   lastViewedAt,
+					LastActivityAt: lastActivityAt,
+				}
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+				if have.HasUnreadActivity() != unread {
+					t.Fatalf("have unread activity %t, want %t", have.HasUnreadActivity(), unread)
+				}
+
+				count, err := s.CountCampaigns(ctx, CountCampaignsOpts{UnreadByUserID: userID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var wantCount int64
+				if unread {
+					wantCount = 1
+				}
+				if count != wantCount {
+					t.Fatalf("have %d unread campaigns, want %d", count, wantCount)
+				}
+			}
+
+			// Campaigns that were never viewed are unread.
+			assertReadState(t, time.Time{}, campaign.CreatedAt, true)
+
+			viewed := clock()
+			if err := s.MarkCampaignViewed(ctx, campaign.ID, userID); err != nil {
+				t.Fatal(err)
+			}
+			assertReadState(t, viewed, campaign.CreatedAt, false)
+
+			now = now.Add(time.Second)
+			changeset := &a8n.Changeset{
+				RepoID:              42,
+				Metadata:            &github.PullRequest{},
+				CampaignIDs:         []int64{campaign.ID},
+				ExternalID:          "read-state",
+				ExternalServiceType: "github",
+			}
+			if err := s.CreateChangesets(ctx, changeset); err != nil {
+				t.Fatal(err)
+			}
+			assertReadState(t, viewed, changeset.CreatedAt, true)
+
+			now = now.Add(time.Second)
+			viewed = clock()
+			if err := s.MarkCampaignViewed(ctx, campaign.ID, userID); err != nil {
+				t.Fatal(err)
+			}
+			assertReadState(t, viewed, changeset.CreatedAt, false)
+
+			// Only events shown in the activity feed are activity.
+			now = now.Add(time.Second)
+			err := s.UpsertChangesetEvents(ctx, &a8n.ChangesetEvent{
+				ChangesetID: changeset.ID,
+				Kind:        a8n.ChangesetEventKindGitHubAssigned,
+				Key:         "assigned",
+				Metadata:    &github.AssignedEvent{},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertReadState(t, viewed, changeset.CreatedAt, false)
+
+			comment := &a8n.ChangesetEvent{
+				ChangesetID: changeset.ID,
+				Kind:        a8n.ChangesetEventKindGitHubCommented,
+				Key:         "commented",
+				Metadata:    &github.IssueComment{},
+			}
+			if err := s.UpsertChangesetEvents(ctx, comment); err != nil {
+				t.Fatal(err)
+			}
+			assertReadState(t, viewed, comment.CreatedAt, true)
+		})
+
+		t.Run("CampaignNameUniqueness", func(t *testing.T) {
+			const (
+				userID int32 = 3001
+				orgID  int32 = 3002
+			)
+
+			// Each case runs in its own transaction, since a violated
+			// index aborts the transaction.
+			for _, tc := range []struct {
+				name string
+				// conflict creates or updates a campaign so that it's
+				// named like taken, which is in the namespace of userID.
+				conflict func(s *Store, taken *a8n.Campaign) error
+			}{
+				{
+					name: "create",
+					conflict: func(s *Store, taken *a8n.Campaign) error {
+						return s.CreateCampaign(ctx, &a8n.Campaign{Name: taken.Name, AuthorID: userID, NamespaceUserID: userID})
+					},
+				},
+				{
+					name: "rename",
+					conflict: func(s *Store, taken *a8n.Campaign) error {
+						c := &a8n.Campaign{Name: "Other name", AuthorID: userID, NamespaceUserID: userID}
+						if err := s.CreateCampaign(ctx, c); err != nil {
+							t.Fatal(err)
+						}
+						c.Name = taken.Name
+						return s.UpdateCampaign(ctx, c)
+					},
+				},
+				{
+					name: "move",
+					conflict: func(s *Store, taken *a8n.Campaign) error {
+						c := &a8n.Campaign{Name: taken.Name, AuthorID: userID, NamespaceOrgID: orgID}
+						if err := s.CreateCampaign(ctx, c); err != nil {
+							t.Fatal(err)
+						}
+						c.NamespaceOrgID = 0
+						c.NamespaceUserID = userID
+						return s.UpdateCampaign(ctx, c)
+					},
+				},
+				{
+					name: "restore",
+					conflict: func(s *Store, taken *a8n.Campaign) error {
+						if err := s.DeleteCampaign(ctx, taken.ID); err != nil {
+							t.Fatal(err)
+						}
+						// The names of deleted campaigns are free.
+						c := &a8n.Campaign{Name: taken.Name, AuthorID: userID, NamespaceUserID: userID}
+						if err := s.CreateCampaign(ctx, c); err != nil {
+							t.Fatal(err)
+						}
+						return campaignNameConflict(s.RestoreCampaign(ctx, taken.ID), taken)
+					},
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					tx, done := dbtest.NewTx(t, db)
+					defer done()
+					s := NewStoreWithClock(tx, clock)
+
+					taken := &a8n.Campaign{Name: "Unique name", AuthorID: userID, NamespaceUserID: userID}
+					if err := s.CreateCampaign(ctx, taken); err != nil {
+						t.Fatal(err)
+					}
+
+					want := &ErrCampaignNameConflict{Name: taken.Name, NamespaceUserID: userID}
+					err := tc.conflict(s, taken)
+					if have, ok := err.(*ErrCampaignNameConflict); !ok || *have != *want {
+						t.Fatalf("have error %v, want %v", err, want)
+					}
+				})
+			}
+		})
+
+		t.Run("CampaignInvolvement", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			users := map[string]int32{}
+			for _, name := range []string{"author", "github-reviewer", "bbs-reviewer", "subscriber"} {
+				var id int32
+				err := tx.QueryRow("INSERT INTO users (username) VALUES ($1) RETURNING id", "involvement-"+name).Scan(&id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				users[name] = id
+			}
+
+			_, err := tx.Exec(`
+INSERT INTO user_external_accounts (user_id, service_type, service_id, account_id, account_data, client_id)
+VALUES ($1, 'github', $2, '1', '{"login": "octocat"}', 'client')`,
+				users["github-reviewer"], repo.ExternalRepo.ServiceID,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The first campaign is the author's, the second has a GitHub
+			// changeset that octocat was requested to review, and the third
+			// is the author's with a Bitbucket Server changeset reviewed by
+			// bbs-reviewer.
+			campaigns := make([]*a8n.Campaign, 0, 3)
+			for i, author := range []int32{users["author"], 4001, users["author"]} {
+				c := &a8n.Campaign{
+					Name:            fmt.Sprintf("Involvement %d", i),
+					AuthorID:        author,
+					NamespaceUserID: author,
+				}
+				if err := s.CreateCampaign(ctx, c); err != nil {
+					t.Fatal(err)
+				}
+				campaigns = append(campaigns, c)
+			}
+
+			changesets := []*a8n.Changeset{
+				{
+					RepoID:              repo.ID,
+					CampaignIDs:         []int64{campaigns[1].ID},
+					ExternalID:          "involvement-github",
+					ExternalServiceType: github.ServiceType,
+					Metadata:            &github.PullRequest{State: "OPEN"},
+				},
+				{
+					RepoID:              42,
+					CampaignIDs:         []int64{campaigns[2].ID},
+					ExternalID:          "involvement-bbs",
+					ExternalServiceType: bitbucketserver.ServiceType,
+					Metadata: &bitbucketserver.PullRequest{
+						State: "OPEN",
+						Reviewers: []struct {
+							User               *bitbucketserver.User `json:"user"`
+							LastReviewedCommit string                `json:"lastReviewedCommit"`
+							Role               string                `json:"role"`
+							Approved           bool                  `json:"approved"`
+							Status             string                `json:"status"`
+						}{{User: &bitbucketserver.User{Name: "involvement-bbs-reviewer"}}},
+					},
+				},
+			}
+			if err := s.CreateChangesets(ctx, changesets...); err != nil {
+				t.Fatal(err)
+			}
+
+			requested := &github.ReviewRequestedEvent{RequestedReviewer: github.Actor{Login: "octocat"}}
+			err = s.UpsertChangesetEvents(ctx, &a8n.ChangesetEvent{
+				ChangesetID: changesets[0].ID,
+				Kind:        a8n.ChangesetEventKindGitHubReviewRequested,
+				Key:         requested.Key(),
+				Metadata:    requested,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, sub := range []*a8n.CampaignSubscription{
+				{CampaignID: campaigns[1].ID, UserID: users["subscriber"], Subscribed: true},
+				{CampaignID: campaigns[2].ID, UserID: users["author"], Subscribed: false},
+			} {
+				if err := s.UpsertCampaignSubscription(ctx, sub); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, tc := range []struct {
+				user string
+				as   a8n.CampaignInvolvement
+				want []int64
+			}{
+				{user: "author", as: a8n.CampaignInvolvementAuthor, want: []int64{campaigns[0].ID, campaigns[2].ID}},
+				// Authors are subscribed unless they unsubscribed.
+				{user: "author", as: a8n.CampaignInvolvementSubscriber, want: []int64{campaigns[0].ID}},
+				{user: "author", as: a8n.CampaignInvolvementReviewer, want: []int64{}},
+				{user: "author", want: []int64{campaigns[0].ID, campaigns[2].ID}},
+				{user: "github-reviewer", as: a8n.CampaignInvolvementReviewer, want: []int64{campaigns[1].ID}},
+				{user: "bbs-reviewer", as: a8n.CampaignInvolvementReviewer, want: []int64{campaigns[2].ID}},
+				{user: "subscriber", as: a8n.CampaignInvolvementSubscriber, want: []int64{campaigns[1].ID}},
+				{user: "subscriber", as: a8n.CampaignInvolvementAuthor, want: []int64{}},
+				{user: "subscriber", want: []int64{campaigns[1].ID}},
+			} {
+				t.Run(fmt.Sprintf("%s as %q", tc.user, tc.as), func(t *testing.T) {
+					cs, _, err := s.ListCampaigns(ctx, ListCampaignsOpts{InvolvedUserID: users[tc.user], InvolvedAs: tc.as})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					have := make([]int64, 0, len(cs))
+					for _, c := range cs {
+						have = append(have, c.ID)
+					}
+					if diff := cmp.Diff(have, tc.want); diff != "" {
+						t.Fatal(diff)
+					}
+
+					count, err := s.CountCampaigns(ctx, CountCampaignsOpts{InvolvedUserID: users[tc.user], InvolvedAs: tc.as})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if have, want := count, int64(len(tc.want)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
+					}
+				})
+			}
+		})
+
+		t.Run("CampaignWebhookDeliveries", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			deliveries := make([]*a8n.CampaignWebhookDelivery, 0, 3)
+			for i := 0; i < cap(deliveries); i++ {
+				d := &a8n.CampaignWebhookDelivery{
+					Channel:         a8n.CampaignNotificationChannelWebhook,
+					URL:             "https://example.com/hook",
+					RecipientUserID: 3001,
+					Payload:         json.RawMessage(fmt.Sprintf(`{"count": %d}`, i)),
+				}
+				if err := s.CreateCampaignWebhookDelivery(ctx, d); err != nil {
+					t.Fatal(err)
+				}
+				if d.ID == 0 {
+					t.Fatal("ID should not be zero")
+				}
+				deliveries = append(deliveries, d)
+			}
+
+			// The first delivery fails and is redelivered successfully, the
+			// second one only fails.
+			attempts := []*a8n.CampaignWebhookDeliveryAttempt{
+				{DeliveryID: deliveries[0].ID, StatusCode: 502, ResponseBody: "bad gateway", Error: "status 502", Duration: 2 * time.Second},
+				{DeliveryID: deliveries[0].ID, Succeeded: true, StatusCode: 200, Duration: time.Second},
+				{DeliveryID: deliveries[1].ID, Error: "connection refused"},
+			}
+			for _, a := range attempts {
+				if err := s.CreateCampaignWebhookDeliveryAttempt(ctx, a); err != nil {
+					t.Fatal(err)
+				}
+				if a.ID == 0 {
+					t.Fatal("ID should not be zero")
+				}
+			}
+
+			have, err := s.GetCampaignWebhookDelivery(ctx, deliveries[0].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := *deliveries[0]
+			want.AttemptCount = 2
+			want.LastAttemptedAt = now
+			want.SucceededAt = now
+			if diff := cmp.Diff(have, &want); diff != "" {
+				t.Fatal(diff)
+			}
+
+			haveAttempts, err := s.ListCampaignWebhookDeliveryAttempts(ctx, deliveries[0].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(haveAttempts, attempts[:2]); diff != "" {
+				t.Fatal(diff)
+			}
+
+			_, err = s.GetCampaignWebhookDelivery(ctx, deliveries[2].ID+1)
+			if err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			listIDs := func(t *testing.T, opts ListCampaignWebhookDeliveriesOpts) (ids []int64, next int64) {
+				t.Helper()
+
+				ds, next, err := s.ListCampaignWebhookDeliveries(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, d := range ds {
+					ids = append(ids, d.ID)
+				}
+				return ids, next
+			}
+
+			ids, next := listIDs(t, ListCampaignWebhookDeliveriesOpts{Limit: 2})
+			if diff := cmp.Diff(ids, []int64{deliveries[2].ID, deliveries[1].ID}); diff != "" {
+				t.Fatalf("first page: %s", diff)
+			}
+			ids, next = listIDs(t, ListCampaignWebhookDeliveriesOpts{Limit: 2, Cursor: next})
+			if diff := cmp.Diff(ids, []int64{deliveries[0].ID}); diff != "" {
+				t.Fatalf("second page: %s", diff)
+			}
+			if next != 0 {
+				t.Fatalf("have next cursor %d after last page, want none", next)
+			}
+
+			ids, _ = listIDs(t, ListCampaignWebhookDeliveriesOpts{OnlyFailed: true})
+			if diff := cmp.Diff(ids, []int64{deliveries[2].ID, deliveries[1].ID}); diff != "" {
+				t.Fatalf("failed: %s", diff)
+			}
+
+			for onlyFailed, want := range map[bool]int64{false: 3, true: 2} {
+				count, err := s.CountCampaignWebhookDeliveries(ctx, onlyFailed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if count != want {
+					t.Fatalf("have count %d with onlyFailed %t, want %d", count, onlyFailed, want)
+				}
+			}
+
+			now = now.Add(CampaignWebhookDeliveryTTL)
+
+			if err := s.DeleteExpiredCampaignWebhookDeliveries(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			count, err := s.CountCampaignWebhookDeliveries(ctx, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != 0 {
+				t.Fatalf("have %d deliveries after expiry, want none", count)
+			}
+		})
+
+		t.Run("GetPendingCampaignJobsWhenNoneAvailable", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			process := func(ctx context.Context, s *Store, job a8n.CampaignJob) error {
+				return errors.New("rollback")
+			}
+			ran, err := s.ProcessPendingCampaignJob(ctx, process)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ran {
+				// We shouldn't have any pending jobs yet
+				t.Fatalf("process function should not have run")
+			}
+		})
+
+		t.Run("GetPendingCampaignJobsWhenAvailable", func(t *testing.T) {
+			tx, done := dbtest.NewTx(t, db)
+			defer done()
+			s := NewStoreWithClock(tx, clock)
+
+			process := func(ctx context.Context, s *Store, job a8n.CampaignJob) error {
+				return errors.New("rollback")
+			}
+			plan := &a8n.CampaignPlan{
+				CampaignType: "test",
+			}
+			err := s.CreateCampaignPlan(context.Background(), plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			job := &a8n.CampaignJob{
+				ID:             0,
+				CampaignPlanID: plan.ID,
+				RepoID:         repo.ID,
+				Rev:            "",
+				BaseRef:        "abc",
+				Diff:           "",
+				Description:    "",
+				Error:          "",
+			}
+			err = s.CreateCampaignJob(context.Background(), job)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ran, err := s.ProcessPendingCampaignJob(ctx, process)
+			if err != nil && err.Error() != "rollback" {
+				t.Fatal(err)
+			}
+			if !ran {
+				// We shouldn't have any pending jobs yet
+				t.Fatalf("process function should have run")
+			}
+		})
+
+		t.Run("GetPendingCampaignJobsWhenAvailableLocking", func(t *testing.T) {
+			dbtesting.SetupGlobalTestDB(t)
+			user := createTestUser(ctx, t)
+			s := NewStoreWithClock(db, clock)
+
+			process := func(ctx context.Context, s *Store, job a8n.CampaignJob) error {
+				time.Sleep(100 * time.Millisecond)
+				return errors.New("rollback")
+			}
+			plan := &a8n.CampaignPlan{
+				CampaignType: "test",
+				UserID:       user.ID,
+			}
+			err := s.CreateCampaignPlan(context.Background(), plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.CreateCampaignJob(context.Background(), &a8n.CampaignJob{
+				ID:             0,
+				CampaignPlanID: plan.ID,
+				RepoID:         repo.ID,
+				Rev:            "",
+				BaseRef:        "abc",
+				Diff:           "",
+				Description:    "",
+				Error:          "",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var runCount int64
+			errChan := make(chan error, 2)
+
+			for i := 0; i < 2; i++ {
+				go func() {
+					ran, err := s.ProcessPendingCampaignJob(ctx, process)
+					if ran {
+						atomic.AddInt64(&runCount, 1)
+					}
+					errChan <- err
+				}()
+			}
+			for i := 0; i < 2; i++ {
+				err := <-errChan
+				if err != nil && err.Error() != "rollback" {
+					t.Fatal(err)
+				}
+			}
+
+			rc := atomic.LoadInt64(&runCount)
+			if rc != 1 {
+				t.Errorf("Want %d, got %d", 1, rc)
+			}
+		})
+	}
+}
+
+func testStoreLocking(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		s := NewStoreWithClock(db, func() time.Time {
+			return now.UTC().Truncate(time.Microsecond)
+		})
+
+		testKey := "test-acquire"
+		s1, err := s.Transact(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s1.Done(nil)
+
+		s2, err := s.Transact(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s2.Done(nil)
 
 		// Get lock
 		ok, err := s1.TryAcquireAdvisoryLock(context.Background(), testKey)
@@ -2718,3 +4644,131 @@ func testStoreLocking(db *sql.DB) func(*testing.T) {
 		}
 	}
 }
+
+func testWorkerJobQueue(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		s := NewStoreWithClock(db, func() time.Time {
+			return now.UTC().Truncate(time.Microsecond)
+		})
+
+		// A dequeue that waits for a locked row instead of skipping it fails
+		// with this deadline rather than hanging the test.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		const queue = "test-locking"
+
+		jobs := make([]*a8n.WorkerJob, 0, 2)
+		for i := 0; i < cap(jobs); i++ {
+			j := &a8n.WorkerJob{Queue: queue, MaxAttempts: 2}
+			if err := s.EnqueueWorkerJob(ctx, j); err != nil {
+				t.Fatal(err)
+			}
+			jobs = append(jobs, j)
+		}
+
+		dequeue := func(t *testing.T, s *Store) *a8n.WorkerJob {
+			t.Helper()
+			j, err := s.DequeueWorkerJob(ctx, queue, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return j
+		}
+
+		noneDue := func(t *testing.T, s *Store) {
+			t.Helper()
+			if j, err := s.DequeueWorkerJob(ctx, queue, time.Minute); err != ErrNoResults {
+				t.Fatalf("have job %+v and err %v, want %v", j, err, ErrNoResults)
+			}
+		}
+
+		var first, second *a8n.WorkerJob
+
+		t.Run("SkipLocked", func(t *testing.T) {
+			s1, err := s.Transact(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s1.Done(nil)
+
+			s2, err := s.Transact(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s2.Done(nil)
+
+			// The first transaction holds the lock of the first job until it
+			// commits, so the second one takes the next job.
+			first = dequeue(t, s1)
+			if first.ID != jobs[0].ID {
+				t.Fatalf("dequeued wrong job: %+v", first)
+			}
+
+			second = dequeue(t, s2)
+			if second.ID != jobs[1].ID {
+				t.Fatalf("dequeued wrong job: %+v", second)
+			}
+
+			noneDue(t, s2)
+		})
+
+		t.Run("Heartbeat", func(t *testing.T) {
+			now = now.Add(2 * time.Minute)
+
+			// The first job is still alive, the second one has stalled and is
+			// taken again as its second attempt.
+			if err := s.HeartbeatWorkerJob(ctx, first); err != nil {
+				t.Fatal(err)
+			}
+
+			retaken := dequeue(t, s)
+			if retaken.ID != second.ID || retaken.Attempts != 2 || retaken.State != a8n.WorkerJobStateProcessing {
+				t.Fatalf("dequeued wrong job: %+v", retaken)
+			}
+			noneDue(t, s)
+
+			// The stalled attempt can't update the job anymore.
+			if err := s.HeartbeatWorkerJob(ctx, second); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+			if err := s.CompleteWorkerJob(ctx, second); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+
+			if err := s.CompleteWorkerJob(ctx, retaken); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		t.Run("Requeue", func(t *testing.T) {
+			if err := s.FailWorkerJob(ctx, first, errors.New("boom"), time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			if first.State != a8n.WorkerJobStateQueued || !first.RunAfter.Equal(now.Add(time.Hour)) {
+				t.Fatalf("failed job not queued again: %+v", first)
+			}
+
+			// The retry isn't due before its delay.
+			noneDue(t, s)
+
+			now = now.Add(time.Hour)
+			retried := dequeue(t, s)
+			if retried.ID != first.ID || retried.Attempts != 2 || retried.Error != "boom" {
+				t.Fatalf("dequeued wrong job: %+v", retried)
+			}
+
+			// The job has run out of attempts.
+			if err := s.FailWorkerJob(ctx, retried, errors.New("boom again"), time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			if retried.State != a8n.WorkerJobStateDead || retried.Attempts != 2 {
+				t.Fatalf("failed job not dead: %+v", retried)
+			}
+
+			now = now.Add(2 * time.Hour)
+			noneDue(t, s)
+		})
+	}
+}
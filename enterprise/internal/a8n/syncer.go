@@ -2,12 +2,17 @@ package a8n
 
 import (
 	"context"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -17,10 +22,62 @@ type ChangesetSyncer struct {
 	Store       *Store
 	ReposStore  repos.Store
 	HTTPFactory *httpcli.Factory
+
+	mu sync.Mutex
+	// rateLimited maps the IDs of external services to the time until which
+	// no changesets should be synced from them, in order to stay within the
+	// rate limit of their code host.
+	rateLimited map[int64]time.Time
 }
 
-// Sync refreshes the metadata of all changesets and updates them in the
-// database
+const (
+	// minSyncDelay and maxSyncDelay bound the time between two syncs of a
+	// changeset.
+	minSyncDelay = 2 * time.Minute
+	maxSyncDelay = 8 * time.Hour
+
+	// maxChangesetsPerSync is the maximum number of changesets of a single
+	// external service that are synced in one call to Sync.
+	maxChangesetsPerSync = 200
+
+	// campaignsRateLimitReserve is the fraction of the rate limit of a code
+	// host token that campaigns leave to repository syncing.
+	campaignsRateLimitReserve = 0.25
+
+	// changesetCreationCost is the number of API requests made to create or
+	// update a single changeset on its code host.
+	changesetCreationCost = 3
+)
+
+// nextSync returns when the given changeset should be synced next. The
+// longer a changeset hasn't been updated on its code host, the less often
+// it's synced. Closed, merged and deleted changesets are synced as seldom as
+// possible, since they're unlikely to change.
+func nextSync(c *a8n.Changeset) time.Time {
+	lastSync := c.UpdatedAt
+
+	lastChange := c.ExternalUpdatedAt()
+	if lastChange.IsZero() || lastChange.After(lastSync) {
+		lastChange = lastSync
+	}
+
+	delay := lastSync.Sub(lastChange) / 2
+	if s, err := c.State(); err == nil && s != a8n.ChangesetStateOpen {
+		delay = maxSyncDelay
+	}
+
+	if delay < minSyncDelay {
+		delay = minSyncDelay
+	} else if delay > maxSyncDelay {
+		delay = maxSyncDelay
+	}
+	return lastSync.Add(delay)
+}
+
+// Sync refreshes the metadata of the changesets that are due to be synced
+// and updates them in the database. The changesets that are the most overdue
+// are synced first and changesets of code hosts whose rate limit is
+// exhausted are skipped until it resets.
 func (s *ChangesetSyncer) Sync(ctx context.Context) error {
 	cs, err := s.listAllNonDeletedChangesets(ctx)
 	if err != nil {
@@ -28,18 +85,105 @@ func (s *ChangesetSyncer) Sync(ctx context.Context) error {
 		return err
 	}
 
-	if err := s.SyncChangesets(ctx, cs...); err != nil {
-		log15.Error("ChangesetSyncer", "error", err)
+	now := s.Store.now()
+
+	due := cs[:0]
+	for _, c := range cs {
+		if !nextSync(c).After(now) {
+			due = append(due, c)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return nextSync(due[i]).Before(nextSync(due[j]))
+	})
+
+	bySource, err := s.GroupChangesetsBySource(ctx, due...)
+	if err != nil {
+		log15.Error("ChangesetSyncer.GroupChangesetsBySource", "error", err)
 		return err
 	}
-	return nil
+
+	var errs *multierror.Error
+	for _, src := range bySource {
+		if s.isRateLimited(src.ExternalServiceID, now) {
+			log15.Debug("ChangesetSyncer: skipping rate limited external service", "external_service_id", src.ExternalServiceID)
+			continue
+		}
+
+		if len(src.Changesets) > maxChangesetsPerSync {
+			src.Changesets = src.Changesets[:maxChangesetsPerSync]
+		}
+
+		// Don't eat into the part of the rate limit that's left to
+		// repository syncing.
+		if b := ratelimit.DefaultBudgets.Lookup(src.ExternalServiceID); b != nil {
+			if wait := b.Wait(len(src.Changesets), campaignsRateLimitReserve); wait > 0 {
+				log15.Debug("ChangesetSyncer: rate limit budget exhausted", "external_service_id", src.ExternalServiceID, "wait", wait)
+				s.setRateLimited(src.ExternalServiceID, now.Add(wait))
+				continue
+			}
+		}
+
+		if err := s.SyncChangesetsWithSources(ctx, []*SourceChangesets{src}); err != nil {
+			log15.Error("ChangesetSyncer", "external_service_id", src.ExternalServiceID, "error", err)
+			errs = multierror.Append(errs, err)
+		}
+
+		// The rate limit of the code host is only known after the requests
+		// made by SyncChangesetsWithSources.
+		if rl, ok := src.ChangesetSource.(rateLimitedSource); ok {
+			if wait := rl.RecommendedWaitForBackgroundOp(len(src.Changesets)); wait > 0 {
+				s.setRateLimited(src.ExternalServiceID, now.Add(wait))
+			}
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// rateLimitedSource is implemented by the repos.ChangesetSources of code
+// hosts that report their rate limit.
+type rateLimitedSource interface {
+	RecommendedWaitForBackgroundOp(cost int) time.Duration
+}
+
+// borrowRateLimit blocks until campaigns may spend the given cost of the rate
+// limit of the external service without eating into the reserve left to
+// repository syncing. The budget of the external service is only known once
+// a source for it was created.
+func borrowRateLimit(ctx context.Context, externalServiceID int64, consumer string, cost int) error {
+	b := ratelimit.DefaultBudgets.Lookup(externalServiceID)
+	if b == nil {
+		return nil
+	}
+	return b.Borrow(ctx, "campaigns_"+consumer, cost, campaignsRateLimitReserve)
+}
+
+func (s *ChangesetSyncer) isRateLimited(externalServiceID int64, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Before(s.rateLimited[externalServiceID])
+}
+
+func (s *ChangesetSyncer) setRateLimited(externalServiceID int64, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rateLimited == nil {
+		s.rateLimited = map[int64]time.Time{}
+	}
+	s.rateLimited[externalServiceID] = until
 }
 
 // A SourceChangesets groups *repos.Changesets together with the
 // repos.ChangesetSource that can be used to modify the changesets.
 type SourceChangesets struct {
 	repos.ChangesetSource
-	Changesets []*repos.Changeset
+	ExternalServiceID int64
+	Changesets        []*repos.Changeset
 }
 
 // SyncChangesets refreshes the metadata of the given changesets and
@@ -61,13 +205,23 @@ func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*a8n.Changes
 // with the given ChangesetSources and updates them in the database.
 func (s *ChangesetSyncer) SyncChangesetsWithSources(ctx context.Context, bySource []*SourceChangesets) (err error) {
 	var (
-		events []*a8n.ChangesetEvent
-		cs     []*a8n.Changeset
+		events        []*a8n.ChangesetEvent
+		cs            []*a8n.Changeset
+		notifications []changesetNotification
 	)
 
 	for _, s := range bySource {
 		var notFound []*repos.Changeset
 
+		revs := make(map[int64]string, len(s.Changesets))
+		before := make(map[int64]changesetNotificationState, len(s.Changesets))
+		for _, c := range s.Changesets {
+			revs[c.Changeset.ID] = diffStatRevs(c.Changeset)
+			if state, ok := newChangesetNotificationState(c.Changeset, c.Events()); ok {
+				before[c.Changeset.ID] = state
+			}
+		}
+
 		err := s.LoadChangesets(ctx, s.Changesets...)
 		if err != nil {
 			notFoundErr, ok := err.(repos.ChangesetsNotFoundError)
@@ -88,8 +242,25 @@ func (s *ChangesetSyncer) SyncChangesetsWithSources(ctx context.Context, bySourc
 				c.Changeset.SetDeleted()
 			}
 
-			events = append(events, c.Events()...)
+			// The diff stat has to be recomputed when the base or head of
+			// the changeset changed.
+			if diffStatRevs(c.Changeset) != revs[c.Changeset.ID] {
+				c.Changeset.SetDiffStat(nil)
+			}
+
+			cevents := c.Events()
+			events = append(events, cevents...)
 			cs = append(cs, c.Changeset)
+
+			// Changesets that were never synced before don't cause
+			// notifications, since we don't know what changed.
+			if b, ok := before[c.Changeset.ID]; ok {
+				if a, ok := newChangesetNotificationState(c.Changeset, cevents); ok {
+					for _, kind := range campaignNotificationKinds(b, a) {
+						notifications = append(notifications, changesetNotification{changesetID: c.Changeset.ID, kind: kind})
+					}
+				}
+			}
 		}
 	}
 
@@ -103,7 +274,17 @@ func (s *ChangesetSyncer) SyncChangesetsWithSources(ctx context.Context, bySourc
 		return err
 	}
 
-	return tx.UpsertChangesetEvents(ctx, events...)
+	if err = tx.UpsertChangesetEvents(ctx, events...); err != nil {
+		return err
+	}
+
+	for _, n := range notifications {
+		if err = tx.EnqueueCampaignNotifications(ctx, n.changesetID, n.kind); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // GroupChangesetsBySource returns a slice of SourceChangesets in which the
@@ -165,7 +346,7 @@ func (s *ChangesetSyncer) GroupChangesetsBySource(ctx context.Context, cs ...*a8
 			return nil, errors.Errorf("unsupported repo type %q", e.Kind)
 		}
 
-		bySource[e.ID] = &SourceChangesets{ChangesetSource: css}
+		bySource[e.ID] = &SourceChangesets{ChangesetSource: css, ExternalServiceID: e.ID}
 	}
 
 	for _, c := range cs {
@@ -0,0 +1,58 @@
+package a8n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestNextSync(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, tc := range []struct {
+		name      string
+		changeset *a8n.Changeset
+		want      time.Time
+	}{
+		{
+			name: "recently updated",
+			changeset: &a8n.Changeset{
+				UpdatedAt: now,
+				Metadata:  &github.PullRequest{State: "OPEN", UpdatedAt: now.Add(-time.Minute)},
+			},
+			want: now.Add(minSyncDelay),
+		},
+		{
+			name: "idle for a while",
+			changeset: &a8n.Changeset{
+				UpdatedAt: now,
+				Metadata:  &github.PullRequest{State: "OPEN", UpdatedAt: now.Add(-2 * time.Hour)},
+			},
+			want: now.Add(time.Hour),
+		},
+		{
+			name: "idle for a long time",
+			changeset: &a8n.Changeset{
+				UpdatedAt: now,
+				Metadata:  &github.PullRequest{State: "OPEN", UpdatedAt: now.AddDate(0, -1, 0)},
+			},
+			want: now.Add(maxSyncDelay),
+		},
+		{
+			name: "merged",
+			changeset: &a8n.Changeset{
+				UpdatedAt: now,
+				Metadata:  &github.PullRequest{State: "MERGED", UpdatedAt: now.Add(-time.Minute)},
+			},
+			want: now.Add(maxSyncDelay),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := nextSync(tc.changeset); !have.Equal(tc.want) {
+				t.Errorf("have %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package a8n
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// deliverNotificationWebhook posts the given payload as JSON to the webhook
+// of a Slack or generic NotificationChannel. Unless the given Store is nil,
+// the payload is recorded as a CampaignWebhookDelivery of the given user,
+// along with the result of posting it, so that site admins can inspect and
+// redeliver it.
+func deliverNotificationWebhook(
+	ctx context.Context,
+	s *Store,
+	channel a8n.CampaignNotificationChannel,
+	webhookURL string,
+	user *types.User,
+	payload interface{},
+) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		_, _, err := postNotificationWebhook(ctx, webhookURL, body)
+		return err
+	}
+
+	d := &a8n.CampaignWebhookDelivery{
+		Channel:         channel,
+		URL:             webhookURL,
+		RecipientUserID: user.ID,
+		Payload:         body,
+	}
+
+	// Failing to record the delivery must not keep the notifications from
+	// being delivered, nor cause them to be delivered again.
+	if err := s.CreateCampaignWebhookDelivery(ctx, d); err != nil {
+		log15.Warn("Recording campaign webhook delivery", "url", webhookURL, "err", err)
+		_, _, err := postNotificationWebhook(ctx, webhookURL, body)
+		return err
+	}
+
+	a, err := postCampaignWebhookDelivery(ctx, d)
+	if recordErr := s.CreateCampaignWebhookDeliveryAttempt(ctx, a); recordErr != nil {
+		log15.Warn("Recording campaign webhook delivery attempt", "delivery", d.ID, "err", recordErr)
+	}
+	return err
+}
+
+// RedeliverCampaignWebhook posts the payload of the given
+// CampaignWebhookDelivery to its webhook again and records the attempt.
+// Whether the webhook accepted the payload is reported by the returned
+// attempt, not by the error.
+func RedeliverCampaignWebhook(ctx context.Context, s *Store, d *a8n.CampaignWebhookDelivery) (*a8n.CampaignWebhookDeliveryAttempt, error) {
+	a, _ := postCampaignWebhookDelivery(ctx, d)
+	if err := s.CreateCampaignWebhookDeliveryAttempt(ctx, a); err != nil {
+		return nil, errors.Wrap(err, "recording campaign webhook delivery attempt")
+	}
+	return a, nil
+}
+
+// postCampaignWebhookDelivery posts the payload of the given
+// CampaignWebhookDelivery to its webhook. It returns the attempt, which isn't
+// recorded yet, and the error of posting it.
+func postCampaignWebhookDelivery(ctx context.Context, d *a8n.CampaignWebhookDelivery) (*a8n.CampaignWebhookDeliveryAttempt, error) {
+	start := time.Now()
+	status, respBody, err := postNotificationWebhook(ctx, d.URL, d.Payload)
+
+	a := &a8n.CampaignWebhookDeliveryAttempt{
+		DeliveryID:   d.ID,
+		Succeeded:    err == nil,
+		StatusCode:   int32(status),
+		ResponseBody: respBody,
+		Duration:     time.Since(start),
+	}
+	if err != nil {
+		a.Error = err.Error()
+	}
+
+	return a, err
+}
@@ -2,10 +2,12 @@ package a8n
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -14,8 +16,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	bbs "github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -83,6 +88,59 @@ func (h Webhook) upsertChangesetEvent(
 	return tx.UpsertChangesetEvents(ctx, event)
 }
 
+// handleEvent upserts the ChangesetEvent normalized from a webhook event
+// for each of the changesets with the given external IDs and, if sync is
+// true, enqueues those changesets in ChangesetSyncQueue. Syncing happens in
+// the background, since code hosts don't wait long for webhook responses.
+func (h Webhook) handleEvent(w http.ResponseWriter, r *http.Request, prs []int64, ev interface{ Key() string }, sync bool) {
+	if len(prs) == 0 || (ev == nil && !sync) {
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	m := new(multierror.Error)
+	if ev != nil {
+		for _, pr := range prs {
+			err := h.upsertChangesetEvent(r.Context(), pr, ev)
+			if err != nil {
+				m = multierror.Append(m, err)
+			}
+		}
+	}
+
+	if sync {
+		if err := h.enqueueChangesetSyncs(r.Context(), prs); err != nil {
+			m = multierror.Append(m, err)
+		}
+	}
+
+	if m.ErrorOrNil() != nil {
+		respond(w, http.StatusInternalServerError, m)
+	}
+}
+
+// enqueueChangesetSyncs enqueues the changesets with the given external IDs
+// in ChangesetSyncQueue, so that they don't remain outdated until the
+// ChangesetSyncer next syncs them.
+func (h Webhook) enqueueChangesetSyncs(ctx context.Context, prs []int64) error {
+	var cs []*a8n.Changeset
+	for _, pr := range prs {
+		c, err := h.Store.GetChangeset(ctx, GetChangesetOpts{
+			ExternalID:          strconv.FormatInt(pr, 10),
+			ExternalServiceType: h.Service,
+		})
+		if err == ErrNoResults {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		cs = append(cs, c)
+	}
+
+	return EnqueueChangesetSyncs(ctx, h.Store, cs...)
+}
+
 // GitHubWebhook receives GitHub organization webhook events that are
 // relevant to a8n, normalizes those events into ChangesetEvents
 // and upserts them to the database.
@@ -94,6 +152,13 @@ type BitbucketServerWebhook struct {
 	*Webhook
 }
 
+// GitLabWebhook receives GitLab project or group webhook events of merge
+// requests and enqueues the changesets of those merge requests in
+// ChangesetSyncQueue.
+type GitLabWebhook struct {
+	*Webhook
+}
+
 func NewGitHubWebhook(store *Store, repos repos.Store, now func() time.Time) *GitHubWebhook {
 	return &GitHubWebhook{&Webhook{store, repos, now, github.ServiceType}}
 }
@@ -102,6 +167,10 @@ func NewBitbucketServerWebhook(store *Store, repos repos.Store, now func() time.
 	return &BitbucketServerWebhook{&Webhook{store, repos, now, bbs.ServiceType}}
 }
 
+func NewGitLabWebhook(store *Store, repos repos.Store, now func() time.Time) *GitLabWebhook {
+	return &GitLabWebhook{&Webhook{store, repos, now, gitlab.ServiceType}}
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (h *GitHubWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	e, err := h.parseEvent(r)
@@ -111,21 +180,16 @@ func (h *GitHubWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	prs, ev := h.convertEvent(r.Context(), e)
-	if len(prs) == 0 || ev == nil {
-		respond(w, http.StatusOK, nil) // Nothing to do
-		return
-	}
 
-	m := new(multierror.Error)
-	for _, pr := range prs {
-		err := h.upsertChangesetEvent(r.Context(), pr, ev)
-		if err != nil {
-			m = multierror.Append(m, err)
-		}
-	}
-	if m.ErrorOrNil() != nil {
-		respond(w, http.StatusInternalServerError, m)
+	// Events of pull requests and their reviews change the state of the
+	// changesets, which only syncing updates.
+	var sync bool
+	switch e.(type) {
+	case *gh.PullRequestEvent, *gh.PullRequestReviewEvent:
+		sync = true
 	}
+
+	h.handleEvent(w, r, prs, ev, sync)
 }
 
 func (h *GitHubWebhook) parseEvent(r *http.Request) (interface{}, *httpError) {
@@ -197,7 +261,11 @@ func (h *GitHubWebhook) convertEvent(ctx context.Context, theirs interface{}) (p
 				ours = h.renamedTitleEvent(e)
 			}
 		case "closed":
-			ours = h.closedEvent(e)
+			if e.PullRequest.GetMerged() {
+				ours = h.mergedEvent(e)
+			} else {
+				ours = h.closedEvent(e)
+			}
 		case "reopened":
 			ours = h.reopenedEvent(e)
 		case "labeled", "unlabeled":
@@ -439,6 +507,24 @@ func (*GitHubWebhook) closedEvent(e *gh.PullRequestEvent) *github.ClosedEvent {
 	}
 }
 
+func (*GitHubWebhook) mergedEvent(e *gh.PullRequestEvent) *github.MergedEvent {
+	return &github.MergedEvent{
+		Actor: github.Actor{
+			AvatarURL: *e.Sender.AvatarURL,
+			Login:     *e.Sender.Login,
+			URL:       *e.Sender.URL,
+		},
+		MergeRefName: e.PullRequest.GetBase().GetRef(),
+		// Like for closed events, the precise event URL isn't available in
+		// this webhook payload.
+		URL: e.PullRequest.GetHTMLURL(),
+		Commit: github.Commit{
+			OID: e.PullRequest.GetMergeCommitSHA(),
+		},
+		CreatedAt: e.PullRequest.GetMergedAt(),
+	}
+}
+
 func (*GitHubWebhook) reopenedEvent(e *gh.PullRequestEvent) *github.ReopenedEvent {
 	return &github.ReopenedEvent{
 		Actor: github.Actor{
@@ -576,14 +662,15 @@ func (h *BitbucketServerWebhook) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 
 	pr, ev := h.convertEvent(e)
-	if pr == 0 || ev == nil {
+	if pr == 0 {
 		respond(w, http.StatusOK, nil) // Nothing to do
 		return
 	}
 
-	if err := h.upsertChangesetEvent(r.Context(), pr, ev); err != nil {
-		respond(w, http.StatusInternalServerError, err)
-	}
+	// All pull request events of Bitbucket Server may change the state of
+	// the changeset.
+	_, sync := e.(*bbs.PullRequestEvent)
+	h.handleEvent(w, r, []int64{pr}, ev, sync)
 }
 
 func (h *BitbucketServerWebhook) parseEvent(r *http.Request) (interface{}, *httpError) {
@@ -629,12 +716,147 @@ func (h *BitbucketServerWebhook) parseEvent(r *http.Request) (interface{}, *http
 func (h *BitbucketServerWebhook) convertEvent(theirs interface{}) (pr int64, ours interface{ Key() string }) {
 	switch e := theirs.(type) {
 	case *bbs.PullRequestEvent:
+		if e.Activity == nil {
+			return int64(e.PullRequest.ID), nil
+		}
 		return int64(e.PullRequest.ID), e.Activity
 	}
 
 	return
 }
 
+// gitlabEvent is the part of the payload of a GitLab merge request event, or
+// of a note event, that identifies the merge request.
+type gitlabEvent struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		ID int64 `json:"id"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID          int64  `json:"iid"`
+		NoteableType string `json:"noteable_type"`
+	} `json:"object_attributes"`
+	// MergeRequest is the merge request of a note event of a merge request.
+	MergeRequest *struct {
+		IID int64 `json:"iid"`
+	} `json:"merge_request"`
+}
+
+// mergeRequestIID returns the project-scoped ID of the merge request of the
+// event, or zero if the event is not of a merge request.
+func (e *gitlabEvent) mergeRequestIID() int64 {
+	switch {
+	case e.ObjectKind == "merge_request":
+		return e.ObjectAttributes.IID
+	case e.ObjectKind == "note" && e.ObjectAttributes.NoteableType == "MergeRequest" && e.MergeRequest != nil:
+		return e.MergeRequest.IID
+	}
+	return 0
+}
+
+// ServeHTTP implements the http.Handler interface. GitLab events aren't
+// normalized into ChangesetEvents, since every change of a merge request is
+// picked up by syncing its changeset.
+func (h *GitLabWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serviceIDs, e, err := h.parseEvent(r)
+	if err != nil {
+		respond(w, err.code, err)
+		return
+	}
+
+	iid := e.mergeRequestIID()
+	if iid == 0 || e.Project.ID == 0 {
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	// Merge request IIDs are only unique within their project, so the
+	// changesets are looked up by the repositories of the project on the
+	// GitLab instances whose secret authenticated the request.
+	specs := make([]api.ExternalRepoSpec, 0, len(serviceIDs))
+	for _, serviceID := range serviceIDs {
+		specs = append(specs, api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(e.Project.ID, 10),
+			ServiceType: gitlab.ServiceType,
+			ServiceID:   serviceID,
+		})
+	}
+
+	rs, lerr := h.Repos.ListRepos(r.Context(), repos.StoreListReposArgs{ExternalRepos: specs})
+	if lerr != nil {
+		respond(w, http.StatusInternalServerError, lerr)
+		return
+	}
+
+	var cs []*a8n.Changeset
+	for _, repo := range rs {
+		c, err := h.Store.GetChangeset(r.Context(), GetChangesetOpts{
+			RepoID:              repo.ID,
+			ExternalID:          strconv.FormatInt(iid, 10),
+			ExternalServiceType: h.Service,
+		})
+		if err == ErrNoResults {
+			continue
+		}
+		if err != nil {
+			respond(w, http.StatusInternalServerError, err)
+			return
+		}
+		cs = append(cs, c)
+	}
+
+	if err := EnqueueChangesetSyncs(r.Context(), h.Store, cs...); err != nil {
+		respond(w, http.StatusInternalServerError, err)
+	}
+}
+
+// parseEvent authenticates the request with the secret tokens of the GitLab
+// external services and returns the normalized URLs of the GitLab instances
+// whose secret matched, along with the event.
+func (h *GitLabWebhook) parseEvent(r *http.Request) ([]string, *gitlabEvent, *httpError) {
+	args := repos.StoreListExternalServicesArgs{Kinds: []string{"GITLAB"}}
+	es, err := h.Repos.ListExternalServices(r.Context(), args)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: GitLab sends the secret token of the webhook as is, so it
+	// is compared in constant time with the secrets of each GitLab external
+	// service. If there are no secrets or none of them matches, we return a
+	// 401 to the client.
+	token := []byte(r.Header.Get("X-Gitlab-Token"))
+
+	var serviceIDs []string
+	for _, e := range es {
+		c, _ := e.Configuration()
+		con, ok := c.(*schema.GitLabConnection)
+		if !ok {
+			continue
+		}
+		for _, hook := range con.Webhooks {
+			if hook.Secret == "" || subtle.ConstantTimeCompare(token, []byte(hook.Secret)) != 1 {
+				continue
+			}
+			baseURL, err := url.Parse(con.Url)
+			if err != nil {
+				return nil, nil, &httpError{http.StatusInternalServerError, err}
+			}
+			serviceIDs = append(serviceIDs, extsvc.NormalizeBaseURL(baseURL).String())
+			break
+		}
+	}
+
+	if len(serviceIDs) == 0 {
+		return nil, nil, &httpError{http.StatusUnauthorized, nil}
+	}
+
+	var e gitlabEvent
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, err}
+	}
+	return serviceIDs, &e, nil
+}
+
 type httpError struct {
 	code int
 	err  error
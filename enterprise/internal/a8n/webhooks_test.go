@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -24,7 +25,10 @@ import (
 	gh "github.com/google/go-github/github"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	bbs "github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/httptestutil"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
@@ -266,7 +270,490 @@ func testGitHubWebhook(db *sql.DB) func(*testing.T) {
 				}
 			})
 		}
+
+		// Events of pull requests change the state of their changesets,
+		// e.g. from closed to merged, so they also enqueue the changesets
+		// in ChangesetSyncQueue.
+		_, err = db.Exec("DELETE FROM changeset_events")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		closedAt := parseTimestamp(t, "2020-01-20T10:00:00Z")
+		mergedAt := closedAt.Add(time.Hour)
+		sender := github.Actor{
+			AvatarURL: "https://avatars2.githubusercontent.com/u/67471?v=4",
+			Login:     "tsenart",
+			URL:       "https://api.github.com/users/tsenart",
+		}
+
+		pullRequestClosed := func(at time.Time, merged bool) event {
+			pr := &gh.PullRequest{
+				Number:    gh.Int(16),
+				URL:       gh.String("https://api.github.com/repos/oklog/ulid/pulls/16"),
+				HTMLURL:   gh.String("https://github.com/oklog/ulid/pull/16"),
+				Merged:    gh.Bool(merged),
+				UpdatedAt: &at,
+				Base:      &gh.PullRequestBranch{Ref: gh.String("master")},
+			}
+			if merged {
+				pr.MergedAt = &at
+				pr.MergeCommitSHA = gh.String("0e59bc4a3d52de2eb1ba53ea2d6fa2a3d1e4c3d5")
+			}
+			return event{name: "pull_request", event: &gh.PullRequestEvent{
+				Action:      gh.String("closed"),
+				Number:      gh.Int(16),
+				PullRequest: pr,
+				Sender: &gh.User{
+					AvatarURL: gh.String(sender.AvatarURL),
+					Login:     gh.String(sender.Login),
+					URL:       gh.String(sender.URL),
+				},
+			}}
+		}
+
+		closedEvent := &a8n.ChangesetEvent{
+			ChangesetID: changesets[0].ID,
+			Kind:        a8n.ChangesetEventKindGitHubClosed,
+			Key:         fmt.Sprintf("tsenart:%d", closedAt.UnixNano()),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Metadata: &github.ClosedEvent{
+				Actor:     sender,
+				CreatedAt: closedAt,
+				URL:       "https://api.github.com/repos/oklog/ulid/pulls/16",
+			},
+		}
+
+		mergedEvent := &a8n.ChangesetEvent{
+			ChangesetID: changesets[0].ID,
+			Kind:        a8n.ChangesetEventKindGitHubMerged,
+			Key:         fmt.Sprintf("tsenart:%d", mergedAt.UnixNano()),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Metadata: &github.MergedEvent{
+				Actor:        sender,
+				MergeRefName: "master",
+				URL:          "https://github.com/oklog/ulid/pull/16",
+				Commit:       github.Commit{OID: "0e59bc4a3d52de2eb1ba53ea2d6fa2a3d1e4c3d5"},
+				CreatedAt:    mergedAt,
+			},
+		}
+
+		commentedEvent := &a8n.ChangesetEvent{
+			ChangesetID: changesets[0].ID,
+			Kind:        a8n.ChangesetEventKindGitHubCommented,
+			Key:         "540540777",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Metadata: func() interface{} {
+				m := issueComment
+				return &m
+			}(),
+		}
+
+		for _, tc := range []struct {
+			name      string
+			event     event
+			want      []*a8n.ChangesetEvent
+			wantSyncs []int64
+		}{
+			{
+				name:      "closed pull request",
+				event:     pullRequestClosed(closedAt, false),
+				want:      []*a8n.ChangesetEvent{closedEvent},
+				wantSyncs: []int64{changesets[0].ID},
+			},
+			{
+				name:      "merged pull request",
+				event:     pullRequestClosed(mergedAt, true),
+				want:      []*a8n.ChangesetEvent{closedEvent, mergedEvent},
+				wantSyncs: []int64{changesets[0].ID},
+			},
+			{
+				name:      "issue comment",
+				event:     fs["issue_comment-edited"],
+				want:      []*a8n.ChangesetEvent{closedEvent, mergedEvent, commentedEvent},
+				wantSyncs: nil,
+			},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := db.Exec("DELETE FROM campaign_worker_jobs"); err != nil {
+					t.Fatal(err)
+				}
+
+				body, err := json.Marshal(tc.event.event)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				req, err := http.NewRequest("POST", "", bytes.NewReader(body))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				req.Header.Set("X-Github-Event", tc.event.name)
+				req.Header.Set("X-Hub-Signature", sign(t, body, []byte(secret)))
+
+				rec := httptest.NewRecorder()
+				hook.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					t.Errorf("have status code %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+				}
+
+				have, _, err := store.ListChangesetEvents(ctx, ListChangesetEventsOpts{Limit: 1000})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// The IDs depend on the cases that ran before.
+				for _, e := range have {
+					e.ID = 0
+				}
+
+				if diff := cmp.Diff(have, tc.want); diff != "" {
+					t.Error(diff)
+				}
+
+				if diff := cmp.Diff(enqueuedChangesetSyncs(t, store), tc.wantSyncs); diff != "" {
+					t.Errorf("enqueued changesets: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+// Ran in integration_test.go
+func testGitLabWebhook(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		now := time.Now()
+		clock := func() time.Time {
+			return now.UTC().Truncate(time.Microsecond)
+		}
+
+		ctx := context.Background()
+
+		var userID int32
+		err := db.QueryRow("INSERT INTO users (username) VALUES ('gitlab-admin') RETURNING id").Scan(&userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		secret := "secret"
+		repoStore := repos.NewDBStore(db, sql.TxOptions{})
+		gitlabExtSvc := &repos.ExternalService{
+			Kind:        "GITLAB",
+			DisplayName: "GitLab",
+			Config: marshalJSON(t, &schema.GitLabConnection{
+				Url:          "https://gitlab.example.com",
+				Token:        "token",
+				ProjectQuery: []string{"none"},
+				Webhooks:     []*schema.GitLabWebhook{{Secret: secret}},
+			}),
+		}
+
+		err = repoStore.UpsertExternalServices(ctx, gitlabExtSvc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		extSvcID := fmt.Sprintf("extsvc:gitlab:%d", gitlabExtSvc.ID)
+		gitlabRepo := &repos.Repo{
+			Name: "gitlab.example.com/group/project",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "42",
+				ServiceType: gitlab.ServiceType,
+				ServiceID:   "https://gitlab.example.com/",
+			},
+			Sources: map[string]*repos.SourceInfo{
+				extSvcID: {
+					ID:       extSvcID,
+					CloneURL: "https://gitlab.example.com/group/project.git",
+				},
+			},
+		}
+
+		err = repoStore.UpsertRepos(ctx, gitlabRepo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		store := NewStoreWithClock(db, clock)
+
+		campaign := &a8n.Campaign{
+			Name:            "GitLab campaign",
+			Description:     "Testing the GitLab webhooks",
+			AuthorID:        userID,
+			NamespaceUserID: userID,
+		}
+
+		err = store.CreateCampaign(ctx, campaign)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changeset := &a8n.Changeset{
+			RepoID:              gitlabRepo.ID,
+			ExternalID:          "7",
+			ExternalServiceType: gitlab.ServiceType,
+			CampaignIDs:         []int64{campaign.ID},
+		}
+
+		err = store.CreateChangesets(ctx, changeset)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hook := NewGitLabWebhook(store, repoStore, clock)
+
+		for _, tc := range []struct {
+			name   string
+			secret string
+			body   string
+			code   int
+			want   []int64
+		}{
+			{
+				name:   "unauthorized",
+				secret: "wrong-secret",
+				body:   `{"object_kind": "merge_request", "project": {"id": 42}, "object_attributes": {"iid": 7}}`,
+				code:   http.StatusUnauthorized,
+			},
+			{
+				name: "no secret",
+				body: `{"object_kind": "merge_request", "project": {"id": 42}, "object_attributes": {"iid": 7}}`,
+				code: http.StatusUnauthorized,
+			},
+			{
+				name:   "invalid payload",
+				secret: secret,
+				body:   `{"object_kind": `,
+				code:   http.StatusBadRequest,
+			},
+			{
+				name:   "merge request",
+				secret: secret,
+				body:   `{"object_kind": "merge_request", "project": {"id": 42}, "object_attributes": {"iid": 7, "action": "merge"}}`,
+				code:   http.StatusOK,
+				want:   []int64{changeset.ID},
+			},
+			{
+				name:   "note of merge request",
+				secret: secret,
+				body:   `{"object_kind": "note", "project": {"id": 42}, "object_attributes": {"noteable_type": "MergeRequest"}, "merge_request": {"iid": 7}}`,
+				code:   http.StatusOK,
+				want:   []int64{changeset.ID},
+			},
+			{
+				name:   "note of issue",
+				secret: secret,
+				body:   `{"object_kind": "note", "project": {"id": 42}, "object_attributes": {"noteable_type": "Issue"}, "issue": {"iid": 7}}`,
+				code:   http.StatusOK,
+			},
+			{
+				name:   "merge request of another project",
+				secret: secret,
+				body:   `{"object_kind": "merge_request", "project": {"id": 43}, "object_attributes": {"iid": 7}}`,
+				code:   http.StatusOK,
+			},
+			{
+				name:   "merge request without changeset",
+				secret: secret,
+				body:   `{"object_kind": "merge_request", "project": {"id": 42}, "object_attributes": {"iid": 8}}`,
+				code:   http.StatusOK,
+			},
+			{
+				name:   "push",
+				secret: secret,
+				body:   `{"object_kind": "push", "project": {"id": 42}}`,
+				code:   http.StatusOK,
+			},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := db.Exec("DELETE FROM campaign_worker_jobs"); err != nil {
+					t.Fatal(err)
+				}
+
+				req, err := http.NewRequest("POST", "", strings.NewReader(tc.body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tc.secret != "" {
+					req.Header.Set("X-Gitlab-Token", tc.secret)
+				}
+
+				rec := httptest.NewRecorder()
+				hook.ServeHTTP(rec, req)
+				if rec.Code != tc.code {
+					t.Errorf("have status code %d, want %d: %s", rec.Code, tc.code, rec.Body)
+				}
+
+				if diff := cmp.Diff(enqueuedChangesetSyncs(t, store), tc.want); diff != "" {
+					t.Errorf("enqueued changesets: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+// Ran in integration_test.go
+func testBitbucketServerWebhook(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		now := time.Now()
+		clock := func() time.Time {
+			return now.UTC().Truncate(time.Microsecond)
+		}
+
+		ctx := context.Background()
+
+		var userID int32
+		err := db.QueryRow("INSERT INTO users (username) VALUES ('bbs-admin') RETURNING id").Scan(&userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		secret := "secret"
+		repoStore := repos.NewDBStore(db, sql.TxOptions{})
+		bbsExtSvc := &repos.ExternalService{
+			Kind:        "BITBUCKETSERVER",
+			DisplayName: "Bitbucket Server",
+			Config: marshalJSON(t, &schema.BitbucketServerConnection{
+				Url:      "https://bitbucket.example.com",
+				Token:    "token",
+				Repos:    []string{"PROJ/repo"},
+				Webhooks: &schema.Webhooks{Secret: secret},
+			}),
+		}
+
+		err = repoStore.UpsertExternalServices(ctx, bbsExtSvc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		extSvcID := fmt.Sprintf("extsvc:bitbucketServer:%d", bbsExtSvc.ID)
+		bbsRepo := &repos.Repo{
+			Name: "bitbucket.example.com/PROJ/repo",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "1",
+				ServiceType: bbs.ServiceType,
+				ServiceID:   "https://bitbucket.example.com/",
+			},
+			Sources: map[string]*repos.SourceInfo{
+				extSvcID: {
+					ID:       extSvcID,
+					CloneURL: "https://bitbucket.example.com/scm/proj/repo.git",
+				},
+			},
+		}
+
+		err = repoStore.UpsertRepos(ctx, bbsRepo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		store := NewStoreWithClock(db, clock)
+
+		campaign := &a8n.Campaign{
+			Name:            "Bitbucket Server campaign",
+			Description:     "Testing the Bitbucket Server webhooks",
+			AuthorID:        userID,
+			NamespaceUserID: userID,
+		}
+
+		err = store.CreateCampaign(ctx, campaign)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changeset := &a8n.Changeset{
+			RepoID:              bbsRepo.ID,
+			ExternalID:          "5",
+			ExternalServiceType: bbs.ServiceType,
+			CampaignIDs:         []int64{campaign.ID},
+		}
+
+		err = store.CreateChangesets(ctx, changeset)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hook := NewBitbucketServerWebhook(store, repoStore, clock)
+
+		for _, tc := range []struct {
+			name   string
+			secret string
+			body   string
+			code   int
+			want   []int64
+		}{
+			{
+				name:   "unauthorized",
+				secret: "wrong-secret",
+				body:   `{"pullRequest": {"id": 5}}`,
+				code:   http.StatusUnauthorized,
+			},
+			{
+				name:   "pull request",
+				secret: secret,
+				body:   `{"pullRequest": {"id": 5}}`,
+				code:   http.StatusOK,
+				want:   []int64{changeset.ID},
+			},
+			{
+				name:   "pull request without changeset",
+				secret: secret,
+				body:   `{"pullRequest": {"id": 6}}`,
+				code:   http.StatusOK,
+			},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := db.Exec("DELETE FROM campaign_worker_jobs"); err != nil {
+					t.Fatal(err)
+				}
+
+				req, err := http.NewRequest("POST", "", strings.NewReader(tc.body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("X-Event-Key", "pr:opened")
+				req.Header.Set("X-Hub-Signature", sign(t, []byte(tc.body), []byte(tc.secret)))
+
+				rec := httptest.NewRecorder()
+				hook.ServeHTTP(rec, req)
+				if rec.Code != tc.code {
+					t.Errorf("have status code %d, want %d: %s", rec.Code, tc.code, rec.Body)
+				}
+
+				if diff := cmp.Diff(enqueuedChangesetSyncs(t, store), tc.want); diff != "" {
+					t.Errorf("enqueued changesets: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+// enqueuedChangesetSyncs returns the IDs of the changesets in
+// ChangesetSyncQueue.
+func enqueuedChangesetSyncs(t *testing.T, store *Store) []int64 {
+	t.Helper()
+
+	jobs, _, err := store.ListWorkerJobs(context.Background(), ListWorkerJobsOpts{Queue: ChangesetSyncQueue})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for _, j := range jobs {
+		var p changesetSyncPayload
+		if err := json.Unmarshal(j.Payload, &p); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, p.ChangesetID)
 	}
+	return ids
 }
 
 type event struct {
@@ -0,0 +1,258 @@
+package a8n
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// The queues of WorkerJobs.
+const (
+	// ChangesetJobsQueue contains the ChangesetJobs that create changesets on
+	// their code hosts.
+	ChangesetJobsQueue = "changeset_jobs"
+	// ChangesetSyncQueue contains the changesets that should be synced with
+	// their code hosts before the ChangesetSyncer would sync them, e.g.
+	// because a webhook reported that they changed.
+	ChangesetSyncQueue = "changeset_sync"
+	// ChangesetReviewersQueue contains the ChangesetJobs whose changesets
+	// still need the reviewers listed in the campaign spec to be requested.
+	ChangesetReviewersQueue = "changeset_reviewers"
+	// CampaignJobExecutionsQueue contains the CampaignJobs whose campaign
+	// spec steps are executed by the campaign-executor.
+	CampaignJobExecutionsQueue = "campaign_job_executions"
+	// ChangesetRebasesQueue contains the ChangesetJobs whose changesets are
+	// rebased onto their base branch, because their Campaign has AutoRebase.
+	ChangesetRebasesQueue = "changeset_rebases"
+)
+
+const (
+	workerHeartbeatInterval = 10 * time.Second
+	// workerStalledAfter is the duration without a heartbeat after which a
+	// WorkerJob is taken again by another worker.
+	workerStalledAfter = 6 * workerHeartbeatInterval
+
+	minWorkerRetryDelay = 30 * time.Second
+	maxWorkerRetryDelay = 30 * time.Minute
+)
+
+// A WorkerHandler processes a WorkerJob. If it returns an error, the attempt
+// fails and the job is retried later.
+type WorkerHandler func(ctx context.Context, s *Store, job *a8n.WorkerJob) error
+
+// A Worker processes the WorkerJobs of a queue. While a job is processed,
+// the Worker records heartbeats, so that the job is taken by another Worker
+// if this one goes away. Failed jobs are retried with exponential backoff
+// until they run out of attempts and end up in the dead-letter state.
+type Worker struct {
+	Store   *Store
+	Queue   string
+	Handler WorkerHandler
+
+	// Concurrency is the number of jobs processed at the same time. It
+	// defaults to 1.
+	Concurrency int
+
+	// Backoff is the time to wait before polling the queue again after it
+	// was empty or dequeueing failed.
+	Backoff time.Duration
+}
+
+// Start starts processing the WorkerJobs of the queue in the background,
+// until ctx is canceled.
+func (w *Worker) Start(ctx context.Context) {
+	n := w.Concurrency
+	if n < 1 {
+		n = 1
+	}
+
+	for i := 0; i < n; i++ {
+		go w.run(ctx)
+	}
+}
+
+func (w *Worker) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			didRun, err := w.processNext(ctx)
+			if err != nil {
+				log15.Error("Processing worker job", "queue", w.Queue, "err", err)
+			}
+			// Back off on error or when no jobs available
+			if err != nil || !didRun {
+				time.Sleep(w.Backoff)
+			}
+		}
+	}
+}
+
+// processNext dequeues and processes the next due WorkerJob of the queue.
+func (w *Worker) processNext(ctx context.Context) (didRun bool, err error) {
+	job, err := w.Store.DequeueWorkerJob(ctx, w.Queue, workerStalledAfter)
+	if err == ErrNoResults {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "dequeueing worker job")
+	}
+
+	var jobErr error
+	if job.Attempts > job.MaxAttempts {
+		// The job stalled during its last attempt, so we don't run it again.
+		jobErr = errors.New("worker stopped recording heartbeats")
+	} else {
+		began := time.Now()
+		jobErr = w.process(ctx, job)
+		observeWorkerJob(w.Queue, began, jobErr)
+	}
+
+	if jobErr == nil {
+		err = w.Store.CompleteWorkerJob(ctx, job)
+	} else {
+		log15.Warn("Worker job failed", "queue", w.Queue, "id", job.ID, "attempt", job.Attempts, "err", jobErr)
+		err = w.Store.FailWorkerJob(ctx, job, jobErr, workerRetryDelay(job.Attempts))
+	}
+
+	if err == ErrNoResults {
+		// The job stalled and was taken by another worker in the meantime,
+		// whose attempt counts instead.
+		return true, nil
+	}
+
+	return true, err
+}
+
+// process runs the Handler for the given job while recording heartbeats.
+func (w *Worker) process(ctx context.Context, job *a8n.WorkerJob) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// The heartbeats update a copy of the job, since the Handler may read it
+	// concurrently.
+	hb := job.Clone()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(workerHeartbeatInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				err := w.Store.HeartbeatWorkerJob(ctx, hb)
+				if err == ErrNoResults {
+					// Another worker took the job, so we stop processing it.
+					cancel()
+					return
+				}
+				if err != nil && ctx.Err() == nil {
+					log15.Warn("Recording worker job heartbeat", "queue", w.Queue, "id", job.ID, "err", err)
+				}
+			}
+		}
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic: %v", r)
+		}
+	}()
+
+	return w.Handler(ctx, w.Store, job)
+}
+
+// workerRetryDelay returns the time to wait before retrying a WorkerJob that
+// failed on the given attempt.
+func workerRetryDelay(attempt int32) time.Duration {
+	d := minWorkerRetryDelay
+	for i := int32(1); i < attempt && d < maxWorkerRetryDelay; i++ {
+		d *= 2
+	}
+
+	if d > maxWorkerRetryDelay {
+		return maxWorkerRetryDelay
+	}
+	return d
+}
+
+// changesetJobPayload is the payload of the WorkerJobs in
+// ChangesetJobsQueue and ChangesetReviewersQueue. It's built by
+// Store.CreateChangesetJob, Store.ResetChangesetJobs and
+// enqueueChangesetReviewers.
+type changesetJobPayload struct {
+	ChangesetJobID int64 `json:"changeset_job_id"`
+}
+
+// changesetSyncPayload is the payload of the WorkerJobs in
+// ChangesetSyncQueue.
+type changesetSyncPayload struct {
+	ChangesetID int64 `json:"changeset_id"`
+}
+
+// EnqueueChangesetSyncs enqueues the given Changesets in ChangesetSyncQueue.
+func EnqueueChangesetSyncs(ctx context.Context, s *Store, cs ...*a8n.Changeset) error {
+	for _, c := range cs {
+		payload, err := json.Marshal(changesetSyncPayload{ChangesetID: c.ID})
+		if err != nil {
+			return err
+		}
+
+		err = s.EnqueueWorkerJob(ctx, &a8n.WorkerJob{
+			Queue:   ChangesetSyncQueue,
+			Payload: payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunChangesetSyncJobs should run in a background goroutine and is
+// responsible for syncing the changesets in ChangesetSyncQueue.
+// ctx should be canceled to terminate the function
+func RunChangesetSyncJobs(ctx context.Context, s *Store, syncer *ChangesetSyncer, backoffDuration time.Duration) {
+	process := func(ctx context.Context, s *Store, job *a8n.WorkerJob) error {
+		var p changesetSyncPayload
+		if err := json.Unmarshal(job.Payload, &p); err != nil {
+			return errors.Wrap(err, "parsing payload")
+		}
+
+		c, err := s.GetChangeset(ctx, GetChangesetOpts{ID: p.ChangesetID})
+		if err == ErrNoResults {
+			// The changeset was deleted in the meantime.
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "getting changeset")
+		}
+
+		return syncer.SyncChangesets(ctx, c)
+	}
+
+	w := &Worker{
+		Store:   s,
+		Queue:   ChangesetSyncQueue,
+		Handler: process,
+		Backoff: backoffDuration,
+	}
+	w.Start(ctx)
+}
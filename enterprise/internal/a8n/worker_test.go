@@ -0,0 +1,21 @@
+package a8n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerRetryDelay(t *testing.T) {
+	for attempt, want := range map[int32]time.Duration{
+		1:   30 * time.Second,
+		2:   time.Minute,
+		3:   2 * time.Minute,
+		6:   16 * time.Minute,
+		7:   maxWorkerRetryDelay,
+		100: maxWorkerRetryDelay,
+	} {
+		if have := workerRetryDelay(attempt); have != want {
+			t.Errorf("attempt %d: have %s, want %s", attempt, have, want)
+		}
+	}
+}
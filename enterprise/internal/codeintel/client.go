@@ -0,0 +1,33 @@
+// Package codeintel contains the parts of code intelligence shared by the
+// enterprise frontend packages.
+package codeintel
+
+import (
+	"context"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+// Client queries the code intelligence data of LSIF uploads. It is implemented by
+// the lsif-server HTTP client, client.DefaultClient.
+type Client interface {
+	// Definitions returns the definitions of the symbol at the given position, the
+	// URL of the next page of results, and whether the results are partial.
+	Definitions(ctx context.Context, args *client.DefinitionsOptions) ([]*lsif.LSIFLocation, string, bool, error)
+	// References returns a page of the references of the symbol at the given
+	// position, the URL of the next page of results, and whether the results are
+	// partial.
+	References(ctx context.Context, args *client.ReferencesOptions) ([]*lsif.LSIFLocation, string, bool, error)
+	// ReferenceCount returns the approximate number of references of the symbol at
+	// the given position.
+	ReferenceCount(ctx context.Context, args *client.ReferenceCountOptions) (int32, error)
+	// Hover returns the hover text of the symbol at the given position and its range.
+	Hover(ctx context.Context, args *client.HoverOptions) (string, lsp.Range, error)
+	// Hovers returns the hovers of the symbols at the given positions of a document, in
+	// the order of the positions. The hover of a position without a symbol is nil.
+	Hovers(ctx context.Context, args *client.HoversOptions) ([]*client.Hover, error)
+}
+
+var _ Client = client.DefaultClient
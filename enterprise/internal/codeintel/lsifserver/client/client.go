@@ -2,20 +2,65 @@ package client
 
 import (
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/golang/groupcache/lru"
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 var DefaultClient = &Client{
 	URL: lsifserver.ServerURLFromEnv,
 	HTTPClient: &http.Client{
 		// nethttp.Transport will propagate opentracing spans
-		Transport: &nethttp.Transport{},
+		Transport: &nethttp.Transport{
+			RoundTripper: &contentEncodingTransport{
+				RoundTripper: &http.Transport{
+					// Default is 2, but hovers send many concurrent requests
+					MaxIdleConnsPerHost: 100,
+				},
+			},
+		},
 	},
+	MaxInFlightRequests: maxInFlightRequests(),
+}
+
+func maxInFlightRequests() int {
+	n, err := strconv.Atoi(lsifserver.MaxInFlightRequestsFromEnv)
+	if err != nil {
+		log15.Error("Invalid LSIF_SERVER_MAX_IN_FLIGHT_REQUESTS, not limiting requests", "err", err)
+		return 0
+	}
+	return n
 }
 
 type Client struct {
+	// URL specifies the lsif-server replicas, either as a space separated list of URLs or
+	// as a k8s+http:// URL (see endpoint.New). Requests concerning the same upload are
+	// always sent to the same replica, other requests to the least busy one.
 	URL        string
 	HTTPClient *http.Client
+
+	// MaxInFlightRequests limits the number of concurrent requests to each replica. It is
+	// unlimited if zero.
+	MaxInFlightRequests int
+
+	once      sync.Once
+	endpoints *endpoint.Map
+
+	replicasMu sync.Mutex
+	replicas   map[string]*replica
+
+	// definitions caches definitions by the range of their symbol (see cachedDefinitions).
+	definitionsMu sync.Mutex
+	definitions   *lru.Cache
+
+	mu                  sync.Mutex
+	lastSuccessfulQuery time.Time
+	lastFailedQuery     time.Time
+	lastQueryError      string
 }
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+// definitionsCacheSize is the number of lines of documents whose definitions are cached.
+const definitionsCacheSize = 10000
+
+// definitionsCacheTTL is how long definitions are cached. Definitions found in other uploads
+// can change when they are uploaded, unlike the upload queried.
+const definitionsCacheTTL = 5 * time.Minute
+
+// definitionsCacheKey identifies a line of a document of an upload. The definitions cached for
+// a line are those of the symbols starting on that line.
+type definitionsCacheKey struct {
+	uploadID int64
+	path     string
+	line     int
+}
+
+type definitionsCacheEntry struct {
+	rng       lsp.Range
+	locations []*lsif.LSIFLocation
+	expiresAt time.Time
+}
+
+// cachedDefinitions returns the cached definitions of the symbol at the given position, if
+// the definitions of a symbol whose range contains the position were cached. All positions
+// of a symbol share its definitions, so hovering anywhere on a token reuses them.
+func (c *Client) cachedDefinitions(uploadID int64, path string, line, character int) ([]*lsif.LSIFLocation, bool) {
+	c.definitionsMu.Lock()
+	defer c.definitionsMu.Unlock()
+
+	if c.definitions == nil {
+		return nil, false
+	}
+
+	v, ok := c.definitions.Get(definitionsCacheKey{uploadID: uploadID, path: path, line: line})
+	if !ok {
+		return nil, false
+	}
+
+	now := time.Now()
+	for _, e := range v.([]*definitionsCacheEntry) {
+		if now.Before(e.expiresAt) && rangeContains(e.rng, line, character) {
+			return e.locations, true
+		}
+	}
+	return nil, false
+}
+
+// cacheDefinitions caches the definitions of the symbol with the given range.
+func (c *Client) cacheDefinitions(uploadID int64, path string, rng lsp.Range, locations []*lsif.LSIFLocation) {
+	c.definitionsMu.Lock()
+	defer c.definitionsMu.Unlock()
+
+	if c.definitions == nil {
+		c.definitions = lru.New(definitionsCacheSize)
+	}
+
+	key := definitionsCacheKey{uploadID: uploadID, path: path, line: rng.Start.Line}
+	now := time.Now()
+	entries := []*definitionsCacheEntry{{rng: rng, locations: locations, expiresAt: now.Add(definitionsCacheTTL)}}
+	if v, ok := c.definitions.Get(key); ok {
+		for _, e := range v.([]*definitionsCacheEntry) {
+			if now.Before(e.expiresAt) && e.rng != rng {
+				entries = append(entries, e)
+			}
+		}
+	}
+	c.definitions.Add(key, entries)
+}
+
+// rangeContains returns whether the given position is within the given range, whose end is
+// exclusive.
+func rangeContains(rng lsp.Range, line, character int) bool {
+	if line < rng.Start.Line || (line == rng.Start.Line && character < rng.Start.Character) {
+		return false
+	}
+	if line > rng.End.Line || (line == rng.End.Line && character >= rng.End.Character) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+func TestRangeContains(t *testing.T) {
+	rng := lsp.Range{
+		Start: lsp.Position{Line: 10, Character: 4},
+		End:   lsp.Position{Line: 11, Character: 2},
+	}
+
+	for _, tc := range []struct {
+		line, character int
+		want            bool
+	}{
+		{line: 10, character: 4, want: true},
+		{line: 10, character: 80, want: true},
+		{line: 11, character: 1, want: true},
+		{line: 10, character: 3, want: false},
+		{line: 11, character: 2, want: false},
+		{line: 9, character: 5, want: false},
+		{line: 12, character: 0, want: false},
+	} {
+		if have := rangeContains(rng, tc.line, tc.character); have != tc.want {
+			t.Errorf("%d:%d: have %v, want %v", tc.line, tc.character, have, tc.want)
+		}
+	}
+}
+
+func TestDefinitionsCache(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		// Definitions of uploads of partial.go are partial.
+		partial := r.URL.Query().Get("path") == "partial.go"
+		if partial {
+			_, _ = io.WriteString(w, `{"locations": [{"path": "b.go"}], "range": {"start": {"line": 10, "character": 4}, "end": {"line": 10, "character": 9}}, "partial": true}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"locations": [{"path": "b.go"}], "range": {"start": {"line": 10, "character": 4}, "end": {"line": 10, "character": 9}}}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+
+	definitions := func(uploadID int64, path string, character int32) {
+		t.Helper()
+		locations, _, _, err := c.Definitions(context.Background(), &DefinitionsOptions{
+			RepoID:    1,
+			Commit:    "deadbeef",
+			Path:      path,
+			Line:      10,
+			Character: character,
+			UploadID:  uploadID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(locations) != 1 || locations[0].Path != "b.go" {
+			t.Errorf("have locations %v, want b.go", locations)
+		}
+	}
+	wantRequests := func(want int32) {
+		t.Helper()
+		if have := atomic.LoadInt32(&requests); have != want {
+			t.Errorf("have %d requests, want %d", have, want)
+		}
+	}
+
+	definitions(2, "a.go", 5)
+	wantRequests(1)
+
+	// Other positions of the same symbol are answered from the cache.
+	definitions(2, "a.go", 4)
+	definitions(2, "a.go", 8)
+	wantRequests(1)
+
+	// Positions after the symbol, and other uploads and documents, are not.
+	definitions(2, "a.go", 9)
+	wantRequests(2)
+	definitions(3, "a.go", 5)
+	wantRequests(3)
+	definitions(2, "c.go", 5)
+	wantRequests(4)
+
+	// Partial results are not cached.
+	definitions(2, "partial.go", 5)
+	definitions(2, "partial.go", 5)
+	wantRequests(6)
+}
+
+func TestDefinitionsCacheExpiry(t *testing.T) {
+	c := &Client{}
+	rng := lsp.Range{
+		Start: lsp.Position{Line: 10, Character: 4},
+		End:   lsp.Position{Line: 10, Character: 9},
+	}
+	other := lsp.Range{
+		Start: lsp.Position{Line: 10, Character: 20},
+		End:   lsp.Position{Line: 10, Character: 25},
+	}
+	locations := []*lsif.LSIFLocation{{Path: "b.go"}}
+
+	if _, ok := c.cachedDefinitions(2, "a.go", 10, 5); ok {
+		t.Fatal("have definitions cached in an empty cache")
+	}
+
+	// The definitions of all symbols starting on a line are kept.
+	c.cacheDefinitions(2, "a.go", rng, locations)
+	c.cacheDefinitions(2, "a.go", other, nil)
+	if have, ok := c.cachedDefinitions(2, "a.go", 10, 5); !ok || len(have) != 1 {
+		t.Errorf("have definitions %v (cached %v), want b.go", have, ok)
+	}
+	if _, ok := c.cachedDefinitions(2, "a.go", 10, 21); !ok {
+		t.Error("have definitions of the other symbol not cached, want cached")
+	}
+	if _, ok := c.cachedDefinitions(2, "a.go", 10, 12); ok {
+		t.Error("have definitions cached between the symbols, want not cached")
+	}
+
+	v, _ := c.definitions.Get(definitionsCacheKey{uploadID: 2, path: "a.go", line: 10})
+	for _, e := range v.([]*definitionsCacheEntry) {
+		if e.rng == rng {
+			e.expiresAt = time.Now().Add(-time.Second)
+		}
+	}
+	if _, ok := c.cachedDefinitions(2, "a.go", 10, 5); ok {
+		t.Error("have expired definitions cached, want not cached")
+	}
+	if _, ok := c.cachedDefinitions(2, "a.go", 10, 21); !ok {
+		t.Error("have definitions of the other symbol expired, want cached")
+	}
+}
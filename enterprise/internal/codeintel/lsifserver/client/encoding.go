@@ -0,0 +1,94 @@
+package client
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// acceptEncoding lists the content encodings that contentEncodingTransport negotiates
+// for responses, in order of preference.
+const acceptEncoding = "zstd, gzip"
+
+// contentEncodingTransport is an http.RoundTripper that negotiates compressed responses
+// from the lsif-server and decodes them transparently, which speeds up the transfer of
+// large responses such as pages of reference results. Unlike the transparent
+// compression of http.Transport, it supports zstd in addition to gzip.
+type contentEncodingTransport struct {
+	http.RoundTripper
+}
+
+func (t *contentEncodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Responses to requests that negotiate their own encoding are passed through.
+	if req.Header.Get("Accept-Encoding") != "" || req.Method == "HEAD" {
+		return t.RoundTripper.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the given request.
+	req2 := *req
+	req2.Header = req.Header.Clone()
+	req2.Header.Set("Accept-Encoding", acceptEncoding)
+
+	resp, err := t.RoundTripper.RoundTrip(&req2)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := resp.Header.Get("Content-Encoding")
+	if encoding == "" {
+		return resp, nil
+	}
+
+	body, err := decodeBody(encoding, resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decodeBody returns the decoded form of the given body with the given content encoding.
+func decodeBody(encoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "identity":
+		return body, nil
+
+	case "gzip":
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding gzip response")
+		}
+		return &decodedBody{Reader: r, close: body.Close}, nil
+
+	case "zstd":
+		d, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding zstd response")
+		}
+		return &decodedBody{Reader: d, close: func() error {
+			d.Close()
+			return body.Close()
+		}}, nil
+
+	default:
+		return nil, errors.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// decodedBody is a decoded response body. Closing it closes the original body.
+type decodedBody struct {
+	io.Reader
+	close func() error
+}
+
+func (b *decodedBody) Close() error { return b.close() }
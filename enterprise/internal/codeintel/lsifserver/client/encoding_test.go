@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestContentEncodingTransport(t *testing.T) {
+	const body = `{"locations": []}`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.URL.Query().Get("encoding")
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+
+		switch encoding {
+		case "zstd":
+			e, _ := zstd.NewWriter(w)
+			_, _ = io.WriteString(e, body)
+			_ = e.Close()
+		case "gzip":
+			g := gzip.NewWriter(w)
+			_, _ = io.WriteString(g, body)
+			_ = g.Close()
+		case "br":
+			_, _ = io.WriteString(w, "not brotli")
+		default:
+			_, _ = io.WriteString(w, body)
+		}
+	}))
+	defer ts.Close()
+
+	c := &http.Client{Transport: &contentEncodingTransport{RoundTripper: http.DefaultTransport}}
+
+	for _, encoding := range []string{"", "identity", "gzip", "zstd"} {
+		resp, err := c.Get(ts.URL + "?encoding=" + encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(have) != body {
+			t.Errorf("%q: have body %q, want %q", encoding, have, body)
+		}
+		if accept := resp.Header.Get("X-Accept-Encoding"); accept != acceptEncoding {
+			t.Errorf("%q: have Accept-Encoding %q, want %q", encoding, accept, acceptEncoding)
+		}
+		if resp.Header.Get("Content-Encoding") != "" {
+			t.Errorf("%q: have Content-Encoding %q on the decoded response", encoding, resp.Header.Get("Content-Encoding"))
+		}
+	}
+
+	if _, err := c.Get(ts.URL + "?encoding=br"); err == nil {
+		t.Error("have no error for an unsupported encoding")
+	}
+}
+
+func TestContentEncodingTransportPassesThroughAcceptEncoding(t *testing.T) {
+	var compressed bytes.Buffer
+	g := gzip.NewWriter(&compressed)
+	_, _ = io.WriteString(g, "body")
+	_ = g.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if have := r.Header.Get("Accept-Encoding"); have != "gzip" {
+			t.Errorf("have Accept-Encoding %q, want gzip", have)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compressed.Bytes())
+	}))
+	defer ts.Close()
+
+	c := &http.Client{Transport: &contentEncodingTransport{RoundTripper: http.DefaultTransport}}
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// Requests that negotiate their own encoding get the response as is.
+	have, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, compressed.Bytes()) || resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("have body %q encoded with %q, want the gzipped body", have, resp.Header.Get("Content-Encoding"))
+	}
+}
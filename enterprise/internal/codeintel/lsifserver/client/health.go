@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Health describes the health of the LSIF server as seen by a Client.
+type Health struct {
+	// Healthy is true if every LSIF server replica responded to the health check.
+	Healthy bool `json:"healthy"`
+	// Replicas is the number of LSIF server replicas requests are balanced across.
+	Replicas int `json:"replicas"`
+	// Error is the reason the health check failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// LastSuccessfulQuery is the time of the last request the LSIF server answered without
+	// a server error, or nil if there was none yet.
+	LastSuccessfulQuery *time.Time `json:"lastSuccessfulQuery"`
+	// LastFailedQuery is the time of the last request that could not be made or that the
+	// LSIF server answered with a server error, or nil if there was none yet.
+	LastFailedQuery *time.Time `json:"lastFailedQuery"`
+	// LastQueryError is the error of the last failed request.
+	LastQueryError string `json:"lastQueryError,omitempty"`
+}
+
+// Health checks whether the LSIF server replicas are up and returns their health along with
+// the outcome of the previous requests made by the client. The returned Health is non-nil
+// even if the health check fails. Health checks are not counted as queries.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	replicas, err := c.checkReplicas(ctx)
+
+	c.mu.Lock()
+	health := &Health{
+		Healthy:             err == nil,
+		Replicas:            replicas,
+		LastSuccessfulQuery: timeOrNil(c.lastSuccessfulQuery),
+		LastFailedQuery:     timeOrNil(c.lastFailedQuery),
+		LastQueryError:      c.lastQueryError,
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		err = errors.Wrap(err, "lsif server health check")
+		health.Error = err.Error()
+	}
+	return health, err
+}
+
+// checkReplicas sends a health check to every replica and returns the number of replicas.
+func (c *Client) checkReplicas(ctx context.Context) (int, error) {
+	endpoints, err := c.endpointMap().Endpoints()
+	if err != nil {
+		return 0, err
+	}
+	if len(endpoints) == 0 {
+		return 0, errors.New("no lsif-server replicas available")
+	}
+
+	urls := make([]string, 0, len(endpoints))
+	for url := range endpoints {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	var failures []string
+	for _, url := range urls {
+		req := &lsifRequest{
+			path:        "/healthz",
+			replica:     url,
+			healthCheck: true,
+		}
+
+		if _, err := c.do(ctx, req, nil); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", url, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return len(urls), errors.New(strings.Join(failures, "; "))
+	}
+	return len(urls), nil
+}
+
+// recordQuery records the outcome of a request for Health. Errors due to the LSIF server
+// rejecting or not finding what was requested do not count as failures.
+func (c *Client) recordQuery(err error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := errors.Cause(err).(*lsifError); err == nil || (ok && e.StatusCode < 500) {
+		c.lastSuccessfulQuery = now
+		return
+	}
+	c.lastFailedQuery = now
+	c.lastQueryError = err.Error()
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
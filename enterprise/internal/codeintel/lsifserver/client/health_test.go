@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	newReplica := func(unhealthy *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/healthz":
+				if atomic.LoadInt32(unhealthy) != 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			case "/uploads/1":
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				http.Error(w, "database is locked", http.StatusInternalServerError)
+			}
+		}))
+	}
+
+	var unhealthy0, unhealthy1 int32
+	ts0, ts1 := newReplica(&unhealthy0), newReplica(&unhealthy1)
+	defer ts0.Close()
+	defer ts1.Close()
+
+	c := &Client{URL: ts0.URL + " " + ts1.URL, HTTPClient: http.DefaultClient}
+
+	health, err := c.Health(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !health.Healthy || health.Replicas != 2 || health.LastSuccessfulQuery != nil || health.LastFailedQuery != nil {
+		t.Errorf("have health %+v, want 2 healthy replicas without queries", health)
+	}
+
+	// Uploads that are not found are no failures of the LSIF server.
+	getUpload := func(id int64) error {
+		_, err := c.GetUpload(context.Background(), &struct{ UploadID int64 }{UploadID: id})
+		return err
+	}
+	if err := getUpload(1); !IsNotFound(err) {
+		t.Fatalf("have error %v, want not found", err)
+	}
+	if health, _ = c.Health(context.Background()); health.LastSuccessfulQuery == nil || health.LastFailedQuery != nil {
+		t.Errorf("have health %+v, want a successful query", health)
+	}
+
+	if err := getUpload(2); err == nil {
+		t.Fatal("have no error, want internal server error")
+	}
+	if health, _ = c.Health(context.Background()); health.LastFailedQuery == nil || !strings.Contains(health.LastQueryError, "database is locked") {
+		t.Errorf("have health %+v, want a failed query", health)
+	}
+	lastFailedQuery := *health.LastFailedQuery
+
+	// Failed health checks are not counted as failed queries.
+	atomic.StoreInt32(&unhealthy1, 1)
+	health, err = c.Health(context.Background())
+	if err == nil {
+		t.Fatal("have no error with an unhealthy replica, want error")
+	}
+	if health.Healthy || health.Replicas != 2 || !strings.Contains(health.Error, ts1.URL) || strings.Contains(health.Error, ts0.URL) {
+		t.Errorf("have health %+v, want replica %s unhealthy", health, ts1.URL)
+	}
+	if !health.LastFailedQuery.Equal(lastFailedQuery) {
+		t.Errorf("have last failed query at %s, want %s", health.LastFailedQuery, lastFailedQuery)
+	}
+}
+
+func TestHealthNoReplicas(t *testing.T) {
+	c := &Client{URL: " "}
+
+	health, err := c.Health(context.Background())
+	if err == nil {
+		t.Fatal("have no error without replicas, want error")
+	}
+	if health.Healthy || health.Replicas != 0 || health.Error == "" {
+		t.Errorf("have health %+v, want unhealthy", health)
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// DefinitionsOptions are the arguments of a definitions request.
+type DefinitionsOptions struct {
+	RepoID    api.RepoID
+	Commit    graphqlbackend.GitObjectID
+	Path      string
+	Line      int32
+	Character int32
+	UploadID  int64
+}
+
+// Validate returns an error if the options don't identify a position in an upload.
+func (o *DefinitionsOptions) Validate() error {
+	return validatePosition(o.RepoID, o.Commit, o.Path, o.Line, o.Character, o.UploadID)
+}
+
+// ReferencesOptions are the arguments of a references request.
+type ReferencesOptions struct {
+	RepoID    api.RepoID
+	Commit    graphqlbackend.GitObjectID
+	Path      string
+	Line      int32
+	Character int32
+	UploadID  int64
+	// Limit is the maximum number of locations in a page of results.
+	Limit *int32
+	// Cursor is the URL of the page of results to fetch, as returned by a previous request.
+	Cursor *string
+}
+
+// Validate returns an error if the options don't identify a position in an upload or
+// if the limit is not positive.
+func (o *ReferencesOptions) Validate() error {
+	if err := validatePosition(o.RepoID, o.Commit, o.Path, o.Line, o.Character, o.UploadID); err != nil {
+		return err
+	}
+	if o.Limit != nil && *o.Limit <= 0 {
+		return errors.Errorf("invalid limit %d", *o.Limit)
+	}
+	return nil
+}
+
+// ReferenceCountOptions are the arguments of a reference count request.
+type ReferenceCountOptions struct {
+	RepoID    api.RepoID
+	Commit    graphqlbackend.GitObjectID
+	Path      string
+	Line      int32
+	Character int32
+	UploadID  int64
+}
+
+// Validate returns an error if the options don't identify a position in an upload.
+func (o *ReferenceCountOptions) Validate() error {
+	return validatePosition(o.RepoID, o.Commit, o.Path, o.Line, o.Character, o.UploadID)
+}
+
+// HoverOptions are the arguments of a hover request.
+type HoverOptions struct {
+	RepoID    api.RepoID
+	Commit    graphqlbackend.GitObjectID
+	Path      string
+	Line      int32
+	Character int32
+	UploadID  int64
+}
+
+// Validate returns an error if the options don't identify a position in an upload.
+func (o *HoverOptions) Validate() error {
+	return validatePosition(o.RepoID, o.Commit, o.Path, o.Line, o.Character, o.UploadID)
+}
+
+// MaxHoverPositions is the maximum number of positions of a hovers request.
+const MaxHoverPositions = 100
+
+// HoversOptions are the arguments of a request for the hovers of several positions of
+// the same document.
+type HoversOptions struct {
+	RepoID    api.RepoID
+	Commit    graphqlbackend.GitObjectID
+	Path      string
+	Positions []lsp.Position
+	UploadID  int64
+}
+
+// Validate returns an error if the options don't identify positions in an upload or if
+// there are more than MaxHoverPositions positions.
+func (o *HoversOptions) Validate() error {
+	switch {
+	case len(o.Positions) == 0:
+		return errors.New("missing positions")
+	case len(o.Positions) > MaxHoverPositions:
+		return errors.Errorf("too many positions (%d), at most %d are allowed", len(o.Positions), MaxHoverPositions)
+	}
+	for _, p := range o.Positions {
+		if err := validatePosition(o.RepoID, o.Commit, o.Path, int32(p.Line), int32(p.Character), o.UploadID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePosition(repoID api.RepoID, commit graphqlbackend.GitObjectID, path string, line, character int32, uploadID int64) error {
+	switch {
+	case repoID == 0:
+		return errors.New("missing repository")
+	case commit == "":
+		return errors.New("missing commit")
+	case path == "":
+		return errors.New("missing path")
+	case line < 0 || character < 0:
+		return errors.Errorf("invalid position %d:%d", line, character)
+	case uploadID <= 0:
+		return errors.New("missing upload")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+)
+
+// uploadKey is the key of requests concerning the upload with the given ID.
+func uploadKey(id int64) string {
+	return fmt.Sprintf("upload:%d", id)
+}
+
+// repositoryKey is the key of requests concerning the repository with the given ID but
+// no particular upload.
+func repositoryKey(id api.RepoID) string {
+	return fmt.Sprintf("repository:%d", id)
+}
+
+// replica tracks the requests in flight to one lsif-server replica.
+type replica struct {
+	// sem holds a token for each request in flight. It is nil if the number of
+	// requests is unlimited.
+	sem      chan struct{}
+	inFlight int64
+}
+
+func (c *Client) endpointMap() *endpoint.Map {
+	c.once.Do(func() {
+		if len(strings.Fields(c.URL)) == 0 {
+			c.endpoints = endpoint.Empty(errors.New("an lsif-server service has not been configured"))
+		} else {
+			c.endpoints = endpoint.New(c.URL)
+		}
+	})
+	return c.endpoints
+}
+
+// pick returns the URL of the replica a request with the given key is sent to. Requests
+// with the same key are sent to the same replica, as long as the set of replicas doesn't
+// change. Requests without a key are sent to the replica with the fewest requests in
+// flight.
+func (c *Client) pick(key string) (string, error) {
+	if key != "" {
+		url, err := c.endpointMap().Get(key, nil)
+		if err == nil && url == "" {
+			err = errors.New("no lsif-server replicas available")
+		}
+		return url, err
+	}
+
+	urls, err := c.endpointMap().Endpoints()
+	if err != nil {
+		return "", err
+	}
+
+	best, bestInFlight := "", int64(-1)
+	for url := range urls {
+		if n := atomic.LoadInt64(&c.replica(url).inFlight); bestInFlight < 0 || n < bestInFlight {
+			best, bestInFlight = url, n
+		}
+	}
+	if best == "" {
+		return "", errors.New("no lsif-server replicas available")
+	}
+	return best, nil
+}
+
+// acquire waits until the replica with the given URL can take another request without
+// exceeding MaxInFlightRequests. The returned function must be called once the request
+// is done.
+func (c *Client) acquire(ctx context.Context, url string) (release func(), err error) {
+	r := c.replica(url)
+
+	if r.sem != nil {
+		select {
+		case r.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "waiting for lsif-server replica")
+		}
+	}
+	atomic.AddInt64(&r.inFlight, 1)
+
+	return func() {
+		atomic.AddInt64(&r.inFlight, -1)
+		if r.sem != nil {
+			<-r.sem
+		}
+	}, nil
+}
+
+func (c *Client) replica(url string) *replica {
+	c.replicasMu.Lock()
+	defer c.replicasMu.Unlock()
+
+	if c.replicas == nil {
+		c.replicas = map[string]*replica{}
+	}
+
+	r, ok := c.replicas[url]
+	if !ok {
+		r = &replica{}
+		if c.MaxInFlightRequests > 0 {
+			r.sem = make(chan struct{}, c.MaxInFlightRequests)
+		}
+		c.replicas[url] = r
+	}
+	return r
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const testReplicas = "http://lsif-server-0 http://lsif-server-1 http://lsif-server-2"
+
+func TestPickKey(t *testing.T) {
+	c := &Client{URL: testReplicas}
+
+	picked := map[string]bool{}
+	for id := int64(1); id <= 100; id++ {
+		url, err := c.pick(uploadKey(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		picked[url] = true
+
+		// Requests of the same upload go to the same replica, regardless of the
+		// requests in flight.
+		release, err := c.acquire(context.Background(), url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := c.pick(uploadKey(id))
+		release()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again != url {
+			t.Errorf("upload %d: have replica %q, want %q", id, again, url)
+		}
+	}
+
+	if len(picked) != 3 {
+		t.Errorf("have requests of 100 uploads sent to %d replicas, want 3", len(picked))
+	}
+}
+
+func TestPickLeastInFlight(t *testing.T) {
+	c := &Client{URL: testReplicas}
+
+	var releases []func()
+	acquire := func(url string) {
+		t.Helper()
+		release, err := c.acquire(context.Background(), url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+	}
+	pick := func() string {
+		t.Helper()
+		url, err := c.pick("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return url
+	}
+
+	acquire("http://lsif-server-0")
+	acquire("http://lsif-server-0")
+	acquire("http://lsif-server-1")
+	if have, want := pick(), "http://lsif-server-2"; have != want {
+		t.Errorf("have replica %q, want %q", have, want)
+	}
+
+	acquire("http://lsif-server-2")
+	acquire("http://lsif-server-2")
+	if have, want := pick(), "http://lsif-server-1"; have != want {
+		t.Errorf("have replica %q, want %q", have, want)
+	}
+
+	// Released requests are no longer in flight.
+	releases[0]()
+	releases[1]()
+	if have, want := pick(), "http://lsif-server-0"; have != want {
+		t.Errorf("have replica %q, want %q", have, want)
+	}
+}
+
+func TestPickNoReplicas(t *testing.T) {
+	c := &Client{URL: " "}
+	if _, err := c.pick(""); err == nil {
+		t.Error("have no error without replicas, want error")
+	}
+	if _, err := c.pick(uploadKey(1)); err == nil {
+		t.Error("have no error without replicas, want error")
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	t.Run("limited", func(t *testing.T) {
+		c := &Client{URL: testReplicas, MaxInFlightRequests: 2}
+
+		var releases []func()
+		for i := 0; i < 2; i++ {
+			release, err := c.acquire(context.Background(), "http://lsif-server-0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			releases = append(releases, release)
+		}
+
+		// The limit is per replica.
+		release, err := c.acquire(context.Background(), "http://lsif-server-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := c.acquire(ctx, "http://lsif-server-0"); err == nil {
+			t.Fatal("have no error when exceeding the limit, want error")
+		}
+
+		acquired := make(chan error)
+		go func() {
+			release, err := c.acquire(context.Background(), "http://lsif-server-0")
+			if err == nil {
+				release()
+			}
+			acquired <- err
+		}()
+
+		select {
+		case err := <-acquired:
+			t.Fatalf("have request acquired (err=%v) before another one was released", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		releases[0]()
+		select {
+		case err := <-acquired:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("have request still waiting after another one was released")
+		}
+		releases[1]()
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		c := &Client{URL: testReplicas}
+
+		for i := 0; i < 100; i++ {
+			if _, err := c.acquire(context.Background(), "http://lsif-server-0"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if have := c.replica("http://lsif-server-0").inFlight; have != 100 {
+			t.Errorf("have %d requests in flight, want 100", have)
+		}
+	})
+}
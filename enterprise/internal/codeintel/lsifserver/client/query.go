@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -13,18 +15,23 @@ import (
 )
 
 func (c *Client) Exists(ctx context.Context, args *struct {
-	RepoID api.RepoID
-	Commit string
-	Path   string
+	RepoID            api.RepoID
+	Commit            string
+	Path              string
+	MaxCommitDistance int
 }) (*lsif.LSIFUpload, error) {
 	query := queryValues{}
 	query.SetInt("repositoryId", int64(args.RepoID))
 	query.Set("commit", args.Commit)
 	query.Set("path", args.Path)
+	if args.MaxCommitDistance > 0 {
+		query.SetInt("maxCommitDistance", int64(args.MaxCommitDistance))
+	}
 
 	req := &lsifRequest{
 		path:  "/exists",
 		query: query,
+		key:   repositoryKey(args.RepoID),
 	}
 
 	payload := struct {
@@ -39,6 +46,70 @@ func (c *Client) Exists(ctx context.Context, args *struct {
 	return payload.Upload, nil
 }
 
+// ExistsAll returns the uploads that contain data for the given path, ordered by their
+// distance to the given commit and then by decreasing root length. Exists returns the
+// first of them. If MaxCommitDistance is positive, only uploads of the commits within
+// that many commits visited from the given commit are returned.
+func (c *Client) ExistsAll(ctx context.Context, args *struct {
+	RepoID            api.RepoID
+	Commit            string
+	Path              string
+	MaxCommitDistance int
+}) ([]*lsif.LSIFUpload, error) {
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", args.Commit)
+	query.Set("path", args.Path)
+	if args.MaxCommitDistance > 0 {
+		query.SetInt("maxCommitDistance", int64(args.MaxCommitDistance))
+	}
+
+	req := &lsifRequest{
+		path:  "/exists",
+		query: query,
+		key:   repositoryKey(args.RepoID),
+	}
+
+	payload := struct {
+		Uploads []*lsif.LSIFUpload `json:"uploads"`
+	}{}
+
+	_, err := c.do(ctx, req, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload.Uploads, nil
+}
+
+// Coverage returns the uploads closest to the given commit of each root and indexer, in
+// the order of ExistsAll, with the paths of the files they contain data for.
+func (c *Client) Coverage(ctx context.Context, args *struct {
+	RepoID api.RepoID
+	Commit string
+}) ([]*lsif.LSIFUploadCoverage, error) {
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", args.Commit)
+
+	req := &lsifRequest{
+		path:  "/coverage",
+		query: query,
+		key:   repositoryKey(args.RepoID),
+	}
+
+	payload := struct {
+		Uploads []*lsif.LSIFUploadCoverage `json:"uploads"`
+	}{}
+
+	_, err := c.do(ctx, req, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload.Uploads, nil
+}
+
 func (c *Client) Upload(ctx context.Context, args *struct {
 	RepoID   api.RepoID
 	Commit   graphqlbackend.GitObjectID
@@ -59,6 +130,7 @@ func (c *Client) Upload(ctx context.Context, args *struct {
 		method: "POST",
 		query:  query,
 		body:   args.Body,
+		key:    repositoryKey(args.RepoID),
 	}
 
 	payload := struct {
@@ -74,26 +146,19 @@ func (c *Client) Upload(ctx context.Context, args *struct {
 
 }
 
-func (c *Client) Definitions(ctx context.Context, args *struct {
-	RepoID    api.RepoID
-	Commit    graphqlbackend.GitObjectID
-	Path      string
-	Line      int32
-	Character int32
-	UploadID  int64
-}) ([]*lsif.LSIFLocation, string, error) {
-	return c.locationQuery(ctx, &struct {
-		Operation string
-		RepoID    api.RepoID
-		Commit    graphqlbackend.GitObjectID
-		Path      string
-		Line      int32
-		Character int32
-		UploadID  int64
-		Limit     *int32
-		Cursor    *string
-	}{
-		Operation: "definitions",
+// Definitions returns the definitions of the symbol at the given position. The definitions
+// are cached by the range of the symbol returned by the LSIF server, so requests for other
+// positions of the same symbol are answered from the cache.
+func (c *Client) Definitions(ctx context.Context, args *DefinitionsOptions) ([]*lsif.LSIFLocation, string, bool, error) {
+	if err := args.Validate(); err != nil {
+		return nil, "", false, err
+	}
+
+	if locations, ok := c.cachedDefinitions(args.UploadID, args.Path, int(args.Line), int(args.Character)); ok {
+		return locations, "", false, nil
+	}
+
+	result, err := c.locationQuery(ctx, "definitions", &ReferencesOptions{
 		RepoID:    args.RepoID,
 		Commit:    args.Commit,
 		Path:      args.Path,
@@ -101,52 +166,52 @@ func (c *Client) Definitions(ctx context.Context, args *struct {
 		Character: args.Character,
 		UploadID:  args.UploadID,
 	})
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	// Partial results aren't cached, since the skipped uploads might answer the next
+	// request in time.
+	if result.Range != nil && !result.Partial {
+		c.cacheDefinitions(args.UploadID, args.Path, *result.Range, result.Locations)
+	}
+	return result.Locations, result.NextURL, result.Partial, nil
 }
 
-func (c *Client) References(ctx context.Context, args *struct {
-	RepoID    api.RepoID
-	Commit    graphqlbackend.GitObjectID
-	Path      string
-	Line      int32
-	Character int32
-	UploadID  int64
-	Limit     *int32
-	Cursor    *string
-}) ([]*lsif.LSIFLocation, string, error) {
-	return c.locationQuery(ctx, &struct {
-		Operation string
-		RepoID    api.RepoID
-		Commit    graphqlbackend.GitObjectID
-		Path      string
-		Line      int32
-		Character int32
-		UploadID  int64
-		Limit     *int32
-		Cursor    *string
-	}{
-		Operation: "references",
-		RepoID:    args.RepoID,
-		Commit:    args.Commit,
-		Path:      args.Path,
-		Line:      args.Line,
-		Character: args.Character,
-		UploadID:  args.UploadID,
-		Limit:     args.Limit,
-		Cursor:    args.Cursor,
-	})
+func (c *Client) References(ctx context.Context, args *ReferencesOptions) ([]*lsif.LSIFLocation, string, bool, error) {
+	if err := args.Validate(); err != nil {
+		return nil, "", false, err
+	}
+
+	result, err := c.locationQuery(ctx, "references", args)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return result.Locations, result.NextURL, result.Partial, nil
+}
+
+// locationQueryResponseMargin is the part of the deadline of a location query that is
+// not given to the LSIF server as its time budget, leaving time to send the response.
+const locationQueryResponseMargin = 250 * time.Millisecond
+
+// locationQueryResult is the result of a definitions or references request.
+type locationQueryResult struct {
+	Locations []*lsif.LSIFLocation
+	// NextURL is the URL of the next page of results, if any.
+	NextURL string
+	// Range is the range of the symbol at the queried position. It's only returned by
+	// definitions requests, and is nil if there is no symbol at the position.
+	Range *lsp.Range
+	// Partial is whether some uploads were skipped because they could not be queried
+	// within their share of the time budget of the request, which is derived from the
+	// deadline of the given context.
+	Partial bool
 }
 
-func (c *Client) locationQuery(ctx context.Context, args *struct {
-	Operation string
-	RepoID    api.RepoID
-	Commit    graphqlbackend.GitObjectID
-	Path      string
-	Line      int32
-	Character int32
-	UploadID  int64
-	Limit     *int32
-	Cursor    *string
-}) ([]*lsif.LSIFLocation, string, error) {
+// locationQuery performs a definitions or references request. The options must already
+// be validated.
+func (c *Client) locationQuery(ctx context.Context, operation string, args *ReferencesOptions) (*locationQueryResult, error) {
 	query := queryValues{}
 	query.SetInt("repositoryId", int64(args.RepoID))
 	query.Set("commit", string(args.Commit))
@@ -156,32 +221,78 @@ func (c *Client) locationQuery(ctx context.Context, args *struct {
 	query.SetInt("uploadId", int64(args.UploadID))
 	query.SetOptionalInt32("limit", args.Limit)
 
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline) - locationQueryResponseMargin
+		if timeout < 0 {
+			timeout = 0
+		}
+		query.SetInt("timeout", int64(timeout/time.Millisecond))
+	}
+
 	req := &lsifRequest{
-		path:   fmt.Sprintf("/%s", args.Operation),
+		path:   fmt.Sprintf("/%s", operation),
 		cursor: args.Cursor,
 		query:  query,
+		key:    uploadKey(args.UploadID),
 	}
 
 	payload := struct {
 		Locations []*lsif.LSIFLocation
+		Range     *lsp.Range
+		Partial   bool
 	}{}
 
 	meta, err := c.do(ctx, req, &payload)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	return payload.Locations, meta.nextURL, nil
+	return &locationQueryResult{
+		Locations: payload.Locations,
+		NextURL:   meta.nextURL,
+		Range:     payload.Range,
+		Partial:   payload.Partial,
+	}, nil
 }
 
-func (c *Client) Hover(ctx context.Context, args *struct {
-	RepoID    api.RepoID
-	Commit    graphqlbackend.GitObjectID
-	Path      string
-	Line      int32
-	Character int32
-	UploadID  int64
-}) (string, lsp.Range, error) {
+// ReferenceCount returns the approximate number of references of the symbol at the
+// given position. Unlike References, it doesn't materialize the locations.
+func (c *Client) ReferenceCount(ctx context.Context, args *ReferenceCountOptions) (int32, error) {
+	if err := args.Validate(); err != nil {
+		return 0, err
+	}
+
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", string(args.Commit))
+	query.Set("path", args.Path)
+	query.SetInt("line", int64(args.Line))
+	query.SetInt("character", int64(args.Character))
+	query.SetInt("uploadId", int64(args.UploadID))
+
+	req := &lsifRequest{
+		path:  "/referenceCount",
+		query: query,
+		key:   uploadKey(args.UploadID),
+	}
+
+	payload := struct {
+		Count int32 `json:"count"`
+	}{}
+
+	_, err := c.do(ctx, req, &payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return payload.Count, nil
+}
+
+func (c *Client) Hover(ctx context.Context, args *HoverOptions) (string, lsp.Range, error) {
+	if err := args.Validate(); err != nil {
+		return "", lsp.Range{}, err
+	}
+
 	query := queryValues{}
 	query.SetInt("repositoryId", int64(args.RepoID))
 	query.Set("commit", string(args.Commit))
@@ -193,6 +304,7 @@ func (c *Client) Hover(ctx context.Context, args *struct {
 	req := &lsifRequest{
 		path:  fmt.Sprintf("/hover"),
 		query: query,
+		key:   uploadKey(args.UploadID),
 	}
 
 	payload := struct {
@@ -207,3 +319,49 @@ func (c *Client) Hover(ctx context.Context, args *struct {
 
 	return payload.Text, payload.Range, nil
 }
+
+// Hover is the hover text of a symbol and its range.
+type Hover struct {
+	Text  string    `json:"text"`
+	Range lsp.Range `json:"range"`
+}
+
+// Hovers returns the hovers of the symbols at the given positions of a document, in the
+// order of the positions. The hover of a position without a symbol is nil. All positions
+// are resolved by a single request.
+func (c *Client) Hovers(ctx context.Context, args *HoversOptions) ([]*Hover, error) {
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+
+	positions := make([]string, 0, len(args.Positions))
+	for _, p := range args.Positions {
+		positions = append(positions, fmt.Sprintf("%d:%d", p.Line, p.Character))
+	}
+
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", string(args.Commit))
+	query.Set("path", args.Path)
+	query.Set("positions", strings.Join(positions, ","))
+	query.SetInt("uploadId", int64(args.UploadID))
+
+	req := &lsifRequest{
+		path:  "/hovers",
+		query: query,
+		key:   uploadKey(args.UploadID),
+	}
+
+	payload := struct {
+		Hovers []*Hover `json:"hovers"`
+	}{}
+
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload.Hovers) != len(args.Positions) {
+		return nil, fmt.Errorf("expected %d hovers, got %d", len(args.Positions), len(payload.Hovers))
+	}
+
+	return payload.Hovers, nil
+}
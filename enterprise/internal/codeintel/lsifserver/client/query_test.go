@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestLocationQueryTimeout(t *testing.T) {
+	timeouts := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeouts <- r.URL.Query().Get("timeout")
+		_, _ = io.WriteString(w, `{"locations": []}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+	opts := &ReferencesOptions{RepoID: 1, Commit: "deadbeef", Path: "main.go", UploadID: 2}
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if _, _, _, err := c.References(ctx, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		// The LSIF server gets the remaining time, without the time needed to
+		// send the response.
+		timeout, err := strconv.Atoi(<-timeouts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if max := int(2*time.Second-locationQueryResponseMargin) / int(time.Millisecond); timeout > max || timeout < max-1000 {
+			t.Errorf("have timeout %dms, want about %dms", timeout, max)
+		}
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		if _, _, _, err := c.References(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+		if timeout := <-timeouts; timeout != "" {
+			t.Errorf("have timeout %q, want none", timeout)
+		}
+	})
+
+	t.Run("exceeded deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), locationQueryResponseMargin/2)
+		defer cancel()
+
+		if _, _, _, err := c.References(ctx, opts); err != nil {
+			t.Fatal(err)
+		}
+		if timeout := <-timeouts; timeout != "0" {
+			t.Errorf("have timeout %q, want 0", timeout)
+		}
+	})
+}
+
+func TestHovers(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		if r.URL.Path != "/hovers" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, `{"hovers": [{"text": "func A()", "range": {"start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 3}}}, null]}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+	opts := &HoversOptions{
+		RepoID:    1,
+		Commit:    "deadbeef",
+		Path:      "main.go",
+		Positions: []lsp.Position{{Line: 1, Character: 2}, {Line: 5, Character: 0}},
+		UploadID:  2,
+	}
+
+	hovers, err := c.Hovers(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := (<-queries).Get("positions"), "1:2,5:0"; have != want {
+		t.Errorf("have positions %q, want %q", have, want)
+	}
+	if len(hovers) != 2 || hovers[0] == nil || hovers[0].Text != "func A()" || hovers[0].Range.Start.Character != 2 || hovers[1] != nil {
+		t.Errorf("have hovers %+v, want a hover of the first position only", hovers)
+	}
+
+	// The hovers must match the positions.
+	opts.Positions = opts.Positions[:1]
+	if _, err := c.Hovers(context.Background(), opts); err == nil {
+		t.Error("have no error for more hovers than positions, want error")
+	}
+	<-queries
+}
+
+func TestHoversOptionsValidate(t *testing.T) {
+	tooMany := make([]lsp.Position, MaxHoverPositions+1)
+
+	for _, tc := range []struct {
+		name      string
+		positions []lsp.Position
+		wantErr   bool
+	}{
+		{name: "positions", positions: []lsp.Position{{Line: 1}, {Line: 2}}},
+		{name: "max positions", positions: tooMany[:MaxHoverPositions]},
+		{name: "no positions", wantErr: true},
+		{name: "too many positions", positions: tooMany, wantErr: true},
+		{name: "invalid position", positions: []lsp.Position{{Line: 1}, {Line: -1}}, wantErr: true},
+	} {
+		opts := &HoversOptions{RepoID: 1, Commit: "deadbeef", Path: "main.go", Positions: tc.positions, UploadID: 2}
+		if err := opts.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: have error %v, want error %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coverage" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if have, want := r.URL.Query().Encode(), "commit=deadbeef&repositoryId=42"; have != want {
+			t.Errorf("have query %q, want %q", have, want)
+		}
+		_, _ = io.WriteString(w, `{"uploads": [{"upload": {"id": 7, "root": "web/"}, "paths": ["web/a.ts", "web/b.ts"]}]}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+
+	coverage, err := c.Coverage(context.Background(), &struct {
+		RepoID api.RepoID
+		Commit string
+	}{RepoID: 42, Commit: "deadbeef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(coverage) != 1 || coverage[0].Upload.ID != 7 || coverage[0].Upload.Root != "web/" {
+		t.Fatalf("have coverage %+v, want upload 7 of web/", coverage)
+	}
+	if want := []string{"web/a.ts", "web/b.ts"}; !reflect.DeepEqual(coverage[0].Paths, want) {
+		t.Errorf("have paths %v, want %v", coverage[0].Paths, want)
+	}
+}
+
+func TestExistsMaxCommitDistance(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		_, _ = io.WriteString(w, `{"upload": {"id": 7}, "uploads": [{"id": 7}]}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+
+	for _, tc := range []struct {
+		maxCommitDistance int
+		want              string
+	}{
+		{maxCommitDistance: 20, want: "20"},
+		// The LSIF server default applies.
+		{maxCommitDistance: 0, want: ""},
+	} {
+		uploads, err := c.ExistsAll(context.Background(), &struct {
+			RepoID            api.RepoID
+			Commit            string
+			Path              string
+			MaxCommitDistance int
+		}{RepoID: 42, Commit: "deadbeef", Path: "main.go", MaxCommitDistance: tc.maxCommitDistance})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(uploads) != 1 || uploads[0].ID != 7 {
+			t.Errorf("have uploads %v, want upload 7", uploads)
+		}
+		if have := (<-queries).Get("maxCommitDistance"); have != tc.want {
+			t.Errorf("distance %d: have maxCommitDistance %q, want %q", tc.maxCommitDistance, have, tc.want)
+		}
+	}
+
+	upload, err := c.Exists(context.Background(), &struct {
+		RepoID            api.RepoID
+		Commit            string
+		Path              string
+		MaxCommitDistance int
+	}{RepoID: 42, Commit: "deadbeef", Path: "main.go", MaxCommitDistance: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload == nil || upload.ID != 7 {
+		t.Errorf("have upload %v, want upload 7", upload)
+	}
+	if have := (<-queries).Get("maxCommitDistance"); have != "5" {
+		t.Errorf("have maxCommitDistance %q, want 5", have)
+	}
+}
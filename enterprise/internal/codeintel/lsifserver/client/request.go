@@ -23,6 +23,16 @@ type lsifRequest struct {
 	cursor *string
 	query  queryValues
 	body   io.ReadCloser
+
+	// key identifies what the request concerns, e.g. an upload, so that requests with the
+	// same key are sent to the same replica. Requests without a key are balanced.
+	key string
+
+	// replica is the URL of the replica the request is sent to. It overrides key.
+	replica string
+
+	// healthCheck excludes the request from the queries recorded for Health.
+	healthCheck bool
 }
 
 type lsifResponseMeta struct {
@@ -33,18 +43,30 @@ type lsifResponseMeta struct {
 // do will make a request to LSIF server. This method will return an error if the request
 // cannot be made or the status code is 400 or 500-level. If a non-nil payload is given,
 // the request body will be unmarshalled into it.
-func (c *Client) do(ctx context.Context, lsifRequest *lsifRequest, payload interface{}) (*lsifResponseMeta, error) {
+func (c *Client) do(ctx context.Context, lsifRequest *lsifRequest, payload interface{}) (meta *lsifResponseMeta, err error) {
+	if !lsifRequest.healthCheck {
+		defer func() { c.recordQuery(err) }()
+	}
+
 	method := lsifRequest.method
 	if method == "" {
 		method = "GET"
 	}
 
-	url, err := buildURL(c.URL, lsifRequest.path, lsifRequest.cursor, lsifRequest.query)
+	baseURL := lsifRequest.replica
+	if baseURL == "" {
+		if baseURL, err = c.pick(lsifRequest.key); err != nil {
+			return nil, err
+		}
+	}
+
+	url, err := buildURL(baseURL, lsifRequest.path, lsifRequest.cursor, lsifRequest.query)
 	if err != nil {
 		return nil, err
 	}
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "lsifserver.client.do")
+	span.SetTag("replica", baseURL)
 	defer func() {
 		if err != nil {
 			ext.Error.Set(span, true)
@@ -53,6 +75,12 @@ func (c *Client) do(ctx context.Context, lsifRequest *lsifRequest, payload inter
 		span.Finish()
 	}()
 
+	release, err := c.acquire(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	req, err := http.NewRequest(method, url, lsifRequest.body)
 	if err != nil {
 		return nil, err
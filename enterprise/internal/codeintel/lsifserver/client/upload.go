@@ -26,8 +26,14 @@ func (c *Client) GetUploads(ctx context.Context, args *struct {
 		query.Set("state", strings.ToLower(*args.State))
 	}
 
+	// Uploads of all repositories are listed if no repository is given.
+	path := "/uploads"
+	if args.RepoID != 0 {
+		path = fmt.Sprintf("/uploads/repository/%d", args.RepoID)
+	}
+
 	req := &lsifRequest{
-		path:   fmt.Sprintf("/uploads/repository/%d", args.RepoID),
+		path:   path,
 		cursor: args.Cursor,
 		query:  query,
 	}
@@ -52,6 +58,7 @@ func (c *Client) GetUpload(ctx context.Context, args *struct {
 }) (*lsif.LSIFUpload, error) {
 	req := &lsifRequest{
 		path: fmt.Sprintf("/uploads/%d", args.UploadID),
+		key:  uploadKey(args.UploadID),
 	}
 
 	payload := &lsif.LSIFUpload{}
@@ -65,11 +72,22 @@ func (c *Client) DeleteUpload(ctx context.Context, args *struct {
 	req := &lsifRequest{
 		path:   fmt.Sprintf("/uploads/%d", args.UploadID),
 		method: "DELETE",
+		key:    uploadKey(args.UploadID),
 	}
 
-	if _, err := c.do(ctx, req, nil); IsNotFound(err) {
-		return err
+	_, err := c.do(ctx, req, nil)
+	return err
+}
+
+func (c *Client) RetryUpload(ctx context.Context, args *struct {
+	UploadID int64
+}) error {
+	req := &lsifRequest{
+		path:   fmt.Sprintf("/uploads/%d/retry", args.UploadID),
+		method: "POST",
+		key:    uploadKey(args.UploadID),
 	}
 
-	return nil
+	_, err := c.do(ctx, req, nil)
+	return err
 }
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestGetUploadsOfAllRepositories(t *testing.T) {
+	paths := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		_, _ = io.WriteString(w, `{"uploads": [{"id": 1}], "totalCount": 1}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+
+	for _, tc := range []struct {
+		repoID api.RepoID
+		want   string
+	}{
+		{repoID: 0, want: "/uploads"},
+		{repoID: 42, want: "/uploads/repository/42"},
+	} {
+		uploads, _, totalCount, err := c.GetUploads(context.Background(), &struct {
+			RepoID          api.RepoID
+			Query           *string
+			State           *string
+			IsLatestForRepo *bool
+			Limit           *int32
+			Cursor          *string
+		}{RepoID: tc.repoID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := <-paths; have != tc.want {
+			t.Errorf("repository %d: have path %q, want %q", tc.repoID, have, tc.want)
+		}
+		if len(uploads) != 1 || uploads[0].ID != 1 || totalCount == nil || *totalCount != 1 {
+			t.Errorf("repository %d: have uploads %v and total count %v, want upload 1", tc.repoID, uploads, totalCount)
+		}
+	}
+}
+
+func TestRetryUpload(t *testing.T) {
+	requests := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r.Method + " " + r.URL.Path
+		if r.URL.Path == "/uploads/2/retry" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+
+	if err := c.RetryUpload(context.Background(), &struct{ UploadID int64 }{UploadID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := <-requests, "POST /uploads/1/retry"; have != want {
+		t.Errorf("have request %q, want %q", have, want)
+	}
+
+	// Uploads that can't be retried are reported by the LSIF server.
+	if err := c.RetryUpload(context.Background(), &struct{ UploadID int64 }{UploadID: 2}); !IsNotFound(err) {
+		t.Errorf("have error %v, want not found", err)
+	}
+	<-requests
+}
+
+func TestDeleteUpload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/uploads/1":
+			w.WriteHeader(http.StatusNoContent)
+		case "/uploads/2":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, HTTPClient: ts.Client()}
+	deleteUpload := func(id int64) error {
+		return c.DeleteUpload(context.Background(), &struct{ UploadID int64 }{UploadID: id})
+	}
+
+	if err := deleteUpload(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := deleteUpload(2); !IsNotFound(err) {
+		t.Errorf("have error %v, want not found", err)
+	}
+	// Other errors of the LSIF server are reported too, e.g. if the cached
+	// data of the upload couldn't be evicted.
+	if err := deleteUpload(3); err == nil || IsNotFound(err) {
+		t.Errorf("have error %v, want internal server error", err)
+	}
+}
@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/featureflag"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/scip"
+)
+
+// Formats of uploaded indexes, given by the format query parameter of uploads.
+const (
+	uploadFormatLSIF = "lsif"
+	uploadFormatSCIP = "scip"
+)
+
+// scipUploadsFlag gates the conversion of uploaded SCIP indexes, so that it can
+// be turned off if it misbehaves for the indexes of a site.
+var scipUploadsFlag = featureflag.Register("codeintel-scip-uploads", "Accepts uploads of SCIP indexes and converts them to LSIF dumps.", true)
+
+// parseUploadFormat returns the given index format, which defaults to LSIF.
+// SCIP indexes are rejected unless the codeintel-scip-uploads feature flag is
+// enabled for the uploader.
+func parseUploadFormat(ctx context.Context, format string) (string, error) {
+	switch format {
+	case "", uploadFormatLSIF:
+		return uploadFormatLSIF, nil
+	case uploadFormatSCIP:
+		if !scipUploadsFlag.Enabled(ctx) {
+			return "", errors.New("uploads of SCIP indexes are disabled on this site")
+		}
+		return uploadFormatSCIP, nil
+	default:
+		return "", errors.Errorf("unsupported index format %q", format)
+	}
+}
+
+var (
+	// zstdMagic is the magic number at the start of a zstd frame.
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	// gzipMagic is the magic number at the start of a gzip member.
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// prepareUpload returns the given index as an LSIF dump compressed with gzip,
+// which is the only form the lsif-server reads.
+//
+// LSIF dumps compressed with zstd, which compresses large dumps better and
+// faster, are recompressed while they are forwarded, and other LSIF dumps are
+// returned as is. SCIP indexes, which may be compressed with gzip or zstd, are
+// converted to LSIF. Closing the returned dump closes the given one.
+func prepareUpload(dump io.ReadCloser, format string) io.ReadCloser {
+	r := bufio.NewReader(dump)
+	magic, _ := r.Peek(len(zstdMagic))
+	isZstd := bytes.Equal(magic, zstdMagic)
+
+	if format == uploadFormatLSIF && !isZstd {
+		return &uploadBody{Reader: r, close: dump.Close}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeGzippedLSIF(pw, r, format, isZstd, bytes.HasPrefix(magic, gzipMagic)))
+	}()
+
+	return &uploadBody{Reader: pr, close: func() error {
+		// Unblocks the goroutine above, which fails to write to the pipe.
+		pr.Close()
+		return dump.Close()
+	}}
+}
+
+func writeGzippedLSIF(w io.Writer, r io.Reader, format string, isZstd, isGzip bool) error {
+	switch {
+	case isZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		r = d
+
+	case isGzip && format == uploadFormatSCIP:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		r = gr
+	}
+
+	gw, err := gzip.NewWriterLevel(w, gzip.BestSpeed)
+	if err != nil {
+		return err
+	}
+	if format == uploadFormatSCIP {
+		err = scip.ConvertToLSIF(r, gw)
+	} else {
+		_, err = io.Copy(gw, r)
+	}
+	if err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+type uploadBody struct {
+	io.Reader
+	close func() error
+}
+
+func (b *uploadBody) Close() error { return b.close() }
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const testDump = `{"id": "1", "type": "vertex", "label": "metaData"}` + "\n"
+
+func TestPrepareUploadRecompressesZstd(t *testing.T) {
+	var compressed bytes.Buffer
+	e, err := zstd.NewWriter(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Write([]byte(testDump)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dump := &testUploadBody{Buffer: &compressed}
+	body := prepareUpload(dump, uploadFormatLSIF)
+
+	gr, err := gzip.NewReader(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ioutil.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(have) != testDump {
+		t.Errorf("have dump %q, want %q", have, testDump)
+	}
+
+	if err := body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !dump.closed {
+		t.Error("have the uploaded dump open after closing the body")
+	}
+}
+
+func TestPrepareUploadPassesThroughGzip(t *testing.T) {
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, _ = gw.Write([]byte(testDump))
+	_ = gw.Close()
+	want := append([]byte(nil), compressed.Bytes()...)
+
+	dump := &testUploadBody{Buffer: &compressed}
+	body := prepareUpload(dump, uploadFormatLSIF)
+
+	have, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, want) {
+		t.Errorf("have dump %q, want the uploaded dump %q", have, want)
+	}
+
+	if err := body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !dump.closed {
+		t.Error("have the uploaded dump open after closing the body")
+	}
+}
+
+func TestPrepareUploadClosedEarly(t *testing.T) {
+	var compressed bytes.Buffer
+	e, _ := zstd.NewWriter(&compressed)
+	_, _ = e.Write(bytes.Repeat([]byte(testDump), 10000))
+	_ = e.Close()
+
+	dump := &testUploadBody{Buffer: &compressed}
+	body := prepareUpload(dump, uploadFormatLSIF)
+
+	// Closing the body before it is read stops the recompression.
+	if err := body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !dump.closed {
+		t.Error("have the uploaded dump open after closing the body")
+	}
+	if _, err := ioutil.ReadAll(body); err == nil {
+		t.Error("have no error reading a closed body")
+	}
+}
+
+type testUploadBody struct {
+	*bytes.Buffer
+	closed bool
+}
+
+func (b *testUploadBody) Close() error {
+	b.closed = true
+	return nil
+}
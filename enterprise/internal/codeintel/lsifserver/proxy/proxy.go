@@ -6,17 +6,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lspgateway"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -24,19 +26,26 @@ import (
 )
 
 func NewProxy() (*httpapi.LSIFServerProxy, error) {
-	url, err := url.Parse(lsifserver.ServerURLFromEnv)
+	maxSize, err := strconv.ParseInt(lsifserver.UploadSessionsMaxSizeFromEnv, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid LSIF_UPLOAD_SESSIONS_MAX_SIZE")
+	}
+	sessions, err := newUploadSessions(lsifserver.UploadSessionsDirFromEnv, maxSize)
 	if err != nil {
 		return nil, err
 	}
-
-	proxy := httputil.NewSingleHostReverseProxy(url)
+	go sessions.runJanitor()
 
 	return &httpapi.LSIFServerProxy{
-		UploadHandler: http.HandlerFunc(uploadProxyHandler(proxy)),
+		UploadHandler:         http.HandlerFunc(uploadProxyHandler()),
+		UploadSessionsHandler: http.HandlerFunc(sessions.serveCreate),
+		UploadSessionHandler:  http.HandlerFunc(sessions.serveSession),
+		LSPHandler:            lspgateway.NewHandler(resolvers.NewResolver()),
+		BadgeHandler:          resolvers.NewBadgeHandler(),
 	}, nil
 }
 
-func uploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter, *http.Request) {
+func uploadProxyHandler() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 		repoName := q.Get("repository")
@@ -44,6 +53,12 @@ func uploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter, *htt
 		root := q.Get("root")
 		ctx := r.Context()
 
+		format, err := parseUploadFormat(ctx, q.Get("format"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		repo, ok := ensureRepoAndCommitExist(ctx, w, repoName, commit)
 		if !ok {
 			return
@@ -52,44 +67,58 @@ func uploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter, *htt
 		// 🚨 SECURITY: Ensure we return before proxying to the lsif-server upload
 		// endpoint. This endpoint is unprotected, so we need to make sure the user
 		// provides a valid token proving contributor access to the repository.
-		if conf.Get().LsifEnforceAuth && !enforceAuth(ctx, w, r, repoName) {
+		if !authorizeUpload(ctx, w, r, repo) {
 			return
 		}
 
-		uploadID, queued, err := client.DefaultClient.Upload(ctx, &struct {
-			RepoID   api.RepoID
-			Commit   graphqlbackend.GitObjectID
-			Root     string
-			Blocking *bool
-			MaxWait  *int32
-			Body     io.ReadCloser
-		}{
-			RepoID: repo.ID,
-			Commit: graphqlbackend.GitObjectID(commit),
-			Root:   root,
-			Body:   r.Body,
-		})
+		forwardUpload(ctx, w, repo.ID, commit, root, format, r.Body)
+	}
+}
 
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+// forwardUpload sends the given index of the given format, compressed with
+// gzip or zstd, to the lsif-server as an LSIF dump and writes the ID of the
+// resulting upload to w. It returns false if the lsif-server did not accept
+// the dump, in which case an error was written to w.
+func forwardUpload(ctx context.Context, w http.ResponseWriter, repoID api.RepoID, commit, root, format string, body io.ReadCloser) bool {
+	// The body is not closed by the client if the request fails before it is
+	// sent.
+	body = prepareUpload(body, format)
+	defer body.Close()
+
+	uploadID, queued, err := client.DefaultClient.Upload(ctx, &struct {
+		RepoID   api.RepoID
+		Commit   graphqlbackend.GitObjectID
+		Root     string
+		Blocking *bool
+		MaxWait  *int32
+		Body     io.ReadCloser
+	}{
+		RepoID: repoID,
+		Commit: graphqlbackend.GitObjectID(commit),
+		Root:   root,
+		Body:   body,
+	})
 
-		// Return id as a string to maintain backwards compatibility with src-cli
-		payload, err := json.Marshal(map[string]string{"id": strconv.FormatInt(uploadID, 10)})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
 
-		if queued {
-			w.WriteHeader(http.StatusAccepted)
-		} else {
-			w.WriteHeader(http.StatusOK)
-		}
+	// Return id as a string to maintain backwards compatibility with src-cli
+	payload, err := json.Marshal(map[string]string{"id": strconv.FormatInt(uploadID, 10)})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
 
-		_, _ = w.Write(payload)
+	if queued {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusOK)
 	}
+
+	_, _ = w.Write(payload)
+	return true
 }
 
 func ensureRepoAndCommitExist(ctx context.Context, w http.ResponseWriter, repoName, commit string) (*types.Repo, bool) {
@@ -117,6 +146,29 @@ func ensureRepoAndCommitExist(ctx context.Context, w http.ResponseWriter, repoNa
 	return repo, true
 }
 
+// authorizeUpload returns whether the request may upload LSIF data to the
+// given repository. A request that provides an upload token of the repository
+// in the upload_token parameter is always authorized. Otherwise, the request
+// must prove contributor access to the repository if LsifEnforceAuth is set.
+// If the request is not authorized, an error was written to w.
+func authorizeUpload(ctx context.Context, w http.ResponseWriter, r *http.Request, repo *types.Repo) bool {
+	if uploadToken := r.URL.Query().Get("upload_token"); uploadToken != "" {
+		if err := db.LSIFUploadTokens.Lookup(ctx, uploadToken, repo.ID); err != nil {
+			if err == db.ErrLSIFUploadTokenNotFound {
+				http.Error(w, "invalid upload_token for repository", http.StatusUnauthorized)
+				return false
+			}
+
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+
+		return true
+	}
+
+	return !conf.Get().LsifEnforceAuth || enforceAuth(ctx, w, r, string(repo.Name))
+}
+
 func enforceAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, repoName string) bool {
 	validatorByCodeHost := map[string]func(context.Context, http.ResponseWriter, *http.Request, string) (int, error){
 		"github.com": enforceAuthGithub,
@@ -0,0 +1,420 @@
+package proxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Resumable uploads follow the tus protocol (https://tus.io/protocols/resumable-upload.html)
+// in spirit, so that uploads of multi-gigabyte dumps survive flaky connections:
+//
+// A client starts an upload session with a POST request to /.api/lsif/upload/sessions,
+// carrying the same query parameters as a plain upload and the size of the dump in the
+// Upload-Length header. The response points to the session in its Location header.
+//
+// The client then sends the dump in chunks with PATCH requests to the session, each
+// carrying the offset of the chunk in the Upload-Offset header. Interrupted chunks are
+// kept up to the last byte that was received, and a HEAD request to the session returns
+// the offset at which the client resumes in the Upload-Offset header.
+//
+// The PATCH request that completes the dump forwards it to the lsif-server and responds
+// like a plain upload. If forwarding fails, a PATCH request without a body at the final
+// offset retries it.
+//
+// Sessions are held in memory by the frontend replica that created them, and their
+// chunks are stored on its disk. Deployments with several frontend replicas must
+// therefore route all requests to a session to the same replica, e.g. with sticky
+// sessions on the load balancer; other replicas respond with 404 Not Found. Sessions
+// are lost when the replica restarts, and are discarded when they haven't received a
+// chunk for uploadSessionTTL.
+//
+// Since sessions reserve disk space and may be created anonymously when
+// lsifEnforceAuth is disabled, a replica holds at most maxUploadSessions sessions,
+// whose dumps are at most maxSize bytes in total.
+
+const (
+	// uploadSessionsPath is the path below which upload sessions are served.
+	uploadSessionsPath = "/.api/lsif/upload/sessions/"
+
+	// uploadSessionTTL is the time after which an upload session that hasn't received a
+	// chunk is discarded along with the part of the dump it received.
+	uploadSessionTTL = time.Hour
+
+	// uploadSessionJanitorInterval is the interval at which expired upload sessions are
+	// discarded.
+	uploadSessionJanitorInterval = 5 * time.Minute
+
+	// maxUploadSessions is the maximum number of open upload sessions of a frontend
+	// replica.
+	maxUploadSessions = 100
+)
+
+var (
+	errUploadSessionNotFound = errors.New("unknown or expired upload session")
+	errUploadSessionBusy     = errors.New("upload session is receiving another chunk")
+	errTooManyUploadSessions = errors.New("too many open upload sessions, retry later")
+)
+
+// uploadSession is a resumable upload of an LSIF dump, whose chunks are stored in a
+// file until the dump is complete.
+type uploadSession struct {
+	id     string
+	repoID api.RepoID
+	commit string
+	root   string
+	format string
+	length int64
+	path   string
+
+	// The fields below are guarded by uploadSessions.mu. offset is only written by the
+	// request that acquired the session.
+	offset   int64
+	busy     bool
+	lastUsed time.Time
+}
+
+// uploadSessions holds the upload sessions of the frontend.
+type uploadSessions struct {
+	dir string
+
+	// maxSize is the maximum total length of the dumps of the open sessions.
+	maxSize int64
+
+	mu       sync.Mutex
+	sessions map[string]*uploadSession
+	size     int64 // total length of the dumps of the open sessions
+}
+
+// newUploadSessions returns the upload sessions stored in a directory below the given
+// one, or below the system's temporary directory if it is empty, whose dumps are at most
+// maxSize bytes in total. Chunks left behind by a previous run of the frontend are
+// removed, since their sessions are lost.
+func newUploadSessions(baseDir string, maxSize int64) (*uploadSessions, error) {
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+
+	dir := filepath.Join(baseDir, "lsif-upload-sessions")
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Wrap(err, "removing stale LSIF upload sessions")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "creating LSIF upload sessions directory")
+	}
+
+	return &uploadSessions{dir: dir, maxSize: maxSize, sessions: map[string]*uploadSession{}}, nil
+}
+
+// create starts a session for a dump of the given length. It returns
+// errTooManyUploadSessions if the session would exceed the limits of the sessions.
+func (s *uploadSessions) create(repoID api.RepoID, commit, root, format string, length int64) (*uploadSession, error) {
+	// 🚨 SECURITY: The session ID authorizes the upload of chunks, so it must not be
+	// guessable.
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.sessions) >= maxUploadSessions || s.size+length > s.maxSize {
+		return nil, errTooManyUploadSessions
+	}
+
+	path := filepath.Join(s.dir, id)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	session := &uploadSession{
+		id:       id,
+		repoID:   repoID,
+		commit:   commit,
+		root:     root,
+		format:   format,
+		length:   length,
+		path:     path,
+		lastUsed: time.Now(),
+	}
+	s.sessions[id] = session
+	s.size += length
+
+	return session, nil
+}
+
+// offset returns the offset and length of the session with the given ID.
+func (s *uploadSessions) offset(id string) (offset, length int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return 0, 0, errUploadSessionNotFound
+	}
+	return session.offset, session.length, nil
+}
+
+// acquire returns the session with the given ID for the exclusive use by the caller,
+// who must release it when done.
+func (s *uploadSessions) acquire(id string) (*uploadSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, errUploadSessionNotFound
+	}
+	if session.busy {
+		return nil, errUploadSessionBusy
+	}
+	session.busy = true
+	return session, nil
+}
+
+func (s *uploadSessions) release(session *uploadSession) {
+	s.mu.Lock()
+	session.busy = false
+	session.lastUsed = time.Now()
+	s.mu.Unlock()
+}
+
+// advance records that n more bytes of the dump of the acquired session were stored.
+func (s *uploadSessions) advance(session *uploadSession, n int64) {
+	s.mu.Lock()
+	session.offset += n
+	s.mu.Unlock()
+}
+
+// remove discards the session and the part of the dump it received.
+func (s *uploadSessions) remove(session *uploadSession) {
+	s.mu.Lock()
+	if _, ok := s.sessions[session.id]; ok {
+		delete(s.sessions, session.id)
+		s.size -= session.length
+	}
+	s.mu.Unlock()
+
+	if err := os.Remove(session.path); err != nil && !os.IsNotExist(err) {
+		log15.Warn("Removing LSIF upload session", "session", session.id, "err", err)
+	}
+}
+
+// expire discards the sessions that haven't been used since uploadSessionTTL before now.
+func (s *uploadSessions) expire(now time.Time) {
+	var expired []*uploadSession
+
+	s.mu.Lock()
+	for _, session := range s.sessions {
+		if !session.busy && now.Sub(session.lastUsed) > uploadSessionTTL {
+			expired = append(expired, session)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, session := range expired {
+		s.remove(session)
+	}
+}
+
+// runJanitor should run in a background goroutine and periodically discards expired
+// sessions.
+func (s *uploadSessions) runJanitor() {
+	for {
+		time.Sleep(uploadSessionJanitorInterval)
+		s.expire(time.Now())
+	}
+}
+
+// write stores the given chunk of the dump of the acquired session at its offset. Bytes
+// beyond the length of the dump are rejected. The part of the chunk stored before an
+// error occurred is kept, so that the client can resume after it.
+func (s *uploadSessions) write(session *uploadSession, chunk io.Reader) (err error) {
+	f, err := os.OpenFile(session.path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err := f.Seek(session.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, io.LimitReader(chunk, session.length-session.offset))
+	s.advance(session, n)
+	if err != nil {
+		return err
+	}
+
+	if n, _ := chunk.Read(make([]byte, 1)); n > 0 {
+		return errors.Errorf("chunk exceeds the upload length of %d bytes", session.length)
+	}
+	return nil
+}
+
+// serveCreate starts an upload session.
+func (s *uploadSessions) serveCreate(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	repoName := q.Get("repository")
+	commit := q.Get("commit")
+	root := q.Get("root")
+	ctx := r.Context()
+
+	format, err := parseUploadFormat(ctx, q.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		http.Error(w, "Upload-Length header must be a positive number of bytes", http.StatusBadRequest)
+		return
+	}
+	if length > s.maxSize {
+		http.Error(w, fmt.Sprintf("Upload-Length exceeds the maximum upload length of %d bytes", s.maxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	repo, ok := ensureRepoAndCommitExist(ctx, w, repoName, commit)
+	if !ok {
+		return
+	}
+
+	// 🚨 SECURITY: Ensure we return before creating the session. Requests to the
+	// session are only authorized by its ID, so the user must prove contributor access
+	// to the repository here.
+	if !authorizeUpload(ctx, w, r, repo) {
+		return
+	}
+
+	session, err := s.create(repo.ID, commit, root, format, length)
+	if err != nil {
+		writeUploadSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", uploadSessionsPath+session.id)
+	w.Header().Set("Upload-Offset", "0")
+	w.Header().Set("Upload-Length", strconv.FormatInt(length, 10))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// serveSession serves the requests to an upload session.
+func (s *uploadSessions) serveSession(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	switch r.Method {
+	case "HEAD":
+		s.serveOffset(w, id)
+	case "PATCH":
+		s.serveChunk(w, r, id)
+	case "DELETE":
+		s.serveAbort(w, id)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *uploadSessions) serveOffset(w http.ResponseWriter, id string) {
+	offset, length, err := s.offset(id)
+	if err != nil {
+		writeUploadSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *uploadSessions) serveChunk(w http.ResponseWriter, r *http.Request, id string) {
+	session, err := s.acquire(id)
+	if err != nil {
+		writeUploadSessionError(w, err)
+		return
+	}
+	defer s.release(session)
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		http.Error(w, "Upload-Offset header must be a number of bytes", http.StatusBadRequest)
+		return
+	}
+	if offset != session.offset {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(session.offset, 10))
+		http.Error(w, fmt.Sprintf("chunk offset %d does not match the upload offset %d", offset, session.offset), http.StatusConflict)
+		return
+	}
+
+	err = s.write(session, r.Body)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(session.offset, 10))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if session.offset < session.length {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	dump, err := os.Open(session.path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The session is kept if the lsif-server didn't accept the dump, so that the
+	// client can retry without sending it again.
+	if forwardUpload(r.Context(), w, session.repoID, session.commit, session.root, session.format, dump) {
+		s.remove(session)
+	}
+}
+
+func (s *uploadSessions) serveAbort(w http.ResponseWriter, id string) {
+	session, err := s.acquire(id)
+	if err != nil {
+		writeUploadSessionError(w, err)
+		return
+	}
+
+	s.remove(session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeUploadSessionError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case errUploadSessionNotFound:
+		status = http.StatusNotFound
+	case errUploadSessionBusy:
+		status = http.StatusConflict
+	case errTooManyUploadSessions:
+		status = http.StatusServiceUnavailable
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,328 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+const testCommit = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+
+func newTestUploadSessions(t *testing.T, maxSize int64) (*uploadSessions, http.Handler, func()) {
+	dir, err := ioutil.TempDir("", "lsif-upload-sessions-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := newUploadSessions(dir, maxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := mux.NewRouter()
+	r.Path("/.api/lsif/upload/sessions").Methods("POST").HandlerFunc(s.serveCreate)
+	r.Path(uploadSessionsPath+"{id}").Methods("HEAD", "PATCH", "DELETE").HandlerFunc(s.serveSession)
+
+	return s, r, func() { os.RemoveAll(dir) }
+}
+
+// mockUploadRepo mocks the repository and commit of the uploads of the tests.
+func mockUploadRepo() func() {
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		return &types.Repo{ID: 50, Name: name}, nil
+	}
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return api.CommitID(rev), nil
+	}
+	conf.Mock(&conf.Unified{})
+
+	return func() {
+		backend.Mocks = backend.MockServices{}
+		conf.Mock(nil)
+	}
+}
+
+func serve(h http.Handler, method, target string, header map[string]string, body io.Reader) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, body)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func serveCreate(h http.Handler, length int) *httptest.ResponseRecorder {
+	return serve(h, "POST", "/.api/lsif/upload/sessions?repository=github.com/test/repo&commit="+testCommit, map[string]string{
+		"Upload-Length": strconv.Itoa(length),
+	}, nil)
+}
+
+func createUploadSession(t *testing.T, h http.Handler, length int) string {
+	w := serveCreate(h, length)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("have status %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	if have := w.Header().Get("Upload-Offset"); have != "0" {
+		t.Fatalf("have Upload-Offset %q, want 0", have)
+	}
+
+	location := w.Header().Get("Location")
+	if !strings.HasPrefix(location, uploadSessionsPath) {
+		t.Fatalf("have Location %q, want a session below %s", location, uploadSessionsPath)
+	}
+	return location
+}
+
+func checkUploadResponse(t *testing.T, w *httptest.ResponseRecorder, wantStatus int, wantOffset string) {
+	t.Helper()
+	if w.Code != wantStatus {
+		t.Fatalf("have status %d, want %d: %s", w.Code, wantStatus, w.Body)
+	}
+	if have := w.Header().Get("Upload-Offset"); have != wantOffset {
+		t.Fatalf("have Upload-Offset %q, want %q", have, wantOffset)
+	}
+}
+
+// interruptedReader returns the given data and then fails, like the body of a
+// request whose connection broke.
+type interruptedReader struct{ r io.Reader }
+
+func (r *interruptedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset by peer")
+	}
+	return n, err
+}
+
+func TestUploadSession(t *testing.T) {
+	defer mockUploadRepo()()
+
+	// The lsif-server fails the first time the dump is forwarded.
+	var (
+		uploads   int
+		forwarded []string
+	)
+	lsifServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/upload" || r.URL.Query().Get("repositoryId") != "50" || r.URL.Query().Get("commit") != testCommit {
+			t.Errorf("unexpected upload request %s", r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		forwarded = append(forwarded, string(body))
+
+		uploads++
+		if uploads == 1 {
+			http.Error(w, "database unavailable", http.StatusInternalServerError)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id": 42}`)
+	}))
+	defer lsifServer.Close()
+
+	defaultClient := client.DefaultClient
+	client.DefaultClient = &client.Client{URL: lsifServer.URL, HTTPClient: lsifServer.Client()}
+	defer func() { client.DefaultClient = defaultClient }()
+
+	_, h, cleanup := newTestUploadSessions(t, 1<<20)
+	defer cleanup()
+
+	const dump = `{"id":"1","type":"vertex","label":"metaData"}`
+	session := createUploadSession(t, h, len(dump))
+
+	offset := func(n int) map[string]string { return map[string]string{"Upload-Offset": strconv.Itoa(n)} }
+
+	// The first chunk.
+	w := serve(h, "PATCH", session, offset(0), strings.NewReader(dump[:10]))
+	checkUploadResponse(t, w, http.StatusNoContent, "10")
+
+	// A chunk at the wrong offset is rejected, and the response tells the
+	// client where to resume.
+	w = serve(h, "PATCH", session, offset(5), strings.NewReader(dump[5:20]))
+	checkUploadResponse(t, w, http.StatusConflict, "10")
+
+	// An interrupted chunk keeps the bytes that were received.
+	w = serve(h, "PATCH", session, offset(10), &interruptedReader{strings.NewReader(dump[10:15])})
+	checkUploadResponse(t, w, http.StatusBadRequest, "15")
+
+	w = serve(h, "HEAD", session, nil, nil)
+	checkUploadResponse(t, w, http.StatusOK, "15")
+	if have, want := w.Header().Get("Upload-Length"), strconv.Itoa(len(dump)); have != want {
+		t.Fatalf("have Upload-Length %q, want %q", have, want)
+	}
+
+	// The last chunk completes the dump, but the lsif-server fails.
+	w = serve(h, "PATCH", session, offset(15), strings.NewReader(dump[15:]))
+	checkUploadResponse(t, w, http.StatusInternalServerError, strconv.Itoa(len(dump)))
+
+	// The session is kept, so that the client can retry without a body.
+	w = serve(h, "PATCH", session, offset(len(dump)), nil)
+	checkUploadResponse(t, w, http.StatusOK, strconv.Itoa(len(dump)))
+	if have, want := strings.TrimSpace(w.Body.String()), `{"id":"42"}`; have != want {
+		t.Fatalf("have body %q, want %q", have, want)
+	}
+
+	if len(forwarded) != 2 || forwarded[0] != dump || forwarded[1] != dump {
+		t.Fatalf("have forwarded dumps %q, want the dump twice", forwarded)
+	}
+
+	// The session is discarded after the upload.
+	w = serve(h, "HEAD", session, nil, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("have status %d after the upload, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestUploadSessionErrors(t *testing.T) {
+	defer mockUploadRepo()()
+
+	s, h, cleanup := newTestUploadSessions(t, 1<<20)
+	defer cleanup()
+
+	t.Run("no upload length", func(t *testing.T) {
+		w := serve(h, "POST", "/.api/lsif/upload/sessions?repository=github.com/test/repo&commit="+testCommit, nil, nil)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("have status %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		w := serve(h, "PATCH", uploadSessionsPath+"0123456789abcdef", map[string]string{"Upload-Offset": "0"}, strings.NewReader("abc"))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("have status %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("chunk beyond the upload length", func(t *testing.T) {
+		session := createUploadSession(t, h, 3)
+
+		w := serve(h, "PATCH", session, map[string]string{"Upload-Offset": "0"}, strings.NewReader("abcdef"))
+		checkUploadResponse(t, w, http.StatusBadRequest, "3")
+		if !strings.Contains(w.Body.String(), "exceeds the upload length") {
+			t.Fatalf("have body %q, want an upload length error", w.Body)
+		}
+	})
+
+	t.Run("chunk while another one is received", func(t *testing.T) {
+		session := createUploadSession(t, h, 3)
+		id := strings.TrimPrefix(session, uploadSessionsPath)
+
+		acquired, err := s.acquire(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := serve(h, "PATCH", session, map[string]string{"Upload-Offset": "0"}, strings.NewReader("abc"))
+		if w.Code != http.StatusConflict {
+			t.Fatalf("have status %d, want %d", w.Code, http.StatusConflict)
+		}
+
+		s.release(acquired)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		session := createUploadSession(t, h, 3)
+
+		w := serve(h, "DELETE", session, nil, nil)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("have status %d, want %d", w.Code, http.StatusNoContent)
+		}
+
+		w = serve(h, "HEAD", session, nil, nil)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("have status %d after aborting, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestUploadSessionsExpire(t *testing.T) {
+	s, _, cleanup := newTestUploadSessions(t, 1<<20)
+	defer cleanup()
+
+	idle, err := s.create(50, testCommit, "", uploadFormatLSIF, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := s.create(50, testCommit, "", uploadFormatLSIF, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A session that receives a chunk isn't expired, however long it takes.
+	busy, err := s.create(50, testCommit, "", uploadFormatLSIF, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.acquire(busy.id); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	idle.lastUsed = now.Add(-uploadSessionTTL - time.Minute)
+	busy.lastUsed = now.Add(-uploadSessionTTL - time.Minute)
+	active.lastUsed = now.Add(-time.Minute)
+
+	s.expire(now)
+
+	if _, _, err := s.offset(idle.id); err != errUploadSessionNotFound {
+		t.Errorf("have error %v for the idle session, want %v", err, errUploadSessionNotFound)
+	}
+	if _, err := os.Stat(idle.path); !os.IsNotExist(err) {
+		t.Errorf("the dump of the idle session was not removed: %v", err)
+	}
+
+	for _, session := range []*uploadSession{active, busy} {
+		if _, _, err := s.offset(session.id); err != nil {
+			t.Errorf("session %s was expired: %v", session.id, err)
+		}
+	}
+}
+
+func TestUploadSessionsLimits(t *testing.T) {
+	defer mockUploadRepo()()
+
+	_, h, cleanup := newTestUploadSessions(t, 100)
+	defer cleanup()
+
+	// A dump larger than all sessions together is rejected upfront.
+	if w := serveCreate(h, 101); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("have status %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	// The dumps of the open sessions may not exceed the maximum size.
+	first := createUploadSession(t, h, 60)
+	if w := serveCreate(h, 41); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("have status %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	second := createUploadSession(t, h, 40)
+
+	// Sessions that are discarded free their space.
+	for _, session := range []string{first, second} {
+		if w := serve(h, "DELETE", session, nil, nil); w.Code != http.StatusNoContent {
+			t.Fatalf("have status %d, want %d", w.Code, http.StatusNoContent)
+		}
+	}
+
+	// The number of open sessions is limited too.
+	for i := 0; i < maxUploadSessions; i++ {
+		createUploadSession(t, h, 1)
+	}
+	if w := serveCreate(h, 1); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("have status %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -4,4 +4,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 )
 
-var ServerURLFromEnv = env.Get("LSIF_SERVER_URL", "http://lsif-server:3186", "URL at which the lsif-server service can be reached")
+var ServerURLFromEnv = env.Get("LSIF_SERVER_URL", "http://lsif-server:3186", "URL at which the lsif-server service can be reached, or a space separated list of URLs or a k8s+http:// URL to balance requests across replicas")
+
+var MaxInFlightRequestsFromEnv = env.Get("LSIF_SERVER_MAX_IN_FLIGHT_REQUESTS", "50", "maximum number of concurrent requests sent to each lsif-server replica")
+
+var UploadSessionsDirFromEnv = env.Get("LSIF_UPLOAD_SESSIONS_DIR", "", "directory in which the frontend stores the chunks of resumable LSIF uploads until they are complete (defaults to the system's temporary directory)")
+
+var UploadSessionsMaxSizeFromEnv = env.Get("LSIF_UPLOAD_SESSIONS_MAX_SIZE", "21474836480", "maximum total size in bytes of the dumps of the open resumable LSIF upload sessions of a frontend replica, which is also the maximum size of a resumable upload")
@@ -0,0 +1,381 @@
+// Package lspgateway serves the precise code intelligence of LSIF uploads over
+// the Language Server Protocol, so that editors can use it without speaking
+// the GraphQL API.
+//
+// Clients connect with a WebSocket to /.api/lsif/lsp, authenticated like other
+// API requests, and send one JSON-RPC 2.0 message per WebSocket message.
+// Documents are identified by URIs of the form git://repo?commit#path, e.g.
+// git://github.com/gorilla/mux?master#mux.go, where the commit may be any
+// revision. The locations of responses have URIs of the same form with
+// absolute commit IDs.
+package lspgateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"golang.org/x/net/websocket"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxReferencePages is the number of pages of references that are fetched for
+// a textDocument/references request, which isn't paginated in LSP.
+const maxReferencePages = 10
+
+// LSIFResolver returns the LSIF query resolver of a file at a commit, or nil if
+// there is no LSIF data for the file.
+type LSIFResolver interface {
+	LSIF(ctx context.Context, args *graphqlbackend.LSIFQueryArgs) (graphqlbackend.LSIFQueryResolver, error)
+}
+
+// NewHandler returns a handler that accepts WebSocket connections and answers
+// the LSP requests sent on them with the LSIF data resolved by r.
+func NewHandler(r LSIFResolver) http.Handler {
+	return websocket.Server{
+		Handshake: checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			(&conn{resolver: r, ws: ws}).serve(ws.Request().Context())
+		},
+	}
+}
+
+// checkOrigin rejects connections opened by web pages of other origins than
+// Sourcegraph itself. Browsers send the cookies of the user along with
+// WebSocket handshakes, so these pages could otherwise read code intelligence
+// on behalf of the user. Editors send no Origin header.
+func checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+
+	externalURL := globals.ExternalURL()
+	if u.Scheme != externalURL.Scheme || u.Host != externalURL.Host {
+		return errors.Errorf("origin %q not allowed", origin)
+	}
+
+	config.Origin = u
+	return nil
+}
+
+// JSON-RPC 2.0 error codes used in responses.
+const (
+	codeParseError     = -32700
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+	codeInternalError  = -32603
+)
+
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  *json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string { return e.Message }
+
+// conn is a WebSocket connection of an LSP client. Requests are answered one
+// at a time, in the order they are received.
+type conn struct {
+	resolver LSIFResolver
+	ws       *websocket.Conn
+}
+
+func (c *conn) serve(ctx context.Context) {
+	for {
+		var req request
+		if err := websocket.JSON.Receive(c.ws, &req); err != nil {
+			if err == io.EOF {
+				return
+			}
+			if _, ok := err.(*json.SyntaxError); ok {
+				c.send(&response{JSONRPC: "2.0", Error: &responseError{Code: codeParseError, Message: err.Error()}})
+				continue
+			}
+			log15.Warn("lspgateway: receiving message", "error", err)
+			return
+		}
+
+		if req.ID == nil {
+			// Notifications don't have responses. The documents are read from
+			// gitserver, so didOpen, didChange etc. are ignored.
+			if req.Method == "exit" {
+				return
+			}
+			continue
+		}
+
+		resp := &response{JSONRPC: "2.0", ID: req.ID}
+		result, err := c.handle(ctx, &req)
+		if err != nil {
+			e, ok := err.(*responseError)
+			if !ok {
+				e = &responseError{Code: codeInternalError, Message: err.Error()}
+			}
+			resp.Error = e
+		} else {
+			resp.Result = result
+		}
+
+		if !c.send(resp) {
+			return
+		}
+	}
+}
+
+func (c *conn) send(resp *response) bool {
+	if resp.Result == nil && resp.Error == nil {
+		// A successful response must have a result, even if it's null.
+		resp.Result = json.RawMessage("null")
+	}
+
+	if err := websocket.JSON.Send(c.ws, resp); err != nil {
+		log15.Warn("lspgateway: sending message", "error", err)
+		return false
+	}
+	return true
+}
+
+func (c *conn) handle(ctx context.Context, req *request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return lsp.InitializeResult{
+			Capabilities: lsp.ServerCapabilities{
+				DefinitionProvider: true,
+				ReferencesProvider: true,
+				HoverProvider:      true,
+			},
+		}, nil
+
+	case "shutdown":
+		return nil, nil
+
+	case "textDocument/definition":
+		var params lsp.TextDocumentPositionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return c.definition(ctx, params)
+
+	case "textDocument/references":
+		var params lsp.ReferenceParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return c.references(ctx, params.TextDocumentPositionParams)
+
+	case "textDocument/hover":
+		var params lsp.TextDocumentPositionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		return c.hover(ctx, params)
+
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+	}
+}
+
+func unmarshalParams(req *request, v interface{}) error {
+	if req.Params == nil {
+		return &responseError{Code: codeInvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(*req.Params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (c *conn) definition(ctx context.Context, params lsp.TextDocumentPositionParams) ([]lsp.Location, error) {
+	query, err := c.query(ctx, params.TextDocument.URI)
+	if err != nil || query == nil {
+		return []lsp.Location{}, err
+	}
+
+	locations, err := query.Definitions(ctx, positionArgs(params.Position))
+	if err != nil {
+		return nil, err
+	}
+	return lspLocations(ctx, locations, nil)
+}
+
+func (c *conn) references(ctx context.Context, params lsp.TextDocumentPositionParams) ([]lsp.Location, error) {
+	query, err := c.query(ctx, params.TextDocument.URI)
+	if err != nil || query == nil {
+		return []lsp.Location{}, err
+	}
+
+	args := &graphqlbackend.LSIFPagedQueryPositionArgs{LSIFQueryPositionArgs: *positionArgs(params.Position)}
+
+	locations := []lsp.Location{}
+	for i := 0; i < maxReferencePages; i++ {
+		// Don't fetch the remaining pages of an abandoned request.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		connection, err := query.References(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+
+		if locations, err = lspLocations(ctx, connection, locations); err != nil {
+			return nil, err
+		}
+
+		pageInfo, err := connection.PageInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !pageInfo.HasNextPage() {
+			break
+		}
+		args.After = pageInfo.EndCursor()
+	}
+
+	return locations, nil
+}
+
+func (c *conn) hover(ctx context.Context, params lsp.TextDocumentPositionParams) (*lsp.Hover, error) {
+	query, err := c.query(ctx, params.TextDocument.URI)
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	hover, err := query.Hover(ctx, &graphqlbackend.LSIFQueryHoverArgs{LSIFQueryPositionArgs: *positionArgs(params.Position)})
+	if err != nil || hover == nil {
+		return nil, err
+	}
+
+	h := &lsp.Hover{Contents: []lsp.MarkedString{lsp.RawMarkedString(hover.Markdown().Text())}}
+	if r := hover.Range(); r != nil {
+		h.Range = &lsp.Range{
+			Start: lsp.Position{Line: int(r.Start().Line()), Character: int(r.Start().Character())},
+			End:   lsp.Position{Line: int(r.End().Line()), Character: int(r.End().Character())},
+		}
+	}
+	return h, nil
+}
+
+// query returns the LSIF query resolver of the document with the given URI, or
+// nil if there is no LSIF data for it.
+func (c *conn) query(ctx context.Context, uri lsp.DocumentURI) (graphqlbackend.LSIFQueryResolver, error) {
+	repoName, rev, path, err := parseURI(uri)
+	if err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	// 🚨 SECURITY: The repository is looked up with the permissions of the user
+	// who opened the connection.
+	repo, err := backend.Repos.GetByName(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	commitID, err := backend.Repos.ResolveRev(ctx, repo, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.resolver.LSIF(ctx, &graphqlbackend.LSIFQueryArgs{
+		Repository: graphqlbackend.NewRepositoryResolver(repo),
+		Commit:     graphqlbackend.GitObjectID(commitID),
+		Path:       path,
+	})
+}
+
+func positionArgs(pos lsp.Position) *graphqlbackend.LSIFQueryPositionArgs {
+	return &graphqlbackend.LSIFQueryPositionArgs{Line: int32(pos.Line), Character: int32(pos.Character)}
+}
+
+// lspLocations appends the locations of the given connection to locations.
+func lspLocations(ctx context.Context, connection graphqlbackend.LocationConnectionResolver, locations []lsp.Location) ([]lsp.Location, error) {
+	if locations == nil {
+		locations = []lsp.Location{}
+	}
+	if connection == nil {
+		return locations, nil
+	}
+
+	nodes, err := connection.Nodes(ctx, &graphqlbackend.LocationConnectionNodesArgs{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		file := node.Resource()
+		r := node.Range()
+		if r == nil {
+			continue
+		}
+
+		locations = append(locations, lsp.Location{
+			URI: formatURI(api.RepoName(file.Repository().Name()), string(file.Commit().OID()), file.Path()),
+			Range: lsp.Range{
+				Start: lsp.Position{Line: int(r.Start().Line()), Character: int(r.Start().Character())},
+				End:   lsp.Position{Line: int(r.End().Line()), Character: int(r.End().Character())},
+			},
+		})
+	}
+
+	return locations, nil
+}
+
+// parseURI returns the repository, revision and path of a document URI of the
+// form git://repo?rev#path. The revision defaults to HEAD.
+func parseURI(uri lsp.DocumentURI) (repo api.RepoName, rev, path string, err error) {
+	u, err := url.Parse(string(uri))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if u.Scheme != "git" || u.Host == "" || u.Fragment == "" {
+		return "", "", "", errors.Errorf("invalid document URI %q, expected git://repo?commit#path", uri)
+	}
+
+	rev = u.RawQuery
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	return api.RepoName(u.Host + strings.TrimSuffix(u.Path, "/")), rev, u.Fragment, nil
+}
+
+// formatURI returns the URI of the given file of a repository at a commit.
+func formatURI(repo api.RepoName, commit, path string) lsp.DocumentURI {
+	u := &url.URL{Scheme: "git", Host: string(repo), RawQuery: commit, Fragment: path}
+	if i := strings.Index(string(repo), "/"); i >= 0 {
+		u.Host, u.Path = string(repo)[:i], string(repo)[i:]
+	}
+	return lsp.DocumentURI(u.String())
+}
@@ -0,0 +1,449 @@
+package lspgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"golang.org/x/net/websocket"
+)
+
+const testCommit = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+
+type fakeLocation struct {
+	repo api.RepoName
+	path string
+	rng  *lsp.Range
+}
+
+// fakeResolver answers the queries of the files in queries, by path. The
+// other files have no LSIF data.
+type fakeResolver struct {
+	t       *testing.T
+	queries map[string]*fakeQuery
+}
+
+func (r *fakeResolver) LSIF(ctx context.Context, args *graphqlbackend.LSIFQueryArgs) (graphqlbackend.LSIFQueryResolver, error) {
+	if args.Repository.Name() != "github.com/gorilla/mux" || args.Commit != testCommit {
+		r.t.Errorf("unexpected LSIF query of %s@%s", args.Repository.Name(), args.Commit)
+	}
+	if q, ok := r.queries[args.Path]; ok {
+		return q, nil
+	}
+	return nil, nil
+}
+
+type fakeQuery struct {
+	definitions []fakeLocation
+	// references are the pages of references.
+	references [][]fakeLocation
+	hover      *fakeHover
+}
+
+var _ graphqlbackend.LSIFQueryResolver = &fakeQuery{}
+
+func (q *fakeQuery) Commit(ctx context.Context) (*graphqlbackend.GitCommitResolver, error) {
+	return nil, nil
+}
+
+func (q *fakeQuery) IsExactCommit() bool { return true }
+
+func (q *fakeQuery) SkippedUploads() []graphqlbackend.LSIFUploadResolver { return nil }
+
+func (q *fakeQuery) Definitions(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (graphqlbackend.LocationConnectionResolver, error) {
+	return &fakeConnection{locations: q.definitions}, nil
+}
+
+func (q *fakeQuery) References(ctx context.Context, args *graphqlbackend.LSIFPagedQueryPositionArgs) (graphqlbackend.LocationConnectionResolver, error) {
+	page := 0
+	if args.After != nil {
+		for page < len(q.references) && *args.After != pageCursor(page) {
+			page++
+		}
+	}
+	if page == len(q.references) {
+		return nil, errors.New("unknown page")
+	}
+
+	c := &fakeConnection{locations: q.references[page]}
+	if page+1 < len(q.references) {
+		c.next = pageCursor(page + 1)
+	}
+	return c, nil
+}
+
+func pageCursor(page int) string { return "page-" + strconv.Itoa(page) }
+
+func (q *fakeQuery) ReferenceCount(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (int32, error) {
+	return 0, nil
+}
+
+func (q *fakeQuery) Hover(ctx context.Context, args *graphqlbackend.LSIFQueryHoverArgs) (graphqlbackend.HoverResolver, error) {
+	if q.hover == nil {
+		return nil, nil
+	}
+	return q.hover, nil
+}
+
+func (q *fakeQuery) Hovers(ctx context.Context, args *graphqlbackend.LSIFQueryHoversArgs) ([]graphqlbackend.HoverResolver, error) {
+	return nil, nil
+}
+
+type fakeConnection struct {
+	locations []fakeLocation
+	next      string
+}
+
+var _ graphqlbackend.LocationConnectionResolver = &fakeConnection{}
+
+func (c *fakeConnection) Nodes(ctx context.Context, args *graphqlbackend.LocationConnectionNodesArgs) ([]graphqlbackend.LocationResolver, error) {
+	var nodes []graphqlbackend.LocationResolver
+	for _, l := range c.locations {
+		repo := graphqlbackend.NewRepositoryResolver(&types.Repo{ID: 1, Name: l.repo})
+		commit, err := repo.CommitFromID(ctx, &graphqlbackend.RepositoryCommitArgs{Rev: testCommit}, testCommit)
+		if err != nil {
+			return nil, err
+		}
+		file := graphqlbackend.NewGitTreeEntryResolver(commit, graphqlbackend.CreateFileInfo(l.path, false))
+		nodes = append(nodes, graphqlbackend.NewLocationResolver(file, l.rng))
+	}
+	return nodes, nil
+}
+
+func (c *fakeConnection) Groups(ctx context.Context, args *graphqlbackend.LocationConnectionNodesArgs) ([]graphqlbackend.LocationRepositoryGroupResolver, error) {
+	return nil, nil
+}
+
+func (c *fakeConnection) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	if c.next != "" {
+		return graphqlutil.NextPageCursor(c.next), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+func (c *fakeConnection) Partial() bool        { return false }
+func (c *fakeConnection) Truncated() bool      { return false }
+func (c *fakeConnection) CountEstimate() int32 { return int32(len(c.locations)) }
+
+type fakeHover struct {
+	text string
+	rng  *lsp.Range
+}
+
+var _ graphqlbackend.HoverResolver = &fakeHover{}
+
+func (h *fakeHover) Markdown() graphqlbackend.MarkdownResolver {
+	return graphqlbackend.NewMarkdownResolver(h.text)
+}
+
+func (h *fakeHover) Contents() []graphqlbackend.HoverContentResolver { return nil }
+
+func (h *fakeHover) Range() graphqlbackend.RangeResolver {
+	if h.rng == nil {
+		return nil
+	}
+	return graphqlbackend.NewRangeResolver(*h.rng)
+}
+
+func testRange(line, start, end int) *lsp.Range {
+	return &lsp.Range{
+		Start: lsp.Position{Line: line, Character: start},
+		End:   lsp.Position{Line: line, Character: end},
+	}
+}
+
+func TestGateway(t *testing.T) {
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		if name != "github.com/gorilla/mux" {
+			return nil, errors.New("repository not found")
+		}
+		return &types.Repo{ID: 1, Name: name}, nil
+	}
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		if rev != "master" && rev != "HEAD" {
+			return "", errors.New("revision not found")
+		}
+		return testCommit, nil
+	}
+	backend.Mocks.Repos.GetCommit = func(ctx context.Context, repo *types.Repo, commitID api.CommitID) (*git.Commit, error) {
+		return &git.Commit{ID: commitID}, nil
+	}
+	defer func() { backend.Mocks = backend.MockServices{} }()
+
+	resolver := &fakeResolver{t: t, queries: map[string]*fakeQuery{
+		"mux.go": {
+			definitions: []fakeLocation{
+				{repo: "github.com/gorilla/mux", path: "route.go", rng: testRange(10, 5, 10)},
+				// Locations without a range are skipped.
+				{repo: "github.com/gorilla/mux", path: "doc.go"},
+			},
+			references: [][]fakeLocation{
+				{{repo: "github.com/gorilla/mux", path: "mux.go", rng: testRange(1, 2, 7)}},
+				{{repo: "github.com/gorilla/context", path: "context.go", rng: testRange(3, 0, 5)}},
+			},
+			hover: &fakeHover{text: "```go\nfunc NewRouter() *Router\n```", rng: testRange(1, 2, 7)},
+		},
+	}}
+
+	server := httptest.NewServer(NewHandler(resolver))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	externalURL := globals.ExternalURL()
+	globals.SetExternalURL(serverURL)
+	defer globals.SetExternalURL(externalURL)
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	position := func(uri string, line, character int) lsp.TextDocumentPositionParams {
+		return lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: lsp.DocumentURI(uri)},
+			Position:     lsp.Position{Line: line, Character: character},
+		}
+	}
+
+	const (
+		muxURI = "git://github.com/gorilla/mux?master#mux.go"
+		// The locations of the responses have absolute commit IDs.
+		locationURIPrefix = "git://github.com/gorilla/mux?" + testCommit
+	)
+
+	for i, tc := range []struct {
+		name       string
+		method     string
+		params     interface{}
+		wantResult string
+		wantError  int
+	}{
+		{
+			name:       "initialize",
+			method:     "initialize",
+			params:     lsp.InitializeParams{},
+			wantResult: `{"capabilities": {"definitionProvider": true, "referencesProvider": true, "hoverProvider": true}}`,
+		},
+		{
+			name:       "definition",
+			method:     "textDocument/definition",
+			params:     position(muxURI, 1, 3),
+			wantResult: `[{"uri": "` + locationURIPrefix + `#route.go", "range": {"start": {"line": 10, "character": 5}, "end": {"line": 10, "character": 10}}}]`,
+		},
+		{
+			name:   "references of all pages",
+			method: "textDocument/references",
+			params: lsp.ReferenceParams{TextDocumentPositionParams: position(muxURI, 1, 3)},
+			wantResult: `[
+				{"uri": "` + locationURIPrefix + `#mux.go", "range": {"start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 7}}},
+				{"uri": "git://github.com/gorilla/context?` + testCommit + `#context.go", "range": {"start": {"line": 3, "character": 0}, "end": {"line": 3, "character": 5}}}
+			]`,
+		},
+		{
+			name:       "hover",
+			method:     "textDocument/hover",
+			params:     position(muxURI, 1, 3),
+			wantResult: `{"contents": ["` + "```go\\nfunc NewRouter() *Router\\n```" + `"], "range": {"start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 7}}}`,
+		},
+		{
+			name:       "definition in file without LSIF data",
+			method:     "textDocument/definition",
+			params:     position("git://github.com/gorilla/mux#README.md", 0, 0),
+			wantResult: `[]`,
+		},
+		{
+			name:       "hover in file without LSIF data",
+			method:     "textDocument/hover",
+			params:     position("git://github.com/gorilla/mux#README.md", 0, 0),
+			wantResult: `null`,
+		},
+		{
+			name:      "invalid document URI",
+			method:    "textDocument/definition",
+			params:    position("file:///mux.go", 0, 0),
+			wantError: codeInvalidParams,
+		},
+		{
+			name:      "unknown repository",
+			method:    "textDocument/definition",
+			params:    position("git://github.com/gorilla/unknown#mux.go", 0, 0),
+			wantError: codeInternalError,
+		},
+		{
+			name:      "missing params",
+			method:    "textDocument/hover",
+			wantError: codeInvalidParams,
+		},
+		{
+			name:      "unsupported method",
+			method:    "textDocument/rename",
+			params:    position(muxURI, 1, 3),
+			wantError: codeMethodNotFound,
+		},
+		{
+			name:       "shutdown",
+			method:     "shutdown",
+			wantResult: `null`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Notifications are ignored, so the request is answered next.
+			if err := websocket.JSON.Send(ws, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen"}); err != nil {
+				t.Fatal(err)
+			}
+
+			req := map[string]interface{}{"jsonrpc": "2.0", "id": i, "method": tc.method}
+			if tc.params != nil {
+				req["params"] = tc.params
+			}
+			if err := websocket.JSON.Send(ws, req); err != nil {
+				t.Fatal(err)
+			}
+
+			var resp struct {
+				ID     int
+				Result json.RawMessage
+				Error  *responseError
+			}
+			if err := websocket.JSON.Receive(ws, &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ID != i {
+				t.Fatalf("have response to request %d, want %d", resp.ID, i)
+			}
+
+			if tc.wantError != 0 {
+				if resp.Error == nil || resp.Error.Code != tc.wantError {
+					t.Fatalf("have error %+v, want code %d", resp.Error, tc.wantError)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error %+v", resp.Error)
+			}
+
+			var have, want interface{}
+			if err := json.Unmarshal(resp.Result, &have); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.wantResult), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("have result %s, want %s", resp.Result, tc.wantResult)
+			}
+		})
+	}
+
+	t.Run("invalid message", func(t *testing.T) {
+		if err := websocket.Message.Send(ws, "{"); err != nil {
+			t.Fatal(err)
+		}
+
+		var resp response
+		if err := websocket.JSON.Receive(ws, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Error == nil || resp.Error.Code != codeParseError {
+			t.Fatalf("have error %+v, want code %d", resp.Error, codeParseError)
+		}
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		if err := websocket.JSON.Send(ws, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// The server closes the connection.
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != io.EOF {
+			t.Fatalf("have error %v and message %q, want io.EOF", err, msg)
+		}
+	})
+}
+
+func TestCheckOrigin(t *testing.T) {
+	externalURL := globals.ExternalURL()
+	globals.SetExternalURL(&url.URL{Scheme: "https", Host: "sourcegraph.example.com"})
+	defer globals.SetExternalURL(externalURL)
+
+	for _, tc := range []struct {
+		origin  string
+		wantErr bool
+	}{
+		// Editors send no Origin header.
+		{origin: ""},
+		{origin: "https://sourcegraph.example.com"},
+		{origin: "https://evil.example.com", wantErr: true},
+		{origin: "http://sourcegraph.example.com", wantErr: true},
+		{origin: "null", wantErr: true},
+	} {
+		t.Run(tc.origin, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/.api/lsif/lsp", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+
+			err := checkOrigin(&websocket.Config{}, r)
+			if have := err != nil; have != tc.wantErr {
+				t.Errorf("have error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	for _, tc := range []struct {
+		uri      string
+		wantRepo api.RepoName
+		wantRev  string
+		wantPath string
+		wantErr  bool
+	}{
+		{uri: "git://github.com/gorilla/mux?master#mux.go", wantRepo: "github.com/gorilla/mux", wantRev: "master", wantPath: "mux.go"},
+		{uri: "git://github.com/gorilla/mux#sub/dir/mux.go", wantRepo: "github.com/gorilla/mux", wantRev: "HEAD", wantPath: "sub/dir/mux.go"},
+		{uri: "git://gitlab.example.com/a/b/c?" + testCommit + "#x.go", wantRepo: "gitlab.example.com/a/b/c", wantRev: testCommit, wantPath: "x.go"},
+		{uri: "git://github.com/gorilla/mux?master", wantErr: true},
+		{uri: "file:///home/me/mux.go", wantErr: true},
+		{uri: "git:///gorilla/mux#mux.go", wantErr: true},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			repo, rev, path, err := parseURI(lsp.DocumentURI(tc.uri))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("have no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo != tc.wantRepo || rev != tc.wantRev || path != tc.wantPath {
+				t.Errorf("have %q %q %q, want %q %q %q", repo, rev, path, tc.wantRepo, tc.wantRev, tc.wantPath)
+			}
+
+			// Formatting the parsed URI results in the same URI.
+			if tc.wantRev != "HEAD" {
+				if have := formatURI(repo, rev, path); string(have) != tc.uri {
+					t.Errorf("have formatted URI %q, want %q", have, tc.uri)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/routevar"
+	"golang.org/x/time/rate"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// badgeCacheTTL is the time for which the badge of a repository is cached,
+	// both by the frontend and by clients. Badges are embedded in READMEs, so
+	// they are requested far more often than uploads change.
+	badgeCacheTTL = 5 * time.Minute
+
+	// badgeFreshCommits is the number of commits an upload may lag behind the
+	// tip of the default branch before the badge shows it as stale.
+	badgeFreshCommits = 10
+
+	// badgeRequestsPerMinute and badgeRequestBurst limit the badge requests of
+	// a single client.
+	badgeRequestsPerMinute = 60
+	badgeRequestBurst      = 30
+
+	// badgeMaxClients is the number of clients whose rate limits are tracked.
+	badgeMaxClients = 10000
+)
+
+// badgesFlag gates the badges, which are served to anonymous clients and thus
+// may need to be turned off on sites that don't want them embedded elsewhere.
+var badgesFlag = featureflag.Register("codeintel-badges", "Serves the code intelligence badges of repositories for READMEs.", true)
+
+// badgeColors are the colors of badges by state.
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"yellow":      "#dfb317",
+	"lightgrey":   "#9f9f9f",
+}
+
+// NewBadgeHandler returns the handler of the code intelligence badges of
+// repositories, which describe the languages of a repository with precise
+// code intelligence and the freshness of their uploads. Badges are served as
+// SVG or as JSON for the shields.io endpoint badge
+// (https://shields.io/endpoint), depending on the format route variable.
+func NewBadgeHandler() http.Handler {
+	return &badgeHandler{
+		clients: lru.New(badgeMaxClients),
+		badges:  lru.New(badgeMaxClients),
+	}
+}
+
+type badgeHandler struct {
+	mu      sync.Mutex
+	clients *lru.Cache // client address -> *rate.Limiter
+	badges  *lru.Cache // repo ID -> *cachedBadge
+}
+
+// badge is a badge in the shields.io endpoint schema.
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+
+	// details describes the support of each language in the tooltip of SVG
+	// badges. It's not part of the schema, which rejects unknown fields.
+	details []string
+}
+
+type cachedBadge struct {
+	badge     *badge
+	expiresAt time.Time
+}
+
+func (h *badgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(badgeClientAddr(r)) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "too many badge requests", http.StatusTooManyRequests)
+		return
+	}
+
+	ctx := r.Context()
+
+	if !badgesFlag.Enabled(ctx) {
+		http.Error(w, "code intelligence badges are disabled", http.StatusNotFound)
+		return
+	}
+
+	// 🚨 SECURITY: Restricted access tokens need the "codeintel:read" scope.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCodeIntelRead); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	// 🚨 SECURITY: The repository is looked up as the requesting user, so that
+	// badges are only served for repositories they can access. Cached badges
+	// are only served after this check.
+	repoName := routevar.ToRepo(mux.Vars(r))
+	repo, err := backend.Repos.GetByName(ctx, repoName)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("unknown repository %q", repoName), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	b, err := h.badge(ctx, repo.ID, func(ctx context.Context) (*badge, error) {
+		support, err := codeIntelSupport(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		return newBadge(support), nil
+	})
+	if err != nil {
+		log15.Error("Computing code intelligence badge", "repo", repoName, "err", err)
+		http.Error(w, "unable to compute badge", http.StatusInternalServerError)
+		return
+	}
+
+	// 🚨 SECURITY: Whether a badge is served depends on the permissions of the
+	// user, so only the badges served to anonymous users may be cached by
+	// shared caches.
+	visibility := "private"
+	if !actor.FromContext(ctx).IsAuthenticated() {
+		visibility = "public"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(badgeCacheTTL/time.Second)))
+
+	if mux.Vars(r)["format"] == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(b)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, _ = w.Write([]byte(renderBadgeSVG(b)))
+}
+
+// allow reports whether a request of the client with the given address is
+// within its rate limit.
+func (h *badgeHandler) allow(client string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	limiter, ok := h.clients.Get(client)
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(badgeRequestsPerMinute)/60, badgeRequestBurst)
+		h.clients.Add(client, limiter)
+	}
+	return limiter.(*rate.Limiter).Allow()
+}
+
+// badge returns the cached badge of the given repository, or computes and
+// caches it if there is none.
+func (h *badgeHandler) badge(ctx context.Context, repoID api.RepoID, compute func(context.Context) (*badge, error)) (*badge, error) {
+	h.mu.Lock()
+	cached, ok := h.badges.Get(repoID)
+	h.mu.Unlock()
+	if ok && time.Now().Before(cached.(*cachedBadge).expiresAt) {
+		return cached.(*cachedBadge).badge, nil
+	}
+
+	b, err := compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	h.badges.Add(repoID, &cachedBadge{badge: b, expiresAt: time.Now().Add(badgeCacheTTL)})
+	h.mu.Unlock()
+
+	return b, nil
+}
+
+// badgeClientAddr returns the address of the client of the request, by which
+// its requests are rate limited. Clients can put any address in the
+// X-Forwarded-For header, so it's only used if the request comes from a proxy
+// on a loopback or private network, e.g. the one in front of the frontend. Its
+// last address is the one that proxy added, i.e. the client of the proxy.
+func badgeClientAddr(r *http.Request) string {
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	xff := r.Header.Get("X-Forwarded-For")
+	if xff == "" || !isTrustedProxy(net.ParseIP(addr)) {
+		return addr
+	}
+	hops := strings.Split(xff, ",")
+	if last := strings.TrimSpace(hops[len(hops)-1]); net.ParseIP(last) != nil {
+		return last
+	}
+	return addr
+}
+
+// privateNetworks are the IPv4 and IPv6 private address ranges (RFC 1918 and
+// RFC 4193).
+var privateNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// isTrustedProxy reports whether the given address is a loopback or private
+// address, from which the X-Forwarded-For header is trusted.
+func isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBadge returns the badge describing the given code intelligence support of
+// the languages of a repository.
+func newBadge(support []*codeIntelLanguageSupportResolver) *badge {
+	b := &badge{SchemaVersion: 1, Label: "code intel", Color: "brightgreen"}
+
+	var languages []string
+	for _, s := range support {
+		if s.upload == nil {
+			continue
+		}
+		languages = append(languages, s.language)
+
+		detail := s.language
+		if s.upload.Indexer != nil {
+			detail += " (" + *s.upload.Indexer + ")"
+		}
+		if s.commitsBehind != nil {
+			switch behind := *s.commitsBehind; {
+			case behind == 0:
+				detail += ": up to date"
+			case behind == 1:
+				detail += ": 1 commit behind"
+			default:
+				detail += fmt.Sprintf(": %d commits behind", behind)
+			}
+
+			if *s.commitsBehind > badgeFreshCommits {
+				b.Color = "yellow"
+			}
+		}
+		b.details = append(b.details, detail)
+	}
+
+	if len(languages) == 0 {
+		b.Message = "search-based"
+		b.Color = "lightgrey"
+		return b
+	}
+
+	b.Message = "precise " + strings.Join(languages, ", ")
+	return b
+}
+
+// renderBadgeSVG renders the badge in the flat style of shields.io. Text
+// widths are estimated, since the fonts of the viewer are unknown.
+func renderBadgeSVG(b *badge) string {
+	textWidth := func(s string) int { return 7*len(s) + 10 }
+
+	var (
+		labelWidth   = textWidth(b.Label)
+		messageWidth = textWidth(b.Message)
+		width        = labelWidth + messageWidth
+		label        = html.EscapeString(b.Label)
+		message      = html.EscapeString(b.Message)
+		title        = label + ": " + message
+	)
+	if len(b.details) > 0 {
+		title += "\n" + html.EscapeString(strings.Join(b.details, "\n"))
+	}
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[6]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[7]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[8]d" y="14">%[4]s</text><text x="%[9]d" y="14">%[5]s</text></g></svg>`,
+		width, labelWidth, messageWidth, label, message, title, badgeColors[b.Color],
+		labelWidth/2, labelWidth+messageWidth/2,
+	)
+}
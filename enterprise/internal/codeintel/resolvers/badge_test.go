@@ -0,0 +1,171 @@
+package resolvers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func init() {
+	dbtesting.DBNameSuffix = "codeintelresolvers"
+}
+
+func TestBadgeClientAddr(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		want       string
+	}{
+		{"no proxy", "203.0.113.7:1234", "", "203.0.113.7"},
+		{"forged header without proxy", "203.0.113.7:1234", "198.51.100.1", "203.0.113.7"},
+		{"loopback proxy", "127.0.0.1:1234", "198.51.100.1", "198.51.100.1"},
+		{"private proxy", "10.0.0.5:1234", "198.51.100.1", "198.51.100.1"},
+		{"IPv6 private proxy", "[fd00::1]:1234", "198.51.100.1", "198.51.100.1"},
+		{"forged first hop", "10.0.0.5:1234", "192.0.2.1, 198.51.100.1", "198.51.100.1"},
+		{"invalid last hop", "10.0.0.5:1234", "198.51.100.1, unknown", "10.0.0.5"},
+		{"no port", "203.0.113.7", "", "203.0.113.7"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			if tc.xff != "" {
+				r.Header.Set("X-Forwarded-For", tc.xff)
+			}
+			if have := badgeClientAddr(r); have != tc.want {
+				t.Errorf("have %q, want %q", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestBadgeHandlerAllow(t *testing.T) {
+	h := NewBadgeHandler().(*badgeHandler)
+
+	for i := 0; i < badgeRequestBurst; i++ {
+		if !h.allow("198.51.100.1") {
+			t.Fatalf("request %d within the burst was denied", i)
+		}
+	}
+	if h.allow("198.51.100.1") {
+		t.Error("request after the burst was allowed")
+	}
+	if !h.allow("198.51.100.2") {
+		t.Error("request of another client was denied")
+	}
+}
+
+type badgeRepoNotFoundErr struct{}
+
+func (badgeRepoNotFoundErr) Error() string  { return "repo not found" }
+func (badgeRepoNotFoundErr) NotFound() bool { return true }
+
+func TestBadgeHandler(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	// The badges feature flag is evaluated against the database.
+	dbtesting.SetupGlobalTestDB(t)
+
+	// The private repository can only be read by user 1.
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		switch name {
+		case "github.com/public/repo":
+			return &types.Repo{ID: 1, Name: name}, nil
+		case "github.com/private/repo":
+			if actor.FromContext(ctx).UID == 1 {
+				return &types.Repo{ID: 2, Name: name}, nil
+			}
+		}
+		return nil, badgeRepoNotFoundErr{}
+	}
+	defer func() { backend.Mocks = backend.MockServices{} }()
+
+	h := NewBadgeHandler().(*badgeHandler)
+
+	// Both badges are cached, so that they are served without querying the
+	// LSIF server.
+	expiresAt := time.Now().Add(time.Hour)
+	h.badges.Add(api.RepoID(1), &cachedBadge{badge: &badge{SchemaVersion: 1, Label: "code intel", Message: "precise Go", Color: "brightgreen"}, expiresAt: expiresAt})
+	h.badges.Add(api.RepoID(2), &cachedBadge{badge: &badge{SchemaVersion: 1, Label: "code intel", Message: "precise secret", Color: "brightgreen"}, expiresAt: expiresAt})
+
+	for _, tc := range []struct {
+		name         string
+		repo         string
+		actor        *actor.Actor
+		wantStatus   int
+		wantCaching  string
+		wantBodyPart string
+	}{
+		{
+			name:         "anonymous user and public repository",
+			repo:         "github.com/public/repo",
+			actor:        &actor.Actor{},
+			wantStatus:   http.StatusOK,
+			wantCaching:  "public, max-age=300",
+			wantBodyPart: "precise Go",
+		},
+		{
+			name:       "anonymous user and private repository",
+			repo:       "github.com/private/repo",
+			actor:      &actor.Actor{},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "user without access to private repository",
+			repo:       "github.com/private/repo",
+			actor:      &actor.Actor{UID: 2},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:         "user with access to private repository",
+			repo:         "github.com/private/repo",
+			actor:        &actor.Actor{UID: 1},
+			wantStatus:   http.StatusOK,
+			wantCaching:  "private, max-age=300",
+			wantBodyPart: "precise secret",
+		},
+		{
+			name:       "access token without codeintel:read scope",
+			repo:       "github.com/private/repo",
+			actor:      &actor.Actor{UID: 1, Scopes: []string{authz.ScopeCampaignsWrite}},
+			wantStatus: http.StatusForbidden,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/"+tc.repo+"/-/badge.json", nil)
+			r = r.WithContext(actor.WithActor(r.Context(), tc.actor))
+			r = mux.SetURLVars(r, map[string]string{"Repo": tc.repo, "format": "json"})
+			// The few requests of the test are well within the burst of the client.
+			r.RemoteAddr = "198.51.100.1:1234"
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("have status %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if have := w.Header().Get("Cache-Control"); have != tc.wantCaching {
+				t.Errorf("have Cache-Control %q, want %q", have, tc.wantCaching)
+			}
+			if tc.wantBodyPart != "" && !strings.Contains(w.Body.String(), tc.wantBodyPart) {
+				t.Errorf("have body %q, want it to contain %q", w.Body, tc.wantBodyPart)
+			}
+			if tc.wantStatus != http.StatusOK && strings.Contains(w.Body.String(), "precise") {
+				t.Errorf("have body %q, want no badge", w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package resolvers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// LSIFCoverage resolves which files and directories of the given repository
+// at the given revision are covered by the uploads closest to it.
+func (r *Resolver) LSIFCoverage(ctx context.Context, repoResolver *graphqlbackend.RepositoryResolver, args *graphqlbackend.LSIFCoverageArgs) (graphqlbackend.LSIFCoverageResolver, error) {
+	// 🚨 SECURITY: Restricted access tokens need the "codeintel:read" scope.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCodeIntelRead); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	repo := repoResolver.Type()
+
+	var rev string
+	if args.Rev != nil {
+		rev = *args.Rev
+	}
+	commitID, err := backend.Repos.ResolveRev(ctx, repo, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := git.ReadDir(ctx, *cachedRepo, commitID, "", true)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			files = append(files, e.Name())
+		}
+	}
+
+	coverage, err := client.DefaultClient.Coverage(ctx, &struct {
+		RepoID api.RepoID
+		Commit string
+	}{
+		RepoID: repo.ID,
+		Commit: string(commitID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newLSIFCoverageResolver(commitID, files, coverage), nil
+}
+
+type lsifCoverageResolver struct {
+	commit  api.CommitID
+	files   []string
+	uploads []*lsifUploadCoverageResolver
+
+	// coveredBy maps the paths of the covered files to the indexes of the
+	// uploads that cover them, in ascending order.
+	coveredBy map[string][]int
+}
+
+var _ graphqlbackend.LSIFCoverageResolver = &lsifCoverageResolver{}
+
+// newLSIFCoverageResolver returns the coverage of the given files of a commit
+// by the given uploads. Paths of the uploads that aren't files of the commit,
+// e.g. because the upload is of another commit, are ignored.
+func newLSIFCoverageResolver(commit api.CommitID, files []string, coverage []*lsif.LSIFUploadCoverage) *lsifCoverageResolver {
+	r := &lsifCoverageResolver{
+		commit:    commit,
+		files:     files,
+		uploads:   make([]*lsifUploadCoverageResolver, 0, len(coverage)),
+		coveredBy: map[string][]int{},
+	}
+
+	isFile := make(map[string]bool, len(files))
+	for _, f := range files {
+		isFile[f] = true
+	}
+
+	for i, c := range coverage {
+		u := &lsifUploadCoverageResolver{upload: c.Upload}
+		for _, p := range c.Paths {
+			if isFile[p] {
+				r.coveredBy[p] = append(r.coveredBy[p], i)
+				u.fileCount++
+			}
+		}
+		r.uploads = append(r.uploads, u)
+	}
+
+	return r
+}
+
+func (r *lsifCoverageResolver) Commit() graphqlbackend.GitObjectID {
+	return graphqlbackend.GitObjectID(r.commit)
+}
+
+func (r *lsifCoverageResolver) Uploads() []graphqlbackend.LSIFUploadCoverageResolver {
+	resolvers := make([]graphqlbackend.LSIFUploadCoverageResolver, 0, len(r.uploads))
+	for _, u := range r.uploads {
+		resolvers = append(resolvers, u)
+	}
+	return resolvers
+}
+
+// Entries returns the coverage of the files and directories in the directory
+// with the given path, directories first, then ordered by path.
+func (r *lsifCoverageResolver) Entries(args *struct{ Path string }) []graphqlbackend.LSIFCoverageEntryResolver {
+	prefix := strings.Trim(args.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	byPath := map[string]*lsifCoverageEntryResolver{}
+	for _, f := range r.files {
+		if !strings.HasPrefix(f, prefix) {
+			continue
+		}
+
+		path, isDirectory := f, false
+		if i := strings.Index(f[len(prefix):], "/"); i >= 0 {
+			path, isDirectory = f[:len(prefix)+i], true
+		}
+
+		e, ok := byPath[path]
+		if !ok {
+			e = &lsifCoverageEntryResolver{path: path, isDirectory: isDirectory, uploads: map[int]bool{}}
+			byPath[path] = e
+		}
+		e.fileCount++
+		if covering := r.coveredBy[f]; len(covering) > 0 {
+			e.coveredFileCount++
+			for _, i := range covering {
+				e.uploads[i] = true
+			}
+		}
+	}
+
+	entries := make([]*lsifCoverageEntryResolver, 0, len(byPath))
+	for _, e := range byPath {
+		e.coverage = r
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].isDirectory != entries[j].isDirectory {
+			return entries[i].isDirectory
+		}
+		return entries[i].path < entries[j].path
+	})
+
+	resolvers := make([]graphqlbackend.LSIFCoverageEntryResolver, 0, len(entries))
+	for _, e := range entries {
+		resolvers = append(resolvers, e)
+	}
+	return resolvers
+}
+
+type lsifUploadCoverageResolver struct {
+	upload    *lsif.LSIFUpload
+	fileCount int32
+}
+
+var _ graphqlbackend.LSIFUploadCoverageResolver = &lsifUploadCoverageResolver{}
+
+func (r *lsifUploadCoverageResolver) Upload() graphqlbackend.LSIFUploadResolver {
+	return &lsifUploadResolver{lsifUpload: r.upload}
+}
+
+func (r *lsifUploadCoverageResolver) Root() string     { return r.upload.Root }
+func (r *lsifUploadCoverageResolver) Indexer() *string { return r.upload.Indexer }
+func (r *lsifUploadCoverageResolver) FileCount() int32 { return r.fileCount }
+
+type lsifCoverageEntryResolver struct {
+	coverage         *lsifCoverageResolver
+	path             string
+	isDirectory      bool
+	fileCount        int32
+	coveredFileCount int32
+	// uploads is the set of indexes of the uploads that cover files of the entry.
+	uploads map[int]bool
+}
+
+var _ graphqlbackend.LSIFCoverageEntryResolver = &lsifCoverageEntryResolver{}
+
+func (r *lsifCoverageEntryResolver) Path() string            { return r.path }
+func (r *lsifCoverageEntryResolver) IsDirectory() bool       { return r.isDirectory }
+func (r *lsifCoverageEntryResolver) FileCount() int32        { return r.fileCount }
+func (r *lsifCoverageEntryResolver) CoveredFileCount() int32 { return r.coveredFileCount }
+
+// Uploads returns the uploads that cover files of the entry, in the order of
+// the uploads of the coverage.
+func (r *lsifCoverageEntryResolver) Uploads() []graphqlbackend.LSIFUploadResolver {
+	resolvers := make([]graphqlbackend.LSIFUploadResolver, 0, len(r.uploads))
+	for i, u := range r.coverage.uploads {
+		if r.uploads[i] {
+			resolvers = append(resolvers, u.Upload())
+		}
+	}
+	return resolvers
+}
@@ -0,0 +1,70 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+func TestLSIFCoverage(t *testing.T) {
+	files := []string{"README", "cmd/main.go", "cmd/util/util.go", "web/a.ts", "web/b.ts"}
+	coverage := newLSIFCoverageResolver(testCommit, files, []*lsif.LSIFUploadCoverage{
+		// Paths that aren't files of the commit are ignored.
+		{Upload: &lsif.LSIFUpload{ID: 1, Root: "cmd/"}, Paths: []string{"cmd/main.go", "cmd/util/util.go", "cmd/old.go"}},
+		{Upload: &lsif.LSIFUpload{ID: 2, Root: "web/"}, Paths: []string{"web/a.ts"}},
+		{Upload: &lsif.LSIFUpload{ID: 3, Root: ""}, Paths: []string{"cmd/main.go"}},
+	})
+
+	if have := coverage.Commit(); have != testCommit {
+		t.Errorf("have commit %q, want %q", have, testCommit)
+	}
+
+	var fileCounts []int32
+	for _, u := range coverage.Uploads() {
+		fileCounts = append(fileCounts, u.FileCount())
+	}
+	if want := []int32{2, 1, 1}; !reflect.DeepEqual(fileCounts, want) {
+		t.Errorf("have upload file counts %v, want %v", fileCounts, want)
+	}
+
+	type entry struct {
+		path             string
+		isDirectory      bool
+		fileCount        int32
+		coveredFileCount int32
+		uploads          []graphql.ID
+	}
+	entries := func(path string) []entry {
+		var entries []entry
+		for _, e := range coverage.Entries(&struct{ Path string }{Path: path}) {
+			var uploads []graphql.ID
+			for _, u := range e.Uploads() {
+				uploads = append(uploads, u.ID())
+			}
+			entries = append(entries, entry{e.Path(), e.IsDirectory(), e.FileCount(), e.CoveredFileCount(), uploads})
+		}
+		return entries
+	}
+
+	// Directories come first, and list the uploads covering any of their files.
+	if have, want := entries(""), []entry{
+		{"cmd", true, 2, 2, []graphql.ID{marshalLSIFUploadGQLID(1), marshalLSIFUploadGQLID(3)}},
+		{"web", true, 2, 1, []graphql.ID{marshalLSIFUploadGQLID(2)}},
+		{"README", false, 1, 0, nil},
+	}; !reflect.DeepEqual(have, want) {
+		t.Errorf("have root entries %+v, want %+v", have, want)
+	}
+
+	if have, want := entries("/cmd/"), []entry{
+		{"cmd/util", true, 1, 1, []graphql.ID{marshalLSIFUploadGQLID(1)}},
+		{"cmd/main.go", false, 1, 1, []graphql.ID{marshalLSIFUploadGQLID(1), marshalLSIFUploadGQLID(3)}},
+	}; !reflect.DeepEqual(have, want) {
+		t.Errorf("have cmd entries %+v, want %+v", have, want)
+	}
+
+	if have := entries("missing"); len(have) != 0 {
+		t.Errorf("have entries %+v of a missing directory, want none", have)
+	}
+}
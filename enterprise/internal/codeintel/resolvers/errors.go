@@ -0,0 +1,29 @@
+package resolvers
+
+import "fmt"
+
+// The codes of the errors returned by the code intelligence resolvers that
+// are exposed to API clients in the "code" field of the GraphQL error
+// extensions.
+const (
+	ErrCodeLSIFUploadNotFound = "LSIF_UPLOAD_NOT_FOUND"
+)
+
+// ErrLSIFUploadNotFound is returned if the LSIF server doesn't know the
+// upload with the given ID. It implements the Extensions method that
+// graphql-go uses to populate the extensions of a GraphQL error.
+type ErrLSIFUploadNotFound struct {
+	ID int64
+}
+
+func (e *ErrLSIFUploadNotFound) Error() string {
+	return fmt.Sprintf("LSIF upload not found: %d", e.ID)
+}
+
+// NotFound implements the interface checked by errcode.IsNotFound.
+func (e *ErrLSIFUploadNotFound) NotFound() bool { return true }
+
+// Extensions returns the extensions of the GraphQL error for e.
+func (e *ErrLSIFUploadNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": ErrCodeLSIFUploadNotFound}
+}
@@ -1,13 +1,24 @@
 package resolvers
 
 import (
+	"html"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
 	"github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/markdown"
+)
+
+const (
+	hoverContentFormatMarkdown  = "MARKDOWN"
+	hoverContentFormatPlaintext = "PLAINTEXT"
 )
 
 type hoverResolver struct {
 	text     string
 	lspRange lsp.Range
+	format   string
 }
 
 var _ graphqlbackend.HoverResolver = &hoverResolver{}
@@ -16,6 +27,98 @@ func (r *hoverResolver) Markdown() graphqlbackend.MarkdownResolver {
 	return graphqlbackend.NewMarkdownResolver(r.text)
 }
 
+func (r *hoverResolver) Contents() []graphqlbackend.HoverContentResolver {
+	var resolvers []graphqlbackend.HoverContentResolver
+	for _, content := range splitHoverText(r.text) {
+		if content.language == nil && r.format == hoverContentFormatPlaintext {
+			content.format = hoverContentFormatPlaintext
+			content.value = markdownToPlaintext(content.value)
+		}
+
+		resolvers = append(resolvers, content)
+	}
+
+	return resolvers
+}
+
 func (r *hoverResolver) Range() graphqlbackend.RangeResolver {
 	return graphqlbackend.NewRangeResolver(r.lspRange)
 }
+
+type hoverContentResolver struct {
+	format   string
+	value    string
+	language *string
+}
+
+var _ graphqlbackend.HoverContentResolver = &hoverContentResolver{}
+
+func (r *hoverContentResolver) Format() string {
+	return r.format
+}
+
+func (r *hoverContentResolver) Value() string {
+	return r.value
+}
+
+func (r *hoverContentResolver) Language() *string {
+	return r.language
+}
+
+// splitHoverText splits the markdown text of a hover into prose and fenced code
+// sections. The LSIF worker joins the contents of a hover with horizontal rules
+// and wraps language-tagged strings in fenced code blocks, so this recovers the
+// structure of the original hover result.
+func splitHoverText(text string) []*hoverContentResolver {
+	var contents []*hoverContentResolver
+	var lines []string
+	var language *string
+
+	flush := func() {
+		value := strings.Join(lines, "\n")
+		lines = nil
+
+		if language == nil {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return
+			}
+
+			contents = append(contents, &hoverContentResolver{format: hoverContentFormatMarkdown, value: value})
+			return
+		}
+
+		contents = append(contents, &hoverContentResolver{format: hoverContentFormatPlaintext, value: value, language: language})
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if language == nil {
+			if strings.HasPrefix(line, "```") {
+				flush()
+				lang := strings.TrimSpace(strings.TrimPrefix(line, "```"))
+				language = &lang
+				continue
+			}
+
+			if strings.TrimSpace(line) == "---" {
+				flush()
+				continue
+			}
+		} else if strings.TrimSpace(line) == "```" {
+			flush()
+			language = nil
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	// An unterminated code block extends to the end of the hover
+	flush()
+	return contents
+}
+
+// markdownToPlaintext renders the given markdown and strips all markup from the result.
+func markdownToPlaintext(text string) string {
+	return strings.TrimSpace(html.UnescapeString(bluemonday.StrictPolicy().Sanitize(markdown.Render(text))))
+}
@@ -0,0 +1,111 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+type testHoverContent struct {
+	format   string
+	value    string
+	language string
+}
+
+func testHoverContents(contents []graphqlbackend.HoverContentResolver) []testHoverContent {
+	var tcs []testHoverContent
+	for _, content := range contents {
+		tc := testHoverContent{format: content.Format(), value: content.Value()}
+		if content.Language() != nil {
+			tc.language = *content.Language()
+		}
+		tcs = append(tcs, tc)
+	}
+	return tcs
+}
+
+func TestSplitHoverText(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		text string
+		want []testHoverContent
+	}{
+		{
+			name: "code and prose",
+			text: "```go\nfunc Foo()\n```\n\n---\n\nFoo does *things*.",
+			want: []testHoverContent{
+				{format: hoverContentFormatPlaintext, value: "func Foo()", language: "go"},
+				{format: hoverContentFormatMarkdown, value: "Foo does *things*."},
+			},
+		},
+		{
+			name: "prose",
+			text: "Foo does *things*.\n\nAnd more.",
+			want: []testHoverContent{
+				{format: hoverContentFormatMarkdown, value: "Foo does *things*.\n\nAnd more."},
+			},
+		},
+		{
+			name: "multiple code blocks",
+			text: "```ts\nconst a = 1\n\nconst b = 2\n```\n---\n```\nplain\n```",
+			want: []testHoverContent{
+				{format: hoverContentFormatPlaintext, value: "const a = 1\n\nconst b = 2", language: "ts"},
+				{format: hoverContentFormatPlaintext, value: "plain", language: ""},
+			},
+		},
+		{
+			name: "unterminated code block",
+			text: "```go\nx := 1\n---\ny := 2",
+			want: []testHoverContent{
+				{format: hoverContentFormatPlaintext, value: "x := 1\n---\ny := 2", language: "go"},
+			},
+		},
+		{
+			name: "empty",
+			text: "",
+			want: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var contents []graphqlbackend.HoverContentResolver
+			for _, content := range splitHoverText(tc.text) {
+				contents = append(contents, content)
+			}
+
+			if have := testHoverContents(contents); !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("have contents %+v, want %+v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestHoverContentsFormat(t *testing.T) {
+	const text = "```go\nfunc Foo()\n```\n\n---\n\nFoo does *things* & `stuff`."
+
+	for _, tc := range []struct {
+		format string
+		want   []testHoverContent
+	}{
+		{
+			format: hoverContentFormatMarkdown,
+			want: []testHoverContent{
+				{format: hoverContentFormatPlaintext, value: "func Foo()", language: "go"},
+				{format: hoverContentFormatMarkdown, value: "Foo does *things* & `stuff`."},
+			},
+		},
+		{
+			// Code is never rendered as markdown, so it's left as is.
+			format: hoverContentFormatPlaintext,
+			want: []testHoverContent{
+				{format: hoverContentFormatPlaintext, value: "func Foo()", language: "go"},
+				{format: hoverContentFormatPlaintext, value: "Foo does things & stuff."},
+			},
+		},
+	} {
+		r := &hoverResolver{text: text, format: tc.format}
+		if have := testHoverContents(r.Contents()); !reflect.DeepEqual(have, tc.want) {
+			t.Errorf("format %s: have contents %+v, want %+v", tc.format, have, tc.want)
+		}
+	}
+}
@@ -3,48 +3,278 @@ package resolvers
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
 
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 type locationConnectionResolver struct {
 	locations []*lsif.LSIFLocation
-	nextURL   string
+	partial   bool
+
+	// cursor identifies the next page of references, if any.
+	cursor *referencesCursor
+
+	// countEstimate is the number of locations found before they were
+	// truncated to locations, if truncated is true.
+	truncated     bool
+	countEstimate int
 }
 
 var _ graphqlbackend.LocationConnectionResolver = &locationConnectionResolver{}
 
-func (r *locationConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.LocationResolver, error) {
+func (r *locationConnectionResolver) Nodes(ctx context.Context, args *graphqlbackend.LocationConnectionNodesArgs) ([]graphqlbackend.LocationResolver, error) {
 	collectionResolver := &repositoryCollectionResolver{
 		commitCollectionResolvers: map[api.RepoID]*commitCollectionResolver{},
 	}
 
-	var l []graphqlbackend.LocationResolver
+	// Look up the repositories of all locations at once, rather than one by one as
+	// they are resolved.
+	repoIDs := make([]api.RepoID, 0, len(r.locations))
+	for _, location := range r.locations {
+		repoIDs = append(repoIDs, location.RepositoryID)
+	}
+	if _, err := graphqlbackend.LoadRepositories(ctx, repoIDs...); err != nil {
+		return nil, err
+	}
+
+	treeResolvers := make([]*graphqlbackend.GitTreeEntryResolver, 0, len(r.locations))
 	for _, location := range r.locations {
 		treeResolver, err := collectionResolver.resolve(ctx, location.RepositoryID, location.Commit, location.Path)
 		if err != nil {
 			return nil, err
 		}
 
+		treeResolvers = append(treeResolvers, treeResolver)
+	}
+
+	var contents map[*graphqlbackend.GitTreeEntryResolver][]string
+	contextLines := 0
+	if args.ContextLines != nil {
+		contextLines = int(*args.ContextLines)
+		if contextLines < 0 {
+			contextLines = 0
+		}
+		if contextLines > maxLocationContextLines {
+			contextLines = maxLocationContextLines
+		}
+		contents = fetchFileLines(ctx, treeResolvers)
+	}
+
+	var l []graphqlbackend.LocationResolver
+	for i, location := range r.locations {
+		treeResolver := treeResolvers[i]
 		if treeResolver == nil {
 			continue
 		}
 
-		l = append(l, graphqlbackend.NewLocationResolver(
+		var content graphqlbackend.LocationContentResolver
+		if lines, ok := contents[treeResolver]; ok {
+			content = locationContent(lines, location.Range.Start.Line, contextLines)
+		}
+
+		l = append(l, graphqlbackend.NewLocationResolverWithContent(
 			treeResolver,
 			&location.Range,
+			content,
 		))
 	}
 
 	return l, nil
 }
 
+func (r *locationConnectionResolver) Groups(ctx context.Context, args *graphqlbackend.LocationConnectionNodesArgs) ([]graphqlbackend.LocationRepositoryGroupResolver, error) {
+	locations, err := r.Nodes(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return groupLocations(locations), nil
+}
+
+func (r *locationConnectionResolver) Partial() bool {
+	return r.partial
+}
+
+func (r *locationConnectionResolver) Truncated() bool {
+	return r.truncated
+}
+
+func (r *locationConnectionResolver) CountEstimate() int32 {
+	if r.truncated {
+		return int32(r.countEstimate)
+	}
+	return int32(len(r.locations))
+}
+
 func (r *locationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
-	if r.nextURL != "" {
-		return graphqlutil.NextPageCursor(base64.StdEncoding.EncodeToString([]byte(r.nextURL))), nil
+	if r.cursor != nil {
+		encoded, err := json.Marshal(r.cursor)
+		if err != nil {
+			return nil, err
+		}
+		return graphqlutil.NextPageCursor(base64.StdEncoding.EncodeToString(encoded)), nil
 	}
 	return graphqlutil.HasNextPage(false), nil
 }
+
+// groupLocations groups the given locations by repository, then by file. Groups are
+// ordered by their first location. Locations of the same file share a tree entry, since
+// they are resolved through the same repositoryCollectionResolver.
+func groupLocations(locations []graphqlbackend.LocationResolver) []graphqlbackend.LocationRepositoryGroupResolver {
+	var (
+		repos     []*locationRepositoryGroupResolver
+		repoIndex = map[graphql.ID]*locationRepositoryGroupResolver{}
+		fileIndex = map[*graphqlbackend.GitTreeEntryResolver]*locationFileGroupResolver{}
+	)
+
+	for _, location := range locations {
+		file := location.Resource()
+
+		repo, ok := repoIndex[file.Repository().ID()]
+		if !ok {
+			repo = &locationRepositoryGroupResolver{repository: file.Repository()}
+			repoIndex[file.Repository().ID()] = repo
+			repos = append(repos, repo)
+		}
+
+		group, ok := fileIndex[file]
+		if !ok {
+			group = &locationFileGroupResolver{file: file}
+			fileIndex[file] = group
+			repo.files = append(repo.files, group)
+		}
+
+		group.locations = append(group.locations, location)
+		repo.count++
+	}
+
+	resolvers := make([]graphqlbackend.LocationRepositoryGroupResolver, 0, len(repos))
+	for _, repo := range repos {
+		resolvers = append(resolvers, repo)
+	}
+	return resolvers
+}
+
+type locationRepositoryGroupResolver struct {
+	repository *graphqlbackend.RepositoryResolver
+	count      int32
+	files      []*locationFileGroupResolver
+}
+
+var _ graphqlbackend.LocationRepositoryGroupResolver = &locationRepositoryGroupResolver{}
+
+func (r *locationRepositoryGroupResolver) Repository() *graphqlbackend.RepositoryResolver {
+	return r.repository
+}
+
+func (r *locationRepositoryGroupResolver) Count() int32 { return r.count }
+
+func (r *locationRepositoryGroupResolver) Files() []graphqlbackend.LocationFileGroupResolver {
+	files := make([]graphqlbackend.LocationFileGroupResolver, 0, len(r.files))
+	for _, file := range r.files {
+		files = append(files, file)
+	}
+	return files
+}
+
+type locationFileGroupResolver struct {
+	file      *graphqlbackend.GitTreeEntryResolver
+	locations []graphqlbackend.LocationResolver
+}
+
+var _ graphqlbackend.LocationFileGroupResolver = &locationFileGroupResolver{}
+
+func (r *locationFileGroupResolver) File() *graphqlbackend.GitTreeEntryResolver { return r.file }
+
+func (r *locationFileGroupResolver) Count() int32 { return int32(len(r.locations)) }
+
+func (r *locationFileGroupResolver) Locations() []graphqlbackend.LocationResolver {
+	return r.locations
+}
+
+// maxLocationContextLines is the maximum number of lines of context that can be requested
+// before and after the line of each location.
+const maxLocationContextLines = 10
+
+// maxConcurrentFileFetches is the number of files read from gitserver in parallel when
+// fetching the contents of locations.
+const maxConcurrentFileFetches = 8
+
+// fetchFileLines reads each distinct file of the given tree entries once and returns its
+// lines. Locations of the same file share a tree entry, since they are resolved through
+// the same repositoryCollectionResolver. Files that can't be read are omitted, so that the
+// locations are still returned without their content.
+func fetchFileLines(ctx context.Context, treeResolvers []*graphqlbackend.GitTreeEntryResolver) map[*graphqlbackend.GitTreeEntryResolver][]string {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, maxConcurrentFileFetches)
+		lines = map[*graphqlbackend.GitTreeEntryResolver][]string{}
+		seen  = map[*graphqlbackend.GitTreeEntryResolver]bool{}
+	)
+
+	for _, treeResolver := range treeResolvers {
+		if treeResolver == nil || seen[treeResolver] {
+			continue
+		}
+		seen[treeResolver] = true
+
+		wg.Add(1)
+		go func(treeResolver *graphqlbackend.GitTreeEntryResolver) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			content, err := treeResolver.Content(ctx)
+			if err != nil {
+				log15.Warn("codeintel: reading file of location", "path", treeResolver.Path(), "error", err)
+				return
+			}
+
+			mu.Lock()
+			lines[treeResolver] = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+			mu.Unlock()
+		}(treeResolver)
+	}
+
+	wg.Wait()
+	return lines
+}
+
+// locationContent returns the given line of a file and up to contextLines lines before and
+// after it, or nil if the file has no such line.
+func locationContent(lines []string, line, contextLines int) graphqlbackend.LocationContentResolver {
+	if line < 0 || line >= len(lines) {
+		return nil
+	}
+
+	start := line - contextLines
+	if start < 0 {
+		start = 0
+	}
+	end := line + 1 + contextLines
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	trim := func(ls []string) []string {
+		trimmed := make([]string, 0, len(ls))
+		for _, l := range ls {
+			trimmed = append(trimmed, strings.TrimSuffix(l, "\r"))
+		}
+		return trimmed
+	}
+
+	return graphqlbackend.NewLocationContentResolver(
+		strings.TrimSuffix(lines[line], "\r"),
+		trim(lines[start:line]),
+		trim(lines[line+1:end]),
+	)
+}
@@ -0,0 +1,159 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestLocationContent(t *testing.T) {
+	lines := []string{"a", "b\r", "c", "d", "e"}
+
+	for _, tc := range []struct {
+		name         string
+		line         int
+		contextLines int
+		wantLine     string
+		wantBefore   []string
+		wantAfter    []string
+		wantNil      bool
+	}{
+		{name: "context", line: 2, contextLines: 1, wantLine: "c", wantBefore: []string{"b"}, wantAfter: []string{"d"}},
+		{name: "no context", line: 1, contextLines: 0, wantLine: "b", wantBefore: []string{}, wantAfter: []string{}},
+		{name: "first line", line: 0, contextLines: 2, wantLine: "a", wantBefore: []string{}, wantAfter: []string{"b", "c"}},
+		{name: "last line", line: 4, contextLines: 10, wantLine: "e", wantBefore: []string{"a", "b", "c", "d"}, wantAfter: []string{}},
+		{name: "line after the end of the file", line: 5, wantNil: true},
+		{name: "negative line", line: -1, wantNil: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			content := locationContent(lines, tc.line, tc.contextLines)
+			if tc.wantNil {
+				if content != nil {
+					t.Errorf("have content %+v, want none", content)
+				}
+				return
+			}
+			if content == nil {
+				t.Fatal("have no content, want content")
+			}
+
+			if content.Line() != tc.wantLine || !reflect.DeepEqual(content.Before(), tc.wantBefore) || !reflect.DeepEqual(content.After(), tc.wantAfter) {
+				t.Errorf("have %q, %q before and %q after, want %q, %q before and %q after", content.Line(), content.Before(), content.After(), tc.wantLine, tc.wantBefore, tc.wantAfter)
+			}
+		})
+	}
+}
+
+func TestFetchFileLines(t *testing.T) {
+	defer func() { backend.Mocks = backend.MockServices{} }()
+
+	var mu sync.Mutex
+	reads := map[string]int{}
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		mu.Lock()
+		reads[name]++
+		mu.Unlock()
+
+		if name == "b.go" {
+			return nil, errors.New("gitserver unavailable")
+		}
+		return []byte("package a\n\nfunc A() {}\n"), nil
+	}
+	defer git.ResetMocks()
+
+	commit := newTestCommit(t, graphqlbackend.NewRepositoryResolver(&types.Repo{ID: 50, Name: "r"}))
+	a := graphqlbackend.NewGitTreeEntryResolver(commit, graphqlbackend.CreateFileInfo("a.go", false))
+	b := graphqlbackend.NewGitTreeEntryResolver(commit, graphqlbackend.CreateFileInfo("b.go", false))
+
+	// Files shared by several locations are read once, and files that can't be
+	// read are left out.
+	lines := fetchFileLines(context.Background(), []*graphqlbackend.GitTreeEntryResolver{a, nil, b, a})
+
+	if want := map[*graphqlbackend.GitTreeEntryResolver][]string{a: {"package a", "", "func A() {}"}}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("have lines %v, want %v", lines, want)
+	}
+	if want := map[string]int{"a.go": 1, "b.go": 1}; !reflect.DeepEqual(reads, want) {
+		t.Errorf("have reads %v, want %v", reads, want)
+	}
+}
+
+func TestGroupLocations(t *testing.T) {
+	defer func() { backend.Mocks = backend.MockServices{} }()
+
+	repo1 := graphqlbackend.NewRepositoryResolver(&types.Repo{ID: 1, Name: "r1"})
+	repo2 := graphqlbackend.NewRepositoryResolver(&types.Repo{ID: 2, Name: "r2"})
+	commit1, commit2 := newTestCommit(t, repo1), newTestCommit(t, repo2)
+
+	// Locations of the same file share a tree entry.
+	a := graphqlbackend.NewGitTreeEntryResolver(commit1, graphqlbackend.CreateFileInfo("a.go", false))
+	b := graphqlbackend.NewGitTreeEntryResolver(commit1, graphqlbackend.CreateFileInfo("b.go", false))
+	x := graphqlbackend.NewGitTreeEntryResolver(commit2, graphqlbackend.CreateFileInfo("x.go", false))
+
+	locations := []graphqlbackend.LocationResolver{
+		graphqlbackend.NewLocationResolver(a, nil),
+		graphqlbackend.NewLocationResolver(x, nil),
+		graphqlbackend.NewLocationResolver(b, nil),
+		graphqlbackend.NewLocationResolver(a, nil),
+	}
+
+	type fileGroup struct {
+		path      string
+		count     int32
+		locations []graphqlbackend.LocationResolver
+	}
+	type repoGroup struct {
+		repo  string
+		count int32
+		files []fileGroup
+	}
+
+	var have []repoGroup
+	for _, repo := range groupLocations(locations) {
+		group := repoGroup{repo: repo.Repository().Name(), count: repo.Count()}
+		for _, file := range repo.Files() {
+			group.files = append(group.files, fileGroup{path: file.File().Path(), count: file.Count(), locations: file.Locations()})
+		}
+		have = append(have, group)
+	}
+
+	// Groups are ordered by their first location.
+	want := []repoGroup{
+		{repo: "r1", count: 3, files: []fileGroup{
+			{path: "a.go", count: 2, locations: []graphqlbackend.LocationResolver{locations[0], locations[3]}},
+			{path: "b.go", count: 1, locations: []graphqlbackend.LocationResolver{locations[2]}},
+		}},
+		{repo: "r2", count: 1, files: []fileGroup{
+			{path: "x.go", count: 1, locations: []graphqlbackend.LocationResolver{locations[1]}},
+		}},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have groups %+v, want %+v", have, want)
+	}
+
+	if groups := groupLocations(nil); len(groups) != 0 {
+		t.Errorf("have groups %+v without locations, want none", groups)
+	}
+}
+
+// newTestCommit returns a resolver of testCommit in the given repository. It
+// mocks backend.Repos.GetCommit, which the caller must reset.
+func newTestCommit(t *testing.T, repo *graphqlbackend.RepositoryResolver) *graphqlbackend.GitCommitResolver {
+	t.Helper()
+
+	backend.Mocks.Repos.GetCommit = func(ctx context.Context, repo *types.Repo, commitID api.CommitID) (*git.Commit, error) {
+		return &git.Commit{ID: commitID}, nil
+	}
+	commit, err := repo.CommitFromID(context.Background(), &graphqlbackend.RepositoryCommitArgs{}, testCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return commit
+}
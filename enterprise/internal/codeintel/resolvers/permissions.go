@@ -0,0 +1,61 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+// filterUploadsByPerms returns the given uploads of the repositories on which
+// the current user has the given permissions, preserving their order.
+func filterUploadsByPerms(ctx context.Context, uploads []*lsif.LSIFUpload, p authz.Perms) ([]*lsif.LSIFUpload, error) {
+	if len(uploads) == 0 {
+		return uploads, nil
+	}
+
+	ids := make([]api.RepoID, 0, len(uploads))
+	for _, upload := range uploads {
+		ids = append(ids, upload.RepositoryID)
+	}
+
+	repos, err := db.Repos.GetByIDsWithPerms(ctx, p, ids...)
+	if err != nil {
+		return nil, err
+	}
+	permitted := make(map[api.RepoID]bool, len(repos))
+	for _, repo := range repos {
+		permitted[repo.ID] = true
+	}
+
+	filtered := make([]*lsif.LSIFUpload, 0, len(uploads))
+	for _, upload := range uploads {
+		if permitted[upload.RepositoryID] {
+			filtered = append(filtered, upload)
+		}
+	}
+	return filtered, nil
+}
+
+// checkCanManageUpload returns an error if the current user may not delete or
+// retry the given upload. Site admins may manage all uploads, and other users
+// the uploads of repositories on which they have write permissions.
+func checkCanManageUpload(ctx context.Context, upload *lsif.LSIFUpload) error {
+	// Errors other than ErrMustBeSiteAdmin mean that the actor is anonymous or
+	// restricted to scopes that don't allow managing uploads.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != backend.ErrMustBeSiteAdmin {
+		return err
+	}
+
+	uploads, err := filterUploadsByPerms(ctx, []*lsif.LSIFUpload{upload}, authz.Write)
+	if err != nil {
+		return err
+	}
+	if len(uploads) == 0 {
+		return &backend.InsufficientAuthorizationError{Message: "must be site admin or have write permissions on the repository of the LSIF upload"}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// positionAdjuster translates positions between the commit of a query and the commit of
+// the upload that answers the query. When there is no upload for the exact commit that is
+// being viewed, the nearest upload is used instead and the lines of each file may have
+// shifted between the two commits. A position can only be translated if its line is not
+// touched by the git diff between the two commits.
+type positionAdjuster struct {
+	repoID       api.RepoID
+	commit       string
+	uploadCommit string
+
+	// fileDiffs maps paths in the query commit to the diff of that file.
+	fileDiffs map[string]*diff.FileDiff
+
+	// uploadFileDiffs maps paths in the upload commit to the diff of that file.
+	uploadFileDiffs map[string]*diff.FileDiff
+}
+
+// newPositionAdjuster returns a position adjuster for the given query commit and upload. If
+// the upload was made for the query commit, a nil adjuster is returned, which does not modify
+// any position.
+func newPositionAdjuster(ctx context.Context, repoID api.RepoID, commit string, upload *lsif.LSIFUpload) (*positionAdjuster, error) {
+	if commit == upload.Commit {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(commit, "-") || strings.HasPrefix(upload.Commit, "-") {
+		// Be careful to avoid letting user input add additional `git diff` command-line flags.
+		return nil, fmt.Errorf("invalid diff range argument: %q..%q", upload.Commit, commit)
+	}
+
+	repo, err := backend.Repos.Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := git.ExecReader(ctx, *cachedRepo, []string{
+		"diff",
+		"--find-renames",
+		"--full-index",
+		"--no-prefix",
+		upload.Commit,
+		commit,
+		"--",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	adjuster := &positionAdjuster{
+		repoID:          repoID,
+		commit:          commit,
+		uploadCommit:    upload.Commit,
+		fileDiffs:       map[string]*diff.FileDiff{},
+		uploadFileDiffs: map[string]*diff.FileDiff{},
+	}
+
+	dr := diff.NewMultiFileDiffReader(rdr)
+	for {
+		fileDiff, err := dr.ReadFile()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		adjuster.fileDiffs[fileDiff.NewName] = fileDiff
+		adjuster.uploadFileDiffs[fileDiff.OrigName] = fileDiff
+	}
+
+	return adjuster, nil
+}
+
+// AdjustPosition translates a position in the given path of the query commit into the
+// corresponding path and position of the upload commit. The returned flag is false if
+// the position cannot be translated because its line was added or changed.
+func (a *positionAdjuster) AdjustPosition(path string, line, character int32) (string, int32, int32, bool) {
+	if a == nil {
+		return path, line, character, true
+	}
+
+	fileDiff, ok := a.fileDiffs[path]
+	if !ok {
+		return path, line, character, true
+	}
+	if fileDiff.OrigName == "/dev/null" {
+		return "", 0, 0, false
+	}
+
+	adjustedLine, ok := adjustLine(fileDiff.Hunks, int(line), false)
+	if !ok {
+		return "", 0, 0, false
+	}
+
+	return fileDiff.OrigName, int32(adjustedLine), character, true
+}
+
+// AdjustLocations translates the locations returned by the upload into the query commit.
+// Locations within other repositories or commits are returned unchanged, and locations
+// that no longer exist in the query commit are removed.
+func (a *positionAdjuster) AdjustLocations(locations []*lsif.LSIFLocation) []*lsif.LSIFLocation {
+	if a == nil {
+		return locations
+	}
+
+	adjusted := make([]*lsif.LSIFLocation, 0, len(locations))
+	for _, location := range locations {
+		if location.RepositoryID != a.repoID || location.Commit != a.uploadCommit {
+			adjusted = append(adjusted, location)
+			continue
+		}
+
+		path, lspRange, ok := a.AdjustRange(location.Path, location.Range)
+		if !ok {
+			continue
+		}
+
+		adjusted = append(adjusted, &lsif.LSIFLocation{
+			RepositoryID: location.RepositoryID,
+			Commit:       a.commit,
+			Path:         path,
+			Range:        lspRange,
+		})
+	}
+
+	return adjusted
+}
+
+// AdjustRange translates a range in the given path of the upload commit into the corresponding
+// path and range of the query commit. The returned flag is false if the range cannot be translated
+// because one of its lines was removed or changed.
+func (a *positionAdjuster) AdjustRange(path string, lspRange lsp.Range) (string, lsp.Range, bool) {
+	if a == nil {
+		return path, lspRange, true
+	}
+
+	fileDiff, ok := a.uploadFileDiffs[path]
+	if !ok {
+		return path, lspRange, true
+	}
+	if fileDiff.NewName == "/dev/null" {
+		return "", lsp.Range{}, false
+	}
+
+	startLine, ok := adjustLine(fileDiff.Hunks, lspRange.Start.Line, true)
+	if !ok {
+		return "", lsp.Range{}, false
+	}
+	endLine, ok := adjustLine(fileDiff.Hunks, lspRange.End.Line, true)
+	if !ok {
+		return "", lsp.Range{}, false
+	}
+
+	return fileDiff.NewName, lsp.Range{
+		Start: lsp.Position{Line: startLine, Character: lspRange.Start.Character},
+		End:   lsp.Position{Line: endLine, Character: lspRange.End.Character},
+	}, true
+}
+
+// adjustLine translates a zero-based line through the given hunks. If forward is true, the
+// line is translated from the original side of the diff to the new side; otherwise, it is
+// translated from the new side to the original side. The returned flag is false if the line
+// is part of a change and has no counterpart on the other side.
+func adjustLine(hunks []*diff.Hunk, line int, forward bool) (int, bool) {
+	// Hunk line numbers are one-based
+	line++
+
+	delta := 0
+	for _, hunk := range hunks {
+		srcStart, srcLines := int(hunk.NewStartLine), int(hunk.NewLines)
+		dstStart, dstLines := int(hunk.OrigStartLine), int(hunk.OrigLines)
+		srcOnly, dstOnly := byte('+'), byte('-')
+		if forward {
+			srcStart, srcLines, dstStart, dstLines = dstStart, dstLines, srcStart, srcLines
+			srcOnly, dstOnly = dstOnly, srcOnly
+		}
+
+		// An empty side of a hunk is identified by the line preceding the change
+		if srcLines == 0 {
+			srcStart++
+		}
+		if dstLines == 0 {
+			dstStart++
+		}
+
+		if line < srcStart {
+			break
+		}
+
+		if line >= srcStart+srcLines {
+			delta = (dstStart + dstLines) - (srcStart + srcLines)
+			continue
+		}
+
+		srcLine, dstLine := srcStart, dstStart
+		for _, bodyLine := range bytes.Split(hunk.Body, []byte("\n")) {
+			if len(bodyLine) == 0 {
+				continue
+			}
+
+			switch bodyLine[0] {
+			case ' ':
+				if srcLine == line {
+					return dstLine - 1, true
+				}
+				srcLine++
+				dstLine++
+			case srcOnly:
+				if srcLine == line {
+					return 0, false
+				}
+				srcLine++
+			case dstOnly:
+				dstLine++
+			}
+		}
+
+		return 0, false
+	}
+
+	return line + delta - 1, true
+}
@@ -0,0 +1,257 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+// testDiff changes the upload commit version of a.go (orig) into the query
+// commit version (new):
+//
+//	orig  new
+//	1     1     context
+//	2     2     context
+//	3     -     removed
+//	-     3     added
+//	-     4     added
+//	4     5     context
+//	5-9   6-10  unchanged, outside of the hunks
+//	10    11    context
+//	-     12    added
+//	11    13    context
+//	12-   14-   unchanged, outside of the hunks
+const testDiff = `diff --git a.go a.go
+index 0000000000000000000000000000000000000001..0000000000000000000000000000000000000002 100644
+--- a.go
++++ a.go
+@@ -2,3 +2,4 @@
+ line 2
+-line 3
++line 3 changed
++line 3 added
+ line 4
+@@ -10,2 +11,3 @@
+ line 10
++line 10 added
+ line 11
+`
+
+func parseTestFileDiff(t *testing.T, d string) *diff.FileDiff {
+	t.Helper()
+
+	fileDiff, err := diff.ParseFileDiff([]byte(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fileDiff
+}
+
+func TestAdjustLine(t *testing.T) {
+	hunks := parseTestFileDiff(t, testDiff).Hunks
+
+	// Lines are one-based in the table, like in the diff, but zero-based
+	// in adjustLine.
+	for _, tc := range []struct {
+		name    string
+		line    int
+		forward bool
+		want    int
+		wantOK  bool
+	}{
+		{name: "before the hunks", line: 1, forward: true, want: 1, wantOK: true},
+		{name: "context line", line: 2, forward: true, want: 2, wantOK: true},
+		{name: "removed line", line: 3, forward: true, wantOK: false},
+		{name: "context line after change", line: 4, forward: true, want: 5, wantOK: true},
+		{name: "between the hunks", line: 5, forward: true, want: 6, wantOK: true},
+		{name: "before the second hunk", line: 9, forward: true, want: 10, wantOK: true},
+		{name: "context line of second hunk", line: 10, forward: true, want: 11, wantOK: true},
+		{name: "context line after addition", line: 11, forward: true, want: 13, wantOK: true},
+		{name: "after the hunks", line: 20, forward: true, want: 22, wantOK: true},
+
+		{name: "backward before the hunks", line: 1, want: 1, wantOK: true},
+		{name: "backward context line", line: 2, want: 2, wantOK: true},
+		{name: "backward changed line", line: 3, wantOK: false},
+		{name: "backward added line", line: 4, wantOK: false},
+		{name: "backward context line after change", line: 5, want: 4, wantOK: true},
+		{name: "backward between the hunks", line: 6, want: 5, wantOK: true},
+		{name: "backward added line of second hunk", line: 12, wantOK: false},
+		{name: "backward context line after addition", line: 13, want: 11, wantOK: true},
+		{name: "backward after the hunks", line: 22, want: 20, wantOK: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, ok := adjustLine(hunks, tc.line-1, tc.forward)
+			if ok != tc.wantOK {
+				t.Fatalf("have ok %v, want %v", ok, tc.wantOK)
+			}
+			if ok && have+1 != tc.want {
+				t.Errorf("have line %d, want %d", have+1, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdjustLineEdgeCases(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		diff    string
+		line    int
+		forward bool
+		want    int
+		wantOK  bool
+	}{
+		{
+			name:    "pure addition",
+			diff:    "--- a.go\n+++ a.go\n@@ -5,0 +6,2 @@\n+added 6\n+added 7\n",
+			line:    6,
+			forward: true,
+			want:    8,
+			wantOK:  true,
+		},
+		{
+			name:   "pure addition backward",
+			diff:   "--- a.go\n+++ a.go\n@@ -5,0 +6,2 @@\n+added 6\n+added 7\n",
+			line:   7,
+			wantOK: false,
+		},
+		{
+			name:    "pure removal",
+			diff:    "--- a.go\n+++ a.go\n@@ -3,2 +2,0 @@\n-removed 3\n-removed 4\n",
+			line:    5,
+			forward: true,
+			want:    3,
+			wantOK:  true,
+		},
+		{
+			name:    "removed line before missing newline at end of file",
+			diff:    "--- a.go\n+++ a.go\n@@ -1,2 +1,2 @@\n first\n-last\n\\ No newline at end of file\n+last\n",
+			line:    2,
+			forward: true,
+			wantOK:  false,
+		},
+		{
+			name:    "context line before missing newline at end of file",
+			diff:    "--- a.go\n+++ a.go\n@@ -1,2 +1,3 @@\n+zeroth\n first\n second\n\\ No newline at end of file\n",
+			line:    2,
+			forward: true,
+			want:    3,
+			wantOK:  true,
+		},
+		{
+			name:   "context line before missing newline at end of file backward",
+			diff:   "--- a.go\n+++ a.go\n@@ -1,2 +1,3 @@\n+zeroth\n first\n second\n\\ No newline at end of file\n",
+			line:   3,
+			want:   2,
+			wantOK: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, ok := adjustLine(parseTestFileDiff(t, tc.diff).Hunks, tc.line-1, tc.forward)
+			if ok != tc.wantOK {
+				t.Fatalf("have ok %v, want %v", ok, tc.wantOK)
+			}
+			if ok && have+1 != tc.want {
+				t.Errorf("have line %d, want %d", have+1, tc.want)
+			}
+		})
+	}
+}
+
+func newTestPositionAdjuster(t *testing.T, diffs ...string) *positionAdjuster {
+	a := &positionAdjuster{
+		repoID:          50,
+		commit:          "deadbeef",
+		uploadCommit:    "cafebabe",
+		fileDiffs:       map[string]*diff.FileDiff{},
+		uploadFileDiffs: map[string]*diff.FileDiff{},
+	}
+	for _, d := range diffs {
+		fileDiff := parseTestFileDiff(t, d)
+		a.fileDiffs[fileDiff.NewName] = fileDiff
+		a.uploadFileDiffs[fileDiff.OrigName] = fileDiff
+	}
+	return a
+}
+
+func TestAdjustPosition(t *testing.T) {
+	a := newTestPositionAdjuster(t,
+		testDiff,
+		"--- old.go\n+++ renamed.go\n@@ -1,1 +1,2 @@\n+added\n line\n",
+		"--- /dev/null\n+++ new.go\n@@ -0,0 +1,1 @@\n+added\n",
+	)
+
+	for _, tc := range []struct {
+		name     string
+		path     string
+		line     int32
+		wantPath string
+		wantLine int32
+		wantOK   bool
+	}{
+		{name: "unchanged file", path: "b.go", line: 7, wantPath: "b.go", wantLine: 7, wantOK: true},
+		{name: "shifted line", path: "a.go", line: 12, wantPath: "a.go", wantLine: 10, wantOK: true},
+		{name: "added line", path: "a.go", line: 11, wantOK: false},
+		{name: "renamed file", path: "renamed.go", line: 1, wantPath: "old.go", wantLine: 0, wantOK: true},
+		{name: "added file", path: "new.go", line: 0, wantOK: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path, line, character, ok := a.AdjustPosition(tc.path, tc.line, 5)
+			if ok != tc.wantOK {
+				t.Fatalf("have ok %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if path != tc.wantPath || line != tc.wantLine || character != 5 {
+				t.Errorf("have %s:%d:%d, want %s:%d:5", path, line, character, tc.wantPath, tc.wantLine)
+			}
+		})
+	}
+
+	var nilAdjuster *positionAdjuster
+	if path, line, character, ok := nilAdjuster.AdjustPosition("a.go", 3, 4); path != "a.go" || line != 3 || character != 4 || !ok {
+		t.Errorf("nil adjuster changed position to %s:%d:%d (ok=%v)", path, line, character, ok)
+	}
+}
+
+func TestAdjustLocations(t *testing.T) {
+	a := newTestPositionAdjuster(t,
+		testDiff,
+		"--- removed.go\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-removed\n",
+	)
+
+	lspRange := func(startLine, endLine int) lsp.Range {
+		return lsp.Range{
+			Start: lsp.Position{Line: startLine, Character: 1},
+			End:   lsp.Position{Line: endLine, Character: 2},
+		}
+	}
+
+	locations := []*lsif.LSIFLocation{
+		// Shifted by the first hunk.
+		{RepositoryID: 50, Commit: "cafebabe", Path: "a.go", Range: lspRange(4, 5)},
+		// Ends on a removed line.
+		{RepositoryID: 50, Commit: "cafebabe", Path: "a.go", Range: lspRange(1, 2)},
+		// In a removed file.
+		{RepositoryID: 50, Commit: "cafebabe", Path: "removed.go", Range: lspRange(0, 0)},
+		// In an unchanged file.
+		{RepositoryID: 50, Commit: "cafebabe", Path: "b.go", Range: lspRange(3, 3)},
+		// In another commit and another repository.
+		{RepositoryID: 50, Commit: "f00dcafe", Path: "a.go", Range: lspRange(2, 2)},
+		{RepositoryID: 51, Commit: "cafebabe", Path: "a.go", Range: lspRange(2, 2)},
+	}
+
+	want := []*lsif.LSIFLocation{
+		{RepositoryID: 50, Commit: "deadbeef", Path: "a.go", Range: lspRange(5, 6)},
+		{RepositoryID: 50, Commit: "deadbeef", Path: "b.go", Range: lspRange(3, 3)},
+		{RepositoryID: 50, Commit: "f00dcafe", Path: "a.go", Range: lspRange(2, 2)},
+		{RepositoryID: 51, Commit: "cafebabe", Path: "a.go", Range: lspRange(2, 2)},
+	}
+
+	if have := a.AdjustLocations(locations); !reflect.DeepEqual(have, want) {
+		t.Errorf("unexpected locations: have %+v, want %+v", have, want)
+	}
+}
@@ -3,117 +3,494 @@ package resolvers
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/featureflag"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/src-d/enry/v2"
 )
 
+// locationQueryTimeout is the time budget of a definitions or references query. The queried
+// uploads are queried concurrently, so each of them gets the whole remaining budget. The LSIF
+// server divides it among the uploads it queries one after the other to resolve remote
+// locations, so that a single slow upload cannot hold up the whole request. Uploads that
+// exceed their share are skipped and the results are marked as partial.
+const locationQueryTimeout = 10 * time.Second
+
 type lsifQueryResolver struct {
+	client codeintel.Client
 	repoID api.RepoID
 	commit graphqlbackend.GitObjectID
 	path   string
-	upload *lsif.LSIFUpload
+
+	// uploads are the uploads that contain the path and are queried, closest first.
+	uploads []*lsif.LSIFUpload
+	// skippedUploads are the uploads that also contain the path, but are not queried
+	// because of the lsifMaxUploadsPerQuery site configuration.
+	skippedUploads []*lsif.LSIFUpload
 }
 
 var _ graphqlbackend.LSIFQueryResolver = &lsifQueryResolver{}
 
+// newLSIFQueryResolver returns a resolver that queries at most
+// conf.LSIFMaxUploadsPerQuery of the given uploads, which must be ordered by
+// decreasing preference. It returns nil if there are no uploads.
+func newLSIFQueryResolver(client codeintel.Client, repoID api.RepoID, commit graphqlbackend.GitObjectID, path string, uploads []*lsif.LSIFUpload) *lsifQueryResolver {
+	if len(uploads) == 0 {
+		return nil
+	}
+
+	r := &lsifQueryResolver{
+		client:  client,
+		repoID:  repoID,
+		commit:  commit,
+		path:    path,
+		uploads: uploads,
+	}
+	if max := conf.LSIFMaxUploadsPerQuery(); len(uploads) > max {
+		r.uploads, r.skippedUploads = uploads[:max], uploads[max:]
+	}
+	return r
+}
+
+// QueryExperiments are the feature flags of changes to how precise code intel queries are
+// resolved. The flags that are enabled for the user of a query are recorded along with the
+// code path that served it, so that the precision and latency of queries can be compared
+// between users with and without a change.
+var QueryExperiments []*featureflag.Flag
+
+// logQuery records a query of the given operation that started at the given time, tagged
+// with the indexer of the closest queried upload and the language of the queried file. Queries
+// without a result are recorded as served by the types.CodeIntelPathPreciseEmpty code path.
+func (r *lsifQueryResolver) logQuery(ctx context.Context, operation string, started time.Time, empty bool) {
+	var indexer string
+	if r.uploads[0].Indexer != nil {
+		indexer = *r.uploads[0].Indexer
+	}
+
+	codePath := types.CodeIntelPathPrecise
+	if empty {
+		codePath = types.CodeIntelPathPreciseEmpty
+	}
+
+	logQuery(ctx, &types.CodeIntelQuery{
+		Operation: operation,
+		CodePath:  codePath,
+		Indexer:   indexer,
+	}, r.path, started)
+}
+
+// logQuery records the given query of the given path that started at the given time, along
+// with the variant of the QueryExperiments of its user.
+func logQuery(ctx context.Context, q *types.CodeIntelQuery, path string, started time.Time) {
+	q.Language, _ = enry.GetLanguageByExtension(path)
+	q.Variant = featureflag.Variant(ctx, QueryExperiments...)
+	q.Duration = time.Since(started)
+	graphqlbackend.LogCodeIntelQuery(ctx, q)
+}
+
+// forEachUpload calls f concurrently for each queried upload and its index in r.uploads,
+// and returns the error of the first upload that failed. The context passed to f is
+// canceled as soon as an upload fails or ctx is done, e.g. because the client went
+// away, so that the requests of the other uploads are abandoned instead of running to
+// completion for nothing.
+func (r *lsifQueryResolver) forEachUpload(ctx context.Context, f func(ctx context.Context, i int, upload *lsif.LSIFUpload) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, upload := range r.uploads {
+		wg.Add(1)
+		go func(i int, upload *lsif.LSIFUpload) {
+			defer wg.Done()
+
+			err := ctx.Err()
+			if err == nil {
+				err = f(ctx, i, upload)
+			}
+			if err != nil {
+				// Only the first error is returned, since the others may just be
+				// caused by the cancellation.
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i, upload)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
 func (r *lsifQueryResolver) Commit(ctx context.Context) (*graphqlbackend.GitCommitResolver, error) {
-	return resolveCommit(ctx, r.repoID, r.upload.Commit)
+	return resolveCommit(ctx, r.repoID, r.uploads[0].Commit)
 }
 
-func (r *lsifQueryResolver) Definitions(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (graphqlbackend.LocationConnectionResolver, error) {
-	opts := &struct {
-		RepoID    api.RepoID
-		Commit    graphqlbackend.GitObjectID
-		Path      string
-		Line      int32
-		Character int32
-		UploadID  int64
-	}{
-		RepoID:    r.repoID,
-		Commit:    r.commit,
-		Path:      r.path,
-		Line:      args.Line,
-		Character: args.Character,
-		UploadID:  r.upload.ID,
+// IsExactCommit returns whether all queried uploads are of the requested commit. Results
+// of uploads of other commits are adjusted to the requested commit by newPositionAdjuster.
+func (r *lsifQueryResolver) IsExactCommit() bool {
+	for _, upload := range r.uploads {
+		if upload.Commit != string(r.commit) {
+			return false
+		}
 	}
+	return true
+}
+
+func (r *lsifQueryResolver) SkippedUploads() []graphqlbackend.LSIFUploadResolver {
+	resolvers := make([]graphqlbackend.LSIFUploadResolver, 0, len(r.skippedUploads))
+	for _, upload := range r.skippedUploads {
+		resolvers = append(resolvers, &lsifUploadResolver{lsifUpload: upload})
+	}
+	return resolvers
+}
+
+func (r *lsifQueryResolver) Definitions(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (graphqlbackend.LocationConnectionResolver, error) {
+	started := time.Now()
+
+	results := make([][]*lsif.LSIFLocation, len(r.uploads))
+	partials := make([]bool, len(r.uploads))
+
+	// The uploads share the deadline of the query instead of dividing the budget, since
+	// they are queried concurrently.
+	queryCtx, cancel := context.WithTimeout(ctx, locationQueryTimeout)
+	defer cancel()
 
-	locations, nextURL, err := client.DefaultClient.Definitions(ctx, opts)
+	err := r.forEachUpload(queryCtx, func(ctx context.Context, i int, upload *lsif.LSIFUpload) error {
+		adjuster, err := newPositionAdjuster(ctx, r.repoID, string(r.commit), upload)
+		if err != nil {
+			return err
+		}
+
+		path, line, character, ok := adjuster.AdjustPosition(r.path, args.Line, args.Character)
+		if !ok {
+			return nil
+		}
+
+		opts := &client.DefinitionsOptions{
+			RepoID:    r.repoID,
+			Commit:    r.commit,
+			Path:      path,
+			Line:      line,
+			Character: character,
+			UploadID:  upload.ID,
+		}
+
+		locations, _, partial, err := r.client.Definitions(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		results[i] = adjuster.AdjustLocations(locations)
+		partials[i] = partial
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &locationConnectionResolver{
-		locations: locations,
-		nextURL:   nextURL,
-	}, nil
+	resolver := &locationConnectionResolver{locations: mergeLocations(results)}
+	r.logQuery(ctx, "definitions", started, len(resolver.locations) == 0)
+	for _, partial := range partials {
+		resolver.partial = resolver.partial || partial
+	}
+	return resolver, nil
+}
+
+// mergeLocations concatenates the locations returned by several uploads, in the order of
+// the uploads. Locations that are returned by more than one upload are only kept once.
+func mergeLocations(results [][]*lsif.LSIFLocation) []*lsif.LSIFLocation {
+	type key struct {
+		repositoryID api.RepoID
+		commit       string
+		path         string
+		lspRange     lsp.Range
+	}
+
+	var locations []*lsif.LSIFLocation
+	seen := map[key]bool{}
+	for _, result := range results {
+		for _, l := range result {
+			k := key{l.RepositoryID, l.Commit, l.Path, l.Range}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			locations = append(locations, l)
+		}
+	}
+	return locations
+}
+
+// referencesCursor identifies a page of references. The queried uploads are paged through
+// one after the other: Upload is the index of the upload of the page in the queried uploads,
+// and NextURL is the URL of the page, which is empty for the first page of the upload.
+type referencesCursor struct {
+	Upload  int    `json:"upload"`
+	NextURL string `json:"nextURL,omitempty"`
 }
 
 func (r *lsifQueryResolver) References(ctx context.Context, args *graphqlbackend.LSIFPagedQueryPositionArgs) (graphqlbackend.LocationConnectionResolver, error) {
-	opts := &struct {
-		RepoID    api.RepoID
-		Commit    graphqlbackend.GitObjectID
-		Path      string
-		Line      int32
-		Character int32
-		UploadID  int64
-		Limit     *int32
-		Cursor    *string
-	}{
+	started := time.Now()
+
+	var cursor referencesCursor
+	if args.After != nil {
+		decoded, err := base64.StdEncoding.DecodeString(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(decoded, &cursor); err != nil {
+			return nil, errors.Wrap(err, "invalid cursor")
+		}
+		if cursor.Upload < 0 || cursor.Upload >= len(r.uploads) {
+			return nil, fmt.Errorf("invalid cursor: upload %d does not exist", cursor.Upload)
+		}
+	}
+	upload := r.uploads[cursor.Upload]
+
+	// The cursor of the next page is the next page of the same upload, if any,
+	// or else the first page of the next upload.
+	resolver := &locationConnectionResolver{}
+	if cursor.Upload+1 < len(r.uploads) {
+		resolver.cursor = &referencesCursor{Upload: cursor.Upload + 1}
+	}
+
+	adjuster, err := newPositionAdjuster(ctx, r.repoID, string(r.commit), upload)
+	if err != nil {
+		return nil, err
+	}
+
+	path, line, character, ok := adjuster.AdjustPosition(r.path, args.Line, args.Character)
+	if !ok {
+		return resolver, nil
+	}
+
+	opts := &client.ReferencesOptions{
 		RepoID:    r.repoID,
 		Commit:    r.commit,
-		Path:      r.path,
-		Line:      args.Line,
-		Character: args.Character,
-		UploadID:  r.upload.ID,
+		Path:      path,
+		Line:      line,
+		Character: character,
+		UploadID:  upload.ID,
 	}
 	if args.First != nil {
 		opts.Limit = args.First
 	}
-	if args.After != nil {
-		decoded, err := base64.StdEncoding.DecodeString(*args.After)
+	if cursor.NextURL != "" {
+		opts.Cursor = &cursor.NextURL
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, locationQueryTimeout)
+	defer cancel()
+
+	locations, nextURL, partial, err := r.client.References(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.logQuery(ctx, "references", started, len(locations) == 0)
+
+	if nextURL != "" {
+		resolver.cursor = &referencesCursor{Upload: cursor.Upload, NextURL: nextURL}
+	}
+	resolver.partial = partial
+
+	// Resolving each location is expensive, so symbols with a huge number of
+	// references must not be resolved in full.
+	if max := conf.LSIFMaxReferences(); len(locations) > max {
+		resolver.truncated = true
+		resolver.countEstimate = len(locations)
+		locations = locations[:max]
+	}
+	resolver.locations = adjuster.AdjustLocations(locations)
+
+	return resolver, nil
+}
+
+func (r *lsifQueryResolver) ReferenceCount(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (int32, error) {
+	counts := make([]int32, len(r.uploads))
+	err := r.forEachUpload(ctx, func(ctx context.Context, i int, upload *lsif.LSIFUpload) error {
+		adjuster, err := newPositionAdjuster(ctx, r.repoID, string(r.commit), upload)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		path, line, character, ok := adjuster.AdjustPosition(r.path, args.Line, args.Character)
+		if !ok {
+			return nil
 		}
-		nextURL := string(decoded)
-		opts.Cursor = &nextURL
+
+		counts[i], err = r.client.ReferenceCount(ctx, &client.ReferenceCountOptions{
+			RepoID:    r.repoID,
+			Commit:    r.commit,
+			Path:      path,
+			Line:      line,
+			Character: character,
+			UploadID:  upload.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int32
+	for _, c := range counts {
+		count += c
 	}
+	return count, nil
+}
 
-	locations, nextURL, err := client.DefaultClient.References(ctx, opts)
+func (r *lsifQueryResolver) Hover(ctx context.Context, args *graphqlbackend.LSIFQueryHoverArgs) (graphqlbackend.HoverResolver, error) {
+	started := time.Now()
+
+	// The hover of the closest upload that has one is returned.
+	hovers := make([]*hoverResolver, len(r.uploads))
+	err := r.forEachUpload(ctx, func(ctx context.Context, i int, upload *lsif.LSIFUpload) error {
+		adjuster, err := newPositionAdjuster(ctx, r.repoID, string(r.commit), upload)
+		if err != nil {
+			return err
+		}
+
+		path, line, character, ok := adjuster.AdjustPosition(r.path, args.Line, args.Character)
+		if !ok {
+			return nil
+		}
+
+		text, lspRange, err := r.client.Hover(ctx, &client.HoverOptions{
+			RepoID:    r.repoID,
+			Commit:    r.commit,
+			Path:      path,
+			Line:      line,
+			Character: character,
+			UploadID:  upload.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, lspRange, ok = adjuster.AdjustRange(path, lspRange)
+		if !ok {
+			return nil
+		}
+
+		hovers[i] = &hoverResolver{
+			text:     text,
+			lspRange: lspRange,
+			format:   args.Format,
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &locationConnectionResolver{
-		locations: locations,
-		nextURL:   nextURL,
-	}, nil
+	for _, hover := range hovers {
+		if hover != nil {
+			r.logQuery(ctx, "hover", started, false)
+			return hover, nil
+		}
+	}
+	r.logQuery(ctx, "hover", started, true)
+	return nil, nil
 }
 
-func (r *lsifQueryResolver) Hover(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (graphqlbackend.HoverResolver, error) {
-	text, lspRange, err := client.DefaultClient.Hover(ctx, &struct {
-		RepoID    api.RepoID
-		Commit    graphqlbackend.GitObjectID
-		Path      string
-		Line      int32
-		Character int32
-		UploadID  int64
-	}{
-		RepoID:    r.repoID,
-		Commit:    r.commit,
-		Path:      r.path,
-		Line:      args.Line,
-		Character: args.Character,
-		UploadID:  r.upload.ID,
+func (r *lsifQueryResolver) Hovers(ctx context.Context, args *graphqlbackend.LSIFQueryHoversArgs) ([]graphqlbackend.HoverResolver, error) {
+	started := time.Now()
+
+	if len(args.Positions) > client.MaxHoverPositions {
+		return nil, fmt.Errorf("too many positions (%d), at most %d are allowed", len(args.Positions), client.MaxHoverPositions)
+	}
+
+	results := make([][]graphqlbackend.HoverResolver, len(r.uploads))
+	err := r.forEachUpload(ctx, func(ctx context.Context, i int, upload *lsif.LSIFUpload) (err error) {
+		results[i], err = r.uploadHovers(ctx, upload, args)
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &hoverResolver{
-		text:     text,
-		lspRange: lspRange,
-	}, nil
+	// The hover of each position is the one of the closest upload that has one.
+	resolvers := make([]graphqlbackend.HoverResolver, len(args.Positions))
+	empty := true
+	for i := range resolvers {
+		for _, result := range results {
+			if result[i] != nil {
+				resolvers[i] = result[i]
+				empty = false
+				break
+			}
+		}
+	}
+	r.logQuery(ctx, "hovers", started, empty)
+	return resolvers, nil
+}
+
+// uploadHovers returns the hovers of the given positions in a single upload.
+func (r *lsifQueryResolver) uploadHovers(ctx context.Context, upload *lsif.LSIFUpload, args *graphqlbackend.LSIFQueryHoversArgs) ([]graphqlbackend.HoverResolver, error) {
+	adjuster, err := newPositionAdjuster(ctx, r.repoID, string(r.commit), upload)
+	if err != nil {
+		return nil, err
+	}
+
+	// Positions on lines that changed since the upload's commit have no hover. The
+	// others are all queried at once, and indices maps them back to their argument.
+	resolvers := make([]graphqlbackend.HoverResolver, len(args.Positions))
+	opts := &client.HoversOptions{
+		RepoID:   r.repoID,
+		Commit:   r.commit,
+		Path:     r.path,
+		UploadID: upload.ID,
+	}
+	indices := make([]int, 0, len(args.Positions))
+	for i, p := range args.Positions {
+		path, line, character, ok := adjuster.AdjustPosition(r.path, p.Line, p.Character)
+		if !ok {
+			continue
+		}
+		opts.Path = path
+		opts.Positions = append(opts.Positions, lsp.Position{Line: int(line), Character: int(character)})
+		indices = append(indices, i)
+	}
+	if len(opts.Positions) == 0 {
+		return resolvers, nil
+	}
+
+	hovers, err := r.client.Hovers(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, hover := range hovers {
+		if hover == nil {
+			continue
+		}
+		_, lspRange, ok := adjuster.AdjustRange(opts.Path, hover.Range)
+		if !ok {
+			continue
+		}
+		resolvers[indices[i]] = &hoverResolver{
+			text:     hover.Text,
+			lspRange: lspRange,
+			format:   args.Format,
+		}
+	}
+
+	return resolvers, nil
 }
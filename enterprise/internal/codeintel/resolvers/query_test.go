@@ -0,0 +1,513 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const testCommit = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+
+// fakeClient answers the queries of each upload from canned results, keyed by
+// upload ID. The queries of the uploads in errs fail, and the definitions
+// queries of the uploads in blocked only return once they are canceled. The
+// deadlines of the definitions and references queries are sent to deadlines.
+type fakeClient struct {
+	definitions map[int64][]*lsif.LSIFLocation
+	// references maps uploads to their pages of references, keyed by the URL
+	// of the page, which is empty for the first one.
+	references map[int64]map[string]fakeReferencesPage
+	counts     map[int64]int32
+	hovers     map[int64][]*client.Hover
+	errs       map[int64]error
+	blocked    map[int64]chan error
+	deadlines  chan time.Time
+}
+
+type fakeReferencesPage struct {
+	locations []*lsif.LSIFLocation
+	nextURL   string
+}
+
+var _ codeintel.Client = &fakeClient{}
+
+func (c *fakeClient) Definitions(ctx context.Context, args *client.DefinitionsOptions) ([]*lsif.LSIFLocation, string, bool, error) {
+	if err := c.errs[args.UploadID]; err != nil {
+		return nil, "", false, err
+	}
+	if c.deadlines != nil {
+		deadline, _ := ctx.Deadline()
+		c.deadlines <- deadline
+	}
+	if done, ok := c.blocked[args.UploadID]; ok {
+		<-ctx.Done()
+		done <- ctx.Err()
+		return nil, "", false, ctx.Err()
+	}
+	return c.definitions[args.UploadID], "", false, nil
+}
+
+func (c *fakeClient) References(ctx context.Context, args *client.ReferencesOptions) ([]*lsif.LSIFLocation, string, bool, error) {
+	if err := c.errs[args.UploadID]; err != nil {
+		return nil, "", false, err
+	}
+	if c.deadlines != nil {
+		deadline, _ := ctx.Deadline()
+		c.deadlines <- deadline
+	}
+	var url string
+	if args.Cursor != nil {
+		url = *args.Cursor
+	}
+	page, ok := c.references[args.UploadID][url]
+	if !ok {
+		return nil, "", false, errors.New("unknown page " + url)
+	}
+	return page.locations, page.nextURL, false, nil
+}
+
+func (c *fakeClient) ReferenceCount(ctx context.Context, args *client.ReferenceCountOptions) (int32, error) {
+	if err := c.errs[args.UploadID]; err != nil {
+		return 0, err
+	}
+	return c.counts[args.UploadID], nil
+}
+
+func (c *fakeClient) Hover(ctx context.Context, args *client.HoverOptions) (string, lsp.Range, error) {
+	if err := c.errs[args.UploadID]; err != nil {
+		return "", lsp.Range{}, err
+	}
+	hovers := c.hovers[args.UploadID]
+	if len(hovers) == 0 || hovers[0] == nil {
+		return "", lsp.Range{}, nil
+	}
+	return hovers[0].Text, hovers[0].Range, nil
+}
+
+func (c *fakeClient) Hovers(ctx context.Context, args *client.HoversOptions) ([]*client.Hover, error) {
+	if err := c.errs[args.UploadID]; err != nil {
+		return nil, err
+	}
+	hovers := make([]*client.Hover, len(args.Positions))
+	copy(hovers, c.hovers[args.UploadID])
+	return hovers, nil
+}
+
+// newTestQueryResolver returns a resolver that queries the given uploads of
+// testCommit, so that no positions need to be adjusted.
+func newTestQueryResolver(c codeintel.Client, uploadIDs ...int64) *lsifQueryResolver {
+	uploads := make([]*lsif.LSIFUpload, 0, len(uploadIDs))
+	for _, id := range uploadIDs {
+		uploads = append(uploads, &lsif.LSIFUpload{ID: id, RepositoryID: 1, Commit: testCommit})
+	}
+	return &lsifQueryResolver{
+		client:  c,
+		repoID:  1,
+		commit:  testCommit,
+		path:    "main.go",
+		uploads: uploads,
+	}
+}
+
+// mockQueryConf mocks the given site configuration. Queries log an event,
+// which would need a database, so event logging is disabled.
+func mockQueryConf(site schema.SiteConfiguration) {
+	site.ExperimentalFeatures = &schema.ExperimentalFeatures{EventLogging: "disabled"}
+	conf.Mock(&conf.Unified{SiteConfiguration: site})
+}
+
+func testLocation(path string, line int) *lsif.LSIFLocation {
+	return &lsif.LSIFLocation{
+		RepositoryID: 1,
+		Commit:       testCommit,
+		Path:         path,
+		Range: lsp.Range{
+			Start: lsp.Position{Line: line},
+			End:   lsp.Position{Line: line, Character: 5},
+		},
+	}
+}
+
+func TestDefinitionsMergesUploads(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	a, b, c := testLocation("a.go", 1), testLocation("b.go", 2), testLocation("c.go", 3)
+	r := newTestQueryResolver(&fakeClient{
+		definitions: map[int64][]*lsif.LSIFLocation{
+			1: {a, b},
+			// The same location found by another upload is only returned once.
+			2: {testLocation("b.go", 2), c},
+		},
+	}, 1, 2)
+
+	resolver, err := r.Definitions(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{Line: 10, Character: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := resolver.(*locationConnectionResolver)
+	if want := []*lsif.LSIFLocation{a, b, c}; !reflect.DeepEqual(have.locations, want) {
+		t.Errorf("have locations %v, want %v", have.locations, want)
+	}
+	if have.partial {
+		t.Error("have partial results, want complete")
+	}
+}
+
+func TestReferencesPagesThroughUploads(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	a, b, c := testLocation("a.go", 1), testLocation("b.go", 2), testLocation("c.go", 3)
+	r := newTestQueryResolver(&fakeClient{
+		references: map[int64]map[string]fakeReferencesPage{
+			1: {
+				"":       {locations: []*lsif.LSIFLocation{a}, nextURL: "/page2"},
+				"/page2": {locations: []*lsif.LSIFLocation{b}},
+			},
+			2: {
+				"": {locations: []*lsif.LSIFLocation{c}},
+			},
+		},
+	}, 1, 2)
+
+	var (
+		after *string
+		pages [][]*lsif.LSIFLocation
+	)
+	for {
+		resolver, err := r.References(context.Background(), &graphqlbackend.LSIFPagedQueryPositionArgs{
+			LSIFQueryPositionArgs: graphqlbackend.LSIFQueryPositionArgs{Line: 10, Character: 5},
+			After:                 after,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		page := resolver.(*locationConnectionResolver)
+		pages = append(pages, page.locations)
+
+		info, err := page.PageInfo(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.HasNextPage() {
+			break
+		}
+		after = info.EndCursor()
+	}
+
+	if want := [][]*lsif.LSIFLocation{{a}, {b}, {c}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("have pages %v, want %v", pages, want)
+	}
+
+	for _, cursor := range []referencesCursor{{Upload: -1}, {Upload: 2}} {
+		b, err := json.Marshal(cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(b)
+		_, err = r.References(context.Background(), &graphqlbackend.LSIFPagedQueryPositionArgs{After: &encoded})
+		if err == nil {
+			t.Errorf("cursor %+v: want error for nonexistent upload", cursor)
+		}
+	}
+}
+
+func TestReferencesTruncated(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{LsifMaxReferences: 2})
+	defer conf.Mock(nil)
+
+	locations := []*lsif.LSIFLocation{testLocation("a.go", 1), testLocation("a.go", 2), testLocation("a.go", 3)}
+	r := newTestQueryResolver(&fakeClient{
+		references: map[int64]map[string]fakeReferencesPage{
+			1: {"": {locations: locations}},
+		},
+	}, 1)
+
+	resolver, err := r.References(context.Background(), &graphqlbackend.LSIFPagedQueryPositionArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := resolver.(*locationConnectionResolver)
+	if !reflect.DeepEqual(have.locations, locations[:2]) || !have.truncated || have.countEstimate != 3 {
+		t.Errorf("have locations %v, truncated %t and count estimate %d, want the first 2 of 3", have.locations, have.truncated, have.countEstimate)
+	}
+	if !have.Truncated() || have.CountEstimate() != 3 {
+		t.Errorf("have truncated %t and count estimate %d, want truncated with an estimate of 3", have.Truncated(), have.CountEstimate())
+	}
+}
+
+func TestReferencesNotTruncated(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{LsifMaxReferences: 3})
+	defer conf.Mock(nil)
+
+	locations := []*lsif.LSIFLocation{testLocation("a.go", 1), testLocation("a.go", 2), testLocation("a.go", 3)}
+	r := newTestQueryResolver(&fakeClient{
+		references: map[int64]map[string]fakeReferencesPage{
+			1: {"": {locations: locations}},
+		},
+	}, 1)
+
+	resolver, err := r.References(context.Background(), &graphqlbackend.LSIFPagedQueryPositionArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := resolver.(*locationConnectionResolver)
+	if !reflect.DeepEqual(have.locations, locations) || have.Truncated() || have.CountEstimate() != 3 {
+		t.Errorf("have locations %v, truncated %t and count estimate %d, want all 3", have.locations, have.Truncated(), have.CountEstimate())
+	}
+}
+
+func TestReferenceCountSumsUploads(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	r := newTestQueryResolver(&fakeClient{counts: map[int64]int32{1: 3, 2: 4}}, 1, 2)
+	count, err := r.ReferenceCount(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 7 {
+		t.Errorf("have count %d, want 7", count)
+	}
+}
+
+func TestHoversOfClosestUpload(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	r := newTestQueryResolver(&fakeClient{
+		hovers: map[int64][]*client.Hover{
+			1: {{Text: "closest"}, nil, nil},
+			2: {{Text: "farther"}, {Text: "only farther"}, nil},
+		},
+	}, 1, 2)
+
+	resolvers, err := r.Hovers(context.Background(), &graphqlbackend.LSIFQueryHoversArgs{
+		Positions: []graphqlbackend.LSIFQueryPositionArgs{{Line: 1}, {Line: 2}, {Line: 3}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var have []string
+	for _, resolver := range resolvers {
+		if resolver == nil {
+			have = append(have, "")
+			continue
+		}
+		have = append(have, resolver.(*hoverResolver).text)
+	}
+	if want := []string{"closest", "only farther", ""}; !reflect.DeepEqual(have, want) {
+		t.Errorf("have hovers %q, want %q", have, want)
+	}
+}
+
+func TestHoversTooManyPositions(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	r := newTestQueryResolver(&fakeClient{}, 1)
+
+	positions := make([]graphqlbackend.LSIFQueryPositionArgs, client.MaxHoverPositions+1)
+	if _, err := r.Hovers(context.Background(), &graphqlbackend.LSIFQueryHoversArgs{Positions: positions}); err == nil {
+		t.Error("have no error, want error for too many positions")
+	}
+	if _, err := r.Hovers(context.Background(), &graphqlbackend.LSIFQueryHoversArgs{Positions: positions[:client.MaxHoverPositions]}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewLSIFQueryResolverCapsUploads(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{LsifMaxUploadsPerQuery: 2})
+	defer conf.Mock(nil)
+
+	var uploads []*lsif.LSIFUpload
+	for _, id := range []int64{3, 1, 2} {
+		uploads = append(uploads, &lsif.LSIFUpload{ID: id, Commit: testCommit})
+	}
+
+	r := newLSIFQueryResolver(&fakeClient{}, 1, testCommit, "main.go", uploads)
+	if !reflect.DeepEqual(r.uploads, uploads[:2]) {
+		t.Errorf("have queried uploads %v, want the 2 closest", r.uploads)
+	}
+
+	var skipped []int64
+	for _, resolver := range r.SkippedUploads() {
+		skipped = append(skipped, resolver.(*lsifUploadResolver).lsifUpload.ID)
+	}
+	if want := []int64{2}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("have skipped uploads %v, want %v", skipped, want)
+	}
+
+	if r := newLSIFQueryResolver(&fakeClient{}, 1, testCommit, "main.go", nil); r != nil {
+		t.Error("want no resolver without uploads")
+	}
+}
+
+func TestIsExactCommit(t *testing.T) {
+	r := newTestQueryResolver(&fakeClient{}, 1, 2)
+	if !r.IsExactCommit() {
+		t.Error("have inexact commit with uploads of the requested commit, want exact")
+	}
+
+	// Results of an upload of a nearby commit may be stale.
+	r.uploads[1].Commit = "cafebabecafebabecafebabecafebabecafebabe"
+	if r.IsExactCommit() {
+		t.Error("have exact commit with an upload of another commit, want inexact")
+	}
+}
+
+func TestDefinitionsKeepUploadOrder(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	a, b, c := testLocation("a.go", 1), testLocation("b.go", 2), testLocation("c.go", 3)
+	definitions := map[int64][]*lsif.LSIFLocation{3: {a}, 1: {b}, 2: {c}}
+
+	t.Run("all uploads succeed", func(t *testing.T) {
+		r := newTestQueryResolver(&fakeClient{definitions: definitions}, 3, 1, 2)
+		resolver, err := r.Definitions(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The locations are in the order of the uploads, closest first, regardless
+		// of their IDs and of which upload answered first.
+		have := resolver.(*locationConnectionResolver)
+		if want := []*lsif.LSIFLocation{a, b, c}; !reflect.DeepEqual(have.locations, want) {
+			t.Errorf("have locations %v, want %v", have.locations, want)
+		}
+		if have.partial {
+			t.Error("have partial results, want complete")
+		}
+	})
+
+	t.Run("an upload fails", func(t *testing.T) {
+		boom := errors.New("boom")
+		r := newTestQueryResolver(&fakeClient{
+			definitions: definitions,
+			errs:        map[int64]error{1: boom},
+		}, 3, 1, 2)
+		if _, err := r.Definitions(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{}); err != boom {
+			t.Fatalf("have error %v, want %v", err, boom)
+		}
+	})
+}
+
+func TestReferenceCountOfFailedUpload(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	boom := errors.New("boom")
+	r := newTestQueryResolver(&fakeClient{
+		counts: map[int64]int32{1: 3, 2: 4},
+		errs:   map[int64]error{2: boom},
+	}, 1, 2)
+	if _, err := r.ReferenceCount(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{}); err != boom {
+		t.Fatalf("have error %v, want %v", err, boom)
+	}
+}
+
+func TestDefinitionsAbortOtherUploads(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	t.Run("an upload fails", func(t *testing.T) {
+		boom := errors.New("boom")
+		blocked := make(chan error, 2)
+		r := newTestQueryResolver(&fakeClient{
+			errs:    map[int64]error{2: boom},
+			blocked: map[int64]chan error{1: blocked, 3: blocked},
+		}, 1, 2, 3)
+
+		// The queries of the other uploads are canceled, so Definitions returns.
+		if _, err := r.Definitions(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{}); err != boom {
+			t.Fatalf("have error %v, want %v", err, boom)
+		}
+		for i := 0; i < 2; i++ {
+			if err := <-blocked; err != context.Canceled {
+				t.Errorf("have error %v for a blocked upload, want %v", err, context.Canceled)
+			}
+		}
+	})
+
+	t.Run("the client went away", func(t *testing.T) {
+		blocked := make(chan error, 2)
+		r := newTestQueryResolver(&fakeClient{
+			blocked: map[int64]chan error{1: blocked, 2: blocked},
+		}, 1, 2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := r.Definitions(ctx, &graphqlbackend.LSIFQueryPositionArgs{}); err != context.Canceled {
+			t.Fatalf("have error %v, want %v", err, context.Canceled)
+		}
+		if len(blocked) != 0 {
+			t.Error("uploads were queried after the client went away")
+		}
+	})
+}
+
+func TestLocationQueriesGetTheWholeBudget(t *testing.T) {
+	mockQueryConf(schema.SiteConfiguration{})
+	defer conf.Mock(nil)
+
+	t.Run("definitions", func(t *testing.T) {
+		deadlines := make(chan time.Time, 3)
+		r := newTestQueryResolver(&fakeClient{deadlines: deadlines}, 1, 2, 3)
+
+		started := time.Now()
+		if _, err := r.Definitions(context.Background(), &graphqlbackend.LSIFQueryPositionArgs{}); err != nil {
+			t.Fatal(err)
+		}
+		finished := time.Now()
+
+		// The uploads are queried concurrently, so they all have the deadline of
+		// the query instead of a share of its budget.
+		first := <-deadlines
+		if first.Before(started.Add(locationQueryTimeout)) || first.After(finished.Add(locationQueryTimeout)) {
+			t.Errorf("have budget %s, want %s", first.Sub(started), locationQueryTimeout)
+		}
+		for i := 0; i < 2; i++ {
+			if deadline := <-deadlines; !deadline.Equal(first) {
+				t.Errorf("have deadline %s, want the deadline %s of the query", deadline, first)
+			}
+		}
+	})
+
+	t.Run("references", func(t *testing.T) {
+		deadlines := make(chan time.Time, 1)
+		r := newTestQueryResolver(&fakeClient{
+			references: map[int64]map[string]fakeReferencesPage{1: {"": {}}},
+			deadlines:  deadlines,
+		}, 1, 2)
+
+		started := time.Now()
+		if _, err := r.References(context.Background(), &graphqlbackend.LSIFPagedQueryPositionArgs{}); err != nil {
+			t.Fatal(err)
+		}
+		finished := time.Now()
+
+		// A page of references is of a single upload.
+		if deadline := <-deadlines; deadline.Before(started.Add(locationQueryTimeout)) || deadline.After(finished.Add(locationQueryTimeout)) {
+			t.Errorf("have budget %s, want %s", deadline.Sub(started), locationQueryTimeout)
+		}
+	})
+}
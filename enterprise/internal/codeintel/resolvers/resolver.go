@@ -3,56 +3,103 @@ package resolvers
 import (
 	"context"
 	"encoding/base64"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
 )
 
-type Resolver struct{}
+type Resolver struct {
+	// client answers the queries of the LSIF query resolvers.
+	client codeintel.Client
+}
 
 var _ graphqlbackend.CodeIntelResolver = &Resolver{}
 
 func NewResolver() graphqlbackend.CodeIntelResolver {
-	return &Resolver{}
+	return &Resolver{client: client.DefaultClient}
 }
 
 func (r *Resolver) LSIFUploadByID(ctx context.Context, id graphql.ID) (graphqlbackend.LSIFUploadResolver, error) {
+	// 🚨 SECURITY: Restricted access tokens need the "codeintel:read" scope.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCodeIntelRead); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
 	uploadID, err := unmarshalLSIFUploadGQLID(id)
 	if err != nil {
 		return nil, err
 	}
 
+	lsifUpload, err := getUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lsifUploadResolver{lsifUpload: lsifUpload}, nil
+}
+
+// getUpload returns the upload with the given ID. If the current user can't
+// read its repository, ErrLSIFUploadNotFound is returned, as if the upload
+// didn't exist.
+func getUpload(ctx context.Context, uploadID int64) (*lsif.LSIFUpload, error) {
 	lsifUpload, err := client.DefaultClient.GetUpload(ctx, &struct {
 		UploadID int64
 	}{
 		UploadID: uploadID,
 	})
+	if client.IsNotFound(err) {
+		return nil, &ErrLSIFUploadNotFound{ID: uploadID}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &lsifUploadResolver{lsifUpload: lsifUpload}, nil
+	// 🚨 SECURITY: Only uploads of repositories the user can read are visible.
+	uploads, err := filterUploadsByPerms(ctx, []*lsif.LSIFUpload{lsifUpload}, authz.Read)
+	if err != nil {
+		return nil, err
+	}
+	if len(uploads) == 0 {
+		return nil, &ErrLSIFUploadNotFound{ID: uploadID}
+	}
+
+	return lsifUpload, nil
 }
 
 func (r *Resolver) DeleteLSIFUpload(ctx context.Context, id graphql.ID) (*graphqlbackend.EmptyResponse, error) {
-	// 🚨 SECURITY: Only site admins may delete LSIF data for now
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+	uploadID, err := unmarshalLSIFUploadGQLID(id)
+	if err != nil {
 		return nil, err
 	}
 
-	uploadID, err := unmarshalLSIFUploadGQLID(id)
+	lsifUpload, err := getUpload(ctx, uploadID)
 	if err != nil {
 		return nil, err
 	}
 
+	// 🚨 SECURITY: Only site admins and users with write permissions on the
+	// repository may delete LSIF data
+	if err := checkCanManageUpload(ctx, lsifUpload); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
 	err = client.DefaultClient.DeleteUpload(ctx, &struct {
 		UploadID int64
 	}{
 		UploadID: uploadID,
 	})
+	if client.IsNotFound(err) {
+		return nil, &ErrLSIFUploadNotFound{ID: uploadID}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +107,45 @@ func (r *Resolver) DeleteLSIFUpload(ctx context.Context, id graphql.ID) (*graphq
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
-// LSIFUploads resolves the LSIF uploads in a given state.
+func (r *Resolver) RetryLSIFUpload(ctx context.Context, id graphql.ID) (graphqlbackend.LSIFUploadResolver, error) {
+	uploadID, err := unmarshalLSIFUploadGQLID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lsifUpload, err := getUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and users with write permissions on the
+	// repository may retry LSIF uploads
+	if err := checkCanManageUpload(ctx, lsifUpload); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	err = client.DefaultClient.RetryUpload(ctx, &struct {
+		UploadID int64
+	}{
+		UploadID: uploadID,
+	})
+	if client.IsNotFound(err) {
+		return nil, &ErrLSIFUploadNotFound{ID: uploadID}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lsifUpload, err = getUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lsifUploadResolver{lsifUpload: lsifUpload}, nil
+}
+
+// LSIFUploads resolves the LSIF uploads in a given state. If no repository is
+// given, the uploads of all repositories are resolved.
 //
 // This method implements cursor-based forward pagination. The `after` parameter
 // should be an `endCursor` value from a previous request. This value is the rel="next"
@@ -69,12 +154,31 @@ func (r *Resolver) DeleteLSIFUpload(ctx context.Context, id graphql.ID) (*graphq
 // dependent on the limit, so we can overwrite this value if the user has changed its
 // value since making the last request.
 func (r *Resolver) LSIFUploads(ctx context.Context, args *graphqlbackend.LSIFRepositoryUploadsQueryArgs) (graphqlbackend.LSIFUploadConnectionResolver, error) {
+	// 🚨 SECURITY: Restricted access tokens need the "codeintel:read" scope.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCodeIntelRead); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
 	opt := LSIFUploadsListOptions{
 		RepositoryID:    args.RepositoryID,
 		Query:           args.Query,
 		State:           args.State,
 		IsLatestForRepo: args.IsLatestForRepo,
 	}
+
+	// 🚨 SECURITY: Only site admins may list the LSIF uploads of all
+	// repositories. Other users only see the uploads of repositories they can
+	// read. The uploads of a single repository are visible to all users who
+	// can read it.
+	if args.RepositoryID == "" {
+		switch err := backend.CheckCurrentUserIsSiteAdmin(ctx); err {
+		case nil:
+		case backend.ErrMustBeSiteAdmin:
+			opt.FilterByPerms = true
+		default:
+			return nil, backend.WithPermissionDeniedCode(err)
+		}
+	}
 	if args.First != nil {
 		opt.Limit = args.First
 	}
@@ -91,28 +195,33 @@ func (r *Resolver) LSIFUploads(ctx context.Context, args *graphqlbackend.LSIFRep
 }
 
 func (r *Resolver) LSIF(ctx context.Context, args *graphqlbackend.LSIFQueryArgs) (graphqlbackend.LSIFQueryResolver, error) {
-	upload, err := client.DefaultClient.Exists(ctx, &struct {
-		RepoID api.RepoID
-		Commit string
-		Path   string
+	// 🚨 SECURITY: Restricted access tokens need the "codeintel:read" scope.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCodeIntelRead); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	started := time.Now()
+
+	repo := args.Repository.Type()
+	uploads, err := client.DefaultClient.ExistsAll(ctx, &struct {
+		RepoID            api.RepoID
+		Commit            string
+		Path              string
+		MaxCommitDistance int
 	}{
-		RepoID: args.Repository.Type().ID,
-		Commit: string(args.Commit),
-		Path:   args.Path,
+		RepoID:            repo.ID,
+		Commit:            string(args.Commit),
+		Path:              args.Path,
+		MaxCommitDistance: conf.LSIFMaxCommitDistance(repo.Name),
 	})
-
 	if err != nil {
 		return nil, err
 	}
 
-	if upload == nil {
+	resolver := newLSIFQueryResolver(r.client, repo.ID, args.Commit, args.Path, uploads)
+	if resolver == nil {
+		logQuery(ctx, &types.CodeIntelQuery{Operation: "lsif", CodePath: types.CodeIntelPathFallback}, args.Path, started)
 		return nil, nil
 	}
-
-	return &lsifQueryResolver{
-		repoID: args.Repository.Type().ID,
-		commit: args.Commit,
-		path:   args.Path,
-		upload: upload,
-	}, nil
+	return resolver, nil
 }
@@ -0,0 +1,157 @@
+package resolvers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// mockCurrentUser makes the returned context that of the given user, or of an
+// anonymous user if it is nil.
+func mockCurrentUser(user *types.User) context.Context {
+	if user == nil {
+		db.Mocks.Users.GetByCurrentAuthUser = nil
+		return context.Background()
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return user, nil
+	}
+	return actor.WithActor(context.Background(), &actor.Actor{UID: user.ID})
+}
+
+func TestLSIFUploadsOfAllRepositories(t *testing.T) {
+	defer func() { db.Mocks.Users = db.MockUsers{} }()
+
+	for _, tc := range []struct {
+		name              string
+		user              *types.User
+		repositoryID      string
+		wantFilterByPerms bool
+		wantErr           bool
+	}{
+		{name: "site admin", user: &types.User{ID: 1, SiteAdmin: true}},
+		{name: "user", user: &types.User{ID: 2}, wantFilterByPerms: true},
+		{name: "anonymous", wantErr: true},
+		// The uploads of a repository are checked when the repository is
+		// resolved.
+		{name: "user with repository", user: &types.User{ID: 2}, repositoryID: "UmVwb3NpdG9yeTox"},
+		{name: "anonymous with repository", repositoryID: "UmVwb3NpdG9yeTox"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := mockCurrentUser(tc.user)
+
+			conn, err := NewResolver().LSIFUploads(ctx, &graphqlbackend.LSIFRepositoryUploadsQueryArgs{
+				LSIFUploadsQueryArgs: &graphqlbackend.LSIFUploadsQueryArgs{},
+				RepositoryID:         graphql.ID(tc.repositoryID),
+			})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("have no error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if have := conn.(*lsifUploadConnectionResolver).opt.FilterByPerms; have != tc.wantFilterByPerms {
+				t.Errorf("have FilterByPerms %v, want %v", have, tc.wantFilterByPerms)
+			}
+		})
+	}
+}
+
+func TestRetryLSIFUpload(t *testing.T) {
+	retries := make(chan int64, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/uploads/1/retry":
+			retries <- 1
+		case r.Method == "GET" && r.URL.Path == "/uploads/1":
+			_, _ = io.WriteString(w, `{"id": 1, "repositoryId": 50, "state": "errored"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	defaultClient := client.DefaultClient
+	client.DefaultClient = &client.Client{URL: ts.URL, HTTPClient: ts.Client()}
+	defer func() { client.DefaultClient = defaultClient }()
+
+	defer func() {
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.Repos = db.MockRepos{}
+		db.MockAuthzFilter = nil
+	}()
+	db.Mocks.Repos.GetByIDs = func(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error) {
+		repos := make([]*types.Repo, 0, len(ids))
+		for _, id := range ids {
+			repos = append(repos, &types.Repo{ID: id})
+		}
+		return repos, nil
+	}
+
+	// Users can read all repositories, and write to repositories with writable
+	// set.
+	writable := false
+	db.MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		if p == authz.Write && !writable {
+			return nil, nil
+		}
+		return repos, nil
+	}
+
+	for _, tc := range []struct {
+		name      string
+		user      *types.User
+		writable  bool
+		uploadID  int64
+		wantRetry bool
+		wantErr   bool
+	}{
+		{name: "site admin", user: &types.User{ID: 1, SiteAdmin: true}, uploadID: 1, wantRetry: true},
+		{name: "user with write permissions", user: &types.User{ID: 2}, writable: true, uploadID: 1, wantRetry: true},
+		{name: "user without write permissions", user: &types.User{ID: 2}, uploadID: 1, wantErr: true},
+		{name: "anonymous", uploadID: 1, wantErr: true},
+		{name: "unknown upload", user: &types.User{ID: 1, SiteAdmin: true}, uploadID: 2, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := mockCurrentUser(tc.user)
+			writable = tc.writable
+
+			upload, err := NewResolver().RetryLSIFUpload(ctx, marshalLSIFUploadGQLID(tc.uploadID))
+			if tc.wantErr {
+				if err == nil {
+					t.Error("have no error, want error")
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if have, want := upload.ID(), marshalLSIFUploadGQLID(tc.uploadID); have != want {
+				t.Errorf("have upload %q, want %q", have, want)
+			}
+
+			select {
+			case <-retries:
+				if !tc.wantRetry {
+					t.Error("have upload retried, want not retried")
+				}
+			default:
+				if tc.wantRetry {
+					t.Error("have upload not retried, want retried")
+				}
+			}
+		})
+	}
+}
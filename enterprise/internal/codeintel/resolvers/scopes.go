@@ -0,0 +1,63 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func init() {
+	// 🚨 SECURITY: Access tokens with the codeintel:read scope may only be used to query code
+	// intelligence. The resolvers of the LSIF fields check the scope.
+	graphqlbackend.RegisterScopedFields(authz.ScopeCodeIntelRead, codeIntelReadFields...)
+}
+
+// codeIntelReadFields are the GraphQL fields that actors restricted to the codeintel:read access
+// token scope may resolve, see graphqlbackend.RegisterScopedFields.
+var codeIntelReadFields = []string{
+	"Query.lsifUploads",
+
+	// The code intelligence of a file is queried through its repository and commit, of which
+	// only the names are allowed.
+	"Query.repository",
+	"Repository.id",
+	"Repository.name",
+	"Repository.url",
+	"Repository.commit",
+	"Repository.lsifUploads",
+	"Repository.lsifCoverage",
+	"Repository.codeIntelSupport",
+	"GitCommit.id",
+	"GitCommit.oid",
+	"GitCommit.abbreviatedOID",
+	"GitCommit.url",
+	"GitCommit.blob",
+	"GitBlob.path",
+	"GitBlob.name",
+	"GitBlob.url",
+	"GitBlob.commit",
+	"GitBlob.repository",
+	"GitBlob.lsif",
+	"GitTree.path",
+	"GitTree.name",
+	"GitTree.url",
+
+	"CodeIntelLanguageSupport",
+	"Hover",
+	"HoverContent",
+	"Location",
+	"LocationConnection",
+	"LocationContent",
+	"LocationFileGroup",
+	"LocationRepositoryGroup",
+	"LSIFCoverage",
+	"LSIFCoverageEntry",
+	"LSIFQueryResolver",
+	"LSIFUpload",
+	"LSIFUploadConnection",
+	"LSIFUploadCoverage",
+	"LSIFUploadFailureReason",
+	"Markdown",
+	"PageInfo",
+	"Position",
+	"Range",
+}
@@ -0,0 +1,14 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func TestCodeIntelReadFields(t *testing.T) {
+	// Creating the schema fails if a field of a scope is not in the schema.
+	if _, err := graphqlbackend.NewSchema(nil, NewResolver(), nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/src-d/enry/v2"
+)
+
+// lsifIndexers maps the languages for which precise code intelligence can be
+// produced to the name of their LSIF indexer.
+var lsifIndexers = map[string]string{
+	"C":          "lsif-cpp",
+	"C++":        "lsif-cpp",
+	"Go":         "lsif-go",
+	"Java":       "lsif-java",
+	"JavaScript": "lsif-node",
+	"Python":     "lsif-py",
+	"TypeScript": "lsif-node",
+}
+
+// CodeIntelSupport resolves the code intelligence support of each language of
+// the given repository at the tip of its default branch.
+func (r *Resolver) CodeIntelSupport(ctx context.Context, repoResolver *graphqlbackend.RepositoryResolver) ([]graphqlbackend.CodeIntelLanguageSupportResolver, error) {
+	// 🚨 SECURITY: Restricted access tokens need the "codeintel:read" scope.
+	if err := backend.CheckActorHasScope(ctx, authz.ScopeCodeIntelRead); err != nil {
+		return nil, backend.WithPermissionDeniedCode(err)
+	}
+
+	support, err := codeIntelSupport(ctx, repoResolver.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.CodeIntelLanguageSupportResolver, 0, len(support))
+	for _, s := range support {
+		resolvers = append(resolvers, s)
+	}
+	return resolvers, nil
+}
+
+// codeIntelSupport returns the code intelligence support of each language of
+// the given repository at the tip of its default branch.
+//
+// The LSIF server has no notion of languages, so for each language we look
+// for an upload that covers one of its files.
+func codeIntelSupport(ctx context.Context, repo *types.Repo) ([]*codeIntelLanguageSupportResolver, error) {
+	commitID, err := backend.Repos.ResolveRev(ctx, repo, "")
+	if err != nil {
+		if gitserver.IsRevisionNotFound(err) {
+			return []*codeIntelLanguageSupportResolver{}, nil
+		}
+		return nil, err
+	}
+
+	inventory, err := backend.Repos.GetInventory(ctx, repo, commitID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := languageSamplePaths(ctx, *cachedRepo, commitID)
+	if err != nil {
+		return nil, err
+	}
+
+	support := make([]*codeIntelLanguageSupportResolver, 0, len(inventory.Languages))
+	for _, l := range inventory.Languages {
+		s := &codeIntelLanguageSupportResolver{language: l.Name}
+		if indexer, ok := lsifIndexers[l.Name]; ok {
+			s.indexer = &indexer
+		}
+
+		if path, ok := paths[l.Name]; ok {
+			upload, err := client.DefaultClient.Exists(ctx, &struct {
+				RepoID            api.RepoID
+				Commit            string
+				Path              string
+				MaxCommitDistance int
+			}{
+				RepoID:            repo.ID,
+				Commit:            string(commitID),
+				Path:              path,
+				MaxCommitDistance: conf.LSIFMaxCommitDistance(repo.Name),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if upload != nil {
+				s.upload = upload
+
+				count, err := git.CommitCount(ctx, *cachedRepo, git.CommitsOptions{
+					Range: upload.Commit + ".." + string(commitID),
+				})
+				if err != nil {
+					return nil, err
+				}
+				behind := int32(count)
+				s.commitsBehind = &behind
+			}
+		}
+
+		support = append(support, s)
+	}
+
+	return support, nil
+}
+
+// languageSamplePaths returns a path of a file in the given commit for each
+// language that can be detected by the file's name.
+func languageSamplePaths(ctx context.Context, repo gitserver.Repo, commitID api.CommitID) (map[string]string, error) {
+	entries, err := git.ReadDir(ctx, repo, commitID, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	for _, e := range entries {
+		if !e.Mode().IsRegular() || enry.IsVendor(e.Name()) {
+			continue
+		}
+
+		language, _ := enry.GetLanguageByExtension(e.Name())
+		if _, ok := paths[language]; language != "" && !ok {
+			paths[language] = e.Name()
+		}
+	}
+
+	return paths, nil
+}
+
+type codeIntelLanguageSupportResolver struct {
+	language      string
+	indexer       *string
+	upload        *lsif.LSIFUpload
+	commitsBehind *int32
+}
+
+var _ graphqlbackend.CodeIntelLanguageSupportResolver = &codeIntelLanguageSupportResolver{}
+
+func (r *codeIntelLanguageSupportResolver) Language() string {
+	return r.language
+}
+
+func (r *codeIntelLanguageSupportResolver) Indexer() *string {
+	return r.indexer
+}
+
+func (r *codeIntelLanguageSupportResolver) Upload() graphqlbackend.LSIFUploadResolver {
+	if r.upload == nil {
+		return nil
+	}
+
+	return &lsifUploadResolver{lsifUpload: r.upload}
+}
+
+func (r *codeIntelLanguageSupportResolver) CommitsBehind() *int32 {
+	return r.commitsBehind
+}
+
+func (r *codeIntelLanguageSupportResolver) SearchBased() bool {
+	return r.upload == nil
+}
@@ -0,0 +1,91 @@
+package resolvers
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+func TestLanguageSamplePaths(t *testing.T) {
+	git.Mocks.ReadDir = func(commit api.CommitID, name string, recurse bool) ([]os.FileInfo, error) {
+		if commit != testCommit || name != "" || !recurse {
+			t.Errorf("have ReadDir(%q, %q, %t), want the whole tree of %q", commit, name, recurse, testCommit)
+		}
+		return []os.FileInfo{
+			&util.FileInfo{Name_: "cmd", Mode_: os.ModeDir},
+			&util.FileInfo{Name_: "cmd/main.go"},
+			&util.FileInfo{Name_: "cmd/util.go"},
+			&util.FileInfo{Name_: "node_modules/lib/index.ts"},
+			&util.FileInfo{Name_: "web/index.ts"},
+			&util.FileInfo{Name_: "web/link.ts", Mode_: os.ModeSymlink},
+			&util.FileInfo{Name_: "README"},
+		}, nil
+	}
+	defer git.ResetMocks()
+
+	paths, err := languageSamplePaths(context.Background(), gitserver.Repo{Name: "r"}, testCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Vendored files, symlinks and files of unknown languages are skipped.
+	if want := map[string]string{"Go": "cmd/main.go", "TypeScript": "web/index.ts"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("have paths %v, want %v", paths, want)
+	}
+}
+
+func TestCodeIntelSupportOfEmptyRepository(t *testing.T) {
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return "", &gitserver.RevisionNotFoundError{Repo: repo.Name, Spec: rev}
+	}
+	defer func() { backend.Mocks = backend.MockServices{} }()
+
+	support, err := codeIntelSupport(context.Background(), &types.Repo{ID: 50, Name: "r"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(support) != 0 {
+		t.Errorf("have support %v, want none", support)
+	}
+}
+
+func TestCodeIntelLanguageSupportResolver(t *testing.T) {
+	indexer := "lsif-go"
+	behind := int32(3)
+
+	precise := &codeIntelLanguageSupportResolver{
+		language:      "Go",
+		indexer:       &indexer,
+		upload:        &lsif.LSIFUpload{ID: 7},
+		commitsBehind: &behind,
+	}
+	if precise.SearchBased() {
+		t.Error("have search-based support with an upload, want precise")
+	}
+	if upload := precise.Upload(); upload == nil || upload.ID() != marshalLSIFUploadGQLID(7) {
+		t.Errorf("have upload %v, want upload 7", upload)
+	}
+	if have := precise.CommitsBehind(); have == nil || *have != 3 {
+		t.Errorf("have commits behind %v, want 3", have)
+	}
+
+	searchBased := &codeIntelLanguageSupportResolver{language: "Haskell"}
+	if !searchBased.SearchBased() {
+		t.Error("have precise support without an upload, want search-based")
+	}
+	if upload := searchBased.Upload(); upload != nil {
+		t.Errorf("have upload %v, want none", upload)
+	}
+	if searchBased.Indexer() != nil || searchBased.CommitsBehind() != nil {
+		t.Errorf("have indexer %v and commits behind %v, want none", searchBased.Indexer(), searchBased.CommitsBehind())
+	}
+}
@@ -10,14 +10,15 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
-// resolveRepository returns a repository resolver for the given name.
+// resolveRepository returns a repository resolver for the given ID. Repositories are
+// cached for the duration of the GraphQL request.
 func resolveRepository(ctx context.Context, repoID api.RepoID) (*graphqlbackend.RepositoryResolver, error) {
-	repo, err := backend.Repos.Get(ctx, repoID)
+	repos, err := graphqlbackend.LoadRepositories(ctx, repoID)
 	if err != nil {
 		return nil, err
 	}
 
-	return graphqlbackend.NewRepositoryResolver(repo), nil
+	return repos[0], nil
 }
 
 // resolveCommit returns the GitCommitResolver for the given repository and commit. If the
@@ -9,6 +9,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsifserver/client"
@@ -96,6 +97,10 @@ type LSIFUploadsListOptions struct {
 	IsLatestForRepo *bool
 	Limit           *int32
 	NextURL         *string
+
+	// FilterByPerms is whether only the uploads of repositories that the
+	// current user can read are resolved.
+	FilterByPerms bool
 }
 
 type lsifUploadConnectionResolver struct {
@@ -153,9 +158,14 @@ func (r *lsifUploadConnectionResolver) PageInfo(ctx context.Context) (*graphqlut
 
 func (r *lsifUploadConnectionResolver) compute(ctx context.Context) ([]*lsif.LSIFUpload, *graphqlbackend.RepositoryResolver, *int, string, error) {
 	r.once.Do(func() {
-		r.repositoryResolver, r.err = graphqlbackend.RepositoryByID(ctx, r.opt.RepositoryID)
-		if r.err != nil {
-			return
+		// The uploads of all repositories are listed if no repository is given.
+		var repoID api.RepoID
+		if r.opt.RepositoryID != "" {
+			r.repositoryResolver, r.err = graphqlbackend.RepositoryByID(ctx, r.opt.RepositoryID)
+			if r.err != nil {
+				return
+			}
+			repoID = r.repositoryResolver.Type().ID
 		}
 
 		r.uploads, r.nextURL, r.totalCount, r.err = client.DefaultClient.GetUploads(ctx, &struct {
@@ -166,13 +176,21 @@ func (r *lsifUploadConnectionResolver) compute(ctx context.Context) ([]*lsif.LSI
 			Limit           *int32
 			Cursor          *string
 		}{
-			RepoID:          r.repositoryResolver.Type().ID,
+			RepoID:          repoID,
 			Query:           r.opt.Query,
 			State:           r.opt.State,
 			IsLatestForRepo: r.opt.IsLatestForRepo,
 			Limit:           r.opt.Limit,
 			Cursor:          r.opt.NextURL,
 		})
+
+		// 🚨 SECURITY: The uploads of each page are filtered, so pages can have
+		// fewer uploads than the limit. The total count would reveal the number
+		// of uploads of other repositories, so it's omitted.
+		if r.opt.FilterByPerms && r.err == nil {
+			r.uploads, r.err = filterUploadsByPerms(ctx, r.uploads, authz.Read)
+			r.totalCount = nil
+		}
 	})
 
 	return r.uploads, r.repositoryResolver, r.totalCount, r.nextURL, r.err
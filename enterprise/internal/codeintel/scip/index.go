@@ -0,0 +1,267 @@
+// Package scip reads indexes in the SCIP Code Intelligence Protocol format
+// (https://github.com/sourcegraph/scip) and converts them to LSIF, so that
+// they are processed like the dumps of LSIF indexers.
+package scip
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Metadata is the metadata of an index.
+type Metadata struct {
+	Version     int32
+	ToolInfo    ToolInfo
+	ProjectRoot string
+}
+
+// ToolInfo describes the indexer that produced an index.
+type ToolInfo struct {
+	Name      string
+	Version   string
+	Arguments []string
+}
+
+// Document is a source file of an index with the occurrences of symbols in it.
+type Document struct {
+	RelativePath string
+	Language     string
+	Occurrences  []*Occurrence
+	Symbols      []*SymbolInformation
+}
+
+// Occurrence is a range of a document that refers to a symbol.
+type Occurrence struct {
+	// Range is [startLine, startCharacter, endLine, endCharacter], or
+	// [startLine, startCharacter, endCharacter] if the range is on one line.
+	Range       []int32
+	Symbol      string
+	SymbolRoles int32
+}
+
+// Symbol roles of an occurrence, which are a bit set.
+const (
+	SymbolRoleDefinition int32 = 1 << iota
+	SymbolRoleImport
+	SymbolRoleWriteAccess
+	SymbolRoleReadAccess
+)
+
+// IsDefinition returns true if the occurrence defines its symbol.
+func (o *Occurrence) IsDefinition() bool { return o.SymbolRoles&SymbolRoleDefinition != 0 }
+
+// SymbolInformation documents a symbol.
+type SymbolInformation struct {
+	Symbol        string
+	Documentation []string
+}
+
+// An IndexVisitor is called with the parts of an index in the order they
+// appear in it.
+type IndexVisitor struct {
+	Metadata       func(*Metadata) error
+	Document       func(*Document) error
+	ExternalSymbol func(*SymbolInformation) error
+}
+
+// Fields of the messages of the SCIP protocol, see
+// https://github.com/sourcegraph/scip/blob/main/scip.proto.
+const (
+	indexMetadata        = 1
+	indexDocuments       = 2
+	indexExternalSymbols = 3
+
+	metadataVersion     = 1
+	metadataToolInfo    = 2
+	metadataProjectRoot = 3
+
+	toolInfoName      = 1
+	toolInfoVersion   = 2
+	toolInfoArguments = 3
+
+	documentRelativePath = 1
+	documentOccurrences  = 2
+	documentSymbols      = 3
+	documentLanguage     = 4
+
+	occurrenceRange       = 1
+	occurrenceSymbol      = 2
+	occurrenceSymbolRoles = 3
+
+	symbolInformationSymbol        = 1
+	symbolInformationDocumentation = 3
+)
+
+// ReadIndex reads the encoded index from r and calls v with its parts. The
+// index is streamed, so that only one document is held in memory at once.
+func ReadIndex(r io.Reader, v IndexVisitor) error {
+	br := bufio.NewReader(r)
+	for {
+		field, value, err := readTopLevelField(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading SCIP index")
+		}
+
+		switch field {
+		case indexMetadata:
+			m, err := decodeMetadata(value)
+			if err != nil {
+				return errors.Wrap(err, "decoding SCIP metadata")
+			}
+			if err := v.Metadata(m); err != nil {
+				return err
+			}
+
+		case indexDocuments:
+			d, err := decodeDocument(value)
+			if err != nil {
+				return errors.Wrap(err, "decoding SCIP document")
+			}
+			if err := v.Document(d); err != nil {
+				return err
+			}
+
+		case indexExternalSymbols:
+			s, err := decodeSymbolInformation(value)
+			if err != nil {
+				return errors.Wrap(err, "decoding SCIP external symbol")
+			}
+			if err := v.ExternalSymbol(s); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeMetadata(b []byte) (*Metadata, error) {
+	var m Metadata
+	r := messageReader{buf: b}
+	for {
+		field, wireType, ok := r.next()
+		if !ok {
+			break
+		}
+		switch {
+		case field == metadataVersion && wireType == wireVarint:
+			m.Version = int32(r.varint())
+		case field == metadataToolInfo && wireType == wireBytes:
+			if b := r.bytes(); r.err == nil {
+				m.ToolInfo, r.err = decodeToolInfo(b)
+			}
+		case field == metadataProjectRoot && wireType == wireBytes:
+			m.ProjectRoot = r.string()
+		default:
+			r.skip(wireType)
+		}
+	}
+	return &m, r.err
+}
+
+func decodeToolInfo(b []byte) (ToolInfo, error) {
+	var t ToolInfo
+	r := messageReader{buf: b}
+	for {
+		field, wireType, ok := r.next()
+		if !ok {
+			break
+		}
+		switch {
+		case field == toolInfoName && wireType == wireBytes:
+			t.Name = r.string()
+		case field == toolInfoVersion && wireType == wireBytes:
+			t.Version = r.string()
+		case field == toolInfoArguments && wireType == wireBytes:
+			t.Arguments = append(t.Arguments, r.string())
+		default:
+			r.skip(wireType)
+		}
+	}
+	return t, r.err
+}
+
+func decodeDocument(b []byte) (*Document, error) {
+	var d Document
+	r := messageReader{buf: b}
+	for {
+		field, wireType, ok := r.next()
+		if !ok {
+			break
+		}
+		switch {
+		case field == documentRelativePath && wireType == wireBytes:
+			d.RelativePath = r.string()
+		case field == documentLanguage && wireType == wireBytes:
+			d.Language = r.string()
+		case field == documentOccurrences && wireType == wireBytes:
+			if b := r.bytes(); r.err == nil {
+				var o *Occurrence
+				if o, r.err = decodeOccurrence(b); r.err == nil {
+					d.Occurrences = append(d.Occurrences, o)
+				}
+			}
+		case field == documentSymbols && wireType == wireBytes:
+			if b := r.bytes(); r.err == nil {
+				var s *SymbolInformation
+				if s, r.err = decodeSymbolInformation(b); r.err == nil {
+					d.Symbols = append(d.Symbols, s)
+				}
+			}
+		default:
+			r.skip(wireType)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &d, nil
+}
+
+func decodeOccurrence(b []byte) (*Occurrence, error) {
+	var o Occurrence
+	r := messageReader{buf: b}
+	for {
+		field, wireType, ok := r.next()
+		if !ok {
+			break
+		}
+		switch {
+		case field == occurrenceRange && (wireType == wireBytes || wireType == wireVarint):
+			o.Range = r.int32s(wireType, o.Range)
+		case field == occurrenceSymbol && wireType == wireBytes:
+			o.Symbol = r.string()
+		case field == occurrenceSymbolRoles && wireType == wireVarint:
+			o.SymbolRoles = int32(r.varint())
+		default:
+			r.skip(wireType)
+		}
+	}
+	if r.err == nil && len(o.Range) != 3 && len(o.Range) != 4 {
+		return nil, errors.Errorf("occurrence of %q has an invalid range %v", o.Symbol, o.Range)
+	}
+	return &o, r.err
+}
+
+func decodeSymbolInformation(b []byte) (*SymbolInformation, error) {
+	var s SymbolInformation
+	r := messageReader{buf: b}
+	for {
+		field, wireType, ok := r.next()
+		if !ok {
+			break
+		}
+		switch {
+		case field == symbolInformationSymbol && wireType == wireBytes:
+			s.Symbol = r.string()
+		case field == symbolInformationDocumentation && wireType == wireBytes:
+			s.Documentation = append(s.Documentation, r.string())
+		default:
+			r.skip(wireType)
+		}
+	}
+	return &s, r.err
+}
@@ -0,0 +1,190 @@
+package scip
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testGlobalSymbol   = "scip-go gomod example.com/m v1.0.0 m/Foo()."
+	testExternalSymbol = "scip-go gomod fmt . fmt/Println()."
+)
+
+func encodeMetadata(projectRoot string) []byte {
+	return bytesField(indexMetadata,
+		varintField(metadataVersion, 0),
+		bytesField(metadataToolInfo,
+			stringField(toolInfoName, "scip-go"),
+			stringField(toolInfoVersion, "0.1.0"),
+			stringField(toolInfoArguments, "--verbose"),
+		),
+		stringField(metadataProjectRoot, projectRoot),
+	)
+}
+
+func encodeOccurrence(symbol string, roles int32, rng []byte) []byte {
+	return bytesField(documentOccurrences,
+		rng,
+		stringField(occurrenceSymbol, symbol),
+		varintField(occurrenceSymbolRoles, uint64(roles)),
+	)
+}
+
+func encodeSymbolInformation(field int, symbol string, documentation ...string) []byte {
+	b := stringField(symbolInformationSymbol, symbol)
+	for _, d := range documentation {
+		b = append(b, stringField(symbolInformationDocumentation, d)...)
+	}
+	return bytesField(field, b)
+}
+
+// testIndex is an index with two documents that both use a local symbol
+// "local 1" and a global symbol, which the first one defines, and an
+// external symbol.
+var testIndex = concat(
+	encodeMetadata("file:///repo/"),
+	bytesField(indexDocuments,
+		stringField(documentRelativePath, "main.go"),
+		stringField(documentLanguage, "Go"),
+		encodeOccurrence(testGlobalSymbol, SymbolRoleDefinition, packedField(occurrenceRange, 0, 5, 8)),
+		encodeOccurrence("local 1", SymbolRoleDefinition|SymbolRoleWriteAccess, unpackedField(occurrenceRange, 1, 1, 2, 4)),
+		encodeOccurrence("local 1", SymbolRoleReadAccess, packedField(occurrenceRange, 3, 2, 5)),
+		encodeOccurrence("", 0, packedField(occurrenceRange, 4, 0, 2)),
+		encodeSymbolInformation(documentSymbols, testGlobalSymbol, "func Foo()", "Foo does things."),
+		// Unknown fields are skipped.
+		varintField(99, 1),
+	),
+	bytesField(indexDocuments,
+		stringField(documentRelativePath, "util.go"),
+		stringField(documentLanguage, "Go"),
+		encodeOccurrence("local 1", SymbolRoleDefinition, packedField(occurrenceRange, 0, 0, 5)),
+		encodeOccurrence(testGlobalSymbol, SymbolRoleReadAccess, packedField(occurrenceRange, 1, 0, 3)),
+	),
+	encodeSymbolInformation(indexExternalSymbols, testExternalSymbol, "Println prints."),
+	// Unknown top-level fields are skipped.
+	stringField(15, "unknown"),
+)
+
+func TestReadIndex(t *testing.T) {
+	var have []interface{}
+	err := ReadIndex(bytes.NewReader(testIndex), IndexVisitor{
+		Metadata:       func(m *Metadata) error { have = append(have, m); return nil },
+		Document:       func(d *Document) error { have = append(have, d); return nil },
+		ExternalSymbol: func(s *SymbolInformation) error { have = append(have, s); return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []interface{}{
+		&Metadata{
+			ToolInfo:    ToolInfo{Name: "scip-go", Version: "0.1.0", Arguments: []string{"--verbose"}},
+			ProjectRoot: "file:///repo/",
+		},
+		&Document{
+			RelativePath: "main.go",
+			Language:     "Go",
+			Occurrences: []*Occurrence{
+				{Range: []int32{0, 5, 8}, Symbol: testGlobalSymbol, SymbolRoles: SymbolRoleDefinition},
+				{Range: []int32{1, 1, 2, 4}, Symbol: "local 1", SymbolRoles: SymbolRoleDefinition | SymbolRoleWriteAccess},
+				{Range: []int32{3, 2, 5}, Symbol: "local 1", SymbolRoles: SymbolRoleReadAccess},
+				{Range: []int32{4, 0, 2}},
+			},
+			Symbols: []*SymbolInformation{
+				{Symbol: testGlobalSymbol, Documentation: []string{"func Foo()", "Foo does things."}},
+			},
+		},
+		&Document{
+			RelativePath: "util.go",
+			Language:     "Go",
+			Occurrences: []*Occurrence{
+				{Range: []int32{0, 0, 5}, Symbol: "local 1", SymbolRoles: SymbolRoleDefinition},
+				{Range: []int32{1, 0, 3}, Symbol: testGlobalSymbol, SymbolRoles: SymbolRoleReadAccess},
+			},
+		},
+		&SymbolInformation{Symbol: testExternalSymbol, Documentation: []string{"Println prints."}},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %+v, want %+v", have, want)
+	}
+}
+
+func TestReadIndexErrors(t *testing.T) {
+	visitor := IndexVisitor{
+		Metadata:       func(*Metadata) error { return nil },
+		Document:       func(*Document) error { return nil },
+		ExternalSymbol: func(*SymbolInformation) error { return nil },
+	}
+
+	for _, tc := range []struct {
+		name    string
+		index   []byte
+		visitor IndexVisitor
+		wantErr string
+	}{
+		{
+			name:    "truncated index",
+			index:   testIndex[:len(testIndex)-3],
+			wantErr: "reading SCIP index: unexpected EOF",
+		},
+		{
+			name:    "invalid metadata",
+			index:   bytesField(indexMetadata, []byte{0x80}),
+			wantErr: "decoding SCIP metadata: invalid varint",
+		},
+		{
+			name:    "invalid tool info",
+			index:   bytesField(indexMetadata, bytesField(metadataToolInfo, encodeTag(toolInfoName, wireBytes), encodeVarint(3))),
+			wantErr: "decoding SCIP metadata: unexpected EOF",
+		},
+		{
+			name: "range with two elements",
+			index: bytesField(indexDocuments,
+				encodeOccurrence("local 1", 0, packedField(occurrenceRange, 1, 2)),
+			),
+			wantErr: `decoding SCIP document: occurrence of "local 1" has an invalid range [1 2]`,
+		},
+		{
+			name: "range with five elements",
+			index: bytesField(indexDocuments,
+				encodeOccurrence("local 1", 0, unpackedField(occurrenceRange, 1, 2, 3, 4, 5)),
+			),
+			wantErr: `decoding SCIP document: occurrence of "local 1" has an invalid range [1 2 3 4 5]`,
+		},
+		{
+			name: "truncated occurrence",
+			index: bytesField(indexDocuments,
+				bytesField(documentOccurrences, encodeTag(occurrenceSymbol, wireBytes), encodeVarint(10)),
+			),
+			wantErr: "decoding SCIP document: unexpected EOF",
+		},
+		{
+			name:    "invalid external symbol",
+			index:   bytesField(indexExternalSymbols, encodeTag(symbolInformationSymbol, 3)),
+			wantErr: "decoding SCIP external symbol: unsupported wire type 3",
+		},
+		{
+			name:  "visitor error",
+			index: testIndex,
+			visitor: IndexVisitor{
+				Metadata: func(*Metadata) error { return nil },
+				Document: func(*Document) error { return errors.New("boom") },
+			},
+			wantErr: "boom",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v := tc.visitor
+			if v.Metadata == nil {
+				v = visitor
+			}
+			err := ReadIndex(bytes.NewReader(tc.index), v)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("have error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
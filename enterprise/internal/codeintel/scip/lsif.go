@@ -0,0 +1,377 @@
+package scip
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// lsifVersion is the version of the LSIF protocol of converted indexes.
+const lsifVersion = "0.4.3"
+
+// ConvertToLSIF reads the SCIP index from r and writes the equivalent LSIF dump
+// as JSON lines to w.
+//
+// Each symbol becomes a result set with a definition and a reference result,
+// which the ranges of its occurrences are added to, and its documentation
+// becomes a hover result. Global symbols also get a moniker with the
+// information of their package, so that cross-repository navigation works as
+// for LSIF dumps. Local symbols are scoped to their document, and are written
+// out when their document has been converted.
+func ConvertToLSIF(r io.Reader, w io.Writer) error {
+	c := &converter{
+		w:        bufio.NewWriter(w),
+		symbols:  map[symbolKey]*symbolResult{},
+		packages: map[[4]string]int{},
+	}
+	c.enc = json.NewEncoder(c.w)
+
+	err := ReadIndex(r, IndexVisitor{
+		Metadata:       c.metadata,
+		Document:       c.document,
+		ExternalSymbol: c.externalSymbol,
+	})
+	if err != nil {
+		return err
+	}
+	if c.projectRoot == "" {
+		return errors.New("SCIP index has no metadata")
+	}
+
+	for _, s := range c.globals {
+		c.writeItems(s)
+		c.writeMoniker(s)
+	}
+	if c.err != nil {
+		return c.err
+	}
+	return c.w.Flush()
+}
+
+// lsifElement is a vertex or an edge of an LSIF dump. Only the properties of
+// its label are set.
+type lsifElement struct {
+	ID    int    `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+
+	Version          string           `json:"version,omitempty"`
+	ProjectRoot      string           `json:"projectRoot,omitempty"`
+	PositionEncoding string           `json:"positionEncoding,omitempty"`
+	ToolInfo         *lsifToolInfo    `json:"toolInfo,omitempty"`
+	URI              string           `json:"uri,omitempty"`
+	LanguageID       string           `json:"languageId,omitempty"`
+	Start            *lsifPosition    `json:"start,omitempty"`
+	End              *lsifPosition    `json:"end,omitempty"`
+	Result           *lsifHoverResult `json:"result,omitempty"`
+	Kind             string           `json:"kind,omitempty"`
+	Scheme           string           `json:"scheme,omitempty"`
+	Identifier       string           `json:"identifier,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	Manager          string           `json:"manager,omitempty"`
+
+	OutV     int   `json:"outV,omitempty"`
+	InV      int   `json:"inV,omitempty"`
+	InVs     []int `json:"inVs,omitempty"`
+	Document int   `json:"document,omitempty"`
+}
+
+type lsifToolInfo struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version,omitempty"`
+	Args    []string `json:"args,omitempty"`
+}
+
+type lsifPosition struct {
+	Line      int32 `json:"line"`
+	Character int32 `json:"character"`
+}
+
+type lsifHoverResult struct {
+	Contents lsifMarkupContent `json:"contents"`
+}
+
+type lsifMarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// symbolKey identifies a symbol of an index. Local symbols are only unique
+// within their document, global symbols have no document.
+type symbolKey struct {
+	document int
+	symbol   string
+}
+
+// symbolResult is the result set of a symbol and the ranges of its
+// occurrences by document.
+type symbolResult struct {
+	symbol           string
+	resultSet        int
+	definitionResult int
+	referenceResult  int
+	hasHover         bool
+	defined          bool
+
+	documents   []int
+	definitions map[int][]int
+	references  map[int][]int
+}
+
+type converter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	err error
+
+	lastID      int
+	projectRoot string
+
+	symbols  map[symbolKey]*symbolResult
+	globals  []*symbolResult
+	locals   []*symbolResult
+	packages map[[4]string]int
+}
+
+func (c *converter) metadata(m *Metadata) error {
+	if c.projectRoot != "" {
+		return errors.New("SCIP index has more than one metadata")
+	}
+	c.projectRoot = strings.TrimSuffix(m.ProjectRoot, "/")
+	if c.projectRoot == "" {
+		return errors.New("SCIP index has no project root")
+	}
+
+	c.vertex(lsifElement{
+		Label:            "metaData",
+		Version:          lsifVersion,
+		ProjectRoot:      c.projectRoot,
+		PositionEncoding: "utf-16",
+		ToolInfo: &lsifToolInfo{
+			Name:    m.ToolInfo.Name,
+			Version: m.ToolInfo.Version,
+			Args:    m.ToolInfo.Arguments,
+		},
+	})
+	return c.err
+}
+
+func (c *converter) document(d *Document) error {
+	if c.projectRoot == "" {
+		return errors.New("SCIP metadata must precede the documents")
+	}
+
+	document := c.vertex(lsifElement{
+		Label:      "document",
+		URI:        c.projectRoot + "/" + d.RelativePath,
+		LanguageID: strings.ToLower(d.Language),
+	})
+
+	ranges := make([]int, 0, len(d.Occurrences))
+	for _, o := range d.Occurrences {
+		start, end := lsifRange(o)
+		rng := c.vertex(lsifElement{Label: "range", Start: &start, End: &end})
+		ranges = append(ranges, rng)
+
+		if o.Symbol == "" {
+			continue
+		}
+
+		s := c.symbol(document, o.Symbol)
+		c.edge(lsifElement{Label: "next", OutV: rng, InV: s.resultSet})
+
+		if _, ok := s.references[document]; !ok {
+			s.documents = append(s.documents, document)
+		}
+		s.references[document] = append(s.references[document], rng)
+		if o.IsDefinition() {
+			s.definitions[document] = append(s.definitions[document], rng)
+			s.defined = true
+		}
+	}
+
+	if len(ranges) > 0 {
+		c.edge(lsifElement{Label: "contains", OutV: document, InVs: ranges})
+	}
+
+	for _, si := range d.Symbols {
+		c.writeHover(c.symbol(document, si.Symbol), si.Documentation)
+	}
+
+	// Local symbols can't occur in later documents.
+	for _, s := range c.locals {
+		c.writeItems(s)
+		delete(c.symbols, symbolKey{document: document, symbol: s.symbol})
+	}
+	c.locals = c.locals[:0]
+
+	return c.err
+}
+
+func (c *converter) externalSymbol(si *SymbolInformation) error {
+	if isLocalSymbol(si.Symbol) {
+		return nil
+	}
+	c.writeHover(c.symbol(0, si.Symbol), si.Documentation)
+	return c.err
+}
+
+// symbol returns the result of the given symbol occurring in the given
+// document, which is created on first use.
+func (c *converter) symbol(document int, symbol string) *symbolResult {
+	local := isLocalSymbol(symbol)
+
+	key := symbolKey{symbol: symbol}
+	if local {
+		key.document = document
+	}
+	if s, ok := c.symbols[key]; ok {
+		return s
+	}
+
+	s := &symbolResult{
+		symbol:      symbol,
+		definitions: map[int][]int{},
+		references:  map[int][]int{},
+	}
+	s.resultSet = c.vertex(lsifElement{Label: "resultSet"})
+	s.definitionResult = c.vertex(lsifElement{Label: "definitionResult"})
+	c.edge(lsifElement{Label: "textDocument/definition", OutV: s.resultSet, InV: s.definitionResult})
+	s.referenceResult = c.vertex(lsifElement{Label: "referenceResult"})
+	c.edge(lsifElement{Label: "textDocument/references", OutV: s.resultSet, InV: s.referenceResult})
+
+	c.symbols[key] = s
+	if local {
+		c.locals = append(c.locals, s)
+	} else {
+		c.globals = append(c.globals, s)
+	}
+	return s
+}
+
+func (c *converter) writeHover(s *symbolResult, documentation []string) {
+	if s.hasHover || len(documentation) == 0 {
+		return
+	}
+	s.hasHover = true
+
+	hover := c.vertex(lsifElement{
+		Label: "hoverResult",
+		Result: &lsifHoverResult{Contents: lsifMarkupContent{
+			Kind:  "markdown",
+			Value: strings.Join(documentation, "\n\n---\n\n"),
+		}},
+	})
+	c.edge(lsifElement{Label: "textDocument/hover", OutV: s.resultSet, InV: hover})
+}
+
+// writeItems adds the ranges of the occurrences of the symbol to its
+// definition and reference results.
+func (c *converter) writeItems(s *symbolResult) {
+	for _, document := range s.documents {
+		if definitions := s.definitions[document]; len(definitions) > 0 {
+			c.edge(lsifElement{Label: "item", OutV: s.definitionResult, InVs: definitions, Document: document})
+		}
+		c.edge(lsifElement{Label: "item", OutV: s.referenceResult, InVs: s.references[document], Document: document})
+	}
+}
+
+// writeMoniker attaches a moniker to the result set of a global symbol, which
+// is exported if the index defines the symbol and imported otherwise.
+func (c *converter) writeMoniker(s *symbolResult) {
+	pkg, descriptors, ok := parseSymbol(s.symbol)
+	if !ok {
+		return
+	}
+
+	kind := "import"
+	if s.defined {
+		kind = "export"
+	}
+
+	moniker := c.vertex(lsifElement{Label: "moniker", Kind: kind, Scheme: pkg[0], Identifier: descriptors})
+	c.edge(lsifElement{Label: "moniker", OutV: s.resultSet, InV: moniker})
+
+	// Without the name of a package, the symbol can't be found in other
+	// repositories.
+	if pkg[2] == "" {
+		return
+	}
+
+	packageInformation, ok := c.packages[pkg]
+	if !ok {
+		packageInformation = c.vertex(lsifElement{
+			Label:   "packageInformation",
+			Manager: pkg[1],
+			Name:    pkg[2],
+			Version: pkg[3],
+		})
+		c.packages[pkg] = packageInformation
+	}
+	c.edge(lsifElement{Label: "packageInformation", OutV: moniker, InV: packageInformation})
+}
+
+func (c *converter) vertex(e lsifElement) int {
+	e.Type = "vertex"
+	return c.write(e)
+}
+
+func (c *converter) edge(e lsifElement) int {
+	e.Type = "edge"
+	return c.write(e)
+}
+
+func (c *converter) write(e lsifElement) int {
+	c.lastID++
+	e.ID = c.lastID
+	if c.err == nil {
+		c.err = c.enc.Encode(e)
+	}
+	return e.ID
+}
+
+// lsifRange returns the start and end position of the range of the
+// given occurrence.
+func lsifRange(o *Occurrence) (start, end lsifPosition) {
+	start = lsifPosition{Line: o.Range[0], Character: o.Range[1]}
+	if len(o.Range) == 3 {
+		return start, lsifPosition{Line: o.Range[0], Character: o.Range[2]}
+	}
+	return start, lsifPosition{Line: o.Range[2], Character: o.Range[3]}
+}
+
+func isLocalSymbol(symbol string) bool {
+	return strings.HasPrefix(symbol, "local ")
+}
+
+// parseSymbol splits a global symbol into the scheme, package manager,
+// package name and package version, which are separated by spaces, and the
+// descriptors that follow them. Spaces within the first four are escaped by
+// doubling them, and "." denotes an empty value.
+func parseSymbol(symbol string) (pkg [4]string, descriptors string, ok bool) {
+	rest := symbol
+	for i := range pkg {
+		var b strings.Builder
+		for {
+			j := strings.IndexByte(rest, ' ')
+			if j < 0 {
+				return pkg, "", false
+			}
+			b.WriteString(rest[:j])
+			if j+1 < len(rest) && rest[j+1] == ' ' {
+				b.WriteByte(' ')
+				rest = rest[j+2:]
+				continue
+			}
+			rest = rest[j+1:]
+			break
+		}
+
+		if pkg[i] = b.String(); pkg[i] == "." {
+			pkg[i] = ""
+		}
+	}
+	return pkg, rest, rest != ""
+}
@@ -0,0 +1,131 @@
+package scip
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The expected dump of testIndex. The local symbol "local 1" gets a result
+// set in each document that uses it, whose items are written out with the
+// document, while the global symbol has a single result set, whose items and
+// export moniker are written out at the end. The external symbol gets an
+// import moniker.
+const testIndexLSIF = `{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///repo","positionEncoding":"utf-16","toolInfo":{"name":"scip-go","version":"0.1.0","args":["--verbose"]}}
+{"id":2,"type":"vertex","label":"document","uri":"file:///repo/main.go","languageId":"go"}
+{"id":3,"type":"vertex","label":"range","start":{"line":0,"character":5},"end":{"line":0,"character":8}}
+{"id":4,"type":"vertex","label":"resultSet"}
+{"id":5,"type":"vertex","label":"definitionResult"}
+{"id":6,"type":"edge","label":"textDocument/definition","outV":4,"inV":5}
+{"id":7,"type":"vertex","label":"referenceResult"}
+{"id":8,"type":"edge","label":"textDocument/references","outV":4,"inV":7}
+{"id":9,"type":"edge","label":"next","outV":3,"inV":4}
+{"id":10,"type":"vertex","label":"range","start":{"line":1,"character":1},"end":{"line":2,"character":4}}
+{"id":11,"type":"vertex","label":"resultSet"}
+{"id":12,"type":"vertex","label":"definitionResult"}
+{"id":13,"type":"edge","label":"textDocument/definition","outV":11,"inV":12}
+{"id":14,"type":"vertex","label":"referenceResult"}
+{"id":15,"type":"edge","label":"textDocument/references","outV":11,"inV":14}
+{"id":16,"type":"edge","label":"next","outV":10,"inV":11}
+{"id":17,"type":"vertex","label":"range","start":{"line":3,"character":2},"end":{"line":3,"character":5}}
+{"id":18,"type":"edge","label":"next","outV":17,"inV":11}
+{"id":19,"type":"vertex","label":"range","start":{"line":4,"character":0},"end":{"line":4,"character":2}}
+{"id":20,"type":"edge","label":"contains","outV":2,"inVs":[3,10,17,19]}
+{"id":21,"type":"vertex","label":"hoverResult","result":{"contents":{"kind":"markdown","value":"func Foo()\n\n---\n\nFoo does things."}}}
+{"id":22,"type":"edge","label":"textDocument/hover","outV":4,"inV":21}
+{"id":23,"type":"edge","label":"item","outV":12,"inVs":[10],"document":2}
+{"id":24,"type":"edge","label":"item","outV":14,"inVs":[10,17],"document":2}
+{"id":25,"type":"vertex","label":"document","uri":"file:///repo/util.go","languageId":"go"}
+{"id":26,"type":"vertex","label":"range","start":{"line":0,"character":0},"end":{"line":0,"character":5}}
+{"id":27,"type":"vertex","label":"resultSet"}
+{"id":28,"type":"vertex","label":"definitionResult"}
+{"id":29,"type":"edge","label":"textDocument/definition","outV":27,"inV":28}
+{"id":30,"type":"vertex","label":"referenceResult"}
+{"id":31,"type":"edge","label":"textDocument/references","outV":27,"inV":30}
+{"id":32,"type":"edge","label":"next","outV":26,"inV":27}
+{"id":33,"type":"vertex","label":"range","start":{"line":1,"character":0},"end":{"line":1,"character":3}}
+{"id":34,"type":"edge","label":"next","outV":33,"inV":4}
+{"id":35,"type":"edge","label":"contains","outV":25,"inVs":[26,33]}
+{"id":36,"type":"edge","label":"item","outV":28,"inVs":[26],"document":25}
+{"id":37,"type":"edge","label":"item","outV":30,"inVs":[26],"document":25}
+{"id":38,"type":"vertex","label":"resultSet"}
+{"id":39,"type":"vertex","label":"definitionResult"}
+{"id":40,"type":"edge","label":"textDocument/definition","outV":38,"inV":39}
+{"id":41,"type":"vertex","label":"referenceResult"}
+{"id":42,"type":"edge","label":"textDocument/references","outV":38,"inV":41}
+{"id":43,"type":"vertex","label":"hoverResult","result":{"contents":{"kind":"markdown","value":"Println prints."}}}
+{"id":44,"type":"edge","label":"textDocument/hover","outV":38,"inV":43}
+{"id":45,"type":"edge","label":"item","outV":5,"inVs":[3],"document":2}
+{"id":46,"type":"edge","label":"item","outV":7,"inVs":[3],"document":2}
+{"id":47,"type":"edge","label":"item","outV":7,"inVs":[33],"document":25}
+{"id":48,"type":"vertex","label":"moniker","kind":"export","scheme":"scip-go","identifier":"m/Foo()."}
+{"id":49,"type":"edge","label":"moniker","outV":4,"inV":48}
+{"id":50,"type":"vertex","label":"packageInformation","version":"v1.0.0","name":"example.com/m","manager":"gomod"}
+{"id":51,"type":"edge","label":"packageInformation","outV":48,"inV":50}
+{"id":52,"type":"vertex","label":"moniker","kind":"import","scheme":"scip-go","identifier":"fmt/Println()."}
+{"id":53,"type":"edge","label":"moniker","outV":38,"inV":52}
+{"id":54,"type":"vertex","label":"packageInformation","name":"fmt","manager":"gomod"}
+{"id":55,"type":"edge","label":"packageInformation","outV":52,"inV":54}
+`
+
+func TestConvertToLSIF(t *testing.T) {
+	var b bytes.Buffer
+	if err := ConvertToLSIF(bytes.NewReader(testIndex), &b); err != nil {
+		t.Fatal(err)
+	}
+
+	have := strings.Split(b.String(), "\n")
+	want := strings.Split(testIndexLSIF, "\n")
+	if len(have) != len(want) {
+		t.Fatalf("have %d lines, want %d:\n%s", len(have), len(want), b.String())
+	}
+	for i := range have {
+		if have[i] != want[i] {
+			t.Errorf("line %d: have %s, want %s", i+1, have[i], want[i])
+		}
+	}
+}
+
+func TestConvertToLSIFErrors(t *testing.T) {
+	document := bytesField(indexDocuments, stringField(documentRelativePath, "main.go"))
+
+	for _, tc := range []struct {
+		name    string
+		index   []byte
+		wantErr string
+	}{
+		{"empty index", nil, "SCIP index has no metadata"},
+		{"document before metadata", concat(document, encodeMetadata("file:///repo/")), "SCIP metadata must precede the documents"},
+		{"two metadata", concat(encodeMetadata("file:///repo/"), encodeMetadata("file:///other/")), "SCIP index has more than one metadata"},
+		{"no project root", encodeMetadata(""), "SCIP index has no project root"},
+		{"truncated index", testIndex[:len(testIndex)/2], "unexpected EOF"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ConvertToLSIF(bytes.NewReader(tc.index), &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("have error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSymbol(t *testing.T) {
+	for _, tc := range []struct {
+		symbol          string
+		wantPkg         [4]string
+		wantDescriptors string
+		wantOK          bool
+	}{
+		{testGlobalSymbol, [4]string{"scip-go", "gomod", "example.com/m", "v1.0.0"}, "m/Foo().", true},
+		{testExternalSymbol, [4]string{"scip-go", "gomod", "fmt", ""}, "fmt/Println().", true},
+		{"scip-java maven my  package 1.0 a/B#", [4]string{"scip-java", "maven", "my package", "1.0"}, "a/B#", true},
+		{"scip-go gomod fmt .", [4]string{"scip-go", "gomod", "fmt"}, "", false},
+		{"scip-go gomod fmt . ", [4]string{"scip-go", "gomod", "fmt", ""}, "", false},
+	} {
+		pkg, descriptors, ok := parseSymbol(tc.symbol)
+		if ok != tc.wantOK || (ok && (!reflect.DeepEqual(pkg, tc.wantPkg) || descriptors != tc.wantDescriptors)) {
+			t.Errorf("parseSymbol(%q): have %q %q %v, want %q %q %v", tc.symbol, pkg, descriptors, ok, tc.wantPkg, tc.wantDescriptors, tc.wantOK)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package scip
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// Protocol buffer wire types, see
+// https://developers.google.com/protocol-buffers/docs/encoding#structure.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// maxTopLevelFieldSize bounds the size of a single document or external
+// symbol of an index. Even the documents of huge generated files take a few
+// megabytes, so larger sizes come from corrupt or malicious indexes.
+const maxTopLevelFieldSize = 64 << 20
+
+// messageReader reads the fields of an encoded protocol buffer message. The
+// first error stops reading and is returned by err.
+type messageReader struct {
+	buf []byte
+	err error
+}
+
+// next reads the tag of the next field. It returns false at the end of the
+// message or after an error.
+func (r *messageReader) next() (field int, wireType int, ok bool) {
+	if r.err != nil || len(r.buf) == 0 {
+		return 0, 0, false
+	}
+	tag := r.varint()
+	if r.err != nil {
+		return 0, 0, false
+	}
+	return int(tag >> 3), int(tag & 7), true
+}
+
+func (r *messageReader) varint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail(errors.New("invalid varint"))
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *messageReader) bytes() []byte {
+	n := r.varint()
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf)) {
+		r.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *messageReader) string() string { return string(r.bytes()) }
+
+// int32s reads a repeated int32 field, which is either packed or a single
+// value.
+func (r *messageReader) int32s(wireType int, values []int32) []int32 {
+	if wireType == wireVarint {
+		return append(values, int32(r.varint()))
+	}
+
+	packed := messageReader{buf: r.bytes()}
+	for r.err == nil && packed.err == nil && len(packed.buf) > 0 {
+		values = append(values, int32(packed.varint()))
+	}
+	r.fail(packed.err)
+	return values
+}
+
+// skip skips the value of a field with the given wire type.
+func (r *messageReader) skip(wireType int) {
+	switch wireType {
+	case wireVarint:
+		r.varint()
+	case wireBytes:
+		r.bytes()
+	case wireFixed64:
+		r.advance(8)
+	case wireFixed32:
+		r.advance(4)
+	default:
+		r.fail(errors.Errorf("unsupported wire type %d", wireType))
+	}
+}
+
+func (r *messageReader) advance(n int) {
+	if n > len(r.buf) {
+		r.fail(io.ErrUnexpectedEOF)
+		return
+	}
+	r.buf = r.buf[n:]
+}
+
+func (r *messageReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// readTopLevelField reads the next field of a message that is streamed from
+// r, so that huge messages are never held in memory at once. Only
+// length-delimited fields are supported, and their contents are returned. It
+// returns io.EOF at the end of the message.
+func readTopLevelField(r *bufio.Reader) (field int, value []byte, err error) {
+	tag, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if wireType := int(tag & 7); wireType != wireBytes {
+		return 0, nil, errors.Errorf("unexpected wire type %d of top-level field %d", wireType, tag>>3)
+	}
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, nil, noEOF(err)
+	}
+	if n > maxTopLevelFieldSize {
+		return 0, nil, errors.Errorf("top-level field %d of %d bytes exceeds the maximum size", tag>>3, n)
+	}
+	// The value grows as it's read instead of being allocated up front, so
+	// that a corrupt length can't allocate more memory than the index holds.
+	value, err = ioutil.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return 0, nil, err
+	}
+	if uint64(len(value)) < n {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return int(tag >> 3), value, nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
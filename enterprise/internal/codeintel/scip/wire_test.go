@@ -0,0 +1,234 @@
+package scip
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The encoding helpers below build the protocol buffer fixtures of the tests.
+
+func encodeVarint(v uint64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, v)]
+}
+
+func encodeTag(field, wireType int) []byte {
+	return encodeVarint(uint64(field<<3 | wireType))
+}
+
+func varintField(field int, v uint64) []byte {
+	return concat(encodeTag(field, wireVarint), encodeVarint(v))
+}
+
+func bytesField(field int, value ...[]byte) []byte {
+	b := concat(value...)
+	return concat(encodeTag(field, wireBytes), encodeVarint(uint64(len(b))), b)
+}
+
+func stringField(field int, s string) []byte {
+	return bytesField(field, []byte(s))
+}
+
+func packedField(field int, values ...int32) []byte {
+	var b []byte
+	for _, v := range values {
+		b = append(b, encodeVarint(uint64(v))...)
+	}
+	return bytesField(field, b)
+}
+
+func unpackedField(field int, values ...int32) []byte {
+	var b []byte
+	for _, v := range values {
+		b = append(b, varintField(field, uint64(v))...)
+	}
+	return b
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func TestMessageReaderInt32s(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		buf  []byte
+		want []int32
+	}{
+		{"packed", packedField(1, 1, 2, 300), []int32{1, 2, 300}},
+		{"unpacked", unpackedField(1, 1, 2, 300), []int32{1, 2, 300}},
+		{"mixed", concat(unpackedField(1, 1), packedField(1, 2, 3), unpackedField(1, 4)), []int32{1, 2, 3, 4}},
+		{"empty packed", packedField(1), nil},
+		{"negative", unpackedField(1, -1), []int32{-1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var have []int32
+			r := messageReader{buf: tc.buf}
+			for {
+				_, wireType, ok := r.next()
+				if !ok {
+					break
+				}
+				have = r.int32s(wireType, have)
+			}
+			if r.err != nil {
+				t.Fatal(r.err)
+			}
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("have %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestMessageReaderSkip(t *testing.T) {
+	buf := concat(
+		varintField(10, 1<<40),
+		encodeTag(11, wireFixed64), make([]byte, 8),
+		encodeTag(12, wireFixed32), make([]byte, 4),
+		stringField(13, "skipped"),
+		stringField(1, "kept"),
+	)
+
+	var have []string
+	r := messageReader{buf: buf}
+	for {
+		field, wireType, ok := r.next()
+		if !ok {
+			break
+		}
+		if field == 1 {
+			have = append(have, r.string())
+			continue
+		}
+		r.skip(wireType)
+	}
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if want := []string{"kept"}; !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+}
+
+func TestMessageReaderErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		buf     []byte
+		wantErr string
+	}{
+		{"truncated tag", []byte{0x80}, "invalid varint"},
+		{"truncated varint", concat(encodeTag(2, wireVarint), []byte{0xff}), "invalid varint"},
+		{"truncated bytes", concat(encodeTag(2, wireBytes), encodeVarint(5), []byte("ab")), io.ErrUnexpectedEOF.Error()},
+		{"truncated fixed64", concat(encodeTag(2, wireFixed64), make([]byte, 7)), io.ErrUnexpectedEOF.Error()},
+		{"truncated fixed32", concat(encodeTag(2, wireFixed32), make([]byte, 3)), io.ErrUnexpectedEOF.Error()},
+		{"group wire type", encodeTag(2, 3), "unsupported wire type 3"},
+		{"truncated packed value", bytesField(1, []byte{0x80}), "invalid varint"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := messageReader{buf: tc.buf}
+			for {
+				field, wireType, ok := r.next()
+				if !ok {
+					break
+				}
+				if field == 1 {
+					r.int32s(wireType, nil)
+					continue
+				}
+				r.skip(wireType)
+			}
+			if r.err == nil || r.err.Error() != tc.wantErr {
+				t.Errorf("have error %v, want %q", r.err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadTopLevelField(t *testing.T) {
+	type field struct {
+		field int
+		value string
+	}
+
+	for _, tc := range []struct {
+		name       string
+		buf        []byte
+		wantFields []field
+		wantErr    string
+	}{
+		{
+			name:       "fields",
+			buf:        concat(stringField(2, "document"), stringField(3, "symbol"), stringField(2, "")),
+			wantFields: []field{{2, "document"}, {3, "symbol"}, {2, ""}},
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:    "varint field",
+			buf:     varintField(1, 5),
+			wantErr: "unexpected wire type 0 of top-level field 1",
+		},
+		{
+			name:    "truncated length",
+			buf:     encodeTag(2, wireBytes),
+			wantErr: io.ErrUnexpectedEOF.Error(),
+		},
+		{
+			name:       "truncated value",
+			buf:        concat(stringField(2, "document"), encodeTag(2, wireBytes), encodeVarint(10), []byte("abc")),
+			wantFields: []field{{2, "document"}},
+			wantErr:    io.ErrUnexpectedEOF.Error(),
+		},
+		{
+			name:    "value above the maximum size",
+			buf:     concat(encodeTag(2, wireBytes), encodeVarint(maxTopLevelFieldSize+1)),
+			wantErr: "top-level field 2 of 67108865 bytes exceeds the maximum size",
+		},
+		{
+			// The length is within the maximum size, but the input is much
+			// shorter.
+			name:    "corrupt length",
+			buf:     concat(encodeTag(2, wireBytes), encodeVarint(maxTopLevelFieldSize), []byte("abc")),
+			wantErr: io.ErrUnexpectedEOF.Error(),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				have []field
+				err  error
+			)
+			r := bufio.NewReader(bytes.NewReader(tc.buf))
+			for {
+				var (
+					f     int
+					value []byte
+				)
+				if f, value, err = readTopLevelField(r); err != nil {
+					break
+				}
+				have = append(have, field{f, string(value)})
+			}
+
+			if !reflect.DeepEqual(have, tc.wantFields) {
+				t.Errorf("have fields %v, want %v", have, tc.wantFields)
+			}
+			switch {
+			case tc.wantErr == "" && err != io.EOF:
+				t.Errorf("have error %v, want io.EOF", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("have error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
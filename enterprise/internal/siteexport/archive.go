@@ -0,0 +1,135 @@
+// Package siteexport exports the campaigns, changesets and LSIF upload
+// metadata of an instance into a portable archive, and imports such an
+// archive into another instance, e.g. when migrating to a new deployment.
+package siteexport
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// ArchiveVersion is the version of the archive format written by
+// WriteArchive. ReadArchive rejects archives of other versions.
+const ArchiveVersion = 1
+
+// An Archive holds the exported records of an instance. Since the IDs of
+// users, orgs and repositories differ between instances, records refer to
+// them by name. The IDs of campaigns and changesets are those of the
+// exporting instance and only link the records within the archive.
+type Archive struct {
+	Version     int           `json:"version"`
+	ExportedAt  time.Time     `json:"exportedAt"`
+	Campaigns   []*Campaign   `json:"campaigns"`
+	Changesets  []*Changeset  `json:"changesets"`
+	LSIFUploads []*LSIFUpload `json:"lsifUploads"`
+}
+
+// Campaign is an exported campaign. Its campaign plan isn't exported, since
+// plans expire shortly after they're created.
+type Campaign struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Branch      string `json:"branch,omitempty"`
+	// Author is the username of the author, or empty if the author has been
+	// deleted.
+	Author string `json:"author"`
+	// Exactly one of NamespaceUser (a username) and NamespaceOrg (an org
+	// name) is set.
+	NamespaceUser        string          `json:"namespaceUser,omitempty"`
+	NamespaceOrg         string          `json:"namespaceOrg,omitempty"`
+	ChangesetIDs         []int64         `json:"changesetIDs"`
+	Spec                 json.RawMessage `json:"spec,omitempty"`
+	RollbackOfCampaignID int64           `json:"rollbackOfCampaignID,omitempty"`
+	ParentCampaignID     int64           `json:"parentCampaignID,omitempty"`
+	AutoRebase           bool            `json:"autoRebase"`
+	Position             string          `json:"position"`
+	CreatedAt            time.Time       `json:"createdAt"`
+	UpdatedAt            time.Time       `json:"updatedAt"`
+	ClosedAt             *time.Time      `json:"closedAt,omitempty"`
+}
+
+// Changeset is an exported changeset and its associations with campaigns.
+// Its diff stat and conflicts aren't exported, since the importing instance
+// computes them again.
+type Changeset struct {
+	ID                  int64           `json:"id"`
+	Repository          api.RepoName    `json:"repository"`
+	ExternalServiceType string          `json:"externalServiceType"`
+	ExternalID          string          `json:"externalID"`
+	ExternalBranch      string          `json:"externalBranch,omitempty"`
+	ExternalDeletedAt   *time.Time      `json:"externalDeletedAt,omitempty"`
+	CampaignIDs         []int64         `json:"campaignIDs"`
+	Metadata            json.RawMessage `json:"metadata"`
+	CreatedAt           time.Time       `json:"createdAt"`
+	UpdatedAt           time.Time       `json:"updatedAt"`
+}
+
+// LSIFUpload is the metadata of an exported LSIF upload. The converted dump
+// of the upload stays on the LSIF server, which stores it in a file named
+// after the ID of the upload, so the ID is kept on import.
+type LSIFUpload struct {
+	ID                int64            `json:"id"`
+	Repository        api.RepoName     `json:"repository"`
+	Commit            string           `json:"commit"`
+	Root              string           `json:"root"`
+	Indexer           *string          `json:"indexer,omitempty"`
+	Filename          string           `json:"filename"`
+	State             string           `json:"state"`
+	FailureSummary    *string          `json:"failureSummary,omitempty"`
+	FailureStacktrace *string          `json:"failureStacktrace,omitempty"`
+	VisibleAtTip      bool             `json:"visibleAtTip"`
+	UploadedAt        time.Time        `json:"uploadedAt"`
+	StartedAt         *time.Time       `json:"startedAt,omitempty"`
+	FinishedAt        *time.Time       `json:"finishedAt,omitempty"`
+	Packages          []*LSIFPackage   `json:"packages,omitempty"`
+	References        []*LSIFReference `json:"references,omitempty"`
+}
+
+// LSIFPackage is a package provided by an exported LSIF upload.
+type LSIFPackage struct {
+	Scheme  string  `json:"scheme"`
+	Name    string  `json:"name"`
+	Version *string `json:"version,omitempty"`
+}
+
+// LSIFReference is a package referenced by an exported LSIF upload, with the
+// bloom filter of the identifiers it references.
+type LSIFReference struct {
+	Scheme  string  `json:"scheme"`
+	Name    string  `json:"name"`
+	Version *string `json:"version,omitempty"`
+	Filter  []byte  `json:"filter"`
+}
+
+// WriteArchive writes the given Archive to w as gzipped JSON.
+func WriteArchive(w io.Writer, a *Archive) error {
+	gw := gzip.NewWriter(w)
+	if err := json.NewEncoder(gw).Encode(a); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ReadArchive reads an Archive written by WriteArchive from r.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading archive")
+	}
+	defer gr.Close()
+
+	var a Archive
+	if err := json.NewDecoder(gr).Decode(&a); err != nil {
+		return nil, errors.Wrap(err, "decoding archive")
+	}
+	if a.Version != ArchiveVersion {
+		return nil, errors.Errorf("unsupported archive version %d, want %d", a.Version, ArchiveVersion)
+	}
+	return &a, nil
+}
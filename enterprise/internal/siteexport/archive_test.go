@@ -0,0 +1,79 @@
+package siteexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	version := "1.0.0"
+	want := &Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: now,
+		Campaigns: []*Campaign{{
+			ID:           1,
+			Name:         "c",
+			Author:       "alice",
+			NamespaceOrg: "acme",
+			ChangesetIDs: []int64{2},
+			Spec:         json.RawMessage(`{"version":1}`),
+			Position:     "V",
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			ClosedAt:     &now,
+		}},
+		Changesets: []*Changeset{{
+			ID:                  2,
+			Repository:          "github.com/a/b",
+			ExternalServiceType: "github",
+			ExternalID:          "12",
+			CampaignIDs:         []int64{1},
+			Metadata:            json.RawMessage(`{"Number":12}`),
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}},
+		LSIFUploads: []*LSIFUpload{{
+			ID:         3,
+			Repository: "github.com/a/b",
+			Commit:     strings.Repeat("a", 40),
+			Filename:   "upload.lsif",
+			State:      "completed",
+			UploadedAt: now,
+			FinishedAt: &now,
+			Packages:   []*LSIFPackage{{Scheme: "npm", Name: "b", Version: &version}},
+			References: []*LSIFReference{{Scheme: "npm", Name: "c", Filter: []byte{0, 1, 255}}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	have, err := ReadArchive(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestReadArchive_Version(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, &Archive{Version: ArchiveVersion + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadArchive(&buf); err == nil {
+		t.Error("want error for archive of unsupported version")
+	}
+
+	if _, err := ReadArchive(strings.NewReader(`{"version":1}`)); err == nil {
+		t.Error("want error for archive that isn't gzipped")
+	}
+}
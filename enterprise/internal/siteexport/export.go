@@ -0,0 +1,319 @@
+package siteexport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// Export returns an Archive of the campaigns and changesets in the given
+// Store and of the metadata of the processed LSIF uploads in its database.
+// Deleted campaigns aren't exported, nor are the changesets and uploads of
+// deleted repositories. Uploads that are still queued or processing aren't
+// exported either, since only the LSIF server they were uploaded to has
+// their files.
+//
+// 🚨 SECURITY: It is the caller's responsibility to ensure the current user is a site admin.
+func Export(ctx context.Context, store *ee.Store) (*Archive, error) {
+	e := &exporter{
+		store:     store,
+		usernames: map[int32]string{},
+		orgNames:  map[int32]string{},
+	}
+
+	a := &Archive{Version: ArchiveVersion, ExportedAt: store.Clock()()}
+
+	var err error
+	if a.Campaigns, err = e.exportCampaigns(ctx); err != nil {
+		return nil, errors.Wrap(err, "exporting campaigns")
+	}
+
+	changesets, _, err := store.ListChangesets(ctx, ee.ListChangesetsOpts{Limit: -1})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing changesets")
+	}
+	uploads, err := listLSIFUploads(ctx, store.DB())
+	if err != nil {
+		return nil, errors.Wrap(err, "listing LSIF uploads")
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(changesets)+len(uploads))
+	for _, c := range changesets {
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+	for _, u := range uploads {
+		repoIDs = append(repoIDs, u.repoID)
+	}
+	repos, err := db.Repos.GetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting repositories")
+	}
+	repoNames := make(map[api.RepoID]api.RepoName, len(repos))
+	for _, r := range repos {
+		repoNames[r.ID] = r.Name
+	}
+
+	a.Changesets = make([]*Changeset, 0, len(changesets))
+	for _, c := range changesets {
+		name, ok := repoNames[c.RepoID]
+		if !ok {
+			continue
+		}
+
+		metadata, err := json.Marshal(c.Metadata)
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshaling metadata of changeset %d", c.ID)
+		}
+
+		ec := &Changeset{
+			ID:                  c.ID,
+			Repository:          name,
+			ExternalServiceType: c.ExternalServiceType,
+			ExternalID:          c.ExternalID,
+			ExternalBranch:      c.ExternalBranch,
+			CampaignIDs:         c.CampaignIDs,
+			Metadata:            metadata,
+			CreatedAt:           c.CreatedAt,
+			UpdatedAt:           c.UpdatedAt,
+		}
+		if !c.ExternalDeletedAt.IsZero() {
+			deletedAt := c.ExternalDeletedAt
+			ec.ExternalDeletedAt = &deletedAt
+		}
+		a.Changesets = append(a.Changesets, ec)
+	}
+
+	a.LSIFUploads = make([]*LSIFUpload, 0, len(uploads))
+	for _, u := range uploads {
+		if name, ok := repoNames[u.repoID]; ok {
+			u.Repository = name
+			a.LSIFUploads = append(a.LSIFUploads, &u.LSIFUpload)
+		}
+	}
+
+	return a, nil
+}
+
+type exporter struct {
+	store *ee.Store
+
+	// usernames and orgNames cache the names of the users and orgs that own
+	// or authored the exported campaigns. Deleted ones have empty names.
+	usernames map[int32]string
+	orgNames  map[int32]string
+}
+
+func (e *exporter) exportCampaigns(ctx context.Context) ([]*Campaign, error) {
+	var campaigns []*Campaign
+
+	opts := ee.ListCampaignsOpts{Limit: 1000}
+	for {
+		cs, next, err := e.store.ListCampaigns(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range cs {
+			ec := &Campaign{
+				ID:                   c.ID,
+				Name:                 c.Name,
+				Description:          c.Description,
+				Branch:               c.Branch,
+				ChangesetIDs:         c.ChangesetIDs,
+				Spec:                 c.Spec,
+				RollbackOfCampaignID: c.RollbackOfCampaignID,
+				ParentCampaignID:     c.ParentCampaignID,
+				AutoRebase:           c.AutoRebase,
+				Position:             c.Position,
+				CreatedAt:            c.CreatedAt,
+				UpdatedAt:            c.UpdatedAt,
+			}
+			if !c.ClosedAt.IsZero() {
+				closedAt := c.ClosedAt
+				ec.ClosedAt = &closedAt
+			}
+
+			if ec.Author, err = e.username(ctx, c.AuthorID); err != nil {
+				return nil, err
+			}
+			if c.NamespaceUserID != 0 {
+				ec.NamespaceUser, err = e.username(ctx, c.NamespaceUserID)
+			} else {
+				ec.NamespaceOrg, err = e.orgName(ctx, c.NamespaceOrgID)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			campaigns = append(campaigns, ec)
+		}
+
+		if next == 0 {
+			return campaigns, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+func (e *exporter) username(ctx context.Context, id int32) (string, error) {
+	if name, ok := e.usernames[id]; ok {
+		return name, nil
+	}
+	user, err := db.Users.GetByID(ctx, id)
+	if err != nil && !errcode.IsNotFound(err) {
+		return "", err
+	}
+	if user != nil {
+		e.usernames[id] = user.Username
+	}
+	return e.usernames[id], nil
+}
+
+func (e *exporter) orgName(ctx context.Context, id int32) (string, error) {
+	if name, ok := e.orgNames[id]; ok {
+		return name, nil
+	}
+	org, err := db.Orgs.GetByID(ctx, id)
+	if _, notFound := err.(*db.OrgNotFoundError); err != nil && !notFound {
+		return "", err
+	}
+	if org != nil {
+		e.orgNames[id] = org.Name
+	}
+	return e.orgNames[id], nil
+}
+
+// lsifUpload is an exported LSIFUpload with the ID of its repository, which
+// is replaced by its name once the repositories of all uploads are known.
+type lsifUpload struct {
+	LSIFUpload
+	repoID api.RepoID
+}
+
+// listLSIFUploads returns the completed and errored LSIF uploads in db with
+// their packages and references, ordered by ID.
+func listLSIFUploads(ctx context.Context, db dbutil.DB) ([]*lsifUpload, error) {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/siteexport/export.go:listLSIFUploads
+SELECT
+  id,
+  repository_id,
+  commit,
+  root,
+  indexer,
+  filename,
+  state,
+  failure_summary,
+  failure_stacktrace,
+  visible_at_tip,
+  uploaded_at,
+  started_at,
+  finished_at
+FROM lsif_uploads
+WHERE state IN ('completed', 'errored')
+ORDER BY id
+`)
+
+	var uploads []*lsifUpload
+	byID := map[int64]*lsifUpload{}
+	err := query(ctx, db, q, func(sc *sql.Rows) error {
+		var u lsifUpload
+		err := sc.Scan(
+			&u.ID,
+			&u.repoID,
+			&u.Commit,
+			&u.Root,
+			&u.Indexer,
+			&u.Filename,
+			&u.State,
+			&u.FailureSummary,
+			&u.FailureStacktrace,
+			&u.VisibleAtTip,
+			&u.UploadedAt,
+			&u.StartedAt,
+			&u.FinishedAt,
+		)
+		if err != nil {
+			return err
+		}
+		uploads = append(uploads, &u)
+		byID[u.ID] = &u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q = sqlf.Sprintf(`
+-- source: enterprise/internal/siteexport/export.go:listLSIFUploads
+SELECT dump_id, scheme, name, version FROM lsif_packages ORDER BY id
+`)
+	err = query(ctx, db, q, func(sc *sql.Rows) error {
+		var (
+			dumpID int64
+			p      LSIFPackage
+		)
+		if err := sc.Scan(&dumpID, &p.Scheme, &p.Name, &p.Version); err != nil {
+			return err
+		}
+		if u, ok := byID[dumpID]; ok {
+			u.Packages = append(u.Packages, &p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q = sqlf.Sprintf(`
+-- source: enterprise/internal/siteexport/export.go:listLSIFUploads
+SELECT dump_id, scheme, name, version, filter FROM lsif_references ORDER BY id
+`)
+	err = query(ctx, db, q, func(sc *sql.Rows) error {
+		var (
+			dumpID int64
+			r      LSIFReference
+		)
+		if err := sc.Scan(&dumpID, &r.Scheme, &r.Name, &r.Version, &r.Filter); err != nil {
+			return err
+		}
+		if u, ok := byID[dumpID]; ok {
+			u.References = append(u.References, &r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// query runs q on db and calls scan for each row of the result. A nil scan
+// ignores the result.
+func query(ctx context.Context, db dbutil.DB, q *sqlf.Query, scan func(*sql.Rows) error) (err error) {
+	rows, err := db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() && scan != nil {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,84 @@
+package siteexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/httpapi"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// NewSiteExportAPI returns the endpoints that export the data in the given
+// Store into an Archive and import such an Archive.
+func NewSiteExportAPI(store *ee.Store) *httpapi.SiteExportAPI {
+	return &httpapi.SiteExportAPI{
+		ExportHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serveExport(store, w, r)
+		}),
+		ImportHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serveImport(store, w, r)
+		}),
+	}
+}
+
+func serveExport(store *ee.Store, w http.ResponseWriter, r *http.Request) {
+	// 🚨 SECURITY: The archive contains the data of all users, so only site
+	// admins may export it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(r.Context()); err != nil {
+		writeAccessError(w, err)
+		return
+	}
+
+	a, err := Export(r.Context(), store)
+	if err != nil {
+		log15.Error("site export", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("sourcegraph-export-%s.json.gz", a.ExportedAt.UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := WriteArchive(w, a); err != nil {
+		log15.Error("site export: writing archive", "error", err)
+	}
+}
+
+func serveImport(store *ee.Store, w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Importing creates campaigns on behalf of other users, so
+	// only site admins may do it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		writeAccessError(w, err)
+		return
+	}
+
+	a, err := ReadArchive(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := Import(ctx, store, a, actor.FromContext(ctx).UID)
+	if err != nil {
+		log15.Error("site import", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func writeAccessError(w http.ResponseWriter, err error) {
+	status := http.StatusForbidden
+	if err == backend.ErrNotAuthenticated {
+		status = http.StatusUnauthorized
+	}
+	http.Error(w, err.Error(), status)
+}
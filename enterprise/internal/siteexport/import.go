@@ -0,0 +1,460 @@
+package siteexport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	ee "github.com/sourcegraph/sourcegraph/enterprise/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+// ImportResult is the number of records created or updated by an import.
+type ImportResult struct {
+	Campaigns   int `json:"campaigns"`
+	Changesets  int `json:"changesets"`
+	LSIFUploads int `json:"lsifUploads"`
+	// Warnings describe the records that were skipped or imported with
+	// changes, e.g. because their repository doesn't exist.
+	Warnings []string `json:"warnings"`
+}
+
+// Import imports the records of the given Archive in a single transaction.
+//
+// Users, orgs and repositories are looked up by name. Campaigns whose
+// namespace or author doesn't exist are imported into the namespace of the
+// user with the given ID, and campaigns named like another campaign in their
+// namespace are skipped. Changesets that already exist are associated with
+// the imported campaigns. Changesets and LSIF uploads of repositories that
+// don't exist are skipped, as are uploads whose ID is taken or that conflict
+// with an upload of the same repository, commit and root.
+//
+// 🚨 SECURITY: It is the caller's responsibility to ensure the current user is a site admin.
+func Import(ctx context.Context, store *ee.Store, a *Archive, userID int32) (_ *ImportResult, err error) {
+	tx, err := store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	i := &importer{
+		tx:      tx,
+		userID:  userID,
+		res:     &ImportResult{Warnings: []string{}},
+		userIDs: map[string]int32{},
+		orgIDs:  map[string]int32{},
+		repoIDs: map[api.RepoName]api.RepoID{},
+	}
+
+	changesets, err := i.importChangesets(ctx, a.Changesets)
+	if err != nil {
+		return nil, errors.Wrap(err, "importing changesets")
+	}
+	campaignIDs, err := i.importCampaigns(ctx, a.Campaigns, changesets)
+	if err != nil {
+		return nil, errors.Wrap(err, "importing campaigns")
+	}
+	if err := i.linkChangesets(ctx, a.Changesets, changesets, campaignIDs); err != nil {
+		return nil, errors.Wrap(err, "associating changesets with campaigns")
+	}
+	if err := i.importLSIFUploads(ctx, a.LSIFUploads); err != nil {
+		return nil, errors.Wrap(err, "importing LSIF uploads")
+	}
+
+	return i.res, nil
+}
+
+type importer struct {
+	tx *ee.Store
+	// userID is the ID of the importing user.
+	userID int32
+	res    *ImportResult
+
+	// userIDs, orgIDs and repoIDs cache the IDs of the users, orgs and
+	// repositories referred to by the archive. Missing ones have ID 0.
+	userIDs map[string]int32
+	orgIDs  map[string]int32
+	repoIDs map[api.RepoName]api.RepoID
+}
+
+func (i *importer) warnf(format string, args ...interface{}) {
+	i.res.Warnings = append(i.res.Warnings, fmt.Sprintf(format, args...))
+}
+
+// importChangesets creates the given changesets without their campaigns,
+// which are added by linkChangesets once the campaigns are imported. It
+// returns the created or already existing changesets by their IDs in the
+// archive.
+func (i *importer) importChangesets(ctx context.Context, ecs []*Changeset) (map[int64]*a8n.Changeset, error) {
+	cs := make([]*a8n.Changeset, 0, len(ecs))
+	ids := make([]int64, 0, len(ecs))
+	for _, ec := range ecs {
+		repoID, err := i.lookupRepoID(ctx, ec.Repository)
+		if err != nil {
+			return nil, err
+		}
+		if repoID == 0 {
+			i.warnf("skipped changeset %s of repository %s, which doesn't exist", ec.ExternalID, ec.Repository)
+			continue
+		}
+
+		var metadata interface{}
+		switch ec.ExternalServiceType {
+		case github.ServiceType:
+			metadata = new(github.PullRequest)
+		case bitbucketserver.ServiceType:
+			metadata = new(bitbucketserver.PullRequest)
+		default:
+			i.warnf("skipped changeset %s of repository %s, which has unknown external service type %q", ec.ExternalID, ec.Repository, ec.ExternalServiceType)
+			continue
+		}
+		if err := json.Unmarshal(ec.Metadata, metadata); err != nil {
+			return nil, errors.Wrapf(err, "decoding metadata of changeset %d", ec.ID)
+		}
+
+		c := &a8n.Changeset{
+			RepoID:              repoID,
+			CreatedAt:           ec.CreatedAt,
+			UpdatedAt:           ec.UpdatedAt,
+			Metadata:            metadata,
+			ExternalID:          ec.ExternalID,
+			ExternalServiceType: ec.ExternalServiceType,
+			ExternalBranch:      ec.ExternalBranch,
+		}
+		if ec.ExternalDeletedAt != nil {
+			c.ExternalDeletedAt = *ec.ExternalDeletedAt
+		}
+		cs = append(cs, c)
+		ids = append(ids, ec.ID)
+	}
+
+	byID := make(map[int64]*a8n.Changeset, len(cs))
+	if len(cs) == 0 {
+		return byID, nil
+	}
+
+	// Existing changesets are returned in place of the given ones.
+	err := i.tx.CreateChangesets(ctx, cs...)
+	if _, exist := err.(ee.AlreadyExistError); err != nil && !exist {
+		return nil, err
+	}
+
+	for k, c := range cs {
+		byID[ids[k]] = c
+	}
+	i.res.Changesets = len(cs)
+	return byID, nil
+}
+
+// importCampaigns creates the given campaigns with the given changesets and
+// returns the IDs of the created campaigns by their IDs in the archive.
+func (i *importer) importCampaigns(ctx context.Context, ecs []*Campaign, changesets map[int64]*a8n.Changeset) (map[int64]int64, error) {
+	ids := make(map[int64]int64, len(ecs))
+	for _, ec := range orderCampaigns(ecs) {
+		c := &a8n.Campaign{
+			Name:        ec.Name,
+			Description: ec.Description,
+			Branch:      ec.Branch,
+			Spec:        ec.Spec,
+			AutoRebase:  ec.AutoRebase,
+			Position:    ec.Position,
+			CreatedAt:   ec.CreatedAt,
+			UpdatedAt:   ec.UpdatedAt,
+		}
+		if ec.ClosedAt != nil {
+			c.ClosedAt = *ec.ClosedAt
+		}
+
+		var err error
+		if c.NamespaceUserID, c.NamespaceOrgID, err = i.namespace(ctx, ec); err != nil {
+			return nil, err
+		}
+
+		_, renamed, err := i.tx.GetCampaignByName(ctx, ee.GetCampaignByNameOpts{
+			Name:            c.Name,
+			NamespaceUserID: c.NamespaceUserID,
+			NamespaceOrgID:  c.NamespaceOrgID,
+		})
+		if err == nil && !renamed {
+			i.warnf("skipped campaign %q, since a campaign with the same name exists in its namespace", ec.Name)
+			continue
+		}
+		if err != nil && err != ee.ErrNoResults {
+			return nil, err
+		}
+
+		if c.AuthorID, err = i.lookupUserID(ctx, ec.Author); err != nil {
+			return nil, err
+		}
+		if c.AuthorID == 0 {
+			c.AuthorID = i.userID
+			i.warnf("made you the author of campaign %q, since its author doesn't exist", ec.Name)
+		}
+
+		if ec.ParentCampaignID != 0 {
+			if c.ParentCampaignID = ids[ec.ParentCampaignID]; c.ParentCampaignID == 0 {
+				i.warnf("imported campaign %q without its parent campaign, which wasn't imported", ec.Name)
+			}
+		}
+		if ec.RollbackOfCampaignID != 0 {
+			if c.RollbackOfCampaignID = ids[ec.RollbackOfCampaignID]; c.RollbackOfCampaignID == 0 {
+				i.warnf("imported campaign %q without the campaign it rolls back, which wasn't imported", ec.Name)
+			}
+		}
+
+		for _, id := range ec.ChangesetIDs {
+			if cs, ok := changesets[id]; ok {
+				c.ChangesetIDs = append(c.ChangesetIDs, cs.ID)
+			}
+		}
+
+		if err := i.tx.CreateCampaign(ctx, c); err != nil {
+			return nil, err
+		}
+		ids[ec.ID] = c.ID
+		i.res.Campaigns++
+	}
+	return ids, nil
+}
+
+// orderCampaigns returns the given campaigns ordered so that each campaign
+// comes after its parent campaign and the campaign it rolls back, so that
+// these are imported first.
+func orderCampaigns(ecs []*Campaign) []*Campaign {
+	byID := make(map[int64]*Campaign, len(ecs))
+	for _, ec := range ecs {
+		byID[ec.ID] = ec
+	}
+
+	ordered := make([]*Campaign, 0, len(ecs))
+	visited := make(map[int64]bool, len(ecs))
+	var visit func(ec *Campaign)
+	visit = func(ec *Campaign) {
+		if visited[ec.ID] {
+			return
+		}
+		visited[ec.ID] = true
+		for _, id := range []int64{ec.ParentCampaignID, ec.RollbackOfCampaignID} {
+			if ref, ok := byID[id]; ok {
+				visit(ref)
+			}
+		}
+		ordered = append(ordered, ec)
+	}
+	for _, ec := range ecs {
+		visit(ec)
+	}
+	return ordered
+}
+
+// namespace returns the IDs of the user or org namespace of the given
+// campaign, falling back to the namespace of the importing user.
+func (i *importer) namespace(ctx context.Context, ec *Campaign) (userID, orgID int32, err error) {
+	switch {
+	case ec.NamespaceOrg != "":
+		if orgID, err = i.lookupOrgID(ctx, ec.NamespaceOrg); err != nil || orgID != 0 {
+			return 0, orgID, err
+		}
+	case ec.NamespaceUser != "":
+		if userID, err = i.lookupUserID(ctx, ec.NamespaceUser); err != nil || userID != 0 {
+			return userID, 0, err
+		}
+	}
+	i.warnf("imported campaign %q into your namespace, since its namespace doesn't exist", ec.Name)
+	return i.userID, 0, nil
+}
+
+// linkChangesets adds the imported campaigns to the campaigns of the
+// imported changesets.
+func (i *importer) linkChangesets(ctx context.Context, ecs []*Changeset, changesets map[int64]*a8n.Changeset, campaignIDs map[int64]int64) error {
+	var updated []*a8n.Changeset
+	for _, ec := range ecs {
+		c, ok := changesets[ec.ID]
+		if !ok {
+			continue
+		}
+
+		n := len(c.CampaignIDs)
+		for _, id := range ec.CampaignIDs {
+			if campaignID, ok := campaignIDs[id]; ok {
+				c.CampaignIDs = append(c.CampaignIDs, campaignID)
+			}
+		}
+		if len(c.CampaignIDs) > n {
+			updated = append(updated, c)
+		}
+	}
+
+	if len(updated) == 0 {
+		return nil
+	}
+	return i.tx.UpdateChangesets(ctx, updated...)
+}
+
+// importLSIFUploads creates the given LSIF uploads with their packages and
+// references, keeping their IDs.
+func (i *importer) importLSIFUploads(ctx context.Context, us []*LSIFUpload) error {
+	for _, u := range us {
+		repoID, err := i.lookupRepoID(ctx, u.Repository)
+		if err != nil {
+			return err
+		}
+		if repoID == 0 {
+			i.warnf("skipped LSIF upload %d of repository %s, which doesn't exist", u.ID, u.Repository)
+			continue
+		}
+
+		q := sqlf.Sprintf(
+			importLSIFUploadQueryFmtstr,
+			u.ID,
+			repoID,
+			u.Commit,
+			u.Root,
+			u.Indexer,
+			u.Filename,
+			u.State,
+			u.FailureSummary,
+			u.FailureStacktrace,
+			u.VisibleAtTip,
+			u.UploadedAt,
+			u.StartedAt,
+			u.FinishedAt,
+		)
+		var inserted bool
+		err = query(ctx, i.tx.DB(), q, func(*sql.Rows) error {
+			inserted = true
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "creating LSIF upload %d", u.ID)
+		}
+		if !inserted {
+			i.warnf("skipped LSIF upload %d of repository %s, since it conflicts with an existing upload", u.ID, u.Repository)
+			continue
+		}
+
+		if len(u.Packages) > 0 {
+			values := make([]*sqlf.Query, 0, len(u.Packages))
+			for _, p := range u.Packages {
+				values = append(values, sqlf.Sprintf("(%s, %s, %s, %s)", p.Scheme, p.Name, p.Version, u.ID))
+			}
+			q := sqlf.Sprintf(importLSIFPackagesQueryFmtstr, sqlf.Join(values, ",\n"))
+			if err := query(ctx, i.tx.DB(), q, nil); err != nil {
+				return errors.Wrapf(err, "creating packages of LSIF upload %d", u.ID)
+			}
+		}
+
+		if len(u.References) > 0 {
+			values := make([]*sqlf.Query, 0, len(u.References))
+			for _, r := range u.References {
+				values = append(values, sqlf.Sprintf("(%s, %s, %s, %s, %s)", r.Scheme, r.Name, r.Version, r.Filter, u.ID))
+			}
+			q := sqlf.Sprintf(importLSIFReferencesQueryFmtstr, sqlf.Join(values, ",\n"))
+			if err := query(ctx, i.tx.DB(), q, nil); err != nil {
+				return errors.Wrapf(err, "creating references of LSIF upload %d", u.ID)
+			}
+		}
+
+		i.res.LSIFUploads++
+	}
+
+	if i.res.LSIFUploads == 0 {
+		return nil
+	}
+	// Uploads created later must not take the kept IDs.
+	return query(ctx, i.tx.DB(), sqlf.Sprintf(advanceLSIFUploadIDSequenceQuery), nil)
+}
+
+var importLSIFUploadQueryFmtstr = `
+-- source: enterprise/internal/siteexport/import.go:importLSIFUploads
+INSERT INTO lsif_uploads (
+  id,
+  repository_id,
+  commit,
+  root,
+  indexer,
+  filename,
+  state,
+  failure_summary,
+  failure_stacktrace,
+  visible_at_tip,
+  uploaded_at,
+  started_at,
+  finished_at,
+  tracing_context
+)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, '{}')
+ON CONFLICT DO NOTHING
+RETURNING id
+`
+
+var importLSIFPackagesQueryFmtstr = `
+-- source: enterprise/internal/siteexport/import.go:importLSIFUploads
+INSERT INTO lsif_packages (scheme, name, version, dump_id)
+VALUES %s
+ON CONFLICT DO NOTHING
+`
+
+var importLSIFReferencesQueryFmtstr = `
+-- source: enterprise/internal/siteexport/import.go:importLSIFUploads
+INSERT INTO lsif_references (scheme, name, version, filter, dump_id)
+VALUES %s
+`
+
+const advanceLSIFUploadIDSequenceQuery = `
+-- source: enterprise/internal/siteexport/import.go:importLSIFUploads
+SELECT setval('lsif_dumps_id_seq', GREATEST(
+  (SELECT max(id) FROM lsif_uploads),
+  (SELECT last_value FROM lsif_dumps_id_seq)
+))
+`
+
+func (i *importer) lookupUserID(ctx context.Context, username string) (int32, error) {
+	if id, ok := i.userIDs[username]; ok || username == "" {
+		return id, nil
+	}
+	user, err := db.Users.GetByUsername(ctx, username)
+	if err != nil && !errcode.IsNotFound(err) {
+		return 0, err
+	}
+	if user != nil {
+		i.userIDs[username] = user.ID
+	}
+	return i.userIDs[username], nil
+}
+
+func (i *importer) lookupOrgID(ctx context.Context, name string) (int32, error) {
+	if id, ok := i.orgIDs[name]; ok {
+		return id, nil
+	}
+	org, err := db.Orgs.GetByName(ctx, name)
+	if _, notFound := err.(*db.OrgNotFoundError); err != nil && !notFound {
+		return 0, err
+	}
+	if org != nil {
+		i.orgIDs[name] = org.ID
+	}
+	return i.orgIDs[name], nil
+}
+
+func (i *importer) lookupRepoID(ctx context.Context, name api.RepoName) (api.RepoID, error) {
+	if id, ok := i.repoIDs[name]; ok {
+		return id, nil
+	}
+	repo, err := db.Repos.GetByName(ctx, name)
+	if err != nil && !errcode.IsNotFound(err) {
+		return 0, err
+	}
+	if repo != nil {
+		i.repoIDs[name] = repo.ID
+	}
+	return i.repoIDs[name], nil
+}
@@ -0,0 +1,27 @@
+package siteexport
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOrderCampaigns(t *testing.T) {
+	campaigns := []*Campaign{
+		{ID: 1, ParentCampaignID: 3},
+		{ID: 2, RollbackOfCampaignID: 1},
+		{ID: 3},
+		{ID: 4, ParentCampaignID: 7}, // The parent isn't in the archive.
+		{ID: 5, ParentCampaignID: 6},
+		{ID: 6, ParentCampaignID: 5}, // Cycles don't hang.
+	}
+
+	var have []int64
+	for _, c := range orderCampaigns(campaigns) {
+		have = append(have, c.ID)
+	}
+	want := []int64{3, 1, 2, 4, 6, 5}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Error(diff)
+	}
+}
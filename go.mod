@@ -100,7 +100,7 @@ require (
 	github.com/keegancsmith/sqlf v1.1.0
 	github.com/keegancsmith/tmpfriend v0.0.0-20180423180255-86e88902a513
 	github.com/kevinburke/go-bindata v3.16.0+incompatible
-	github.com/klauspost/compress v1.9.7 // indirect
+	github.com/klauspost/compress v1.9.7
 	github.com/kr/pretty v0.2.0 // indirect
 	github.com/kr/text v0.1.0
 	github.com/kylelemons/godebug v1.1.0
@@ -1,12 +1,14 @@
 package a8n
 
 import (
+	"encoding/json"
 	"reflect"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -86,6 +88,16 @@ func (c *CampaignJob) Clone() *CampaignJob {
 	return &cc
 }
 
+// A CampaignJobLogChunk is a part of the output written while a CampaignJob
+// was executed on the server. The log of the job is the concatenation of its
+// chunks, ordered by ID.
+type CampaignJobLogChunk struct {
+	ID            int64
+	CampaignJobID int64
+	Output        string
+	CreatedAt     time.Time
+}
+
 // A Campaign of changesets over multiple Repos over time.
 type Campaign struct {
 	ID              int64
@@ -100,6 +112,27 @@ type Campaign struct {
 	ChangesetIDs    []int64
 	CampaignPlanID  int64
 	ClosedAt        time.Time
+
+	// Spec is the campaign spec describing how the changesets of the campaign
+	// are produced, as JSON, or nil if the campaign has none.
+	Spec json.RawMessage
+
+	// RollbackOfCampaignID is the ID of the campaign whose merged changesets
+	// are reverted by this campaign, if it's a rollback campaign.
+	RollbackOfCampaignID int64
+
+	// ParentCampaignID is the ID of the umbrella campaign grouping this
+	// campaign with related ones, if any.
+	ParentCampaignID int64
+
+	// AutoRebase is whether the open changesets of the campaign are rebased
+	// onto their base branch when they have conflicts with it or fall behind
+	// it.
+	AutoRebase bool
+
+	// Position orders the campaign among the others in the same state, e.g.
+	// in a column of a board, by comparing it bytewise to their positions.
+	Position string
 }
 
 // Clone returns a clone of a Campaign.
@@ -159,6 +192,33 @@ const (
 	CampaignStateClosed CampaignState = "CLOSED"
 )
 
+// CampaignInvolvement defines how a user is involved in a Campaign.
+type CampaignInvolvement string
+
+const (
+	// CampaignInvolvementAuthor is the involvement of the author of a Campaign.
+	CampaignInvolvementAuthor CampaignInvolvement = "AUTHOR"
+	// CampaignInvolvementReviewer is the involvement of a user that was
+	// requested to review, or reviewed, a Changeset of a Campaign on the code
+	// host.
+	CampaignInvolvementReviewer CampaignInvolvement = "REVIEWER"
+	// CampaignInvolvementSubscriber is the involvement of a user that is
+	// subscribed to a Campaign (see CampaignSubscription).
+	CampaignInvolvementSubscriber CampaignInvolvement = "SUBSCRIBER"
+)
+
+// Valid returns true if the given CampaignInvolvement is valid.
+func (i CampaignInvolvement) Valid() bool {
+	switch i {
+	case CampaignInvolvementAuthor,
+		CampaignInvolvementReviewer,
+		CampaignInvolvementSubscriber:
+		return true
+	default:
+		return false
+	}
+}
+
 // BackgroundProcessStatus defines the status of a background process.
 type BackgroundProcessStatus struct {
 	Canceled      bool
@@ -193,6 +253,296 @@ const (
 	// Remember to update Finished() above if a new state is added
 )
 
+// CampaignMetricsPeriod holds aggregate campaign metrics of the period that
+// starts at StartTime.
+type CampaignMetricsPeriod struct {
+	StartTime        time.Time
+	CampaignsCreated int32
+	ChangesetsMerged int32
+
+	// MedianTimeToMerge is the median time between the creation of a
+	// changeset on its codehost and its merge, across all changesets merged
+	// in this period. It is nil if no changeset was merged.
+	MedianTimeToMerge *time.Duration
+}
+
+// CampaignMetricsPeriodType is the length of a CampaignMetricsPeriod.
+type CampaignMetricsPeriodType string
+
+// CampaignMetricsPeriodType constants. The values are valid date_trunc
+// fields in Postgres.
+const (
+	CampaignMetricsPeriodDay   CampaignMetricsPeriodType = "day"
+	CampaignMetricsPeriodWeek  CampaignMetricsPeriodType = "week"
+	CampaignMetricsPeriodMonth CampaignMetricsPeriodType = "month"
+)
+
+// CampaignFacets holds the number of Campaigns in each state and the number
+// of Campaigns created by each author.
+type CampaignFacets struct {
+	States  map[CampaignState]int32
+	Authors map[int32]int32
+}
+
+// CampaignRepositoryActivity holds the campaign activity in a repository:
+// the number of open Campaigns with a Changeset in it, the number of its
+// open Changesets and the number of its Changesets merged this week.
+type CampaignRepositoryActivity struct {
+	RepoID api.RepoID
+
+	OpenCampaigns            int32
+	OpenChangesets           int32
+	ChangesetsMergedThisWeek int32
+}
+
+// CampaignProgress holds the number of changesets in each state of a
+// Campaign and of all the Campaigns below it in the hierarchy of campaigns.
+// Changesets belonging to several of these Campaigns are counted once.
+type CampaignProgress struct {
+	Total   int32
+	Open    int32
+	Merged  int32
+	Closed  int32
+	Deleted int32
+}
+
+// A CampaignSavedFilter is a named combination of filters of a list of
+// Campaigns, saved by a user to get back to the same view of the list.
+type CampaignSavedFilter struct {
+	ID      int64
+	UserID  int32
+	Name    string
+	Filters CampaignListFilters
+
+	// IsDefault is true for the view shown when the user opens the list of
+	// Campaigns. A user has at most one default CampaignSavedFilter.
+	IsDefault bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a CampaignSavedFilter.
+func (f *CampaignSavedFilter) Clone() *CampaignSavedFilter {
+	ff := *f
+	ff.Filters.NamespaceUserIDs = append([]int32(nil), f.Filters.NamespaceUserIDs...)
+	ff.Filters.NamespaceOrgIDs = append([]int32(nil), f.Filters.NamespaceOrgIDs...)
+	return &ff
+}
+
+// CampaignNamespaceSettings are the defaults of the Campaigns created in a
+// namespace, which is either a user or an org.
+type CampaignNamespaceSettings struct {
+	ID              int64
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+
+	// BranchPrefix is prepended to the branches of new Campaigns that don't
+	// start with it already.
+	BranchPrefix string
+	// DefaultLabels are added to the changesets created by the Campaigns, on
+	// code hosts that support labels.
+	DefaultLabels []string
+	// RequireApproval makes new Campaigns drafts, so that their changesets
+	// are only created once they're published.
+	RequireApproval bool
+	// NotificationChannel is the channel through which the
+	// CampaignNotifications about the Campaigns in the namespace are sent.
+	// NotificationWebhookURL is the URL they're posted to, unless they're
+	// sent by email.
+	NotificationChannel    CampaignNotificationChannel
+	NotificationWebhookURL string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a CampaignNamespaceSettings.
+func (s *CampaignNamespaceSettings) Clone() *CampaignNamespaceSettings {
+	ss := *s
+	ss.DefaultLabels = append([]string(nil), s.DefaultLabels...)
+	return &ss
+}
+
+// CampaignReadState is the state of a Campaign as seen by a user: when they
+// last viewed it and when the latest activity in it happened, i.e. its
+// creation, a Changeset being added to it or an event of one of its
+// Changesets being synced.
+type CampaignReadState struct {
+	CampaignID int64
+	UserID     int32
+
+	// LastViewedAt is zero if the user never viewed the Campaign.
+	LastViewedAt   time.Time
+	LastActivityAt time.Time
+}
+
+// HasUnreadActivity returns whether the Campaign had activity since the user
+// last viewed it. Campaigns that the user never viewed are unread.
+func (s *CampaignReadState) HasUnreadActivity() bool {
+	return s.LastViewedAt.IsZero() || s.LastActivityAt.After(s.LastViewedAt)
+}
+
+// CampaignNotificationKind is the kind of change of a Changeset that the
+// authors and subscribers of its Campaigns are notified about.
+type CampaignNotificationKind string
+
+// CampaignNotificationKind constants.
+const (
+	CampaignNotificationKindMerged       CampaignNotificationKind = "MERGED"
+	CampaignNotificationKindClosed       CampaignNotificationKind = "CLOSED"
+	CampaignNotificationKindChecksFailed CampaignNotificationKind = "CHECKS_FAILED"
+)
+
+// CampaignNotificationChannel is the transport through which the
+// CampaignNotifications about the Campaigns of a namespace are sent.
+type CampaignNotificationChannel string
+
+// CampaignNotificationChannel constants.
+const (
+	CampaignNotificationChannelEmail   CampaignNotificationChannel = "EMAIL"
+	CampaignNotificationChannelSlack   CampaignNotificationChannel = "SLACK"
+	CampaignNotificationChannelWebhook CampaignNotificationChannel = "WEBHOOK"
+)
+
+// Valid returns true if the given CampaignNotificationChannel is valid.
+func (c CampaignNotificationChannel) Valid() bool {
+	switch c {
+	case CampaignNotificationChannelEmail,
+		CampaignNotificationChannelSlack,
+		CampaignNotificationChannelWebhook:
+		return true
+	default:
+		return false
+	}
+}
+
+// A CampaignNotification notifies a user about a change of a Changeset of a
+// Campaign. Notifications are collected and sent in email digests.
+type CampaignNotification struct {
+	ID          int64
+	UserID      int32
+	CampaignID  int64
+	ChangesetID int64
+	Kind        CampaignNotificationKind
+	CreatedAt   time.Time
+	SentAt      time.Time
+}
+
+// CampaignNotificationSettings are the preferences of a user for the
+// notifications about the Changesets of Campaigns.
+type CampaignNotificationSettings struct {
+	UserID int32
+
+	NotifyMerged       bool
+	NotifyClosed       bool
+	NotifyChecksFailed bool
+
+	// DigestInterval is how long notifications are collected before they're
+	// sent in a single email, so that many changes of a Campaign don't cause
+	// a flood of emails.
+	DigestInterval time.Duration
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DefaultCampaignNotificationSettings returns the CampaignNotificationSettings
+// of a user that didn't change them.
+func DefaultCampaignNotificationSettings(userID int32) *CampaignNotificationSettings {
+	return &CampaignNotificationSettings{
+		UserID:             userID,
+		NotifyMerged:       true,
+		NotifyClosed:       true,
+		NotifyChecksFailed: true,
+		DigestInterval:     15 * time.Minute,
+	}
+}
+
+// A CampaignSubscription records whether a user is notified about the
+// Changesets of a Campaign. The author of a Campaign is subscribed unless
+// they unsubscribed explicitly.
+type CampaignSubscription struct {
+	CampaignID int64
+	UserID     int32
+	Subscribed bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// A CampaignWebhookDelivery is a payload posted to the Slack or generic
+// webhook of a namespace, e.g. a digest of CampaignNotifications. Every
+// attempt to post it is recorded as a CampaignWebhookDeliveryAttempt.
+type CampaignWebhookDelivery struct {
+	ID      int64
+	Channel CampaignNotificationChannel
+	URL     string
+
+	// RecipientUserID is the user whose notifications are delivered, or zero
+	// if the user was deleted.
+	RecipientUserID int32
+
+	Payload json.RawMessage
+
+	AttemptCount    int32
+	LastAttemptedAt time.Time
+	// SucceededAt is the time of the first successful attempt, or the zero
+	// time if every attempt failed.
+	SucceededAt time.Time
+
+	CreatedAt time.Time
+}
+
+// Succeeded returns whether the webhook accepted the payload in any attempt.
+func (d *CampaignWebhookDelivery) Succeeded() bool {
+	return !d.SucceededAt.IsZero()
+}
+
+// A CampaignWebhookDeliveryAttempt is an attempt to post the payload of a
+// CampaignWebhookDelivery to its webhook.
+type CampaignWebhookDeliveryAttempt struct {
+	ID         int64
+	DeliveryID int64
+	Succeeded  bool
+
+	// StatusCode is the HTTP status of the response of the webhook, or zero
+	// if it didn't respond.
+	StatusCode int32
+	// ResponseBody is the beginning of the body of the response.
+	ResponseBody string
+	// Error is the reason why the attempt failed.
+	Error string
+
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+// CampaignListFilters are the filters of a list of Campaigns. The zero value
+// doesn't filter out any Campaign.
+type CampaignListFilters struct {
+	Query             string         `json:"query,omitempty"`
+	State             CampaignState  `json:"state,omitempty"`
+	NamespaceUserIDs  []int32        `json:"namespaceUserIDs,omitempty"`
+	NamespaceOrgIDs   []int32        `json:"namespaceOrgIDs,omitempty"`
+	HasOpenChangesets bool           `json:"hasOpenChangesets,omitempty"`
+	ChangesetState    ChangesetState `json:"changesetState,omitempty"`
+}
+
+// An IdempotencyKey is a key supplied by a client with a mutation, which
+// records the results of the mutation so that retrying it with the same key
+// returns those results instead of running the mutation again.
+type IdempotencyKey struct {
+	UserID   int32
+	Mutation string
+	Key      string
+
+	// ResultIDs are the IDs of the entities created by the mutation. They
+	// are nil while the mutation is running.
+	ResultIDs []int64
+
+	CreatedAt time.Time
+}
+
 // ChangesetReviewState defines the possible states of a Changeset's review.
 type ChangesetReviewState string
 
@@ -240,12 +590,21 @@ type ChangesetJob struct {
 	ChangesetID int64
 
 	Branch string
+	// BranchCollision records what was done because the branch of the
+	// campaign already existed on the code host, if it did.
+	BranchCollision BranchCollision
 
 	Error string
 
 	StartedAt  time.Time
 	FinishedAt time.Time
 
+	// ReviewersRequestedAt is when the reviewers listed in the campaign spec
+	// were requested on the changeset. ReviewersError is the error of the
+	// last failed attempt to request them.
+	ReviewersRequestedAt time.Time
+	ReviewersError       string
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -261,6 +620,123 @@ func (c *ChangesetJob) SuccessfullyCompleted() bool {
 	return c.Error == "" && !c.FinishedAt.IsZero() && c.ChangesetID != 0
 }
 
+// BranchCollision is the decision taken by a ChangesetJob whose branch already
+// existed on the code host, according to the configured strategy.
+type BranchCollision string
+
+// BranchCollision constants.
+const (
+	// BranchCollisionSuffixed means that a new branch with a numeric suffix
+	// was pushed instead.
+	BranchCollisionSuffixed BranchCollision = "SUFFIXED"
+	// BranchCollisionReused means that the existing branch was force-pushed.
+	BranchCollisionReused BranchCollision = "REUSED"
+	// BranchCollisionFailed means that the ChangesetJob failed.
+	BranchCollisionFailed BranchCollision = "FAILED"
+)
+
+// A ChangesetCloseJob is the closing of a Changeset on its code host after
+// the Campaign it belongs to has been closed.
+type ChangesetCloseJob struct {
+	ID          int64
+	CampaignID  int64
+	ChangesetID int64
+
+	Error string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a ChangesetCloseJob.
+func (c *ChangesetCloseJob) Clone() *ChangesetCloseJob {
+	cc := *c
+	return &cc
+}
+
+// A WorkerJob is a job in one of the queues of background jobs, e.g. the
+// creation of a changeset from a ChangesetJob.
+type WorkerJob struct {
+	ID    int64
+	Queue string
+
+	// Payload is the JSON object describing the job, whose format depends on
+	// the Queue.
+	Payload json.RawMessage
+
+	State       WorkerJobState
+	Attempts    int32
+	MaxAttempts int32
+
+	// Error is the error of the last failed attempt.
+	Error string
+
+	RunAfter    time.Time
+	HeartbeatAt time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a WorkerJob.
+func (j *WorkerJob) Clone() *WorkerJob {
+	jj := *j
+	jj.Payload = append(json.RawMessage(nil), j.Payload...)
+	return &jj
+}
+
+// WorkerJobState defines the possible states of a WorkerJob.
+type WorkerJobState string
+
+// WorkerJobState constants.
+const (
+	// WorkerJobStateQueued is the state of jobs waiting to be run, including
+	// failed jobs that are retried.
+	WorkerJobStateQueued     WorkerJobState = "QUEUED"
+	WorkerJobStateProcessing WorkerJobState = "PROCESSING"
+	WorkerJobStateCompleted  WorkerJobState = "COMPLETED"
+	// WorkerJobStateDead is the state of jobs that failed on every attempt
+	// and aren't retried anymore.
+	WorkerJobStateDead WorkerJobState = "DEAD"
+)
+
+// Valid returns true if the given WorkerJobState is valid.
+func (s WorkerJobState) Valid() bool {
+	switch s {
+	case WorkerJobStateQueued,
+		WorkerJobStateProcessing,
+		WorkerJobStateCompleted,
+		WorkerJobStateDead:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkerQueueStats are the number of jobs in each state in a queue of
+// WorkerJobs.
+type WorkerQueueStats struct {
+	Queue string
+
+	Queued     int32
+	Processing int32
+	Completed  int32
+	Dead       int32
+
+	// Retrying is the number of queued jobs that already failed at least
+	// once.
+	Retrying int32
+
+	// OldestQueuedAt is the time the longest waiting queued job was
+	// enqueued, or the zero time if no job is queued.
+	OldestQueuedAt time.Time
+}
+
 // A Changeset is a changeset on a code host belonging to a Repository and many
 // Campaigns.
 type Changeset struct {
@@ -274,6 +750,16 @@ type Changeset struct {
 	ExternalServiceType string
 	ExternalBranch      string
 	ExternalDeletedAt   time.Time
+	DiffStatAdded       *int32
+	DiffStatChanged     *int32
+	DiffStatDeleted     *int32
+	// HasConflicts is whether the head of the Changeset has merge conflicts
+	// with its base branch, or nil if that hasn't been checked yet.
+	HasConflicts *bool
+	// ConflictsBaseOid and ConflictsHeadOid are the commits of the base branch
+	// and the head that HasConflicts was checked for.
+	ConflictsBaseOid string
+	ConflictsHeadOid string
 }
 
 // Clone returns a clone of a Changeset.
@@ -293,6 +779,30 @@ func (c *Changeset) RemoveCampaignID(id int64) {
 	}
 }
 
+// DiffStat returns the number of lines added, changed and deleted by the
+// Changeset. It returns nil when the diff stat hasn't been computed yet.
+func (c *Changeset) DiffStat() *diff.Stat {
+	if c.DiffStatAdded == nil || c.DiffStatChanged == nil || c.DiffStatDeleted == nil {
+		return nil
+	}
+	return &diff.Stat{
+		Added:   *c.DiffStatAdded,
+		Changed: *c.DiffStatChanged,
+		Deleted: *c.DiffStatDeleted,
+	}
+}
+
+// SetDiffStat sets the diff stat of the Changeset. A nil stat marks the diff
+// stat as needing to be computed.
+func (c *Changeset) SetDiffStat(stat *diff.Stat) {
+	if stat == nil {
+		c.DiffStatAdded, c.DiffStatChanged, c.DiffStatDeleted = nil, nil, nil
+		return
+	}
+	added, changed, deleted := stat.Added, stat.Changed, stat.Deleted
+	c.DiffStatAdded, c.DiffStatChanged, c.DiffStatDeleted = &added, &changed, &deleted
+}
+
 // Title of the Changeset.
 func (c *Changeset) Title() (string, error) {
 	switch m := c.Metadata.(type) {
@@ -319,6 +829,20 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 	}
 }
 
+// ExternalUpdatedAt is when the Changeset was last updated on the codehost.
+// When it cannot be determined when the changeset was last updated, a
+// zero-value timestamp is returned.
+func (c *Changeset) ExternalUpdatedAt() time.Time {
+	switch m := c.Metadata.(type) {
+	case *github.PullRequest:
+		return m.UpdatedAt
+	case *bitbucketserver.PullRequest:
+		return unixMilliToTime(int64(m.UpdatedDate))
+	default:
+		return time.Time{}
+	}
+}
+
 // Body of the Changeset.
 func (c *Changeset) Body() (string, error) {
 	switch m := c.Metadata.(type) {
@@ -974,6 +1498,8 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		return e.ReceivedAt
 	case *bitbucketserver.Activity:
 		t = unixMilliToTime(int64(e.CreatedDate))
+	case *ChangesetRebase:
+		t = e.CreatedAt
 	}
 
 	return t
@@ -1370,6 +1896,8 @@ func ChangesetEventKindFor(e interface{}) ChangesetEventKind {
 // ChangesetEventKind.
 func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 	switch {
+	case k == ChangesetEventKindSourcegraphRebased:
+		return new(ChangesetRebase), nil
 	case strings.HasPrefix(string(k), "bitbucketserver"):
 		return new(bitbucketserver.Activity), nil
 	case strings.HasPrefix(string(k), "github"):
@@ -1451,8 +1979,27 @@ const (
 	ChangesetEventKindBitbucketServerUpdated    ChangesetEventKind = "bitbucketserver:updated"
 	ChangesetEventKindBitbucketServerCommented  ChangesetEventKind = "bitbucketserver:commented"
 	ChangesetEventKindBitbucketServerMerged     ChangesetEventKind = "bitbucketserver:merged"
+
+	// ChangesetEventKindSourcegraphRebased is the kind of the ChangesetEvents
+	// recorded by Sourcegraph itself when it rebases a changeset of a
+	// Campaign with AutoRebase.
+	ChangesetEventKindSourcegraphRebased ChangesetEventKind = "sourcegraph:rebased"
 )
 
+// A ChangesetRebase is the metadata of a ChangesetEvent of kind
+// ChangesetEventKindSourcegraphRebased.
+type ChangesetRebase struct {
+	// BaseOid is the commit of the base branch that the changeset was
+	// rebased onto.
+	BaseOid string
+	// Reexecuted is whether the steps of the campaign spec were run again on
+	// BaseOid, rather than the diff of the changeset being applied to it.
+	Reexecuted bool
+	// Error is the error of the rebase, if it failed.
+	Error     string
+	CreatedAt time.Time
+}
+
 func unixMilliToTime(ms int64) time.Time {
 	return time.Unix(0, ms*int64(time.Millisecond))
 }
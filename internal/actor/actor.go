@@ -24,6 +24,11 @@ type Actor struct {
 	// to selectively display a logout link. (If the actor wasn't authenticated with a session
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
+
+	// Scopes restricts the actor to the given access token scopes. It is only set if the actor was
+	// authenticated with an access token that lacks the "user:all" scope. If empty, the actor is
+	// not restricted.
+	Scopes []string `json:",omitempty"`
 }
 
 // FromUser returns an actor corresponding to a user
@@ -41,6 +46,20 @@ func (a *Actor) IsAuthenticated() bool {
 	return a != nil && a.UID != 0
 }
 
+// HasScope reports whether the actor is allowed to perform actions that require the given access
+// token scope. Actors that are not restricted to a set of scopes have all scopes.
+func (a *Actor) HasScope(scope string) bool {
+	if a == nil || len(a.Scopes) == 0 {
+		return true
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 type key int
 
 const actorKey key = iota
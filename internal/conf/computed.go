@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
@@ -218,6 +219,35 @@ func AutomationReadAccessEnabled() bool {
 	return false
 }
 
+// AutomationDeletedCampaignRetention returns how long a deleted Automation
+// campaign can be restored before it is purged.
+func AutomationDeletedCampaignRetention() time.Duration {
+	days := 7
+	if v := Get().AutomationDeletedCampaignRetentionDays; v != nil {
+		days = *v
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// AutomationBranchCollisionStrategy returns what Automation campaigns do when
+// the branch of a changeset already exists on the codehost: "suffix" (the
+// default), "reuse" or "fail".
+func AutomationBranchCollisionStrategy() string {
+	if v := Get().AutomationBranchCollisionStrategy; v != "" {
+		return v
+	}
+	return "suffix"
+}
+
+// AutomationQuotas returns the configured Automation quotas. A zero limit
+// means that the quota is not enforced.
+func AutomationQuotas() schema.AutomationQuotas {
+	if q := Get().AutomationQuotas; q != nil {
+		return *q
+	}
+	return schema.AutomationQuotas{}
+}
+
 func UsingExternalURL() bool {
 	url := Get().ExternalURL
 	return !(url == "" || strings.HasPrefix(url, "http://localhost") || strings.HasPrefix(url, "https://localhost") || strings.HasPrefix(url, "http://127.0.0.1") || strings.HasPrefix(url, "https://127.0.0.1")) // CI:LOCALHOST_OK
@@ -265,6 +295,39 @@ func SearchSymbolsParallelism() int {
 	return val
 }
 
+// LSIFMaxCommitDistance returns the maximum number of commits visited to find
+// an LSIF upload near the requested commit of the given repository.
+func LSIFMaxCommitDistance(repo api.RepoName) int {
+	c := Get()
+	if val, ok := c.LsifMaxCommitDistanceByRepository[string(repo)]; ok && val > 0 {
+		return val
+	}
+	if c.LsifMaxCommitDistance <= 0 {
+		return 100
+	}
+	return c.LsifMaxCommitDistance
+}
+
+// LSIFMaxReferences returns the maximum number of locations returned by a
+// single LSIF references request.
+func LSIFMaxReferences() int {
+	val := Get().LsifMaxReferences
+	if val <= 0 {
+		return 10000
+	}
+	return val
+}
+
+// LSIFMaxUploadsPerQuery returns the maximum number of LSIF uploads queried for
+// a single code intelligence request.
+func LSIFMaxUploadsPerQuery() int {
+	val := Get().LsifMaxUploadsPerQuery
+	if val <= 0 {
+		return 3
+	}
+	return val
+}
+
 func BitbucketServerFastPerm() bool {
 	val := Get().ExperimentalFeatures.BitbucketServerFastPerm
 	if val == "" {
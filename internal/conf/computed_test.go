@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 
@@ -85,6 +86,52 @@ func TestSearchIndexEnabled(t *testing.T) {
 	}
 }
 
+func TestLSIFMaxReferences(t *testing.T) {
+	defer Mock(nil)
+
+	for _, tc := range []struct {
+		max  int
+		want int
+	}{
+		{max: 0, want: 10000},
+		{max: -1, want: 10000},
+		{max: 50, want: 50},
+	} {
+		Mock(&Unified{SiteConfiguration: schema.SiteConfiguration{LsifMaxReferences: tc.max}})
+		if have := LSIFMaxReferences(); have != tc.want {
+			t.Errorf("lsifMaxReferences %d: have %d, want %d", tc.max, have, tc.want)
+		}
+	}
+}
+
+func TestLSIFMaxCommitDistance(t *testing.T) {
+	defer Mock(nil)
+
+	Mock(&Unified{SiteConfiguration: schema.SiteConfiguration{
+		LsifMaxCommitDistance: 20,
+		LsifMaxCommitDistanceByRepository: map[string]int{
+			"github.com/foo/bar":  5,
+			"github.com/foo/zero": 0,
+		},
+	}})
+	for repo, want := range map[api.RepoName]int{
+		"github.com/foo/bar":   5,
+		"github.com/foo/zero":  20,
+		"github.com/foo/other": 20,
+	} {
+		if have := LSIFMaxCommitDistance(repo); have != want {
+			t.Errorf("%s: have %d, want %d", repo, have, want)
+		}
+	}
+
+	for _, max := range []int{0, -1} {
+		Mock(&Unified{SiteConfiguration: schema.SiteConfiguration{LsifMaxCommitDistance: max}})
+		if have := LSIFMaxCommitDistance("github.com/foo/bar"); have != 100 {
+			t.Errorf("lsifMaxCommitDistance %d: have %d, want 100", max, have)
+		}
+	}
+}
+
 func setenv(t *testing.T, keyval string) func() {
 	t.Helper()
 
@@ -0,0 +1,72 @@
+package dbtest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// queryPlanNode is a node of the plan returned by EXPLAIN (FORMAT JSON).
+type queryPlanNode struct {
+	NodeType     string          `json:"Node Type"`
+	RelationName string          `json:"Relation Name"`
+	Plans        []queryPlanNode `json:"Plans"`
+}
+
+// AssertNoSeqScans runs EXPLAIN on the given query within the given
+// transaction and fails the test if the resulting plan contains a
+// sequential scan on any of the given tables, or on any table at all if
+// none are given.
+//
+// Sequential scans are disabled for the remainder of the transaction
+// before the query is planned, so that the planner picks an index
+// whenever one is usable, even on the nearly empty tables of a test
+// database. A sequential scan in the plan therefore means that no index
+// supports the query.
+func AssertNoSeqScans(t testing.TB, tx *sql.Tx, q *sqlf.Query, tables ...string) {
+	t.Helper()
+
+	if _, err := tx.Exec("SET LOCAL enable_seqscan = off"); err != nil {
+		t.Fatal(err)
+	}
+
+	var raw []byte
+	err := tx.QueryRow("EXPLAIN (FORMAT JSON) "+q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&raw)
+	if err != nil {
+		t.Fatalf("failed to explain query: %s\n%s", err, q.Query(sqlf.PostgresBindVar))
+	}
+
+	var plans []struct {
+		Plan queryPlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		t.Fatalf("failed to parse query plan: %s", err)
+	}
+
+	checked := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		checked[table] = true
+	}
+
+	var scanned []string
+	var walk func(node queryPlanNode)
+	walk = func(node queryPlanNode) {
+		if node.NodeType == "Seq Scan" && (len(checked) == 0 || checked[node.RelationName]) {
+			scanned = append(scanned, node.RelationName)
+		}
+		for _, child := range node.Plans {
+			walk(child)
+		}
+	}
+	for _, p := range plans {
+		walk(p.Plan)
+	}
+
+	if len(scanned) > 0 {
+		t.Errorf("query plan has sequential scans on %s:\n%s\n%s",
+			strings.Join(scanned, ", "), q.Query(sqlf.PostgresBindVar), raw)
+	}
+}
@@ -0,0 +1,151 @@
+// Package leader elects a single process among the replicas of a service to
+// run a background job, using Postgres advisory locks.
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/fasthash/fnv1"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// lockNamespace is the first key of all advisory locks taken by this package,
+// so that they don't conflict with the locks taken elsewhere.
+var lockNamespace = int32(fnv1.HashString32("leader"))
+
+// A Lock elects the leader for the job with the given name. The leader holds
+// a session-level advisory lock on a dedicated database connection, so the
+// lock is released when the leader goes away and its connection is closed.
+type Lock struct {
+	name string
+	db   *sql.DB
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// New returns a Lock for the job with the given name. Locks for the same name
+// must be created in all replicas that run the job.
+func New(db *sql.DB, name string) *Lock {
+	return &Lock{name: name, db: db}
+}
+
+// Held reports whether this process is the leader, trying to become it if no
+// other process is. It's meant to be called before each run of the job, so
+// that another replica takes over if the leader went away.
+func (l *Lock) Held(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		err := l.conn.PingContext(ctx)
+		if err == nil {
+			return true, nil
+		}
+
+		// The connection broke, so the lock was released by Postgres.
+		log15.Warn("leader: lost connection holding lock", "name", l.name, "error", err)
+		l.release()
+	}
+
+	held, err := l.tryAcquire(ctx)
+	switch {
+	case err != nil:
+		lockAcquisitions.WithLabelValues(l.name, "error").Inc()
+	case held:
+		lockAcquisitions.WithLabelValues(l.name, "acquired").Inc()
+		log15.Info("leader: acquired lock", "name", l.name)
+	default:
+		lockAcquisitions.WithLabelValues(l.name, "held_elsewhere").Inc()
+	}
+
+	if held {
+		leaderGauge.WithLabelValues(l.name).Set(1)
+	} else {
+		leaderGauge.WithLabelValues(l.name).Set(0)
+	}
+
+	return held, err
+}
+
+func (l *Lock) tryAcquire(ctx context.Context) (bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var held bool
+	lockID := int32(fnv1.HashString32(l.name))
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1, $2)", lockNamespace, lockID).Scan(&held)
+	if err != nil {
+		// We can't tell whether the lock was taken, so the session must end.
+		discard(conn)
+		return false, err
+	}
+	if !held {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release gives up the leadership, if this process has it.
+func (l *Lock) Release(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+
+	l.release()
+	leaderGauge.WithLabelValues(l.name).Set(0)
+}
+
+// release discards the connection holding the lock, which ends its session
+// and so releases the lock. Unlocking and closing the sql.Conn wouldn't be
+// enough: Close returns the connection to the pool, where it would keep the
+// lock forever if the unlock failed.
+func (l *Lock) release() {
+	if err := discard(l.conn); err != nil {
+		log15.Warn("leader: discarding connection holding lock", "name", l.name, "error", err)
+	}
+	l.conn = nil
+}
+
+// discard closes the underlying connection of conn instead of returning it to
+// the pool of the database handle.
+func discard(conn *sql.Conn) error {
+	// Returning driver.ErrBadConn from Raw makes database/sql close the
+	// driver connection.
+	err := conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	if err == driver.ErrBadConn {
+		return nil
+	}
+	return err
+}
+
+var lockAcquisitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "leader",
+	Name:      "lock_acquisition_attempts_total",
+	Help:      "Attempts to become the leader for a job, by result (acquired, held_elsewhere or error).",
+}, []string{"name", "result"})
+
+var leaderGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "src",
+	Subsystem: "leader",
+	Name:      "is_leader",
+	Help:      "Whether this process is the leader for a job (1) or not (0).",
+}, []string{"name"})
+
+func init() {
+	prometheus.MustRegister(lockAcquisitions)
+	prometheus.MustRegister(leaderGauge)
+}
@@ -0,0 +1,92 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	a := New(dbconn.Global, "test-job")
+	b := New(dbconn.Global, "test-job")
+	other := New(dbconn.Global, "other-job")
+	defer a.Release(ctx)
+	defer b.Release(ctx)
+	defer other.Release(ctx)
+
+	for _, tc := range []struct {
+		name string
+		lock *Lock
+		want bool
+	}{
+		{"first replica acquires", a, true},
+		{"first replica still holds", a, true},
+		{"second replica doesn't acquire", b, false},
+		{"other job acquires", other, true},
+	} {
+		held, err := tc.lock.Held(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held != tc.want {
+			t.Fatalf("%s: have held %t, want %t", tc.name, held, tc.want)
+		}
+	}
+
+	a.Release(ctx)
+
+	held, err := b.Held(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !held {
+		t.Fatal("second replica should acquire the released lock")
+	}
+}
+
+func TestReleaseDiscardsConnection(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	l := New(dbconn.Global, "test-release")
+	if held, err := l.Held(ctx); err != nil || !held {
+		t.Fatalf("have held %t and error %v, want lock", held, err)
+	}
+
+	var pid int
+	if err := l.conn.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Release(ctx)
+
+	// The session holding the lock must end rather than go back to the pool.
+	for i := 0; ; i++ {
+		var sessions int
+		err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM pg_stat_activity WHERE pid = $1", pid).Scan(&sessions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sessions == 0 {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("session %d holding the lock is still open", pid)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
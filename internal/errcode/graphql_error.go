@@ -0,0 +1,66 @@
+package errcode
+
+// The codes of errors that can be returned by any part of the GraphQL API. API clients find them in
+// the "code" field of the extensions of a GraphQL error, so they don't need to match on error
+// messages. Codes of errors specific to a part of the API are defined next to those errors (e.g.,
+// CAMPAIGN_NOT_FOUND in the campaigns package).
+//
+// Codes are written in SCREAMING_SNAKE_CASE and must not change once released.
+const (
+	// CodePermissionDenied is the code of errors returned because the current actor is not allowed
+	// to perform the operation, e.g. because they are not a site admin or their access token lacks
+	// a required scope.
+	CodePermissionDenied = "PERMISSION_DENIED"
+)
+
+// WithGraphQLCode annotates err with the given code, which graphql-go exposes in the extensions of
+// the GraphQL error. If err is nil, WithGraphQLCode returns nil. The message and the causes of err
+// are preserved.
+//
+// graphql-go only reads the extensions of the error returned by a resolver, so the return value
+// must not be wrapped further.
+func WithGraphQLCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	return &graphQLCodeError{cause: err, code: code}
+}
+
+// graphQLCodeError implements the Extensions method used by graphql-go.
+type graphQLCodeError struct {
+	cause error
+	code  string
+}
+
+func (e *graphQLCodeError) Error() string { return e.cause.Error() }
+
+func (e *graphQLCodeError) Cause() error { return e.cause }
+
+func (e *graphQLCodeError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// GraphQLCode returns the code in the GraphQL error extensions of err or of one of its causes, or
+// the empty string if there is none.
+func GraphQLCode(err error) string {
+	type extensioner interface {
+		Extensions() map[string]interface{}
+	}
+	type causer interface {
+		Cause() error
+	}
+
+	for err != nil {
+		if e, ok := err.(extensioner); ok {
+			if code, ok := e.Extensions()["code"].(string); ok {
+				return code
+			}
+		}
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package errcode
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestWithGraphQLCode(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		if err := WithGraphQLCode(nil, "C"); err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	})
+
+	t.Run("root", func(t *testing.T) {
+		cause := errors.New("x")
+		err := WithGraphQLCode(cause, "C")
+		if got, want := GraphQLCode(err), "C"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if got, want := err.Error(), "x"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if errors.Cause(err) != cause {
+			t.Errorf("got cause %v, want %v", errors.Cause(err), cause)
+		}
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		err := errors.WithMessage(WithGraphQLCode(errors.New("x"), "C"), "y")
+		if got, want := GraphQLCode(err), "C"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		if got, want := GraphQLCode(errors.New("x")), ""; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
@@ -532,6 +532,35 @@ func (c *Client) DeclinePullRequest(ctx context.Context, pr *PullRequest) error
 	return c.send(ctx, "POST", path, qry, nil, pr)
 }
 
+// AddPullRequestReviewer adds the user with the given name as a reviewer of
+// the given PullRequest, returning an error in case of failure.
+func (c *Client) AddPullRequestReviewer(ctx context.Context, pr *PullRequest, username string) error {
+	if pr.ToRef.Repository.Slug == "" {
+		return errors.New("repository slug empty")
+	}
+
+	if pr.ToRef.Repository.Project.Key == "" {
+		return errors.New("project key empty")
+	}
+
+	path := fmt.Sprintf(
+		"rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/participants",
+		pr.ToRef.Repository.Project.Key,
+		pr.ToRef.Repository.Slug,
+		pr.ID,
+	)
+
+	payload := struct {
+		User User   `json:"user"`
+		Role string `json:"role"`
+	}{
+		User: User{Name: username},
+		Role: "REVIEWER",
+	}
+
+	return c.send(ctx, "POST", path, nil, payload, nil)
+}
+
 // LoadPullRequestActivities loads the given PullRequest's timeline of activities,
 // returning an error in case of failure.
 func (c *Client) LoadPullRequestActivities(ctx context.Context, pr *PullRequest) (err error) {
@@ -1,9 +1,11 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -582,6 +584,64 @@ func (c *Client) ClosePullRequest(ctx context.Context, pr *PullRequest) error {
 	return nil
 }
 
+// RequestReviews requests reviews of the pull request with the given number
+// in the repository with the given owner and name ("org/repo-name") from the
+// given users and teams, which are identified by their logins and slugs.
+// Reviewers that were already requested stay requested.
+func (c *Client) RequestReviews(ctx context.Context, nameWithOwner string, number int64, reviewers, teamReviewers []string) error {
+	owner, name, err := SplitRepositoryNameWithOwner(nameWithOwner)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{
+		Reviewers:     reviewers,
+		TeamReviewers: teamReviewers,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, name, number), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	var result struct{}
+	return c.do(ctx, "", req, &result)
+}
+
+// AddLabels adds the given labels to the pull request with the given number
+// in the repository with the given owner and name ("org/repo-name"). Labels
+// that don't exist in the repository yet are created.
+func (c *Client) AddLabels(ctx context.Context, nameWithOwner string, number int64, labels []string) error {
+	owner, name, err := SplitRepositoryNameWithOwner(nameWithOwner)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(struct {
+		Labels []string `json:"labels"`
+	}{
+		Labels: labels,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Pull requests are issues in the REST API of GitHub.
+	req, err := http.NewRequest("POST", fmt.Sprintf("repos/%s/%s/issues/%d/labels", owner, name, number), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	var result []struct{}
+	return c.do(ctx, "", req, &result)
+}
+
 // LoadPullRequests loads a list of PullRequests from Github.
 func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) error {
 	const batchSize = 15
@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -144,6 +146,10 @@ type CreateCommitFromPatchRequest struct {
 	TargetRef string
 	// If set to true and the TargetRef already exists, an unique number will be appended to the end (ie TargetRef-{#}). The generated ref will be returned.
 	UniqueRef bool
+	// If set to true and the TargetRef already exists, the request fails with
+	// a CreateCommitFromPatchError whose RefExists is true. UniqueRef takes
+	// precedence.
+	FailIfRefExists bool
 	// CommitInfo is the information that will be used when creating the commit from a patch
 	CommitInfo PatchCommitInfo
 	// Push specifies whether the target ref will be pushed to the code host
@@ -195,6 +201,51 @@ type CreateCommitFromPatchError struct {
 	Command string
 	// CombinedOutput is the combined stderr and stdout from running the command
 	CombinedOutput string
+	// RefExists is true if the request failed because FailIfRefExists was
+	// set and the TargetRef already exists.
+	RefExists bool
+}
+
+// createCommitFromPatchErrorJSON is the JSON encoding of a
+// CreateCommitFromPatchError, whose Err can't be decoded from JSON as is.
+type createCommitFromPatchErrorJSON struct {
+	RepositoryName string
+	Err            string
+	Command        string
+	CombinedOutput string
+	RefExists      bool
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *CreateCommitFromPatchError) MarshalJSON() ([]byte, error) {
+	v := createCommitFromPatchErrorJSON{
+		RepositoryName: e.RepositoryName,
+		Command:        e.Command,
+		CombinedOutput: e.CombinedOutput,
+		RefExists:      e.RefExists,
+	}
+	if e.Err != nil {
+		v.Err = e.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *CreateCommitFromPatchError) UnmarshalJSON(data []byte) error {
+	var v createCommitFromPatchErrorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = CreateCommitFromPatchError{
+		RepositoryName: v.RepositoryName,
+		Command:        v.Command,
+		CombinedOutput: v.CombinedOutput,
+		RefExists:      v.RefExists,
+	}
+	if v.Err != "" {
+		e.Err = errors.New(v.Err)
+	}
+	return nil
 }
 
 // Error returns a detailed error conforming to the error interface
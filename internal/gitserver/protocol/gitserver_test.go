@@ -0,0 +1,35 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCreateCommitFromPatchResponse_JSON(t *testing.T) {
+	want := CreateCommitFromPatchResponse{
+		Rev: "refs/heads/my-branch",
+		Error: &CreateCommitFromPatchError{
+			RepositoryName: "github.com/foo/bar",
+			Err:            errors.New("ref already exists"),
+			Command:        "git push",
+			CombinedOutput: "rejected",
+			RefExists:      true,
+		},
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var have CreateCommitFromPatchResponse
+	if err := json.Unmarshal(data, &have); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %+v, want %+v", have.Error, want.Error)
+	}
+}
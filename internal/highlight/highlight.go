@@ -223,6 +223,60 @@ func Code(ctx context.Context, p Params) (h template.HTML, aborted bool, err err
 	return template.HTML(table), false, nil
 }
 
+// CodeAsLines highlights the given file content like Code, but returns the
+// HTML of each line instead of a table. This is useful to render snippets of
+// files, such as the lines of diff hunks, in other layouts.
+func CodeAsLines(ctx context.Context, p Params) (lines []template.HTML, aborted bool, err error) {
+	table, aborted, err := Code(ctx, p)
+	if err != nil {
+		return nil, aborted, err
+	}
+	lines, err = splitHighlightedLines(string(table))
+	return lines, aborted, err
+}
+
+// splitHighlightedLines returns the inner HTML of the code cells of a table
+// produced by preSpansToTable or generatePlainTable, one per row.
+func splitHighlightedLines(h string) ([]template.HTML, error) {
+	doc, err := html.Parse(strings.NewReader(h))
+	if err != nil {
+		return nil, err
+	}
+
+	table := doc.FirstChild.LastChild.FirstChild // html > body > table
+	if table == nil || table.Type != html.ElementNode || table.DataAtom != atom.Table {
+		return nil, fmt.Errorf("expected html->body->table, found %+v", table)
+	}
+
+	var (
+		lines []template.HTML
+		buf   bytes.Buffer
+	)
+	for tbody := table.FirstChild; tbody != nil; tbody = tbody.NextSibling {
+		for tr := tbody.FirstChild; tr != nil; tr = tr.NextSibling {
+			td := tr.LastChild // tr > td.code
+			if td == nil || td.DataAtom != atom.Td {
+				return nil, fmt.Errorf("expected tr->td, found %+v", td)
+			}
+
+			// Highlighted lines are wrapped in a div, plain ones aren't.
+			cell := td
+			if div := td.FirstChild; div != nil && div.DataAtom == atom.Div {
+				cell = div
+			}
+
+			buf.Reset()
+			for n := cell.FirstChild; n != nil; n = n.NextSibling {
+				if err := html.Render(&buf, n); err != nil {
+					return nil, err
+				}
+			}
+			lines = append(lines, template.HTML(buf.String()))
+		}
+	}
+	return lines, nil
+}
+
 var requestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Namespace: "src",
 	Subsystem: "syntax_highlighting",
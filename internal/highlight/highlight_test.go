@@ -2,6 +2,7 @@ package highlight
 
 import (
 	"html/template"
+	"reflect"
 	"testing"
 )
 
@@ -69,6 +70,45 @@ line 2
 	}
 }
 
+func TestSplitHighlightedLines(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  []template.HTML
+	}{
+		{
+			name: "highlighted",
+			input: `<table><tr><td class="line" data-line="1"></td><td class="code"><div><span style="color:#a71d5d;">package</span><span> main
+</span></div></td></tr><tr><td class="line" data-line="2"></td><td class="code"><div><span>&#34;x&#34;</span></div></td></tr></table>`,
+			want: []template.HTML{
+				`<span style="color:#a71d5d;">package</span><span> main
+</span>`,
+				`<span>&#34;x&#34;</span>`,
+			},
+		},
+		{
+			name: "plain",
+			input: `<table><tr><td class="line" data-line="1"></td><td class="code"><span>a &lt; b</span></td></tr><tr><td class="line" data-line="2"></td><td class="code"><span>
+</span></td></tr></table>`,
+			want: []template.HTML{
+				`<span>a &lt; b</span>`,
+				`<span>
+</span>`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := splitHighlightedLines(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("\ngot:\n%q\nwant:\n%q\n", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestGeneratePlainTableSecurity(t *testing.T) {
 	input := `<strong>line 1</strong>
 <script>alert("line 2")</script>
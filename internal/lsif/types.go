@@ -20,6 +20,14 @@ type LSIFUpload struct {
 	FailureSummary    *string    `json:"failureSummary"`
 	FailureStacktrace *string    `json:"failureStacktrace"`
 	VisibleAtTip      bool       `json:"visibleAtTip"`
+	Indexer           *string    `json:"indexer"`
+}
+
+// LSIFUploadCoverage is an upload with the repository-relative paths of the
+// files it contains data for.
+type LSIFUploadCoverage struct {
+	Upload *LSIFUpload `json:"upload"`
+	Paths  []string    `json:"paths"`
 }
 
 type LSIFLocation struct {
@@ -0,0 +1,208 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// A Budget is the rate limit of the token of an external service. All the
+// API clients of a process that use the token share the Budget's Monitor, so
+// that background consumers like campaigns can leave part of the rate limit
+// to repository syncing instead of exhausting it.
+type Budget struct {
+	ExternalServiceID int64
+	Kind              string
+	Monitor           *Monitor
+}
+
+// BudgetStatus is the state of a Budget as of the last API response received
+// with its token.
+type BudgetStatus struct {
+	ExternalServiceID int64
+	Kind              string
+	Known             bool
+	Limit             int
+	Remaining         int
+	ResetAt           time.Time
+	RetryAt           time.Time
+}
+
+// Status returns the current state of the budget.
+func (b *Budget) Status() BudgetStatus {
+	m := b.Monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return BudgetStatus{
+		ExternalServiceID: b.ExternalServiceID,
+		Kind:              b.Kind,
+		Known:             m.known,
+		Limit:             m.limit,
+		Remaining:         m.remaining,
+		ResetAt:           m.reset,
+		RetryAt:           m.retry,
+	}
+}
+
+// Wait returns how long a consumer needs to wait before it can spend the
+// given cost without the remaining budget dropping below the reserve, a
+// fraction of the limit kept for other consumers. It returns 0 if the cost
+// can be spent right away or if the rate limit is unknown.
+func (b *Budget) Wait(cost int, reserve float64) time.Duration {
+	m := b.Monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if wait := m.retry.Sub(now); wait > 0 {
+		return wait
+	}
+
+	// If the rate limit info is out of date, assume it was reset.
+	if !m.known || now.After(m.reset) {
+		return 0
+	}
+
+	if float64(m.remaining-cost) < reserve*float64(m.limit) {
+		return m.reset.Sub(now)
+	}
+	return 0
+}
+
+// Borrow blocks until the given cost can be spent by the named consumer
+// without dipping into the reserve (see Wait), or until ctx is done.
+func (b *Budget) Borrow(ctx context.Context, consumer string, cost int, reserve float64) error {
+	for {
+		wait := b.Wait(cost, reserve)
+		if wait <= 0 {
+			budgetBorrowed.WithLabelValues(b.Kind, consumer).Add(float64(cost))
+			return nil
+		}
+
+		budgetWaits.WithLabelValues(b.Kind, consumer).Inc()
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// A BudgetRegistry holds the Budgets of the external services used by a
+// process.
+type BudgetRegistry struct {
+	mu      sync.Mutex
+	budgets map[int64]*Budget
+}
+
+// DefaultBudgets is the BudgetRegistry shared by all the API clients of this
+// process.
+var DefaultBudgets = &BudgetRegistry{}
+
+// Get returns the Budget of the external service with the given ID, creating
+// it with a Monitor using the given header prefix if it doesn't exist yet.
+func (r *BudgetRegistry) Get(externalServiceID int64, kind, headerPrefix string) *Budget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.budgets[externalServiceID]; ok {
+		return b
+	}
+
+	if r.budgets == nil {
+		r.budgets = map[int64]*Budget{}
+	}
+	b := &Budget{
+		ExternalServiceID: externalServiceID,
+		Kind:              kind,
+		Monitor:           &Monitor{HeaderPrefix: headerPrefix},
+	}
+	r.budgets[externalServiceID] = b
+	return b
+}
+
+// Lookup returns the Budget of the external service with the given ID, or
+// nil if none of its API clients was created in this process.
+func (r *BudgetRegistry) Lookup(externalServiceID int64) *Budget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.budgets[externalServiceID]
+}
+
+// All returns the status of all budgets, ordered by external service ID.
+func (r *BudgetRegistry) All() []BudgetStatus {
+	r.mu.Lock()
+	budgets := make([]*Budget, 0, len(r.budgets))
+	for _, b := range r.budgets {
+		budgets = append(budgets, b)
+	}
+	r.mu.Unlock()
+
+	statuses := make([]BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ExternalServiceID < statuses[j].ExternalServiceID
+	})
+	return statuses
+}
+
+var (
+	budgetRemainingDesc = prometheus.NewDesc(
+		"src_ratelimit_budget_remaining",
+		"Remaining rate limit of the token of an external service, as of the last API response.",
+		[]string{"external_service_id", "kind"}, nil,
+	)
+	budgetLimitDesc = prometheus.NewDesc(
+		"src_ratelimit_budget_limit",
+		"Rate limit of the token of an external service, as of the last API response.",
+		[]string{"external_service_id", "kind"}, nil,
+	)
+)
+
+// budgetCollector exports the status of the DefaultBudgets whose rate limit
+// is known.
+type budgetCollector struct{}
+
+func (budgetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- budgetRemainingDesc
+	ch <- budgetLimitDesc
+}
+
+func (budgetCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range DefaultBudgets.All() {
+		if !s.Known {
+			continue
+		}
+		id := strconv.FormatInt(s.ExternalServiceID, 10)
+		ch <- prometheus.MustNewConstMetric(budgetRemainingDesc, prometheus.GaugeValue, float64(s.Remaining), id, s.Kind)
+		ch <- prometheus.MustNewConstMetric(budgetLimitDesc, prometheus.GaugeValue, float64(s.Limit), id, s.Kind)
+	}
+}
+
+var budgetBorrowed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "ratelimit",
+	Name:      "budget_borrowed_total",
+	Help:      "Rate limit cost borrowed from external service budgets, by consumer.",
+}, []string{"kind", "consumer"})
+
+var budgetWaits = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "ratelimit",
+	Name:      "budget_waits_total",
+	Help:      "Times a consumer had to wait for an external service budget to leave its reserve.",
+}, []string{"kind", "consumer"})
+
+func init() {
+	prometheus.MustRegister(budgetCollector{})
+	prometheus.MustRegister(budgetBorrowed)
+	prometheus.MustRegister(budgetWaits)
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBudget_Wait(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	for _, tc := range []struct {
+		name    string
+		monitor *Monitor
+		cost    int
+		want    time.Duration
+	}{
+		{
+			name:    "unknown",
+			monitor: &Monitor{clock: clock},
+			cost:    100,
+			want:    0,
+		},
+		{
+			name:    "above reserve",
+			monitor: &Monitor{clock: clock, known: true, limit: 5000, remaining: 2000, reset: now.Add(30 * time.Minute)},
+			cost:    100,
+			want:    0,
+		},
+		{
+			name:    "would dip into reserve",
+			monitor: &Monitor{clock: clock, known: true, limit: 5000, remaining: 1300, reset: now.Add(30 * time.Minute)},
+			cost:    100,
+			want:    30 * time.Minute,
+		},
+		{
+			name:    "reset passed",
+			monitor: &Monitor{clock: clock, known: true, limit: 5000, remaining: 0, reset: now.Add(-time.Second)},
+			cost:    100,
+			want:    0,
+		},
+		{
+			name:    "retry after",
+			monitor: &Monitor{clock: clock, known: true, limit: 5000, remaining: 4000, reset: now.Add(30 * time.Minute), retry: now.Add(time.Minute)},
+			cost:    1,
+			want:    time.Minute,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Budget{ExternalServiceID: 1, Kind: "GITHUB", Monitor: tc.monitor}
+			if got := b.Wait(tc.cost, 0.25); got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBudget_Borrow(t *testing.T) {
+	now := time.Now()
+	b := &Budget{ExternalServiceID: 1, Kind: "GITHUB", Monitor: &Monitor{
+		clock:     func() time.Time { return now },
+		known:     true,
+		limit:     5000,
+		remaining: 100,
+		reset:     now.Add(time.Hour),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Borrow(ctx, "test", 10, 0.25); err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+
+	b.Monitor.remaining = 5000
+	if err := b.Borrow(context.Background(), "test", 10, 0.25); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBudgetRegistry(t *testing.T) {
+	var r BudgetRegistry
+	if b := r.Lookup(1); b != nil {
+		t.Fatalf("got budget %+v before Get", b)
+	}
+
+	b := r.Get(2, "GITLAB", "")
+	if r.Get(2, "GITLAB", "") != b {
+		t.Fatal("Get returned a different budget for the same external service")
+	}
+	r.Get(1, "GITHUB", "X-")
+
+	all := r.All()
+	if len(all) != 2 || all[0].ExternalServiceID != 1 || all[1].ExternalServiceID != 2 {
+		t.Fatalf("unexpected statuses: %+v", all)
+	}
+}
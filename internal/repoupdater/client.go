@@ -293,6 +293,36 @@ func (c *Client) StatusMessages(ctx context.Context) (*protocol.StatusMessagesRe
 	return &res, nil
 }
 
+// MockRateLimits mocks (*Client).RateLimits for tests.
+var MockRateLimits func(context.Context) (*protocol.RateLimitsResponse, error)
+
+// RateLimits returns the rate limit budgets of the external services that
+// repo-updater made API requests to.
+func (c *Client) RateLimits(ctx context.Context) (*protocol.RateLimitsResponse, error) {
+	if MockRateLimits != nil {
+		return MockRateLimits(ctx)
+	}
+
+	resp, err := c.httpGet(ctx, "rate-limits")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	var res protocol.RateLimitsResponse
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	} else if err = json.Unmarshal(bs, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
 func (c *Client) httpPost(ctx context.Context, method string, payload interface{}) (resp *http.Response, err error) {
 	reqBody, err := json.Marshal(payload)
 	if err != nil {
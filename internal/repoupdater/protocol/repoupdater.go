@@ -196,3 +196,19 @@ type StatusMessage struct {
 type StatusMessagesResponse struct {
 	Messages []StatusMessage `json:"messages"`
 }
+
+// RateLimit is the rate limit budget of the token of an external service, as
+// of the last API response repo-updater received with it.
+type RateLimit struct {
+	ExternalServiceID int64     `json:"external_service_id"`
+	Kind              string    `json:"kind"`
+	Known             bool      `json:"known"`
+	Limit             int       `json:"limit"`
+	Remaining         int       `json:"remaining"`
+	ResetAt           time.Time `json:"reset_at"`
+	RetryAt           time.Time `json:"retry_at"`
+}
+
+type RateLimitsResponse struct {
+	RateLimits []RateLimit `json:"rate_limits"`
+}
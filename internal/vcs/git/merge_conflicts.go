@@ -0,0 +1,38 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// HasMergeConflicts reports whether merging commit head into commit base
+// results in conflicts. The merge is performed in memory (with git merge-tree)
+// and doesn't touch the working tree or refs of the repository.
+func HasMergeConflicts(ctx context.Context, repo gitserver.Repo, base, head api.CommitID) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: HasMergeConflicts")
+	span.SetTag("Base", base)
+	span.SetTag("Head", head)
+	defer span.Finish()
+
+	mb, err := MergeBase(ctx, repo, base, head)
+	if err != nil {
+		return false, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "merge-tree", string(mb), string(base), string(head))
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		return false, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+
+	// merge-tree prints the result of the merge as a diff, in which the
+	// conflicting hunks are added with conflict markers.
+	return bytes.Contains(out, []byte("\n+<<<<<<< .our")), nil
+}
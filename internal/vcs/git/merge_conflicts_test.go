@@ -0,0 +1,62 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestHasMergeConflicts(t *testing.T) {
+	t.Parallel()
+
+	cmds := []string{
+		"echo line1 > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout -b clean",
+		"echo line2 > g",
+		"git add g",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout master",
+		"git checkout -b conflicting",
+		"echo line3 > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m baz --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout master",
+		"echo line4 > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m qux --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	}
+	repo := MakeGitRepository(t, cmds...)
+
+	tests := map[string]struct {
+		base, head string // can be any revspec; is resolved during the test
+
+		want bool
+	}{
+		"clean":       {base: "master", head: "clean", want: false},
+		"conflicting": {base: "master", head: "conflicting", want: true},
+	}
+
+	for label, test := range tests {
+		base, err := ResolveRevision(ctx, repo, nil, test.base, nil)
+		if err != nil {
+			t.Errorf("%s: ResolveRevision(%q) on base: %s", label, test.base, err)
+			continue
+		}
+
+		head, err := ResolveRevision(ctx, repo, nil, test.head, nil)
+		if err != nil {
+			t.Errorf("%s: ResolveRevision(%q) on head: %s", label, test.head, err)
+			continue
+		}
+
+		got, err := HasMergeConflicts(ctx, repo, base, head)
+		if err != nil {
+			t.Errorf("%s: HasMergeConflicts(%s, %s): %s", label, base, head, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%s: HasMergeConflicts(%s, %s): got %t, want %t", label, base, head, got, test.want)
+		}
+	}
+}
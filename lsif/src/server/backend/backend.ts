@@ -4,12 +4,14 @@ import * as settings from '../settings'
 import * as pgModels from '../../shared/models/pg'
 import { addTags, logSpan, TracingContext } from '../../shared/tracing'
 import { ConnectionCache, DocumentCache, ResultChunkCache } from './cache'
-import { Database, sortMonikers, InternalLocation } from './database'
+import { Database, sortMonikers, InternalLocation, createRange } from './database'
 import { dbFilename } from '../../shared/paths'
-import { isEqual, uniqWith } from 'lodash'
+import { chunk, isEqual, uniqWith } from 'lodash'
 import { mustGet } from '../../shared/maps'
 import { DumpManager } from '../../shared/store/dumps'
 import { DependencyManager } from '../../shared/store/dependencies'
+import { UploadManager } from '../../shared/store/uploads'
+import { QueryBudget } from './budget'
 
 /**
  * Context describing the current request for paginated results.
@@ -83,6 +85,14 @@ export interface ReferencePaginationCursor {
  */
 const pathToDatabase = (root: string, path: string): string => (path.startsWith(root) ? path.slice(root.length) : path)
 
+/**
+ * A dump with the repository-relative paths of the documents it contains data for.
+ */
+export interface DumpCoverage {
+    dump: pgModels.LsifDump
+    paths: string[]
+}
+
 /**
  * Converts a location in a dump to the corresponding location in the repository.
  *
@@ -118,6 +128,38 @@ export class Backend {
         private frontendUrl: string
     ) {}
 
+    /**
+     * Delete the upload with the given identifier. If the upload was visible from the tip
+     * of the default branch, the dumps visible from the tip are recalculated. All cached
+     * data belonging to the dump (its connection, documents, and result chunks) is evicted
+     * so that subsequent queries cannot read the deleted data. This returns true if the
+     * upload existed.
+     *
+     * @param uploadManager The uploads manager instance.
+     * @param id The upload identifier.
+     * @param ctx The tracing context.
+     */
+    public async deleteUpload(uploadManager: UploadManager, id: number, ctx: TracingContext = {}): Promise<boolean> {
+        const deleted = await uploadManager.deleteUpload(id, async (entityManager, repositoryId) => {
+            const tipCommit = await this.dumpManager.discoverTip({
+                repositoryId,
+                frontendUrl: this.frontendUrl,
+                ctx,
+            })
+            if (tipCommit === undefined) {
+                throw new Error('No tip commit available for repository')
+            }
+
+            await this.dumpManager.updateDumpsVisibleFromTip(repositoryId, tipCommit, ctx, entityManager)
+        })
+
+        if (deleted) {
+            await this.evictDump(id)
+        }
+
+        return deleted
+    }
+
     /**
      * Determine if data exists for a particular document.
      *
@@ -142,6 +184,56 @@ export class Backend {
         return (await database.exists(pathToDatabase(dump.root, path))) ? dump : undefined
     }
 
+    /**
+     * Return the closest dumps that contain data for a particular document, as ordered by
+     * `DumpManager.findClosestDumps`. Each of them can be queried with its identifier.
+     *
+     * @param repositoryId The repository identifier.
+     * @param commit The commit.
+     * @param path The path of the document.
+     * @param ctx The tracing context.
+     * @param maxCommitDistance The maximum number of commits visited to find the dumps.
+     */
+    public async existsAll(
+        repositoryId: number,
+        commit: string,
+        path: string,
+        ctx: TracingContext = {},
+        maxCommitDistance?: number
+    ): Promise<pgModels.LsifDump[]> {
+        const dumps = await this.dumpManager.findClosestDumps(
+            repositoryId,
+            commit,
+            path,
+            ctx,
+            this.frontendUrl,
+            maxCommitDistance
+        )
+        const exists = await Promise.all(
+            dumps.map(dump => this.createDatabase(dump).exists(pathToDatabase(dump.root, path)))
+        )
+        return dumps.filter((_, i) => exists[i])
+    }
+
+    /**
+     * Return the closest dumps of all roots and indexers, as ordered by
+     * `DumpManager.findClosestDumps`, with the repository-relative paths of the
+     * documents that each of them contains data for.
+     *
+     * @param repositoryId The repository identifier.
+     * @param commit The commit.
+     * @param ctx The tracing context.
+     */
+    public async coverage(repositoryId: number, commit: string, ctx: TracingContext = {}): Promise<DumpCoverage[]> {
+        const dumps = await this.dumpManager.findClosestDumps(repositoryId, commit, undefined, ctx, this.frontendUrl)
+        return Promise.all(
+            dumps.map(async dump => ({
+                dump,
+                paths: (await this.createDatabase(dump).documentPaths(ctx)).map(path => dump.root + path),
+            }))
+        )
+    }
+
     /**
      * Return the location for the symbol at the given position. Returns undefined if no dump can
      * be loaded to answer this query.
@@ -152,6 +244,7 @@ export class Backend {
      * @param position The current hover position.
      * @param dumpId The identifier of the dump to load. If not supplied, the closest dump will be used.
      * @param ctx The tracing context.
+     * @param budget The time budget shared by the queries of remote dumps.
      */
     public async definitions(
         repositoryId: number,
@@ -159,9 +252,10 @@ export class Backend {
         path: string,
         position: lsp.Position,
         dumpId?: number,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<InternalLocation[] | undefined> {
-        const result = await this.internalDefinitions(repositoryId, commit, path, position, dumpId, ctx)
+        const result = await this.definitionsWithRange(repositoryId, commit, path, position, dumpId, ctx, budget)
         if (result === undefined) {
             return undefined
         }
@@ -169,6 +263,37 @@ export class Backend {
         return result.locations
     }
 
+    /**
+     * Return the location for the symbol at the given position along with the range of the
+     * symbol, which is undefined if there is no symbol at the given position. The definitions
+     * are the same for all positions within that range, so clients can reuse them. Returns
+     * undefined if no dump can be loaded to answer this query.
+     *
+     * @param repositoryId The repository identifier.
+     * @param commit The commit.
+     * @param path The path of the document to which the position belongs.
+     * @param position The current hover position.
+     * @param dumpId The identifier of the dump to load. If not supplied, the closest dump will be used.
+     * @param ctx The tracing context.
+     * @param budget The time budget shared by the queries of remote dumps.
+     */
+    public async definitionsWithRange(
+        repositoryId: number,
+        commit: string,
+        path: string,
+        position: lsp.Position,
+        dumpId?: number,
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
+    ): Promise<{ locations: InternalLocation[]; range?: lsp.Range } | undefined> {
+        const result = await this.internalDefinitions(repositoryId, commit, path, position, dumpId, ctx, budget)
+        if (result === undefined) {
+            return undefined
+        }
+
+        return { locations: result.locations, range: result.range }
+    }
+
     /**
      * Return a list of locations which reference the symbol at the given position. Returns
      * undefined if no dump can be loaded to answer this query.
@@ -180,6 +305,7 @@ export class Backend {
      * @param paginationContext Context describing the current request for paginated results.
      * @param dumpId The identifier of the dump to load. If not supplied, the closest dump will be used.
      * @param ctx The tracing context.
+     * @param budget The time budget shared by the queries of remote dumps.
      */
     public async references(
         repositoryId: number,
@@ -188,9 +314,44 @@ export class Backend {
         position: lsp.Position,
         paginationContext: ReferencePaginationContext = { limit: 10 },
         dumpId?: number,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<{ locations: InternalLocation[]; cursor?: ReferencePaginationCursor } | undefined> {
-        return this.internalReferences(repositoryId, commit, path, position, paginationContext, dumpId, ctx)
+        return this.internalReferences(repositoryId, commit, path, position, paginationContext, dumpId, ctx, budget)
+    }
+
+    /**
+     * Return the approximate number of locations which reference the symbol at the given position.
+     * Only the reference results of the closest dump are counted. References that are found through
+     * monikers, either in the same dump or in other dumps, are not included. Returns undefined if no
+     * dump can be loaded to answer this query.
+     *
+     * @param repositoryId The repository identifier.
+     * @param commit The commit.
+     * @param path The path of the document to which the position belongs.
+     * @param position The current hover position.
+     * @param dumpId The identifier of the dump to load. If not supplied, the closest dump will be used.
+     * @param ctx The tracing context.
+     */
+    public async referenceCount(
+        repositoryId: number,
+        commit: string,
+        path: string,
+        position: lsp.Position,
+        dumpId?: number,
+        ctx: TracingContext = {}
+    ): Promise<number | undefined> {
+        const closestDatabaseAndDump = await this.loadClosestDatabase(repositoryId, commit, path, dumpId, ctx)
+        if (!closestDatabaseAndDump) {
+            if (ctx.logger) {
+                ctx.logger.warn('No database could be loaded', { repositoryId, commit, path })
+            }
+
+            return undefined
+        }
+        const { database, dump, ctx: newCtx } = closestDatabaseAndDump
+
+        return database.referenceCount(pathToDatabase(dump.root, path), position, newCtx)
     }
 
     /**
@@ -222,8 +383,79 @@ export class Backend {
         }
         const { database, dump, ctx: newCtx } = closestDatabaseAndDump
 
+        return this.internalHover(database, dump, repositoryId, commit, path, position, ctx, newCtx)
+    }
+
+    /**
+     * Return the hover content for the symbols at each of the given positions of the same document,
+     * in the order of the positions. The closest dump is loaded once for all positions, which are
+     * resolved in batches of `HOVER_BATCH_SIZE`. Returns undefined if no dump can be loaded to answer
+     * this query.
+     *
+     * @param repositoryId The repository identifier.
+     * @param commit The commit.
+     * @param path The path of the document to which the positions belong.
+     * @param positions The hover positions.
+     * @param dumpId The identifier of the dump to load. If not supplied, the closest dump will be used.
+     * @param ctx The tracing context.
+     */
+    public async hovers(
+        repositoryId: number,
+        commit: string,
+        path: string,
+        positions: lsp.Position[],
+        dumpId?: number,
+        ctx: TracingContext = {}
+    ): Promise<({ text: string; range: lsp.Range } | null)[] | undefined> {
+        const closestDatabaseAndDump = await this.loadClosestDatabase(repositoryId, commit, path, dumpId, ctx)
+        if (!closestDatabaseAndDump) {
+            if (ctx.logger) {
+                ctx.logger.warn('No database could be loaded', { repositoryId, commit, path })
+            }
+
+            return undefined
+        }
+        const { database, dump, ctx: newCtx } = closestDatabaseAndDump
+
+        const hovers: ({ text: string; range: lsp.Range } | null)[] = []
+        for (const batch of chunk(positions, settings.HOVER_BATCH_SIZE)) {
+            hovers.push(
+                ...(await Promise.all(
+                    batch.map(position =>
+                        this.internalHover(database, dump, repositoryId, commit, path, position, ctx, newCtx)
+                    )
+                ))
+            )
+        }
+
+        return hovers
+    }
+
+    /**
+     * Return the hover content for the symbol at the given position of the given dump, or of
+     * the dump that defines it.
+     *
+     * @param database The database of the dump.
+     * @param dump The dump.
+     * @param repositoryId The repository identifier.
+     * @param commit The commit.
+     * @param path The path of the document to which the position belongs.
+     * @param position The current hover position.
+     * @param ctx The tracing context.
+     * @param dumpCtx The tracing context of the dump's database.
+     */
+    private async internalHover(
+        database: Database,
+        dump: pgModels.LsifDump,
+        repositoryId: number,
+        commit: string,
+        path: string,
+        position: lsp.Position,
+        ctx: TracingContext,
+        dumpCtx: TracingContext
+    ): Promise<{ text: string; range: lsp.Range } | null> {
         // Try to find hover in the same dump
-        const hover = await database.hover(pathToDatabase(dump.root, path), position, newCtx)
+        const hover = await database.hover(pathToDatabase(dump.root, path), position, dumpCtx)
         if (hover !== null) {
             return hover
         }
@@ -231,9 +463,10 @@ export class Backend {
         // If we don't have a local hover, lookup the definitions of the
         // range and read the hover data from the remote database. This
         // can happen when the indexer only gives a moniker but does not
-        // give hover data for externally defined symbols.
+        // give hover data for externally defined symbols. The dump is passed
+        // by identifier so that the closest dump is not looked up again.
 
-        const result = await this.internalDefinitions(repositoryId, commit, path, position, dumpId, ctx)
+        const result = await this.internalDefinitions(repositoryId, commit, path, position, dump.id, ctx)
         if (result === undefined || result.locations.length === 0) {
             return null
         }
@@ -241,7 +474,7 @@ export class Backend {
         return this.createDatabase(result.locations[0].dump).hover(
             pathToDatabase(result.locations[0].dump.root, result.locations[0].path),
             result.locations[0].range.start,
-            newCtx
+            dumpCtx
         )
     }
 
@@ -251,8 +484,9 @@ export class Backend {
         path: string,
         position: lsp.Position,
         dumpId?: number,
-        ctx: TracingContext = {}
-    ): Promise<{ dump: pgModels.LsifDump; locations: InternalLocation[] } | undefined> {
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
+    ): Promise<{ dump: pgModels.LsifDump; locations: InternalLocation[]; range?: lsp.Range } | undefined> {
         const closestDatabaseAndDump = await this.loadClosestDatabase(repositoryId, commit, path, dumpId, ctx)
         if (!closestDatabaseAndDump) {
             if (ctx.logger) {
@@ -266,20 +500,21 @@ export class Backend {
         // Construct path within dump
         const pathInDb = pathToDatabase(dump.root, path)
 
+        // The innermost range at the position is the symbol whose definitions are returned
+        const { document, ranges } = await database.getRangeByPosition(pathInDb, position, ctx)
+        if (!document || ranges.length === 0) {
+            return { dump, locations: [] }
+        }
+        const symbolRange = createRange(ranges[0])
+
         // Try to find definitions in the same dump
         const dbDefinitions = await database.definitions(pathInDb, position, newCtx)
         const definitions = dbDefinitions.map(loc => locationFromDatabase(dump.root, loc))
         if (definitions.length > 0) {
-            return { dump, locations: definitions }
-        }
-
-        // Try to find definitions in other dumps
-        const { document, ranges } = await database.getRangeByPosition(pathInDb, position, ctx)
-        if (!document || ranges.length === 0) {
-            return { dump, locations: [] }
+            return { dump, locations: definitions, range: symbolRange }
         }
 
-        // First, we find the monikers for each range, from innermost to
+        // Try to find definitions in other dumps. First, we find the monikers for each range, from innermost to
         // outermost, such that the set of monikers for reach range is sorted by
         // priority. Then, we perform a search for each moniker, in sequence,
         // until valid results are found.
@@ -297,10 +532,11 @@ export class Backend {
                         document,
                         moniker,
                         sqliteModels.DefinitionModel,
-                        ctx
+                        ctx,
+                        budget
                     )
                     if (remoteDefinitions.length > 0) {
-                        return { dump, locations: remoteDefinitions }
+                        return { dump, locations: remoteDefinitions, range: symbolRange }
                     }
                 } else {
                     // This symbol was not imported from another database. We search the definitions
@@ -310,12 +546,12 @@ export class Backend {
                     const monikerResults = await database.monikerResults(sqliteModels.DefinitionModel, moniker, ctx)
                     const localDefinitions = monikerResults.map(loc => locationFromDatabase(dump.root, loc))
                     if (localDefinitions.length > 0) {
-                        return { dump, locations: localDefinitions }
+                        return { dump, locations: localDefinitions, range: symbolRange }
                     }
                 }
             }
         }
-        return { dump, locations: [] }
+        return { dump, locations: [], range: symbolRange }
     }
 
     private async internalReferences(
@@ -325,7 +561,8 @@ export class Backend {
         position: lsp.Position,
         paginationContext: ReferencePaginationContext = { limit: 10 },
         dumpId?: number,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<
         { dump: pgModels.LsifDump; locations: InternalLocation[]; cursor?: ReferencePaginationCursor } | undefined
     > {
@@ -341,7 +578,8 @@ export class Backend {
                 commit,
                 paginationContext.limit,
                 paginationContext.cursor,
-                ctx
+                ctx,
+                budget
             )
             if (results !== undefined) {
                 return { dump, ...results }
@@ -403,7 +641,8 @@ export class Backend {
                         document,
                         moniker,
                         sqliteModels.ReferenceModel,
-                        ctx
+                        ctx,
+                        budget
                     )
                     locations = locations.concat(monikerLocations)
                 }
@@ -432,7 +671,8 @@ export class Backend {
                     commit,
                     paginationContext.limit,
                     cursor,
-                    ctx
+                    ctx,
+                    budget
                 )
 
                 if (results !== undefined) {
@@ -489,12 +729,14 @@ export class Backend {
      * @param moniker The target moniker.
      * @param model The target model.
      * @param ctx The tracing context.
+     * @param budget The time budget of the request.
      */
     private async lookupMoniker(
         document: sqliteModels.DocumentData,
         moniker: sqliteModels.MonikerData,
         model: typeof sqliteModels.DefinitionModel | typeof sqliteModels.ReferenceModel,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<InternalLocation[]> {
         const packageInformation = this.lookupPackageInformation(document, moniker, ctx)
         if (!packageInformation) {
@@ -517,9 +759,15 @@ export class Backend {
             packageCommit: packageEntity.dump.commit,
         })
 
-        return (await this.createDatabase(packageEntity.dump).monikerResults(model, moniker, ctx)).map(loc =>
-            locationFromDatabase(packageEntity.dump.root, loc)
+        const results = await budget.run(1, () =>
+            this.createDatabase(packageEntity.dump).monikerResults(model, moniker, ctx)
         )
+        if (results === undefined) {
+            logSpan(ctx, 'package_entity_timeout', { packageRepositoryId: packageEntity.dump.repositoryId })
+            return []
+        }
+
+        return results.map(loc => locationFromDatabase(packageEntity.dump.root, loc))
     }
 
     /**
@@ -532,13 +780,15 @@ export class Backend {
      * @param limit The maximum number of dumps to open.
      * @param cursor The pagination cursor.
      * @param ctx The tracing context.
+     * @param budget The time budget of the request.
      */
     private async performRemoteReferences(
         repositoryId: number,
         commit: string,
         limit: number,
         cursor: ReferencePaginationCursor,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<{ locations: InternalLocation[]; cursor?: ReferencePaginationCursor } | undefined> {
         const moniker = { scheme: cursor.scheme, identifier: cursor.identifier }
         const packageInformation = { name: cursor.name, version: cursor.version }
@@ -552,7 +802,8 @@ export class Backend {
                 packageInformation,
                 limit,
                 cursor.offset,
-                ctx
+                ctx,
+                budget
             )
 
             if (locations.length > 0) {
@@ -600,7 +851,8 @@ export class Backend {
             packageInformation,
             limit,
             cursor.offset,
-            ctx
+            ctx,
+            budget
         )
 
         if (locations.length > 0) {
@@ -632,6 +884,7 @@ export class Backend {
      * @param limit The maximum number of remote dumps to search.
      * @param offset The number of remote dumps to skip.
      * @param ctx The tracing context.
+     * @param budget The time budget of the request.
      */
     private async remoteReferences(
         dumpId: pgModels.DumpId,
@@ -640,7 +893,8 @@ export class Backend {
         packageInformation: Pick<sqliteModels.PackageInformationData, 'name' | 'version'>,
         limit: number,
         offset: number,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<{ locations: InternalLocation[]; totalCount: number; newOffset: number }> {
         const { references, totalCount, newOffset } = await this.dependencyManager.getReferences({
             repositoryId,
@@ -653,7 +907,7 @@ export class Backend {
         })
 
         const dumps = references.map(r => r.dump)
-        const locations = await this.locationsFromRemoteReferences(dumpId, moniker, dumps, ctx)
+        const locations = await this.locationsFromRemoteReferences(dumpId, moniker, dumps, ctx, budget)
         return { locations, totalCount, newOffset }
     }
 
@@ -671,6 +925,7 @@ export class Backend {
      * @param limit The maximum number of remote dumps to search.
      * @param offset The number of remote dumps to skip.
      * @param ctx The tracing context.
+     * @param budget The time budget of the request.
      */
     private async sameRepositoryRemoteReferences(
         dumpId: pgModels.DumpId,
@@ -680,7 +935,8 @@ export class Backend {
         packageInformation: Pick<sqliteModels.PackageInformationData, 'name' | 'version'>,
         limit: number,
         offset: number,
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<{ locations: InternalLocation[]; totalCount: number; newOffset: number }> {
         const { references, totalCount, newOffset } = await this.dependencyManager.getSameRepoRemoteReferences({
             repositoryId,
@@ -694,40 +950,47 @@ export class Backend {
         })
 
         const dumps = references.map(r => r.dump)
-        const locations = await this.locationsFromRemoteReferences(dumpId, moniker, dumps, ctx)
+        const locations = await this.locationsFromRemoteReferences(dumpId, moniker, dumps, ctx, budget)
         return { locations, totalCount, newOffset }
     }
 
     /**
-     * Query the given dumps for references to the given moniker.
+     * Query the given dumps for references to the given moniker. The remaining time budget
+     * is divided evenly among the dumps that have not yet been queried. Dumps that do not
+     * answer within their share are skipped.
      *
      * @param dumpId The ID of the dump for which this database answers queries.
      * @param moniker The target moniker.
      * @param dumps The dumps to open.
      * @param ctx The tracing context.
+     * @param budget The time budget of the request.
      */
     private async locationsFromRemoteReferences(
         dumpId: pgModels.DumpId,
         moniker: Pick<sqliteModels.MonikerData, 'scheme' | 'identifier'>,
         dumps: pgModels.LsifDump[],
-        ctx: TracingContext = {}
+        ctx: TracingContext = {},
+        budget: QueryBudget = new QueryBudget()
     ): Promise<InternalLocation[]> {
         logSpan(ctx, 'package_references', {
             references: dumps.map(d => ({ repositoryId: d.repositoryId, commit: d.commit })),
         })
 
+        // Skip the remote reference that show up for ourselves - we've already gathered
+        // these in the previous step of the references query.
+        const remoteDumps = dumps.filter(dump => dump.id !== dumpId)
+
         let locations: InternalLocation[] = []
-        for (const dump of dumps) {
-            // Skip the remote reference that show up for ourselves - we've already gathered
-            // these in the previous step of the references query.
-            if (dump.id === dumpId) {
+        for (const [i, dump] of remoteDumps.entries()) {
+            const results = await budget.run(remoteDumps.length - i, () =>
+                this.createDatabase(dump).monikerResults(sqliteModels.ReferenceModel, moniker, ctx)
+            )
+            if (results === undefined) {
+                logSpan(ctx, 'package_references_timeout', { repositoryId: dump.repositoryId, commit: dump.commit })
                 continue
             }
 
-            const references = (
-                await this.createDatabase(dump).monikerResults(sqliteModels.ReferenceModel, moniker, ctx)
-            ).map(loc => locationFromDatabase(dump.root, loc))
-            locations = locations.concat(references)
+            locations = locations.concat(results.map(loc => locationFromDatabase(dump.root, loc)))
         }
 
         return locations
@@ -768,6 +1031,23 @@ export class Backend {
         return undefined
     }
 
+    /**
+     * Remove the connection, documents, and result chunks of the given dump from
+     * their respective caches.
+     *
+     * @param id The dump identifier.
+     */
+    private async evictDump(id: pgModels.DumpId): Promise<void> {
+        const databasePath = dbFilename(this.storageRoot, id)
+        const isDumpKey = (key: string): boolean => key.startsWith(`${databasePath}::`)
+
+        await Promise.all([
+            this.connectionCache.bustKey(databasePath),
+            this.documentCache.bustKeys(isDumpKey),
+            this.resultChunkCache.bustKeys(isDumpKey),
+        ])
+    }
+
     /**
      * Create a database instance backed by the given dump.
      *
@@ -0,0 +1,27 @@
+import { QueryBudget } from './budget'
+
+describe('QueryBudget', () => {
+    const delayed = <T>(value: T, ms: number): Promise<T> =>
+        new Promise(resolve => setTimeout(() => resolve(value), ms))
+
+    it('should not bound queries without a deadline', async () => {
+        const budget = new QueryBudget()
+        expect(await budget.run(10, () => delayed('foo', 20))).toEqual('foo')
+        expect(budget.exceeded).toBeFalsy()
+    })
+
+    it('should return results of queries completing within their share', async () => {
+        const budget = QueryBudget.fromTimeout(1000)
+        expect(await budget.run(2, () => delayed('foo', 10))).toEqual('foo')
+        expect(budget.exceeded).toBeFalsy()
+    })
+
+    it('should abandon queries exceeding their share', async () => {
+        const budget = QueryBudget.fromTimeout(100)
+        expect(await budget.run(4, () => delayed('foo', 200))).toBeUndefined()
+        expect(budget.exceeded).toBeTruthy()
+
+        // Later queries are still given a share of the remaining time
+        expect(await budget.run(1, () => delayed('bar', 0))).toEqual('bar')
+    })
+})
@@ -0,0 +1,69 @@
+/**
+ * A sentinel value resolved by a query that exceeded its share of the budget.
+ */
+const timedOut = Symbol('timed-out')
+
+/**
+ * The time budget of a single request. The remaining time is divided among the dumps
+ * that are queried to answer the request so that a single slow dump cannot consume the
+ * time available to the dumps that are queried after it.
+ */
+export class QueryBudget {
+    /**
+     * Whether any query was abandoned because it did not complete within its share of
+     * the budget. If so, the results of the request are incomplete.
+     */
+    public exceeded = false
+
+    /**
+     * Create a new `QueryBudget`.
+     *
+     * @param deadline The time (in milliseconds since the epoch) at which the request must be answered.
+     *     If not supplied, queries are not bounded.
+     */
+    constructor(private deadline?: number) {}
+
+    /**
+     * Create a budget that expires the given number of milliseconds from now.
+     *
+     * @param timeout The maximum duration of the request. If not supplied, queries are not bounded.
+     */
+    public static fromTimeout(timeout?: number): QueryBudget {
+        return new QueryBudget(timeout === undefined ? undefined : Date.now() + timeout)
+    }
+
+    /**
+     * Run the given query with an even share of the remaining budget. If the query does not
+     * resolve in time, the budget is marked as exceeded and undefined is returned. The query
+     * itself is not cancelled, but its result is discarded.
+     *
+     * @param remainingQueries The number of queries that still have to run, including this one.
+     * @param query The function performing the query.
+     */
+    public async run<T>(remainingQueries: number, query: () => Promise<T>): Promise<T | undefined> {
+        if (this.deadline === undefined) {
+            return query()
+        }
+
+        const slice = Math.max(0, this.deadline - Date.now()) / Math.max(1, remainingQueries)
+
+        let timer: NodeJS.Timeout | undefined
+        const timeout = new Promise<typeof timedOut>(resolve => {
+            timer = setTimeout(() => resolve(timedOut), slice)
+        })
+
+        try {
+            const result = await Promise.race([query(), timeout])
+            if (result === timedOut) {
+                this.exceeded = true
+                return undefined
+            }
+
+            return result
+        } finally {
+            if (timer !== undefined) {
+                clearTimeout(timer)
+            }
+        }
+    }
+}
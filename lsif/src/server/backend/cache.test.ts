@@ -206,6 +206,29 @@ describe('GenericCache', () => {
         expect(factory.args).toHaveLength(2)
     })
 
+    it('should dispose busted keys matching a predicate', async () => {
+        const disposer = sinon.spy()
+        const cache = new GenericCache<string, string>(5, () => 1, disposer, testMetrics)
+
+        for (const key of ['dump1::foo', 'dump1::bar', 'dump2::foo']) {
+            await cache.withValue(
+                key,
+                () => Promise.resolve(key),
+                () => Promise.resolve()
+            )
+        }
+
+        await cache.bustKeys(key => key.startsWith('dump1::'))
+        expect(disposer.args.map(args => args[0]).sort()).toEqual(['dump1::bar', 'dump1::foo'])
+
+        // Ensure only the matching entries were removed
+        const factory = sinon.stub<string[], Promise<string>>()
+        factory.returns(Promise.resolve('baz'))
+        await cache.withValue('dump2::foo', factory, () => Promise.resolve())
+        await cache.withValue('dump1::foo', factory, () => Promise.resolve())
+        expect(factory.callCount).toEqual(1)
+    })
+
     it('should wait to dispose busted keys that are in use', async () => {
         const { wait: wait1, done: done1 } = createBarrierPromise()
         const { wait: wait2, done: done2 } = createBarrierPromise()
@@ -182,6 +182,17 @@ export class GenericCache<K, V> {
         await this.disposeFunction(value)
     }
 
+    /**
+     * Remove all keys matching the given predicate from the cache. This blocks
+     * until each of the busted values has been disposed. See `bustKey`.
+     *
+     * @param predicate The function that determines if a key should be removed.
+     */
+    public async bustKeys(predicate: (key: K) => boolean): Promise<void> {
+        const keys = Array.from(this.cache.keys()).filter(predicate)
+        await Promise.all(keys.map(key => this.bustKey(key)))
+    }
+
     /**
      * Check if `key` exists in the cache. If it does not, create a value
      * from `factory` and add it to the cache. In either case, update the
@@ -64,6 +64,21 @@ export class Database {
         )
     }
 
+    /**
+     * Return the paths of all documents in this database.
+     *
+     * @param ctx The tracing context.
+     */
+    public documentPaths(ctx: TracingContext = {}): Promise<string[]> {
+        return this.logAndTraceCall(ctx, 'Listing document paths', async () => {
+            const documents = await this.withConnection(connection =>
+                connection.getRepository(sqliteModels.DocumentModel).find({ select: ['path'] })
+            )
+
+            return documents.map(d => d.path)
+        })
+    }
+
     /**
      * Return the locations for the symbol at the given position.
      *
@@ -142,6 +157,37 @@ export class Database {
         })
     }
 
+    /**
+     * Return the number of locations which reference the symbol at the given position. This
+     * counts the entries of the reference results of the ranges at the given position without
+     * loading the documents that contain them, so it is much cheaper than `references`. The
+     * count is approximate: references to documents missing from the database are included.
+     *
+     * @param path The path of the document to which the position belongs.
+     * @param position The current hover position.
+     * @param ctx The tracing context.
+     */
+    public async referenceCount(path: string, position: lsp.Position, ctx: TracingContext = {}): Promise<number> {
+        return this.logAndTraceCall(ctx, 'Counting references', async ctx => {
+            const { document, ranges } = await this.getRangeByPosition(path, position, ctx)
+            if (!document || ranges.length === 0) {
+                return 0
+            }
+
+            // Ranges may share reference results, so count each location only once
+            const locations = new Set<string>()
+            for (const range of ranges) {
+                if (range.referenceResultId) {
+                    for (const { documentPath, rangeId } of await this.getResultById(range.referenceResultId)) {
+                        locations.add(`${documentPath}::${rangeId}`)
+                    }
+                }
+            }
+
+            return locations.size
+        })
+    }
+
     /**
      * Return the hover content for the symbol at the given position.
      *
@@ -500,7 +546,7 @@ export function sortMonikers(monikers: sqliteModels.MonikerData[]): sqliteModels
  *
  * @param result The start/end line/character of the range.
  */
-function createRange(result: {
+export function createRange(result: {
     startLine: number
     startCharacter: number
     endLine: number
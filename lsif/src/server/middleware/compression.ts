@@ -0,0 +1,41 @@
+import express from 'express'
+import { gzip } from 'mz/zlib'
+
+/**
+ * The size in bytes of the JSON representation of a response body below which the
+ * response is sent uncompressed, as compressing it is not worth the CPU time.
+ */
+const COMPRESSION_THRESHOLD = 8 * 1024
+
+/**
+ * Middleware function used to gzip large JSON responses, such as pages of reference
+ * results, for clients that accept gzip. Other responses are sent as is.
+ */
+export const compressionMiddleware = (req: express.Request, res: express.Response, next: express.NextFunction): void => {
+    res.vary('Accept-Encoding')
+
+    if (!req.headers['accept-encoding'] || req.acceptsEncodings('gzip') !== 'gzip') {
+        next()
+        return
+    }
+
+    const json = res.json.bind(res)
+    res.json = (body?: unknown): express.Response => {
+        if (body === undefined) {
+            return json(body)
+        }
+
+        const data = Buffer.from(JSON.stringify(body))
+        if (data.length < COMPRESSION_THRESHOLD) {
+            return json(body)
+        }
+
+        gzip(data).then(
+            compressed => res.set('Content-Encoding', 'gzip').type('json').send(compressed),
+            error => next(error)
+        )
+        return res
+    }
+
+    next()
+}
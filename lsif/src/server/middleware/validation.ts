@@ -85,6 +85,34 @@ export const validateOffset = validateOptionalInt('offset')
 export const validateCursor = <T>(): ValidationChain =>
     validateOptionalString('cursor').customSanitizer(value => parseCursor<T>(value))
 
+/**
+ * Create a query string validator for a non-empty list of at most `max` document positions,
+ * encoded as comma-separated `line:character` pairs. The value is sanitized into a list of
+ * positions.
+ *
+ * @param key The query string key.
+ * @param max The maximum number of positions.
+ */
+export const validatePositions = (key: string, max: number): ValidationChain =>
+    query(key)
+        .isString()
+        .custom((value: string) => {
+            const pairs = value.split(',')
+            if (pairs.length > max) {
+                throw new Error(`at most ${max} positions are allowed`)
+            }
+            if (!pairs.every(pair => /^\d+:\d+$/.test(pair))) {
+                throw new Error('positions must be comma-separated line:character pairs')
+            }
+            return true
+        })
+        .customSanitizer((value: string) =>
+            value.split(',').map(pair => {
+                const [line, character] = pair.split(':').map(v => parseInt(v, 10))
+                return { line, character }
+            })
+        )
+
 /**
  * Middleware function used to apply a sequence of validators and then return
  * an unprocessable entity response with an error message if validation fails.
@@ -1,5 +1,6 @@
 import * as constants from '../../shared/constants'
 import * as fs from 'mz/fs'
+import * as lsp from 'vscode-languageserver-protocol'
 import * as nodepath from 'path'
 import * as settings from '../settings'
 import * as validation from '../middleware/validation'
@@ -16,6 +17,7 @@ import { Span, Tracer } from 'opentracing'
 import { wrap } from 'async-middleware'
 import { extractLimitOffset } from '../pagination/limit-offset'
 import { UploadManager } from '../../shared/store/uploads'
+import { QueryBudget } from '../backend/budget'
 
 const pipeline = promisify(_pipeline)
 
@@ -114,6 +116,7 @@ export function createLsifRouter(
         repositoryId: number
         commit: string
         path: string
+        maxCommitDistance?: number
     }
 
     router.get(
@@ -122,13 +125,35 @@ export function createLsifRouter(
             validation.validateInt('repositoryId'),
             validation.validateNonEmptyString('commit').matches(commitPattern),
             validation.validateNonEmptyString('path'),
+            validation.validateOptionalInt('maxCommitDistance'),
         ]),
         wrap(
             async (req: express.Request, res: express.Response): Promise<void> => {
-                const { repositoryId, commit, path }: ExistsQueryArgs = req.query
+                const { repositoryId, commit, path, maxCommitDistance }: ExistsQueryArgs = req.query
                 const ctx = createTracingContext(req, { repositoryId, commit })
-                const upload = await backend.exists(repositoryId, commit, path, undefined, ctx)
-                res.json({ upload })
+                const uploads = await backend.existsAll(repositoryId, commit, path, ctx, maxCommitDistance)
+                res.json({ upload: uploads.length > 0 ? uploads[0] : undefined, uploads })
+            }
+        )
+    )
+
+    interface CoverageQueryArgs {
+        repositoryId: number
+        commit: string
+    }
+
+    router.get(
+        '/coverage',
+        validation.validationMiddleware([
+            validation.validateInt('repositoryId'),
+            validation.validateNonEmptyString('commit').matches(commitPattern),
+        ]),
+        wrap(
+            async (req: express.Request, res: express.Response): Promise<void> => {
+                const { repositoryId, commit }: CoverageQueryArgs = req.query
+                const ctx = createTracingContext(req, { repositoryId, commit })
+                const coverage = await backend.coverage(repositoryId, commit, ctx)
+                res.json({ uploads: coverage.map(({ dump, paths }) => ({ upload: dump, paths })) })
             }
         )
     )
@@ -140,6 +165,7 @@ export function createLsifRouter(
         line: number
         character: number
         uploadId?: number
+        timeout?: number
     }
 
     router.get(
@@ -151,31 +177,36 @@ export function createLsifRouter(
             validation.validateInt('line'),
             validation.validateInt('character'),
             validation.validateOptionalInt('uploadId'),
+            validation.validateOptionalInt('timeout'),
         ]),
         wrap(
             async (req: express.Request, res: express.Response): Promise<void> => {
-                const { repositoryId, commit, path, line, character, uploadId }: FilePositionArgs = req.query
+                const { repositoryId, commit, path, line, character, uploadId, timeout }: FilePositionArgs = req.query
                 const ctx = createTracingContext(req, { repositoryId, commit, path })
+                const budget = QueryBudget.fromTimeout(timeout)
 
-                const locations = await backend.definitions(
+                const result = await backend.definitionsWithRange(
                     repositoryId,
                     commit,
                     path,
                     { line, character },
                     uploadId,
-                    ctx
+                    ctx,
+                    budget
                 )
-                if (locations === undefined) {
+                if (result === undefined) {
                     throw Object.assign(new Error('LSIF upload not found'), { status: 404 })
                 }
 
                 res.send({
-                    locations: locations.map(l => ({
+                    locations: result.locations.map(l => ({
                         repositoryId: l.dump.repositoryId,
                         commit: l.dump.commit,
                         path: l.path,
                         range: l.range,
                     })),
+                    range: result.range,
+                    partial: budget.exceeded,
                 })
             }
         )
@@ -195,14 +226,25 @@ export function createLsifRouter(
             validation.validateInt('line'),
             validation.validateInt('character'),
             validation.validateOptionalInt('uploadId'),
+            validation.validateOptionalInt('timeout'),
             validation.validateLimit,
             validation.validateCursor<ReferencePaginationCursor>(),
         ]),
         wrap(
             async (req: express.Request, res: express.Response): Promise<void> => {
-                const { repositoryId, commit, path, line, character, uploadId, cursor }: ReferencesQueryArgs = req.query
+                const {
+                    repositoryId,
+                    commit,
+                    path,
+                    line,
+                    character,
+                    uploadId,
+                    timeout,
+                    cursor,
+                }: ReferencesQueryArgs = req.query
                 const { limit } = extractLimitOffset(req.query, settings.DEFAULT_REFERENCES_NUM_REMOTE_DUMPS)
                 const ctx = createTracingContext(req, { repositoryId, commit, path })
+                const budget = QueryBudget.fromTimeout(timeout)
 
                 const result = await backend.references(
                     repositoryId,
@@ -211,7 +253,8 @@ export function createLsifRouter(
                     { line, character },
                     { limit, cursor },
                     uploadId,
-                    ctx
+                    ctx,
+                    budget
                 )
                 if (result === undefined) {
                     throw Object.assign(new Error('LSIF upload not found'), { status: 404 })
@@ -230,11 +273,44 @@ export function createLsifRouter(
                         path: l.path,
                         range: l.range,
                     })),
+                    partial: budget.exceeded,
                 })
             }
         )
     )
 
+    router.get(
+        '/referenceCount',
+        validation.validationMiddleware([
+            validation.validateInt('repositoryId'),
+            validation.validateNonEmptyString('commit'),
+            validation.validateNonEmptyString('path'),
+            validation.validateInt('line'),
+            validation.validateInt('character'),
+            validation.validateOptionalInt('uploadId'),
+        ]),
+        wrap(
+            async (req: express.Request, res: express.Response): Promise<void> => {
+                const { repositoryId, commit, path, line, character, uploadId }: FilePositionArgs = req.query
+                const ctx = createTracingContext(req, { repositoryId, commit, path })
+
+                const count = await backend.referenceCount(
+                    repositoryId,
+                    commit,
+                    path,
+                    { line, character },
+                    uploadId,
+                    ctx
+                )
+                if (count === undefined) {
+                    throw Object.assign(new Error('LSIF upload not found'), { status: 404 })
+                }
+
+                res.json({ count })
+            }
+        )
+    )
+
     router.get(
         '/hover',
         validation.validationMiddleware([
@@ -260,5 +336,37 @@ export function createLsifRouter(
         )
     )
 
+    interface HoversQueryArgs {
+        repositoryId: number
+        commit: string
+        path: string
+        positions: lsp.Position[]
+        uploadId?: number
+    }
+
+    router.get(
+        '/hovers',
+        validation.validationMiddleware([
+            validation.validateInt('repositoryId'),
+            validation.validateNonEmptyString('commit'),
+            validation.validateNonEmptyString('path'),
+            validation.validatePositions('positions', settings.MAX_HOVER_POSITIONS),
+            validation.validateOptionalInt('uploadId'),
+        ]),
+        wrap(
+            async (req: express.Request, res: express.Response): Promise<void> => {
+                const { repositoryId, commit, path, positions, uploadId }: HoversQueryArgs = req.query
+                const ctx = createTracingContext(req, { repositoryId, commit, path, numPositions: positions.length })
+
+                const hovers = await backend.hovers(repositoryId, commit, path, positions, uploadId, ctx)
+                if (hovers === undefined) {
+                    throw Object.assign(new Error('LSIF upload not found'), { status: 404 })
+                }
+
+                res.json({ hovers })
+            }
+        )
+    )
+
     return router
 }
@@ -1,6 +1,7 @@
 import * as pgModels from '../../shared/models/pg'
 import * as settings from '../settings'
 import * as validation from '../middleware/validation'
+import { Backend } from '../backend/backend'
 import express from 'express'
 import { nextLink } from '../pagination/link'
 import { wrap } from 'async-middleware'
@@ -10,9 +11,10 @@ import { UploadManager } from '../../shared/store/uploads'
 /**
  * Create a router containing the upload endpoints.
  *
+ * @param backend The backend instance.
  * @param uploadManager The uploads manager instance.
  */
-export function createUploadRouter(uploadManager: UploadManager): express.Router {
+export function createUploadRouter(backend: Backend, uploadManager: UploadManager): express.Router {
     const router = express.Router()
 
     interface UploadsQueryArgs {
@@ -42,7 +44,7 @@ export function createUploadRouter(uploadManager: UploadManager): express.Router
         '/uploads/:id([0-9]+)',
         wrap(
             async (req: express.Request, res: express.Response): Promise<void> => {
-                if (await uploadManager.deleteUpload(parseInt(req.params.id, 10))) {
+                if (await backend.deleteUpload(uploadManager, parseInt(req.params.id, 10))) {
                     res.status(204).send()
                     return
                 }
@@ -54,6 +56,52 @@ export function createUploadRouter(uploadManager: UploadManager): express.Router
         )
     )
 
+    router.post(
+        '/uploads/:id([0-9]+)/retry',
+        wrap(
+            async (req: express.Request, res: express.Response): Promise<void> => {
+                if (await uploadManager.retryUpload(parseInt(req.params.id, 10))) {
+                    res.status(204).send()
+                    return
+                }
+
+                throw Object.assign(new Error('Errored upload not found'), {
+                    status: 404,
+                })
+            }
+        )
+    )
+
+    router.get(
+        '/uploads',
+        validation.validationMiddleware([
+            validation.validateQuery,
+            validation.validateLsifUploadState,
+            validation.validateLimit,
+            validation.validateOffset,
+        ]),
+        wrap(
+            async (req: express.Request, res: express.Response): Promise<void> => {
+                const { query, state }: UploadsQueryArgs = req.query
+                const { limit, offset } = extractLimitOffset(req.query, settings.DEFAULT_UPLOAD_PAGE_SIZE)
+                const { uploads, totalCount } = await uploadManager.getUploads(
+                    undefined,
+                    state,
+                    query,
+                    false,
+                    limit,
+                    offset
+                )
+
+                if (offset + uploads.length < totalCount) {
+                    res.set('Link', nextLink(req, { limit, offset: offset + uploads.length }))
+                }
+
+                res.json({ uploads, totalCount })
+            }
+        )
+    )
+
     router.get(
         '/uploads/repository/:id([0-9]+)',
         validation.validationMiddleware([
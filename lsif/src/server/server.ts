@@ -5,6 +5,7 @@ import * as path from 'path'
 import * as settings from './settings'
 import express from 'express'
 import promClient from 'prom-client'
+import { compressionMiddleware } from './middleware/compression'
 import { createClient } from 'redis'
 import { promisify } from 'util'
 import { Backend } from './backend/backend'
@@ -83,10 +84,11 @@ async function main(logger: Logger): Promise<void> {
         })
     )
     app.use(metricsMiddleware)
+    app.use(compressionMiddleware)
 
     // Register endpoints
     app.use(createMetaRouter())
-    app.use(createUploadRouter(uploadManager))
+    app.use(createUploadRouter(backend, uploadManager))
     app.use(createLsifRouter(backend, uploadManager, logger, tracer))
 
     // Error handler must be registered last
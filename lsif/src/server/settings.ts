@@ -91,3 +91,24 @@ export const STALLED_UPLOAD_MAX_AGE = readEnvInt('STALLED_UPLOAD_MAX_AGE', 5)
  * The maximum space (in bytes) that the dbs directory can use.
  */
 export const DBS_DIR_MAXIMUM_SIZE_BYTES = readEnvInt('DBS_DIR_MAXIMUM_SIZE_BYTES', 1024 * 1024 * 1024 * 10)
+
+/**
+ * A comma-separated list of git ref patterns, as accepted by `git for-each-ref`. The dumps
+ * of the commits of matching refs, such as tags and release branches, are retained when the
+ * dbs directory is pruned, so that code intelligence remains available for released versions.
+ * An empty list only retains the dumps visible from the tip of the default branch.
+ */
+export const RETAINED_REF_PATTERNS = (process.env.RETAINED_REF_PATTERNS ?? 'refs/tags,refs/heads/release/*')
+    .split(',')
+    .map(pattern => pattern.trim())
+    .filter(pattern => pattern !== '')
+
+/**
+ * The maximum number of positions of a single hovers request.
+ */
+export const MAX_HOVER_POSITIONS = readEnvInt('MAX_HOVER_POSITIONS', 100)
+
+/**
+ * The number of positions of a hovers request that are resolved concurrently.
+ */
+export const HOVER_BATCH_SIZE = readEnvInt('HOVER_BATCH_SIZE', 10)
@@ -13,6 +13,7 @@ import { Logger } from 'winston'
 import { tryWithLock } from '../../shared/store/locks'
 import { UploadManager } from '../../shared/store/uploads'
 import { DumpManager } from '../../shared/store/dumps'
+import { SRC_FRONTEND_INTERNAL } from '../../shared/config/settings'
 
 /**
  * Begin running cleanup tasks on a schedule in the background.
@@ -64,7 +65,15 @@ export function startTasks(
 
     runTask(
         wrapTask('Purging old dumps', ctx =>
-            purgeOldDumps(connection, dumpManager, settings.STORAGE_ROOT, settings.DBS_DIR_MAXIMUM_SIZE_BYTES, ctx)
+            purgeOldDumps(
+                connection,
+                dumpManager,
+                settings.STORAGE_ROOT,
+                settings.DBS_DIR_MAXIMUM_SIZE_BYTES,
+                SRC_FRONTEND_INTERNAL,
+                settings.RETAINED_REF_PATTERNS,
+                ctx
+            )
         ),
         settings.PURGE_OLD_DUMPS_INTERVAL
     )
@@ -10,6 +10,7 @@ import { UploadManager } from '../../shared/store/uploads'
 import { withLock } from '../../shared/store/locks'
 import { DumpManager } from '../../shared/store/dumps'
 import { dbFilename, idFromFilename } from '../../shared/paths'
+import { getRefCommits } from '../../shared/gitserver/gitserver'
 import { Connection } from 'typeorm'
 
 /**
@@ -54,12 +55,15 @@ export const cleanOldUploads = async (
 
 /**
  * Remove dumps until the space occupied by the dbs directory is below
- * the given limit.
+ * the given limit. Dumps of commits that a ref matching one of the given
+ * patterns points to are retained.
  *
  * @param connection The Postgres connection.
  * @param dumpManager The dumps manager instance.
  * @param storageRoot The path where SQLite databases are stored.
  * @param maximumSizeBytes The maximum number of bytes.
+ * @param frontendUrl The url of the frontend internal API.
+ * @param retainedRefPatterns The patterns of the refs whose dumps are retained.
  * @param ctx The tracing context.
  */
 export function purgeOldDumps(
@@ -67,6 +71,8 @@ export function purgeOldDumps(
     dumpManager: DumpManager,
     storageRoot: string,
     maximumSizeBytes: number,
+    frontendUrl: string,
+    retainedRefPatterns: string[],
     { logger = createSilentLogger() }: TracingContext = {}
 ): Promise<void> {
     const purge = async (): Promise<void> => {
@@ -83,18 +89,34 @@ export function purgeOldDumps(
 
         let currentSizeBytes = await dirsize(path.join(storageRoot, constants.DBS_DIR))
 
+        // The commits of the retained refs of each repository, fetched when a dump of the
+        // repository is first considered, and the dumps found to be at one of those commits
+        const retainedCommits = new Map<number, Set<string>>()
+        const retainedIds: number[] = []
+
         while (currentSizeBytes > maximumSizeBytes) {
             // While our current data usage is too big, find candidate dumps to delete
-            const dump = await dumpManager.getOldestPrunableDump()
+            const dump = await dumpManager.getOldestPrunableDump(retainedIds)
             if (!dump) {
                 logger.warn(
                     'Unable to reduce disk usage of the DB directory because deleting any single dump would drop in-use code intel for a repository.',
-                    { currentSizeBytes, softMaximumSizeBytes: maximumSizeBytes }
+                    { currentSizeBytes, softMaximumSizeBytes: maximumSizeBytes, retainedDumps: retainedIds.length }
                 )
 
                 break
             }
 
+            let commits = retainedCommits.get(dump.repositoryId)
+            if (!commits) {
+                commits = await getRefCommits(frontendUrl, dump.repositoryId, retainedRefPatterns, { logger })
+                retainedCommits.set(dump.repositoryId, commits)
+            }
+
+            if (commits.has(dump.commit)) {
+                retainedIds.push(dump.id)
+                continue
+            }
+
             logger.info('Pruning dump', {
                 repository: dump.repositoryId,
                 commit: dump.commit,
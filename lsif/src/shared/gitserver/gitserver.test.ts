@@ -1,5 +1,5 @@
 import nock from 'nock'
-import { flattenCommitParents, getCommitsNear } from './gitserver'
+import { flattenCommitParents, flattenRefCommits, getCommitsNear, getRefCommits } from './gitserver'
 
 describe('getCommitsNear', () => {
     it('should parse response from gitserver', async () => {
@@ -39,3 +39,35 @@ describe('flattenCommitParents', () => {
         )
     })
 })
+
+describe('getRefCommits', () => {
+    it('should parse response from gitserver', async () => {
+        nock('http://frontend')
+            .post('/.internal/git/42/exec', {
+                args: ['for-each-ref', '--format=%(objectname) %(*objectname)', 'refs/tags', 'refs/heads/release/*'],
+            })
+            .reply(200, 'a \nb c\nd ')
+
+        expect(await getRefCommits('frontend', 42, ['refs/tags', 'refs/heads/release/*'])).toEqual(
+            new Set(['a', 'c', 'd'])
+        )
+    })
+
+    it('should not query gitserver without patterns', async () => {
+        expect(await getRefCommits('frontend', 42, [])).toEqual(new Set())
+    })
+
+    it('should handle request for unknown repository', async () => {
+        nock('http://frontend')
+            .post('/.internal/git/42/exec')
+            .reply(404)
+
+        expect(await getRefCommits('frontend', 42, ['refs/tags'])).toEqual(new Set())
+    })
+})
+
+describe('flattenRefCommits', () => {
+    it('should prefer the commits of annotated tags', () => {
+        expect(flattenRefCommits(['a ', 'b c', '', 'd'])).toEqual(new Set(['a', 'c', 'd']))
+    })
+})
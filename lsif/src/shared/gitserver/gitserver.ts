@@ -81,6 +81,64 @@ export async function getHead(
     return lines[0]
 }
 
+/**
+ * Get the commits that the refs of the given repository matching one of the given patterns
+ * point to. The patterns are those accepted by `git for-each-ref`, e.g. `refs/tags` or
+ * `refs/heads/release/*`.
+ *
+ * If the repository is unknown by gitserver, then the result will be empty but no error
+ * will be thrown. Any other error type will be thrown without modification.
+ *
+ * @param frontendUrl The url of the frontend internal API.
+ * @param repositoryId The repository identifier.
+ * @param patterns The ref patterns.
+ * @param ctx The tracing context.
+ */
+export async function getRefCommits(
+    frontendUrl: string,
+    repositoryId: number,
+    patterns: string[],
+    ctx: TracingContext = {}
+): Promise<Set<string>> {
+    if (patterns.length === 0) {
+        return new Set()
+    }
+
+    const args = ['for-each-ref', '--format=%(objectname) %(*objectname)', ...patterns]
+
+    try {
+        return flattenRefCommits(await gitserverExecLines(frontendUrl, repositoryId, args, ctx))
+    } catch (error) {
+        if (error.statusCode === 404) {
+            // repository unknown
+            return new Set()
+        }
+
+        throw error
+    }
+}
+
+/**
+ * Convert git for-each-ref output into a set of commits. Each line of the input should have
+ * the form `object peeled`, where peeled is only present for annotated tags and is the commit
+ * the tag object points to.
+ *
+ * @param lines The output lines of `git for-each-ref`.
+ */
+export function flattenRefCommits(lines: string[]): Set<string> {
+    const commits = new Set<string>()
+    for (const line of lines) {
+        const objects = line.trim().split(' ')
+        if (objects[0] === '') {
+            continue
+        }
+
+        commits.add(objects[objects.length - 1])
+    }
+
+    return commits
+}
+
 /**
  * Execute a git command via gitserver and return its output split into non-empty lines.
  *
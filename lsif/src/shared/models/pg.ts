@@ -105,6 +105,14 @@ export class LsifUpload {
      */
     @Column('boolean', { name: 'visible_at_tip' })
     public visibleAtTip!: boolean
+
+    /**
+     * The name of the tool that produced the LSIF dump (e.g. `lsif-go`), as declared in
+     * its metadata vertex. This value is null until the upload has been converted, or if
+     * the indexer does not declare its name.
+     */
+    @Column('text', { nullable: true })
+    public indexer!: string | null
 }
 
 /**
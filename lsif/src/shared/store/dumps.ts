@@ -5,6 +5,7 @@ import { Brackets, Connection, EntityManager } from 'typeorm'
 import { logAndTraceCall, TracingContext } from '../tracing'
 import { instrumentQuery, instrumentQueryOrTransaction, withInstrumentedTransaction } from '../database/postgres'
 import { TableInserter } from '../database/inserter'
+import { MAX_TRAVERSAL_LIMIT } from '../constants'
 import { visibleDumps, lineageWithDumps, ancestorLineage, bidirectionalLineage } from '../models/queries'
 
 /**
@@ -95,20 +96,26 @@ export class DumpManager {
     /**
      * Get the oldest dump that is not visible at the tip of its repository.
      *
+     * @param excludedIds The identifiers of dumps that must not be pruned for other reasons.
      * @param entityManager The EntityManager to use as part of a transaction.
      */
     public getOldestPrunableDump(
+        excludedIds: number[] = [],
         entityManager: EntityManager = this.connection.createEntityManager()
     ): Promise<pgModels.LsifDump | undefined> {
-        return instrumentQuery(() =>
-            entityManager
+        return instrumentQuery(() => {
+            let query = entityManager
                 .getRepository(pgModels.LsifDump)
                 .createQueryBuilder()
                 .select()
                 .where({ visibleAtTip: false })
-                .orderBy('uploaded_at')
-                .getOne()
-        )
+
+            if (excludedIds.length > 0) {
+                query = query.andWhere('id NOT IN (:...excludedIds)', { excludedIds })
+            }
+
+            return query.orderBy('uploaded_at').getOne()
+        })
     }
 
     /**
@@ -128,6 +135,34 @@ export class DumpManager {
         ctx: TracingContext = {},
         frontendUrl?: string
     ): Promise<pgModels.LsifDump | undefined> {
+        const dumps = await this.findClosestDumps(repositoryId, commit, file, ctx, frontendUrl)
+        return dumps.length > 0 ? dumps[0] : undefined
+    }
+
+    /**
+     * Return the dumps 'closest' to the given target commit (a direct descendant or ancestor of
+     * the target commit) that contain the given file. A file is covered by several dumps when
+     * it is part of several roots, or when it was indexed by several indexers. Only the closest
+     * dump of each root and indexer is returned. The dumps are ordered by their distance to the
+     * target commit, then by decreasing root length (the most specific root first), then by
+     * identifier, so that the order is deterministic.
+     *
+     * @param repositoryId The repository identifier.
+     * @param commit The target commit.
+     * @param file One of the files in the dumps, or undefined to return the closest dumps of all roots.
+     * @param ctx The tracing context.
+     * @param frontendUrl The url of the frontend internal API.
+     * @param maxCommitDistance The maximum number of commits visited breadth-first from the target
+     * commit, including the target commit itself. It's capped at `MAX_TRAVERSAL_LIMIT`.
+     */
+    public async findClosestDumps(
+        repositoryId: number,
+        commit: string,
+        file: string | undefined,
+        ctx: TracingContext = {},
+        frontendUrl?: string,
+        maxCommitDistance: number = MAX_TRAVERSAL_LIMIT
+    ): Promise<pgModels.LsifDump[]> {
         // Request updated commit data from gitserver if this commit isn't already
         // tracked. This will pull back ancestors for this commit up to a certain
         // (configurable) depth and insert them into the database. This populates
@@ -140,24 +175,35 @@ export class DumpManager {
             )
         }
 
-        return logAndTraceCall(ctx, 'Finding closest dump', async () => {
+        return logAndTraceCall(ctx, 'Finding closest dumps', async () => {
             const query = `
                 WITH
                 ${bidirectionalLineage()},
-                ${lineageWithDumps()}
+                ${lineageWithDumps(Math.max(1, Math.min(maxCommitDistance, MAX_TRAVERSAL_LIMIT)))},
+                closest_dumps AS (
+                    SELECT DISTINCT ON (d.root, u.indexer) d.dump_id, d.root, d.n FROM lineage_with_dumps d
+                    JOIN lsif_dumps u ON u.id = d.dump_id
+                    WHERE $3::text IS NULL OR $3 LIKE (d.root || '%')
+                    ORDER BY d.root, u.indexer, d.n, d.dump_id
+                )
 
-                SELECT d.dump_id FROM lineage_with_dumps d
-                WHERE $3 LIKE (d.root || '%')
-                ORDER BY d.n LIMIT 1
+                SELECT d.dump_id FROM closest_dumps d
+                ORDER BY d.n, length(d.root) DESC, d.dump_id
             `
 
             return withInstrumentedTransaction(this.connection, async entityManager => {
-                const results: { dump_id: number }[] = await entityManager.query(query, [repositoryId, commit, file])
-                if (results.length > 0) {
-                    return entityManager.getRepository(pgModels.LsifDump).findOne(results[0].dump_id)
+                const results: { dump_id: number }[] = await entityManager.query(query, [
+                    repositoryId,
+                    commit,
+                    file === undefined ? null : file,
+                ])
+                if (results.length === 0) {
+                    return []
                 }
 
-                return undefined
+                const ids = results.map(r => r.dump_id)
+                const dumps = await entityManager.getRepository(pgModels.LsifDump).findByIds(ids)
+                return dumps.sort((a, b) => ids.indexOf(a.id) - ids.indexOf(b.id))
             })
         })
     }
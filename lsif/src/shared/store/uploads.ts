@@ -40,7 +40,7 @@ export class UploadManager {
     /**
      * Get the uploads in the given state.
      *
-     * @param repositoryId The repository identifier.
+     * @param repositoryId The repository identifier. If undefined, uploads of all repositories are returned.
      * @param state The state.
      * @param query A search query.
      * @param visibleAtTip If true, only return dumps visible at tip.
@@ -48,7 +48,7 @@ export class UploadManager {
      * @param offset The number of uploads to skip.
      */
     public async getUploads(
-        repositoryId: number,
+        repositoryId: number | undefined,
         state: pgModels.LsifUploadState | undefined,
         query: string,
         visibleAtTip: boolean,
@@ -59,11 +59,14 @@ export class UploadManager {
             let queryBuilder = this.connection
                 .getRepository(pgModels.LsifUpload)
                 .createQueryBuilder('upload')
-                .where({ repositoryId })
                 .orderBy('uploaded_at', 'DESC')
                 .limit(limit)
                 .offset(offset)
 
+            if (repositoryId !== undefined) {
+                queryBuilder = queryBuilder.andWhere('repository_id = :repositoryId', { repositoryId })
+            }
+
             if (state) {
                 queryBuilder = queryBuilder.andWhere('state = :state', { state })
             }
@@ -102,11 +105,54 @@ export class UploadManager {
     /**
      * Delete an upload. This returns true if the upload existed.
      *
+     * If the upload was visible from the tip of the default branch, the given function is
+     * called in the same transaction so that the visibility of the remaining dumps of the
+     * repository can be recalculated. Otherwise, the tip would not be able to fall back to
+     * an older dump until the next upload for that repository is converted.
+     *
+     * @param id The upload identifier.
+     * @param updateDumpsVisibleFromTip A function that recalculates the dumps visible from tip.
+     */
+    public deleteUpload(
+        id: number,
+        updateDumpsVisibleFromTip: (entityManager: EntityManager, repositoryId: number) => Promise<void>
+    ): Promise<boolean> {
+        return withInstrumentedTransaction(this.connection, async entityManager => {
+            const results: [{ repository_id: number; visible_at_tip: boolean }[]] = await entityManager.query(
+                'DELETE FROM lsif_uploads WHERE id = $1 RETURNING repository_id, visible_at_tip',
+                [id]
+            )
+
+            if (results[0].length === 0) {
+                return false
+            }
+
+            const { repository_id: repositoryId, visible_at_tip: visibleAtTip } = results[0][0]
+            if (visibleAtTip) {
+                await updateDumpsVisibleFromTip(entityManager, repositoryId)
+            }
+
+            return true
+        })
+    }
+
+    /**
+     * Move an errored upload back to the `queued` state so that the worker converts it
+     * again. This returns true if the upload existed and was errored.
+     *
      * @param id The upload identifier.
      */
-    public async deleteUpload(id: number): Promise<boolean> {
+    public async retryUpload(id: number): Promise<boolean> {
         const results: [{ id: number }[]] = await instrumentQuery(() =>
-            this.connection.query('DELETE FROM lsif_uploads WHERE id = $1 RETURNING id', [id])
+            this.connection.query(
+                `
+                    UPDATE lsif_uploads
+                    SET state = 'queued', started_at = null, finished_at = null, failure_summary = null, failure_stacktrace = null
+                    WHERE id = $1 AND state = 'errored'
+                    RETURNING id
+                `,
+                [id]
+            )
         )
 
         return results[0].length > 0
@@ -330,7 +376,7 @@ export class UploadManager {
     }
 
     /**
-     * Mark an upload as complete and set its finished timestamp.
+     * Mark an upload as complete and set its finished timestamp and indexer.
      *
      * @param upload The upload.
      * @param entityManager The EntityManager to use as part of a transaction.
@@ -339,8 +385,9 @@ export class UploadManager {
         upload: pgModels.LsifUpload,
         entityManager: EntityManager = this.connection.createEntityManager()
     ): Promise<void> {
-        return entityManager.query("UPDATE lsif_uploads SET state = 'completed', finished_at = now() WHERE id = $1", [
-            upload.id,
-        ])
+        return entityManager.query(
+            "UPDATE lsif_uploads SET state = 'completed', finished_at = now(), indexer = $2 WHERE id = $1",
+            [upload.id, upload.indexer]
+        )
     }
 }
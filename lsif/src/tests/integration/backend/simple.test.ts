@@ -42,6 +42,38 @@ describe('Backend', () => {
         ])
     })
 
+    it('should return the range of the symbol with its definitions', async () => {
+        if (!ctx.backend) {
+            fail('failed beforeAll')
+        }
+
+        const result = await ctx.backend.definitionsWithRange(repositoryId, commit, 'src/b.ts', {
+            line: 2,
+            character: 1,
+        })
+        expect(result?.locations.map(util.mapLocation)).toEqual([
+            util.createLocation(repositoryId, commit, 'src/a.ts', 0, 16, 0, 19),
+        ])
+        expect(result?.range).toEqual({ start: { line: 2, character: 0 }, end: { line: 2, character: 3 } })
+    })
+
+    it('should return the hovers of several positions in order', async () => {
+        if (!ctx.backend) {
+            fail('failed beforeAll')
+        }
+
+        const positions = [
+            { line: 2, character: 1 },
+            { line: 0, character: 17 },
+        ]
+        const hovers = await ctx.backend.hovers(repositoryId, commit, 'src/b.ts', positions)
+        const expected = await Promise.all(
+            positions.map(position => ctx.backend?.hover(repositoryId, commit, 'src/b.ts', position))
+        )
+        expect(hovers).toEqual(expected)
+        expect(hovers?.[0]?.range).toEqual({ start: { line: 2, character: 0 }, end: { line: 2, character: 3 } })
+    })
+
     it('should find all simple refs of `add` from a.ts', async () => {
         if (!ctx.backend) {
             fail('failed beforeAll')
@@ -65,4 +97,16 @@ describe('Backend', () => {
         expect(locations).toContainEqual(util.createLocation(repositoryId, commit, 'src/b.ts', 2, 14, 2, 17)) // use
         expect(locations).toHaveLength(5)
     })
+
+    it('should count all simple refs of `add` from a.ts', async () => {
+        if (!ctx.backend) {
+            fail('failed beforeAll')
+        }
+
+        const count = await ctx.backend.referenceCount(repositoryId, commit, 'src/a.ts', {
+            line: 0,
+            character: 17,
+        })
+        expect(count).toEqual(5)
+    })
 })
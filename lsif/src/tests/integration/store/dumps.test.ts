@@ -217,6 +217,78 @@ describe('DumpManager', () => {
         })
     })
 
+    it('should find the closest dumps of each root', async () => {
+        if (!dumpManager) {
+            fail('failed beforeAll')
+        }
+
+        // This database has the following commit graph:
+        //
+        // [a] --+-- [b] --+-- [c]
+        //
+        // Where LSIF dumps exist at a at root '', at b at root root1/, and at c at
+        // root root1/sub/.
+
+        const repositoryId = nextId()
+        const ca = util.createCommit()
+        const cb = util.createCommit()
+        const cc = util.createCommit()
+        const fields = ['commit', 'root']
+
+        // Add relations
+        await dumpManager.updateCommits(
+            repositoryId,
+            new Map<string, Set<string>>([
+                [ca, new Set()],
+                [cb, new Set([ca])],
+                [cc, new Set([cb])],
+            ])
+        )
+
+        // Add dumps
+        await util.insertDump(connection, dumpManager, repositoryId, ca, '')
+        await util.insertDump(connection, dumpManager, repositoryId, cb, 'root1/')
+        await util.insertDump(connection, dumpManager, repositoryId, cc, 'root1/sub/')
+
+        const closestDumps = async (commit: string, file: string | undefined): Promise<Partial<pgModels.LsifDump>[]> =>
+            (await dumpManager.findClosestDumps(repositoryId, commit, file)).map(dump => pick(dump, ...fields))
+
+        expect(await closestDumps(cc, 'root1/sub/file.ts')).toEqual([
+            { commit: cc, root: 'root1/sub/' },
+            { commit: cb, root: 'root1/' },
+            { commit: ca, root: '' },
+        ])
+        expect((await closestDumps(cb, 'root1/sub/file.ts'))[0]).toEqual({ commit: cb, root: 'root1/' })
+        expect(await closestDumps(cc, 'root2/file.ts')).toEqual([{ commit: ca, root: '' }])
+        expect(await closestDumps(cb, undefined)).toEqual([
+            { commit: cb, root: 'root1/' },
+            { commit: cc, root: 'root1/sub/' },
+            { commit: ca, root: '' },
+        ])
+        expect(pick(await dumpManager.findClosestDump(repositoryId, cc, 'root1/file.ts'), ...fields)).toEqual({
+            commit: cb,
+            root: 'root1/',
+        })
+
+        const closestDumpsWithin = async (maxCommitDistance: number): Promise<Partial<pgModels.LsifDump>[]> =>
+            (
+                await dumpManager.findClosestDumps(
+                    repositoryId,
+                    cc,
+                    'root1/sub/file.ts',
+                    {},
+                    undefined,
+                    maxCommitDistance
+                )
+            ).map(dump => pick(dump, ...fields))
+
+        expect(await closestDumpsWithin(1)).toEqual([{ commit: cc, root: 'root1/sub/' }])
+        expect(await closestDumpsWithin(3)).toEqual([
+            { commit: cc, root: 'root1/sub/' },
+            { commit: cb, root: 'root1/' },
+        ])
+    })
+
     it('should not return elements farther than MAX_TRAVERSAL_LIMIT', async () => {
         if (!dumpManager) {
             fail('failed beforeAll')
@@ -0,0 +1,106 @@
+import * as util from '../integration-test-util'
+import { Connection } from 'typeorm'
+import { DumpManager } from '../../../shared/store/dumps'
+import { UploadManager } from '../../../shared/store/uploads'
+import { fail } from 'assert'
+
+describe('UploadManager', () => {
+    let connection!: Connection
+    let cleanup!: () => Promise<void>
+    let dumpManager!: DumpManager
+    let uploadManager!: UploadManager
+
+    let counter = 400
+    const nextId = () => {
+        counter++
+        return counter
+    }
+
+    beforeAll(async () => {
+        ;({ connection, cleanup } = await util.createCleanPostgresDatabase())
+        dumpManager = new DumpManager(connection)
+        uploadManager = new UploadManager(connection)
+    })
+
+    afterAll(async () => {
+        if (cleanup) {
+            await cleanup()
+        }
+    })
+
+    beforeEach(async () => {
+        if (connection) {
+            await util.truncatePostgresTables(connection)
+        }
+    })
+
+    it('should update the dumps visible from tip when deleting a visible upload', async () => {
+        if (!uploadManager) {
+            fail('failed beforeAll')
+        }
+
+        // This database has the following commit graph:
+        //
+        // [a] -- [b]
+
+        const repositoryId = nextId()
+        const ca = util.createCommit()
+        const cb = util.createCommit()
+
+        await dumpManager.updateCommits(
+            repositoryId,
+            new Map<string, Set<string>>([
+                [ca, new Set<string>()],
+                [cb, new Set<string>([ca])],
+            ])
+        )
+        const d1 = await util.insertDump(connection, dumpManager, repositoryId, ca, '')
+        const d2 = await util.insertDump(connection, dumpManager, repositoryId, cb, '')
+        await dumpManager.updateDumpsVisibleFromTip(repositoryId, cb)
+
+        expect((await dumpManager.getDumpById(d1.id))?.visibleAtTip).toBeFalsy()
+        expect((await dumpManager.getDumpById(d2.id))?.visibleAtTip).toBeTruthy()
+
+        const updatedRepositoryIds: number[] = []
+        const deleted = await uploadManager.deleteUpload(d2.id, async (entityManager, id) => {
+            updatedRepositoryIds.push(id)
+            await dumpManager.updateDumpsVisibleFromTip(id, cb, {}, entityManager)
+        })
+
+        // The tip falls back to the dump of the older commit
+        expect(deleted).toBeTruthy()
+        expect(updatedRepositoryIds).toEqual([repositoryId])
+        expect(await dumpManager.getDumpById(d2.id)).toBeUndefined()
+        expect((await dumpManager.getDumpById(d1.id))?.visibleAtTip).toBeTruthy()
+    })
+
+    it('should not update the dumps visible from tip when deleting other uploads', async () => {
+        if (!uploadManager) {
+            fail('failed beforeAll')
+        }
+
+        const repositoryId = nextId()
+        const ca = util.createCommit()
+        const cb = util.createCommit()
+
+        await dumpManager.updateCommits(
+            repositoryId,
+            new Map<string, Set<string>>([
+                [ca, new Set<string>()],
+                [cb, new Set<string>([ca])],
+            ])
+        )
+        const d1 = await util.insertDump(connection, dumpManager, repositoryId, ca, '')
+        const d2 = await util.insertDump(connection, dumpManager, repositoryId, cb, '')
+        await dumpManager.updateDumpsVisibleFromTip(repositoryId, cb)
+
+        const updateDumpsVisibleFromTip = (): Promise<void> => {
+            throw new Error('Unexpected update of the dumps visible from tip')
+        }
+
+        expect(await uploadManager.deleteUpload(d1.id, updateDumpsVisibleFromTip)).toBeTruthy()
+        expect(await uploadManager.deleteUpload(d1.id, updateDumpsVisibleFromTip)).toBeFalsy()
+        expect(await dumpManager.getDumpById(d1.id)).toBeUndefined()
+        expect((await dumpManager.getDumpById(d2.id))?.visibleAtTip).toBeTruthy()
+    })
+})
@@ -13,7 +13,8 @@ import { DependencyManager } from '../../shared/store/dependencies'
 
 /**
  * Convert the LSIF dump input into a SQLite database and populate the dependency tables
- * with packages and reference data.
+ * with packages and reference data. The name of the indexer that produced the dump is
+ * set on the given upload, to be saved when it is marked as complete.
  *
  * @param entityManager The EntityManager to use as part of a transaction.
  * @param dumpManager The dumps manager instance.
@@ -32,7 +33,8 @@ export async function convertDatabase(
 
     try {
         // Create database in a temp path
-        const { packages, references } = await convertLsif(upload.filename, tempFile, { logger, span })
+        const { packages, references, indexer } = await convertLsif(upload.filename, tempFile, { logger, span })
+        upload.indexer = indexer || null
 
         // Insert dump and add packages and references to Postgres
         await dependencyManager.addPackagesAndReferences(
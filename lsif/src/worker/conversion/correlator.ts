@@ -60,6 +60,12 @@ export class Correlator {
      */
     public projectRoot?: URL
 
+    /**
+     * The name of the tool that produced the input, if declared. This is extracted
+     * from the metadata vertex at the beginning of processing.
+     */
+    public indexer?: string
+
     // Vertex data
     public documentPaths = new Map<sqliteModels.DocumentId, string>()
     public rangeData = new Map<lsif.RangeId, sqliteModels.RangeData>()
@@ -248,13 +254,15 @@ export class Correlator {
     /**
      * This should be the first vertex seen. Extract the project root so we
      * can create relative paths for documents and cache the LSIF protocol
-     * version that we will later insert into he metadata table.
+     * version that we will later insert into he metadata table. The name of
+     * the indexer is recorded on the upload.
      *
      * @param vertex The metadata vertex.
      */
     private handleMetaData(vertex: lsif.MetaData): void {
         this.lsifVersion = vertex.version
         this.projectRoot = new URL(vertex.projectRoot)
+        this.indexer = vertex.toolInfo?.name
     }
 
     //
@@ -55,7 +55,7 @@ export async function convertLsif(
     path: string,
     database: string,
     ctx: TracingContext = {}
-): Promise<{ packages: Package[]; references: SymbolReferences[] }> {
+): Promise<{ packages: Package[]; references: SymbolReferences[]; indexer?: string }> {
     const connection = await createSqliteConnection(database, sqliteModels.entities)
 
     try {
@@ -71,7 +71,8 @@ export async function convertLsif(
 /**
  * Correlate each vertex and edge together, then populate the provided entity manager
  * with the document, definition, and reference information. Returns the package and
- * external reference data needed to populate the dependency tables in Postgres.
+ * external reference data needed to populate the dependency tables in Postgres, and
+ * the name of the indexer that produced the dump.
  *
  * @param entityManager A transactional SQLite entity manager.
  * @param path The filepath containing a gzipped compressed stream of JSON lines composing the LSIF dump.
@@ -81,7 +82,7 @@ export async function importLsif(
     entityManager: EntityManager,
     path: string,
     ctx: TracingContext
-): Promise<{ packages: Package[]; references: SymbolReferences[] }> {
+): Promise<{ packages: Package[]; references: SymbolReferences[]; indexer?: string }> {
     // Correlate input data into in-memory maps
     const correlator = new Correlator(ctx)
     await logAndTraceCall(ctx, 'Correlating LSIF data', async () => {
@@ -160,7 +161,7 @@ export async function importLsif(
     })
 
     // Return data to populate dependency tables in Postgres
-    return { packages: getPackages(correlator), references: getReferences(correlator) }
+    return { packages: getPackages(correlator), references: getReferences(correlator), indexer: correlator.indexer }
 }
 
 /**
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_name_history (
+  id bigserial PRIMARY KEY,
+  campaign_id bigint NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE DEFERRABLE,
+  name text NOT NULL,
+  namespace_user_id integer REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  namespace_org_id integer REFERENCES orgs(id) ON DELETE CASCADE DEFERRABLE,
+  renamed_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS campaign_name_history_campaign_id ON campaign_name_history(campaign_id);
+CREATE INDEX IF NOT EXISTS campaign_name_history_name ON campaign_name_history(name);
+
+COMMIT;
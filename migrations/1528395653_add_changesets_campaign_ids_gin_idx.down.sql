@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS changesets_campaign_ids_gin_idx;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS changesets_campaign_ids_gin_idx ON changesets USING gin (campaign_ids);
+
+COMMIT;
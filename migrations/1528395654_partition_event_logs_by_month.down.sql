@@ -0,0 +1,31 @@
+BEGIN;
+
+-- Copies all events into a regular table again, which takes a while and needs
+-- disk space for a second copy of the events on instances with many events.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relname = 'event_logs' AND relkind = 'p') THEN
+        RETURN;
+    END IF;
+
+    ALTER SEQUENCE event_logs_id_seq OWNED BY NONE;
+    ALTER TABLE event_logs RENAME TO event_logs_partitioned;
+
+    CREATE TABLE event_logs (LIKE event_logs_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+    ALTER SEQUENCE event_logs_id_seq OWNED BY event_logs.id;
+
+    INSERT INTO event_logs SELECT * FROM event_logs_partitioned;
+
+    -- Dropping the partitioned table drops its partitions and indexes.
+    DROP TABLE event_logs_partitioned;
+
+    ALTER TABLE event_logs ADD CONSTRAINT event_logs_pkey PRIMARY KEY (id);
+    CREATE INDEX event_logs_name ON event_logs(name);
+    CREATE INDEX event_logs_source ON event_logs(source);
+    CREATE INDEX event_logs_timestamp ON event_logs("timestamp");
+    CREATE INDEX event_logs_timestamp_at_utc ON event_logs(DATE(timestamp AT TIME ZONE 'UTC'));
+    CREATE INDEX event_logs_user_id ON event_logs(user_id);
+END
+$$;
+
+COMMIT;
@@ -0,0 +1,116 @@
+BEGIN;
+
+-- Partitions event_logs by month so that usage statistics queries over a
+-- bounded time span only scan the partitions of that span, and so that expired
+-- events can be removed by dropping whole partitions.
+--
+-- A primary key on a partitioned table requires Postgres 11. On older versions
+-- event_logs stays a regular table. The partitions for upcoming months are
+-- created by EventLogs.MaintainPartitions in cmd/frontend/db, which does nothing
+-- if event_logs is not partitioned.
+--
+-- The existing events are not copied. The existing table becomes the partition
+-- event_logs_legacy for the time span of its events, which
+-- EventLogs.MaintainPartitions drops once they have all expired. Attaching it
+-- reads it once to validate the bounds and builds the index of the new primary
+-- key on it, during which event_logs is locked. That takes about as long as a
+-- sequential scan of the table, instead of the time to copy and index all events
+-- into new partitions, and doesn't need disk space for a second copy.
+DO $$
+DECLARE
+    legacy_start timestamptz;
+    legacy_end timestamptz := now();
+    month timestamp;
+    partition text;
+BEGIN
+    IF current_setting('server_version_num')::integer < 110000 THEN
+        RETURN;
+    END IF;
+
+    -- Keep the sequence when the old table is renamed below. The names of the
+    -- constraints and indexes of the old table are needed for those of the new
+    -- table.
+    ALTER SEQUENCE event_logs_id_seq OWNED BY NONE;
+    ALTER TABLE event_logs RENAME TO event_logs_legacy;
+    ALTER TABLE event_logs_legacy RENAME CONSTRAINT event_logs_pkey TO event_logs_legacy_pkey;
+    ALTER INDEX IF EXISTS event_logs_name RENAME TO event_logs_legacy_name;
+    ALTER INDEX IF EXISTS event_logs_source RENAME TO event_logs_legacy_source;
+    ALTER INDEX IF EXISTS event_logs_timestamp RENAME TO event_logs_legacy_timestamp;
+    ALTER INDEX IF EXISTS event_logs_timestamp_at_utc RENAME TO event_logs_legacy_timestamp_at_utc;
+    ALTER INDEX IF EXISTS event_logs_user_id RENAME TO event_logs_legacy_user_id;
+
+    CREATE TABLE event_logs (
+        id bigint DEFAULT nextval('event_logs_id_seq'::regclass) NOT NULL,
+        name text NOT NULL,
+        url text NOT NULL,
+        user_id integer NOT NULL,
+        anonymous_user_id text NOT NULL,
+        source text NOT NULL,
+        argument jsonb NOT NULL,
+        version text NOT NULL,
+        "timestamp" timestamp with time zone NOT NULL,
+        CONSTRAINT event_logs_check_has_user CHECK ((((user_id = 0) AND (anonymous_user_id <> ''::text)) OR ((user_id <> 0) AND (anonymous_user_id = ''::text)) OR ((user_id <> 0) AND (anonymous_user_id <> ''::text)))),
+        CONSTRAINT event_logs_check_name_not_empty CHECK ((name <> ''::text)),
+        CONSTRAINT event_logs_check_source_not_empty CHECK ((source <> ''::text)),
+        CONSTRAINT event_logs_check_url_not_empty CHECK ((url <> ''::text)),
+        CONSTRAINT event_logs_check_version_not_empty CHECK ((version <> ''::text))
+    ) PARTITION BY RANGE ("timestamp");
+
+    ALTER SEQUENCE event_logs_id_seq OWNED BY event_logs.id;
+
+    -- Catches events outside of the other partitions, e.g. from clients with a
+    -- wrong clock.
+    CREATE TABLE event_logs_default PARTITION OF event_logs DEFAULT;
+
+    SELECT MIN("timestamp") INTO legacy_start FROM event_logs_legacy;
+    IF legacy_start IS NULL THEN
+        DROP TABLE event_logs_legacy;
+        legacy_end := NULL;
+    END IF;
+
+    -- Create a partition (named event_logs_yYYYYmMM) for every month from now up
+    -- to two months from now. The partition of this month starts where
+    -- event_logs_legacy ends.
+    FOR month IN
+        SELECT generate_series(
+            date_trunc('month', now() AT TIME ZONE 'UTC'),
+            date_trunc('month', now() AT TIME ZONE 'UTC') + interval '2 months',
+            interval '1 month'
+        )
+    LOOP
+        partition := 'event_logs_y' || to_char(month, 'YYYY"m"MM');
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF event_logs FOR VALUES FROM (%L) TO (%L)',
+            partition,
+            GREATEST(month AT TIME ZONE 'UTC', legacy_end),
+            (month + interval '1 month') AT TIME ZONE 'UTC'
+        );
+    END LOOP;
+
+    IF legacy_start IS NOT NULL THEN
+        -- Events from the future (e.g. from clients with a wrong clock) don't fit
+        -- into the bounds of event_logs_legacy. There are few, so they're moved.
+        WITH moved AS (
+            DELETE FROM event_logs_legacy WHERE "timestamp" >= legacy_end RETURNING *
+        )
+        INSERT INTO event_logs SELECT * FROM moved;
+
+        EXECUTE format(
+            'ALTER TABLE event_logs ATTACH PARTITION event_logs_legacy FOR VALUES FROM (%L) TO (%L)',
+            legacy_start,
+            legacy_end
+        );
+    END IF;
+
+    -- The partition key has to be part of the primary key. The existing indexes
+    -- of event_logs_legacy are attached to the new ones instead of being rebuilt.
+    ALTER TABLE event_logs ADD CONSTRAINT event_logs_pkey PRIMARY KEY (id, "timestamp");
+    CREATE INDEX event_logs_name ON event_logs(name);
+    CREATE INDEX event_logs_source ON event_logs(source);
+    CREATE INDEX event_logs_timestamp ON event_logs("timestamp");
+    CREATE INDEX event_logs_timestamp_at_utc ON event_logs(DATE(timestamp AT TIME ZONE 'UTC'));
+    CREATE INDEX event_logs_user_id ON event_logs(user_id);
+END
+$$;
+
+COMMIT;
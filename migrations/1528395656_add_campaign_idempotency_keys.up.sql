@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_idempotency_keys (
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  mutation text NOT NULL,
+  key text NOT NULL,
+  result_ids jsonb,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, mutation, key),
+  CONSTRAINT campaign_idempotency_keys_key_check CHECK (key <> ''),
+  CONSTRAINT campaign_idempotency_keys_result_ids_check CHECK (jsonb_typeof(result_ids) = 'array')
+);
+
+CREATE INDEX IF NOT EXISTS campaign_idempotency_keys_created_at ON campaign_idempotency_keys(created_at);
+
+COMMIT;
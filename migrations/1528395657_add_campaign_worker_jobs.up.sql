@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_worker_jobs (
+  id bigserial PRIMARY KEY,
+  queue text NOT NULL,
+  payload jsonb NOT NULL DEFAULT '{}'::jsonb,
+  state text NOT NULL DEFAULT 'QUEUED',
+  attempts integer NOT NULL DEFAULT 0,
+  max_attempts integer NOT NULL DEFAULT 5,
+  error text,
+  run_after timestamp with time zone NOT NULL DEFAULT now(),
+  heartbeat_at timestamp with time zone,
+  started_at timestamp with time zone,
+  finished_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT campaign_worker_jobs_queue_check CHECK (queue <> ''),
+  CONSTRAINT campaign_worker_jobs_payload_check CHECK (jsonb_typeof(payload) = 'object'),
+  CONSTRAINT campaign_worker_jobs_state_check CHECK (state IN ('QUEUED', 'PROCESSING', 'COMPLETED', 'DEAD')),
+  CONSTRAINT campaign_worker_jobs_max_attempts_check CHECK (max_attempts > 0)
+);
+
+CREATE INDEX IF NOT EXISTS campaign_worker_jobs_queue_state_run_after ON campaign_worker_jobs(queue, state, run_after);
+
+-- Changeset jobs that haven't been started yet are run by the worker queue
+-- from now on.
+INSERT INTO campaign_worker_jobs (queue, payload)
+SELECT 'changeset_jobs', jsonb_build_object('changeset_job_id', id)
+FROM changeset_jobs
+WHERE started_at IS NULL
+ORDER BY id ASC;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS aggregated_search_latencies;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS aggregated_search_latencies (
+  day date NOT NULL,
+  filter text NOT NULL,
+  p50 double precision NOT NULL,
+  p90 double precision NOT NULL,
+  p99 double precision NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY (day, filter)
+);
+
+COMMIT;
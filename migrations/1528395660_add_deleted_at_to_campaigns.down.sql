@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM campaigns WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS campaigns_deleted_at;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS campaigns_deleted_at ON campaigns USING btree (deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
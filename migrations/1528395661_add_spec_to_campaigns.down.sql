@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_spec_check;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS spec;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS spec jsonb;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_spec_check CHECK (spec IS NULL OR (jsonb_typeof(spec) = 'object' AND spec ? 'version'));
+
+COMMIT;
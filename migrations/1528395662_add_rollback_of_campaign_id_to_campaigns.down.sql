@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS campaigns_rollback_of_campaign_id;
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_rollback_of_campaign_id_fkey;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS rollback_of_campaign_id;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS rollback_of_campaign_id bigint;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_rollback_of_campaign_id_fkey FOREIGN KEY (rollback_of_campaign_id) REFERENCES campaigns(id) ON DELETE SET NULL DEFERRABLE;
+CREATE INDEX IF NOT EXISTS campaigns_rollback_of_campaign_id ON campaigns(rollback_of_campaign_id) WHERE rollback_of_campaign_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS campaigns_parent_campaign_id;
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_parent_campaign_id_check;
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_parent_campaign_id_fkey;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS parent_campaign_id;
+
+COMMIT;
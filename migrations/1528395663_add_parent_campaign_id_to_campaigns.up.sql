@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS parent_campaign_id bigint;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_parent_campaign_id_fkey FOREIGN KEY (parent_campaign_id) REFERENCES campaigns(id) ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_parent_campaign_id_check CHECK (parent_campaign_id <> id);
+CREATE INDEX IF NOT EXISTS campaigns_parent_campaign_id ON campaigns(parent_campaign_id) WHERE parent_campaign_id IS NOT NULL;
+
+COMMIT;
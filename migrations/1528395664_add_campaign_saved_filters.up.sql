@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_saved_filters (
+  id bigserial PRIMARY KEY,
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  name text NOT NULL,
+  filters jsonb NOT NULL DEFAULT '{}'::jsonb,
+  is_default boolean NOT NULL DEFAULT false,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT campaign_saved_filters_user_id_name_unique UNIQUE (user_id, name),
+  CONSTRAINT campaign_saved_filters_name_check CHECK (name <> ''),
+  CONSTRAINT campaign_saved_filters_filters_check CHECK (jsonb_typeof(filters) = 'object')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS campaign_saved_filters_user_id_default ON campaign_saved_filters(user_id) WHERE is_default;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Drop view dependent on the column
+DROP VIEW lsif_dumps;
+
+ALTER TABLE lsif_uploads DROP COLUMN IF EXISTS indexer;
+
+CREATE VIEW lsif_dumps AS SELECT u.*, u.finished_at as processed_at FROM lsif_uploads u WHERE state = 'completed';
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE lsif_uploads ADD COLUMN IF NOT EXISTS indexer text;
+
+-- Recreate view so that it includes the new column
+DROP VIEW lsif_dumps;
+CREATE VIEW lsif_dumps AS SELECT u.*, u.finished_at as processed_at FROM lsif_uploads u WHERE state = 'completed';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE changeset_jobs DROP COLUMN IF EXISTS reviewers_requested_at;
+ALTER TABLE changeset_jobs DROP COLUMN IF EXISTS reviewers_error;
+
+COMMIT;
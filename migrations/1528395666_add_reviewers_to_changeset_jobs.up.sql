@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE changeset_jobs ADD COLUMN IF NOT EXISTS reviewers_requested_at timestamp with time zone;
+ALTER TABLE changeset_jobs ADD COLUMN IF NOT EXISTS reviewers_error text;
+
+COMMIT;
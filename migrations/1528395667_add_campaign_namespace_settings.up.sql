@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_namespace_settings (
+  id bigserial PRIMARY KEY,
+  namespace_user_id integer REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  namespace_org_id integer REFERENCES orgs(id) ON DELETE CASCADE DEFERRABLE,
+  branch_prefix text NOT NULL DEFAULT '',
+  default_labels jsonb NOT NULL DEFAULT '[]'::jsonb,
+  require_approval boolean NOT NULL DEFAULT false,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT campaign_namespace_settings_namespace_user_id_unique UNIQUE (namespace_user_id),
+  CONSTRAINT campaign_namespace_settings_namespace_org_id_unique UNIQUE (namespace_org_id),
+  CONSTRAINT campaign_namespace_settings_has_1_namespace CHECK ((namespace_user_id IS NULL) <> (namespace_org_id IS NULL)),
+  CONSTRAINT campaign_namespace_settings_default_labels_check CHECK (jsonb_typeof(default_labels) = 'array')
+);
+
+COMMIT;
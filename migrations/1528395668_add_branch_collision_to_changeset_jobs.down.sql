@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE changeset_jobs DROP COLUMN IF EXISTS branch_collision;
+
+COMMIT;
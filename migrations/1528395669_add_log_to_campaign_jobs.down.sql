@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE campaign_jobs DROP COLUMN IF EXISTS log;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE campaign_jobs ADD COLUMN IF NOT EXISTS log text NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE campaign_jobs ADD COLUMN IF NOT EXISTS log text NOT NULL DEFAULT '';
+
+UPDATE campaign_jobs SET log = chunks.log
+FROM (
+  SELECT campaign_job_id, string_agg(output, '' ORDER BY id) AS log
+  FROM campaign_job_log_chunks
+  GROUP BY campaign_job_id
+) AS chunks
+WHERE campaign_jobs.id = chunks.campaign_job_id;
+
+DROP TABLE IF EXISTS campaign_job_log_chunks;
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_job_log_chunks (
+  id bigserial PRIMARY KEY,
+  campaign_job_id bigint NOT NULL REFERENCES campaign_jobs(id) ON DELETE CASCADE DEFERRABLE,
+  output text NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT campaign_job_log_chunks_output_check CHECK (output <> '')
+);
+
+CREATE INDEX IF NOT EXISTS campaign_job_log_chunks_campaign_job_id ON campaign_job_log_chunks(campaign_job_id, id);
+
+INSERT INTO campaign_job_log_chunks (campaign_job_id, output)
+SELECT id, log FROM campaign_jobs WHERE log <> '' ORDER BY id;
+
+ALTER TABLE campaign_jobs DROP COLUMN IF EXISTS log;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS campaign_notifications;
+DROP TABLE IF EXISTS campaign_subscriptions;
+DROP TABLE IF EXISTS campaign_notification_settings;
+
+COMMIT;
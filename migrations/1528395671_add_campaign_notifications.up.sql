@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_notification_settings (
+  user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  notify_merged boolean NOT NULL DEFAULT true,
+  notify_closed boolean NOT NULL DEFAULT true,
+  notify_checks_failed boolean NOT NULL DEFAULT true,
+  digest_interval_minutes integer NOT NULL DEFAULT 15,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT campaign_notification_settings_digest_interval_check CHECK (digest_interval_minutes >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS campaign_subscriptions (
+  campaign_id bigint NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE DEFERRABLE,
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  subscribed boolean NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  PRIMARY KEY (campaign_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS campaign_notifications (
+  id bigserial PRIMARY KEY,
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  campaign_id bigint NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE DEFERRABLE,
+  changeset_id bigint NOT NULL REFERENCES changesets(id) ON DELETE CASCADE DEFERRABLE,
+  kind text NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  sent_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS campaign_notifications_unsent ON campaign_notifications (user_id, created_at) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS campaign_notifications_created_at ON campaign_notifications (created_at);
+
+COMMIT;
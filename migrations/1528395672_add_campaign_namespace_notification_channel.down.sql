@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE campaign_namespace_settings DROP COLUMN IF EXISTS notification_webhook_url;
+ALTER TABLE campaign_namespace_settings DROP COLUMN IF EXISTS notification_channel;
+
+COMMIT;
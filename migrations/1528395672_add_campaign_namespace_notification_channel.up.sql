@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE campaign_namespace_settings ADD COLUMN IF NOT EXISTS notification_channel text NOT NULL DEFAULT 'EMAIL';
+ALTER TABLE campaign_namespace_settings ADD COLUMN IF NOT EXISTS notification_webhook_url text NOT NULL DEFAULT '';
+
+ALTER TABLE campaign_namespace_settings ADD CONSTRAINT campaign_namespace_settings_notification_channel_check
+  CHECK (notification_channel IN ('EMAIL', 'SLACK', 'WEBHOOK'));
+ALTER TABLE campaign_namespace_settings ADD CONSTRAINT campaign_namespace_settings_notification_webhook_url_check
+  CHECK (notification_channel = 'EMAIL' OR notification_webhook_url <> '');
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS campaign_webhook_delivery_attempts;
+DROP TABLE IF EXISTS campaign_webhook_deliveries;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign_webhook_deliveries (
+  id bigserial PRIMARY KEY,
+  channel text NOT NULL,
+  url text NOT NULL,
+  recipient_user_id integer REFERENCES users(id) ON DELETE SET NULL DEFERRABLE,
+  payload jsonb NOT NULL,
+  attempt_count integer NOT NULL DEFAULT 0,
+  last_attempted_at timestamp with time zone,
+  succeeded_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS campaign_webhook_deliveries_failed ON campaign_webhook_deliveries (id) WHERE succeeded_at IS NULL;
+CREATE INDEX IF NOT EXISTS campaign_webhook_deliveries_created_at ON campaign_webhook_deliveries (created_at);
+
+CREATE TABLE IF NOT EXISTS campaign_webhook_delivery_attempts (
+  id bigserial PRIMARY KEY,
+  delivery_id bigint NOT NULL REFERENCES campaign_webhook_deliveries(id) ON DELETE CASCADE DEFERRABLE,
+  succeeded boolean NOT NULL,
+  status_code integer,
+  response_body text,
+  error text,
+  duration_ms integer NOT NULL,
+  attempted_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS campaign_webhook_delivery_attempts_delivery_id ON campaign_webhook_delivery_attempts (delivery_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS feature_flag_overrides;
+DROP TABLE IF EXISTS feature_flags;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+  name text PRIMARY KEY CHECK (name <> ''),
+  enabled boolean NOT NULL,
+  rollout_percentage integer CHECK (rollout_percentage >= 0 AND rollout_percentage <= 100),
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+  id bigserial PRIMARY KEY,
+  flag_name text NOT NULL,
+  namespace_user_id integer REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  namespace_org_id integer REFERENCES orgs(id) ON DELETE CASCADE DEFERRABLE,
+  enabled boolean NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT feature_flag_overrides_has_1_namespace CHECK ((namespace_user_id IS NULL) <> (namespace_org_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS feature_flag_overrides_unique_user ON feature_flag_overrides (flag_name, namespace_user_id) WHERE namespace_user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS feature_flag_overrides_unique_org ON feature_flag_overrides (flag_name, namespace_org_id) WHERE namespace_org_id IS NOT NULL;
+
+COMMIT;
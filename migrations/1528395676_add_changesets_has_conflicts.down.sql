@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changesets DROP COLUMN IF EXISTS has_conflicts;
+ALTER TABLE changesets DROP COLUMN IF EXISTS conflicts_base_oid;
+ALTER TABLE changesets DROP COLUMN IF EXISTS conflicts_head_oid;
+
+COMMIT;
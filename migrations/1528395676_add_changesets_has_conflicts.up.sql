@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS has_conflicts boolean;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS conflicts_base_oid text;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS conflicts_head_oid text;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_upload_tokens (
+  id bigserial PRIMARY KEY,
+  repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE,
+  value_sha256 bytea NOT NULL UNIQUE,
+  note text NOT NULL,
+  creator_user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  last_used_at timestamp with time zone,
+  deleted_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS lsif_upload_tokens_repo_id ON lsif_upload_tokens(repo_id) WHERE deleted_at IS NULL;
+
+COMMIT;
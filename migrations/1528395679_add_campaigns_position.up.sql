@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS position text COLLATE "C";
+
+-- Existing campaigns keep their order by creation, like the initial positions
+-- of new campaigns.
+UPDATE campaigns
+SET position = lpad(to_hex((extract(epoch FROM created_at) * 1000000)::bigint), 14, '0') || 'V'
+WHERE position IS NULL;
+
+ALTER TABLE campaigns ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS campaigns_position ON campaigns(position, id) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS usage_statistics_jobs (
+  name text PRIMARY KEY,
+  started_at timestamp with time zone NOT NULL,
+  finished_at timestamp with time zone NOT NULL,
+  rows_processed bigint NOT NULL DEFAULT 0,
+  error text,
+  last_succeeded_at timestamp with time zone
+);
+
+COMMIT;
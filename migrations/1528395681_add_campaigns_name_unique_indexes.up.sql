@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Campaign names are unique within a namespace. Campaigns that were created
+-- with the name of an older campaign in the same namespace before this was
+-- enforced get their ID appended to their name.
+UPDATE campaigns c
+SET name = c.name || ' (' || c.id || ')'
+WHERE c.deleted_at IS NULL AND EXISTS (
+  SELECT 1
+  FROM campaigns o
+  WHERE o.deleted_at IS NULL
+    AND o.id < c.id
+    AND o.name = c.name
+    AND o.namespace_user_id IS NOT DISTINCT FROM c.namespace_user_id
+    AND o.namespace_org_id IS NOT DISTINCT FROM c.namespace_org_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS campaigns_namespace_user_id_name_unique ON campaigns(namespace_user_id, name) WHERE deleted_at IS NULL AND namespace_user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS campaigns_namespace_org_id_name_unique ON campaigns(namespace_org_id, name) WHERE deleted_at IS NULL AND namespace_org_id IS NOT NULL;
+
+COMMIT;
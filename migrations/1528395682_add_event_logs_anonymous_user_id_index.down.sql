@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS event_logs_anonymous_user_id;
+
+COMMIT;
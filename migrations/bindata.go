@@ -68,6 +68,70 @@
 // 1528395649_add_campaign_branch.up.sql (820B)
 // 1528395650_add_versions_table.down.sql (48B)
 // 1528395650_add_versions_table.up.sql (159B)
+// 1528395651_add_campaign_name_history.down.sql (61B)
+// 1528395651_add_campaign_name_history.up.sql (597B)
+// 1528395652_add_changeset_close_jobs.down.sql (60B)
+// 1528395652_add_changeset_close_jobs.up.sql (472B)
+// 1528395653_add_changesets_campaign_ids_gin_idx.down.sql (71B)
+// 1528395653_add_changesets_campaign_ids_gin_idx.up.sql (116B)
+// 1528395654_partition_event_logs_by_month.down.sql (1.181kB)
+// 1528395654_partition_event_logs_by_month.up.sql (5.383kB)
+// 1528395655_add_changesets_diff_stat.down.sql (207B)
+// 1528395655_add_changesets_diff_stat.up.sql (240B)
+// 1528395656_add_campaign_idempotency_keys.down.sql (65B)
+// 1528395656_add_campaign_idempotency_keys.up.sql (596B)
+// 1528395657_add_campaign_worker_jobs.down.sql (60B)
+// 1528395657_add_campaign_worker_jobs.up.sql (1.347kB)
+// 1528395658_add_aggregated_search_latencies.down.sql (67B)
+// 1528395658_add_aggregated_search_latencies.up.sql (311B)
+// 1528395659_add_user_usage_digests.down.sql (58B)
+// 1528395659_add_user_usage_digests.up.sql (308B)
+// 1528395660_add_deleted_at_to_campaigns.down.sql (169B)
+// 1528395660_add_deleted_at_to_campaigns.up.sql (205B)
+// 1528395661_add_spec_to_campaigns.down.sql (137B)
+// 1528395661_add_spec_to_campaigns.up.sql (212B)
+// 1528395662_add_rollback_of_campaign_id_to_campaigns.down.sql (230B)
+// 1528395662_add_rollback_of_campaign_id_to_campaigns.up.sql (408B)
+// 1528395663_add_parent_campaign_id_to_campaigns.down.sql (299B)
+// 1528395663_add_parent_campaign_id_to_campaigns.up.sql (484B)
+// 1528395664_add_campaign_saved_filters.down.sql (62B)
+// 1528395664_add_campaign_saved_filters.up.sql (780B)
+// 1528395665_add_indexer_to_lsif_uploads.down.sql (249B)
+// 1528395665_add_indexer_to_lsif_uploads.up.sql (271B)
+// 1528395666_add_reviewers_to_changeset_jobs.down.sql (156B)
+// 1528395666_add_reviewers_to_changeset_jobs.up.sql (192B)
+// 1528395667_add_campaign_namespace_settings.down.sql (67B)
+// 1528395667_add_campaign_namespace_settings.up.sql (950B)
+// 1528395668_add_branch_collision_to_changeset_jobs.down.sql (84B)
+// 1528395668_add_branch_collision_to_changeset_jobs.up.sql (92B)
+// 1528395669_add_log_to_campaign_jobs.down.sql (70B)
+// 1528395669_add_log_to_campaign_jobs.up.sql (98B)
+// 1528395670_add_campaign_job_log_chunks.down.sql (382B)
+// 1528395670_add_campaign_job_log_chunks.up.sql (648B)
+// 1528395671_add_campaign_notifications.down.sql (160B)
+// 1528395671_add_campaign_notifications.up.sql (1.685kB)
+// 1528395672_add_campaign_namespace_notification_channel.down.sql (189B)
+// 1528395672_add_campaign_namespace_notification_channel.up.sql (616B)
+// 1528395673_add_campaign_views.down.sql (54B)
+// 1528395673_add_campaign_views.up.sql (405B)
+// 1528395674_add_campaign_webhook_deliveries.down.sql (124B)
+// 1528395674_add_campaign_webhook_deliveries.up.sql (1.198kB)
+// 1528395675_add_feature_flags.down.sql (98B)
+// 1528395675_add_feature_flags.up.sql (1.211kB)
+// 1528395676_add_changesets_has_conflicts.down.sql (207B)
+// 1528395676_add_changesets_has_conflicts.up.sql (234B)
+// 1528395677_add_campaigns_auto_rebase.down.sql (74B)
+// 1528395677_add_campaigns_auto_rebase.up.sql (108B)
+// 1528395678_add_lsif_upload_tokens.down.sql (58B)
+// 1528395678_add_lsif_upload_tokens.up.sql (572B)
+// 1528395679_add_campaigns_position.down.sql (71B)
+// 1528395679_add_campaigns_position.up.sql (488B)
+// 1528395680_add_usage_statistics_jobs.down.sql (61B)
+// 1528395680_add_usage_statistics_jobs.up.sql (296B)
+// 1528395681_add_campaigns_name_unique_indexes.down.sql (140B)
+// 1528395681_add_campaigns_name_unique_indexes.up.sql (903B)
+// 1528395682_add_event_logs_anonymous_user_id_index.down.sql (68B)
+// 1528395682_add_event_logs_anonymous_user_id_index.up.sql (107B)
 
 package migrations
 
@@ -1496,6 +1560,1286 @@ func _1528395650_add_versions_tableUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395651_add_campaign_name_historyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\x4b\xcc\x4d\x8d\xcf\xc8\x2c\x2e\xc9\x2f\xaa\x04\x2a\x76\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x2b\x9d\x1e\x6a\x3d\x00\x00\x00")
+
+func _1528395651_add_campaign_name_historyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395651_add_campaign_name_historyDownSql,
+		"1528395651_add_campaign_name_history.down.sql",
+	)
+}
+
+func _1528395651_add_campaign_name_historyDownSql() (*asset, error) {
+	bytes, err := _1528395651_add_campaign_name_historyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395651_add_campaign_name_history.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8a, 0xef, 0x4e, 0xcf, 0xd2, 0x15, 0xe9, 0x66, 0xc7, 0xe5, 0xf2, 0xa4, 0xa, 0x15, 0x8, 0xc6, 0x9, 0xd3, 0xfe, 0x7d, 0xdc, 0xb2, 0x9d, 0x28, 0xb4, 0xbd, 0xff, 0xdd, 0x89, 0x3, 0x4, 0x90}}
+	return a, nil
+}
+
+var __1528395651_add_campaign_name_historyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x91\x4d\x6e\x83\x30\x10\x85\xf7\x3e\xc5\x2c\x41\xea\x0d\xb2\x72\x60\x52\x59\x05\x53\x19\x47\x4a\x56\xc8\x0d\x16\xb1\xd4\x40\x64\x5c\xf5\xe7\xf4\x1d\x13\xb5\xb0\x68\x24\xba\x1c\xcf\xf3\xf7\xde\xcc\x6c\xf1\x51\xc8\x0d\x63\x99\x42\xae\x11\x34\xdf\x16\x08\x62\x07\xb2\xd2\x80\x07\x51\xeb\x1a\x4e\xe6\x72\x35\xae\xeb\x9b\xde\x5c\x6c\x73\x76\x63\x18\xfc\x27\x24\x0c\xc0\xb5\xf0\xe2\xba\xd1\x7a\x67\x5e\xe1\x59\x89\x92\xab\x23\x3c\xe1\xf1\x81\x7a\xbf\xbf\x6e\x22\xd7\x87\x89\x29\xf7\x45\x01\x0a\x77\xa8\x50\x66\x38\xc3\xc7\xc4\xb5\x29\x54\x12\x72\x2c\x90\x82\x64\xbc\xce\x78\x8e\x54\x92\x54\xc5\x54\x11\x1a\x13\x40\xb0\x1f\x33\xeb\xe7\x75\xbc\x9a\x93\x6d\xde\x28\x4b\x34\x24\x37\xdb\x59\xbf\x34\x8a\xad\xf5\x26\x37\xdc\xe0\xbb\x3b\x34\xea\xac\x83\x79\x1b\x71\x6d\x63\x02\x04\x47\xdc\x40\xf3\x86\xaf\x79\x15\xa4\xe6\xfb\x42\x43\x3f\xbc\x27\x29\x4b\xe7\x4b\x08\x99\xe3\x61\xcd\x25\x9a\xe5\xa6\x29\xce\x9f\xa2\x64\x21\x22\x93\x7f\x7b\x4c\x8b\xbf\x0b\x8f\xc5\x14\xbd\x2a\x4b\xa1\x37\xec\x1b\x7a\x56\x0d\x93\x55\x02\x00\x00")
+
+func _1528395651_add_campaign_name_historyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395651_add_campaign_name_historyUpSql,
+		"1528395651_add_campaign_name_history.up.sql",
+	)
+}
+
+func _1528395651_add_campaign_name_historyUpSql() (*asset, error) {
+	bytes, err := _1528395651_add_campaign_name_historyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395651_add_campaign_name_history.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1e, 0x7b, 0xe5, 0xd4, 0x56, 0x48, 0x9c, 0xd9, 0x61, 0x4, 0xc7, 0x6f, 0xc2, 0x6e, 0x27, 0xf1, 0xef, 0x58, 0x17, 0x73, 0xad, 0xa7, 0x48, 0x20, 0x30, 0x9c, 0x6, 0x74, 0xfc, 0x6e, 0x26, 0xac}}
+	return a, nil
+}
+
+var __1528395652_add_changeset_close_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x89\x4f\xce\xc9\x2f\x4e\x8d\xcf\xca\x4f\x2a\x06\xaa\x75\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x31\x57\x3e\xb0\x3c\x00\x00\x00")
+
+func _1528395652_add_changeset_close_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395652_add_changeset_close_jobsDownSql,
+		"1528395652_add_changeset_close_jobs.down.sql",
+	)
+}
+
+func _1528395652_add_changeset_close_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395652_add_changeset_close_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395652_add_changeset_close_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5f, 0xb9, 0x0, 0xe0, 0x8b, 0x97, 0x37, 0x1f, 0x3e, 0x41, 0x10, 0x49, 0xf7, 0xbc, 0x55, 0xe7, 0xca, 0x6d, 0x9f, 0xf7, 0x97, 0x64, 0x28, 0x4a, 0x5d, 0x2, 0xe0, 0xd0, 0xdd, 0x3a, 0x63, 0x65}}
+	return a, nil
+}
+
+var __1528395652_add_changeset_close_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x51\xcb\x4e\xc3\x30\x10\xbc\xfb\x2b\xf6\x98\x48\xfd\x83\x9e\xdc\x74\x8b\x2c\x12\x07\x1c\x47\xa2\xa7\xc8\x4d\x4c\x6a\xd4\x38\x91\x6d\x04\xe2\xeb\xb1\x8b\x28\x20\x90\x80\xe3\x68\x1e\x3b\x9a\xdd\xe0\x15\xe3\x6b\x42\x0a\x81\x54\x22\x48\xba\x29\x11\xd8\x0e\x78\x2d\x01\xef\x58\x23\x1b\xe8\x8f\xca\x8e\xda\xeb\xd0\xf5\xa7\xd9\xeb\xee\x61\x3e\x78\xc8\x08\x80\x19\xe0\x60\x46\xaf\x9d\x51\x27\xb8\x11\xac\xa2\x62\x0f\xd7\xb8\x5f\x45\xae\x57\xd3\xa2\xcc\x68\xbb\x37\x91\xb1\xe1\x1c\xc9\xdb\xb2\x04\x81\x3b\x14\xc8\x0b\x6c\x2e\x32\x9f\x99\x21\x87\x9a\xc3\x16\x4b\x8c\x3d\x0a\xda\x14\x74\x8b\x11\x46\xa9\x48\xa5\xce\xa1\x97\x26\xbf\xa4\xbe\xeb\xfe\x16\xab\x9d\x9b\x1d\x04\xfd\x1c\x12\xf2\x41\xb9\xa0\x87\x4e\x05\x08\x66\xd2\x11\x4e\x4b\x78\x49\xcc\xbd\xb1\xc6\x1f\x7f\xa4\x7a\xa7\xd5\x77\xd3\x47\xb9\x78\x90\xb6\xa5\x04\x3b\x3f\x65\x79\x32\x3c\x2e\xc3\xff\x0c\x2d\x67\xb7\x2d\x42\xf6\x69\xd8\xd5\x97\x41\x72\x92\xa7\x3f\xd6\x55\xc5\xe4\x9a\xbc\x02\x27\xe2\xd3\x78\xd8\x01\x00\x00")
+
+func _1528395652_add_changeset_close_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395652_add_changeset_close_jobsUpSql,
+		"1528395652_add_changeset_close_jobs.up.sql",
+	)
+}
+
+func _1528395652_add_changeset_close_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395652_add_changeset_close_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395652_add_changeset_close_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0x8e, 0x85, 0x8b, 0xd5, 0xcf, 0xaa, 0x79, 0xda, 0xe4, 0xc3, 0xa7, 0x28, 0x2e, 0xdf, 0xfa, 0x48, 0x15, 0x96, 0xef, 0x21, 0xfa, 0x78, 0x8, 0xa1, 0x50, 0x2, 0xc, 0x7f, 0xa4, 0x1, 0x2}}
+	return a, nil
+}
+
+var __1528395653_add_changesets_campaign_ids_gin_idxDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x29\x8e\x4f\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\x4c\x29\x8e\x4f\xcf\x04\xd1\x15\x40\x6d\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x00\xba\x29\x05\xad\x47\x00\x00\x00")
+
+func _1528395653_add_changesets_campaign_ids_gin_idxDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395653_add_changesets_campaign_ids_gin_idxDownSql,
+		"1528395653_add_changesets_campaign_ids_gin_idx.down.sql",
+	)
+}
+
+func _1528395653_add_changesets_campaign_ids_gin_idxDownSql() (*asset, error) {
+	bytes, err := _1528395653_add_changesets_campaign_ids_gin_idxDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395653_add_changesets_campaign_ids_gin_idx.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe0, 0xec, 0xb4, 0xdf, 0x9d, 0x1c, 0xbc, 0x87, 0xd, 0x48, 0x58, 0xe, 0x1f, 0x33, 0x27, 0xe1, 0x73, 0x96, 0x16, 0xa7, 0x31, 0x41, 0x1e, 0x3d, 0x32, 0xe4, 0x32, 0xe2, 0xf0, 0xe1, 0x2a, 0x2e}}
+	return a, nil
+}
+
+var __1528395653_add_changesets_campaign_ids_gin_idxUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x29\x8e\x4f\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\x4c\x29\x8e\x4f\xcf\x04\xd1\x15\x0a\xfe\x7e\x48\x4a\x14\x42\x83\x3d\xfd\xdc\x15\x80\x72\x0a\x1a\xc8\xaa\x35\x41\x76\xf8\xfb\xfa\x7a\x86\x58\x73\x01\x00\x3b\xfb\x5e\x3e\x74\x00\x00\x00")
+
+func _1528395653_add_changesets_campaign_ids_gin_idxUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395653_add_changesets_campaign_ids_gin_idxUpSql,
+		"1528395653_add_changesets_campaign_ids_gin_idx.up.sql",
+	)
+}
+
+func _1528395653_add_changesets_campaign_ids_gin_idxUpSql() (*asset, error) {
+	bytes, err := _1528395653_add_changesets_campaign_ids_gin_idxUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395653_add_changesets_campaign_ids_gin_idx.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd7, 0x5c, 0x9d, 0xbc, 0x78, 0x2f, 0x5, 0x7a, 0x1c, 0xa2, 0x20, 0xb9, 0x5b, 0xc8, 0x37, 0x7b, 0xda, 0x56, 0xc8, 0xe7, 0x63, 0x80, 0xc3, 0xd8, 0xef, 0xee, 0xee, 0x7f, 0x71, 0x6, 0x73, 0x66}}
+	return a, nil
+}
+
+var __1528395654_partition_event_logs_by_monthDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x53\x51\x6f\x9b\x30\x10\x7e\xe7\x57\x9c\xaa\x48\x49\xa6\xb5\xd2\x9e\xa3\x3d\x10\xb8\xb4\x56\x89\xe9\x8c\x51\x9b\xbd\x20\x0f\xdc\xc4\x0a\x01\x86\xc9\xba\xfc\xfb\x1e\x09\x6d\x68\xba\x2e\x9b\x5f\xc0\xf7\xf9\xfb\xee\x7c\xf7\x79\x8a\xd7\x8c\x4f\x1c\xe7\xf2\x12\xbc\xb2\x32\xda\x82\xca\x73\xd0\xbf\x74\xd1\x58\x30\x45\x53\x82\x82\x5a\x2f\xb7\xb9\xaa\xa1\x51\x3f\x72\x0d\x6a\xa9\x4c\xf1\x19\x9e\x56\x26\x5d\x51\x68\xdd\x52\xda\x5d\x0b\x15\x19\x14\x5a\x67\xb6\x95\xcb\x8c\x5d\x83\xad\x54\xaa\xe1\xb1\xac\xe9\x8c\xd5\x69\x49\x07\xd2\xb2\xda\x41\xf9\x08\xcd\x4a\xbf\xe4\x29\x0b\x4a\x65\x1b\x55\xa4\x24\xf6\x64\x9a\x15\x6c\x54\xb1\xeb\xd0\x2b\xc7\x0f\x61\x30\x70\xa6\x6d\xa5\x0e\xd0\x62\x33\xe0\xa1\x04\x7c\x60\x91\x8c\x60\x14\x61\x80\x9e\x84\x2f\x30\x13\xe1\x1c\xaa\x65\x92\xe6\xca\x5a\xb8\xbf\x41\x81\x54\x7b\x5e\xa8\x8d\x86\xaf\x30\xdc\xcb\x25\x79\xb9\xb4\x43\x70\xb9\xdf\x42\x6b\x43\x05\x11\x54\x0d\xc7\x20\x6f\xf0\x20\xdf\x2e\x81\x32\x16\xd4\x97\xf6\x1f\xe9\x2c\x9b\x51\x8f\xda\x8d\x1b\x48\x14\x10\xe1\xb7\x18\xb9\x87\x70\xd4\x4c\x4c\x96\x58\xfd\x13\xc2\x7b\x8e\x3e\x4c\x17\x54\x22\xc7\x49\x8f\x23\xdd\x69\xd0\x27\x50\x0e\xee\xce\x11\x64\xd8\x57\xa9\x54\xdd\x98\xc6\x94\x85\xce\xba\x8c\x9e\x40\x57\xe2\x7b\xfa\x28\x60\xb7\xf8\x01\x15\x18\xf7\x82\xd8\x67\xfc\x1a\x7c\x9c\xb9\x71\x40\x7d\x3a\x86\xbc\x90\x47\x52\xb8\x8c\xcb\x68\x3c\xf9\xcf\x5b\x1d\xa1\x2b\xf3\x52\x21\xe3\x11\x0a\x49\x9f\x37\x57\x81\x6e\x2e\x9f\x0e\x73\xf9\xeb\x1d\xc9\x2e\x7e\x5d\x56\x95\x29\x96\x7b\x5f\xf4\xaf\x72\x70\x5d\x46\x30\x19\x92\xcc\xf2\x8a\xd9\xbd\xdf\x68\x82\xfa\xb7\x26\x97\xb4\x3a\xbe\x08\xef\xde\x75\xea\x0f\xf9\x3e\x98\x88\xeb\xfb\xbd\xe6\xbc\x91\x58\xeb\x1d\xdc\x09\x36\x77\xc5\x02\x6e\x71\x01\x23\x93\x75\xbd\xeb\xe6\xc3\xb8\x8f\x0f\x7d\xca\xde\x76\x21\xef\x85\x46\x6d\xe8\x0c\xcb\x96\xdb\x3a\x3d\xe5\x1d\x82\x67\x98\x8d\xd9\x68\x7a\x43\x9b\xea\x84\x7c\xf1\x0a\x5c\xfc\xab\x42\xa2\x9a\x64\xdb\xa4\x27\x42\x3e\x91\x46\xc7\x2c\xae\x04\xc9\xc8\xbf\xdf\xc9\xe7\x30\x8c\xa5\x37\x1c\x9f\xd1\xdf\x5a\x5d\x93\xa1\x4e\x64\xbb\x28\x71\xe9\xa1\x39\x83\x01\x8d\xc8\x0b\xe7\x73\x26\x27\xce\x33\xb5\x66\xed\xa3\x9d\x04\x00\x00")
+
+func _1528395654_partition_event_logs_by_monthDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395654_partition_event_logs_by_monthDownSql,
+		"1528395654_partition_event_logs_by_month.down.sql",
+	)
+}
+
+func _1528395654_partition_event_logs_by_monthDownSql() (*asset, error) {
+	bytes, err := _1528395654_partition_event_logs_by_monthDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395654_partition_event_logs_by_month.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x47, 0x3b, 0xb6, 0x46, 0x75, 0x8b, 0xbd, 0x87, 0xb, 0x8c, 0x14, 0x88, 0x20, 0xe5, 0xe4, 0xd6, 0x24, 0x38, 0x1c, 0x84, 0x97, 0x9f, 0x59, 0x24, 0x55, 0x73, 0x89, 0xeb, 0xf6, 0x4e, 0xe9, 0x4c}}
+	return a, nil
+}
+
+var __1528395654_partition_event_logs_by_monthUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x58\x6d\x93\xd2\x48\x10\xfe\xbe\xbf\xa2\xcb\xd2\x0a\x28\xa2\xde\xc7\x5d\xb5\x2a\x42\xd6\xa5\x84\xb0\x07\xd9\x53\xef\x0b\x35\x24\x03\xe4\x36\x24\x98\x99\xc8\x62\xf9\xe3\xaf\x7b\x66\x12\x26\x4b\x40\x94\xaa\xad\x85\x4c\xf7\x33\xfd\xf2\x74\x4f\x4f\x3e\x78\x1f\x07\xfe\xd5\xc5\xc5\xcb\x97\x70\xcb\x72\x19\xcb\x38\x4b\x05\xf0\xef\x3c\x95\xb3\x24\x5b\x0a\x98\xef\x60\x9d\xa5\x72\x05\x22\x03\xb9\x62\x12\x0a\xc1\x96\x1c\x84\x64\x32\x16\x32\x0e\x05\x7c\x2b\x78\x1e\x73\x01\xd9\x77\x9e\x03\x23\xa4\x79\x56\xa4\x11\x8f\x40\xc6\x6b\x94\xdc\xb0\x14\xb2\x34\xd9\x81\x08\xf1\x9b\x5c\x71\xd8\xec\x77\xca\x16\x1a\x95\xa4\x3a\xc0\xd2\xa8\xda\x87\x3f\x6c\xe2\x9c\x47\x84\xa7\xcc\x11\x40\xea\x73\x0e\x39\x5f\xe3\x56\x11\x59\x16\xe5\xd9\x66\x13\xa7\x4b\xd8\xae\xb2\xc4\xc6\xed\xa2\x1a\x69\xba\xb0\xc9\xe3\x35\xcb\x77\x70\xcf\x77\x68\x05\xb0\xbd\x10\x19\xc8\xe6\x09\x01\x7e\x2b\x70\x2b\x01\xb7\x99\x90\x4b\xfa\xf2\xe6\x4d\x17\xc6\x68\x75\x12\xa1\x4b\xe8\x96\x20\xcc\xca\x12\x1d\x18\x8c\xc0\x4e\x20\x5e\xce\x97\x45\xc2\x72\x8d\xd5\x85\xa0\xee\xdf\x22\xcb\xa1\xd8\x84\xd9\x9a\xac\x54\x81\x44\x9d\x9c\x13\x56\x98\x73\x26\xb5\x1f\x1e\xc1\x0e\x11\xb5\x3b\x62\x71\x2a\xf1\xcf\x4a\x46\x9c\x42\xb8\x8e\x5e\x2d\x72\xd4\xe6\x69\xf4\x2a\x9a\x77\xd0\xdf\x38\x5c\x41\x94\xa1\xad\x69\x26\x57\x08\x4e\x88\xf1\xc2\x36\x30\x56\x6b\xb6\xbf\x65\x54\xc8\x46\xfe\x40\xe9\x43\xa3\x4c\x70\xd1\x28\x25\x1e\x66\x9b\x18\x25\xeb\x32\x3a\x4e\x73\x8e\x7e\xe0\x8e\xb5\x14\xd6\xa3\x32\x4b\xf8\x92\x85\x3b\xe5\x36\x89\x59\x0c\x58\x40\x2c\x0d\xb3\x84\x71\x80\x74\x4f\xba\x4e\xf9\x45\x8e\xa4\x21\x27\xb4\x1d\xac\xd8\x77\x0e\x2c\x49\x4a\x72\x74\xc1\x95\x92\x85\xe4\x3f\xa2\x13\x1c\xc6\x34\x42\xd7\xa5\x51\xca\xe0\x3b\x4b\xe2\x08\xe3\xac\xcc\x51\xc4\x14\x8a\x66\xf3\x22\x4e\x22\xed\x4b\x8c\x64\x7d\xd0\x4c\xc4\x18\xf0\x6d\xc9\x19\xc2\x33\xb4\x89\x65\x07\xa2\x22\xd7\x54\xa3\xd0\xd7\xe3\x9c\x64\xe1\xbd\x0e\x1a\x12\x57\xb2\x7b\x0c\x12\xc3\xbd\x24\x30\x5a\x43\x25\xfc\xaf\x2a\x43\x20\xd5\x50\x31\x66\x89\xae\x06\xb3\xa9\x8a\x6f\x07\x0d\x11\x12\xed\xaf\x9e\x52\xf0\xd0\x05\x4c\xc9\x4e\xd9\xac\x0d\x55\xfe\xab\x30\xaa\x9c\xa7\x28\xa1\x8c\xae\xc2\xa6\xeb\x88\xc8\x91\x3a\x12\xd7\x90\x63\x51\x2c\xee\x29\x0f\x18\x13\x4a\x0d\x43\x43\xc2\x0c\x85\x08\xba\x7b\xd1\x1f\xc3\xd3\xa7\x17\x7d\xaf\x37\x74\x27\xde\x05\xe0\x47\xa7\x71\x86\x1c\xcf\xa5\xb2\x03\xbf\xad\x37\xf2\xc7\x95\xbd\x8a\x64\xb4\xd7\xe0\xf2\x1d\x32\x68\xdb\x6a\x6b\x21\xdd\x35\xaa\x75\xfd\xb0\x32\x12\x24\x7f\x90\x57\x17\x1f\xa8\xfd\xa8\x95\xc1\x35\x84\x45\x9e\x53\x54\x05\x97\xc4\xba\x96\x23\x78\x8e\xc5\x37\x33\x05\x38\x4b\x8b\xb5\xd3\xbe\xbc\x44\x8f\xf9\x12\xeb\xf2\x2d\x96\xe9\x6b\xfc\x40\x70\xe3\x69\x0c\xfa\x4c\xbc\xe0\x6e\xe2\xeb\xdd\x3c\xbf\x8f\xb8\xd8\xdd\xe8\x07\xc6\xea\x13\xe7\x1b\x15\x59\x9d\x06\x0c\xc6\x76\xc5\x75\x4b\xc2\x52\x37\x2c\xc7\x74\xa2\x15\x6c\x4d\x95\xc9\x93\x6c\xab\x4b\x81\x1e\x98\x6e\xc5\x4b\x38\x8c\xa0\x90\x39\xb1\x56\xec\xd3\x53\x49\x59\x90\xaa\xb6\x30\x0d\x08\xa9\x0b\x23\x13\xdc\xe2\x5b\x89\xa7\x3b\x88\xfa\xe5\x0e\x03\x6f\x02\x53\xef\xef\x3b\xcf\xef\x79\x76\x81\xc5\x11\xc6\xe7\x1b\x8c\x3f\xfb\x5e\x1f\x3e\x7c\x05\x7f\xec\x7b\x57\x96\x4e\xe0\x7e\x18\xda\x0a\x18\x10\xdf\x1d\x79\x10\x8c\x0f\xcb\xf4\x94\x5e\x59\xc9\x46\xbd\x37\xf6\xa7\xc1\xc4\x1d\xf8\x81\x2d\xb3\xa1\xf2\x68\x42\x56\x2b\x36\xfc\xc0\xef\x7b\x5f\x28\xc9\xde\x97\xc1\x34\x98\xda\x1a\x14\xda\x53\x56\x2a\x81\x33\xb1\x44\x56\xe4\xe1\x69\x34\x2d\x72\x26\x5e\x45\xdf\x93\x90\x8f\x48\x7e\x3e\xea\x8c\xc9\x59\x21\xc3\xf3\xc0\x8d\xf0\x99\x7b\x14\x58\x3c\x48\x96\x93\xd0\x46\xc6\x14\x48\x6f\xe2\xb9\x81\x77\x48\xa0\x56\x55\x5b\x08\x37\x8f\x97\xc8\x77\xe8\x7b\xd7\xee\xdd\x30\x40\xf6\x3e\x48\xec\xb0\x2d\xe7\x80\xa1\xce\xe5\x25\x9e\x8c\x61\xc2\x84\x68\x23\x49\x03\xf0\xef\x86\xc3\x4e\x05\xa5\x92\x4e\x3d\xa0\x61\xad\xc8\x93\xa3\x4b\xc6\xab\xb2\x07\x1c\x4a\xb0\x34\x4b\x77\xeb\xac\xd8\x47\xe0\x08\x94\xa1\xca\x91\x55\x96\x2f\x8b\x35\xba\x04\xff\x89\x2c\x9d\x37\x08\x98\xa6\x74\x4c\xff\x49\x95\xb6\x27\xfb\x1e\x08\xdb\xd8\xb4\x44\xf8\x81\xe7\x71\x83\x5a\x73\x99\x85\x2b\x1e\xde\xcf\x56\x4c\xfb\x04\xbd\x1b\xaf\xf7\x09\x5a\xf8\x29\x5d\x7c\x07\xaf\xdb\xe0\x62\xb7\x6b\x1d\xba\xff\xf6\x3d\x38\x98\x0c\xb2\xb3\xdd\x86\xf1\x04\xf6\x6a\xb8\x74\x5c\xef\xdd\x9f\xa9\xd5\xb6\x6b\xb7\xcf\x73\x8d\xd8\x30\xc3\xe9\x63\xc6\xf1\x20\xd9\x55\x0e\x2a\x92\xd4\x00\xcf\x83\xd3\xa9\x6d\x00\x34\x39\xff\x03\x48\xe4\x64\x03\x1e\x31\xf5\x0f\xc0\xaa\x03\xed\x00\xb0\x64\x55\x0d\x54\x61\xb6\xe1\xd6\x9d\x04\x83\x60\x30\xf6\xa9\xed\x4f\x5c\xff\xa3\x07\x2d\x8b\x66\x6d\x53\xc6\xe7\x1f\x1d\xfb\xa5\x6e\xd5\x04\xf0\x18\xea\x31\x89\x66\x96\xa3\x1a\xe0\x18\x23\xe2\xa8\x3a\xaf\x70\xda\x44\x0a\xda\xc3\x06\xef\x2e\xbb\x80\xd3\xe9\x1a\xc2\x24\x56\x2a\x8a\xe6\xac\xc4\xdb\xe6\x34\x01\x85\x34\x23\x75\x4f\x35\x9a\x59\xc4\x17\xac\x48\xa4\xe5\xe8\xf8\xda\x6e\x44\xa6\xe9\x18\x4b\xa7\xde\xd0\xeb\x05\x30\x1a\xf8\xb5\x28\x60\x4f\xc4\x5e\x57\x9b\x5f\xae\x27\xe3\xd1\xb1\xc3\x0f\x7b\x67\x4d\x76\x30\x55\x15\x59\x9f\x29\xfa\x93\xf1\xed\xb1\x03\xf2\xaa\x92\xb2\xa6\x22\x9c\x84\x08\xa5\x79\x0a\xe9\xa9\xb9\xdf\xbe\x89\x40\x4b\xcf\x1b\x16\xf8\xee\x2b\x7e\xd6\xa3\x51\x5b\x4d\x0c\xb8\x90\x97\xf7\x30\x15\x69\x9c\xb3\xf0\x5a\x51\x0d\x0e\x78\x65\xda\x66\xe5\xf5\xa2\x14\x78\x74\x17\xd1\x09\xc4\xe9\xc6\x5c\xe7\xc8\x5d\x41\x03\x50\x5e\x0d\x34\x87\xa7\x3f\x3a\x23\x74\xd2\xae\xb1\x05\x68\xcd\xc1\x3e\x30\x26\x07\x4b\x9e\xf2\x1c\x7d\x42\x7a\xd1\x4d\x70\x7f\x60\xd0\x87\x86\xef\x99\xcc\x8b\x34\x6c\x39\x0a\xc0\xe9\xe8\x31\x11\xdc\x00\x82\x01\x9e\x4d\xff\xe2\x08\x03\xce\x5d\xd0\x73\xac\xfa\xf9\x6d\x55\x78\xa1\x4e\x85\x1c\x8f\x23\x70\xfe\x32\xc1\x70\xea\x80\x7b\x81\x37\x5a\xc0\xa9\x96\x75\x95\x0d\xc7\xe3\xdb\xea\xd1\x3e\x74\x98\x4f\xfb\x84\xdb\x39\xf0\xf3\x27\x46\x1d\xcb\x99\xe5\x2d\x05\xd4\x01\x87\x32\xf6\x64\xfd\x64\x34\x72\xda\x7b\x52\x78\x5f\xbc\xde\x1d\x92\x1d\xb3\xb8\x66\xb2\x1e\x19\xa7\x56\x08\xcf\x06\x47\x89\x4f\xb1\xff\xc7\x1d\xde\x79\x53\xcd\xe5\xd6\xb3\x61\x9b\x4e\x74\xfa\xff\xc8\xc3\xca\xe6\xfa\xe3\x8f\x6a\xa7\x69\xa0\x8d\x6d\x08\x5f\xc7\xa2\xef\xa3\x2c\x18\x9d\x17\x0d\xd1\x6b\x4a\xc4\x3e\xa2\x7b\xfa\x53\x58\x4d\x01\x34\x15\x9c\x39\x06\xeb\x45\x57\xde\x0d\x0d\xa1\xa9\xf7\x2c\x0a\x59\xe0\x28\xdd\x3a\xd6\x71\xec\x56\xd3\xc6\x0b\x10\x5d\x7f\x16\x78\x2f\xb4\x20\xd5\x75\xc9\xba\x0a\x66\x8b\x43\xd6\xab\xc2\xc9\xf5\xd8\xbe\xe0\xdb\x8e\x7e\x25\xc1\x77\x0e\xfe\x56\xaf\x1e\xba\x15\xe2\xe7\x41\x70\xa3\x9f\x81\x3b\x85\x7a\x7a\xfb\x58\x1c\x98\xdd\xe6\xee\x03\x9f\x6f\xbc\x89\x57\x1b\x13\xde\xbf\xb3\x5b\x88\xbe\xc7\x0c\xfc\x8f\xf0\xfc\x11\x47\x55\x14\xfd\xa9\x37\x09\x74\xaf\xb3\x88\x62\xea\xf1\xb9\xde\x54\xd9\x65\xc2\xfe\x4b\x2e\x1e\xb9\x3d\xb8\x41\xe0\xf6\x6e\x2c\x66\x1e\x7a\xf2\x1b\xec\xb4\x33\xdf\xb8\x82\x9e\x37\xf1\xc7\x6e\x9f\xf5\xa6\x76\xaf\xde\x0a\x08\x6a\x81\x73\xfd\xbc\x3c\xa8\xac\x17\x3f\x8f\xde\x66\x98\x7b\x5a\x09\xd8\x44\x01\x95\x7b\xa6\xde\x2d\xd0\x6b\xa2\xac\x7a\x35\x80\x63\x9b\xb0\xef\xe9\x73\x4e\x88\x39\xa7\xd7\x09\xb2\x7b\xea\x1e\xe6\xf6\xfb\xa7\x6e\x51\xb7\x93\xc1\xc8\x9d\x7c\x85\x4f\xde\x57\x68\xc5\x51\x07\xea\x47\xbb\x75\x6e\xea\xa1\xff\xf1\x05\xaa\x96\x1a\x75\x9e\xfc\x42\xcb\xcc\x42\x75\x3d\xfd\xf0\x17\x9a\xfb\x79\xb6\xae\x7c\xbe\xc5\x07\x17\xa0\x3a\x50\x1f\x95\x5a\xfb\x5d\x1a\x9a\xfd\x2f\xf0\xcb\x61\xb4\x0e\x6b\x9e\xa2\x2e\x72\xea\xe2\xe9\x53\xe4\x54\x6f\x3c\x1a\x0d\x70\xa2\xf8\x1f\xd1\xda\x88\x86\x07\x15\x00\x00")
+
+func _1528395654_partition_event_logs_by_monthUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395654_partition_event_logs_by_monthUpSql,
+		"1528395654_partition_event_logs_by_month.up.sql",
+	)
+}
+
+func _1528395654_partition_event_logs_by_monthUpSql() (*asset, error) {
+	bytes, err := _1528395654_partition_event_logs_by_monthUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395654_partition_event_logs_by_month.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa4, 0x33, 0xa8, 0x8a, 0xa2, 0xcc, 0x54, 0xa, 0xd9, 0x1b, 0x8e, 0x76, 0x91, 0xd2, 0x54, 0x1f, 0x6e, 0x52, 0x79, 0x98, 0x5b, 0x21, 0xdb, 0x10, 0x20, 0xac, 0x63, 0x64, 0x84, 0x2b, 0x99, 0xc8}}
+	return a, nil
+}
+
+var __1528395655_add_changesets_diff_statDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xc9\x4c\x4b\x8b\x2f\x2e\x49\x2c\x89\x4f\x4c\x49\x49\x4d\xb1\x26\x57\x37\x44\x29\xf9\xfa\x53\x52\x73\x52\x4b\x40\xfa\xb9\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x06\x9b\xc1\xe8\xcf\x00\x00\x00")
+
+func _1528395655_add_changesets_diff_statDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395655_add_changesets_diff_statDownSql,
+		"1528395655_add_changesets_diff_stat.down.sql",
+	)
+}
+
+func _1528395655_add_changesets_diff_statDownSql() (*asset, error) {
+	bytes, err := _1528395655_add_changesets_diff_statDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395655_add_changesets_diff_stat.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2e, 0x8e, 0xe7, 0xc, 0x6a, 0x4c, 0x73, 0x4c, 0x33, 0x12, 0x37, 0xf8, 0x11, 0xd5, 0x6b, 0x4d, 0xcc, 0x5f, 0xe5, 0x9c, 0x94, 0xb9, 0xd5, 0x7b, 0xf3, 0x8f, 0x92, 0x80, 0x75, 0x17, 0x2f, 0xfc}}
+	return a, nil
+}
+
+var __1528395655_add_changesets_diff_statUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x29\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xc9\x4c\x4b\x8b\x2f\x2e\x49\x2c\x89\x4f\x4c\x49\x49\x4d\x51\xc8\xcc\x2b\x49\x4d\x4f\x2d\xb2\xa6\xc0\x20\x88\x6a\xaa\x18\x95\x92\x9a\x93\x5a\x82\x6c\x14\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x00\x50\xfd\x86\x42\xf0\x00\x00\x00")
+
+func _1528395655_add_changesets_diff_statUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395655_add_changesets_diff_statUpSql,
+		"1528395655_add_changesets_diff_stat.up.sql",
+	)
+}
+
+func _1528395655_add_changesets_diff_statUpSql() (*asset, error) {
+	bytes, err := _1528395655_add_changesets_diff_statUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395655_add_changesets_diff_stat.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa0, 0xef, 0x1b, 0x3c, 0xe1, 0xc7, 0xfd, 0x2a, 0x19, 0x81, 0xc0, 0x23, 0x60, 0xd9, 0x71, 0xa8, 0x41, 0xe1, 0x83, 0x60, 0x46, 0x8b, 0x56, 0x24, 0x18, 0x5a, 0xc1, 0x38, 0x6e, 0xa0, 0x15, 0x46}}
+	return a, nil
+}
+
+var __1528395656_add_campaign_idempotency_keysDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\x4c\x49\xcd\x2d\xc8\x2f\x49\xcd\x4b\xae\x8c\xcf\x4e\xad\x2c\x06\x6a\x70\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x76\x2d\x70\x73\x41\x00\x00\x00")
+
+func _1528395656_add_campaign_idempotency_keysDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395656_add_campaign_idempotency_keysDownSql,
+		"1528395656_add_campaign_idempotency_keys.down.sql",
+	)
+}
+
+func _1528395656_add_campaign_idempotency_keysDownSql() (*asset, error) {
+	bytes, err := _1528395656_add_campaign_idempotency_keysDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395656_add_campaign_idempotency_keys.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3, 0xb4, 0x72, 0xb9, 0x88, 0x8f, 0x20, 0x79, 0x48, 0x0, 0xdd, 0x99, 0xd6, 0xfa, 0x59, 0xc8, 0xf6, 0xcd, 0xd7, 0xb0, 0xfc, 0x3a, 0xc4, 0xc5, 0x90, 0x81, 0xa2, 0x91, 0xd2, 0x2, 0xe9, 0xf1}}
+	return a, nil
+}
+
+var __1528395656_add_campaign_idempotency_keysUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x51\x5f\x6b\xc2\x30\x10\x7f\xef\xa7\xb8\xb7\xb6\xe0\x37\x70\x1b\xd4\x7a\x6e\xc1\x9a\x8e\x34\x82\x3e\x95\xae\xde\x34\x73\x4d\xa5\x89\xb8\xee\xd3\x2f\x29\xc3\x6e\x0c\x61\x2f\x81\xcb\xfd\xee\xf7\xe7\x6e\x86\x8f\x8c\x4f\x83\x20\x15\x98\x48\x04\x99\xcc\x32\x04\xb6\x00\x9e\x4b\xc0\x0d\x2b\x64\x01\x75\xd5\x9c\x2a\xb5\xd7\xa5\xda\x51\x73\x6a\x2d\xe9\xba\x2f\x8f\xd4\x1b\x88\x02\x80\xb3\xa1\xce\x75\x40\x69\x4b\x7b\xea\x86\x41\xbe\xce\x32\x10\xb8\x40\x81\x3c\xc5\x62\xc0\x98\x48\xed\x62\xc8\x39\xcc\x31\x43\xa7\x94\x26\x45\x9a\xcc\xd1\x95\x0e\x26\xbc\xec\xc4\xb1\x35\x67\x5b\x59\xd5\x6a\xb0\xf4\x61\xaf\x5c\xbe\xe3\x04\xff\x7e\x76\x64\xce\xef\xd6\xc9\x1b\x78\x33\xad\x7e\xf1\x7f\x75\x47\x95\xa5\x5d\x59\x59\xb0\xaa\x21\x63\x9d\x7d\xb8\x28\x7b\x18\x4a\xf8\x6c\x35\x8d\x26\x9d\x7a\xb2\xce\x24\xe8\xf6\x12\xc5\x7e\xfa\x59\xb0\x55\x22\xb6\xb0\xc4\x2d\x44\xdf\xd9\x26\x57\x5b\x13\x6f\x63\xc0\xa5\x39\x2f\xa4\x48\x18\x97\xb7\xf7\xe3\x9f\xb2\x3e\x50\x7d\x84\xf4\x09\xd3\x25\x44\x3e\xc4\xdd\x03\x84\xe1\xff\x39\xc6\x88\xbf\xa9\x86\xbc\xa5\xed\x4f\xd4\xbe\x46\x23\x28\x86\x7b\x08\xab\xae\xab\xfa\x30\x0e\xe2\xf1\xb0\x8c\xcf\x71\xf3\xdf\xc3\x96\x3f\x76\xe8\x2e\x76\x13\x17\x8d\xb8\x41\x2a\x5f\xad\x98\x9c\x06\x5f\x73\x07\x0b\xe1\x54\x02\x00\x00")
+
+func _1528395656_add_campaign_idempotency_keysUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395656_add_campaign_idempotency_keysUpSql,
+		"1528395656_add_campaign_idempotency_keys.up.sql",
+	)
+}
+
+func _1528395656_add_campaign_idempotency_keysUpSql() (*asset, error) {
+	bytes, err := _1528395656_add_campaign_idempotency_keysUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395656_add_campaign_idempotency_keys.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcb, 0xb9, 0x53, 0x70, 0xc8, 0xdd, 0xf6, 0xc9, 0x9d, 0xe, 0xe1, 0x1f, 0x91, 0x24, 0xf8, 0x88, 0xd5, 0x61, 0x80, 0x2f, 0xa0, 0xc3, 0x68, 0x12, 0x0, 0x50, 0x1, 0x14, 0x3, 0x6, 0xa9, 0xbf}}
+	return a, nil
+}
+
+var __1528395657_add_campaign_worker_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\x2f\xcf\x2f\xca\x4e\x2d\x8a\xcf\xca\x4f\x2a\x06\xaa\x75\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x1d\x03\xaf\xc1\x3c\x00\x00\x00")
+
+func _1528395657_add_campaign_worker_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395657_add_campaign_worker_jobsDownSql,
+		"1528395657_add_campaign_worker_jobs.down.sql",
+	)
+}
+
+func _1528395657_add_campaign_worker_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395657_add_campaign_worker_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395657_add_campaign_worker_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x28, 0xbd, 0x61, 0x9, 0x56, 0xf7, 0xaf, 0x95, 0xee, 0xb0, 0x3d, 0x2f, 0x83, 0x73, 0xa6, 0x5a, 0xb9, 0xe, 0x74, 0x78, 0x52, 0x66, 0x7f, 0xe8, 0x7, 0xd, 0x8, 0x4c, 0xb2, 0x18, 0xe1, 0xdf}}
+	return a, nil
+}
+
+var __1528395657_add_campaign_worker_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x94\x5d\x6f\x9b\x30\x14\x86\xef\xf9\x15\xe7\x0e\x22\xa5\x53\x6f\x76\xd3\x6e\x95\x08\x38\x2d\x2a\x81\x0c\x88\xd6\x5c\x21\x03\x27\xc1\x69\xf8\x98\x71\x96\x66\xd3\xfe\xfb\x6c\x48\x9a\xb2\x7e\x24\xd2\x2e\x8d\x1f\xbf\xc7\xc6\x8f\xcf\x88\xdc\x3a\xde\xb5\xa6\x59\x01\x31\x23\x02\x91\x39\x72\x09\x38\x63\xf0\xfc\x08\xc8\x83\x13\x46\x21\xa4\xb4\xa8\x29\x5b\x96\xf1\xb6\xe2\x8f\xc8\xe3\x55\x95\x34\x60\x68\x00\x2c\x83\x84\x2d\x1b\xe4\x8c\xae\x61\x1a\x38\x13\x33\x98\xc3\x3d\x99\x0f\xe5\xdc\x8f\x0d\x6e\x10\x04\x3e\x89\x36\xca\x9b\xb9\xae\xfa\x5c\xd3\xdd\xba\xa2\x19\xac\x9a\xaa\x4c\x9e\x67\xc0\x26\x63\x73\xe6\x46\xa0\xff\xfe\xa3\x5f\x5d\xb5\x93\x8a\x6e\x04\x15\xff\x84\x1c\xd1\x6f\x33\x32\x23\xb6\xae\x38\x2a\x04\x16\xb5\x68\x80\x95\x02\x97\xc8\x5f\xd3\x97\x0a\x2b\xe8\x53\x7c\x1a\xfd\xac\x50\xe4\xbc\xe2\x6d\x65\x35\xe2\x9b\x32\xa6\x0b\x21\x69\xc1\x0a\x94\xbb\x2a\x6a\xd8\x32\x91\xb7\x43\xf8\x55\x95\xf8\x3a\xa6\xac\xb6\xc6\x40\x2d\xce\x91\x72\x91\x20\x15\xb2\xf6\xbb\xeb\xf7\xa7\xe5\x02\xb3\x53\xd8\x82\x95\xac\xc9\x4f\x73\x29\x97\x35\x3f\xc6\x3e\xd8\xf5\xa6\xce\xfe\x63\xb5\xe5\x7b\x61\x14\x98\x8e\x17\xbd\x69\x4f\xdc\xda\x11\xa7\x39\xa6\x8f\x60\xdd\x11\xeb\x1e\x8c\x4e\x98\x2f\x37\xa0\xeb\x67\x45\xec\x4d\xea\x87\xb4\xe6\xc4\x62\x57\x63\xb5\x30\xf6\xc4\x00\xbe\x82\x5e\x25\x2b\x4c\xc5\x79\xc9\xad\x75\xfd\xdc\x4e\x44\xc7\x03\xe3\x59\x3b\xd0\xa7\x81\x6f\x91\x30\x74\xbc\x5b\x35\xb2\xfc\xc9\xd4\x25\x51\x37\x65\x13\xd3\xd6\x07\x67\x55\x7b\x29\x65\xbf\x68\x4f\xd7\x1b\xb8\x1c\x68\x83\xe3\x43\x75\x3c\x9b\x3c\x9c\xf1\x50\xf7\xbf\xba\x3b\xd3\xd1\x63\xdf\x7b\x93\xee\x6e\x61\xd8\x3d\xbc\xe1\xd1\x7b\x55\xf8\xe2\x02\xac\x9c\x96\x4b\x6c\x50\x40\xdb\x03\x44\x2e\xed\xc8\xe9\x4f\x2c\x75\x01\x09\x62\x79\x50\x18\x76\x12\xa1\x1c\x55\x00\x24\x3b\x09\x22\x74\x55\xba\xbe\xa0\xb2\x16\xbc\x2a\x94\x30\x50\x95\x9f\x34\xc7\x0b\x49\x10\xc9\x43\x45\xfe\x3b\xed\x66\xbf\xb1\xc3\x9d\x6a\x21\x71\x89\x25\xbb\x40\x7a\xd8\x52\xcb\xc9\x7f\xdf\x29\x90\x6c\xd8\x3a\x8b\xbb\x5b\x37\xfa\x50\xcc\x32\x89\x31\x99\x31\x0e\xfc\x09\xf4\x03\xb4\xef\x77\x24\x20\x2f\x9f\xa2\x13\xb6\x8e\x6b\x7e\x60\x93\x00\x46\x73\xd5\xf5\xcc\xd0\x52\x37\xe1\x4f\x26\x4e\x74\xad\xfd\x05\x9b\x1e\xb7\x22\x43\x05\x00\x00")
+
+func _1528395657_add_campaign_worker_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395657_add_campaign_worker_jobsUpSql,
+		"1528395657_add_campaign_worker_jobs.up.sql",
+	)
+}
+
+func _1528395657_add_campaign_worker_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395657_add_campaign_worker_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395657_add_campaign_worker_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x38, 0x5a, 0x2c, 0x9, 0xd4, 0xc4, 0xde, 0xaf, 0x91, 0xb4, 0x51, 0xad, 0x72, 0x97, 0x88, 0x82, 0xa7, 0x64, 0xb8, 0x5, 0x3b, 0x1b, 0xee, 0xe8, 0x34, 0xd3, 0xf4, 0x57, 0x3d, 0x13, 0x49, 0x53}}
+	return a, nil
+}
+
+var __1528395658_add_aggregated_search_latenciesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4c\x4f\x2f\x4a\x4d\x4f\x2c\x49\x4d\x89\x2f\x4e\x4d\x2c\x4a\xce\x88\xcf\x01\x72\xf2\x92\x33\x53\x8b\x81\x5a\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\xed\xc0\x0f\xf9\x43\x00\x00\x00")
+
+func _1528395658_add_aggregated_search_latenciesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395658_add_aggregated_search_latenciesDownSql,
+		"1528395658_add_aggregated_search_latencies.down.sql",
+	)
+}
+
+func _1528395658_add_aggregated_search_latenciesDownSql() (*asset, error) {
+	bytes, err := _1528395658_add_aggregated_search_latenciesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395658_add_aggregated_search_latencies.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe9, 0x54, 0xe6, 0xc2, 0xcc, 0x13, 0x50, 0xae, 0xee, 0x22, 0xf5, 0xbe, 0x2e, 0x94, 0x82, 0x17, 0xa7, 0xc8, 0x57, 0x39, 0x9c, 0x1d, 0xa5, 0x8a, 0xde, 0xca, 0xe7, 0xf4, 0xfb, 0x9c, 0x3a, 0x1d}}
+	return a, nil
+}
+
+var __1528395658_add_aggregated_search_latenciesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x90\xbd\x0a\xc2\x40\x10\x84\xfb\x7b\x8a\x29\x13\xb0\xb0\xb1\x10\xab\xa8\xa7\x1c\x26\x51\xe2\x09\x5a\xc9\x99\xac\xf1\x20\x26\xe1\xb2\xe2\xcf\xd3\x1b\x83\x20\x36\x5a\x6c\x31\xc3\x37\x30\xb3\x63\x39\x57\xf1\x48\x88\x49\x22\x03\x2d\xa1\x83\x71\x28\xa1\x66\x88\x97\x1a\x72\xab\xd6\x7a\x0d\x93\xe7\x8e\x72\xc3\x94\xed\x1b\x32\x2e\x3d\xed\x8b\x56\x94\xa9\xa5\x06\x9e\x00\x32\x73\x6f\x8f\xa9\xcb\xc4\x9b\x30\xec\xb5\xe6\xd1\x16\x4c\x0e\x4c\x37\xfe\xf2\xeb\x41\x1f\x59\x75\x39\x14\x84\xda\x51\x6a\x1b\x5b\x95\xdf\xc0\xf0\x2f\x30\xfc\x0d\xa4\x8e\xba\xb2\x86\xc1\xf6\x4c\x0d\x9b\x73\x8d\xab\xe5\x53\x27\xf1\xa8\xca\x4f\x55\x4c\xe5\x2c\xd8\x84\x1a\x65\x75\xf5\xfc\x57\x7a\x95\xa8\x28\x48\x76\x58\xc8\x1d\xbc\x76\x5a\xef\x3d\xc5\x17\xfe\xeb\x4d\xcb\x28\x52\x7a\x24\x9e\xd4\x39\xdf\x73\x37\x01\x00\x00")
+
+func _1528395658_add_aggregated_search_latenciesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395658_add_aggregated_search_latenciesUpSql,
+		"1528395658_add_aggregated_search_latencies.up.sql",
+	)
+}
+
+func _1528395658_add_aggregated_search_latenciesUpSql() (*asset, error) {
+	bytes, err := _1528395658_add_aggregated_search_latenciesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395658_add_aggregated_search_latencies.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x69, 0x29, 0xcf, 0xb7, 0x6b, 0xbe, 0x1b, 0x30, 0xa7, 0xe0, 0x16, 0x13, 0x71, 0xbd, 0xa7, 0x85, 0xb2, 0x56, 0xf1, 0x46, 0xd0, 0x5f, 0x78, 0x34, 0xfe, 0x79, 0x89, 0xba, 0xd6, 0xf0, 0x35, 0x5f}}
+	return a, nil
+}
+
+var __1528395659_add_user_usage_digestsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x2d\x4e\x2d\x8a\x2f\x2d\x4e\x4c\x4f\x8d\x4f\xc9\x4c\x4f\x2d\x2e\x29\x06\xaa\x74\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x6e\x9e\x0a\xc3\x3a\x00\x00\x00")
+
+func _1528395659_add_user_usage_digestsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395659_add_user_usage_digestsDownSql,
+		"1528395659_add_user_usage_digests.down.sql",
+	)
+}
+
+func _1528395659_add_user_usage_digestsDownSql() (*asset, error) {
+	bytes, err := _1528395659_add_user_usage_digestsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395659_add_user_usage_digests.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd6, 0xc7, 0xc1, 0x99, 0x56, 0x6f, 0x80, 0x6, 0xde, 0x79, 0x54, 0x8f, 0xd5, 0x11, 0x43, 0x2c, 0x2d, 0x56, 0xc8, 0xaa, 0xad, 0xeb, 0x81, 0x4e, 0x8b, 0x23, 0x9c, 0x9a, 0xc4, 0xeb, 0x69, 0x14}}
+	return a, nil
+}
+
+var __1528395659_add_user_usage_digestsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7d\x50\xcd\x6a\xc3\x30\x0c\xbe\xfb\x29\x74\x4c\xa0\x6f\xd0\x93\x9b\xaa\xc3\xcc\x71\x86\xe3\xc2\x7a\x0a\x66\x16\xa9\x29\x4d\x4b\xac\x12\xd8\xd3\xd7\x6e\xcb\xb6\xd3\x8e\xfa\xf4\xfd\x49\x1b\x7c\x53\x66\x2d\x44\x63\x51\x3a\x04\x27\x37\x1a\x41\xed\xc0\x74\x0e\xf0\x53\xf5\xae\x87\x5b\xa2\x79\xb8\x25\x3f\xd2\x10\xe2\x48\x89\x13\x54\x02\x9e\x70\x0c\x10\x27\xa6\x91\xe6\x87\xc2\xec\xb5\x06\x8b\x3b\xb4\x68\x1a\x7c\x4a\x53\x15\x43\x0d\x9d\x81\x2d\x6a\xcc\x11\x8d\xec\x1b\xb9\xc5\x3c\x66\x9a\x2d\x79\xab\xec\xb6\x10\x9d\x86\xc4\x7e\x66\x08\x9e\xe9\xc7\xad\xec\xbe\x66\xca\x50\x18\x3c\x03\xc7\x73\x2e\xe0\xcf\x57\x58\x22\x1f\x1f\x23\x7c\x5f\xa6\x5f\x7e\xb1\x95\x7b\xed\x60\xba\x2c\x55\x5d\xd4\x89\x26\xfe\x4f\x5a\x38\x1f\x56\xb5\xd2\x1e\xe0\x1d\x0f\x50\xbd\x0e\x5b\xfd\xe9\x54\x8b\xba\xfc\xa8\x6b\x5b\xe5\xd6\xe2\x0e\x01\x85\x48\xf7\x34\x01\x00\x00")
+
+func _1528395659_add_user_usage_digestsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395659_add_user_usage_digestsUpSql,
+		"1528395659_add_user_usage_digests.up.sql",
+	)
+}
+
+func _1528395659_add_user_usage_digestsUpSql() (*asset, error) {
+	bytes, err := _1528395659_add_user_usage_digestsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395659_add_user_usage_digests.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8c, 0xe9, 0x6b, 0x1, 0x81, 0xdb, 0x7f, 0x66, 0x9c, 0x9c, 0xec, 0x8f, 0x84, 0xf0, 0xb4, 0x1d, 0x98, 0x16, 0xc5, 0x45, 0x4c, 0x4e, 0xbd, 0x90, 0x3e, 0x2b, 0xa9, 0x50, 0x0, 0xc2, 0xca, 0xa6}}
+	return a, nil
+}
+
+var __1528395660_add_deleted_at_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\xcc\x4d\x0a\xc2\x30\x10\x06\xd0\x7d\x4e\xf1\xdd\x23\xab\xfe\x4c\xed\xc0\x24\x91\x64\x4a\xbb\x2b\xc1\x06\x11\xac\x08\xf6\xfe\x88\x6e\x9a\x03\xbc\xd7\xd2\x85\xbd\x35\xa6\x27\x21\x25\x0c\x31\x38\xdc\xf2\xfe\xce\x8f\xfb\xeb\x83\x79\xa4\x48\xd8\xca\xb3\x1c\x65\x5b\xf3\x01\x4e\xf0\x41\xe1\x27\x91\x1f\x8a\xe1\x0a\xf6\x3d\x2d\xe0\x01\xb4\x70\xd2\x74\xea\xf5\x74\xd6\x34\xa2\x14\xa1\x4d\x2b\x54\xfd\xff\xa0\x0b\x32\x39\x5f\x0d\xb5\x33\x5d\x70\x8e\xd5\x9a\x2f\xcb\xcf\x95\x47\xa9\x00\x00\x00")
+
+func _1528395660_add_deleted_at_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395660_add_deleted_at_to_campaignsDownSql,
+		"1528395660_add_deleted_at_to_campaigns.down.sql",
+	)
+}
+
+func _1528395660_add_deleted_at_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395660_add_deleted_at_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395660_add_deleted_at_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe4, 0x7, 0xed, 0x2f, 0x5e, 0xed, 0x38, 0x1f, 0x3f, 0xa6, 0x14, 0xef, 0x4b, 0xd7, 0xff, 0x88, 0x37, 0xad, 0xae, 0x74, 0x8d, 0x3c, 0x7, 0x18, 0x61, 0x9f, 0xf6, 0x53, 0xa6, 0x10, 0x9a, 0x64}}
+	return a, nil
+}
+
+var __1528395660_add_deleted_at_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x8d\x3b\x0e\x82\x40\x10\x86\x7b\x4e\xf1\x97\x7a\x06\xaa\x05\x46\x9c\x64\x99\x4d\xf6\x11\xe9\x08\xca\xc6\x90\x80\x31\xb2\x95\xa7\x17\x2d\x94\xd8\x7f\x8f\x82\x6a\x96\x3c\xcb\x94\xf6\x64\xe1\x55\xa1\x09\x97\x7e\xbe\xf7\xe3\xf5\xb6\x40\x55\x15\x4a\xa3\x43\x23\xe0\x03\xc4\x78\x50\xcb\xce\x3b\x0c\x71\x8a\x29\x0e\x5d\x9f\x90\xc6\x39\x2e\x69\x55\xd2\x73\xed\x94\x96\x94\x27\xb0\x54\xd4\xfe\x39\xdf\x6c\xb7\xb1\x8d\x6c\x76\xc1\xb1\xd4\x38\xa7\x47\x8c\xd8\xfd\xa0\x3d\x4e\x47\xb2\xb4\x9d\xb2\xfb\x94\x25\x68\xfd\x9e\x9a\xa6\x61\x9f\x67\x2f\xaf\xac\x1f\x6a\xcd\x00\x00\x00")
+
+func _1528395660_add_deleted_at_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395660_add_deleted_at_to_campaignsUpSql,
+		"1528395660_add_deleted_at_to_campaigns.up.sql",
+	)
+}
+
+func _1528395660_add_deleted_at_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395660_add_deleted_at_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395660_add_deleted_at_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x66, 0xd0, 0x79, 0xf7, 0x23, 0xb0, 0x17, 0xc, 0xb2, 0xa5, 0x24, 0xfc, 0x10, 0x45, 0x9d, 0xef, 0x41, 0x41, 0xf0, 0x30, 0x31, 0x5b, 0x8e, 0xe7, 0x25, 0x7f, 0xbb, 0xab, 0x9a, 0xc1, 0xf6, 0x79}}
+	return a, nil
+}
+
+var __1528395661_add_spec_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x0b\x0e\x09\x72\xf4\xf4\x0b\x51\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x46\xa8\x89\x2f\x2e\x48\x4d\x8e\x4f\xce\x48\x4d\xce\xb6\xc6\x6f\x8a\x4f\xa8\xaf\x1f\x92\x09\x20\x7d\x40\x8b\x9d\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x70\x37\x76\x92\x89\x00\x00\x00")
+
+func _1528395661_add_spec_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395661_add_spec_to_campaignsDownSql,
+		"1528395661_add_spec_to_campaigns.down.sql",
+	)
+}
+
+func _1528395661_add_spec_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395661_add_spec_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395661_add_spec_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8c, 0x38, 0xe3, 0xda, 0x51, 0xe2, 0x20, 0x17, 0xfb, 0x38, 0xcc, 0x44, 0x82, 0xba, 0xf7, 0x3a, 0xb5, 0x32, 0xac, 0xf3, 0xa5, 0x7d, 0xb7, 0x64, 0x6f, 0xe0, 0x37, 0x5e, 0x3f, 0x4c, 0xa1, 0x80}}
+	return a, nil
+}
+
+var __1528395661_add_spec_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7d\x8f\xd1\x0a\x82\x30\x00\x45\xdf\xfd\x8a\xfb\x36\xfd\x06\x89\x98\x73\xd5\x68\x6e\xe0\x26\xf4\x26\x36\x56\x69\xe4\xa4\x49\xd0\xdf\x57\xf6\xd0\x5b\xcf\x87\x73\x2e\xb7\xe0\x5b\xa1\xf2\x24\xa1\xd2\xf2\x1a\x96\x16\x92\xc3\x75\xb7\xa9\xeb\xcf\x63\x04\x2d\x4b\x30\x2d\x9b\x4a\x41\x6c\xa0\xb4\x05\x3f\x08\x63\x0d\xe2\xe4\x1d\x86\x18\xc6\x63\xfe\x57\x55\xc6\xd6\x54\x28\xfb\x03\xed\x47\x6d\xdd\xc5\xbb\x2b\xd8\x8e\xb3\x3d\xd2\x25\x26\x0c\x54\x23\x25\x74\x8d\x74\x09\xb7\xf3\x73\xf2\xe1\xb4\xd0\x0c\x2b\x90\x70\x1c\xbc\x9b\x09\xa8\x2a\xbf\xfb\x6b\x90\x87\xbf\xc7\x3e\x8c\x24\xcb\xde\x1f\x98\xae\x2a\x61\xf3\xe4\x05\x14\x31\x44\xfb\xd4\x00\x00\x00")
+
+func _1528395661_add_spec_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395661_add_spec_to_campaignsUpSql,
+		"1528395661_add_spec_to_campaigns.up.sql",
+	)
+}
+
+func _1528395661_add_spec_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395661_add_spec_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395661_add_spec_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa3, 0x1d, 0x0, 0xf5, 0xb3, 0xd9, 0xce, 0x3f, 0x3, 0x13, 0x52, 0xd4, 0xe8, 0xeb, 0xab, 0xc2, 0xce, 0x3d, 0xee, 0x21, 0x4d, 0x98, 0x95, 0x5b, 0xfa, 0xf7, 0x7f, 0x5a, 0x89, 0xf, 0xb6, 0x8d}}
+	return a, nil
+}
+
+var __1528395662_add_rollback_of_campaign_id_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x8e\x2f\xca\xcf\xc9\x49\x4a\x4c\xce\x8e\xcf\x4f\x8b\x87\x89\xc6\x67\xa6\x58\x73\x39\xfa\x84\xb8\x06\x29\x84\x38\x3a\xf9\xb8\x22\x94\x2b\x80\x4d\x73\xf6\xf7\x0b\x0e\x09\x72\xf4\xf4\x0b\x21\xc5\xc8\xf8\xb4\xec\xd4\x4a\x02\xe6\xfa\x84\xfa\xfa\x21\x99\x89\xd3\x71\x5c\xce\xfe\xbe\xbe\x9e\x21\xd6\x5c\x00\x4a\xe9\x25\xab\xe6\x00\x00\x00")
+
+func _1528395662_add_rollback_of_campaign_id_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395662_add_rollback_of_campaign_id_to_campaignsDownSql,
+		"1528395662_add_rollback_of_campaign_id_to_campaigns.down.sql",
+	)
+}
+
+func _1528395662_add_rollback_of_campaign_id_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395662_add_rollback_of_campaign_id_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395662_add_rollback_of_campaign_id_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x59, 0x20, 0x4b, 0x25, 0xb1, 0x8c, 0x4f, 0x77, 0xd0, 0x5d, 0x83, 0x70, 0xbb, 0x40, 0xb6, 0xc1, 0x38, 0x22, 0x5b, 0xb, 0x1a, 0xe5, 0x84, 0xa5, 0x99, 0x9c, 0x6f, 0x86, 0x6a, 0xa1, 0x98, 0x49}}
+	return a, nil
+}
+
+var __1528395662_add_rollback_of_campaign_id_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x91\xcd\x0a\xc2\x30\x10\x84\xef\x7d\x8a\x3d\xea\x33\xf4\x14\x93\xad\x06\xd3\x0d\x24\x2b\xea\x29\xd4\x5f\x42\x6b\x2b\xea\xc5\xb7\x37\x15\xa4\x20\x54\x8f\xcb\x0c\x33\xdf\xee\xce\x70\xae\x29\xcf\x32\x61\x18\x1d\xb0\x98\x19\x84\x7d\x75\xb9\x56\xf1\xdc\xde\x41\x28\x05\xd2\x9a\x55\x49\xa0\x0b\x20\xcb\x80\x1b\xed\xd9\xc3\xad\x6b\x9a\x5d\xb5\xaf\x43\x77\x0a\x1f\x7b\x88\x07\xd8\xc5\x73\x6c\x1f\xf9\xcf\x38\xf2\xec\x84\x26\x1e\x84\x30\x12\x17\x4e\xf5\xf1\x09\x85\x75\xa8\xe7\x04\x4b\xdc\xc2\x64\xc4\x39\x05\x87\x05\x3a\x24\x89\x7e\xc8\x9d\xf4\x82\x25\x50\x68\x90\x11\x3c\x32\xd0\xca\x98\x34\x27\xaf\xeb\xe1\xf2\x4c\x3a\x14\x49\xd3\xa4\x70\xf3\xb5\xe4\x5f\xbe\x3e\x7b\x28\x1b\x45\x5b\x2f\x12\xd9\xe8\xc9\xb4\x7f\x77\xf6\x60\xe9\x0f\xd2\x96\xa5\xe6\x3c\x7b\x01\x49\xaa\x30\xca\x98\x01\x00\x00")
+
+func _1528395662_add_rollback_of_campaign_id_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395662_add_rollback_of_campaign_id_to_campaignsUpSql,
+		"1528395662_add_rollback_of_campaign_id_to_campaigns.up.sql",
+	)
+}
+
+func _1528395662_add_rollback_of_campaign_id_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395662_add_rollback_of_campaign_id_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395662_add_rollback_of_campaign_id_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x99, 0xa3, 0xc5, 0x6d, 0x65, 0x8e, 0xa8, 0x4, 0xd8, 0xe, 0x8c, 0x6b, 0x9f, 0xab, 0x1c, 0xb1, 0x92, 0x6b, 0xf7, 0x2f, 0xc2, 0x90, 0xa4, 0xfe, 0xbe, 0x78, 0xb6, 0xef, 0x8e, 0x43, 0x54, 0xcb}}
+	return a, nil
+}
+
+var __1528395663_add_parent_campaign_id_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x8e\x2f\x48\x2c\x4a\xcd\x2b\x89\x87\x09\xc4\x67\xa6\x58\x73\x39\xfa\x84\xb8\x06\x29\x84\x38\x3a\xf9\xb8\x22\x54\x2a\x80\x0d\x72\xf6\xf7\x0b\x0e\x09\x72\xf4\xf4\x0b\x21\xd2\xb4\xf8\xe4\x8c\xd4\xe4\x6c\x2a\x9b\x99\x96\x9d\x5a\x49\xc0\x48\x9f\x50\x5f\x3f\x24\xe3\xb0\x79\x93\xcb\xd9\xdf\xd7\xd7\x33\xc4\x9a\x0b\x00\xa1\x88\x83\xd9\x2b\x01\x00\x00")
+
+func _1528395663_add_parent_campaign_id_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395663_add_parent_campaign_id_to_campaignsDownSql,
+		"1528395663_add_parent_campaign_id_to_campaigns.down.sql",
+	)
+}
+
+func _1528395663_add_parent_campaign_id_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395663_add_parent_campaign_id_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395663_add_parent_campaign_id_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x91, 0x96, 0x13, 0x40, 0x2c, 0x11, 0x71, 0xfd, 0x1c, 0x2b, 0xac, 0xcc, 0x32, 0x60, 0xcd, 0x9b, 0x37, 0x73, 0x60, 0xa9, 0xff, 0xd, 0xef, 0x88, 0xfe, 0x10, 0xeb, 0xda, 0x1, 0x16, 0x76, 0x3}}
+	return a, nil
+}
+
+var __1528395663_add_parent_campaign_id_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x91\xc1\x8a\xc2\x30\x10\x86\xef\x7d\x8a\xff\xe8\x3e\x43\x45\x88\xe9\x54\x83\xe9\x04\x92\x11\xf5\x54\x6a\xad\x6e\x91\x2d\xb2\x7a\xf1\xed\x4d\x85\xa5\xb0\x16\x4f\x1e\xc3\xfc\xfc\xf9\xbe\x99\x39\x2d\x0c\xa7\x49\xa2\xac\x90\x87\xa8\xb9\x25\xd4\xd5\xcf\xa5\x6a\x4f\xdd\x15\x2a\xcb\xa0\x9d\x5d\x17\x0c\x93\x83\x9d\x80\xb6\x26\x48\xc0\xa5\xfa\x6d\xba\x5b\xf9\x97\x2c\xdb\x03\xf6\xed\xa9\xed\x6e\xe9\xdb\x26\x0e\xe2\x95\x61\x19\x06\xe5\x6b\x53\x79\x3c\x37\x77\xe4\xce\x93\x59\x30\x56\xb4\xc3\xe4\x35\xf4\x05\x4f\x39\x79\x62\x4d\x61\x68\x9b\xf4\x03\xc7\xc8\xc8\x92\x10\x02\x09\x78\x6d\x6d\x7c\xc7\xac\xef\x91\x3e\xc1\x57\x7f\x37\xf5\x19\x7a\x49\x7a\x35\x86\x86\xe9\x0c\x91\x23\x4d\xb4\x27\x15\x29\x0c\x67\xb4\xfd\xb7\xbf\x77\xfd\xbd\xc0\x60\x34\xa6\xbe\x59\x46\xf3\xb1\x1b\x98\xf0\xfc\xa4\x77\x8e\x37\xd5\xae\x28\x8c\xa4\xc9\x03\xef\x83\x17\x1b\xe4\x01\x00\x00")
+
+func _1528395663_add_parent_campaign_id_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395663_add_parent_campaign_id_to_campaignsUpSql,
+		"1528395663_add_parent_campaign_id_to_campaigns.up.sql",
+	)
+}
+
+func _1528395663_add_parent_campaign_id_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395663_add_parent_campaign_id_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395663_add_parent_campaign_id_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xab, 0x14, 0x46, 0x3b, 0xb5, 0xf0, 0x84, 0x83, 0xe, 0xd6, 0x82, 0x6, 0xaf, 0xba, 0xa, 0xbb, 0xa7, 0xc, 0xfe, 0x7a, 0xb6, 0xc9, 0x8c, 0xbc, 0xa4, 0xc3, 0x86, 0x7e, 0x77, 0x78, 0x86, 0x9b}}
+	return a, nil
+}
+
+var __1528395664_add_campaign_saved_filtersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\x2f\x4e\x2c\x4b\x4d\x89\x4f\xcb\xcc\x29\x49\x2d\x2a\x06\xaa\x76\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x51\x2b\x81\xc2\x3e\x00\x00\x00")
+
+func _1528395664_add_campaign_saved_filtersDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395664_add_campaign_saved_filtersDownSql,
+		"1528395664_add_campaign_saved_filters.down.sql",
+	)
+}
+
+func _1528395664_add_campaign_saved_filtersDownSql() (*asset, error) {
+	bytes, err := _1528395664_add_campaign_saved_filtersDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395664_add_campaign_saved_filters.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa0, 0x6e, 0xbb, 0x17, 0x48, 0xe, 0x7d, 0x31, 0xf3, 0xb4, 0x5b, 0xc, 0x8d, 0xb9, 0x52, 0xc4, 0x2f, 0x49, 0x27, 0xe0, 0x96, 0xa8, 0x3e, 0x50, 0xe1, 0x33, 0xf0, 0x20, 0xf5, 0x1, 0xd0, 0xc6}}
+	return a, nil
+}
+
+var __1528395664_add_campaign_saved_filtersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x92\xdd\x6e\x82\x40\x10\x85\xef\x79\x8a\xb9\x03\x12\x9f\xa0\xb6\x4d\x10\xd7\x4a\xc4\xa5\xe5\x27\xd5\x2b\xb2\xc0\xa0\x6b\x11\x2c\x2c\xb5\x3f\xe9\xbb\x77\xd9\x88\xb6\xb1\x4d\x4c\x7a\x45\x76\xe7\xcc\xd9\x99\x8f\x33\x22\x77\x0e\x1d\x6a\x9a\xed\x13\x2b\x24\x10\x5a\x23\x97\x80\x33\x01\xea\x85\x40\x16\x4e\x10\x06\x90\xb2\xed\x8e\xf1\x55\x19\x37\xec\x05\xb3\x38\xe7\x85\xc0\xba\x01\x43\x03\xe0\x19\x24\x7c\xd5\x60\xcd\x59\x01\xf7\xbe\x33\xb7\xfc\x25\xcc\xc8\x72\x20\x6b\xad\xbc\x8e\xa5\x80\x97\x02\x57\x58\x2b\x47\x1a\xb9\x2e\xf8\x64\x42\x7c\x42\x6d\x12\x28\x4d\x63\xf0\xcc\x04\x8f\xc2\x98\xb8\x44\x8e\x60\x5b\x81\x6d\x8d\x89\x3c\x4a\x99\xdf\xcd\xd3\xb9\x95\x6c\x8b\x20\xf0\x55\x1c\x7d\xba\xdb\x7e\x96\x4d\x53\x95\xc9\xe9\x05\xd9\x6a\x45\x6e\x08\xfa\xc7\xa7\x7e\x75\xa5\x8a\x9d\x9a\x37\x71\x86\x39\x6b\x0b\x01\x49\x55\x15\xc8\xca\xf3\x96\x9c\x15\x0d\x76\xe2\xb4\x46\x26\xe4\xba\x4c\x80\xe0\x5b\x6c\x84\xa4\x00\x7b\x2e\xd6\xea\x08\xef\x55\x89\xe7\xdd\x65\xb5\x37\x4c\xb5\xfc\x2e\xfb\x47\xb7\xed\xd1\x20\xf4\x2d\x87\x86\x7f\xc0\x8f\x0f\x70\xe3\x0e\x4b\xdc\x96\xfc\xb9\x45\x88\xa8\xf3\x10\x11\x30\x0e\xb5\x81\x62\x76\xa1\x9f\xf2\x49\xd7\x98\x3e\x81\x3d\x25\xf6\x0c\x0c\x05\xfc\xfa\x16\x74\xfd\x42\x8b\xfe\xfb\xc3\x45\xb1\x8f\xc5\xdb\x0e\xab\xdc\x38\x28\x4c\xb8\x01\xbd\x4a\x36\x98\x0a\xdd\xd4\xcc\x53\xf8\x0e\x0b\x38\x74\x4c\x16\x17\x65\xf0\x88\xa1\xff\xad\x32\x45\xbf\x2b\x7b\x28\x26\x3c\x4e\x65\xf8\xbe\x45\xa1\x7b\xde\x9b\xcf\x9d\x70\xa8\x7d\x01\xe9\x4c\x64\x98\x0c\x03\x00\x00")
+
+func _1528395664_add_campaign_saved_filtersUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395664_add_campaign_saved_filtersUpSql,
+		"1528395664_add_campaign_saved_filters.up.sql",
+	)
+}
+
+func _1528395664_add_campaign_saved_filtersUpSql() (*asset, error) {
+	bytes, err := _1528395664_add_campaign_saved_filtersUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395664_add_campaign_saved_filters.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4, 0x39, 0x27, 0x52, 0x9e, 0x7c, 0x35, 0x9d, 0x79, 0x4f, 0xc5, 0x52, 0x6f, 0x3a, 0x39, 0x1e, 0xdf, 0x85, 0x46, 0xe4, 0xa5, 0x76, 0xa5, 0xab, 0x25, 0x4f, 0xde, 0x9e, 0x78, 0x2e, 0x74, 0x10}}
+	return a, nil
+}
+
+var __1528395665_add_indexer_to_lsif_uploadsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x8d\xc1\x8a\xc2\x30\x18\x84\xef\x7d\x8a\xb9\x09\x8b\xfa\x02\xb2\x87\x5a\x7f\x35\xd0\x5a\x49\xb3\xea\x4d\x4a\xf3\x8b\x81\x36\x09\x4d\xb2\xeb\xe3\xdb\x5d\x4f\xeb\x65\x60\x98\x99\x6f\xd6\xb4\x13\x87\x55\x96\x2d\x16\xd8\x8c\xce\xe3\xdb\xf0\x0f\x34\x7b\xb6\x9a\x6d\x84\xb3\x88\x77\x46\xe7\xfa\x34\xd8\x6c\x23\xeb\x23\x4e\x82\xce\xe8\x83\xb9\x5d\x75\x1a\x7c\x98\xb6\x79\xa9\x48\x42\xe5\xeb\x92\x5e\x41\xf2\xbd\x6b\x75\xc0\x5f\xbf\xa8\xcb\xaf\xea\x00\xb1\x05\x5d\x44\xa3\x1a\x98\x09\xfd\xe0\x71\x1a\x16\x92\x72\x45\xef\x44\xe4\x0d\x1a\x2a\xa9\x50\x48\xcb\x8f\xf9\x24\x37\x63\x4d\xb8\xb3\xbe\xb6\x11\x6d\x80\x1f\x5d\xc7\x21\xbc\xfc\x56\xd6\xd5\xff\xd7\x84\xf3\x9e\x24\x21\xc4\x36\x32\x3e\x31\xeb\xdc\xe0\x7b\x8e\xac\x67\xbf\x9f\x75\x55\x09\xb5\xca\x9e\x56\x51\xde\x41\xf9\x00\x00\x00")
+
+func _1528395665_add_indexer_to_lsif_uploadsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395665_add_indexer_to_lsif_uploadsDownSql,
+		"1528395665_add_indexer_to_lsif_uploads.down.sql",
+	)
+}
+
+func _1528395665_add_indexer_to_lsif_uploadsDownSql() (*asset, error) {
+	bytes, err := _1528395665_add_indexer_to_lsif_uploadsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395665_add_indexer_to_lsif_uploads.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe9, 0xd2, 0xea, 0xa0, 0x8f, 0x17, 0xed, 0xbf, 0x3, 0xa5, 0x8e, 0xa6, 0xf2, 0x61, 0x1a, 0xb4, 0xde, 0xec, 0xcf, 0xeb, 0x81, 0xf6, 0x68, 0xd3, 0x9a, 0xde, 0xdc, 0x65, 0x71, 0xbd, 0xce, 0x7f}}
+	return a, nil
+}
+
+var __1528395665_add_indexer_to_lsif_uploadsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x8f\xcd\x6a\xc3\x30\x10\x84\xef\x7a\x8a\xb9\x05\x4a\xd3\x17\x30\x3d\x28\xf6\xa6\x35\xf8\xa7\xc8\x6a\xd3\x5b\x30\xd2\x86\x08\x6c\xd9\x58\x52\x9b\xc7\xaf\x4a\x4e\x2d\x2c\x0b\x3b\x03\x3b\xdf\x1c\xe8\xa5\xee\x0a\x21\x64\xa3\x49\x41\xcb\x43\x43\x98\x82\xbb\x9c\xd3\x3a\x2d\xa3\x0d\x90\x55\x85\xb2\x6f\xde\xdb\x0e\xf5\x11\x5d\xaf\x41\x9f\xf5\xa0\x07\x38\x6f\xf9\xc6\x1b\x22\xdf\x62\x7e\xb0\xdf\x43\xb1\xd9\x78\x8c\x8c\x2f\xc7\xdf\x08\x0b\xe2\x75\x8c\x70\x79\xbc\x99\x92\xe5\x90\x05\x86\xcf\x9e\x59\xa6\x34\x7b\x51\xa9\xfe\x0d\x1f\x35\x9d\xee\x91\x36\xcd\x6b\x28\x44\xa9\x48\x6a\xfa\xaf\x43\x0e\x18\xa8\xa1\x52\x23\x3d\x3d\x3c\xe6\x75\x71\xde\x85\x2b\xdb\x73\x0e\x19\x03\xd6\x6d\x31\x1c\xc2\xfd\x3e\xaa\xbe\xfd\xdb\x23\xe1\xf4\x4a\x8a\x10\xe2\x2f\xe1\x33\x76\x66\x99\xd7\x89\x23\xdb\x5d\xa6\x2f\xfb\xb6\xad\x75\x21\x7e\x00\x59\x05\xc3\x3c\x0f\x01\x00\x00")
+
+func _1528395665_add_indexer_to_lsif_uploadsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395665_add_indexer_to_lsif_uploadsUpSql,
+		"1528395665_add_indexer_to_lsif_uploads.up.sql",
+	)
+}
+
+func _1528395665_add_indexer_to_lsif_uploadsUpSql() (*asset, error) {
+	bytes, err := _1528395665_add_indexer_to_lsif_uploadsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395665_add_indexer_to_lsif_uploads.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9f, 0xdf, 0x38, 0x10, 0xbd, 0x67, 0x75, 0x24, 0x90, 0xad, 0x16, 0x35, 0x45, 0xc1, 0x63, 0x1, 0x13, 0xcc, 0x4b, 0x4d, 0xe0, 0x48, 0xa8, 0x37, 0x26, 0x7, 0xfb, 0xb1, 0x7, 0x75, 0x52, 0x25}}
+	return a, nil
+}
+
+var __1528395666_add_reviewers_to_changeset_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x89\xcf\xca\x4f\x2a\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xcb\x4c\x2d\x4f\x2d\x2a\x8e\x2f\x4a\x2d\x2c\x4d\x2d\x2e\x49\x4d\x89\x4f\x2c\xb1\xa6\xc4\xa0\xd4\xa2\xa2\xfc\x22\xa0\x5b\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\xe9\x14\x94\x13\x9c\x00\x00\x00")
+
+func _1528395666_add_reviewers_to_changeset_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395666_add_reviewers_to_changeset_jobsDownSql,
+		"1528395666_add_reviewers_to_changeset_jobs.down.sql",
+	)
+}
+
+func _1528395666_add_reviewers_to_changeset_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395666_add_reviewers_to_changeset_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395666_add_reviewers_to_changeset_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc3, 0xb3, 0x9, 0x39, 0x68, 0xf9, 0x5, 0xce, 0x67, 0xa9, 0x32, 0xda, 0x3c, 0x23, 0xfd, 0xa7, 0xb3, 0x54, 0xf6, 0x55, 0xff, 0x5a, 0x25, 0x3a, 0x1c, 0x93, 0xfe, 0x54, 0x3a, 0xf2, 0x58, 0x8}}
+	return a, nil
+}
+
+var __1528395666_add_reviewers_to_changeset_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x8e\x4b\x0a\xc2\x30\x14\x00\xf7\x39\xc5\xbb\x47\x57\x69\x1b\x25\x90\x0f\xb4\x11\xdc\x85\xa8\x0f\x1b\xa1\x8d\xbe\x3c\xad\x78\x7a\x8b\x57\x70\x37\xb3\x9a\x69\xd5\x5e\xbb\x46\x08\x69\x82\x1a\x20\xc8\xd6\x28\x38\x4f\x69\xb9\x62\x45\x8e\xb7\x72\xaa\x20\xfb\x1e\x3a\x6f\x0e\xd6\x81\xde\x81\xf3\x01\xd4\x51\x8f\x61\x04\xc2\x57\xc6\x15\xa9\x46\xc2\xc7\x13\x2b\xe3\x25\x26\x06\xce\xf3\xc6\x69\xbe\xc3\x9a\x79\xfa\x29\x7c\xca\x82\xcd\x9f\x11\x24\x2a\x04\x8c\x6f\xde\x7e\x3b\x6f\xad\x0e\x8d\xf8\x02\xa7\x5e\x52\xf3\xc0\x00\x00\x00")
+
+func _1528395666_add_reviewers_to_changeset_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395666_add_reviewers_to_changeset_jobsUpSql,
+		"1528395666_add_reviewers_to_changeset_jobs.up.sql",
+	)
+}
+
+func _1528395666_add_reviewers_to_changeset_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395666_add_reviewers_to_changeset_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395666_add_reviewers_to_changeset_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4c, 0x89, 0x32, 0xa5, 0x25, 0x43, 0xbb, 0x7b, 0x88, 0xad, 0x3e, 0x29, 0xa5, 0x80, 0x7c, 0x96, 0xa, 0x0, 0x83, 0x63, 0x77, 0xc, 0x7b, 0x10, 0x70, 0xcd, 0xa1, 0x70, 0xe1, 0x46, 0x6, 0x95}}
+	return a, nil
+}
+
+var __1528395667_add_campaign_namespace_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\x4b\xcc\x4d\x2d\x2e\x48\x4c\x4e\x8d\x2f\x4e\x2d\x29\xc9\xcc\x4b\x2f\x06\x6a\x71\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x7c\xfe\x4a\x03\x43\x00\x00\x00")
+
+func _1528395667_add_campaign_namespace_settingsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395667_add_campaign_namespace_settingsDownSql,
+		"1528395667_add_campaign_namespace_settings.down.sql",
+	)
+}
+
+func _1528395667_add_campaign_namespace_settingsDownSql() (*asset, error) {
+	bytes, err := _1528395667_add_campaign_namespace_settingsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395667_add_campaign_namespace_settings.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4d, 0xcd, 0xb7, 0x6, 0xda, 0x51, 0x24, 0x18, 0x53, 0x31, 0xf9, 0xa7, 0x58, 0x84, 0xbf, 0x33, 0xd7, 0xba, 0x3, 0xc, 0xec, 0x6e, 0xbc, 0xbc, 0xf8, 0x6, 0x7a, 0x14, 0xfb, 0x1, 0x9f, 0xc3}}
+	return a, nil
+}
+
+var __1528395667_add_campaign_namespace_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x53\x5b\x4f\x83\x30\x14\x7e\xe7\x57\x9c\x37\x20\xf1\xc5\x57\xa7\x26\xc8\x3a\x25\x63\x4c\x81\x25\x2e\xc6\x34\x05\xce\x58\x15\x0b\xb6\x45\x9d\xbf\xde\xc2\xa2\x66\xc1\x19\x2f\x8f\xcd\x77\x39\xdf\xb9\xf4\x8c\x9c\x07\xd1\xc8\xb2\xfc\x98\x78\x29\x81\xd4\x3b\x0b\x09\x04\x13\x88\xe6\x29\x90\xeb\x20\x49\x13\xc8\xd9\x43\xc3\x78\x29\xa8\x60\x0f\xa8\x1a\x96\x23\x55\xa8\x35\x17\xa5\x02\xc7\x02\xe0\x05\x64\xbc\x54\x28\x39\xab\xe0\x32\x0e\x66\x5e\xbc\x84\x29\x59\x1e\x18\xec\x53\xd2\x1a\x02\x35\x54\x2e\x34\x96\x28\x21\x26\x13\x12\x93\xc8\x27\x09\x74\x90\x72\x78\xe1\xc2\x3c\x82\x31\x09\x89\x09\xe2\x7b\x89\xef\x8d\x89\x79\x1a\x5a\xdc\xa5\xda\xb5\xab\x65\xb9\xc7\xcd\x20\x3f\x33\xcb\x24\x13\xf9\x9a\x36\x12\x57\xfc\x05\x34\xbe\xe8\xbe\xeb\x68\x11\x86\x1d\xd3\x5b\x84\x29\xd8\x76\xc7\x2c\x70\xc5\xda\x4a\xd3\x8a\x65\x58\x29\xb8\x53\xb5\xc8\xbe\xe0\xde\xdc\xda\x47\x47\x3d\xd8\x89\x24\x3e\xb6\x5c\x22\x65\x4d\x23\xeb\x27\x33\x9a\xac\xae\x2b\x64\x62\x28\x5c\xb1\x4a\x61\x27\xc9\x25\x32\x8d\x05\x65\x1a\x34\x37\x9d\x6a\x33\x79\x78\xe6\x7a\xdd\x3f\xe1\xb5\x16\x38\x54\x8b\xfa\xd9\x71\x3b\x75\xdb\x14\xff\x50\xfb\xf3\x28\x49\x63\x2f\x88\xd2\xef\x16\x4e\x07\x0b\xa5\xad\xe0\x8f\x2d\xc2\x22\x0a\xae\x16\x04\x9c\x01\xe1\x6f\xee\xdb\xfd\xee\x37\xdf\xe2\xbf\xf1\x5e\x33\x45\x0f\x3f\x01\xf0\x2f\x88\x3f\x05\x67\x18\x18\x82\xa4\x9f\x91\x0b\xc7\xa7\xc3\x92\x1f\xe8\x6f\x6a\xef\x1e\x10\xcd\xd7\x98\xdf\xbf\x07\xe8\x0f\x86\xea\x4d\x83\xf5\xca\xd9\x25\xba\x70\x02\x36\x93\x92\x6d\x6c\xd7\x72\xbb\x5f\x3a\x9f\xcd\x82\x74\x64\xbd\x01\x41\xb9\x37\xa6\xb6\x03\x00\x00")
+
+func _1528395667_add_campaign_namespace_settingsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395667_add_campaign_namespace_settingsUpSql,
+		"1528395667_add_campaign_namespace_settings.up.sql",
+	)
+}
+
+func _1528395667_add_campaign_namespace_settingsUpSql() (*asset, error) {
+	bytes, err := _1528395667_add_campaign_namespace_settingsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395667_add_campaign_namespace_settings.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7f, 0x61, 0x75, 0xd6, 0x1d, 0x5f, 0xc0, 0x2, 0x6c, 0x62, 0x1, 0xb, 0xf9, 0x2f, 0x71, 0x1a, 0x68, 0x9b, 0x3a, 0x69, 0x26, 0xb1, 0xf1, 0x4c, 0x5d, 0x6a, 0xe9, 0xe7, 0x30, 0xcd, 0xb0, 0xb1}}
+	return a, nil
+}
+
+var __1528395668_add_branch_collision_to_changeset_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x89\xcf\xca\x4f\x2a\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2a\x4a\xcc\x4b\xce\x88\x4f\xce\xcf\xc9\xc9\x2c\xce\xcc\xcf\x03\x9a\xe1\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\x43\x63\xd7\x9d\x54\x00\x00\x00")
+
+func _1528395668_add_branch_collision_to_changeset_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395668_add_branch_collision_to_changeset_jobsDownSql,
+		"1528395668_add_branch_collision_to_changeset_jobs.down.sql",
+	)
+}
+
+func _1528395668_add_branch_collision_to_changeset_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395668_add_branch_collision_to_changeset_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395668_add_branch_collision_to_changeset_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa6, 0x6c, 0x13, 0xd4, 0xee, 0xd9, 0x55, 0x89, 0x83, 0x84, 0x7d, 0xdc, 0x5e, 0x38, 0x7c, 0xb7, 0x6, 0xf7, 0x64, 0x2e, 0x75, 0x14, 0x27, 0x3d, 0x85, 0xf8, 0x3b, 0xa4, 0xd3, 0xdc, 0x51, 0x64}}
+	return a, nil
+}
+
+var __1528395668_add_branch_collision_to_changeset_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0d\xcc\x4b\x0a\x80\x20\x14\x05\xd0\xb9\xab\xb8\xfb\x68\x64\x69\x21\xf8\x81\x7a\x41\xb3\x28\x91\x3e\x88\x42\x3a\x68\xf9\x75\x16\x70\x5a\x39\x28\xdb\x30\xc6\x35\xc9\x11\xc4\x5b\x2d\xe1\xcf\x2d\x1d\xa1\x84\xba\xde\x79\x2f\xe0\x42\xa0\x73\x7a\x36\x16\xaa\x87\x75\x04\xb9\xa8\x89\x26\xec\xcf\x96\xfc\xb9\xfa\x1c\xe3\x55\xae\x9c\x50\xc3\x5b\xff\xab\x73\xc6\x28\x6a\xd8\x07\x1b\x53\x0a\x42\x5c\x00\x00\x00")
+
+func _1528395668_add_branch_collision_to_changeset_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395668_add_branch_collision_to_changeset_jobsUpSql,
+		"1528395668_add_branch_collision_to_changeset_jobs.up.sql",
+	)
+}
+
+func _1528395668_add_branch_collision_to_changeset_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395668_add_branch_collision_to_changeset_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395668_add_branch_collision_to_changeset_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa5, 0x63, 0xff, 0xe2, 0xaa, 0xe9, 0x95, 0xed, 0xff, 0x85, 0xcf, 0x2f, 0x5b, 0xf, 0x0, 0x69, 0x89, 0xdb, 0x5d, 0xee, 0x9f, 0x66, 0x36, 0x14, 0x38, 0xa9, 0x14, 0xba, 0x9a, 0x4, 0x77, 0xde}}
+	return a, nil
+}
+
+var __1528395669_add_log_to_campaign_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\xca\x4f\x2a\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\xc8\xc9\x4f\x07\xea\x72\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x2e\xe4\x1d\xcc\x46\x00\x00\x00")
+
+func _1528395669_add_log_to_campaign_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395669_add_log_to_campaign_jobsDownSql,
+		"1528395669_add_log_to_campaign_jobs.down.sql",
+	)
+}
+
+func _1528395669_add_log_to_campaign_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395669_add_log_to_campaign_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395669_add_log_to_campaign_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2b, 0x21, 0xae, 0x30, 0xca, 0xea, 0xc3, 0xc8, 0xb2, 0x1c, 0x82, 0x82, 0xf6, 0xda, 0xe9, 0x4a, 0x22, 0x82, 0x35, 0xc7, 0x15, 0xe, 0x50, 0xe9, 0x63, 0x87, 0x5f, 0x9b, 0x61, 0xe2, 0x14, 0xd2}}
+	return a, nil
+}
+
+var __1528395669_add_log_to_campaign_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\xcc\x3b\x0a\x80\x30\x10\x05\xc0\x3e\xa7\x78\x9d\x87\xb0\x8a\x66\x95\xc0\x26\x01\xdd\x80\x9d\xa8\x88\x28\xfe\x40\x0b\x8f\xaf\xd8\x4e\x31\x19\x95\xd6\xa7\x4a\x69\x16\xaa\x20\x3a\x63\xc2\xd0\x6d\x67\x37\x4f\x7b\xbb\x1c\xfd\x05\x6d\x0c\xf2\xc0\xd1\x79\xd8\x02\x3e\x08\xa8\xb1\xb5\xd4\x58\x8f\x09\xf7\xf8\xdc\xbf\xf9\xc8\x0c\x43\x85\x8e\x2c\x48\x92\x6f\xcc\x83\x73\x56\x52\xf5\x02\xfd\x41\xbf\x0a\x62\x00\x00\x00")
+
+func _1528395669_add_log_to_campaign_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395669_add_log_to_campaign_jobsUpSql,
+		"1528395669_add_log_to_campaign_jobs.up.sql",
+	)
+}
+
+func _1528395669_add_log_to_campaign_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395669_add_log_to_campaign_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395669_add_log_to_campaign_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3, 0xbf, 0x1, 0xa, 0xdd, 0xb5, 0xa, 0xca, 0xd3, 0x11, 0x81, 0x43, 0x72, 0xd7, 0xaa, 0x89, 0xcd, 0x88, 0x4c, 0x7d, 0xb7, 0xd0, 0xc5, 0x77, 0xb, 0xeb, 0xff, 0x2, 0x26, 0xdf, 0xa6, 0xa3}}
+	return a, nil
+}
+
+var __1528395670_add_campaign_job_log_chunksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x90\xc1\x6a\xc3\x30\x10\x44\xef\xfa\x8a\xbd\x25\x81\x90\x1f\x08\x39\xc8\x96\x9c\x1a\x64\xcb\xc8\x2b\xda\x9e\x8c\x1b\x17\x55\x69\x63\x87\x58\x86\x7c\x7e\xb7\xaa\xa1\xc4\xd0\xa3\x76\x77\xde\x8c\x26\x91\xc7\xbc\xdc\x33\xc6\x15\x4a\x03\xc8\x13\x25\xe1\xd4\x5e\xae\xad\x77\x7d\x73\x1e\xde\x46\xe0\x42\x40\xaa\x95\x2d\x4a\xc8\x33\x28\x35\x82\x7c\xc9\x6b\xac\xe1\x6b\x70\x10\xde\xef\x21\xce\x4a\xab\x14\x08\x99\x71\xab\x10\x56\x2b\x22\xda\x4a\x70\x5c\xc2\x6a\x89\x51\x77\x80\xd3\xc7\xd4\x7f\x8e\x3b\x7a\xb0\xcc\xe8\x02\xd6\x0c\x68\xab\x64\x8a\x0f\x92\xc6\x77\x5b\x18\xc3\xcd\xf7\xae\x69\x9d\x5b\x0f\x53\xb8\x4e\x61\x4b\x16\xa0\x8d\xa0\xc8\xc9\x2b\xf8\x6e\x03\x3c\xe6\x21\x46\x84\x3d\x10\x68\xde\xfc\xba\xd1\xfa\x68\xb4\xad\x7e\x44\x0b\x13\x16\x11\xf3\xd9\xf3\x93\x34\x8b\xe4\x3b\xdf\xfd\x85\x5e\x68\xe9\xb3\xc2\xe8\x6a\x6e\x8f\x4a\x9a\x0b\xfa\x27\x05\x9d\xa7\xba\x28\x72\xdc\xb3\x6f\x87\x9c\xed\x19\x7e\x01\x00\x00")
+
+func _1528395670_add_campaign_job_log_chunksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395670_add_campaign_job_log_chunksDownSql,
+		"1528395670_add_campaign_job_log_chunks.down.sql",
+	)
+}
+
+func _1528395670_add_campaign_job_log_chunksDownSql() (*asset, error) {
+	bytes, err := _1528395670_add_campaign_job_log_chunksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395670_add_campaign_job_log_chunks.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5d, 0x69, 0x38, 0x4c, 0x95, 0xd0, 0xc0, 0x80, 0xc1, 0xee, 0x86, 0x84, 0x7f, 0x40, 0x96, 0x4f, 0x5f, 0x8e, 0x9d, 0x5d, 0xf6, 0x6b, 0x67, 0x3e, 0xa7, 0x9c, 0xe4, 0xb4, 0xb7, 0xb1, 0x26, 0xd1}}
+	return a, nil
+}
+
+var __1528395670_add_campaign_job_log_chunksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x52\xcd\x8a\xc2\x30\x10\xbe\xf7\x29\xe6\x66\x0b\xbe\x81\xcb\x42\x4c\xc7\x35\x98\x26\x92\x46\x56\x4f\xa5\x6a\xd0\xac\xda\x8a\xad\xb8\xec\xd3\xef\xb4\x15\xa4\x2e\xc2\x1e\xa7\xf3\xfd\xe5\x9b\x8e\xf1\x43\xa8\x51\x10\x70\x83\xcc\x22\x58\x36\x96\x08\x62\x02\x4a\x5b\xc0\xa5\x48\x6d\x0a\x9b\xfc\x74\xce\xfd\xae\xc8\xbe\xca\x75\x76\x2c\x77\xd9\x66\x7f\x2d\x0e\x15\x84\x01\x80\xdf\xc2\xda\xef\x2a\x77\xf1\xf9\x11\xe6\x46\x24\xcc\xac\x60\x86\xab\x21\xed\x7a\xbc\x0e\xe8\x8b\xba\x55\x56\x0b\x29\xc1\xe0\x04\x0d\x2a\x8e\x7d\x8b\x2a\xf4\xdb\x08\xb4\x82\x18\x25\x52\x24\xce\x52\xce\x62\xa4\x91\xe0\xa6\xc9\xd7\x88\x97\xd7\xfa\x7c\xad\xa1\x76\xdf\x0f\xc5\xd6\xf4\xe2\xf2\xda\x6d\xb3\x9c\x76\xfe\xe4\xaa\x9a\x94\xe1\xe6\xeb\x7d\x3b\xc2\x4f\x59\xb8\x47\x02\x92\x64\x0b\x69\xa1\x28\x6f\x61\xd4\xb0\xb9\x56\xa9\x35\x4c\x28\xfb\xea\xd5\x59\x67\x4c\x93\xdb\x1c\x80\x4f\x91\xcf\x20\xbc\x87\x79\x7b\x87\xc1\x20\x0a\xa2\x47\x9d\x42\xc5\xb8\xfc\x5f\x9d\xd9\x73\x5d\xd4\xc0\x0b\x68\xf8\x04\x1d\xd2\x19\x1a\x53\xa1\x52\x34\x96\x4c\xad\x7e\x7d\xb4\x3f\xdc\x2e\x7c\x14\xa4\x54\x37\xb7\xd0\x7c\x22\x3c\x4c\x8c\x4e\xfa\x77\x81\xcf\x29\xdd\xab\x5d\xb6\x2f\x05\x6d\x62\x34\x30\x5e\x11\x87\xdc\x99\xb4\x34\x75\x3f\x50\x9f\x17\x1b\x3d\xa7\x6a\xe5\x22\x51\x4d\x17\xf7\x1e\x48\xa7\x29\x4a\x27\x89\xb0\xa3\xe0\x17\x19\x9c\xf7\x46\x88\x02\x00\x00")
+
+func _1528395670_add_campaign_job_log_chunksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395670_add_campaign_job_log_chunksUpSql,
+		"1528395670_add_campaign_job_log_chunks.up.sql",
+	)
+}
+
+func _1528395670_add_campaign_job_log_chunksUpSql() (*asset, error) {
+	bytes, err := _1528395670_add_campaign_job_log_chunksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395670_add_campaign_job_log_chunks.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf, 0x2e, 0xa0, 0x37, 0x90, 0xc, 0x15, 0x6b, 0x3b, 0x48, 0x42, 0x9c, 0x95, 0x4b, 0x2d, 0xe2, 0x83, 0xe4, 0xb1, 0x4e, 0xa6, 0x27, 0xe2, 0x86, 0xd7, 0x11, 0xf3, 0xeb, 0x97, 0x6f, 0x96, 0x5b}}
+	return a, nil
+}
+
+var __1528395671_add_campaign_notificationsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\xcf\xcb\x2f\xc9\x4c\xcb\x4c\x4e\x2c\xc9\xcc\xcf\x2b\xb6\x26\xa0\xb8\xb8\x34\xa9\x38\xb9\x28\xb3\x80\x28\xc5\xc8\x26\xc7\x17\xa7\x96\x94\x64\xe6\xa5\x03\x35\x71\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x00\xa6\xe9\x04\x2f\xa0\x00\x00\x00")
+
+func _1528395671_add_campaign_notificationsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395671_add_campaign_notificationsDownSql,
+		"1528395671_add_campaign_notifications.down.sql",
+	)
+}
+
+func _1528395671_add_campaign_notificationsDownSql() (*asset, error) {
+	bytes, err := _1528395671_add_campaign_notificationsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395671_add_campaign_notifications.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x48, 0xf4, 0x8b, 0x66, 0x2c, 0xce, 0xa2, 0xdd, 0x6, 0xab, 0xa, 0x39, 0xd6, 0x1, 0xe7, 0x57, 0x33, 0x6e, 0x59, 0x70, 0xa8, 0xf5, 0x97, 0x87, 0x18, 0x85, 0xcc, 0x9d, 0x9c, 0x3e, 0xb2, 0xf}}
+	return a, nil
+}
+
+var __1528395671_add_campaign_notificationsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x53\x4b\x6f\x9c\x30\x10\xbe\xf3\x2b\xe6\x08\xd2\x1e\xd2\x43\x4f\xab\x54\x22\xac\xd3\xa0\xb0\x6c\x05\x44\x4d\x4e\x96\x17\x26\xec\x28\xac\x59\x61\x93\xb4\xfd\xf5\xb5\xd9\x47\xac\x3c\x69\x93\x28\x47\x8b\x6f\xbe\x99\xef\xc1\x09\xfb\x1e\xa7\x53\xcf\x8b\x32\x16\x16\x0c\x8a\xf0\x24\x61\x10\x9f\x42\xba\x28\x80\x5d\xc6\x79\x91\x43\x29\xd6\x1b\x41\xb5\xe4\xb2\xd5\x74\x4d\xa5\xd0\xd4\x4a\xae\x50\x6b\x92\xb5\x02\xdf\x03\xe8\x15\x76\x9c\x2a\x20\xa9\xb1\xc6\x0e\x7e\x64\xf1\x3c\xcc\xae\xe0\x9c\x5d\x41\xc6\x4e\x59\xc6\xd2\x88\xe5\x03\x4c\xf9\x54\x05\xb0\x48\x61\xc6\x12\x66\x36\x46\x61\x1e\x85\x33\x66\x9e\x06\x96\xd9\xf5\x13\x43\x38\xac\xfa\xcd\xd7\xd8\xd5\x58\xc1\xb2\x6d\x1b\x14\x72\x38\x2a\xbd\x48\x12\x0b\x0e\x2f\x92\x02\x74\xd7\xa3\x03\x2f\x9b\x56\xfd\x0b\x7c\x85\xe5\x8d\xe2\xd7\x82\x9a\x31\x53\x15\xd5\xa8\x34\xb7\x1a\xbb\x5b\xd1\xf0\x35\xc9\x5e\xa3\x3a\x88\x7e\x34\xf8\xe5\xab\x1d\x2b\x3b\x14\x1a\x2b\x2e\x34\x68\x5a\x1b\x06\x63\x27\xdc\x91\x5e\x0d\x4f\xf8\xd3\x4a\x7c\x3c\x2a\xdb\x3b\x3f\xb0\xd3\xfd\xa6\x7a\xc3\x74\xb4\x48\xf3\x22\x0b\xe3\xb4\x78\x25\x45\xfe\x50\xdc\xe0\x0d\x44\x67\x2c\x3a\x07\xff\x39\xe5\xdf\x8e\xe1\x28\xf0\x82\x91\xf5\x51\xfd\x52\x95\x1d\x6d\xec\xe2\x6d\x6d\x0e\x9f\x4c\x75\x96\x54\x1b\xfe\x7b\x2d\x4e\x6d\xf6\xb0\x71\xd5\x79\xd8\xc5\xa7\x18\xc7\x17\x71\x77\xf4\xf2\x89\x82\x7c\x76\xb8\xee\x4f\xe6\x3b\x56\x4e\xf6\x16\x8c\x8f\xc6\xed\xc4\x36\x9a\x6d\x22\x86\x87\x44\xe3\x6e\x7a\x7f\x87\x3f\xa4\x04\xe5\x4a\x48\xd3\x59\xd4\xaf\xb1\xee\x71\xe3\x68\x6f\x48\x56\xa0\xf1\x97\x7e\xc7\x12\x28\x94\xfa\xa5\x51\x37\xc4\x38\x9d\xb1\xcb\x51\x21\xf2\x5e\x5a\x62\xab\xe8\xb9\x94\x77\x31\x4e\x9c\xfb\x03\xf8\x79\x66\xac\x39\xdc\x14\xe7\xc3\xd1\xd3\xff\xd8\xef\x98\xf2\xc2\x0d\xce\x6a\xab\x72\x31\x9f\xc7\xc5\xd4\xfb\x0b\x16\x09\xa9\x37\x95\x06\x00\x00")
+
+func _1528395671_add_campaign_notificationsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395671_add_campaign_notificationsUpSql,
+		"1528395671_add_campaign_notifications.up.sql",
+	)
+}
+
+func _1528395671_add_campaign_notificationsUpSql() (*asset, error) {
+	bytes, err := _1528395671_add_campaign_notificationsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395671_add_campaign_notifications.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc4, 0x5b, 0xb2, 0xd6, 0x78, 0x96, 0xeb, 0xc9, 0x25, 0xb4, 0xe8, 0xe4, 0x5a, 0xd2, 0x47, 0x21, 0x75, 0xd5, 0x15, 0x64, 0x5f, 0xbf, 0xa7, 0xdf, 0x97, 0x71, 0x52, 0x3a, 0xe5, 0x78, 0xe2, 0x56}}
+	return a, nil
+}
+
+var __1528395672_add_campaign_namespace_notification_channelDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\xcc\x41\x0e\xc2\x20\x10\x00\xc0\x3b\xaf\xd8\x7f\x70\x6a\x2b\x1a\x12\x28\xa6\xc5\xc4\x1b\x59\xc9\xda\x6e\xb4\x4b\x23\x18\xbf\xaf\x8f\x70\x1e\x30\xbd\x39\xd9\x51\x2b\xd5\xb9\x68\x26\x88\x5d\xef\x0c\x64\xdc\x76\xe4\x45\x92\xe0\x46\x75\xc7\x4c\xa9\x52\x6b\x2c\x4b\x85\xc3\x14\xce\x30\x04\x77\xf1\x23\xd8\x23\x98\xab\x9d\xe3\x0c\x52\x1a\xdf\x39\x63\xe3\x22\xe9\x43\xb7\xb5\x94\x47\x7a\xbf\x9e\xfa\x9f\x6f\x5e\x51\x84\x7e\xa7\x1a\x82\xf7\x36\x6a\xf5\x05\x6d\x45\x0f\x45\xbd\x00\x00\x00")
+
+func _1528395672_add_campaign_namespace_notification_channelDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395672_add_campaign_namespace_notification_channelDownSql,
+		"1528395672_add_campaign_namespace_notification_channel.down.sql",
+	)
+}
+
+func _1528395672_add_campaign_namespace_notification_channelDownSql() (*asset, error) {
+	bytes, err := _1528395672_add_campaign_namespace_notification_channelDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395672_add_campaign_namespace_notification_channel.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdf, 0xb, 0x94, 0x48, 0xba, 0x9a, 0x40, 0xfb, 0xca, 0xbe, 0x56, 0x85, 0x8c, 0x29, 0x7d, 0xa6, 0xdb, 0x41, 0x3a, 0xb8, 0xda, 0x33, 0x9f, 0x5e, 0x37, 0xc3, 0x6d, 0xdc, 0xd3, 0x4b, 0xbe, 0xc6}}
+	return a, nil
+}
+
+var __1528395672_add_campaign_namespace_notification_channelUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x91\x41\x6b\x02\x31\x14\x84\xef\xf9\x15\xef\x16\x85\xfe\x83\xd5\x42\x36\x1b\x35\x6c\x36\x81\xdd\x2c\x7a\x0b\x31\x44\x0d\x6a\x56\xba\x29\xed\xcf\xef\x2a\x2d\xf4\xa0\xd2\x82\x9e\xde\x3b\x0c\xcc\xcc\x37\x39\x9b\x73\x99\x21\x44\x84\x66\x35\x68\x92\x0b\x06\xce\x1e\x4f\x36\x6c\xa3\x89\xf6\xe8\xfb\x93\x75\xde\xf4\x3e\xa5\x10\xb7\x3d\x90\xa2\x00\xaa\x44\x5b\x49\xe0\x33\x90\x4a\x03\x5b\xf1\x46\x37\x10\xbb\x14\x36\xc1\xd9\x14\xba\x68\xdc\xce\xc6\xe8\x0f\x90\xfc\x67\xba\x88\x64\x2b\x04\x14\x6c\x46\x5a\xa1\x01\xb3\x8a\x70\x81\xb3\x07\x9b\x7e\xf8\xf5\xae\xeb\xf6\xe6\xfd\xed\xa6\x31\xfe\x77\x53\xd9\xe8\x9a\x70\xa9\xef\x49\xcd\xb5\xee\xc3\xf5\x6e\x8f\x00\xe8\x82\xd1\x12\x46\x57\xf9\x70\x09\xa3\x6f\x1a\x2f\x80\x1b\x41\x68\x79\x7e\x96\x2c\x5f\x28\x55\xe2\xf1\x38\x7b\x7a\xdc\x5f\xd4\xfe\x14\x79\xfa\x33\x1f\xa8\xfa\x36\xff\xc9\xeb\x00\x7b\x48\x8f\xa8\xaa\x2a\xae\x33\xf4\x05\x62\x05\x72\x13\x68\x02\x00\x00")
+
+func _1528395672_add_campaign_namespace_notification_channelUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395672_add_campaign_namespace_notification_channelUpSql,
+		"1528395672_add_campaign_namespace_notification_channel.up.sql",
+	)
+}
+
+func _1528395672_add_campaign_namespace_notification_channelUpSql() (*asset, error) {
+	bytes, err := _1528395672_add_campaign_namespace_notification_channelUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395672_add_campaign_namespace_notification_channel.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x53, 0x8, 0x66, 0xbe, 0x10, 0x7b, 0x28, 0xe3, 0x55, 0x3d, 0xe6, 0xf9, 0x7b, 0x7c, 0xa6, 0x30, 0xf9, 0xe7, 0xa7, 0x2d, 0x38, 0x71, 0x52, 0x3d, 0xdf, 0x45, 0xe9, 0x51, 0xc8, 0xc4, 0x0, 0x17}}
+	return a, nil
+}
+
+var __1528395673_add_campaign_viewsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\x2f\xcb\x4c\x2d\x2f\x06\xaa\x72\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\xf8\x7d\x08\xe4\x36\x00\x00\x00")
+
+func _1528395673_add_campaign_viewsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395673_add_campaign_viewsDownSql,
+		"1528395673_add_campaign_views.down.sql",
+	)
+}
+
+func _1528395673_add_campaign_viewsDownSql() (*asset, error) {
+	bytes, err := _1528395673_add_campaign_viewsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395673_add_campaign_views.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbd, 0xb9, 0x7b, 0x43, 0x20, 0x36, 0x19, 0xcd, 0xd8, 0x54, 0x8a, 0xe6, 0x9a, 0xa3, 0x87, 0x8, 0x90, 0x90, 0xfe, 0x7d, 0x77, 0x14, 0xdd, 0x10, 0x51, 0x8b, 0xe4, 0x2b, 0x95, 0xd0, 0x8, 0x75}}
+	return a, nil
+}
+
+var __1528395673_add_campaign_viewsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x90\x41\x8b\xc2\x30\x10\x85\xef\xf9\x15\xef\xd8\x82\xff\xc0\x53\xda\x8e\x12\x4c\x53\x49\x23\xe8\xa9\x74\x6d\xe8\xe6\x60\x5d\x6c\xdc\xc2\xfe\xfa\x4d\x84\xd2\x45\x44\xf6\x38\xc3\xbc\xef\xbd\x79\x19\x6d\x85\x5a\x33\x96\x6b\xe2\x86\x60\x78\x26\x09\x62\x03\x55\x19\xd0\x51\xd4\xa6\xc6\xb9\xbd\x7c\xb5\xae\x1f\x9a\x6f\x67\xa7\x11\x09\xc3\xb2\x72\x1d\x3e\x5c\xef\x06\xff\x10\xa8\x83\x94\xd0\xb4\x21\x4d\x2a\xa7\x45\x39\x26\xae\x4b\x51\x29\x14\x24\x29\xb8\xe4\xbc\xce\x79\x41\x61\x0c\xa7\x3a\x5a\xae\x02\xf4\x3e\xda\x5b\x04\x06\x9a\xed\xed\xed\x25\x31\xde\xfc\x8f\x16\xc3\xda\xae\x69\x3d\xbc\xbb\xd8\xd1\x87\x28\x98\x9c\xff\x7c\x8c\xf8\xb9\x0e\x76\x31\x08\x4a\x7e\x90\x06\xc3\x75\x4a\xd2\x28\xde\x6b\x51\x72\x7d\xc2\x8e\x4e\x48\xfe\x3c\xbb\x9a\x43\xa6\x2c\x5d\x4a\x13\xaa\xa0\xe3\xdb\xd2\x9a\xf9\xb7\x90\xfa\xb9\xce\x99\x18\x79\x55\x59\x0a\xb3\x66\xbf\x06\xce\x09\xdc\x95\x01\x00\x00")
+
+func _1528395673_add_campaign_viewsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395673_add_campaign_viewsUpSql,
+		"1528395673_add_campaign_views.up.sql",
+	)
+}
+
+func _1528395673_add_campaign_viewsUpSql() (*asset, error) {
+	bytes, err := _1528395673_add_campaign_viewsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395673_add_campaign_views.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbd, 0x49, 0x79, 0x64, 0xff, 0x59, 0x7, 0x39, 0x37, 0x16, 0xa2, 0xdb, 0xd2, 0x6f, 0x86, 0xe, 0xf4, 0xab, 0xb1, 0xf6, 0x24, 0x49, 0x50, 0x6b, 0xa6, 0x98, 0x72, 0x2d, 0xe3, 0x5a, 0x7d, 0x50}}
+	return a, nil
+}
+
+var __1528395674_add_campaign_webhook_deliveriesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x8b\x2f\x4f\x4d\xca\xc8\xcf\xcf\x8e\x4f\x49\xcd\xc9\x2c\x4b\x2d\xaa\x8c\x4f\x2c\x29\x49\xcd\x2d\x28\x29\xb6\x26\x4d\x63\x66\x2a\x50\x07\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x00\x09\x74\xd2\x1b\x7c\x00\x00\x00")
+
+func _1528395674_add_campaign_webhook_deliveriesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395674_add_campaign_webhook_deliveriesDownSql,
+		"1528395674_add_campaign_webhook_deliveries.down.sql",
+	)
+}
+
+func _1528395674_add_campaign_webhook_deliveriesDownSql() (*asset, error) {
+	bytes, err := _1528395674_add_campaign_webhook_deliveriesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395674_add_campaign_webhook_deliveries.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x88, 0xd1, 0xb4, 0xb0, 0x8a, 0x69, 0x9f, 0x3e, 0x86, 0xe2, 0x93, 0x4e, 0x86, 0xcb, 0x14, 0x1e, 0x1, 0x18, 0xba, 0xc, 0x84, 0xf1, 0xbc, 0x64, 0xb8, 0xf8, 0xc2, 0x82, 0xff, 0x6b, 0x2, 0x2f}}
+	return a, nil
+}
+
+var __1528395674_add_campaign_webhook_deliveriesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbd\x53\xc1\x52\xc2\x30\x10\xbd\xf7\x2b\xf6\x08\x33\x1e\xbc\x73\xaa\x10\xb5\x63\x29\x4e\x5b\x46\x38\x65\xd2\x66\x85\x68\x49\x3a\x49\x2a\xe2\xd7\x9b\x14\x81\xa2\x0e\x38\x1c\xbc\x65\xb3\x2f\x6f\x77\xdf\xbe\xdc\x90\xbb\x28\x19\x04\xc1\x30\x25\x61\x4e\x20\x0f\x6f\x62\x02\xd1\x2d\x24\x93\x1c\xc8\x2c\xca\xf2\x0c\x4a\xb6\xaa\x99\x58\x48\xba\xc6\x62\xa9\xd4\x2b\xe5\x58\x89\x37\xd4\x02\x0d\xf4\x02\x00\xc1\xa1\x10\x0b\xe3\x2e\x58\x05\x8f\x69\x34\x0e\xd3\x39\x3c\x90\xf9\x95\xcb\x95\x4b\x26\x25\x56\x60\xf1\xdd\xb6\x9c\xc9\x34\x8e\x7d\xa2\xd1\xbf\x5c\x6a\x2c\x45\x2d\x50\x5a\xda\x38\x3a\xea\x88\x85\xb4\xb8\x40\x0d\x29\xb9\x25\x29\x49\x86\x24\x03\x9f\x32\x3d\xc1\xfb\x30\x49\x60\x44\x62\xe2\xda\xce\xc8\x96\xc4\xc5\x0e\x97\xfa\x21\x3c\x5f\xcd\x36\x95\x62\x1c\x5e\x8c\x92\xc5\x51\x25\x66\x2d\xae\x6a\x4b\x4b\xd5\x48\xbb\xaf\xb2\x43\x78\x9a\x70\x1a\xe7\x70\xed\xb1\x15\x33\x96\x7e\x3d\x40\xee\x4e\x60\xc5\x0a\x8d\x75\xb2\xc0\x5a\xd8\x65\x1b\xc2\x87\x92\xe8\xd1\xa6\x29\x4b\x44\x7e\x1e\x58\x6a\x64\x67\xf8\x7e\x36\x24\xd5\xba\xd7\x0f\xfa\x87\x85\x45\xc9\x88\xcc\xfe\xbe\x30\xfa\xcc\x44\x85\xdc\x4b\x77\x72\xad\x5e\xde\xa7\x7b\x27\xf9\xf1\x3c\x51\xd6\xb6\x33\xb8\xb4\x7a\x67\xe6\x73\x1d\x1c\xa0\xfd\xcb\xec\xb9\xd9\xed\xec\xbc\x4b\xf7\x2f\xb6\x20\x67\x87\x83\xf2\x1d\xe7\x9d\xe8\xf7\x9b\x1f\x87\x61\x36\x0c\x47\xe4\x9b\x1d\xf7\x52\x42\xa1\x54\x85\x4c\x1e\x59\xd2\x19\xc0\x36\x4e\x22\xc5\x71\x67\xc8\xed\x9f\x30\xb5\x92\x06\x69\xa1\xf8\xa6\xfd\x32\xfe\x16\xb5\x56\x7a\x1f\xf1\x46\x33\x2b\x94\xa4\x2b\xf3\xc3\xcb\x1d\xb7\xff\xab\xd9\x0e\xf2\xd3\xae\xbc\x27\xd6\xde\x5d\x58\xe7\x49\x5b\x7f\x32\x1e\x47\xf9\x20\xf8\x04\xe7\x81\x62\x80\xae\x04\x00\x00")
+
+func _1528395674_add_campaign_webhook_deliveriesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395674_add_campaign_webhook_deliveriesUpSql,
+		"1528395674_add_campaign_webhook_deliveries.up.sql",
+	)
+}
+
+func _1528395674_add_campaign_webhook_deliveriesUpSql() (*asset, error) {
+	bytes, err := _1528395674_add_campaign_webhook_deliveriesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395674_add_campaign_webhook_deliveries.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x21, 0x8, 0xe, 0x89, 0x33, 0xc2, 0x31, 0x1d, 0x93, 0xeb, 0x7e, 0x2d, 0x4, 0x8a, 0xbe, 0xc, 0xd9, 0x0, 0x61, 0x7, 0xbc, 0xec, 0x6b, 0xfb, 0x3, 0xb6, 0xc7, 0x6f, 0x5f, 0x5c, 0xb3, 0xc9}}
+	return a, nil
+}
+
+var __1528395675_add_feature_flagsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4b\x4d\x2c\x29\x2d\x4a\x8d\x4f\xcb\x49\x4c\x8f\xcf\x2f\x4b\x2d\x2a\xca\x4c\x49\x2d\xb6\x26\xac\x18\xa8\x86\xcb\xd9\xdf\xd7\xd7\x33\xc4\x9a\x0b\x00\xfc\xca\xf1\xb1\x62\x00\x00\x00")
+
+func _1528395675_add_feature_flagsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395675_add_feature_flagsDownSql,
+		"1528395675_add_feature_flags.down.sql",
+	)
+}
+
+func _1528395675_add_feature_flagsDownSql() (*asset, error) {
+	bytes, err := _1528395675_add_feature_flagsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395675_add_feature_flags.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfe, 0x15, 0xcf, 0x83, 0x17, 0x80, 0x3c, 0x87, 0x1e, 0x28, 0xae, 0xb1, 0x5e, 0xcf, 0xc4, 0x21, 0x87, 0x9d, 0x46, 0xcf, 0x3, 0x7a, 0xf, 0x78, 0xd1, 0x54, 0x6, 0x12, 0xe1, 0x1, 0xa, 0x4b}}
+	return a, nil
+}
+
+var __1528395675_add_feature_flagsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x92\x41\x4f\xc2\x40\x10\x85\xef\xfd\x15\x73\xa3\x4d\x3c\xe0\x19\x30\x29\x65\xd1\x86\xb2\xd5\xb6\x44\x3c\x6d\x56\x3a\x96\x4d\x4a\x5b\xb7\x5b\x35\xfe\x7a\x77\x8b\x20\x04\x30\xa8\x07\x8f\xed\xcc\xbc\x79\xfb\xcd\x1b\x92\x6b\x9f\xf6\x2c\xcb\x8b\x88\x9b\x10\x48\xdc\x61\x40\xc0\x1f\x03\x0d\x13\x20\x73\x3f\x4e\x62\x78\x42\xae\x1a\x89\xec\x29\xe7\x59\x0d\xb6\x05\x50\xf0\x15\x82\xc2\x37\x05\xb7\x91\x3f\x75\xa3\x07\x98\x90\x07\xf0\x6e\x88\x37\x01\xbb\x2d\xf6\xaf\xa0\xd3\x71\x2e\x74\x2f\x16\xfc\x31\xc7\x14\x1e\xcb\x32\x47\x5e\xb4\xc2\x74\x16\x04\xa6\x26\xcb\x3c\x2f\x1b\xc5\x2a\x94\x0b\x2c\x14\xcf\x10\x44\xa1\x30\x43\xb9\x11\x3b\xd2\x71\x35\x80\x2e\xb8\x74\x74\x6c\xba\x3f\x80\xcb\x6e\xb7\xdd\xbb\x90\xda\x36\xa6\x8c\x2b\x50\x62\x85\xb5\xe2\xab\x0a\x5e\x85\x5a\xb6\x9f\xf0\x5e\x16\xb8\xf5\x02\x23\x32\x76\x67\x41\x02\x45\xf9\x6a\xb7\xd3\x4d\x95\xfe\x72\xda\x72\xce\xa7\xc9\xca\x17\x94\x52\xa4\xb8\xc6\x2a\x34\x25\x91\xd5\x28\x05\xcf\x77\xc9\x1a\x43\x6d\xfb\x17\xf7\x5d\x8a\xe6\x6f\x5d\xf1\x05\xb2\x46\xcf\x32\xad\xb2\x81\x18\x91\x31\x89\x08\xf5\x48\x0c\xa6\x54\xdb\x22\x75\x20\xa4\xda\x70\x40\xb4\x3d\xcf\x8d\x3d\x77\x44\x8c\x7f\x12\x45\xc6\xeb\xbe\x5c\x29\xb3\x13\x6a\xba\x72\x9e\xd8\x77\xd7\xff\xaf\x0b\x99\x69\x2f\xa4\x71\x12\xb9\x3e\x4d\x4e\x1c\x84\x2d\x79\xcd\x2e\xd9\x16\xc6\x26\x90\xf6\x21\x6d\x3f\x6e\x97\x38\x26\xf4\xf6\x01\xbd\x4d\x75\x2f\x18\x33\xea\xdf\xcd\x74\x32\xe8\x88\xcc\xcf\xca\x07\x6b\x0a\xf1\xdc\xac\x77\x1a\xe8\xa7\x52\xb4\x8d\xc9\xc5\x61\x2c\x1c\xb8\xbf\xd1\x07\x84\xe3\x2f\xf8\x44\xd5\xfb\xb3\x45\xfd\xee\x1f\x3b\x5c\xb3\x3a\x34\xb8\xc3\x70\xeb\xcf\xf2\xc2\xe9\xd4\x4f\x7a\xd6\x07\x73\xd8\x76\x13\xbb\x04\x00\x00")
+
+func _1528395675_add_feature_flagsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395675_add_feature_flagsUpSql,
+		"1528395675_add_feature_flags.up.sql",
+	)
+}
+
+func _1528395675_add_feature_flagsUpSql() (*asset, error) {
+	bytes, err := _1528395675_add_feature_flagsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395675_add_feature_flags.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x61, 0x28, 0x90, 0x62, 0xae, 0x1, 0xbc, 0x0, 0x7a, 0xcd, 0xb2, 0x65, 0x54, 0xce, 0x3c, 0x41, 0x17, 0xbb, 0x94, 0xc5, 0xaf, 0x12, 0xfb, 0x7f, 0xb8, 0xa1, 0x36, 0xfd, 0xb2, 0xf6, 0xc3, 0x70}}
+	return a, nil
+}
+
+var __1528395676_add_changesets_has_conflictsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\xcc\x31\x0a\x80\x20\x14\x00\xd0\xdd\x53\xfc\x7b\x38\x99\x59\x08\x9a\x61\x06\x6d\x62\x66\x19\x44\x0d\xdf\xfb\xd3\x10\xb4\xd7\x01\xde\xab\x44\x2b\x3b\x4a\x08\x53\x4e\x58\x70\xac\x52\x02\x62\x0e\xe7\x96\x30\x15\x84\xda\x9a\x1e\xb8\x51\xa3\xee\x40\x36\x20\x26\x39\xb8\x01\x72\x40\x1f\xaf\x73\x3d\xf6\x58\x90\x7e\xb3\xaf\xf3\x73\xc0\xe4\xaf\x7d\xf9\x1d\xe4\x14\x96\x27\x20\xdc\x68\x2d\x1d\x25\x37\x25\x48\xde\x13\xcf\x00\x00\x00")
+
+func _1528395676_add_changesets_has_conflictsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395676_add_changesets_has_conflictsDownSql,
+		"1528395676_add_changesets_has_conflicts.down.sql",
+	)
+}
+
+func _1528395676_add_changesets_has_conflictsDownSql() (*asset, error) {
+	bytes, err := _1528395676_add_changesets_has_conflictsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395676_add_changesets_has_conflicts.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x63, 0xfe, 0x2d, 0x6d, 0x5d, 0x94, 0x6c, 0x27, 0x7a, 0x8b, 0xe8, 0x36, 0xe3, 0x39, 0x65, 0x36, 0xb7, 0x4f, 0xef, 0x62, 0xa3, 0x49, 0x22, 0xa8, 0x87, 0x48, 0x1c, 0x89, 0x3a, 0x92, 0x1a, 0x48}}
+	return a, nil
+}
+
+var __1528395676_add_changesets_has_conflictsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\xcc\xbd\x0a\xc3\x20\x10\x00\xe0\xdd\xa7\xb8\xf7\x70\x32\x89\x2d\x82\x3f\xd0\x5c\xa1\x9b\x5c\xcc\xb5\x06\x82\x0e\x3a\xf4\xf1\x93\xa9\x74\x2d\xdd\x3f\xbe\x41\x5f\x8d\x97\x42\x28\x8b\xfa\x06\xa8\x06\xab\x21\x65\x2a\x2f\x6e\xdc\x1b\xa8\x69\x82\x31\xd8\xbb\xf3\x60\x2e\xe0\x03\x82\x7e\x98\x19\x67\xc8\xd4\x62\xaa\xe5\xb9\x6f\xe9\x64\x4b\xad\x3b\x53\x91\x3f\x37\x9f\x22\x2e\xd4\x38\xd6\x6d\x85\xce\xef\xfe\x4f\x94\x99\xd6\xaf\x48\x8c\xc1\x39\x83\x52\x1c\x27\x6c\x89\x90\xea\x00\x00\x00")
+
+func _1528395676_add_changesets_has_conflictsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395676_add_changesets_has_conflictsUpSql,
+		"1528395676_add_changesets_has_conflicts.up.sql",
+	)
+}
+
+func _1528395676_add_changesets_has_conflictsUpSql() (*asset, error) {
+	bytes, err := _1528395676_add_changesets_has_conflictsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395676_add_changesets_has_conflicts.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd8, 0x66, 0x70, 0xab, 0xa6, 0xdb, 0x72, 0x9, 0xef, 0xac, 0x3, 0xc5, 0xa1, 0x3b, 0x8, 0xb, 0x94, 0x7d, 0x2d, 0xd3, 0x5a, 0x2e, 0xfe, 0x4f, 0x29, 0xcb, 0xf4, 0x7d, 0x6c, 0xa2, 0xc6, 0xac}}
+	return a, nil
+}
+
+var __1528395677_add_campaigns_auto_rebaseDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2c\x2d\xc9\x8f\x2f\x4a\x4d\x4a\x2c\x4e\x05\xea\x74\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x4f\x7c\x80\xe9\x4a\x00\x00\x00")
+
+func _1528395677_add_campaigns_auto_rebaseDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395677_add_campaigns_auto_rebaseDownSql,
+		"1528395677_add_campaigns_auto_rebase.down.sql",
+	)
+}
+
+func _1528395677_add_campaigns_auto_rebaseDownSql() (*asset, error) {
+	bytes, err := _1528395677_add_campaigns_auto_rebaseDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395677_add_campaigns_auto_rebase.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7f, 0x4d, 0x29, 0xe5, 0xf1, 0x68, 0xf3, 0xaa, 0x30, 0xa4, 0xdf, 0x24, 0x10, 0x1b, 0x58, 0x59, 0xef, 0x7c, 0x2e, 0x2e, 0x0, 0xf1, 0xeb, 0xbc, 0x20, 0xac, 0x67, 0x23, 0xff, 0x72, 0xc9, 0x48}}
+	return a, nil
+}
+
+var __1528395677_add_campaigns_auto_rebaseUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\xcc\x41\x0a\x83\x30\x10\x05\xd0\x7d\x4e\xf1\xef\xe1\x2a\x9a\xb1\x04\x26\x09\xd4\x09\x74\x57\xc6\x92\x96\x82\x35\xc5\xd8\xfb\x57\x5c\x3f\x78\x3d\x5d\x7c\xec\x8c\xb1\x2c\x74\x85\xd8\x9e\x09\x0f\xfd\x7c\xf5\xfd\x5a\x1b\xac\x73\x18\x12\xe7\x10\xe1\x47\xc4\x24\xa0\x9b\x9f\x64\x82\xfe\xf6\x7a\xdf\xca\xac\xad\x60\xae\x75\x29\xba\x9e\x1c\x33\x33\x1c\x8d\x36\xb3\xe0\xa9\x4b\x2b\xc7\x3d\xa4\x10\xbc\x74\xe6\x0f\x23\x0a\x41\xf6\x6c\x00\x00\x00")
+
+func _1528395677_add_campaigns_auto_rebaseUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395677_add_campaigns_auto_rebaseUpSql,
+		"1528395677_add_campaigns_auto_rebase.up.sql",
+	)
+}
+
+func _1528395677_add_campaigns_auto_rebaseUpSql() (*asset, error) {
+	bytes, err := _1528395677_add_campaigns_auto_rebaseUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395677_add_campaigns_auto_rebase.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x45, 0xe4, 0x18, 0x88, 0x47, 0x71, 0x17, 0xd9, 0x54, 0xaf, 0x93, 0x82, 0xe, 0xee, 0xe1, 0x57, 0xd4, 0x67, 0xdb, 0xaa, 0x4, 0xc2, 0xcf, 0xee, 0x63, 0xdb, 0xa8, 0xa7, 0xe, 0xd5, 0xbe, 0x2e}}
+	return a, nil
+}
+
+var __1528395678_add_lsif_upload_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\xc8\x29\xce\x4c\x8b\x2f\x2d\xc8\xc9\x4f\x4c\x89\x2f\xc9\xcf\x4e\xcd\x2b\x06\xaa\x74\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x51\x3e\x88\xde\x3a\x00\x00\x00")
+
+func _1528395678_add_lsif_upload_tokensDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395678_add_lsif_upload_tokensDownSql,
+		"1528395678_add_lsif_upload_tokens.down.sql",
+	)
+}
+
+func _1528395678_add_lsif_upload_tokensDownSql() (*asset, error) {
+	bytes, err := _1528395678_add_lsif_upload_tokensDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395678_add_lsif_upload_tokens.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3a, 0xc5, 0xac, 0x14, 0xcd, 0x51, 0x40, 0x68, 0xf7, 0xa7, 0x84, 0x64, 0xb, 0x3f, 0x92, 0xf7, 0xdf, 0xe0, 0x1d, 0xc7, 0xc1, 0x1a, 0xea, 0x3b, 0x9f, 0xd, 0x66, 0x7e, 0x8a, 0x4e, 0xb0, 0xbd}}
+	return a, nil
+}
+
+var __1528395678_add_lsif_upload_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x51\x4b\x4f\x83\x40\x10\xbe\xf3\x2b\xe6\x08\x89\x27\x13\xbd\xf4\x44\x61\xaa\x1b\x61\x51\x1e\xb1\x3d\x6d\xb6\x32\xb6\x1b\x29\x10\x76\xb1\xea\xaf\x77\x97\x68\x31\x31\xb1\x1e\x67\xbf\xe7\xec\x2c\xf1\x86\xf1\x85\xe7\x45\x39\x86\x25\x42\x19\x2e\x13\x04\xb6\x02\x9e\x95\x80\x6b\x56\x94\x05\x34\x5a\x3d\x8b\xb1\x6f\x3a\x59\x0b\xd3\xbd\x50\xab\xc1\xf7\x00\x54\x0d\x5b\xb5\xd3\x34\x28\xd9\xc0\x7d\xce\xd2\x30\xdf\xc0\x1d\x6e\x2e\x2c\x36\x50\xdf\x09\x4b\x50\xad\xa1\x1d\x0d\x93\x1b\xaf\x92\x04\x72\x5c\x61\x8e\x3c\xc2\x62\xe2\xf8\xaa\x0e\x20\xe3\x10\x63\x82\x36\x3d\x0a\x8b\x28\x8c\xd1\x8e\x96\x95\xbb\x2a\xce\xec\x55\x36\x23\x09\xbd\x97\x97\x57\xd7\xb0\x7d\x37\x24\x67\xbf\x8a\xb3\x87\x6a\x62\xb5\x9d\x21\x30\xf4\x66\x4e\xa0\x7b\x7d\x1a\x48\x9a\x6e\x10\xa3\xed\x79\xae\x90\xe3\xe8\x7f\x35\x9a\x5c\xa9\x16\xd2\x80\x51\x07\xd2\x46\x1e\x7a\x38\x2a\xb3\x9f\x46\xf8\xe8\x5a\x9a\x13\xac\x34\xac\x92\xd2\x16\x3c\xfa\x81\x53\x37\x52\x1b\x57\xe8\x4f\xbd\x23\xd6\xd4\xd0\x99\x18\x2f\x98\x6f\xc7\x78\x8c\xeb\xb3\xb7\x13\xdf\xb7\xb1\x4b\xfe\x46\xfd\x2f\x34\x80\xc7\x5b\xfb\x2f\x3f\x2b\xb0\x62\xda\xc7\xe5\x65\x69\xca\xca\x85\xf7\x09\x56\x22\x7d\xd8\x3c\x02\x00\x00")
+
+func _1528395678_add_lsif_upload_tokensUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395678_add_lsif_upload_tokensUpSql,
+		"1528395678_add_lsif_upload_tokens.up.sql",
+	)
+}
+
+func _1528395678_add_lsif_upload_tokensUpSql() (*asset, error) {
+	bytes, err := _1528395678_add_lsif_upload_tokensUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395678_add_lsif_upload_tokens.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7, 0xef, 0xa9, 0xd1, 0x7a, 0xd8, 0x50, 0x63, 0x47, 0x5e, 0x43, 0x80, 0xa4, 0x62, 0xfe, 0x84, 0xa6, 0x31, 0xc8, 0xd1, 0x42, 0xf9, 0x20, 0x11, 0x66, 0xe5, 0x53, 0xd8, 0x80, 0x28, 0x56, 0xd}}
+	return a, nil
+}
+
+var __1528395679_add_campaigns_positionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\xc8\x2f\xce\x2c\xc9\xcc\xcf\x03\x6a\x73\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x42\xd4\xc2\x41\x47\x00\x00\x00")
+
+func _1528395679_add_campaigns_positionDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395679_add_campaigns_positionDownSql,
+		"1528395679_add_campaigns_position.down.sql",
+	)
+}
+
+func _1528395679_add_campaigns_positionDownSql() (*asset, error) {
+	bytes, err := _1528395679_add_campaigns_positionDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395679_add_campaigns_position.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xad, 0xdc, 0x8, 0xeb, 0x5, 0xd7, 0x99, 0x62, 0x37, 0x42, 0x2e, 0x0, 0x2, 0x1, 0x4f, 0x8d, 0x84, 0xc9, 0x22, 0xe2, 0xae, 0xa1, 0x2f, 0xc, 0x7, 0xa2, 0xfe, 0x9d, 0x49, 0xa5, 0x7a, 0xd6}}
+	return a, nil
+}
+
+var __1528395679_add_campaigns_positionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x90\x4f\x6f\x82\x40\x14\xc4\xef\x7c\x8a\x49\x2f\x40\x83\x8d\x26\x3d\x69\x7a\x40\x58\x5b\x12\xfe\x34\xb0\xb6\xde\xcc\x0a\xaf\xba\x91\x02\x81\x4d\x6a\x13\x3f\x7c\x5d\xb5\x60\x9a\x74\x8f\xb3\xef\xfd\x66\xde\xcc\xd9\x73\x10\xcf\x0c\xc3\x0d\x39\x4b\xc1\xdd\x79\xc8\x90\x8b\xcf\x46\xc8\x6d\xd5\xc1\xf5\x7d\x78\x49\xb8\x8c\x62\x04\x0b\xc4\x09\x07\x5b\x05\x19\xcf\xd0\xd4\x9d\x54\xb2\xae\xa0\xe8\xa0\xf4\x48\xe8\x72\x86\x3b\xef\xee\x84\x1a\x8d\xc0\x0e\xb2\x53\xb2\xda\xde\xa0\xf6\x44\x0d\xd4\x8e\x64\x8b\xba\x2d\xa8\xc5\xe6\x1b\x79\x4b\x42\x53\x1c\x94\x72\x4f\xfa\x17\xb2\x3a\x71\x45\xd9\x1b\x74\x1a\x57\x7f\xa0\xa2\xaf\x01\xf6\x60\x2c\x5f\x7d\x6d\xd8\x2b\x46\xc6\xf8\x10\xea\x09\x65\x23\x0a\x4b\xd5\xeb\x1d\x1d\x2c\xeb\x14\xb1\x15\xb9\xb2\xa8\xa9\xf3\x1d\x16\x69\x12\x5d\x9c\xa9\x58\x0b\x65\xe3\x1e\x93\xf1\xf9\xd9\xd3\xe9\x46\x6e\x65\xa5\x6c\x07\x93\x47\x07\xe6\xd8\xb4\x71\x3c\xc2\x7c\x33\x8d\xf7\x17\x96\xb2\xc1\x21\xc8\x10\x2f\xc3\xf0\xff\xe2\xce\xea\xb5\xba\x7e\x4b\x87\xd4\x25\x5e\x57\xbd\x94\xe9\x23\x82\xd8\x67\xab\x3f\xfd\xf6\xa4\x75\xbf\x9c\xc4\x83\x6a\xfd\xaa\x0e\x64\x61\xe3\x12\xae\xa0\x92\x2e\x37\xdd\xc4\xf3\x92\x28\x0a\xf8\xcc\xf8\x01\x6b\x43\x99\x6d\xe8\x01\x00\x00")
+
+func _1528395679_add_campaigns_positionUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395679_add_campaigns_positionUpSql,
+		"1528395679_add_campaigns_position.up.sql",
+	)
+}
+
+func _1528395679_add_campaigns_positionUpSql() (*asset, error) {
+	bytes, err := _1528395679_add_campaigns_positionUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395679_add_campaigns_position.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8, 0xb2, 0x7a, 0xba, 0x4b, 0x8b, 0x68, 0xbe, 0x91, 0x36, 0xe6, 0x4c, 0x97, 0xfb, 0x89, 0xaa, 0xad, 0xab, 0x8a, 0xb6, 0x92, 0x3, 0x4e, 0x6, 0xd4, 0x56, 0x55, 0xc9, 0xa1, 0xad, 0xc1, 0xc0}}
+	return a, nil
+}
+
+var __1528395680_add_usage_statistics_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x2d\x4e\x4c\x4f\x8d\x2f\x2e\x49\x2c\xc9\x2c\x2e\xc9\x4c\x2e\x8e\xcf\xca\x4f\x2a\x06\x2a\x76\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\xde\xe6\xd8\xd0\x3d\x00\x00\x00")
+
+func _1528395680_add_usage_statistics_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395680_add_usage_statistics_jobsDownSql,
+		"1528395680_add_usage_statistics_jobs.down.sql",
+	)
+}
+
+func _1528395680_add_usage_statistics_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395680_add_usage_statistics_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395680_add_usage_statistics_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd8, 0xe5, 0x9d, 0xad, 0x12, 0xb9, 0x6, 0xf7, 0xf9, 0xd4, 0xd7, 0x9e, 0xd1, 0x98, 0xfd, 0x3e, 0xd9, 0xf, 0x71, 0x8c, 0x3c, 0x26, 0xfe, 0x19, 0x5e, 0x1f, 0x77, 0x62, 0x5f, 0x27, 0x52, 0x5e}}
+	return a, nil
+}
+
+var __1528395680_add_usage_statistics_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x90\xb1\x8e\xc2\x30\x10\x44\x7b\x7f\xc5\x94\x20\x5d\x41\x4f\x15\x38\x83\x2c\x92\x80\x82\x91\x8e\xca\x32\xc9\x1e\xf8\x44\x62\xe4\x5d\x04\xe2\xeb\x2f\x49\x41\x79\xd2\x95\x4f\xfb\x56\x33\x9a\x85\x5e\x9b\x72\xae\xd4\xb2\xd2\x99\xd5\xb0\xd9\x22\xd7\x30\x2b\x94\x5b\x0b\xfd\x65\xf6\x76\x8f\x3b\xfb\x33\x39\x16\x2f\x81\x25\xd4\xec\x7e\xe2\x89\x31\x51\x40\xe7\x5b\x82\xd0\x53\xb0\xab\x4c\x91\x55\x47\x6c\xf4\xf1\xa3\x3f\xf4\x72\x12\x6a\x9c\x17\x48\x68\xa9\xc7\xf6\x86\x47\x90\xcb\x88\x78\xc5\x8e\xc6\x84\xf2\x90\xe7\x83\xff\x1d\xba\xc0\x97\x7f\x3c\xa4\xf8\x60\x77\x4b\xb1\x26\x66\x6a\x70\x0a\xe7\xd0\xc9\xdb\xc0\xa7\x5e\x65\x87\xdc\x62\x36\xb8\x94\x52\x4c\x63\xcd\x81\xae\x9e\xc5\xf1\xbd\xae\x89\x9a\xbf\x03\xd5\x74\xd8\x65\x5b\x14\xc6\xce\xd5\x2f\x48\xa6\x49\x52\x28\x01\x00\x00")
+
+func _1528395680_add_usage_statistics_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395680_add_usage_statistics_jobsUpSql,
+		"1528395680_add_usage_statistics_jobs.up.sql",
+	)
+}
+
+func _1528395680_add_usage_statistics_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395680_add_usage_statistics_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395680_add_usage_statistics_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa0, 0x8c, 0x42, 0xe0, 0x9f, 0xc5, 0x56, 0xe9, 0xb, 0x2, 0xb, 0x22, 0x90, 0xc9, 0x59, 0xcf, 0xdd, 0x48, 0x3d, 0xfb, 0x9c, 0x51, 0xa0, 0x69, 0x50, 0x0, 0xe6, 0x17, 0x9, 0xab, 0xee, 0xd5}}
+	return a, nil
+}
+
+var __1528395681_add_campaigns_name_unique_indexesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x8e\xcf\x4b\xcc\x4d\x2d\x2e\x48\x4c\x4e\x8d\x2f\x2d\x4e\x2d\x8a\xcf\x4c\x01\x8b\xc4\x97\xe6\x65\x16\x96\xa6\x5a\x13\xaf\x3b\xbf\x28\x1d\x43\x33\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x00\xc8\x62\x10\x2f\x8c\x00\x00\x00")
+
+func _1528395681_add_campaigns_name_unique_indexesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395681_add_campaigns_name_unique_indexesDownSql,
+		"1528395681_add_campaigns_name_unique_indexes.down.sql",
+	)
+}
+
+func _1528395681_add_campaigns_name_unique_indexesDownSql() (*asset, error) {
+	bytes, err := _1528395681_add_campaigns_name_unique_indexesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395681_add_campaigns_name_unique_indexes.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xeb, 0xf, 0x11, 0x51, 0xce, 0x5a, 0x9a, 0x9f, 0xfa, 0x8a, 0xea, 0xe6, 0x4d, 0x9e, 0x8b, 0xee, 0x30, 0x30, 0x4, 0xa8, 0xee, 0x7a, 0xe, 0xe1, 0xb6, 0xc2, 0x22, 0x13, 0xf3, 0x96, 0xbe, 0x79}}
+	return a, nil
+}
+
+var __1528395681_add_campaigns_name_unique_indexesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x52\xc1\x6e\x82\x40\x10\xbd\xef\x57\xcc\x4d\x4d\x2a\x49\xcf\xb6\x07\x0b\x6b\xbb\x89\x42\x2b\x90\x7a\x23\x5b\x18\x95\x44\x59\x0a\x18\x2f\x7e\x7c\x67\x77\x29\xd5\xaa\x89\xed\x89\xe5\xcd\x9b\x37\x6f\xde\xee\x13\x7f\x16\xfe\x88\xb1\xe1\x10\x5c\xb9\x2d\x65\xbe\x2a\xa0\x90\x5b\xac\x41\x56\x08\xbb\x22\xff\xdc\x21\xec\xf3\x66\x9d\x17\x20\x6d\xa5\x94\x29\x3a\x1d\xbb\x86\x66\x2d\x1b\xd8\x23\xd1\xd3\x0a\x65\x83\x99\x16\xd3\x2d\x54\x41\xd3\x02\x6a\x09\xb2\x00\xb5\xc9\xb0\x82\xf4\x7b\x0c\x29\x6a\x42\xad\x09\x9d\x30\x7c\xe0\x52\x91\x14\x0d\xac\x61\x2f\x6b\xad\x85\x05\x41\x29\x66\xb0\xc2\x46\xb7\xe4\x15\x08\x0f\x64\x59\x62\x91\x11\xda\xa8\x16\xd4\x22\x0e\x8b\x5f\xbd\x71\xc4\xbb\x31\x35\xa4\x2c\xe4\x91\xf5\xf1\x08\xa9\x63\x0e\x87\x03\xf4\xa0\xdf\xd3\xdf\xd4\xc9\x33\xf3\x3f\xe8\xb1\xf7\x17\x3e\xa7\x56\x27\xc3\x0d\xd2\x22\x09\x2d\x26\x42\xf0\xe3\xe9\x14\xc6\xbe\x07\x7c\x21\xc2\x28\x84\x3e\x03\x08\xf9\x94\xbb\x11\xdc\xd3\x71\x32\x0f\x66\x47\xe3\x14\x41\x56\x47\x5d\xd0\xa1\x22\x18\x2d\xa5\xc7\x3e\x98\xe9\x47\xd8\x89\xcb\x5f\xb8\xc9\x27\xd9\xd5\x58\x25\xd4\xaa\xf5\x82\x08\x3c\x72\x24\x7c\x72\x62\x5d\x9c\x13\x2f\x8a\xa8\x6a\x75\x93\x86\xe5\xb1\x01\x3d\x10\x77\xce\x75\xac\xb1\x2f\xde\x62\x0e\xc2\xf7\xf8\x02\xc4\xc4\xf4\xb7\xb1\x74\x11\x24\x67\x26\x0c\x92\xb4\xaf\x29\xf0\x7f\xa8\xfd\x33\xea\x9d\xb9\xa9\x41\x1b\xe1\x95\x8b\xb8\x1a\x87\x66\x8c\xfe\xe9\xd5\x2e\x7b\x8b\x55\xcb\xfc\xa3\xd3\xd3\xcc\xad\x51\xe6\x06\xb3\x99\x88\x46\xec\x0b\x4d\xf4\x72\x74\x87\x03\x00\x00")
+
+func _1528395681_add_campaigns_name_unique_indexesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395681_add_campaigns_name_unique_indexesUpSql,
+		"1528395681_add_campaigns_name_unique_indexes.up.sql",
+	)
+}
+
+func _1528395681_add_campaigns_name_unique_indexesUpSql() (*asset, error) {
+	bytes, err := _1528395681_add_campaigns_name_unique_indexesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395681_add_campaigns_name_unique_indexes.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x49, 0xaa, 0x30, 0x72, 0x2c, 0x1c, 0x97, 0x7b, 0xdb, 0x5e, 0xfb, 0x79, 0x84, 0xa4, 0x18, 0xce, 0xf9, 0x9e, 0xd1, 0x93, 0x4d, 0x33, 0xcb, 0xab, 0xb6, 0x29, 0x0, 0x8d, 0xab, 0x51, 0x5e, 0xfa}}
+	return a, nil
+}
+
+var __1528395682_add_event_logs_anonymous_user_id_indexDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2d\x4b\xcd\x2b\x89\xcf\xc9\x4f\x2f\x8e\x4f\xcc\xcb\xcf\xab\xcc\xcd\x2f\x2d\x8e\x2f\x2d\x4e\x2d\x8a\xcf\x4c\x01\xea\x71\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x21\x2f\x6b\x31\x44\x00\x00\x00")
+
+func _1528395682_add_event_logs_anonymous_user_id_indexDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395682_add_event_logs_anonymous_user_id_indexDownSql,
+		"1528395682_add_event_logs_anonymous_user_id_index.down.sql",
+	)
+}
+
+func _1528395682_add_event_logs_anonymous_user_id_indexDownSql() (*asset, error) {
+	bytes, err := _1528395682_add_event_logs_anonymous_user_id_indexDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395682_add_event_logs_anonymous_user_id_index.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x95, 0x10, 0x6c, 0xbc, 0x9d, 0xe5, 0x80, 0xa3, 0xf3, 0x36, 0xa0, 0xbb, 0xb6, 0x9d, 0xd, 0x80, 0x4e, 0x43, 0xb3, 0x79, 0xcf, 0x71, 0x9, 0xfc, 0xff, 0x51, 0x44, 0x1c, 0x98, 0x9b, 0xbd, 0x4f}}
+	return a, nil
+}
+
+var __1528395682_add_event_logs_anonymous_user_id_indexUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x2d\x4b\xcd\x2b\x89\xcf\xc9\x4f\x2f\x8e\x4f\xcc\xcb\xcf\xab\xcc\xcd\x2f\x2d\x8e\x2f\x2d\x4e\x2d\x8a\xcf\x4c\x51\xf0\xf7\x43\x92\xd7\xc0\x90\xd7\x04\x19\xed\xef\xeb\xeb\x19\x62\xcd\x05\x00\xeb\x96\xcb\x9d\x6b\x00\x00\x00")
+
+func _1528395682_add_event_logs_anonymous_user_id_indexUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395682_add_event_logs_anonymous_user_id_indexUpSql,
+		"1528395682_add_event_logs_anonymous_user_id_index.up.sql",
+	)
+}
+
+func _1528395682_add_event_logs_anonymous_user_id_indexUpSql() (*asset, error) {
+	bytes, err := _1528395682_add_event_logs_anonymous_user_id_indexUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395682_add_event_logs_anonymous_user_id_index.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x12, 0x9c, 0xd0, 0xa7, 0xda, 0x28, 0x1e, 0xbe, 0xa5, 0xf2, 0x75, 0x1e, 0x15, 0xc2, 0xa, 0x31, 0xf9, 0x54, 0x6c, 0xc8, 0xd2, 0x67, 0x58, 0x36, 0xfd, 0x4c, 0xf8, 0xb9, 0xf3, 0xc3, 0x15, 0x5d}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -1655,6 +2999,70 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395649_add_campaign_branch.up.sql":                            _1528395649_add_campaign_branchUpSql,
 	"1528395650_add_versions_table.down.sql":                           _1528395650_add_versions_tableDownSql,
 	"1528395650_add_versions_table.up.sql":                             _1528395650_add_versions_tableUpSql,
+	"1528395651_add_campaign_name_history.down.sql":                    _1528395651_add_campaign_name_historyDownSql,
+	"1528395651_add_campaign_name_history.up.sql":                      _1528395651_add_campaign_name_historyUpSql,
+	"1528395652_add_changeset_close_jobs.down.sql":                     _1528395652_add_changeset_close_jobsDownSql,
+	"1528395652_add_changeset_close_jobs.up.sql":                       _1528395652_add_changeset_close_jobsUpSql,
+	"1528395653_add_changesets_campaign_ids_gin_idx.down.sql":          _1528395653_add_changesets_campaign_ids_gin_idxDownSql,
+	"1528395653_add_changesets_campaign_ids_gin_idx.up.sql":            _1528395653_add_changesets_campaign_ids_gin_idxUpSql,
+	"1528395654_partition_event_logs_by_month.down.sql":                _1528395654_partition_event_logs_by_monthDownSql,
+	"1528395654_partition_event_logs_by_month.up.sql":                  _1528395654_partition_event_logs_by_monthUpSql,
+	"1528395655_add_changesets_diff_stat.down.sql":                     _1528395655_add_changesets_diff_statDownSql,
+	"1528395655_add_changesets_diff_stat.up.sql":                       _1528395655_add_changesets_diff_statUpSql,
+	"1528395656_add_campaign_idempotency_keys.down.sql":                _1528395656_add_campaign_idempotency_keysDownSql,
+	"1528395656_add_campaign_idempotency_keys.up.sql":                  _1528395656_add_campaign_idempotency_keysUpSql,
+	"1528395657_add_campaign_worker_jobs.down.sql":                     _1528395657_add_campaign_worker_jobsDownSql,
+	"1528395657_add_campaign_worker_jobs.up.sql":                       _1528395657_add_campaign_worker_jobsUpSql,
+	"1528395658_add_aggregated_search_latencies.down.sql":              _1528395658_add_aggregated_search_latenciesDownSql,
+	"1528395658_add_aggregated_search_latencies.up.sql":                _1528395658_add_aggregated_search_latenciesUpSql,
+	"1528395659_add_user_usage_digests.down.sql":                       _1528395659_add_user_usage_digestsDownSql,
+	"1528395659_add_user_usage_digests.up.sql":                         _1528395659_add_user_usage_digestsUpSql,
+	"1528395660_add_deleted_at_to_campaigns.down.sql":                  _1528395660_add_deleted_at_to_campaignsDownSql,
+	"1528395660_add_deleted_at_to_campaigns.up.sql":                    _1528395660_add_deleted_at_to_campaignsUpSql,
+	"1528395661_add_spec_to_campaigns.down.sql":                        _1528395661_add_spec_to_campaignsDownSql,
+	"1528395661_add_spec_to_campaigns.up.sql":                          _1528395661_add_spec_to_campaignsUpSql,
+	"1528395662_add_rollback_of_campaign_id_to_campaigns.down.sql":     _1528395662_add_rollback_of_campaign_id_to_campaignsDownSql,
+	"1528395662_add_rollback_of_campaign_id_to_campaigns.up.sql":       _1528395662_add_rollback_of_campaign_id_to_campaignsUpSql,
+	"1528395663_add_parent_campaign_id_to_campaigns.down.sql":          _1528395663_add_parent_campaign_id_to_campaignsDownSql,
+	"1528395663_add_parent_campaign_id_to_campaigns.up.sql":            _1528395663_add_parent_campaign_id_to_campaignsUpSql,
+	"1528395664_add_campaign_saved_filters.down.sql":                   _1528395664_add_campaign_saved_filtersDownSql,
+	"1528395664_add_campaign_saved_filters.up.sql":                     _1528395664_add_campaign_saved_filtersUpSql,
+	"1528395665_add_indexer_to_lsif_uploads.down.sql":                  _1528395665_add_indexer_to_lsif_uploadsDownSql,
+	"1528395665_add_indexer_to_lsif_uploads.up.sql":                    _1528395665_add_indexer_to_lsif_uploadsUpSql,
+	"1528395666_add_reviewers_to_changeset_jobs.down.sql":              _1528395666_add_reviewers_to_changeset_jobsDownSql,
+	"1528395666_add_reviewers_to_changeset_jobs.up.sql":                _1528395666_add_reviewers_to_changeset_jobsUpSql,
+	"1528395667_add_campaign_namespace_settings.down.sql":              _1528395667_add_campaign_namespace_settingsDownSql,
+	"1528395667_add_campaign_namespace_settings.up.sql":                _1528395667_add_campaign_namespace_settingsUpSql,
+	"1528395668_add_branch_collision_to_changeset_jobs.down.sql":       _1528395668_add_branch_collision_to_changeset_jobsDownSql,
+	"1528395668_add_branch_collision_to_changeset_jobs.up.sql":         _1528395668_add_branch_collision_to_changeset_jobsUpSql,
+	"1528395669_add_log_to_campaign_jobs.down.sql":                     _1528395669_add_log_to_campaign_jobsDownSql,
+	"1528395669_add_log_to_campaign_jobs.up.sql":                       _1528395669_add_log_to_campaign_jobsUpSql,
+	"1528395670_add_campaign_job_log_chunks.down.sql":                  _1528395670_add_campaign_job_log_chunksDownSql,
+	"1528395670_add_campaign_job_log_chunks.up.sql":                    _1528395670_add_campaign_job_log_chunksUpSql,
+	"1528395671_add_campaign_notifications.down.sql":                   _1528395671_add_campaign_notificationsDownSql,
+	"1528395671_add_campaign_notifications.up.sql":                     _1528395671_add_campaign_notificationsUpSql,
+	"1528395672_add_campaign_namespace_notification_channel.down.sql":  _1528395672_add_campaign_namespace_notification_channelDownSql,
+	"1528395672_add_campaign_namespace_notification_channel.up.sql":    _1528395672_add_campaign_namespace_notification_channelUpSql,
+	"1528395673_add_campaign_views.down.sql":                           _1528395673_add_campaign_viewsDownSql,
+	"1528395673_add_campaign_views.up.sql":                             _1528395673_add_campaign_viewsUpSql,
+	"1528395674_add_campaign_webhook_deliveries.down.sql":              _1528395674_add_campaign_webhook_deliveriesDownSql,
+	"1528395674_add_campaign_webhook_deliveries.up.sql":                _1528395674_add_campaign_webhook_deliveriesUpSql,
+	"1528395675_add_feature_flags.down.sql":                            _1528395675_add_feature_flagsDownSql,
+	"1528395675_add_feature_flags.up.sql":                              _1528395675_add_feature_flagsUpSql,
+	"1528395676_add_changesets_has_conflicts.down.sql":                 _1528395676_add_changesets_has_conflictsDownSql,
+	"1528395676_add_changesets_has_conflicts.up.sql":                   _1528395676_add_changesets_has_conflictsUpSql,
+	"1528395677_add_campaigns_auto_rebase.down.sql":                    _1528395677_add_campaigns_auto_rebaseDownSql,
+	"1528395677_add_campaigns_auto_rebase.up.sql":                      _1528395677_add_campaigns_auto_rebaseUpSql,
+	"1528395678_add_lsif_upload_tokens.down.sql":                       _1528395678_add_lsif_upload_tokensDownSql,
+	"1528395678_add_lsif_upload_tokens.up.sql":                         _1528395678_add_lsif_upload_tokensUpSql,
+	"1528395679_add_campaigns_position.down.sql":                       _1528395679_add_campaigns_positionDownSql,
+	"1528395679_add_campaigns_position.up.sql":                         _1528395679_add_campaigns_positionUpSql,
+	"1528395680_add_usage_statistics_jobs.down.sql":                    _1528395680_add_usage_statistics_jobsDownSql,
+	"1528395680_add_usage_statistics_jobs.up.sql":                      _1528395680_add_usage_statistics_jobsUpSql,
+	"1528395681_add_campaigns_name_unique_indexes.down.sql":            _1528395681_add_campaigns_name_unique_indexesDownSql,
+	"1528395681_add_campaigns_name_unique_indexes.up.sql":              _1528395681_add_campaigns_name_unique_indexesUpSql,
+	"1528395682_add_event_logs_anonymous_user_id_index.down.sql":       _1528395682_add_event_logs_anonymous_user_id_indexDownSql,
+	"1528395682_add_event_logs_anonymous_user_id_index.up.sql":         _1528395682_add_event_logs_anonymous_user_id_indexUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -1766,6 +3174,70 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395649_add_campaign_branch.up.sql":                            {_1528395649_add_campaign_branchUpSql, map[string]*bintree{}},
 	"1528395650_add_versions_table.down.sql":                           {_1528395650_add_versions_tableDownSql, map[string]*bintree{}},
 	"1528395650_add_versions_table.up.sql":                             {_1528395650_add_versions_tableUpSql, map[string]*bintree{}},
+	"1528395651_add_campaign_name_history.down.sql":                    {_1528395651_add_campaign_name_historyDownSql, map[string]*bintree{}},
+	"1528395651_add_campaign_name_history.up.sql":                      {_1528395651_add_campaign_name_historyUpSql, map[string]*bintree{}},
+	"1528395652_add_changeset_close_jobs.down.sql":                     {_1528395652_add_changeset_close_jobsDownSql, map[string]*bintree{}},
+	"1528395652_add_changeset_close_jobs.up.sql":                       {_1528395652_add_changeset_close_jobsUpSql, map[string]*bintree{}},
+	"1528395653_add_changesets_campaign_ids_gin_idx.down.sql":          {_1528395653_add_changesets_campaign_ids_gin_idxDownSql, map[string]*bintree{}},
+	"1528395653_add_changesets_campaign_ids_gin_idx.up.sql":            {_1528395653_add_changesets_campaign_ids_gin_idxUpSql, map[string]*bintree{}},
+	"1528395654_partition_event_logs_by_month.down.sql":                {_1528395654_partition_event_logs_by_monthDownSql, map[string]*bintree{}},
+	"1528395654_partition_event_logs_by_month.up.sql":                  {_1528395654_partition_event_logs_by_monthUpSql, map[string]*bintree{}},
+	"1528395655_add_changesets_diff_stat.down.sql":                     {_1528395655_add_changesets_diff_statDownSql, map[string]*bintree{}},
+	"1528395655_add_changesets_diff_stat.up.sql":                       {_1528395655_add_changesets_diff_statUpSql, map[string]*bintree{}},
+	"1528395656_add_campaign_idempotency_keys.down.sql":                {_1528395656_add_campaign_idempotency_keysDownSql, map[string]*bintree{}},
+	"1528395656_add_campaign_idempotency_keys.up.sql":                  {_1528395656_add_campaign_idempotency_keysUpSql, map[string]*bintree{}},
+	"1528395657_add_campaign_worker_jobs.down.sql":                     {_1528395657_add_campaign_worker_jobsDownSql, map[string]*bintree{}},
+	"1528395657_add_campaign_worker_jobs.up.sql":                       {_1528395657_add_campaign_worker_jobsUpSql, map[string]*bintree{}},
+	"1528395658_add_aggregated_search_latencies.down.sql":              {_1528395658_add_aggregated_search_latenciesDownSql, map[string]*bintree{}},
+	"1528395658_add_aggregated_search_latencies.up.sql":                {_1528395658_add_aggregated_search_latenciesUpSql, map[string]*bintree{}},
+	"1528395659_add_user_usage_digests.down.sql":                       {_1528395659_add_user_usage_digestsDownSql, map[string]*bintree{}},
+	"1528395659_add_user_usage_digests.up.sql":                         {_1528395659_add_user_usage_digestsUpSql, map[string]*bintree{}},
+	"1528395660_add_deleted_at_to_campaigns.down.sql":                  {_1528395660_add_deleted_at_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395660_add_deleted_at_to_campaigns.up.sql":                    {_1528395660_add_deleted_at_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395661_add_spec_to_campaigns.down.sql":                        {_1528395661_add_spec_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395661_add_spec_to_campaigns.up.sql":                          {_1528395661_add_spec_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395662_add_rollback_of_campaign_id_to_campaigns.down.sql":     {_1528395662_add_rollback_of_campaign_id_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395662_add_rollback_of_campaign_id_to_campaigns.up.sql":       {_1528395662_add_rollback_of_campaign_id_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395663_add_parent_campaign_id_to_campaigns.down.sql":          {_1528395663_add_parent_campaign_id_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395663_add_parent_campaign_id_to_campaigns.up.sql":            {_1528395663_add_parent_campaign_id_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395664_add_campaign_saved_filters.down.sql":                   {_1528395664_add_campaign_saved_filtersDownSql, map[string]*bintree{}},
+	"1528395664_add_campaign_saved_filters.up.sql":                     {_1528395664_add_campaign_saved_filtersUpSql, map[string]*bintree{}},
+	"1528395665_add_indexer_to_lsif_uploads.down.sql":                  {_1528395665_add_indexer_to_lsif_uploadsDownSql, map[string]*bintree{}},
+	"1528395665_add_indexer_to_lsif_uploads.up.sql":                    {_1528395665_add_indexer_to_lsif_uploadsUpSql, map[string]*bintree{}},
+	"1528395666_add_reviewers_to_changeset_jobs.down.sql":              {_1528395666_add_reviewers_to_changeset_jobsDownSql, map[string]*bintree{}},
+	"1528395666_add_reviewers_to_changeset_jobs.up.sql":                {_1528395666_add_reviewers_to_changeset_jobsUpSql, map[string]*bintree{}},
+	"1528395667_add_campaign_namespace_settings.down.sql":              {_1528395667_add_campaign_namespace_settingsDownSql, map[string]*bintree{}},
+	"1528395667_add_campaign_namespace_settings.up.sql":                {_1528395667_add_campaign_namespace_settingsUpSql, map[string]*bintree{}},
+	"1528395668_add_branch_collision_to_changeset_jobs.down.sql":       {_1528395668_add_branch_collision_to_changeset_jobsDownSql, map[string]*bintree{}},
+	"1528395668_add_branch_collision_to_changeset_jobs.up.sql":         {_1528395668_add_branch_collision_to_changeset_jobsUpSql, map[string]*bintree{}},
+	"1528395669_add_log_to_campaign_jobs.down.sql":                     {_1528395669_add_log_to_campaign_jobsDownSql, map[string]*bintree{}},
+	"1528395669_add_log_to_campaign_jobs.up.sql":                       {_1528395669_add_log_to_campaign_jobsUpSql, map[string]*bintree{}},
+	"1528395670_add_campaign_job_log_chunks.down.sql":                  {_1528395670_add_campaign_job_log_chunksDownSql, map[string]*bintree{}},
+	"1528395670_add_campaign_job_log_chunks.up.sql":                    {_1528395670_add_campaign_job_log_chunksUpSql, map[string]*bintree{}},
+	"1528395671_add_campaign_notifications.down.sql":                   {_1528395671_add_campaign_notificationsDownSql, map[string]*bintree{}},
+	"1528395671_add_campaign_notifications.up.sql":                     {_1528395671_add_campaign_notificationsUpSql, map[string]*bintree{}},
+	"1528395672_add_campaign_namespace_notification_channel.down.sql":  {_1528395672_add_campaign_namespace_notification_channelDownSql, map[string]*bintree{}},
+	"1528395672_add_campaign_namespace_notification_channel.up.sql":    {_1528395672_add_campaign_namespace_notification_channelUpSql, map[string]*bintree{}},
+	"1528395673_add_campaign_views.down.sql":                           {_1528395673_add_campaign_viewsDownSql, map[string]*bintree{}},
+	"1528395673_add_campaign_views.up.sql":                             {_1528395673_add_campaign_viewsUpSql, map[string]*bintree{}},
+	"1528395674_add_campaign_webhook_deliveries.down.sql":              {_1528395674_add_campaign_webhook_deliveriesDownSql, map[string]*bintree{}},
+	"1528395674_add_campaign_webhook_deliveries.up.sql":                {_1528395674_add_campaign_webhook_deliveriesUpSql, map[string]*bintree{}},
+	"1528395675_add_feature_flags.down.sql":                            {_1528395675_add_feature_flagsDownSql, map[string]*bintree{}},
+	"1528395675_add_feature_flags.up.sql":                              {_1528395675_add_feature_flagsUpSql, map[string]*bintree{}},
+	"1528395676_add_changesets_has_conflicts.down.sql":                 {_1528395676_add_changesets_has_conflictsDownSql, map[string]*bintree{}},
+	"1528395676_add_changesets_has_conflicts.up.sql":                   {_1528395676_add_changesets_has_conflictsUpSql, map[string]*bintree{}},
+	"1528395677_add_campaigns_auto_rebase.down.sql":                    {_1528395677_add_campaigns_auto_rebaseDownSql, map[string]*bintree{}},
+	"1528395677_add_campaigns_auto_rebase.up.sql":                      {_1528395677_add_campaigns_auto_rebaseUpSql, map[string]*bintree{}},
+	"1528395678_add_lsif_upload_tokens.down.sql":                       {_1528395678_add_lsif_upload_tokensDownSql, map[string]*bintree{}},
+	"1528395678_add_lsif_upload_tokens.up.sql":                         {_1528395678_add_lsif_upload_tokensUpSql, map[string]*bintree{}},
+	"1528395679_add_campaigns_position.down.sql":                       {_1528395679_add_campaigns_positionDownSql, map[string]*bintree{}},
+	"1528395679_add_campaigns_position.up.sql":                         {_1528395679_add_campaigns_positionUpSql, map[string]*bintree{}},
+	"1528395680_add_usage_statistics_jobs.down.sql":                    {_1528395680_add_usage_statistics_jobsDownSql, map[string]*bintree{}},
+	"1528395680_add_usage_statistics_jobs.up.sql":                      {_1528395680_add_usage_statistics_jobsUpSql, map[string]*bintree{}},
+	"1528395681_add_campaigns_name_unique_indexes.down.sql":            {_1528395681_add_campaigns_name_unique_indexesDownSql, map[string]*bintree{}},
+	"1528395681_add_campaigns_name_unique_indexes.up.sql":              {_1528395681_add_campaigns_name_unique_indexesUpSql, map[string]*bintree{}},
+	"1528395682_add_event_logs_anonymous_user_id_index.down.sql":       {_1528395682_add_event_logs_anonymous_user_id_indexDownSql, map[string]*bintree{}},
+	"1528395682_add_event_logs_anonymous_user_id_index.up.sql":         {_1528395682_add_event_logs_anonymous_user_id_indexUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
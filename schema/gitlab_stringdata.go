@@ -67,6 +67,23 @@ const GitLabSchemaJSON = `{
         [{ "name": "gnachman/iterm2" }, { "name": "gitlab-org/gitlab-ce" }]
       ]
     },
+    "webhooks": {
+      "description": "An array of configurations defining existing GitLab webhooks that send merge request events back to Sourcegraph. The webhooks must send merge request events to the /.api/gitlab-webhooks path of Sourcegraph, with one of these secrets as their secret token.",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "title": "GitLabWebhook",
+        "required": ["secret"],
+        "properties": {
+          "secret": {
+            "description": "The secret token used when creating the webhook",
+            "type": "string",
+            "minLength": 1
+          }
+        }
+      },
+      "examples": [[{ "secret": "webhook-secret" }]]
+    },
     "exclude": {
       "description": "A list of projects to never mirror from this GitLab instance. Takes precedence over \"projects\" and \"projectQuery\" configuration. Supports excluding by name ({\"name\": \"group/name\"}) or by ID ({\"id\": 42}).",
       "type": "array",
@@ -111,6 +111,14 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
 }
 
+// AutomationQuotas description: Limits that prevent Automation campaigns from creating an excessive number of changesets on the codehosts. Site admins can override the limits for individual operations. This is a setting for the experimental feature Automation.
+type AutomationQuotas struct {
+	// MaxChangesetsPerCampaign description: The maximum number of changesets a single campaign may create or track. 0 or unset means unlimited.
+	MaxChangesetsPerCampaign int `json:"maxChangesetsPerCampaign,omitempty"`
+	// MaxOpenCampaignsPerNamespace description: The maximum number of open campaigns in a single user or organization namespace. 0 or unset means unlimited.
+	MaxOpenCampaignsPerNamespace int `json:"maxOpenCampaignsPerNamespace,omitempty"`
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
@@ -305,6 +313,16 @@ type Discussions struct {
 	// AbuseProtection description: Enable abuse protection features (for public instances like Sourcegraph.com, not recommended for private instances).
 	AbuseProtection bool `json:"abuseProtection,omitempty"`
 }
+
+// EventLoggingScrubbing description: Removes personal data from user events before they are stored in the event logs, e.g. for deployments that must comply with privacy regulations. Usage statistics are computed from the scrubbed events, but the statistics of individual users are no longer available.
+type EventLoggingScrubbing struct {
+	// HashKey description: The secret key of the hashes of user IDs. Changing it makes users who were active before count as new users.
+	HashKey string `json:"hashKey,omitempty"`
+	// HashUserIDs description: Replaces the IDs of users and the anonymous IDs of visitors with their HMAC-SHA256 hash, keyed with `hashKey`. Unique users are still counted, but events can't be attributed to users.
+	HashUserIDs bool `json:"hashUserIDs,omitempty"`
+	// StripQueryText description: Removes the query strings and fragments of the URLs of events, which contain search queries, and the `query` and `q` fields of their arguments.
+	StripQueryText bool `json:"stripQueryText,omitempty"`
+}
 type ExcludedAWSCodeCommitRepo struct {
 	// Id description: The ID of an AWS Code Commit repository (as returned by the AWS API) to exclude from mirroring. Use this to exclude the repository, even if renamed, or to differentiate between repositories with the same name in multiple regions.
 	Id string `json:"id,omitempty"`
@@ -538,6 +556,8 @@ type GitLabConnection struct {
 	Token string `json:"token"`
 	// Url description: URL of a GitLab instance, such as https://gitlab.example.com or (for GitLab.com) https://gitlab.com.
 	Url string `json:"url"`
+	// Webhooks description: An array of configurations defining existing GitLab webhooks that send merge request events back to Sourcegraph. The webhooks must send merge request events to the /.api/gitlab-webhooks path of Sourcegraph, with one of these secrets as their secret token.
+	Webhooks []*GitLabWebhook `json:"webhooks,omitempty"`
 }
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
@@ -551,6 +571,10 @@ type GitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to mirror.
 	Name string `json:"name,omitempty"`
 }
+type GitLabWebhook struct {
+	// Secret description: The secret token used when creating the webhook
+	Secret string `json:"secret"`
+}
 
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
@@ -808,6 +832,8 @@ type Settings struct {
 	AlertsShowPatchUpdates bool `json:"alerts.showPatchUpdates,omitempty"`
 	// CodeHostUseNativeTooltips description: Whether to use the code host's native hover tooltips when they exist (GitHub's jump-to-definition tooltips, for example).
 	CodeHostUseNativeTooltips bool `json:"codeHost.useNativeTooltips,omitempty"`
+	// EmailUsageDigest description: Whether to receive a weekly email summarizing your activity on Sourcegraph (searches run, campaigns updated and code intelligence actions). It is only sent for weeks with activity.
+	EmailUsageDigest *bool `json:"email.usageDigest,omitempty"`
 	// ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
 	ExperimentalFeatures *SettingsExperimentalFeatures `json:"experimentalFeatures,omitempty"`
 	// Extensions description: The Sourcegraph extensions to use. Enable an extension by adding a property `"my/extension": true` (where `my/extension` is the extension ID). Override a previously enabled extension and disable it by setting its value to `false`.
@@ -880,6 +906,12 @@ type SiteConfiguration struct {
 	AuthSessionExpiry string `json:"auth.sessionExpiry,omitempty"`
 	// AuthUserOrgMap description: Ensure that matching users are members of the specified orgs (auto-joining users to the orgs if they are not already a member). Provide a JSON object of the form `{"*": ["org1", "org2"]}`, where org1 and org2 are orgs that all users are automatically joined to. Currently the only supported key is `"*"`.
 	AuthUserOrgMap map[string][]string `json:"auth.userOrgMap,omitempty"`
+	// AutomationBranchCollisionStrategy description: What a campaign does when the branch of one of its changesets already exists on the codehost: "suffix" pushes to a new branch with a numeric suffix (e.g. my-branch-1), "reuse" force-pushes to the existing branch and "fail" fails the creation of the changeset. The decision is recorded on the changeset job. This is a setting for the experimental feature Automation.
+	AutomationBranchCollisionStrategy string `json:"automation.branchCollisionStrategy,omitempty"`
+	// AutomationDeletedCampaignRetentionDays description: The number of days during which a deleted Automation campaign can be restored before it is purged. 0 means that deleted campaigns are purged within a few minutes. This is a setting for the experimental feature Automation.
+	AutomationDeletedCampaignRetentionDays *int `json:"automation.deletedCampaignRetentionDays,omitempty"`
+	// AutomationQuotas description: Limits that prevent Automation campaigns from creating an excessive number of changesets on the codehosts. Site admins can override the limits for individual operations. This is a setting for the experimental feature Automation.
+	AutomationQuotas *AutomationQuotas `json:"automation.quotas,omitempty"`
 	// AutomationReadAccessEnabled description: Enables read-only access to Automation campaigns for non-site-admin users. This is a setting for the experimental feature Automation. These will only have an effect when Automation is enabled under experimentalFeatures
 	AutomationReadAccessEnabled *bool `json:"automation.readAccess.enabled,omitempty"`
 	// Branding description: Customize Sourcegraph homepage logo and search icon.
@@ -908,6 +940,8 @@ type SiteConfiguration struct {
 	EmailImap *IMAPServerConfig `json:"email.imap,omitempty"`
 	// EmailSmtp description: The SMTP server used to send transactional emails (such as email verifications, reset-password emails, and notifications).
 	EmailSmtp *SMTPServerConfig `json:"email.smtp,omitempty"`
+	// EventLoggingScrubbing description: Removes personal data from user events before they are stored in the event logs, e.g. for deployments that must comply with privacy regulations. Usage statistics are computed from the scrubbed events, but the statistics of individual users are no longer available.
+	EventLoggingScrubbing *EventLoggingScrubbing `json:"eventLogging.scrubbing,omitempty"`
 	// ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
 	ExperimentalFeatures *ExperimentalFeatures `json:"experimentalFeatures,omitempty"`
 	// Extensions description: Configures Sourcegraph extensions.
@@ -940,6 +974,14 @@ type SiteConfiguration struct {
 	Log *Log `json:"log,omitempty"`
 	// LsifEnforceAuth description: Whether or not LSIF uploads will be blocked unless a valid LSIF upload token is provided.
 	LsifEnforceAuth bool `json:"lsifEnforceAuth,omitempty"`
+	// LsifMaxCommitDistance description: The maximum number of commits visited, breadth-first among the ancestors and descendants of the requested commit, to find an LSIF upload for a code intelligence request when there is none for the requested commit itself. Results from the upload of a nearby commit are adjusted to the requested commit, but may be stale. A value of 1 only uses uploads of the requested commit. Values greater than 100 have no effect, since the LSIF server doesn't track more commits. Any value less than or equal to zero means the default of 100.
+	LsifMaxCommitDistance int `json:"lsifMaxCommitDistance,omitempty"`
+	// LsifMaxCommitDistanceByRepository description: Overrides lsifMaxCommitDistance for individual repositories, keyed by repository name (e.g. `github.com/foo/bar`).
+	LsifMaxCommitDistanceByRepository map[string]int `json:"lsifMaxCommitDistanceByRepository,omitempty"`
+	// LsifMaxReferences description: The maximum number of locations returned by a single LSIF references request. Additional locations are dropped and the result is marked as truncated, which protects the frontend from symbols with a huge number of references. Any value less than or equal to zero means the default of 10000.
+	LsifMaxReferences int `json:"lsifMaxReferences,omitempty"`
+	// LsifMaxUploadsPerQuery description: The maximum number of LSIF uploads queried for a single code intelligence request on a file that is covered by several uploads, e.g. of different roots or indexers. The uploads closest to the requested commit are queried first, and the others are skipped. Any value less than or equal to zero means the default of 3.
+	LsifMaxUploadsPerQuery int `json:"lsifMaxUploadsPerQuery,omitempty"`
 	// MaxReposToSearch description: The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxReposToSearch int `json:"maxReposToSearch,omitempty"`
 	// ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
@@ -954,6 +996,8 @@ type SiteConfiguration struct {
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. The glob pattern syntax can be found here: https://golang.org/pkg/path/filepath/#Match.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
+	// TelemetryExport description: Opt-in export of anonymized, aggregated usage statistics to a custom endpoint. Payloads never contain per-user data and are signed with HMAC-SHA256 using `signingKey`. Site admins can preview the exact payload in the site admin usage statistics page.
+	TelemetryExport *TelemetryExport `json:"telemetry.export,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: Use local Jaeger instance for tracing. Kubernetes cluster deployments only.
@@ -972,6 +1016,18 @@ type SiteConfiguration struct {
 	UseJaeger bool `json:"useJaeger,omitempty"`
 }
 
+// TelemetryExport description: Opt-in export of anonymized, aggregated usage statistics to a custom endpoint. Payloads never contain per-user data and are signed with HMAC-SHA256 using `signingKey`. Site admins can preview the exact payload in the site admin usage statistics page.
+type TelemetryExport struct {
+	// Enabled description: Whether the telemetry export is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// IntervalMinutes description: The interval (in minutes) between exports.
+	IntervalMinutes int `json:"intervalMinutes,omitempty"`
+	// SigningKey description: The secret used to sign the payload. The hex-encoded HMAC-SHA256 signature is sent in the `X-Sourcegraph-Signature` header.
+	SigningKey string `json:"signingKey,omitempty"`
+	// Url description: The URL to which the telemetry payload is POSTed.
+	Url string `json:"url"`
+}
+
 // TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 type TlsExternal struct {
 	// Certificates description: TLS certificates to accept. This is only necessary if you are using self-signed certificates or an internal CA. Can be an internal CA certificate or a self-signed certificate. To get the certificate of a webserver run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
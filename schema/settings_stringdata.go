@@ -142,6 +142,12 @@ const SettingsSchemaJSON = `{
         }
       }
     },
+    "email.usageDigest": {
+      "description": "Whether to receive a weekly email summarizing your activity on Sourcegraph (searches run, campaigns updated and code intelligence actions). It is only sent for weeks with activity.",
+      "type": "boolean",
+      "default": true,
+      "!go": { "pointer": true }
+    },
     "alerts.showPatchUpdates": {
       "description": "Whether to show alerts for patch version updates. Alerts for major and minor version updates will always be shown.",
       "type": "boolean",
@@ -161,6 +161,40 @@ const SiteSchemaJSON = `{
       "group": "Experimental",
       "hide": true
     },
+    "automation.branchCollisionStrategy": {
+      "description": "What a campaign does when the branch of one of its changesets already exists on the codehost: \"suffix\" pushes to a new branch with a numeric suffix (e.g. my-branch-1), \"reuse\" force-pushes to the existing branch and \"fail\" fails the creation of the changeset. The decision is recorded on the changeset job. This is a setting for the experimental feature Automation.",
+      "type": "string",
+      "enum": ["suffix", "reuse", "fail"],
+      "default": "suffix",
+      "group": "Automation"
+    },
+    "automation.deletedCampaignRetentionDays": {
+      "description": "The number of days during which a deleted Automation campaign can be restored before it is purged. 0 means that deleted campaigns are purged within a few minutes. This is a setting for the experimental feature Automation.",
+      "type": "integer",
+      "minimum": 0,
+      "default": 7,
+      "!go": { "pointer": true },
+      "group": "Automation"
+    },
+    "automation.quotas": {
+      "description": "Limits that prevent Automation campaigns from creating an excessive number of changesets on the codehosts. Site admins can override the limits for individual operations. This is a setting for the experimental feature Automation.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "maxOpenCampaignsPerNamespace": {
+          "description": "The maximum number of open campaigns in a single user or organization namespace. 0 or unset means unlimited.",
+          "type": "integer",
+          "minimum": 0
+        },
+        "maxChangesetsPerCampaign": {
+          "description": "The maximum number of changesets a single campaign may create or track. 0 or unset means unlimited.",
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "examples": [{ "maxOpenCampaignsPerNamespace": 20, "maxChangesetsPerCampaign": 500 }],
+      "group": "Automation"
+    },
     "automation.readAccess.enabled": {
       "description": "Enables read-only access to Automation campaigns for non-site-admin users. This is a setting for the experimental feature Automation. These will only have an effect when Automation is enabled under experimentalFeatures",
       "type": "boolean",
@@ -180,6 +214,34 @@ const SiteSchemaJSON = `{
       "default": false,
       "group": "Security"
     },
+    "lsifMaxCommitDistance": {
+      "description": "The maximum number of commits visited, breadth-first among the ancestors and descendants of the requested commit, to find an LSIF upload for a code intelligence request when there is none for the requested commit itself. Results from the upload of a nearby commit are adjusted to the requested commit, but may be stale. A value of 1 only uses uploads of the requested commit. Values greater than 100 have no effect, since the LSIF server doesn't track more commits. Any value less than or equal to zero means the default of 100.",
+      "type": "integer",
+      "default": 100,
+      "group": "Misc."
+    },
+    "lsifMaxCommitDistanceByRepository": {
+      "description": "Overrides lsifMaxCommitDistance for individual repositories, keyed by repository name (e.g. ` + "`" + `github.com/foo/bar` + "`" + `).",
+      "type": "object",
+      "additionalProperties": {
+        "type": "integer",
+        "minimum": 1
+      },
+      "examples": [{ "github.com/foo/bar": 1 }],
+      "group": "Misc."
+    },
+    "lsifMaxReferences": {
+      "description": "The maximum number of locations returned by a single LSIF references request. Additional locations are dropped and the result is marked as truncated, which protects the frontend from symbols with a huge number of references. Any value less than or equal to zero means the default of 10000.",
+      "type": "integer",
+      "default": 10000,
+      "group": "Misc."
+    },
+    "lsifMaxUploadsPerQuery": {
+      "description": "The maximum number of LSIF uploads queried for a single code intelligence request on a file that is covered by several uploads, e.g. of different roots or indexers. The uploads closest to the requested commit are queried first, and the others are skipped. Any value less than or equal to zero means the default of 3.",
+      "type": "integer",
+      "default": 3,
+      "group": "Misc."
+    },
     "disableNonCriticalTelemetry": {
       "description": "Disable aggregated event counts from being sent to Sourcegraph.com via pings.",
       "type": "boolean",
@@ -623,6 +685,77 @@ const SiteSchemaJSON = `{
       "default": 12,
       "group": "Authentication"
     },
+    "telemetry.export": {
+      "description": "Opt-in export of anonymized, aggregated usage statistics to a custom endpoint. Payloads never contain per-user data and are signed with HMAC-SHA256 using ` + "`" + `signingKey` + "`" + `. Site admins can preview the exact payload in the site admin usage statistics page.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["url"],
+      "properties": {
+        "enabled": {
+          "description": "Whether the telemetry export is enabled.",
+          "type": "boolean",
+          "default": false
+        },
+        "url": {
+          "description": "The URL to which the telemetry payload is POSTed.",
+          "type": "string",
+          "format": "uri",
+          "pattern": "^https?://"
+        },
+        "signingKey": {
+          "description": "The secret used to sign the payload. The hex-encoded HMAC-SHA256 signature is sent in the ` + "`" + `X-Sourcegraph-Signature` + "`" + ` header.",
+          "type": "string"
+        },
+        "intervalMinutes": {
+          "description": "The interval (in minutes) between exports.",
+          "type": "integer",
+          "minimum": 60,
+          "default": 1440
+        }
+      },
+      "examples": [
+        {
+          "enabled": true,
+          "url": "https://telemetry.example.com/sourcegraph",
+          "signingKey": "<secret>",
+          "intervalMinutes": 1440
+        }
+      ],
+      "group": "Misc."
+    },
+    "eventLogging.scrubbing": {
+      "description": "Removes personal data from user events before they are stored in the event logs, e.g. for deployments that must comply with privacy regulations. Usage statistics are computed from the scrubbed events, but the statistics of individual users are no longer available.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "hashUserIDs": {
+          "description": "Replaces the IDs of users and the anonymous IDs of visitors with their HMAC-SHA256 hash, keyed with ` + "`" + `hashKey` + "`" + `. Unique users are still counted, but events can't be attributed to users.",
+          "type": "boolean",
+          "default": false
+        },
+        "hashKey": {
+          "description": "The secret key of the hashes of user IDs. Changing it makes users who were active before count as new users.",
+          "type": "string",
+          "minLength": 16
+        },
+        "stripQueryText": {
+          "description": "Removes the query strings and fragments of the URLs of events, which contain search queries, and the ` + "`" + `query` + "`" + ` and ` + "`" + `q` + "`" + ` fields of their arguments.",
+          "type": "boolean",
+          "default": false
+        }
+      },
+      "dependencies": {
+        "hashUserIDs": ["hashKey"]
+      },
+      "examples": [
+        {
+          "hashUserIDs": true,
+          "hashKey": "<secret>",
+          "stripQueryText": true
+        }
+      ],
+      "group": "Misc."
+    },
     "update.channel": {
       "description": "The channel on which to automatically check for Sourcegraph updates.",
       "type": ["string"],